@@ -362,6 +362,14 @@ storage:
     secure: {{ $remoteStorageMinio.secure | default ($minio.enabled | default false) }}
     region: {{ $remoteStorageMinio.region | default "local" }}
     parallelUpload: {{ $remoteStorageMinio.parallelUpload | default "" }}
+{{- if .remoteStorage.encryption }}
+  encryption:
+{{ toYaml .remoteStorage.encryption | indent 4 }}
+{{- end }}
+{{- if .remoteStorage.transfer }}
+  transfer:
+{{ toYaml .remoteStorage.transfer | indent 4 }}
+{{- end }}
 {{- else }}
 {{ toYaml .remoteStorage | indent 2 }}
 {{- end -}}
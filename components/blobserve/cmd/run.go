@@ -79,6 +79,20 @@ var runCmd = &cobra.Command{
 		}
 		go srv.MustServe()
 
+		err = srv.RegisterMetrics(reg)
+		if err != nil {
+			log.WithError(err).Fatal("cannot register blobserve metrics")
+		}
+		if cfg.AdminAddr != "" {
+			go func() {
+				err := http.ListenAndServe(cfg.AdminAddr, srv.AdminHandler())
+				if err != nil {
+					log.WithError(err).Error("admin server failed")
+				}
+			}()
+			log.WithField("addr", cfg.AdminAddr).Info("started admin server")
+		}
+
 		if cfg.PProfAddr != "" {
 			go pprof.Serve(cfg.PProfAddr)
 		}
@@ -12,17 +12,21 @@ import (
 	"html"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/remotes"
 	"github.com/docker/distribution/reference"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
@@ -38,6 +42,7 @@ type Server struct {
 	Resolver ResolverProvider
 
 	refstore *refstore
+	metrics  *metrics
 }
 
 type BlobserveInlineVars struct {
@@ -47,14 +52,22 @@ type BlobserveInlineVars struct {
 
 type BlobSpace struct {
 	Location string `json:"location"`
-	MaxSize  int64  `json:"maxSizeBytes,omitempty"`
+	// MaxSize is the size the blobspace is garbage collected to. Zero means the blobspace is unbounded.
+	MaxSize int64 `json:"maxSizeBytes,omitempty"`
+	// EvictionPolicy determines which blobs are removed first when the blobspace is full. Defaults to lru.
+	EvictionPolicy EvictionPolicy `json:"evictionPolicy,omitempty"`
+	// PreCompress stores brotli and zstd compressed variants of static assets when extracting images,
+	// and serves them to clients which accept them.
+	PreCompress bool `json:"preCompress,omitempty"`
 }
 
 type Repo struct {
-	PrePull      []string            `json:"prePull,omitempty"`
-	Workdir      string              `json:"workdir,omitempty"`
-	Replacements []StringReplacement `json:"replacements,omitempty"`
-	InlineStatic []InlineReplacement `json:"inlineStatic,omitempty"`
+	PrePull []string `json:"prePull,omitempty"`
+	Workdir string   `json:"workdir,omitempty"`
+	// Replacements are applied before the transformations and behave like transformations of type replace
+	Replacements    []StringReplacement `json:"replacements,omitempty"`
+	Transformations []Transformation    `json:"transformations,omitempty"`
+	InlineStatic    []InlineReplacement `json:"inlineStatic,omitempty"`
 }
 
 // Config configures a server.
@@ -117,7 +130,8 @@ var ReferenceRegexp = expression(reference.NameRegexp,
 
 // NewServer creates a new blob server
 func NewServer(cfg Config, resolver ResolverProvider) (*Server, error) {
-	refstore, err := newRefStore(cfg, resolver)
+	metrics := newMetrics()
+	refstore, err := newRefStore(cfg, resolver, metrics)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +140,7 @@ func NewServer(cfg Config, resolver ResolverProvider) (*Server, error) {
 		Config:   cfg,
 		Resolver: resolver,
 		refstore: refstore,
+		metrics:  metrics,
 	}
 	for repo, repoCfg := range cfg.Repos {
 		for _, ver := range repoCfg.PrePull {
@@ -161,6 +176,81 @@ func (reg *Server) Serve() error {
 	return http.ListenAndServe(fmt.Sprintf(":%d", reg.Config.Port), h)
 }
 
+// RegisterMetrics registers the blobspace metrics
+func (reg *Server) RegisterMetrics(r prometheus.Registerer) error {
+	return reg.metrics.register(r)
+}
+
+// AdminHandler serves endpoints to inspect (GET /blobspace) and purge (DELETE /blobspace[/<image-ref>]) the blobspace,
+// and to prewarm it with images (POST /prewarm).
+func (reg *Server) AdminHandler() http.Handler {
+	r := mux.NewRouter()
+	r.Path("/prewarm").Methods(http.MethodPost).HandlerFunc(reg.prewarm)
+	r.Path("/blobspace").Methods(http.MethodGet).HandlerFunc(reg.listBlobspace)
+	r.Path("/blobspace").Methods(http.MethodDelete).HandlerFunc(reg.purgeBlobspace)
+	r.Path(`/blobspace/{image:` + ReferenceRegexp.String() + `}`).Methods(http.MethodDelete).HandlerFunc(reg.purgeBlobspace)
+	return r
+}
+
+func (reg *Server) listBlobspace(w http.ResponseWriter, req *http.Request) {
+	type blob struct {
+		BlobInfo
+		Refs []string `json:"refs,omitempty"`
+	}
+
+	refs := make(map[string][]string)
+	for ref, dgst := range reg.refstore.Refs() {
+		refs[dgst] = append(refs[dgst], ref)
+	}
+	infos := reg.refstore.blobspace.List()
+	res := make([]blob, 0, len(infos))
+	for _, info := range infos {
+		sort.Strings(refs[info.Digest])
+		res = append(res, blob{BlobInfo: info, Refs: refs[info.Digest]})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(res)
+	if err != nil {
+		log.WithError(err).Warn("cannot list blobspace")
+	}
+}
+
+func (reg *Server) purgeBlobspace(w http.ResponseWriter, req *http.Request) {
+	var refs []string
+	if image, ok := mux.Vars(req)["image"]; ok {
+		pref, err := reference.ParseNamed(image)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cannot parse image '%q': %q", html.EscapeString(image), err), http.StatusBadRequest)
+			return
+		}
+		refs = []string{pref.String()}
+	} else {
+		for ref := range reg.refstore.Refs() {
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, ref := range refs {
+		err := reg.refstore.Purge(ref)
+		if errdefs.IsNotFound(err) && len(refs) == 1 {
+			http.Error(w, fmt.Sprintf("image %s not found", html.EscapeString(ref)), http.StatusNotFound)
+			return
+		}
+		if errdefs.IsUnavailable(err) {
+			http.Error(w, html.EscapeString(err.Error()), http.StatusConflict)
+			return
+		}
+		if err != nil && !errdefs.IsNotFound(err) {
+			log.WithError(err).WithField("ref", ref).Warn("cannot purge image from blobspace")
+			http.Error(w, fmt.Sprintf("cannot purge %s: %q", html.EscapeString(ref), err), http.StatusInternalServerError)
+			return
+		}
+		log.WithField("ref", ref).Info("purged image from blobspace")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // MustServe calls serve and logs any error as Fatal
 func (reg *Server) MustServe() {
 	err := reg.Serve()
@@ -169,6 +259,113 @@ func (reg *Server) MustServe() {
 	}
 }
 
+// PrewarmRequest lists the images the blobspace is prewarmed with. If Images is empty,
+// the images configured for pre-pulling are used.
+type PrewarmRequest struct {
+	Images []string `json:"images,omitempty"`
+}
+
+// PrewarmResult is the outcome of prewarming a single image
+type PrewarmResult struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// prewarm downloads and extracts images ahead of their first use, e.g. when a new IDE version is rolled out.
+// Unless the request has the wait=true query parameter, the images are prepared in the background.
+func (reg *Server) prewarm(w http.ResponseWriter, req *http.Request) {
+	var preq PrewarmRequest
+	if req.ContentLength != 0 {
+		err := json.NewDecoder(req.Body).Decode(&preq)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cannot parse prewarm request: %q", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	refs, err := reg.prewarmRefs(preq.Images)
+	if err != nil {
+		http.Error(w, html.EscapeString(err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if req.URL.Query().Get("wait") != "true" {
+		go reg.Prewarm(context.Background(), refs)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	res := reg.Prewarm(req.Context(), refs)
+	status := http.StatusOK
+	for _, r := range res {
+		if r.Error != "" {
+			status = http.StatusInternalServerError
+			break
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err = json.NewEncoder(w).Encode(res)
+	if err != nil {
+		log.WithError(err).Warn("cannot write prewarm result")
+	}
+}
+
+// prewarmRefs validates the images of a prewarm request and normalises them to refs
+func (reg *Server) prewarmRefs(images []string) ([]string, error) {
+	if len(images) == 0 {
+		for repo, repoCfg := range reg.Config.Repos {
+			for _, ver := range repoCfg.PrePull {
+				images = append(images, repo+":"+ver)
+			}
+		}
+		sort.Strings(images)
+	}
+
+	refs := make([]string, 0, len(images))
+	for _, image := range images {
+		pref, err := reference.ParseNamed(image)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse image %q: %w", image, err)
+		}
+		_, hasTag := pref.(reference.Tagged)
+		_, hasDigest := pref.(reference.Digested)
+		if !hasTag && !hasDigest {
+			return nil, xerrors.Errorf("cannot parse image %q: tag or digest is missing", image)
+		}
+		if _, ok := reg.Config.Repos[pref.Name()]; !ok && !reg.Config.AllowAnyRepo {
+			return nil, xerrors.Errorf("forbidden repo: %q", pref.Name())
+		}
+		refs = append(refs, pref.String())
+	}
+	return refs, nil
+}
+
+// Prewarm prepares the blobs of all refs in parallel
+func (reg *Server) Prewarm(ctx context.Context, refs []string) []PrewarmResult {
+	res := make([]PrewarmResult, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref string) {
+			defer wg.Done()
+
+			res[i].Image = ref
+			_, dgst, err := reg.refstore.BlobFor(ctx, ref, false)
+			if err != nil {
+				log.WithError(err).WithField("ref", ref).Warn("cannot prewarm blobspace")
+				res[i].Error = err.Error()
+				return
+			}
+			res[i].Digest = dgst
+			log.WithField("ref", ref).WithField("digest", dgst).Info("prewarmed blobspace")
+		}(i, ref)
+	}
+	wg.Wait()
+	return res
+}
+
 // serve serves a single file from an image
 func (reg *Server) serve(w http.ResponseWriter, req *http.Request) {
 	defer func() {
@@ -204,9 +401,11 @@ func (reg *Server) serve(w http.ResponseWriter, req *http.Request) {
 
 	var workdir string
 	var inlineReplacements []InlineReplacement
+	var headers []headerInjection
 	if cfg, ok := reg.Config.Repos[repo]; ok {
 		workdir = cfg.Workdir
 		inlineReplacements = cfg.InlineStatic
+		headers = reg.refstore.config[repo].Headers
 	} else if !reg.Config.AllowAnyRepo {
 		log.WithField("repo", repo).Debug("forbidden repo access attempt")
 		http.Error(w, fmt.Sprintf("forbidden repo: %q", html.EscapeString(repo)), http.StatusForbidden)
@@ -230,7 +429,7 @@ func (reg *Server) serve(w http.ResponseWriter, req *http.Request) {
 		req.URL.Path += "/"
 	}
 
-	w.Header().Set("ETag", hash)
+	w.Header().Set("ETag", etag(hash, ""))
 	w.Header().Set("Cache-Control", "no-cache")
 
 	// http.FileServer has a special case where ServeFile redirects any request where r.URL.Path
@@ -239,6 +438,7 @@ func (reg *Server) serve(w http.ResponseWriter, req *http.Request) {
 	imagePath := strings.TrimPrefix(req.URL.Path, pathPrefix)
 	if imagePath == "/index.html" || imagePath == "/" {
 		fn := filepath.Join(workdir, "index.html")
+		injectHeaders(w, fn, headers)
 
 		fc, err := blob.Open(fn)
 		if err != nil {
@@ -261,14 +461,65 @@ func (reg *Server) serve(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	injectHeaders(w, filepath.Join(workdir, imagePath), headers)
+
 	var fs http.FileSystem
 	fs = blob
 	if workdir != "" {
 		fs = prefixingFilesystem{Prefix: workdir, FS: fs}
 	}
+	if reg.Config.BlobSpace.PreCompress {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if servePrecompressed(w, req, fs, imagePath, hash) {
+			return
+		}
+	}
 	http.StripPrefix(pathPrefix, http.FileServer(fs)).ServeHTTP(w, req)
 }
 
+// servePrecompressed serves the pre-compressed variant of a file if the client accepts one.
+// Range requests are served from the compressed variant. Returns false if there is no such variant.
+func servePrecompressed(w http.ResponseWriter, req *http.Request, fs http.FileSystem, name, hash string) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if strings.HasSuffix(name, "/") {
+		return false
+	}
+
+	for _, enc := range acceptedEncodings(req.Header.Get("Accept-Encoding")) {
+		f, err := fs.Open(name + enc.Extension)
+		if err != nil {
+			continue
+		}
+		stat, err := f.Stat()
+		if err != nil || stat.IsDir() {
+			f.Close()
+			continue
+		}
+		defer f.Close()
+
+		ctype := mime.TypeByExtension(filepath.Ext(name))
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", ctype)
+		w.Header().Set("Content-Encoding", enc.Name)
+		w.Header().Set("ETag", etag(hash, enc.Name))
+		http.ServeContent(w, req, name, stat.ModTime(), f)
+		return true
+	}
+	return false
+}
+
+// etag produces a strong entity tag for a file of a blob. Strong tags allow clients to resume downloads using If-Range.
+func etag(hash, encoding string) string {
+	if encoding != "" {
+		hash += "-" + encoding
+	}
+	return `"` + hash + `"`
+}
+
 func inlineVars(req *http.Request, r io.ReadSeeker, inlineReplacements []InlineReplacement) (io.ReadSeeker, error) {
 	inlineVarsValue := req.Header.Get("X-BlobServe-InlineVars")
 	if len(inlineReplacements) == 0 || inlineVarsValue == "" {
@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package blobserve
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrewarmRefs(t *testing.T) {
+	cfg := Config{
+		Repos: map[string]Repo{
+			"gitpod.io/ide":        {PrePull: []string{"latest", "commit-1"}},
+			"gitpod.io/supervisor": {PrePull: []string{"commit-2"}},
+			"gitpod.io/other":      {},
+		},
+	}
+	type Expectation struct {
+		Refs  []string
+		Error string
+	}
+	tests := []struct {
+		Name         string
+		AllowAnyRepo bool
+		Images       []string
+		Expectation  Expectation
+	}{
+		{
+			Name: "configured images",
+			Expectation: Expectation{
+				Refs: []string{"gitpod.io/ide:commit-1", "gitpod.io/ide:latest", "gitpod.io/supervisor:commit-2"},
+			},
+		},
+		{
+			Name:   "explicit images",
+			Images: []string{"gitpod.io/other:commit-3", "gitpod.io/ide@sha256:4970405cb2a3a461cc00fd755712beded51919d7e69270d7d10d0dcf5e209714"},
+			Expectation: Expectation{
+				Refs: []string{"gitpod.io/other:commit-3", "gitpod.io/ide@sha256:4970405cb2a3a461cc00fd755712beded51919d7e69270d7d10d0dcf5e209714"},
+			},
+		},
+		{
+			Name:        "missing tag",
+			Images:      []string{"gitpod.io/ide"},
+			Expectation: Expectation{Error: `cannot parse image "gitpod.io/ide": tag or digest is missing`},
+		},
+		{
+			Name:        "forbidden repo",
+			Images:      []string{"docker.io/library/alpine:latest"},
+			Expectation: Expectation{Error: `forbidden repo: "docker.io/library/alpine"`},
+		},
+		{
+			Name:         "any repo",
+			AllowAnyRepo: true,
+			Images:       []string{"docker.io/library/alpine:latest"},
+			Expectation:  Expectation{Refs: []string{"docker.io/library/alpine:latest"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			c := cfg
+			c.AllowAnyRepo = test.AllowAnyRepo
+			reg := &Server{Config: c}
+
+			var act Expectation
+			refs, err := reg.prewarmRefs(test.Images)
+			if err != nil {
+				act.Error = err.Error()
+			}
+			act.Refs = refs
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected prewarmRefs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -10,13 +10,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/containerd/containerd/errdefs"
@@ -31,17 +35,59 @@ type FileModifier func(in io.Reader, out io.Writer) error
 type blobspace interface {
 	Get(name string) (fs http.FileSystem, state blobstate)
 	AddFromTarGzip(ctx context.Context, name string, in io.Reader, modifications []blobModifier) (err error)
+	Remove(name string) error
+	List() []BlobInfo
+}
+
+// EvictionPolicy determines which blobs are removed first when the blobspace is full
+type EvictionPolicy string
+
+const (
+	// EvictionPolicyLRU removes the least recently used blobs first
+	EvictionPolicyLRU EvictionPolicy = "lru"
+	// EvictionPolicyLFU removes the least frequently used blobs first. Uses are counted in memory only,
+	// hence after a restart all blobs start from zero and are evicted in LRU order until they're used again.
+	EvictionPolicyLFU EvictionPolicy = "lfu"
+)
+
+// BlobInfo describes a blob in the blobspace
+type BlobInfo struct {
+	Digest   string    `json:"digest"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+	Uses     int64     `json:"uses"`
+	Ready    bool      `json:"ready"`
 }
 
 type diskBlobspace struct {
 	Location string
 	MaxSize  int64
+	Policy   EvictionPolicy
+	// PreCompress stores brotli and zstd compressed variants of static assets next to them
+	PreCompress bool
+
+	mu   sync.Mutex
+	uses map[string]int64
+
+	metrics *metrics
 }
 
-func newBlobSpace(loc string, maxSize int64, housekeepingInterval time.Duration) (bs *diskBlobspace, err error) {
+func newBlobSpace(cfg BlobSpace, housekeepingInterval time.Duration, metrics *metrics) (bs *diskBlobspace, err error) {
+	var (
+		loc     = cfg.Location
+		maxSize = cfg.MaxSize
+		policy  = cfg.EvictionPolicy
+	)
 	if tproot := os.Getenv("TELEPRESENCE_ROOT"); tproot != "" {
 		loc = filepath.Join(tproot, loc)
 	}
+	switch policy {
+	case "":
+		policy = EvictionPolicyLRU
+	case EvictionPolicyLRU, EvictionPolicyLFU:
+	default:
+		return nil, xerrors.Errorf("unknown eviction policy: %s", policy)
+	}
 
 	err = os.MkdirAll(loc, 0755)
 	if err != nil {
@@ -49,8 +95,12 @@ func newBlobSpace(loc string, maxSize int64, housekeepingInterval time.Duration)
 	}
 
 	bs = &diskBlobspace{
-		Location: loc,
-		MaxSize:  maxSize,
+		Location:    loc,
+		MaxSize:     maxSize,
+		Policy:      policy,
+		PreCompress: cfg.PreCompress,
+		uses:        make(map[string]int64),
+		metrics:     metrics,
 	}
 	if maxSize > 0 {
 		go bs.collectGarbage(housekeepingInterval)
@@ -92,6 +142,7 @@ func (b *diskBlobspace) collectGarbage(interval time.Duration) {
 			}
 
 			blob := getGCBlob(b.Location, f)
+			blob.Uses = b.usesOf(f.Name())
 			if blob.Size == 0 && time.Since(blob.LastUsed) > minBlobAge {
 				// this blob has neither been used nor ready for long enough
 				// let's remove it
@@ -112,8 +163,7 @@ func (b *diskBlobspace) collectGarbage(interval time.Duration) {
 
 		var spaceFreed int64
 		if totalSize > b.MaxSize {
-			// oldest first
-			sort.Slice(blobs, func(i, j int) bool { return blobs[j].LastUsed.After(blobs[i].LastUsed) })
+			sortForEviction(blobs, b.Policy)
 
 			for totalSize > b.MaxSize && len(blobs) > 0 {
 				blob := blobs[0]
@@ -121,18 +171,17 @@ func (b *diskBlobspace) collectGarbage(interval time.Duration) {
 
 				log.WithField("location", blob.F).WithField("lastUsed", blob.LastUsed.Format(time.RFC3339Nano)).Info("removing old blob to make some space")
 
-				os.Remove(fmt.Sprintf("%s.ready", blob.F))
-				os.Remove(fmt.Sprintf("%s.size", blob.F))
-				os.Remove(fmt.Sprintf("%s.used", blob.F))
-				err = os.RemoveAll(blob.F)
+				err = b.Remove(filepath.Base(blob.F))
 				if err != nil {
 					log.WithError(err).WithField("location", blob.F).Error("cannot remove blob")
 					continue
 				}
 				totalSize -= blob.Size
 				spaceFreed += blob.Size
+				b.metrics.evicted()
 			}
 		}
+		b.metrics.setSize(totalSize)
 		log.WithField("spaceFreed", spaceFreed).Info("blobspace GC complete")
 
 		<-t.C
@@ -143,6 +192,24 @@ type gcBlob struct {
 	F        string
 	LastUsed time.Time
 	Size     int64
+	Uses     int64
+}
+
+// sortForEviction sorts blobs so that the ones to be evicted first come first
+func sortForEviction(blobs []gcBlob, policy EvictionPolicy) {
+	sort.SliceStable(blobs, func(i, j int) bool {
+		if policy == EvictionPolicyLFU && blobs[i].Uses != blobs[j].Uses {
+			return blobs[i].Uses < blobs[j].Uses
+		}
+		// oldest first
+		return blobs[j].LastUsed.After(blobs[i].LastUsed)
+	})
+}
+
+func (b *diskBlobspace) usesOf(name string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.uses[name]
 }
 
 func getGCBlob(wd string, f os.DirEntry) (blob gcBlob) {
@@ -181,9 +248,52 @@ func (b *diskBlobspace) Get(name string) (fs http.FileSystem, state blobstate) {
 	}
 
 	os.WriteFile(fmt.Sprintf("%s.used", fn), nil, 0644)
+	b.mu.Lock()
+	b.uses[name]++
+	b.mu.Unlock()
 	return http.Dir(fn), blobReady
 }
 
+// Remove removes a blob from the blobspace
+func (b *diskBlobspace) Remove(name string) error {
+	fn := filepath.Join(b.Location, name)
+	os.Remove(fmt.Sprintf("%s.ready", fn))
+	os.Remove(fmt.Sprintf("%s.size", fn))
+	os.Remove(fmt.Sprintf("%s.used", fn))
+
+	b.mu.Lock()
+	delete(b.uses, name)
+	b.mu.Unlock()
+
+	return os.RemoveAll(fn)
+}
+
+// List lists all blobs in the blobspace
+func (b *diskBlobspace) List() []BlobInfo {
+	files, err := os.ReadDir(b.Location)
+	if err != nil {
+		log.WithError(err).WithField("location", b.Location).Error("blobspace cannot list files in working area")
+		return nil
+	}
+
+	res := make([]BlobInfo, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		blob := getGCBlob(b.Location, f)
+		_, err := os.Stat(fmt.Sprintf("%s.ready", blob.F))
+		res = append(res, BlobInfo{
+			Digest:   f.Name(),
+			Size:     blob.Size,
+			LastUsed: blob.LastUsed,
+			Uses:     b.usesOf(f.Name()),
+			Ready:    err == nil,
+		})
+	}
+	return res
+}
+
 // AddFromTar adds content to this store under the given name.
 // In is expected to yield an uncompressed tar stream.
 func (b *diskBlobspace) AddFromTar(ctx context.Context, name string, in io.Reader, modifications []blobModifier) (err error) {
@@ -214,10 +324,25 @@ func (b *diskBlobspace) AddFromTar(ctx context.Context, name string, in io.Reade
 	}
 
 	for _, mod := range modifications {
-		err := b.modifyFile(name, mod.Path, mod.Modifier)
+		files, err := b.matchFiles(name, mod.Path)
+		if err != nil {
+			log.WithField("path", mod.Path).WithError(err).Error("Blobspace::AddFromTar error while trying to find files to modify")
+			continue
+		}
+		for _, f := range files {
+			err := b.modifyFile(name, f, mod.Modifier)
+			if err != nil {
+				log.WithField("path", f).WithError(err).Error("Blobspace::AddFromTar error while trying to modify file")
+			}
+		}
+	}
+
+	if b.PreCompress {
+		n, err := precompress(fn)
 		if err != nil {
-			log.WithField("path", mod.Path).WithError(err).Error("Blobspace::AddFromTar error while trying to modify file")
+			log.WithError(err).WithField("blob", name).Warn("cannot pre-compress static assets - serving them uncompressed")
 		}
+		cw.C += n
 	}
 
 	os.WriteFile(fmt.Sprintf("%s.size", fn), []byte(fmt.Sprintf("%d", cw.C)), 0644)
@@ -238,6 +363,35 @@ func (b *diskBlobspace) AddFromTarGzip(ctx context.Context, name string, in io.R
 	return b.AddFromTar(ctx, name, gin, modifications)
 }
 
+// matchFiles returns the files of a blob matching a pattern (path.Match syntax, relative to the blob root).
+// Patterns without wildcards are returned as-is.
+func (b *diskBlobspace) matchFiles(blobName, pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		return []string{pattern}, nil
+	}
+
+	root := filepath.Join(b.Location, blobName)
+	var res []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = "/" + filepath.ToSlash(rel)
+		if match, _ := path.Match(pattern, rel); match {
+			res = append(res, rel)
+		}
+		return nil
+	})
+	return res, err
+}
+
 // ModifyFile modifies a file in the blobspace.
 // Beware: this function is not synchronised.
 // Beware: this function is not safe for user-provided input (does not file path sanitisation).
@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -181,3 +182,44 @@ func Test_inlineVars(t *testing.T) {
 		})
 	}
 }
+
+func Test_sortForEviction(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	blobs := func(restarted bool) []gcBlob {
+		res := []gcBlob{
+			{F: "recent-rare", LastUsed: now, Uses: 1},
+			{F: "old-frequent", LastUsed: now.Add(-2 * time.Hour), Uses: 10},
+			{F: "older-rare", LastUsed: now.Add(-1 * time.Hour), Uses: 1},
+		}
+		if restarted {
+			for i := range res {
+				res[i].Uses = 0
+			}
+		}
+		return res
+	}
+	tests := []struct {
+		Name      string
+		Policy    EvictionPolicy
+		Restarted bool
+		Expected  []string
+	}{
+		{Name: "lru", Policy: EvictionPolicyLRU, Expected: []string{"old-frequent", "older-rare", "recent-rare"}},
+		{Name: "lfu", Policy: EvictionPolicyLFU, Expected: []string{"older-rare", "recent-rare", "old-frequent"}},
+		{Name: "lfu after restart", Policy: EvictionPolicyLFU, Restarted: true, Expected: []string{"old-frequent", "older-rare", "recent-rare"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			bs := blobs(tt.Restarted)
+			sortForEviction(bs, tt.Policy)
+
+			var act []string
+			for _, b := range bs {
+				act = append(act, b.F)
+			}
+			if diff := cmp.Diff(tt.Expected, act); diff != "" {
+				t.Errorf("sortForEviction() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
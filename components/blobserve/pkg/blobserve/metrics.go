@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package blobserve
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	cacheResultHit  = "hit"
+	cacheResultMiss = "miss"
+)
+
+// metrics describe the state of the blobspace. A nil metrics is valid and records nothing.
+type metrics struct {
+	Requests  *prometheus.CounterVec
+	Bytes     prometheus.Gauge
+	Evictions prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blobserve_blobspace_requests_total",
+			Help: "Number of blob requests, by blobspace cache result",
+		}, []string{"result"}),
+		Bytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "blobserve_blobspace_bytes",
+			Help: "Size of the blobs in the blobspace as of the last garbage collection",
+		}),
+		Evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "blobserve_blobspace_evictions_total",
+			Help: "Number of blobs evicted from the blobspace to make space",
+		}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.Requests, m.Bytes, m.Evictions} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *metrics) request(result string) {
+	if m == nil {
+		return
+	}
+	m.Requests.WithLabelValues(result).Inc()
+}
+
+func (m *metrics) setSize(size int64) {
+	if m == nil {
+		return
+	}
+	m.Bytes.Set(float64(size))
+}
+
+func (m *metrics) evicted() {
+	if m == nil {
+		return
+	}
+	m.Evictions.Inc()
+}
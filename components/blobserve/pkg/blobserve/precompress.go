@@ -0,0 +1,183 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package blobserve
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// minPrecompressSize is the size below which files are not worth compressing
+	minPrecompressSize = 1024
+	// brotliLevel trades compression ratio for extraction time - brotli's best compression is too slow for large IDE images
+	brotliLevel = 9
+)
+
+// contentEncoding is a compressed variant of a file, stored next to the file with its extension appended
+type contentEncoding struct {
+	// Name is the name of the encoding in Accept-Encoding and Content-Encoding headers
+	Name      string
+	Extension string
+	Writer    func(out io.Writer) (io.WriteCloser, error)
+}
+
+// contentEncodings are the pre-compressed variants we produce, in order of preference
+var contentEncodings = []contentEncoding{
+	{
+		Name:      "br",
+		Extension: ".br",
+		Writer: func(out io.Writer) (io.WriteCloser, error) {
+			return brotli.NewWriterLevel(out, brotliLevel), nil
+		},
+	},
+	{
+		Name:      "zstd",
+		Extension: ".zst",
+		Writer: func(out io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(out, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+		},
+	},
+}
+
+// precompressedExtensions are the file types which compress well
+var precompressedExtensions = map[string]struct{}{
+	".css":  {},
+	".eot":  {},
+	".html": {},
+	".js":   {},
+	".json": {},
+	".map":  {},
+	".mjs":  {},
+	".otf":  {},
+	".svg":  {},
+	".ttf":  {},
+	".txt":  {},
+	".wasm": {},
+	".xml":  {},
+}
+
+// precompress stores compressed variants of all static assets below dir and returns the number of bytes written
+func precompress(dir string) (size int64, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if _, ok := precompressedExtensions[strings.ToLower(filepath.Ext(path))]; !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() < minPrecompressSize {
+			return nil
+		}
+
+		for _, enc := range contentEncodings {
+			n, err := compressFile(path, info.Size(), enc)
+			if err != nil {
+				return xerrors.Errorf("cannot compress %s: %w", path, err)
+			}
+			size += n
+		}
+		return nil
+	})
+	return
+}
+
+// compressFile stores the compressed variant of fn unless it ends up larger than the original
+func compressFile(fn string, size int64, enc contentEncoding) (n int64, err error) {
+	in, err := os.Open(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	dst := fn + enc.Extension
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil || n == 0 {
+			os.Remove(dst)
+		}
+	}()
+
+	var cw countingWriter
+	cout, err := enc.Writer(io.MultiWriter(out, &cw))
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	_, err = io.Copy(cout, in)
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	err = cout.Close()
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	err = out.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	if cw.C >= size {
+		return 0, nil
+	}
+	return cw.C, nil
+}
+
+// acceptedEncodings returns the pre-compressed variants a client accepts, in order of preference
+func acceptedEncodings(acceptEncoding string) []contentEncoding {
+	if acceptEncoding == "" {
+		return nil
+	}
+
+	accepted := make(map[string]bool)
+	for _, e := range strings.Split(acceptEncoding, ",") {
+		segs := strings.Split(e, ";")
+		name := strings.ToLower(strings.TrimSpace(segs[0]))
+		q := 1.0
+		for _, p := range segs[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64)
+			if err == nil {
+				q = v
+			}
+		}
+		accepted[name] = q > 0
+	}
+
+	var res []contentEncoding
+	for _, enc := range contentEncodings {
+		ok, explicit := accepted[enc.Name]
+		if !explicit {
+			ok = accepted["*"]
+		}
+		if ok {
+			res = append(res, enc)
+		}
+	}
+	return res
+}
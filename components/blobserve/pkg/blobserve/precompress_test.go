@@ -0,0 +1,159 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package blobserve
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAcceptedEncodings(t *testing.T) {
+	tests := []struct {
+		AcceptEncoding string
+		Expected       []string
+	}{
+		{AcceptEncoding: "", Expected: nil},
+		{AcceptEncoding: "gzip, deflate", Expected: nil},
+		{AcceptEncoding: "gzip, deflate, br", Expected: []string{"br"}},
+		{AcceptEncoding: "zstd, br;q=0.9", Expected: []string{"br", "zstd"}},
+		{AcceptEncoding: "br;q=0, zstd", Expected: []string{"zstd"}},
+		{AcceptEncoding: "*", Expected: []string{"br", "zstd"}},
+		{AcceptEncoding: "*, zstd;q=0", Expected: []string{"br"}},
+	}
+	for _, test := range tests {
+		t.Run(test.AcceptEncoding, func(t *testing.T) {
+			var act []string
+			for _, enc := range acceptedEncodings(test.AcceptEncoding) {
+				act = append(act, enc.Name)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected accepted encodings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServePrecompressed(t *testing.T) {
+	tmp, err := os.MkdirTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	content := []byte(strings.Repeat("console.log('hello world');\n", 100))
+	err = os.WriteFile(filepath.Join(tmp, "main.js"), content, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(tmp, "small.js"), []byte("let a;"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = precompress(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"main.js.br", "main.js.zst"} {
+		if _, err := os.Stat(filepath.Join(tmp, fn)); err != nil {
+			t.Errorf("expected %s to exist: %v", fn, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "small.js.br")); !os.IsNotExist(err) {
+		t.Errorf("expected small files not to be compressed")
+	}
+	compressed, err := os.ReadFile(filepath.Join(tmp, "main.js.br"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Expectation struct {
+		Served          bool
+		Status          int
+		ContentEncoding string
+		ETag            string
+		Body            []byte
+	}
+	tests := []struct {
+		Name        string
+		Path        string
+		Header      http.Header
+		Expectation Expectation
+	}{
+		{
+			Name:        "identity",
+			Path:        "/main.js",
+			Expectation: Expectation{},
+		},
+		{
+			Name:   "brotli",
+			Path:   "/main.js",
+			Header: http.Header{"Accept-Encoding": []string{"gzip, br"}},
+			Expectation: Expectation{
+				Served:          true,
+				Status:          http.StatusOK,
+				ContentEncoding: "br",
+				ETag:            `"hash-br"`,
+				Body:            compressed,
+			},
+		},
+		{
+			Name:   "range",
+			Path:   "/main.js",
+			Header: http.Header{"Accept-Encoding": []string{"br"}, "Range": []string{"bytes=0-9"}},
+			Expectation: Expectation{
+				Served:          true,
+				Status:          http.StatusPartialContent,
+				ContentEncoding: "br",
+				ETag:            `"hash-br"`,
+				Body:            compressed[:10],
+			},
+		},
+		{
+			Name:        "not compressed",
+			Path:        "/small.js",
+			Header:      http.Header{"Accept-Encoding": []string{"br, zstd"}},
+			Expectation: Expectation{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+			for k, v := range test.Header {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+
+			var act Expectation
+			act.Served = servePrecompressed(rec, req, http.Dir(tmp), test.Path, "hash")
+			if act.Served {
+				act.Status = rec.Code
+				act.ContentEncoding = rec.Header().Get("Content-Encoding")
+				act.ETag = rec.Header().Get("ETag")
+				act.Body = rec.Body.Bytes()
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected response (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	decompressed, err := io.ReadAll(brotli.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, content) {
+		t.Errorf("pre-compressed content does not match original")
+	}
+}
@@ -38,6 +38,8 @@ type blobConfig struct {
 	// Workdir is the path that files are served from relative to the blob root.
 	Workdir  string
 	Modifier []blobModifier
+	// Headers are added to the responses serving files of the blob
+	Headers []headerInjection
 }
 
 type refstore struct {
@@ -48,27 +50,34 @@ type refstore struct {
 	requests  chan downloadRequest
 	blobspace blobspace
 	config    map[string]blobConfig
+	metrics   *metrics
 
 	close chan struct{}
 	once  *sync.Once
 }
 
-func newRefStore(cfg Config, resolver ResolverProvider) (*refstore, error) {
-	bs, err := newBlobSpace(cfg.BlobSpace.Location, cfg.BlobSpace.MaxSize, 10*time.Minute)
+func newRefStore(cfg Config, resolver ResolverProvider, metrics *metrics) (*refstore, error) {
+	bs, err := newBlobSpace(cfg.BlobSpace, 10*time.Minute, metrics)
 	if err != nil {
 		return nil, err
 	}
 
 	config := make(map[string]blobConfig)
 	for ref, repo := range cfg.Repos {
-		mods := make([]blobModifier, 0, len(repo.Replacements))
+		ts := make([]Transformation, 0, len(repo.Replacements)+len(repo.Transformations))
 		for _, mod := range repo.Replacements {
-			mods = append(mods, blobModifier{Path: mod.Path, Modifier: modifySearchAndReplace(mod.Search, mod.Replacement)})
+			ts = append(ts, Transformation{Type: TransformationReplace, Path: mod.Path, Search: mod.Search, Replacement: mod.Replacement})
+		}
+		ts = append(ts, repo.Transformations...)
+		mods, headers, err := compileTransformations(ts)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid transformations for %s: %w", ref, err)
 		}
 
 		config[ref] = blobConfig{
 			Workdir:  repo.Workdir,
 			Modifier: mods,
+			Headers:  headers,
 		}
 	}
 
@@ -76,6 +85,7 @@ func newRefStore(cfg Config, resolver ResolverProvider) (*refstore, error) {
 		Resolver:  resolver,
 		blobspace: bs,
 		config:    config,
+		metrics:   metrics,
 		refcache:  make(map[string]*refstate),
 		requests:  make(chan downloadRequest),
 		once:      &sync.Once{},
@@ -138,6 +148,7 @@ func (store *refstore) BlobFor(ctx context.Context, ref string, readOnly bool) (
 		}
 	}
 	if !exists && readOnly {
+		store.metrics.request(cacheResultMiss)
 		return nil, "", errdefs.ErrNotFound
 	}
 
@@ -147,7 +158,12 @@ func (store *refstore) BlobFor(ctx context.Context, ref string, readOnly bool) (
 		// hence blobState can validly be blobUnknown.
 		fs, blobState = store.blobspace.Get(rs.Digest)
 	}
+	if blobState == blobReady {
+		store.metrics.request(cacheResultHit)
+	}
 	if blobState == blobUnknown {
+		store.metrics.request(cacheResultMiss)
+
 		// if refcache thinks the blob should exist, but it doesn't, we force a redownload.
 		err = store.downloadBlobFor(ctx, ref, exists)
 		if err != nil {
@@ -181,6 +197,40 @@ func (store *refstore) BlobFor(ctx context.Context, ref string, readOnly bool) (
 	return fs, rs.Digest, nil
 }
 
+// Purge removes the blob of a ref from the blobspace. Other refs pointing to the same blob
+// will download it again when they're requested next.
+func (store *refstore) Purge(ref string) error {
+	store.mu.Lock()
+	rs, exists := store.refcache[ref]
+	if !exists {
+		store.mu.Unlock()
+		return errdefs.ErrNotFound
+	}
+	if !rs.Done() {
+		store.mu.Unlock()
+		return xerrors.Errorf("%s is still downloading: %w", ref, errdefs.ErrUnavailable)
+	}
+	delete(store.refcache, ref)
+	store.mu.Unlock()
+
+	return store.blobspace.Remove(rs.Digest)
+}
+
+// Refs returns the refs the store knows and the digest of their blob
+func (store *refstore) Refs() map[string]string {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	res := make(map[string]string, len(store.refcache))
+	for ref, rs := range store.refcache {
+		if !rs.Done() {
+			continue
+		}
+		res[ref] = rs.Digest
+	}
+	return res
+}
+
 func (store *refstore) Close() {
 	store.once.Do(func() {
 		close(store.requests)
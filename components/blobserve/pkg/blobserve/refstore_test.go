@@ -376,6 +376,30 @@ func TestBlobFor(t *testing.T) {
 				},
 			},
 		},
+		{
+			Desc: "purge removes ref and blob",
+			FetchableContent: map[string]provider{
+				refDescriptor: provideDescriptor,
+				hashManifest:  provideManifest,
+				hashLayer:     provideLayer,
+			},
+			ExtraAction: func(t *testing.T, s *refstore) error {
+				_, _, err := s.BlobFor(context.Background(), refDescriptor, false)
+				if err != nil {
+					return err
+				}
+				return s.Purge(refDescriptor)
+			},
+		},
+		{
+			Desc: "purge unknown ref",
+			ExtraAction: func(t *testing.T, s *refstore) error {
+				return s.Purge(refDescriptor)
+			},
+			Expectation: Expectation{
+				Error: "not found",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -447,6 +471,19 @@ func (s *inMemoryBlobspace) AddFromTarGzip(ctx context.Context, name string, in
 	return s.Adder(ctx, name, in)
 }
 
+func (s *inMemoryBlobspace) Remove(name string) error {
+	delete(s.Content, name)
+	return nil
+}
+
+func (s *inMemoryBlobspace) List() []BlobInfo {
+	res := make([]BlobInfo, 0, len(s.Content))
+	for name, state := range s.Content {
+		res = append(res, BlobInfo{Digest: name, Ready: state == blobReady})
+	}
+	return res
+}
+
 type provider func() ([]byte, error)
 
 type fakeFetcher struct {
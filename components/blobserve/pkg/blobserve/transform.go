@@ -0,0 +1,318 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package blobserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// TransformationType names a transformation plugin
+type TransformationType string
+
+const (
+	// TransformationReplace replaces all occurrences of a string
+	TransformationReplace TransformationType = "replace"
+	// TransformationRegex replaces all matches of a regular expression. The replacement can refer to groups using $1 or ${name}.
+	TransformationRegex TransformationType = "regex"
+	// TransformationJSONPatch applies an RFC 6902 JSON patch (add, remove and replace operations)
+	TransformationJSONPatch TransformationType = "jsonPatch"
+	// TransformationHeader adds headers to the responses serving a file
+	TransformationHeader TransformationType = "header"
+)
+
+// Transformation adapts the files of an image, e.g. to point third-party IDE assets to different endpoints.
+// Path is matched against the path of the files relative to the image root using path.Match syntax,
+// e.g. "/ide/out/vs/workbench/*.js". Transformations of a repo are applied in order.
+type Transformation struct {
+	Type TransformationType `json:"type"`
+	Path string             `json:"path"`
+
+	// Search and Replacement configure the replace and regex transformations
+	Search      string `json:"search,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+
+	// Patch configures the jsonPatch transformation
+	Patch []JSONPatchOperation `json:"patch,omitempty"`
+
+	// Headers configures the header transformation
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// JSONPatchOperation is a single operation of an RFC 6902 JSON patch
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// transformationPlugin compiles a transformation into a modifier of file content or a header injection
+type transformationPlugin func(t Transformation) (FileModifier, http.Header, error)
+
+var transformationPlugins = map[TransformationType]transformationPlugin{
+	TransformationReplace: func(t Transformation) (FileModifier, http.Header, error) {
+		if t.Search == "" {
+			return nil, nil, xerrors.Errorf("search must not be empty")
+		}
+		return modifySearchAndReplace(t.Search, t.Replacement), nil, nil
+	},
+	TransformationRegex: func(t Transformation) (FileModifier, http.Header, error) {
+		re, err := regexp.Compile(t.Search)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("invalid search expression: %w", err)
+		}
+		return modifyRegexReplace(re, t.Replacement), nil, nil
+	},
+	TransformationJSONPatch: func(t Transformation) (FileModifier, http.Header, error) {
+		if len(t.Patch) == 0 {
+			return nil, nil, xerrors.Errorf("patch must not be empty")
+		}
+		for i, op := range t.Patch {
+			switch op.Op {
+			case "add", "replace":
+				if len(op.Value) == 0 {
+					return nil, nil, xerrors.Errorf("operation %d: %s requires a value", i, op.Op)
+				}
+			case "remove":
+			default:
+				return nil, nil, xerrors.Errorf("operation %d: unsupported op %q", i, op.Op)
+			}
+		}
+		return modifyJSONPatch(t.Patch), nil, nil
+	},
+	TransformationHeader: func(t Transformation) (FileModifier, http.Header, error) {
+		if len(t.Headers) == 0 {
+			return nil, nil, xerrors.Errorf("headers must not be empty")
+		}
+		h := make(http.Header, len(t.Headers))
+		for k, v := range t.Headers {
+			h.Set(k, v)
+		}
+		return nil, h, nil
+	},
+}
+
+// headerInjection adds headers to the responses serving files matching a pattern
+type headerInjection struct {
+	Pattern string
+	Headers http.Header
+}
+
+// compileTransformations turns the transformations of a repo into blob modifiers applied when the image is
+// extracted, and header injections applied when its files are served.
+func compileTransformations(ts []Transformation) (mods []blobModifier, headers []headerInjection, err error) {
+	for i, t := range ts {
+		if _, err := path.Match(t.Path, ""); err != nil || t.Path == "" {
+			return nil, nil, xerrors.Errorf("transformation %d: invalid path %q", i, t.Path)
+		}
+		plugin, ok := transformationPlugins[t.Type]
+		if !ok {
+			return nil, nil, xerrors.Errorf("transformation %d: unknown type %q", i, t.Type)
+		}
+		mod, h, err := plugin(t)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("transformation %d (%s): %w", i, t.Type, err)
+		}
+		if mod != nil {
+			mods = append(mods, blobModifier{Path: t.Path, Modifier: mod})
+		}
+		if h != nil {
+			headers = append(headers, headerInjection{Pattern: t.Path, Headers: h})
+		}
+	}
+	return
+}
+
+// injectHeaders adds the headers of all injections matching the file fn, relative to the image root
+func injectHeaders(w http.ResponseWriter, fn string, injections []headerInjection) {
+	if !strings.HasPrefix(fn, "/") {
+		fn = "/" + fn
+	}
+	for _, inj := range injections {
+		if match, _ := path.Match(inj.Pattern, fn); !match {
+			continue
+		}
+		for k, vs := range inj.Headers {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+	}
+}
+
+func modifyRegexReplace(re *regexp.Regexp, replace string) FileModifier {
+	return func(in io.Reader, out io.Writer) error {
+		buf, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+		buf = re.ReplaceAll(buf, []byte(replace))
+		_, err = io.Copy(out, bytes.NewReader(buf))
+		return err
+	}
+}
+
+func modifyJSONPatch(patch []JSONPatchOperation) FileModifier {
+	return func(in io.Reader, out io.Writer) error {
+		var doc interface{}
+		err := json.NewDecoder(in).Decode(&doc)
+		if err != nil {
+			return xerrors.Errorf("cannot parse JSON: %w", err)
+		}
+		for _, op := range patch {
+			doc, err = applyJSONPatchOperation(doc, op)
+			if err != nil {
+				return xerrors.Errorf("cannot %s %s: %w", op.Op, op.Path, err)
+			}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "\t")
+		return enc.Encode(doc)
+	}
+}
+
+func applyJSONPatchOperation(doc interface{}, op JSONPatchOperation) (interface{}, error) {
+	var value interface{}
+	if op.Op != "remove" {
+		err := json.Unmarshal(op.Value, &value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tokens, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		if op.Op == "remove" {
+			return nil, xerrors.Errorf("cannot remove the whole document")
+		}
+		return value, nil
+	}
+
+	var (
+		parent = doc
+		last   = tokens[len(tokens)-1]
+	)
+	for _, tkn := range tokens[:len(tokens)-1] {
+		parent, err = jsonChild(parent, tkn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		_, exists := p[last]
+		switch {
+		case op.Op == "add":
+			p[last] = value
+		case !exists:
+			return nil, xerrors.Errorf("%s does not exist", op.Path)
+		case op.Op == "replace":
+			p[last] = value
+		case op.Op == "remove":
+			delete(p, last)
+		}
+		return doc, nil
+	case []interface{}:
+		// adding to or removing from a slice yields a new slice, which we store in the array's parent
+		idx := len(p)
+		if last != "-" {
+			idx, err = strconv.Atoi(last)
+			if err != nil || idx < 0 || idx > len(p) {
+				return nil, xerrors.Errorf("invalid array index %q", last)
+			}
+		}
+		var res []interface{}
+		switch op.Op {
+		case "add":
+			res = append(append(append(res, p[:idx]...), value), p[idx:]...)
+		case "replace", "remove":
+			if idx == len(p) {
+				return nil, xerrors.Errorf("invalid array index %q", last)
+			}
+			res = append(res, p[:idx]...)
+			if op.Op == "replace" {
+				res = append(res, value)
+			}
+			res = append(res, p[idx+1:]...)
+		}
+		return setJSONValue(doc, tokens[:len(tokens)-1], res)
+	default:
+		return nil, xerrors.Errorf("parent of %s is not an object or array", op.Path)
+	}
+}
+
+// setJSONValue replaces the value at the path of tokens in doc
+func setJSONValue(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent := doc
+	for _, tkn := range tokens[:len(tokens)-1] {
+		var err error
+		parent, err = jsonChild(parent, tkn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	last := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+	case []interface{}:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx >= len(p) {
+			return nil, xerrors.Errorf("invalid array index %q", last)
+		}
+		p[idx] = value
+	}
+	return doc, nil
+}
+
+func jsonChild(doc interface{}, token string) (interface{}, error) {
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		c, ok := d[token]
+		if !ok {
+			return nil, xerrors.Errorf("%s does not exist", token)
+		}
+		return c, nil
+	case []interface{}:
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= len(d) {
+			return nil, xerrors.Errorf("invalid array index %q", token)
+		}
+		return d[idx], nil
+	default:
+		return nil, xerrors.Errorf("%s is not an object or array", token)
+	}
+}
+
+// parseJSONPointer parses an RFC 6901 JSON pointer into its reference tokens
+func parseJSONPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, xerrors.Errorf("JSON pointer %q must start with /", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package blobserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransformations(t *testing.T) {
+	type Expectation struct {
+		Error   string
+		Content string
+	}
+	tests := []struct {
+		Name           string
+		Transformation Transformation
+		Input          string
+		Expectation    Expectation
+	}{
+		{
+			Name:           "replace",
+			Transformation: Transformation{Type: TransformationReplace, Search: "open-vsx.org", Replacement: "open-vsx.gitpod.io"},
+			Input:          `fetch("https://open-vsx.org/api")`,
+			Expectation:    Expectation{Content: `fetch("https://open-vsx.gitpod.io/api")`},
+		},
+		{
+			Name:           "regex",
+			Transformation: Transformation{Type: TransformationRegex, Search: `telemetryEndpoint:"[^"]*"`, Replacement: `telemetryEndpoint:""`},
+			Input:          `{telemetryEndpoint:"https://telemetry.example.com",x:1}`,
+			Expectation:    Expectation{Content: `{telemetryEndpoint:"",x:1}`},
+		},
+		{
+			Name:           "regex groups",
+			Transformation: Transformation{Type: TransformationRegex, Search: `https://(\w+)\.example\.com`, Replacement: `https://${1}.gitpod.io`},
+			Input:          `https://cdn.example.com/a https://api.example.com/b`,
+			Expectation:    Expectation{Content: `https://cdn.gitpod.io/a https://api.gitpod.io/b`},
+		},
+		{
+			Name:           "invalid regex",
+			Transformation: Transformation{Type: TransformationRegex, Search: `(`},
+			Expectation:    Expectation{Error: "transformation 0 (regex): invalid search expression: error parsing regexp: missing closing ): `(`"},
+		},
+		{
+			Name: "json patch",
+			Transformation: Transformation{Type: TransformationJSONPatch, Patch: []JSONPatchOperation{
+				{Op: "replace", Path: "/extensionsGallery/serviceUrl", Value: json.RawMessage(`"https://open-vsx.gitpod.io/vscode/gallery"`)},
+				{Op: "remove", Path: "/enableTelemetry"},
+				{Op: "add", Path: "/linkProtectionTrustedDomains/-", Value: json.RawMessage(`"https://gitpod.io"`)},
+				{Op: "add", Path: "/linkProtectionTrustedDomains/0", Value: json.RawMessage(`"https://first.io"`)},
+				{Op: "add", Path: "/a~1b", Value: json.RawMessage(`true`)},
+			}},
+			Input: `{"enableTelemetry":true,"extensionsGallery":{"serviceUrl":"https://open-vsx.org/vscode/gallery"},"linkProtectionTrustedDomains":["https://open-vsx.org"]}`,
+			Expectation: Expectation{Content: `{
+	"a/b": true,
+	"extensionsGallery": {
+		"serviceUrl": "https://open-vsx.gitpod.io/vscode/gallery"
+	},
+	"linkProtectionTrustedDomains": [
+		"https://first.io",
+		"https://open-vsx.org",
+		"https://gitpod.io"
+	]
+}
+`},
+		},
+		{
+			Name: "json patch replace missing",
+			Transformation: Transformation{Type: TransformationJSONPatch, Patch: []JSONPatchOperation{
+				{Op: "replace", Path: "/missing", Value: json.RawMessage(`1`)},
+			}},
+			Input:       `{}`,
+			Expectation: Expectation{Error: "cannot replace /missing: /missing does not exist"},
+		},
+		{
+			Name: "json patch unsupported op",
+			Transformation: Transformation{Type: TransformationJSONPatch, Patch: []JSONPatchOperation{
+				{Op: "move", Path: "/a"},
+			}},
+			Expectation: Expectation{Error: `transformation 0 (jsonPatch): operation 0: unsupported op "move"`},
+		},
+		{
+			Name:           "unknown type",
+			Transformation: Transformation{Type: "lua"},
+			Expectation:    Expectation{Error: `transformation 0: unknown type "lua"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Transformation.Path = "/ide/product.json"
+
+			var act Expectation
+			mods, _, err := compileTransformations([]Transformation{test.Transformation})
+			if err == nil {
+				out := bytes.NewBuffer(nil)
+				err = mods[0].Modifier(strings.NewReader(test.Input), out)
+				act.Content = out.String()
+			}
+			if err != nil {
+				act.Error = err.Error()
+				act.Content = ""
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected transformation result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInjectHeaders(t *testing.T) {
+	_, headers, err := compileTransformations([]Transformation{
+		{Type: TransformationHeader, Path: "/ide/out/*.js", Headers: map[string]string{"Cross-Origin-Resource-Policy": "cross-origin"}},
+		{Type: TransformationHeader, Path: "/ide/index.html", Headers: map[string]string{"X-Frame-Options": "DENY"}},
+		{Type: TransformationReplace, Path: "/ide/out/*.js", Search: "a", Replacement: "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Path     string
+		Expected http.Header
+	}{
+		{Path: "/ide/out/main.js", Expected: http.Header{"Cross-Origin-Resource-Policy": {"cross-origin"}}},
+		{Path: "ide/index.html", Expected: http.Header{"X-Frame-Options": {"DENY"}}},
+		{Path: "/ide/out/nested/main.js", Expected: http.Header{}},
+	}
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			injectHeaders(rec, test.Path, headers)
+			if diff := cmp.Diff(test.Expected, rec.Header()); diff != "" {
+				t.Errorf("unexpected headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchFiles(t *testing.T) {
+	tmp, err := os.MkdirTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, fn := range []string{"b1/ide/out/a.js", "b1/ide/out/b.js", "b1/ide/out/c.css", "b1/ide/out/nested/d.js"} {
+		err := os.MkdirAll(filepath.Join(tmp, filepath.Dir(fn)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(tmp, fn), nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		Pattern  string
+		Expected []string
+	}{
+		{Pattern: "/ide/out/*.js", Expected: []string{"/ide/out/a.js", "/ide/out/b.js"}},
+		{Pattern: "/ide/out/*/*.js", Expected: []string{"/ide/out/nested/d.js"}},
+		{Pattern: "/ide/out/missing.js", Expected: []string{"/ide/out/missing.js"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Pattern, func(t *testing.T) {
+			b := &diskBlobspace{Location: tmp}
+			act, err := b.matchFiles("b1", test.Pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected files (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ type Config struct {
 	AuthCfg        string           `json:"dockerAuth"`
 	PProfAddr      string           `json:"pprofAddr"`
 	PrometheusAddr string           `json:"prometheusAddr"`
+	AdminAddr      string           `json:"adminAddr,omitempty"`
 }
 
 // getConfig loads and validates the configuration
@@ -0,0 +1,24 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package fileaudit
+
+import (
+	"time"
+)
+
+// Event describes the modification of a file in a sensitive path of a workspace, e.g. ~/.ssh.
+// ws-daemon records these events and ships them to agent-smith as JSON, one event per line.
+type Event struct {
+	Time time.Time `json:"time"`
+
+	OwnerID     string `json:"ownerID"`
+	WorkspaceID string `json:"workspaceID"`
+	InstanceID  string `json:"instanceID"`
+
+	// Path is the path of the modified file
+	Path string `json:"path"`
+	// PID is the process which modified the file, as seen from the node
+	PID int `json:"pid"`
+}
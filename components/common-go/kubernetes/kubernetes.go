@@ -11,6 +11,9 @@ package kubernetes
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math"
 
@@ -49,6 +52,18 @@ const (
 	// CPULimitAnnotation enforces a strict CPU limit on a workspace by virtue of ws-daemon
 	CPULimitAnnotation = "gitpod.io/cpuLimit"
 
+	// IOBandwidthAnnotation limits the disk read and write bandwidth of a workspace in bytes per second by virtue of ws-daemon
+	IOBandwidthAnnotation = "gitpod.io/ioBandwidth"
+
+	// IOBurstBandwidthAnnotation is the disk bandwidth in bytes per second a workspace may use while it has burst allowance left
+	IOBurstBandwidthAnnotation = "gitpod.io/ioBurstBandwidth"
+
+	// IOBurstAllowanceAnnotation is the number of bytes a workspace may transfer at burst bandwidth
+	IOBurstAllowanceAnnotation = "gitpod.io/ioBurstAllowance"
+
+	// EgressBandwidthAnnotation limits the network egress bandwidth of a workspace in bytes per second by virtue of ws-daemon
+	EgressBandwidthAnnotation = "gitpod.io/egressBandwidth"
+
 	// RequiredNodeServicesAnnotation lists all Gitpod services required on the node
 	RequiredNodeServicesAnnotation = "gitpod.io/requiredNodeServices"
 
@@ -71,6 +86,10 @@ const (
 
 	// WorkspaceExposedPorts contains the exposed ports in the workspace
 	WorkspaceExposedPorts = "gitpod/exposedPorts"
+
+	// StopReasonAnnotation explains why a workspace is stopped, if it was stopped explicitly (e.g. by request or by agent-smith).
+	// Its value is the name of a ws-manager StopReason.
+	StopReasonAnnotation = "gitpod.io/stopReason"
 )
 
 // WorkspaceSupervisorEndpoint produces the supervisor endpoint of a workspace.
@@ -78,6 +97,14 @@ func WorkspaceSupervisorEndpoint(workspaceID, kubernetesNamespace string) string
 	return fmt.Sprintf("ws-%s-theia.%s.svc:22999", workspaceID, kubernetesNamespace)
 }
 
+// AccessLogToken produces the token ws-proxy authenticates with when it records access log entries in supervisor.
+// It's derived from the owner token so that the workspace never learns the owner token itself.
+func AccessLogToken(ownerToken string) string {
+	mac := hmac.New(sha256.New, []byte(ownerToken))
+	_, _ = mac.Write([]byte("access-log"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // GetOWIFromObject finds the owner, workspace and instance information on a Kubernetes object using labels
 func GetOWIFromObject(pod *metav1.ObjectMeta) logrus.Fields {
 	owner := pod.Labels[OwnerLabel]
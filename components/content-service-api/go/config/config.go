@@ -36,6 +36,31 @@ type StorageConfig struct {
 	} `json:"backupTrail"`
 
 	BlobQuota int64 `json:"blobQuota"`
+
+	// Encryption enables the encryption of backups and snapshots before we upload them
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
+
+	// Transfer enables transferring backups in parts, several at a time. Nil transfers them as a whole.
+	Transfer *TransferConfig `json:"transfer,omitempty"`
+}
+
+// TransferConfig configures how we transfer backups in parts. Failed parts are retried individually, and a failed
+// upload resumes with the parts which are still missing when it's retried.
+type TransferConfig struct {
+	// PartSize is the size of the parts in bytes. Defaults to 64 MiB, must be at least 5 MiB.
+	PartSize int64 `json:"partSize,omitempty"`
+
+	// Concurrency is the number of parts we transfer at once. Defaults to 4.
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// PartAttempts is how often we try to transfer a part before the transfer fails. Defaults to 3.
+	PartAttempts int `json:"partAttempts,omitempty"`
+
+	// MaxUploadBandwidth caps the upload rate in bytes per second, across all parts. Zero means no cap.
+	MaxUploadBandwidth int64 `json:"maxUploadBandwidth,omitempty"`
+
+	// MaxDownloadBandwidth caps the download rate in bytes per second, across all parts. Zero means no cap.
+	MaxDownloadBandwidth int64 `json:"maxDownloadBandwidth,omitempty"`
 }
 
 // Stage represents the deployment environment in which we're operating
@@ -114,6 +139,69 @@ type MinIOConfig struct {
 	ParallelUpload uint   `json:"parallelUpload,omitempty"`
 }
 
+// EncryptionConfig configures the envelope encryption of backups and snapshots: we encrypt each object with a key of its own,
+// and have a key management service (KMS) encrypt that key in turn. Content at rest in the bucket is thus unreadable to
+// anyone who cannot use the key of the KMS, including the admins of the storage.
+type EncryptionConfig struct {
+	// KMS determines which key management service holds the key which encrypts the keys of the objects
+	KMS KMSKind `json:"kms"`
+
+	// GCP configures a key of Google Cloud KMS
+	GCP *GCPKMSConfig `json:"gcp,omitempty"`
+
+	// AWS configures a key of AWS KMS
+	AWS *AWSKMSConfig `json:"aws,omitempty"`
+
+	// Vault configures a key of the transit secrets engine of HashiCorp Vault
+	Vault *VaultKMSConfig `json:"vault,omitempty"`
+
+	// AllowUnencrypted restores objects which are not encrypted, e.g. those uploaded before encryption was enabled.
+	// Only set this while migrating existing content: it lets anyone who can write to the bucket swap in content of their own.
+	AllowUnencrypted bool `json:"allowUnencrypted,omitempty"`
+}
+
+// KMSKind is a key management service
+type KMSKind string
+
+const (
+	// GCPKMS is Google Cloud KMS
+	GCPKMS KMSKind = "gcp"
+
+	// AWSKMS is AWS KMS
+	AWSKMS KMSKind = "aws"
+
+	// VaultKMS is the transit secrets engine of HashiCorp Vault
+	VaultKMS KMSKind = "vault"
+)
+
+// GCPKMSConfig configures a key of Google Cloud KMS
+type GCPKMSConfig struct {
+	// KeyName is the resource name of the key, i.e. projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>
+	KeyName string `json:"keyName"`
+	// CredentialsFile is the key of a service account which may encrypt and decrypt using the key.
+	// Defaults to the application default credentials.
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+}
+
+// AWSKMSConfig configures a key of AWS KMS
+type AWSKMSConfig struct {
+	// KeyID is the ID, ARN or alias of the key
+	KeyID               string `json:"keyId"`
+	Region              string `json:"region"`
+	AccessKeyIDFile     string `json:"accessKeyFile"`
+	SecretAccessKeyFile string `json:"secretKeyFile"`
+}
+
+// VaultKMSConfig configures a key of the transit secrets engine of HashiCorp Vault
+type VaultKMSConfig struct {
+	Address string `json:"address"`
+	// MountPath is where the transit secrets engine is mounted. Defaults to transit.
+	MountPath string `json:"mountPath,omitempty"`
+	KeyName   string `json:"keyName"`
+	// TokenFile contains a token whose policy allows for encrypting and decrypting using the key
+	TokenFile string `json:"tokenFile"`
+}
+
 type Service struct {
 	Addr string    `json:"address"`
 	TLS  TLSConfig `json:"tls"`
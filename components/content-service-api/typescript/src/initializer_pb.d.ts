@@ -49,6 +49,16 @@ export class WorkspaceInitializer extends jspb.Message {
     getBackup(): FromBackupInitializer | undefined;
     setBackup(value?: FromBackupInitializer): WorkspaceInitializer;
 
+    hasMercurial(): boolean;
+    clearMercurial(): void;
+    getMercurial(): MercurialInitializer | undefined;
+    setMercurial(value?: MercurialInitializer): WorkspaceInitializer;
+
+    hasSubversion(): boolean;
+    clearSubversion(): void;
+    getSubversion(): SubversionInitializer | undefined;
+    setSubversion(value?: SubversionInitializer): WorkspaceInitializer;
+
     getSpecCase(): WorkspaceInitializer.SpecCase;
 
     serializeBinary(): Uint8Array;
@@ -70,6 +80,8 @@ export namespace WorkspaceInitializer {
         composite?: CompositeInitializer.AsObject,
         download?: FileDownloadInitializer.AsObject,
         backup?: FromBackupInitializer.AsObject,
+        mercurial?: MercurialInitializer.AsObject,
+        subversion?: SubversionInitializer.AsObject,
     }
 
     export enum SpecCase {
@@ -81,6 +93,8 @@ export namespace WorkspaceInitializer {
         COMPOSITE = 5,
         DOWNLOAD = 6,
         BACKUP = 7,
+        MERCURIAL = 8,
+        SUBVERSION = 9,
     }
 
 }
@@ -194,6 +208,13 @@ export class GitInitializer extends jspb.Message {
     getConfig(): GitConfig | undefined;
     setConfig(value?: GitConfig): GitInitializer;
 
+    hasCloneStrategy(): boolean;
+    clearCloneStrategy(): void;
+    getCloneStrategy(): CloneStrategy | undefined;
+    setCloneStrategy(value?: CloneStrategy): GitInitializer;
+    getLfsMode(): LFSMode;
+    setLfsMode(value: LFSMode): GitInitializer;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): GitInitializer.AsObject;
     static toObject(includeInstance: boolean, msg: GitInitializer): GitInitializer.AsObject;
@@ -212,6 +233,39 @@ export namespace GitInitializer {
         cloneTaget: string,
         checkoutLocation: string,
         config?: GitConfig.AsObject,
+        cloneStrategy?: CloneStrategy.AsObject,
+        lfsMode: LFSMode,
+    }
+}
+
+export class CloneStrategy extends jspb.Message {
+    getDepth(): number;
+    setDepth(value: number): CloneStrategy;
+    getFilter(): CloneFilter;
+    setFilter(value: CloneFilter): CloneStrategy;
+    clearSparseCheckoutPatternsList(): void;
+    getSparseCheckoutPatternsList(): Array<string>;
+    setSparseCheckoutPatternsList(value: Array<string>): CloneStrategy;
+    addSparseCheckoutPatterns(value: string, index?: number): string;
+    getUnshallow(): boolean;
+    setUnshallow(value: boolean): CloneStrategy;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CloneStrategy.AsObject;
+    static toObject(includeInstance: boolean, msg: CloneStrategy): CloneStrategy.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CloneStrategy, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CloneStrategy;
+    static deserializeBinaryFromReader(message: CloneStrategy, reader: jspb.BinaryReader): CloneStrategy;
+}
+
+export namespace CloneStrategy {
+    export type AsObject = {
+        depth: number,
+        filter: CloneFilter,
+        sparseCheckoutPatternsList: Array<string>,
+        unshallow: boolean,
     }
 }
 
@@ -315,6 +369,99 @@ export namespace FromBackupInitializer {
     }
 }
 
+export class MercurialInitializer extends jspb.Message {
+    getRemoteUri(): string;
+    setRemoteUri(value: string): MercurialInitializer;
+    getRevision(): string;
+    setRevision(value: string): MercurialInitializer;
+    getCheckoutLocation(): string;
+    setCheckoutLocation(value: string): MercurialInitializer;
+
+    hasAuth(): boolean;
+    clearAuth(): void;
+    getAuth(): RemoteAuth | undefined;
+    setAuth(value?: RemoteAuth): MercurialInitializer;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): MercurialInitializer.AsObject;
+    static toObject(includeInstance: boolean, msg: MercurialInitializer): MercurialInitializer.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: MercurialInitializer, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): MercurialInitializer;
+    static deserializeBinaryFromReader(message: MercurialInitializer, reader: jspb.BinaryReader): MercurialInitializer;
+}
+
+export namespace MercurialInitializer {
+    export type AsObject = {
+        remoteUri: string,
+        revision: string,
+        checkoutLocation: string,
+        auth?: RemoteAuth.AsObject,
+    }
+}
+
+export class SubversionInitializer extends jspb.Message {
+    getRemoteUri(): string;
+    setRemoteUri(value: string): SubversionInitializer;
+    getRevision(): string;
+    setRevision(value: string): SubversionInitializer;
+    getCheckoutLocation(): string;
+    setCheckoutLocation(value: string): SubversionInitializer;
+
+    hasAuth(): boolean;
+    clearAuth(): void;
+    getAuth(): RemoteAuth | undefined;
+    setAuth(value?: RemoteAuth): SubversionInitializer;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): SubversionInitializer.AsObject;
+    static toObject(includeInstance: boolean, msg: SubversionInitializer): SubversionInitializer.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: SubversionInitializer, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): SubversionInitializer;
+    static deserializeBinaryFromReader(message: SubversionInitializer, reader: jspb.BinaryReader): SubversionInitializer;
+}
+
+export namespace SubversionInitializer {
+    export type AsObject = {
+        remoteUri: string,
+        revision: string,
+        checkoutLocation: string,
+        auth?: RemoteAuth.AsObject,
+    }
+}
+
+export class RemoteAuth extends jspb.Message {
+    getMethod(): GitAuthMethod;
+    setMethod(value: GitAuthMethod): RemoteAuth;
+    getUser(): string;
+    setUser(value: string): RemoteAuth;
+    getPassword(): string;
+    setPassword(value: string): RemoteAuth;
+    getOts(): string;
+    setOts(value: string): RemoteAuth;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): RemoteAuth.AsObject;
+    static toObject(includeInstance: boolean, msg: RemoteAuth): RemoteAuth.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: RemoteAuth, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): RemoteAuth;
+    static deserializeBinaryFromReader(message: RemoteAuth, reader: jspb.BinaryReader): RemoteAuth;
+}
+
+export namespace RemoteAuth {
+    export type AsObject = {
+        method: GitAuthMethod,
+        user: string,
+        password: string,
+        ots: string,
+    }
+}
+
 export class GitStatus extends jspb.Message {
     getBranch(): string;
     setBranch(value: string): GitStatus;
@@ -369,6 +516,18 @@ export enum CloneTargetMode {
     LOCAL_BRANCH = 3,
 }
 
+export enum CloneFilter {
+    NO_FILTER = 0,
+    BLOBLESS = 1,
+    TREELESS = 2,
+}
+
+export enum LFSMode {
+    LFS_FETCH = 0,
+    LFS_LAZY = 1,
+    LFS_SKIP = 2,
+}
+
 export enum GitAuthMethod {
     NO_AUTH = 0,
     BASIC_AUTH = 1,
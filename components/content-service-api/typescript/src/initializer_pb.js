@@ -27,6 +27,8 @@ var global = (function() {
   return Function('return this')();
 }.call(null));
 
+goog.exportSymbol('proto.contentservice.CloneFilter', null, global);
+goog.exportSymbol('proto.contentservice.CloneStrategy', null, global);
 goog.exportSymbol('proto.contentservice.CloneTargetMode', null, global);
 goog.exportSymbol('proto.contentservice.CompositeInitializer', null, global);
 goog.exportSymbol('proto.contentservice.EmptyInitializer', null, global);
@@ -37,8 +39,12 @@ goog.exportSymbol('proto.contentservice.GitAuthMethod', null, global);
 goog.exportSymbol('proto.contentservice.GitConfig', null, global);
 goog.exportSymbol('proto.contentservice.GitInitializer', null, global);
 goog.exportSymbol('proto.contentservice.GitStatus', null, global);
+goog.exportSymbol('proto.contentservice.LFSMode', null, global);
+goog.exportSymbol('proto.contentservice.MercurialInitializer', null, global);
 goog.exportSymbol('proto.contentservice.PrebuildInitializer', null, global);
+goog.exportSymbol('proto.contentservice.RemoteAuth', null, global);
 goog.exportSymbol('proto.contentservice.SnapshotInitializer', null, global);
+goog.exportSymbol('proto.contentservice.SubversionInitializer', null, global);
 goog.exportSymbol('proto.contentservice.WorkspaceInitializer', null, global);
 goog.exportSymbol('proto.contentservice.WorkspaceInitializer.SpecCase', null, global);
 /**
@@ -167,6 +173,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.contentservice.GitInitializer.displayName = 'proto.contentservice.GitInitializer';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.CloneStrategy = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.contentservice.CloneStrategy.repeatedFields_, null);
+};
+goog.inherits(proto.contentservice.CloneStrategy, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.CloneStrategy.displayName = 'proto.contentservice.CloneStrategy';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -251,6 +278,69 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.contentservice.FromBackupInitializer.displayName = 'proto.contentservice.FromBackupInitializer';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.MercurialInitializer = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.MercurialInitializer, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.MercurialInitializer.displayName = 'proto.contentservice.MercurialInitializer';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.SubversionInitializer = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.SubversionInitializer, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.SubversionInitializer.displayName = 'proto.contentservice.SubversionInitializer';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.RemoteAuth = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.RemoteAuth, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.RemoteAuth.displayName = 'proto.contentservice.RemoteAuth';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -281,7 +371,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<!Array<number>>}
  * @const
  */
-proto.contentservice.WorkspaceInitializer.oneofGroups_ = [[1,2,3,4,5,6,7]];
+proto.contentservice.WorkspaceInitializer.oneofGroups_ = [[1,2,3,4,5,6,7,8,9]];
 
 /**
  * @enum {number}
@@ -294,7 +384,9 @@ proto.contentservice.WorkspaceInitializer.SpecCase = {
   PREBUILD: 4,
   COMPOSITE: 5,
   DOWNLOAD: 6,
-  BACKUP: 7
+  BACKUP: 7,
+  MERCURIAL: 8,
+  SUBVERSION: 9
 };
 
 /**
@@ -341,7 +433,9 @@ proto.contentservice.WorkspaceInitializer.toObject = function(includeInstance, m
     prebuild: (f = msg.getPrebuild()) && proto.contentservice.PrebuildInitializer.toObject(includeInstance, f),
     composite: (f = msg.getComposite()) && proto.contentservice.CompositeInitializer.toObject(includeInstance, f),
     download: (f = msg.getDownload()) && proto.contentservice.FileDownloadInitializer.toObject(includeInstance, f),
-    backup: (f = msg.getBackup()) && proto.contentservice.FromBackupInitializer.toObject(includeInstance, f)
+    backup: (f = msg.getBackup()) && proto.contentservice.FromBackupInitializer.toObject(includeInstance, f),
+    mercurial: (f = msg.getMercurial()) && proto.contentservice.MercurialInitializer.toObject(includeInstance, f),
+    subversion: (f = msg.getSubversion()) && proto.contentservice.SubversionInitializer.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -413,6 +507,16 @@ proto.contentservice.WorkspaceInitializer.deserializeBinaryFromReader = function
       reader.readMessage(value,proto.contentservice.FromBackupInitializer.deserializeBinaryFromReader);
       msg.setBackup(value);
       break;
+    case 8:
+      var value = new proto.contentservice.MercurialInitializer;
+      reader.readMessage(value,proto.contentservice.MercurialInitializer.deserializeBinaryFromReader);
+      msg.setMercurial(value);
+      break;
+    case 9:
+      var value = new proto.contentservice.SubversionInitializer;
+      reader.readMessage(value,proto.contentservice.SubversionInitializer.deserializeBinaryFromReader);
+      msg.setSubversion(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -498,6 +602,22 @@ proto.contentservice.WorkspaceInitializer.serializeBinaryToWriter = function(mes
       proto.contentservice.FromBackupInitializer.serializeBinaryToWriter
     );
   }
+  f = message.getMercurial();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.contentservice.MercurialInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getSubversion();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      proto.contentservice.SubversionInitializer.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -760,6 +880,80 @@ proto.contentservice.WorkspaceInitializer.prototype.hasBackup = function() {
 };
 
 
+/**
+ * optional MercurialInitializer mercurial = 8;
+ * @return {?proto.contentservice.MercurialInitializer}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.getMercurial = function() {
+  return /** @type{?proto.contentservice.MercurialInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.MercurialInitializer, 8));
+};
+
+
+/**
+ * @param {?proto.contentservice.MercurialInitializer|undefined} value
+ * @return {!proto.contentservice.WorkspaceInitializer} returns this
+*/
+proto.contentservice.WorkspaceInitializer.prototype.setMercurial = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 8, proto.contentservice.WorkspaceInitializer.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.contentservice.WorkspaceInitializer} returns this
+ */
+proto.contentservice.WorkspaceInitializer.prototype.clearMercurial = function() {
+  return this.setMercurial(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.hasMercurial = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional SubversionInitializer subversion = 9;
+ * @return {?proto.contentservice.SubversionInitializer}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.getSubversion = function() {
+  return /** @type{?proto.contentservice.SubversionInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.SubversionInitializer, 9));
+};
+
+
+/**
+ * @param {?proto.contentservice.SubversionInitializer|undefined} value
+ * @return {!proto.contentservice.WorkspaceInitializer} returns this
+*/
+proto.contentservice.WorkspaceInitializer.prototype.setSubversion = function(value) {
+  return jspb.Message.setOneofWrapperField(this, 9, proto.contentservice.WorkspaceInitializer.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.contentservice.WorkspaceInitializer} returns this
+ */
+proto.contentservice.WorkspaceInitializer.prototype.clearSubversion = function() {
+  return this.setSubversion(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.hasSubversion = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
 
 /**
  * List of repeated fields within this message type.
@@ -1438,7 +1632,9 @@ proto.contentservice.GitInitializer.toObject = function(includeInstance, msg) {
     targetMode: jspb.Message.getFieldWithDefault(msg, 3, 0),
     cloneTaget: jspb.Message.getFieldWithDefault(msg, 4, ""),
     checkoutLocation: jspb.Message.getFieldWithDefault(msg, 5, ""),
-    config: (f = msg.getConfig()) && proto.contentservice.GitConfig.toObject(includeInstance, f)
+    config: (f = msg.getConfig()) && proto.contentservice.GitConfig.toObject(includeInstance, f),
+    cloneStrategy: (f = msg.getCloneStrategy()) && proto.contentservice.CloneStrategy.toObject(includeInstance, f),
+    lfsMode: jspb.Message.getFieldWithDefault(msg, 8, 0)
   };
 
   if (includeInstance) {
@@ -1500,6 +1696,15 @@ proto.contentservice.GitInitializer.deserializeBinaryFromReader = function(msg,
       reader.readMessage(value,proto.contentservice.GitConfig.deserializeBinaryFromReader);
       msg.setConfig(value);
       break;
+    case 7:
+      var value = new proto.contentservice.CloneStrategy;
+      reader.readMessage(value,proto.contentservice.CloneStrategy.deserializeBinaryFromReader);
+      msg.setCloneStrategy(value);
+      break;
+    case 8:
+      var value = /** @type {!proto.contentservice.LFSMode} */ (reader.readEnum());
+      msg.setLfsMode(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1572,6 +1777,21 @@ proto.contentservice.GitInitializer.serializeBinaryToWriter = function(message,
       proto.contentservice.GitConfig.serializeBinaryToWriter
     );
   }
+  f = message.getCloneStrategy();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      proto.contentservice.CloneStrategy.serializeBinaryToWriter
+    );
+  }
+  f = message.getLfsMode();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      8,
+      f
+    );
+  }
 };
 
 
@@ -1702,6 +1922,68 @@ proto.contentservice.GitInitializer.prototype.hasConfig = function() {
 };
 
 
+/**
+ * optional CloneStrategy clone_strategy = 7;
+ * @return {?proto.contentservice.CloneStrategy}
+ */
+proto.contentservice.GitInitializer.prototype.getCloneStrategy = function() {
+  return /** @type{?proto.contentservice.CloneStrategy} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.CloneStrategy, 7));
+};
+
+
+/**
+ * @param {?proto.contentservice.CloneStrategy|undefined} value
+ * @return {!proto.contentservice.GitInitializer} returns this
+*/
+proto.contentservice.GitInitializer.prototype.setCloneStrategy = function(value) {
+  return jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.contentservice.GitInitializer} returns this
+ */
+proto.contentservice.GitInitializer.prototype.clearCloneStrategy = function() {
+  return this.setCloneStrategy(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.GitInitializer.prototype.hasCloneStrategy = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional LFSMode lfs_mode = 8;
+ * @return {!proto.contentservice.LFSMode}
+ */
+proto.contentservice.GitInitializer.prototype.getLfsMode = function() {
+  return /** @type {!proto.contentservice.LFSMode} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
+};
+
+
+/**
+ * @param {!proto.contentservice.LFSMode} value
+ * @return {!proto.contentservice.GitInitializer} returns this
+ */
+proto.contentservice.GitInitializer.prototype.setLfsMode = function(value) {
+  return jspb.Message.setProto3EnumField(this, 8, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.contentservice.CloneStrategy.repeatedFields_ = [3];
 
 
 
@@ -1718,8 +2000,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.contentservice.GitConfig.prototype.toObject = function(opt_includeInstance) {
-  return proto.contentservice.GitConfig.toObject(opt_includeInstance, this);
+proto.contentservice.CloneStrategy.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.CloneStrategy.toObject(opt_includeInstance, this);
 };
 
 
@@ -1728,17 +2010,16 @@ proto.contentservice.GitConfig.prototype.toObject = function(opt_includeInstance
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.contentservice.GitConfig} msg The msg instance to transform.
+ * @param {!proto.contentservice.CloneStrategy} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.contentservice.GitConfig.toObject = function(includeInstance, msg) {
+proto.contentservice.CloneStrategy.toObject = function(includeInstance, msg) {
   var f, obj = {
-    customConfigMap: (f = msg.getCustomConfigMap()) ? f.toObject(includeInstance, undefined) : [],
-    authentication: jspb.Message.getFieldWithDefault(msg, 2, 0),
-    authUser: jspb.Message.getFieldWithDefault(msg, 3, ""),
-    authPassword: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    authOts: jspb.Message.getFieldWithDefault(msg, 5, "")
+    depth: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    filter: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    sparseCheckoutPatternsList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
+    unshallow: jspb.Message.getBooleanFieldWithDefault(msg, 4, false)
   };
 
   if (includeInstance) {
@@ -1752,23 +2033,23 @@ proto.contentservice.GitConfig.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.contentservice.GitConfig}
+ * @return {!proto.contentservice.CloneStrategy}
  */
-proto.contentservice.GitConfig.deserializeBinary = function(bytes) {
+proto.contentservice.CloneStrategy.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.contentservice.GitConfig;
-  return proto.contentservice.GitConfig.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.contentservice.CloneStrategy;
+  return proto.contentservice.CloneStrategy.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.contentservice.GitConfig} msg The message object to deserialize into.
+ * @param {!proto.contentservice.CloneStrategy} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.contentservice.GitConfig}
+ * @return {!proto.contentservice.CloneStrategy}
  */
-proto.contentservice.GitConfig.deserializeBinaryFromReader = function(msg, reader) {
+proto.contentservice.CloneStrategy.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1776,26 +2057,20 @@ proto.contentservice.GitConfig.deserializeBinaryFromReader = function(msg, reade
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = msg.getCustomConfigMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
-         });
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setDepth(value);
       break;
     case 2:
-      var value = /** @type {!proto.contentservice.GitAuthMethod} */ (reader.readEnum());
-      msg.setAuthentication(value);
+      var value = /** @type {!proto.contentservice.CloneFilter} */ (reader.readEnum());
+      msg.setFilter(value);
       break;
     case 3:
       var value = /** @type {string} */ (reader.readString());
-      msg.setAuthUser(value);
+      msg.addSparseCheckoutPatterns(value);
       break;
     case 4:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setAuthPassword(value);
-      break;
-    case 5:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setAuthOts(value);
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setUnshallow(value);
       break;
     default:
       reader.skipField();
@@ -1810,9 +2085,9 @@ proto.contentservice.GitConfig.deserializeBinaryFromReader = function(msg, reade
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.contentservice.GitConfig.prototype.serializeBinary = function() {
+proto.contentservice.CloneStrategy.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.contentservice.GitConfig.serializeBinaryToWriter(this, writer);
+  proto.contentservice.CloneStrategy.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1820,56 +2095,302 @@ proto.contentservice.GitConfig.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.contentservice.GitConfig} message
+ * @param {!proto.contentservice.CloneStrategy} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.contentservice.GitConfig.serializeBinaryToWriter = function(message, writer) {
+proto.contentservice.CloneStrategy.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getCustomConfigMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(1, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  f = message.getDepth();
+  if (f !== 0) {
+    writer.writeUint32(
+      1,
+      f
+    );
   }
-  f = message.getAuthentication();
+  f = message.getFilter();
   if (f !== 0.0) {
     writer.writeEnum(
       2,
       f
     );
   }
-  f = message.getAuthUser();
+  f = message.getSparseCheckoutPatternsList();
   if (f.length > 0) {
-    writer.writeString(
+    writer.writeRepeatedString(
       3,
       f
     );
   }
-  f = message.getAuthPassword();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getUnshallow();
+  if (f) {
+    writer.writeBool(
       4,
       f
     );
   }
-  f = message.getAuthOts();
-  if (f.length > 0) {
-    writer.writeString(
-      5,
-      f
-    );
-  }
 };
 
 
 /**
- * map<string, string> custom_config = 1;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
+ * optional uint32 depth = 1;
+ * @return {number}
  */
-proto.contentservice.GitConfig.prototype.getCustomConfigMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 1, opt_noLazyCreate,
+proto.contentservice.CloneStrategy.prototype.getDepth = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.contentservice.CloneStrategy} returns this
+ */
+proto.contentservice.CloneStrategy.prototype.setDepth = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+/**
+ * optional CloneFilter filter = 2;
+ * @return {!proto.contentservice.CloneFilter}
+ */
+proto.contentservice.CloneStrategy.prototype.getFilter = function() {
+  return /** @type {!proto.contentservice.CloneFilter} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/**
+ * @param {!proto.contentservice.CloneFilter} value
+ * @return {!proto.contentservice.CloneStrategy} returns this
+ */
+proto.contentservice.CloneStrategy.prototype.setFilter = function(value) {
+  return jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * repeated string sparse_checkout_patterns = 3;
+ * @return {!Array<string>}
+ */
+proto.contentservice.CloneStrategy.prototype.getSparseCheckoutPatternsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.contentservice.CloneStrategy} returns this
+ */
+proto.contentservice.CloneStrategy.prototype.setSparseCheckoutPatternsList = function(value) {
+  return jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.contentservice.CloneStrategy} returns this
+ */
+proto.contentservice.CloneStrategy.prototype.addSparseCheckoutPatterns = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.contentservice.CloneStrategy} returns this
+ */
+proto.contentservice.CloneStrategy.prototype.clearSparseCheckoutPatternsList = function() {
+  return this.setSparseCheckoutPatternsList([]);
+};
+
+
+/**
+ * optional bool unshallow = 4;
+ * @return {boolean}
+ */
+proto.contentservice.CloneStrategy.prototype.getUnshallow = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.contentservice.CloneStrategy} returns this
+ */
+proto.contentservice.CloneStrategy.prototype.setUnshallow = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.GitConfig.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.GitConfig.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.GitConfig} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.GitConfig.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    customConfigMap: (f = msg.getCustomConfigMap()) ? f.toObject(includeInstance, undefined) : [],
+    authentication: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    authUser: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    authPassword: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    authOts: jspb.Message.getFieldWithDefault(msg, 5, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.GitConfig}
+ */
+proto.contentservice.GitConfig.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.GitConfig;
+  return proto.contentservice.GitConfig.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.GitConfig} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.GitConfig}
+ */
+proto.contentservice.GitConfig.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = msg.getCustomConfigMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
+    case 2:
+      var value = /** @type {!proto.contentservice.GitAuthMethod} */ (reader.readEnum());
+      msg.setAuthentication(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAuthUser(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAuthPassword(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAuthOts(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.GitConfig.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.GitConfig.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.GitConfig} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.GitConfig.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCustomConfigMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(1, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+  f = message.getAuthentication();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getAuthUser();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getAuthPassword();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getAuthOts();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+};
+
+
+/**
+ * map<string, string> custom_config = 1;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.contentservice.GitConfig.prototype.getCustomConfigMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 1, opt_noLazyCreate,
       null));
 };
 
@@ -2068,20 +2589,564 @@ proto.contentservice.SnapshotInitializer.serializeBinaryToWriter = function(mess
 
 
 /**
- * optional string snapshot = 1;
- * @return {string}
+ * optional string snapshot = 1;
+ * @return {string}
+ */
+proto.contentservice.SnapshotInitializer.prototype.getSnapshot = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.SnapshotInitializer} returns this
+ */
+proto.contentservice.SnapshotInitializer.prototype.setSnapshot = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.PrebuildInitializer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.PrebuildInitializer.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.PrebuildInitializer} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.PrebuildInitializer.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    prebuild: (f = msg.getPrebuild()) && proto.contentservice.SnapshotInitializer.toObject(includeInstance, f),
+    git: (f = msg.getGit()) && proto.contentservice.GitInitializer.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.PrebuildInitializer}
+ */
+proto.contentservice.PrebuildInitializer.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.PrebuildInitializer;
+  return proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.PrebuildInitializer} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.PrebuildInitializer}
+ */
+proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.contentservice.SnapshotInitializer;
+      reader.readMessage(value,proto.contentservice.SnapshotInitializer.deserializeBinaryFromReader);
+      msg.setPrebuild(value);
+      break;
+    case 2:
+      var value = new proto.contentservice.GitInitializer;
+      reader.readMessage(value,proto.contentservice.GitInitializer.deserializeBinaryFromReader);
+      msg.setGit(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.PrebuildInitializer.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.PrebuildInitializer.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.PrebuildInitializer} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.PrebuildInitializer.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getPrebuild();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.contentservice.SnapshotInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getGit();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.contentservice.GitInitializer.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional SnapshotInitializer prebuild = 1;
+ * @return {?proto.contentservice.SnapshotInitializer}
+ */
+proto.contentservice.PrebuildInitializer.prototype.getPrebuild = function() {
+  return /** @type{?proto.contentservice.SnapshotInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.SnapshotInitializer, 1));
+};
+
+
+/**
+ * @param {?proto.contentservice.SnapshotInitializer|undefined} value
+ * @return {!proto.contentservice.PrebuildInitializer} returns this
+*/
+proto.contentservice.PrebuildInitializer.prototype.setPrebuild = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.contentservice.PrebuildInitializer} returns this
+ */
+proto.contentservice.PrebuildInitializer.prototype.clearPrebuild = function() {
+  return this.setPrebuild(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.PrebuildInitializer.prototype.hasPrebuild = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional GitInitializer git = 2;
+ * @return {?proto.contentservice.GitInitializer}
+ */
+proto.contentservice.PrebuildInitializer.prototype.getGit = function() {
+  return /** @type{?proto.contentservice.GitInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.GitInitializer, 2));
+};
+
+
+/**
+ * @param {?proto.contentservice.GitInitializer|undefined} value
+ * @return {!proto.contentservice.PrebuildInitializer} returns this
+*/
+proto.contentservice.PrebuildInitializer.prototype.setGit = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.contentservice.PrebuildInitializer} returns this
+ */
+proto.contentservice.PrebuildInitializer.prototype.clearGit = function() {
+  return this.setGit(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.PrebuildInitializer.prototype.hasGit = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.FromBackupInitializer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.FromBackupInitializer.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.FromBackupInitializer} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.FromBackupInitializer.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.FromBackupInitializer}
+ */
+proto.contentservice.FromBackupInitializer.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.FromBackupInitializer;
+  return proto.contentservice.FromBackupInitializer.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.FromBackupInitializer} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.FromBackupInitializer}
+ */
+proto.contentservice.FromBackupInitializer.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.FromBackupInitializer.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.FromBackupInitializer.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.FromBackupInitializer} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.FromBackupInitializer.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.MercurialInitializer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.MercurialInitializer.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.MercurialInitializer} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.MercurialInitializer.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    remoteUri: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    revision: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    checkoutLocation: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    auth: (f = msg.getAuth()) && proto.contentservice.RemoteAuth.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.MercurialInitializer}
+ */
+proto.contentservice.MercurialInitializer.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.MercurialInitializer;
+  return proto.contentservice.MercurialInitializer.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.MercurialInitializer} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.MercurialInitializer}
+ */
+proto.contentservice.MercurialInitializer.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRemoteUri(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRevision(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setCheckoutLocation(value);
+      break;
+    case 4:
+      var value = new proto.contentservice.RemoteAuth;
+      reader.readMessage(value,proto.contentservice.RemoteAuth.deserializeBinaryFromReader);
+      msg.setAuth(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.MercurialInitializer.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.MercurialInitializer.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.MercurialInitializer} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.MercurialInitializer.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getRemoteUri();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getRevision();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getCheckoutLocation();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getAuth();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.contentservice.RemoteAuth.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string remote_uri = 1;
+ * @return {string}
+ */
+proto.contentservice.MercurialInitializer.prototype.getRemoteUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.MercurialInitializer} returns this
+ */
+proto.contentservice.MercurialInitializer.prototype.setRemoteUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string revision = 2;
+ * @return {string}
+ */
+proto.contentservice.MercurialInitializer.prototype.getRevision = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.MercurialInitializer} returns this
+ */
+proto.contentservice.MercurialInitializer.prototype.setRevision = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string checkout_location = 3;
+ * @return {string}
+ */
+proto.contentservice.MercurialInitializer.prototype.getCheckoutLocation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.MercurialInitializer} returns this
+ */
+proto.contentservice.MercurialInitializer.prototype.setCheckoutLocation = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional RemoteAuth auth = 4;
+ * @return {?proto.contentservice.RemoteAuth}
  */
-proto.contentservice.SnapshotInitializer.prototype.getSnapshot = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.contentservice.MercurialInitializer.prototype.getAuth = function() {
+  return /** @type{?proto.contentservice.RemoteAuth} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.RemoteAuth, 4));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.contentservice.SnapshotInitializer} returns this
+ * @param {?proto.contentservice.RemoteAuth|undefined} value
+ * @return {!proto.contentservice.MercurialInitializer} returns this
+*/
+proto.contentservice.MercurialInitializer.prototype.setAuth = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.contentservice.MercurialInitializer} returns this
  */
-proto.contentservice.SnapshotInitializer.prototype.setSnapshot = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.contentservice.MercurialInitializer.prototype.clearAuth = function() {
+  return this.setAuth(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.MercurialInitializer.prototype.hasAuth = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
@@ -2101,8 +3166,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.contentservice.PrebuildInitializer.prototype.toObject = function(opt_includeInstance) {
-  return proto.contentservice.PrebuildInitializer.toObject(opt_includeInstance, this);
+proto.contentservice.SubversionInitializer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.SubversionInitializer.toObject(opt_includeInstance, this);
 };
 
 
@@ -2111,14 +3176,16 @@ proto.contentservice.PrebuildInitializer.prototype.toObject = function(opt_inclu
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.contentservice.PrebuildInitializer} msg The msg instance to transform.
+ * @param {!proto.contentservice.SubversionInitializer} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.contentservice.PrebuildInitializer.toObject = function(includeInstance, msg) {
+proto.contentservice.SubversionInitializer.toObject = function(includeInstance, msg) {
   var f, obj = {
-    prebuild: (f = msg.getPrebuild()) && proto.contentservice.SnapshotInitializer.toObject(includeInstance, f),
-    git: (f = msg.getGit()) && proto.contentservice.GitInitializer.toObject(includeInstance, f)
+    remoteUri: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    revision: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    checkoutLocation: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    auth: (f = msg.getAuth()) && proto.contentservice.RemoteAuth.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -2132,23 +3199,23 @@ proto.contentservice.PrebuildInitializer.toObject = function(includeInstance, ms
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.contentservice.PrebuildInitializer}
+ * @return {!proto.contentservice.SubversionInitializer}
  */
-proto.contentservice.PrebuildInitializer.deserializeBinary = function(bytes) {
+proto.contentservice.SubversionInitializer.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.contentservice.PrebuildInitializer;
-  return proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.contentservice.SubversionInitializer;
+  return proto.contentservice.SubversionInitializer.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.contentservice.PrebuildInitializer} msg The message object to deserialize into.
+ * @param {!proto.contentservice.SubversionInitializer} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.contentservice.PrebuildInitializer}
+ * @return {!proto.contentservice.SubversionInitializer}
  */
-proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader = function(msg, reader) {
+proto.contentservice.SubversionInitializer.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2156,14 +3223,21 @@ proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader = function(
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new proto.contentservice.SnapshotInitializer;
-      reader.readMessage(value,proto.contentservice.SnapshotInitializer.deserializeBinaryFromReader);
-      msg.setPrebuild(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRemoteUri(value);
       break;
     case 2:
-      var value = new proto.contentservice.GitInitializer;
-      reader.readMessage(value,proto.contentservice.GitInitializer.deserializeBinaryFromReader);
-      msg.setGit(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRevision(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setCheckoutLocation(value);
+      break;
+    case 4:
+      var value = new proto.contentservice.RemoteAuth;
+      reader.readMessage(value,proto.contentservice.RemoteAuth.deserializeBinaryFromReader);
+      msg.setAuth(value);
       break;
     default:
       reader.skipField();
@@ -2178,9 +3252,9 @@ proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader = function(
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.contentservice.PrebuildInitializer.prototype.serializeBinary = function() {
+proto.contentservice.SubversionInitializer.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.contentservice.PrebuildInitializer.serializeBinaryToWriter(this, writer);
+  proto.contentservice.SubversionInitializer.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2188,93 +3262,123 @@ proto.contentservice.PrebuildInitializer.prototype.serializeBinary = function()
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.contentservice.PrebuildInitializer} message
+ * @param {!proto.contentservice.SubversionInitializer} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.contentservice.PrebuildInitializer.serializeBinaryToWriter = function(message, writer) {
+proto.contentservice.SubversionInitializer.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getPrebuild();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getRemoteUri();
+  if (f.length > 0) {
+    writer.writeString(
       1,
-      f,
-      proto.contentservice.SnapshotInitializer.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getGit();
+  f = message.getRevision();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getCheckoutLocation();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getAuth();
   if (f != null) {
     writer.writeMessage(
-      2,
+      4,
       f,
-      proto.contentservice.GitInitializer.serializeBinaryToWriter
+      proto.contentservice.RemoteAuth.serializeBinaryToWriter
     );
   }
 };
 
 
 /**
- * optional SnapshotInitializer prebuild = 1;
- * @return {?proto.contentservice.SnapshotInitializer}
+ * optional string remote_uri = 1;
+ * @return {string}
  */
-proto.contentservice.PrebuildInitializer.prototype.getPrebuild = function() {
-  return /** @type{?proto.contentservice.SnapshotInitializer} */ (
-    jspb.Message.getWrapperField(this, proto.contentservice.SnapshotInitializer, 1));
+proto.contentservice.SubversionInitializer.prototype.getRemoteUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * @param {?proto.contentservice.SnapshotInitializer|undefined} value
- * @return {!proto.contentservice.PrebuildInitializer} returns this
-*/
-proto.contentservice.PrebuildInitializer.prototype.setPrebuild = function(value) {
-  return jspb.Message.setWrapperField(this, 1, value);
+ * @param {string} value
+ * @return {!proto.contentservice.SubversionInitializer} returns this
+ */
+proto.contentservice.SubversionInitializer.prototype.setRemoteUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.contentservice.PrebuildInitializer} returns this
+ * optional string revision = 2;
+ * @return {string}
  */
-proto.contentservice.PrebuildInitializer.prototype.clearPrebuild = function() {
-  return this.setPrebuild(undefined);
+proto.contentservice.SubversionInitializer.prototype.getRevision = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.contentservice.SubversionInitializer} returns this
  */
-proto.contentservice.PrebuildInitializer.prototype.hasPrebuild = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.contentservice.SubversionInitializer.prototype.setRevision = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional GitInitializer git = 2;
- * @return {?proto.contentservice.GitInitializer}
+ * optional string checkout_location = 3;
+ * @return {string}
  */
-proto.contentservice.PrebuildInitializer.prototype.getGit = function() {
-  return /** @type{?proto.contentservice.GitInitializer} */ (
-    jspb.Message.getWrapperField(this, proto.contentservice.GitInitializer, 2));
+proto.contentservice.SubversionInitializer.prototype.getCheckoutLocation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * @param {?proto.contentservice.GitInitializer|undefined} value
- * @return {!proto.contentservice.PrebuildInitializer} returns this
+ * @param {string} value
+ * @return {!proto.contentservice.SubversionInitializer} returns this
+ */
+proto.contentservice.SubversionInitializer.prototype.setCheckoutLocation = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional RemoteAuth auth = 4;
+ * @return {?proto.contentservice.RemoteAuth}
+ */
+proto.contentservice.SubversionInitializer.prototype.getAuth = function() {
+  return /** @type{?proto.contentservice.RemoteAuth} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.RemoteAuth, 4));
+};
+
+
+/**
+ * @param {?proto.contentservice.RemoteAuth|undefined} value
+ * @return {!proto.contentservice.SubversionInitializer} returns this
 */
-proto.contentservice.PrebuildInitializer.prototype.setGit = function(value) {
-  return jspb.Message.setWrapperField(this, 2, value);
+proto.contentservice.SubversionInitializer.prototype.setAuth = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.contentservice.PrebuildInitializer} returns this
+ * @return {!proto.contentservice.SubversionInitializer} returns this
  */
-proto.contentservice.PrebuildInitializer.prototype.clearGit = function() {
-  return this.setGit(undefined);
+proto.contentservice.SubversionInitializer.prototype.clearAuth = function() {
+  return this.setAuth(undefined);
 };
 
 
@@ -2282,8 +3386,8 @@ proto.contentservice.PrebuildInitializer.prototype.clearGit = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.contentservice.PrebuildInitializer.prototype.hasGit = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.contentservice.SubversionInitializer.prototype.hasAuth = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
@@ -2303,8 +3407,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.contentservice.FromBackupInitializer.prototype.toObject = function(opt_includeInstance) {
-  return proto.contentservice.FromBackupInitializer.toObject(opt_includeInstance, this);
+proto.contentservice.RemoteAuth.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.RemoteAuth.toObject(opt_includeInstance, this);
 };
 
 
@@ -2313,13 +3417,16 @@ proto.contentservice.FromBackupInitializer.prototype.toObject = function(opt_inc
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.contentservice.FromBackupInitializer} msg The msg instance to transform.
+ * @param {!proto.contentservice.RemoteAuth} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.contentservice.FromBackupInitializer.toObject = function(includeInstance, msg) {
+proto.contentservice.RemoteAuth.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    method: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    user: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    password: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    ots: jspb.Message.getFieldWithDefault(msg, 4, "")
   };
 
   if (includeInstance) {
@@ -2333,29 +3440,45 @@ proto.contentservice.FromBackupInitializer.toObject = function(includeInstance,
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.contentservice.FromBackupInitializer}
+ * @return {!proto.contentservice.RemoteAuth}
  */
-proto.contentservice.FromBackupInitializer.deserializeBinary = function(bytes) {
+proto.contentservice.RemoteAuth.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.contentservice.FromBackupInitializer;
-  return proto.contentservice.FromBackupInitializer.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.contentservice.RemoteAuth;
+  return proto.contentservice.RemoteAuth.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.contentservice.FromBackupInitializer} msg The message object to deserialize into.
+ * @param {!proto.contentservice.RemoteAuth} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.contentservice.FromBackupInitializer}
+ * @return {!proto.contentservice.RemoteAuth}
  */
-proto.contentservice.FromBackupInitializer.deserializeBinaryFromReader = function(msg, reader) {
+proto.contentservice.RemoteAuth.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = /** @type {!proto.contentservice.GitAuthMethod} */ (reader.readEnum());
+      msg.setMethod(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUser(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPassword(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOts(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -2369,9 +3492,9 @@ proto.contentservice.FromBackupInitializer.deserializeBinaryFromReader = functio
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.contentservice.FromBackupInitializer.prototype.serializeBinary = function() {
+proto.contentservice.RemoteAuth.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.contentservice.FromBackupInitializer.serializeBinaryToWriter(this, writer);
+  proto.contentservice.RemoteAuth.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2379,12 +3502,112 @@ proto.contentservice.FromBackupInitializer.prototype.serializeBinary = function(
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.contentservice.FromBackupInitializer} message
+ * @param {!proto.contentservice.RemoteAuth} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.contentservice.FromBackupInitializer.serializeBinaryToWriter = function(message, writer) {
+proto.contentservice.RemoteAuth.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getMethod();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      1,
+      f
+    );
+  }
+  f = message.getUser();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getPassword();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getOts();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional GitAuthMethod method = 1;
+ * @return {!proto.contentservice.GitAuthMethod}
+ */
+proto.contentservice.RemoteAuth.prototype.getMethod = function() {
+  return /** @type {!proto.contentservice.GitAuthMethod} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/**
+ * @param {!proto.contentservice.GitAuthMethod} value
+ * @return {!proto.contentservice.RemoteAuth} returns this
+ */
+proto.contentservice.RemoteAuth.prototype.setMethod = function(value) {
+  return jspb.Message.setProto3EnumField(this, 1, value);
+};
+
+
+/**
+ * optional string user = 2;
+ * @return {string}
+ */
+proto.contentservice.RemoteAuth.prototype.getUser = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.RemoteAuth} returns this
+ */
+proto.contentservice.RemoteAuth.prototype.setUser = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string password = 3;
+ * @return {string}
+ */
+proto.contentservice.RemoteAuth.prototype.getPassword = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.RemoteAuth} returns this
+ */
+proto.contentservice.RemoteAuth.prototype.setPassword = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string ots = 4;
+ * @return {string}
+ */
+proto.contentservice.RemoteAuth.prototype.getOts = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.RemoteAuth} returns this
+ */
+proto.contentservice.RemoteAuth.prototype.setOts = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
 };
 
 
@@ -2802,6 +4025,24 @@ proto.contentservice.CloneTargetMode = {
   LOCAL_BRANCH: 3
 };
 
+/**
+ * @enum {number}
+ */
+proto.contentservice.CloneFilter = {
+  NO_FILTER: 0,
+  BLOBLESS: 1,
+  TREELESS: 2
+};
+
+/**
+ * @enum {number}
+ */
+proto.contentservice.LFSMode = {
+  LFS_FETCH: 0,
+  LFS_LAZY: 1,
+  LFS_SKIP: 2
+};
+
 /**
  * @enum {number}
  */
@@ -92,8 +92,49 @@ type Client struct {
 
 	// UpstreamCloneURI is the fork upstream of a repository
 	UpstreamRemoteURI string
+
+	// CloneProgress is called with the completion of a clone in percent while Clone runs
+	CloneProgress func(percent int)
+
+	// Strategy determines how much of the repository Clone fetches. If nil, Clone uses DefaultCloneStrategy.
+	Strategy *CloneStrategy
+}
+
+// CloneFilter determines which objects a partial clone omits. Git fetches them on demand.
+type CloneFilter string
+
+const (
+	// NoFilter clones all objects
+	NoFilter CloneFilter = ""
+
+	// BloblessFilter omits all file contents
+	BloblessFilter CloneFilter = "blob:none"
+
+	// TreelessFilter omits all file contents and trees
+	TreelessFilter CloneFilter = "tree:0"
+)
+
+// CloneStrategy determines how much of a repository we clone
+type CloneStrategy struct {
+	// Depth limits the history to the given number of commits. Zero clones the full history.
+	Depth int
+
+	// Filter makes the clone a partial one
+	Filter CloneFilter
+
+	// SparseCheckout restricts the working copy to these directories (cone mode). If empty, we check out everything.
+	SparseCheckout []string
+
+	// Unshallow has supervisor fetch the full history once the workspace is running
+	Unshallow bool
 }
 
+// DefaultCloneStrategy clones all branches with a depth of one and fetches the rest of the history later on
+var DefaultCloneStrategy = CloneStrategy{Depth: 1, Unshallow: true}
+
+// ConfigUnshallow is the Git config key which tells supervisor whether to fetch the full history of a shallow clone
+const ConfigUnshallow = "gitpod.unshallow"
+
 // Status describes the status of a Git repo/working copy akin to "git status"
 type Status struct {
 	porcelainStatus
@@ -148,6 +189,11 @@ func (e OpFailedError) Error() string {
 // GitWithOutput starts git and returns the stdout of the process. This function returns once git is started,
 // not after it finishd. Once the returned reader returned io.EOF, the command is finished.
 func (c *Client) GitWithOutput(ctx context.Context, subcommand string, args ...string) (out []byte, err error) {
+	return c.gitWithOutput(ctx, nil, subcommand, args...)
+}
+
+// gitWithOutput runs git like GitWithOutput does, and additionally copies the output of git to progress if that's not nil
+func (c *Client) gitWithOutput(ctx context.Context, progress io.Writer, subcommand string, args ...string) (out []byte, err error) {
 	//nolint:staticcheck,ineffassign
 	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("git.%s", subcommand))
 	defer tracing.FinishSpan(span, &err)
@@ -184,7 +230,19 @@ func (c *Client) GitWithOutput(ctx context.Context, subcommand string, args ...s
 	cmd.Dir = c.Location
 	cmd.Env = env
 
-	res, err := cmd.CombinedOutput()
+	var (
+		buf    bytes.Buffer
+		output io.Writer = &buf
+	)
+	if progress != nil {
+		output = io.MultiWriter(&buf, progress)
+	}
+	// git writes to stdout and stderr through the same writer, hence they share a pipe just like with CombinedOutput
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err = cmd.Run()
+	res := buf.Bytes()
 	if err != nil {
 		if strings.Contains(err.Error(), "no child process") {
 			return res, nil
@@ -265,7 +323,32 @@ func (c *Client) Clone(ctx context.Context) (err error) {
 		log.WithError(err).Error("cannot create clone location")
 	}
 
-	args := []string{"--depth=1", "--no-single-branch", c.RemoteURI}
+	strategy := DefaultCloneStrategy
+	if c.Strategy != nil {
+		strategy = *c.Strategy
+	}
+
+	var args []string
+	if strategy.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", strategy.Depth), "--no-single-branch")
+		if !strategy.Unshallow {
+			args = append(args, "--config", ConfigUnshallow+"=false")
+		}
+	}
+	if strategy.Filter != NoFilter {
+		args = append(args, "--filter="+string(strategy.Filter))
+	}
+	if len(strategy.SparseCheckout) > 0 {
+		// --sparse checks out the top-level files only until we've set the patterns
+		args = append(args, "--sparse")
+	}
+	args = append(args, c.RemoteURI)
+
+	var progress io.Writer
+	if c.CloneProgress != nil {
+		args = append([]string{"--progress"}, args...)
+		progress = &cloneProgress{report: c.CloneProgress}
+	}
 
 	for key, value := range c.Config {
 		args = append(args, "--config")
@@ -274,7 +357,20 @@ func (c *Client) Clone(ctx context.Context) (err error) {
 
 	args = append(args, ".")
 
-	return c.Git(ctx, "clone", args...)
+	_, err = c.gitWithOutput(ctx, progress, "clone", args...)
+	if err != nil {
+		return err
+	}
+
+	if len(strategy.SparseCheckout) > 0 {
+		if err := c.Git(ctx, "sparse-checkout", "init", "--cone"); err != nil {
+			return err
+		}
+		if err := c.Git(ctx, "sparse-checkout", append([]string{"set"}, strategy.SparseCheckout...)...); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Fetch runs git fetch and prunes remote-tracking references as well as ALL LOCAL TAGS.
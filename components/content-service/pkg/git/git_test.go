@@ -6,8 +6,10 @@ package git
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -228,6 +230,122 @@ func TestGitStatus(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	type Expectation struct {
+		Commits   int
+		Files     []string
+		Filter    string
+		Unshallow string
+	}
+	tests := []struct {
+		Name        string
+		Strategy    *CloneStrategy
+		Expectation Expectation
+	}{
+		{
+			Name:        "default",
+			Expectation: Expectation{Commits: 1, Files: []string{"backend/main.go", "frontend/index.ts", "README.md"}},
+		},
+		{
+			Name:        "full",
+			Strategy:    &CloneStrategy{},
+			Expectation: Expectation{Commits: 3, Files: []string{"backend/main.go", "frontend/index.ts", "README.md"}},
+		},
+		{
+			Name:        "shallow without unshallow",
+			Strategy:    &CloneStrategy{Depth: 2},
+			Expectation: Expectation{Commits: 2, Files: []string{"backend/main.go", "frontend/index.ts", "README.md"}, Unshallow: "false"},
+		},
+		{
+			Name:        "blobless",
+			Strategy:    &CloneStrategy{Filter: BloblessFilter},
+			Expectation: Expectation{Commits: 3, Files: []string{"backend/main.go", "frontend/index.ts", "README.md"}, Filter: "blob:none"},
+		},
+		{
+			Name:        "sparse treeless",
+			Strategy:    &CloneStrategy{Filter: TreelessFilter, SparseCheckout: []string{"backend"}},
+			Expectation: Expectation{Commits: 3, Files: []string{"backend/main.go", "README.md"}, Filter: "tree:0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			remote, err := newGitClient(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(remote.Location)
+			err = remote.Git(ctx, "init")
+			if err != nil {
+				t.Fatal(err)
+			}
+			// partial clones need the remote to serve filtered packs
+			err = remote.Git(ctx, "config", "uploadpack.allowFilter", "true")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, fn := range []string{"README.md", "backend/main.go", "frontend/index.ts"} {
+				err = os.MkdirAll(filepath.Join(remote.Location, filepath.Dir(fn)), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(remote.Location, fn), []byte(fn), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = remote.Git(ctx, "add", fn)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = remote.Git(ctx, "-c", "user.email=foo@bar.com", "-c", "user.name=foo bar", "commit", "-m", fmt.Sprintf("commit %d", i))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			client, err := newGitClient(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(client.Location)
+			// local clones ignore depth and filters, hence the file:// URL
+			client.RemoteURI = "file://" + remote.Location
+			client.Strategy = test.Strategy
+			err = client.Clone(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var act Expectation
+			out, err := client.GitWithOutput(ctx, "rev-list", "--count", "HEAD")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = fmt.Sscan(string(out), &act.Commits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, fn := range []string{"backend/main.go", "frontend/index.ts", "README.md"} {
+				if _, err := os.Stat(filepath.Join(client.Location, fn)); err == nil {
+					act.Files = append(act.Files, fn)
+				}
+			}
+			// git config fails for unset keys
+			out, _ = client.GitWithOutput(ctx, "config", "remote.origin.partialclonefilter")
+			act.Filter = strings.TrimSpace(string(out))
+			out, _ = client.GitWithOutput(ctx, "config", ConfigUnshallow)
+			act.Unshallow = strings.TrimSpace(string(out))
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected clone (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func newGitClient(ctx context.Context) (*Client, error) {
 	loc, err := os.MkdirTemp("", "gittest")
 	if err != nil {
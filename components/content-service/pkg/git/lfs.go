@@ -0,0 +1,95 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+)
+
+// ConfigLFS is the Git config key which tells supervisor to pull the LFS objects of a repository in the background
+const ConfigLFS = "gitpod.lfs"
+
+// LFSLazy is the value of ConfigLFS for repositories whose LFS objects supervisor pulls once the workspace is running
+const LFSLazy = "lazy"
+
+// UsesLFS returns true if the .gitattributes files of the working copy have Git LFS track files.
+// Attribute files which aren't checked out, e.g. because of a sparse checkout, don't count.
+func (c *Client) UsesLFS(ctx context.Context) (res bool, err error) {
+	//nolint:staticcheck,ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "usesLFS")
+	defer tracing.FinishSpan(span, &err)
+
+	out, err := c.GitWithOutput(ctx, "ls-files", "-z", "--", ":(glob)**/.gitattributes")
+	if err != nil {
+		return false, err
+	}
+	for _, fn := range strings.Split(string(out), "\x00") {
+		if fn == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(c.Location, fn))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if tracksLFS(content) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// tracksLFS returns true if a .gitattributes file assigns the LFS filter to some files
+func tracksLFS(gitattributes []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(gitattributes))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// the first field is the pattern, all others are attributes
+		for _, attr := range strings.Fields(line)[1:] {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// InstallLFS configures the LFS filters for the repository, so that Git replaces LFS pointer files with their content on checkout.
+// Unlike "git lfs install" on its own, this neither installs hooks nor makes the filters required, so that the repository
+// keeps working with workspace images which don't have Git LFS installed.
+func (c *Client) InstallLFS(ctx context.Context) (err error) {
+	//nolint:staticcheck,ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "installLFS")
+	defer tracing.FinishSpan(span, &err)
+
+	if err := c.Git(ctx, "lfs", "install", "--local", "--skip-repo"); err != nil {
+		return err
+	}
+	return c.Git(ctx, "config", "--local", "filter.lfs.required", "false")
+}
+
+// PullLFS downloads the LFS objects of the checkout and replaces the LFS pointer files with their content.
+// Git LFS uses the same credentials as the clone.
+func (c *Client) PullLFS(ctx context.Context) (err error) {
+	//nolint:staticcheck,ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "pullLFS")
+	defer tracing.FinishSpan(span, &err)
+
+	return c.Git(ctx, "lfs", "pull")
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsesLFS(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Files       map[string]string
+		Untracked   map[string]string
+		Expectation bool
+	}{
+		{Name: "no attributes", Files: map[string]string{"README.md": "hello"}},
+		{
+			Name:  "no lfs",
+			Files: map[string]string{".gitattributes": "*.sh text eol=lf\n"},
+		},
+		{
+			Name:        "top-level lfs",
+			Files:       map[string]string{".gitattributes": "*.sh text eol=lf\n*.psd filter=lfs diff=lfs merge=lfs -text\n"},
+			Expectation: true,
+		},
+		{
+			Name:        "nested lfs",
+			Files:       map[string]string{"assets/textures/.gitattributes": "*.png filter=lfs diff=lfs merge=lfs -text\n"},
+			Expectation: true,
+		},
+		{
+			Name:  "commented out",
+			Files: map[string]string{".gitattributes": "# *.psd filter=lfs diff=lfs merge=lfs -text\n"},
+		},
+		{
+			Name:      "untracked attributes",
+			Files:     map[string]string{"README.md": "hello"},
+			Untracked: map[string]string{".gitattributes": "*.psd filter=lfs diff=lfs merge=lfs -text\n"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := newGitClient(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(client.Location)
+			err = client.Git(ctx, "init")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			write := func(files map[string]string) {
+				for fn, content := range files {
+					err := os.MkdirAll(filepath.Join(client.Location, filepath.Dir(fn)), 0755)
+					if err != nil {
+						t.Fatal(err)
+					}
+					err = os.WriteFile(filepath.Join(client.Location, fn), []byte(content), 0644)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			write(test.Files)
+			err = client.Git(ctx, "add", ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			write(test.Untracked)
+
+			act, err := client.UsesLFS(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.Expectation {
+				t.Errorf("expected UsesLFS to be %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package git
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// cloneStages are the stages of git clone which report their progress, together with the share
+// of the overall clone progress we attribute to them.
+var cloneStages = []struct {
+	Prefix   string
+	From, To int
+}{
+	{Prefix: "Receiving objects:", From: 0, To: 70},
+	{Prefix: "Resolving deltas:", From: 70, To: 90},
+	{Prefix: "Updating files:", From: 90, To: 100},
+}
+
+// cloneProgress parses the progress git clone --progress writes to stderr, and reports
+// the completion of the clone whenever it grows.
+type cloneProgress struct {
+	report func(percent int)
+
+	buf  []byte
+	last int
+}
+
+// Write parses the progress lines of git. git rewrites a progress line using \r and ends it using \n.
+func (p *cloneProgress) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexAny(p.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		p.parseLine(string(p.buf[:i]))
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+func (p *cloneProgress) parseLine(line string) {
+	for _, stage := range cloneStages {
+		if !strings.HasPrefix(line, stage.Prefix) {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, stage.Prefix))
+		if len(fields) == 0 || !strings.HasSuffix(fields[0], "%") {
+			return
+		}
+		pct, err := strconv.Atoi(strings.TrimSuffix(fields[0], "%"))
+		if err != nil {
+			return
+		}
+
+		total := stage.From + pct*(stage.To-stage.From)/100
+		if total > p.last {
+			p.last = total
+			p.report(total)
+		}
+		return
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package git
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCloneProgress(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Writes      []string
+		Expectation []int
+	}{
+		{
+			Name: "full clone",
+			Writes: []string{
+				"Cloning into '.'...\n",
+				"remote: Counting objects:  50% (5/10)\rremote: Counting objects: 100% (10/10), done.\n",
+				"Receiving objects:   0% (0/10)\rReceiving objects:  50% (5/10)\rReceiving objects: 100% (10/10), 1.20 KiB | 1.20 MiB/s, done.\n",
+				"Resolving deltas:   0% (0/4)\rResolving deltas: 100% (4/4), done.\n",
+				"Updating files:  50% (1/2)\rUpdating files: 100% (2/2), done.\n",
+			},
+			Expectation: []int{35, 70, 90, 95, 100},
+		},
+		{
+			Name: "lines split across writes",
+			Writes: []string{
+				"Receiving obj",
+				"ects:  10% (1/10)\rReceiving objects:  2",
+				"0% (2/10)\r",
+			},
+			Expectation: []int{7, 14},
+		},
+		{
+			Name: "progress does not go backwards",
+			Writes: []string{
+				"Receiving objects: 100% (10/10)\n",
+				"Receiving objects:  50% (5/10)\n",
+			},
+			Expectation: []int{70},
+		},
+		{
+			Name:   "no progress",
+			Writes: []string{"fatal: repository 'foo' does not exist\n"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act []int
+			p := &cloneProgress{report: func(percent int) { act = append(act, percent) }}
+			for _, w := range test.Writes {
+				_, err := p.Write([]byte(w))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected progress (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package initializer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
@@ -41,6 +42,23 @@ const (
 	LocalBranch CloneTargetMode = "local-branch"
 )
 
+// LFSMode determines how we fetch the Git LFS objects of a repository
+type LFSMode string
+
+const (
+	// LFSFetch downloads the LFS objects of the checkout while initializing the workspace
+	LFSFetch LFSMode = "fetch"
+
+	// LFSLazy has supervisor download the LFS objects in the background once the workspace is running
+	LFSLazy LFSMode = "lazy"
+
+	// LFSSkip leaves the LFS pointer files in place
+	LFSSkip LFSMode = "skip"
+)
+
+// minCommitFetchDepth is the number of commits we fetch at least when checking out a specific commit of a shallow clone
+const minCommitFetchDepth = 20
+
 // GitInitializer is a local workspace with a Git connection
 type GitInitializer struct {
 	git.Client
@@ -53,6 +71,9 @@ type GitInitializer struct {
 
 	// If true, the Git initializer will chown(gitpod) after the clone
 	Chown bool
+
+	// LFS determines how we fetch the Git LFS objects of the repository, if it uses Git LFS. Defaults to LFSFetch.
+	LFS LFSMode
 }
 
 // Run initializes the workspace using Git
@@ -69,13 +90,18 @@ func (ws *GitInitializer) Run(ctx context.Context, mappings []archive.IDMapping)
 		return
 	}
 
+	cloneCtx := withProgressPhase(ctx, ProgressClone)
 	gitClone := func() error {
 		if err := os.MkdirAll(ws.Location, 0770); err != nil {
 			return err
 		}
 
 		log.WithField("stage", "init").WithField("location", ws.Location).Debug("Running git clone on workspace")
-		return ws.Clone(ctx)
+		c := ws.Client
+		if c.CloneProgress == nil {
+			c.CloneProgress = cloneProgress(cloneCtx)
+		}
+		return c.Clone(cloneCtx)
 	}
 	onGitCloneFailure := func(e error, d time.Duration) {
 		if err := os.RemoveAll(ws.Location); err != nil {
@@ -100,23 +126,16 @@ func (ws *GitInitializer) Run(ctx context.Context, mappings []archive.IDMapping)
 	}
 
 	if ws.Chown {
-		// TODO (aledbf): refactor to remove the need of manual chown
-		args := []string{"-R", "-L", "gitpod", ws.Location}
-		cmd := exec.Command("chown", args...)
-		res, cerr := cmd.CombinedOutput()
-		if cerr != nil && !process.IsNotChildProcess(cerr) {
-			err = git.OpFailedError{
-				Args:       args,
-				ExecErr:    cerr,
-				Output:     string(res),
-				Subcommand: "chown",
-			}
+		if err = chownGitpod(ws.Location); err != nil {
 			return
 		}
 	}
 	if err := ws.realizeCloneTarget(ctx); err != nil {
 		return src, xerrors.Errorf("git initializer: %w", err)
 	}
+	if err := ws.realizeLFS(ctx); err != nil {
+		return src, xerrors.Errorf("git initializer: %w", err)
+	}
 	if err := ws.UpdateRemote(ctx); err != nil {
 		return src, xerrors.Errorf("git initializer: %w", err)
 	}
@@ -128,6 +147,23 @@ func (ws *GitInitializer) Run(ctx context.Context, mappings []archive.IDMapping)
 	return
 }
 
+// chownGitpod hands the content at location over to the gitpod user
+func chownGitpod(location string) error {
+	// TODO (aledbf): refactor to remove the need of manual chown
+	args := []string{"-R", "-L", "gitpod", location}
+	cmd := exec.Command("chown", args...)
+	res, err := cmd.CombinedOutput()
+	if err != nil && !process.IsNotChildProcess(err) {
+		return git.OpFailedError{
+			Args:       args,
+			ExecErr:    err,
+			Output:     string(res),
+			Subcommand: "chown",
+		}
+	}
+	return nil
+}
+
 // realizeCloneTarget ensures the clone target is checked out
 func (ws *GitInitializer) realizeCloneTarget(ctx context.Context) (err error) {
 	//nolint:ineffassign
@@ -149,10 +185,10 @@ func (ws *GitInitializer) realizeCloneTarget(ctx context.Context) (err error) {
 			return err
 		}
 	} else if ws.TargetMode == RemoteCommit {
-		// We did a shallow clone before, hence need to fetch the commit we are about to check out.
+		// We may have done a shallow clone before, hence need to fetch the commit we are about to check out.
 		// Because we don't want to make the "git fetch" mechanism in supervisor more complicated,
 		// we'll just fetch the 20 commits right away.
-		if err := ws.Git(ctx, "fetch", "origin", ws.CloneTarget, "--depth=20"); err != nil {
+		if err := ws.Git(ctx, "fetch", ws.commitFetchArgs()...); err != nil {
 			return err
 		}
 
@@ -174,3 +210,55 @@ func (ws *GitInitializer) realizeCloneTarget(ctx context.Context) (err error) {
 	}
 	return nil
 }
+
+// realizeLFS replaces the LFS pointer files of the checkout with their content, or has supervisor do that later on
+func (ws *GitInitializer) realizeLFS(ctx context.Context) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "realizeLFS")
+	span.SetTag("lfsMode", ws.LFS)
+	defer tracing.FinishSpan(span, &err)
+
+	if ws.LFS == LFSSkip {
+		return nil
+	}
+	usesLFS, err := ws.UsesLFS(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("usesLFS", usesLFS)
+	if !usesLFS {
+		return nil
+	}
+
+	err = ws.InstallLFS(ctx)
+	if err != nil {
+		return err
+	}
+	if ws.LFS != LFSLazy {
+		err = ws.PullLFS(ctx)
+		if err == nil {
+			return nil
+		}
+		// LFS servers enforce bandwidth quotas, which shouldn't keep the workspace from starting
+		log.WithError(err).WithField("location", ws.Location).Warn("cannot pull Git LFS objects - leaving that to supervisor")
+	}
+	return ws.Git(ctx, "config", "--local", git.ConfigLFS, git.LFSLazy)
+}
+
+// commitFetchArgs returns the arguments to fetch the commit we check out in RemoteCommit mode.
+// The fetch must not shorten the history of shallow clones, nor make full clones shallow.
+func (ws *GitInitializer) commitFetchArgs() []string {
+	depth := git.DefaultCloneStrategy.Depth
+	if ws.Strategy != nil {
+		depth = ws.Strategy.Depth
+	}
+
+	args := []string{"origin", ws.CloneTarget}
+	if depth > 0 {
+		if depth < minCommitFetchDepth {
+			depth = minCommitFetchDepth
+		}
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	return args
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/git"
+)
+
+func TestNewCloneStrategy(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Req         *csapi.CloneStrategy
+		Expectation *git.CloneStrategy
+		Error       string
+	}{
+		{Name: "default"},
+		{Name: "full clone", Req: &csapi.CloneStrategy{}, Expectation: &git.CloneStrategy{}},
+		{
+			Name:        "shallow blobless",
+			Req:         &csapi.CloneStrategy{Depth: 10, Filter: csapi.CloneFilter_BLOBLESS, Unshallow: true},
+			Expectation: &git.CloneStrategy{Depth: 10, Filter: git.BloblessFilter, Unshallow: true},
+		},
+		{
+			Name:        "sparse",
+			Req:         &csapi.CloneStrategy{Filter: csapi.CloneFilter_TREELESS, SparseCheckoutPatterns: []string{"components/server/", " docs ", "", "/"}},
+			Expectation: &git.CloneStrategy{Filter: git.TreelessFilter, SparseCheckout: []string{"components/server", "docs"}},
+		},
+		{Name: "invalid filter", Req: &csapi.CloneStrategy{Filter: 42}, Error: "invalid clone filter: 42"},
+		{
+			Name:  "flag as pattern",
+			Req:   &csapi.CloneStrategy{SparseCheckoutPatterns: []string{"--no-cone"}},
+			Error: `invalid sparse checkout pattern "--no-cone": must be a directory within the repository`,
+		},
+		{
+			Name:  "pattern outside the repository",
+			Req:   &csapi.CloneStrategy{SparseCheckoutPatterns: []string{"docs/../../etc"}},
+			Error: `invalid sparse checkout pattern "docs/../../etc": must be a directory within the repository`,
+		},
+		{
+			Name:  "glob",
+			Req:   &csapi.CloneStrategy{SparseCheckoutPatterns: []string{"components/*"}},
+			Error: `invalid sparse checkout pattern "components/*": must be a directory within the repository`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := newCloneStrategy(test.Req)
+			if test.Error != "" {
+				if err == nil || err.Error() != test.Error {
+					t.Fatalf("expected error %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected clone strategy (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCommitFetchArgs(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Strategy    *git.CloneStrategy
+		Expectation []string
+	}{
+		{Name: "default", Expectation: []string{"origin", "abc", "--depth=20"}},
+		{Name: "shallow", Strategy: &git.CloneStrategy{Depth: 5}, Expectation: []string{"origin", "abc", "--depth=20"}},
+		{Name: "deep", Strategy: &git.CloneStrategy{Depth: 100}, Expectation: []string{"origin", "abc", "--depth=100"}},
+		{Name: "full", Strategy: &git.CloneStrategy{}, Expectation: []string{"origin", "abc"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ws := &GitInitializer{
+				Client:      git.Client{Strategy: test.Strategy},
+				TargetMode:  RemoteCommit,
+				CloneTarget: "abc",
+			}
+			if diff := cmp.Diff(test.Expectation, ws.commitFetchArgs()); diff != "" {
+				t.Errorf("unexpected fetch args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
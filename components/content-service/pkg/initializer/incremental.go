@@ -0,0 +1,81 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// RestoreIncrementalBackups applies all incremental backups on top of a restored default backup, in order.
+// Returns the number of incremental backups which were applied.
+func RestoreIncrementalBackups(ctx context.Context, location string, rs storage.DirectDownloader, mappings []archive.IDMapping) (n int, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RestoreIncrementalBackups")
+	defer tracing.FinishSpan(span, &err)
+
+	for {
+		name := fmt.Sprintf(storage.FmtIncrementalBackup, n+1)
+		found, err := rs.Download(ctx, location, name, mappings)
+		if err != nil {
+			return n, xerrors.Errorf("cannot restore incremental backup %s: %w", name, err)
+		}
+		if !found {
+			span.SetTag("incrementalBackups", n)
+			return n, nil
+		}
+
+		err = applyIncrementalDeletions(location)
+		if err != nil {
+			return n, xerrors.Errorf("cannot restore incremental backup %s: %w", name, err)
+		}
+		n++
+	}
+}
+
+// applyIncrementalDeletions removes the files an incremental backup lists as deleted
+func applyIncrementalDeletions(location string) error {
+	fn := filepath.Join(location, storage.IncrementalBackupDeletions)
+	f, err := os.Open(fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fn)
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		p := strings.TrimSpace(scanner.Text())
+		if p == "" {
+			continue
+		}
+
+		// Cleaning the path as if it were absolute makes sure we never leave the workspace location
+		dst := filepath.Join(location, filepath.Clean("/"+p))
+		if dst == location {
+			continue
+		}
+		err = os.RemoveAll(dst)
+		if err != nil {
+			log.WithError(err).WithField("path", p).Warn("cannot apply deletion of incremental backup")
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// fileDownloader "downloads" a backup by writing its files into the destination
+type fileDownloader map[string]map[string]string
+
+func (d fileDownloader) Download(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (found bool, err error) {
+	files, ok := d[name]
+	if !ok {
+		return false, nil
+	}
+	for fn, content := range files {
+		err = os.MkdirAll(filepath.Dir(filepath.Join(destination, fn)), 0755)
+		if err != nil {
+			return true, err
+		}
+		err = os.WriteFile(filepath.Join(destination, fn), []byte(content), 0644)
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func (d fileDownloader) DownloadSnapshot(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (found bool, err error) {
+	return d.Download(ctx, destination, name, mappings)
+}
+
+func TestRestoreIncrementalBackups(t *testing.T) {
+	type Expectation struct {
+		Count int
+		Files []string
+	}
+	tests := []struct {
+		Name        string
+		Initial     []string
+		Backups     fileDownloader
+		Expectation Expectation
+	}{
+		{
+			Name:        "no incremental backups",
+			Initial:     []string{"a.txt"},
+			Expectation: Expectation{Files: []string{"a.txt"}},
+		},
+		{
+			Name:    "changes and deletions",
+			Initial: []string{"a.txt", "b.txt", "dir/c.txt"},
+			Backups: fileDownloader{
+				"incremental-001.tar": {
+					"d.txt":                            "new",
+					storage.IncrementalBackupDeletions: "b.txt\n",
+				},
+				"incremental-002.tar": {
+					storage.IncrementalBackupDeletions: "dir\n",
+				},
+			},
+			Expectation: Expectation{Count: 2, Files: []string{"a.txt", "d.txt"}},
+		},
+		{
+			Name:    "deletions outside the workspace",
+			Initial: []string{"a.txt"},
+			Backups: fileDownloader{
+				"incremental-001.tar": {
+					storage.IncrementalBackupDeletions: "../../a.txt\n/\n",
+				},
+			},
+			Expectation: Expectation{Count: 1},
+		},
+		{
+			Name:    "gap in sequence",
+			Initial: []string{"a.txt"},
+			Backups: fileDownloader{
+				"incremental-002.tar": {"b.txt": "skipped"},
+			},
+			Expectation: Expectation{Files: []string{"a.txt"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			loc := t.TempDir()
+			for _, fn := range test.Initial {
+				err := os.MkdirAll(filepath.Dir(filepath.Join(loc, fn)), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(loc, fn), nil, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var (
+				act Expectation
+				err error
+			)
+			act.Count, err = initializer.RestoreIncrementalBackups(context.Background(), loc, test.Backups, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = filepath.Walk(loc, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(loc, path)
+				act.Files = append(act.Files, rel)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(act.Files)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected RestoreIncrementalBackups (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
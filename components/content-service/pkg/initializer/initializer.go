@@ -73,9 +73,9 @@ func (e CompositeInitializer) Run(ctx context.Context, mappings []archive.IDMapp
 
 // NewFromRequestOpts configures the initializer produced from a content init request
 type NewFromRequestOpts struct {
-	// ForceGitpodUserForGit forces gitpod:gitpod ownership on all files produced by the Git initializer.
+	// ForceGitpodUserForGit forces gitpod:gitpod ownership on all files produced by the Git, Mercurial and Subversion initializers.
 	// For FWB workspaces the content init is run from supervisor which runs as UID 0. Using this flag, the
-	// Git content is forced to the Gitpod user. All other content (backup, prebuild, snapshot) will already
+	// cloned content is forced to the Gitpod user. All other content (backup, prebuild, snapshot) will already
 	// have the correct user.
 	ForceGitpodUserForGit bool
 }
@@ -137,6 +137,18 @@ func NewFromRequest(ctx context.Context, loc string, rs storage.DirectDownloader
 		initializer, err = newFileDownloadInitializer(loc, ir.Download)
 	} else if ir, ok := spec.(*csapi.WorkspaceInitializer_Backup); ok {
 		initializer, err = newFromBackupInitializer(loc, rs, ir.Backup)
+	} else if ir, ok := spec.(*csapi.WorkspaceInitializer_Mercurial); ok {
+		if ir.Mercurial == nil {
+			return nil, status.Error(codes.InvalidArgument, "missing Mercurial initializer spec")
+		}
+
+		initializer, err = newMercurialInitializer(ctx, loc, ir.Mercurial, opts.ForceGitpodUserForGit)
+	} else if ir, ok := spec.(*csapi.WorkspaceInitializer_Subversion); ok {
+		if ir.Subversion == nil {
+			return nil, status.Error(codes.InvalidArgument, "missing Subversion initializer spec")
+		}
+
+		initializer, err = newSubversionInitializer(ctx, loc, ir.Subversion, opts.ForceGitpodUserForGit)
 	} else {
 		initializer = &EmptyInitializer{}
 	}
@@ -190,6 +202,10 @@ func (bi *fromBackupInitializer) Run(ctx context.Context, mappings []archive.IDM
 	if err != nil {
 		return src, xerrors.Errorf("cannot restore backup: %w", err)
 	}
+	_, err = RestoreIncrementalBackups(ctx, bi.Location, bi.RemoteStorage, mappings)
+	if err != nil {
+		return src, err
+	}
 
 	return csapi.WorkspaceInitFromBackup, nil
 }
@@ -215,48 +231,152 @@ func newGitInitializer(ctx context.Context, loc string, req *csapi.GitInitialize
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid target mode: %v", req.TargetMode))
 	}
 
+	authMethod, authProvider := newAuthProvider(ctx, loc, req.Config.Authentication, req.Config.AuthUser, req.Config.AuthPassword, req.Config.AuthOts)
+
+	strategy, err := newCloneStrategy(req.CloneStrategy)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var lfsMode LFSMode
+	switch req.LfsMode {
+	case csapi.LFSMode_LFS_FETCH:
+		lfsMode = LFSFetch
+	case csapi.LFSMode_LFS_LAZY:
+		lfsMode = LFSLazy
+	case csapi.LFSMode_LFS_SKIP:
+		lfsMode = LFSSkip
+	default:
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid LFS mode: %v", req.LfsMode))
+	}
+
+	log.WithField("location", loc).Debug("using Git initializer")
+	return &GitInitializer{
+		Client: git.Client{
+			Location:          filepath.Join(loc, req.CheckoutLocation),
+			RemoteURI:         req.RemoteUri,
+			UpstreamRemoteURI: req.Upstream_RemoteUri,
+			Config:            req.Config.CustomConfig,
+			AuthMethod:        authMethod,
+			AuthProvider:      authProvider,
+			Strategy:          strategy,
+		},
+		TargetMode:  targetMode,
+		CloneTarget: req.CloneTaget,
+		Chown:       forceGitpodUser,
+		LFS:         lfsMode,
+	}, nil
+}
+
+// newAuthProvider produces the authentication of an initializer request.
+// Returns gRPC errors.
+func newAuthProvider(ctx context.Context, loc string, method csapi.GitAuthMethod, authUser, authPassword, authOTS string) (git.AuthMethod, git.AuthProvider) {
 	var authMethod = git.BasicAuth
-	if req.Config.Authentication == csapi.GitAuthMethod_NO_AUTH {
+	if method == csapi.GitAuthMethod_NO_AUTH {
 		authMethod = git.NoAuth
 	}
 
 	// the auth provider must cache the OTS because it may be used several times,
 	// but can download the one-time-secret only once.
 	authProvider := git.CachingAuthProvider(func() (user string, pwd string, err error) {
-		switch req.Config.Authentication {
+		switch method {
 		case csapi.GitAuthMethod_BASIC_AUTH:
-			user = req.Config.AuthUser
-			pwd = req.Config.AuthPassword
+			user = authUser
+			pwd = authPassword
 		case csapi.GitAuthMethod_BASIC_AUTH_OTS:
-			user, pwd, err = downloadOTS(ctx, req.Config.AuthOts)
+			user, pwd, err = downloadOTS(ctx, authOTS)
 			if err != nil {
-				log.WithField("location", loc).WithError(err).Error("cannot download Git auth OTS")
+				log.WithField("location", loc).WithError(err).Error("cannot download auth OTS")
 				return "", "", status.Error(codes.InvalidArgument, "cannot get OTS")
 			}
 		case csapi.GitAuthMethod_NO_AUTH:
 		default:
-			return "", "", status.Error(codes.InvalidArgument, fmt.Sprintf("invalid Git authentication method: %v", req.Config.Authentication))
+			return "", "", status.Error(codes.InvalidArgument, fmt.Sprintf("invalid authentication method: %v", method))
 		}
 
 		return
 	})
+	return authMethod, authProvider
+}
 
-	log.WithField("location", loc).Debug("using Git initializer")
-	return &GitInitializer{
-		Client: git.Client{
-			Location:          filepath.Join(loc, req.CheckoutLocation),
-			RemoteURI:         req.RemoteUri,
-			UpstreamRemoteURI: req.Upstream_RemoteUri,
-			Config:            req.Config.CustomConfig,
-			AuthMethod:        authMethod,
-			AuthProvider:      authProvider,
-		},
-		TargetMode:  targetMode,
-		CloneTarget: req.CloneTaget,
-		Chown:       forceGitpodUser,
+// newMercurialInitializer creates a Mercurial initializer based on the request.
+// Returns gRPC errors.
+func newMercurialInitializer(ctx context.Context, loc string, req *csapi.MercurialInitializer, forceGitpodUser bool) (*MercurialInitializer, error) {
+	if req.RemoteUri == "" {
+		return nil, status.Error(codes.InvalidArgument, "Mercurial initializer misses remote URI")
+	}
+
+	authMethod, authProvider := newAuthProvider(ctx, loc, req.Auth.GetMethod(), req.Auth.GetUser(), req.Auth.GetPassword(), req.Auth.GetOts())
+
+	log.WithField("location", loc).Debug("using Mercurial initializer")
+	return &MercurialInitializer{
+		Location:     filepath.Join(loc, req.CheckoutLocation),
+		RemoteURI:    req.RemoteUri,
+		Revision:     req.Revision,
+		AuthMethod:   authMethod,
+		AuthProvider: authProvider,
+		Chown:        forceGitpodUser,
 	}, nil
 }
 
+// newSubversionInitializer creates a Subversion initializer based on the request.
+// Returns gRPC errors.
+func newSubversionInitializer(ctx context.Context, loc string, req *csapi.SubversionInitializer, forceGitpodUser bool) (*SubversionInitializer, error) {
+	if req.RemoteUri == "" {
+		return nil, status.Error(codes.InvalidArgument, "Subversion initializer misses remote URI")
+	}
+
+	authMethod, authProvider := newAuthProvider(ctx, loc, req.Auth.GetMethod(), req.Auth.GetUser(), req.Auth.GetPassword(), req.Auth.GetOts())
+
+	log.WithField("location", loc).Debug("using Subversion initializer")
+	return &SubversionInitializer{
+		Location:     filepath.Join(loc, req.CheckoutLocation),
+		RemoteURI:    req.RemoteUri,
+		Revision:     req.Revision,
+		AuthMethod:   authMethod,
+		AuthProvider: authProvider,
+		Chown:        forceGitpodUser,
+	}, nil
+}
+
+// newCloneStrategy translates the clone strategy of a Git initializer request.
+// Returns nil if the request has none, i.e. we use the default strategy.
+func newCloneStrategy(req *csapi.CloneStrategy) (*git.CloneStrategy, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	res := &git.CloneStrategy{
+		Depth:     int(req.Depth),
+		Unshallow: req.Unshallow,
+	}
+	switch req.Filter {
+	case csapi.CloneFilter_NO_FILTER:
+		res.Filter = git.NoFilter
+	case csapi.CloneFilter_BLOBLESS:
+		res.Filter = git.BloblessFilter
+	case csapi.CloneFilter_TREELESS:
+		res.Filter = git.TreelessFilter
+	default:
+		return nil, xerrors.Errorf("invalid clone filter: %v", req.Filter)
+	}
+
+	for _, p := range req.SparseCheckoutPatterns {
+		// cone mode patterns are directories relative to the root of the repository, with or without leading slash
+		dir := filepath.Clean(strings.Trim(strings.TrimSpace(p), "/"))
+		if dir == "." {
+			continue
+		}
+		// anything but a directory within the repository could end up as a flag or outside the working copy
+		if dir == ".." || strings.HasPrefix(dir, "../") || strings.HasPrefix(dir, "-") || strings.ContainsAny(dir, "*?[\\!") {
+			return nil, xerrors.Errorf("invalid sparse checkout pattern %q: must be a directory within the repository", p)
+		}
+		res.SparseCheckout = append(res.SparseCheckout, dir)
+	}
+
+	return res, nil
+}
+
 func newSnapshotInitializer(loc string, rs storage.DirectDownloader, req *csapi.SnapshotInitializer) (*SnapshotInitializer, error) {
 	return &SnapshotInitializer{
 		Location: loc,
@@ -412,13 +532,18 @@ func InitializeWorkspace(ctx context.Context, location string, remoteStorage sto
 	}
 
 	// Run the initializer
-	hasBackup, err := remoteStorage.Download(ctx, location, storage.DefaultBackup, cfg.mappings)
+	backupCtx := withProgressPhase(ctx, ProgressBackup)
+	hasBackup, err := remoteStorage.Download(backupCtx, location, storage.DefaultBackup, cfg.mappings)
 	if err != nil {
 		return src, xerrors.Errorf("cannot restore backup: %w", err)
 	}
 
 	span.SetTag("hasBackup", hasBackup)
 	if hasBackup {
+		_, err = RestoreIncrementalBackups(backupCtx, location, remoteStorage, cfg.mappings)
+		if err != nil {
+			return src, err
+		}
 		src = csapi.WorkspaceInitFromBackup
 	} else {
 		src, err = cfg.Initializer.Run(ctx, cfg.mappings)
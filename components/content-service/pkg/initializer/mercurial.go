@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/git"
+)
+
+// MercurialInitializer is a local workspace with a Mercurial connection
+type MercurialInitializer struct {
+	// Location is the path in which we clone the repository
+	Location string
+
+	// RemoteURI is the repository we clone
+	RemoteURI string
+
+	// Revision is the changeset, branch, bookmark or tag we update the working copy to.
+	// Defaults to the tip of the default branch.
+	Revision string
+
+	// AuthMethod is the means of authentication used during clone
+	AuthMethod git.AuthMethod
+
+	// AuthProvider provides the credentials if we use basic auth
+	AuthProvider git.AuthProvider
+
+	// If true, the Mercurial initializer will chown(gitpod) after the clone
+	Chown bool
+}
+
+// Run initializes the workspace using Mercurial
+func (ws *MercurialInitializer) Run(ctx context.Context, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
+	isHgWS := isWorkingCopy(ws.Location, ".hg")
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "MercurialInitializer.Run")
+	span.SetTag("isHgWS", isHgWS)
+	defer tracing.FinishSpan(span, &err)
+
+	src = csapi.WorkspaceInitFromOther
+	if isHgWS {
+		log.WithField("stage", "init").WithField("location", ws.Location).Info("Not running hg clone. Workspace is already a Mercurial workspace")
+		return
+	}
+
+	cloneCtx := withProgressPhase(ctx, ProgressClone)
+	err = cloneWithRetry(ws.Location, func() error {
+		log.WithField("stage", "init").WithField("location", ws.Location).Debug("Running hg clone on workspace")
+		return ws.clone(cloneCtx)
+	})
+	if err != nil {
+		return src, xerrors.Errorf("mercurial initializer: %w", err)
+	}
+
+	if ws.Chown {
+		if err = chownGitpod(ws.Location); err != nil {
+			return
+		}
+	}
+
+	log.WithField("stage", "init").WithField("location", ws.Location).Debug("Mercurial operations complete")
+	return
+}
+
+// clone clones the repository into the working copy location
+func (ws *MercurialInitializer) clone(ctx context.Context) error {
+	// Mercurial reads credentials from its configuration only. We hand them over in the hgrc of a temporary
+	// home directory, so that they neither show up in the process list nor remain in the working copy.
+	home, err := os.MkdirTemp("", "hg-home-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+
+	if ws.AuthMethod == git.BasicAuth {
+		if ws.AuthProvider == nil {
+			return xerrors.Errorf("basic-auth method requires an auth provider")
+		}
+		user, pwd, err := ws.AuthProvider()
+		if err != nil {
+			return err
+		}
+		hgrc, err := hgAuthConfig(user, pwd)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join(home, ".hgrc"), hgrc, 0600)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = runVCS(ctx, ws.Location, []string{"HOME=" + home, "HGPLAIN=1"}, nil, "hg", ws.cloneArgs()...)
+	return err
+}
+
+// cloneArgs returns the arguments of "hg clone"
+func (ws *MercurialInitializer) cloneArgs() []string {
+	args := []string{"clone", "--noninteractive"}
+	if ws.Revision != "" {
+		args = append(args, "--updaterev="+ws.Revision)
+	}
+	// the remote URI is user input and must not be mistaken for an option
+	return append(args, "--", ws.RemoteURI, ".")
+}
+
+// hgAuthConfig produces an hgrc which has Mercurial authenticate with any remote using user and pwd
+func hgAuthConfig(user, pwd string) ([]byte, error) {
+	// hgrc is line-based, a line break would let the credentials add arbitrary configuration
+	if strings.ContainsAny(user+pwd, "\r\n") {
+		return nil, xerrors.Errorf("credentials must not contain line breaks")
+	}
+
+	var res strings.Builder
+	res.WriteString("[auth]\ngitpod.prefix = *\ngitpod.schemes = http https\n")
+	if user != "" {
+		fmt.Fprintf(&res, "gitpod.username = %s\n", user)
+	}
+	fmt.Fprintf(&res, "gitpod.password = %s\n", pwd)
+	return []byte(res.String()), nil
+}
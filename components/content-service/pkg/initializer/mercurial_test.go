@@ -0,0 +1,71 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMercurialCloneArgs(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Revision    string
+		Expectation []string
+	}{
+		{Name: "default branch", Expectation: []string{"clone", "--noninteractive", "--", "https://hg.example.com/repo", "."}},
+		{Name: "revision", Revision: "stable", Expectation: []string{"clone", "--noninteractive", "--updaterev=stable", "--", "https://hg.example.com/repo", "."}},
+		{Name: "option as revision", Revision: "--config=hooks.pre-clone=true", Expectation: []string{"clone", "--noninteractive", "--updaterev=--config=hooks.pre-clone=true", "--", "https://hg.example.com/repo", "."}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ws := &MercurialInitializer{RemoteURI: "https://hg.example.com/repo", Revision: test.Revision}
+			if diff := cmp.Diff(test.Expectation, ws.cloneArgs()); diff != "" {
+				t.Errorf("unexpected clone args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHgAuthConfig(t *testing.T) {
+	tests := []struct {
+		Name        string
+		User        string
+		Password    string
+		Expectation string
+		Error       string
+	}{
+		{
+			Name:        "user and password",
+			User:        "foo",
+			Password:    "bar",
+			Expectation: "[auth]\ngitpod.prefix = *\ngitpod.schemes = http https\ngitpod.username = foo\ngitpod.password = bar\n",
+		},
+		{
+			Name:        "token only",
+			Password:    "token",
+			Expectation: "[auth]\ngitpod.prefix = *\ngitpod.schemes = http https\ngitpod.password = token\n",
+		},
+		{Name: "line break", User: "foo", Password: "bar\n[hooks]", Error: "credentials must not contain line breaks"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := hgAuthConfig(test.User, test.Password)
+			if test.Error != "" {
+				if err == nil || err.Error() != test.Error {
+					t.Fatalf("expected error %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, string(act)); diff != "" {
+				t.Errorf("unexpected hgrc (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -57,13 +57,15 @@ func (p *PrebuildInitializer) Run(ctx context.Context, mappings []archive.IDMapp
 	}
 	span.LogFields(spandata...)
 
+	// the Git fallback below reports its own progress phase, hence we must not overwrite ctx
+	prebuildCtx := withProgressPhase(ctx, ProgressPrebuild)
 	if p.Prebuild != nil {
 		var (
 			snapshot = p.Prebuild.Snapshot
 			location = p.Prebuild.Location
 			log      = log.WithField("location", p.Prebuild.Location)
 		)
-		_, err = p.Prebuild.Run(ctx, mappings)
+		_, err = p.Prebuild.run(prebuildCtx, mappings)
 		if err != nil {
 			log.WithError(err).Warnf("prebuilt init was unable to restore snapshot %s. Resorting the regular Git init", snapshot)
 
@@ -106,6 +108,10 @@ func (p *PrebuildInitializer) Run(ctx context.Context, mappings []archive.IDMapp
 		if err != nil {
 			return src, xerrors.Errorf("prebuild initializer: %w", err)
 		}
+		err = p.Git.realizeLFS(ctx)
+		if err != nil {
+			return src, xerrors.Errorf("prebuild initializer: %w", err)
+		}
 
 		// If any of these cleanup operations fail that's no reason to fail ws initialization.
 		// It just results in a slightly degraded state.
@@ -0,0 +1,112 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// ProgressPhase is the step of the content initialization a workspace is in
+type ProgressPhase string
+
+const (
+	// ProgressBackup means we're restoring the backup of a workspace
+	ProgressBackup ProgressPhase = "backup"
+	// ProgressSnapshot means we're restoring a snapshot
+	ProgressSnapshot ProgressPhase = "snapshot"
+	// ProgressPrebuild means we're restoring a prebuild and update it using Git
+	ProgressPrebuild ProgressPhase = "prebuild"
+	// ProgressClone means we're cloning a Git, Mercurial or Subversion repository
+	ProgressClone ProgressPhase = "clone"
+)
+
+// Progress describes how far the content initialization of a workspace has come
+type Progress struct {
+	Phase ProgressPhase `json:"phase"`
+	// Percent is the completion of the phase. It remains zero for phases which cannot tell.
+	Percent int `json:"percent"`
+	// BytesDone and BytesTotal are the bytes downloaded during the phase, and the size of that download
+	BytesDone  int64 `json:"bytesDone,omitempty"`
+	BytesTotal int64 `json:"bytesTotal,omitempty"`
+}
+
+// ProgressReporter receives the progress of a content initialization
+type ProgressReporter func(Progress)
+
+// progressBytesStep is the number of downloaded bytes after which we report progress of a download of unknown size
+const progressBytesStep = 4 << 20
+
+type progressReporterKey struct{}
+
+// WithProgressReporter returns a context which makes InitializeWorkspace and the initializers report their progress to r
+func WithProgressReporter(ctx context.Context, r ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, r)
+}
+
+type progressPhaseKey struct{}
+
+// phaseProgress reports the progress of a single phase. It reports only when the progress has grown noticeably,
+// so that reporting remains cheap even if downloads report every read.
+type phaseProgress struct {
+	report ProgressReporter
+
+	mu   sync.Mutex
+	last Progress
+}
+
+func (p *phaseProgress) percent(pct int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pct <= p.last.Percent {
+		return
+	}
+	p.last.Percent = pct
+	p.report(p.last)
+}
+
+func (p *phaseProgress) bytes(done, total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var pct int
+	if total > 0 {
+		pct = int(done * 100 / total)
+	}
+	if pct == p.last.Percent && done-p.last.BytesDone < progressBytesStep {
+		return
+	}
+	p.last.Percent = pct
+	p.last.BytesDone = done
+	p.last.BytesTotal = total
+	p.report(p.last)
+}
+
+// withProgressPhase starts a new phase of the content initialization. Downloads and Git clones using the returned
+// context report their progress as part of that phase. Returns ctx if no one is interested in the progress.
+func withProgressPhase(ctx context.Context, phase ProgressPhase) context.Context {
+	r, ok := ctx.Value(progressReporterKey{}).(ProgressReporter)
+	if !ok || r == nil {
+		return ctx
+	}
+
+	p := &phaseProgress{report: r, last: Progress{Phase: phase}}
+	r(p.last)
+
+	ctx = context.WithValue(ctx, progressPhaseKey{}, p)
+	return storage.WithDownloadProgress(ctx, p.bytes)
+}
+
+// cloneProgress returns the function a Git clone reports its progress to, or nil if there's no phase in progress
+func cloneProgress(ctx context.Context) func(percent int) {
+	p, ok := ctx.Value(progressPhaseKey{}).(*phaseProgress)
+	if !ok {
+		return nil
+	}
+	return p.percent
+}
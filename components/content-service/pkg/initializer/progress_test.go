@@ -0,0 +1,102 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// chunkedDownloader downloads Size bytes in chunks of Chunk bytes
+type chunkedDownloader struct {
+	Size        int64
+	Chunk       int
+	UnknownSize bool
+}
+
+func (d *chunkedDownloader) Download(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
+	total := d.Size
+	if d.UnknownSize {
+		total = -1
+	}
+	r := storage.DownloadProgressReader(ctx, bytes.NewReader(make([]byte, d.Size)), total)
+	buf := make([]byte, d.Chunk)
+	for {
+		_, err := r.Read(buf)
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return true, err
+		}
+	}
+}
+
+func (d *chunkedDownloader) DownloadSnapshot(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
+	return d.Download(ctx, destination, name, mappings)
+}
+
+func TestSnapshotProgress(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Downloader  *chunkedDownloader
+		Expectation []Progress
+	}{
+		{
+			Name:       "known size",
+			Downloader: &chunkedDownloader{Size: 100, Chunk: 40},
+			Expectation: []Progress{
+				{Phase: ProgressSnapshot},
+				{Phase: ProgressSnapshot, Percent: 40, BytesDone: 40, BytesTotal: 100},
+				{Phase: ProgressSnapshot, Percent: 80, BytesDone: 80, BytesTotal: 100},
+				{Phase: ProgressSnapshot, Percent: 100, BytesDone: 100, BytesTotal: 100},
+			},
+		},
+		{
+			Name:       "only whole percents",
+			Downloader: &chunkedDownloader{Size: 1000, Chunk: 5},
+			Expectation: func() []Progress {
+				res := []Progress{{Phase: ProgressSnapshot}}
+				for i := 1; i <= 100; i++ {
+					res = append(res, Progress{Phase: ProgressSnapshot, Percent: i, BytesDone: int64(i * 10), BytesTotal: 1000})
+				}
+				return res
+			}(),
+		},
+		{
+			Name:       "unknown size",
+			Downloader: &chunkedDownloader{Size: 2 * progressBytesStep, Chunk: progressBytesStep / 2, UnknownSize: true},
+			Expectation: []Progress{
+				{Phase: ProgressSnapshot},
+				{Phase: ProgressSnapshot, BytesDone: progressBytesStep},
+				{Phase: ProgressSnapshot, BytesDone: 2 * progressBytesStep},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act []Progress
+			ctx := WithProgressReporter(context.Background(), func(p Progress) { act = append(act, p) })
+
+			init := &SnapshotInitializer{Location: t.TempDir(), Snapshot: "snapshot.tar", Storage: test.Downloader}
+			_, err := init.Run(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected progress (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
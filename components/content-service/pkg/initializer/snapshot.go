@@ -25,6 +25,11 @@ type SnapshotInitializer struct {
 
 // Run downloads a snapshot from a remote storage
 func (s *SnapshotInitializer) Run(ctx context.Context, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
+	return s.run(withProgressPhase(ctx, ProgressSnapshot), mappings)
+}
+
+// run downloads the snapshot as part of whatever progress phase the context is in
+func (s *SnapshotInitializer) run(ctx context.Context, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
 	//nolint:ineffassign
 	span, ctx := opentracing.StartSpanFromContext(ctx, "SnapshotInitializer")
 	span.SetTag("snapshot", s.Snapshot)
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/git"
+)
+
+// SubversionInitializer is a local workspace with a Subversion connection
+type SubversionInitializer struct {
+	// Location is the path in which we check out the repository
+	Location string
+
+	// RemoteURI is the repository path we check out, e.g. https://svn.example.com/repo/trunk
+	RemoteURI string
+
+	// Revision is the revision we check out. Defaults to HEAD.
+	Revision string
+
+	// AuthMethod is the means of authentication used during checkout
+	AuthMethod git.AuthMethod
+
+	// AuthProvider provides the credentials if we use basic auth
+	AuthProvider git.AuthProvider
+
+	// If true, the Subversion initializer will chown(gitpod) after the checkout
+	Chown bool
+}
+
+// Run initializes the workspace using Subversion
+func (ws *SubversionInitializer) Run(ctx context.Context, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
+	isSvnWS := isWorkingCopy(ws.Location, ".svn")
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SubversionInitializer.Run")
+	span.SetTag("isSvnWS", isSvnWS)
+	defer tracing.FinishSpan(span, &err)
+
+	src = csapi.WorkspaceInitFromOther
+	if isSvnWS {
+		log.WithField("stage", "init").WithField("location", ws.Location).Info("Not running svn checkout. Workspace is already a Subversion workspace")
+		return
+	}
+
+	checkoutCtx := withProgressPhase(ctx, ProgressClone)
+	err = cloneWithRetry(ws.Location, func() error {
+		log.WithField("stage", "init").WithField("location", ws.Location).Debug("Running svn checkout on workspace")
+		return ws.checkout(checkoutCtx)
+	})
+	if err != nil {
+		return src, xerrors.Errorf("subversion initializer: %w", err)
+	}
+
+	if ws.Chown {
+		if err = chownGitpod(ws.Location); err != nil {
+			return
+		}
+	}
+
+	log.WithField("stage", "init").WithField("location", ws.Location).Debug("Subversion operations complete")
+	return
+}
+
+// checkout checks out the repository into the working copy location
+func (ws *SubversionInitializer) checkout(ctx context.Context) error {
+	// Subversion keeps its configuration in the home directory. A temporary one keeps the checkout
+	// from picking up or leaving behind configuration of whoever runs the initializer.
+	home, err := os.MkdirTemp("", "svn-home-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+
+	var (
+		user  string
+		stdin io.Reader
+	)
+	if ws.AuthMethod == git.BasicAuth {
+		if ws.AuthProvider == nil {
+			return xerrors.Errorf("basic-auth method requires an auth provider")
+		}
+		var pwd string
+		user, pwd, err = ws.AuthProvider()
+		if err != nil {
+			return err
+		}
+		// the password goes through stdin so that it doesn't show up in the process list
+		stdin = strings.NewReader(pwd)
+	}
+
+	_, err = runVCS(ctx, ws.Location, []string{"HOME=" + home}, stdin, "svn", ws.checkoutArgs(user, stdin != nil)...)
+	return err
+}
+
+// checkoutArgs returns the arguments of "svn checkout". If withPassword is true, svn reads the password from stdin.
+func (ws *SubversionInitializer) checkoutArgs(user string, withPassword bool) []string {
+	args := []string{"checkout", "--non-interactive", "--no-auth-cache"}
+	if user != "" {
+		args = append(args, "--username="+user)
+	}
+	if withPassword {
+		args = append(args, "--password-from-stdin")
+	}
+	if ws.Revision != "" {
+		args = append(args, "--revision="+ws.Revision)
+	}
+	// the remote URI is user input and must not be mistaken for an option
+	return append(args, "--", ws.RemoteURI, ".")
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSubversionCheckoutArgs(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Revision     string
+		User         string
+		WithPassword bool
+		Expectation  []string
+	}{
+		{Name: "anonymous", Expectation: []string{"checkout", "--non-interactive", "--no-auth-cache", "--", "https://svn.example.com/repo/trunk", "."}},
+		{
+			Name:         "basic auth",
+			User:         "foo",
+			WithPassword: true,
+			Expectation:  []string{"checkout", "--non-interactive", "--no-auth-cache", "--username=foo", "--password-from-stdin", "--", "https://svn.example.com/repo/trunk", "."},
+		},
+		{
+			Name:         "token only",
+			WithPassword: true,
+			Expectation:  []string{"checkout", "--non-interactive", "--no-auth-cache", "--password-from-stdin", "--", "https://svn.example.com/repo/trunk", "."},
+		},
+		{
+			Name:        "revision",
+			Revision:    "1234",
+			Expectation: []string{"checkout", "--non-interactive", "--no-auth-cache", "--revision=1234", "--", "https://svn.example.com/repo/trunk", "."},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ws := &SubversionInitializer{RemoteURI: "https://svn.example.com/repo/trunk", Revision: test.Revision}
+			if diff := cmp.Diff(test.Expectation, ws.checkoutArgs(test.User, test.WithPassword)); diff != "" {
+				t.Errorf("unexpected checkout args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/process"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+)
+
+// vcsOpFailedError is returned if a Mercurial or Subversion operation fails, e.g. returns with a non-zero exit code
+type vcsOpFailedError struct {
+	Command string
+	Args    []string
+	ExecErr error
+	Output  string
+}
+
+func (e vcsOpFailedError) Error() string {
+	return fmt.Sprintf("%s %s failed (%v): %v", e.Command, strings.Join(e.Args, " "), e.ExecErr, e.Output)
+}
+
+// runVCS runs a Mercurial or Subversion command in dir and returns its combined output. Like Git, the command
+// only gets to see the PATH and proxy configuration of our environment, plus env. Passwords must never be part
+// of args, as they'd end up in errors and logs - pass them through stdin or a config file instead.
+func runVCS(ctx context.Context, dir string, env []string, stdin io.Reader, command string, args ...string) (out []byte, err error) {
+	//nolint:staticcheck,ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("%s.%s", command, args[0]))
+	defer tracing.FinishSpan(span, &err)
+
+	env = append(env, fmt.Sprintf("PATH=%s", os.Getenv("PATH")))
+	if os.Getenv("http_proxy") != "" {
+		env = append(env, fmt.Sprintf("http_proxy=%s", os.Getenv("http_proxy")))
+	}
+	if os.Getenv("https_proxy") != "" {
+		env = append(env, fmt.Sprintf("https_proxy=%s", os.Getenv("https_proxy")))
+	}
+
+	var buf bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdin = stdin
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+
+	err = cmd.Run()
+	if err != nil && !process.IsNotChildProcess(err) {
+		return nil, vcsOpFailedError{
+			Command: command,
+			Args:    args,
+			ExecErr: err,
+			Output:  buf.String(),
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// cloneWithRetry runs clone until it succeeds or we run out of time. A failed attempt removes whatever it left
+// at location, so that the next attempt starts from scratch.
+func cloneWithRetry(location string, clone func() error) error {
+	op := func() error {
+		if err := os.MkdirAll(location, 0770); err != nil {
+			return err
+		}
+		return clone()
+	}
+	onFailure := func(e error, d time.Duration) {
+		if err := os.RemoveAll(location); err != nil {
+			log.
+				WithField("stage", "init").
+				WithField("location", location).
+				WithError(err).
+				Error("Cleaning workspace location failed.")
+		}
+		log.
+			WithField("stage", "init").
+			WithField("location", location).
+			WithField("sleepTime", d).
+			WithError(e).
+			Debugf("Cloning the workspace failed. Retrying in %s ...", d)
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Minute
+	return backoff.RetryNotify(op, b, onFailure)
+}
+
+// isWorkingCopy returns true if location is the root of a working copy with the given metadata directory, e.g. ".hg"
+func isWorkingCopy(location, metadataDir string) bool {
+	if stat, err := os.Stat(filepath.Join(location, metadataDir)); err == nil {
+		return stat.IsDir()
+	}
+
+	return false
+}
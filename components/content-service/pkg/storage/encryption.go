@@ -0,0 +1,420 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+)
+
+const (
+	// ObjectAnnotationEncryptionKeyID identifies the KMS key which encrypted the key of an encrypted object
+	ObjectAnnotationEncryptionKeyID = "gitpod-encryptionKeyId"
+
+	// ObjectAnnotationEncryptedKey is the encrypted key of an encrypted object, base64 encoded
+	ObjectAnnotationEncryptedKey = "gitpod-encryptedKey"
+)
+
+/* Encrypted objects start with a header, followed by chunks of AES-256-GCM encrypted content:
+ *
+ *   magic "GPENC" | version (1 byte) | key ID length (uint16) | key ID | encrypted key length (uint16) | encrypted key |
+ *   nonce prefix (7 bytes) | chunk size (uint32)
+ *
+ * Every chunk holds chunk size bytes of content, except for the last one which may hold fewer. The nonce of a chunk is the
+ * nonce prefix, followed by the index of the chunk (uint32) and a byte which is 1 for the last chunk and 0 otherwise.
+ * Reordered, dropped or truncated chunks thus fail to decrypt. Chunking lets us stream objects of any size in constant memory.
+ */
+const (
+	encryptionMagic        = "GPENC"
+	encryptionVersion      = 1
+	encryptionChunkSize    = 64 * 1024
+	encryptionNoncePrefix  = 7
+	encryptionMaxChunkSize = 16 * 1024 * 1024
+	encryptionKeySize      = 32
+	encryptionMaxFieldSize = 1<<16 - 1
+)
+
+// ErrNotEncrypted is returned when we expect an object to be encrypted, but it is not
+var ErrNotEncrypted = xerrors.Errorf("object is not encrypted")
+
+// EnvelopeEncryption encrypts objects with a key of their own, which a KMS encrypts in turn.
+// A nil *EnvelopeEncryption leaves objects as they are.
+type EnvelopeEncryption struct {
+	KMS KeyManagementService
+
+	// AllowUnencrypted makes us pass objects which are not encrypted through as they are, instead of rejecting them
+	AllowUnencrypted bool
+}
+
+// NewEnvelopeEncryption produces envelope encryption as configured. Returns nil if cfg is nil, i.e. encryption is disabled.
+func NewEnvelopeEncryption(cfg *config.EncryptionConfig) (*EnvelopeEncryption, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	kms, err := NewKeyManagementService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &EnvelopeEncryption{KMS: kms, AllowUnencrypted: cfg.AllowUnencrypted}, nil
+}
+
+// EncryptFile encrypts source into a temporary file next to it and adds the encrypted key to the annotations of options,
+// so that one can decrypt presigned downloads without downloading the object first. Call cleanup once the upload is done.
+// If e is nil, EncryptFile returns source.
+func (e *EnvelopeEncryption) EncryptFile(ctx context.Context, source string, options *UploadOptions) (encrypted string, cleanup func(), err error) {
+	if e == nil {
+		return source, func() {}, nil
+	}
+
+	key := make([]byte, encryptionKeySize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot produce object key: %w", err)
+	}
+	encryptedKey, err := e.KMS.Encrypt(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	src, err := os.Open(source)
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot open file for encryption: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(filepath.Dir(source), filepath.Base(source)+".enc-*")
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot create encrypted file: %w", err)
+	}
+	cleanup = func() { os.Remove(dst.Name()) }
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	w := bufio.NewWriter(dst)
+	err = encrypt(w, src, key, e.KMS.KeyID(), encryptedKey)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot encrypt %s: %w", source, err)
+	}
+
+	annotations := make(map[string]string, len(options.Annotations)+2)
+	for k, v := range options.Annotations {
+		annotations[k] = v
+	}
+	annotations[ObjectAnnotationEncryptionKeyID] = e.KMS.KeyID()
+	annotations[ObjectAnnotationEncryptedKey] = base64.StdEncoding.EncodeToString(encryptedKey)
+	options.Annotations = annotations
+
+	return dst.Name(), cleanup, nil
+}
+
+// DecryptReader decrypts the object r reads, having the KMS decrypt its key. Objects we did not encrypt, e.g. those uploaded
+// before encryption was enabled, pass through as they are if encryption is disabled or allows unencrypted objects.
+func (e *EnvelopeEncryption) DecryptReader(ctx context.Context, r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	hdr, encrypted, err := readEncryptionHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		if e != nil && !e.AllowUnencrypted {
+			return nil, ErrNotEncrypted
+		}
+		return br, nil
+	}
+	if e == nil {
+		return nil, xerrors.Errorf("object is encrypted with %s, but encryption is not configured", hdr.KeyID)
+	}
+	key, err := e.decryptKey(ctx, hdr.KeyID, hdr.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptingReader(br, hdr, key)
+}
+
+// DecryptKeys decrypts the keys of the encrypted objects among the downloads, so that one can pass them to DecryptingReader.
+// Unless e allows unencrypted objects, all downloads must be encrypted.
+func (e *EnvelopeEncryption) DecryptKeys(ctx context.Context, downloads map[string]DownloadInfo) error {
+	for name, info := range downloads {
+		if info.Meta.EncryptedKey == "" {
+			if e != nil && !e.AllowUnencrypted {
+				return xerrors.Errorf("%s: %w", name, ErrNotEncrypted)
+			}
+			continue
+		}
+		if e == nil {
+			return xerrors.Errorf("%s is encrypted with %s, but encryption is not configured", name, info.Meta.EncryptionKeyID)
+		}
+		encryptedKey, err := base64.StdEncoding.DecodeString(info.Meta.EncryptedKey)
+		if err != nil {
+			return xerrors.Errorf("%s has an invalid encrypted key: %w", name, err)
+		}
+		key, err := e.decryptKey(ctx, info.Meta.EncryptionKeyID, encryptedKey)
+		if err != nil {
+			return xerrors.Errorf("cannot decrypt the key of %s: %w", name, err)
+		}
+		info.DecryptionKey = key
+		downloads[name] = info
+	}
+	return nil
+}
+
+func (e *EnvelopeEncryption) decryptKey(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error) {
+	if keyID != e.KMS.KeyID() {
+		return nil, xerrors.Errorf("object is encrypted with %s, but we are configured to use %s", keyID, e.KMS.KeyID())
+	}
+	key, err := e.KMS.Decrypt(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != encryptionKeySize {
+		return nil, xerrors.Errorf("object key has %d bytes, expected %d", len(key), encryptionKeySize)
+	}
+	return key, nil
+}
+
+// DecryptingReader decrypts the object r reads using the key DecryptKeys produced. If key is nil, we expect the object
+// not to be encrypted and pass it through as it is. If there is a key, the object must be encrypted.
+func DecryptingReader(r io.Reader, key []byte) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	hdr, encrypted, err := readEncryptionHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		if key != nil {
+			return nil, ErrNotEncrypted
+		}
+		return br, nil
+	}
+	if key == nil {
+		return nil, xerrors.Errorf("object is encrypted with %s, but we have no key to decrypt it", hdr.KeyID)
+	}
+	return newDecryptingReader(br, hdr, key)
+}
+
+type encryptionHeader struct {
+	KeyID        string
+	EncryptedKey []byte
+	NoncePrefix  []byte
+	ChunkSize    int
+}
+
+func encrypt(dst io.Writer, src io.Reader, key []byte, keyID string, encryptedKey []byte) error {
+	if len(keyID) > encryptionMaxFieldSize || len(encryptedKey) > encryptionMaxFieldSize {
+		return xerrors.Errorf("key ID or encrypted key are too long")
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	noncePrefix := make([]byte, encryptionNoncePrefix)
+	_, err = rand.Read(noncePrefix)
+	if err != nil {
+		return err
+	}
+
+	var hdr bytes.Buffer
+	hdr.WriteString(encryptionMagic)
+	hdr.WriteByte(encryptionVersion)
+	_ = binary.Write(&hdr, binary.BigEndian, uint16(len(keyID)))
+	hdr.WriteString(keyID)
+	_ = binary.Write(&hdr, binary.BigEndian, uint16(len(encryptedKey)))
+	hdr.Write(encryptedKey)
+	hdr.Write(noncePrefix)
+	_ = binary.Write(&hdr, binary.BigEndian, uint32(encryptionChunkSize))
+	_, err = dst.Write(hdr.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var (
+		in  = bufio.NewReader(src)
+		buf = make([]byte, encryptionChunkSize)
+		out = make([]byte, 0, encryptionChunkSize+aead.Overhead())
+	)
+	for idx := uint32(0); ; idx++ {
+		n, err := io.ReadFull(in, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		last := n < len(buf)
+		if !last {
+			_, perr := in.Peek(1)
+			last = perr == io.EOF
+		}
+
+		out = aead.Seal(out[:0], chunkNonce(noncePrefix, idx, last), buf[:n], nil)
+		_, err = dst.Write(out)
+		if err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// readEncryptionHeader reads the header of an encrypted object. If the object does not start with our magic, it is not
+// encrypted and we leave r untouched.
+func readEncryptionHeader(r *bufio.Reader) (hdr *encryptionHeader, encrypted bool, err error) {
+	magic, err := r.Peek(len(encryptionMagic))
+	if err == io.EOF || (err == nil && string(magic) != encryptionMagic) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	_, _ = r.Discard(len(encryptionMagic))
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, true, xerrors.Errorf("cannot read encryption header: %w", err)
+	}
+	if version != encryptionVersion {
+		return nil, true, xerrors.Errorf("unsupported encryption version %d", version)
+	}
+
+	readField := func() ([]byte, error) {
+		var l uint16
+		err := binary.Read(r, binary.BigEndian, &l)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]byte, l)
+		_, err = io.ReadFull(r, res)
+		return res, err
+	}
+	keyID, err := readField()
+	if err != nil {
+		return nil, true, xerrors.Errorf("cannot read encryption header: %w", err)
+	}
+	encryptedKey, err := readField()
+	if err != nil {
+		return nil, true, xerrors.Errorf("cannot read encryption header: %w", err)
+	}
+	noncePrefix := make([]byte, encryptionNoncePrefix)
+	_, err = io.ReadFull(r, noncePrefix)
+	if err != nil {
+		return nil, true, xerrors.Errorf("cannot read encryption header: %w", err)
+	}
+	var chunkSize uint32
+	err = binary.Read(r, binary.BigEndian, &chunkSize)
+	if err != nil {
+		return nil, true, xerrors.Errorf("cannot read encryption header: %w", err)
+	}
+	if chunkSize == 0 || chunkSize > encryptionMaxChunkSize {
+		return nil, true, xerrors.Errorf("invalid chunk size %d", chunkSize)
+	}
+
+	return &encryptionHeader{
+		KeyID:        string(keyID),
+		EncryptedKey: encryptedKey,
+		NoncePrefix:  noncePrefix,
+		ChunkSize:    int(chunkSize),
+	}, true, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, idx uint32, last bool) []byte {
+	nonce := make([]byte, encryptionNoncePrefix+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[encryptionNoncePrefix:], idx)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+func newDecryptingReader(r *bufio.Reader, hdr *encryptionHeader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		r:    r,
+		hdr:  hdr,
+		aead: aead,
+		in:   make([]byte, hdr.ChunkSize+aead.Overhead()),
+	}, nil
+}
+
+type decryptingReader struct {
+	r    *bufio.Reader
+	hdr  *encryptionHeader
+	aead cipher.AEAD
+
+	in   []byte
+	out  []byte
+	idx  uint32
+	done bool
+}
+
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		err := d.nextChunk()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+func (d *decryptingReader) nextChunk() error {
+	n, err := io.ReadFull(d.r, d.in)
+	if err == io.EOF {
+		return xerrors.Errorf("encrypted object is truncated")
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	last := n < len(d.in)
+	if !last {
+		_, perr := d.r.Peek(1)
+		last = perr == io.EOF
+	}
+
+	d.out, err = d.aead.Open(d.in[:0], chunkNonce(d.hdr.NoncePrefix, d.idx, last), d.in[:n], nil)
+	if err != nil {
+		return xerrors.Errorf("cannot decrypt chunk %d - the object is corrupted or truncated", d.idx)
+	}
+	d.idx++
+	d.done = last
+	return nil
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+)
+
+// xorKMS "encrypts" keys by xoring them, which is enough to tell encrypted and plain keys apart
+type xorKMS struct{}
+
+func (xorKMS) KeyID() string { return "test:xor" }
+
+func (xorKMS) Encrypt(ctx context.Context, key []byte) ([]byte, error) { return xor(key), nil }
+
+func (xorKMS) Decrypt(ctx context.Context, encrypted []byte) ([]byte, error) {
+	return xor(encrypted), nil
+}
+
+func xor(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[i] = b[i] ^ 0x42
+	}
+	return res
+}
+
+func TestEnvelopeEncryption(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Content []byte
+		Tamper  func(encrypted []byte) []byte
+		Error   string
+	}{
+		{Name: "empty", Content: []byte{}},
+		{Name: "short", Content: []byte("hello world")},
+		{Name: "exact chunks", Content: bytes.Repeat([]byte("a"), 2*encryptionChunkSize)},
+		{Name: "several chunks", Content: bytes.Repeat([]byte("0123456789"), encryptionChunkSize/4)},
+		{
+			Name:    "flipped bit",
+			Content: bytes.Repeat([]byte("a"), encryptionChunkSize+10),
+			Tamper: func(encrypted []byte) []byte {
+				encrypted[len(encrypted)-20] ^= 1
+				return encrypted
+			},
+			Error: "cannot decrypt chunk 1 - the object is corrupted or truncated",
+		},
+		{
+			Name:    "truncated at chunk boundary",
+			Content: bytes.Repeat([]byte("a"), encryptionChunkSize+10),
+			Tamper: func(encrypted []byte) []byte {
+				return encrypted[:len(encrypted)-10-16]
+			},
+			Error: "cannot decrypt chunk 0 - the object is corrupted or truncated",
+		},
+		{
+			Name:    "last chunk dropped",
+			Content: bytes.Repeat([]byte("a"), 2*encryptionChunkSize),
+			Tamper: func(encrypted []byte) []byte {
+				return encrypted[:len(encrypted)-encryptionChunkSize-16]
+			},
+			Error: "cannot decrypt chunk 0 - the object is corrupted or truncated",
+		},
+	}
+
+	enc := &EnvelopeEncryption{KMS: xorKMS{}}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			source := filepath.Join(t.TempDir(), "backup.tar")
+			err := os.WriteFile(source, test.Content, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			options := &UploadOptions{Annotations: map[string]string{"foo": "bar"}}
+			fn, cleanup, err := enc.EncryptFile(context.Background(), source, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cleanup()
+			if options.Annotations["foo"] != "bar" || options.Annotations[ObjectAnnotationEncryptionKeyID] != "test:xor" || options.Annotations[ObjectAnnotationEncryptedKey] == "" {
+				t.Errorf("unexpected annotations: %v", options.Annotations)
+			}
+
+			encrypted, err := os.ReadFile(fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(test.Content) > 0 && bytes.Contains(encrypted, test.Content) {
+				t.Fatal("encrypted file contains the content")
+			}
+			if test.Tamper != nil {
+				encrypted = test.Tamper(encrypted)
+			}
+
+			r, err := enc.DecryptReader(context.Background(), bytes.NewReader(encrypted))
+			if err != nil {
+				t.Fatal(err)
+			}
+			act, err := io.ReadAll(r)
+			if test.Error != "" {
+				if err == nil || err.Error() != test.Error {
+					t.Fatalf("expected error %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(test.Content, act) {
+				t.Errorf("decrypted content differs: expected %d bytes, got %d", len(test.Content), len(act))
+			}
+
+			// presigned downloads decrypt the key upfront
+			downloads := map[string]DownloadInfo{"backup": {Meta: ObjectMeta{
+				EncryptionKeyID: options.Annotations[ObjectAnnotationEncryptionKeyID],
+				EncryptedKey:    options.Annotations[ObjectAnnotationEncryptedKey],
+			}}}
+			err = enc.DecryptKeys(context.Background(), downloads)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err = DecryptingReader(bytes.NewReader(encrypted), downloads["backup"].DecryptionKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act, err = io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(test.Content, act) {
+				t.Errorf("decrypted content of presigned download differs: expected %d bytes, got %d", len(test.Content), len(act))
+			}
+		})
+	}
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Encryption *EnvelopeEncryption
+		Rejected   bool
+	}{
+		{Name: "encryption disabled"},
+		{Name: "encryption enabled", Encryption: &EnvelopeEncryption{KMS: xorKMS{}}, Rejected: true},
+		{Name: "encryption enabled allowing unencrypted objects", Encryption: &EnvelopeEncryption{KMS: xorKMS{}, AllowUnencrypted: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			for _, content := range []string{"", "GPE", "plain old backup"} {
+				r, err := test.Encryption.DecryptReader(context.Background(), strings.NewReader(content))
+				if test.Rejected {
+					if !errors.Is(err, ErrNotEncrypted) {
+						t.Fatalf("expected %q to be rejected, got %v", content, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				act, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(content, string(act)); diff != "" {
+					t.Errorf("unexpected content (-want +got):\n%s", diff)
+				}
+			}
+
+			err := test.Encryption.DecryptKeys(context.Background(), map[string]DownloadInfo{"backup": {}})
+			if test.Rejected && !errors.Is(err, ErrNotEncrypted) {
+				t.Errorf("expected download without key to be rejected, got %v", err)
+			} else if !test.Rejected && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecryptingReaderRejectsPlaintext(t *testing.T) {
+	_, err := DecryptingReader(strings.NewReader("plain old backup"), make([]byte, encryptionKeySize))
+	if !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("expected plaintext to be rejected when there is a key, got %v", err)
+	}
+}
+
+func TestVaultKMS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/backups":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]}})
+		case "/v1/transit/decrypt/backups":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:")}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(tokenFile, []byte("s.token\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms, err := NewKeyManagementService(&config.EncryptionConfig{
+		KMS:   config.VaultKMS,
+		Vault: &config.VaultKMSConfig{Address: srv.URL, KeyName: "backups", TokenFile: tokenFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kms.KeyID() != "vault:transit/backups" {
+		t.Errorf("unexpected key ID %q", kms.KeyID())
+	}
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	encrypted, err := kms.Encrypt(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := kms.Decrypt(context.Background(), encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(key, act); diff != "" {
+		t.Errorf("unexpected key (-want +got):\n%s", diff)
+	}
+}
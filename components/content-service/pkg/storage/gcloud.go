@@ -82,7 +82,11 @@ type DirectGCPStorage struct {
 	GCPConfig     config.GCPConfig
 	Stage         config.Stage
 
-	client *gcpstorage.Client
+	// Encryption encrypts objects before we upload them. Nil if encryption is disabled.
+	Encryption *EnvelopeEncryption
+
+	client   *gcpstorage.Client
+	transfer *transfer
 
 	// ObjectAccess just exists so that we can swap out the stream access during testing
 	ObjectAccess func(ctx context.Context, btk, obj string) (io.ReadCloser, bool, error)
@@ -175,10 +179,27 @@ func (rs *DirectGCPStorage) defaultObjectAccess(ctx context.Context, bkt, obj st
 	}
 
 	hdl := rs.client.Bucket(bkt).Object(obj)
+	if rs.transfer != nil {
+		attrs, err := hdl.Attrs(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		// we must not mix the parts of two backups, should the object be overwritten while we download it
+		hdl = hdl.Generation(attrs.Generation)
+		if attrs.Size > rs.transfer.PartSize {
+			return rs.transfer.newPartReader(ctx, attrs.Size, func(ctx context.Context, offset, n int64) (io.ReadCloser, error) {
+				return hdl.NewRangeReader(ctx, offset, n)
+			}), false, nil
+		}
+	}
+
 	rc, err := hdl.NewReader(ctx)
 	if err != nil {
 		return nil, false, err
 	}
+	if rs.transfer != nil {
+		return throttleReadCloser(ctx, rc, rs.transfer.download), false, nil
+	}
 
 	return rc, false, nil
 }
@@ -196,7 +217,11 @@ func (rs *DirectGCPStorage) download(ctx context.Context, destination string, bk
 	}
 	defer rc.Close()
 
-	err = extractTarbal(ctx, destination, rc, mappings)
+	content, err := rs.Encryption.DecryptReader(ctx, rc)
+	if err != nil {
+		return true, err
+	}
+	err = extractTarbal(ctx, destination, content, mappings)
 	if err != nil {
 		return true, err
 	}
@@ -314,6 +339,29 @@ func (rs *DirectGCPStorage) UploadInstance(ctx context.Context, source string, n
 	return rs.Upload(ctx, source, InstanceObjectName(rs.InstanceID, name), opts...)
 }
 
+// VerifyObject downloads an object and returns the indices of its chunks which do not match the integrity manifest
+func (rs *DirectGCPStorage) VerifyObject(ctx context.Context, name string, mf *IntegrityManifest) (corruptedChunks []int, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GCloudBucketRemotegcpStorage.VerifyObject")
+	span.SetTag("name", name)
+	defer tracing.FinishSpan(span, &err)
+
+	rc, _, err := rs.ObjectAccess(ctx, rs.bucketName(), rs.objectName(name))
+	if errors.Is(err, gcpstorage.ErrObjectNotExist) || errors.Is(err, gcpstorage.ErrBucketNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := rs.Encryption.DecryptReader(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	return mf.Verify(content)
+}
+
 // Upload takes all files from a local location and uploads it to the remote storage
 func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name string, opts ...UploadOption) (bucket, object string, err error) {
 	//nolint:ineffassign
@@ -333,12 +381,33 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 	}
 
 	// check if we have not yet exceeded the max number of backups
-	if name != DefaultBackup {
+	if name != DefaultBackup && !strings.HasPrefix(name, IncrementalBackupPrefix) {
 		if err = rs.ensureBackupSlotAvailable(); err != nil {
 			return
 		}
 	}
 
+	var (
+		cp, stale *uploadCheckpoint
+		cleanup   = func() {}
+	)
+	if rs.transfer != nil {
+		var plain os.FileInfo
+		plain, err = os.Stat(source)
+		if err != nil {
+			err = xerrors.Errorf("cannot stat file for uploading: %w", err)
+			return
+		}
+		cp, stale = rs.transfer.checkpoint(source, plain, rs.bucketName(), rs.objectName(name), options)
+		source, err = cp.Encrypt(ctx, rs.Encryption, options)
+	} else {
+		source, cleanup, err = rs.Encryption.EncryptFile(ctx, source, options)
+	}
+	if err != nil {
+		return
+	}
+	defer cleanup()
+
 	sfn, err := os.Open(source)
 	if err != nil {
 		err = xerrors.Errorf("cannot open file for uploading: %w", err)
@@ -363,11 +432,24 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 	 * for more details.
 	 */
 	var chunks []string
-	if chunks, err = rs.uploadChunks(opentracing.ContextWithSpan(ctx, uploadSpan), sfn, totalSize, rs.GCPConfig.ParallelUpload); err != nil {
+	if rs.transfer != nil {
+		chunks, err = rs.uploadParts(opentracing.ContextWithSpan(ctx, uploadSpan), sfn, stat, rs.objectName(name), cp, stale)
+	} else {
+		chunks, err = rs.uploadChunks(opentracing.ContextWithSpan(ctx, uploadSpan), sfn, totalSize, rs.GCPConfig.ParallelUpload)
+	}
+	if err != nil {
 		tracing.FinishSpan(uploadSpan, &err)
 		return
 	}
 	defer func() {
+		if rs.transfer != nil {
+			if err != nil && err != ErrChecksumMismatch {
+				// we keep the parts so that retrying the upload resumes with them
+				return
+			}
+			rs.transfer.complete(rs.bucketName(), rs.objectName(name))
+		}
+
 		err := rs.deleteChunks(opentracing.ContextWithSpan(ctx, uploadSpan), chunks)
 		if err != nil {
 			log.WithError(err).WithField("name", name).Warn("cannot clean up upload chunks")
@@ -379,10 +461,6 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 	// compose the uploaded chunks
 	bucket = rs.bucketName()
 	bkt := rs.client.Bucket(bucket)
-	src := make([]*gcpstorage.ObjectHandle, len(chunks))
-	for i := 0; i < len(chunks); i++ {
-		src[i] = bkt.Object(chunks[i])
-	}
 	object = rs.objectName(name)
 	obj := bkt.Object(object)
 
@@ -401,7 +479,8 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 
 	// now that the upload is complete and the backup trail has been created, compose the chunks to
 	// create the actual backup
-	_, err = obj.ComposerFrom(src...).Run(ctx)
+	intermediates, err := rs.compose(ctx, bkt, obj, chunks)
+	chunks = append(chunks, intermediates...)
 	if err != nil {
 		tracing.FinishSpan(uploadSpan, &err)
 		return
@@ -441,7 +520,11 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 	} else if remotehash == localhash {
 		log.WithField("remotehash", remotehash).WithField("localhash", localhash).Debug("checksums match")
 	} else {
-		log.WithField("remotehash", remotehash).WithField("localhash", localhash).Debug("checksums do not match")
+		// The upload got corrupted along the way. Failing here gives the caller a chance to retry
+		// instead of finding out at the next workspace start.
+		log.WithField("remotehash", remotehash).WithField("localhash", localhash).Warn("checksums do not match")
+		err = ErrChecksumMismatch
+		return
 	}
 
 	err = nil
@@ -560,6 +643,113 @@ func (rs *DirectGCPStorage) uploadChunks(ctx context.Context, f io.ReaderAt, tot
 	return chunks, nil
 }
 
+// uploadParts uploads f in parts, several at a time. Parts which made it during a previous attempt of the upload cp records
+// are not uploaded again. The parts of the stale upload are deleted.
+func (rs *DirectGCPStorage) uploadParts(ctx context.Context, f *os.File, stat os.FileInfo, object string, cp, stale *uploadCheckpoint) (parts []string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "uploadParts")
+	defer tracing.FinishSpan(span, &err)
+
+	bkt := rs.client.Bucket(rs.bucketName())
+	if stale != nil {
+		var stalePartNames []string
+		for _, idx := range stale.Indices() {
+			stalePartNames = append(stalePartNames, gcpPartName(stale.ID, idx))
+		}
+		if err := rs.deleteChunks(ctx, stalePartNames); err != nil {
+			log.WithError(err).WithField("object", object).Debug("cannot clean up the parts of a previous upload")
+		}
+	}
+	if cp.ID == "" {
+		cp.ID = randomString(20)
+	}
+
+	ps := rs.transfer.parts(stat.Size())
+	span.SetTag("parts", len(ps))
+	parts = make([]string, len(ps))
+	for i, p := range ps {
+		parts[i] = gcpPartName(cp.ID, p.Index)
+	}
+	err = rs.transfer.run(ctx, ps, func(ctx context.Context, p part) error {
+		h := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+		_, err := io.Copy(h, io.NewSectionReader(f, p.Offset, p.Size))
+		if err != nil {
+			return err
+		}
+		crc := h.Sum32()
+
+		obj := bkt.Object(parts[p.Index])
+		if tag, ok := cp.Part(p.Index); ok && tag == fmt.Sprint(crc) {
+			attrs, err := obj.Attrs(ctx)
+			if err == nil && attrs.CRC32C == crc {
+				log.WithField("part", p.Index).WithField("object", object).Debug("part made it during a previous attempt")
+				return nil
+			}
+			cp.Forget(p.Index)
+		}
+
+		// cancelling the context is the only way to abort a write
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		w := obj.NewWriter(ctx)
+		w.CRC32C = crc
+		w.SendCRC32C = true
+		_, err = io.Copy(w, throttle(ctx, io.NewSectionReader(f, p.Offset, p.Size), rs.transfer.upload))
+		if err != nil {
+			cancel()
+			_ = w.Close()
+			return err
+		}
+		err = w.Close()
+		if err != nil {
+			return err
+		}
+
+		cp.Done(p.Index, fmt.Sprint(crc))
+		return nil
+	})
+	return parts, err
+}
+
+func gcpPartName(uploadID string, idx int) string {
+	return fmt.Sprintf("uploads/%s/%d-part", uploadID, idx)
+}
+
+// compose composes the chunks into obj. GCS composes at most 32 objects at once, hence we compose more chunks
+// in several steps. Returns the intermediate objects it creates along the way.
+func (rs *DirectGCPStorage) compose(ctx context.Context, bkt *gcpstorage.BucketHandle, obj *gcpstorage.ObjectHandle, chunks []string) (intermediates []string, err error) {
+	const maxComposeSources = 32
+
+	handles := func(names []string) []*gcpstorage.ObjectHandle {
+		res := make([]*gcpstorage.ObjectHandle, len(names))
+		for i, n := range names {
+			res[i] = bkt.Object(n)
+		}
+		return res
+	}
+
+	for len(chunks) > maxComposeSources {
+		var next []string
+		for i := 0; i < len(chunks); i += maxComposeSources {
+			end := i + maxComposeSources
+			if end > len(chunks) {
+				end = len(chunks)
+			}
+			name := chunks[i] + "-composed"
+			_, err = bkt.Object(name).ComposerFrom(handles(chunks[i:end])...).Run(ctx)
+			if err != nil {
+				return
+			}
+			intermediates = append(intermediates, name)
+			next = append(next, name)
+		}
+		chunks = next
+	}
+
+	_, err = obj.ComposerFrom(handles(chunks)...).Run(ctx)
+	return
+}
+
 func (rs *DirectGCPStorage) uploadChunk(ctx context.Context, name string, r io.Reader, size int64, wg *sync.WaitGroup, errchan chan error) {
 	//nolint:ineffassign
 	span, ctx := opentracing.StartSpanFromContext(ctx, "uploadChunk")
@@ -860,6 +1050,8 @@ func (p *PresignedGCPStorage) downloadInfo(ctx context.Context, client *gcpstora
 		OCIMediaType:       obj.Metadata[ObjectAnnotationOCIContentType],
 		Digest:             obj.Metadata[ObjectAnnotationDigest],
 		UncompressedDigest: obj.Metadata[ObjectAnnotationUncompressedDigest],
+		EncryptionKeyID:    obj.Metadata[ObjectAnnotationEncryptionKeyID],
+		EncryptedKey:       obj.Metadata[ObjectAnnotationEncryptedKey],
 	}
 	url, err := gcpstorage.SignedURL(obj.Bucket, obj.Name, &gcpstorage.SignedURLOptions{
 		Method:         "GET",
@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+// DefaultIntegrityChunkSize is the size of the chunks an integrity manifest checksums individually
+const DefaultIntegrityChunkSize = 64 * 1024 * 1024
+
+// IntegrityManifest records the checksums of an archive as it was before the upload,
+// so that we can tell if and where the remote copy got corrupted.
+type IntegrityManifest struct {
+	Size      int64 `json:"size"`
+	ChunkSize int64 `json:"chunkSize"`
+	// Chunks are the hex encoded SHA-256 sums of the consecutive chunks of the archive
+	Chunks []string `json:"chunks"`
+}
+
+// NewIntegrityManifest checksums the content of r in chunks of chunkSize bytes
+func NewIntegrityManifest(r io.Reader, chunkSize int64) (*IntegrityManifest, error) {
+	if chunkSize <= 0 {
+		return nil, xerrors.Errorf("chunk size must be positive")
+	}
+
+	res := &IntegrityManifest{ChunkSize: chunkSize}
+	for {
+		h := sha256.New()
+		n, err := io.CopyN(h, r, chunkSize)
+		if n > 0 {
+			res.Size += n
+			res.Chunks = append(res.Chunks, hex.EncodeToString(h.Sum(nil)))
+		}
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Verify checksums the content of r and returns the indices of the chunks which do not match the manifest.
+// Content r has in excess of the manifest's size counts towards the last chunk.
+func (mf *IntegrityManifest) Verify(r io.Reader) (corrupted []int, err error) {
+	act, err := NewIntegrityManifest(r, mf.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range mf.Chunks {
+		if i >= len(act.Chunks) || act.Chunks[i] != c {
+			corrupted = append(corrupted, i)
+		}
+	}
+	if len(act.Chunks) > len(mf.Chunks) {
+		var last int
+		if len(mf.Chunks) > 0 {
+			last = len(mf.Chunks) - 1
+		}
+		if len(corrupted) == 0 || corrupted[len(corrupted)-1] != last {
+			corrupted = append(corrupted, last)
+		}
+	}
+	return corrupted, nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIntegrityManifestVerify(t *testing.T) {
+	const original = "aaaabbbbcc"
+
+	tests := []struct {
+		Name     string
+		Remote   string
+		Expected []int
+	}{
+		{Name: "intact", Remote: original},
+		{Name: "corrupted chunk", Remote: "aaaabxbbcc", Expected: []int{1}},
+		{Name: "corrupted chunks", Remote: "xaaabbbbcx", Expected: []int{0, 2}},
+		{Name: "truncated", Remote: "aaaabb", Expected: []int{1, 2}},
+		{Name: "empty", Remote: "", Expected: []int{0, 1, 2}},
+		{Name: "excess content", Remote: "aaaabbbbccdddd", Expected: []int{2}},
+	}
+
+	mf, err := NewIntegrityManifest(strings.NewReader(original), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mf.Size != int64(len(original)) || len(mf.Chunks) != 3 {
+		t.Fatalf("unexpected manifest: size %d, %d chunks", mf.Size, len(mf.Chunks))
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := mf.Verify(strings.NewReader(test.Remote))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected corrupted chunks (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+)
+
+// KeyManagementService encrypts and decrypts the keys we encrypt objects with
+type KeyManagementService interface {
+	// KeyID identifies the key which encrypts the object keys. We store it alongside the objects.
+	KeyID() string
+
+	// Encrypt encrypts an object key
+	Encrypt(ctx context.Context, key []byte) (encrypted []byte, err error)
+
+	// Decrypt decrypts an object key
+	Decrypt(ctx context.Context, encrypted []byte) (key []byte, err error)
+}
+
+// NewKeyManagementService produces the KMS the config asks for
+func NewKeyManagementService(cfg *config.EncryptionConfig) (KeyManagementService, error) {
+	switch cfg.KMS {
+	case config.GCPKMS:
+		if cfg.GCP == nil || cfg.GCP.KeyName == "" {
+			return nil, xerrors.Errorf("kms %s requires gcp.keyName", cfg.KMS)
+		}
+		return newGCPKMS(cfg.GCP)
+	case config.AWSKMS:
+		if cfg.AWS == nil || cfg.AWS.KeyID == "" || cfg.AWS.Region == "" {
+			return nil, xerrors.Errorf("kms %s requires aws.keyId and aws.region", cfg.KMS)
+		}
+		return newAWSKMS(cfg.AWS)
+	case config.VaultKMS:
+		if cfg.Vault == nil || cfg.Vault.Address == "" || cfg.Vault.KeyName == "" || cfg.Vault.TokenFile == "" {
+			return nil, xerrors.Errorf("kms %s requires vault.address, vault.keyName and vault.tokenFile", cfg.KMS)
+		}
+		return newVaultKMS(cfg.Vault)
+	default:
+		return nil, xerrors.Errorf("unknown kms %q", cfg.KMS)
+	}
+}
+
+// gcpKMS uses a key of Google Cloud KMS
+type gcpKMS struct {
+	keyName string
+	keys    *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+}
+
+func newGCPKMS(cfg *config.GCPKMSConfig) (*gcpKMS, error) {
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+	svc, err := cloudkms.NewService(context.Background(), opts...)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create GCP KMS client: %w", err)
+	}
+	return &gcpKMS{
+		keyName: cfg.KeyName,
+		keys:    svc.Projects.Locations.KeyRings.CryptoKeys,
+	}, nil
+}
+
+func (k *gcpKMS) KeyID() string {
+	return "gcp:" + k.keyName
+}
+
+func (k *gcpKMS) Encrypt(ctx context.Context, key []byte) ([]byte, error) {
+	resp, err := k.keys.Encrypt(k.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(key),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot encrypt using %s: %w", k.keyName, err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+func (k *gcpKMS) Decrypt(ctx context.Context, encrypted []byte) ([]byte, error) {
+	resp, err := k.keys.Decrypt(k.keyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(encrypted),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decrypt using %s: %w", k.keyName, err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// awsKMS uses a key of AWS KMS. We speak its JSON API directly rather than pulling in the AWS SDK for two calls.
+type awsKMS struct {
+	keyID           string
+	region          string
+	endpoint        string
+	accessKeyID     string
+	secretAccessKey string
+	client          *http.Client
+	now             func() time.Time
+}
+
+func newAWSKMS(cfg *config.AWSKMSConfig) (*awsKMS, error) {
+	accessKeyID, err := readSecretFile(cfg.AccessKeyIDFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read aws.accessKeyFile: %w", err)
+	}
+	secretAccessKey, err := readSecretFile(cfg.SecretAccessKeyFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read aws.secretKeyFile: %w", err)
+	}
+	return &awsKMS{
+		keyID:           cfg.KeyID,
+		region:          cfg.Region,
+		endpoint:        fmt.Sprintf("https://kms.%s.amazonaws.com/", cfg.Region),
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		client:          &http.Client{Timeout: 30 * time.Second},
+		now:             time.Now,
+	}, nil
+}
+
+func (k *awsKMS) KeyID() string {
+	return "aws:" + k.keyID
+}
+
+func (k *awsKMS) Encrypt(ctx context.Context, key []byte) ([]byte, error) {
+	var resp struct {
+		CiphertextBlob []byte
+	}
+	err := k.call(ctx, "Encrypt", map[string]interface{}{"KeyId": k.keyID, "Plaintext": key}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.CiphertextBlob, nil
+}
+
+func (k *awsKMS) Decrypt(ctx context.Context, encrypted []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext []byte
+	}
+	err := k.call(ctx, "Decrypt", map[string]interface{}{"KeyId": k.keyID, "CiphertextBlob": encrypted}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// call invokes an action of the KMS API, signing the request with signature version 4
+func (k *awsKMS) call(ctx context.Context, action string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, k.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpReq.Header.Set("X-Amz-Target", "TrentService."+action)
+	k.sign(httpReq, body)
+
+	httpResp, err := k.client.Do(httpReq)
+	if err != nil {
+		return xerrors.Errorf("cannot call AWS KMS %s: %w", action, err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, 4096))
+		return xerrors.Errorf("AWS KMS %s failed with status %d: %s", action, httpResp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(httpResp.Body).Decode(resp)
+}
+
+// sign adds an AWS signature version 4 to the request, see https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func (k *awsKMS) sign(req *http.Request, body []byte) {
+	const service = "kms"
+	var (
+		now       = k.now().UTC()
+		amzDate   = now.Format("20060102T150405Z")
+		date      = now.Format("20060102")
+		scope     = strings.Join([]string{date, k.region, service, "aws4_request"}, "/")
+		bodyHash  = sha256.Sum256(body)
+		headerSet = []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headerSet {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = req.URL.Host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", h, strings.TrimSpace(v))
+	}
+	signedHeaders := strings.Join(headerSet, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+k.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, k.region)
+	signingKey = hmacSHA256(signingKey, service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", k.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// vaultKMS uses a key of the transit secrets engine of HashiCorp Vault
+type vaultKMS struct {
+	address   string
+	mountPath string
+	keyName   string
+	tokenFile string
+	client    *http.Client
+}
+
+func newVaultKMS(cfg *config.VaultKMSConfig) (*vaultKMS, error) {
+	// we read the token on every request so that a rotated token takes effect without a restart
+	if _, err := readSecretFile(cfg.TokenFile); err != nil {
+		return nil, xerrors.Errorf("cannot read vault.tokenFile: %w", err)
+	}
+	mountPath := strings.Trim(cfg.MountPath, "/")
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+	return &vaultKMS{
+		address:   strings.TrimSuffix(cfg.Address, "/"),
+		mountPath: mountPath,
+		keyName:   cfg.KeyName,
+		tokenFile: cfg.TokenFile,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (k *vaultKMS) KeyID() string {
+	return fmt.Sprintf("vault:%s/%s", k.mountPath, k.keyName)
+}
+
+func (k *vaultKMS) Encrypt(ctx context.Context, key []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	err := k.call(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	// Vault ciphertexts are strings of the form vault:v1:... which carry the key version
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+func (k *vaultKMS) Decrypt(ctx context.Context, encrypted []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	err := k.call(ctx, "decrypt", map[string]string{"ciphertext": string(encrypted)}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (k *vaultKMS) call(ctx context.Context, op string, req, resp interface{}) error {
+	token, err := readSecretFile(k.tokenFile)
+	if err != nil {
+		return xerrors.Errorf("cannot read vault token: %w", err)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v1/%s/%s/%s", k.address, k.mountPath, op, k.keyName)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Vault-Token", token)
+
+	httpResp, err := k.client.Do(httpReq)
+	if err != nil {
+		return xerrors.Errorf("cannot call vault %s: %w", op, err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, 4096))
+		return xerrors.Errorf("vault %s failed with status %d: %s", op, httpResp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(httpResp.Body).Decode(resp)
+}
+
+func readSecretFile(fn string) (string, error) {
+	if fn == "" {
+		return "", xerrors.Errorf("no file configured")
+	}
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(fc)), nil
+}
@@ -6,6 +6,8 @@ package storage
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -105,7 +107,11 @@ type DirectMinIOStorage struct {
 	InstanceID    string
 	MinIOConfig   config.MinIOConfig
 
-	client *minio.Client
+	// Encryption encrypts objects before we upload them. Nil if encryption is disabled.
+	Encryption *EnvelopeEncryption
+
+	client   *minio.Client
+	transfer *transfer
 
 	// ObjectAccess just exists so that we can swap out the stream access during testing
 	ObjectAccess func(ctx context.Context, btk, obj string) (io.ReadCloser, error)
@@ -152,6 +158,10 @@ func (rs *DirectMinIOStorage) defaultObjectAccess(ctx context.Context, bkt, obj
 		return nil, xerrors.Errorf("no MinIO client available - did you call Init()?")
 	}
 
+	if rs.transfer != nil {
+		return rs.transferObject(ctx, bkt, obj)
+	}
+
 	object, err := rs.client.GetObject(ctx, bkt, obj, minio.GetObjectOptions{})
 	if err != nil {
 		return nil, translateMinioError(err)
@@ -164,6 +174,44 @@ func (rs *DirectMinIOStorage) defaultObjectAccess(ctx context.Context, bkt, obj
 	return object, nil
 }
 
+// transferObject reads an object in parts, several at a time
+func (rs *DirectMinIOStorage) transferObject(ctx context.Context, bkt, obj string) (io.ReadCloser, error) {
+	stat, err := rs.client.StatObject(ctx, bkt, obj, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, translateMinioError(err)
+	}
+
+	core := minio.Core{Client: rs.client}
+	fetch := func(ctx context.Context, offset, n int64) (io.ReadCloser, error) {
+		var opts minio.GetObjectOptions
+		// we must not mix the parts of two backups, should the object be overwritten while we download it
+		err := opts.SetMatchETag(stat.ETag)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			err = opts.SetRange(offset, offset+n-1)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rc, _, _, err := core.GetObject(ctx, bkt, obj, opts)
+		if err != nil {
+			return nil, translateMinioError(err)
+		}
+		return rc, nil
+	}
+	if stat.Size > rs.transfer.PartSize {
+		return rs.transfer.newPartReader(ctx, stat.Size, fetch), nil
+	}
+
+	rc, err := fetch(ctx, 0, stat.Size)
+	if err != nil {
+		return nil, err
+	}
+	return throttleReadCloser(ctx, rc, rs.transfer.download), nil
+}
+
 // EnsureExists makes sure that the remote storage location exists and can be up- or downloaded from
 func (rs *DirectMinIOStorage) EnsureExists(ctx context.Context) (err error) {
 	return minioEnsureExists(ctx, rs.client, rs.bucketName(), rs.MinIOConfig)
@@ -209,7 +257,11 @@ func (rs *DirectMinIOStorage) download(ctx context.Context, destination string,
 	}
 	defer rc.Close()
 
-	err = extractTarbal(ctx, destination, rc, mappings)
+	content, err := rs.Encryption.DecryptReader(ctx, rc)
+	if err != nil {
+		return true, err
+	}
+	err = extractTarbal(ctx, destination, content, mappings)
 	if err != nil {
 		return true, err
 	}
@@ -273,6 +325,26 @@ func (rs *DirectMinIOStorage) UploadInstance(ctx context.Context, source string,
 	return rs.Upload(ctx, source, InstanceObjectName(rs.InstanceID, name), opts...)
 }
 
+// VerifyObject downloads an object and returns the indices of its chunks which do not match the integrity manifest
+func (rs *DirectMinIOStorage) VerifyObject(ctx context.Context, name string, mf *IntegrityManifest) (corruptedChunks []int, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DirectVerifyObject")
+	span.SetTag("name", name)
+	defer tracing.FinishSpan(span, &err)
+
+	rc, err := rs.ObjectAccess(ctx, rs.bucketName(), rs.objectName(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := rs.Encryption.DecryptReader(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	return mf.Verify(content)
+}
+
 // Upload takes all files from a local location and uploads it to the remote storage
 func (rs *DirectMinIOStorage) Upload(ctx context.Context, source string, name string, opts ...UploadOption) (bucket, obj string, err error) {
 	//nolint:ineffassign
@@ -298,6 +370,17 @@ func (rs *DirectMinIOStorage) Upload(ctx context.Context, source string, name st
 	span.LogKV("endpoint", rs.MinIOConfig.Endpoint)
 	span.LogKV("region", rs.MinIOConfig.Region)
 	span.LogKV("key", rs.MinIOConfig.AccessKeyID)
+	if rs.transfer != nil {
+		err = rs.uploadParts(ctx, source, bucket, obj, options)
+		return
+	}
+
+	source, cleanup, err := rs.Encryption.EncryptFile(ctx, source, options)
+	if err != nil {
+		return
+	}
+	defer cleanup()
+
 	_, err = rs.client.FPutObject(ctx, bucket, obj, source, minio.PutObjectOptions{
 		NumThreads:   rs.MinIOConfig.ParallelUpload,
 		UserMetadata: options.Annotations,
@@ -310,6 +393,108 @@ func (rs *DirectMinIOStorage) Upload(ctx context.Context, source string, name st
 	return
 }
 
+// uploadParts uploads source as multipart upload, several parts at a time, encrypting it if so configured. Parts which made it
+// during a previous attempt to upload source are not uploaded again.
+func (rs *DirectMinIOStorage) uploadParts(ctx context.Context, source, bucket, object string, options *UploadOptions) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "uploadParts")
+	defer tracing.FinishSpan(span, &err)
+
+	plain, err := os.Stat(source)
+	if err != nil {
+		return xerrors.Errorf("cannot stat file for uploading: %w", err)
+	}
+	core := minio.Core{Client: rs.client}
+	cp, stale := rs.transfer.checkpoint(source, plain, bucket, object, options)
+	if stale != nil && stale.ID != "" {
+		if err := core.AbortMultipartUpload(ctx, bucket, object, stale.ID); err != nil {
+			log.WithError(err).WithField("object", object).Debug("cannot abort previous multipart upload")
+		}
+	}
+
+	source, err = cp.Encrypt(ctx, rs.Encryption, options)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return xerrors.Errorf("cannot open file for uploading: %w", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// the parts which made it during a previous attempt, by their part number
+	remote := make(map[int]string)
+	if cp.ID != "" {
+		var marker int
+		for {
+			res, err := core.ListObjectParts(ctx, bucket, object, cp.ID, marker, 1000)
+			if err != nil {
+				// the upload might have expired or been aborted - we start over
+				log.WithError(err).WithField("object", object).Debug("cannot resume multipart upload")
+				cp.Reset()
+				break
+			}
+			for _, p := range res.ObjectParts {
+				remote[p.PartNumber] = p.ETag
+			}
+			if !res.IsTruncated {
+				break
+			}
+			marker = res.NextPartNumberMarker
+		}
+	}
+	if cp.ID == "" {
+		cp.ID, err = core.NewMultipartUpload(ctx, bucket, object, minio.PutObjectOptions{
+			UserMetadata: options.Annotations,
+			ContentType:  options.ContentType,
+		})
+		if err != nil {
+			return translateMinioError(err)
+		}
+	}
+
+	ps := rs.transfer.parts(stat.Size())
+	span.SetTag("parts", len(ps))
+	err = rs.transfer.run(ctx, ps, func(ctx context.Context, p part) error {
+		if tag, ok := cp.Part(p.Index); ok && remote[p.Index+1] == tag {
+			log.WithField("part", p.Index).WithField("object", object).Debug("part made it during a previous attempt")
+			return nil
+		}
+
+		h := md5.New()
+		_, err := io.Copy(h, io.NewSectionReader(f, p.Offset, p.Size))
+		if err != nil {
+			return err
+		}
+		res, err := core.PutObjectPart(ctx, bucket, object, cp.ID, p.Index+1, throttle(ctx, io.NewSectionReader(f, p.Offset, p.Size), rs.transfer.upload), p.Size, base64.StdEncoding.EncodeToString(h.Sum(nil)), "", nil)
+		if err != nil {
+			return translateMinioError(err)
+		}
+		cp.Done(p.Index, res.ETag)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	complete := make([]minio.CompletePart, len(ps))
+	for i, p := range ps {
+		tag, _ := cp.Part(p.Index)
+		complete[i] = minio.CompletePart{PartNumber: p.Index + 1, ETag: tag}
+	}
+	_, err = core.CompleteMultipartUpload(ctx, bucket, object, cp.ID, complete)
+	if err != nil {
+		return translateMinioError(err)
+	}
+	rs.transfer.complete(bucket, object)
+
+	return nil
+}
+
 func minioBucketName(ownerID string) string {
 	return fmt.Sprintf("gitpod-user-%s", ownerID)
 }
@@ -408,6 +593,8 @@ func (s *presignedMinIOStorage) SignDownload(ctx context.Context, bucket, object
 			OCIMediaType:       stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationOCIContentType)),
 			Digest:             stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationDigest)),
 			UncompressedDigest: stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationUncompressedDigest)),
+			EncryptionKeyID:    stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationEncryptionKeyID)),
+			EncryptedKey:       stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationEncryptedKey)),
 		},
 		Size: stat.Size,
 		URL:  url.String(),
@@ -393,3 +393,18 @@ func (mr *MockDirectAccessMockRecorder) UploadInstance(arg0, arg1, arg2 interfac
 	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadInstance", reflect.TypeOf((*MockDirectAccess)(nil).UploadInstance), varargs...)
 }
+
+// VerifyObject mocks base method.
+func (m *MockDirectAccess) VerifyObject(arg0 context.Context, arg1 string, arg2 *storage.IntegrityManifest) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyObject", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyObject indicates an expected call of VerifyObject.
+func (mr *MockDirectAccessMockRecorder) VerifyObject(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyObject", reflect.TypeOf((*MockDirectAccess)(nil).VerifyObject), arg0, arg1, arg2)
+}
@@ -42,7 +42,7 @@ func (d *NamedURLDownloader) Download(ctx context.Context, destination string, n
 	}
 	defer resp.Body.Close()
 
-	err = extractTarbal(ctx, destination, resp.Body, mappings)
+	err = extractTarbal(ctx, destination, DownloadProgressReader(ctx, resp.Body, resp.ContentLength), mappings)
 	if err != nil {
 		return true, err
 	}
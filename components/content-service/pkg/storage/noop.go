@@ -50,6 +50,11 @@ func (rs *DirectNoopStorage) UploadInstance(ctx context.Context, source string,
 	return "", "", nil
 }
 
+// VerifyObject does nothing and reports no corruption
+func (rs *DirectNoopStorage) VerifyObject(ctx context.Context, name string, mf *IntegrityManifest) ([]int, error) {
+	return nil, nil
+}
+
 // Upload does nothing
 func (rs *DirectNoopStorage) Upload(ctx context.Context, source string, name string, opts ...UploadOption) (string, string, error) {
 	return "", "", nil
@@ -0,0 +1,50 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// DownloadProgressFunc is called while an object downloads with the number of bytes downloaded so far,
+// and the size of the object. The size is zero if it's unknown.
+type DownloadProgressFunc func(done, total int64)
+
+type downloadProgressKey struct{}
+
+// WithDownloadProgress returns a context which makes downloads report their progress to f
+func WithDownloadProgress(ctx context.Context, f DownloadProgressFunc) context.Context {
+	return context.WithValue(ctx, downloadProgressKey{}, f)
+}
+
+// DownloadProgressReader wraps the content of a download such that reading it reports the download progress
+// to the DownloadProgressFunc of the context. Returns r if the context has none.
+func DownloadProgressReader(ctx context.Context, r io.Reader, total int64) io.Reader {
+	f, ok := ctx.Value(downloadProgressKey{}).(DownloadProgressFunc)
+	if !ok || f == nil {
+		return r
+	}
+	if total < 0 {
+		total = 0
+	}
+	return &progressReader{r: r, total: total, f: f}
+}
+
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	f     DownloadProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (n int, err error) {
+	n, err = p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.f(p.done, p.total)
+	}
+	return
+}
@@ -28,11 +28,25 @@ const (
 
 	// FmtFullWorkspaceBackup is the format for names of full workspace backups
 	FmtFullWorkspaceBackup = "wsfull-%d.tar"
+
+	// FmtIncrementalBackup is the format for names of incremental backups. They apply on top of the default
+	// backup in the order of their sequence number, starting at 1.
+	FmtIncrementalBackup = IncrementalBackupPrefix + "%03d.tar"
+
+	// IncrementalBackupPrefix is the name prefix all incremental backups share
+	IncrementalBackupPrefix = "incremental-"
+
+	// IncrementalBackupDeletions is the file in an incremental backup which lists the paths, one per line,
+	// that were deleted since the previous backup. It's removed once the deletions are applied.
+	IncrementalBackupDeletions = ".gitpod-incremental-deletions"
 )
 
 var (
 	// ErrNotFound is returned when an object is not found
 	ErrNotFound = xerrors.Errorf("not found")
+
+	// ErrChecksumMismatch is returned when an uploaded object does not match its local source
+	ErrChecksumMismatch = xerrors.Errorf("checksum mismatch")
 )
 
 // BucketNamer provides names for storage buckets
@@ -101,6 +115,10 @@ type ObjectMeta struct {
 	OCIMediaType       string
 	Digest             string
 	UncompressedDigest string
+
+	// EncryptionKeyID and EncryptedKey are set if the object is encrypted, see EnvelopeEncryption
+	EncryptionKeyID string
+	EncryptedKey    string
 }
 
 // DownloadInfo describes an object for download
@@ -108,6 +126,10 @@ type DownloadInfo struct {
 	Meta ObjectMeta
 	URL  string
 	Size int64
+
+	// DecryptionKey decrypts the object if it is encrypted, see EnvelopeEncryption.DecryptKeys.
+	// It must never be persisted, hence we do not serialise it.
+	DecryptionKey []byte `json:"-"`
 }
 
 // UploadInfo describes an object for upload
@@ -161,6 +183,10 @@ type DirectAccess interface {
 
 	// UploadInstance takes all files from a local location and uploads it to the remote storage
 	UploadInstance(ctx context.Context, source string, name string, options ...UploadOption) (bucket, obj string, err error)
+
+	// VerifyObject downloads an object and returns the indices of its chunks which do not match the integrity manifest.
+	// If the object does not exist, ErrNotFound is returned.
+	VerifyObject(ctx context.Context, name string, mf *IntegrityManifest) (corruptedChunks []int, err error)
 }
 
 // UploadOptions configure remote storage upload
@@ -244,11 +270,28 @@ func NewDirectAccess(c *config.StorageConfig) (DirectAccess, error) {
 		return nil, xerrors.Errorf("missing storage stage")
 	}
 
+	enc, err := NewEnvelopeEncryption(c.Encryption)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot configure encryption: %w", err)
+	}
+
 	switch c.Kind {
 	case config.GCloudStorage:
-		return newDirectGCPAccess(c.GCloudConfig, stage)
+		rs, err := newDirectGCPAccess(c.GCloudConfig, stage)
+		if err != nil {
+			return nil, err
+		}
+		rs.Encryption = enc
+		rs.transfer = newTransfer(c.Transfer)
+		return rs, nil
 	case config.MinIOStorage:
-		return newDirectMinIOAccess(c.MinIOConfig)
+		rs, err := newDirectMinIOAccess(c.MinIOConfig)
+		if err != nil {
+			return nil, err
+		}
+		rs.Encryption = enc
+		rs.transfer = newTransfer(c.Transfer)
+		return rs, nil
 	default:
 		return &DirectNoopStorage{}, nil
 	}
@@ -0,0 +1,496 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+)
+
+const (
+	defaultTransferPartSize     = 64 * 1024 * 1024
+	defaultTransferConcurrency  = 4
+	defaultTransferPartAttempts = 3
+
+	// minTransferPartSize is the smallest part S3 accepts, except for the last one
+	minTransferPartSize = 5 * 1024 * 1024
+	// maxTransferParts is the largest number of parts S3 accepts
+	maxTransferParts = 10000
+	// minThrottleBurst keeps us from reading in tiny pieces when the bandwidth cap is low
+	minThrottleBurst = 32 * 1024
+)
+
+// transfer moves objects in parts, several at a time and within the configured bandwidth
+type transfer struct {
+	PartSize    int64
+	Concurrency int
+	Attempts    int
+
+	upload   *rate.Limiter
+	download *rate.Limiter
+	backoff  time.Duration
+
+	checkpoints sync.Map
+}
+
+// newTransfer produces a transfer as configured. Returns nil if cfg is nil, i.e. we transfer objects as a whole.
+func newTransfer(cfg *config.TransferConfig) *transfer {
+	if cfg == nil {
+		return nil
+	}
+
+	res := &transfer{
+		PartSize:    defaultTransferPartSize,
+		Concurrency: defaultTransferConcurrency,
+		Attempts:    defaultTransferPartAttempts,
+		backoff:     time.Second,
+	}
+	if cfg.PartSize > 0 {
+		res.PartSize = cfg.PartSize
+	}
+	if res.PartSize < minTransferPartSize {
+		res.PartSize = minTransferPartSize
+	}
+	if cfg.Concurrency > 0 {
+		res.Concurrency = cfg.Concurrency
+	}
+	if cfg.PartAttempts > 0 {
+		res.Attempts = cfg.PartAttempts
+	}
+	res.upload = newBandwidthLimiter(cfg.MaxUploadBandwidth)
+	res.download = newBandwidthLimiter(cfg.MaxDownloadBandwidth)
+	return res
+}
+
+func newBandwidthLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	burst := int(bytesPerSecond)
+	if burst < minThrottleBurst {
+		burst = minThrottleBurst
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// part is a section of an object
+type part struct {
+	Index  int
+	Offset int64
+	Size   int64
+}
+
+// parts splits an object into parts. Objects with more than the maximum number of parts get larger parts.
+// Empty objects have a single empty part.
+func (t *transfer) parts(size int64) []part {
+	partSize := t.PartSize
+	if size > partSize*maxTransferParts {
+		partSize = (size + maxTransferParts - 1) / maxTransferParts
+	}
+
+	res := []part{{Size: size}}
+	if size > partSize {
+		res = make([]part, 0, (size+partSize-1)/partSize)
+		for off := int64(0); off < size; off += partSize {
+			n := partSize
+			if off+n > size {
+				n = size - off
+			}
+			res = append(res, part{Index: len(res), Offset: off, Size: n})
+		}
+	}
+	return res
+}
+
+// run calls fn for all parts, several at a time, and retries failed parts. Returns the first error of a part
+// which failed on all attempts, after all other parts have finished.
+func (t *transfer) run(ctx context.Context, parts []part, fn func(ctx context.Context, p part) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		rerr error
+		work = make(chan part)
+	)
+	for i := 0; i < t.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range work {
+				err := t.retry(ctx, p, fn)
+				if err == nil {
+					continue
+				}
+
+				mu.Lock()
+				if rerr == nil {
+					rerr = err
+					// there's no point in transferring the other parts
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, p := range parts {
+		select {
+		case work <- p:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if rerr != nil {
+		return rerr
+	}
+	return ctx.Err()
+}
+
+func (t *transfer) retry(ctx context.Context, p part, fn func(ctx context.Context, p part) error) (err error) {
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx, p)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if attempt >= t.Attempts {
+			return xerrors.Errorf("part %d failed after %d attempts: %w", p.Index, attempt, err)
+		}
+
+		backoff := time.Duration(attempt) * t.backoff
+		log.WithError(err).WithField("part", p.Index).WithField("backoff", backoff.String()).Debug("retrying part after backoff")
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// throttle caps the rate at which we read from r
+func throttle(ctx context.Context, r io.Reader, limiter *rate.Limiter) io.Reader {
+	if limiter == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, limiter: limiter}
+}
+
+// throttleReadCloser caps the rate at which we read from rc
+func throttleReadCloser(ctx context.Context, rc io.ReadCloser, limiter *rate.Limiter) io.ReadCloser {
+	if limiter == nil {
+		return rc
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{throttle(ctx, rc, limiter), rc}
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > t.limiter.Burst() {
+		p = p[:t.limiter.Burst()]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.WaitN(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// fetchFunc reads n bytes of an object, starting at offset
+type fetchFunc func(ctx context.Context, offset, n int64) (io.ReadCloser, error)
+
+// newPartReader reads an object of the given size in parts, several at a time, and returns its content in order.
+// A part which fails mid-way resumes where it failed. It holds at most Concurrency parts in memory.
+func (t *transfer) newPartReader(ctx context.Context, size int64, fetch fetchFunc) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	parts := t.parts(size)
+	res := &partReader{
+		cancel:  cancel,
+		results: make([]chan partResult, len(parts)),
+		slots:   make(chan struct{}, t.Concurrency),
+	}
+	for i := range res.results {
+		res.results[i] = make(chan partResult, 1)
+	}
+
+	go func() {
+		for i, p := range parts {
+			select {
+			case res.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func(i int, p part) {
+				var buf []byte
+				err := t.retry(ctx, p, func(ctx context.Context, p part) error {
+					var err error
+					buf, err = t.fetchPart(ctx, p, buf, fetch)
+					return err
+				})
+				res.results[i] <- partResult{Content: buf, Err: err}
+			}(i, p)
+		}
+	}()
+
+	return res
+}
+
+// fetchPart reads the rest of a part, given the content we read so far
+func (t *transfer) fetchPart(ctx context.Context, p part, buf []byte, fetch fetchFunc) ([]byte, error) {
+	if buf == nil {
+		buf = make([]byte, 0, p.Size)
+	}
+	rest := p.Size - int64(len(buf))
+	if rest == 0 {
+		return buf, nil
+	}
+
+	rc, err := fetch(ctx, p.Offset+int64(len(buf)), rest)
+	if err != nil {
+		return buf, err
+	}
+	defer rc.Close()
+
+	n, err := io.ReadFull(throttle(ctx, rc, t.download), buf[len(buf):p.Size])
+	buf = buf[:len(buf)+n]
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = xerrors.Errorf("part %d ended after %d of %d bytes", p.Index, len(buf), p.Size)
+	}
+	return buf, err
+}
+
+type partResult struct {
+	Content []byte
+	Err     error
+}
+
+type partReader struct {
+	cancel  context.CancelFunc
+	results []chan partResult
+	slots   chan struct{}
+
+	current []byte
+	next    int
+	err     error
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.next >= len(r.results) {
+			return 0, io.EOF
+		}
+
+		res := <-r.results[r.next]
+		<-r.slots
+		r.next++
+		r.current, r.err = res.Content, res.Err
+		if r.err != nil {
+			return 0, r.err
+		}
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
+
+func (r *partReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// uploadCheckpoint records the parts of an upload which made it, so that retrying the upload resumes with the missing ones.
+//
+// Checkpoints live in memory only and do not survive a restart of the process. That's deliberate: the sources we upload,
+// e.g. the backup archives of ws-daemon, are temporary files which do not survive a restart either, hence there would be
+// nothing to resume.
+type uploadCheckpoint struct {
+	// ID identifies the upload, e.g. the multipart upload ID of S3
+	ID string
+
+	source   string
+	identity string
+
+	mu    sync.Mutex
+	parts map[int]string
+
+	// encrypted is the encrypted copy of source all attempts upload, and annotations the upload annotations
+	// which carry its key. Encrypting anew would produce different content under a different key.
+	encrypted   string
+	annotations map[string]string
+	cleanup     func()
+}
+
+// Part returns the tag of a part which made it
+func (c *uploadCheckpoint) Part(idx int) (tag string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tag, ok = c.parts[idx]
+	return
+}
+
+// Done records that a part made it
+func (c *uploadCheckpoint) Done(idx int, tag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.parts[idx] = tag
+}
+
+// Forget drops a part, e.g. because it did not make it after all
+func (c *uploadCheckpoint) Forget(idx int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.parts, idx)
+}
+
+// Indices returns the indices of all parts which made it
+func (c *uploadCheckpoint) Indices() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := make([]int, 0, len(c.parts))
+	for i := range c.parts {
+		res = append(res, i)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// Reset forgets the upload and all its parts, e.g. because the upload expired
+func (c *uploadCheckpoint) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ID = ""
+	c.parts = make(map[int]string)
+}
+
+// Encrypt returns the file to upload in place of source, and sets the annotations of options to carry its key.
+// All attempts of an upload upload the same file, which e encrypts on the first attempt. If e is nil, Encrypt returns source.
+func (c *uploadCheckpoint) Encrypt(ctx context.Context, e *EnvelopeEncryption, options *UploadOptions) (string, error) {
+	if e == nil {
+		return c.source, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.encrypted != "" {
+		if _, err := os.Stat(c.encrypted); err == nil {
+			options.Annotations = c.annotations
+			return c.encrypted, nil
+		}
+
+		// someone removed the encrypted file - the parts we uploaded so far are of no use anymore
+		c.ID = ""
+		c.parts = make(map[int]string)
+		c.encrypted, c.annotations, c.cleanup = "", nil, nil
+	}
+
+	encrypted, cleanup, err := e.EncryptFile(ctx, c.source, options)
+	if err != nil {
+		return "", err
+	}
+	c.encrypted, c.annotations, c.cleanup = encrypted, options.Annotations, cleanup
+	return encrypted, nil
+}
+
+// release removes the encrypted copy of the source
+func (c *uploadCheckpoint) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cleanup != nil {
+		c.cleanup()
+	}
+	c.encrypted, c.annotations, c.cleanup = "", nil, nil
+}
+
+// checkpoint returns the checkpoint of uploading source to bucket/object, where stat is that of the plaintext source.
+// If the source or the upload options changed since the last attempt, the previous checkpoint is returned as stale
+// so that one can clean up after it.
+func (t *transfer) checkpoint(source string, stat os.FileInfo, bucket, object string, options *UploadOptions) (cp, stale *uploadCheckpoint) {
+	key := bucket + "/" + object
+	identity := uploadIdentity(source, stat, t.PartSize, options)
+	if prev, ok := t.checkpoints.Load(key); ok {
+		prev := prev.(*uploadCheckpoint)
+		if prev.identity == identity {
+			return prev, nil
+		}
+		prev.release()
+		stale = prev
+	}
+
+	// Uploads which never completed keep their checkpoint. Once their source is gone they cannot resume anymore,
+	// and we remove their encrypted copy so that it does not fill up the disk.
+	t.checkpoints.Range(func(k, v interface{}) bool {
+		if other := v.(*uploadCheckpoint); k != key {
+			if _, err := os.Stat(other.source); os.IsNotExist(err) {
+				other.release()
+				t.checkpoints.Delete(k)
+			}
+		}
+		return true
+	})
+
+	cp = &uploadCheckpoint{
+		source:   source,
+		identity: identity,
+		parts:    make(map[int]string),
+	}
+	t.checkpoints.Store(key, cp)
+	return cp, stale
+}
+
+// complete drops the checkpoint of an upload once it's done
+func (t *transfer) complete(bucket, object string) {
+	if cp, ok := t.checkpoints.LoadAndDelete(bucket + "/" + object); ok {
+		cp.(*uploadCheckpoint).release()
+	}
+}
+
+// uploadIdentity tells apart attempts to upload the same content with the same options, which may resume one another.
+// A resumed upload keeps the metadata it started with, hence the options are part of its identity. source, stat and
+// options are those before encryption, which produces a different file with a different key on every attempt.
+func uploadIdentity(source string, stat os.FileInfo, partSize int64, options *UploadOptions) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n%d\n%d\n%s\n", source, stat.Size(), stat.ModTime().UnixNano(), partSize, options.ContentType)
+	keys := make([]string, 0, len(options.Annotations))
+	for k := range options.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, options.Annotations[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
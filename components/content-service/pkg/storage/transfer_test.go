@@ -0,0 +1,261 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+)
+
+const mib = 1024 * 1024
+
+func TestTransferParts(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Size        int64
+		Expectation []part
+	}{
+		{Name: "empty", Size: 0, Expectation: []part{{}}},
+		{Name: "smaller than a part", Size: 3 * mib, Expectation: []part{{Size: 3 * mib}}},
+		{Name: "exactly a part", Size: 5 * mib, Expectation: []part{{Size: 5 * mib}}},
+		{
+			Name: "several parts",
+			Size: 12 * mib,
+			Expectation: []part{
+				{Index: 0, Offset: 0, Size: 5 * mib},
+				{Index: 1, Offset: 5 * mib, Size: 5 * mib},
+				{Index: 2, Offset: 10 * mib, Size: 2 * mib},
+			},
+		},
+	}
+
+	tr := newTransfer(&config.TransferConfig{PartSize: 5 * mib})
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := tr.parts(test.Size)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected parts (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("more than the maximum number of parts", func(t *testing.T) {
+		act := tr.parts(maxTransferParts*5*mib + 1)
+		if len(act) > maxTransferParts {
+			t.Errorf("expected at most %d parts, got %d", maxTransferParts, len(act))
+		}
+	})
+}
+
+func TestTransferRun(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Failures map[int]int
+		Error    string
+		Attempts map[int]int
+	}{
+		{Name: "no failures", Attempts: map[int]int{0: 1, 1: 1, 2: 1, 3: 1}},
+		{Name: "retried part", Failures: map[int]int{2: 2}, Attempts: map[int]int{0: 1, 1: 1, 2: 3, 3: 1}},
+		{Name: "failed part", Failures: map[int]int{1: 3}, Error: "part 1 failed after 3 attempts: flaky"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tr := newTransfer(&config.TransferConfig{Concurrency: 2})
+			tr.backoff = 0
+
+			var (
+				mu       sync.Mutex
+				attempts = make(map[int]int)
+			)
+			err := tr.run(context.Background(), []part{{Index: 0}, {Index: 1}, {Index: 2}, {Index: 3}}, func(ctx context.Context, p part) error {
+				mu.Lock()
+				defer mu.Unlock()
+				attempts[p.Index]++
+				if attempts[p.Index] <= test.Failures[p.Index] {
+					return xerrors.Errorf("flaky")
+				}
+				return nil
+			})
+			if test.Error != "" {
+				if err == nil || err.Error() != test.Error {
+					t.Fatalf("expected error %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Attempts, attempts); diff != "" {
+				t.Errorf("unexpected attempts (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// flakyReader fails after reading limit bytes
+type flakyReader struct {
+	r     io.Reader
+	limit int
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	if f.limit <= 0 {
+		return 0, xerrors.Errorf("connection reset")
+	}
+	if len(p) > f.limit {
+		p = p[:f.limit]
+	}
+	n, err := f.r.Read(p)
+	f.limit -= n
+	return n, err
+}
+
+func TestPartReader(t *testing.T) {
+	content := make([]byte, 12*mib)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+
+	tr := newTransfer(&config.TransferConfig{PartSize: 5 * mib, Concurrency: 2})
+	tr.backoff = 0
+
+	var (
+		mu      sync.Mutex
+		fetches []int64
+	)
+	rc := tr.newPartReader(context.Background(), int64(len(content)), func(ctx context.Context, offset, n int64) (io.ReadCloser, error) {
+		mu.Lock()
+		fetches = append(fetches, offset)
+		first := len(fetches) == 1
+		mu.Unlock()
+
+		var r io.Reader = bytes.NewReader(content[offset : offset+n])
+		if first {
+			// the first fetch breaks off after a MiB and must resume where it broke off
+			r = &flakyReader{r: r, limit: mib}
+		}
+		return io.NopCloser(r), nil
+	})
+	defer rc.Close()
+
+	act, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, act) {
+		t.Fatal("content differs")
+	}
+	if len(fetches) != 4 {
+		t.Errorf("expected 4 fetches, got %v", fetches)
+	}
+	for _, off := range fetches {
+		if off%(5*mib) != 0 && off%(5*mib) != mib {
+			t.Errorf("unexpected fetch offset %d", off)
+		}
+	}
+}
+
+func TestUploadCheckpoint(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "backup.tar")
+	err := os.WriteFile(source, []byte("content"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := newTransfer(&config.TransferConfig{})
+	opts := &UploadOptions{Annotations: map[string]string{"foo": "bar"}}
+	cp, stale := tr.checkpoint(source, stat, "bucket", "object", opts)
+	if stale != nil {
+		t.Fatal("expected no stale checkpoint on first attempt")
+	}
+	cp.ID = "upload"
+	cp.Done(1, "tag")
+
+	resumed, stale := tr.checkpoint(source, stat, "bucket", "object", &UploadOptions{Annotations: map[string]string{"foo": "bar"}})
+	if stale != nil || resumed != cp {
+		t.Fatal("expected the same upload to resume")
+	}
+	if tag, ok := resumed.Part(1); !ok || tag != "tag" {
+		t.Errorf("expected part 1 to have made it, got %q", tag)
+	}
+
+	changed, stale := tr.checkpoint(source, stat, "bucket", "object", &UploadOptions{Annotations: map[string]string{"foo": "baz"}})
+	if stale != cp {
+		t.Fatal("expected changed annotations to make the previous checkpoint stale")
+	}
+	if changed.ID != "" || len(changed.Indices()) != 0 {
+		t.Errorf("expected a fresh checkpoint, got ID %q and parts %v", changed.ID, changed.Indices())
+	}
+
+	tr.complete("bucket", "object")
+	_, stale = tr.checkpoint(source, stat, "bucket", "object", opts)
+	if stale != nil {
+		t.Error("expected no stale checkpoint after completion")
+	}
+}
+
+func TestUploadCheckpointEncryption(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "backup.tar")
+	err := os.WriteFile(source, []byte("content"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		ctx = context.Background()
+		tr  = newTransfer(&config.TransferConfig{})
+		enc = &EnvelopeEncryption{KMS: xorKMS{}}
+	)
+	attempt := func() (cp *uploadCheckpoint, encrypted string, annotations map[string]string) {
+		opts := &UploadOptions{Annotations: map[string]string{"foo": "bar"}}
+		cp, _ = tr.checkpoint(source, stat, "bucket", "object", opts)
+		encrypted, err := cp.Encrypt(ctx, enc, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cp, encrypted, opts.Annotations
+	}
+
+	cp, encrypted, annotations := attempt()
+	if encrypted == source {
+		t.Fatal("expected an encrypted copy of the source")
+	}
+	cp.ID = "upload"
+	cp.Done(0, "tag")
+
+	resumed, reencrypted, reannotations := attempt()
+	if resumed != cp || reencrypted != encrypted {
+		t.Fatalf("expected the upload to resume with %s, got %s", encrypted, reencrypted)
+	}
+	if diff := cmp.Diff(annotations, reannotations); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+	if _, ok := resumed.Part(0); !ok {
+		t.Error("expected part 0 to have made it")
+	}
+
+	tr.complete("bucket", "object")
+	if _, err := os.Stat(encrypted); !os.IsNotExist(err) {
+		t.Errorf("expected the encrypted copy to be removed once the upload completed, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/agent"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/spf13/cobra"
+)
+
+var allowlistAddOpts struct {
+	Reason  string
+	AddedBy string
+}
+
+// allowlistAddCmd represents the allowlist add command
+var allowlistAddCmd = &cobra.Command{
+	Use:   "add <repository|user|binary> <value>",
+	Short: "Adds a repository, user or executable to the runtime allow-list",
+	Long: `Adds a repository, user or executable to the runtime allow-list.
+Repositories are git remote URLs which may start or end with a * wildcard, users are user IDs,
+and executables are identified by their hex-encoded SHA256 hash, e.g. as printed by sha256sum.
+Agent smith picks up the change within its poll interval.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, err := parseAllowlistKind(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		client, namespace, err := allowlistClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		entry := config.AllowlistEntry{
+			Value:   args[1],
+			Reason:  allowlistAddOpts.Reason,
+			AddedBy: allowlistAddOpts.AddedBy,
+			Added:   time.Now().UTC(),
+		}
+		err = agent.UpdateAllowlist(context.Background(), client, namespace, allowlistOpts.ConfigMap, func(list *config.Allowlist) error {
+			return list.Add(kind, entry)
+		})
+		if err != nil {
+			log.WithError(err).Fatal("cannot add to allow-list")
+		}
+		log.WithField("allowlist", kind).WithField("value", entry.Value).Info("added to allow-list")
+	},
+}
+
+func init() {
+	allowlistCmd.AddCommand(allowlistAddCmd)
+
+	allowlistAddCmd.Flags().StringVar(&allowlistAddOpts.Reason, "reason", "", "why agent smith should not penalise this, e.g. a link to the false positive report")
+	allowlistAddCmd.Flags().StringVar(&allowlistAddOpts.AddedBy, "added-by", os.Getenv("USER"), "who added the entry")
+	_ = allowlistAddCmd.MarkFlagRequired("reason")
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/agent"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/spf13/cobra"
+)
+
+// allowlistListCmd represents the allowlist list command
+var allowlistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Prints the runtime allow-list",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, namespace, err := allowlistClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		list, err := agent.ReadAllowlist(context.Background(), client, namespace, allowlistOpts.ConfigMap)
+		if err != nil {
+			log.WithError(err).Fatal("cannot read allow-list")
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		defer w.Flush()
+		fmt.Fprintln(w, "KIND\tVALUE\tREASON\tADDED BY\tADDED")
+		for _, l := range []struct {
+			Kind    config.AllowlistKind
+			Entries []config.AllowlistEntry
+		}{
+			{config.AllowlistRepository, list.Repositories},
+			{config.AllowlistUser, list.Users},
+			{config.AllowlistBinary, list.Binaries},
+		} {
+			for _, e := range l.Entries {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", l.Kind, e.Value, e.Reason, e.AddedBy, e.Added.Format(time.RFC3339))
+			}
+		}
+	},
+}
+
+func init() {
+	allowlistCmd.AddCommand(allowlistListCmd)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/agent"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// allowlistRemoveCmd represents the allowlist remove command
+var allowlistRemoveCmd = &cobra.Command{
+	Use:   "remove <repository|user|binary> <value>",
+	Short: "Removes a repository, user or executable from the runtime allow-list",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, err := parseAllowlistKind(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		client, namespace, err := allowlistClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = agent.UpdateAllowlist(context.Background(), client, namespace, allowlistOpts.ConfigMap, func(list *config.Allowlist) error {
+			ok, err := list.Remove(kind, args[1])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return xerrors.Errorf("%s %s is not allow-listed", kind, args[1])
+			}
+			return nil
+		})
+		if err != nil {
+			log.WithError(err).Fatal("cannot remove from allow-list")
+		}
+		log.WithField("allowlist", kind).WithField("value", args[1]).Info("removed from allow-list")
+	},
+}
+
+func init() {
+	allowlistCmd.AddCommand(allowlistRemoveCmd)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var allowlistOpts struct {
+	Kubeconfig string
+	Namespace  string
+	ConfigMap  string
+}
+
+// allowlistCmd represents the allowlist command
+var allowlistCmd = &cobra.Command{
+	Use:   "allowlist",
+	Short: "manages the runtime allow-list of repositories, users and executables which agent smith never penalises",
+	Args:  cobra.MinimumNArgs(1),
+}
+
+// allowlistClient connects to the cluster using the kubeconfig, or the in-cluster config if there is none,
+// and returns the namespace of the allow-list config map
+func allowlistClient() (kubernetes.Interface, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = allowlistOpts.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = allowlistOpts.Namespace
+	cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+
+	namespace, _, err := cfg.Namespace()
+	if err != nil {
+		return nil, "", xerrors.Errorf("cannot determine namespace: %w", err)
+	}
+	rc, err := cfg.ClientConfig()
+	if err != nil {
+		return nil, "", xerrors.Errorf("cannot connect to kubernetes: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(rc)
+	if err != nil {
+		return nil, "", xerrors.Errorf("cannot connect to kubernetes: %w", err)
+	}
+	return client, namespace, nil
+}
+
+func parseAllowlistKind(kind string) (config.AllowlistKind, error) {
+	switch k := config.AllowlistKind(kind); k {
+	case config.AllowlistRepository, config.AllowlistUser, config.AllowlistBinary:
+		return k, nil
+	default:
+		return "", xerrors.Errorf("unknown allow-list %q - must be one of repository, user or binary", kind)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(allowlistCmd)
+
+	allowlistCmd.PersistentFlags().StringVar(&allowlistOpts.Kubeconfig, "kubeconfig", "", "path to the kubeconfig - defaults to $KUBECONFIG, ~/.kube/config or the in-cluster config")
+	allowlistCmd.PersistentFlags().StringVarP(&allowlistOpts.Namespace, "namespace", "n", "", "namespace of agent smith - defaults to the namespace of the current context")
+	allowlistCmd.PersistentFlags().StringVar(&allowlistOpts.ConfigMap, "config-map", config.DefaultAllowlistConfigMap, "name of the config map which holds the allow-list")
+}
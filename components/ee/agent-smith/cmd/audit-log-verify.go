@@ -0,0 +1,53 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/agent"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/spf13/cobra"
+)
+
+// auditLogVerifyCmd represents the audit-log verify command
+var auditLogVerifyCmd = &cobra.Command{
+	Use:   "verify <file.jsonl> ...",
+	Short: "Checks that no record of an exported audit log was modified or removed",
+	Long: `Checks that no record of an exported audit log was modified or removed.
+The files are read in the order given, hence the objects of a bucket must be passed in the order of their names.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			rs    []io.Reader
+			files []*os.File
+		)
+		for _, fn := range args {
+			f, err := os.Open(fn)
+			if err != nil {
+				log.WithError(err).Fatal("cannot open audit log")
+			}
+			files = append(files, f)
+			rs = append(rs, f)
+		}
+		defer func() {
+			for _, f := range files {
+				f.Close()
+			}
+		}()
+
+		n, err := agent.VerifyAuditLog(io.MultiReader(rs...))
+		if err != nil {
+			log.WithError(err).WithField("verifiedRecords", n).Fatal("audit log is not intact")
+		}
+		fmt.Printf("verified %d records\n", n)
+	},
+}
+
+func init() {
+	auditLogCmd.AddCommand(auditLogVerifyCmd)
+}
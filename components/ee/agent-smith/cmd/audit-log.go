@@ -0,0 +1,20 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// auditLogCmd represents the audit-log command
+var auditLogCmd = &cobra.Command{
+	Use:   "audit-log",
+	Short: "makes working with the exported audit log easier",
+	Args:  cobra.MinimumNArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(auditLogCmd)
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/spf13/cobra"
+)
+
+// configSchemaValidateCmd represents the config-schema validate command
+var configSchemaValidateCmd = &cobra.Command{
+	Use:   "validate <config.json>",
+	Short: "Validates a configuration against the config schema and the rules which span several of its parts",
+	Long: `Validates a configuration against the config schema and the rules which span several of its parts,
+e.g. that penalties which block users come with access to the Gitpod API. Prints all problems it finds
+and exits with a non-zero status if there are any, which makes it suitable for CI.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fc, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("cannot read config")
+		}
+
+		errs, err := validateConfig(fc)
+		if err != nil {
+			log.WithError(err).Fatal("cannot validate config")
+		}
+		if len(errs) == 0 {
+			fmt.Printf("%s is valid\n", args[0])
+			return
+		}
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", args[0], e)
+		}
+		os.Exit(1)
+	},
+}
+
+// validateConfig returns the problems of a configuration. It only returns an error if it cannot validate at all.
+func validateConfig(fc []byte) (problems []string, err error) {
+	var doc interface{}
+	err = json.Unmarshal(fc, &doc)
+	if err != nil {
+		if serr, ok := err.(*json.SyntaxError); ok {
+			return []string{fmt.Sprintf("%s: %v", position(fc, serr.Offset), err)}, nil
+		}
+		return []string{err.Error()}, nil
+	}
+
+	// The schema does not know how some types unmarshal, e.g. durations are strings but their schema says integer.
+	// We hence only check the structure against the schema and leave the types to unmarshalling.
+	sc, err := json.Marshal(reflectConfigSchema())
+	if err != nil {
+		return nil, err
+	}
+	var schema map[string]interface{}
+	err = json.Unmarshal(sc, &schema)
+	if err != nil {
+		return nil, err
+	}
+	defs, _ := schema["definitions"].(map[string]interface{})
+	problems = checkStructure(defs, schema, doc, "", problems)
+	if len(problems) > 0 {
+		return problems, nil
+	}
+
+	var cfg config.ServiceConfig
+	err = json.Unmarshal(fc, &cfg)
+	if err != nil {
+		if terr, ok := err.(*json.UnmarshalTypeError); ok {
+			return []string{fmt.Sprintf("%s: %s: cannot use %s as %s", position(fc, terr.Offset), terr.Field, terr.Value, terr.Type)}, nil
+		}
+		return []string{err.Error()}, nil
+	}
+	for _, e := range cfg.Validate() {
+		problems = append(problems, e.Error())
+	}
+	return problems, nil
+}
+
+// checkStructure checks that doc only has the properties the schema lists, and all those it requires
+func checkStructure(defs map[string]interface{}, schema map[string]interface{}, doc interface{}, path string, problems []string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := defs[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{})
+		if !ok {
+			return problems
+		}
+		schema = def
+	}
+
+	switch d := doc.(type) {
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return problems
+		}
+		for i, v := range d {
+			problems = checkStructure(defs, items, v, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	case map[string]interface{}:
+		props, hasProps := schema["properties"].(map[string]interface{})
+		if !hasProps {
+			// a map, e.g. the enforcement rules per repository
+			if values, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				for _, k := range sortedKeys(d) {
+					problems = checkStructure(defs, values, d[k], fmt.Sprintf("%s[%q]", path, k), problems)
+				}
+			} else if pp, ok := schema["patternProperties"].(map[string]interface{}); ok && len(pp) == 1 {
+				for _, values := range pp {
+					values, _ := values.(map[string]interface{})
+					for _, k := range sortedKeys(d) {
+						problems = checkStructure(defs, values, d[k], fmt.Sprintf("%s[%q]", path, k), problems)
+					}
+				}
+			}
+			return problems
+		}
+
+		if req, ok := schema["required"].([]interface{}); ok {
+			for _, r := range req {
+				name, _ := r.(string)
+				if _, ok := d[name]; !ok {
+					problems = append(problems, fmt.Sprintf("%s: missing required property %q", displayPath(path), name))
+				}
+			}
+		}
+		for _, k := range sortedKeys(d) {
+			if path == "" && k == "$schema" {
+				// editors use it to find the schema
+				continue
+			}
+			prop, ok := props[k].(map[string]interface{})
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown property %q - known are %s", displayPath(path), k, strings.Join(sortedKeys(props), ", ")))
+				continue
+			}
+			problems = checkStructure(defs, prop, d[k], joinPath(path, k), problems)
+		}
+	}
+	return problems
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func joinPath(path, prop string) string {
+	if path == "" {
+		return prop
+	}
+	return path + "." + prop
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// position turns an offset into the config into a line:column position
+func position(fc []byte, offset int64) string {
+	if offset > int64(len(fc)) {
+		offset = int64(len(fc))
+	}
+	before := fc[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("%d:%d", line, col)
+}
+
+func init() {
+	configSchemaCmd.AddCommand(configSchemaValidateCmd)
+}
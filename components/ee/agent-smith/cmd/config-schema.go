@@ -19,9 +19,7 @@ var configSchemaCmd = &cobra.Command{
 	Use:   "config-schema",
 	Short: "Generates the JSON schema validating the configuration",
 	Run: func(cmd *cobra.Command, args []string) {
-		schema := jsonschema.Reflect(&config.ServiceConfig{})
-		schema.Title = "agent-smith config schema - generated using agent-smith config-schema"
-		out, err := json.MarshalIndent(schema, "", "  ")
+		out, err := json.MarshalIndent(reflectConfigSchema(), "", "  ")
 		if err != nil {
 			log.WithError(err).Fatal()
 			return
@@ -30,6 +28,12 @@ var configSchemaCmd = &cobra.Command{
 	},
 }
 
+func reflectConfigSchema() *jsonschema.Schema {
+	schema := jsonschema.Reflect(&config.ServiceConfig{})
+	schema.Title = "agent-smith config schema - generated using agent-smith config-schema"
+	return schema
+}
+
 func init() {
 	rootCmd.AddCommand(configSchemaCmd)
 }
@@ -21,14 +21,45 @@ import (
 // how workspace pods look like. This code should eventually be moved to ws-manager or
 // call one of ws-manager's libraries.
 
+// stopReasonPenalty is ws-manager's StopReason for workspaces we stop
+const stopReasonPenalty = "STOP_REASON_PENALTY"
+
 // stopWorkspace stops a workspace
-func (agent *Smith) stopWorkspace(supervisorPID int) error {
+func (agent *Smith) stopWorkspace(supervisorPID int, podname string) error {
+	// we'd rather stop the workspace without telling why than not stop it at all
+	err := agent.markStopReason(podname)
+	if err != nil {
+		log.WithError(err).WithField("pod", podname).Warn("cannot record why we stop the workspace")
+	}
 	return unix.Kill(supervisorPID, unix.SIGKILL)
 }
 
+// markStopReason tells ws-manager that the workspace was stopped as a penalty
+func (agent *Smith) markStopReason(podname string) error {
+	if agent.Kubernetes == nil {
+		return xerrors.Errorf("not connected to Kubernetes - cannot mark the stop reason")
+	}
+
+	ctx := context.Background()
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		pods := agent.Kubernetes.CoreV1().Pods(agent.Config.KubernetesNamespace)
+		pod, err := pods.Get(ctx, podname, corev1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[wsk8s.StopReasonAnnotation] = stopReasonPenalty
+		_, err = pods.Update(ctx, pod, corev1.UpdateOptions{})
+		return err
+	})
+}
+
 // stopWorkspaceAndBlockUser stops a workspace and blocks the user (who would have guessed?)
-func (agent *Smith) stopWorkspaceAndBlockUser(supervisorPID int, ownerID string) error {
-	err := agent.stopWorkspace(supervisorPID)
+func (agent *Smith) stopWorkspaceAndBlockUser(supervisorPID int, podname string, ownerID string) error {
+	err := agent.stopWorkspace(supervisorPID, podname)
 	if err != nil {
 		log.WithError(err).WithField("owner", ownerID).Warn("error stopping workspace")
 	}
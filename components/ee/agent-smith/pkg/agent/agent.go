@@ -25,7 +25,6 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/utils/lru"
 )
 
 const (
@@ -43,10 +42,17 @@ type Smith struct {
 
 	egressTrafficCheckHandler func(pid int) (int64, error)
 	timeElapsedHandler        func(t time.Time) time.Duration
-	notifiedInfringements     *lru.Cache
-
-	detector   detector.ProcessDetector
-	classifier classifier.ProcessClassifier
+	fileAudits                *fileAudits
+	networkAnomalies          *networkAnomalies
+	miners                    *minerDetection
+	policies                  *penaltyPolicies
+	auditLog                  *auditLog
+	allowlist                 *runtimeAllowlist
+	sampler                   *processSampler
+
+	detector      detector.ProcessDetector
+	classifier    classifier.ProcessClassifier
+	signatureFeed *signatureFeed
 }
 
 // NewAgentSmith creates a new agent smith
@@ -97,7 +103,22 @@ func NewAgentSmith(cfg config.Config) (*Smith, error) {
 		}
 	}
 
-	detec, err := detector.NewProcfsDetector()
+	var (
+		detec detector.ProcessDetector
+		err   error
+	)
+	switch cfg.ProcessDetector {
+	case config.ProcessDetectorProcfs, "":
+		var scanInterval time.Duration
+		if cfg.Sampling != nil {
+			scanInterval = time.Duration(cfg.Sampling.ProcfsScanInterval)
+		}
+		detec, err = detector.NewProcfsDetector(scanInterval)
+	case config.ProcessDetectorEBPF:
+		detec, err = detector.NewEBPFDetector(cfg.ProbePath)
+	default:
+		err = xerrors.Errorf("unknown process detector %q", cfg.ProcessDetector)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -110,12 +131,7 @@ func NewAgentSmith(cfg config.Config) (*Smith, error) {
 	m := newAgentMetrics()
 	res := &Smith{
 		EnforcementRules: map[string]config.EnforcementRules{
-			defaultRuleset: {
-				config.GradeKind(config.InfringementExec, common.SeverityBarely):          config.PenaltyLimitCPU,
-				config.GradeKind(config.InfringementExec, common.SeverityAudit):           config.PenaltyStopWorkspace,
-				config.GradeKind(config.InfringementExec, common.SeverityVery):            config.PenaltyStopWorkspaceAndBlockUser,
-				config.GradeKind(config.InfringementExcessiveEgress, common.SeverityVery): config.PenaltyStopWorkspace,
-			},
+			defaultRuleset: config.DefaultEnforcementRules(),
 		},
 		Config:     cfg,
 		GitpodAPI:  api,
@@ -124,11 +140,51 @@ func NewAgentSmith(cfg config.Config) (*Smith, error) {
 		detector:   detec,
 		classifier: class,
 
-		notifiedInfringements:     lru.New(notificationCacheSize),
 		metrics:                   m,
+		sampler:                   newProcessSampler(cfg.Sampling),
 		egressTrafficCheckHandler: getEgressTraffic,
 		timeElapsedHandler:        time.Since,
 	}
+	if cfg.FileAudit != nil {
+		res.fileAudits = newFileAudits(time.Duration(cfg.FileAudit.CorrelationWindow))
+	}
+	if cfg.NetworkAnomalies != nil {
+		res.networkAnomalies, err = newNetworkAnomalies(*cfg.NetworkAnomalies, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.MinerDetection != nil {
+		res.miners, err = newMinerDetection(*cfg.MinerDetection, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.AuditLog != nil {
+		res.auditLog, err = newAuditLog(*cfg.AuditLog, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.RuntimeAllowlist != nil {
+		res.allowlist, err = newRuntimeAllowlist(*cfg.RuntimeAllowlist, clientset, cfg.KubernetesNamespace, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SignatureFeed != nil {
+		// until we've loaded the feed we classify using the static blocklists
+		class, err := cfg.Blocklists.Merge(nil).Classifier()
+		if err != nil {
+			return nil, err
+		}
+		reloadable := classifier.NewReloadableClassifier(class)
+		res.classifier = reloadable
+		res.signatureFeed, err = newSignatureFeed(*cfg.SignatureFeed, cfg.Blocklists, reloadable, m)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if cfg.Enforcement.Default != nil {
 		if err := cfg.Enforcement.Default.Validate(); err != nil {
 			return nil, err
@@ -141,6 +197,12 @@ func NewAgentSmith(cfg config.Config) (*Smith, error) {
 		}
 		res.EnforcementRules[repo] = rules
 	}
+	if len(cfg.Enforcement.Policies) > 0 {
+		res.policies, err = newPenaltyPolicies(cfg.Enforcement.Policies)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return res, nil
 }
@@ -187,6 +249,8 @@ func (ws InfringingWorkspace) DescribeInfringements(charCount int) string {
 type Infringement struct {
 	Description string
 	Kind        config.GradedInfringementKind
+	// AuditOnly is true if the infringement should be reported, but not penalised
+	AuditOnly bool
 }
 
 // defaultRuleset is the name ("remote origin URL") of the default enforcement rules
@@ -200,28 +264,68 @@ type classifiedProcess struct {
 
 // Start gets a stream of Infringements from Run and executes a callback on them to apply a Penalty
 func (agent *Smith) Start(ctx context.Context, callback func(InfringingWorkspace, []config.PenaltyKind)) {
+	if agent.networkAnomalies != nil || agent.miners != nil {
+		// we must discover connections before processes to not miss any
+		if cd, ok := agent.detector.(detector.ConnectionDetector); ok {
+			conns, err := cd.DiscoverConnections(ctx)
+			if err != nil {
+				log.WithError(err).Fatal("cannot start connection detector")
+			}
+			if agent.networkAnomalies != nil {
+				go agent.networkAnomalies.Run(ctx)
+			}
+			go agent.watchConnections(ctx, conns, callback)
+		} else {
+			log.WithField("processDetector", agent.Config.ProcessDetector).Warn("network anomaly and stratum connection detection need the ebpf process detector - not watching connections")
+		}
+	}
+
 	ps, err := agent.detector.DiscoverProcesses(ctx)
 	if err != nil {
 		log.WithError(err).Fatal("cannot start process detector")
 	}
+	if agent.Config.FileAudit != nil {
+		go agent.receiveFileAudits(ctx)
+	}
+	if agent.signatureFeed != nil {
+		go agent.signatureFeed.Run(ctx)
+	}
+	if agent.auditLog != nil {
+		go agent.auditLog.Run(ctx)
+	}
+	if agent.allowlist != nil {
+		go agent.allowlist.Run(ctx)
+	}
+	if agent.miners != nil {
+		go agent.watchMiners(ctx, callback)
+	}
 
 	var (
 		wg  sync.WaitGroup
-		cli = make(chan detector.Process, 500)
+		cli = make(chan detector.Process, agent.sampler.QueueSize)
 		clo = make(chan classifiedProcess, 50)
 	)
 	agent.metrics.RegisterClassificationQueues(cli, clo)
 	defer wg.Wait()
-	for i := 0; i < 25; i++ {
+	for i := 0; i < agent.sampler.Workers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for i := range cli {
+				if agent.sampler.Wait(ctx) != nil {
+					return
+				}
+				start := time.Now()
 				class, err := agent.classifier.Matches(i.Path, i.CommandLine)
+				agent.metrics.classificationDuration.Observe(time.Since(start).Seconds())
 				// optimisation: early out to not block on the CLO chan
 				if err == nil && class.Level == classifier.LevelNoMatch {
 					continue
 				}
+				if err == nil && agent.allowlist.ExemptsExecutable(i.Path) {
+					log.WithFields(log.OWI(i.Workspace.OwnerID, i.Workspace.WorkspaceID, i.Workspace.InstanceID)).WithField("path", i.Path).Debug("executable is allow-listed")
+					continue
+				}
 				clo <- classifiedProcess{P: i, C: class, Err: err}
 			}
 		}()
@@ -241,10 +345,17 @@ func (agent *Smith) Start(ctx context.Context, callback func(InfringingWorkspace
 				if !ok {
 					return
 				}
+				agent.miners.Track(proc)
+				if !agent.sampler.Sample() {
+					agent.metrics.processes.WithLabelValues("sampled_out").Inc()
+					continue
+				}
 				select {
 				case cli <- proc:
+					agent.metrics.processes.WithLabelValues("queued").Inc()
 				default:
 					// we're overfilling the classifier worker
+					agent.metrics.processes.WithLabelValues("dropped").Inc()
 					agent.metrics.classificationBackpressureInDrop.Inc()
 				}
 			}
@@ -265,35 +376,51 @@ func (agent *Smith) Start(ctx context.Context, callback func(InfringingWorkspace
 				continue
 			}
 
-			agent.Penalize(InfringingWorkspace{
+			infringements := []Infringement{
+				{Kind: config.GradeKind(config.InfringementExec, common.Severity(cl.Level)), Description: fmt.Sprintf("%s: %s", cl.Classifier, cl.Message), AuditOnly: cl.AuditOnly},
+			}
+			agent.correlateFileAudits(proc.Workspace.InstanceID, infringements)
+
+			ws := InfringingWorkspace{
 				SupervisorPID: proc.Workspace.PID,
 				Owner:         proc.Workspace.OwnerID,
 				InstanceID:    proc.Workspace.InstanceID,
 				GitRemoteURL:  []string{proc.Workspace.GitURL},
-				Infringements: []Infringement{
-					{Kind: config.GradeKind(config.InfringementExec, common.Severity(cl.Level)), Description: fmt.Sprintf("%s: %s", cl.Classifier, cl.Message)},
-				},
-			})
+				Infringements: infringements,
+			}
+			penalties, _ := agent.Penalize(ws)
+			callback(ws, penalties)
 		}
 	}
 }
 
 // Penalize acts on infringements and e.g. stops pods
-func (agent *Smith) Penalize(ws InfringingWorkspace) ([]config.PenaltyKind, error) {
+func (agent *Smith) Penalize(ws InfringingWorkspace) (penalty []config.PenaltyKind, err error) {
+	defer func() {
+		agent.auditLog.Record(ws, penalty, err)
+	}()
+
 	var remoteURL string
 	if len(ws.GitRemoteURL) > 0 {
 		remoteURL = ws.GitRemoteURL[0]
 	}
 
 	owi := log.OWI(ws.Owner, ws.WorkspaceID, ws.InstanceID)
+	for _, inf := range ws.Infringements {
+		kind, _ := inf.Kind.Kind()
+		agent.metrics.infringements.WithLabelValues(string(kind), severityLabel(inf.Kind.Severity())).Inc()
+	}
 
-	penalty := getPenalty(agent.EnforcementRules[defaultRuleset], agent.EnforcementRules[remoteURL], ws.Infringements)
+	penalty = agent.decidePenalty(ws, remoteURL, time.Now())
 	for _, p := range penalty {
 		switch p {
+		case config.PenaltyNotify:
+			// the callback of Start does the notifying
+			log.WithField("infringement", ws.Infringements).WithFields(owi).Info("notifying about infringement")
 		case config.PenaltyStopWorkspace:
 			log.WithField("infringement", ws.Infringements).WithFields(owi).Info("stopping workspace")
 			agent.metrics.penaltyAttempts.WithLabelValues(string(p)).Inc()
-			err := agent.stopWorkspace(ws.SupervisorPID)
+			err := agent.stopWorkspace(ws.SupervisorPID, ws.Pod)
 			if err != nil {
 				log.WithError(err).WithFields(owi).Debug("failed to stop workspace")
 				agent.metrics.penaltyFailures.WithLabelValues(string(p), err.Error()).Inc()
@@ -302,7 +429,7 @@ func (agent *Smith) Penalize(ws InfringingWorkspace) ([]config.PenaltyKind, erro
 		case config.PenaltyStopWorkspaceAndBlockUser:
 			log.WithField("infringement", ws.Infringements).WithFields(owi).Info("stopping workspace and blocking user")
 			agent.metrics.penaltyAttempts.WithLabelValues(string(p)).Inc()
-			err := agent.stopWorkspaceAndBlockUser(ws.SupervisorPID, ws.Owner)
+			err := agent.stopWorkspaceAndBlockUser(ws.SupervisorPID, ws.Pod, ws.Owner)
 			if err != nil {
 				log.WithError(err).WithFields(owi).Debug("failed to stop workspace and block user")
 				agent.metrics.penaltyFailures.WithLabelValues(string(p), err.Error()).Inc()
@@ -330,14 +457,7 @@ func findEnforcementRules(rules map[string]config.EnforcementRules, remoteURL st
 	}
 
 	for k, v := range rules {
-		hp, hs := strings.HasPrefix(k, "*"), strings.HasSuffix(k, "*")
-		if hp && hs && strings.Contains(strings.ToLower(remoteURL), strings.Trim(k, "*")) {
-			return v
-		}
-		if hp && strings.HasSuffix(strings.ToLower(remoteURL), strings.Trim(k, "*")) {
-			return v
-		}
-		if hs && strings.HasPrefix(strings.ToLower(remoteURL), strings.Trim(k, "*")) {
+		if matchesRemoteURL(k, remoteURL) {
 			return v
 		}
 	}
@@ -345,6 +465,63 @@ func findEnforcementRules(rules map[string]config.EnforcementRules, remoteURL st
 	return nil
 }
 
+// matchesRemoteURL returns true if the remote URL matches the pattern, which may start or end with a * wildcard
+func matchesRemoteURL(pattern, remoteURL string) bool {
+	hp, hs := strings.HasPrefix(pattern, "*"), strings.HasSuffix(pattern, "*")
+	if hp && hs {
+		return strings.Contains(strings.ToLower(remoteURL), strings.Trim(pattern, "*"))
+	}
+	if hp {
+		return strings.HasSuffix(strings.ToLower(remoteURL), strings.Trim(pattern, "*"))
+	}
+	if hs {
+		return strings.HasPrefix(strings.ToLower(remoteURL), strings.Trim(pattern, "*"))
+	}
+	return pattern == remoteURL
+}
+
+// decidePenalty decides what kind of penalty should be applied for the infringements of a workspace.
+// Audit-only infringements are reported, but never penalised. If a policy applies to the owner of the
+// workspace, it escalates the penalty for every infringement the enforcement rules penalise.
+// Allow-listed owners and repositories are never penalised.
+func (agent *Smith) decidePenalty(ws InfringingWorkspace, remoteURL string, now time.Time) []config.PenaltyKind {
+	if reason, ok := agent.allowlist.ExemptsWorkspace(ws.Owner, ws.GitRemoteURL); ok {
+		log.WithFields(log.OWI(ws.Owner, ws.WorkspaceID, ws.InstanceID)).WithField("reason", reason).Info("workspace is allow-listed - not applying penalty")
+		return nil
+	}
+
+	var (
+		enforced  = make([]Infringement, 0, len(ws.Infringements))
+		auditOnly bool
+	)
+	for _, inf := range ws.Infringements {
+		if inf.AuditOnly {
+			auditOnly = true
+			continue
+		}
+		enforced = append(enforced, inf)
+	}
+
+	penalty := getPenalty(agent.EnforcementRules[defaultRuleset], agent.EnforcementRules[remoteURL], enforced)
+	if policy := agent.policies.Find(ws.Owner); policy != nil && len(penalty) > 0 {
+		p := agent.policies.Escalate(policy, ws.Owner, ws.InstanceID, now)
+		if policy.DryRun {
+			log.WithFields(log.OWI(ws.Owner, ws.WorkspaceID, ws.InstanceID)).WithField("policy", policy.Name).WithField("penalty", p).Info("dry run - not applying penalty")
+			agent.metrics.penaltyDryRuns.WithLabelValues(policy.Name, string(p)).Inc()
+			p = config.PenaltyNotify
+		}
+
+		penalty = nil
+		if p != config.PenaltyNone {
+			penalty = []config.PenaltyKind{p}
+		}
+	}
+	if auditOnly && len(penalty) == 0 {
+		penalty = []config.PenaltyKind{config.PenaltyNotify}
+	}
+	return penalty
+}
+
 // getPenalty decides what kind of penalty should be applied for a set of infringements.
 // The penalty list will never contain PenaltyNone, but may be empty
 func getPenalty(defaultRules, perRepoRules config.EnforcementRules, vs []Infringement) []config.PenaltyKind {
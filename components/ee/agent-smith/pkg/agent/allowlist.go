@@ -0,0 +1,264 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"golang.org/x/xerrors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// defaultAllowlistInterval is the time between polls of the allow-list config map
+	defaultAllowlistInterval = 30 * time.Second
+)
+
+// runtimeAllowlist keeps the allow-list of a config map, which we poll
+type runtimeAllowlist struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Name      string
+	Interval  time.Duration
+
+	metrics *metrics
+
+	mu       sync.RWMutex
+	list     config.Allowlist
+	users    map[string]string
+	binaries map[string]string
+}
+
+func newRuntimeAllowlist(cfg config.RuntimeAllowlist, client kubernetes.Interface, namespace string, m *metrics) (*runtimeAllowlist, error) {
+	if client == nil {
+		return nil, xerrors.Errorf("the runtime allow-list requires a connection to Kubernetes")
+	}
+	if namespace == "" {
+		return nil, xerrors.Errorf("the runtime allow-list requires the namespace")
+	}
+	if cfg.ConfigMap == "" {
+		cfg.ConfigMap = config.DefaultAllowlistConfigMap
+	}
+	interval := time.Duration(cfg.Interval)
+	if interval == 0 {
+		interval = defaultAllowlistInterval
+	}
+
+	return &runtimeAllowlist{
+		Client:    client,
+		Namespace: namespace,
+		Name:      cfg.ConfigMap,
+		Interval:  interval,
+		metrics:   m,
+	}, nil
+}
+
+// Run polls the config map until ctx is canceled
+func (a *runtimeAllowlist) Run(ctx context.Context) {
+	t := time.NewTicker(a.Interval)
+	defer t.Stop()
+
+	for {
+		err := a.Update(ctx)
+		if err != nil {
+			log.WithError(err).WithField("configMap", a.Name).Warn("cannot update runtime allow-list - keeping the current one")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Update reads the allow-list from the config map. If it cannot be read, we keep the current one.
+func (a *runtimeAllowlist) Update(ctx context.Context) error {
+	list, err := ReadAllowlist(ctx, a.Client, a.Namespace, a.Name)
+	if err != nil {
+		a.metrics.allowlistUpdates.WithLabelValues("error").Inc()
+		return err
+	}
+	a.Set(*list)
+	a.metrics.allowlistUpdates.WithLabelValues("loaded").Inc()
+	return nil
+}
+
+// Set replaces the allow-list
+func (a *runtimeAllowlist) Set(list config.Allowlist) {
+	users := make(map[string]string, len(list.Users))
+	for _, e := range list.Users {
+		users[e.Value] = e.Reason
+	}
+	binaries := make(map[string]string, len(list.Binaries))
+	for _, e := range list.Binaries {
+		binaries[e.Value] = e.Reason
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.list = list
+	a.users = users
+	a.binaries = binaries
+}
+
+// ExemptsWorkspace returns the reason why the owner or one of the remote URLs of a workspace is allow-listed.
+// It returns false if neither is.
+func (a *runtimeAllowlist) ExemptsWorkspace(owner string, remoteURLs []string) (reason string, exempt bool) {
+	if a == nil {
+		return "", false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if reason, ok := a.users[owner]; ok {
+		a.metrics.allowlistExemptions.WithLabelValues(string(config.AllowlistUser)).Inc()
+		return reason, true
+	}
+	for _, e := range a.list.Repositories {
+		for _, u := range remoteURLs {
+			if u != "" && matchesRemoteURL(e.Value, u) {
+				a.metrics.allowlistExemptions.WithLabelValues(string(config.AllowlistRepository)).Inc()
+				return e.Reason, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ExemptsExecutable returns true if the SHA256 hash of the executable is allow-listed
+func (a *runtimeAllowlist) ExemptsExecutable(path string) bool {
+	if a == nil {
+		return false
+	}
+	a.mu.RLock()
+	empty := len(a.binaries) == 0
+	a.mu.RUnlock()
+	if empty {
+		// optimisation: don't hash executables if there's nothing to compare against
+		return false
+	}
+
+	hash, err := hashExecutable(path)
+	if err != nil {
+		log.WithError(err).WithField("path", path).Debug("cannot hash executable")
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if _, ok := a.binaries[hash]; !ok {
+		return false
+	}
+	a.metrics.allowlistExemptions.WithLabelValues(string(config.AllowlistBinary)).Inc()
+	return true
+}
+
+func hashExecutable(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadAllowlist reads the allow-list from a config map. A missing config map is an empty allow-list.
+func ReadAllowlist(ctx context.Context, client kubernetes.Interface, namespace, name string) (*config.Allowlist, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return &config.Allowlist{}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get allow-list config map: %w", err)
+	}
+	return parseAllowlist(cm)
+}
+
+func parseAllowlist(cm *corev1.ConfigMap) (*config.Allowlist, error) {
+	var res config.Allowlist
+	if c, ok := cm.Data[config.AllowlistConfigMapKey]; ok {
+		err := json.Unmarshal([]byte(c), &res)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse allow-list: %w", err)
+		}
+	}
+	err := res.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateAllowlist applies mod to the allow-list of a config map and stores the result, creating the config map if need be.
+// If someone else modifies the config map concurrently, we apply mod again.
+func UpdateAllowlist(ctx context.Context, client kubernetes.Interface, namespace, name string, mod func(*config.Allowlist) error) error {
+	cms := client.CoreV1().ConfigMaps(namespace)
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}, func() error {
+		cm, err := cms.Get(ctx, name, metav1.GetOptions{})
+		exists := err == nil
+		if errors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels:    map[string]string{"component": "agent-smith"},
+				},
+			}
+		} else if err != nil {
+			return xerrors.Errorf("cannot get allow-list config map: %w", err)
+		}
+
+		list, err := parseAllowlist(cm)
+		if err != nil {
+			return err
+		}
+		err = mod(list)
+		if err != nil {
+			return err
+		}
+		err = list.Validate()
+		if err != nil {
+			return err
+		}
+		c, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[config.AllowlistConfigMapKey] = string(c)
+
+		if exists {
+			_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+		} else {
+			_, err = cms.Create(ctx, cm, metav1.CreateOptions{})
+		}
+		return err
+	})
+}
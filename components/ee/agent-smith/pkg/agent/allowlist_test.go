@@ -0,0 +1,155 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUpdateAllowlist(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = fake.NewSimpleClientset()
+		added  = time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	)
+	add := func(kind config.AllowlistKind, value string) func(*config.Allowlist) error {
+		return func(l *config.Allowlist) error {
+			return l.Add(kind, config.AllowlistEntry{Value: value, Reason: "research", Added: added})
+		}
+	}
+
+	tests := []struct {
+		Name        string
+		Mod         func(*config.Allowlist) error
+		Expectation string
+	}{
+		{Name: "add repository", Mod: add(config.AllowlistRepository, "https://github.com/gitpod-io/*")},
+		{Name: "add user", Mod: add(config.AllowlistUser, "alice")},
+		{Name: "add binary", Mod: add(config.AllowlistBinary, "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824")},
+		{Name: "add invalid binary", Mod: add(config.AllowlistBinary, "xmrig"), Expectation: "binary allow-list entry xmrig is not a hex-encoded SHA256 hash"},
+		{
+			Name: "add without reason",
+			Mod: func(l *config.Allowlist) error {
+				return l.Add(config.AllowlistUser, config.AllowlistEntry{Value: "bob"})
+			},
+			Expectation: "user allow-list entry bob has no reason",
+		},
+		{Name: "add unknown kind", Mod: add("team", "gitpod"), Expectation: `unknown allow-list "team"`},
+		{Name: "replace user", Mod: add(config.AllowlistUser, "alice")},
+		{
+			Name: "remove user",
+			Mod: func(l *config.Allowlist) error {
+				_, err := l.Remove(config.AllowlistUser, "alice")
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		var act string
+		err := UpdateAllowlist(ctx, client, "default", config.DefaultAllowlistConfigMap, test.Mod)
+		if err != nil {
+			act = err.Error()
+		}
+		if diff := cmp.Diff(test.Expectation, act); diff != "" {
+			t.Errorf("%s: unexpected error (-want +got):\n%s", test.Name, diff)
+		}
+	}
+
+	act, err := ReadAllowlist(ctx, client, "default", config.DefaultAllowlistConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &config.Allowlist{
+		Repositories: []config.AllowlistEntry{{Value: "https://github.com/gitpod-io/*", Reason: "research", Added: added}},
+		Binaries:     []config.AllowlistEntry{{Value: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Reason: "research", Added: added}},
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected allow-list (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadAllowlistMissingConfigMap(t *testing.T) {
+	act, err := ReadAllowlist(context.Background(), fake.NewSimpleClientset(), "default", config.DefaultAllowlistConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&config.Allowlist{}, act); diff != "" {
+		t.Errorf("unexpected allow-list (-want +got):\n%s", diff)
+	}
+}
+
+func TestRuntimeAllowlist(t *testing.T) {
+	exec := filepath.Join(t.TempDir(), "hello")
+	err := os.WriteFile(exec, []byte("hello"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(t.TempDir(), "world")
+	err = os.WriteFile(other, []byte("world"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	al, err := newRuntimeAllowlist(config.RuntimeAllowlist{}, fake.NewSimpleClientset(), "default", newAgentMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Set(config.Allowlist{
+		Repositories: []config.AllowlistEntry{{Value: "https://github.com/security-research/*", Reason: "research"}},
+		Users:        []config.AllowlistEntry{{Value: "alice", Reason: "red team"}},
+		Binaries:     []config.AllowlistEntry{{Value: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Reason: "sha256 of hello"}},
+	})
+
+	var nilAllowlist *runtimeAllowlist
+	tests := []struct {
+		Name        string
+		Allowlist   *runtimeAllowlist
+		Owner       string
+		RemoteURL   string
+		Executable  string
+		Expectation []config.PenaltyKind
+	}{
+		{Name: "no allow-list", Allowlist: nilAllowlist, Owner: "alice", RemoteURL: "https://github.com/security-research/miner", Executable: exec, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspaceAndBlockUser}},
+		{Name: "allow-listed user", Allowlist: al, Owner: "alice", Expectation: nil},
+		{Name: "allow-listed repository", Allowlist: al, Owner: "bob", RemoteURL: "https://github.com/security-research/miner", Expectation: nil},
+		{Name: "other repository", Allowlist: al, Owner: "bob", RemoteURL: "https://github.com/miner/security-research", Executable: other, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspaceAndBlockUser}},
+		{Name: "allow-listed executable", Allowlist: al, Owner: "bob", Executable: exec},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			agent := &Smith{
+				EnforcementRules: map[string]config.EnforcementRules{
+					defaultRuleset: {config.GradeKind(config.InfringementExec, common.SeverityVery): config.PenaltyStopWorkspaceAndBlockUser},
+				},
+				metrics:   newAgentMetrics(),
+				allowlist: test.Allowlist,
+			}
+			if test.Executable != "" && agent.allowlist.ExemptsExecutable(test.Executable) {
+				// classified processes of allow-listed executables never turn into infringements
+				return
+			}
+
+			ws := InfringingWorkspace{
+				Owner:         test.Owner,
+				GitRemoteURL:  []string{test.RemoteURL},
+				Infringements: []Infringement{{Kind: config.GradeKind(config.InfringementExec, common.SeverityVery)}},
+			}
+			act := agent.decidePenalty(ws, test.RemoteURL, time.Now())
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected penalty (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
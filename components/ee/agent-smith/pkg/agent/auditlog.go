@@ -0,0 +1,425 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultAuditLogFlushInterval is the time between two exports of the audit log
+	defaultAuditLogFlushInterval = 10 * time.Second
+	// maxPendingAuditRecords is the number of records we hold on to per sink while it's unavailable
+	maxPendingAuditRecords = 10000
+)
+
+// AuditRecord is an entry of the audit log
+type AuditRecord struct {
+	// Chain identifies the records of one agent smith process. Their sequence numbers have no gaps.
+	Chain    string    `json:"chain"`
+	Sequence uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+
+	Owner         string               `json:"owner"`
+	WorkspaceID   string               `json:"workspaceID,omitempty"`
+	InstanceID    string               `json:"instanceID"`
+	GitRemoteURL  []string             `json:"gitRemoteURL,omitempty"`
+	Infringements []AuditInfringement  `json:"infringements"`
+	Penalties     []config.PenaltyKind `json:"penalties,omitempty"`
+	Error         string               `json:"error,omitempty"`
+
+	// PrevHash is the hash of the previous record of the chain, empty for the first one
+	PrevHash string `json:"prevHash,omitempty"`
+	// Hash is the hex-encoded SHA-256 of the JSON encoding of the record with an empty hash
+	Hash string `json:"hash"`
+}
+
+// AuditInfringement is an infringement in the audit log
+type AuditInfringement struct {
+	Kind        config.GradedInfringementKind `json:"kind"`
+	Description string                        `json:"description"`
+	AuditOnly   bool                          `json:"auditOnly,omitempty"`
+}
+
+func (r AuditRecord) computeHash() (string, error) {
+	r.Hash = ""
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyAuditLog checks that the records in r, JSON objects one after the other, are unmodified
+// and that no record is missing in between. The records of a chain must be in order, but may repeat.
+func VerifyAuditLog(r io.Reader) (records int, err error) {
+	last := make(map[string]AuditRecord)
+	dec := json.NewDecoder(r)
+	for {
+		var rec AuditRecord
+		err = dec.Decode(&rec)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, xerrors.Errorf("cannot parse record %d: %w", records, err)
+		}
+
+		hash, err := rec.computeHash()
+		if err != nil {
+			return records, err
+		}
+		if hash != rec.Hash {
+			return records, xerrors.Errorf("chain %s: record %d was modified", rec.Chain, rec.Sequence)
+		}
+		if prev, ok := last[rec.Chain]; ok {
+			if rec.Sequence == prev.Sequence && rec.Hash == prev.Hash {
+				// sinks receive records at least once
+				continue
+			}
+			if rec.Sequence != prev.Sequence+1 {
+				return records, xerrors.Errorf("chain %s: records %d to %d are missing", rec.Chain, prev.Sequence+1, rec.Sequence-1)
+			}
+			if rec.PrevHash != prev.Hash {
+				return records, xerrors.Errorf("chain %s: record %d does not follow record %d", rec.Chain, rec.Sequence, prev.Sequence)
+			}
+		}
+		last[rec.Chain] = rec
+		records++
+	}
+}
+
+// auditSink receives the records of the audit log
+type auditSink interface {
+	Name() string
+	Export(ctx context.Context, records []AuditRecord) error
+}
+
+// auditLog hash-chains the infringements and penalties and exports them to the sinks periodically
+type auditLog struct {
+	sinks    []auditSink
+	interval time.Duration
+	metrics  *metrics
+	now      func() time.Time
+
+	mu       sync.Mutex
+	chain    string
+	seq      uint64
+	prevHash string
+	// pending are the records we have yet to export, per sink
+	pending [][]AuditRecord
+}
+
+func newAuditLog(cfg config.AuditLog, m *metrics) (*auditLog, error) {
+	var sinks []auditSink
+	if cfg.Webhook != nil {
+		s, err := newWebhookAuditSink(*cfg.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	if cfg.Bucket != nil {
+		s, err := newBucketAuditSink(*cfg.Bucket)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	if cfg.Syslog != nil {
+		s, err := newSyslogAuditSink(*cfg.Syslog)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	if len(sinks) == 0 {
+		return nil, xerrors.Errorf("audit log has no sink")
+	}
+
+	interval := time.Duration(cfg.FlushInterval)
+	if interval == 0 {
+		interval = defaultAuditLogFlushInterval
+	}
+	return newAuditLogWithSinks(sinks, interval, m)
+}
+
+func newAuditLogWithSinks(sinks []auditSink, interval time.Duration, m *metrics) (*auditLog, error) {
+	chain := make([]byte, 8)
+	_, err := rand.Read(chain)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot produce audit log chain ID: %w", err)
+	}
+
+	return &auditLog{
+		sinks:    sinks,
+		interval: interval,
+		metrics:  m,
+		now:      time.Now,
+		chain:    hex.EncodeToString(chain),
+		pending:  make([][]AuditRecord, len(sinks)),
+	}, nil
+}
+
+// Record adds the infringements of a workspace and the penalties we applied for them to the audit log
+func (a *auditLog) Record(ws InfringingWorkspace, penalties []config.PenaltyKind, perr error) {
+	if a == nil {
+		return
+	}
+
+	rec := AuditRecord{
+		Chain:         a.chain,
+		Time:          a.now().UTC(),
+		Owner:         ws.Owner,
+		WorkspaceID:   ws.WorkspaceID,
+		InstanceID:    ws.InstanceID,
+		GitRemoteURL:  ws.GitRemoteURL,
+		Infringements: make([]AuditInfringement, 0, len(ws.Infringements)),
+		Penalties:     penalties,
+	}
+	for _, inf := range ws.Infringements {
+		rec.Infringements = append(rec.Infringements, AuditInfringement{Kind: inf.Kind, Description: inf.Description, AuditOnly: inf.AuditOnly})
+	}
+	if perr != nil {
+		rec.Error = perr.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rec.Sequence = a.seq
+	rec.PrevHash = a.prevHash
+	hash, err := rec.computeHash()
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI(ws.Owner, ws.WorkspaceID, ws.InstanceID)).Error("cannot add infringement to audit log")
+		return
+	}
+	rec.Hash = hash
+	a.seq++
+	a.prevHash = hash
+
+	for i := range a.pending {
+		a.pending[i] = a.appendPending(a.sinks[i], a.pending[i], rec)
+	}
+}
+
+// appendPending adds records to the pending ones of a sink, dropping the oldest ones if there are too many. Must be called with mu held.
+func (a *auditLog) appendPending(sink auditSink, pending []AuditRecord, recs ...AuditRecord) []AuditRecord {
+	pending = append(pending, recs...)
+	if drop := len(pending) - maxPendingAuditRecords; drop > 0 {
+		log.WithField("sink", sink.Name()).WithField("records", drop).Warn("audit log sink is unavailable for too long - dropping records")
+		if a.metrics != nil {
+			a.metrics.auditLogDrops.WithLabelValues(sink.Name()).Add(float64(drop))
+		}
+		pending = pending[drop:]
+	}
+	return pending
+}
+
+// Run exports the audit log periodically until ctx is canceled
+func (a *auditLog) Run(ctx context.Context) {
+	t := time.NewTicker(a.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// export what we have before we go
+			fctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			a.Flush(fctx)
+			cancel()
+			return
+		case <-t.C:
+			a.Flush(ctx)
+		}
+	}
+}
+
+// Flush exports the pending records to all sinks. Records a sink fails to receive are retried on the next flush,
+// hence a sink may receive a record twice.
+func (a *auditLog) Flush(ctx context.Context) {
+	for i, sink := range a.sinks {
+		a.mu.Lock()
+		recs := a.pending[i]
+		a.pending[i] = nil
+		a.mu.Unlock()
+		if len(recs) == 0 {
+			continue
+		}
+
+		err := sink.Export(ctx, recs)
+		if err != nil {
+			log.WithError(err).WithField("sink", sink.Name()).WithField("records", len(recs)).Warn("cannot export audit log - will retry")
+			if a.metrics != nil {
+				a.metrics.auditLogExports.WithLabelValues(sink.Name(), "error").Inc()
+			}
+
+			a.mu.Lock()
+			a.pending[i] = a.appendPending(sink, recs, a.pending[i]...)
+			a.mu.Unlock()
+			continue
+		}
+		if a.metrics != nil {
+			a.metrics.auditLogExports.WithLabelValues(sink.Name(), "success").Inc()
+		}
+	}
+}
+
+type webhookAuditSink struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+func newWebhookAuditSink(cfg config.AuditLogWebhook) (*webhookAuditSink, error) {
+	if cfg.URL == "" {
+		return nil, xerrors.Errorf("audit log webhook has no URL")
+	}
+	res := &webhookAuditSink{
+		URL:    cfg.URL,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+	if cfg.TokenFile != "" {
+		token, err := readSecretFile(cfg.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		res.Token = token
+	}
+	return res, nil
+}
+
+func (s *webhookAuditSink) Name() string { return "webhook" }
+
+func (s *webhookAuditSink) Export(ctx context.Context, records []AuditRecord) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return xerrors.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+type bucketAuditSink struct {
+	Client *minio.Client
+	Bucket string
+	Prefix string
+}
+
+func newBucketAuditSink(cfg config.AuditLogBucket) (*bucketAuditSink, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, xerrors.Errorf("audit log bucket needs an endpoint and a bucket")
+	}
+	accessKey, err := readSecretFile(cfg.AccessKeyIDFile)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := readSecretFile(cfg.SecretAccessKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: cfg.Secure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create audit log bucket client: %w", err)
+	}
+	return &bucketAuditSink{Client: client, Bucket: cfg.Bucket, Prefix: cfg.Prefix}, nil
+}
+
+func (s *bucketAuditSink) Name() string { return "bucket" }
+
+func (s *bucketAuditSink) Export(ctx context.Context, records []AuditRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		err := enc.Encode(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	// zero-padded sequence numbers make the objects of a chain sort in order
+	name := path.Join(s.Prefix, records[0].Chain, fmt.Sprintf("%020d.jsonl", records[0].Sequence))
+	_, err := s.Client.PutObject(ctx, s.Bucket, name, &buf, int64(buf.Len()), minio.PutObjectOptions{ContentType: "application/x-ndjson"})
+	return err
+}
+
+type syslogAuditSink struct {
+	Writer *syslog.Writer
+}
+
+func newSyslogAuditSink(cfg config.AuditLogSyslog) (*syslogAuditSink, error) {
+	tag := cfg.Tag
+	if tag == "" {
+		tag = "agent-smith"
+	}
+	w, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_NOTICE|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot connect to syslog: %w", err)
+	}
+	return &syslogAuditSink{Writer: w}, nil
+}
+
+func (s *syslogAuditSink) Name() string { return "syslog" }
+
+func (s *syslogAuditSink) Export(ctx context.Context, records []AuditRecord) error {
+	for i, r := range records {
+		msg, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		err = s.Writer.Notice(string(msg))
+		if err != nil {
+			return xerrors.Errorf("cannot write record %d of %d: %w", i, len(records), err)
+		}
+	}
+	return nil
+}
+
+func readSecretFile(fn string) (string, error) {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return "", xerrors.Errorf("cannot read secret: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
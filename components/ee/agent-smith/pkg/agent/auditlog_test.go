@@ -0,0 +1,187 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+type memoryAuditSink struct {
+	Fail    bool
+	Records []AuditRecord
+}
+
+func (s *memoryAuditSink) Name() string { return "memory" }
+
+func (s *memoryAuditSink) Export(ctx context.Context, records []AuditRecord) error {
+	if s.Fail {
+		return errors.New("unavailable")
+	}
+	s.Records = append(s.Records, records...)
+	return nil
+}
+
+func TestAuditLog(t *testing.T) {
+	var (
+		good  = &memoryAuditSink{}
+		flaky = &memoryAuditSink{Fail: true}
+	)
+	al, err := newAuditLogWithSinks([]auditSink{good, flaky}, time.Second, newAgentMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.now = func() time.Time { return time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	inf := Infringement{Kind: config.GradeKind(config.InfringementExec, common.SeverityVery), Description: "composite.signature: matches xmrig"}
+	al.Record(InfringingWorkspace{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{inf}}, []config.PenaltyKind{config.PenaltyStopWorkspace}, nil)
+	al.Record(InfringingWorkspace{Owner: "bob", InstanceID: "ws2", Infringements: []Infringement{inf}}, []config.PenaltyKind{config.PenaltyStopWorkspace}, errors.New("not connected to Kubernetes"))
+	al.Flush(context.Background())
+
+	flaky.Fail = false
+	al.Record(InfringingWorkspace{Owner: "alice", InstanceID: "ws3", Infringements: []Infringement{inf}}, nil, nil)
+	al.Flush(context.Background())
+
+	if diff := cmp.Diff(good.Records, flaky.Records); diff != "" {
+		t.Errorf("sinks received different records (-good +flaky):\n%s", diff)
+	}
+
+	type Expectation struct {
+		Sequence []uint64
+		Owner    []string
+		Error    []string
+	}
+	var act Expectation
+	for _, r := range good.Records {
+		act.Sequence = append(act.Sequence, r.Sequence)
+		act.Owner = append(act.Owner, r.Owner)
+		act.Error = append(act.Error, r.Error)
+	}
+	exp := Expectation{
+		Sequence: []uint64{0, 1, 2},
+		Owner:    []string{"alice", "bob", "alice"},
+		Error:    []string{"", "not connected to Kubernetes", ""},
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	for _, r := range good.Records {
+		json.NewEncoder(&buf).Encode(r)
+	}
+	n, err := VerifyAuditLog(&buf)
+	if err != nil {
+		t.Errorf("cannot verify audit log: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 verified records, got %d", n)
+	}
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	al, err := newAuditLogWithSinks([]auditSink{&memoryAuditSink{}}, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, owner := range []string{"alice", "bob", "carol"} {
+		al.Record(InfringingWorkspace{Owner: owner, Infringements: []Infringement{{Kind: config.GradeKind(config.InfringementExec, common.SeverityVery)}}}, nil, nil)
+	}
+	records := al.pending[0]
+	chain := records[0].Chain
+
+	tests := []struct {
+		Name        string
+		Modify      func(recs []AuditRecord) []AuditRecord
+		Expectation string
+	}{
+		{
+			Name:   "unmodified",
+			Modify: func(recs []AuditRecord) []AuditRecord { return recs },
+		},
+		{
+			Name:   "repeated record",
+			Modify: func(recs []AuditRecord) []AuditRecord { return []AuditRecord{recs[0], recs[1], recs[1], recs[2]} },
+		},
+		{
+			Name: "modified record",
+			Modify: func(recs []AuditRecord) []AuditRecord {
+				recs[1].Owner = "mallory"
+				return recs
+			},
+			Expectation: "chain " + chain + ": record 1 was modified",
+		},
+		{
+			Name:        "missing record",
+			Modify:      func(recs []AuditRecord) []AuditRecord { return []AuditRecord{recs[0], recs[2]} },
+			Expectation: "chain " + chain + ": records 1 to 1 are missing",
+		},
+		{
+			Name: "replaced record",
+			Modify: func(recs []AuditRecord) []AuditRecord {
+				recs[1].PrevHash = ""
+				recs[1].Hash, _ = recs[1].computeHash()
+				return recs
+			},
+			Expectation: "chain " + chain + ": record 1 does not follow record 0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			recs := test.Modify(append([]AuditRecord{}, records...))
+			var buf bytes.Buffer
+			for _, r := range recs {
+				json.NewEncoder(&buf).Encode(r)
+			}
+
+			var act string
+			_, err := VerifyAuditLog(&buf)
+			if err != nil {
+				act = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected verification result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWebhookAuditSink(t *testing.T) {
+	var (
+		auth    string
+		records []AuditRecord
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		err := json.NewDecoder(r.Body).Decode(&records)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	sink := &webhookAuditSink{URL: srv.URL, Token: "secret", Client: srv.Client()}
+	exp := []AuditRecord{{Chain: "c", Sequence: 1, Owner: "alice", Infringements: []AuditInfringement{{Kind: "very blocklisted executable"}}, Hash: "abc"}}
+	err := sink.Export(context.Background(), exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("unexpected authorization header %q", auth)
+	}
+	if diff := cmp.Diff(exp, records); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+}
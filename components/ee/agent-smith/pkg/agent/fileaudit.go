@@ -0,0 +1,166 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/fileaudit"
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// defaultCorrelationWindow is the time during which we correlate file modifications with other infringements
+	defaultCorrelationWindow = 10 * time.Minute
+	// maxFileAuditsPerWorkspace is the number of recent file modifications we hold on to per workspace
+	maxFileAuditsPerWorkspace = 20
+)
+
+// fileAudits holds on to the recent modifications of files in sensitive paths per workspace instance,
+// so that we can attach them to other infringements of the same workspace.
+type fileAudits struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	events map[string][]fileaudit.Event
+}
+
+func newFileAudits(window time.Duration) *fileAudits {
+	if window == 0 {
+		window = defaultCorrelationWindow
+	}
+	return &fileAudits{
+		window: window,
+		events: make(map[string][]fileaudit.Event),
+	}
+}
+
+// Add records a file modification and forgets those which are too old to be correlated
+func (f *fileAudits) Add(evt fileaudit.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	evts := append(f.events[evt.InstanceID], evt)
+	if len(evts) > maxFileAuditsPerWorkspace {
+		evts = evts[len(evts)-maxFileAuditsPerWorkspace:]
+	}
+	f.events[evt.InstanceID] = evts
+
+	for id := range f.events {
+		f.prune(id, evt.Time)
+	}
+}
+
+// Recent returns the file modifications of a workspace instance within the correlation window
+func (f *fileAudits) Recent(instanceID string, now time.Time) []fileaudit.Event {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prune(instanceID, now)
+	return f.events[instanceID]
+}
+
+// prune must be called with mu held
+func (f *fileAudits) prune(instanceID string, now time.Time) {
+	evts := f.events[instanceID]
+	for len(evts) > 0 && now.Sub(evts[0].Time) > f.window {
+		evts = evts[1:]
+	}
+	if len(evts) == 0 {
+		delete(f.events, instanceID)
+		return
+	}
+	f.events[instanceID] = evts
+}
+
+// receiveFileAudits accepts connections from ws-daemon on the file audit socket until the context is canceled
+func (agent *Smith) receiveFileAudits(ctx context.Context) {
+	socket := agent.Config.FileAudit.Socket
+
+	err := os.MkdirAll(filepath.Dir(socket), 0755)
+	if err != nil {
+		log.WithError(err).Error("cannot create file audit socket directory, file audits are not available")
+		return
+	}
+	// a previous agent smith may have left its socket behind
+	_ = os.Remove(socket)
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		log.WithError(err).Error("cannot listen on file audit socket, file audits are not available")
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	log.WithField("socket", socket).Info("receiving file audits")
+	for {
+		conn, err := l.Accept()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.WithError(err).Warn("cannot accept file audit connection")
+			continue
+		}
+		go agent.readFileAudits(conn)
+	}
+}
+
+// readFileAudits reads the file modifications ws-daemon ships, one JSON object per line, until the connection closes
+func (agent *Smith) readFileAudits(conn net.Conn) {
+	defer conn.Close()
+
+	dec := json.NewDecoder(conn)
+	for {
+		var evt fileaudit.Event
+		err := dec.Decode(&evt)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.WithError(err).Warn("cannot read file audit event")
+			return
+		}
+
+		agent.metrics.fileAuditEvents.Inc()
+		log.WithFields(log.OWI(evt.OwnerID, evt.WorkspaceID, evt.InstanceID)).WithField("path", evt.Path).WithField("pid", evt.PID).Info("file in sensitive path was modified")
+		agent.fileAudits.Add(evt)
+	}
+}
+
+// correlateFileAudits adds the recent modifications of files in sensitive paths of a workspace to the description of
+// its other infringements. Such modifications are common (dotfiles, git config, prompt tools) and must not make
+// an infringement more severe on their own, which is why they serve as evidence only.
+func (agent *Smith) correlateFileAudits(instanceID string, infringements []Infringement) {
+	evts := agent.fileAudits.Recent(instanceID, time.Now())
+	if len(evts) == 0 {
+		return
+	}
+
+	paths := make([]string, len(evts))
+	for i, evt := range evts {
+		paths[i] = evt.Path
+	}
+	evidence := fmt.Sprintf("recently modified %s", strings.Join(paths, ", "))
+	for i := range infringements {
+		infringements[i].Description = fmt.Sprintf("%s (%s)", infringements[i].Description, evidence)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/fileaudit"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileAudits(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	evt := func(instanceID, path string, dt time.Duration) fileaudit.Event {
+		return fileaudit.Event{InstanceID: instanceID, Path: path, Time: t0.Add(dt)}
+	}
+
+	tests := []struct {
+		Name        string
+		Events      []fileaudit.Event
+		InstanceID  string
+		Now         time.Time
+		Expectation []fileaudit.Event
+	}{
+		{
+			Name:       "no events",
+			InstanceID: "foo",
+			Now:        t0,
+		},
+		{
+			Name: "recent events of the instance",
+			Events: []fileaudit.Event{
+				evt("foo", "/home/gitpod/.ssh/authorized_keys", 0),
+				evt("bar", "/home/gitpod/.bashrc", 1*time.Minute),
+				evt("foo", "/workspace/gitpod/.git/hooks/pre-commit", 2*time.Minute),
+			},
+			InstanceID: "foo",
+			Now:        t0.Add(5 * time.Minute),
+			Expectation: []fileaudit.Event{
+				evt("foo", "/home/gitpod/.ssh/authorized_keys", 0),
+				evt("foo", "/workspace/gitpod/.git/hooks/pre-commit", 2*time.Minute),
+			},
+		},
+		{
+			Name: "events outside the correlation window",
+			Events: []fileaudit.Event{
+				evt("foo", "/home/gitpod/.ssh/authorized_keys", 0),
+				evt("foo", "/workspace/gitpod/.git/hooks/pre-commit", 5*time.Minute),
+			},
+			InstanceID: "foo",
+			Now:        t0.Add(12 * time.Minute),
+			Expectation: []fileaudit.Event{
+				evt("foo", "/workspace/gitpod/.git/hooks/pre-commit", 5*time.Minute),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			audits := newFileAudits(0)
+			for _, e := range test.Events {
+				audits.Add(e)
+			}
+
+			act := audits.Recent(test.InstanceID, test.Now)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileAuditsLimit(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	audits := newFileAudits(0)
+	for i := 0; i < 2*maxFileAuditsPerWorkspace; i++ {
+		audits.Add(fileaudit.Event{InstanceID: "foo", Time: t0.Add(time.Duration(i) * time.Second)})
+	}
+
+	act := audits.Recent("foo", t0)
+	if len(act) != maxFileAuditsPerWorkspace {
+		t.Errorf("unexpected number of events: expected %d, got %d", maxFileAuditsPerWorkspace, len(act))
+	}
+}
+
+func TestCorrelateFileAudits(t *testing.T) {
+	agent := &Smith{fileAudits: newFileAudits(0)}
+	agent.fileAudits.Add(fileaudit.Event{InstanceID: "foo", Path: "/workspace/.bashrc", Time: time.Now()})
+
+	infringements := []Infringement{
+		{Kind: config.GradeKind(config.InfringementExec, common.SeverityBarely), Description: "miner: xmrig"},
+	}
+	agent.correlateFileAudits("foo", infringements)
+	agent.correlateFileAudits("bar", infringements)
+
+	expected := []Infringement{
+		{Kind: config.GradeKind(config.InfringementExec, common.SeverityBarely), Description: "miner: xmrig (recently modified /workspace/.bashrc)"},
+	}
+	if diff := cmp.Diff(expected, infringements); diff != "" {
+		t.Errorf("unexpected infringements (-want +got):\n%s", diff)
+	}
+}
@@ -7,6 +7,7 @@ package agent
 import (
 	"sync"
 
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
 	"github.com/gitpod-io/gitpod/agent-smith/pkg/detector"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -17,6 +18,20 @@ type metrics struct {
 	classificationBackpressureInCount  prometheus.GaugeFunc
 	classificationBackpressureOutCount prometheus.GaugeFunc
 	classificationBackpressureInDrop   prometheus.Counter
+	fileAuditEvents                    prometheus.Counter
+	signatureFeedUpdates               *prometheus.CounterVec
+	signatureFeedVersion               *prometheus.GaugeVec
+	networkAnomalies                   *prometheus.CounterVec
+	penaltyDryRuns                     *prometheus.CounterVec
+	auditLogExports                    *prometheus.CounterVec
+	auditLogDrops                      *prometheus.CounterVec
+	allowlistUpdates                   *prometheus.CounterVec
+	allowlistExemptions                *prometheus.CounterVec
+	minerDetections                    *prometheus.CounterVec
+	minerScanDuration                  prometheus.Histogram
+	processes                          *prometheus.CounterVec
+	classificationDuration             prometheus.Histogram
+	infringements                      *prometheus.CounterVec
 
 	mu sync.RWMutex
 	cl []prometheus.Collector
@@ -47,14 +62,122 @@ func newAgentMetrics() *metrics {
 		Name:      "classification_backpressure_in_drop_total",
 		Help:      "total count of processes that went unclassified because of backpressure",
 	})
+	m.fileAuditEvents = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "file_audit_events_total",
+		Help:      "total count of file modifications in sensitive paths of workspaces ws-daemon reported",
+	})
+	m.signatureFeedUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "signature_feed_updates_total",
+		Help:      "total count of signature feed polls by result",
+	}, []string{"result"})
+	m.signatureFeedVersion = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "signature_feed_version",
+		Help:      "version of the signature feed currently in use",
+	}, []string{"version"})
+	m.networkAnomalies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "network_anomalies_total",
+		Help:      "total count of anomalous connection patterns of workspaces",
+	}, []string{"anomaly"})
+	m.penaltyDryRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "penalty_dry_runs_total",
+		Help:      "total count of penalties a policy in dry-run mode would have applied",
+	}, []string{"policy", "penalty"})
+	m.auditLogExports = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "audit_log_exports_total",
+		Help:      "total count of audit log exports by sink and result",
+	}, []string{"sink", "result"})
+	m.auditLogDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "audit_log_dropped_records_total",
+		Help:      "total count of audit log records we dropped because a sink was unavailable for too long",
+	}, []string{"sink"})
+	m.allowlistUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "runtime_allowlist_updates_total",
+		Help:      "total count of runtime allow-list polls by result",
+	}, []string{"result"})
+	m.allowlistExemptions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "runtime_allowlist_exemptions_total",
+		Help:      "total count of infringements we did not penalise because of the runtime allow-list",
+	}, []string{"list"})
+	m.minerDetections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "miner_detections_total",
+		Help:      "total count of workspaces whose processes behave like a cryptominer by severity",
+	}, []string{"severity"})
+	m.minerScanDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "miner_scan_duration_seconds",
+		Help:      "time it takes to sample the CPU usage of all processes of workspaces",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
+	m.processes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "processes_total",
+		Help:      "total count of processes the process detector reported by what we did with them",
+	}, []string{"result"})
+	m.classificationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "classification_duration_seconds",
+		Help:      "time it takes to classify a process",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	})
+	m.infringements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitpod",
+		Subsystem: "agent_smith",
+		Name:      "infringements_total",
+		Help:      "total count of infringements by kind and severity",
+	}, []string{"kind", "severity"})
 	m.cl = []prometheus.Collector{
 		m.penaltyAttempts,
 		m.penaltyFailures,
 		m.classificationBackpressureInDrop,
+		m.fileAuditEvents,
+		m.signatureFeedUpdates,
+		m.signatureFeedVersion,
+		m.networkAnomalies,
+		m.penaltyDryRuns,
+		m.auditLogExports,
+		m.auditLogDrops,
+		m.allowlistUpdates,
+		m.allowlistExemptions,
+		m.minerDetections,
+		m.minerScanDuration,
+		m.processes,
+		m.classificationDuration,
+		m.infringements,
 	}
 	return m
 }
 
+// severityLabel returns the label of a severity in our metrics, where audit stands for the empty common.SeverityAudit
+func severityLabel(s common.Severity) string {
+	if s == common.SeverityAudit {
+		return "audit"
+	}
+	return string(s)
+}
+
 func (m *metrics) RegisterClassificationQueues(in chan detector.Process, out chan classifiedProcess) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
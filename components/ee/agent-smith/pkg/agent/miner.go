@@ -0,0 +1,342 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/detector"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultMinerInterval        = 30 * time.Second
+	defaultMinerSustainedFor    = 10 * time.Minute
+	defaultMinerCoreThreshold   = 0.9
+	defaultMinerMinThreads      = 2
+	defaultMinerBusyThreadRatio = 0.8
+	defaultMinerAuditConfidence = 0.5
+	defaultMinerVeryConfidence  = 0.8
+
+	// minerWeightCPU, minerWeightThreads and minerWeightStratum are what each signal contributes to the confidence
+	minerWeightCPU     = 0.4
+	minerWeightThreads = 0.2
+	minerWeightStratum = 0.4
+
+	// clockTicksPerSecond is USER_HZ, the unit of the CPU times in /proc/<pid>/stat
+	clockTicksPerSecond = 100
+)
+
+var defaultStratumPorts = []int{3333, 4444, 5555, 7777, 14444}
+
+// procStat is the part of /proc/<pid>/stat we're interested in
+type procStat struct {
+	Comm string
+	// CPUTicks is the time the process was scheduled in user and kernel mode
+	CPUTicks  uint64
+	Threads   int
+	Starttime uint64
+}
+
+// minerDetection scores the processes of workspaces by how much they behave like a cryptominer
+type minerDetection struct {
+	Config config.MinerDetection
+
+	readStat func(pid int) (*procStat, error)
+	metrics  *metrics
+
+	mu    sync.Mutex
+	procs map[int]*minerProcess
+	// stratum is the time of the last connection to a stratum port per workspace instance
+	stratum map[string]time.Time
+	// reported is what we last reported per workspace instance, so that we report once per SustainedFor only
+	reported map[string]reportedMiner
+}
+
+type minerProcess struct {
+	Workspace *common.Workspace
+	Starttime uint64
+
+	Sampled    bool
+	LastSample time.Time
+	LastTicks  uint64
+	// BusySince is the time since which the process uses CoreThreshold cores, or zero if it doesn't
+	BusySince time.Time
+}
+
+type reportedMiner struct {
+	Time     time.Time
+	Severity common.Severity
+}
+
+// minerFinding is the most miner-like process of a workspace
+type minerFinding struct {
+	Workspace   *common.Workspace
+	Confidence  float64
+	Description string
+}
+
+func newMinerDetection(cfg config.MinerDetection, m *metrics) (*minerDetection, error) {
+	if cfg.Interval == 0 {
+		cfg.Interval = util.Duration(defaultMinerInterval)
+	}
+	if cfg.SustainedFor == 0 {
+		cfg.SustainedFor = util.Duration(defaultMinerSustainedFor)
+	}
+	if cfg.CoreThreshold == 0 {
+		cfg.CoreThreshold = defaultMinerCoreThreshold
+	}
+	if cfg.MinThreads == 0 {
+		cfg.MinThreads = defaultMinerMinThreads
+	}
+	if cfg.BusyThreadRatio == 0 {
+		cfg.BusyThreadRatio = defaultMinerBusyThreadRatio
+	}
+	if len(cfg.StratumPorts) == 0 {
+		cfg.StratumPorts = defaultStratumPorts
+	}
+	if cfg.AuditConfidence == 0 {
+		cfg.AuditConfidence = defaultMinerAuditConfidence
+	}
+	if cfg.VeryConfidence == 0 {
+		cfg.VeryConfidence = defaultMinerVeryConfidence
+	}
+
+	for _, port := range cfg.StratumPorts {
+		if port <= 0 || port > 65535 {
+			return nil, xerrors.Errorf("miner detection: invalid stratum port %d", port)
+		}
+	}
+	if cfg.AuditConfidence > cfg.VeryConfidence || cfg.VeryConfidence > 1 {
+		return nil, xerrors.Errorf("miner detection: confidences must satisfy auditConfidence <= veryConfidence <= 1")
+	}
+
+	return &minerDetection{
+		Config:   cfg,
+		readStat: readProcStat,
+		metrics:  m,
+		procs:    make(map[int]*minerProcess),
+		stratum:  make(map[string]time.Time),
+		reported: make(map[string]reportedMiner),
+	}, nil
+}
+
+// Track starts sampling the CPU usage of a process of a workspace
+func (d *minerDetection) Track(p detector.Process) {
+	if d == nil || p.PID == 0 || p.Workspace == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if mp, ok := d.procs[p.PID]; ok && mp.Workspace.InstanceID == p.Workspace.InstanceID {
+		return
+	}
+	d.procs[p.PID] = &minerProcess{Workspace: p.Workspace}
+}
+
+// ObserveConnection records connections of workspaces to stratum ports
+func (d *minerDetection) ObserveConnection(c detector.Connection) {
+	if d == nil || c.Workspace == nil || !containsPort(d.Config.StratumPorts, c.Port) {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stratum[c.Workspace.InstanceID] = c.Time
+}
+
+// Sample samples the CPU usage of all tracked processes and returns the workspaces whose most miner-like
+// process is confident enough a miner
+func (d *minerDetection) Sample(now time.Time) []InfringingWorkspace {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sustainedFor := time.Duration(d.Config.SustainedFor)
+	for id, t := range d.stratum {
+		if now.Sub(t) > sustainedFor {
+			delete(d.stratum, id)
+		}
+	}
+	for id, r := range d.reported {
+		if now.Sub(r.Time) > sustainedFor {
+			delete(d.reported, id)
+		}
+	}
+
+	findings := make(map[string]minerFinding)
+	for pid, p := range d.procs {
+		st, err := d.readStat(pid)
+		if err != nil {
+			// the process is gone
+			delete(d.procs, pid)
+			continue
+		}
+		if p.Sampled && st.Starttime != p.Starttime {
+			// the PID was reused - the detector reports the new process if it's part of a workspace
+			delete(d.procs, pid)
+			continue
+		}
+		if !p.Sampled {
+			p.Sampled, p.Starttime, p.LastSample, p.LastTicks = true, st.Starttime, now, st.CPUTicks
+			continue
+		}
+
+		f := d.score(p, st, now)
+		if f.Confidence > findings[p.Workspace.InstanceID].Confidence {
+			findings[p.Workspace.InstanceID] = f
+		}
+	}
+
+	ids := make([]string, 0, len(findings))
+	for id := range findings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var res []InfringingWorkspace
+	for _, id := range ids {
+		f := findings[id]
+		var severity common.Severity
+		switch {
+		case f.Confidence >= d.Config.VeryConfidence:
+			severity = common.SeverityVery
+		case f.Confidence >= d.Config.AuditConfidence:
+			severity = common.SeverityAudit
+		default:
+			continue
+		}
+		if r, ok := d.reported[id]; ok && (r.Severity == common.SeverityVery || severity == common.SeverityAudit) {
+			continue
+		}
+		d.reported[id] = reportedMiner{Time: now, Severity: severity}
+
+		if d.metrics != nil {
+			d.metrics.minerDetections.WithLabelValues(severityLabel(severity)).Inc()
+		}
+		res = append(res, InfringingWorkspace{
+			SupervisorPID: f.Workspace.PID,
+			Owner:         f.Workspace.OwnerID,
+			InstanceID:    f.Workspace.InstanceID,
+			WorkspaceID:   f.Workspace.WorkspaceID,
+			GitRemoteURL:  []string{f.Workspace.GitURL},
+			Infringements: []Infringement{{
+				Kind:        config.GradeKind(config.InfringementMinerBehaviour, severity),
+				Description: f.Description,
+			}},
+		})
+	}
+	return res
+}
+
+// score updates the CPU usage of a process and computes how confident we are it's a miner. Must be called with mu held.
+func (d *minerDetection) score(p *minerProcess, st *procStat, now time.Time) minerFinding {
+	var cores float64
+	if dt := now.Sub(p.LastSample).Seconds(); dt > 0 && st.CPUTicks >= p.LastTicks {
+		cores = float64(st.CPUTicks-p.LastTicks) / clockTicksPerSecond / dt
+	}
+	if cores >= d.Config.CoreThreshold {
+		if p.BusySince.IsZero() {
+			p.BusySince = p.LastSample
+		}
+	} else {
+		p.BusySince = time.Time{}
+	}
+	p.LastSample, p.LastTicks = now, st.CPUTicks
+
+	var (
+		confidence float64
+		signals    []string
+	)
+	if !p.BusySince.IsZero() && now.Sub(p.BusySince) >= time.Duration(d.Config.SustainedFor) {
+		confidence += minerWeightCPU
+		signals = append(signals, fmt.Sprintf("used %.1f cores for %s", cores, now.Sub(p.BusySince).Round(time.Second)))
+	}
+	if st.Threads >= d.Config.MinThreads && cores/float64(st.Threads) >= d.Config.BusyThreadRatio {
+		confidence += minerWeightThreads
+		signals = append(signals, fmt.Sprintf("kept all %d threads busy", st.Threads))
+	}
+	if _, ok := d.stratum[p.Workspace.InstanceID]; ok {
+		confidence += minerWeightStratum
+		signals = append(signals, "its workspace connected to stratum ports")
+	}
+
+	return minerFinding{
+		Workspace:   p.Workspace,
+		Confidence:  confidence,
+		Description: fmt.Sprintf("%s %s - miner confidence %.2f", st.Comm, strings.Join(signals, ", "), confidence),
+	}
+}
+
+func readProcStat(pid int) (*procStat, error) {
+	c, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	return parseProcStat(c)
+}
+
+func parseProcStat(c []byte) (*procStat, error) {
+	// the comm may contain spaces and parentheses, hence we look for the last closing one
+	start, end := bytes.IndexByte(c, '('), bytes.LastIndexByte(c, ')')
+	if start < 0 || end < start {
+		return nil, xerrors.Errorf("cannot parse stat: no comm")
+	}
+	// fields[0] is the state, i.e. the third field of stat
+	fields := strings.Fields(string(c[end+1:]))
+	if len(fields) < 20 {
+		return nil, xerrors.Errorf("cannot parse stat: too few fields")
+	}
+
+	var (
+		res  = procStat{Comm: string(c[start+1 : end])}
+		vals [4]uint64
+	)
+	for i, idx := range []int{11, 12, 17, 19} {
+		v, err := strconv.ParseUint(fields[idx], 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse stat: %w", err)
+		}
+		vals[i] = v
+	}
+	res.CPUTicks = vals[0] + vals[1]
+	res.Threads = int(vals[2])
+	res.Starttime = vals[3]
+	return &res, nil
+}
+
+// watchMiners samples the processes of workspaces and raises infringements for those which behave like a miner until ctx is canceled
+func (agent *Smith) watchMiners(ctx context.Context, callback func(InfringingWorkspace, []config.PenaltyKind)) {
+	t := time.NewTicker(time.Duration(agent.miners.Config.Interval))
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		start := time.Now()
+		infringing := agent.miners.Sample(start)
+		agent.metrics.minerScanDuration.Observe(time.Since(start).Seconds())
+		for _, ws := range infringing {
+			agent.correlateFileAudits(ws.InstanceID, ws.Infringements)
+			penalties, _ := agent.Penalize(ws)
+			callback(ws, penalties)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/detector"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMinerDetection(t *testing.T) {
+	type fakeProc struct {
+		Comm    string
+		Cores   float64
+		Threads int
+		// GoneAfter is the sample after which the process exits, or zero if it never does
+		GoneAfter int
+	}
+	var (
+		start = time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+		procs = map[int]fakeProc{
+			10: {Comm: "kworker", Cores: 4, Threads: 4},
+			20: {Comm: "node", Cores: 2, Threads: 2},
+			30: {Comm: "go", Cores: 0.5, Threads: 8},
+			40: {Comm: "xmrig", Cores: 4, Threads: 4, GoneAfter: 1},
+		}
+		sample int
+	)
+
+	d, err := newMinerDetection(config.MinerDetection{
+		Interval:     util.Duration(time.Minute),
+		SustainedFor: util.Duration(3 * time.Minute),
+	}, newAgentMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.readStat = func(pid int) (*procStat, error) {
+		p := procs[pid]
+		if p.GoneAfter > 0 && sample > p.GoneAfter {
+			return nil, os.ErrNotExist
+		}
+		ticks := uint64(p.Cores * clockTicksPerSecond * 60 * float64(sample))
+		return &procStat{Comm: p.Comm, CPUTicks: ticks, Threads: p.Threads, Starttime: 1}, nil
+	}
+
+	for pid, ws := range map[int]string{10: "ws1", 20: "ws2", 30: "ws3", 40: "ws4"} {
+		d.Track(detector.Process{PID: pid, Workspace: &common.Workspace{InstanceID: ws, OwnerID: "owner-" + ws}})
+	}
+	d.ObserveConnection(detector.Connection{Port: 443, Time: start, Workspace: &common.Workspace{InstanceID: "ws2"}})
+	d.ObserveConnection(detector.Connection{Port: 3333, Time: start, Workspace: &common.Workspace{InstanceID: "ws1"}})
+
+	var act []string
+	for sample = 0; sample <= 5; sample++ {
+		for _, ws := range d.Sample(start.Add(time.Duration(sample) * time.Minute)) {
+			for _, inf := range ws.Infringements {
+				act = append(act, fmt.Sprintf("%d %s %s: %s", sample, ws.InstanceID, inf.Kind, inf.Description))
+			}
+		}
+	}
+
+	exp := []string{
+		"1 ws1 miner behaviour: kworker kept all 4 threads busy, its workspace connected to stratum ports - miner confidence 0.60",
+		"3 ws1 very miner behaviour: kworker used 4.0 cores for 3m0s, kept all 4 threads busy, its workspace connected to stratum ports - miner confidence 1.00",
+		"3 ws2 miner behaviour: node used 2.0 cores for 3m0s, kept all 2 threads busy - miner confidence 0.60",
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected infringements (-want +got):\n%s", diff)
+	}
+	if _, ok := d.procs[40]; ok {
+		t.Errorf("process 40 exited, but is still tracked")
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Input       string
+		Expectation *procStat
+		Error       string
+	}{
+		{
+			Name:        "valid",
+			Input:       "4242 (xmrig (1)) S 4200 4242 4200 0 -1 4194560 1066 0 0 0 12000 3000 0 0 20 0 5 0 987654 1183744 2000 18446744073709551615",
+			Expectation: &procStat{Comm: "xmrig (1)", CPUTicks: 15000, Threads: 5, Starttime: 987654},
+		},
+		{
+			Name:  "truncated",
+			Input: "4242 (xmrig) S 4200 4242",
+			Error: "cannot parse stat: too few fields",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := parseProcStat([]byte(test.Input))
+			var errmsg string
+			if err != nil {
+				errmsg = err.Error()
+			}
+			if diff := cmp.Diff(test.Error, errmsg); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected stat (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/detector"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultNetworkAnomalyWindow is the time over which we look at the connections of a workspace
+	defaultNetworkAnomalyWindow = 10 * time.Minute
+	// defaultMinFingerprintConnections is the number of connections matching a fingerprint which constitute an infringement
+	defaultMinFingerprintConnections = 3
+	// maxConnectionsPerWorkspace is the number of recent connections we hold on to per workspace
+	maxConnectionsPerWorkspace = 4096
+	// miningPoolResolveInterval is the time between two resolutions of the mining pool host names
+	miningPoolResolveInterval = 10 * time.Minute
+)
+
+const (
+	anomalyMiningPool  = "mining_pool"
+	anomalyFingerprint = "fingerprint"
+	anomalyFanOut      = "fan_out"
+)
+
+// networkAnomalies watches the connection patterns of workspaces
+type networkAnomalies struct {
+	Config config.NetworkAnomalies
+
+	window     time.Duration
+	minFPConns int
+	// poolNets are the mining pools configured as IP or CIDR
+	poolNets []*net.IPNet
+	// poolHosts are the mining pools configured as host name
+	poolHosts []string
+	lookupIP  func(ctx context.Context, host string) ([]net.IPAddr, error)
+	metrics   *metrics
+
+	mu sync.Mutex
+	// resolvedPools are the IPs the pool host names resolved to
+	resolvedPools map[string]string
+	workspaces    map[string]*workspaceConnections
+}
+
+type workspaceConnections struct {
+	Connections []detector.Connection
+	// Reported is the time we last reported an anomaly, so that we report each one once per window only
+	Reported map[string]time.Time
+}
+
+func newNetworkAnomalies(cfg config.NetworkAnomalies, m *metrics) (*networkAnomalies, error) {
+	res := &networkAnomalies{
+		Config:        cfg,
+		window:        time.Duration(cfg.Window),
+		minFPConns:    cfg.MinFingerprintConnections,
+		lookupIP:      net.DefaultResolver.LookupIPAddr,
+		metrics:       m,
+		resolvedPools: make(map[string]string),
+		workspaces:    make(map[string]*workspaceConnections),
+	}
+	if res.window == 0 {
+		res.window = defaultNetworkAnomalyWindow
+	}
+	if res.minFPConns == 0 {
+		res.minFPConns = defaultMinFingerprintConnections
+	}
+	for _, p := range cfg.MiningPools {
+		if ip := net.ParseIP(p); ip != nil {
+			bits := 8 * len(ip)
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			res.poolNets = append(res.poolNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		if strings.Contains(p, "/") {
+			_, n, err := net.ParseCIDR(p)
+			if err != nil {
+				return nil, xerrors.Errorf("invalid mining pool %s: %w", p, err)
+			}
+			res.poolNets = append(res.poolNets, n)
+			continue
+		}
+		res.poolHosts = append(res.poolHosts, p)
+	}
+	for proto, ports := range cfg.Fingerprints {
+		for _, port := range ports {
+			if port <= 0 || port > 65535 {
+				return nil, xerrors.Errorf("fingerprint %s: invalid port %d", proto, port)
+			}
+		}
+	}
+	return res, nil
+}
+
+// Run resolves the host names of the mining pools periodically until ctx is canceled
+func (n *networkAnomalies) Run(ctx context.Context) {
+	if len(n.poolHosts) == 0 {
+		return
+	}
+
+	t := time.NewTicker(miningPoolResolveInterval)
+	defer t.Stop()
+	for {
+		n.resolvePools(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (n *networkAnomalies) resolvePools(ctx context.Context) {
+	resolved := make(map[string]string)
+	for _, host := range n.poolHosts {
+		addrs, err := n.lookupIP(ctx, host)
+		if err != nil {
+			log.WithError(err).WithField("host", host).Debug("cannot resolve mining pool")
+			continue
+		}
+		for _, addr := range addrs {
+			resolved[addr.IP.String()] = host
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// pools which fail to resolve keep their previous addresses
+	for ip, host := range n.resolvedPools {
+		if _, ok := resolved[ip]; !ok && !containsValue(resolved, host) {
+			resolved[ip] = host
+		}
+	}
+	n.resolvedPools = resolved
+}
+
+func containsValue(m map[string]string, v string) bool {
+	for _, mv := range m {
+		if mv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Observe records a connection of a workspace and returns the infringements its recent connections constitute
+func (n *networkAnomalies) Observe(c detector.Connection) []Infringement {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.prune(c.Time)
+
+	id := c.Workspace.InstanceID
+	ws, ok := n.workspaces[id]
+	if !ok {
+		ws = &workspaceConnections{Reported: make(map[string]time.Time)}
+		n.workspaces[id] = ws
+	}
+	ws.Connections = append(ws.Connections, c)
+	if len(ws.Connections) > maxConnectionsPerWorkspace {
+		ws.Connections = ws.Connections[len(ws.Connections)-maxConnectionsPerWorkspace:]
+	}
+
+	var res []Infringement
+	report := func(anomaly string, severity common.Severity, description string) {
+		if t, ok := ws.Reported[anomaly]; ok && c.Time.Sub(t) < n.window {
+			return
+		}
+		ws.Reported[anomaly] = c.Time
+		if n.metrics != nil {
+			n.metrics.networkAnomalies.WithLabelValues(anomaly).Inc()
+		}
+		res = append(res, Infringement{
+			Kind:        config.GradeKind(config.InfringementNetworkAnomaly, severity),
+			Description: description,
+		})
+	}
+
+	dst := net.JoinHostPort(c.IP.String(), strconv.Itoa(c.Port))
+	if pool, ok := n.miningPool(c.IP); ok {
+		report(anomalyMiningPool, common.SeverityVery, fmt.Sprintf("%s connected to mining pool %s (%s)", c.Comm, pool, dst))
+	}
+
+	protos := make([]string, 0, len(n.Config.Fingerprints))
+	for proto := range n.Config.Fingerprints {
+		protos = append(protos, proto)
+	}
+	sort.Strings(protos)
+	for _, proto := range protos {
+		ports := n.Config.Fingerprints[proto]
+		if !containsPort(ports, c.Port) {
+			continue
+		}
+		var cnt int
+		for _, wc := range ws.Connections {
+			if containsPort(ports, wc.Port) {
+				cnt++
+			}
+		}
+		if cnt >= n.minFPConns {
+			report(anomalyFingerprint+"_"+proto, common.SeverityAudit, fmt.Sprintf("%d connections using %s ports, last by %s to %s", cnt, proto, c.Comm, dst))
+		}
+	}
+
+	if n.Config.MaxDestinations > 0 {
+		dsts := make(map[string]struct{})
+		for _, wc := range ws.Connections {
+			dsts[wc.IP.String()] = struct{}{}
+		}
+		if len(dsts) > n.Config.MaxDestinations {
+			report(anomalyFanOut, common.SeverityAudit, fmt.Sprintf("connected to %d distinct destinations within %s", len(dsts), n.window))
+		}
+	}
+
+	return res
+}
+
+// prune forgets connections which are too old to matter. Must be called with mu held.
+func (n *networkAnomalies) prune(now time.Time) {
+	for id, ws := range n.workspaces {
+		conns := ws.Connections
+		for len(conns) > 0 && now.Sub(conns[0].Time) > n.window {
+			conns = conns[1:]
+		}
+		ws.Connections = conns
+		for anomaly, t := range ws.Reported {
+			if now.Sub(t) > n.window {
+				delete(ws.Reported, anomaly)
+			}
+		}
+		if len(ws.Connections) == 0 && len(ws.Reported) == 0 {
+			delete(n.workspaces, id)
+		}
+	}
+}
+
+// miningPool returns the name of the mining pool ip belongs to. Must be called with mu held.
+func (n *networkAnomalies) miningPool(ip net.IP) (string, bool) {
+	if host, ok := n.resolvedPools[ip.String()]; ok {
+		return host, true
+	}
+	for _, pn := range n.poolNets {
+		if pn.Contains(ip) {
+			return pn.String(), true
+		}
+	}
+	return "", false
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// watchConnections raises infringements for anomalous connection patterns of workspaces until ctx is canceled
+func (agent *Smith) watchConnections(ctx context.Context, conns <-chan detector.Connection, callback func(InfringingWorkspace, []config.PenaltyKind)) {
+	for {
+		var (
+			c  detector.Connection
+			ok bool
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case c, ok = <-conns:
+			if !ok {
+				return
+			}
+		}
+
+		agent.miners.ObserveConnection(c)
+		if agent.networkAnomalies == nil {
+			continue
+		}
+		infringements := agent.networkAnomalies.Observe(c)
+		if len(infringements) == 0 {
+			continue
+		}
+		agent.correlateFileAudits(c.Workspace.InstanceID, infringements)
+
+		ws := InfringingWorkspace{
+			SupervisorPID: c.Workspace.PID,
+			Owner:         c.Workspace.OwnerID,
+			InstanceID:    c.Workspace.InstanceID,
+			WorkspaceID:   c.Workspace.WorkspaceID,
+			GitRemoteURL:  []string{c.Workspace.GitURL},
+			Infringements: infringements,
+		}
+		penalties, _ := agent.Penalize(ws)
+		callback(ws, penalties)
+	}
+}
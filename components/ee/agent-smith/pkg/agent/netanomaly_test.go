@@ -0,0 +1,159 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/detector"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNetworkAnomalies(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	ws1 := &common.Workspace{InstanceID: "ws1"}
+	ws2 := &common.Workspace{InstanceID: "ws2"}
+	ws3 := &common.Workspace{InstanceID: "ws3"}
+	conn := func(ws *common.Workspace, dst string, port int, dt time.Duration) detector.Connection {
+		return detector.Connection{IP: net.ParseIP(dst), Port: port, Comm: "xmrig", Workspace: ws, Time: t0.Add(dt)}
+	}
+	veryAnomaly := config.GradeKind(config.InfringementNetworkAnomaly, common.SeverityVery)
+	auditAnomaly := config.GradeKind(config.InfringementNetworkAnomaly, common.SeverityAudit)
+
+	cfg := config.NetworkAnomalies{
+		Window:          util.Duration(10 * time.Minute),
+		MaxDestinations: 3,
+		MiningPools:     []string{"203.0.113.7", "198.51.100.0/24", "pool.example.com"},
+		Fingerprints:    map[string][]int{"stratum": {3333, 4444}},
+	}
+
+	tests := []struct {
+		Name        string
+		Connections []detector.Connection
+		Expectation [][]Infringement
+	}{
+		{
+			Name:        "harmless",
+			Connections: []detector.Connection{conn(ws1, "140.82.121.4", 443, 0)},
+			Expectation: [][]Infringement{nil},
+		},
+		{
+			Name: "mining pools",
+			Connections: []detector.Connection{
+				conn(ws1, "203.0.113.7", 443, 0),
+				conn(ws2, "198.51.100.12", 443, 0),
+				conn(ws3, "192.0.2.1", 443, 0),
+			},
+			Expectation: [][]Infringement{
+				{{Kind: veryAnomaly, Description: "xmrig connected to mining pool 203.0.113.7/32 (203.0.113.7:443)"}},
+				{{Kind: veryAnomaly, Description: "xmrig connected to mining pool 198.51.100.0/24 (198.51.100.12:443)"}},
+				{{Kind: veryAnomaly, Description: "xmrig connected to mining pool pool.example.com (192.0.2.1:443)"}},
+			},
+		},
+		{
+			Name: "fingerprint",
+			Connections: []detector.Connection{
+				conn(ws1, "140.82.121.4", 3333, 0),
+				conn(ws1, "140.82.121.4", 4444, time.Minute),
+				conn(ws2, "140.82.121.4", 3333, time.Minute),
+				conn(ws1, "140.82.121.4", 3333, 2*time.Minute),
+			},
+			Expectation: [][]Infringement{
+				nil,
+				nil,
+				nil,
+				{{Kind: auditAnomaly, Description: "3 connections using stratum ports, last by xmrig to 140.82.121.4:3333"}},
+			},
+		},
+		{
+			Name: "fingerprint outside of window",
+			Connections: []detector.Connection{
+				conn(ws1, "140.82.121.4", 3333, 0),
+				conn(ws1, "140.82.121.4", 3333, time.Minute),
+				conn(ws1, "140.82.121.4", 3333, 12*time.Minute),
+			},
+			Expectation: [][]Infringement{nil, nil, nil},
+		},
+		{
+			Name: "fan-out reported once per window",
+			Connections: []detector.Connection{
+				conn(ws1, "10.0.0.1", 22, 0),
+				conn(ws1, "10.0.0.2", 22, 0),
+				conn(ws1, "10.0.0.3", 22, 0),
+				conn(ws1, "10.0.0.4", 22, 0),
+				conn(ws1, "10.0.0.5", 22, time.Minute),
+				conn(ws1, "10.0.0.6", 22, 11*time.Minute),
+				conn(ws1, "10.0.0.7", 22, 11*time.Minute),
+				conn(ws1, "10.0.0.8", 22, 11*time.Minute),
+				conn(ws1, "10.0.0.9", 22, 11*time.Minute),
+			},
+			Expectation: [][]Infringement{
+				nil,
+				nil,
+				nil,
+				{{Kind: auditAnomaly, Description: "connected to 4 distinct destinations within 10m0s"}},
+				nil,
+				nil,
+				nil,
+				{{Kind: auditAnomaly, Description: "connected to 4 distinct destinations within 10m0s"}},
+				nil,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			n, err := newNetworkAnomalies(cfg, newAgentMetrics())
+			if err != nil {
+				t.Fatal(err)
+			}
+			n.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+				if host != "pool.example.com" {
+					return nil, fmt.Errorf("unknown host %s", host)
+				}
+				return []net.IPAddr{{IP: net.ParseIP("192.0.2.1")}}, nil
+			}
+			n.resolvePools(context.Background())
+
+			var act [][]Infringement
+			for _, c := range test.Connections {
+				act = append(act, n.Observe(c))
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected infringements (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewNetworkAnomalies(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      config.NetworkAnomalies
+		Expectation string
+	}{
+		{Name: "valid", Config: config.NetworkAnomalies{MiningPools: []string{"::1", "10.0.0.0/8", "pool.example.com"}, Fingerprints: map[string][]int{"tor": {9001}}}},
+		{Name: "invalid CIDR", Config: config.NetworkAnomalies{MiningPools: []string{"10.0.0.0/33"}}, Expectation: "invalid mining pool 10.0.0.0/33: invalid CIDR address: 10.0.0.0/33"},
+		{Name: "invalid port", Config: config.NetworkAnomalies{Fingerprints: map[string][]int{"tor": {0}}}, Expectation: "fingerprint tor: invalid port 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act string
+			_, err := newNetworkAnomalies(test.Config, nil)
+			if err != nil {
+				act = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	slack "github.com/ashwanthkumar/slack-go-webhook"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	"k8s.io/utils/lru"
+)
+
+const (
+	// notificationQueueSize is the number of notifications we buffer while the channels are slow
+	notificationQueueSize = 100
+	// notificationTimeout is the time we give a channel to accept a notification
+	notificationTimeout = 30 * time.Second
+)
+
+// Notification describes an infringing workspace. Generic webhooks receive it as JSON.
+type Notification struct {
+	Time          time.Time            `json:"time"`
+	Region        string               `json:"region,omitempty"`
+	Owner         string               `json:"owner"`
+	WorkspaceID   string               `json:"workspaceID,omitempty"`
+	InstanceID    string               `json:"instanceID"`
+	Pod           string               `json:"pod,omitempty"`
+	GitRemoteURL  []string             `json:"gitRemoteURL,omitempty"`
+	Infringements []AuditInfringement  `json:"infringements"`
+	Penalties     []config.PenaltyKind `json:"penalties,omitempty"`
+
+	UserAdminURL      string `json:"userAdminURL,omitempty"`
+	WorkspaceAdminURL string `json:"workspaceAdminURL,omitempty"`
+	BlockUserURL      string `json:"blockUserURL,omitempty"`
+}
+
+// notificationChannel delivers notifications, e.g. to Slack
+type notificationChannel interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+type notificationTarget struct {
+	Channel    notificationChannel
+	Severities map[common.Severity]struct{}
+}
+
+// Notifier tells people about infringements and the penalties we applied for them
+type Notifier struct {
+	targets []notificationTarget
+	hostURL string
+	region  string
+	now     func() time.Time
+
+	// notified are the notifications we don't want to send again
+	notified *lru.Cache
+	queue    chan Notification
+
+	notifications *prometheus.CounterVec
+}
+
+// NewNotifier creates a notifier for the notifications of the config. hostURL is the Gitpod installation we link to.
+func NewNotifier(cfg config.Config, hostURL string) (*Notifier, error) {
+	var notifications config.Notifications
+	if cfg.Notifications != nil {
+		notifications = *cfg.Notifications
+	}
+	if cfg.SlackWebhooks != nil {
+		if cfg.SlackWebhooks.Audit != "" {
+			notifications.Slack = append(notifications.Slack, config.SlackNotification{URL: cfg.SlackWebhooks.Audit, Severities: []string{"audit"}})
+		}
+		if cfg.SlackWebhooks.Warning != "" {
+			notifications.Slack = append(notifications.Slack, config.SlackNotification{URL: cfg.SlackWebhooks.Warning, Severities: []string{string(common.SeverityVery)}})
+		}
+	}
+
+	var targets []notificationTarget
+	for _, s := range notifications.Slack {
+		sev, err := notificationSeverities(s.Severities)
+		if err != nil {
+			return nil, xerrors.Errorf("slack notification: %w", err)
+		}
+		targets = append(targets, notificationTarget{Channel: slackChannel{URL: s.URL}, Severities: sev})
+	}
+	for _, w := range notifications.Webhooks {
+		sev, err := notificationSeverities(w.Severities)
+		if err != nil {
+			return nil, xerrors.Errorf("webhook notification: %w", err)
+		}
+		ch := &webhookChannel{URL: w.URL, Client: &http.Client{Timeout: notificationTimeout}}
+		if w.TokenFile != "" {
+			ch.Token, err = readSecretFile(w.TokenFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		targets = append(targets, notificationTarget{Channel: ch, Severities: sev})
+	}
+
+	return newNotifierWithTargets(targets, hostURL), nil
+}
+
+func newNotifierWithTargets(targets []notificationTarget, hostURL string) *Notifier {
+	return &Notifier{
+		targets:  targets,
+		hostURL:  strings.TrimSuffix(hostURL, "/"),
+		region:   os.Getenv("GITPOD_REGION"),
+		now:      time.Now,
+		notified: lru.New(notificationCacheSize),
+		queue:    make(chan Notification, notificationQueueSize),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gitpod",
+			Subsystem: "agent_smith",
+			Name:      "notifications_total",
+			Help:      "total count of notifications about infringements by channel and result",
+		}, []string{"channel", "result"}),
+	}
+}
+
+func notificationSeverities(severities []string) (map[common.Severity]struct{}, error) {
+	if len(severities) == 0 {
+		severities = []string{"audit", string(common.SeverityVery)}
+	}
+	sev, err := config.ParseSeverities(severities)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[common.Severity]struct{}, len(sev))
+	for _, s := range sev {
+		res[s] = struct{}{}
+	}
+	return res, nil
+}
+
+// Notify queues a notification about the infringements of a workspace unless we've sent the same one before
+func (n *Notifier) Notify(ws InfringingWorkspace, penalties []config.PenaltyKind) {
+	if n == nil || len(n.targets) == 0 {
+		return
+	}
+
+	key := notificationKey(ws, penalties)
+	if _, notified := n.notified.Get(key); notified {
+		return
+	}
+	n.notified.Add(key, struct{}{})
+
+	notification := Notification{
+		Time:          n.now().UTC(),
+		Region:        n.region,
+		Owner:         ws.Owner,
+		WorkspaceID:   ws.WorkspaceID,
+		InstanceID:    ws.InstanceID,
+		Pod:           ws.Pod,
+		GitRemoteURL:  ws.GitRemoteURL,
+		Infringements: make([]AuditInfringement, 0, len(ws.Infringements)),
+		Penalties:     penalties,
+	}
+	for _, inf := range ws.Infringements {
+		notification.Infringements = append(notification.Infringements, AuditInfringement{Kind: inf.Kind, Description: inf.Description, AuditOnly: inf.AuditOnly})
+	}
+	if n.hostURL != "" {
+		notification.UserAdminURL = fmt.Sprintf("%s/admin/users/%s", n.hostURL, ws.Owner)
+		notification.WorkspaceAdminURL = fmt.Sprintf("%s/admin/workspaces/%s", n.hostURL, ws.WorkspaceID)
+		notification.BlockUserURL = fmt.Sprintf("%s/api/enforcement/block-user/%s", n.hostURL, ws.Owner)
+	}
+
+	select {
+	case n.queue <- notification:
+	default:
+		log.WithFields(log.OWI(ws.Owner, ws.WorkspaceID, ws.InstanceID)).Warn("notification queue is full - dropping notification")
+		n.notifications.WithLabelValues("queue", "dropped").Inc()
+	}
+}
+
+// notificationKey identifies a notification, so that a workspace whose infringements don't change produces one notification only
+func notificationKey(ws InfringingWorkspace, penalties []config.PenaltyKind) string {
+	parts := make([]string, 0, len(ws.Infringements)+len(penalties))
+	for _, inf := range ws.Infringements {
+		parts = append(parts, string(inf.Kind))
+	}
+	for _, p := range penalties {
+		parts = append(parts, string(p))
+	}
+	sort.Strings(parts)
+	return ws.InstanceID + "/" + strings.Join(parts, ":")
+}
+
+// Run sends the queued notifications until ctx is canceled
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-n.queue:
+			n.send(ctx, notification)
+		}
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, notification Notification) {
+	for _, t := range n.targets {
+		if !t.matches(notification) {
+			continue
+		}
+
+		sctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+		err := t.Channel.Send(sctx, notification)
+		cancel()
+		if err != nil {
+			log.WithError(err).WithField("channel", t.Channel.Name()).WithFields(log.OWI(notification.Owner, notification.WorkspaceID, notification.InstanceID)).Warn("cannot send notification")
+			n.notifications.WithLabelValues(t.Channel.Name(), "error").Inc()
+			continue
+		}
+		n.notifications.WithLabelValues(t.Channel.Name(), "success").Inc()
+	}
+}
+
+// matches is true if the target wants to know about one of the infringements of the notification
+func (t notificationTarget) matches(n Notification) bool {
+	for _, inf := range n.Infringements {
+		if _, ok := t.Severities[inf.Kind.Severity()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) Describe(d chan<- *prometheus.Desc) {
+	n.notifications.Describe(d)
+}
+
+func (n *Notifier) Collect(m chan<- prometheus.Metric) {
+	n.notifications.Collect(m)
+}
+
+type slackChannel struct {
+	URL string
+}
+
+func (slackChannel) Name() string { return "slack" }
+
+func (s slackChannel) Send(ctx context.Context, n Notification) error {
+	errs := slack.Send(s.URL, "", slackPayload(n))
+	if len(errs) > 0 {
+		allerr := make([]string, len(errs))
+		for i, err := range errs {
+			allerr[i] = err.Error()
+		}
+		return xerrors.Errorf("cannot notify Slack: %s", strings.Join(allerr, ", "))
+	}
+	return nil
+}
+
+func slackPayload(n Notification) slack.Payload {
+	var (
+		lblDetails       = "Details"
+		lblActions       = "Actions"
+		lblPenalties     = "Penalties"
+		lblInfringements = "Long Infringements details"
+	)
+
+	attachments := []slack.Attachment{
+		{
+			Title: &lblDetails,
+			Fields: []*slack.Field{
+				{Title: "pod", Value: n.Pod},
+				{Title: "owner", Value: n.Owner},
+				{Title: "workspace", Value: n.WorkspaceID},
+				{Title: "instance", Value: n.InstanceID},
+				{Title: "repository", Value: strings.Join(n.GitRemoteURL, ", ")},
+				{Title: "region", Value: n.Region},
+			},
+		},
+	}
+	if len(n.Penalties) > 0 {
+		vs := make([]*slack.Field, len(n.Penalties))
+		for i, p := range n.Penalties {
+			vs[i] = &slack.Field{Title: "enforced", Value: string(p)}
+		}
+		attachments = append(attachments, slack.Attachment{
+			Title:  &lblPenalties,
+			Fields: vs,
+		})
+	}
+	if n.UserAdminURL != "" {
+		attachments = append(attachments,
+			slack.Attachment{
+				Title: &lblActions,
+				Fields: []*slack.Field{
+					{Title: "User Admin", Value: n.UserAdminURL},
+					{Title: "Workspace Admin", Value: n.WorkspaceAdminURL},
+				},
+				Actions: []*slack.Action{
+					{Type: "button", Text: "Block User", Url: n.BlockUserURL},
+				},
+			},
+		)
+	}
+
+	infringements := make([]*slack.Field, 0, len(n.Infringements))
+	descriptions := make([]string, 0, len(n.Infringements))
+	for _, v := range n.Infringements {
+		infringements = append(infringements, &slack.Field{
+			Title: string(v.Kind), Value: v.Description,
+		})
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", v.Kind, v.Description))
+	}
+	attachments = append(attachments,
+		slack.Attachment{Title: &lblInfringements, Fields: infringements},
+	)
+
+	text := strings.Join(descriptions, "\n")
+	if len(text) > 150 {
+		text = text[:150]
+	}
+	return slack.Payload{
+		Text:        fmt.Sprintf("Agent Smith: %s", text),
+		IconEmoji:   ":-(",
+		Attachments: attachments,
+	}
+}
+
+type webhookChannel struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+func (*webhookChannel) Name() string { return "webhook" }
+
+func (w *webhookChannel) Send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return xerrors.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNotifier(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[string][]string)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var msg string
+		switch r.URL.Path {
+		case "/webhook":
+			var n Notification
+			err := json.NewDecoder(r.Body).Decode(&n)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			msg = n.Owner + " " + n.UserAdminURL
+		default:
+			var p struct {
+				Text string `json:"text"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&p)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			msg = p.Text
+		}
+		received[r.URL.Path] = append(received[r.URL.Path], msg)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(config.Config{
+		SlackWebhooks: &config.SlackWebhooks{Warning: srv.URL + "/legacy-warning"},
+		Notifications: &config.Notifications{
+			Slack:    []config.SlackNotification{{URL: srv.URL + "/slack"}},
+			Webhooks: []config.WebhookNotification{{URL: srv.URL + "/webhook", Severities: []string{"barely", "very"}}},
+		},
+	}, "https://gitpod.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		very   = Infringement{Kind: config.GradeKind(config.InfringementExec, common.SeverityVery), Description: "matches xmrig"}
+		audit  = Infringement{Kind: config.GradeKind(config.InfringementExec, common.SeverityAudit), Description: "matches nsjail"}
+		barely = Infringement{Kind: config.GradeKind(config.InfringementExcessiveEgress, common.SeverityBarely), Description: "egress"}
+	)
+	n.Notify(InfringingWorkspace{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{very}}, []config.PenaltyKind{config.PenaltyStopWorkspace})
+	// the same infringements and penalties don't notify again
+	n.Notify(InfringingWorkspace{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{very}}, []config.PenaltyKind{config.PenaltyStopWorkspace})
+	n.Notify(InfringingWorkspace{Owner: "bob", InstanceID: "ws2", Infringements: []Infringement{audit}}, nil)
+	n.Notify(InfringingWorkspace{Owner: "carol", InstanceID: "ws3", Infringements: []Infringement{barely}}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for len(n.queue) > 0 {
+		n.send(ctx, <-n.queue)
+	}
+
+	exp := map[string][]string{
+		"/slack":          {"Agent Smith: very blocklisted executable: matches xmrig", "Agent Smith: blocklisted executable: matches nsjail"},
+		"/legacy-warning": {"Agent Smith: very blocklisted executable: matches xmrig"},
+		"/webhook":        {"alice https://gitpod.example.com/admin/users/alice", "carol https://gitpod.example.com/admin/users/carol"},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff(exp, received); diff != "" {
+		t.Errorf("unexpected notifications (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      config.Notifications
+		Expectation string
+	}{
+		{Name: "valid", Config: config.Notifications{Slack: []config.SlackNotification{{URL: "https://hooks.slack.com/services/foo", Severities: []string{"audit"}}}}},
+		{Name: "unknown severity", Config: config.Notifications{Webhooks: []config.WebhookNotification{{URL: "https://example.com", Severities: []string{"severe"}}}}, Expectation: `webhook notification: unknown severity "severe"`},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act string
+			_, err := NewNotifier(config.Config{Notifications: &test.Config}, "")
+			if err != nil {
+				act = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
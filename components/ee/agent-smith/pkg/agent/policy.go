@@ -0,0 +1,130 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultPolicyWindow is the time after which we forget a user's infringements
+	defaultPolicyWindow = 24 * time.Hour
+	// defaultPolicyCooldown is the time during which further infringements in the same workspace don't escalate the penalty
+	defaultPolicyCooldown = 5 * time.Minute
+)
+
+// penaltyPolicies escalates the penalties for repeated infringements of users
+type penaltyPolicies struct {
+	perUser  map[string]*config.PenaltyPolicy
+	fallback *config.PenaltyPolicy
+
+	mu sync.Mutex
+	// strikes are the recent escalations per user
+	strikes map[string][]strike
+}
+
+type strike struct {
+	InstanceID string
+	Time       time.Time
+}
+
+func newPenaltyPolicies(policies []config.PenaltyPolicy) (*penaltyPolicies, error) {
+	res := &penaltyPolicies{
+		perUser: make(map[string]*config.PenaltyPolicy),
+		strikes: make(map[string][]strike),
+	}
+	for i := range policies {
+		// we set defaults on a copy to not modify the config
+		policy := policies[i]
+		p := &policy
+		err := p.Validate()
+		if err != nil {
+			return nil, err
+		}
+		if p.Window == 0 {
+			p.Window = util.Duration(defaultPolicyWindow)
+		}
+		if p.Cooldown == 0 {
+			p.Cooldown = util.Duration(defaultPolicyCooldown)
+		}
+
+		if len(p.Users) == 0 {
+			if res.fallback != nil {
+				return nil, xerrors.Errorf("policies %s and %s both apply to everyone", res.fallback.Name, p.Name)
+			}
+			res.fallback = p
+			continue
+		}
+		for _, u := range p.Users {
+			if other, exists := res.perUser[u]; exists {
+				return nil, xerrors.Errorf("user %s is in policies %s and %s", u, other.Name, p.Name)
+			}
+			res.perUser[u] = p
+		}
+	}
+	return res, nil
+}
+
+// Find returns the policy of a user or nil if there is none
+func (p *penaltyPolicies) Find(owner string) *config.PenaltyPolicy {
+	if p == nil {
+		return nil
+	}
+	if res, ok := p.perUser[owner]; ok {
+		return res
+	}
+	return p.fallback
+}
+
+// Escalate records an infringement of a user in a workspace and returns the penalty the policy demands for it
+func (p *penaltyPolicies) Escalate(policy *config.PenaltyPolicy, owner, instanceID string, now time.Time) config.PenaltyKind {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prune(now)
+
+	strikes := p.strikes[owner]
+	if len(strikes) > 0 {
+		last := strikes[len(strikes)-1]
+		if last.InstanceID == instanceID && now.Sub(last.Time) < time.Duration(policy.Cooldown) {
+			return policy.Escalation[escalationStep(policy, len(strikes)-1)]
+		}
+	}
+
+	strikes = append(strikes, strike{InstanceID: instanceID, Time: now})
+	p.strikes[owner] = strikes
+	return policy.Escalation[escalationStep(policy, len(strikes)-1)]
+}
+
+func escalationStep(policy *config.PenaltyPolicy, strike int) int {
+	if strike >= len(policy.Escalation) {
+		return len(policy.Escalation) - 1
+	}
+	return strike
+}
+
+// prune forgets strikes which are outside of the window of their policy. Must be called with mu held.
+func (p *penaltyPolicies) prune(now time.Time) {
+	for owner, strikes := range p.strikes {
+		policy := p.Find(owner)
+		if policy == nil {
+			delete(p.strikes, owner)
+			continue
+		}
+		for len(strikes) > 0 && now.Sub(strikes[0].Time) > time.Duration(policy.Window) {
+			strikes = strikes[1:]
+		}
+		if len(strikes) == 0 {
+			delete(p.strikes, owner)
+			continue
+		}
+		p.strikes[owner] = strikes
+	}
+}
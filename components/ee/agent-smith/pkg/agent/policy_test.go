@@ -0,0 +1,176 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecidePenalty(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	var (
+		very   = Infringement{Kind: config.GradeKind(config.InfringementExec, common.SeverityVery)}
+		barely = Infringement{Kind: config.GradeKind(config.InfringementExec, common.SeverityBarely)}
+		newSig = Infringement{Kind: config.GradeKind(config.InfringementExec, common.SeverityVery), AuditOnly: true}
+	)
+	rules := map[string]config.EnforcementRules{
+		defaultRuleset: {
+			very.Kind:   config.PenaltyStopWorkspaceAndBlockUser,
+			barely.Kind: config.PenaltyNone,
+		},
+	}
+	policies := []config.PenaltyPolicy{
+		{
+			Name:       "team-a",
+			Users:      []string{"alice", "bob"},
+			Escalation: []config.PenaltyKind{config.PenaltyNotify, config.PenaltyLimitCPU, config.PenaltyStopWorkspace, config.PenaltyStopWorkspaceAndBlockUser},
+			Window:     util.Duration(time.Hour),
+		},
+		{
+			Name:       "trial",
+			Users:      []string{"dave"},
+			Escalation: []config.PenaltyKind{config.PenaltyStopWorkspace},
+			DryRun:     true,
+		},
+	}
+
+	type Step struct {
+		Owner         string
+		InstanceID    string
+		Infringements []Infringement
+		Offset        time.Duration
+		Expectation   []config.PenaltyKind
+	}
+	tests := []struct {
+		Name  string
+		Steps []Step
+	}{
+		{
+			Name: "no policy",
+			Steps: []Step{
+				{Owner: "carol", InstanceID: "ws1", Infringements: []Infringement{very}, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspaceAndBlockUser}},
+				{Owner: "carol", InstanceID: "ws1", Infringements: []Infringement{barely}},
+			},
+		},
+		{
+			Name: "escalation",
+			Steps: []Step{
+				{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{very}, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{very}, Offset: time.Minute, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{very}, Offset: 6 * time.Minute, Expectation: []config.PenaltyKind{config.PenaltyLimitCPU}},
+				{Owner: "alice", InstanceID: "ws2", Infringements: []Infringement{very}, Offset: 7 * time.Minute, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspace}},
+				{Owner: "bob", InstanceID: "ws3", Infringements: []Infringement{very}, Offset: 7 * time.Minute, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "alice", InstanceID: "ws3", Infringements: []Infringement{very}, Offset: 8 * time.Minute, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspaceAndBlockUser}},
+				{Owner: "alice", InstanceID: "ws4", Infringements: []Infringement{very}, Offset: 9 * time.Minute, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspaceAndBlockUser}},
+			},
+		},
+		{
+			Name: "window",
+			Steps: []Step{
+				{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{very}, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "alice", InstanceID: "ws2", Infringements: []Infringement{very}, Offset: 2 * time.Hour, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+			},
+		},
+		{
+			Name: "unpenalised infringements don't escalate",
+			Steps: []Step{
+				{Owner: "alice", InstanceID: "ws1", Infringements: []Infringement{barely}},
+				{Owner: "alice", InstanceID: "ws2", Infringements: []Infringement{newSig}, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "alice", InstanceID: "ws3", Infringements: []Infringement{very}, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "alice", InstanceID: "ws4", Infringements: []Infringement{very}, Expectation: []config.PenaltyKind{config.PenaltyLimitCPU}},
+			},
+		},
+		{
+			Name: "audit only",
+			Steps: []Step{
+				{Owner: "carol", InstanceID: "ws1", Infringements: []Infringement{newSig}, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+				{Owner: "carol", InstanceID: "ws1", Infringements: []Infringement{newSig, very}, Expectation: []config.PenaltyKind{config.PenaltyStopWorkspaceAndBlockUser}},
+			},
+		},
+		{
+			Name: "dry run",
+			Steps: []Step{
+				{Owner: "dave", InstanceID: "ws1", Infringements: []Infringement{very}, Expectation: []config.PenaltyKind{config.PenaltyNotify}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pp, err := newPenaltyPolicies(policies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			agent := &Smith{EnforcementRules: rules, policies: pp, metrics: newAgentMetrics()}
+
+			for i, step := range test.Steps {
+				act := agent.decidePenalty(InfringingWorkspace{Owner: step.Owner, InstanceID: step.InstanceID, Infringements: step.Infringements}, "", t0.Add(step.Offset))
+				if diff := cmp.Diff(step.Expectation, act); diff != "" {
+					t.Errorf("step %d: unexpected penalty (-want +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPenaltyPolicies(t *testing.T) {
+	escalation := []config.PenaltyKind{config.PenaltyNotify, config.PenaltyStopWorkspace}
+	tests := []struct {
+		Name        string
+		Policies    []config.PenaltyPolicy
+		Expectation string
+	}{
+		{
+			Name: "valid",
+			Policies: []config.PenaltyPolicy{
+				{Name: "team-a", Users: []string{"alice"}, Escalation: escalation},
+				{Name: "everyone", Escalation: escalation},
+			},
+		},
+		{
+			Name:        "empty escalation",
+			Policies:    []config.PenaltyPolicy{{Name: "team-a"}},
+			Expectation: "policy team-a: escalation is empty",
+		},
+		{
+			Name:        "unknown penalty",
+			Policies:    []config.PenaltyPolicy{{Name: "team-a", Escalation: []config.PenaltyKind{"shout"}}},
+			Expectation: "policy team-a: shout: unknown penalty",
+		},
+		{
+			Name: "user in two policies",
+			Policies: []config.PenaltyPolicy{
+				{Name: "team-a", Users: []string{"alice"}, Escalation: escalation},
+				{Name: "team-b", Users: []string{"alice"}, Escalation: escalation},
+			},
+			Expectation: "user alice is in policies team-a and team-b",
+		},
+		{
+			Name: "two fallbacks",
+			Policies: []config.PenaltyPolicy{
+				{Name: "everyone", Escalation: escalation},
+				{Name: "everyone else", Escalation: escalation},
+			},
+			Expectation: "policies everyone and everyone else both apply to everyone",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act string
+			_, err := newPenaltyPolicies(test.Policies)
+			if err != nil {
+				act = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"math"
+	"math/rand"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultClassificationWorkers   = 25
+	defaultClassificationQueueSize = 500
+)
+
+// processSampler decides which processes we classify and how fast, so that operators can tune our overhead
+type processSampler struct {
+	Workers   int
+	QueueSize int
+
+	ratio   float64
+	limiter *rate.Limiter
+	random  func() float64
+}
+
+func newProcessSampler(cfg *config.Sampling) *processSampler {
+	res := &processSampler{
+		Workers:   defaultClassificationWorkers,
+		QueueSize: defaultClassificationQueueSize,
+		ratio:     1,
+		random:    rand.Float64,
+	}
+	if cfg == nil {
+		return res
+	}
+
+	if cfg.ClassificationWorkers > 0 {
+		res.Workers = cfg.ClassificationWorkers
+	}
+	if cfg.ClassificationQueueSize > 0 {
+		res.QueueSize = cfg.ClassificationQueueSize
+	}
+	if cfg.ProcessSampleRatio > 0 {
+		res.ratio = cfg.ProcessSampleRatio
+	}
+	if cfg.MaxClassificationsPerSecond > 0 {
+		// we allow bursts of a second's worth of classifications, as the process detectors report processes in bursts
+		burst := int(math.Ceil(cfg.MaxClassificationsPerSecond))
+		res.limiter = rate.NewLimiter(rate.Limit(cfg.MaxClassificationsPerSecond), burst)
+	}
+	return res
+}
+
+// Sample returns true if we should classify the next process
+func (s *processSampler) Sample() bool {
+	return s.ratio >= 1 || s.random() < s.ratio
+}
+
+// Wait blocks until the rate limit allows for another classification or ctx is canceled
+func (s *processSampler) Wait(ctx context.Context) error {
+	if s.limiter == nil {
+		return nil
+	}
+	return s.limiter.Wait(ctx)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessSampler(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      *config.Sampling
+		Random      []float64
+		Expectation []bool
+	}{
+		{Name: "no config", Random: []float64{0.1, 0.9}, Expectation: []bool{true, true}},
+		{Name: "all", Config: &config.Sampling{ProcessSampleRatio: 1}, Random: []float64{0.1, 0.9}, Expectation: []bool{true, true}},
+		{Name: "half", Config: &config.Sampling{ProcessSampleRatio: 0.5}, Random: []float64{0.1, 0.5, 0.9}, Expectation: []bool{true, false, false}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s := newProcessSampler(test.Config)
+			var i int
+			s.random = func() float64 {
+				r := test.Random[i]
+				i++
+				return r
+			}
+
+			var act []bool
+			for range test.Random {
+				act = append(act, s.Sample())
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected samples (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessSamplerRateLimit(t *testing.T) {
+	s := newProcessSampler(&config.Sampling{MaxClassificationsPerSecond: 2})
+	if s.Workers != defaultClassificationWorkers || s.QueueSize != defaultClassificationQueueSize {
+		t.Errorf("expected default workers and queue size, got %d and %d", s.Workers, s.QueueSize)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var n int
+	for s.Wait(ctx) == nil {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("expected a burst of 2 classifications, got %d", n)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/classifier"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultSignatureFeedInterval is the time between polls of the signature feed
+	defaultSignatureFeedInterval = 5 * time.Minute
+	// maxSignatureFeedSize limits the size of the documents we download from the feed
+	maxSignatureFeedSize = 10 << 20
+
+	gcsMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+var validFeedVersion = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// signatureFeed polls a remote feed of blocklists and hot-reloads the classifier whenever the version changes
+type signatureFeed struct {
+	Config config.SignatureFeed
+	// Static are the blocklists of the config which we merge with those of the feed
+	Static     *config.Blocklists
+	Classifier *classifier.ReloadableClassifier
+	Client     *http.Client
+
+	// tokenURL is where we get an access token for gs:// feeds from
+	tokenURL string
+	metrics  *metrics
+	// version is the currently loaded version of the feed
+	version string
+}
+
+func newSignatureFeed(cfg config.SignatureFeed, static *config.Blocklists, cl *classifier.ReloadableClassifier, m *metrics) (*signatureFeed, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid signature feed URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "gs" {
+		return nil, xerrors.Errorf("signature feed URL must be https:// or gs://, not %s", u.Scheme)
+	}
+	if cfg.Version != "" && !validFeedVersion.MatchString(cfg.Version) {
+		return nil, xerrors.Errorf("invalid signature feed version %q", cfg.Version)
+	}
+
+	return &signatureFeed{
+		Config:     cfg,
+		Static:     static,
+		Classifier: cl,
+		Client:     &http.Client{Timeout: 30 * time.Second},
+		tokenURL:   gcsMetadataTokenURL,
+		metrics:    m,
+	}, nil
+}
+
+// Run polls the feed until ctx is canceled
+func (f *signatureFeed) Run(ctx context.Context) {
+	interval := time.Duration(f.Config.Interval)
+	if interval == 0 {
+		interval = defaultSignatureFeedInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		err := f.Update(ctx)
+		if err != nil {
+			log.WithError(err).WithField("url", f.Config.URL).Warn("cannot update signature feed - keeping the current signatures")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Update loads the current version of the feed unless we've loaded it already.
+// If the version cannot be loaded, we keep the current one.
+func (f *signatureFeed) Update(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			f.metrics.signatureFeedUpdates.WithLabelValues("error").Inc()
+		}
+	}()
+
+	version := f.Config.Version
+	if version == "" {
+		var latest config.SignatureFeedDocument
+		err = f.fetch(ctx, "latest.json", &latest)
+		if err != nil {
+			return err
+		}
+		if !validFeedVersion.MatchString(latest.Version) {
+			return xerrors.Errorf("latest.json names invalid version %q", latest.Version)
+		}
+		version = latest.Version
+	}
+	if version == f.version {
+		f.metrics.signatureFeedUpdates.WithLabelValues("unchanged").Inc()
+		return nil
+	}
+
+	var doc config.SignatureFeedDocument
+	err = f.fetch(ctx, version+".json", &doc)
+	if err != nil {
+		return err
+	}
+	if doc.Version != version {
+		return xerrors.Errorf("%s.json contains version %q", version, doc.Version)
+	}
+	if doc.Blocklists != nil {
+		err = doc.Blocklists.Validate()
+		if err != nil {
+			return xerrors.Errorf("version %s: %w", version, err)
+		}
+	}
+	cl, err := f.Static.Merge(doc.Blocklists).Classifier()
+	if err != nil {
+		return xerrors.Errorf("version %s: %w", version, err)
+	}
+	f.Classifier.Reload(cl)
+
+	log.WithField("version", version).WithField("previousVersion", f.version).Info("loaded new signature feed version")
+	f.version = version
+	f.metrics.signatureFeedUpdates.WithLabelValues("loaded").Inc()
+	f.metrics.signatureFeedVersion.Reset()
+	f.metrics.signatureFeedVersion.WithLabelValues(version).Set(1)
+	return nil
+}
+
+func (f *signatureFeed) fetch(ctx context.Context, name string, dst interface{}) error {
+	u, err := url.Parse(f.Config.URL)
+	if err != nil {
+		return err
+	}
+	var token string
+	if u.Scheme == "gs" {
+		// objects are available through the XML API
+		u.Path = "/" + u.Host + u.Path
+		u.Scheme, u.Host = "https", "storage.googleapis.com"
+
+		token, err = f.gcsToken(ctx)
+		if err != nil {
+			// public buckets need no token, hence we try without one
+			log.WithError(err).Debug("cannot get GCS access token")
+		}
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + name
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("cannot download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("cannot download %s: %s", name, resp.Status)
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxSignatureFeedSize)).Decode(dst)
+	if err != nil {
+		return xerrors.Errorf("cannot parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// gcsToken returns an access token of the service account of the node we're running on
+func (f *signatureFeed) gcsToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
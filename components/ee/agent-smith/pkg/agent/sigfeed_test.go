@@ -0,0 +1,212 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/classifier"
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSignatureFeed(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		files = map[string]string{
+			"v1.json": `{"version":"v1","blocklists":{"very":{"binaries":["xmrig"]}}}`,
+			"v2.json": `{"version":"v2","blocklists":{"very":{"binaries":["xmrig","t-rex"]}}}`,
+			"v3.json": `{"version":"v3","blocklists":{"very":{"allowlist":["("]}}}`,
+			"v4.json": `{"version":"v2","blocklists":{}}`,
+			"v5.json": `{"version":"v5","blocklists":{"very":{"signatures":[{"name":"empty"}]}}}`,
+		}
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/feed/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	static := &config.Blocklists{Audit: &config.PerLevelBlocklist{Binaries: []string{"nsjail"}}}
+	initial, err := static.Merge(nil).Classifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := classifier.NewReloadableClassifier(initial)
+	feed, err := newSignatureFeed(config.SignatureFeed{URL: srv.URL + "/feed/"}, static, cl, newAgentMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed.Client = srv.Client()
+
+	type Expectation struct {
+		Error   string
+		Version string
+		Levels  map[string]classifier.Level
+	}
+	classify := func() map[string]classifier.Level {
+		res := make(map[string]classifier.Level)
+		for _, bin := range []string{"/usr/bin/xmrig", "/usr/bin/t-rex", "/usr/bin/nsjail"} {
+			c, err := cl.Matches(bin, []string{bin})
+			if err != nil {
+				t.Fatal(err)
+			}
+			res[bin] = c.Level
+		}
+		return res
+	}
+	levels := func(xmrig, trex classifier.Level) map[string]classifier.Level {
+		return map[string]classifier.Level{
+			"/usr/bin/xmrig":  xmrig,
+			"/usr/bin/t-rex":  trex,
+			"/usr/bin/nsjail": classifier.LevelAudit,
+		}
+	}
+
+	steps := []struct {
+		Name        string
+		Latest      string
+		Expectation Expectation
+	}{
+		{
+			Name:        "missing latest",
+			Expectation: Expectation{Error: "cannot download latest.json: 404 Not Found", Levels: levels(classifier.LevelNoMatch, classifier.LevelNoMatch)},
+		},
+		{
+			Name:        "initial version",
+			Latest:      `{"version":"v1"}`,
+			Expectation: Expectation{Version: "v1", Levels: levels(classifier.LevelVery, classifier.LevelNoMatch)},
+		},
+		{
+			Name:        "new version",
+			Latest:      `{"version":"v2"}`,
+			Expectation: Expectation{Version: "v2", Levels: levels(classifier.LevelVery, classifier.LevelVery)},
+		},
+		{
+			Name:        "invalid version keeps current one",
+			Latest:      `{"version":"v3"}`,
+			Expectation: Expectation{Version: "v2", Error: "version v3: cannot compile (: error parsing regexp: missing closing ): `(`", Levels: levels(classifier.LevelVery, classifier.LevelVery)},
+		},
+		{
+			Name:        "version mismatch",
+			Latest:      `{"version":"v4"}`,
+			Expectation: Expectation{Version: "v2", Error: `v4.json contains version "v2"`, Levels: levels(classifier.LevelVery, classifier.LevelVery)},
+		},
+		{
+			Name:        "invalid signature",
+			Latest:      `{"version":"v5"}`,
+			Expectation: Expectation{Version: "v2", Error: "version v5: invalid signature empty on level very: signature has no pattern", Levels: levels(classifier.LevelVery, classifier.LevelVery)},
+		},
+		{
+			Name:        "missing version",
+			Latest:      `{"version":"v6"}`,
+			Expectation: Expectation{Version: "v2", Error: "cannot download v6.json: 404 Not Found", Levels: levels(classifier.LevelVery, classifier.LevelVery)},
+		},
+		{
+			Name:        "path traversal",
+			Latest:      `{"version":"../v1"}`,
+			Expectation: Expectation{Version: "v2", Error: `latest.json names invalid version "../v1"`, Levels: levels(classifier.LevelVery, classifier.LevelVery)},
+		},
+		{
+			Name:        "rollback",
+			Latest:      `{"version":"v1"}`,
+			Expectation: Expectation{Version: "v1", Levels: levels(classifier.LevelVery, classifier.LevelNoMatch)},
+		},
+	}
+	for _, step := range steps {
+		mu.Lock()
+		if step.Latest == "" {
+			delete(files, "latest.json")
+		} else {
+			files["latest.json"] = step.Latest
+		}
+		mu.Unlock()
+
+		var act Expectation
+		err := feed.Update(context.Background())
+		if err != nil {
+			act.Error = err.Error()
+		}
+		act.Version = feed.version
+		act.Levels = classify()
+
+		if diff := cmp.Diff(step.Expectation, act); diff != "" {
+			t.Errorf("%s: unexpected update (-want +got):\n%s", step.Name, diff)
+		}
+	}
+}
+
+func TestSignatureFeedPinnedVersion(t *testing.T) {
+	var requests []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Write([]byte(`{"version":"v1","blocklists":{"very":{"binaries":["xmrig"]}}}`))
+	}))
+	defer srv.Close()
+
+	initial, err := (*config.Blocklists)(nil).Merge(nil).Classifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := classifier.NewReloadableClassifier(initial)
+	feed, err := newSignatureFeed(config.SignatureFeed{URL: srv.URL + "/feed", Version: "v1"}, nil, cl, newAgentMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed.Client = srv.Client()
+
+	for i := 0; i < 2; i++ {
+		err = feed.Update(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff([]string{"/feed/v1.json"}, requests); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+	c, err := cl.Matches("/usr/bin/xmrig", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Level != classifier.LevelVery {
+		t.Errorf("expected pinned version to be loaded, got level %s", c.Level)
+	}
+}
+
+func TestNewSignatureFeed(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      config.SignatureFeed
+		Expectation string
+	}{
+		{Name: "https", Config: config.SignatureFeed{URL: "https://example.com/feed"}},
+		{Name: "gcs", Config: config.SignatureFeed{URL: "gs://bucket/feed"}},
+		{Name: "http", Config: config.SignatureFeed{URL: "http://example.com/feed"}, Expectation: "signature feed URL must be https:// or gs://, not http"},
+		{Name: "invalid version", Config: config.SignatureFeed{URL: "gs://bucket/feed", Version: "../v1"}, Expectation: `invalid signature feed version "../v1"`},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act string
+			_, err := newSignatureFeed(test.Config, nil, nil, nil)
+			if err != nil {
+				act = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
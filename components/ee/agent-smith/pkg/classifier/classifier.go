@@ -10,6 +10,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
 	"github.com/gitpod-io/gitpod/common-go/log"
@@ -28,6 +29,8 @@ type Classification struct {
 	Level      Level
 	Classifier string
 	Message    string
+	// AuditOnly is true if the match should be reported, but not penalised
+	AuditOnly bool
 }
 
 type Level string
@@ -204,6 +207,7 @@ func (sigcl *SignatureMatchClassifier) Matches(executable string, cmdline []stri
 				Level:      sigcl.DefaultLevel,
 				Classifier: ClassifierSignature,
 				Message:    fmt.Sprintf("matches %s", sig.Name),
+				AuditOnly:  sig.AuditOnly,
 			}, nil
 		}
 		if err != nil {
@@ -394,3 +398,43 @@ func (cl *CountingMetricsClassifier) Collect(m chan<- prometheus.Metric) {
 	cl.callCount.Collect(m)
 	cl.D.Collect(m)
 }
+
+// NewReloadableClassifier creates a classifier which delegates to d until it's reloaded
+func NewReloadableClassifier(d ProcessClassifier) *ReloadableClassifier {
+	return &ReloadableClassifier{d: d}
+}
+
+// ReloadableClassifier delegates to a classifier which can be replaced at runtime, e.g. when new signatures
+// become available. Replacements must produce the same metrics as the classifier they replace.
+type ReloadableClassifier struct {
+	mu sync.RWMutex
+	d  ProcessClassifier
+}
+
+var _ ProcessClassifier = &ReloadableClassifier{}
+
+// Reload replaces the classifier we delegate to
+func (cl *ReloadableClassifier) Reload(d ProcessClassifier) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.d = d
+}
+
+func (cl *ReloadableClassifier) Matches(executable string, cmdline []string) (*Classification, error) {
+	cl.mu.RLock()
+	d := cl.d
+	cl.mu.RUnlock()
+	return d.Matches(executable, cmdline)
+}
+
+func (cl *ReloadableClassifier) Describe(d chan<- *prometheus.Desc) {
+	cl.mu.RLock()
+	defer cl.mu.RUnlock()
+	cl.d.Describe(d)
+}
+
+func (cl *ReloadableClassifier) Collect(m chan<- prometheus.Metric) {
+	cl.mu.RLock()
+	defer cl.mu.RUnlock()
+	cl.d.Collect(m)
+}
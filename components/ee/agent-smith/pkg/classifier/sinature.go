@@ -62,6 +62,9 @@ type Signature struct {
 	// Filenames is a list of filenames this signature can match to
 	Filename []string `json:"filenames,omitempty"`
 
+	// AuditOnly marks a signature whose matches we report, but don't penalise, e.g. while we try out a new signature
+	AuditOnly bool `json:"auditOnly,omitempty"`
+
 	// compiledRegexp is an optimization so that we don't have to re-compile the regexp every time we use it
 	compiledRegexp *regexp.Regexp
 }
@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gitpod-io/gitpod/agent-smith/pkg/classifier"
 	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
@@ -36,6 +38,10 @@ func GetConfig(cfgFile string) (*ServiceConfig, error) {
 	if cfg.ProbePath == "" {
 		cfg.ProbePath = "/app/probe.o"
 	}
+	// ServiceConfig.Namespace shadows Config.KubernetesNamespace, hence the latter is never unmarshalled
+	if cfg.KubernetesNamespace == "" {
+		cfg.KubernetesNamespace = cfg.Namespace
+	}
 
 	return &cfg, nil
 }
@@ -65,11 +71,68 @@ type Enforcement struct {
 	Default         *EnforcementRules           `json:"default,omitempty"`
 	PerRepo         map[string]EnforcementRules `json:"perRepo,omitempty"`
 	CPULimitPenalty string                      `json:"cpuLimitPenalty,omitempty"`
+
+	// Policies escalate the penalties for repeated infringements of the users of a cohort.
+	// The enforcement rules decide whether an infringement is penalised, the policy how hard.
+	// Users who are in no cohort get the penalties of the enforcement rules.
+	Policies []PenaltyPolicy `json:"policies,omitempty"`
+}
+
+// PenaltyPolicy escalates the penalties for a cohort of users, e.g. the members of a team
+type PenaltyPolicy struct {
+	// Name identifies the policy in logs and metrics, e.g. the name of the team
+	Name string `json:"name"`
+	// Users are the IDs of the users the policy applies to. A policy without users applies to everyone
+	// whom no other policy lists.
+	Users []string `json:"users,omitempty"`
+	// Escalation lists the penalties in the order we apply them to repeated infringements of a user,
+	// e.g. notify, limit CPU, stop workspace, stop workspace and block user. The last one applies to all further infringements.
+	Escalation []PenaltyKind `json:"escalation"`
+	// Window is the time after which we forget a user's infringements. Defaults to 24 hours.
+	Window util.Duration `json:"window,omitempty"`
+	// Cooldown is the time during which further infringements in the same workspace don't escalate the penalty.
+	// Defaults to 5 minutes.
+	Cooldown util.Duration `json:"cooldown,omitempty"`
+	// DryRun makes us only report the penalties of this policy instead of applying them
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Validate returns an error if the policy is invalid for some reason
+func (p PenaltyPolicy) Validate() error {
+	if len(p.Escalation) == 0 {
+		return xerrors.Errorf("policy %s: escalation is empty", p.Name)
+	}
+	for _, pk := range p.Escalation {
+		if _, ok := validPenalties[pk]; !ok {
+			return xerrors.Errorf("policy %s: %s: unknown penalty", p.Name, pk)
+		}
+	}
+	return nil
+}
+
+var validPenalties = map[PenaltyKind]struct{}{
+	PenaltyLimitCPU:                  {},
+	PenaltyNone:                      {},
+	PenaltyNotify:                    {},
+	PenaltyStopWorkspace:             {},
+	PenaltyStopWorkspaceAndBlockUser: {},
 }
 
 // EnforcementRules matches a infringement with a particular penalty
 type EnforcementRules map[GradedInfringementKind]PenaltyKind
 
+// DefaultEnforcementRules are the enforcement rules which apply unless Enforcement.Default replaces them
+func DefaultEnforcementRules() EnforcementRules {
+	return EnforcementRules{
+		GradeKind(InfringementExec, common.SeverityBarely):          PenaltyLimitCPU,
+		GradeKind(InfringementExec, common.SeverityAudit):           PenaltyStopWorkspace,
+		GradeKind(InfringementExec, common.SeverityVery):            PenaltyStopWorkspaceAndBlockUser,
+		GradeKind(InfringementExcessiveEgress, common.SeverityVery): PenaltyStopWorkspace,
+		GradeKind(InfringementNetworkAnomaly, common.SeverityVery):  PenaltyStopWorkspace,
+		GradeKind(InfringementMinerBehaviour, common.SeverityVery):  PenaltyStopWorkspace,
+	}
+}
+
 // Validate returns an error if the enforcement rules are invalid for some reason
 func (er EnforcementRules) Validate() error {
 	for k := range er {
@@ -78,12 +141,6 @@ func (er EnforcementRules) Validate() error {
 		}
 	}
 
-	validPenalties := map[PenaltyKind]struct{}{
-		PenaltyLimitCPU:                  {},
-		PenaltyNone:                      {},
-		PenaltyStopWorkspace:             {},
-		PenaltyStopWorkspaceAndBlockUser: {},
-	}
 	for _, v := range er {
 		if _, ok := validPenalties[v]; !ok {
 			return xerrors.Errorf("%s: unknown penalty", v)
@@ -101,6 +158,10 @@ const (
 	InfringementExec InfringementKind = "blocklisted executable"
 	// InfringementExcessiveEgress means a user produced too much egress traffic
 	InfringementExcessiveEgress InfringementKind = "excessive egress"
+	// InfringementNetworkAnomaly means a workspace's connections look suspicious, e.g. because it connects to a mining pool
+	InfringementNetworkAnomaly InfringementKind = "network anomaly"
+	// InfringementMinerBehaviour means a process of a workspace behaves like a cryptominer, regardless of its executable
+	InfringementMinerBehaviour InfringementKind = "miner behaviour"
 )
 
 // PenaltyKind describes a kind of penalty for a violating workspace
@@ -109,6 +170,8 @@ type PenaltyKind string
 const (
 	// PenaltyNone means there's no penalty for a particular infringement
 	PenaltyNone PenaltyKind = ""
+	// PenaltyNotify only reports the infringement, e.g. on Slack
+	PenaltyNotify PenaltyKind = "notify"
 	// PenaltyStopWorkspace stops a workspace hard
 	PenaltyStopWorkspace PenaltyKind = "stop workspace"
 	// PenaltyLimitCPU permanently limits the CPU a workspace can use
@@ -146,6 +209,8 @@ func (g GradedInfringementKind) Kind() (InfringementKind, error) {
 	validKinds := []InfringementKind{
 		InfringementExcessiveEgress,
 		InfringementExec,
+		InfringementNetworkAnomaly,
+		InfringementMinerBehaviour,
 	}
 	for _, k := range validKinds {
 		if string(k) == wopfx {
@@ -176,23 +241,336 @@ type Config struct {
 	GitpodAPI           GitpodAPI `json:"gitpodAPI"`
 	KubernetesNamespace string    `json:"namespace"`
 
-	Blocklists *Blocklists `json:"blocklists,omitempty"`
+	Blocklists    *Blocklists    `json:"blocklists,omitempty"`
+	SignatureFeed *SignatureFeed `json:"signatureFeed,omitempty"`
 
 	EgressTraffic     *EgressTraffic     `json:"egressTraffic,omitempty"`
 	Enforcement       Enforcement        `json:"enforcement,omitempty"`
 	ExcessiveCPUCheck *ExcessiveCPUCheck `json:"excessiveCPUCheck,omitempty"`
 	SlackWebhooks     *SlackWebhooks     `json:"slackWebhooks,omitempty"`
+	Notifications     *Notifications     `json:"notifications,omitempty"`
 	Kubernetes        Kubernetes         `json:"kubernetes"`
+	FileAudit         *FileAudit         `json:"fileAudit,omitempty"`
+	NetworkAnomalies  *NetworkAnomalies  `json:"networkAnomalies,omitempty"`
+	MinerDetection    *MinerDetection    `json:"minerDetection,omitempty"`
+	AuditLog          *AuditLog          `json:"auditLog,omitempty"`
+	RuntimeAllowlist  *RuntimeAllowlist  `json:"runtimeAllowlist,omitempty"`
+	Sampling          *Sampling          `json:"sampling,omitempty"`
+
+	// ProcessDetector selects how we discover processes on the node. Defaults to procfs.
+	ProcessDetector ProcessDetectorKind `json:"processDetector,omitempty"`
+	ProbePath       string              `json:"probePath,omitempty"`
+}
+
+// ProcessDetectorKind names a way to discover processes on the node
+type ProcessDetectorKind string
+
+const (
+	// ProcessDetectorProcfs periodically scans procfs for processes
+	ProcessDetectorProcfs ProcessDetectorKind = "procfs"
+	// ProcessDetectorEBPF loads an eBPF sensor from the probe path which reports execve, connect and setuid calls
+	ProcessDetectorEBPF ProcessDetectorKind = "ebpf"
+)
+
+// Sampling trades detection coverage for overhead, e.g. on very dense nodes
+type Sampling struct {
+	// ProcfsScanInterval is the time between two scans of the procfs process detector. Defaults to 30 seconds.
+	ProcfsScanInterval util.Duration `json:"procfsScanInterval,omitempty"`
+	// ClassificationWorkers is the number of processes we classify concurrently. Defaults to 25.
+	ClassificationWorkers int `json:"classificationWorkers,omitempty"`
+	// ClassificationQueueSize is the number of processes which may wait for their classification.
+	// We drop further processes until the queue drains. Defaults to 500.
+	ClassificationQueueSize int `json:"classificationQueueSize,omitempty"`
+	// MaxClassificationsPerSecond caps the rate at which we classify processes. Processes wait in the queue
+	// for their turn. Zero means no cap.
+	MaxClassificationsPerSecond float64 `json:"maxClassificationsPerSecond,omitempty"`
+	// ProcessSampleRatio is the fraction of processes we classify, e.g. 0.5 for every other one. Defaults to 1, i.e. all of them.
+	ProcessSampleRatio float64 `json:"processSampleRatio,omitempty"`
+}
+
+// SignatureFeed configures a remote feed of blocklists which agent smith polls and hot-reloads.
+// The blocklists of the feed are added to those of the config.
+type SignatureFeed struct {
+	// URL points to the directory of the feed, either https://host/path or gs://bucket/path.
+	// The directory contains latest.json, which names the current version, and one <version>.json
+	// per version. Both are SignatureFeedDocuments. Rolling back means pointing latest.json to an older version.
+	URL string `json:"url"`
+	// Version pins the feed to a version, ignoring latest.json
+	Version string `json:"version,omitempty"`
+	// Interval is the time between polls of the feed. Defaults to 5 minutes.
+	Interval util.Duration `json:"interval,omitempty"`
+}
+
+// SignatureFeedDocument is a version of the blocklists of a signature feed
+type SignatureFeedDocument struct {
+	Version    string      `json:"version"`
+	Blocklists *Blocklists `json:"blocklists,omitempty"`
+}
+
+// NetworkAnomalies configures the detection of suspicious connection patterns of workspaces.
+// It relies on the connections the ebpf process detector reports.
+type NetworkAnomalies struct {
+	// Window is the time over which we look at the connections of a workspace. Defaults to 10 minutes.
+	Window util.Duration `json:"window,omitempty"`
+	// MaxDestinations is the number of distinct addresses a workspace may connect to within the window.
+	// Zero disables the fan-out check.
+	MaxDestinations int `json:"maxDestinations,omitempty"`
+	// MiningPools are the IPs, CIDRs or host names of known mining pools. Host names are resolved periodically.
+	MiningPools []string `json:"miningPools,omitempty"`
+	// Fingerprints map a protocol to the destination ports it typically uses, e.g. "stratum": [3333, 4444, 14444]
+	Fingerprints map[string][]int `json:"fingerprints,omitempty"`
+	// MinFingerprintConnections is the number of connections matching a fingerprint within the window
+	// which constitute an infringement. Defaults to 3.
+	MinFingerprintConnections int `json:"minFingerprintConnections,omitempty"`
+}
+
+// MinerDetection configures the detection of cryptominers by their behaviour rather than their executable,
+// so that we catch renamed or recompiled miners, too. We sample the CPU usage of the processes of workspaces and
+// score each one by three signals: sustained full-core usage (0.4), all of its threads being busy (0.2) and
+// connections of its workspace to stratum ports (0.4). The latter needs the ebpf process detector.
+type MinerDetection struct {
+	// Interval is the time between two samples of the CPU usage of a process. Defaults to 30 seconds.
+	Interval util.Duration `json:"interval,omitempty"`
+	// SustainedFor is the time a process needs to use CoreThreshold cores without pause. Defaults to 10 minutes.
+	SustainedFor util.Duration `json:"sustainedFor,omitempty"`
+	// CoreThreshold is the number of cores a process needs to use to count as busy. Defaults to 0.9.
+	CoreThreshold float64 `json:"coreThreshold,omitempty"`
+	// MinThreads is the number of threads from which on a process whose threads are all busy looks like a miner. Defaults to 2.
+	MinThreads int `json:"minThreads,omitempty"`
+	// BusyThreadRatio is the number of cores used per thread from which on we consider all threads busy. Defaults to 0.8.
+	BusyThreadRatio float64 `json:"busyThreadRatio,omitempty"`
+	// StratumPorts are the destination ports of the stratum mining protocol. Defaults to 3333, 4444, 5555, 7777 and 14444.
+	StratumPorts []int `json:"stratumPorts,omitempty"`
+	// AuditConfidence is the confidence from which on we report an audit infringement. Defaults to 0.5.
+	AuditConfidence float64 `json:"auditConfidence,omitempty"`
+	// VeryConfidence is the confidence from which on we report a very severe infringement. Defaults to 0.8.
+	VeryConfidence float64 `json:"veryConfidence,omitempty"`
+}
+
+// AuditLog configures the export of all infringements and the penalties we applied for them.
+// Each record contains the hash of the previous one, so that gaps and modifications become evident.
+// All configured sinks receive all records.
+type AuditLog struct {
+	Webhook *AuditLogWebhook `json:"webhook,omitempty"`
+	Bucket  *AuditLogBucket  `json:"bucket,omitempty"`
+	Syslog  *AuditLogSyslog  `json:"syslog,omitempty"`
+
+	// FlushInterval is the time between two exports of the records. Defaults to 10 seconds.
+	FlushInterval util.Duration `json:"flushInterval,omitempty"`
+}
+
+// AuditLogWebhook receives the records of each export as JSON array in a POST request
+type AuditLogWebhook struct {
+	URL string `json:"url"`
+	// TokenFile contains a token we send as bearer token
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// AuditLogBucket receives the records of each export as JSON lines object in an S3-compatible bucket, e.g. on S3 or GCS
+type AuditLogBucket struct {
+	Endpoint            string `json:"endpoint"`
+	AccessKeyIDFile     string `json:"accessKeyFile"`
+	SecretAccessKeyFile string `json:"secretKeyFile"`
+	Secure              bool   `json:"secure,omitempty"`
+	Region              string `json:"region,omitempty"`
+
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to the name of the objects, which are <chain>/<sequence of the first record>.jsonl
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// AuditLogSyslog receives each record as JSON message
+type AuditLogSyslog struct {
+	// Network and Address of the syslog server, e.g. udp and logs.example.com:514. Both empty means the local syslog daemon.
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+	// Tag defaults to agent-smith
+	Tag string `json:"tag,omitempty"`
+}
 
-	ProbePath string `json:"probePath,omitempty"`
+// RuntimeAllowlist configures an allow-list we can change without redeploying agent smith, e.g. using "agent-smith allowlist".
+// The allow-list lives in a config map in the namespace of agent smith, which we poll.
+type RuntimeAllowlist struct {
+	// ConfigMap is the name of the config map. Defaults to agent-smith-allowlist.
+	ConfigMap string `json:"configMap,omitempty"`
+	// Interval is the time between polls of the config map. Defaults to 30 seconds.
+	Interval util.Duration `json:"interval,omitempty"`
+}
+
+const (
+	// DefaultAllowlistConfigMap is the name of the config map which holds the runtime allow-list
+	DefaultAllowlistConfigMap = "agent-smith-allowlist"
+	// AllowlistConfigMapKey is the key of the allow-list in its config map
+	AllowlistConfigMapKey = "allowlist.json"
+)
+
+// Allowlist exempts repositories, users and executables from penalties
+type Allowlist struct {
+	// Repositories are git remote URLs, which may start or end with a * wildcard
+	Repositories []AllowlistEntry `json:"repositories,omitempty"`
+	// Users are user IDs
+	Users []AllowlistEntry `json:"users,omitempty"`
+	// Binaries are the hex-encoded SHA256 hashes of executables
+	Binaries []AllowlistEntry `json:"binaries,omitempty"`
+}
+
+// AllowlistEntry is a single entry of an allow-list, together with the reason for its existence
+type AllowlistEntry struct {
+	Value   string    `json:"value"`
+	Reason  string    `json:"reason"`
+	AddedBy string    `json:"addedBy,omitempty"`
+	Added   time.Time `json:"added"`
+}
+
+// AllowlistKind names one of the lists of an allow-list
+type AllowlistKind string
+
+const (
+	AllowlistRepository AllowlistKind = "repository"
+	AllowlistUser       AllowlistKind = "user"
+	AllowlistBinary     AllowlistKind = "binary"
+)
+
+var validSHA256 = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+func (a *Allowlist) list(kind AllowlistKind) (*[]AllowlistEntry, error) {
+	switch kind {
+	case AllowlistRepository:
+		return &a.Repositories, nil
+	case AllowlistUser:
+		return &a.Users, nil
+	case AllowlistBinary:
+		return &a.Binaries, nil
+	default:
+		return nil, xerrors.Errorf("unknown allow-list %q", kind)
+	}
 }
 
-// Slackwebhooks holds slack notification configuration for different levels of penalty severity
+// Add adds an entry to the allow-list of the kind, replacing an entry with the same value
+func (a *Allowlist) Add(kind AllowlistKind, entry AllowlistEntry) error {
+	if kind == AllowlistBinary {
+		entry.Value = strings.ToLower(entry.Value)
+	}
+	err := entry.validate(kind)
+	if err != nil {
+		return err
+	}
+
+	l, err := a.list(kind)
+	if err != nil {
+		return err
+	}
+	for i, e := range *l {
+		if e.Value == entry.Value {
+			(*l)[i] = entry
+			return nil
+		}
+	}
+	*l = append(*l, entry)
+	return nil
+}
+
+// Remove removes the entry with the value from the allow-list of the kind. It returns false if there was no such entry.
+func (a *Allowlist) Remove(kind AllowlistKind, value string) (bool, error) {
+	l, err := a.list(kind)
+	if err != nil {
+		return false, err
+	}
+	if kind == AllowlistBinary {
+		value = strings.ToLower(value)
+	}
+	for i, e := range *l {
+		if e.Value == value {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Validate checks that all entries have a value and a reason, and that binaries are SHA256 hashes
+func (a *Allowlist) Validate() error {
+	for _, kind := range []AllowlistKind{AllowlistRepository, AllowlistUser, AllowlistBinary} {
+		l, _ := a.list(kind)
+		for _, e := range *l {
+			err := e.validate(kind)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e AllowlistEntry) validate(kind AllowlistKind) error {
+	if e.Value == "" {
+		return xerrors.Errorf("%s allow-list entry has no value", kind)
+	}
+	if e.Reason == "" {
+		return xerrors.Errorf("%s allow-list entry %s has no reason", kind, e.Value)
+	}
+	if kind == AllowlistBinary && !validSHA256.MatchString(e.Value) {
+		return xerrors.Errorf("binary allow-list entry %s is not a hex-encoded SHA256 hash", e.Value)
+	}
+	return nil
+}
+
+// FileAudit configures the reception of the file modifications in sensitive paths of workspaces which ws-daemon records
+type FileAudit struct {
+	// Socket is the unix socket ws-daemon ships the file modifications to
+	Socket string `json:"socket"`
+	// CorrelationWindow is the time during which we correlate a file modification with other infringements
+	// of the same workspace. Defaults to 10 minutes.
+	CorrelationWindow util.Duration `json:"correlationWindow,omitempty"`
+}
+
+// Slackwebhooks holds slack notification configuration for different levels of penalty severity.
+// Deprecated: use Notifications instead. Audit is notified about audit infringements, Warning about very severe ones.
 type SlackWebhooks struct {
 	Audit   string `json:"audit,omitempty"`
 	Warning string `json:"warning,omitempty"`
 }
 
+// Notifications configures whom we notify about infringements and the penalties we applied for them
+type Notifications struct {
+	Slack    []SlackNotification   `json:"slack,omitempty"`
+	Webhooks []WebhookNotification `json:"webhooks,omitempty"`
+}
+
+// SlackNotification posts a message to a Slack incoming webhook
+type SlackNotification struct {
+	URL string `json:"url"`
+	// Severities lists the severities, i.e. barely, audit or very, of the infringements we notify about.
+	// Defaults to audit and very.
+	Severities []string `json:"severities,omitempty"`
+}
+
+// WebhookNotification posts the infringements of a workspace as JSON to a generic webhook
+type WebhookNotification struct {
+	URL string `json:"url"`
+	// TokenFile contains a token we send as bearer token
+	TokenFile string `json:"tokenFile,omitempty"`
+	// Severities lists the severities, i.e. barely, audit or very, of the infringements we notify about.
+	// Defaults to audit and very.
+	Severities []string `json:"severities,omitempty"`
+}
+
+// ParseSeverities parses severities as they appear in the config, where audit stands for the empty common.SeverityAudit
+func ParseSeverities(severities []string) ([]common.Severity, error) {
+	res := make([]common.Severity, 0, len(severities))
+	for _, s := range severities {
+		switch s {
+		case "audit":
+			res = append(res, common.SeverityAudit)
+		case string(common.SeverityBarely), string(common.SeverityVery):
+			res = append(res, common.Severity(s))
+		default:
+			return nil, xerrors.Errorf("unknown severity %q", s)
+		}
+	}
+	return res, nil
+}
+
 // EgressTraffic configures an upper limit of allowed egress traffic over time
 type EgressTraffic struct {
 	WindowDuration util.Duration `json:"dt"`
@@ -237,6 +615,46 @@ func (b *Blocklists) Classifier() (res classifier.ProcessClassifier, err error)
 	return gres, nil
 }
 
+// Merge returns blocklists which list the entries of b and other on all levels, even those which are empty.
+// Their classifier thus always produces the same metrics, no matter which entries the blocklists have.
+func (b *Blocklists) Merge(other *Blocklists) *Blocklists {
+	merge := func(ls ...*PerLevelBlocklist) *PerLevelBlocklist {
+		res := &PerLevelBlocklist{}
+		for _, l := range ls {
+			if l == nil {
+				continue
+			}
+			res.Binaries = append(res.Binaries, l.Binaries...)
+			res.AllowList = append(res.AllowList, l.AllowList...)
+			res.Signatures = append(res.Signatures, l.Signatures...)
+		}
+		return res
+	}
+	if b == nil {
+		b = &Blocklists{}
+	}
+	if other == nil {
+		other = &Blocklists{}
+	}
+	return &Blocklists{
+		Barely: merge(b.Barely, other.Barely),
+		Audit:  merge(b.Audit, other.Audit),
+		Very:   merge(b.Very, other.Very),
+	}
+}
+
+// Validate checks the signatures of all levels
+func (b *Blocklists) Validate() error {
+	for level, bl := range b.Levels() {
+		for _, sig := range bl.Signatures {
+			if err := sig.Validate(); err != nil {
+				return xerrors.Errorf("invalid signature %s on level %s: %w", sig.Name, level, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (b *Blocklists) Levels() map[common.Severity]*PerLevelBlocklist {
 	res := make(map[common.Severity]*PerLevelBlocklist)
 	if b.Barely != nil {
@@ -0,0 +1,206 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"golang.org/x/xerrors"
+)
+
+// Validate checks the rules which span several parts of the config, e.g. that penalties which block users
+// come with access to the Gitpod API. Unlike the Validate functions of the parts it returns all problems
+// rather than the first one, so that one can fix them in one go. Each error starts with the path of the offending field.
+func (c *ServiceConfig) Validate() []error {
+	var res []error
+	fail := func(path, format string, args ...interface{}) {
+		res = append(res, xerrors.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	switch c.ProcessDetector {
+	case "", ProcessDetectorProcfs, ProcessDetectorEBPF:
+	default:
+		fail("processDetector", "unknown process detector %q - use %q or %q", c.ProcessDetector, ProcessDetectorProcfs, ProcessDetectorEBPF)
+	}
+
+	hasAPI := c.GitpodAPI.HostURL != "" && c.GitpodAPI.APIToken != ""
+	if c.GitpodAPI.HostURL != "" {
+		u, err := url.Parse(c.GitpodAPI.HostURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			fail("gitpodAPI.hostURL", "%q is not an absolute URL, e.g. https://gitpod.example.com", c.GitpodAPI.HostURL)
+		}
+		if c.GitpodAPI.APIToken == "" {
+			fail("gitpodAPI.apiToken", "is required when gitpodAPI.hostURL is set")
+		}
+	} else if c.GitpodAPI.APIToken != "" {
+		fail("gitpodAPI.hostURL", "is required when gitpodAPI.apiToken is set")
+	}
+
+	if c.Kubernetes.Enabled && c.Namespace == "" && c.KubernetesNamespace == "" {
+		fail("namespace", "is required when kubernetes.enabled is true - set it to the namespace agent smith runs in")
+	}
+	if c.RuntimeAllowlist != nil {
+		if !c.Kubernetes.Enabled {
+			fail("runtimeAllowlist", "requires kubernetes.enabled to be true")
+		}
+		if c.Namespace == "" && c.KubernetesNamespace == "" {
+			fail("runtimeAllowlist", "requires the namespace of its config map - set namespace")
+		}
+	}
+	if c.NetworkAnomalies != nil && c.ProcessDetector != ProcessDetectorEBPF {
+		fail("networkAnomalies", "requires processDetector %q, which reports the connections of workspaces", ProcessDetectorEBPF)
+	}
+	if c.FileAudit != nil && c.FileAudit.Socket == "" {
+		fail("fileAudit.socket", "is required")
+	}
+	if c.SignatureFeed != nil {
+		u, err := url.Parse(c.SignatureFeed.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "gs") {
+			fail("signatureFeed.url", "%q must be https:// or gs://", c.SignatureFeed.URL)
+		}
+	}
+	if c.Sampling != nil {
+		if c.Sampling.ProcessSampleRatio < 0 || c.Sampling.ProcessSampleRatio > 1 {
+			fail("sampling.processSampleRatio", "%v is not between 0 and 1", c.Sampling.ProcessSampleRatio)
+		}
+		if c.Sampling.ClassificationWorkers < 0 {
+			fail("sampling.classificationWorkers", "must not be negative")
+		}
+		if c.Sampling.ClassificationQueueSize < 0 {
+			fail("sampling.classificationQueueSize", "must not be negative")
+		}
+		if c.Sampling.MaxClassificationsPerSecond < 0 {
+			fail("sampling.maxClassificationsPerSecond", "must not be negative")
+		}
+		if c.Sampling.ProcfsScanInterval != 0 && c.ProcessDetector == ProcessDetectorEBPF {
+			fail("sampling.procfsScanInterval", "has no effect with processDetector %q - remove it", ProcessDetectorEBPF)
+		}
+	}
+	if c.Blocklists != nil {
+		if err := c.Blocklists.Validate(); err != nil {
+			fail("blocklists", "%v", err)
+		}
+	}
+	if c.Notifications != nil {
+		for i, n := range c.Notifications.Slack {
+			if _, err := ParseSeverities(n.Severities); err != nil {
+				fail(fmt.Sprintf("notifications.slack[%d].severities", i), "%v - use barely, audit or very", err)
+			}
+		}
+		for i, n := range c.Notifications.Webhooks {
+			if _, err := ParseSeverities(n.Severities); err != nil {
+				fail(fmt.Sprintf("notifications.webhooks[%d].severities", i), "%v - use barely, audit or very", err)
+			}
+		}
+	}
+
+	// rules lists the enforcement rules by their path in the config
+	rules := map[string]EnforcementRules{"enforcement.default": c.Enforcement.Default.orDefault()}
+	for repo, r := range c.Enforcement.PerRepo {
+		rules[fmt.Sprintf("enforcement.perRepo[%q]", repo)] = r
+	}
+	paths := make([]string, 0, len(rules))
+	for p := range rules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		r := rules[path]
+		if err := r.Validate(); err != nil {
+			fail(path, "%v", err)
+			continue
+		}
+
+		kinds := make([]string, 0, len(r))
+		for k := range r {
+			kinds = append(kinds, string(k))
+		}
+		sort.Strings(kinds)
+		for _, k := range kinds {
+			gk := GradedInfringementKind(k)
+			if !c.detects(gk) {
+				continue
+			}
+			switch r[gk] {
+			case PenaltyStopWorkspaceAndBlockUser:
+				if !hasAPI {
+					fail(path, "%q blocks users, which requires gitpodAPI.hostURL and gitpodAPI.apiToken - configure both or choose another penalty", k)
+				}
+			case PenaltyLimitCPU:
+				if c.Enforcement.CPULimitPenalty == "" || !c.Kubernetes.Enabled {
+					fail(path, "%q limits the CPU, which requires enforcement.cpuLimitPenalty and kubernetes.enabled - configure both or choose another penalty", k)
+				}
+			}
+		}
+	}
+
+	detectsAnything := c.detectsAnything()
+	for i, p := range c.Enforcement.Policies {
+		path := fmt.Sprintf("enforcement.policies[%d]", i)
+		if err := p.Validate(); err != nil {
+			fail(path, "%v", err)
+			continue
+		}
+		for _, pk := range p.Escalation {
+			if pk == PenaltyStopWorkspaceAndBlockUser && !hasAPI && detectsAnything {
+				fail(path, "escalates to %q, which requires gitpodAPI.hostURL and gitpodAPI.apiToken - configure both or end the escalation earlier", pk)
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// orDefault returns the enforcement rules which apply, i.e. the default ones if er is nil
+func (er *EnforcementRules) orDefault() EnforcementRules {
+	if er == nil {
+		return DefaultEnforcementRules()
+	}
+	return *er
+}
+
+// detects returns true if the config makes us detect infringements of the graded kind. Penalties for others never apply.
+func (c *ServiceConfig) detects(gk GradedInfringementKind) bool {
+	kind, err := gk.Kind()
+	if err != nil {
+		return false
+	}
+	switch kind {
+	case InfringementExec:
+		if c.SignatureFeed != nil {
+			return true
+		}
+		if c.Blocklists == nil {
+			return false
+		}
+		bl, ok := c.Blocklists.Levels()[gk.Severity()]
+		return ok && (len(bl.Binaries) > 0 || len(bl.Signatures) > 0)
+	case InfringementExcessiveEgress:
+		return c.EgressTraffic != nil
+	case InfringementNetworkAnomaly:
+		return c.NetworkAnomalies != nil
+	case InfringementMinerBehaviour:
+		return c.MinerDetection != nil
+	default:
+		return false
+	}
+}
+
+// detectsAnything returns true if the config makes us detect infringements of any kind
+func (c *ServiceConfig) detectsAnything() bool {
+	for _, kind := range []InfringementKind{InfringementExec, InfringementExcessiveEgress, InfringementNetworkAnomaly, InfringementMinerBehaviour} {
+		for _, sev := range []common.Severity{common.SeverityBarely, common.SeverityAudit, common.SeverityVery} {
+			if c.detects(GradeKind(kind, sev)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	var (
+		api        = GitpodAPI{HostURL: "https://gitpod.example.com", APIToken: "token"}
+		veryBinary = &Blocklists{Very: &PerLevelBlocklist{Binaries: []string{"xmrig"}}}
+	)
+	tests := []struct {
+		Name        string
+		Config      ServiceConfig
+		Expectation []string
+	}{
+		{Name: "empty", Config: ServiceConfig{}},
+		{
+			Name:   "very blocklist with API",
+			Config: ServiceConfig{Config: Config{GitpodAPI: api, Blocklists: veryBinary}},
+		},
+		{
+			Name:        "very blocklist without API",
+			Config:      ServiceConfig{Config: Config{Blocklists: veryBinary}},
+			Expectation: []string{`enforcement.default: "very blocklisted executable" blocks users, which requires gitpodAPI.hostURL and gitpodAPI.apiToken - configure both or choose another penalty`},
+		},
+		{
+			Name: "very blocklist without API, but no penalty",
+			Config: ServiceConfig{Config: Config{
+				Blocklists:  veryBinary,
+				Enforcement: Enforcement{Default: &EnforcementRules{GradeKind(InfringementExec, common.SeverityVery): PenaltyStopWorkspace}},
+			}},
+		},
+		{
+			Name: "barely blocklist without CPU limit",
+			Config: ServiceConfig{Config: Config{
+				GitpodAPI:  api,
+				Blocklists: &Blocklists{Barely: &PerLevelBlocklist{Binaries: []string{"nc"}}},
+			}},
+			Expectation: []string{`enforcement.default: "barely blocklisted executable" limits the CPU, which requires enforcement.cpuLimitPenalty and kubernetes.enabled - configure both or choose another penalty`},
+		},
+		{
+			Name: "per-repo rules",
+			Config: ServiceConfig{Config: Config{
+				Blocklists: veryBinary,
+				GitpodAPI:  api,
+				Enforcement: Enforcement{PerRepo: map[string]EnforcementRules{
+					"https://github.com/gitpod-io/*": {"very blocklisted binaries": PenaltyNotify},
+				}},
+			}},
+			Expectation: []string{`enforcement.perRepo["https://github.com/gitpod-io/*"]: very blocklisted binaries: unknown kind`},
+		},
+		{
+			Name: "policy blocks users without API",
+			Config: ServiceConfig{Config: Config{
+				MinerDetection: &MinerDetection{},
+				Enforcement: Enforcement{Policies: []PenaltyPolicy{
+					{Name: "everyone", Escalation: []PenaltyKind{PenaltyNotify, PenaltyStopWorkspaceAndBlockUser}},
+				}},
+			}},
+			Expectation: []string{`enforcement.policies[0]: escalates to "stop workspace and block user", which requires gitpodAPI.hostURL and gitpodAPI.apiToken - configure both or end the escalation earlier`},
+		},
+		{
+			Name: "incomplete API",
+			Config: ServiceConfig{Config: Config{
+				GitpodAPI: GitpodAPI{HostURL: "gitpod.example.com"},
+			}},
+			Expectation: []string{
+				`gitpodAPI.hostURL: "gitpod.example.com" is not an absolute URL, e.g. https://gitpod.example.com`,
+				"gitpodAPI.apiToken: is required when gitpodAPI.hostURL is set",
+			},
+		},
+		{
+			Name: "runtime allow-list without Kubernetes",
+			Config: ServiceConfig{Config: Config{
+				RuntimeAllowlist: &RuntimeAllowlist{},
+			}},
+			Expectation: []string{
+				"runtimeAllowlist: requires kubernetes.enabled to be true",
+				"runtimeAllowlist: requires the namespace of its config map - set namespace",
+			},
+		},
+		{
+			Name: "network anomalies with procfs",
+			Config: ServiceConfig{Config: Config{
+				ProcessDetector:  ProcessDetectorProcfs,
+				NetworkAnomalies: &NetworkAnomalies{},
+			}},
+			Expectation: []string{`networkAnomalies: requires processDetector "ebpf", which reports the connections of workspaces`},
+		},
+		{
+			Name: "unknown severity",
+			Config: ServiceConfig{Config: Config{
+				Notifications: &Notifications{Slack: []SlackNotification{{URL: "https://hooks.slack.com/foo", Severities: []string{"high"}}}},
+			}},
+			Expectation: []string{`notifications.slack[0].severities: unknown severity "high" - use barely, audit or very`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act []string
+			for _, err := range test.Config.Validate() {
+				act = append(act, err.Error())
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+// The agent-smith sensor reports execve, connect and setuid calls to user space through a ring buffer.
+// It relies on CO-RE: the kernel structs below only declare the fields we read, and libbpf-style
+// relocations adjust their offsets to the running kernel when the probe is loaded.
+
+typedef unsigned char __u8;
+typedef unsigned short __u16;
+typedef unsigned int __u32;
+typedef unsigned long long __u64;
+typedef int pid_t;
+
+#include <bpf/bpf_helpers.h>
+#include <bpf/bpf_core_read.h>
+
+#define TASK_COMM_LEN 16
+#define MAX_FILENAME_LEN 256
+
+#define AF_INET 2
+#define AF_INET6 10
+
+enum event_type {
+    EVENT_EXEC = 1,
+    EVENT_CONNECT = 2,
+    EVENT_SETUID = 3,
+};
+
+// event must match sensorEvent in ebpf.go
+struct event {
+    __u32 type;
+    __u32 pid;
+    __u32 ppid;
+    __u32 uid;
+    __u64 cgroup_id;
+    __u32 target_uid;
+    __u16 family;
+    __u16 port;
+    __u8 addr[16];
+    char comm[TASK_COMM_LEN];
+    char filename[MAX_FILENAME_LEN];
+};
+
+struct task_struct {
+    pid_t tgid;
+    struct task_struct *real_parent;
+} __attribute__((preserve_access_index));
+
+struct trace_event_raw_sched_process_exec {
+    __u32 __data_loc_filename;
+} __attribute__((preserve_access_index));
+
+struct trace_event_raw_sys_enter {
+    long int id;
+    unsigned long args[6];
+} __attribute__((preserve_access_index));
+
+struct sockaddr_in {
+    __u16 sin_family;
+    __u16 sin_port;
+    __u8 sin_addr[4];
+};
+
+struct sockaddr_in6 {
+    __u16 sin6_family;
+    __u16 sin6_port;
+    __u32 sin6_flowinfo;
+    __u8 sin6_addr[16];
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_RINGBUF);
+    __uint(max_entries, 1 << 24);
+} events SEC(".maps");
+
+static __always_inline struct event *new_event(__u32 type)
+{
+    struct event *e = bpf_ringbuf_reserve(&events, sizeof(struct event), 0);
+    if (!e)
+        return 0;
+
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+    e->type = type;
+    e->pid = bpf_get_current_pid_tgid() >> 32;
+    e->ppid = BPF_CORE_READ(task, real_parent, tgid);
+    e->uid = bpf_get_current_uid_gid();
+    e->cgroup_id = bpf_get_current_cgroup_id();
+    e->target_uid = 0;
+    e->family = 0;
+    e->port = 0;
+    __builtin_memset(e->addr, 0, sizeof(e->addr));
+    __builtin_memset(e->filename, 0, sizeof(e->filename));
+    bpf_get_current_comm(&e->comm, sizeof(e->comm));
+    return e;
+}
+
+SEC("tracepoint/sched/sched_process_exec")
+int handle_exec(struct trace_event_raw_sched_process_exec *ctx)
+{
+    struct event *e = new_event(EVENT_EXEC);
+    if (!e)
+        return 0;
+
+    unsigned int off = BPF_CORE_READ(ctx, __data_loc_filename) & 0xFFFF;
+    bpf_probe_read_str(&e->filename, sizeof(e->filename), (void *)ctx + off);
+
+    bpf_ringbuf_submit(e, 0);
+    return 0;
+}
+
+SEC("tracepoint/syscalls/sys_enter_connect")
+int handle_connect(struct trace_event_raw_sys_enter *ctx)
+{
+    void *uaddr = (void *)BPF_CORE_READ(ctx, args[1]);
+    __u16 family = 0;
+    if (bpf_probe_read_user(&family, sizeof(family), uaddr) < 0)
+        return 0;
+    if (family != AF_INET && family != AF_INET6)
+        return 0;
+
+    struct event *e = new_event(EVENT_CONNECT);
+    if (!e)
+        return 0;
+
+    e->family = family;
+    if (family == AF_INET) {
+        struct sockaddr_in sa = {};
+        bpf_probe_read_user(&sa, sizeof(sa), uaddr);
+        e->port = sa.sin_port;
+        __builtin_memcpy(e->addr, sa.sin_addr, sizeof(sa.sin_addr));
+    } else {
+        struct sockaddr_in6 sa = {};
+        bpf_probe_read_user(&sa, sizeof(sa), uaddr);
+        e->port = sa.sin6_port;
+        __builtin_memcpy(e->addr, sa.sin6_addr, sizeof(sa.sin6_addr));
+    }
+
+    bpf_ringbuf_submit(e, 0);
+    return 0;
+}
+
+SEC("tracepoint/syscalls/sys_enter_setuid")
+int handle_setuid(struct trace_event_raw_sys_enter *ctx)
+{
+    struct event *e = new_event(EVENT_SETUID);
+    if (!e)
+        return 0;
+
+    e->target_uid = (__u32)BPF_CORE_READ(ctx, args[0]);
+
+    bpf_ringbuf_submit(e, 0);
+    return 0;
+}
+
+char LICENSE[] SEC("license") = "GPL";
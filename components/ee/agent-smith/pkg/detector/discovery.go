@@ -6,6 +6,8 @@ package detector
 
 import (
 	"context"
+	"net"
+	"time"
 
 	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,6 +15,8 @@ import (
 
 // Process describes a process ont the node that might warant closer inspection
 type Process struct {
+	// PID is the ID of the process in the PID namespace of agent smith
+	PID         int
 	Path        string
 	CommandLine []string
 	Kind        ProcessKind
@@ -28,6 +32,22 @@ type ProcessDetector interface {
 	DiscoverProcesses(ctx context.Context) (<-chan Process, error)
 }
 
+// Connection describes an outgoing connection of a workspace
+type Connection struct {
+	IP   net.IP
+	Port int
+	// Comm is the name of the process which connected
+	Comm      string
+	Workspace *common.Workspace
+	Time      time.Time
+}
+
+// ConnectionDetector discovers the outgoing connections of workspaces
+type ConnectionDetector interface {
+	// DiscoverConnections starts the discovery of connections
+	DiscoverConnections(ctx context.Context) (<-chan Connection, error)
+}
+
 type ProcessKind int
 
 const (
@@ -0,0 +1,425 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package detector
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"golang.org/x/xerrors"
+)
+
+// processTree provides the ancestry of individual processes without scanning all of procfs
+type processTree interface {
+	discoverableProcFS
+	Parent(pid int) (int, error)
+	Cmdline(pid int) ([]string, error)
+}
+
+var _ processTree = realProcfs{}
+
+func (fs realProcfs) Parent(pid int) (int, error) {
+	stat, err := statProc(pid)
+	if err != nil {
+		return 0, err
+	}
+	return stat.PPID, nil
+}
+
+func (fs realProcfs) Cmdline(pid int) ([]string, error) {
+	p, err := procfs.FS(fs).Proc(pid)
+	if err != nil {
+		return nil, err
+	}
+	return p.CmdLine()
+}
+
+type sensorEventType uint32
+
+const (
+	sensorEventExec    sensorEventType = 1
+	sensorEventConnect sensorEventType = 2
+	sensorEventSetuid  sensorEventType = 3
+)
+
+func (t sensorEventType) String() string {
+	switch t {
+	case sensorEventExec:
+		return "exec"
+	case sensorEventConnect:
+		return "connect"
+	case sensorEventSetuid:
+		return "setuid"
+	default:
+		return "unknown"
+	}
+}
+
+// sensorEvent must match struct event in bpf/sensor.bpf.c
+type sensorEvent struct {
+	Type      sensorEventType
+	PID       uint32
+	PPID      uint32
+	UID       uint32
+	CgroupID  uint64
+	TargetUID uint32
+	Family    uint16
+	// Port is in network byte order
+	Port     [2]byte
+	Addr     [16]byte
+	Comm     [16]byte
+	Filename [256]byte
+}
+
+const (
+	afInet  = 2
+	afInet6 = 10
+)
+
+func parseSensorEvent(raw []byte) (*sensorEvent, error) {
+	var evt sensorEvent
+	if len(raw) < binary.Size(evt) {
+		return nil, xerrors.Errorf("event too short: %d bytes", len(raw))
+	}
+	err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &evt)
+	if err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+// Destination returns the address a connect event connects to
+func (evt *sensorEvent) Destination() string {
+	var ip net.IP
+	switch evt.Family {
+	case afInet:
+		ip = net.IP(evt.Addr[:4])
+	case afInet6:
+		ip = net.IP(evt.Addr[:])
+	default:
+		return ""
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(binary.BigEndian.Uint16(evt.Port[:]))))
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+const (
+	// maxAncestry is the number of parents we walk up until we give up finding a supervisor
+	maxAncestry = 64
+	// negativeAttributionTTL is the time we remember that a cgroup does not belong to a workspace.
+	// Workspace containers start without a supervisor, hence we must not remember this forever.
+	negativeAttributionTTL = 10 * time.Second
+)
+
+type attribution struct {
+	Workspace *common.Workspace
+	Until     time.Time
+}
+
+var (
+	_ ProcessDetector    = &EBPFDetector{}
+	_ ConnectionDetector = &EBPFDetector{}
+)
+
+// EBPFDetector detects processes of workspaces using an eBPF sensor which reports execve, connect and setuid
+// calls as they happen. Compared to the ProcfsDetector it has lower overhead and does not miss short-lived processes.
+type EBPFDetector struct {
+	mu    sync.RWMutex
+	ps    chan Process
+	conns chan Connection
+
+	eventsCounterVec          *prometheus.CounterVec
+	droppedCounter            prometheus.Counter
+	droppedConnectionsCounter prometheus.Counter
+	attributionCounter        *prometheus.CounterVec
+
+	probePath string
+	proc      processTree
+	// workspaces caches the attribution of cgroups to workspaces
+	workspaces *lru.Cache
+	// seen caches the processes we've reported already
+	seen *lru.Cache
+}
+
+// NewEBPFDetector creates a new eBPF detector loading the sensor from probePath
+func NewEBPFDetector(probePath string) (*EBPFDetector, error) {
+	p, err := procfs.NewFS("/proc")
+	if err != nil {
+		return nil, err
+	}
+	return newEBPFDetector(probePath, realProcfs(p))
+}
+
+func newEBPFDetector(probePath string, proc processTree) (*EBPFDetector, error) {
+	workspaces, err := lru.New(2000)
+	if err != nil {
+		return nil, err
+	}
+	seen, err := lru.New(2000)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EBPFDetector{
+		eventsCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gitpod",
+			Subsystem: "agent_smith_ebpf_detector",
+			Name:      "events_total",
+			Help:      "number of events received from the eBPF sensor",
+		}, []string{"type"}),
+		droppedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gitpod",
+			Subsystem: "agent_smith_ebpf_detector",
+			Name:      "dropped_processes_total",
+			Help:      "number of processes dropped because the consumer could not keep up",
+		}),
+		droppedConnectionsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gitpod",
+			Subsystem: "agent_smith_ebpf_detector",
+			Name:      "dropped_connections_total",
+			Help:      "number of connections dropped because the consumer could not keep up",
+		}),
+		attributionCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gitpod",
+			Subsystem: "agent_smith_ebpf_detector",
+			Name:      "attribution_total",
+			Help:      "attribution of events to workspaces",
+		}, []string{"result"}),
+		probePath:  probePath,
+		proc:       proc,
+		workspaces: workspaces,
+		seen:       seen,
+	}, nil
+}
+
+func (det *EBPFDetector) Describe(d chan<- *prometheus.Desc) {
+	det.eventsCounterVec.Describe(d)
+	det.droppedCounter.Describe(d)
+	det.droppedConnectionsCounter.Describe(d)
+	det.attributionCounter.Describe(d)
+}
+
+func (det *EBPFDetector) Collect(m chan<- prometheus.Metric) {
+	det.eventsCounterVec.Collect(m)
+	det.droppedCounter.Collect(m)
+	det.droppedConnectionsCounter.Collect(m)
+	det.attributionCounter.Collect(m)
+}
+
+// DiscoverProcesses loads the eBPF sensor and starts process discovery. Must not be called more than once.
+func (det *EBPFDetector) DiscoverProcesses(ctx context.Context) (<-chan Process, error) {
+	det.mu.Lock()
+	defer det.mu.Unlock()
+
+	if det.ps != nil {
+		return nil, fmt.Errorf("already discovering processes")
+	}
+
+	events, err := loadSensor(ctx, det.probePath)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot load eBPF sensor: %w", err)
+	}
+
+	res := make(chan Process, 100)
+	det.ps = res
+	go func() {
+		for raw := range events {
+			evt, err := parseSensorEvent(raw)
+			if err != nil {
+				log.WithError(err).Warn("cannot parse eBPF sensor event")
+				continue
+			}
+			det.handleEvent(evt, time.Now())
+		}
+	}()
+	log.WithField("probe", det.probePath).Info("eBPF detector started")
+
+	return res, nil
+}
+
+func (det *EBPFDetector) handleEvent(evt *sensorEvent, now time.Time) {
+	det.eventsCounterVec.WithLabelValues(evt.Type.String()).Inc()
+
+	if evt.Type == sensorEventConnect {
+		det.reportConnection(evt, now)
+	}
+
+	pid := int(evt.PID)
+	if evt.Type == sensorEventExec {
+		// the process runs a new executable which we have to look at again
+		det.seen.Remove(pid)
+	} else if _, ok := det.seen.Get(pid); ok {
+		return
+	}
+
+	proc, ok := det.attribute(evt, now)
+	if !ok {
+		return
+	}
+	det.seen.Add(pid, struct{}{})
+
+	log.WithField("proc", proc).WithFields(log.OWI(proc.Workspace.OwnerID, proc.Workspace.WorkspaceID, proc.Workspace.InstanceID)).
+		WithField("event", evt.Type.String()).
+		WithField("comm", cString(evt.Comm[:])).
+		WithField("destination", evt.Destination()).
+		WithField("targetUID", evt.TargetUID).
+		Debug("found process")
+
+	select {
+	case det.ps <- *proc:
+	default:
+		// we must never block the ring buffer reader, otherwise the kernel drops events
+		det.droppedCounter.Inc()
+	}
+}
+
+// reportConnection forwards a connect event of a workspace to the connection consumer, if there is one
+func (det *EBPFDetector) reportConnection(evt *sensorEvent, now time.Time) {
+	det.mu.RLock()
+	conns := det.conns
+	det.mu.RUnlock()
+	if conns == nil {
+		return
+	}
+
+	var ip net.IP
+	switch evt.Family {
+	case afInet:
+		ip = net.IP(append([]byte(nil), evt.Addr[:4]...))
+	case afInet6:
+		ip = net.IP(append([]byte(nil), evt.Addr[:]...))
+	default:
+		return
+	}
+	ws := det.workspaceOf(evt, now)
+	if ws == nil || ws.PID == int(evt.PID) {
+		// we're only interested in user workloads, not supervisor
+		return
+	}
+
+	select {
+	case conns <- Connection{
+		IP:        ip,
+		Port:      int(binary.BigEndian.Uint16(evt.Port[:])),
+		Comm:      cString(evt.Comm[:]),
+		Workspace: ws,
+		Time:      now,
+	}:
+	default:
+		det.droppedConnectionsCounter.Inc()
+	}
+}
+
+// DiscoverConnections reports the outgoing connections of workspaces. Must be called before DiscoverProcesses
+// to not miss any connections, and must not be called more than once.
+func (det *EBPFDetector) DiscoverConnections(ctx context.Context) (<-chan Connection, error) {
+	det.mu.Lock()
+	defer det.mu.Unlock()
+
+	if det.conns != nil {
+		return nil, fmt.Errorf("already discovering connections")
+	}
+	res := make(chan Connection, 500)
+	det.conns = res
+	return res, nil
+}
+
+// attribute finds the workspace the process of an event belongs to. Only user workloads are attributed,
+// i.e. processes below a workspace's supervisor.
+func (det *EBPFDetector) attribute(evt *sensorEvent, now time.Time) (*Process, bool) {
+	pid := int(evt.PID)
+	cmdline, err := det.proc.Cmdline(pid)
+	if err != nil || len(cmdline) == 0 {
+		// the process is gone already - we still know what it executed though
+		if fn := cString(evt.Filename[:]); fn != "" {
+			cmdline = []string{fn}
+		}
+	}
+	if isSupervisor(cmdline) {
+		return nil, false
+	}
+	ws := det.workspaceOf(evt, now)
+	if ws == nil {
+		return nil, false
+	}
+
+	return &Process{
+		PID:         pid,
+		Path:        filepath.Join("proc", strconv.Itoa(pid), "exe"),
+		CommandLine: cmdline,
+		Kind:        ProcessUserWorkload,
+		Workspace:   ws,
+	}, true
+}
+
+// workspaceOf finds the workspace the process of an event runs in, or returns nil if it's not part of a workspace
+func (det *EBPFDetector) workspaceOf(evt *sensorEvent, now time.Time) *common.Workspace {
+	if a, ok := det.workspaces.Get(evt.CgroupID); ok {
+		a := a.(attribution)
+		if a.Workspace != nil {
+			det.attributionCounter.WithLabelValues("cached").Inc()
+			return a.Workspace
+		}
+		if now.Before(a.Until) {
+			det.attributionCounter.WithLabelValues("cached_none").Inc()
+			return nil
+		}
+	}
+
+	ws := findWorkspaceOf(det.proc, int(evt.PPID))
+	if ws == nil {
+		det.attributionCounter.WithLabelValues("none").Inc()
+		det.workspaces.Add(evt.CgroupID, attribution{Until: now.Add(negativeAttributionTTL)})
+		return nil
+	}
+	det.attributionCounter.WithLabelValues("found").Inc()
+	det.workspaces.Add(evt.CgroupID, attribution{Workspace: ws})
+	return ws
+}
+
+// findWorkspaceOf walks up the ancestry of a process, starting at its parent ppid, until it finds a supervisor.
+// The parent of that supervisor is workspacekit, whose environment tells us which workspace we're in.
+func findWorkspaceOf(proc processTree, ppid int) *common.Workspace {
+	for i := 0; i < maxAncestry && ppid > 1; i++ {
+		cmdline, err := proc.Cmdline(ppid)
+		if err != nil {
+			log.WithField("pid", ppid).WithError(err).Debug("cannot get commandline of process")
+			return nil
+		}
+		grandparent, err := proc.Parent(ppid)
+		if err != nil {
+			log.WithField("pid", ppid).WithError(err).Debug("cannot stat process")
+			return nil
+		}
+		if isSupervisor(cmdline) {
+			ws := extractWorkspaceFromWorkspacekit(proc, grandparent)
+			if ws != nil {
+				// extractWorkspaceFromWorkspacekit sets the PID to workspacekit, but we want to point to supervisor
+				ws.PID = ppid
+			}
+			return ws
+		}
+		ppid = grandparent
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+//go:build linux
+
+package detector
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/ringbuf"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+)
+
+// sensorObjects are the programs and maps of bpf/sensor.bpf.c
+type sensorObjects struct {
+	HandleExec    *ebpf.Program `ebpf:"handle_exec"`
+	HandleConnect *ebpf.Program `ebpf:"handle_connect"`
+	HandleSetuid  *ebpf.Program `ebpf:"handle_setuid"`
+	Events        *ebpf.Map     `ebpf:"events"`
+}
+
+func (objs *sensorObjects) Close() {
+	for _, c := range []interface{ Close() error }{objs.HandleExec, objs.HandleConnect, objs.HandleSetuid, objs.Events} {
+		c.Close()
+	}
+}
+
+// loadSensor loads the sensor from probePath, attaches it to its tracepoints and returns the raw events it reports.
+// The sensor is detached once ctx is canceled.
+func loadSensor(ctx context.Context, probePath string) (<-chan []byte, error) {
+	// kernels before 5.11 account eBPF maps against the memlock limit
+	err := unix.Setrlimit(unix.RLIMIT_MEMLOCK, &unix.Rlimit{Cur: unix.RLIM_INFINITY, Max: unix.RLIM_INFINITY})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot raise memlock limit: %w", err)
+	}
+
+	spec, err := ebpf.LoadCollectionSpec(probePath)
+	if err != nil {
+		return nil, err
+	}
+	var objs sensorObjects
+	err = spec.LoadAndAssign(&objs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []link.Link
+	cleanup := func() {
+		for _, l := range links {
+			l.Close()
+		}
+		objs.Close()
+	}
+	for _, tp := range []struct {
+		Group, Name string
+		Prog        *ebpf.Program
+	}{
+		{"sched", "sched_process_exec", objs.HandleExec},
+		{"syscalls", "sys_enter_connect", objs.HandleConnect},
+		{"syscalls", "sys_enter_setuid", objs.HandleSetuid},
+	} {
+		l, err := link.Tracepoint(tp.Group, tp.Name, tp.Prog)
+		if err != nil {
+			cleanup()
+			return nil, xerrors.Errorf("cannot attach to %s/%s: %w", tp.Group, tp.Name, err)
+		}
+		links = append(links, l)
+	}
+
+	rd, err := ringbuf.NewReader(objs.Events)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		rd.Close()
+	}()
+
+	res := make(chan []byte, 1000)
+	go func() {
+		defer close(res)
+		defer cleanup()
+
+		for {
+			rec, err := rd.Read()
+			if errors.Is(err, ringbuf.ErrClosed) {
+				return
+			}
+			if err != nil {
+				log.WithError(err).Warn("cannot read from eBPF ring buffer")
+				continue
+			}
+			res <- rec.RawSample
+		}
+	}()
+	return res, nil
+}
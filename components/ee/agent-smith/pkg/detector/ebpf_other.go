@@ -0,0 +1,17 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+//go:build !linux
+
+package detector
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+)
+
+func loadSensor(ctx context.Context, probePath string) (<-chan []byte, error) {
+	return nil, xerrors.Errorf("the eBPF sensor is only supported on Linux")
+}
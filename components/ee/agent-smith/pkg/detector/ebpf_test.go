@@ -0,0 +1,296 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the Gitpod Enterprise Source Code License,
+// See License.enterprise.txt in the project root folder.
+
+package detector
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/common"
+	"github.com/google/go-cmp/cmp"
+)
+
+func (p memoryProc) Parent(pid int) (int, error) {
+	proc, ok := p[pid]
+	if !ok {
+		return 0, fmt.Errorf("process does not exist")
+	}
+	if proc.P.Parent == nil {
+		return 0, nil
+	}
+	return proc.P.Parent.PID, nil
+}
+
+func (p memoryProc) Cmdline(pid int) ([]string, error) {
+	proc, ok := p[pid]
+	if !ok {
+		return nil, fmt.Errorf("process does not exist")
+	}
+	return proc.P.Cmdline, nil
+}
+
+func encodeSensorEvent(t *testing.T, evt sensorEvent) []byte {
+	buf := bytes.NewBuffer(nil)
+	err := binary.Write(buf, binary.LittleEndian, evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseSensorEvent(t *testing.T) {
+	type Expectation struct {
+		Error       string
+		Type        string
+		PID         uint32
+		Comm        string
+		Filename    string
+		Destination string
+	}
+	tests := []struct {
+		Name        string
+		Input       func(t *testing.T) []byte
+		Expectation Expectation
+	}{
+		{
+			Name: "exec",
+			Input: func(t *testing.T) []byte {
+				evt := sensorEvent{Type: sensorEventExec, PID: 42}
+				copy(evt.Comm[:], "bash")
+				copy(evt.Filename[:], "/usr/bin/xmrig")
+				return encodeSensorEvent(t, evt)
+			},
+			Expectation: Expectation{Type: "exec", PID: 42, Comm: "bash", Filename: "/usr/bin/xmrig"},
+		},
+		{
+			Name: "connect ipv4",
+			Input: func(t *testing.T) []byte {
+				evt := sensorEvent{Type: sensorEventConnect, PID: 42, Family: afInet, Port: [2]byte{0x0d, 0x05}}
+				copy(evt.Addr[:], []byte{10, 0, 0, 1})
+				return encodeSensorEvent(t, evt)
+			},
+			Expectation: Expectation{Type: "connect", PID: 42, Destination: "10.0.0.1:3333"},
+		},
+		{
+			Name: "connect ipv6",
+			Input: func(t *testing.T) []byte {
+				evt := sensorEvent{Type: sensorEventConnect, PID: 42, Family: afInet6, Port: [2]byte{0x01, 0xbb}}
+				evt.Addr[15] = 1
+				return encodeSensorEvent(t, evt)
+			},
+			Expectation: Expectation{Type: "connect", PID: 42, Destination: "[::1]:443"},
+		},
+		{
+			Name: "too short",
+			Input: func(t *testing.T) []byte {
+				return make([]byte, 10)
+			},
+			Expectation: Expectation{Error: "event too short: 10 bytes"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			evt, err := parseSensorEvent(test.Input(t))
+			if err != nil {
+				act.Error = err.Error()
+			} else {
+				act.Type = evt.Type.String()
+				act.PID = evt.PID
+				act.Comm = cString(evt.Comm[:])
+				act.Filename = cString(evt.Filename[:])
+				act.Destination = evt.Destination()
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected event (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEBPFDetectorHandleEvent(t *testing.T) {
+	proc := (func() memoryProc {
+		res := make(map[int]memoryProcEntry)
+		res[1] = memoryProcEntry{P: &process{PID: 1}}
+		res[2] = memoryProcEntry{
+			P:   &process{PID: 2, Parent: res[1].P, Cmdline: []string{"/proc/self/exe", "ring1"}},
+			Env: []string{"GITPOD_WORKSPACE_ID=foobar", "GITPOD_INSTANCE_ID=baz"},
+		}
+		res[3] = memoryProcEntry{P: &process{PID: 3, Parent: res[2].P, Cmdline: []string{"supervisor", "init"}}}
+		res[4] = memoryProcEntry{P: &process{PID: 4, Parent: res[3].P, Cmdline: []string{"bash"}}}
+		res[5] = memoryProcEntry{P: &process{PID: 5, Parent: res[4].P, Cmdline: []string{"bad-actor", "has", "args"}}}
+		res[6] = memoryProcEntry{P: &process{PID: 6, Parent: res[1].P, Cmdline: []string{"kubelet"}}}
+		return res
+	})()
+	wsWithoutOwner := &common.Workspace{WorkspaceID: "foobar", InstanceID: "baz", PID: 3}
+
+	tests := []struct {
+		Name        string
+		Events      []sensorEvent
+		Expectation []Process
+	}{
+		{
+			Name: "user workload",
+			Events: []sensorEvent{
+				{Type: sensorEventExec, PID: 5, PPID: 4, CgroupID: 100},
+			},
+			Expectation: []Process{
+				{PID: 5, Path: "proc/5/exe", CommandLine: []string{"bad-actor", "has", "args"}, Kind: ProcessUserWorkload, Workspace: wsWithoutOwner},
+			},
+		},
+		{
+			Name: "reported once unless exec'ed again",
+			Events: []sensorEvent{
+				{Type: sensorEventConnect, PID: 5, PPID: 4, CgroupID: 100},
+				{Type: sensorEventSetuid, PID: 5, PPID: 4, CgroupID: 100},
+				{Type: sensorEventExec, PID: 5, PPID: 4, CgroupID: 100},
+			},
+			Expectation: []Process{
+				{PID: 5, Path: "proc/5/exe", CommandLine: []string{"bad-actor", "has", "args"}, Kind: ProcessUserWorkload, Workspace: wsWithoutOwner},
+				{PID: 5, Path: "proc/5/exe", CommandLine: []string{"bad-actor", "has", "args"}, Kind: ProcessUserWorkload, Workspace: wsWithoutOwner},
+			},
+		},
+		{
+			Name: "supervisor",
+			Events: []sensorEvent{
+				{Type: sensorEventConnect, PID: 3, PPID: 2, CgroupID: 100},
+			},
+		},
+		{
+			Name: "outside of workspace",
+			Events: []sensorEvent{
+				{Type: sensorEventConnect, PID: 6, PPID: 1, CgroupID: 200},
+			},
+		},
+		{
+			Name: "exited process",
+			Events: (func() []sensorEvent {
+				evt := sensorEvent{Type: sensorEventExec, PID: 7, PPID: 4, CgroupID: 100}
+				copy(evt.Filename[:], "/tmp/miner")
+				return []sensorEvent{evt}
+			})(),
+			Expectation: []Process{
+				{PID: 7, Path: "proc/7/exe", CommandLine: []string{"/tmp/miner"}, Kind: ProcessUserWorkload, Workspace: wsWithoutOwner},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			det, err := newEBPFDetector("", proc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ps := make(chan Process, len(test.Events))
+			det.ps = ps
+
+			for i := range test.Events {
+				det.handleEvent(&test.Events[i], time.Now())
+			}
+			close(ps)
+
+			var res []Process
+			for p := range ps {
+				res = append(res, p)
+			}
+			if diff := cmp.Diff(test.Expectation, res); diff != "" {
+				t.Errorf("unexpected processes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEBPFDetectorNegativeAttribution(t *testing.T) {
+	proc := make(memoryProc)
+	proc[1] = memoryProcEntry{P: &process{PID: 1}}
+	proc[2] = memoryProcEntry{
+		P:   &process{PID: 2, Parent: proc[1].P, Cmdline: []string{"/proc/self/exe", "ring1"}},
+		Env: []string{"GITPOD_WORKSPACE_ID=foobar", "GITPOD_INSTANCE_ID=baz"},
+	}
+	proc[4] = memoryProcEntry{P: &process{PID: 4, Parent: proc[2].P, Cmdline: []string{"bash"}}}
+
+	det, err := newEBPFDetector("", proc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := make(chan Process, 10)
+	det.ps = ps
+
+	now := time.Now()
+	// supervisor hasn't started yet
+	det.handleEvent(&sensorEvent{Type: sensorEventExec, PID: 4, PPID: 2, CgroupID: 100}, now)
+
+	proc[3] = memoryProcEntry{P: &process{PID: 3, Parent: proc[2].P, Cmdline: []string{"supervisor", "init"}}}
+	proc[4].P.Parent = proc[3].P
+	det.handleEvent(&sensorEvent{Type: sensorEventExec, PID: 4, PPID: 3, CgroupID: 100}, now.Add(time.Second))
+	det.handleEvent(&sensorEvent{Type: sensorEventExec, PID: 4, PPID: 3, CgroupID: 100}, now.Add(negativeAttributionTTL+time.Second))
+	close(ps)
+
+	var res []string
+	for p := range ps {
+		res = append(res, p.Workspace.WorkspaceID)
+	}
+	if diff := cmp.Diff([]string{"foobar"}, res); diff != "" {
+		t.Errorf("unexpected workspaces (-want +got):\n%s", diff)
+	}
+}
+
+func TestEBPFDetectorConnections(t *testing.T) {
+	proc := make(memoryProc)
+	proc[1] = memoryProcEntry{P: &process{PID: 1}}
+	proc[2] = memoryProcEntry{
+		P:   &process{PID: 2, Parent: proc[1].P, Cmdline: []string{"/proc/self/exe", "ring1"}},
+		Env: []string{"GITPOD_WORKSPACE_ID=foobar", "GITPOD_INSTANCE_ID=baz"},
+	}
+	proc[3] = memoryProcEntry{P: &process{PID: 3, Parent: proc[2].P, Cmdline: []string{"supervisor", "init"}}}
+	proc[4] = memoryProcEntry{P: &process{PID: 4, Parent: proc[3].P, Cmdline: []string{"xmrig"}}}
+	proc[5] = memoryProcEntry{P: &process{PID: 5, Parent: proc[1].P, Cmdline: []string{"kubelet"}}}
+
+	det, err := newEBPFDetector("", proc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	det.ps = make(chan Process, 10)
+	conns, err := det.DiscoverConnections(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	connect := func(pid, ppid uint32, cgroup uint64, addr []byte, port [2]byte) *sensorEvent {
+		evt := &sensorEvent{Type: sensorEventConnect, PID: pid, PPID: ppid, CgroupID: cgroup, Family: afInet, Port: port}
+		copy(evt.Addr[:], addr)
+		copy(evt.Comm[:], proc[int(pid)].P.Cmdline[0])
+		return evt
+	}
+	det.handleEvent(connect(4, 3, 100, []byte{10, 0, 0, 1}, [2]byte{0x0d, 0x05}), now)
+	det.handleEvent(connect(4, 3, 100, []byte{10, 0, 0, 2}, [2]byte{0x01, 0xbb}), now)
+	det.handleEvent(connect(3, 2, 100, []byte{10, 0, 0, 3}, [2]byte{0x01, 0xbb}), now)
+	det.handleEvent(connect(5, 1, 200, []byte{10, 0, 0, 4}, [2]byte{0x01, 0xbb}), now)
+	close(det.conns)
+
+	type conn struct {
+		Destination string
+		Comm        string
+		WorkspaceID string
+	}
+	var res []conn
+	for c := range conns {
+		res = append(res, conn{Destination: net.JoinHostPort(c.IP.String(), strconv.Itoa(c.Port)), Comm: c.Comm, WorkspaceID: c.Workspace.WorkspaceID})
+	}
+	expectation := []conn{
+		{Destination: "10.0.0.1:3333", Comm: "xmrig", WorkspaceID: "foobar"},
+		{Destination: "10.0.0.2:443", Comm: "xmrig", WorkspaceID: "foobar"},
+	}
+	if diff := cmp.Diff(expectation, res); diff != "" {
+		t.Errorf("unexpected connections (-want +got):\n%s", diff)
+	}
+}
@@ -252,14 +252,24 @@ type ProcfsDetector struct {
 	indexSizeGuage     prometheus.Gauge
 	cacheUseCounterVec *prometheus.CounterVec
 	workspaceGauge     prometheus.Gauge
+	scanDuration       prometheus.Histogram
 
-	startOnce sync.Once
+	startOnce    sync.Once
+	scanInterval time.Duration
 
 	proc  discoverableProcFS
 	cache *lru.Cache
 }
 
-func NewProcfsDetector() (*ProcfsDetector, error) {
+// defaultProcfsScanInterval is the time between two scans of procfs unless configured otherwise
+const defaultProcfsScanInterval = 30 * time.Second
+
+// NewProcfsDetector creates a detector which scans procfs every scanInterval. A zero scanInterval means every 30 seconds.
+func NewProcfsDetector(scanInterval time.Duration) (*ProcfsDetector, error) {
+	if scanInterval == 0 {
+		scanInterval = defaultProcfsScanInterval
+	}
+
 	p, err := procfs.NewFS("/proc")
 	if err != nil {
 		return nil, err
@@ -289,8 +299,16 @@ func NewProcfsDetector() (*ProcfsDetector, error) {
 			Name:      "workspace_count",
 			Help:      "number of detected workspaces",
 		}),
-		proc:  realProcfs(p),
-		cache: cache,
+		scanDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "gitpod",
+			Subsystem: "agent_smith_procfs_detector",
+			Name:      "scan_duration_seconds",
+			Help:      "time it takes to scan procfs",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}),
+		scanInterval: scanInterval,
+		proc:         realProcfs(p),
+		cache:        cache,
 	}, nil
 }
 
@@ -298,22 +316,26 @@ func (det *ProcfsDetector) Describe(d chan<- *prometheus.Desc) {
 	det.indexSizeGuage.Describe(d)
 	det.cacheUseCounterVec.Describe(d)
 	det.workspaceGauge.Describe(d)
+	det.scanDuration.Describe(d)
 }
 
 func (det *ProcfsDetector) Collect(m chan<- prometheus.Metric) {
 	det.indexSizeGuage.Collect(m)
 	det.cacheUseCounterVec.Collect(m)
 	det.workspaceGauge.Collect(m)
+	det.scanDuration.Collect(m)
 }
 
 func (det *ProcfsDetector) start() {
 	ps := make(chan Process, 100)
 	go func() {
-		t := time.NewTicker(30 * time.Second)
+		t := time.NewTicker(det.scanInterval)
 		defer t.Stop()
 
 		for {
+			start := time.Now()
 			det.run(ps)
+			det.scanDuration.Observe(time.Since(start).Seconds())
 			<-t.C
 		}
 	}()
@@ -375,6 +397,7 @@ func (det *ProcfsDetector) run(processes chan<- Process) {
 		det.cache.Add(p.Hash, struct{}{})
 
 		proc := Process{
+			PID:         p.PID,
 			Path:        p.Path,
 			CommandLine: p.Cmdline,
 			Kind:        p.Kind,
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// bootProfileLocation is where supervisor writes the boot profile
+const bootProfileLocation = "/tmp/gitpod-boot-profile.json"
+
+// bootProfileBarWidth is the width of the timeline bars in characters
+const bootProfileBarWidth = 40
+
+type bootProfile struct {
+	Started time.Time `json:"started"`
+	Phases  []struct {
+		Name  string     `json:"name"`
+		Start time.Time  `json:"start"`
+		End   *time.Time `json:"end,omitempty"`
+		Error string     `json:"error,omitempty"`
+	} `json:"phases"`
+}
+
+var bootProfileCmd = &cobra.Command{
+	Use:   "boot-profile",
+	Short: "Shows how long the phases of the workspace start took",
+	Long: `Shows how long the phases of the workspace start took, e.g. initializing the workspace content,
+starting the IDE and the tasks, and serving the ports.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fc, err := os.ReadFile(bootProfileLocation)
+		if err != nil {
+			fail(fmt.Sprintf("cannot read boot profile: %v", err))
+		}
+		var profile bootProfile
+		err = json.Unmarshal(fc, &profile)
+		if err != nil {
+			fail(fmt.Sprintf("cannot parse boot profile: %v", err))
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			_, _ = os.Stdout.Write(fc)
+			return
+		}
+		renderBootProfile(os.Stdout, &profile, time.Now())
+	},
+}
+
+func renderBootProfile(out io.Writer, profile *bootProfile, now time.Time) {
+	// the timeline spans from the supervisor start to the end of the last phase
+	total := time.Duration(0)
+	for _, p := range profile.Phases {
+		end := now
+		if p.End != nil {
+			end = *p.End
+		}
+		if d := end.Sub(profile.Started); d > total {
+			total = d
+		}
+	}
+	pos := func(t time.Time) int {
+		if total == 0 {
+			return 0
+		}
+		return int(int64(bootProfileBarWidth) * int64(t.Sub(profile.Started)) / int64(total))
+	}
+
+	tw := tabwriter.NewWriter(out, 2, 4, 1, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintf(tw, "PHASE\tSTART\tDURATION\tTIMELINE\n")
+	for _, p := range profile.Phases {
+		end, duration := now, "running"
+		if p.End != nil {
+			end = *p.End
+			duration = p.End.Sub(p.Start).Round(100 * time.Millisecond).String()
+		}
+		if p.Error != "" {
+			duration = "failed: " + p.Error
+		}
+		from, to := pos(p.Start), pos(end)
+		if to == from {
+			// short phases get at least one character
+			if to < bootProfileBarWidth {
+				to++
+			} else {
+				from--
+			}
+		}
+		bar := strings.Repeat(" ", from) + strings.Repeat("█", to-from)
+		fmt.Fprintf(tw, "%s\t+%s\t%s\t|%-*s|\n", p.Name, p.Start.Sub(profile.Started).Round(100*time.Millisecond), duration, bootProfileBarWidth, bar)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(bootProfileCmd)
+	bootProfileCmd.Flags().Bool("json", false, "print the boot profile as JSON")
+}
@@ -34,7 +34,7 @@ var envCmd = &cobra.Command{
 To set the persistent environment variable 'foo' to the value 'bar' use:
 	gp env foo=bar
 
-Beware that this does not modify your current terminal session, but rather persists this variable for the next workspace on this repository.
+Beware that this does not modify your current terminal session, but rather persists this variable for new terminals and the next workspace on this repository.
 This command can only interact with environment variables for this repository. If you want to set that environment variable in your terminal,
 you can do so using -e:
 	eval $(gp env -e foo=bar)
@@ -73,6 +73,7 @@ delete environment variables with a repository pattern of */foo, foo/* or */*.
 type connectToServerResult struct {
 	repositoryPattern string
 	client            *serverapi.APIoverJSONRPC
+	supervisorConn    *grpc.ClientConn
 }
 
 func connectToServer(ctx context.Context) (*connectToServerResult, error) {
@@ -119,7 +120,7 @@ func connectToServer(ctx context.Context) (*connectToServerResult, error) {
 	if err != nil {
 		return nil, xerrors.Errorf("failed connecting to server: %w", err)
 	}
-	return &connectToServerResult{repositoryPattern, client}, nil
+	return &connectToServerResult{repositoryPattern, client, supervisorConn}, nil
 }
 
 func getEnvs() {
@@ -169,6 +170,7 @@ func setEnvs(args []string) {
 		}(v)
 	}
 	wg.Wait()
+	reloadEnvs(ctx, result)
 	os.Exit(exitCode)
 }
 
@@ -194,9 +196,18 @@ func deleteEnvs(args []string) {
 		}(name)
 	}
 	wg.Wait()
+	reloadEnvs(ctx, result)
 	os.Exit(exitCode)
 }
 
+// reloadEnvs passes the changed variables to all terminals opened from now on
+func reloadEnvs(ctx context.Context, result *connectToServerResult) {
+	_, err := supervisor.NewEnvironmentServiceClient(result.supervisorConn).ReloadEnvironmentVariables(ctx, &supervisor.ReloadEnvironmentVariablesRequest{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot update the environment of new terminals: %v\n", err)
+	}
+}
+
 func fail(msg string) {
 	fmt.Fprintln(os.Stderr, msg)
 	os.Exit(-1)
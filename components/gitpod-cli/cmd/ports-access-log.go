@@ -0,0 +1,74 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// accessLogPortsCmd represents the ports access-log command
+var accessLogPortsCmd = &cobra.Command{
+	Use:   "access-log [port]",
+	Short: "Lists recent requests to exposed ports, e.g. to debug webhook deliveries.",
+	Long: `Lists recent requests to exposed ports which reached the workspace through its port URLs.
+
+Requests are sampled and only recorded if the Gitpod installation forwards access logs to workspaces.
+Request paths are hashed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var port uint64
+		if len(args) > 0 {
+			var err error
+			port, err = strconv.ParseUint(args[0], 10, 16)
+			if err != nil {
+				log.WithError(err).Fatal("port must be a number")
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		supervisorAddr := os.Getenv("SUPERVISOR_ADDR")
+		if supervisorAddr == "" {
+			supervisorAddr = "localhost:22999"
+		}
+		supervisorConn, err := grpc.Dial(supervisorAddr, grpc.WithInsecure())
+		if err != nil {
+			log.WithError(err).Fatal("cannot connect to supervisor")
+		}
+		defer supervisorConn.Close()
+
+		resp, err := supervisor.NewPortServiceClient(supervisorConn).ListAccessLog(ctx, &supervisor.ListAccessLogRequest{})
+		if err != nil {
+			log.WithError(err).Fatal("cannot get access log")
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 2, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "TIME\tPORT\tMETHOD\tPATH HASH\tSTATUS\tBYTES\tLATENCY\n")
+		for _, e := range resp.Entries {
+			if port != 0 && e.Port != uint32(port) {
+				continue
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%d\t%d\t%dms\n", e.Time.AsTime().Local().Format(time.RFC3339), e.Port, e.Method, e.PathHash, e.Status, e.Bytes, e.LatencyMs)
+		}
+	},
+}
+
+func init() {
+	portsCmd.AddCommand(accessLogPortsCmd)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// listPortsCmd represents the ports list command
+var listPortsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists workspace ports and the processes serving them.",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		supervisorAddr := os.Getenv("SUPERVISOR_ADDR")
+		if supervisorAddr == "" {
+			supervisorAddr = "localhost:22999"
+		}
+		supervisorConn, err := grpc.Dial(supervisorAddr, grpc.WithInsecure())
+		if err != nil {
+			log.WithError(err).Fatal("cannot connect to supervisor")
+		}
+		defer supervisorConn.Close()
+
+		statusClient, err := supervisor.NewStatusServiceClient(supervisorConn).PortsStatus(ctx, &supervisor.PortsStatusRequest{})
+		if err != nil {
+			log.WithError(err).Fatal("cannot get ports status")
+		}
+		resp, err := statusClient.Recv()
+		if err != nil {
+			log.WithError(err).Fatal("cannot get ports status")
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 2, 4, 1, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "PORT\tPROTOCOL\tSTATUS\tURL\tPID\tPROCESS\n")
+		for _, port := range resp.Ports {
+			status := "not served"
+			if port.Served {
+				status = "served"
+			}
+			var url string
+			if port.Exposed != nil {
+				url = port.Exposed.Url
+				status += fmt.Sprintf(", exposed (%s)", port.Exposed.Visibility)
+			}
+
+			var (
+				pid     string
+				process string
+			)
+			if port.Process != nil {
+				pid = fmt.Sprint(port.Process.Pid)
+				process = strings.Join(port.Process.Cmdline, " ")
+				if process == "" {
+					process = port.Process.Name
+				}
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", port.LocalPort, port.Protocol, status, url, pid, process)
+		}
+	},
+}
+
+func init() {
+	portsCmd.AddCommand(listPortsCmd)
+}
@@ -6,29 +6,24 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	gitpod "github.com/gitpod-io/gitpod/gitpod-cli/pkg/gitpod"
-	protocol "github.com/gitpod-io/gitpod/gitpod-protocol"
-	"github.com/sourcegraph/jsonrpc2"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
 	"github.com/spf13/cobra"
-)
-
-const (
-	ErrorCodeSnapshotNotFound = 404
-	ErrorCodeSnapshotError    = 630
+	"google.golang.org/grpc"
 )
 
 // snapshotCmd represents the snapshotCmd command
 var snapshotCmd = &cobra.Command{
 	Use:   "snapshot",
 	Short: "Take a snapshot of the current workspace",
-	Args:  cobra.ArbitraryArgs,
+	Long: `Takes a snapshot of the current workspace content while the workspace keeps running.
+Prints the URL of the snapshot once it is available. Opening the URL creates a new workspace from the snapshot.`,
+	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx, cancel := context.WithCancel(context.Background())
 		sigChan := make(chan os.Signal, 1)
@@ -37,39 +32,36 @@ var snapshotCmd = &cobra.Command{
 			<-sigChan
 			cancel()
 		}()
-		wsInfo, err := gitpod.GetWSInfo(ctx)
-		if err != nil {
-			fail(err.Error())
+
+		supervisorAddr := os.Getenv("SUPERVISOR_ADDR")
+		if supervisorAddr == "" {
+			supervisorAddr = "localhost:22999"
 		}
-		client, err := gitpod.ConnectToServer(ctx, wsInfo, []string{
-			"function:takeSnapshot",
-			"function:waitForSnapshot",
-			"resource:workspace::" + wsInfo.WorkspaceId + "::get/update",
-		})
+		supervisorConn, err := grpc.Dial(supervisorAddr, grpc.WithInsecure())
 		if err != nil {
 			fail(err.Error())
 		}
-		snapshotId, err := client.TakeSnapshot(ctx, &protocol.TakeSnapshotOptions{
-			WorkspaceID: wsInfo.WorkspaceId,
-			DontWait:    true,
-		})
+		defer supervisorConn.Close()
+
+		progress, err := supervisor.NewControlServiceClient(supervisorConn).TakeSnapshot(ctx, &supervisor.TakeSnapshotRequest{})
 		if err != nil {
 			fail(err.Error())
 		}
-		for ctx.Err() == nil {
-			err := client.WaitForSnapshot(ctx, snapshotId)
+		for {
+			resp, err := progress.Recv()
+			if err == io.EOF {
+				return
+			}
 			if err != nil {
-				var responseErr *jsonrpc2.Error
-				if errors.As(err, &responseErr) && (responseErr.Code == ErrorCodeSnapshotNotFound || responseErr.Code == ErrorCodeSnapshotError) {
-					panic(err)
-				}
-				time.Sleep(time.Second * 3)
-			} else {
-				break
+				fail(err.Error())
+			}
+			switch resp.Phase {
+			case supervisor.TakeSnapshotResponse_in_progress:
+				fmt.Fprintf(os.Stderr, "Taking snapshot %s...\n", resp.SnapshotId)
+			case supervisor.TakeSnapshotResponse_available:
+				fmt.Println(resp.Url)
 			}
 		}
-		url := fmt.Sprintf("%s/#snapshot/%s", wsInfo.GitpodHost, snapshotId)
-		fmt.Println(url)
 	},
 }
 
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// topCmd represents the top command
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Shows the processes of the workspace and their resource consumption",
+	Long: `Shows the processes of the workspace as a tree, together with their CPU and memory consumption,
+the task or terminal they run in, and the ports they serve.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		supervisorAddr := os.Getenv("SUPERVISOR_ADDR")
+		if supervisorAddr == "" {
+			supervisorAddr = "localhost:22999"
+		}
+		supervisorConn, err := grpc.Dial(supervisorAddr, grpc.WithInsecure())
+		if err != nil {
+			log.WithError(err).Fatal("cannot connect to supervisor")
+		}
+		defer supervisorConn.Close()
+
+		resp, err := supervisor.NewStatusServiceClient(supervisorConn).ListProcesses(ctx, &supervisor.ListProcessesRequest{})
+		if err != nil {
+			log.WithError(err).Fatal("cannot list processes")
+		}
+		renderProcesses(os.Stdout, resp.Processes)
+	},
+}
+
+func renderProcesses(out io.Writer, processes []*supervisor.ProcessInfo) {
+	tw := tabwriter.NewWriter(out, 2, 4, 1, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintf(tw, "PID\tCPU\tMEMORY\tTASK\tPORTS\tCOMMAND\n")
+	var render func(procs []*supervisor.ProcessInfo, indent string)
+	render = func(procs []*supervisor.ProcessInfo, indent string) {
+		for _, p := range procs {
+			var owner string
+			if p.Task != "" {
+				owner = "task " + p.Task
+			} else if p.Terminal != "" {
+				owner = "terminal"
+			}
+			ports := make([]string, 0, len(p.Ports))
+			for _, port := range p.Ports {
+				ports = append(ports, fmt.Sprint(port))
+			}
+			command := strings.Join(p.Cmdline, " ")
+			if command == "" {
+				command = "[" + p.Name + "]"
+			}
+			fmt.Fprintf(tw, "%d\t%dm\t%s\t%s\t%s\t%s%s\n", p.Pid, p.Cpu, formatBytes(p.Memory), owner, strings.Join(ports, ","), indent, command)
+			render(p.Children, indent+"  ")
+		}
+	}
+	render(processes, "")
+}
+
+// formatBytes formats a number of bytes in binary units, e.g. 1.5Mi
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ci", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+}
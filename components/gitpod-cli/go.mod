@@ -24,7 +24,7 @@ require (
 
 require (
 	github.com/prometheus/procfs v0.7.3
-	github.com/sourcegraph/jsonrpc2 v0.0.0-20200429184054-15c2290dcb37
+	github.com/sourcegraph/jsonrpc2 v0.0.0-20200429184054-15c2290dcb37 // indirect
 )
 
 require (
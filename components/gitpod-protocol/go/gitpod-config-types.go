@@ -14,6 +14,38 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// Checkout Configures how much of the repository is cloned. Helps big repositories start faster.
+type Checkout struct {
+
+	// The number of commits to clone. 0 clones the full history.
+	Depth int `yaml:"depth,omitempty"`
+
+	// Makes the clone a partial one. Git then fetches file contents (blobless), or file contents and trees (treeless), when they're needed.
+	Filter string `yaml:"filter,omitempty"`
+
+	// How to fetch the Git LFS objects of the repository: while the workspace starts (fetch), in the background once the workspace is running (lazy), or not at all (skip).
+	Lfs string `yaml:"lfs,omitempty"`
+
+	// The directories to check out (sparse-checkout cone patterns). All other directories are left out of the working copy.
+	Sparse []string `yaml:"sparse,omitempty"`
+
+	// Set to false to not fetch the full history in the background once the workspace is running.
+	Unshallow bool `yaml:"unshallow,omitempty"`
+}
+
+// Dotfiles Configures how the dotfiles of users are installed in workspaces of this repository.
+type Dotfiles struct {
+
+	// Set to true to not install the dotfiles of users in workspaces of this repository.
+	Disabled bool `yaml:"disabled,omitempty"`
+
+	// The install script to run, relative to the root of the dotfiles repository. Must be one of the allowed install scripts.
+	InstallScript string `yaml:"installScript,omitempty"`
+
+	// A dotfiles repository to install instead of the user's dotfiles repository.
+	Repository string `yaml:"repository,omitempty"`
+}
+
 // Env Environment variables to set.
 type Env struct {
 }
@@ -28,9 +60,15 @@ type Github struct {
 // GitpodConfig
 type GitpodConfig struct {
 
+	// Configures how much of the repository is cloned. Helps big repositories start faster.
+	Checkout *Checkout `yaml:"checkout,omitempty"`
+
 	// Path to where the repository should be checked out.
 	CheckoutLocation string `yaml:"checkoutLocation,omitempty"`
 
+	// Configures how the dotfiles of users are installed in workspaces of this repository.
+	Dotfiles *Dotfiles `yaml:"dotfiles,omitempty"`
+
 	// Git config values should be provided in pairs. E.g. `core.autocrlf: input`. See https://git-scm.com/docs/git-config#_values.
 	GitConfig map[string]string `yaml:"gitConfig,omitempty"`
 
@@ -56,6 +94,19 @@ type GitpodConfig struct {
 	WorkspaceLocation string `yaml:"workspaceLocation,omitempty"`
 }
 
+// Headers Headers which are added to the responses of the port, e.g. to allow embedding the port in other sites or accessing it from other origins.
+type Headers struct {
+
+	// Origins which may access the port from a browser, e.g. 'https://example.com'. '*' allows all origins.
+	CorsAllowedOrigins []string `yaml:"corsAllowedOrigins,omitempty"`
+
+	// Additional headers which are added to every response of the port, e.g. 'Cross-Origin-Embedder-Policy: require-corp'.
+	Custom map[string]string `yaml:"custom,omitempty"`
+
+	// The X-Frame-Options header of the port's responses. By default, the port may be embedded by every site.
+	XFrameOptions string `yaml:"xFrameOptions,omitempty"`
+}
+
 // Image_object The Docker image to run your workspace in.
 type Image_object struct {
 
@@ -69,7 +120,16 @@ type Image_object struct {
 // PortsItems
 type PortsItems struct {
 
-	// Port name (deprecated).
+	// A description to identify what is this port used for. For port ranges, '{port}' is replaced by the number of the served port.
+	Description string `yaml:"description,omitempty"`
+
+	// Headers which are added to the responses of the port, e.g. to allow embedding the port in other sites or accessing it from other origins.
+	Headers *Headers `yaml:"headers,omitempty"`
+
+	// Set to true to ignore the port (e.g. 1337) or range (e.g. 3000-3999). Ignored ports are neither shown, nor auto-exposed, nor tunneled. Useful for ports of language servers, debuggers and other internal tools.
+	Ignore bool `yaml:"ignore,omitempty"`
+
+	// Port name. For port ranges, '{port}' is replaced by the number of the served port, e.g. 'worker-{port}'.
 	Name string `yaml:"name,omitempty"`
 
 	// What to do when a service on this port was detected. 'notify' (default) will show a notification asking the user what to do. 'open-browser' will open a new browser tab. 'open-preview' will open in the preview on the right of the IDE. 'ignore' will do nothing.
@@ -81,7 +141,10 @@ type PortsItems struct {
 	// The protocol to be used. (deprecated)
 	Protocol string `yaml:"protocol,omitempty"`
 
-	// Whether the port visibility should be private or public. 'public' (default) will allow everyone with the port URL to access the port. 'private' will only allow users with workspace access to access the port.
+	// An HTTP probe which has to succeed before the port is considered to be served. Until then no notification is shown and no browser or preview is opened.
+	ReadinessProbe *ReadinessProbe `yaml:"readinessProbe,omitempty"`
+
+	// Who may access the port. 'private' (default) will only allow users with workspace access to access the port. 'authenticated' will allow every user who is logged in to Gitpod to access the port. 'public' will allow everyone with the port URL to access the port.
 	Visibility string `yaml:"visibility,omitempty"`
 }
 
@@ -107,6 +170,19 @@ type Prebuilds_object struct {
 	PullRequestsFromForks bool `yaml:"pullRequestsFromForks,omitempty"`
 }
 
+// ReadinessProbe An HTTP probe which has to succeed before the port is considered to be served. Until then no notification is shown and no browser or preview is opened.
+type ReadinessProbe struct {
+
+	// The HTTP path to probe, e.g. '/healthz'. Defaults to '/'.
+	Path string `yaml:"path,omitempty"`
+
+	// The HTTP status code the probe expects. Defaults to 200.
+	Status int `yaml:"status,omitempty"`
+
+	// The number of seconds after which a single probe request times out. Defaults to 1.
+	Timeout int `yaml:"timeout,omitempty"`
+}
+
 // TasksItems
 type TasksItems struct {
 
@@ -125,6 +201,9 @@ type TasksItems struct {
 	// Name of the task. Shown on the tab of the opened terminal.
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 
+	// Names of the tasks which have to be ready before this task is started.
+	Needs []string `yaml:"needs,omitempty" json:"needs,omitempty"`
+
 	// The panel/area where to open the terminal. Default is 'bottom' panel.
 	OpenIn string `yaml:"openIn,omitempty" json:"openIn,omitempty"`
 
@@ -133,6 +212,9 @@ type TasksItems struct {
 
 	// A shell command to run after `before`. This command is executed only on during workspace prebuilds. This command is expected to terminate. If it fails, the workspace build fails.
 	Prebuild string `yaml:"prebuild,omitempty" json:"prebuild,omitempty"`
+
+	// A shell command which succeeds once the task is ready, e.g. once a server started listening. Tasks which need this task are started afterwards. If not set, the task is ready once `before` and `init` are done.
+	Ready string `yaml:"ready,omitempty" json:"ready,omitempty"`
 }
 
 // Vscode Configure VS Code integration
@@ -142,6 +224,176 @@ type Vscode struct {
 	Extensions []string `yaml:"extensions,omitempty"`
 }
 
+func (strct *Checkout) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// Marshal the "depth" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"depth\": ")
+	if tmp, err := json.Marshal(strct.Depth); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "filter" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"filter\": ")
+	if tmp, err := json.Marshal(strct.Filter); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "lfs" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"lfs\": ")
+	if tmp, err := json.Marshal(strct.Lfs); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "sparse" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"sparse\": ")
+	if tmp, err := json.Marshal(strct.Sparse); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "unshallow" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"unshallow\": ")
+	if tmp, err := json.Marshal(strct.Unshallow); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *Checkout) UnmarshalJSON(b []byte) error {
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "depth":
+			if err := json.Unmarshal([]byte(v), &strct.Depth); err != nil {
+				return err
+			}
+		case "filter":
+			if err := json.Unmarshal([]byte(v), &strct.Filter); err != nil {
+				return err
+			}
+		case "lfs":
+			if err := json.Unmarshal([]byte(v), &strct.Lfs); err != nil {
+				return err
+			}
+		case "sparse":
+			if err := json.Unmarshal([]byte(v), &strct.Sparse); err != nil {
+				return err
+			}
+		case "unshallow":
+			if err := json.Unmarshal([]byte(v), &strct.Unshallow); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
+func (strct *Dotfiles) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// Marshal the "disabled" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"disabled\": ")
+	if tmp, err := json.Marshal(strct.Disabled); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "installScript" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"installScript\": ")
+	if tmp, err := json.Marshal(strct.InstallScript); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "repository" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"repository\": ")
+	if tmp, err := json.Marshal(strct.Repository); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *Dotfiles) UnmarshalJSON(b []byte) error {
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "disabled":
+			if err := json.Unmarshal([]byte(v), &strct.Disabled); err != nil {
+				return err
+			}
+		case "installScript":
+			if err := json.Unmarshal([]byte(v), &strct.InstallScript); err != nil {
+				return err
+			}
+		case "repository":
+			if err := json.Unmarshal([]byte(v), &strct.Repository); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
 func (strct *Github) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
@@ -186,6 +438,17 @@ func (strct *GitpodConfig) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
 	comma := false
+	// Marshal the "checkout" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"checkout\": ")
+	if tmp, err := json.Marshal(strct.Checkout); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "checkoutLocation" field
 	if comma {
 		buf.WriteString(",")
@@ -197,6 +460,17 @@ func (strct *GitpodConfig) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
+	// Marshal the "dotfiles" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"dotfiles\": ")
+	if tmp, err := json.Marshal(strct.Dotfiles); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "gitConfig" field
 	if comma {
 		buf.WriteString(",")
@@ -299,10 +573,18 @@ func (strct *GitpodConfig) UnmarshalJSON(b []byte) error {
 	// parse all the defined properties
 	for k, v := range jsonMap {
 		switch k {
+		case "checkout":
+			if err := json.Unmarshal([]byte(v), &strct.Checkout); err != nil {
+				return err
+			}
 		case "checkoutLocation":
 			if err := json.Unmarshal([]byte(v), &strct.CheckoutLocation); err != nil {
 				return err
 			}
+		case "dotfiles":
+			if err := json.Unmarshal([]byte(v), &strct.Dotfiles); err != nil {
+				return err
+			}
 		case "gitConfig":
 			if err := json.Unmarshal([]byte(v), &strct.GitConfig); err != nil {
 				return err
@@ -342,6 +624,76 @@ func (strct *GitpodConfig) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (strct *Headers) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// Marshal the "corsAllowedOrigins" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"corsAllowedOrigins\": ")
+	if tmp, err := json.Marshal(strct.CorsAllowedOrigins); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "custom" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"custom\": ")
+	if tmp, err := json.Marshal(strct.Custom); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "xFrameOptions" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"xFrameOptions\": ")
+	if tmp, err := json.Marshal(strct.XFrameOptions); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *Headers) UnmarshalJSON(b []byte) error {
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "corsAllowedOrigins":
+			if err := json.Unmarshal([]byte(v), &strct.CorsAllowedOrigins); err != nil {
+				return err
+			}
+		case "custom":
+			if err := json.Unmarshal([]byte(v), &strct.Custom); err != nil {
+				return err
+			}
+		case "xFrameOptions":
+			if err := json.Unmarshal([]byte(v), &strct.XFrameOptions); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
 func (strct *Image_object) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
@@ -409,6 +761,39 @@ func (strct *PortsItems) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
 	comma := false
+	// Marshal the "description" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"description\": ")
+	if tmp, err := json.Marshal(strct.Description); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "headers" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"headers\": ")
+	if tmp, err := json.Marshal(strct.Headers); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "ignore" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"ignore\": ")
+	if tmp, err := json.Marshal(strct.Ignore); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "name" field
 	if comma {
 		buf.WriteString(",")
@@ -455,6 +840,17 @@ func (strct *PortsItems) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
+	// Marshal the "readinessProbe" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"readinessProbe\": ")
+	if tmp, err := json.Marshal(strct.ReadinessProbe); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "visibility" field
 	if comma {
 		buf.WriteString(",")
@@ -481,6 +877,18 @@ func (strct *PortsItems) UnmarshalJSON(b []byte) error {
 	// parse all the defined properties
 	for k, v := range jsonMap {
 		switch k {
+		case "description":
+			if err := json.Unmarshal([]byte(v), &strct.Description); err != nil {
+				return err
+			}
+		case "headers":
+			if err := json.Unmarshal([]byte(v), &strct.Headers); err != nil {
+				return err
+			}
+		case "ignore":
+			if err := json.Unmarshal([]byte(v), &strct.Ignore); err != nil {
+				return err
+			}
 		case "name":
 			if err := json.Unmarshal([]byte(v), &strct.Name); err != nil {
 				return err
@@ -498,6 +906,10 @@ func (strct *PortsItems) UnmarshalJSON(b []byte) error {
 			if err := json.Unmarshal([]byte(v), &strct.Protocol); err != nil {
 				return err
 			}
+		case "readinessProbe":
+			if err := json.Unmarshal([]byte(v), &strct.ReadinessProbe); err != nil {
+				return err
+			}
 		case "visibility":
 			if err := json.Unmarshal([]byte(v), &strct.Visibility); err != nil {
 				return err
@@ -513,6 +925,76 @@ func (strct *PortsItems) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (strct *ReadinessProbe) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// Marshal the "path" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"path\": ")
+	if tmp, err := json.Marshal(strct.Path); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "status" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"status\": ")
+	if tmp, err := json.Marshal(strct.Status); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "timeout" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"timeout\": ")
+	if tmp, err := json.Marshal(strct.Timeout); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *ReadinessProbe) UnmarshalJSON(b []byte) error {
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "path":
+			if err := json.Unmarshal([]byte(v), &strct.Path); err != nil {
+				return err
+			}
+		case "status":
+			if err := json.Unmarshal([]byte(v), &strct.Status); err != nil {
+				return err
+			}
+		case "timeout":
+			if err := json.Unmarshal([]byte(v), &strct.Timeout); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
 func (strct *TasksItems) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
@@ -572,6 +1054,17 @@ func (strct *TasksItems) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
+	// Marshal the "needs" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"needs\": ")
+	if tmp, err := json.Marshal(strct.Needs); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "openIn" field
 	if comma {
 		buf.WriteString(",")
@@ -605,6 +1098,17 @@ func (strct *TasksItems) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
+	// Marshal the "ready" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"ready\": ")
+	if tmp, err := json.Marshal(strct.Ready); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 
 	buf.WriteString("}")
 	rv := buf.Bytes()
@@ -639,6 +1143,10 @@ func (strct *TasksItems) UnmarshalJSON(b []byte) error {
 			if err := json.Unmarshal([]byte(v), &strct.Name); err != nil {
 				return err
 			}
+		case "needs":
+			if err := json.Unmarshal([]byte(v), &strct.Needs); err != nil {
+				return err
+			}
 		case "openIn":
 			if err := json.Unmarshal([]byte(v), &strct.OpenIn); err != nil {
 				return err
@@ -651,6 +1159,10 @@ func (strct *TasksItems) UnmarshalJSON(b []byte) error {
 			if err := json.Unmarshal([]byte(v), &strct.Prebuild); err != nil {
 				return err
 			}
+		case "ready":
+			if err := json.Unmarshal([]byte(v), &strct.Ready); err != nil {
+				return err
+			}
 		default:
 			return xerrors.Errorf("additional property not allowed: \"" + k + "\"")
 		}
@@ -59,11 +59,14 @@ type APIInterface interface {
 	GetOpenPorts(ctx context.Context, workspaceID string) (res []*WorkspaceInstancePort, err error)
 	OpenPort(ctx context.Context, workspaceID string, port *WorkspaceInstancePort) (res *WorkspaceInstancePort, err error)
 	ClosePort(ctx context.Context, workspaceID string, port float32) (err error)
+	GetPortTCPAccess(ctx context.Context, workspaceID string, port float32) (res *PortTCPAccess, err error)
 	GetUserStorageResource(ctx context.Context, options *GetUserStorageResourceOptions) (res string, err error)
 	UpdateUserStorageResource(ctx context.Context, options *UpdateUserStorageResourceOptions) (err error)
 	GetEnvVars(ctx context.Context) (res []*UserEnvVarValue, err error)
+	GetAllEnvVars(ctx context.Context) (res []*UserEnvVarValue, err error)
 	SetEnvVar(ctx context.Context, variable *UserEnvVarValue) (err error)
 	DeleteEnvVar(ctx context.Context, variable *UserEnvVarValue) (err error)
+	GetSSHPublicKeys(ctx context.Context) (res []*UserSSHPublicKey, err error)
 	GetContentBlobUploadURL(ctx context.Context, name string) (url string, err error)
 	GetContentBlobDownloadURL(ctx context.Context, name string) (url string, err error)
 	GetGitpodTokens(ctx context.Context) (res []*APIToken, err error)
@@ -160,16 +163,22 @@ const (
 	FunctionOpenPort FunctionName = "openPort"
 	// FunctionClosePort is the name of the closePort function
 	FunctionClosePort FunctionName = "closePort"
+	// FunctionGetPortTCPAccess is the name of the getPortTCPAccess function
+	FunctionGetPortTCPAccess FunctionName = "getPortTCPAccess"
 	// FunctionGetUserStorageResource is the name of the getUserStorageResource function
 	FunctionGetUserStorageResource FunctionName = "getUserStorageResource"
 	// FunctionUpdateUserStorageResource is the name of the updateUserStorageResource function
 	FunctionUpdateUserStorageResource FunctionName = "updateUserStorageResource"
 	// FunctionGetEnvVars is the name of the getEnvVars function
 	FunctionGetEnvVars FunctionName = "getEnvVars"
+	// FunctionGetAllEnvVars is the name of the getAllEnvVars function
+	FunctionGetAllEnvVars FunctionName = "getAllEnvVars"
 	// FunctionSetEnvVar is the name of the setEnvVar function
 	FunctionSetEnvVar FunctionName = "setEnvVar"
 	// FunctionDeleteEnvVar is the name of the deleteEnvVar function
 	FunctionDeleteEnvVar FunctionName = "deleteEnvVar"
+	// FunctionGetSSHPublicKeys is the name of the getSSHPublicKeys function
+	FunctionGetSSHPublicKeys FunctionName = "getSSHPublicKeys"
 	// FunctionGetContentBlobUploadURL is the name fo the getContentBlobUploadUrl function
 	FunctionGetContentBlobUploadURL FunctionName = "getContentBlobUploadUrl"
 	// FunctionGetContentBlobDownloadURL is the name fo the getContentBlobDownloadUrl function
@@ -1018,6 +1027,27 @@ func (gp *APIoverJSONRPC) ClosePort(ctx context.Context, workspaceID string, por
 	return
 }
 
+// GetPortTCPAccess calls getPortTCPAccess on the server
+func (gp *APIoverJSONRPC) GetPortTCPAccess(ctx context.Context, workspaceID string, port float32) (res *PortTCPAccess, err error) {
+	if gp == nil {
+		err = errNotConnected
+		return
+	}
+	var _params []interface{}
+
+	_params = append(_params, workspaceID)
+	_params = append(_params, port)
+
+	var result PortTCPAccess
+	err = gp.C.Call(ctx, "getPortTCPAccess", _params, &result)
+	if err != nil {
+		return
+	}
+	res = &result
+
+	return
+}
+
 // GetUserStorageResource calls getUserStorageResource on the server
 func (gp *APIoverJSONRPC) GetUserStorageResource(ctx context.Context, options *GetUserStorageResourceOptions) (res string, err error) {
 	if gp == nil {
@@ -1074,6 +1104,24 @@ func (gp *APIoverJSONRPC) GetEnvVars(ctx context.Context) (res []*UserEnvVarValu
 	return
 }
 
+// GetAllEnvVars calls getAllEnvVars on the server
+func (gp *APIoverJSONRPC) GetAllEnvVars(ctx context.Context) (res []*UserEnvVarValue, err error) {
+	if gp == nil {
+		err = errNotConnected
+		return
+	}
+	var _params []interface{}
+
+	var result []*UserEnvVarValue
+	err = gp.C.Call(ctx, "getAllEnvVars", _params, &result)
+	if err != nil {
+		return
+	}
+	res = result
+
+	return
+}
+
 // SetEnvVar calls setEnvVar on the server
 func (gp *APIoverJSONRPC) SetEnvVar(ctx context.Context, variable *UserEnvVarValue) (err error) {
 	if gp == nil {
@@ -1110,6 +1158,24 @@ func (gp *APIoverJSONRPC) DeleteEnvVar(ctx context.Context, variable *UserEnvVar
 	return
 }
 
+// GetSSHPublicKeys calls getSSHPublicKeys on the server
+func (gp *APIoverJSONRPC) GetSSHPublicKeys(ctx context.Context) (res []*UserSSHPublicKey, err error) {
+	if gp == nil {
+		err = errNotConnected
+		return
+	}
+	var _params []interface{}
+
+	var result []*UserSSHPublicKey
+	err = gp.C.Call(ctx, "getSSHPublicKeys", _params, &result)
+	if err != nil {
+		return
+	}
+	res = result
+
+	return
+}
+
 // GetContentBlobUploadURL calls getContentBlobUploadUrl on the server
 func (gp *APIoverJSONRPC) GetContentBlobUploadURL(ctx context.Context, name string) (url string, err error) {
 	if gp == nil {
@@ -1797,6 +1863,13 @@ type WorkspaceInstancePort struct {
 	Visibility string  `json:"visibility,omitempty"`
 }
 
+// PortTCPAccess is the PortTCPAccess message type
+type PortTCPAccess struct {
+	Address   string `json:"address,omitempty"`
+	Token     string `json:"token,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
 // GithubAppConfig is the GithubAppConfig message type
 type GithubAppConfig struct {
 	Prebuilds *GithubAppPrebuildConfig `json:"prebuilds,omitempty"`
@@ -1822,11 +1895,13 @@ type ImageConfigFile struct {
 
 // PortConfig is the PortConfig message type
 type PortConfig struct {
-	OnOpen      string  `json:"onOpen,omitempty"`
-	Port        float64 `json:"port,omitempty"`
-	Visibility  string  `json:"visibility,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Name        string  `json:"name,omitempty"`
+	OnOpen         string          `json:"onOpen,omitempty"`
+	Port           float64         `json:"port,omitempty"`
+	Visibility     string          `json:"visibility,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	ReadinessProbe *ReadinessProbe `json:"readinessProbe,omitempty"`
+	Ignore         bool            `json:"ignore,omitempty"`
 }
 
 // ResolvedPlugins is the ResolvedPlugins message type
@@ -1928,6 +2003,13 @@ type UserEnvVarValue struct {
 	Value             string `json:"value,omitempty"`
 }
 
+// UserSSHPublicKey is the UserSSHPublicKey message type
+type UserSSHPublicKey struct {
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	CreationTime string `json:"creationTime,omitempty"`
+}
+
 // GenerateNewGitpodTokenOptions is the GenerateNewGitpodTokenOptions message type
 type GenerateNewGitpodTokenOptions struct {
 	Name string `json:"name,omitempty"`
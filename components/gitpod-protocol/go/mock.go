@@ -180,6 +180,21 @@ func (mr *MockAPIInterfaceMockRecorder) GenerateNewGitpodToken(ctx, options inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateNewGitpodToken", reflect.TypeOf((*MockAPIInterface)(nil).GenerateNewGitpodToken), ctx, options)
 }
 
+// GetAllEnvVars mocks base method.
+func (m *MockAPIInterface) GetAllEnvVars(ctx context.Context) ([]*UserEnvVarValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllEnvVars", ctx)
+	ret0, _ := ret[0].([]*UserEnvVarValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllEnvVars indicates an expected call of GetAllEnvVars.
+func (mr *MockAPIInterfaceMockRecorder) GetAllEnvVars(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllEnvVars", reflect.TypeOf((*MockAPIInterface)(nil).GetAllEnvVars), ctx)
+}
+
 // GetAuthProviders mocks base method.
 func (m *MockAPIInterface) GetAuthProviders(ctx context.Context) ([]*AuthProviderInfo, error) {
 	m.ctrl.T.Helper()
@@ -375,6 +390,36 @@ func (mr *MockAPIInterfaceMockRecorder) GetPortAuthenticationToken(ctx, workspac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortAuthenticationToken", reflect.TypeOf((*MockAPIInterface)(nil).GetPortAuthenticationToken), ctx, workspaceID)
 }
 
+// GetPortTCPAccess mocks base method.
+func (m *MockAPIInterface) GetPortTCPAccess(ctx context.Context, workspaceID string, port float32) (*PortTCPAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortTCPAccess", ctx, workspaceID, port)
+	ret0, _ := ret[0].(*PortTCPAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPortTCPAccess indicates an expected call of GetPortTCPAccess.
+func (mr *MockAPIInterfaceMockRecorder) GetPortTCPAccess(ctx, workspaceID, port interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortTCPAccess", reflect.TypeOf((*MockAPIInterface)(nil).GetPortTCPAccess), ctx, workspaceID, port)
+}
+
+// GetSSHPublicKeys mocks base method.
+func (m *MockAPIInterface) GetSSHPublicKeys(ctx context.Context) ([]*UserSSHPublicKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSSHPublicKeys", ctx)
+	ret0, _ := ret[0].([]*UserSSHPublicKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSSHPublicKeys indicates an expected call of GetSSHPublicKeys.
+func (mr *MockAPIInterfaceMockRecorder) GetSSHPublicKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSSHPublicKeys", reflect.TypeOf((*MockAPIInterface)(nil).GetSSHPublicKeys), ctx)
+}
+
 // GetSnapshots mocks base method.
 func (m *MockAPIInterface) GetSnapshots(ctx context.Context, workspaceID string) ([]*string, error) {
 	m.ctrl.T.Helper()
@@ -107,6 +107,15 @@ export interface AdditionalUserData {
 
     // Git clone URL pointing to the user's dotfile repo
     dotfileRepo?: string;
+    // SSH public keys which grant access to the user's workspaces
+    sshPublicKeys?: UserSSHPublicKey[];
+}
+
+export interface UserSSHPublicKey {
+    name: string;
+    // public key in the authorized_keys format, e.g. "ssh-ed25519 AAAA..."
+    key: string;
+    creationTime: string;
 }
 
 export interface EmailNotificationSettings {
@@ -574,8 +583,10 @@ export interface WorkspaceConfig {
     checkoutLocation?: string;
     workspaceLocation?: string;
     gitConfig?: { [config: string]: string };
+    checkout?: CheckoutConfig;
     github?: GithubAppConfig;
     vscode?: VSCodeConfig;
+    dotfiles?: DotfilesConfig;
 
     /** deprecated. Enabled by default **/
     experimentalNetwork?: boolean;
@@ -705,6 +716,9 @@ export interface PortConfig {
     visibility?: PortVisibility;
     description?: string;
     name?: string;
+    readinessProbe?: PortReadinessProbe;
+    ignore?: boolean;
+    headers?: PortHeadersConfig;
 }
 export namespace PortConfig {
     export function is(config: any): config is PortConfig {
@@ -712,9 +726,29 @@ export namespace PortConfig {
     }
 }
 
+export interface PortReadinessProbe {
+    path?: string;
+    status?: number;
+    timeout?: number;
+}
+
+export interface PortHeadersConfig {
+    corsAllowedOrigins?: string[];
+    xFrameOptions?: 'DENY' | 'SAMEORIGIN';
+    custom?: { [name: string]: string };
+}
+
 export interface PortRangeConfig {
     port: string;
     onOpen?: PortOnOpen;
+    visibility?: PortVisibility;
+    /** may contain `{port}`, which is replaced by the number of the served port */
+    description?: string;
+    /** may contain `{port}`, which is replaced by the number of the served port */
+    name?: string;
+    readinessProbe?: PortReadinessProbe;
+    ignore?: boolean;
+    headers?: PortHeadersConfig;
 }
 export namespace PortRangeConfig {
     export function is(config: any): config is PortRangeConfig {
@@ -722,12 +756,33 @@ export namespace PortRangeConfig {
     }
 }
 
+export interface CheckoutConfig {
+    /** number of commits to clone - 0 clones the full history. Defaults to 1. */
+    depth?: number;
+    /** makes the clone a partial one, which fetches file contents (blobless) or trees as well (treeless) on demand */
+    filter?: 'blobless' | 'treeless';
+    /** directories to check out, leaving out the rest of the repository */
+    sparse?: string[];
+    /** whether to fetch the full history in the background once the workspace is running. Defaults to true. */
+    unshallow?: boolean;
+    /** how to fetch Git LFS objects: while the workspace starts (fetch), in the background once it's running (lazy), or not at all (skip). Defaults to fetch. */
+    lfs?: 'fetch' | 'lazy' | 'skip';
+}
+
+export interface DotfilesConfig {
+    disabled?: boolean;
+    repository?: string;
+    installScript?: string;
+}
+
 export interface TaskConfig {
     name?: string;
     before?: string;
     init?: string;
     prebuild?: string;
     command?: string;
+    needs?: string[];
+    ready?: string;
     env?: { [env: string]: any };
     openIn?: 'bottom' | 'main' | 'left' | 'right';
     openMode?: 'split-top' | 'split-left' | 'split-right' | 'split-bottom' | 'tab-before' | 'tab-after';
@@ -119,6 +119,10 @@ export class GitpodHostUrl {
         return this.with(url => ({ pathname: `/api/auth/workspace-cookie/${instanceID}`, search: redirect ? "redirect" : "" }));
     }
 
+    asWorkspacePortAuth(instanceID: string, returnTo?: string): GitpodHostUrl {
+        return this.with(url => ({ pathname: `/api/auth/workspace-port/${instanceID}`, search: returnTo ? `returnTo=${encodeURIComponent(returnTo)}` : "" }));
+    }
+
     toString() {
         return this.url.toString();
     }
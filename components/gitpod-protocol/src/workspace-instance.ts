@@ -153,13 +153,18 @@ export interface WorkspaceInstanceConditions {
 
     // stopped_by_request is true if the workspace was stopped using a StopWorkspace call
     stoppedByRequest?: boolean;
+
+    // evicted contains the reason the workspace was evicted or preempted from its node. Evicted workspaces are
+    // restarted once they stopped. If this field is empty, the workspace was not evicted.
+    evicted?: string;
 }
 
 // AdmissionLevel describes who can access a workspace instance and its ports.
 export type AdmissionLevel = 'owner_only' | 'everyone';
 
-// PortVisibility describes how a port can be accessed
-export type PortVisibility = 'public' | 'private';
+// PortVisibility describes how a port can be accessed: by the workspace owner only, by every user
+// who is logged in to Gitpod, or by everyone with the port URL.
+export type PortVisibility = 'public' | 'private' | 'authenticated';
 
 // WorkspaceInstancePort describes a port exposed on a workspace instance
 export interface WorkspaceInstancePort {
@@ -173,6 +178,18 @@ export interface WorkspaceInstancePort {
     url?: string;
 }
 
+// PortTCPAccess grants time-limited access to a workspace port over raw TCP
+export interface PortTCPAccess {
+    // The address clients connect to using TLS. Its host name must be sent as server name (SNI).
+    address: string;
+
+    // The token clients send followed by a newline right after the TLS handshake
+    token: string;
+
+    // The date after which the token is no longer accepted, in ISO 8601 format
+    expiresAt: string;
+}
+
 // WorkspaceInstanceRepoStatus describes the status of th Git working copy of a workspace
 export interface WorkspaceInstanceRepoStatus {
     // branch is branch we're currently on
@@ -8,14 +8,9 @@ set -euo pipefail
 # kill background jobs when the script exits
 trap "jobs -p | xargs -r kill" SIGINT SIGTERM EXIT
 
+# supervisor starts the desktop IDE once the workspace content is available
 /ide-desktop/status "$1" "$2" &
 
-echo "Desktop IDE: Waiting for the content initializer ..."
-until curl -sS "$SUPERVISOR_ADDR"/_supervisor/v1/status/content/wait/true | grep '"available":true' > /dev/null; do
-    sleep 1
-done
-echo "Desktop IDE: Content available."
-
 # instead put them into /ide-desktop/backend/bin/idea64.vmoptions
 # otherwise JB will complain to a user on each startup
 # by default remote dev already set -Xmx2048m, see /ide-desktop/backend/plugins/remote-dev-server/bin/launcher.sh
@@ -26,12 +21,8 @@ if [ "${SUPERVISOR_DEBUG_ENABLE+}" = "true" ]; then
   export JAVA_TOOL_OPTIONS "-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=*:0"
 fi
 
-# Set default config and system directories under /workspace to preserve between restarts
-export IJ_HOST_CONFIG_BASE_DIR=/workspace/.config/JetBrains
-export IJ_HOST_SYSTEM_BASE_DIR=/workspace/.cache/JetBrains
-
-# Enable host status endpoint
-export CWM_HOST_STATUS_OVER_HTTP_TOKEN=gitpod
+# IJ_HOST_CONFIG_BASE_DIR, IJ_HOST_SYSTEM_BASE_DIR and CWM_HOST_STATUS_OVER_HTTP_TOKEN are set by supervisor,
+# see the env of supervisor-ide-config.json
 
 /ide-desktop/backend/bin/remote-dev-server.sh run "$GITPOD_REPO_ROOT"
 
@@ -67,6 +67,13 @@ func main() {
 		if backendPort == "" {
 			backendPort = defaultBackendPort
 		}
+		// supervisor uses this endpoint to check the health of the backend
+		_, err := resolveJsonLink(backendPort)
+		if err != nil {
+			errlog.Printf("backend is not healthy: %v\n", err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		gatewayLink, err := resolveGatewayLink(backendPort)
 		if err != nil {
 			errlog.Printf("cannot resolve gateway link: %v\n", err)
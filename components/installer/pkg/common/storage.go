@@ -92,6 +92,8 @@ func StorageConfig(context *RenderContext) storageconfig.StorageConfig {
 	_ = context.WithExperimental(func(ucfg *experimental.Config) error {
 		if ucfg.Workspace != nil {
 			res.Stage = storageconfig.Stage(ucfg.Workspace.Stage)
+			res.Encryption = ucfg.Workspace.BackupEncryption
+			res.Transfer = ucfg.Workspace.BackupTransfer
 		}
 		return nil
 	})
@@ -25,6 +25,7 @@ func configmap(ctx *common.RenderContext) ([]runtime.Object, error) {
 		PProfAddr:      fmt.Sprintf("localhost:%d", PProfPort),
 		PrometheusAddr: fmt.Sprintf("localhost:%d", PrometheusPort),
 		HostURL:        fmt.Sprintf("https://%s", ctx.Config.Domain),
+		Namespace:      ctx.Namespace,
 		Config: config.Config{
 			Blocklists: &config.Blocklists{
 				Very: &config.PerLevelBlocklist{
@@ -48,7 +49,8 @@ func configmap(ctx *common.RenderContext) ([]runtime.Object, error) {
 					Threshold:  resource.MustParse("250Mi"),
 				},
 			},
-			Kubernetes: config.Kubernetes{Enabled: true},
+			Kubernetes:       config.Kubernetes{Enabled: true},
+			RuntimeAllowlist: &config.RuntimeAllowlist{ConfigMap: config.DefaultAllowlistConfigMap},
 		},
 	}
 
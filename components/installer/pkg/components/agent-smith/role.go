@@ -7,6 +7,7 @@ package agentsmith
 import (
 	"fmt"
 
+	"github.com/gitpod-io/gitpod/agent-smith/pkg/config"
 	"github.com/gitpod-io/gitpod/installer/pkg/common"
 
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -34,6 +35,12 @@ func role(ctx *common.RenderContext) ([]runtime.Object, error) {
 				Resources: []string{"pods"},
 				Verbs:     []string{"get", "update"},
 			},
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"configmaps"},
+				Verbs:         []string{"get"},
+				ResourceNames: []string{config.DefaultAllowlistConfigMap},
+			},
 		},
 	}}, nil
 }
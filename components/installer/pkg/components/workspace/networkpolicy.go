@@ -65,6 +65,20 @@ func networkpolicy(ctx *common.RenderContext) ([]runtime.Object, error) {
 						},
 					},
 				},
+				{
+					// ws-manager tails the logs of headless workspaces from supervisor
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Protocol: common.TCPProtocol,
+							Port:     &intstr.IntOrString{IntVal: SupervisorPort},
+						},
+					},
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{MatchLabels: common.DefaultLabels(common.WSManagerComponent)},
+						},
+					},
+				},
 				{
 					Ports: []networkingv1.NetworkPolicyPort{
 						{
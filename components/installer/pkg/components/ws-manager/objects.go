@@ -9,6 +9,7 @@ import (
 )
 
 var Objects = common.CompositeRenderFunc(
+	clusterrole,
 	configmap,
 	deployment,
 	networkpolicy,
@@ -33,6 +33,8 @@ func role(ctx *common.RenderContext) ([]runtime.Object, error) {
 						"services",
 						"endpoints",
 						"configmaps",
+						"persistentvolumeclaims",
+						"secrets",
 					},
 					Verbs: []string{
 						"get",
@@ -45,6 +47,28 @@ func role(ctx *common.RenderContext) ([]runtime.Object, error) {
 						"deletecollection",
 					},
 				},
+				{
+					// workspaces can have their own network policy
+					APIGroups: []string{"networking.k8s.io"},
+					Resources: []string{"networkpolicies"},
+					Verbs: []string{
+						"get",
+						"create",
+						"update",
+						"delete",
+					},
+				},
+				{
+					APIGroups: []string{"snapshot.storage.k8s.io"},
+					Resources: []string{"volumesnapshots"},
+					Verbs: []string{
+						"get",
+						"list",
+						"create",
+						"watch",
+						"delete",
+					},
+				},
 			},
 		},
 	}, nil
@@ -10,7 +10,10 @@
 // If you use any setting herein, you forfeit support from Gitpod.
 package experimental
 
-import "k8s.io/apimachinery/pkg/api/resource"
+import (
+	storageconfig "github.com/gitpod-io/gitpod/content-service/api/config"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
 
 // Config contains all experimental configuration.
 type Config struct {
@@ -29,6 +32,13 @@ type WorkspaceConfig struct {
 		Limit            resource.Quantity `json:"limit"`
 		BurstLimit       resource.Quantity `json:"burstLimit"`
 	}
+
+	// BackupEncryption encrypts backups and snapshots before they're uploaded. With cloud storage or S3 the credential
+	// files of the KMS can live in the object storage secret, which is mounted at /mnt/secrets/storage.
+	BackupEncryption *storageconfig.EncryptionConfig `json:"backupEncryption,omitempty"`
+
+	// BackupTransfer transfers backups in parts, several at a time and within a bandwidth cap
+	BackupTransfer *storageconfig.TransferConfig `json:"backupTransfer,omitempty"`
 }
 
 type WebAppConfig struct {
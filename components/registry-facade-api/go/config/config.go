@@ -11,10 +11,25 @@ import (
 
 // ServiceConfig configures this service
 type ServiceConfig struct {
-	Registry       Config `json:"registry"`
-	AuthCfg        string `json:"dockerAuth"`
-	PProfAddr      string `json:"pprofAddr"`
-	PrometheusAddr string `json:"prometheusAddr"`
+	Registry Config `json:"registry"`
+	AuthCfg  string `json:"dockerAuth"`
+	// CredentialHelpers maps registry host patterns to docker credential helpers, e.g. "*.azurecr.io": "acr-env"
+	CredentialHelpers map[string]string `json:"credHelpers,omitempty"`
+	PProfAddr         string            `json:"pprofAddr"`
+	PrometheusAddr    string            `json:"prometheusAddr"`
+	// SignatureVerification refuses images which are not signed using cosign
+	SignatureVerification *SignatureVerificationConfig `json:"signatureVerification,omitempty"`
+	// AdminAddr serves the admin endpoints, e.g. to inspect and purge the blob cache
+	AdminAddr string `json:"adminAddr,omitempty"`
+}
+
+// SignatureVerificationConfig configures the verification of cosign image signatures
+type SignatureVerificationConfig struct {
+	Enabled bool `json:"enabled"`
+	// PublicKeys are the PEM encoded public keys of trusted signers
+	PublicKeys []string `json:"publicKeys"`
+	// Images are the repository patterns which must be signed, e.g. "eu.gcr.io/gitpod-core-dev/build/*". If empty, all images must be signed.
+	Images []string `json:"images,omitempty"`
 }
 
 // GetConfig loads and validates the configuration
@@ -53,6 +68,31 @@ type Config struct {
 	Store              string           `json:"store"`
 	RequireAuth        bool             `json:"requireAuth"`
 	TLS                *TLS             `json:"tls"`
+	IPFSCache          *IPFSCacheConfig `json:"ipfsCache,omitempty"`
+	BlobCache          *BlobCacheConfig `json:"blobCache,omitempty"`
+}
+
+// BlobCacheConfig configures the node-local cache of image layers in the store
+type BlobCacheConfig struct {
+	Enabled bool `json:"enabled"`
+	// MaxSizeBytes bounds the size of the store. Least recently used content is evicted first.
+	MaxSizeBytes int64 `json:"maxSizeBytes"`
+	// MaxBlobSizeBytes is the size of the largest layer we cache
+	MaxBlobSizeBytes int64 `json:"maxBlobSizeBytes"`
+}
+
+// IPFSCacheConfig configures the distribution of image layers between nodes using IPFS
+type IPFSCacheConfig struct {
+	Enabled bool `json:"enabled"`
+	// IPFSAddr is the address of the HTTP RPC API of the node's IPFS node. Environment variables are expanded, e.g. http://${NODE_IP}:5001
+	IPFSAddr string `json:"ipfsAddr"`
+	// Redis stores which IPFS content holds which layer
+	Redis RedisConfig `json:"redis"`
+}
+
+// RedisConfig configures the connection to Redis
+type RedisConfig struct {
+	Addr string `json:"addr"`
 }
 
 // StaticLayerCfg configure statically added layer
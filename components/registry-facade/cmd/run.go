@@ -95,18 +95,37 @@ var runCmd = &cobra.Command{
 			log.WithField("fn", authCfg).Info("using authentication for backing registries")
 		}
 
+		var credHelpers *registry.CredentialHelpers
+		if len(cfg.CredentialHelpers) > 0 {
+			credHelpers = registry.NewCredentialHelpers(cfg.CredentialHelpers)
+			log.WithField("credHelpers", cfg.CredentialHelpers).Info("using credential helpers for backing registries")
+		}
+
+		var verifier *registry.SignatureVerifier
+		if sv := cfg.SignatureVerification; sv != nil && sv.Enabled {
+			verifier, err = registry.NewSignatureVerifier(sv.PublicKeys, sv.Images)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create signature verifier")
+			}
+			log.WithField("images", sv.Images).Info("verifying image signatures")
+		}
+
 		resolverProvider := func() remotes.Resolver {
 			var resolverOpts docker.ResolverOptions
-			if dockerCfg != nil {
+			if dockerCfg != nil || credHelpers != nil {
 				resolverOpts.Hosts = docker.ConfigureDefaultRegistries(
-					docker.WithAuthorizer(authorizerFromDockerConfig(dockerCfg)),
+					docker.WithAuthorizer(authorizerFromDockerConfig(dockerCfg, credHelpers)),
 					docker.WithClient(&http.Client{
 						Transport: rtt,
 					}),
 				)
 			}
 
-			return docker.NewResolver(resolverOpts)
+			resolver := docker.NewResolver(resolverOpts)
+			if verifier != nil {
+				return registry.NewVerifyingResolver(resolver, verifier)
+			}
+			return resolver
 		}
 
 		registryDoneChan := make(chan struct{})
@@ -115,6 +134,15 @@ var runCmd = &cobra.Command{
 			log.WithError(err).Fatal("cannot create registry")
 		}
 		go watchConfig(configPath, reg)
+		if cfg.AdminAddr != "" && reg.BlobCache != nil {
+			go func() {
+				err := http.ListenAndServe(cfg.AdminAddr, reg.BlobCache.AdminHandler())
+				if err != nil {
+					log.WithError(err).Error("admin server failed")
+				}
+			}()
+			log.WithField("addr", cfg.AdminAddr).Info("started admin server")
+		}
 		go func() {
 			defer close(registryDoneChan)
 			reg.MustServe()
@@ -151,9 +179,21 @@ func init() {
 	rootCmd.AddCommand(runCmd)
 }
 
-// FromDockerConfig turns docker client config into docker registry hosts
-func authorizerFromDockerConfig(cfg *configfile.ConfigFile) docker.Authorizer {
+// authorizerFromDockerConfig turns docker client config and credential helpers into a docker registry authorizer.
+// Credential helpers take precedence over the static docker config.
+func authorizerFromDockerConfig(cfg *configfile.ConfigFile, credHelpers *registry.CredentialHelpers) docker.Authorizer {
 	return docker.NewDockerAuthorizer(docker.WithAuthCreds(func(host string) (user, pass string, err error) {
+		user, pass, ok, err := credHelpers.Credentials(host)
+		if err != nil {
+			log.WithError(err).WithField("host", host).Warn("cannot get credentials from credential helper")
+		}
+		if ok && err == nil {
+			return user, pass, nil
+		}
+		if cfg == nil {
+			return "", "", nil
+		}
+
 		auth, err := cfg.GetAuthConfig(host)
 		if err != nil {
 			return
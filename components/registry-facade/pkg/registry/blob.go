@@ -56,6 +56,9 @@ func (reg *Registry) handleBlob(ctx context.Context, r *http.Request) http.Handl
 		Spec:     spec,
 		Resolver: reg.Resolver(),
 		Store:    reg.Store,
+		IPFS:     reg.IPFS,
+		Cache:    reg.BlobCache,
+		Fallback: reg.fallback,
 		AdditionalSources: []BlobSource{
 			reg.LayerSource,
 		},
@@ -84,6 +87,9 @@ type blobHandler struct {
 	Spec              *api.ImageSpec
 	Resolver          remotes.Resolver
 	Store             content.Store
+	IPFS              *IPFSBlobCache
+	Cache             *BlobCache
+	Fallback          *manifestFallback
 	AdditionalSources []BlobSource
 	ConfigModifier    ConfigModifier
 
@@ -107,27 +113,56 @@ func (bh *blobHandler) getBlob(w http.ResponseWriter, r *http.Request) {
 			return err
 		}
 
+		proxying := proxyingBlobSource{Fetcher: fetcher, Blobs: manifest.Layers}
 		var srcs []BlobSource
 		srcs = append(srcs, storeBlobSource{Store: bh.Store})
-		srcs = append(srcs, proxyingBlobSource{Fetcher: fetcher, Blobs: manifest.Layers})
-		srcs = append(srcs, &configBlobSource{Fetcher: fetcher, Spec: bh.Spec, Manifest: manifest, ConfigModifier: bh.ConfigModifier})
+		srcs = append(srcs, proxying)
+		if bh.IPFS != nil {
+			srcs = append(srcs, ipfsBlobSource{Cache: bh.IPFS, MediaType: proxying.mediaType})
+		}
+		if bh.Cache != nil {
+			// layers cached on this node take precedence over fetching them from upstream or IPFS
+			srcs = append(srcs, storeBlobSource{Store: bh.Store})
+		}
+		srcs = append(srcs, &configBlobSource{Fetcher: fetcher, Store: bh.Store, Spec: bh.Spec, Manifest: manifest, ConfigModifier: bh.ConfigModifier})
 		srcs = append(srcs, bh.AdditionalSources...)
 
-		var src BlobSource
-		for _, s := range srcs {
-			if !s.HasBlob(ctx, bh.Spec, bh.Digest) {
+		// later sources take precedence - if one fails, we fall back to the ones before it
+		var (
+			src       BlobSource
+			mediaType string
+			url       string
+			rc        io.ReadCloser
+		)
+		err = distv2.ErrorCodeBlobUnknown
+		for i := len(srcs) - 1; i >= 0; i-- {
+			if !srcs[i].HasBlob(ctx, bh.Spec, bh.Digest) {
 				continue
 			}
-			src = s
-		}
-		if src == nil {
-			return distv2.ErrorCodeBlobUnknown
+			src = srcs[i]
+			mediaType, url, rc, err = src.GetBlob(ctx, bh.Spec, bh.Digest)
+			if err == nil {
+				break
+			}
+			log.WithError(err).WithField("digest", bh.Digest).Warn("cannot get blob from source, trying the next one")
 		}
-
-		mediaType, url, rc, err := src.GetBlob(ctx, bh.Spec, bh.Digest)
 		if err != nil {
 			return err
 		}
+		if _, fromUpstream := src.(proxyingBlobSource); fromUpstream && rc != nil {
+			if bh.IPFS != nil {
+				rc = seedIPFS(bh.IPFS, bh.Digest, rc)
+			}
+			if bh.Cache != nil {
+				bh.Cache.observe(bh.Digest, bh.Spec.BaseRef, false)
+				if desc, ok := proxying.descriptor(bh.Digest); ok {
+					rc = bh.Cache.cacheBlob(ctx, desc, bh.Spec.BaseRef, rc)
+				}
+			}
+		}
+		if _, fromStore := src.(storeBlobSource); fromStore && bh.Cache != nil {
+			bh.Cache.observe(bh.Digest, bh.Spec.BaseRef, true)
+		}
 		if rc != nil {
 			defer rc.Close()
 		}
@@ -157,7 +192,7 @@ func (bh *blobHandler) getBlob(w http.ResponseWriter, r *http.Request) {
 }
 
 func (bh *blobHandler) downloadManifest(ctx context.Context, ref string) (res *ociv1.Manifest, fetcher remotes.Fetcher, err error) {
-	_, desc, err := bh.Resolver.Resolve(ctx, ref)
+	desc, _, err := bh.Fallback.Resolve(ctx, bh.Resolver, ref)
 	if err != nil {
 		// ErrInvalidAuthorization
 		return nil, nil, err
@@ -168,7 +203,11 @@ func (bh *blobHandler) downloadManifest(ctx context.Context, ref string) (res *o
 		log.WithError(err).WithField("ref", ref).WithField("instanceId", bh.Name).Error("cannot get fetcher")
 		return nil, nil, err
 	}
-	res, _, err = DownloadManifest(ctx, fetcher, desc, WithStore(bh.Store))
+	res, mdesc, err := DownloadManifest(ctx, fetcher, desc, WithStore(bh.Store))
+	if err != nil {
+		return nil, nil, err
+	}
+	bh.Fallback.Remember(ref, *mdesc)
 	return
 }
 
@@ -230,6 +269,22 @@ func (pbs proxyingBlobSource) HasBlob(ctx context.Context, spec *api.ImageSpec,
 	return false
 }
 
+// descriptor returns the descriptor of a blob which is part of the image
+func (pbs proxyingBlobSource) descriptor(dgst digest.Digest) (desc ociv1.Descriptor, ok bool) {
+	for _, b := range pbs.Blobs {
+		if b.Digest == dgst {
+			return b, true
+		}
+	}
+	return ociv1.Descriptor{}, false
+}
+
+// mediaType returns the media type of a blob, or an empty string if the blob is not part of the image
+func (pbs proxyingBlobSource) mediaType(dgst digest.Digest) string {
+	desc, _ := pbs.descriptor(dgst)
+	return desc.MediaType
+}
+
 func (pbs proxyingBlobSource) GetBlob(ctx context.Context, spec *api.ImageSpec, dgst digest.Digest) (mediaType string, url string, data io.ReadCloser, err error) {
 	var src ociv1.Descriptor
 	for _, b := range pbs.Blobs {
@@ -252,6 +307,7 @@ func (pbs proxyingBlobSource) GetBlob(ctx context.Context, spec *api.ImageSpec,
 
 type configBlobSource struct {
 	Fetcher        remotes.Fetcher
+	Store          content.Store
 	Spec           *api.ImageSpec
 	Manifest       *ociv1.Manifest
 	ConfigModifier ConfigModifier
@@ -285,7 +341,7 @@ func (pbs *configBlobSource) GetBlob(ctx context.Context, spec *api.ImageSpec, d
 
 func (pbs *configBlobSource) getConfig(ctx context.Context) (rawCfg []byte, err error) {
 	manifest := *pbs.Manifest
-	cfg, err := DownloadConfig(ctx, pbs.Fetcher, manifest.Config)
+	cfg, err := DownloadConfig(ctx, pbs.Fetcher, manifest.Config, WithStore(pbs.Store))
 	if err != nil {
 		return
 	}
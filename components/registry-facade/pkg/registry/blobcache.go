@@ -0,0 +1,407 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru/simplelru"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	blobCacheResultHit  = "hit"
+	blobCacheResultMiss = "miss"
+
+	// maxImagesPerBlob limits the number of images we remember per cached blob for the deduplication metrics
+	maxImagesPerBlob = 32
+)
+
+// BlobCache bounds the size of a content store by evicting the least recently used content.
+// Layers fetched from upstream registries are added to the store, so that they're served from
+// the node's disk when the next workspace using the same layer starts.
+type BlobCache struct {
+	content.Store
+
+	MaxSize     int64
+	MaxBlobSize int64
+
+	mu      sync.Mutex
+	entries *lru.LRU
+	size    int64
+
+	bytes             prometheus.Gauge
+	blobs             prometheus.Gauge
+	requests          *prometheus.CounterVec
+	evictions         prometheus.Counter
+	deduplicatedBytes prometheus.Counter
+}
+
+type blobCacheEntry struct {
+	Digest     digest.Digest `json:"digest"`
+	Size       int64         `json:"size"`
+	LastAccess time.Time     `json:"lastAccess"`
+	Images     []string      `json:"images,omitempty"`
+}
+
+// NewBlobCache produces a new blob cache which accounts for all content already present in the store
+func NewBlobCache(ctx context.Context, store content.Store, maxSize, maxBlobSize int64, reg prometheus.Registerer) (*BlobCache, error) {
+	if maxSize <= 0 {
+		return nil, xerrors.Errorf("blob cache size must be positive")
+	}
+	if maxBlobSize <= 0 || maxBlobSize > maxSize {
+		maxBlobSize = maxSize
+	}
+
+	c := &BlobCache{
+		Store:       store,
+		MaxSize:     maxSize,
+		MaxBlobSize: maxBlobSize,
+		bytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "blob_cache_bytes",
+			Help: "Size of the content in the node-local blob cache",
+		}),
+		blobs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "blob_cache_blobs",
+			Help: "Number of blobs in the node-local blob cache",
+		}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blob_cache_requests_total",
+			Help: "Number of layer requests, by blob cache result",
+		}, []string{"result"}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "blob_cache_evictions_total",
+			Help: "Number of blobs evicted from the node-local blob cache",
+		}),
+		deduplicatedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "blob_cache_deduplicated_bytes_total",
+			Help: "Bytes served from the blob cache for a layer which was cached for a different image",
+		}),
+	}
+	if reg != nil {
+		for _, m := range []prometheus.Collector{c.bytes, c.blobs, c.requests, c.evictions, c.deduplicatedBytes} {
+			err := reg.Register(m)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	// the LRU is bounded by size in bytes rather than by number of entries, see add
+	c.entries, _ = lru.NewLRU(int(^uint(0)>>1), func(key, value interface{}) {
+		c.size -= value.(*blobCacheEntry).Size
+	})
+
+	var infos []content.Info
+	err := store.Walk(ctx, func(info content.Info) error {
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		// the local store has no blobs directory until the first content is written
+		return nil, xerrors.Errorf("cannot walk store: %w", err)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].UpdatedAt.Before(infos[j].UpdatedAt) })
+	for _, info := range infos {
+		c.add(info.Digest, info.Size, info.UpdatedAt)
+	}
+	log.WithField("size", c.size).WithField("blobs", c.entries.Len()).Info("blob cache ready")
+
+	return c, nil
+}
+
+// add adds content to the cache and evicts the least recently used content if the cache is full.
+// Must not be called with c.mu held.
+func (c *BlobCache) add(dgst digest.Digest, size int64, lastAccess time.Time) {
+	var evicted []digest.Digest
+
+	c.mu.Lock()
+	if v, ok := c.entries.Get(dgst); ok {
+		v.(*blobCacheEntry).LastAccess = lastAccess
+	} else {
+		c.entries.Add(dgst, &blobCacheEntry{Digest: dgst, Size: size, LastAccess: lastAccess})
+		c.size += size
+	}
+	for c.size > c.MaxSize && c.entries.Len() > 1 {
+		k, _, _ := c.entries.RemoveOldest()
+		evicted = append(evicted, k.(digest.Digest))
+	}
+	c.updateGauges()
+	c.mu.Unlock()
+
+	for _, dgst := range evicted {
+		c.evictions.Inc()
+		err := c.Store.Delete(context.Background(), dgst)
+		if err != nil && !errdefs.IsNotFound(err) {
+			log.WithError(err).WithField("digest", dgst).Warn("cannot evict blob from store")
+		}
+	}
+}
+
+func (c *BlobCache) updateGauges() {
+	c.bytes.Set(float64(c.size))
+	c.blobs.Set(float64(c.entries.Len()))
+}
+
+// ReaderAt marks the content as recently used
+func (c *BlobCache) ReaderAt(ctx context.Context, desc ociv1.Descriptor) (content.ReaderAt, error) {
+	r, err := c.Store.ReaderAt(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if v, ok := c.entries.Get(desc.Digest); ok {
+		v.(*blobCacheEntry).LastAccess = time.Now()
+	}
+	c.mu.Unlock()
+
+	return r, nil
+}
+
+// Writer adds committed content to the cache
+func (c *BlobCache) Writer(ctx context.Context, opts ...content.WriterOpt) (content.Writer, error) {
+	w, err := c.Store.Writer(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &blobCacheWriter{Writer: w, cache: c}, nil
+}
+
+// Delete removes content from the store and the cache
+func (c *BlobCache) Delete(ctx context.Context, dgst digest.Digest) error {
+	c.mu.Lock()
+	c.entries.Remove(dgst)
+	c.updateGauges()
+	c.mu.Unlock()
+
+	return c.Store.Delete(ctx, dgst)
+}
+
+type blobCacheWriter struct {
+	content.Writer
+	cache *BlobCache
+}
+
+func (w *blobCacheWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	err := w.Writer.Commit(ctx, size, expected, opts...)
+	if err != nil && !errdefs.IsAlreadyExists(err) {
+		return err
+	}
+
+	dgst := expected
+	if dgst == "" {
+		dgst = w.Writer.Digest()
+	}
+	info, ierr := w.cache.Store.Info(ctx, dgst)
+	if ierr != nil {
+		log.WithError(ierr).WithField("digest", dgst).Warn("cannot add committed content to blob cache")
+		return err
+	}
+	w.cache.add(dgst, info.Size, time.Now())
+
+	return err
+}
+
+// observe records a layer request served for an image. Hits for images other than the ones
+// the layer was cached for count as deduplicated bytes.
+func (c *BlobCache) observe(dgst digest.Digest, image string, hit bool) {
+	if !hit {
+		c.requests.WithLabelValues(blobCacheResultMiss).Inc()
+		return
+	}
+	c.requests.WithLabelValues(blobCacheResultHit).Inc()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.entries.Peek(dgst)
+	if !ok {
+		return
+	}
+	entry := v.(*blobCacheEntry)
+	for _, img := range entry.Images {
+		if img == image {
+			return
+		}
+	}
+	if len(entry.Images) > 0 {
+		c.deduplicatedBytes.Add(float64(entry.Size))
+	}
+	if len(entry.Images) < maxImagesPerBlob {
+		entry.Images = append(entry.Images, image)
+	}
+}
+
+// cacheBlob adds a blob to the cache while it is read. If the blob is too large, already being
+// cached or not read completely it is not cached.
+func (c *BlobCache) cacheBlob(ctx context.Context, desc ociv1.Descriptor, image string, rc io.ReadCloser) io.ReadCloser {
+	if desc.Size <= 0 || desc.Size > c.MaxBlobSize {
+		return rc
+	}
+	w, err := c.Writer(ctx, content.WithRef("blobcache-"+desc.Digest.String()), content.WithDescriptor(desc))
+	if err != nil {
+		if !errdefs.IsUnavailable(err) && !errdefs.IsAlreadyExists(err) {
+			log.WithError(err).WithField("digest", desc.Digest).Warn("cannot cache blob")
+		}
+		return rc
+	}
+	err = w.Truncate(0)
+	if err != nil {
+		w.Close()
+		log.WithError(err).WithField("digest", desc.Digest).Warn("cannot cache blob")
+		return rc
+	}
+
+	return &blobCachingReader{
+		ReadCloser: rc,
+		ctx:        ctx,
+		cache:      c,
+		w:          w,
+		desc:       desc,
+		image:      image,
+	}
+}
+
+type blobCachingReader struct {
+	io.ReadCloser
+
+	ctx    context.Context
+	cache  *BlobCache
+	w      content.Writer
+	desc   ociv1.Descriptor
+	image  string
+	failed bool
+	eof    bool
+}
+
+func (r *blobCachingReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	if n > 0 && !r.failed {
+		_, werr := r.w.Write(p[:n])
+		if werr != nil {
+			log.WithError(werr).WithField("digest", r.desc.Digest).Warn("cannot cache blob")
+			r.failed = true
+		}
+	}
+	if err == io.EOF {
+		r.eof = true
+	}
+	return
+}
+
+func (r *blobCachingReader) Close() error {
+	err := r.ReadCloser.Close()
+	defer r.w.Close()
+
+	if !r.eof || r.failed {
+		return err
+	}
+	cerr := r.w.Commit(r.ctx, r.desc.Size, r.desc.Digest, content.WithLabels(map[string]string{
+		"Content-Type": r.desc.MediaType,
+	}))
+	if cerr != nil && !errdefs.IsAlreadyExists(cerr) {
+		log.WithError(cerr).WithField("digest", r.desc.Digest).Warn("cannot cache blob")
+		return err
+	}
+
+	r.cache.mu.Lock()
+	if v, ok := r.cache.entries.Peek(r.desc.Digest); ok {
+		entry := v.(*blobCacheEntry)
+		if len(entry.Images) == 0 {
+			entry.Images = append(entry.Images, r.image)
+		}
+	}
+	r.cache.mu.Unlock()
+
+	return err
+}
+
+// AdminHandler serves endpoints to inspect (GET /blobcache) and purge (DELETE /blobcache[/<digest>]) the cache
+func (c *BlobCache) AdminHandler() http.Handler {
+	routes := mux.NewRouter()
+	routes.HandleFunc("/blobcache", c.handleList).Methods(http.MethodGet)
+	routes.HandleFunc("/blobcache", c.handlePurge).Methods(http.MethodDelete)
+	routes.HandleFunc("/blobcache/{digest}", c.handlePurge).Methods(http.MethodDelete)
+	return routes
+}
+
+func (c *BlobCache) handleList(w http.ResponseWriter, r *http.Request) {
+	type listing struct {
+		Size    int64            `json:"size"`
+		MaxSize int64            `json:"maxSize"`
+		Blobs   []blobCacheEntry `json:"blobs"`
+	}
+
+	c.mu.Lock()
+	res := listing{
+		Size:    c.size,
+		MaxSize: c.MaxSize,
+		Blobs:   make([]blobCacheEntry, 0, c.entries.Len()),
+	}
+	keys := c.entries.Keys()
+	// most recently used first
+	for i := len(keys) - 1; i >= 0; i-- {
+		v, _ := c.entries.Peek(keys[i])
+		entry := *v.(*blobCacheEntry)
+		entry.Images = append([]string(nil), entry.Images...)
+		res.Blobs = append(res.Blobs, entry)
+	}
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(res)
+	if err != nil {
+		log.WithError(err).Warn("cannot list blob cache")
+	}
+}
+
+func (c *BlobCache) handlePurge(w http.ResponseWriter, r *http.Request) {
+	var dgsts []digest.Digest
+	if d, ok := mux.Vars(r)["digest"]; ok {
+		dgst, err := digest.Parse(d)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.mu.Lock()
+		known := c.entries.Contains(dgst)
+		c.mu.Unlock()
+		if !known {
+			http.Error(w, "blob not found", http.StatusNotFound)
+			return
+		}
+		dgsts = []digest.Digest{dgst}
+	} else {
+		c.mu.Lock()
+		for _, k := range c.entries.Keys() {
+			dgsts = append(dgsts, k.(digest.Digest))
+		}
+		c.mu.Unlock()
+	}
+
+	for _, dgst := range dgsts {
+		err := c.Delete(r.Context(), dgst)
+		if err != nil && !errdefs.IsNotFound(err) {
+			log.WithError(err).WithField("digest", dgst).Warn("cannot purge blob from store")
+		}
+	}
+	log.WithField("blobs", len(dgsts)).Info("purged blob cache")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containerd/containerd/content/local"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestBlobCache(t *testing.T) {
+	ctx := context.Background()
+	store, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := NewBlobCache(ctx, store, 25, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blob := func(content string) ociv1.Descriptor {
+		return ociv1.Descriptor{
+			MediaType: ociv1.MediaTypeImageLayerGzip,
+			Digest:    digest.FromString(content),
+			Size:      int64(len(content)),
+		}
+	}
+	pull := func(content, image string) {
+		rc := cache.cacheBlob(ctx, blob(content), image, io.NopCloser(bytes.NewReader([]byte(content))))
+		_, err := io.Copy(io.Discard, rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cached := func(content string) bool {
+		_, err := store.Info(ctx, blob(content).Digest)
+		return err == nil
+	}
+
+	pull("layer-one", "image-a")
+	pull("layer-two", "image-a")
+	if !cached("layer-one") || !cached("layer-two") {
+		t.Fatal("expected layers to be cached")
+	}
+
+	// blobs larger than the max blob size are not cached
+	pull("a-very-large-layer", "image-a")
+	if cached("a-very-large-layer") {
+		t.Error("expected large layer not to be cached")
+	}
+
+	// using layer-one makes layer-two the least recently used one
+	r, err := cache.ReaderAt(ctx, blob("layer-one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	pull("layer-333", "image-b")
+	if !cached("layer-one") || cached("layer-two") || !cached("layer-333") {
+		t.Errorf("unexpected eviction: layer-one %v, layer-two %v, layer-333 %v", cached("layer-one"), cached("layer-two"), cached("layer-333"))
+	}
+	if cache.size != 18 {
+		t.Errorf("unexpected cache size: expected 18, got %d", cache.size)
+	}
+
+	// content already in the store is accounted for on startup
+	restarted, err := NewBlobCache(ctx, store, 25, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted.size != 18 {
+		t.Errorf("unexpected cache size after restart: expected 18, got %d", restarted.size)
+	}
+
+	admin := restarted.AdminHandler()
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/blobcache/"+blob("layer-one").Digest.String(), nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("unexpected status purging a blob: %d", rec.Code)
+	}
+	if cached("layer-one") || !cached("layer-333") {
+		t.Error("expected only layer-one to be purged")
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/blobcache", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("unexpected status purging the cache: %d", rec.Code)
+	}
+	if cached("layer-333") || restarted.size != 0 {
+		t.Errorf("expected cache to be empty, size is %d", restarted.size)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"golang.org/x/xerrors"
+)
+
+// credentialHelperTTL is the time we cache credentials obtained from a helper. Cloud registries issue
+// credentials which are valid for at least an hour, hence we can safely reuse them for a while.
+const credentialHelperTTL = 5 * time.Minute
+
+// NewCredentialHelpers produces a credential helper resolver from a host pattern to helper name mapping,
+// e.g. "*.dkr.ecr.*.amazonaws.com": "ecr-login". Patterns follow path.Match syntax.
+func NewCredentialHelpers(helpers map[string]string) *CredentialHelpers {
+	res := &CredentialHelpers{
+		Program: client.NewShellProgramFunc,
+		cache:   make(map[string]cachedCredentials),
+	}
+	for pattern, helper := range helpers {
+		res.helpers = append(res.helpers, credentialHelper{Pattern: pattern, Helper: helper})
+	}
+	// exact hosts take precedence over patterns, and more specific patterns over general ones
+	sort.Slice(res.helpers, func(i, j int) bool {
+		hi, hj := res.helpers[i], res.helpers[j]
+		ei, ej := !strings.ContainsAny(hi.Pattern, "*?["), !strings.ContainsAny(hj.Pattern, "*?[")
+		if ei != ej {
+			return ei
+		}
+		if len(hi.Pattern) != len(hj.Pattern) {
+			return len(hi.Pattern) > len(hj.Pattern)
+		}
+		return hi.Pattern < hj.Pattern
+	})
+	return res
+}
+
+// CredentialHelpers resolves registry credentials using docker credential helpers
+// (e.g. docker-credential-ecr-login). Those helpers exchange the cloud identity of the
+// pod for short-lived registry credentials, hence don't expire like static docker config secrets.
+type CredentialHelpers struct {
+	// Program produces the program used to talk to a helper binary, e.g. docker-credential-ecr-login
+	Program func(name string) client.ProgramFunc
+
+	helpers []credentialHelper
+
+	mu    sync.Mutex
+	cache map[string]cachedCredentials
+}
+
+type credentialHelper struct {
+	Pattern string
+	Helper  string
+}
+
+type cachedCredentials struct {
+	User    string
+	Secret  string
+	Expires time.Time
+}
+
+// Helper returns the name of the credential helper responsible for a host
+func (c *CredentialHelpers) Helper(host string) (helper string, ok bool) {
+	if c == nil {
+		return "", false
+	}
+	for _, h := range c.helpers {
+		if h.Pattern == host {
+			return h.Helper, true
+		}
+		if match, _ := path.Match(h.Pattern, host); match {
+			return h.Helper, true
+		}
+	}
+	return "", false
+}
+
+// Credentials returns the credentials for a host. If no helper is configured for the host ok is false.
+func (c *CredentialHelpers) Credentials(host string) (user, secret string, ok bool, err error) {
+	helper, ok := c.Helper(host)
+	if !ok {
+		return "", "", false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, exists := c.cache[host]; exists && time.Now().Before(cached.Expires) {
+		return cached.User, cached.Secret, true, nil
+	}
+
+	creds, err := client.Get(c.Program("docker-credential-"+helper), host)
+	if credentials.IsErrCredentialsNotFound(err) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", true, xerrors.Errorf("cannot get credentials for %s from %s helper: %w", host, helper, err)
+	}
+
+	c.cache[host] = cachedCredentials{
+		User:    creds.Username,
+		Secret:  creds.Secret,
+		Expires: time.Now().Add(credentialHelperTTL),
+	}
+	return creds.Username, creds.Secret, true, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker-credential-helpers/client"
+)
+
+func TestCredentialHelpersHelper(t *testing.T) {
+	helpers := NewCredentialHelpers(map[string]string{
+		"*.dkr.ecr.*.amazonaws.com": "ecr-login",
+		"gcr.io":                    "gcr",
+		"*.gcr.io":                  "gcr",
+		"*-docker.pkg.dev":          "gcr",
+		"*.azurecr.io":              "acr-env",
+		"special.azurecr.io":        "special",
+	})
+
+	tests := []struct {
+		Host   string
+		Helper string
+		OK     bool
+	}{
+		{Host: "123456789012.dkr.ecr.eu-west-1.amazonaws.com", Helper: "ecr-login", OK: true},
+		{Host: "gcr.io", Helper: "gcr", OK: true},
+		{Host: "eu.gcr.io", Helper: "gcr", OK: true},
+		{Host: "europe-west1-docker.pkg.dev", Helper: "gcr", OK: true},
+		{Host: "gitpod.azurecr.io", Helper: "acr-env", OK: true},
+		{Host: "special.azurecr.io", Helper: "special", OK: true},
+		{Host: "registry-1.docker.io"},
+		{Host: "azurecr.io"},
+	}
+	for _, test := range tests {
+		t.Run(test.Host, func(t *testing.T) {
+			helper, ok := helpers.Helper(test.Host)
+			if helper != test.Helper || ok != test.OK {
+				t.Errorf("unexpected helper: expected %q (%v), got %q (%v)", test.Helper, test.OK, helper, ok)
+			}
+		})
+	}
+}
+
+type fakeCredentialHelper struct {
+	Response string
+	Calls    *int
+}
+
+func (f fakeCredentialHelper) Output() ([]byte, error) {
+	*f.Calls++
+	return []byte(f.Response), nil
+}
+
+func (f fakeCredentialHelper) Input(in io.Reader) {
+	_, _ = io.Copy(ioutil.Discard, in)
+}
+
+func TestCredentialHelpersCredentials(t *testing.T) {
+	var (
+		calls    int
+		programs []string
+	)
+	helpers := NewCredentialHelpers(map[string]string{"*.azurecr.io": "acr-env"})
+	helpers.Program = func(name string) client.ProgramFunc {
+		programs = append(programs, name)
+		return func(args ...string) client.Program {
+			return fakeCredentialHelper{
+				Response: `{"ServerURL":"gitpod.azurecr.io","Username":"user","Secret":"secret"}`,
+				Calls:    &calls,
+			}
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		user, secret, ok, err := helpers.Credentials("gitpod.azurecr.io")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || user != "user" || secret != "secret" {
+			t.Errorf("unexpected credentials: %q, %q, %v", user, secret, ok)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected credentials to be cached, but helper was called %d times", calls)
+	}
+	if len(programs) != 1 || programs[0] != "docker-credential-acr-env" {
+		t.Errorf("unexpected helper programs: %v", programs)
+	}
+
+	_, _, ok, err := helpers.Credentials("registry-1.docker.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("expected no credentials for host without helper")
+	}
+
+	var nilHelpers *CredentialHelpers
+	if _, _, ok, _ := nilHelpers.Credentials("gitpod.azurecr.io"); ok {
+		t.Errorf("expected no credentials without helpers")
+	}
+}
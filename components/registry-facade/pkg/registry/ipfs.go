@@ -0,0 +1,294 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/registry-facade/api"
+)
+
+// ipfsSeedTimeout is the time we give IPFS to add a layer
+const ipfsSeedTimeout = 10 * time.Minute
+
+// IPFSBlobCache distributes image layers between nodes using IPFS. Every node runs an IPFS node which
+// registry-facade adds the layers it downloads from upstream to. Redis maps layer digests to IPFS content IDs,
+// so that other nodes can fetch the layers from their peers instead of the upstream registry.
+type IPFSBlobCache struct {
+	Redis *redis.Client
+	IPFS  *IPFSClient
+}
+
+func ipfsCacheKey(dgst digest.Digest) string {
+	return "ipfs-cid:" + dgst.String()
+}
+
+// Get returns the IPFS content ID of a blob, or an empty string if the blob is not in IPFS.
+func (c *IPFSBlobCache) Get(ctx context.Context, dgst digest.Digest) (cid string, err error) {
+	cid, err = c.Redis.WithContext(ctx).Get(ipfsCacheKey(dgst)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", xerrors.Errorf("cannot get IPFS content ID of %s: %w", dgst, err)
+	}
+	return cid, nil
+}
+
+// Store adds a blob to IPFS and makes it available to the other nodes.
+func (c *IPFSBlobCache) Store(ctx context.Context, dgst digest.Digest, content io.Reader) error {
+	cid, err := c.IPFS.Add(ctx, content)
+	if err != nil {
+		return xerrors.Errorf("cannot add %s to IPFS: %w", dgst, err)
+	}
+	err = c.Redis.WithContext(ctx).Set(ipfsCacheKey(dgst), cid, 0).Err()
+	if err != nil {
+		return xerrors.Errorf("cannot store IPFS content ID of %s: %w", dgst, err)
+	}
+	log.WithField("digest", dgst).WithField("cid", cid).Debug("stored blob in IPFS")
+	return nil
+}
+
+// forget removes a blob from the cache, e.g. if it cannot be retrieved from IPFS anymore.
+func (c *IPFSBlobCache) forget(ctx context.Context, dgst digest.Digest) {
+	err := c.Redis.WithContext(ctx).Del(ipfsCacheKey(dgst)).Err()
+	if err != nil {
+		log.WithError(err).WithField("digest", dgst).Warn("cannot remove IPFS content ID")
+	}
+}
+
+// ipfsBlobSource serves layers from IPFS if a peer has added them before.
+type ipfsBlobSource struct {
+	Cache     *IPFSBlobCache
+	MediaType func(dgst digest.Digest) string
+}
+
+func (s ipfsBlobSource) HasBlob(ctx context.Context, spec *api.ImageSpec, dgst digest.Digest) bool {
+	if s.MediaType(dgst) == "" {
+		// we only serve blobs of the image we were asked for
+		return false
+	}
+	cid, err := s.Cache.Get(ctx, dgst)
+	if err != nil {
+		log.WithError(err).Warn("cannot check IPFS blob cache")
+		return false
+	}
+	return cid != ""
+}
+
+func (s ipfsBlobSource) GetBlob(ctx context.Context, spec *api.ImageSpec, dgst digest.Digest) (mediaType string, url string, data io.ReadCloser, err error) {
+	cid, err := s.Cache.Get(ctx, dgst)
+	if err != nil {
+		return
+	}
+	if cid == "" {
+		err = xerrors.Errorf("%s is not in IPFS", dgst)
+		return
+	}
+
+	rc, err := s.Cache.IPFS.Cat(ctx, cid)
+	if err != nil {
+		s.Cache.forget(ctx, dgst)
+		return
+	}
+	// We verify the content before we serve it. If the content ID is stale or wrong, we forget it
+	// and fall back to the next source instead of failing the download midway.
+	data, err = verifyBlob(dgst, rc)
+	if err != nil {
+		log.WithError(err).WithField("digest", dgst).WithField("cid", cid).Warn("IPFS served bad content")
+		s.Cache.forget(ctx, dgst)
+		return
+	}
+	return s.MediaType(dgst), "", data, nil
+}
+
+// verifyBlob reads a blob into a temporary file and checks it against its digest. The returned reader
+// serves the verified blob and removes the temporary file when closed.
+func verifyBlob(dgst digest.Digest, rc io.ReadCloser) (io.ReadCloser, error) {
+	defer rc.Close()
+
+	f, err := os.CreateTemp("", "ipfs-blob-*")
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create temporary file for %s: %w", dgst, err)
+	}
+	verifier := dgst.Verifier()
+	_, err = io.Copy(io.MultiWriter(f, verifier), rc)
+	if err == nil && !verifier.Verified() {
+		err = xerrors.Errorf("content does not match digest %s", dgst)
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &tempFileReader{File: f}, nil
+}
+
+// tempFileReader removes its file when closed.
+type tempFileReader struct {
+	*os.File
+}
+
+func (r *tempFileReader) Close() error {
+	err := r.File.Close()
+	os.Remove(r.File.Name())
+	return err
+}
+
+// IPFSClient talks to the HTTP RPC API of an IPFS node.
+type IPFSClient struct {
+	// Addr is the base URL of the API, e.g. http://10.0.0.1:5001
+	Addr   string
+	Client *http.Client
+}
+
+type ipfsError struct {
+	Message string `json:"Message"`
+}
+
+func (c *IPFSClient) call(ctx context.Context, command string, args url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	u := strings.TrimSuffix(c.Addr, "/") + "/api/v0/" + command + "?" + args.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var ipfsErr ipfsError
+		_ = json.NewDecoder(resp.Body).Decode(&ipfsErr)
+		return nil, xerrors.Errorf("IPFS %s failed with status %d: %s", command, resp.StatusCode, ipfsErr.Message)
+	}
+	return resp, nil
+}
+
+// Add adds and pins content and returns its content ID.
+func (c *IPFSClient) Add(ctx context.Context, content io.Reader) (cid string, err error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", "blob")
+		if err == nil {
+			_, err = io.Copy(part, content)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	resp, err := c.call(ctx, "add", url.Values{
+		"pin":         {"true"},
+		"cid-version": {"1"},
+		"quieter":     {"true"},
+	}, pr, mw.FormDataContentType())
+	if err != nil {
+		pr.CloseWithError(err)
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Hash string `json:"Hash"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return "", xerrors.Errorf("cannot decode IPFS add response: %w", err)
+	}
+	if res.Hash == "" {
+		return "", xerrors.Errorf("IPFS add returned no content ID")
+	}
+	return res.Hash, nil
+}
+
+// Cat returns the content for a content ID. The caller must close the returned reader.
+func (c *IPFSClient) Cat(ctx context.Context, cid string) (io.ReadCloser, error) {
+	resp, err := c.call(ctx, "cat", url.Values{"arg": {cid}}, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// seedIPFS keeps a copy of a blob we download from upstream and adds it to IPFS once the download completed.
+func seedIPFS(cache *IPFSBlobCache, dgst digest.Digest, rc io.ReadCloser) io.ReadCloser {
+	f, err := os.CreateTemp("", "ipfs-seed-*")
+	if err != nil {
+		log.WithError(err).Warn("cannot create temporary file for seeding IPFS")
+		return rc
+	}
+	verifier := dgst.Verifier()
+	return &ipfsSeedingReader{
+		Reader:   io.TeeReader(rc, io.MultiWriter(f, verifier)),
+		src:      rc,
+		file:     f,
+		verifier: verifier,
+		cache:    cache,
+		digest:   dgst,
+	}
+}
+
+type ipfsSeedingReader struct {
+	io.Reader
+	src      io.ReadCloser
+	file     *os.File
+	verifier digest.Verifier
+	cache    *IPFSBlobCache
+	digest   digest.Digest
+}
+
+func (r *ipfsSeedingReader) Close() error {
+	err := r.src.Close()
+	if !r.verifier.Verified() {
+		// the client did not read the whole blob - we don't seed partial content
+		r.file.Close()
+		os.Remove(r.file.Name())
+		return err
+	}
+
+	go func() {
+		defer os.Remove(r.file.Name())
+		defer r.file.Close()
+
+		_, err := r.file.Seek(0, io.SeekStart)
+		if err != nil {
+			log.WithError(err).Warn("cannot seed IPFS")
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ipfsSeedTimeout)
+		defer cancel()
+		err = r.cache.Store(ctx, r.digest, r.file)
+		if err != nil {
+			log.WithError(err).WithField("digest", r.digest).Warn("cannot seed IPFS")
+		}
+	}()
+	return err
+}
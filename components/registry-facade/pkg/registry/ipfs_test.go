@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// fakeIPFSNode implements the add and cat commands of the IPFS HTTP RPC API
+type fakeIPFSNode struct {
+	content map[string]string
+}
+
+func (n *fakeIPFSNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	switch r.URL.Path {
+	case "/api/v0/add":
+		if r.URL.Query().Get("pin") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(f)
+		cid := "bafy" + strings.Repeat("x", len(n.content))
+		n.content[cid] = string(b)
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": "blob", "Hash": cid})
+	case "/api/v0/cat":
+		c, ok := n.content[r.URL.Query().Get("arg")]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(ipfsError{Message: "block was not found locally (offline)"})
+			return
+		}
+		_, _ = io.WriteString(w, c)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestIPFSClient(t *testing.T) {
+	srv := httptest.NewServer(&fakeIPFSNode{content: make(map[string]string)})
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := &IPFSClient{Addr: srv.URL}
+
+	cid, err := client.Add(ctx, strings.NewReader("hello layer"))
+	if err != nil {
+		t.Fatalf("cannot add content: %q", err)
+	}
+
+	rc, err := client.Cat(ctx, cid)
+	if err != nil {
+		t.Fatalf("cannot cat content: %q", err)
+	}
+	act, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("cannot read content: %q", err)
+	}
+	if string(act) != "hello layer" {
+		t.Errorf("unexpected content: expected %q, got %q", "hello layer", string(act))
+	}
+
+	_, err = client.Cat(ctx, "bafyunknown")
+	if err == nil || !strings.Contains(err.Error(), "block was not found") {
+		t.Errorf("expected error for unknown content, got %v", err)
+	}
+}
+
+func TestVerifyBlob(t *testing.T) {
+	tests := []struct {
+		Desc    string
+		Content string
+		Digest  digest.Digest
+		Error   bool
+	}{
+		{Desc: "matching content", Content: "hello layer", Digest: digest.FromString("hello layer")},
+		{Desc: "stale content", Content: "another layer", Digest: digest.FromString("hello layer"), Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			rc, err := verifyBlob(test.Digest, io.NopCloser(strings.NewReader(test.Content)))
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			defer rc.Close()
+
+			act, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(act) != test.Content {
+				t.Errorf("unexpected content: expected %q, got %q", test.Content, string(act))
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/remotes"
 	distv2 "github.com/docker/distribution/registry/api/v2"
 	"github.com/gorilla/handlers"
@@ -58,6 +59,7 @@ func (reg *Registry) handleManifest(ctx context.Context, r *http.Request) http.H
 		Resolver:       reg.Resolver(),
 		Store:          reg.Store,
 		ConfigModifier: reg.ConfigModifier,
+		Fallback:       reg.fallback,
 	}
 	reference := getReference(ctx)
 	dgst, err := digest.Parse(reference)
@@ -90,6 +92,7 @@ type manifestHandler struct {
 	Resolver       remotes.Resolver
 	Store          content.Store
 	ConfigModifier ConfigModifier
+	Fallback       *manifestFallback
 
 	Name   string
 	Tag    string
@@ -135,24 +138,21 @@ func (mh *manifestHandler) getManifest(w http.ResponseWriter, r *http.Request) {
 		// Note: we ignore the mh.Digest for now because we always return a manifest, never a manifest index.
 		ref := mh.Spec.BaseRef
 
-		_, desc, err := mh.Resolver.Resolve(ctx, ref)
+		desc, cached, err := mh.Fallback.Resolve(ctx, mh.Resolver, ref)
 		if err != nil {
 			log.WithError(err).WithField("ref", ref).WithFields(logFields).Error("cannot resolve")
 			// ErrInvalidAuthorization
 			return err
 		}
+		if cached {
+			log.WithField("ref", ref).WithFields(logFields).Warn("upstream registry unavailable - serving cached manifest")
+		}
 
 		fetcher, err := mh.Resolver.Fetcher(ctx, ref)
 		if err != nil {
 			log.WithError(err).WithField("ref", ref).WithFields(logFields).Error("cannot get fetcher")
 			return distv2.ErrorCodeManifestUnknown.WithDetail(err)
 		}
-		rc, err := fetcher.Fetch(ctx, desc)
-		if err != nil {
-			log.WithError(err).WithField("ref", ref).WithField("desc", desc).WithFields(logFields).Error("cannot fetch manifest")
-			return distv2.ErrorCodeManifestUnknown.WithDetail(err)
-		}
-		defer rc.Close()
 
 		manifest, ndesc, err := DownloadManifest(ctx, fetcher, desc, WithStore(mh.Store))
 		if err != nil {
@@ -160,12 +160,13 @@ func (mh *manifestHandler) getManifest(w http.ResponseWriter, r *http.Request) {
 			return distv2.ErrorCodeManifestUnknown.WithDetail(err)
 		}
 		desc = *ndesc
+		mh.Fallback.Remember(ref, desc)
 
 		var p []byte
 		switch desc.MediaType {
 		case images.MediaTypeDockerSchema2Manifest, ociv1.MediaTypeImageManifest:
 			// download config
-			cfg, err := DownloadConfig(ctx, fetcher, manifest.Config)
+			cfg, err := DownloadConfig(ctx, fetcher, manifest.Config, WithStore(mh.Store))
 			if err != nil {
 				log.WithError(err).WithFields(logFields).Error("cannot download config")
 				return err
@@ -247,30 +248,63 @@ func (mh *manifestHandler) getManifest(w http.ResponseWriter, r *http.Request) {
 }
 
 // DownloadConfig downloads and unmarshales OCIv2 image config, referred to by an OCI descriptor.
-func DownloadConfig(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.Descriptor) (cfg *ociv1.Image, err error) {
+// If a store is given, the config is served from and placed in that store.
+func DownloadConfig(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.Descriptor, options ...ManifestDownloadOption) (cfg *ociv1.Image, err error) {
 	if desc.MediaType != images.MediaTypeDockerSchema2Config &&
 		desc.MediaType != ociv1.MediaTypeImageConfig {
 
 		return nil, xerrors.Errorf("unsupported media type")
 	}
 
-	rc, err := fetcher.Fetch(ctx, desc)
+	var opts manifestDownloadOptions
+	for _, o := range options {
+		o(&opts)
+	}
+
+	var (
+		rc           io.ReadCloser
+		placeInStore bool
+	)
+	if opts.Store != nil {
+		r, err := opts.Store.ReaderAt(ctx, desc)
+		if err == nil {
+			rc = &reader{ReaderAt: r}
+		} else if !errdefs.IsNotFound(err) {
+			log.WithError(err).WithField("desc", desc).Warn("cannot get config from store")
+		}
+	}
+	if rc == nil {
+		placeInStore = opts.Store != nil
+		rc, err = fetcher.Fetch(ctx, desc)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot download config: %w", err)
+		}
+	}
+	inpt, err := io.ReadAll(rc)
+	rc.Close()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot download config: %w", err)
 	}
-	defer rc.Close()
 
 	var res ociv1.Image
-	err = json.NewDecoder(rc).Decode(&res)
+	err = json.Unmarshal(inpt, &res)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot decode config: %w", err)
 	}
 
+	if placeInStore {
+		err = content.WriteBlob(ctx, opts.Store, desc.Digest.String(), bytes.NewReader(inpt), desc)
+		if err != nil {
+			log.WithError(err).WithField("desc", desc).Warn("cannot store config")
+		}
+	}
+
 	return &res, nil
 }
 
 type manifestDownloadOptions struct {
-	Store content.Store
+	Store    content.Store
+	Platform platforms.MatchComparer
 }
 
 // ManifestDownloadOption alters the default manifest download behaviour
@@ -283,10 +317,20 @@ func WithStore(store content.Store) ManifestDownloadOption {
 	}
 }
 
+// WithPlatform chooses the manifest for a platform from manifest lists. Defaults to the platform
+// registry-facade runs on, i.e. the platform of the node.
+func WithPlatform(platform platforms.MatchComparer) ManifestDownloadOption {
+	return func(o *manifestDownloadOptions) {
+		o.Platform = platform
+	}
+}
+
 // DownloadManifest downloads and unmarshals the manifest of the given desc. If the desc points to manifest list
-// we choose the first manifest in that list.
+// we choose the manifest which best matches the platform.
 func DownloadManifest(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.Descriptor, options ...ManifestDownloadOption) (cfg *ociv1.Manifest, rdesc *ociv1.Descriptor, err error) {
-	var opts manifestDownloadOptions
+	opts := manifestDownloadOptions{
+		Platform: platforms.Default(),
+	}
 	for _, o := range options {
 		o(&opts)
 	}
@@ -325,7 +369,7 @@ func DownloadManifest(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.D
 
 	switch rdesc.MediaType {
 	case images.MediaTypeDockerSchema2ManifestList, ociv1.MediaTypeImageIndex:
-		// we received a manifest list which means we'll pick the default platform
+		// we received a manifest list which means we'll pick the manifest of our platform
 		// and fetch that manifest
 		var list ociv1.Index
 		err = json.Unmarshal(inpt, &list)
@@ -338,8 +382,11 @@ func DownloadManifest(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.D
 			return
 		}
 
-		// TODO: choose by platform, not just the first manifest
-		md := list.Manifests[0]
+		var md ociv1.Descriptor
+		md, err = selectManifest(list.Manifests, opts.Platform)
+		if err != nil {
+			return
+		}
 		rc, err = fetcher.Fetch(ctx, md)
 		if err != nil {
 			err = xerrors.Errorf("cannot download config: %w", err)
@@ -393,6 +440,39 @@ func DownloadManifest(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.D
 	return
 }
 
+// selectManifest chooses the manifest from a manifest list which best matches the platform.
+// Manifests without platform are only chosen if no manifest matches the platform.
+func selectManifest(manifests []ociv1.Descriptor, platform platforms.MatchComparer) (ociv1.Descriptor, error) {
+	var (
+		res   ociv1.Descriptor
+		found bool
+	)
+	for _, m := range manifests {
+		if m.Platform == nil || !platform.Match(*m.Platform) {
+			continue
+		}
+		if !found || platform.Less(*m.Platform, *res.Platform) {
+			res = m
+			found = true
+		}
+	}
+	if found {
+		return res, nil
+	}
+
+	for _, m := range manifests {
+		if m.Platform == nil {
+			return m, nil
+		}
+	}
+
+	var available []string
+	for _, m := range manifests {
+		available = append(available, platforms.Format(*m.Platform))
+	}
+	return ociv1.Descriptor{}, xerrors.Errorf("image is not available for this platform, only for %s", strings.Join(available, ", "))
+}
+
 func (mh *manifestHandler) putManifest(w http.ResponseWriter, r *http.Request) {
 	respondWithError(w, distv2.ErrorCodeManifestInvalid)
 }
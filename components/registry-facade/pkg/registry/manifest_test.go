@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestSelectManifest(t *testing.T) {
+	manifest := func(name string, platform *ociv1.Platform) ociv1.Descriptor {
+		return ociv1.Descriptor{
+			MediaType: ociv1.MediaTypeImageManifest,
+			Digest:    digest.FromString(name),
+			Platform:  platform,
+		}
+	}
+	var (
+		amd64       = manifest("amd64", &ociv1.Platform{OS: "linux", Architecture: "amd64"})
+		arm64       = manifest("arm64", &ociv1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+		armv7       = manifest("armv7", &ociv1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"})
+		attestation = manifest("attestation", &ociv1.Platform{OS: "unknown", Architecture: "unknown"})
+		noPlatform  = manifest("no-platform", nil)
+	)
+
+	tests := []struct {
+		Name        string
+		Manifests   []ociv1.Descriptor
+		Platform    string
+		Expectation ociv1.Descriptor
+		Error       bool
+	}{
+		{
+			Name:        "amd64",
+			Manifests:   []ociv1.Descriptor{arm64, amd64, attestation},
+			Platform:    "linux/amd64",
+			Expectation: amd64,
+		},
+		{
+			Name:        "arm64",
+			Manifests:   []ociv1.Descriptor{amd64, arm64, attestation},
+			Platform:    "linux/arm64",
+			Expectation: arm64,
+		},
+		{
+			Name:        "arm64 prefers exact match",
+			Manifests:   []ociv1.Descriptor{armv7, arm64},
+			Platform:    "linux/arm64",
+			Expectation: arm64,
+		},
+		{
+			Name:        "manifest without platform",
+			Manifests:   []ociv1.Descriptor{noPlatform},
+			Platform:    "linux/arm64",
+			Expectation: noPlatform,
+		},
+		{
+			Name:      "unavailable platform",
+			Manifests: []ociv1.Descriptor{amd64, attestation},
+			Platform:  "linux/arm64",
+			Error:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			platform, err := platforms.Parse(test.Platform)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			act, err := selectManifest(test.Manifests, platforms.Only(platform))
+			if test.Error {
+				if err == nil {
+					t.Errorf("expected an error, got manifest %s", act.Digest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act.Digest != test.Expectation.Digest {
+				t.Errorf("unexpected manifest: expected %s, got %s", test.Expectation.Digest, act.Digest)
+			}
+		})
+	}
+}
@@ -5,7 +5,10 @@
 package registry
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -18,15 +21,21 @@ func NewMeasuringRegistryRoundTripper(delegate http.RoundTripper, reg prometheus
 	if err != nil {
 		return nil, err
 	}
+	upstream, err := newUpstreamMetrics(reg)
+	if err != nil {
+		return nil, err
+	}
 	return &measuringRegistryRoundTripper{
 		delegate: delegate,
 		metrics:  metrics,
+		upstream: upstream,
 	}, nil
 }
 
 type measuringRegistryRoundTripper struct {
 	delegate http.RoundTripper
 	metrics  *metrics
+	upstream *upstreamMetrics
 }
 
 func (m *measuringRegistryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -34,6 +43,18 @@ func (m *measuringRegistryRoundTripper) RoundTrip(req *http.Request) (*http.Resp
 	resp, err := m.delegate.RoundTrip(req)
 	dt := time.Since(t0)
 
+	reqType := "other"
+	if strings.Contains(req.URL.Path, "/manifests/") {
+		reqType = "manifest"
+	} else if strings.Contains(req.URL.Path, "/blobs/") {
+		reqType = "blob"
+	}
+	m.upstream.ReqHist.WithLabelValues(reqType).Observe(dt.Seconds())
+	m.upstream.ReqCounter.WithLabelValues(reqType, errorClass(resp, err)).Inc()
+	if reqType == "blob" && err == nil && resp.StatusCode == http.StatusOK && resp.ContentLength >= 0 {
+		m.upstream.BlobSizeHist.Observe(float64(resp.ContentLength))
+	}
+
 	if strings.Contains(req.URL.Path, "/manifests/") {
 		m.metrics.ManifestHist.Observe(dt.Seconds())
 		if err != nil {
@@ -107,3 +128,64 @@ func newMetrics(reg prometheus.Registerer, upstream bool) (*metrics, error) {
 		BlobDownloadSpeedHist: blobDownloadSpeedHist,
 	}, nil
 }
+
+// upstreamMetrics describe the requests registry-facade makes to upstream registries
+type upstreamMetrics struct {
+	ReqHist      *prometheus.HistogramVec
+	ReqCounter   *prometheus.CounterVec
+	BlobSizeHist prometheus.Histogram
+}
+
+func newUpstreamMetrics(reg prometheus.Registerer) (*upstreamMetrics, error) {
+	res := &upstreamMetrics{
+		ReqHist: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "req_duration_seconds",
+			Help:    "duration of requests made to the registry, by type (manifest, blob, other)",
+			Buckets: []float64{0.05, 0.1, 0.5, 1, 2, 5, 10, 60, 300, 600, 1800},
+		}, []string{"type"}),
+		ReqCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "req_total",
+			Help: "number of requests made to the registry, by type and result class",
+		}, []string{"type", "class"}),
+		BlobSizeHist: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "blob_size_bytes",
+			Help:    "size of blobs downloaded from the registry",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 12),
+		}),
+	}
+	for _, m := range []prometheus.Collector{res.ReqHist, res.ReqCounter, res.BlobSizeHist} {
+		err := reg.Register(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// errorClass classifies the outcome of a registry request for the upstream metrics
+func errorClass(resp *http.Response, err error) string {
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err) {
+			return "timeout"
+		}
+		if errors.Is(err, context.Canceled) {
+			return "canceled"
+		}
+		return "network"
+	}
+
+	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return "auth"
+	case code == http.StatusNotFound:
+		return "not_found"
+	case code == http.StatusTooManyRequests:
+		return "rate_limited"
+	case code >= 500:
+		return "server_error"
+	case code >= 400:
+		return "client_error"
+	default:
+		return "ok"
+	}
+}
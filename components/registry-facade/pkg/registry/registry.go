@@ -26,6 +26,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/distribution/registry/api/errcode"
 	distv2 "github.com/docker/distribution/registry/api/v2"
+	"github.com/go-redis/redis/v7"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/xerrors"
@@ -68,8 +69,11 @@ type Registry struct {
 	LayerSource    LayerSource
 	ConfigModifier ConfigModifier
 	SpecProvider   map[string]ImageSpecProvider
+	IPFS           *IPFSBlobCache
+	BlobCache      *BlobCache
 
 	staticLayerSource *RevisioningLayerSource
+	fallback          *manifestFallback
 	metrics           *metrics
 	srv               *http.Server
 }
@@ -84,11 +88,19 @@ func NewRegistry(cfg config.Config, newResolver ResolverProvider, reg prometheus
 	if err != nil {
 		return nil, err
 	}
-	// TODO: GC the store
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	var blobCache *BlobCache
+	if cfg.BlobCache != nil && cfg.BlobCache.Enabled {
+		blobCache, err = NewBlobCache(ctx, store, cfg.BlobCache.MaxSizeBytes, cfg.BlobCache.MaxBlobSizeBytes, reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create blob cache: %w", err)
+		}
+		store = blobCache
+	}
+
 	metrics, err := newMetrics(reg, true)
 	if err != nil {
 		return nil, err
@@ -171,14 +183,32 @@ func NewRegistry(cfg config.Config, newResolver ResolverProvider, reg prometheus
 		specProvider[api.ProviderPrefixRemote] = specprov
 	}
 
+	var ipfs *IPFSBlobCache
+	if cfg.IPFSCache != nil && cfg.IPFSCache.Enabled {
+		ipfs = &IPFSBlobCache{
+			Redis: redis.NewClient(&redis.Options{Addr: cfg.IPFSCache.Redis.Addr}),
+			IPFS:  &IPFSClient{Addr: os.ExpandEnv(cfg.IPFSCache.IPFSAddr)},
+		}
+		log.WithField("ipfsAddr", ipfs.IPFS.Addr).Info("distributing layers using IPFS")
+	}
+
+	fallback := newManifestFallback()
+	err = fallback.RegisterMetrics(reg)
+	if err != nil {
+		return nil, err
+	}
+
 	layerSource := CompositeLayerSource(layerSources)
 	return &Registry{
 		Config:            cfg,
 		Resolver:          newResolver,
 		Store:             store,
 		SpecProvider:      specProvider,
+		IPFS:              ipfs,
+		BlobCache:         blobCache,
 		LayerSource:       layerSource,
 		staticLayerSource: staticLayer,
+		fallback:          fallback,
 		ConfigModifier:    NewConfigModifierFromLayerSource(layerSource),
 		metrics:           metrics,
 	}, nil
@@ -0,0 +1,244 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// cosignSignatureAnnotation holds the base64 encoded signature of a cosign signature layer
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// maxSignaturePayloadSize limits the size of signature payloads we download
+	maxSignaturePayloadSize = 1 << 20
+	// verifiedDigestCacheSize is the number of verified image digests we remember
+	verifiedDigestCacheSize = 1024
+)
+
+// SignatureError is returned when an image is not signed by any of the trusted keys
+type SignatureError struct {
+	Ref    string
+	Reason string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("signature verification of %s failed: %s", e.Ref, e.Reason)
+}
+
+// IsSignatureError returns true if err is caused by a failed signature verification
+func IsSignatureError(err error) bool {
+	var serr *SignatureError
+	return xerrors.As(err, &serr)
+}
+
+// NewSignatureVerifier produces a verifier for cosign signatures from PEM encoded public keys.
+// Images only need to be signed if their repository matches one of the patterns (path.Match syntax, e.g. "eu.gcr.io/gitpod-core-dev/build/*").
+// If there are no patterns, all images need to be signed.
+func NewSignatureVerifier(publicKeys []string, images []string) (*SignatureVerifier, error) {
+	if len(publicKeys) == 0 {
+		return nil, xerrors.Errorf("signature verification requires at least one public key")
+	}
+
+	res := &SignatureVerifier{Images: images}
+	for i, k := range publicKeys {
+		block, _ := pem.Decode([]byte(k))
+		if block == nil {
+			return nil, xerrors.Errorf("public key %d is not PEM encoded", i)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse public key %d: %w", i, err)
+		}
+		switch key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, xerrors.Errorf("public key %d has unsupported type %T", i, key)
+		}
+		res.keys = append(res.keys, key)
+	}
+	res.verified, _ = lru.New(verifiedDigestCacheSize)
+	return res, nil
+}
+
+// SignatureVerifier verifies cosign signatures of images. Signatures are expected next to the image,
+// i.e. in the same repository tagged with sha256-<digest>.sig, as pushed by cosign sign.
+type SignatureVerifier struct {
+	Images []string
+
+	keys     []crypto.PublicKey
+	verified *lru.Cache
+}
+
+// Requires returns true if images of the repository need to be signed
+func (v *SignatureVerifier) Requires(repo string) bool {
+	if len(v.Images) == 0 {
+		return true
+	}
+	for _, p := range v.Images {
+		if match, _ := path.Match(p, repo); match {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks that the image ref resolved to desc is signed by one of the trusted keys
+func (v *SignatureVerifier) Verify(ctx context.Context, resolver remotes.Resolver, ref string, desc ociv1.Descriptor) error {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return xerrors.Errorf("cannot parse ref %s: %w", ref, err)
+	}
+	repo := reference.TrimNamed(named).String()
+	if !v.Requires(repo) {
+		return nil
+	}
+	cacheKey := repo + "@" + desc.Digest.String()
+	if _, ok := v.verified.Get(cacheKey); ok {
+		return nil
+	}
+
+	sigRef := fmt.Sprintf("%s:%s-%s.sig", repo, desc.Digest.Algorithm(), desc.Digest.Encoded())
+	_, sigDesc, err := resolver.Resolve(ctx, sigRef)
+	if err != nil {
+		return &SignatureError{Ref: ref, Reason: fmt.Sprintf("cannot find signature %s: %v", sigRef, err)}
+	}
+	fetcher, err := resolver.Fetcher(ctx, sigRef)
+	if err != nil {
+		return xerrors.Errorf("cannot get fetcher for %s: %w", sigRef, err)
+	}
+	var manifest ociv1.Manifest
+	err = fetchJSON(ctx, fetcher, sigDesc, &manifest)
+	if err != nil {
+		return xerrors.Errorf("cannot download signature manifest %s: %w", sigRef, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		sig, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok || layer.Size > maxSignaturePayloadSize {
+			continue
+		}
+		payload, err := fetchBlob(ctx, fetcher, layer)
+		if err != nil {
+			log.WithError(err).WithField("ref", sigRef).Warn("cannot download signature payload")
+			continue
+		}
+		if v.verifyPayload(payload, sig, desc.Digest) {
+			v.verified.Add(cacheKey, struct{}{})
+			return nil
+		}
+	}
+	return &SignatureError{Ref: ref, Reason: "no valid signature by a trusted key"}
+}
+
+// verifyPayload checks that the payload is signed by one of the trusted keys and refers to the image digest
+func (v *SignatureVerifier) verifyPayload(payload []byte, signature string, dgst digest.Digest) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	var signed bool
+	hash := sha256.Sum256(payload)
+	for _, key := range v.keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			signed = ecdsa.VerifyASN1(k, hash[:], sig)
+		case *rsa.PublicKey:
+			signed = rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil
+		case ed25519.PublicKey:
+			signed = ed25519.Verify(k, payload, sig)
+		}
+		if signed {
+			break
+		}
+	}
+	if !signed {
+		return false
+	}
+
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	err = json.Unmarshal(payload, &simpleSigning)
+	if err != nil {
+		return false
+	}
+	return simpleSigning.Critical.Image.DockerManifestDigest == dgst.String()
+}
+
+func fetchJSON(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.Descriptor, dst interface{}) error {
+	content, err := fetchBlob(ctx, fetcher, desc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, dst)
+}
+
+func fetchBlob(ctx context.Context, fetcher remotes.Fetcher, desc ociv1.Descriptor) ([]byte, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxSignaturePayloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxSignaturePayloadSize {
+		return nil, xerrors.Errorf("%s is too large", desc.Digest)
+	}
+	if desc.Digest != "" && digest.FromBytes(content) != desc.Digest {
+		return nil, xerrors.Errorf("digest mismatch for %s", desc.Digest)
+	}
+	return content, nil
+}
+
+// NewVerifyingResolver produces a resolver which only resolves images with a valid signature
+func NewVerifyingResolver(delegate remotes.Resolver, verifier *SignatureVerifier) remotes.Resolver {
+	return &verifyingResolver{Resolver: delegate, verifier: verifier}
+}
+
+type verifyingResolver struct {
+	remotes.Resolver
+	verifier *SignatureVerifier
+}
+
+func (r *verifyingResolver) Resolve(ctx context.Context, ref string) (name string, desc ociv1.Descriptor, err error) {
+	name, desc, err = r.Resolver.Resolve(ctx, ref)
+	if err != nil {
+		return
+	}
+	err = r.verifier.Verify(ctx, r.Resolver, ref, desc)
+	if err != nil {
+		return "", ociv1.Descriptor{}, err
+	}
+	return
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// fakeRegistry resolves refs to descriptors and serves blobs by digest
+type fakeRegistry struct {
+	remotes.Resolver
+
+	Refs  map[string]ociv1.Descriptor
+	Blobs map[digest.Digest][]byte
+}
+
+func (r *fakeRegistry) Resolve(ctx context.Context, ref string) (name string, desc ociv1.Descriptor, err error) {
+	desc, ok := r.Refs[ref]
+	if !ok {
+		return "", desc, errdefs.ErrNotFound
+	}
+	return ref, desc, nil
+}
+
+func (r *fakeRegistry) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	return r, nil
+}
+
+func (r *fakeRegistry) Fetch(ctx context.Context, desc ociv1.Descriptor) (io.ReadCloser, error) {
+	b, ok := r.Blobs[desc.Digest]
+	if !ok {
+		return nil, errdefs.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (r *fakeRegistry) add(content []byte, mediaType string) ociv1.Descriptor {
+	dgst := digest.FromBytes(content)
+	r.Blobs[dgst] = content
+	return ociv1.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(content))}
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	genKey := func() (*ecdsa.PrivateKey, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	}
+	var (
+		trustedKey, trustedPEM = genKey()
+		otherKey, _            = genKey()
+	)
+
+	reg := &fakeRegistry{Refs: map[string]ociv1.Descriptor{}, Blobs: map[digest.Digest][]byte{}}
+	image := func(repo, tag string) ociv1.Descriptor {
+		desc := reg.add([]byte(repo+":"+tag), ociv1.MediaTypeImageManifest)
+		reg.Refs[repo+":"+tag] = desc
+		return desc
+	}
+	sign := func(repo string, img ociv1.Descriptor, key *ecdsa.PrivateKey, signedDigest digest.Digest) {
+		payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, repo, signedDigest))
+		hash := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		layer := reg.add(payload, "application/vnd.dev.cosign.simplesigning.v1+json")
+		layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+		manifest, err := json.Marshal(ociv1.Manifest{Layers: []ociv1.Descriptor{layer}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reg.Refs[fmt.Sprintf("%s:sha256-%s.sig", repo, img.Digest.Encoded())] = reg.add(manifest, ociv1.MediaTypeImageManifest)
+	}
+
+	signed := image("docker.io/gitpod/signed", "latest")
+	sign("docker.io/gitpod/signed", signed, trustedKey, signed.Digest)
+	image("docker.io/gitpod/unsigned", "latest")
+	otherSigner := image("docker.io/gitpod/other-signer", "latest")
+	sign("docker.io/gitpod/other-signer", otherSigner, otherKey, otherSigner.Digest)
+	replayed := image("docker.io/gitpod/replayed", "latest")
+	sign("docker.io/gitpod/replayed", replayed, trustedKey, signed.Digest)
+	image("docker.io/library/alpine", "latest")
+
+	tests := []struct {
+		Ref   string
+		Error bool
+	}{
+		{Ref: "docker.io/gitpod/signed:latest"},
+		{Ref: "docker.io/gitpod/unsigned:latest", Error: true},
+		{Ref: "docker.io/gitpod/other-signer:latest", Error: true},
+		{Ref: "docker.io/gitpod/replayed:latest", Error: true},
+		{Ref: "docker.io/library/alpine:latest"},
+	}
+	for _, test := range tests {
+		t.Run(test.Ref, func(t *testing.T) {
+			verifier, err := NewSignatureVerifier([]string{trustedPEM}, []string{"docker.io/gitpod/*"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolver := NewVerifyingResolver(reg, verifier)
+
+			_, _, err = resolver.Resolve(context.Background(), test.Ref)
+			if test.Error && !IsSignatureError(err) {
+				t.Errorf("expected signature error, got %v", err)
+			}
+			if !test.Error && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
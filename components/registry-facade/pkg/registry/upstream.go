@@ -0,0 +1,194 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	lru "github.com/hashicorp/golang-lru"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// upstreamFailureThreshold is the number of consecutive failures after which we consider an upstream registry down
+	upstreamFailureThreshold = 5
+	// upstreamCooldown is the time we don't contact an upstream registry which is considered down
+	upstreamCooldown = 30 * time.Second
+	// resolvedManifestCacheSize is the number of image refs we remember the manifest of
+	resolvedManifestCacheSize = 1024
+)
+
+// circuitBreaker tracks the health of upstream registries. Once a registry failed upstreamFailureThreshold
+// times in a row, the circuit opens and we don't contact the registry until the cooldown has passed.
+// Afterwards we try again, and close the circuit on success.
+type circuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*circuitState
+	now   func() time.Time
+
+	open *prometheus.GaugeVec
+}
+
+type circuitState struct {
+	Failures  int
+	OpenUntil time.Time
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{
+		Threshold: upstreamFailureThreshold,
+		Cooldown:  upstreamCooldown,
+		hosts:     make(map[string]*circuitState),
+		now:       time.Now,
+		open: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upstream_circuit_open",
+			Help: "Set to 1 while an upstream registry is considered down",
+		}, []string{"host"}),
+	}
+}
+
+// Allow returns false while the circuit of a host is open
+func (b *circuitBreaker) Allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.hosts[host]
+	if !ok {
+		return true
+	}
+	return !b.now().Before(s.OpenUntil)
+}
+
+// Success closes the circuit of a host
+func (b *circuitBreaker) Success(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.hosts[host]; !ok {
+		return
+	}
+	delete(b.hosts, host)
+	b.open.WithLabelValues(host).Set(0)
+}
+
+// Failure opens the circuit of a host once it failed too often
+func (b *circuitBreaker) Failure(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.hosts[host]
+	if !ok {
+		s = &circuitState{}
+		b.hosts[host] = s
+	}
+	s.Failures++
+	if s.Failures < b.Threshold {
+		return
+	}
+	if s.OpenUntil.IsZero() || !b.now().Before(s.OpenUntil) {
+		log.WithField("host", host).WithField("failures", s.Failures).Warn("upstream registry is unavailable - serving cached manifests")
+	}
+	s.OpenUntil = b.now().Add(b.Cooldown)
+	b.open.WithLabelValues(host).Set(1)
+}
+
+// manifestFallback resolves image refs and remembers the manifest they resolved to. When the upstream
+// registry is unavailable it serves the manifest we resolved last time, so that workspaces keep
+// starting during registry outages. The manifest content itself is served from the store.
+type manifestFallback struct {
+	breaker  *circuitBreaker
+	resolved *lru.Cache
+
+	fallbacks prometheus.Counter
+}
+
+func newManifestFallback() *manifestFallback {
+	resolved, _ := lru.New(resolvedManifestCacheSize)
+	return &manifestFallback{
+		breaker:  newCircuitBreaker(),
+		resolved: resolved,
+		fallbacks: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "manifest_fallback_total",
+			Help: "Number of manifests served from cache because the upstream registry was unavailable",
+		}),
+	}
+}
+
+// RegisterMetrics registers the circuit breaker metrics
+func (f *manifestFallback) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, m := range []prometheus.Collector{f.breaker.open, f.fallbacks} {
+		err := reg.Register(m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolve resolves a ref to its descriptor. If the upstream registry is unavailable, it returns the
+// descriptor of the image manifest the ref resolved to last time and cached is true.
+func (f *manifestFallback) Resolve(ctx context.Context, resolver remotes.Resolver, ref string) (desc ociv1.Descriptor, cached bool, err error) {
+	if f == nil {
+		_, desc, err = resolver.Resolve(ctx, ref)
+		return
+	}
+
+	host := upstreamHost(ref)
+	if f.breaker.Allow(host) {
+		_, desc, err = resolver.Resolve(ctx, ref)
+		if err == nil {
+			f.breaker.Success(host)
+			return desc, false, nil
+		}
+		if errdefs.IsNotFound(err) || IsSignatureError(err) {
+			// the registry is up, the image just does not exist (anymore) or must not be used
+			f.breaker.Success(host)
+			return desc, false, err
+		}
+		if ctx.Err() != nil {
+			// the request was cancelled, which says nothing about the registry
+			return desc, false, err
+		}
+		f.breaker.Failure(host)
+	} else {
+		err = xerrors.Errorf("upstream registry %s is unavailable", host)
+	}
+
+	v, ok := f.resolved.Get(ref)
+	if !ok {
+		return desc, false, err
+	}
+	log.WithError(err).WithField("ref", ref).Debug("cannot resolve ref - using cached manifest")
+	f.fallbacks.Inc()
+	return v.(ociv1.Descriptor), true, nil
+}
+
+// Remember stores the descriptor of the image manifest a ref resolved to
+func (f *manifestFallback) Remember(ref string, manifest ociv1.Descriptor) {
+	if f == nil {
+		return
+	}
+	f.resolved.Add(ref, manifest)
+}
+
+func upstreamHost(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ref
+	}
+	return reference.Domain(named)
+}
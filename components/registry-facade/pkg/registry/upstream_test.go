@@ -0,0 +1,98 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/xerrors"
+)
+
+type fakeResolver struct {
+	remotes.Resolver
+
+	Desc  ociv1.Descriptor
+	Err   error
+	Calls int
+}
+
+func (r *fakeResolver) Resolve(ctx context.Context, ref string) (name string, desc ociv1.Descriptor, err error) {
+	r.Calls++
+	return ref, r.Desc, r.Err
+}
+
+func TestManifestFallback(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		ref      = "docker.io/library/alpine:latest"
+		index    = ociv1.Descriptor{MediaType: ociv1.MediaTypeImageIndex, Digest: digest.FromString("index")}
+		manifest = ociv1.Descriptor{MediaType: ociv1.MediaTypeImageManifest, Digest: digest.FromString("manifest")}
+		now      = time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+		resolver = &fakeResolver{Desc: index}
+	)
+	fallback := newManifestFallback()
+	fallback.breaker.now = func() time.Time { return now }
+
+	desc, cached, err := fallback.Resolve(ctx, resolver, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached || desc.Digest != index.Digest {
+		t.Fatalf("unexpected resolution: %s (cached %v)", desc.Digest, cached)
+	}
+	fallback.Remember(ref, manifest)
+
+	// the registry goes down: we serve the manifest we resolved last time
+	resolver.Err = xerrors.Errorf("unexpected status code: 503 Service Unavailable")
+	for i := 0; i < upstreamFailureThreshold; i++ {
+		desc, cached, err = fallback.Resolve(ctx, resolver, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cached || desc.Digest != manifest.Digest {
+			t.Fatalf("unexpected fallback: %s (cached %v)", desc.Digest, cached)
+		}
+	}
+	if resolver.Calls != upstreamFailureThreshold+1 {
+		t.Errorf("unexpected number of resolve calls: %d", resolver.Calls)
+	}
+
+	// the circuit is open: we don't contact the registry at all
+	_, cached, err = fallback.Resolve(ctx, resolver, ref)
+	if err != nil || !cached {
+		t.Errorf("expected cached manifest while circuit is open, got err %v", err)
+	}
+	if resolver.Calls != upstreamFailureThreshold+1 {
+		t.Errorf("expected registry not to be contacted while circuit is open, got %d calls", resolver.Calls)
+	}
+	_, _, err = fallback.Resolve(ctx, resolver, "docker.io/library/unknown:latest")
+	if err == nil {
+		t.Errorf("expected an error for refs we have never resolved")
+	}
+
+	// after the cooldown the registry is back
+	now = now.Add(upstreamCooldown)
+	resolver.Err = nil
+	desc, cached, err = fallback.Resolve(ctx, resolver, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached || desc.Digest != index.Digest {
+		t.Errorf("expected registry to be contacted after cooldown, got %s (cached %v)", desc.Digest, cached)
+	}
+
+	// images which don't exist are not served from cache
+	resolver.Err = errdefs.ErrNotFound
+	_, _, err = fallback.Resolve(ctx, resolver, ref)
+	if !errdefs.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
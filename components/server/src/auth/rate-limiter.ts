@@ -80,12 +80,14 @@ function getConfig(config: RateLimiterConfig): RateLimiterConfig {
         "getOpenPorts": { group: "default", points: 1 },
         "openPort": { group: "default", points: 1 },
         "closePort": { group: "default", points: 1 },
+        "getPortTCPAccess": { group: "default", points: 1 },
         "getUserStorageResource": { group: "default", points: 1 },
         "updateUserStorageResource": { group: "default", points: 1 },
         "getEnvVars": { group: "default", points: 1 },
         "getAllEnvVars": { group: "default", points: 1 },
         "setEnvVar": { group: "default", points: 1 },
         "deleteEnvVar": { group: "default", points: 1 },
+        "getSSHPublicKeys": { group: "default", points: 1 },
         "setProjectEnvironmentVariable": { group: "default", points: 1 },
         "getProjectEnvironmentVariables": { group: "default", points: 1 },
         "deleteProjectEnvironmentVariable": { group: "default", points: 1 },
@@ -145,6 +145,22 @@ export interface ConfigSerialized {
 
     vsxRegistryUrl: string;
 
+    /**
+     * Public key of the installation's SSH certificate authority. Workspaces accept
+     * OpenSSH user certificates signed by this CA with the workspace ID as principal.
+     */
+    sshCAPublicKey?: string;
+
+    /**
+     * Exposure of workspace ports over raw TCP through ws-proxy. Clients get time-limited
+     * access tokens through the ports API. Disabled if not present.
+     */
+    workspacePortTCPProxy?: {
+        /** port is the port ws-proxy accepts TCP connections on */
+        port: number;
+        tokenLifetimeSeconds: number;
+    };
+
     /**
      * Payment related options
      */
@@ -44,6 +44,7 @@ import { StorageClient } from './storage/storage-client';
 import { ImageBuilderClientConfig, ImageBuilderClientProvider, CachingImageBuilderClientProvider, ImageBuilderClientCallMetrics } from '@gitpod/image-builder/lib';
 import { ImageSourceProvider } from './workspace/image-source-provider';
 import { WorkspaceGarbageCollector } from './workspace/garbage-collector';
+import { EvictedWorkspaceRestarter } from './workspace/evicted-workspace-restarter';
 import { TokenGarbageCollector } from './user/token-garbage-collector';
 import { WorkspaceDownloadService } from './workspace/workspace-download-service';
 import { WebsocketConnectionManager } from './websocket/websocket-connection-manager';
@@ -185,6 +186,7 @@ export const productionContainerModule = new ContainerModule((bind, unbind, isBo
     bind(ConsensusLeaderQorum).toSelf().inSingletonScope();
 
     bind(WorkspaceGarbageCollector).toSelf().inSingletonScope();
+    bind(EvictedWorkspaceRestarter).toSelf().inSingletonScope();
     bind(WorkspaceDownloadService).toSelf().inSingletonScope();
 
     bind(OneTimeSecretServer).toSelf().inSingletonScope();
@@ -27,6 +27,7 @@ import { URL } from 'url';
 import { ConsensusLeaderQorum } from './consensus/consensus-leader-quorum';
 import { RabbitMQConsensusLeaderMessenger } from './consensus/rabbitmq-consensus-leader-messenger';
 import { WorkspaceGarbageCollector } from './workspace/garbage-collector';
+import { EvictedWorkspaceRestarter } from './workspace/evicted-workspace-restarter';
 import { WorkspaceDownloadService } from './workspace/workspace-download-service';
 import { MonitoringEndpointsApp } from './monitoring-endpoints';
 import { WebsocketConnectionManager } from './websocket/websocket-connection-manager';
@@ -69,6 +70,7 @@ export class Server<C extends GitpodClient, S extends GitpodServer> {
     @inject(RabbitMQConsensusLeaderMessenger) protected readonly consensusMessenger: RabbitMQConsensusLeaderMessenger;
     @inject(ConsensusLeaderQorum) protected readonly qorum: ConsensusLeaderQorum;
     @inject(WorkspaceGarbageCollector) protected readonly workspaceGC: WorkspaceGarbageCollector;
+    @inject(EvictedWorkspaceRestarter) protected readonly evictedWorkspaceRestarter: EvictedWorkspaceRestarter;
     @inject(DeletedEntryGC) protected readonly deletedEntryGC: DeletedEntryGC;
     @inject(OneTimeSecretServer) protected readonly oneTimeSecretServer: OneTimeSecretServer;
 
@@ -254,6 +256,9 @@ export class Server<C extends GitpodClient, S extends GitpodServer> {
         // Start workspace garbage collector
         this.workspaceGC.start().catch((err) => log.error("wsgc: error during startup", err));
 
+        // Restart workspaces which were evicted from their node
+        this.disposables.push(this.evictedWorkspaceRestarter.start());
+
         // Start deleted entry GC
         this.deletedEntryGC.start();
 
@@ -247,6 +247,54 @@ export class UserController {
             });
             res.sendStatus(200);
         });
+        router.get("/auth/workspace-port/:instanceID", async (req: express.Request, res: express.Response, next: express.NextFunction) => {
+            // ws-proxy sends users here who try to access a workspace port with 'authenticated' visibility without the port auth cookie
+            const returnTo = this.getSafeWorkspaceReturnToParam(req);
+            if (!req.isAuthenticated() || !User.is(req.user)) {
+                // come back here once the user is logged in
+                const self = this.config.hostUrl.asWorkspacePortAuth(req.params.instanceID, returnTo);
+                res.redirect(this.config.hostUrl.withApi({ pathname: '/login', search: `returnTo=${encodeURIComponent(self.toString())}` }).toString());
+                return;
+            }
+
+            const user = req.user as User;
+            if (user.blocked) {
+                res.sendStatus(403);
+                log.warn("blocked user attempted to fetch workspace port cookie", { instanceId: req.params.instanceID, userId: user.id });
+                return;
+            }
+
+            const instanceID = req.params.instanceID;
+            const instance = await this.workspaceDB.findInstanceById(instanceID);
+            if (!instance) {
+                res.sendStatus(404);
+                log.warn("attempted to fetch workspace port cookie for non-existent workspace instance", { instanceId: instanceID, userId: user.id });
+                return;
+            }
+            const ownerToken = instance.status.ownerToken;
+            if (!ownerToken) {
+                res.sendStatus(404);
+                return;
+            }
+
+            let cookiePrefix: string = this.config.hostUrl.url.host;
+            [" ", "-", "."].forEach(c => cookiePrefix = cookiePrefix.split(c).join("_"));
+            // ws-proxy derives the same value from the owner token, without the owner token being revealed to other users
+            const portAuthToken = crypto.createHmac("sha256", ownerToken).update(instanceID).digest("hex");
+            res.cookie(`_${cookiePrefix}_ws_${instanceID}_port_auth_`, portAuthToken, {
+                path: "/",
+                httpOnly: true,
+                secure: true,
+                maxAge: 1000 * 60 * 60 * 24 * 1,    // 1 day
+                sameSite: "lax",
+                domain: `.${this.config.hostUrl.url.host}`
+            });
+            if (returnTo) {
+                res.redirect(returnTo);
+                return;
+            }
+            res.sendStatus(200);
+        });
         if (this.config.enableLocalApp) {
             router.get("/auth/local-app", async (req: express.Request, res: express.Response, next: express.NextFunction) => {
                 if (!req.isAuthenticated() || !User.is(req.user)) {
@@ -614,6 +662,26 @@ export class UserController {
         return url.toLowerCase().startsWith(prefixUrl.toLowerCase());
     }
 
+    /**
+     * returns the returnTo URL if it points to a workspace, i.e. a subdomain of the Gitpod host
+     */
+    protected getSafeWorkspaceReturnToParam(req: express.Request): string | undefined {
+        const returnTo = req.query.returnTo;
+        if (typeof returnTo !== 'string') {
+            return;
+        }
+        try {
+            const url = new URL(returnTo);
+            if (url.protocol === 'https:' && url.hostname.toLowerCase().endsWith(`.${this.config.hostUrl.url.hostname.toLowerCase()}`)) {
+                return returnTo;
+            }
+        } catch (err) {
+            // invalid URL
+        }
+        log.debug({ sessionId: req.sessionID }, "The workspace redirect URL does not match", { query: req.query });
+        return;
+    }
+
     protected getSafeReturnToParam(req: express.Request) {
         // @ts-ignore Type 'ParsedQs' is not assignable
         const returnToURL: string | undefined = req.query.redirect || req.query.returnTo;
@@ -0,0 +1,90 @@
+/**
+ * Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+ * Licensed under the GNU Affero General Public License (AGPL).
+ * See License-AGPL.txt in the project root for license information.
+ */
+
+import "reflect-metadata";
+
+import { suite, test } from "@testdeck/mocha";
+import * as chai from 'chai';
+import { User, Workspace, WorkspaceInstance } from "@gitpod/gitpod-protocol";
+import { TraceContext } from "@gitpod/gitpod-protocol/lib/util/tracing";
+import { EvictedWorkspaceRestarter, shouldRestartEvictedWorkspace } from "./evicted-workspace-restarter";
+const expect = chai.expect;
+
+@suite
+class TestEvictedWorkspaceRestarter {
+    protected instance(id: string, phase: "running" | "stopped", conditions: WorkspaceInstance["status"]["conditions"]): WorkspaceInstance {
+        return <WorkspaceInstance>{ id, workspaceId: "ws", status: { phase, conditions } };
+    }
+
+    protected workspace: Workspace = <Workspace>{ id: "ws", ownerId: "user", type: "regular" };
+    protected user: User = <User>{ id: "user" };
+
+    @test
+    public testRestartEvicted() {
+        const evicted = this.instance("i1", "stopped", { evicted: "The node was low on resource: [DiskPressure]." });
+        expect(shouldRestartEvictedWorkspace(evicted, this.workspace, evicted, this.user)).to.be.true;
+    }
+
+    @test
+    public testDontRestartRegularlyStopped() {
+        const stopped = this.instance("i1", "stopped", {});
+        expect(shouldRestartEvictedWorkspace(stopped, this.workspace, stopped, this.user)).to.be.false;
+    }
+
+    @test
+    public testDontRestartWhileStopping() {
+        const stopping = this.instance("i1", "running", { evicted: "preempted" });
+        expect(shouldRestartEvictedWorkspace(stopping, this.workspace, stopping, this.user)).to.be.false;
+    }
+
+    @test
+    public testDontRestartAfterFailedBackup() {
+        const evicted = this.instance("i1", "stopped", { evicted: "preempted", failed: "last backup failed: timeout." });
+        expect(shouldRestartEvictedWorkspace(evicted, this.workspace, evicted, this.user)).to.be.false;
+    }
+
+    @test
+    public testDontRestartTwice() {
+        const evicted = this.instance("i1", "stopped", { evicted: "preempted" });
+        const restarted = this.instance("i2", "running", {});
+        expect(shouldRestartEvictedWorkspace(evicted, this.workspace, restarted, this.user)).to.be.false;
+    }
+
+    @test
+    public testDontRestartForBlockedUser() {
+        const evicted = this.instance("i1", "stopped", { evicted: "preempted" });
+        expect(shouldRestartEvictedWorkspace(evicted, this.workspace, evicted, <User>{ id: "user", blocked: true })).to.be.false;
+    }
+
+    @test
+    public testDontRestartDeletedWorkspace() {
+        const evicted = this.instance("i1", "stopped", { evicted: "preempted" });
+        expect(shouldRestartEvictedWorkspace(evicted, <Workspace>{ ...this.workspace, softDeleted: "user" }, evicted, this.user)).to.be.false;
+        expect(shouldRestartEvictedWorkspace(evicted, undefined, evicted, this.user)).to.be.false;
+    }
+
+    @test
+    public async testRestartOnceForRepeatedUpdates() {
+        const evicted = this.instance("i1", "stopped", { evicted: "preempted" });
+        let starts = 0;
+        const restarter = new class extends EvictedWorkspaceRestarter {
+            public restartIfEvicted(ctx: TraceContext, instance: WorkspaceInstance) {
+                return super.restartIfEvicted(ctx, instance);
+            }
+        }();
+        Object.assign(restarter, {
+            leaderQuorum: { areWeLeader: async () => true },
+            workspaceDB: { trace: () => ({ findById: async () => this.workspace, findCurrentInstance: async () => evicted }) },
+            userDB: { findUserById: async () => this.user, getEnvVars: async () => [] },
+            workspaceStarter: { startWorkspace: async () => { starts++; await new Promise(resolve => setTimeout(resolve, 10)); } },
+        });
+
+        await Promise.all([restarter.restartIfEvicted({}, evicted), restarter.restartIfEvicted({}, evicted)]);
+        expect(starts).to.equal(1);
+    }
+}
+
+module.exports = new TestEvictedWorkspaceRestarter()
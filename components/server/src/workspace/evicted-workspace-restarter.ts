@@ -0,0 +1,102 @@
+/**
+ * Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+ * Licensed under the GNU Affero General Public License (AGPL).
+ * See License-AGPL.txt in the project root for license information.
+ */
+
+import { injectable, inject } from "inversify";
+import { Disposable, User, Workspace, WorkspaceInstance } from "@gitpod/gitpod-protocol";
+import { log } from "@gitpod/gitpod-protocol/lib/util/logging";
+import { TraceContext } from "@gitpod/gitpod-protocol/lib/util/tracing";
+import { DBWithTracing, TracedWorkspaceDB, UserDB, WorkspaceDB } from "@gitpod/gitpod-db/lib";
+import { ConsensusLeaderQorum } from "../consensus/consensus-leader-quorum";
+import { ProjectsService } from "../projects/projects-service";
+import { MessageBusIntegration } from "./messagebus-integration";
+import { WorkspaceStarter } from "./workspace-starter";
+
+/**
+ * The EvictedWorkspaceRestarter starts workspaces anew which stopped because Kubernetes evicted or preempted them
+ * from their node. ws-manager backs up their content as they stop, hence the restarted workspace continues where
+ * the evicted one left off. Only the leader of the server quorum restarts workspaces, so that we start them once.
+ */
+@injectable()
+export class EvictedWorkspaceRestarter {
+    @inject(ConsensusLeaderQorum) protected readonly leaderQuorum: ConsensusLeaderQorum;
+    @inject(MessageBusIntegration) protected readonly messageBusIntegration: MessageBusIntegration;
+    @inject(TracedWorkspaceDB) protected readonly workspaceDB: DBWithTracing<WorkspaceDB>;
+    @inject(UserDB) protected readonly userDB: UserDB;
+    @inject(ProjectsService) protected readonly projectsService: ProjectsService;
+    @inject(WorkspaceStarter) protected readonly workspaceStarter: WorkspaceStarter;
+
+    // ws-manager may report an evicted instance as stopped more than once. Until the restart has stored the new
+    // instance, those updates would all pass the current instance check, hence we restart each instance only once at a time.
+    protected readonly restarting = new Set<string>();
+
+    public start(): Disposable {
+        return this.messageBusIntegration.listenForWorkspaceInstanceUpdates(undefined, (ctx, instance) => {
+            this.restartIfEvicted(ctx, instance).catch(err => log.error({ instanceId: instance.id, workspaceId: instance.workspaceId }, "cannot restart evicted workspace", err));
+        });
+    }
+
+    protected async restartIfEvicted(ctx: TraceContext, instance: WorkspaceInstance) {
+        if (!isStoppedByEviction(instance)) {
+            return;
+        }
+        if (!await this.leaderQuorum.areWeLeader()) {
+            return;
+        }
+        if (this.restarting.has(instance.id)) {
+            return;
+        }
+        this.restarting.add(instance.id);
+        try {
+            await this.restart(ctx, instance);
+        } finally {
+            this.restarting.delete(instance.id);
+        }
+    }
+
+    protected async restart(ctx: TraceContext, instance: WorkspaceInstance) {
+        const [workspace, currentInstance] = await Promise.all([
+            this.workspaceDB.trace(ctx).findById(instance.workspaceId),
+            this.workspaceDB.trace(ctx).findCurrentInstance(instance.workspaceId),
+        ]);
+        const user = workspace && await this.userDB.findUserById(workspace.ownerId);
+        if (!shouldRestartEvictedWorkspace(instance, workspace, currentInstance, user)) {
+            return;
+        }
+
+        const logCtx = { userId: user!.id, workspaceId: workspace!.id, instanceId: instance.id };
+        log.info(logCtx, "restarting evicted workspace", { reason: instance.status.conditions.evicted });
+        const [userEnvVars, projectEnvVars] = await Promise.all([
+            this.userDB.getEnvVars(user!.id),
+            workspace!.projectId ? this.projectsService.getProjectEnvironmentVariables(workspace!.projectId) : Promise.resolve([]),
+        ]);
+        await this.workspaceStarter.startWorkspace(ctx, workspace!, user!, userEnvVars, projectEnvVars.filter(v => !v.censored));
+    }
+}
+
+/**
+ * isStoppedByEviction returns true if an instance stopped because Kubernetes evicted or preempted it from its node
+ */
+export function isStoppedByEviction(instance: WorkspaceInstance): boolean {
+    return instance.status.phase === "stopped" && !!instance.status.conditions.evicted;
+}
+
+/**
+ * shouldRestartEvictedWorkspace returns true if we should start a workspace anew whose instance was evicted.
+ * We don't restart workspaces which someone started again already, which are gone, or whose owner is blocked.
+ * Neither do we restart workspaces whose final backup failed, as the restarted workspace would miss the latest changes.
+ */
+export function shouldRestartEvictedWorkspace(instance: WorkspaceInstance, workspace: Workspace | undefined, currentInstance: WorkspaceInstance | undefined, user: User | undefined): boolean {
+    if (!isStoppedByEviction(instance) || !!instance.status.conditions.failed) {
+        return false;
+    }
+    if (!workspace || workspace.type !== "regular" || workspace.deleted || !!workspace.softDeleted) {
+        return false;
+    }
+    if (!currentInstance || currentInstance.id !== instance.id) {
+        return false;
+    }
+    return !!user && !user.blocked;
+}
@@ -6,7 +6,7 @@
 
 import { DownloadUrlRequest, DownloadUrlResponse, UploadUrlRequest, UploadUrlResponse } from '@gitpod/content-service/lib/blobs_pb';
 import { AppInstallationDB, UserDB, UserMessageViewsDB, WorkspaceDB, DBWithTracing, TracedWorkspaceDB, DBGitpodToken, DBUser, UserStorageResourcesDB, TeamDB, InstallationAdminDB, ProjectDB } from '@gitpod/gitpod-db/lib';
-import { AuthProviderEntry, AuthProviderInfo, CommitContext, Configuration, CreateWorkspaceMode, DisposableCollection, GetWorkspaceTimeoutResult, GitpodClient as GitpodApiClient, GitpodServer, GitpodToken, GitpodTokenType, InstallPluginsParams, PermissionName, PortVisibility, PrebuiltWorkspace, PrebuiltWorkspaceContext, PreparePluginUploadParams, ResolvedPlugins, ResolvePluginsParams, SetWorkspaceTimeoutResult, StartPrebuildContext, StartWorkspaceResult, Terms, Token, UninstallPluginParams, User, UserEnvVar, UserEnvVarValue, UserInfo, WhitelistedRepository, Workspace, WorkspaceContext, WorkspaceCreationResult, WorkspaceImageBuild, WorkspaceInfo, WorkspaceInstance, WorkspaceInstancePort, WorkspaceInstanceUser, WorkspaceTimeoutDuration, GuessGitTokenScopesParams, GuessedGitTokenScopes, Team, TeamMemberInfo, TeamMembershipInvite, CreateProjectParams, Project, ProviderRepository, TeamMemberRole, WithDefaultConfig, FindPrebuildsParams, PrebuildWithStatus, StartPrebuildResult, ClientHeaderFields, Permission, SnapshotContext } from '@gitpod/gitpod-protocol';
+import { AuthProviderEntry, AuthProviderInfo, CommitContext, Configuration, CreateWorkspaceMode, DisposableCollection, GetWorkspaceTimeoutResult, GitpodClient as GitpodApiClient, GitpodServer, GitpodToken, GitpodTokenType, InstallPluginsParams, PermissionName, PortVisibility, PrebuiltWorkspace, PrebuiltWorkspaceContext, PreparePluginUploadParams, ResolvedPlugins, ResolvePluginsParams, SetWorkspaceTimeoutResult, StartPrebuildContext, StartWorkspaceResult, Terms, Token, UninstallPluginParams, User, UserEnvVar, UserEnvVarValue, UserInfo, UserSSHPublicKey, WhitelistedRepository, Workspace, WorkspaceContext, WorkspaceCreationResult, WorkspaceImageBuild, WorkspaceInfo, WorkspaceInstance, WorkspaceInstancePort, WorkspaceInstanceUser, PortTCPAccess, WorkspaceTimeoutDuration, GuessGitTokenScopesParams, GuessedGitTokenScopes, Team, TeamMemberInfo, TeamMembershipInvite, CreateProjectParams, Project, ProviderRepository, TeamMemberRole, WithDefaultConfig, FindPrebuildsParams, PrebuildWithStatus, StartPrebuildResult, ClientHeaderFields, Permission, SnapshotContext } from '@gitpod/gitpod-protocol';
 import { AccountStatement } from "@gitpod/gitpod-protocol/lib/accounting-protocol";
 import { AdminBlockUserRequest, AdminGetListRequest, AdminGetListResult, AdminGetWorkspacesRequest, AdminModifyPermanentWorkspaceFeatureFlagRequest, AdminModifyRoleOrPermissionRequest, WorkspaceAndInstance } from '@gitpod/gitpod-protocol/lib/admin-protocol';
 import { GetLicenseInfoResult, LicenseFeature, LicenseValidationResult } from '@gitpod/gitpod-protocol/lib/license-protocol';
@@ -20,7 +20,7 @@ import { InterfaceWithTraceContext, TraceContext } from '@gitpod/gitpod-protocol
 import { IdentifyMessage, RemoteIdentifyMessage, RemotePageMessage, RemoteTrackMessage } from '@gitpod/gitpod-protocol/lib/analytics';
 import { ImageBuilderClientProvider, LogsRequest } from '@gitpod/image-builder/lib';
 import { WorkspaceManagerClientProvider } from '@gitpod/ws-manager/lib/client-provider';
-import { ControlPortRequest, DescribeWorkspaceRequest, MarkActiveRequest, PortSpec, PortVisibility as ProtoPortVisibility, StopWorkspacePolicy, StopWorkspaceRequest } from '@gitpod/ws-manager/lib/core_pb';
+import { ControlPortRequest, DescribeWorkspaceRequest, MarkActiveRequest, PortSpec, PortVisibility as ProtoPortVisibility, StopReason, StopWorkspacePolicy, StopWorkspaceRequest } from '@gitpod/ws-manager/lib/core_pb';
 import * as crypto from 'crypto';
 import { inject, injectable } from 'inversify';
 import { URL } from 'url';
@@ -44,7 +44,7 @@ import { ContextParser } from './context-parser-service';
 import { GitTokenScopeGuesser } from "./git-token-scope-guesser";
 import { WorkspaceDeletionService } from './workspace-deletion-service';
 import { WorkspaceFactory } from './workspace-factory';
-import { WorkspaceStarter } from './workspace-starter';
+import { portHeadersToProto, WorkspaceStarter } from './workspace-starter';
 import { HeadlessLogUrls } from "@gitpod/gitpod-protocol/lib/headless-workspace-log";
 import { HeadlessLogService, HeadlessLogEndpoint } from "./headless-log-service";
 import { InvalidGitpodYMLError } from "./config-provider";
@@ -573,7 +573,7 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                 await this.guardAccess({ kind: "workspaceInstance", subject: instance, workspace }, "update");
             }
         }
-        await this.internalStopWorkspaceInstance(ctx, instance.id, instance.region, policy);
+        await this.internalStopWorkspaceInstance(ctx, instance.id, instance.region, policy, admin ? StopReason.STOP_REASON_ADMIN_REQUEST : undefined);
     }
 
     protected async guardAdminAccess(method: string, params: any, requiredPermission: PermissionName) {
@@ -585,10 +585,11 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
         log.info({ userId: this.user?.id }, "admin access", { authorised: true, method, params });
     }
 
-    protected async internalStopWorkspaceInstance(ctx: TraceContext, instanceId: string, instanceRegion: string, policy?: StopWorkspacePolicy): Promise<void> {
+    protected async internalStopWorkspaceInstance(ctx: TraceContext, instanceId: string, instanceRegion: string, policy?: StopWorkspacePolicy, reason?: StopReason): Promise<void> {
         const req = new StopWorkspaceRequest();
         req.setId(instanceId);
         req.setPolicy(policy || StopWorkspacePolicy.NORMALLY);
+        req.setReason(reason || StopReason.STOP_REASON_USER_REQUEST);
 
         const client = await this.workspaceManagerClientProvider.get(instanceRegion);
         await client.stopWorkspace(ctx, req);
@@ -1155,6 +1156,8 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
         const spec = new PortSpec();
         spec.setPort(port.port);
         spec.setVisibility(this.portVisibilityToProto(port.visibility))
+        const portConfig = (workspace.config.ports || []).find(p => p.port === port.port);
+        spec.setHeaders(portHeadersToProto(portConfig?.headers));
         req.setSpec(spec);
         req.setExpose(true);
 
@@ -1169,6 +1172,8 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                 return 'private';
             case ProtoPortVisibility.PORT_VISIBILITY_PUBLIC:
                 return 'public';
+            case ProtoPortVisibility.PORT_VISIBILITY_AUTHENTICATED:
+                return 'authenticated';
         }
     }
 
@@ -1179,6 +1184,8 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                 return ProtoPortVisibility.PORT_VISIBILITY_PRIVATE;
             case 'public':
                 return ProtoPortVisibility.PORT_VISIBILITY_PUBLIC;
+            case 'authenticated':
+                return ProtoPortVisibility.PORT_VISIBILITY_AUTHENTICATED;
         }
     }
 
@@ -1207,6 +1214,43 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
         await client.controlPort(ctx, req);
     }
 
+    public async getPortTCPAccess(ctx: TraceContext, workspaceId: string, port: number): Promise<PortTCPAccess> {
+        traceAPIParams(ctx, { workspaceId, port });
+        traceWI(ctx, { workspaceId });
+
+        this.checkAndBlockUser("getPortTCPAccess");
+
+        const tcpProxy = this.config.workspacePortTCPProxy;
+        if (!tcpProxy) {
+            throw new ResponseError(ErrorCodes.NOT_FOUND, "TCP port exposure is not enabled in this installation");
+        }
+
+        const { workspace, instance } = await this.internGetCurrentWorkspaceInstance(ctx, workspaceId);
+        if (!instance || instance.status.phase !== 'running') {
+            throw new ResponseError(ErrorCodes.NOT_FOUND, `Workspace ${workspaceId} has no running instance`);
+        }
+        traceWI(ctx, { instanceId: instance.id });
+        await this.guardAccess({ kind: "workspaceInstance", subject: instance, workspace }, "update");
+
+        const exposedPort = (instance.status.exposedPorts || []).find(p => p.port === port);
+        if (!exposedPort?.url) {
+            throw new ResponseError(ErrorCodes.NOT_FOUND, `Port ${port} is not exposed`);
+        }
+        const ownerToken = instance.status.ownerToken;
+        if (!ownerToken) {
+            throw new Error("owner token not found");
+        }
+
+        // ws-proxy derives the same token from the owner token, see components/ws-proxy/pkg/tcpproxy/token.go
+        const expiry = Math.floor(Date.now() / 1000) + tcpProxy.tokenLifetimeSeconds;
+        const mac = crypto.createHmac("sha256", ownerToken).update(`${instance.id}:${port}:${expiry}`).digest("hex");
+        return {
+            address: `${new URL(exposedPort.url).hostname}:${tcpProxy.port}`,
+            token: `${expiry}.${mac}`,
+            expiresAt: new Date(expiry * 1000).toISOString(),
+        };
+    }
+
     async watchWorkspaceImageBuildLogs(ctx: TraceContext, workspaceId: string): Promise<void> {
         traceAPIParams(ctx, { workspaceId });
         traceWI(ctx, { workspaceId });
@@ -1555,6 +1599,12 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
         await this.userDB.deleteEnvVar(envvar);
     }
 
+    async getSSHPublicKeys(ctx: TraceContext): Promise<UserSSHPublicKey[]> {
+        // Note: this operation is per-user only, hence needs no resource guard
+        const user = this.checkUser("getSSHPublicKeys");
+        return user.additionalData?.sshPublicKeys || [];
+    }
+
     async setProjectEnvironmentVariable(ctx: TraceContext, projectId: string, name: string, value: string, censored: boolean): Promise<void> {
         traceAPIParams(ctx, { projectId, name }); // value may contain secrets
         const user = this.checkAndBlockUser("setProjectEnvironmentVariable");
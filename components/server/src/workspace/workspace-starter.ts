@@ -4,17 +4,17 @@
  * See License-AGPL.txt in the project root for license information.
  */
 
-import { CloneTargetMode, FileDownloadInitializer, GitAuthMethod, GitConfig, GitInitializer, PrebuildInitializer, SnapshotInitializer, WorkspaceInitializer } from "@gitpod/content-service/lib";
+import { CloneFilter, CloneStrategy, CloneTargetMode, FileDownloadInitializer, GitAuthMethod, GitConfig, GitInitializer, LFSMode, PrebuildInitializer, SnapshotInitializer, WorkspaceInitializer } from "@gitpod/content-service/lib";
 import { CompositeInitializer, FromBackupInitializer } from "@gitpod/content-service/lib/initializer_pb";
 import { DBUser, DBWithTracing, ProjectDB, TracedUserDB, TracedWorkspaceDB, UserDB, WorkspaceDB } from '@gitpod/gitpod-db/lib';
-import { CommitContext, Disposable, GitpodToken, GitpodTokenType, IssueContext, NamedWorkspaceFeatureFlag, PullRequestContext, RefType, SnapshotContext, StartWorkspaceResult, User, UserEnvVar, UserEnvVarValue, WithEnvvarsContext, WithPrebuild, Workspace, WorkspaceContext, WorkspaceImageSource, WorkspaceImageSourceDocker, WorkspaceImageSourceReference, WorkspaceInstance, WorkspaceInstanceConfiguration, WorkspaceInstanceStatus, WorkspaceProbeContext, Permission, HeadlessWorkspaceEvent, HeadlessWorkspaceEventType, DisposableCollection, AdditionalContentContext, ImageConfigFile, ProjectEnvVar, ImageBuildLogInfo } from "@gitpod/gitpod-protocol";
+import { CommitContext, Disposable, GitpodToken, GitpodTokenType, IssueContext, NamedWorkspaceFeatureFlag, PullRequestContext, RefType, SnapshotContext, StartWorkspaceResult, User, UserEnvVar, UserEnvVarValue, WithEnvvarsContext, WithPrebuild, Workspace, WorkspaceContext, WorkspaceImageSource, WorkspaceImageSourceDocker, WorkspaceImageSourceReference, WorkspaceInstance, WorkspaceInstanceConfiguration, WorkspaceInstanceStatus, WorkspaceProbeContext, Permission, HeadlessWorkspaceEvent, HeadlessWorkspaceEventType, DisposableCollection, AdditionalContentContext, ImageConfigFile, ProjectEnvVar, ImageBuildLogInfo, PortHeadersConfig } from "@gitpod/gitpod-protocol";
 import { IAnalyticsWriter } from '@gitpod/gitpod-protocol/lib/analytics';
 import { log } from '@gitpod/gitpod-protocol/lib/util/logging';
 import { TraceContext } from "@gitpod/gitpod-protocol/lib/util/tracing";
 import { BuildRegistryAuth, BuildRegistryAuthSelective, BuildRegistryAuthTotal, BuildRequest, BuildResponse, BuildSource, BuildSourceDockerfile, BuildSourceReference, BuildStatus, ImageBuilderClientProvider, ResolveBaseImageRequest, ResolveWorkspaceImageRequest } from "@gitpod/image-builder/lib";
 import { StartWorkspaceSpec, WorkspaceFeatureFlag, StartWorkspaceResponse, IDEImage } from "@gitpod/ws-manager/lib";
 import { WorkspaceManagerClientProvider } from "@gitpod/ws-manager/lib/client-provider";
-import { AdmissionLevel, EnvironmentVariable, GitSpec, PortSpec, PortVisibility, StartWorkspaceRequest, WorkspaceMetadata, WorkspaceType } from "@gitpod/ws-manager/lib/core_pb";
+import { AdmissionLevel, EnvironmentVariable, GitSpec, PortHeaders, PortSpec, PortVisibility, StartWorkspaceRequest, WorkspaceMetadata, WorkspaceType } from "@gitpod/ws-manager/lib/core_pb";
 import * as crypto from 'crypto';
 import { inject, injectable } from "inversify";
 import { v4 as uuidv4 } from 'uuid';
@@ -665,27 +665,32 @@ export class WorkspaceStarter {
         const context = workspace.context;
 
         let allEnvVars: EnvVarWithValue[] = [];
+        // we copy the envvars to a stable format so that things don't break when someone changes the
+        // EnvVarWithValue shape. The JSON.stringify(envvars) will be consumed by supervisor and we
+        // need to make sure we're speaking the same language. The scope tells supervisor where a
+        // variable comes from, so that it can reload them individually.
+        const stableEnvvars: { name: string, value: string, scope: "user" | "project" | "workspace" }[] = [];
         if (userEnvVars.length > 0) {
+            let vars: EnvVarWithValue[];
             if (CommitContext.is(context)) {
                 // this is a commit context, thus we can filter the env vars
-                allEnvVars = allEnvVars.concat(UserEnvVar.filter(userEnvVars, context.repository.owner, context.repository.name));
+                vars = UserEnvVar.filter(userEnvVars, context.repository.owner, context.repository.name);
             } else {
-                allEnvVars = allEnvVars.concat(userEnvVars);
+                vars = userEnvVars;
             }
+            allEnvVars = allEnvVars.concat(vars);
+            vars.forEach(e => stableEnvvars.push({ name: e.name, value: e.value, scope: "user" }));
         }
         if (projectEnvVars.length > 0) {
             const projectEnvVarsWithValues = await this.projectDB.getProjectEnvironmentVariableValues(projectEnvVars);
             allEnvVars = allEnvVars.concat(projectEnvVarsWithValues);
+            projectEnvVarsWithValues.forEach(e => stableEnvvars.push({ name: e.name, value: e.value, scope: "project" }));
         }
         if (WithEnvvarsContext.is(context)) {
             allEnvVars = allEnvVars.concat(context.envvars);
+            context.envvars.forEach(e => stableEnvvars.push({ name: e.name, value: e.value, scope: "workspace" }));
         }
 
-        // we copy the envvars to a stable format so that things don't break when someone changes the
-        // EnvVarWithValue shape. The JSON.stringify(envvars) will be consumed by supervisor and we
-        // need to make sure we're speaking the same language.
-        const stableEnvvars = allEnvVars.map(e => { return { name: e.name, value: e.value }});
-
         // we ship the user-specific env vars as OTS because they might contain secrets
         const envvarOTSExpirationTime = new Date();
         envvarOTSExpirationTime.setMinutes(envvarOTSExpirationTime.getMinutes() + 30);
@@ -762,6 +767,13 @@ export class WorkspaceStarter {
         dotfileEnv.setValue(user.additionalData?.dotfileRepo || "");
         envvars.push(dotfileEnv);
 
+        if (this.config.sshCAPublicKey) {
+            const sshCAEnv = new EnvironmentVariable();
+            sshCAEnv.setName("SUPERVISOR_SSH_CA_PUBLIC_KEY");
+            sshCAEnv.setValue(this.config.sshCAPublicKey);
+            envvars.push(sshCAEnv);
+        }
+
         const createGitpodTokenPromise = (async () => {
             const scopes = this.createDefaultGitpodAPITokenScopes(workspace, instance);
             const token = crypto.randomBytes(30).toString('hex');
@@ -806,7 +818,17 @@ export class WorkspaceStarter {
 
             const spec = new PortSpec();
             spec.setPort(p.port);
-            spec.setVisibility(p.visibility == 'public' ? PortVisibility.PORT_VISIBILITY_PUBLIC : PortVisibility.PORT_VISIBILITY_PRIVATE);
+            switch (p.visibility) {
+                case 'public':
+                    spec.setVisibility(PortVisibility.PORT_VISIBILITY_PUBLIC);
+                    break;
+                case 'authenticated':
+                    spec.setVisibility(PortVisibility.PORT_VISIBILITY_AUTHENTICATED);
+                    break;
+                default:
+                    spec.setVisibility(PortVisibility.PORT_VISIBILITY_PRIVATE);
+            }
+            spec.setHeaders(portHeadersToProto(p.headers));
             return spec;
         }).filter(spec => !!spec) as PortSpec[];
 
@@ -889,8 +911,10 @@ export class WorkspaceStarter {
             "function:accessCodeSyncStorage",
             "function:guessGitTokenScopes",
             "function:getEnvVars",
+            "function:getAllEnvVars",
             "function:setEnvVar",
             "function:deleteEnvVar",
+            "function:getSSHPublicKeys",
             "function:trackEvent",
 
             "resource:" + ScopedResourceGuard.marshalResourceScope({ kind: "workspace", subjectID: workspace.id, operations: ["get", "update"] }),
@@ -1080,6 +1104,18 @@ export class WorkspaceStarter {
         if (!!upstreamRemoteURI) {
             result.setUpstreamRemoteUri(upstreamRemoteURI);
         }
+        const cloneStrategy = this.createCloneStrategy(workspace);
+        if (!!cloneStrategy) {
+            result.setCloneStrategy(cloneStrategy);
+        }
+        switch (workspace.config.checkout?.lfs) {
+            case 'lazy':
+                result.setLfsMode(LFSMode.LFS_LAZY);
+                break;
+            case 'skip':
+                result.setLfsMode(LFSMode.LFS_SKIP);
+                break;
+        }
 
         return {
             git: result,
@@ -1087,6 +1123,28 @@ export class WorkspaceStarter {
         };
     }
 
+    protected createCloneStrategy(workspace: Workspace): CloneStrategy | undefined {
+        const checkout = workspace.config.checkout;
+        if (!checkout || (checkout.depth === undefined && !checkout.filter && !checkout.sparse && checkout.unshallow === undefined)) {
+            // the initializer's default clone strategy applies
+            return undefined;
+        }
+
+        const result = new CloneStrategy();
+        result.setDepth(checkout.depth === undefined ? 1 : Math.max(0, Math.floor(checkout.depth)));
+        switch (checkout.filter) {
+            case 'blobless':
+                result.setFilter(CloneFilter.BLOBLESS);
+                break;
+            case 'treeless':
+                result.setFilter(CloneFilter.TREELESS);
+                break;
+        }
+        result.setSparseCheckoutPatternsList(checkout.sparse || []);
+        result.setUnshallow(checkout.unshallow !== false);
+        return result;
+    }
+
     protected getCheckoutLocation(workspace: Workspace) {
         return workspace.config.checkoutLocation || CommitContext.is(workspace.context) && workspace.context.repository.name || '.';
     }
@@ -1120,3 +1178,16 @@ export class WorkspaceStarter {
     }
 
 }
+
+export function portHeadersToProto(config: PortHeadersConfig | undefined): PortHeaders | undefined {
+    if (!config) {
+        return undefined;
+    }
+    const headers = new PortHeaders();
+    headers.setCorsAllowedOriginsList(config.corsAllowedOrigins || []);
+    headers.setXFrameOptions(config.xFrameOptions || "");
+    for (const [name, value] of Object.entries(config.custom || {})) {
+        headers.getCustomMap().set(name, value);
+    }
+    return headers;
+}
@@ -26,6 +26,14 @@ type ControlServiceClient interface {
 	ExposePort(ctx context.Context, in *ExposePortRequest, opts ...grpc.CallOption) (*ExposePortResponse, error)
 	// CreateSSHKeyPair Create a pair of SSH Keys and put them in ~/.ssh/authorized_keys, this will only be generated once in the entire workspace lifecycle
 	CreateSSHKeyPair(ctx context.Context, in *CreateSSHKeyPairRequest, opts ...grpc.CallOption) (*CreateSSHKeyPairResponse, error)
+	// InstallDotfiles clones the dotfiles repository again and re-runs its installation, e.g. after the dotfiles changed
+	InstallDotfiles(ctx context.Context, in *InstallDotfilesRequest, opts ...grpc.CallOption) (*InstallDotfilesResponse, error)
+	// TakeSnapshot takes a snapshot of the workspace content while the workspace keeps running.
+	// It streams the progress of the snapshot until it is available.
+	TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (ControlService_TakeSnapshotClient, error)
+	// GetSSHPublicKeys returns the SSH public keys the workspace owner registered with Gitpod, e.g. for the SSH gateway
+	// to authenticate the owner
+	GetSSHPublicKeys(ctx context.Context, in *GetSSHPublicKeysRequest, opts ...grpc.CallOption) (*GetSSHPublicKeysResponse, error)
 }
 
 type controlServiceClient struct {
@@ -54,6 +62,56 @@ func (c *controlServiceClient) CreateSSHKeyPair(ctx context.Context, in *CreateS
 	return out, nil
 }
 
+func (c *controlServiceClient) InstallDotfiles(ctx context.Context, in *InstallDotfilesRequest, opts ...grpc.CallOption) (*InstallDotfilesResponse, error) {
+	out := new(InstallDotfilesResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.ControlService/InstallDotfiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (ControlService_TakeSnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[0], "/supervisor.ControlService/TakeSnapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceTakeSnapshotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_TakeSnapshotClient interface {
+	Recv() (*TakeSnapshotResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceTakeSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceTakeSnapshotClient) Recv() (*TakeSnapshotResponse, error) {
+	m := new(TakeSnapshotResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlServiceClient) GetSSHPublicKeys(ctx context.Context, in *GetSSHPublicKeysRequest, opts ...grpc.CallOption) (*GetSSHPublicKeysResponse, error) {
+	out := new(GetSSHPublicKeysResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.ControlService/GetSSHPublicKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlServiceServer is the server API for ControlService service.
 // All implementations must embed UnimplementedControlServiceServer
 // for forward compatibility
@@ -62,6 +120,14 @@ type ControlServiceServer interface {
 	ExposePort(context.Context, *ExposePortRequest) (*ExposePortResponse, error)
 	// CreateSSHKeyPair Create a pair of SSH Keys and put them in ~/.ssh/authorized_keys, this will only be generated once in the entire workspace lifecycle
 	CreateSSHKeyPair(context.Context, *CreateSSHKeyPairRequest) (*CreateSSHKeyPairResponse, error)
+	// InstallDotfiles clones the dotfiles repository again and re-runs its installation, e.g. after the dotfiles changed
+	InstallDotfiles(context.Context, *InstallDotfilesRequest) (*InstallDotfilesResponse, error)
+	// TakeSnapshot takes a snapshot of the workspace content while the workspace keeps running.
+	// It streams the progress of the snapshot until it is available.
+	TakeSnapshot(*TakeSnapshotRequest, ControlService_TakeSnapshotServer) error
+	// GetSSHPublicKeys returns the SSH public keys the workspace owner registered with Gitpod, e.g. for the SSH gateway
+	// to authenticate the owner
+	GetSSHPublicKeys(context.Context, *GetSSHPublicKeysRequest) (*GetSSHPublicKeysResponse, error)
 	mustEmbedUnimplementedControlServiceServer()
 }
 
@@ -75,6 +141,15 @@ func (UnimplementedControlServiceServer) ExposePort(context.Context, *ExposePort
 func (UnimplementedControlServiceServer) CreateSSHKeyPair(context.Context, *CreateSSHKeyPairRequest) (*CreateSSHKeyPairResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSSHKeyPair not implemented")
 }
+func (UnimplementedControlServiceServer) InstallDotfiles(context.Context, *InstallDotfilesRequest) (*InstallDotfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstallDotfiles not implemented")
+}
+func (UnimplementedControlServiceServer) TakeSnapshot(*TakeSnapshotRequest, ControlService_TakeSnapshotServer) error {
+	return status.Errorf(codes.Unimplemented, "method TakeSnapshot not implemented")
+}
+func (UnimplementedControlServiceServer) GetSSHPublicKeys(context.Context, *GetSSHPublicKeysRequest) (*GetSSHPublicKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSSHPublicKeys not implemented")
+}
 func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
 
 // UnsafeControlServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -124,6 +199,63 @@ func _ControlService_CreateSSHKeyPair_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlService_InstallDotfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstallDotfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).InstallDotfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.ControlService/InstallDotfiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).InstallDotfiles(ctx, req.(*InstallDotfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_TakeSnapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TakeSnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).TakeSnapshot(m, &controlServiceTakeSnapshotServer{stream})
+}
+
+type ControlService_TakeSnapshotServer interface {
+	Send(*TakeSnapshotResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceTakeSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceTakeSnapshotServer) Send(m *TakeSnapshotResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ControlService_GetSSHPublicKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSSHPublicKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetSSHPublicKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.ControlService/GetSSHPublicKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetSSHPublicKeys(ctx, req.(*GetSSHPublicKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ControlService_ServiceDesc is the grpc.ServiceDesc for ControlService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -139,7 +271,21 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateSSHKeyPair",
 			Handler:    _ControlService_CreateSSHKeyPair_Handler,
 		},
+		{
+			MethodName: "InstallDotfiles",
+			Handler:    _ControlService_InstallDotfiles_Handler,
+		},
+		{
+			MethodName: "GetSSHPublicKeys",
+			Handler:    _ControlService_GetSSHPublicKeys_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TakeSnapshot",
+			Handler:       _ControlService_TakeSnapshot_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "control.proto",
 }
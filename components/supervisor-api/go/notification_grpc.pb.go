@@ -23,7 +23,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type NotificationServiceClient interface {
 	// Prompts the user and asks for a decision. Typically called by some external process.
-	// If the list of actions is empty this service returns immediately,
+	// If the lists of actions and commands are empty this service returns immediately,
 	// otherwise it blocks until the user has made their choice.
 	Notify(ctx context.Context, in *NotifyRequest, opts ...grpc.CallOption) (*NotifyResponse, error)
 	// Subscribe to notifications. Typically called by the IDE.
@@ -95,7 +95,7 @@ func (c *notificationServiceClient) Respond(ctx context.Context, in *RespondRequ
 // for forward compatibility
 type NotificationServiceServer interface {
 	// Prompts the user and asks for a decision. Typically called by some external process.
-	// If the list of actions is empty this service returns immediately,
+	// If the lists of actions and commands are empty this service returns immediately,
 	// otherwise it blocks until the user has made their choice.
 	Notify(context.Context, *NotifyRequest) (*NotifyResponse, error)
 	// Subscribe to notifications. Typically called by the IDE.
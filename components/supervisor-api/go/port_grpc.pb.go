@@ -33,6 +33,11 @@ type PortServiceClient interface {
 	AutoTunnel(ctx context.Context, in *AutoTunnelRequest, opts ...grpc.CallOption) (*AutoTunnelResponse, error)
 	// RetryAutoExpose retries auto exposing the give port
 	RetryAutoExpose(ctx context.Context, in *RetryAutoExposeRequest, opts ...grpc.CallOption) (*RetryAutoExposeResponse, error)
+	// RecordAccessLog records requests to exposed ports observed by ws-proxy.
+	// Callers must authenticate with the access log token of the workspace as bearer token.
+	RecordAccessLog(ctx context.Context, in *RecordAccessLogRequest, opts ...grpc.CallOption) (*RecordAccessLogResponse, error)
+	// ListAccessLog lists the most recent requests to exposed ports, e.g. to debug webhook deliveries.
+	ListAccessLog(ctx context.Context, in *ListAccessLogRequest, opts ...grpc.CallOption) (*ListAccessLogResponse, error)
 }
 
 type portServiceClient struct {
@@ -110,6 +115,24 @@ func (c *portServiceClient) RetryAutoExpose(ctx context.Context, in *RetryAutoEx
 	return out, nil
 }
 
+func (c *portServiceClient) RecordAccessLog(ctx context.Context, in *RecordAccessLogRequest, opts ...grpc.CallOption) (*RecordAccessLogResponse, error) {
+	out := new(RecordAccessLogResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.PortService/RecordAccessLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) ListAccessLog(ctx context.Context, in *ListAccessLogRequest, opts ...grpc.CallOption) (*ListAccessLogResponse, error) {
+	out := new(ListAccessLogResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.PortService/ListAccessLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PortServiceServer is the server API for PortService service.
 // All implementations must embed UnimplementedPortServiceServer
 // for forward compatibility
@@ -125,6 +148,11 @@ type PortServiceServer interface {
 	AutoTunnel(context.Context, *AutoTunnelRequest) (*AutoTunnelResponse, error)
 	// RetryAutoExpose retries auto exposing the give port
 	RetryAutoExpose(context.Context, *RetryAutoExposeRequest) (*RetryAutoExposeResponse, error)
+	// RecordAccessLog records requests to exposed ports observed by ws-proxy.
+	// Callers must authenticate with the access log token of the workspace as bearer token.
+	RecordAccessLog(context.Context, *RecordAccessLogRequest) (*RecordAccessLogResponse, error)
+	// ListAccessLog lists the most recent requests to exposed ports, e.g. to debug webhook deliveries.
+	ListAccessLog(context.Context, *ListAccessLogRequest) (*ListAccessLogResponse, error)
 	mustEmbedUnimplementedPortServiceServer()
 }
 
@@ -147,6 +175,12 @@ func (UnimplementedPortServiceServer) AutoTunnel(context.Context, *AutoTunnelReq
 func (UnimplementedPortServiceServer) RetryAutoExpose(context.Context, *RetryAutoExposeRequest) (*RetryAutoExposeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RetryAutoExpose not implemented")
 }
+func (UnimplementedPortServiceServer) RecordAccessLog(context.Context, *RecordAccessLogRequest) (*RecordAccessLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordAccessLog not implemented")
+}
+func (UnimplementedPortServiceServer) ListAccessLog(context.Context, *ListAccessLogRequest) (*ListAccessLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccessLog not implemented")
+}
 func (UnimplementedPortServiceServer) mustEmbedUnimplementedPortServiceServer() {}
 
 // UnsafePortServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -258,6 +292,42 @@ func _PortService_RetryAutoExpose_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortService_RecordAccessLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordAccessLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).RecordAccessLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.PortService/RecordAccessLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).RecordAccessLog(ctx, req.(*RecordAccessLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_ListAccessLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccessLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).ListAccessLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.PortService/ListAccessLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).ListAccessLog(ctx, req.(*ListAccessLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PortService_ServiceDesc is the grpc.ServiceDesc for PortService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -281,6 +351,14 @@ var PortService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RetryAutoExpose",
 			Handler:    _PortService_RetryAutoExpose_Handler,
 		},
+		{
+			MethodName: "RecordAccessLog",
+			Handler:    _PortService_RecordAccessLog_Handler,
+		},
+		{
+			MethodName: "ListAccessLog",
+			Handler:    _PortService_ListAccessLog_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -29,14 +29,26 @@ type StatusServiceClient interface {
 	// ContentStatus returns the status of the workspace content. When used with `wait`, the call
 	// returns when the content has become available.
 	ContentStatus(ctx context.Context, in *ContentStatusRequest, opts ...grpc.CallOption) (*ContentStatusResponse, error)
+	// ContentProgress streams the progress of the workspace content initialization until the content
+	// is available, so that the IDE can show how far it has come.
+	ContentProgress(ctx context.Context, in *ContentProgressRequest, opts ...grpc.CallOption) (StatusService_ContentProgressClient, error)
 	// BackupStatus offers feedback on the workspace backup status. This status information can
 	// be relayed to the user to provide transparency as to how "safe" their files/content
 	// data are w.r.t. to being lost.
 	BackupStatus(ctx context.Context, in *BackupStatusRequest, opts ...grpc.CallOption) (*BackupStatusResponse, error)
 	// PortsStatus provides feedback about the network ports currently in use.
 	PortsStatus(ctx context.Context, in *PortsStatusRequest, opts ...grpc.CallOption) (StatusService_PortsStatusClient, error)
+	// PortsStatusChanges provides a stream of changes of the network ports currently in use.
+	// The first response contains all ports as added, subsequent responses only the ports
+	// which were added, changed or removed.
+	PortsStatusChanges(ctx context.Context, in *PortsStatusChangesRequest, opts ...grpc.CallOption) (StatusService_PortsStatusChangesClient, error)
 	// TasksStatus provides tasks status information.
 	TasksStatus(ctx context.Context, in *TasksStatusRequest, opts ...grpc.CallOption) (StatusService_TasksStatusClient, error)
+	// ResourcesStatus provides the resource consumption of the workspace, i.e. CPU, memory and disk usage.
+	ResourcesStatus(ctx context.Context, in *ResourcesStatusRequest, opts ...grpc.CallOption) (StatusService_ResourcesStatusClient, error)
+	// ListProcesses lists the processes of the workspace as a tree, together with their resource
+	// consumption and the task, terminal and ports they belong to.
+	ListProcesses(ctx context.Context, in *ListProcessesRequest, opts ...grpc.CallOption) (*ListProcessesResponse, error)
 }
 
 type statusServiceClient struct {
@@ -74,6 +86,38 @@ func (c *statusServiceClient) ContentStatus(ctx context.Context, in *ContentStat
 	return out, nil
 }
 
+func (c *statusServiceClient) ContentProgress(ctx context.Context, in *ContentProgressRequest, opts ...grpc.CallOption) (StatusService_ContentProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[0], "/supervisor.StatusService/ContentProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &statusServiceContentProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StatusService_ContentProgressClient interface {
+	Recv() (*ContentProgressResponse, error)
+	grpc.ClientStream
+}
+
+type statusServiceContentProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *statusServiceContentProgressClient) Recv() (*ContentProgressResponse, error) {
+	m := new(ContentProgressResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *statusServiceClient) BackupStatus(ctx context.Context, in *BackupStatusRequest, opts ...grpc.CallOption) (*BackupStatusResponse, error) {
 	out := new(BackupStatusResponse)
 	err := c.cc.Invoke(ctx, "/supervisor.StatusService/BackupStatus", in, out, opts...)
@@ -84,7 +128,7 @@ func (c *statusServiceClient) BackupStatus(ctx context.Context, in *BackupStatus
 }
 
 func (c *statusServiceClient) PortsStatus(ctx context.Context, in *PortsStatusRequest, opts ...grpc.CallOption) (StatusService_PortsStatusClient, error) {
-	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[0], "/supervisor.StatusService/PortsStatus", opts...)
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[1], "/supervisor.StatusService/PortsStatus", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,8 +159,40 @@ func (x *statusServicePortsStatusClient) Recv() (*PortsStatusResponse, error) {
 	return m, nil
 }
 
+func (c *statusServiceClient) PortsStatusChanges(ctx context.Context, in *PortsStatusChangesRequest, opts ...grpc.CallOption) (StatusService_PortsStatusChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[2], "/supervisor.StatusService/PortsStatusChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &statusServicePortsStatusChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StatusService_PortsStatusChangesClient interface {
+	Recv() (*PortsStatusChangesResponse, error)
+	grpc.ClientStream
+}
+
+type statusServicePortsStatusChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *statusServicePortsStatusChangesClient) Recv() (*PortsStatusChangesResponse, error) {
+	m := new(PortsStatusChangesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *statusServiceClient) TasksStatus(ctx context.Context, in *TasksStatusRequest, opts ...grpc.CallOption) (StatusService_TasksStatusClient, error) {
-	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[1], "/supervisor.StatusService/TasksStatus", opts...)
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[3], "/supervisor.StatusService/TasksStatus", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +223,47 @@ func (x *statusServiceTasksStatusClient) Recv() (*TasksStatusResponse, error) {
 	return m, nil
 }
 
+func (c *statusServiceClient) ResourcesStatus(ctx context.Context, in *ResourcesStatusRequest, opts ...grpc.CallOption) (StatusService_ResourcesStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[4], "/supervisor.StatusService/ResourcesStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &statusServiceResourcesStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StatusService_ResourcesStatusClient interface {
+	Recv() (*ResourcesStatusResponse, error)
+	grpc.ClientStream
+}
+
+type statusServiceResourcesStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *statusServiceResourcesStatusClient) Recv() (*ResourcesStatusResponse, error) {
+	m := new(ResourcesStatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *statusServiceClient) ListProcesses(ctx context.Context, in *ListProcessesRequest, opts ...grpc.CallOption) (*ListProcessesResponse, error) {
+	out := new(ListProcessesResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.StatusService/ListProcesses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StatusServiceServer is the server API for StatusService service.
 // All implementations must embed UnimplementedStatusServiceServer
 // for forward compatibility
@@ -158,14 +275,26 @@ type StatusServiceServer interface {
 	// ContentStatus returns the status of the workspace content. When used with `wait`, the call
 	// returns when the content has become available.
 	ContentStatus(context.Context, *ContentStatusRequest) (*ContentStatusResponse, error)
+	// ContentProgress streams the progress of the workspace content initialization until the content
+	// is available, so that the IDE can show how far it has come.
+	ContentProgress(*ContentProgressRequest, StatusService_ContentProgressServer) error
 	// BackupStatus offers feedback on the workspace backup status. This status information can
 	// be relayed to the user to provide transparency as to how "safe" their files/content
 	// data are w.r.t. to being lost.
 	BackupStatus(context.Context, *BackupStatusRequest) (*BackupStatusResponse, error)
 	// PortsStatus provides feedback about the network ports currently in use.
 	PortsStatus(*PortsStatusRequest, StatusService_PortsStatusServer) error
+	// PortsStatusChanges provides a stream of changes of the network ports currently in use.
+	// The first response contains all ports as added, subsequent responses only the ports
+	// which were added, changed or removed.
+	PortsStatusChanges(*PortsStatusChangesRequest, StatusService_PortsStatusChangesServer) error
 	// TasksStatus provides tasks status information.
 	TasksStatus(*TasksStatusRequest, StatusService_TasksStatusServer) error
+	// ResourcesStatus provides the resource consumption of the workspace, i.e. CPU, memory and disk usage.
+	ResourcesStatus(*ResourcesStatusRequest, StatusService_ResourcesStatusServer) error
+	// ListProcesses lists the processes of the workspace as a tree, together with their resource
+	// consumption and the task, terminal and ports they belong to.
+	ListProcesses(context.Context, *ListProcessesRequest) (*ListProcessesResponse, error)
 	mustEmbedUnimplementedStatusServiceServer()
 }
 
@@ -182,15 +311,27 @@ func (UnimplementedStatusServiceServer) IDEStatus(context.Context, *IDEStatusReq
 func (UnimplementedStatusServiceServer) ContentStatus(context.Context, *ContentStatusRequest) (*ContentStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ContentStatus not implemented")
 }
+func (UnimplementedStatusServiceServer) ContentProgress(*ContentProgressRequest, StatusService_ContentProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method ContentProgress not implemented")
+}
 func (UnimplementedStatusServiceServer) BackupStatus(context.Context, *BackupStatusRequest) (*BackupStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BackupStatus not implemented")
 }
 func (UnimplementedStatusServiceServer) PortsStatus(*PortsStatusRequest, StatusService_PortsStatusServer) error {
 	return status.Errorf(codes.Unimplemented, "method PortsStatus not implemented")
 }
+func (UnimplementedStatusServiceServer) PortsStatusChanges(*PortsStatusChangesRequest, StatusService_PortsStatusChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method PortsStatusChanges not implemented")
+}
 func (UnimplementedStatusServiceServer) TasksStatus(*TasksStatusRequest, StatusService_TasksStatusServer) error {
 	return status.Errorf(codes.Unimplemented, "method TasksStatus not implemented")
 }
+func (UnimplementedStatusServiceServer) ResourcesStatus(*ResourcesStatusRequest, StatusService_ResourcesStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method ResourcesStatus not implemented")
+}
+func (UnimplementedStatusServiceServer) ListProcesses(context.Context, *ListProcessesRequest) (*ListProcessesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProcesses not implemented")
+}
 func (UnimplementedStatusServiceServer) mustEmbedUnimplementedStatusServiceServer() {}
 
 // UnsafeStatusServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -258,6 +399,27 @@ func _StatusService_ContentStatus_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatusService_ContentProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ContentProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StatusServiceServer).ContentProgress(m, &statusServiceContentProgressServer{stream})
+}
+
+type StatusService_ContentProgressServer interface {
+	Send(*ContentProgressResponse) error
+	grpc.ServerStream
+}
+
+type statusServiceContentProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *statusServiceContentProgressServer) Send(m *ContentProgressResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _StatusService_BackupStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BackupStatusRequest)
 	if err := dec(in); err != nil {
@@ -297,6 +459,27 @@ func (x *statusServicePortsStatusServer) Send(m *PortsStatusResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _StatusService_PortsStatusChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PortsStatusChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StatusServiceServer).PortsStatusChanges(m, &statusServicePortsStatusChangesServer{stream})
+}
+
+type StatusService_PortsStatusChangesServer interface {
+	Send(*PortsStatusChangesResponse) error
+	grpc.ServerStream
+}
+
+type statusServicePortsStatusChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *statusServicePortsStatusChangesServer) Send(m *PortsStatusChangesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _StatusService_TasksStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(TasksStatusRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -318,6 +501,45 @@ func (x *statusServiceTasksStatusServer) Send(m *TasksStatusResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _StatusService_ResourcesStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResourcesStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StatusServiceServer).ResourcesStatus(m, &statusServiceResourcesStatusServer{stream})
+}
+
+type StatusService_ResourcesStatusServer interface {
+	Send(*ResourcesStatusResponse) error
+	grpc.ServerStream
+}
+
+type statusServiceResourcesStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *statusServiceResourcesStatusServer) Send(m *ResourcesStatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StatusService_ListProcesses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProcessesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatusServiceServer).ListProcesses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.StatusService/ListProcesses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatusServiceServer).ListProcesses(ctx, req.(*ListProcessesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StatusService_ServiceDesc is the grpc.ServiceDesc for StatusService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -341,18 +563,37 @@ var StatusService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BackupStatus",
 			Handler:    _StatusService_BackupStatus_Handler,
 		},
+		{
+			MethodName: "ListProcesses",
+			Handler:    _StatusService_ListProcesses_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ContentProgress",
+			Handler:       _StatusService_ContentProgress_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "PortsStatus",
 			Handler:       _StatusService_PortsStatus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PortsStatusChanges",
+			Handler:       _StatusService_PortsStatusChanges_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TasksStatus",
 			Handler:       _StatusService_TasksStatus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ResourcesStatus",
+			Handler:       _StatusService_ResourcesStatus_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "status.proto",
 }
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/creack/pty"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+var recordSessionOpts struct {
+	Dir          string
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+var recordSessionCmd = &cobra.Command{
+	Use:    "record-session",
+	Short:  "runs an SSH session and records it",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		command := os.Getenv("SSH_ORIGINAL_COMMAND")
+
+		// sshd forces this command for subsystems, too
+		if command == "internal-sftp" {
+			bin, err := os.Executable()
+			if err != nil {
+				log.WithError(err).Fatal("cannot find executable path")
+			}
+			sftp := filepath.Join(filepath.Dir(bin), "ssh", "sftp-server")
+			err = syscall.Exec(sftp, []string{sftp}, os.Environ())
+			log.WithError(err).Fatal("cannot start sftp server")
+		}
+
+		argv := []string{"-" + filepath.Base(shell)}
+		if command != "" {
+			argv = []string{filepath.Base(shell), "-c", command}
+		}
+
+		// sessions without a terminal, e.g. scp or git, don't produce anything worth replaying
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			err := syscall.Exec(shell, argv, os.Environ())
+			log.WithError(err).Fatal("cannot start shell")
+		}
+
+		size, err := pty.GetsizeFull(os.Stdin)
+		if err != nil {
+			size = nil
+		}
+		recordings := &terminal.Recordings{
+			Dir:          recordSessionOpts.Dir,
+			MaxFileSize:  recordSessionOpts.MaxFileSize,
+			MaxTotalSize: recordSessionOpts.MaxTotalSize,
+		}
+		recording, err := recordings.Open("ssh", size, command)
+		if err != nil {
+			log.WithError(err).Fatal("cannot record SSH session")
+		}
+		defer recording.Close()
+
+		c := &exec.Cmd{Path: shell, Args: argv, Env: os.Environ()}
+		ptmx, err := pty.StartWithSize(c, size)
+		if err != nil {
+			log.WithError(err).Fatal("cannot start shell")
+		}
+		defer ptmx.Close()
+
+		oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			log.WithError(err).Fatal("cannot put terminal into raw mode")
+		}
+		defer func() { _ = term.Restore(int(os.Stdin.Fd()), oldState) }() // Best effort.
+
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGWINCH)
+		go func() {
+			for range ch {
+				size, err := pty.GetsizeFull(os.Stdin)
+				if err != nil {
+					continue
+				}
+				_ = pty.Setsize(ptmx, size)
+				_ = recording.Resize(size)
+			}
+		}()
+
+		//nolint:errcheck
+		go io.Copy(ptmx, os.Stdin)
+		_, _ = io.Copy(io.MultiWriter(os.Stdout, recording), ptmx)
+
+		err = c.Wait()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			_ = term.Restore(int(os.Stdin.Fd()), oldState)
+			_ = recording.Close()
+			os.Exit(exitErr.ExitCode())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recordSessionCmd)
+
+	recordSessionCmd.Flags().StringVar(&recordSessionOpts.Dir, "dir", "", "directory to record the session to")
+	recordSessionCmd.Flags().Int64Var(&recordSessionOpts.MaxFileSize, "max-file-size", 0, "size in bytes after which the recording is continued in a new file")
+	recordSessionCmd.Flags().Int64Var(&recordSessionOpts.MaxTotalSize, "max-total-size", 0, "size in bytes of all recordings after which the oldest ones are removed")
+	_ = recordSessionCmd.MarkFlagRequired("dir")
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package activity
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// Names of the activity sources supervisor knows about.
+const (
+	SourceTerminal = "terminal"
+	SourceAPI      = "api"
+	SourcePort     = "port"
+	SourceSSH      = "ssh"
+)
+
+// Source tells when the user was last active in the workspace.
+type Source interface {
+	// LastActivity returns the time of the last activity, or the zero time if there was none.
+	LastActivity() time.Time
+}
+
+// LastActivity returns the time of the latest activity of all sources, or the zero time if there was none.
+func LastActivity(sources map[string]Source) time.Time {
+	var latest time.Time
+	for _, src := range sources {
+		if last := src.LastActivity(); last.After(latest) {
+			latest = last
+		}
+	}
+	return latest
+}
+
+// Tracker is a Source which is told about activity.
+type Tracker struct {
+	last int64
+}
+
+// Mark records activity now.
+func (t *Tracker) Mark() {
+	atomic.StoreInt64(&t.last, time.Now().UnixNano())
+}
+
+// LastActivity returns the time of the last Mark.
+func (t *Tracker) LastActivity() time.Time {
+	last := atomic.LoadInt64(&t.last)
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+// Reader marks the tracker whenever data is read from r.
+func (t *Tracker) Reader(r io.Reader) io.Reader {
+	return &trackingReader{r: r, t: t}
+}
+
+type trackingReader struct {
+	r io.Reader
+	t *Tracker
+}
+
+func (r *trackingReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if n > 0 {
+		r.t.Mark()
+	}
+	return
+}
+
+// Heartbeat sends a heartbeat whenever any of its sources saw activity since the last heartbeat.
+type Heartbeat struct {
+	Sources  map[string]Source
+	Interval time.Duration
+	Send     func(ctx context.Context) error
+
+	mu       sync.Mutex
+	lastSent time.Time
+}
+
+// Run checks the sources every interval until the context is canceled.
+func (h *Heartbeat) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.check(ctx)
+		}
+	}
+}
+
+// check sends a heartbeat if there was activity since the last one and returns the sources which were active.
+func (h *Heartbeat) check(ctx context.Context) (active []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var latest time.Time
+	for name, src := range h.Sources {
+		last := src.LastActivity()
+		if !last.After(h.lastSent) {
+			continue
+		}
+		active = append(active, name)
+		if last.After(latest) {
+			latest = last
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	err := h.Send(ctx)
+	if err != nil {
+		log.WithError(err).WithField("sources", active).Warn("cannot send heartbeat")
+		return active
+	}
+	h.lastSent = latest
+	return active
+}
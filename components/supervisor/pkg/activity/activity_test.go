@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package activity
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fixedSource time.Time
+
+func (s *fixedSource) LastActivity() time.Time { return time.Time(*s) }
+
+func TestHeartbeatCheck(t *testing.T) {
+	var (
+		start = time.Now()
+		t1    = start.Add(1 * time.Second)
+		t2    = start.Add(2 * time.Second)
+	)
+	type step struct {
+		Terminal time.Time
+		SSH      time.Time
+		SendErr  error
+	}
+	type result struct {
+		Active []string
+		Sent   bool
+	}
+	tests := []struct {
+		Desc        string
+		Steps       []step
+		Expectation []result
+	}{
+		{
+			Desc:        "no activity",
+			Steps:       []step{{}, {}},
+			Expectation: []result{{}, {}},
+		},
+		{
+			Desc: "terminal activity",
+			Steps: []step{
+				{Terminal: t1},
+				{Terminal: t1},
+				{Terminal: t2},
+			},
+			Expectation: []result{
+				{Active: []string{SourceTerminal}, Sent: true},
+				{},
+				{Active: []string{SourceTerminal}, Sent: true},
+			},
+		},
+		{
+			Desc: "ssh only",
+			Steps: []step{
+				{SSH: t1},
+				{SSH: t2},
+			},
+			Expectation: []result{
+				{Active: []string{SourceSSH}, Sent: true},
+				{Active: []string{SourceSSH}, Sent: true},
+			},
+		},
+		{
+			Desc: "multiple sources",
+			Steps: []step{
+				{Terminal: t1, SSH: t2},
+				{Terminal: t1, SSH: t2},
+			},
+			Expectation: []result{
+				{Active: []string{SourceSSH, SourceTerminal}, Sent: true},
+				{},
+			},
+		},
+		{
+			Desc: "retries failed heartbeats",
+			Steps: []step{
+				{Terminal: t1, SendErr: errors.New("not connected")},
+				{Terminal: t1},
+			},
+			Expectation: []result{
+				{Active: []string{SourceTerminal}, Sent: true},
+				{Active: []string{SourceTerminal}, Sent: true},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var (
+				terminal fixedSource
+				ssh      fixedSource
+				sent     bool
+				sendErr  error
+			)
+			hb := &Heartbeat{
+				Sources: map[string]Source{
+					SourceTerminal: &terminal,
+					SourceSSH:      &ssh,
+				},
+				Send: func(ctx context.Context) error {
+					sent = true
+					return sendErr
+				},
+				lastSent: start,
+			}
+
+			var act []result
+			for _, s := range test.Steps {
+				terminal, ssh, sent, sendErr = fixedSource(s.Terminal), fixedSource(s.SSH), false, s.SendErr
+
+				active := hb.check(context.Background())
+				sort.Strings(active)
+				act = append(act, result{Active: active, Sent: sent})
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTracker(t *testing.T) {
+	var tracker Tracker
+	if !tracker.LastActivity().IsZero() {
+		t.Errorf("expected no activity, got %v", tracker.LastActivity())
+	}
+
+	before := time.Now()
+	tracker.Mark()
+	if last := tracker.LastActivity(); last.Before(before) {
+		t.Errorf("expected activity after %v, got %v", before, last)
+	}
+}
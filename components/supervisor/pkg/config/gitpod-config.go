@@ -174,6 +174,11 @@ func (service *ConfigService) updateConfig() error {
 	return err
 }
 
+// Read reads the config file right away, also if the config is not watched yet.
+func (service *ConfigService) Read() (*gitpod.GitpodConfig, error) {
+	return service.parse()
+}
+
 func (service *ConfigService) parse() (*gitpod.GitpodConfig, error) {
 	data, err := os.ReadFile(service.location)
 	if err != nil {
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"sync"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// AccessLog keeps the most recent requests to exposed ports which ws-proxy reported,
+// so that users can inspect them, e.g. to debug webhook deliveries.
+type AccessLog struct {
+	size int
+
+	mu      sync.Mutex
+	entries []*api.PortAccessLogEntry
+}
+
+// NewAccessLog creates a new access log which keeps up to size entries.
+func NewAccessLog(size int) *AccessLog {
+	return &AccessLog{size: size}
+}
+
+// Add appends entries to the log and drops the oldest ones if the log is full.
+func (l *AccessLog) Add(entries ...*api.PortAccessLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entries...)
+	if overflow := len(l.entries) - l.size; overflow > 0 {
+		l.entries = append([]*api.PortAccessLogEntry(nil), l.entries[overflow:]...)
+	}
+}
+
+// Entries returns the entries of the log, oldest first.
+func (l *AccessLog) Entries() []*api.PortAccessLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]*api.PortAccessLogEntry(nil), l.entries...)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestAccessLog(t *testing.T) {
+	entry := func(status uint32) *api.PortAccessLogEntry {
+		return &api.PortAccessLogEntry{Port: 8080, Method: "POST", Status: status}
+	}
+
+	tests := []struct {
+		Desc        string
+		Size        int
+		Add         [][]*api.PortAccessLogEntry
+		Expectation []*api.PortAccessLogEntry
+	}{
+		{
+			Desc: "empty",
+			Size: 2,
+		},
+		{
+			Desc:        "below size",
+			Size:        2,
+			Add:         [][]*api.PortAccessLogEntry{{entry(200)}},
+			Expectation: []*api.PortAccessLogEntry{entry(200)},
+		},
+		{
+			Desc:        "drops oldest entries",
+			Size:        2,
+			Add:         [][]*api.PortAccessLogEntry{{entry(200), entry(404)}, {entry(500)}},
+			Expectation: []*api.PortAccessLogEntry{entry(404), entry(500)},
+		},
+		{
+			Desc:        "batch larger than size",
+			Size:        2,
+			Add:         [][]*api.PortAccessLogEntry{{entry(200), entry(404), entry(500)}},
+			Expectation: []*api.PortAccessLogEntry{entry(404), entry(500)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			log := NewAccessLog(test.Size)
+			for _, entries := range test.Add {
+				log.Add(entries...)
+			}
+
+			act := log.Entries()
+			if diff := cmp.Diff(test.Expectation, act, cmpopts.IgnoreUnexported(api.PortAccessLogEntry{})); diff != "" {
+				t.Errorf("unexpected entries (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
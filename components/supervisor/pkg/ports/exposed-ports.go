@@ -6,17 +6,28 @@ package ports
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
 )
 
 // ExposedPort represents an exposed pprt
 type ExposedPort struct {
-	LocalPort uint32
-	URL       string
-	Public    bool
+	LocalPort  uint32
+	URL        string
+	Visibility api.PortVisibility
+}
+
+// parsePortVisibility maps the visibility of a port in the Gitpod API or in the .gitpod.yml to the supervisor API.
+// Ports without or with an unknown visibility are private.
+func parsePortVisibility(visibility string) api.PortVisibility {
+	if v, ok := api.PortVisibility_value[visibility]; ok {
+		return api.PortVisibility(v)
+	}
+	return api.PortVisibility_private
 }
 
 // ExposedPortsInterface provides access to port exposure
@@ -32,7 +43,7 @@ type ExposedPortsInterface interface {
 	Run(ctx context.Context)
 
 	// Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
-	Expose(ctx context.Context, port uint32, public bool) <-chan error
+	Expose(ctx context.Context, port uint32, visibility api.PortVisibility) <-chan error
 }
 
 // NoopExposedPorts implements ExposedPortsInterface but does nothing
@@ -47,7 +58,7 @@ func (*NoopExposedPorts) Observe(ctx context.Context) (<-chan []ExposedPort, <-c
 func (*NoopExposedPorts) Run(ctx context.Context) {}
 
 // Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
-func (*NoopExposedPorts) Expose(ctx context.Context, local uint32, public bool) <-chan error {
+func (*NoopExposedPorts) Expose(ctx context.Context, local uint32, visibility api.PortVisibility) <-chan error {
 	done := make(chan error)
 	close(done)
 	return done
@@ -61,8 +72,12 @@ type GitpodExposedPorts struct {
 	C           gitpod.APIInterface
 
 	minExposeDelay        time.Duration
+	maxExposeDelay        time.Duration
 	maxExposeAttempts     uint32
 	exposeDelayGrowFactor float64
+	exposeDelayJitter     float64
+	// rand is only used by Run, hence needs no synchronisation
+	rand *rand.Rand
 
 	requests chan *exposePortRequest
 }
@@ -81,8 +96,11 @@ func NewGitpodExposedPorts(workspaceID string, instanceID string, gitpodService
 		C:           gitpodService,
 
 		minExposeDelay:        2 * time.Second,
+		maxExposeDelay:        30 * time.Second,
 		maxExposeAttempts:     5,
 		exposeDelayGrowFactor: 1.5,
+		exposeDelayJitter:     0.2,
+		rand:                  rand.New(rand.NewSource(time.Now().UnixNano())),
 
 		// allow clients to submit 30 expose requests without blocking
 		requests: make(chan *exposePortRequest, 30),
@@ -115,9 +133,9 @@ func (g *GitpodExposedPorts) Observe(ctx context.Context) (<-chan []ExposedPort,
 				res := make([]ExposedPort, len(u.Status.ExposedPorts))
 				for i, p := range u.Status.ExposedPorts {
 					res[i] = ExposedPort{
-						LocalPort: uint32(p.Port),
-						Public:    p.Visibility == "public",
-						URL:       p.URL,
+						LocalPort:  uint32(p.Port),
+						Visibility: parsePortVisibility(p.Visibility),
+						URL:        p.URL,
 					}
 				}
 
@@ -154,34 +172,44 @@ func (g *GitpodExposedPorts) doExpose(req *exposePortRequest) {
 		close(req.done)
 	}()
 	delay := g.minExposeDelay
-	attempt := 0
+	var attempt uint32
 	for {
 		_, err = g.C.OpenPort(req.ctx, g.WorkspaceID, req.port)
-		if err == nil || req.ctx.Err() != nil || attempt == 5 {
+		attempt++
+		if err == nil || req.ctx.Err() != nil || attempt >= g.maxExposeAttempts {
 			return
 		}
-		log.WithError(err).WithField("port", req.port).Warnf("cannot expose port, trying again in %d seconds...", uint32(delay.Seconds()))
+		wait := g.jitter(delay)
+		log.WithError(err).WithField("port", req.port).WithField("attempt", attempt).Warnf("cannot expose port, trying again in %.1f seconds...", wait.Seconds())
 		select {
 		case <-req.ctx.Done():
 			err = req.ctx.Err()
 			return
-		case <-time.After(delay):
+		case <-time.After(wait):
 			delay = time.Duration(float64(delay) * g.exposeDelayGrowFactor)
-			attempt++
+			if g.maxExposeDelay > 0 && delay > g.maxExposeDelay {
+				delay = g.maxExposeDelay
+			}
 		}
 	}
 }
 
-// Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
-func (g *GitpodExposedPorts) Expose(ctx context.Context, local uint32, public bool) <-chan error {
-	v := "private"
-	if public {
-		v = "public"
+// jitter randomizes the delay by up to exposeDelayJitter in both directions,
+// so that retries of ports which failed together are spread out.
+func (g *GitpodExposedPorts) jitter(delay time.Duration) time.Duration {
+	if g.exposeDelayJitter <= 0 {
+		return delay
 	}
+	return time.Duration(float64(delay) * (1 + g.exposeDelayJitter*(2*g.rand.Float64()-1)))
+}
+
+// Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
+func (g *GitpodExposedPorts) Expose(ctx context.Context, local uint32, visibility api.PortVisibility) <-chan error {
 	req := &exposePortRequest{
 		port: &gitpod.WorkspaceInstancePort{
-			Port:       float64(local),
-			Visibility: v,
+			Port: float64(local),
+			// the names of the supervisor API's visibilities match those of the Gitpod API
+			Visibility: visibility.String(),
 		},
 		ctx:  ctx,
 		done: make(chan error),
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestExposeRetries(t *testing.T) {
+	errTransient := errors.New("transient")
+	tests := []struct {
+		Desc        string
+		Failures    int
+		Calls       int
+		Expectation error
+	}{
+		{
+			Desc:  "succeeds at once",
+			Calls: 1,
+		},
+		{
+			Desc:     "succeeds after transient failures",
+			Failures: 2,
+			Calls:    3,
+		},
+		{
+			Desc:        "gives up after max attempts",
+			Failures:    10,
+			Calls:       4,
+			Expectation: errTransient,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var calls int
+			gitpodAPI := gitpod.NewMockAPIInterface(ctrl)
+			gitpodAPI.EXPECT().OpenPort(gomock.Any(), "ws", gomock.Any()).Times(test.Calls).DoAndReturn(func(ctx context.Context, workspaceID string, port *gitpod.WorkspaceInstancePort) (*gitpod.WorkspaceInstancePort, error) {
+				calls++
+				if calls <= test.Failures {
+					return nil, errTransient
+				}
+				return port, nil
+			})
+
+			exposed := NewGitpodExposedPorts("ws", "instance", gitpodAPI)
+			exposed.minExposeDelay = time.Millisecond
+			exposed.maxExposeDelay = 2 * time.Millisecond
+			exposed.maxExposeAttempts = 4
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go exposed.Run(ctx)
+
+			err := <-exposed.Expose(ctx, 8080, api.PortVisibility_private)
+			if err != test.Expectation {
+				t.Errorf("unexpected error: want %v, got %v", test.Expectation, err)
+			}
+		})
+	}
+}
+
+func TestExposeJitter(t *testing.T) {
+	exposed := NewGitpodExposedPorts("ws", "instance", nil)
+	delay := 10 * time.Second
+	for i := 0; i < 100; i++ {
+		act := exposed.jitter(delay)
+		if act < 8*time.Second || act > 12*time.Second {
+			t.Fatalf("jittered delay out of bounds: %s", act)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/config"
@@ -74,15 +75,26 @@ func (configs *Configs) Get(port uint32) (*gitpod.PortConfig, ConfigKind, bool)
 	for _, rangeConfig := range configs.instanceRangeConfigs {
 		if rangeConfig.Start <= port && port <= rangeConfig.End {
 			return &gitpod.PortConfig{
-				Port:       float64(port),
-				OnOpen:     rangeConfig.OnOpen,
-				Visibility: rangeConfig.Visibility,
+				Port:           float64(port),
+				Name:           expandPortTemplate(rangeConfig.Name, port),
+				Description:    expandPortTemplate(rangeConfig.Description, port),
+				OnOpen:         rangeConfig.OnOpen,
+				Visibility:     rangeConfig.Visibility,
+				ReadinessProbe: rangeConfig.ReadinessProbe,
+				Ignore:         rangeConfig.Ignore,
 			}, RangeConfigKind, true
 		}
 	}
 	return nil, PortConfigKind, false
 }
 
+// portTemplateVar is replaced by the actual port number in names and descriptions of port ranges.
+const portTemplateVar = "{port}"
+
+func expandPortTemplate(template string, port uint32) string {
+	return strings.ReplaceAll(template, portTemplateVar, strconv.FormatUint(uint64(port), 10))
+}
+
 // ConfigInterace allows to watch port configurations.
 type ConfigInterace interface {
 	// Observe provides channels triggered whenever the port configurations are changed.
@@ -197,9 +209,13 @@ func parseInstanceConfigs(ports []*gitpod.PortsItems) (portConfigs map[uint32]*g
 			_, exists := portConfigs[port]
 			if !exists {
 				portConfigs[port] = &gitpod.PortConfig{
-					OnOpen:     config.OnOpen,
-					Port:       float64(Port),
-					Visibility: config.Visibility,
+					OnOpen:         config.OnOpen,
+					Port:           float64(Port),
+					Name:           config.Name,
+					Description:    config.Description,
+					Visibility:     config.Visibility,
+					ReadinessProbe: config.ReadinessProbe,
+					Ignore:         config.Ignore,
 				}
 			}
 			continue
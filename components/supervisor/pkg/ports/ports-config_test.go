@@ -49,18 +49,41 @@ func TestPortsConfig(t *testing.T) {
 			GitpodConfig: &gitpod.GitpodConfig{
 				Ports: []*gitpod.PortsItems{
 					{
-						Port:       9229,
-						OnOpen:     "ignore",
-						Visibility: "public",
+						Port:        9229,
+						OnOpen:      "ignore",
+						Visibility:  "public",
+						Name:        "Debugger",
+						Description: "The debugger port",
 					},
 				},
 			},
 			Expectation: &PortConfigTestExpectations{
 				InstancePortConfigs: []*gitpod.PortConfig{
 					{
-						Port:       9229,
-						OnOpen:     "ignore",
-						Visibility: "public",
+						Port:        9229,
+						OnOpen:      "ignore",
+						Visibility:  "public",
+						Name:        "Debugger",
+						Description: "The debugger port",
+					},
+				},
+			},
+		},
+		{
+			Desc: "ignored instance port config",
+			GitpodConfig: &gitpod.GitpodConfig{
+				Ports: []*gitpod.PortsItems{
+					{
+						Port:   9229,
+						Ignore: true,
+					},
+				},
+			},
+			Expectation: &PortConfigTestExpectations{
+				InstancePortConfigs: []*gitpod.PortConfig{
+					{
+						Port:   9229,
+						Ignore: true,
 					},
 				},
 			},
@@ -150,6 +173,87 @@ func TestPortsConfig(t *testing.T) {
 	}
 }
 
+func TestPortsConfigGet(t *testing.T) {
+	_, rangeConfigs := parseInstanceConfigs([]*gitpod.PortsItems{
+		{
+			Port:        "3000-3010",
+			Name:        "worker-{port}",
+			Description: "Worker listening on {port}",
+			OnOpen:      "open-browser",
+			Visibility:  "public",
+		},
+		{
+			Port: "4000-4010",
+		},
+	})
+	configs := &Configs{
+		workspaceConfigs: map[uint32]*gitpod.PortConfig{
+			3005: {Port: 3005, Name: "admin"},
+		},
+		instanceRangeConfigs: rangeConfigs,
+	}
+
+	type Expectation struct {
+		Config *gitpod.PortConfig
+		Kind   ConfigKind
+		Exists bool
+	}
+	tests := []struct {
+		Desc        string
+		Port        uint32
+		Expectation Expectation
+	}{
+		{
+			Desc: "range port inherits range config",
+			Port: 3001,
+			Expectation: Expectation{
+				Config: &gitpod.PortConfig{
+					Port:        3001,
+					Name:        "worker-3001",
+					Description: "Worker listening on 3001",
+					OnOpen:      "open-browser",
+					Visibility:  "public",
+				},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "port config takes precedence over range config",
+			Port: 3005,
+			Expectation: Expectation{
+				Config: &gitpod.PortConfig{Port: 3005, Name: "admin"},
+				Kind:   PortConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "range without templates",
+			Port: 4010,
+			Expectation: Expectation{
+				Config: &gitpod.PortConfig{Port: 4010},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc:        "not configured",
+			Port:        5000,
+			Expectation: Expectation{Kind: PortConfigKind},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var act Expectation
+			act.Config, act.Kind, act.Exists = configs.Get(test.Port)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type PortConfigTestExpectations struct {
 	WorkspaceConfigs     []*gitpod.PortConfig
 	InstancePortConfigs  []*gitpod.PortConfig
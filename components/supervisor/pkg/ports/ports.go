@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"reflect"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -62,10 +63,14 @@ func NewManager(exposed ExposedPortsInterface, served ServedPortsObserver, confi
 		proxies:      make(map[uint32]*localhostProxy),
 		autoExposed:  make(map[uint32]*autoExposure),
 		autoTunneled: make(map[uint32]struct{}),
+		probes:       make(map[uint32]*readinessProbe),
+		sniffed:      make(map[uint32]*sniffedProtocol),
 
 		state:         state,
 		subscriptions: make(map[*Subscription]struct{}),
 		proxyStarter:  startLocalhostProxy,
+		prober:        probePortReadiness,
+		sniffer:       sniffPortProtocol,
 
 		autoTunnelEnabled: true,
 	}
@@ -77,9 +82,27 @@ type localhostProxy struct {
 }
 
 type autoExposure struct {
-	state  api.PortAutoExposure
-	ctx    context.Context
-	public bool
+	state      api.PortAutoExposure
+	err        error
+	ctx        context.Context
+	visibility api.PortVisibility
+}
+
+func (a *autoExposure) errorMessage() string {
+	if a.err == nil {
+		return ""
+	}
+	return a.err.Error()
+}
+
+type readinessProbe struct {
+	ready  bool
+	cancel context.CancelFunc
+}
+
+type sniffedProtocol struct {
+	protocol api.PortApplicationProtocol
+	cancel   context.CancelFunc
 }
 
 // Manager brings together served and exposed ports. It keeps track of which port is exposed, which one is served,
@@ -95,8 +118,12 @@ type Manager struct {
 
 	internal     map[uint32]struct{}
 	proxies      map[uint32]*localhostProxy
-	proxyStarter func(port uint32) (proxy io.Closer, err error)
+	proxyStarter func(port uint32, target net.IP) (proxy io.Closer, err error)
 	autoExposed  map[uint32]*autoExposure
+	probes       map[uint32]*readinessProbe
+	prober       func(ctx context.Context, port uint32, probe *gitpod.ReadinessProbe) error
+	sniffed      map[uint32]*sniffedProtocol
+	sniffer      func(ctx context.Context, port uint32) api.PortApplicationProtocol
 
 	autoTunneled      map[uint32]struct{}
 	autoTunnelEnabled bool
@@ -122,6 +149,11 @@ type managedPort struct {
 	URL          string
 	OnExposed    api.OnPortExposedAction
 	AutoExposure api.PortAutoExposure
+	Protocol     api.PortProtocol
+	Process      *ServedPortProcess
+
+	ApplicationProtocol api.PortApplicationProtocol
+	AutoExposureError   string
 
 	LocalhostPort uint32
 
@@ -266,7 +298,12 @@ func (pm *Manager) updateState(ctx context.Context, exposed []ExposedPort, serve
 			}
 
 			current, exists := servedMap[port.Port]
-			if !exists || (!port.BoundToLocalhost && current.BoundToLocalhost) {
+			// prefer TCP over UDP, and within the same protocol ports which are not bound to localhost
+			preferred := port.Protocol == api.PortProtocol_tcp && current.Protocol != api.PortProtocol_tcp
+			if port.Protocol == current.Protocol {
+				preferred = !port.BoundToLocalhost && current.BoundToLocalhost
+			}
+			if !exists || preferred {
 				servedMap[port.Port] = port
 			}
 		}
@@ -287,6 +324,7 @@ func (pm *Manager) updateState(ctx context.Context, exposed []ExposedPort, serve
 		if !reflect.DeepEqual(pm.served, newServed) {
 			log.WithField("served", newServed).Debug("updating served ports")
 			pm.served = newServed
+			pm.updateProbes()
 			pm.updateProxies()
 			pm.updateSlirp()
 			pm.autoTunnel(ctx)
@@ -328,14 +366,10 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 		}
 
 		config, _, _ := pm.configs.Get(port)
-		Visibility := api.PortVisibility_private
-		if exposed.Public {
-			Visibility = api.PortVisibility_public
-		}
 		state[port] = &managedPort{
 			LocalhostPort: port,
 			Exposed:       true,
-			Visibility:    Visibility,
+			Visibility:    exposed.Visibility,
 			URL:           exposed.URL,
 			OnExposed:     getOnExposedAction(config, port),
 		}
@@ -362,7 +396,7 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 	// 2. second capture configured since we don't want to auto expose already exposed ports
 	if pm.configs != nil {
 		pm.configs.ForEach(func(port uint32, config *gitpod.PortConfig) {
-			if pm.boundInternally(port) {
+			if pm.boundInternally(port) || config.Ignore {
 				return
 			}
 
@@ -378,6 +412,7 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 			autoExpose, autoExposed := pm.autoExposed[port]
 			if autoExposed {
 				mp.AutoExposure = autoExpose.state
+				mp.AutoExposureError = autoExpose.errorMessage()
 			}
 			if mp.Exposed {
 				return
@@ -388,12 +423,8 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 				return
 			}
 
-			mp.Visibility = api.PortVisibility_private
-			if config.Visibility == "public" {
-				mp.Visibility = api.PortVisibility_public
-			}
-			public := mp.Visibility == api.PortVisibility_public
-			mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, public).state
+			mp.Visibility = parsePortVisibility(config.Visibility)
+			mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, mp.Visibility).state
 		})
 	}
 
@@ -405,8 +436,17 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 		if pm.boundInternally(port) {
 			continue
 		}
+		if !pm.isReady(ctx, served) {
+			// the port is announced once its readiness probe succeeded
+			continue
+		}
 
 		mp, exists := state[port]
+		ignored := pm.ignored(port)
+		if ignored && !exists {
+			// ignored ports are only reported if they were exposed or tunneled explicitly
+			continue
+		}
 		if !exists {
 			mp = &managedPort{}
 			state[port] = mp
@@ -414,27 +454,50 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 
 		mp.LocalhostPort = port
 		mp.Served = true
+		mp.Protocol = served.Protocol
+		mp.Process = served.Process
+
+		config, kind, exists := pm.configs.Get(port)
+		if exists && kind == RangeConfigKind {
+			// served ports within a range become individual ports inheriting the range config
+			mp.Name = config.Name
+			mp.Description = config.Description
+			if !mp.Exposed {
+				mp.OnExposed = getOnExposedAction(config, port)
+			}
+		}
+
+		if served.Protocol != api.PortProtocol_tcp || ignored {
+			// only TCP ports can be exposed through the workspace proxy
+			continue
+		}
+
+		if !exists || config.OnOpen != "ignore" {
+			// Sniffing talks to the port, hence we don't do so for ports the user asked us to ignore. The application
+			// protocol is informational only and never keeps us from exposing a port.
+			mp.ApplicationProtocol = pm.sniffProtocol(ctx, port)
+		}
 
 		autoExposure, autoExposed := pm.autoExposed[port]
 		if autoExposed {
 			mp.AutoExposure = autoExposure.state
+			mp.AutoExposureError = autoExposure.errorMessage()
 			continue
 		}
 
-		var public bool
-		config, kind, exists := pm.configs.Get(mp.LocalhostPort)
+		visibility := api.PortVisibility_private
 		configured := exists && kind == PortConfigKind
 		if mp.Exposed || configured {
-			public = mp.Visibility == api.PortVisibility_public
-		} else {
-			public = exists && config.Visibility == "public"
+			visibility = mp.Visibility
+		} else if exists {
+			visibility = parsePortVisibility(config.Visibility)
 		}
 
-		if mp.Exposed && ((mp.Visibility == api.PortVisibility_public && public) || (mp.Visibility == api.PortVisibility_private && !public)) {
+		if mp.Exposed && mp.Visibility == visibility {
 			continue
 		}
 
-		mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, public).state
+		mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, visibility).state
 	}
 
 	var ports []uint32
@@ -455,24 +518,31 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 }
 
 // clients should guard a call with check whether such port is already exposed or auto exposed
-func (pm *Manager) autoExpose(ctx context.Context, localPort uint32, public bool) *autoExposure {
-	exposing := pm.E.Expose(ctx, localPort, public)
+func (pm *Manager) autoExpose(ctx context.Context, localPort uint32, visibility api.PortVisibility) *autoExposure {
+	exposing := pm.E.Expose(ctx, localPort, visibility)
 	autoExpose := &autoExposure{
-		state:  api.PortAutoExposure_trying,
-		ctx:    ctx,
-		public: public,
+		state:      api.PortAutoExposure_trying,
+		ctx:        ctx,
+		visibility: visibility,
 	}
 	go func() {
 		err := <-exposing
-		if err != nil {
-			if err != context.Canceled {
-				autoExpose.state = api.PortAutoExposure_failed
-				log.WithError(err).WithField("localPort", localPort).Warn("cannot auto-expose port")
-			}
+		if err == context.Canceled {
 			return
 		}
-		autoExpose.state = api.PortAutoExposure_succeeded
-		log.WithField("localPort", localPort).Info("auto-exposed port")
+
+		pm.mu.Lock()
+		if err != nil {
+			autoExpose.state = api.PortAutoExposure_failed
+			autoExpose.err = err
+			log.WithError(err).WithField("localPort", localPort).Warn("cannot auto-expose port")
+		} else {
+			autoExpose.state = api.PortAutoExposure_succeeded
+			log.WithField("localPort", localPort).Info("auto-exposed port")
+		}
+		pm.mu.Unlock()
+		// clients should learn about failures, there won't be an update of the exposed ports for them
+		pm.forceUpdate()
 	}()
 	pm.autoExposed[localPort] = autoExpose
 	log.WithField("localPort", localPort).Info("auto-exposing port")
@@ -487,7 +557,7 @@ func (pm *Manager) RetryAutoExpose(ctx context.Context, localPort uint32) {
 	if !autoExposed || autoExpose.state != api.PortAutoExposure_failed || autoExpose.ctx.Err() != nil {
 		return
 	}
-	pm.autoExpose(autoExpose.ctx, localPort, autoExpose.public)
+	pm.autoExpose(autoExpose.ctx, localPort, autoExpose.visibility)
 	pm.forceUpdate()
 }
 
@@ -513,7 +583,7 @@ func (pm *Manager) autoTunnel(ctx context.Context) {
 	}
 	var descs []*PortTunnelDescription
 	for _, served := range pm.served {
-		if pm.boundInternally(served.Port) {
+		if pm.boundInternally(served.Port) || pm.ignored(served.Port) || served.Protocol != api.PortProtocol_tcp {
 			continue
 		}
 
@@ -537,12 +607,105 @@ func (pm *Manager) autoTunnel(ctx context.Context) {
 	}
 }
 
+// isReady returns true if the port has no readiness probe configured or the probe has succeeded.
+// Clients should guard a call with a lock.
+func (pm *Manager) isReady(ctx context.Context, served ServedPort) bool {
+	if served.Protocol != api.PortProtocol_tcp {
+		return true
+	}
+	config, _, exists := pm.configs.Get(served.Port)
+	if !exists || config.ReadinessProbe == nil {
+		return true
+	}
+
+	probe, exists := pm.probes[served.Port]
+	if exists {
+		return probe.ready
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	probe = &readinessProbe{cancel: cancel}
+	pm.probes[served.Port] = probe
+	go func(port uint32, config *gitpod.ReadinessProbe) {
+		err := pm.prober(ctx, port, config)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.WithError(err).WithField("port", port).Warn("readiness probe failed")
+			}
+			return
+		}
+		log.WithField("port", port).Info("port is ready")
+
+		pm.mu.Lock()
+		probe.ready = true
+		pm.mu.Unlock()
+		pm.forceUpdate()
+	}(served.Port, config.ReadinessProbe)
+	log.WithField("port", served.Port).Info("probing port readiness")
+	return false
+}
+
+// sniffProtocol returns the application protocol of a served port, which is unknown while it is still being detected.
+// Clients should guard a call with a lock.
+func (pm *Manager) sniffProtocol(ctx context.Context, port uint32) api.PortApplicationProtocol {
+	if pm.sniffer == nil {
+		return api.PortApplicationProtocol_unknown
+	}
+	sniffed, exists := pm.sniffed[port]
+	if exists {
+		return sniffed.protocol
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sniffed = &sniffedProtocol{cancel: cancel}
+	pm.sniffed[port] = sniffed
+	go func() {
+		protocol := pm.sniffer(ctx, port)
+		if ctx.Err() != nil {
+			return
+		}
+		log.WithField("port", port).WithField("protocol", protocol).Debug("detected port protocol")
+
+		pm.mu.Lock()
+		sniffed.protocol = protocol
+		pm.mu.Unlock()
+		pm.forceUpdate()
+	}()
+	return api.PortApplicationProtocol_unknown
+}
+
+// updateProbes stops readiness probes and protocol detection of ports which are no longer served,
+// so that they are probed again once served anew.
+func (pm *Manager) updateProbes() {
+	served := make(map[uint32]struct{}, len(pm.served))
+	for _, s := range pm.served {
+		served[s.Port] = struct{}{}
+	}
+	for port, probe := range pm.probes {
+		if _, exists := served[port]; exists {
+			continue
+		}
+		probe.cancel()
+		delete(pm.probes, port)
+	}
+	for port, sniffed := range pm.sniffed {
+		if _, exists := served[port]; exists {
+			continue
+		}
+		sniffed.cancel()
+		delete(pm.sniffed, port)
+	}
+}
+
 func (pm *Manager) updateSlirp() {
 	if pm.Slirp == nil {
 		return
 	}
 
 	for _, served := range pm.served {
+		if served.Protocol != api.PortProtocol_tcp {
+			continue
+		}
 		err := pm.Slirp.Expose(served.Port)
 		if err != nil {
 			log.WithError(err).Debug("cannot expose port for slirp")
@@ -553,6 +716,9 @@ func (pm *Manager) updateSlirp() {
 func (pm *Manager) updateProxies() {
 	servedPortMap := map[uint32]bool{}
 	for _, s := range pm.served {
+		if s.Protocol != api.PortProtocol_tcp {
+			continue
+		}
 		servedPortMap[s.Port] = s.BoundToLocalhost
 	}
 
@@ -571,11 +737,11 @@ func (pm *Manager) updateProxies() {
 	for _, served := range pm.served {
 		localPort := served.Port
 		_, exists := pm.proxies[localPort]
-		if exists || !served.BoundToLocalhost {
+		if exists || !served.BoundToLocalhost || served.Protocol != api.PortProtocol_tcp {
 			continue
 		}
 
-		proxy, err := pm.proxyStarter(localPort)
+		proxy, err := pm.proxyStarter(localPort, served.Address)
 		if err != nil {
 			log.WithError(err).WithField("localPort", localPort).Warn("cannot start localhost proxy")
 			continue
@@ -616,6 +782,13 @@ func (pm *Manager) boundInternally(port uint32) bool {
 	return exists
 }
 
+// ignored returns true if the port is configured to be ignored, i.e. the port is neither
+// reported nor auto-exposed nor auto-tunneled unless the user explicitly asks for it.
+func (pm *Manager) ignored(port uint32) bool {
+	config, _, exists := pm.configs.Get(port)
+	return exists && config.Ignore
+}
+
 // Expose exposes a port
 func (pm *Manager) Expose(ctx context.Context, port uint32) error {
 	unlock := true
@@ -647,8 +820,15 @@ func (pm *Manager) Expose(ctx context.Context, port uint32) error {
 	pm.mu.RUnlock()
 	unlock = false
 
-	public := exists && config.Visibility != "private"
-	err := <-pm.E.Expose(ctx, port, public)
+	// ports in a configured range are public unless configured otherwise
+	visibility := api.PortVisibility_private
+	if exists {
+		visibility = api.PortVisibility_public
+		if config.Visibility != "" {
+			visibility = parsePortVisibility(config.Visibility)
+		}
+	}
+	err := <-pm.E.Expose(ctx, port, visibility)
 	if err != nil && err != context.Canceled {
 		log.WithError(err).WithField("port", port).Error("cannot expose port")
 	}
@@ -765,6 +945,9 @@ func (pm *Manager) getPortStatus(port uint32) *api.PortsStatus {
 		Served:      mp.Served,
 		Description: mp.Description,
 		Name:        mp.Name,
+		Protocol:    mp.Protocol,
+
+		ApplicationProtocol: mp.ApplicationProtocol,
 	}
 	if mp.Exposed && mp.URL != "" {
 		ps.Exposed = &api.ExposedPortInfo{
@@ -774,6 +957,14 @@ func (pm *Manager) getPortStatus(port uint32) *api.PortsStatus {
 		}
 	}
 	ps.AutoExposure = mp.AutoExposure
+	ps.AutoExposureError = mp.AutoExposureError
+	if mp.Process != nil {
+		ps.Process = &api.PortProcessInfo{
+			Pid:     mp.Process.PID,
+			Name:    mp.Process.Name,
+			Cmdline: mp.Process.Cmdline,
+		}
+	}
 	if mp.Tunneled {
 		ps.Tunneled = &api.TunneledPortInfo{
 			TargetPort: mp.TunneledTargetPort,
@@ -784,10 +975,16 @@ func (pm *Manager) getPortStatus(port uint32) *api.PortsStatus {
 	return ps
 }
 
-func startLocalhostProxy(port uint32) (io.Closer, error) {
+// startLocalhostProxy makes a port which is bound to a loopback address available on the workspace IP.
+// The proxy forwards to the exact loopback address, so that services bound to "::1" only are reachable, too.
+func startLocalhostProxy(port uint32, target net.IP) (io.Closer, error) {
 	host := fmt.Sprintf("localhost:%d", port)
+	dsthost := host
+	if target != nil && target.IsLoopback() {
+		dsthost = net.JoinHostPort(target.String(), strconv.FormatUint(uint64(port), 10))
+	}
 
-	dsturl, err := url.Parse("http://" + host)
+	dsturl, err := url.Parse("http://" + dsthost)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot produce proxy destination URL: %w", err)
 	}
@@ -803,7 +1000,7 @@ func startLocalhostProxy(port uint32) (io.Closer, error) {
 		rw.WriteHeader(http.StatusBadGateway)
 	}
 
-	proxyAddr := fmt.Sprintf("%v:%d", workspaceIPAdress, port)
+	proxyAddr := net.JoinHostPort(workspaceIPAdress, strconv.FormatUint(uint64(port), 10))
 	lis, err := net.Listen("tcp", proxyAddr)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot listen on proxy port %d: %w", port, err)
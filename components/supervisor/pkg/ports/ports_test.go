@@ -6,8 +6,10 @@ package ports
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
@@ -49,10 +51,10 @@ func TestPortsUpdateState(t *testing.T) {
 		{
 			Desc: "basic locally served",
 			Changes: []Change{
-				{Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 8080, true}}},
+				{Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 8080, BoundToLocalhost: true}}},
 				{Exposed: []ExposedPort{{LocalPort: 8080, URL: "foobar"}}},
-				{Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 8080, true}, {net.IPv4zero, 60000, false}}},
-				{Served: []ServedPort{{net.IPv4zero, 60000, false}}},
+				{Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 8080, BoundToLocalhost: true}, {Address: net.IPv4zero, Port: 60000}}},
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 60000}}},
 				{Served: []ServedPort{}},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -71,7 +73,7 @@ func TestPortsUpdateState(t *testing.T) {
 		{
 			Desc: "basic globally served",
 			Changes: []Change{
-				{Served: []ServedPort{{net.IPv4zero, 8080, false}}},
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 8080}}},
 				{Served: []ServedPort{}},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -83,11 +85,41 @@ func TestPortsUpdateState(t *testing.T) {
 				{},
 			},
 		},
+		{
+			Desc: "udp served",
+			Changes: []Change{
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 8080, Protocol: api.PortProtocol_udp}}},
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 8080, Protocol: api.PortProtocol_udp}, {Address: net.IPv4zero, Port: 8080}}},
+				{Served: []ServedPort{}},
+			},
+			ExpectedExposure: []ExposedPort{
+				{LocalPort: 8080},
+			},
+			ExpectedUpdates: UpdateExpectation{
+				{},
+				[]*api.PortsStatus{{LocalPort: 8080, Served: true, Protocol: api.PortProtocol_udp}},
+				[]*api.PortsStatus{{LocalPort: 8080, Served: true}},
+				{},
+			},
+		},
+		{
+			Desc: "served port process",
+			Changes: []Change{
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 3000, Process: &ServedPortProcess{PID: 42, Name: "node", Cmdline: []string{"node", "server.js"}}}}},
+			},
+			ExpectedExposure: []ExposedPort{
+				{LocalPort: 3000},
+			},
+			ExpectedUpdates: UpdateExpectation{
+				{},
+				[]*api.PortsStatus{{LocalPort: 3000, Served: true, Process: &api.PortProcessInfo{Pid: 42, Name: "node", Cmdline: []string{"node", "server.js"}}}},
+			},
+		},
 		{
 			Desc: "basic port publically exposed",
 			Changes: []Change{
-				{Exposed: []ExposedPort{{LocalPort: 8080, Public: false, URL: "foobar"}}},
-				{Exposed: []ExposedPort{{LocalPort: 8080, Public: true, URL: "foobar"}}},
+				{Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_private, URL: "foobar"}}},
+				{Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_public, URL: "foobar"}}},
 				{Served: []ServedPort{{Port: 8080}}},
 			},
 			ExpectedUpdates: UpdateExpectation{
@@ -102,7 +134,7 @@ func TestPortsUpdateState(t *testing.T) {
 			InternalPorts: []uint32{8080},
 			Changes: []Change{
 				{Served: []ServedPort{}},
-				{Served: []ServedPort{{net.IPv4zero, 8080, false}}},
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 8080}}},
 			},
 
 			ExpectedExposure: ExposureExpectation(nil),
@@ -119,14 +151,14 @@ func TestPortsUpdateState(t *testing.T) {
 				}},
 				{
 					Exposed: []ExposedPort{
-						{LocalPort: 8080, Public: true, URL: "8080-foobar"},
-						{LocalPort: 9229, Public: false, URL: "9229-foobar"},
+						{LocalPort: 8080, Visibility: api.PortVisibility_public, URL: "8080-foobar"},
+						{LocalPort: 9229, Visibility: api.PortVisibility_private, URL: "9229-foobar"},
 					},
 				},
 				{
 					Served: []ServedPort{
-						{net.IPv4zero, 8080, false},
-						{net.IPv4(127, 0, 0, 1), 9229, true},
+						{Address: net.IPv4zero, Port: 8080},
+						{Address: net.IPv4(127, 0, 0, 1), Port: 9229, BoundToLocalhost: true},
 					},
 				},
 			},
@@ -156,9 +188,9 @@ func TestPortsUpdateState(t *testing.T) {
 						Port:   "4000-5000",
 					}},
 				}},
-				{Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 4040, true}}},
-				{Exposed: []ExposedPort{{LocalPort: 4040, Public: true, URL: "4040-foobar"}}},
-				{Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 4040, true}, {net.IPv4zero, 60000, false}}},
+				{Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 4040, BoundToLocalhost: true}}},
+				{Exposed: []ExposedPort{{LocalPort: 4040, Visibility: api.PortVisibility_public, URL: "4040-foobar"}}},
+				{Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 4040, BoundToLocalhost: true}, {Address: net.IPv4zero, Port: 60000}}},
 			},
 			ExpectedExposure: []ExposedPort{
 				{LocalPort: 4040},
@@ -174,6 +206,53 @@ func TestPortsUpdateState(t *testing.T) {
 				},
 			},
 		},
+		{
+			Desc: "serving ports from a named port range",
+			Changes: []Change{
+				{Config: &ConfigChange{
+					instance: []*gitpod.PortsItems{{
+						Port:        "4000-5000",
+						Name:        "worker-{port}",
+						Description: "Worker {port}",
+						OnOpen:      "open-preview",
+					}},
+				}},
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 4040}}},
+				{Exposed: []ExposedPort{{LocalPort: 4040, URL: "4040-foobar"}}},
+			},
+			ExpectedExposure: []ExposedPort{
+				{LocalPort: 4040},
+			},
+			ExpectedUpdates: UpdateExpectation{
+				{},
+				[]*api.PortsStatus{{LocalPort: 4040, Served: true, Name: "worker-4040", Description: "Worker 4040"}},
+				[]*api.PortsStatus{{LocalPort: 4040, Served: true, Name: "worker-4040", Description: "Worker 4040", Exposed: &api.ExposedPortInfo{Visibility: api.PortVisibility_private, Url: "4040-foobar", OnExposed: api.OnPortExposedAction_open_preview}}},
+			},
+		},
+		{
+			Desc: "ignored ports",
+			Changes: []Change{
+				{Config: &ConfigChange{
+					instance: []*gitpod.PortsItems{
+						{Port: 9229, Ignore: true},
+						{Port: "4000-5000", Ignore: true},
+					},
+				}},
+				{Served: []ServedPort{{Address: net.IPv4zero, Port: 9229}, {Address: net.IPv4zero, Port: 4040}, {Address: net.IPv4zero, Port: 8080}}},
+				{Exposed: []ExposedPort{{LocalPort: 4040, URL: "4040-foobar"}}},
+			},
+			ExpectedExposure: []ExposedPort{
+				{LocalPort: 8080},
+			},
+			ExpectedUpdates: UpdateExpectation{
+				{},
+				[]*api.PortsStatus{{LocalPort: 8080, Served: true}},
+				[]*api.PortsStatus{
+					{LocalPort: 4040, Served: true, Exposed: &api.ExposedPortInfo{Visibility: api.PortVisibility_private, Url: "4040-foobar", OnExposed: api.OnPortExposedAction_notify}},
+					{LocalPort: 8080, Served: true},
+				},
+			},
+		},
 		{
 			Desc: "auto expose configured ports",
 			Changes: []Change{
@@ -183,29 +262,29 @@ func TestPortsUpdateState(t *testing.T) {
 					}},
 				},
 				{
-					Exposed: []ExposedPort{{LocalPort: 8080, Public: false, URL: "foobar"}},
+					Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_private, URL: "foobar"}},
 				},
 				{
-					Exposed: []ExposedPort{{LocalPort: 8080, Public: true, URL: "foobar"}},
+					Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_public, URL: "foobar"}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 8080, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 8080, BoundToLocalhost: true}},
 				},
 				{
-					Exposed: []ExposedPort{{LocalPort: 8080, Public: true, URL: "foobar"}},
+					Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_public, URL: "foobar"}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 8080, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 8080, BoundToLocalhost: true}},
 				},
 				{
 					Served: []ServedPort{},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 8080, false}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 8080}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
-				{LocalPort: 8080, Public: false},
+				{LocalPort: 8080, Visibility: api.PortVisibility_private},
 			},
 			ExpectedUpdates: UpdateExpectation{
 				{},
@@ -221,7 +300,7 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "starting multiple proxies for the same served event",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 8080, true}, {net.IPv4zero, 3000, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 8080, BoundToLocalhost: true}, {Address: net.IPv4zero, Port: 3000, BoundToLocalhost: true}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -245,10 +324,10 @@ func TestPortsUpdateState(t *testing.T) {
 					}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 8080, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 8080}},
 				},
 				{
-					Exposed: []ExposedPort{{LocalPort: 8080, Public: false, URL: "foobar"}},
+					Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_private, URL: "foobar"}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -265,13 +344,13 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served locally and then globally too, prefer globally (exposed in between)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}, {net.IPv4zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}, {Address: net.IPv4zero, Port: 5900}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
@@ -290,10 +369,10 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served locally and then globally too, prefer globally (exposed after)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}, {net.IPv4zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}, {Address: net.IPv4zero, Port: 5900}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
@@ -315,13 +394,13 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served globally and then locally too, prefer globally (exposed in between)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}, {net.IPv4(127, 0, 0, 1), 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}, {Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -337,10 +416,10 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served globally and then locally too, prefer globally (exposed after)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}, {net.IPv4(127, 0, 0, 1), 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}, {Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
@@ -359,13 +438,13 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served locally on ip4 and then locally on ip6 too, prefer first (exposed in between)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}, {net.IPv6zero, 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}, {Address: net.IPv6zero, Port: 5900, BoundToLocalhost: true}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -381,10 +460,10 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served locally on ip4 and then locally on ip6 too, prefer first (exposed after)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4(127, 0, 0, 1), 5900, true}, {net.IPv6zero, 5900, true}},
+					Served: []ServedPort{{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true}, {Address: net.IPv6zero, Port: 5900, BoundToLocalhost: true}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
@@ -403,13 +482,13 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served locally on ip4 and then globally on ip6 too, prefer first (exposed in between)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}, {net.IPv6zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}, {Address: net.IPv6zero, Port: 5900}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -425,10 +504,10 @@ func TestPortsUpdateState(t *testing.T) {
 			Desc: "the same port served locally on ip4 and then globally on ip6 too, prefer first (exposed after)",
 			Changes: []Change{
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 5900, false}, {net.IPv6zero, 5900, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 5900}, {Address: net.IPv6zero, Port: 5900}},
 				},
 				{
 					Exposed: []ExposedPort{{LocalPort: 5900, URL: "foobar"}},
@@ -452,10 +531,10 @@ func TestPortsUpdateState(t *testing.T) {
 					}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 8080, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 8080}},
 				},
 				{
-					Exposed: []ExposedPort{{LocalPort: 8080, Public: false, URL: "foobar"}},
+					Exposed: []ExposedPort{{LocalPort: 8080, Visibility: api.PortVisibility_private, URL: "foobar"}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -477,10 +556,10 @@ func TestPortsUpdateState(t *testing.T) {
 					}},
 				},
 				{
-					Served: []ServedPort{{net.IPv4zero, 3000, false}},
+					Served: []ServedPort{{Address: net.IPv4zero, Port: 3000}},
 				},
 				{
-					Exposed: []ExposedPort{{LocalPort: 3000, Public: false, URL: "foobar"}},
+					Exposed: []ExposedPort{{LocalPort: 3000, Visibility: api.PortVisibility_private, URL: "foobar"}},
 				},
 			},
 			ExpectedExposure: []ExposedPort{
@@ -520,9 +599,10 @@ func TestPortsUpdateState(t *testing.T) {
 				pm    = NewManager(exposed, served, config, tunneled, nil, test.InternalPorts...)
 				updts [][]*api.PortsStatus
 			)
-			pm.proxyStarter = func(port uint32) (io.Closer, error) {
+			pm.proxyStarter = func(port uint32, target net.IP) (io.Closer, error) {
 				return io.NopCloser(nil), nil
 			}
+			pm.sniffer = nil
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -642,13 +722,13 @@ func (tep *testExposedPorts) Observe(ctx context.Context) (<-chan []ExposedPort,
 func (tep *testExposedPorts) Run(ctx context.Context) {
 }
 
-func (tep *testExposedPorts) Expose(ctx context.Context, local uint32, public bool) <-chan error {
+func (tep *testExposedPorts) Expose(ctx context.Context, local uint32, visibility api.PortVisibility) <-chan error {
 	tep.mu.Lock()
 	defer tep.mu.Unlock()
 
 	tep.Exposures = append(tep.Exposures, ExposedPort{
-		LocalPort: local,
-		Public:    public,
+		LocalPort:  local,
+		Visibility: visibility,
 	})
 	return nil
 }
@@ -685,9 +765,10 @@ func TestPortsConcurrentSubscribe(t *testing.T) {
 		}
 		pm = NewManager(exposed, served, config, tunneled, nil)
 	)
-	pm.proxyStarter = func(local uint32) (io.Closer, error) {
+	pm.proxyStarter = func(local uint32, target net.IP) (io.Closer, error) {
 		return io.NopCloser(nil), nil
 	}
+	pm.sniffer = nil
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -745,3 +826,39 @@ func TestPortsConcurrentSubscribe(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestLocalhostProxyIPv6(t *testing.T) {
+	lis, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback is not available: %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	})}
+	go srv.Serve(lis)
+	defer srv.Close()
+	port := uint32(lis.Addr().(*net.TCPAddr).Port)
+
+	defaultIP := workspaceIPAdress
+	workspaceIPAdress = "127.0.0.1"
+	defer func() { workspaceIPAdress = defaultIP }()
+
+	proxy, err := startLocalhostProxy(port, net.IPv6loopback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := fmt.Sprintf("localhost:%d", port); string(body) != exp {
+		t.Errorf("unexpected host: want %s, got %s", exp, body)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// sniffTimeout is the timeout of each of the sniffing attempts
+const sniffTimeout = 1 * time.Second
+
+var (
+	// http2Preface is the HTTP/2 client connection preface followed by an empty SETTINGS frame
+	http2Preface = append([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"), 0, 0, 0, 4, 0, 0, 0, 0, 0)
+	http1Prefix  = []byte("HTTP/")
+)
+
+const http2FrameTypeSettings = 0x4
+
+// sniffPortProtocol classifies the application protocol spoken on a local TCP port.
+// HTTPS is detected by a successful TLS handshake. On plain connections we send the HTTP/2 preface:
+// HTTP/1 servers reject it with an HTTP response, while h2c servers - which in practice are gRPC servers -
+// answer with a SETTINGS frame. Everything else is considered raw TCP.
+func sniffPortProtocol(ctx context.Context, port uint32) api.PortApplicationProtocol {
+	addr := fmt.Sprintf("localhost:%d", port)
+
+	isTLS, err := sniffTLS(ctx, addr)
+	if err != nil {
+		return api.PortApplicationProtocol_unknown
+	}
+	if isTLS {
+		return api.PortApplicationProtocol_https
+	}
+
+	resp, err := sniffHTTP2Preface(ctx, addr)
+	if err != nil {
+		return api.PortApplicationProtocol_unknown
+	}
+	return classifyPrefaceResponse(resp)
+}
+
+// sniffTLS attempts a TLS handshake. An error is returned only if we cannot connect at all.
+func sniffTLS(ctx context.Context, addr string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, sniffTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false, err
+	}
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+	defer tlsConn.Close()
+
+	return tlsConn.HandshakeContext(ctx) == nil, nil
+}
+
+// sniffHTTP2Preface sends the HTTP/2 preface and returns the beginning of the response, if any.
+// An error is returned only if we cannot connect at all.
+func sniffHTTP2Preface(ctx context.Context, addr string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, sniffTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	_, err = conn.Write(http2Preface)
+	if err != nil {
+		return nil, nil
+	}
+	// an HTTP/2 frame header is 9 bytes long, which is more than the HTTP/1 prefix
+	buf := make([]byte, 9)
+	n, _ := io.ReadFull(conn, buf)
+	return buf[:n], nil
+}
+
+func classifyPrefaceResponse(resp []byte) api.PortApplicationProtocol {
+	if bytes.HasPrefix(resp, http1Prefix) {
+		return api.PortApplicationProtocol_http
+	}
+	if len(resp) == 9 && resp[3] == http2FrameTypeSettings {
+		return api.PortApplicationProtocol_grpc
+	}
+	return api.PortApplicationProtocol_raw
+}
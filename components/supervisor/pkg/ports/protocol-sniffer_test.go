@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestSniffPortProtocol(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		Desc        string
+		Serve       func(t *testing.T) (addr net.Addr, stop func())
+		Expectation api.PortApplicationProtocol
+	}{
+		{
+			Desc: "http",
+			Serve: func(t *testing.T) (net.Addr, func()) {
+				srv := httptest.NewServer(handler)
+				return srv.Listener.Addr(), srv.Close
+			},
+			Expectation: api.PortApplicationProtocol_http,
+		},
+		{
+			Desc: "https",
+			Serve: func(t *testing.T) (net.Addr, func()) {
+				srv := httptest.NewTLSServer(handler)
+				return srv.Listener.Addr(), srv.Close
+			},
+			Expectation: api.PortApplicationProtocol_https,
+		},
+		{
+			Desc: "grpc",
+			Serve: func(t *testing.T) (net.Addr, func()) {
+				l := listenTCP(t)
+				srv := grpc.NewServer()
+				go func() { _ = srv.Serve(l) }()
+				return l.Addr(), srv.Stop
+			},
+			Expectation: api.PortApplicationProtocol_grpc,
+		},
+		{
+			Desc: "raw tcp",
+			Serve: func(t *testing.T) (net.Addr, func()) {
+				l := listenTCP(t)
+				go func() {
+					for {
+						conn, err := l.Accept()
+						if err != nil {
+							return
+						}
+						_, _ = conn.Write([]byte("+OK ready\r\n"))
+						conn.Close()
+					}
+				}()
+				return l.Addr(), func() { l.Close() }
+			},
+			Expectation: api.PortApplicationProtocol_raw,
+		},
+		{
+			Desc: "not served",
+			Serve: func(t *testing.T) (net.Addr, func()) {
+				l := listenTCP(t)
+				l.Close()
+				return l.Addr(), func() {}
+			},
+			Expectation: api.PortApplicationProtocol_unknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			addr, stop := test.Serve(t)
+			defer stop()
+
+			act := sniffPortProtocol(context.Background(), uint32(addr.(*net.TCPAddr).Port))
+			if act != test.Expectation {
+				t.Errorf("unexpected result: want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func listenTCP(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
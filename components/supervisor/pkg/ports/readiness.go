@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+)
+
+const (
+	defaultReadinessProbePath    = "/"
+	defaultReadinessProbeStatus  = http.StatusOK
+	defaultReadinessProbeTimeout = 1 * time.Second
+
+	readinessProbeInterval = 1 * time.Second
+)
+
+// probePortReadiness sends HTTP requests to the port until it responds with the expected status code
+// or the context is canceled.
+func probePortReadiness(ctx context.Context, port uint32, probe *gitpod.ReadinessProbe) error {
+	var (
+		path    = defaultReadinessProbePath
+		status  = defaultReadinessProbeStatus
+		timeout = defaultReadinessProbeTimeout
+	)
+	if probe.Path != "" {
+		path = probe.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+	}
+	if probe.Status != 0 {
+		status = probe.Status
+	}
+	if probe.Timeout > 0 {
+		timeout = time.Duration(probe.Timeout) * time.Second
+	}
+
+	var (
+		url    = fmt.Sprintf("http://localhost:%d%s", port, path)
+		client = &http.Client{Timeout: timeout}
+	)
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode == status {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(readinessProbeInterval):
+		}
+	}
+}
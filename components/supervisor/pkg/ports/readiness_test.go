@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+)
+
+func TestProbePortReadiness(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Probe       *gitpod.ReadinessProbe
+		Expectation error
+	}{
+		{
+			Desc:  "default probe",
+			Probe: &gitpod.ReadinessProbe{},
+		},
+		{
+			Desc:  "custom path and status",
+			Probe: &gitpod.ReadinessProbe{Path: "healthz", Status: http.StatusNoContent},
+		},
+		{
+			Desc:        "unexpected status",
+			Probe:       &gitpod.ReadinessProbe{Path: "/healthz"},
+			Expectation: context.DeadlineExceeded,
+		},
+		{
+			Desc:        "unknown path",
+			Probe:       &gitpod.ReadinessProbe{Path: "/unknown", Status: http.StatusNoContent},
+			Expectation: context.DeadlineExceeded,
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.WriteHeader(http.StatusOK)
+		case "/healthz":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	port := uint32(srv.Listener.Addr().(*net.TCPAddr).Port)
+
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			act := probePortReadiness(ctx, port, test.Probe)
+			if act != test.Expectation {
+				t.Errorf("unexpected result: want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// sockDiagByFamily is the SOCK_DIAG_BY_FAMILY netlink message type
+	sockDiagByFamily = 20
+	// tcpListen is the TCP_LISTEN socket state
+	tcpListen = 10
+	// tcpClose is the TCP_CLOSE socket state, which unconnected UDP sockets are in
+	tcpClose = 7
+
+	// sock_diag multicast groups (SKNLGRP_*) which announce destroyed sockets. There are no groups
+	// which announce new sockets, hence new listeners can only be found by dumping the sockets.
+	sknlgrpInetTCPDestroy  = 1
+	sknlgrpInetUDPDestroy  = 2
+	sknlgrpInet6TCPDestroy = 3
+	sknlgrpInet6UDPDestroy = 4
+
+	sizeofInetDiagReqV2 = int(unsafe.Sizeof(inetDiagReqV2{}))
+	sizeofInetDiagMsg   = int(unsafe.Sizeof(inetDiagMsg{}))
+)
+
+// inetDiagSockID mirrors struct inet_diag_sockid. Ports and addresses are in network byte order.
+type inetDiagSockID struct {
+	SPort  [2]byte
+	DPort  [2]byte
+	Src    [16]byte
+	Dst    [16]byte
+	If     uint32
+	Cookie [2]uint32
+}
+
+// inetDiagReqV2 mirrors struct inet_diag_req_v2.
+type inetDiagReqV2 struct {
+	Family   uint8
+	Protocol uint8
+	Ext      uint8
+	Pad      uint8
+	States   uint32
+	ID       inetDiagSockID
+}
+
+// inetDiagMsg mirrors struct inet_diag_msg.
+type inetDiagMsg struct {
+	Family  uint8
+	State   uint8
+	Timer   uint8
+	Retrans uint8
+	ID      inetDiagSockID
+	Expires uint32
+	RQueue  uint32
+	WQueue  uint32
+	UID     uint32
+	Inode   uint32
+}
+
+// NewServedPortsObserver produces a netlink based observer if NETLINK_SOCK_DIAG is available,
+// and falls back to polling "/proc" otherwise.
+func NewServedPortsObserver(refreshInterval time.Duration) ServedPortsObserver {
+	_, err := dumpListeningSockets(unix.AF_INET, unix.IPPROTO_TCP)
+	if err != nil {
+		log.WithError(err).Warn("netlink sock_diag is not available - falling back to polling /proc")
+		return &PollingServedPortsObserver{
+			RefreshInterval:    refreshInterval,
+			MinRefreshInterval: minRefreshInterval,
+		}
+	}
+	return &NetlinkServedPortsObserver{
+		RefreshInterval: refreshInterval,
+	}
+}
+
+// NetlinkServedPortsObserver uses NETLINK_SOCK_DIAG to observe port changes. Instead of parsing
+// "/proc/net/{tcp,udp}*" it asks the kernel for listening sockets directly, and refreshes immediately
+// whenever the kernel announces a destroyed socket. Announcements require CAP_NET_ADMIN - without
+// them the observer refreshes on RefreshInterval only.
+//
+// The detection of new listeners is not push-based: the kernel doesn't announce them, hence they are
+// only found on the next refresh, i.e. after RefreshInterval at most, or earlier if a socket was destroyed.
+type NetlinkServedPortsObserver struct {
+	RefreshInterval time.Duration
+
+	dump      func(family, protocol uint8) ([]ServedPort, error)
+	events    func(ctx context.Context) <-chan struct{}
+	processes *portProcessResolver
+}
+
+// Observe starts observing the served ports until the context is canceled.
+// Unlike the polling observer, updates are only produced if the list of served ports has changed.
+func (n *NetlinkServedPortsObserver) Observe(ctx context.Context) (<-chan []ServedPort, <-chan error) {
+	if n.dump == nil {
+		n.dump = dumpListeningSockets
+	}
+	if n.events == nil {
+		n.events = observeSocketDestroyEvents
+	}
+	if n.processes == nil {
+		n.processes = newPortProcessResolver("/proc")
+	}
+
+	var (
+		errchan = make(chan error, 1)
+		reschan = make(chan []ServedPort)
+		ticker  = time.NewTicker(n.RefreshInterval)
+		events  = n.events(ctx)
+	)
+
+	go func() {
+		defer close(errchan)
+		defer close(reschan)
+		defer ticker.Stop()
+
+		var (
+			last []ServedPort
+			// dumps holds the last successful dump per protocol and family. If a dump fails we use its
+			// previous result instead of reporting the ports it found before as gone.
+			dumps = make(map[[2]uint8][]ServedPort)
+		)
+		for {
+			var (
+				visited = make(map[string]struct{})
+				ports   = make([]ServedPort, 0)
+			)
+			for _, protocol := range []uint8{unix.IPPROTO_TCP, unix.IPPROTO_UDP} {
+				for _, family := range []uint8{unix.AF_INET, unix.AF_INET6} {
+					ps, err := n.dump(family, protocol)
+					if err != nil {
+						errchan <- err
+						ps = dumps[[2]uint8{family, protocol}]
+					} else {
+						dumps[[2]uint8{family, protocol}] = ps
+					}
+					for _, port := range ps {
+						key := fmt.Sprintf("%s:%d/%s", hex.EncodeToString(port.Address), port.Port, port.Protocol)
+						_, exists := visited[key]
+						if exists {
+							continue
+						}
+						visited[key] = struct{}{}
+						ports = append(ports, port)
+					}
+				}
+			}
+
+			n.processes.Resolve(ports)
+			if !reflect.DeepEqual(last, ports) {
+				last = ports
+				select {
+				case reschan <- ports:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-events:
+			}
+		}
+	}()
+
+	return reschan, errchan
+}
+
+// dumpListeningSockets lists all listening TCP or unconnected UDP sockets of the given address family using inet_diag.
+func dumpListeningSockets(family, protocol uint8) ([]ServedPort, error) {
+	var (
+		states      uint32 = 1 << tcpListen
+		apiProtocol        = api.PortProtocol_tcp
+	)
+	if protocol == unix.IPPROTO_UDP {
+		states = 1 << tcpClose
+		apiProtocol = api.PortProtocol_udp
+	}
+
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot open sock_diag socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	req := make([]byte, unix.SizeofNlMsghdr+sizeofInetDiagReqV2)
+	*(*unix.NlMsghdr)(unsafe.Pointer(&req[0])) = unix.NlMsghdr{
+		Len:   uint32(len(req)),
+		Type:  sockDiagByFamily,
+		Flags: unix.NLM_F_REQUEST | unix.NLM_F_DUMP,
+		Seq:   1,
+	}
+	*(*inetDiagReqV2)(unsafe.Pointer(&req[unix.SizeofNlMsghdr])) = inetDiagReqV2{
+		Family:   family,
+		Protocol: protocol,
+		States:   states,
+	}
+	err = unix.Sendto(fd, req, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot send sock_diag request: %w", err)
+	}
+
+	var (
+		ports []ServedPort
+		buf   = make([]byte, 8*unix.Getpagesize())
+	)
+	for {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot receive sock_diag response: %w", err)
+		}
+		ps, done, err := parseInetDiagMessages(buf[:n], apiProtocol)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, ps...)
+		if done {
+			break
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Port < ports[j].Port
+	})
+	return ports, nil
+}
+
+// parseInetDiagMessages parses a sock_diag dump response. done is true once the end of the dump was reached.
+func parseInetDiagMessages(data []byte, protocol api.PortProtocol) (ports []ServedPort, done bool, err error) {
+	msgs, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return nil, false, xerrors.Errorf("cannot parse sock_diag response: %w", err)
+	}
+	for _, m := range msgs {
+		switch m.Header.Type {
+		case unix.NLMSG_DONE:
+			return ports, true, nil
+		case unix.NLMSG_ERROR:
+			if len(m.Data) < 4 {
+				return nil, false, xerrors.Errorf("sock_diag request failed")
+			}
+			// struct nlmsgerr is in host byte order
+			errno := -*(*int32)(unsafe.Pointer(&m.Data[0]))
+			if errno == 0 {
+				continue
+			}
+			return nil, false, xerrors.Errorf("sock_diag request failed: %w", syscall.Errno(errno))
+		case sockDiagByFamily:
+		default:
+			continue
+		}
+		if len(m.Data) < sizeofInetDiagMsg {
+			continue
+		}
+
+		msg := (*inetDiagMsg)(unsafe.Pointer(&m.Data[0]))
+		var ip net.IP
+		switch msg.Family {
+		case unix.AF_INET:
+			ip = make(net.IP, net.IPv4len)
+		case unix.AF_INET6:
+			ip = make(net.IP, net.IPv6len)
+		default:
+			continue
+		}
+		copy(ip, msg.ID.Src[:])
+
+		ports = append(ports, ServedPort{
+			BoundToLocalhost: ip.IsLoopback(),
+			Address:          ip,
+			Port:             uint32(binary.BigEndian.Uint16(msg.ID.SPort[:])),
+			Protocol:         protocol,
+			Inode:            uint64(msg.Inode),
+		})
+	}
+	return ports, false, nil
+}
+
+// observeSocketDestroyEvents subscribes to the sock_diag destroy multicast groups and signals
+// on the returned channel whenever a TCP or UDP socket is destroyed. If the subscription is not permitted
+// the channel never fires.
+func observeSocketDestroyEvents(ctx context.Context) <-chan struct{} {
+	events := make(chan struct{}, 1)
+
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		log.WithError(err).Debug("cannot open sock_diag socket - not observing socket destroy events")
+		return events
+	}
+	err = unix.Bind(fd, &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: 1<<(sknlgrpInetTCPDestroy-1) | 1<<(sknlgrpInetUDPDestroy-1) |
+			1<<(sknlgrpInet6TCPDestroy-1) | 1<<(sknlgrpInet6UDPDestroy-1),
+	})
+	if err == nil {
+		// we wake up regularly to check if the context was canceled
+		err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1})
+	}
+	if err != nil {
+		unix.Close(fd)
+		log.WithError(err).Debug("cannot subscribe to socket destroy events - relying on refresh interval only")
+		return events
+	}
+
+	go func() {
+		defer unix.Close(fd)
+
+		buf := make([]byte, unix.Getpagesize())
+		for ctx.Err() == nil {
+			_, _, err := unix.Recvfrom(fd, buf, 0)
+			if err == unix.EAGAIN || err == unix.EINTR {
+				continue
+			}
+			if err != nil {
+				log.WithError(err).Warn("cannot receive socket destroy events")
+				return
+			}
+
+			select {
+			case events <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return events
+}
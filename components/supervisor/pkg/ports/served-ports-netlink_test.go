@@ -0,0 +1,255 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/sys/unix"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestNetlinkObserve(t *testing.T) {
+	type Expectation [][]ServedPort
+	type Dump struct {
+		Family   uint8
+		Protocol uint8
+	}
+	var (
+		tcp4 = Dump{unix.AF_INET, unix.IPPROTO_TCP}
+		tcp6 = Dump{unix.AF_INET6, unix.IPPROTO_TCP}
+		udp4 = Dump{unix.AF_INET, unix.IPPROTO_UDP}
+		udp6 = Dump{unix.AF_INET6, unix.IPPROTO_UDP}
+	)
+	tests := []struct {
+		Name        string
+		Dumps       []map[Dump][]ServedPort
+		Failures    map[int]Dump
+		Expectation Expectation
+	}{
+		{
+			Name: "deduplicates and reports changes only",
+			Dumps: []map[Dump][]ServedPort{
+				{
+					tcp4: {
+						{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
+						{Address: net.IPv4zero, Port: 6080},
+						{Address: net.IPv4zero, Port: 6080},
+					},
+					tcp6: {
+						{Address: net.IPv6zero, Port: 22999},
+					},
+				},
+				{
+					tcp4: {
+						{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
+						{Address: net.IPv4zero, Port: 6080},
+					},
+					tcp6: {
+						{Address: net.IPv6zero, Port: 22999},
+					},
+				},
+				{
+					tcp6: {
+						{Address: net.IPv6zero, Port: 22999},
+					},
+				},
+				{},
+			},
+			Expectation: Expectation{
+				{
+					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
+					{Address: net.IPv4zero, Port: 6080},
+					{Address: net.IPv6zero, Port: 22999},
+				},
+				{
+					{Address: net.IPv6zero, Port: 22999},
+				},
+				{},
+			},
+		},
+		{
+			Name: "UDP ports",
+			Dumps: []map[Dump][]ServedPort{
+				{
+					tcp4: {
+						{Address: net.IPv4zero, Port: 8080},
+					},
+					udp4: {
+						{Address: net.IPv4zero, Port: 8080, Protocol: api.PortProtocol_udp},
+						{Address: net.IPv4zero, Port: 8080, Protocol: api.PortProtocol_udp},
+					},
+					udp6: {
+						{Address: net.IPv6zero, Port: 60001, Protocol: api.PortProtocol_udp},
+					},
+				},
+			},
+			Expectation: Expectation{
+				{
+					{Address: net.IPv4zero, Port: 8080},
+					{Address: net.IPv4zero, Port: 8080, Protocol: api.PortProtocol_udp},
+					{Address: net.IPv6zero, Port: 60001, Protocol: api.PortProtocol_udp},
+				},
+			},
+		},
+		{
+			Name: "failed dump keeps its previous result",
+			Dumps: []map[Dump][]ServedPort{
+				{
+					tcp4: {
+						{Address: net.IPv4zero, Port: 8080},
+					},
+					tcp6: {
+						{Address: net.IPv6zero, Port: 3000},
+					},
+				},
+				{
+					tcp6: {},
+				},
+				{},
+			},
+			Failures: map[int]Dump{1: tcp4},
+			Expectation: Expectation{
+				{
+					{Address: net.IPv4zero, Port: 8080},
+					{Address: net.IPv6zero, Port: 3000},
+				},
+				{
+					{Address: net.IPv4zero, Port: 8080},
+				},
+				{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				round  int
+				events = make(chan struct{})
+			)
+			obs := NetlinkServedPortsObserver{
+				RefreshInterval: time.Hour,
+				dump: func(family, protocol uint8) ([]ServedPort, error) {
+					if round >= len(test.Dumps) {
+						return nil, nil
+					}
+					d := Dump{family, protocol}
+					res := test.Dumps[round][d]
+					failed := test.Failures[round] == d
+					if d == udp6 {
+						round++
+					}
+					if failed {
+						return nil, errors.New("cannot dump sockets")
+					}
+					return res, nil
+				},
+				events: func(ctx context.Context) <-chan struct{} {
+					return events
+				},
+				processes: newPortProcessResolver(t.TempDir()),
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			updates, errs := obs.Observe(ctx)
+			go func() {
+				for range errs {
+				}
+			}()
+			go func() {
+				for i := 1; i < len(test.Dumps); i++ {
+					select {
+					case events <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			var act Expectation
+			for up := range updates {
+				act = append(act, up)
+				if len(act) == len(test.Expectation) {
+					cancel()
+				}
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseInetDiagMessages(t *testing.T) {
+	msg := func(family uint8, addr net.IP, port uint16) []byte {
+		res := make([]byte, unix.SizeofNlMsghdr+sizeofInetDiagMsg)
+		*(*unix.NlMsghdr)(unsafe.Pointer(&res[0])) = unix.NlMsghdr{
+			Len:  uint32(len(res)),
+			Type: sockDiagByFamily,
+		}
+		m := inetDiagMsg{Family: family, State: tcpListen}
+		m.ID.SPort = [2]byte{byte(port >> 8), byte(port)}
+		copy(m.ID.Src[:], addr)
+		*(*inetDiagMsg)(unsafe.Pointer(&res[unix.SizeofNlMsghdr])) = m
+		return res
+	}
+	done := make([]byte, unix.SizeofNlMsghdr+4)
+	*(*unix.NlMsghdr)(unsafe.Pointer(&done[0])) = unix.NlMsghdr{
+		Len:  uint32(len(done)),
+		Type: unix.NLMSG_DONE,
+	}
+
+	var input []byte
+	input = append(input, msg(unix.AF_INET, net.IPv4(127, 0, 0, 1).To4(), 5900)...)
+	input = append(input, msg(unix.AF_INET, net.IPv4zero.To4(), 23000)...)
+	input = append(input, msg(unix.AF_INET6, net.IPv6loopback, 22999)...)
+	input = append(input, done...)
+
+	type Expectation struct {
+		Ports []ServedPort
+		Done  bool
+		Error error
+	}
+	var act Expectation
+	act.Ports, act.Done, act.Error = parseInetDiagMessages(input, api.PortProtocol_tcp)
+
+	exp := Expectation{
+		Ports: []ServedPort{
+			{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
+			{Address: net.IPv4zero, Port: 23000},
+			{Address: net.IPv6loopback, Port: 22999, BoundToLocalhost: true},
+		},
+		Done: true,
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseInetDiagMessagesError(t *testing.T) {
+	res := make([]byte, unix.SizeofNlMsghdr+unix.SizeofNlMsgerr)
+	*(*unix.NlMsghdr)(unsafe.Pointer(&res[0])) = unix.NlMsghdr{
+		Len:  uint32(len(res)),
+		Type: unix.NLMSG_ERROR,
+	}
+	// the kernel writes the negative errno in host byte order
+	*(*int32)(unsafe.Pointer(&res[unix.SizeofNlMsghdr])) = -int32(unix.EPERM)
+
+	_, _, err := parseInetDiagMessages(res, api.PortProtocol_tcp)
+	if !errors.Is(err, syscall.EPERM) {
+		t.Errorf("unexpected error: expected %v, got %v", syscall.EPERM, err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ServedPortProcess describes the process which opened a served port.
+type ServedPortProcess struct {
+	PID     int64
+	Name    string
+	Cmdline []string
+}
+
+// portProcessResolver attributes served ports to processes by finding the process
+// which holds the port's socket inode open, i.e. has a "/proc/<pid>/fd/<fd> -> socket:[<inode>]" link.
+type portProcessResolver struct {
+	ProcFS string
+
+	cache map[uint64]*ServedPortProcess
+}
+
+func newPortProcessResolver(procfs string) *portProcessResolver {
+	return &portProcessResolver{
+		ProcFS: procfs,
+		cache:  make(map[uint64]*ServedPortProcess),
+	}
+}
+
+// Resolve sets the process of all ports whose socket inode is known.
+// Processes we cannot inspect, e.g. because they belong to another user, remain unknown.
+func (r *portProcessResolver) Resolve(ports []ServedPort) {
+	var (
+		wanted = make(map[uint64]struct{})
+		cache  = make(map[uint64]*ServedPortProcess)
+	)
+	for _, port := range ports {
+		if port.Inode == 0 {
+			continue
+		}
+		if proc, ok := r.cache[port.Inode]; ok {
+			cache[port.Inode] = proc
+			continue
+		}
+		wanted[port.Inode] = struct{}{}
+	}
+	if len(wanted) > 0 {
+		found := r.findProcesses(wanted)
+		for inode := range wanted {
+			// we remember processes we could not find, too, so that we don't search for them over and over again
+			cache[inode] = found[inode]
+		}
+	}
+	// sockets which are gone are dropped from the cache
+	r.cache = cache
+
+	for i := range ports {
+		ports[i].Process = r.cache[ports[i].Inode]
+	}
+}
+
+func (r *portProcessResolver) findProcesses(inodes map[uint64]struct{}) map[uint64]*ServedPortProcess {
+	res := make(map[uint64]*ServedPortProcess)
+
+	procs, err := os.ReadDir(r.ProcFS)
+	if err != nil {
+		return res
+	}
+	for _, p := range procs {
+		pid, err := strconv.ParseInt(p.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(r.ProcFS, p.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		var proc *ServedPortProcess
+		for _, fd := range fds {
+			inode, ok := socketInode(filepath.Join(fdDir, fd.Name()))
+			if !ok {
+				continue
+			}
+			if _, wanted := inodes[inode]; !wanted {
+				continue
+			}
+			if _, found := res[inode]; found {
+				// sockets can be shared by several processes, e.g. after a fork - we report the first one
+				continue
+			}
+			if proc == nil {
+				proc = r.readProcess(pid)
+			}
+			res[inode] = proc
+		}
+		if len(res) == len(inodes) {
+			break
+		}
+	}
+	return res
+}
+
+func (r *portProcessResolver) readProcess(pid int64) *ServedPortProcess {
+	proc := &ServedPortProcess{PID: pid}
+	dir := filepath.Join(r.ProcFS, strconv.FormatInt(pid, 10))
+	if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		proc.Name = strings.TrimSpace(string(comm))
+	}
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		cmdline = bytes.TrimRight(cmdline, "\x00")
+		if len(cmdline) > 0 {
+			proc.Cmdline = strings.Split(string(cmdline), "\x00")
+		}
+	}
+	return proc
+}
+
+// socketInode returns the inode of the socket a file descriptor link points to.
+func socketInode(fn string) (inode uint64, ok bool) {
+	lnk, err := os.Readlink(fn)
+	if err != nil {
+		return 0, false
+	}
+	if !strings.HasPrefix(lnk, "socket:[") || !strings.HasSuffix(lnk, "]") {
+		return 0, false
+	}
+	inode, err = strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(lnk, "socket:["), "]"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return inode, true
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPortProcessResolver(t *testing.T) {
+	type Process struct {
+		PID     int
+		Comm    string
+		Cmdline string
+		Sockets []uint64
+	}
+	tests := []struct {
+		Name        string
+		Processes   []Process
+		Ports       []ServedPort
+		Expectation []ServedPort
+	}{
+		{
+			Name: "resolves processes",
+			Processes: []Process{
+				{PID: 1, Comm: "supervisor", Cmdline: "supervisor\x00run\x00", Sockets: []uint64{100}},
+				{PID: 42, Comm: "node", Cmdline: "node\x00server.js\x00", Sockets: []uint64{200, 201}},
+			},
+			Ports: []ServedPort{
+				{Port: 22999, Inode: 100},
+				{Port: 3000, Inode: 200},
+				{Port: 3001, Inode: 201},
+			},
+			Expectation: []ServedPort{
+				{Port: 22999, Inode: 100, Process: &ServedPortProcess{PID: 1, Name: "supervisor", Cmdline: []string{"supervisor", "run"}}},
+				{Port: 3000, Inode: 200, Process: &ServedPortProcess{PID: 42, Name: "node", Cmdline: []string{"node", "server.js"}}},
+				{Port: 3001, Inode: 201, Process: &ServedPortProcess{PID: 42, Name: "node", Cmdline: []string{"node", "server.js"}}},
+			},
+		},
+		{
+			Name: "unknown inodes",
+			Processes: []Process{
+				{PID: 42, Comm: "node", Cmdline: "node\x00server.js\x00", Sockets: []uint64{200}},
+			},
+			Ports: []ServedPort{
+				{Port: 3000},
+				{Port: 8080, Inode: 300},
+			},
+			Expectation: []ServedPort{
+				{Port: 3000},
+				{Port: 8080, Inode: 300},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			procfs := t.TempDir()
+			for _, p := range test.Processes {
+				dir := filepath.Join(procfs, fmt.Sprint(p.PID))
+				err := os.MkdirAll(filepath.Join(dir, "fd"), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(dir, "comm"), []byte(p.Comm+"\n"), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(dir, "cmdline"), []byte(p.Cmdline), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.Symlink("/dev/null", filepath.Join(dir, "fd", "0"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i, inode := range p.Sockets {
+					err = os.Symlink(fmt.Sprintf("socket:[%d]", inode), filepath.Join(dir, "fd", fmt.Sprint(i+3)))
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			act := append([]ServedPort(nil), test.Ports...)
+			newPortProcessResolver(procfs).Resolve(act)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -13,12 +13,15 @@ import (
 	"io"
 	"net"
 	"os"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
 )
 
 // ServedPort describes a port served by a local service.
@@ -26,6 +29,12 @@ type ServedPort struct {
 	Address          net.IP
 	Port             uint32
 	BoundToLocalhost bool
+	Protocol         api.PortProtocol
+
+	// Inode is the inode of the socket, 0 if unknown
+	Inode uint64
+	// Process is the process which opened the port, nil if unknown
+	Process *ServedPortProcess
 }
 
 // ServedPortsObserver observes the locally served ports and provides
@@ -44,13 +53,48 @@ const (
 
 	fnNetTCP  = "/proc/net/tcp"
 	fnNetTCP6 = "/proc/net/tcp6"
+	fnNetUDP  = "/proc/net/udp"
+	fnNetUDP6 = "/proc/net/udp6"
+
+	// socket states as found in /proc/net/{tcp,udp}*
+	stateListen = "0A"
+	stateClose  = "07"
+)
+
+const (
+	// minRefreshInterval is the polling interval while served ports are changing
+	minRefreshInterval = 250 * time.Millisecond
+	// stableAfter is the time after which unchanged served ports are considered stable
+	stableAfter = 10 * time.Second
 )
 
 // PollingServedPortsObserver regularly polls "/proc" to observe port changes.
+// It polls every MinRefreshInterval while the served ports are changing and gradually
+// slows down to RefreshInterval once they have been stable for a while.
 type PollingServedPortsObserver struct {
-	RefreshInterval time.Duration
+	RefreshInterval    time.Duration
+	MinRefreshInterval time.Duration
 
 	fileOpener func(fn string) (io.ReadCloser, error)
+	processes  *portProcessResolver
+
+	once  sync.Once
+	reset chan struct{}
+}
+
+// Reset makes the observer poll fast again, e.g. because a task was started which is likely to serve new ports.
+func (p *PollingServedPortsObserver) Reset() {
+	p.init()
+	select {
+	case p.reset <- struct{}{}:
+	default:
+	}
+}
+
+func (p *PollingServedPortsObserver) init() {
+	p.once.Do(func() {
+		p.reset = make(chan struct{}, 1)
+	})
 }
 
 // Observe starts observing the served ports until the context is canceled.
@@ -60,36 +104,56 @@ func (p *PollingServedPortsObserver) Observe(ctx context.Context) (<-chan []Serv
 			return os.Open(fn)
 		}
 	}
+	if p.processes == nil {
+		p.processes = newPortProcessResolver("/proc")
+	}
+
+	p.init()
 
 	var (
-		errchan = make(chan error, 1)
-		reschan = make(chan []ServedPort)
-		ticker  = time.NewTicker(p.RefreshInterval)
+		errchan  = make(chan error, 1)
+		reschan  = make(chan []ServedPort)
+		interval = p.nextInterval(0, 0)
+		timer    = time.NewTimer(interval)
+
+		lastChange = time.Now()
+		lastKeys   map[string]struct{}
 	)
 
 	go func() {
 		defer close(errchan)
 		defer close(reschan)
+		defer timer.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
 				log.Warn("done")
 				return
-			case <-ticker.C:
+			case <-p.reset:
+				if !timer.Stop() {
+					<-timer.C
+				}
+				lastChange = time.Now()
+			case <-timer.C:
 			}
 
 			var (
 				visited = make(map[string]struct{})
 				ports   []ServedPort
 			)
-			for _, fn := range []string{fnNetTCP, fnNetTCP6} {
+			for _, fn := range []string{fnNetTCP, fnNetTCP6, fnNetUDP, fnNetUDP6} {
 				fc, err := p.fileOpener(fn)
 				if err != nil {
 					errchan <- err
 					continue
 				}
-				ps, err := readNetTCPFile(fc, true)
+				var ps []ServedPort
+				if fn == fnNetUDP || fn == fnNetUDP6 {
+					ps, err = readNetUDPFile(fc)
+				} else {
+					ps, err = readNetTCPFile(fc, true)
+				}
 				fc.Close()
 
 				if err != nil {
@@ -97,7 +161,7 @@ func (p *PollingServedPortsObserver) Observe(ctx context.Context) (<-chan []Serv
 					continue
 				}
 				for _, port := range ps {
-					key := fmt.Sprintf("%s:%d", hex.EncodeToString(port.Address), port.Port)
+					key := fmt.Sprintf("%s:%d/%s", hex.EncodeToString(port.Address), port.Port, port.Protocol)
 					_, exists := visited[key]
 					if exists {
 						continue
@@ -108,22 +172,59 @@ func (p *PollingServedPortsObserver) Observe(ctx context.Context) (<-chan []Serv
 			}
 
 			if len(ports) > 0 {
+				p.processes.Resolve(ports)
 				reschan <- ports
 			}
+
+			if !reflect.DeepEqual(visited, lastKeys) {
+				lastChange = time.Now()
+			}
+			lastKeys = visited
+			interval = p.nextInterval(interval, time.Since(lastChange))
+			timer.Reset(interval)
 		}
 	}()
 
 	return reschan, errchan
 }
 
+// nextInterval polls fast while ports are changing and doubles the interval up to RefreshInterval once they are stable.
+func (p *PollingServedPortsObserver) nextInterval(current, unchangedFor time.Duration) time.Duration {
+	if p.MinRefreshInterval <= 0 || p.MinRefreshInterval >= p.RefreshInterval {
+		return p.RefreshInterval
+	}
+	if unchangedFor < stableAfter {
+		return p.MinRefreshInterval
+	}
+	next := current * 2
+	if next > p.RefreshInterval {
+		next = p.RefreshInterval
+	}
+	return next
+}
+
 func readNetTCPFile(fc io.Reader, listeningOnly bool) (ports []ServedPort, err error) {
+	var state string
+	if listeningOnly {
+		state = stateListen
+	}
+	return readNetFile(fc, state, api.PortProtocol_tcp)
+}
+
+// readNetUDPFile reads unconnected UDP sockets, i.e. those which can receive datagrams from anyone.
+func readNetUDPFile(fc io.Reader) (ports []ServedPort, err error) {
+	return readNetFile(fc, stateClose, api.PortProtocol_udp)
+}
+
+// readNetFile parses a /proc/net/{tcp,udp}* file. If state is not empty, only sockets in that state are returned.
+func readNetFile(fc io.Reader, state string, protocol api.PortProtocol) (ports []ServedPort, err error) {
 	scanner := bufio.NewScanner(fc)
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 		if len(fields) < 4 {
 			continue
 		}
-		if listeningOnly && fields[3] != "0A" {
+		if state != "" && fields[3] != state {
 			continue
 		}
 
@@ -135,15 +236,22 @@ func readNetTCPFile(fc io.Reader, listeningOnly bool) (ports []ServedPort, err e
 
 		port, err := strconv.ParseUint(portHex, 16, 32)
 		if err != nil {
-			log.WithError(err).WithField("port", portHex).Warn("cannot parse port entry from /proc/net/tcp* file")
+			log.WithError(err).WithField("port", portHex).Warn("cannot parse port entry from /proc/net/* file")
 			continue
 		}
 		ipAddress := hexDecodeIP([]byte(addrHex))
 
+		var inode uint64
+		if len(fields) > 9 {
+			inode, _ = strconv.ParseUint(fields[9], 10, 64)
+		}
+
 		ports = append(ports, ServedPort{
 			BoundToLocalhost: ipAddress.IsLoopback(),
 			Address:          ipAddress,
 			Port:             uint32(port),
+			Protocol:         protocol,
+			Inode:            inode,
 		})
 
 		sort.Slice(ports, func(i, j int) bool {
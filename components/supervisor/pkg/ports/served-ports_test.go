@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
 )
 
 const validTCPInput = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
@@ -36,6 +38,12 @@ const validTCP6Input = `  sl  local_address                         remote_addre
    7: 0000000000000000FFFF0000940C380A:59D7 0000000000000000FFFF00006100840A:E08A 06 00000000:00000000 03:000003E6 00000000     0        0 0 3 0000000000000000
   20: 0000000000000000FFFF00000100007F:59D7 0000000000000000FFFF00000100007F:EB64 01 00000000:00000000 02:000003D2 00000000 33333        0 57014424 2 0000000000000000 20 4 0 10 -1`
 
+const validUDPInput = `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  118: 00000000:EA61 00000000:0000 07 00000000:00000000 00:00000000 00000000 33333        0 63042117 2 0000000000000000 0
+  553: 0100007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 63039720 2 0000000000000000 0
+  734: 940C380A:D0C9 0302380A:0035 01 00000000:00000000 00:00000000 00000000 33333        0 63049918 2 0000000000000000 0
+`
+
 func TestObserve(t *testing.T) {
 	type Expectation [][]ServedPort
 	tests := []struct {
@@ -51,13 +59,13 @@ func TestObserve(t *testing.T) {
 			},
 			Expectation: Expectation{
 				{
-					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv4zero, Port: 6080},
-					{Address: net.IPv4zero, Port: 23000},
-					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv6zero, Port: 22999},
-					{Address: net.IPv6zero, Port: 35900},
-					{Address: net.IPv6zero, Port: 36080},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true, Inode: 57019442},
+					{Address: net.IPv4zero, Port: 6080, Inode: 57020850},
+					{Address: net.IPv4zero, Port: 23000, Inode: 57008615},
+					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true, Inode: 57019446},
+					{Address: net.IPv6zero, Port: 22999, Inode: 57007063},
+					{Address: net.IPv6zero, Port: 35900, Inode: 57022992},
+					{Address: net.IPv6zero, Port: 36080, Inode: 57018070},
 				},
 			},
 		},
@@ -76,12 +84,12 @@ func TestObserve(t *testing.T) {
 			},
 			Expectation: Expectation{
 				{
-					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv4zero, Port: 6080},
-					{Address: net.IPv4zero, Port: 23000},
-					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv6zero, Port: 22999},
-					{Address: net.IPv6zero, Port: 60000},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true, Inode: 21752303},
+					{Address: net.IPv4zero, Port: 6080, Inode: 21757239},
+					{Address: net.IPv4zero, Port: 23000, Inode: 21752496},
+					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true, Inode: 21752306},
+					{Address: net.IPv6zero, Port: 22999, Inode: 21748173},
+					{Address: net.IPv6zero, Port: 60000, Inode: 21750982},
 				},
 			},
 		},
@@ -100,12 +108,12 @@ func TestObserve(t *testing.T) {
 			},
 			Expectation: Expectation{
 				{
-					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv4zero, Port: 6080},
-					{Address: net.IPv4zero, Port: 23000},
-					{Address: net.IPv6zero, Port: 5900, BoundToLocalhost: false},
-					{Address: net.IPv6zero, Port: 22999},
-					{Address: net.IPv6zero, Port: 60000},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true, Inode: 21752303},
+					{Address: net.IPv4zero, Port: 6080, Inode: 21757239},
+					{Address: net.IPv4zero, Port: 23000, Inode: 21752496},
+					{Address: net.IPv6zero, Port: 5900, BoundToLocalhost: false, Inode: 21752306},
+					{Address: net.IPv6zero, Port: 22999, Inode: 21748173},
+					{Address: net.IPv6zero, Port: 60000, Inode: 21750982},
 				},
 			},
 		},
@@ -124,12 +132,12 @@ func TestObserve(t *testing.T) {
 			},
 			Expectation: Expectation{
 				{
-					{Address: net.IPv4zero, Port: 5900},
-					{Address: net.IPv4zero, Port: 6080},
-					{Address: net.IPv4zero, Port: 23000},
-					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv6zero, Port: 22999},
-					{Address: net.IPv6zero, Port: 60000},
+					{Address: net.IPv4zero, Port: 5900, Inode: 21752303},
+					{Address: net.IPv4zero, Port: 6080, Inode: 21757239},
+					{Address: net.IPv4zero, Port: 23000, Inode: 21752496},
+					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true, Inode: 21752306},
+					{Address: net.IPv6zero, Port: 22999, Inode: 21748173},
+					{Address: net.IPv6zero, Port: 60000, Inode: 21750982},
 				},
 			},
 		},
@@ -163,11 +171,11 @@ func TestObserve(t *testing.T) {
 			},
 			Expectation: Expectation{
 				{
-					{Address: net.IPv4(10, 96, 14, 173), Port: 9229},
-					{Address: net.IPv4(127, 0, 0, 1), Port: 9229, BoundToLocalhost: true},
-					{Address: net.IPv4zero, Port: 23000},
-					{Address: net.IPv4(10, 96, 14, 173), Port: 27017, BoundToLocalhost: false},
-					{Address: net.IPv4(127, 0, 0, 1), Port: 27017, BoundToLocalhost: true},
+					{Address: net.IPv4(10, 96, 14, 173), Port: 9229, Inode: 53934502},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 9229, BoundToLocalhost: true, Inode: 53938101},
+					{Address: net.IPv4zero, Port: 23000, Inode: 53939555},
+					{Address: net.IPv4(10, 96, 14, 173), Port: 27017, BoundToLocalhost: false, Inode: 53934503},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 27017, BoundToLocalhost: true, Inode: 54384751},
 				},
 			},
 		},
@@ -197,10 +205,10 @@ func TestObserve(t *testing.T) {
 			},
 			Expectation: Expectation{
 				{
-					{Address: net.IPv4(10, 96, 14, 34), Port: 9229},
-					{Address: net.IPv4(127, 0, 0, 1), Port: 9229, BoundToLocalhost: true},
-					{Address: net.IPv4zero, Port: 23000},
-					{Address: net.IPv4(10, 96, 14, 34), Port: 27017},
+					{Address: net.IPv4(10, 96, 14, 34), Port: 9229, Inode: 61354169},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 9229, BoundToLocalhost: true, Inode: 61232087},
+					{Address: net.IPv4zero, Port: 23000, Inode: 61285963},
+					{Address: net.IPv4(10, 96, 14, 34), Port: 27017, Inode: 61354170},
 				},
 			},
 		},
@@ -211,7 +219,11 @@ func TestObserve(t *testing.T) {
 			var f int
 			obs := PollingServedPortsObserver{
 				RefreshInterval: 100 * time.Millisecond,
+				processes:       newPortProcessResolver(t.TempDir()),
 				fileOpener: func(fn string) (io.ReadCloser, error) {
+					if fn == fnNetUDP || fn == fnNetUDP6 {
+						return io.NopCloser(bytes.NewReader(nil)), nil
+					}
 					if f >= len(test.FileContents) {
 						return nil, os.ErrNotExist
 					}
@@ -262,9 +274,9 @@ func TestReadNetTCPFile(t *testing.T) {
 			ListeningOnly: true,
 			Expectation: Expectation{
 				Ports: []ServedPort{
-					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv4zero, Port: 6080},
-					{Address: net.IPv4zero, Port: 23000},
+					{Address: net.IPv4(127, 0, 0, 1), Port: 5900, BoundToLocalhost: true, Inode: 57019442},
+					{Address: net.IPv4zero, Port: 6080, Inode: 57020850},
+					{Address: net.IPv4zero, Port: 23000, Inode: 57008615},
 				},
 			},
 		},
@@ -274,10 +286,10 @@ func TestReadNetTCPFile(t *testing.T) {
 			ListeningOnly: true,
 			Expectation: Expectation{
 				Ports: []ServedPort{
-					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true},
-					{Address: net.IPv6zero, Port: 22999},
-					{Address: net.IPv6zero, Port: 35900},
-					{Address: net.IPv6zero, Port: 36080},
+					{Address: net.IPv6loopback, Port: 5900, BoundToLocalhost: true, Inode: 57019446},
+					{Address: net.IPv6zero, Port: 22999, Inode: 57007063},
+					{Address: net.IPv6zero, Port: 35900, Inode: 57022992},
+					{Address: net.IPv6zero, Port: 36080, Inode: 57018070},
 				},
 			},
 		},
@@ -300,3 +312,69 @@ func TestReadNetTCPFile(t *testing.T) {
 		})
 	}
 }
+
+func TestReadNetUDPFile(t *testing.T) {
+	type Expectation struct {
+		Ports []ServedPort
+		Error error
+	}
+	tests := []struct {
+		Name        string
+		Input       string
+		Expectation Expectation
+	}{
+		{
+			Name:  "valid udp4 input",
+			Input: validUDPInput,
+			Expectation: Expectation{
+				Ports: []ServedPort{
+					{Address: net.IPv4(127, 0, 0, 1), Port: 53, BoundToLocalhost: true, Protocol: api.PortProtocol_udp, Inode: 63039720},
+					{Address: net.IPv4zero, Port: 60001, Protocol: api.PortProtocol_udp, Inode: 63042117},
+				},
+			},
+		},
+		{
+			Name:        "tcp input",
+			Input:       validTCPInput,
+			Expectation: Expectation{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			act.Ports, act.Error = readNetUDPFile(bytes.NewReader([]byte(test.Input)))
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPollingRefreshInterval(t *testing.T) {
+	tests := []struct {
+		Desc         string
+		Min          time.Duration
+		Current      time.Duration
+		UnchangedFor time.Duration
+		Expectation  time.Duration
+	}{
+		{Desc: "changing", Min: 250 * time.Millisecond, Current: 2 * time.Second, UnchangedFor: time.Second, Expectation: 250 * time.Millisecond},
+		{Desc: "stable", Min: 250 * time.Millisecond, Current: 250 * time.Millisecond, UnchangedFor: stableAfter, Expectation: 500 * time.Millisecond},
+		{Desc: "stable for long", Min: 250 * time.Millisecond, Current: 4 * time.Second, UnchangedFor: time.Hour, Expectation: 5 * time.Second},
+		{Desc: "no min interval", Current: 5 * time.Second, Expectation: 5 * time.Second},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			obs := &PollingServedPortsObserver{
+				RefreshInterval:    5 * time.Second,
+				MinRefreshInterval: test.Min,
+			}
+			act := obs.nextInterval(test.Current, test.UnchangedFor)
+			if act != test.Expectation {
+				t.Errorf("unexpected interval: want %s, got %s", test.Expectation, act)
+			}
+		})
+	}
+}
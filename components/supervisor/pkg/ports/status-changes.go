@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// StatusChange describes how the port status changed since the previous update.
+type StatusChange struct {
+	Added   []*api.PortsStatus
+	Changed []*api.PortsStatus
+	Removed []uint32
+}
+
+// Empty returns true if nothing changed.
+func (c *StatusChange) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// ChangesSubscription is a subscription to status changes
+type ChangesSubscription struct {
+	updates chan *StatusChange
+	Close   func() error
+}
+
+// Updates returns the changes channel. The first change contains all current ports as added.
+func (s *ChangesSubscription) Updates() <-chan *StatusChange {
+	return s.updates
+}
+
+// SubscribeChanges subscribes to status changes. Unlike Subscribe, which always
+// provides the complete status, only ports which were added, changed or removed are reported.
+func (pm *Manager) SubscribeChanges() (*ChangesSubscription, error) {
+	sub, err := pm.Subscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		done = make(chan struct{})
+		once sync.Once
+		res  = &ChangesSubscription{updates: make(chan *StatusChange, 5)}
+	)
+	res.Close = func() error {
+		once.Do(func() {
+			close(done)
+		})
+		return sub.Close()
+	}
+	go func() {
+		defer close(res.updates)
+
+		var (
+			current []*api.PortsStatus
+			initial = true
+		)
+		for update := range sub.Updates() {
+			change := diffStatus(current, update)
+			current = update
+			if change.Empty() && !initial {
+				continue
+			}
+			initial = false
+
+			select {
+			case res.updates <- change:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return res, nil
+}
+
+// diffStatus computes the change between two port status lists.
+func diffStatus(prev, next []*api.PortsStatus) *StatusChange {
+	prevPorts := make(map[uint32]*api.PortsStatus, len(prev))
+	for _, p := range prev {
+		prevPorts[p.LocalPort] = p
+	}
+
+	res := &StatusChange{}
+	for _, n := range next {
+		p, exists := prevPorts[n.LocalPort]
+		delete(prevPorts, n.LocalPort)
+		if !exists {
+			res.Added = append(res.Added, n)
+		} else if !proto.Equal(p, n) {
+			res.Changed = append(res.Changed, n)
+		}
+	}
+	for port := range prevPorts {
+		res.Removed = append(res.Removed, port)
+	}
+
+	sort.Slice(res.Added, func(i, j int) bool { return res.Added[i].LocalPort < res.Added[j].LocalPort })
+	sort.Slice(res.Changed, func(i, j int) bool { return res.Changed[i].LocalPort < res.Changed[j].LocalPort })
+	sort.Slice(res.Removed, func(i, j int) bool { return res.Removed[i] < res.Removed[j] })
+	return res
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestDiffStatus(t *testing.T) {
+	type Expectation struct {
+		Added   []uint32
+		Changed []uint32
+		Removed []uint32
+	}
+	tests := []struct {
+		Desc        string
+		Prev        []*api.PortsStatus
+		Next        []*api.PortsStatus
+		Expectation Expectation
+	}{
+		{
+			Desc: "initial status",
+			Next: []*api.PortsStatus{{LocalPort: 8080, Served: true}, {LocalPort: 3000}},
+			Expectation: Expectation{
+				Added: []uint32{3000, 8080},
+			},
+		},
+		{
+			Desc: "no changes",
+			Prev: []*api.PortsStatus{{LocalPort: 8080, Served: true}},
+			Next: []*api.PortsStatus{{LocalPort: 8080, Served: true}},
+		},
+		{
+			Desc: "added, changed and removed",
+			Prev: []*api.PortsStatus{
+				{LocalPort: 3000},
+				{LocalPort: 8080, Served: true},
+				{LocalPort: 9229, Served: true},
+			},
+			Next: []*api.PortsStatus{
+				{LocalPort: 3000},
+				{LocalPort: 5000, Served: true},
+				{LocalPort: 8080, Served: true, Exposed: &api.ExposedPortInfo{Url: "8080-foobar"}},
+			},
+			Expectation: Expectation{
+				Added:   []uint32{5000},
+				Changed: []uint32{8080},
+				Removed: []uint32{9229},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			change := diffStatus(test.Prev, test.Next)
+
+			var act Expectation
+			for _, p := range change.Added {
+				act.Added = append(act.Added, p.LocalPort)
+			}
+			for _, p := range change.Changed {
+				act.Changed = append(act.Changed, p.LocalPort)
+			}
+			act.Removed = change.Removed
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -204,6 +204,18 @@ func (p *TunneledPortsService) CloseTunnel(ctx context.Context, localPorts ...ui
 	return closedPorts, err
 }
 
+// TunnelDescription returns the description of the tunnel of a local port.
+func (p *TunneledPortsService) TunnelDescription(localPort uint32) (desc PortTunnelDescription, exists bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	tunnel, exists := p.tunnels[localPort]
+	if !exists {
+		return PortTunnelDescription{}, false
+	}
+	return tunnel.State.Desc, true
+}
+
 // EstablishTunnel actually establishes the tunnel.
 func (p *TunneledPortsService) EstablishTunnel(ctx context.Context, clientID string, localPort uint32, targetPort uint32) (net.Conn, error) {
 	p.cond.L.Lock()
@@ -0,0 +1,202 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// PortEventType is the kind of a port lifecycle event.
+type PortEventType string
+
+const (
+	// PortEventServed is posted when a process starts serving a port.
+	PortEventServed PortEventType = "served"
+	// PortEventExposed is posted when a port becomes available from outside the workspace.
+	PortEventExposed PortEventType = "exposed"
+	// PortEventClosed is posted when a port is no longer served.
+	PortEventClosed PortEventType = "closed"
+)
+
+// PortEvent is the payload posted to the ports webhook.
+type PortEvent struct {
+	Type       PortEventType `json:"type"`
+	Port       uint32        `json:"port"`
+	Name       string        `json:"name,omitempty"`
+	Protocol   string        `json:"protocol"`
+	URL        string        `json:"url,omitempty"`
+	Visibility string        `json:"visibility,omitempty"`
+}
+
+const (
+	// webhookQueueSize is the number of events which can be pending before new events are dropped
+	webhookQueueSize = 100
+	webhookTimeout   = 5 * time.Second
+)
+
+// PortsWebhook posts port lifecycle events to a local URL, so that in-workspace tooling
+// can react to port changes without using the supervisor API.
+type PortsWebhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewPortsWebhook creates a new webhook posting to the given URL.
+func NewPortsWebhook(url string) *PortsWebhook {
+	return &PortsWebhook{
+		URL:    url,
+		Client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// ValidateWebhookURL makes sure that port events are posted within the workspace only.
+func ValidateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return xerrors.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return xerrors.Errorf("webhook URL must use http or https, not %q", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return xerrors.Errorf("webhook URL must point to localhost, not %q", host)
+}
+
+// Run posts events for all port changes until the context is canceled or the ports manager stops.
+func (w *PortsWebhook) Run(ctx context.Context, pm *Manager) {
+	sub, err := pm.Subscribe()
+	if err != nil {
+		log.WithError(err).Error("cannot subscribe to port changes for the ports webhook")
+		return
+	}
+	defer sub.Close()
+
+	// events are posted in the background to not hold up the ports manager with slow webhooks
+	queue := make(chan PortEvent, webhookQueueSize)
+	defer close(queue)
+	go func() {
+		for event := range queue {
+			err := w.post(ctx, event)
+			if err != nil && ctx.Err() == nil {
+				log.WithError(err).WithField("port", event.Port).WithField("event", event.Type).Warn("cannot post port event to webhook")
+			}
+		}
+	}()
+
+	var current []*api.PortsStatus
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update := <-sub.Updates():
+			if update == nil {
+				return
+			}
+			for _, event := range diffPortEvents(current, update) {
+				select {
+				case queue <- event:
+				default:
+					log.WithField("port", event.Port).WithField("event", event.Type).Warn("ports webhook is too slow, dropping event")
+				}
+			}
+			current = update
+		}
+	}
+}
+
+func (w *PortsWebhook) post(ctx context.Context, event PortEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return xerrors.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// diffPortEvents produces the lifecycle events of the transition between two port status lists.
+func diffPortEvents(prev, next []*api.PortsStatus) []PortEvent {
+	prevPorts := make(map[uint32]*api.PortsStatus, len(prev))
+	for _, p := range prev {
+		prevPorts[p.LocalPort] = p
+	}
+	nextPorts := make(map[uint32]*api.PortsStatus, len(next))
+	for _, p := range next {
+		nextPorts[p.LocalPort] = p
+	}
+
+	var events []PortEvent
+	for port, n := range nextPorts {
+		p := prevPorts[port]
+		wasServed := p != nil && p.Served
+		wasExposed := p != nil && p.Exposed != nil
+		if n.Served && !wasServed {
+			events = append(events, newPortEvent(PortEventServed, n))
+		}
+		if n.Exposed != nil && !wasExposed {
+			events = append(events, newPortEvent(PortEventExposed, n))
+		}
+		if !n.Served && wasServed {
+			events = append(events, newPortEvent(PortEventClosed, n))
+		}
+	}
+	for port, p := range prevPorts {
+		if _, exists := nextPorts[port]; !exists && p.Served {
+			events = append(events, newPortEvent(PortEventClosed, p))
+		}
+	}
+
+	order := map[PortEventType]int{PortEventServed: 0, PortEventExposed: 1, PortEventClosed: 2}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Port != events[j].Port {
+			return events[i].Port < events[j].Port
+		}
+		return order[events[i].Type] < order[events[j].Type]
+	})
+	return events
+}
+
+func newPortEvent(tpe PortEventType, status *api.PortsStatus) PortEvent {
+	event := PortEvent{
+		Type:     tpe,
+		Port:     status.LocalPort,
+		Name:     status.Name,
+		Protocol: status.Protocol.String(),
+	}
+	if status.Exposed != nil {
+		event.URL = status.Exposed.Url
+		event.Visibility = status.Exposed.Visibility.String()
+	}
+	return event
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestDiffPortEvents(t *testing.T) {
+	exposed := &api.ExposedPortInfo{Visibility: api.PortVisibility_public, Url: "8080-foobar"}
+	tests := []struct {
+		Desc        string
+		Prev        []*api.PortsStatus
+		Next        []*api.PortsStatus
+		Expectation []PortEvent
+	}{
+		{
+			Desc: "no changes",
+			Prev: []*api.PortsStatus{{LocalPort: 8080, Served: true}},
+			Next: []*api.PortsStatus{{LocalPort: 8080, Served: true}},
+		},
+		{
+			Desc: "served and exposed at once",
+			Next: []*api.PortsStatus{{LocalPort: 8080, Name: "web", Served: true, Exposed: exposed}},
+			Expectation: []PortEvent{
+				{Type: PortEventServed, Port: 8080, Name: "web", Protocol: "tcp", URL: "8080-foobar", Visibility: "public"},
+				{Type: PortEventExposed, Port: 8080, Name: "web", Protocol: "tcp", URL: "8080-foobar", Visibility: "public"},
+			},
+		},
+		{
+			Desc: "configured port is served",
+			Prev: []*api.PortsStatus{{LocalPort: 3000}},
+			Next: []*api.PortsStatus{{LocalPort: 3000, Served: true}, {LocalPort: 60001, Served: true, Protocol: api.PortProtocol_udp}},
+			Expectation: []PortEvent{
+				{Type: PortEventServed, Port: 3000, Protocol: "tcp"},
+				{Type: PortEventServed, Port: 60001, Protocol: "udp"},
+			},
+		},
+		{
+			Desc: "exposed port is closed",
+			Prev: []*api.PortsStatus{{LocalPort: 8080, Served: true, Exposed: exposed}},
+			Next: []*api.PortsStatus{{LocalPort: 8080, Exposed: exposed}},
+			Expectation: []PortEvent{
+				{Type: PortEventClosed, Port: 8080, Protocol: "tcp", URL: "8080-foobar", Visibility: "public"},
+			},
+		},
+		{
+			Desc: "port disappears",
+			Prev: []*api.PortsStatus{{LocalPort: 8080, Served: true}, {LocalPort: 9090}},
+			Next: []*api.PortsStatus{},
+			Expectation: []PortEvent{
+				{Type: PortEventClosed, Port: 8080, Protocol: "tcp"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act := diffPortEvents(test.Prev, test.Next)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPortsWebhookPost(t *testing.T) {
+	received := make(chan PortEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event PortEvent
+		err := json.NewDecoder(r.Body).Decode(&event)
+		if err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer srv.Close()
+
+	exp := PortEvent{Type: PortEventServed, Port: 3000, Protocol: "tcp"}
+	err := NewPortsWebhook(srv.URL).post(context.Background(), exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(exp, <-received); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		URL   string
+		Valid bool
+	}{
+		{URL: "http://localhost:8000/ports", Valid: true},
+		{URL: "http://127.0.0.1:8000", Valid: true},
+		{URL: "https://[::1]:8443/hook", Valid: true},
+		{URL: "http://example.com/hook"},
+		{URL: "ftp://localhost/hook"},
+		{URL: "localhost:8000"},
+	}
+	for _, test := range tests {
+		t.Run(test.URL, func(t *testing.T) {
+			err := ValidateWebhookURL(test.URL)
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
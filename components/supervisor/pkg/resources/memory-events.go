@@ -0,0 +1,192 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package resources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// MemoryEventType is the kind of a memory event.
+type MemoryEventType int
+
+const (
+	// MemoryPressure means that the workspace is about to run out of memory
+	MemoryPressure MemoryEventType = iota
+	// OOMKill means that a process was killed because the workspace ran out of memory
+	OOMKill
+)
+
+// MemoryEvent describes a memory related event of the workspace.
+type MemoryEvent struct {
+	Type MemoryEventType
+
+	// Used and Limit are the memory usage and limit in bytes when the event happened
+	Used  int64
+	Limit int64
+
+	// PID and Command identify the process which was most likely killed.
+	// They are empty if we don't know.
+	PID     int
+	Command string
+}
+
+const (
+	// memoryPressureThreshold is the share of the memory limit at which we report memory pressure
+	memoryPressureThreshold = 0.9
+	// memoryPressureRecovered is the share of the memory limit below which we report memory pressure again
+	memoryPressureRecovered = 0.8
+	// fullPressureThreshold is the share of time in percent all processes may be stalled on memory before we report memory pressure
+	fullPressureThreshold = 10
+)
+
+// MemoryWatcher watches the workspace cgroup for OOM kills and memory pressure.
+type MemoryWatcher struct {
+	CgroupBasePath string
+	ProcPath       string
+	Interval       time.Duration
+
+	oomKills      uint64
+	underPressure bool
+	processes     map[int]process
+}
+
+// NewMemoryWatcher creates a new memory watcher for the workspace container.
+func NewMemoryWatcher() *MemoryWatcher {
+	return &MemoryWatcher{
+		CgroupBasePath: DefaultCgroupBasePath,
+		ProcPath:       "/proc",
+		Interval:       5 * time.Second,
+	}
+}
+
+// Run checks for memory events every interval and calls onEvent for each of them until the context is canceled.
+func (w *MemoryWatcher) Run(ctx context.Context, onEvent func(MemoryEvent)) {
+	_, memory := cgroupControllers(w.CgroupBasePath)
+	oomKills, err := memory.OOMKills()
+	if err != nil {
+		log.WithError(err).Warn("cannot read OOM kills, memory events are not available")
+		return
+	}
+	w.oomKills = oomKills
+	w.processes = listProcesses(w.ProcPath)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, evt := range w.check(memory) {
+			onEvent(evt)
+		}
+	}
+}
+
+// check compares the current state with the last check and produces the events in between.
+func (w *MemoryWatcher) check(memory cgroupMemory) []MemoryEvent {
+	var (
+		res       []MemoryEvent
+		processes = listProcesses(w.ProcPath)
+		status, _ = memory.Memory()
+		used      int64
+		limit     int64
+	)
+	if status != nil {
+		used, limit = status.Used, status.Limit
+	}
+
+	oomKills, err := memory.OOMKills()
+	if err != nil {
+		log.WithError(err).Debug("cannot read OOM kills")
+	} else if oomKills > w.oomKills {
+		// The kernel doesn't tell us which process was killed. Our best guess is the process which used
+		// the most memory among the processes which disappeared since the last check.
+		var victim *process
+		for pid, p := range w.processes {
+			if _, exists := processes[pid]; exists {
+				continue
+			}
+			if victim == nil || p.RSS > victim.RSS {
+				p := p
+				victim = &p
+			}
+		}
+		for i := w.oomKills; i < oomKills; i++ {
+			evt := MemoryEvent{Type: OOMKill, Used: used, Limit: limit}
+			if victim != nil && i == oomKills-1 {
+				evt.PID, evt.Command = victim.PID, victim.Command
+			}
+			res = append(res, evt)
+		}
+	}
+	w.oomKills = oomKills
+	w.processes = processes
+
+	pressure, _ := memory.FullPressure()
+	if limit > 0 {
+		share := float64(used) / float64(limit)
+		if !w.underPressure && (share >= memoryPressureThreshold || pressure >= fullPressureThreshold) {
+			w.underPressure = true
+			res = append(res, MemoryEvent{Type: MemoryPressure, Used: used, Limit: limit})
+		} else if w.underPressure && share < memoryPressureRecovered && pressure < fullPressureThreshold {
+			w.underPressure = false
+		}
+	}
+	return res
+}
+
+type process struct {
+	PID     int
+	Command string
+	// RSS is the resident set size in pages
+	RSS uint64
+}
+
+// listProcesses lists all processes visible in procPath.
+func listProcesses(procPath string) map[int]process {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		log.WithError(err).Debug("cannot list processes")
+		return nil
+	}
+
+	res := make(map[int]process, len(entries))
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(procPath, e.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+		// statm: size resident shared text lib data dt
+		statm, err := os.ReadFile(filepath.Join(procPath, e.Name(), "statm"))
+		if err != nil {
+			continue
+		}
+		segs := strings.Fields(string(statm))
+		if len(segs) < 2 {
+			continue
+		}
+		rss, _ := strconv.ParseUint(segs[1], 10, 64)
+		res[pid] = process{
+			PID:     pid,
+			Command: strings.TrimSpace(string(comm)),
+			RSS:     rss,
+		}
+	}
+	return res
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMemoryWatcherCheck(t *testing.T) {
+	type testProcess struct {
+		PID     int
+		Command string
+		RSS     int
+	}
+	type step struct {
+		OOMKills  int
+		Current   int
+		Pressure  string
+		Processes []testProcess
+	}
+	tests := []struct {
+		Desc        string
+		Steps       []step
+		Expectation [][]MemoryEvent
+	}{
+		{
+			Desc: "no events",
+			Steps: []step{
+				{Current: 100},
+				{Current: 200},
+			},
+			Expectation: [][]MemoryEvent{nil, nil},
+		},
+		{
+			Desc: "OOM kill",
+			Steps: []step{
+				{Current: 900, Processes: []testProcess{{PID: 1, Command: "bash", RSS: 10}, {PID: 42, Command: "node", RSS: 200}, {PID: 43, Command: "sleep", RSS: 1}}},
+				{Current: 300, OOMKills: 1, Processes: []testProcess{{PID: 1, Command: "bash", RSS: 10}}},
+			},
+			Expectation: [][]MemoryEvent{
+				{{Type: MemoryPressure, Used: 900, Limit: 1000}},
+				{{Type: OOMKill, Used: 300, Limit: 1000, PID: 42, Command: "node"}},
+			},
+		},
+		{
+			Desc: "memory pressure is reported once",
+			Steps: []step{
+				{Current: 950},
+				{Current: 990},
+				{Current: 850},
+				{Current: 500},
+				{Current: 950},
+			},
+			Expectation: [][]MemoryEvent{
+				{{Type: MemoryPressure, Used: 950, Limit: 1000}},
+				nil,
+				nil,
+				nil,
+				{{Type: MemoryPressure, Used: 950, Limit: 1000}},
+			},
+		},
+		{
+			Desc: "stalled on memory",
+			Steps: []step{
+				{Current: 500, Pressure: "some avg10=30.00 avg60=5.00 avg300=1.00 total=100\nfull avg10=12.50 avg60=2.00 avg300=0.50 total=50\n"},
+			},
+			Expectation: [][]MemoryEvent{
+				{{Type: MemoryPressure, Used: 500, Limit: 1000}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var (
+				cgroupPath = t.TempDir()
+				procPath   = t.TempDir()
+				write      = func(fn, content string) {
+					err := os.MkdirAll(filepath.Dir(fn), 0755)
+					if err != nil {
+						t.Fatal(err)
+					}
+					err = os.WriteFile(fn, []byte(content), 0644)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			)
+			write(filepath.Join(cgroupPath, "cgroup.controllers"), "memory")
+			write(filepath.Join(cgroupPath, "memory.max"), "1000")
+			write(filepath.Join(cgroupPath, "memory.stat"), "inactive_file 0\n")
+			write(filepath.Join(cgroupPath, "memory.events"), "oom 0\noom_kill 0\n")
+
+			w := &MemoryWatcher{CgroupBasePath: cgroupPath, ProcPath: procPath}
+			_, memory := cgroupControllers(cgroupPath)
+
+			var act [][]MemoryEvent
+			for _, s := range test.Steps {
+				write(filepath.Join(cgroupPath, "memory.current"), strconv.Itoa(s.Current))
+				write(filepath.Join(cgroupPath, "memory.events"), "oom "+strconv.Itoa(s.OOMKills)+"\noom_kill "+strconv.Itoa(s.OOMKills)+"\n")
+				pressure := s.Pressure
+				if pressure == "" {
+					pressure = "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"
+				}
+				write(filepath.Join(cgroupPath, "memory.pressure"), pressure)
+
+				err := os.RemoveAll(procPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, p := range s.Processes {
+					pid := strconv.Itoa(p.PID)
+					write(filepath.Join(procPath, pid, "comm"), p.Command+"\n")
+					write(filepath.Join(procPath, pid, "statm"), "1000 "+strconv.Itoa(p.RSS)+" 0 0 0 0 0\n")
+				}
+
+				act = append(act, w.check(memory))
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
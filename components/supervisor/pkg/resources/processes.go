@@ -0,0 +1,148 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package resources
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// DefaultProcFS is where the proc filesystem of the workspace container is mounted
+const DefaultProcFS = "/proc"
+
+// clockTicks is the number of clock ticks per second in which /proc/<pid>/stat reports CPU times.
+// This is USER_HZ, which is 100 on all architectures we run on.
+const clockTicks = 100
+
+// Process is a process of the workspace and its resource consumption.
+type Process struct {
+	PID     int64
+	PPID    int64
+	Name    string
+	Cmdline []string
+	UID     uint32
+	// CPU is measured in millicores
+	CPU int64
+	// Memory is the resident set size in bytes
+	Memory int64
+}
+
+// ListProcesses lists all processes in procfs. Their CPU usage is sampled over a short window.
+func ListProcesses(ctx context.Context, procfs string) ([]*Process, error) {
+	prev, prevTime, err := readProcesses(procfs)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(cpuSampleWindow):
+	}
+	cur, curTime, err := readProcesses(procfs)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := curTime.Sub(prevTime)
+	res := make([]*Process, 0, len(cur))
+	for pid, c := range cur {
+		if p, ok := prev[pid]; ok && c.CPUTicks >= p.CPUTicks && elapsed > 0 {
+			usage := time.Duration(c.CPUTicks-p.CPUTicks) * time.Second / clockTicks
+			c.CPU = int64(float64(usage) / float64(elapsed) * 1000)
+		}
+		res = append(res, &c.Process)
+	}
+	return res, nil
+}
+
+type processSample struct {
+	Process
+	// CPUTicks is the cumulative CPU time in clock ticks
+	CPUTicks uint64
+}
+
+func readProcesses(procfs string) (map[int64]*processSample, time.Time, error) {
+	now := time.Now()
+	entries, err := os.ReadDir(procfs)
+	if err != nil {
+		return nil, now, xerrors.Errorf("cannot list processes: %w", err)
+	}
+	res := make(map[int64]*processSample)
+	for _, e := range entries {
+		pid, err := strconv.ParseInt(e.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		// processes can exit while we read them, in which case we just skip them
+		p, err := readProcess(filepath.Join(procfs, e.Name()), pid)
+		if err != nil {
+			continue
+		}
+		res[pid] = p
+	}
+	return res, now, nil
+}
+
+func readProcess(dir string, pid int64) (*processSample, error) {
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	// the name is enclosed in parentheses and may contain spaces and parentheses itself
+	start, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return nil, xerrors.Errorf("cannot parse %s/stat", dir)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return nil, xerrors.Errorf("cannot parse %s/stat", dir)
+	}
+	ppid, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse ppid of %d: %w", pid, err)
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse utime of %d: %w", pid, err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse stime of %d: %w", pid, err)
+	}
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse rss of %d: %w", pid, err)
+	}
+
+	res := &processSample{
+		Process: Process{
+			PID:    pid,
+			PPID:   ppid,
+			Name:   string(stat[start+1 : end]),
+			Memory: rss * int64(os.Getpagesize()),
+		},
+		CPUTicks: utime + stime,
+	}
+	if info, err := os.Stat(dir); err == nil {
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			res.UID = st.Uid
+		}
+	}
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		cmdline = bytes.TrimRight(cmdline, "\x00")
+		if len(cmdline) > 0 {
+			res.Cmdline = strings.Split(string(cmdline), "\x00")
+		}
+	}
+	return res, nil
+}
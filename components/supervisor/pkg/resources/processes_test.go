@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestListProcesses(t *testing.T) {
+	type proc struct {
+		PID     int
+		Stat    string
+		Cmdline string
+	}
+	procs := []proc{
+		{PID: 1, Stat: "1 (supervisor) S 0 1 1 0 -1 4194560 100 0 0 0 150 50 0 0 20 0 10 0 100 1000000 2000 18446744073709551615", Cmdline: "supervisor\x00run\x00"},
+		{PID: 42, Stat: "42 (node (dev) server) R 1 42 42 0 -1 4194560 100 0 0 0 10 5 0 0 20 0 1 0 200 1000000 100 18446744073709551615", Cmdline: "node\x00server.js\x00"},
+		{PID: 43, Stat: "43 (broken) S"},
+	}
+	procfs := t.TempDir()
+	for _, p := range procs {
+		dir := filepath.Join(procfs, fmt.Sprint(p.PID))
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, "stat"), []byte(p.Stat+"\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, "cmdline"), []byte(p.Cmdline), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// non-process entries are ignored
+	err := os.WriteFile(filepath.Join(procfs, "meminfo"), nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := ListProcesses(context.Background(), procfs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(act, func(i, j int) bool { return act[i].PID < act[j].PID })
+
+	pagesize := int64(os.Getpagesize())
+	uid := uint32(os.Getuid())
+	expectation := []*Process{
+		{PID: 1, PPID: 0, Name: "supervisor", Cmdline: []string{"supervisor", "run"}, UID: uid, Memory: 2000 * pagesize},
+		{PID: 42, PPID: 1, Name: "node (dev) server", Cmdline: []string{"node", "server.js"}, UID: uid, Memory: 100 * pagesize},
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package resources
+
+import (
+	"bufio"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// DefaultCgroupBasePath is where the cgroup filesystem of the workspace container is mounted
+	DefaultCgroupBasePath = "/sys/fs/cgroup"
+
+	// cpuSampleWindow is the time between the two CPU usage readings of a single sample
+	cpuSampleWindow = 250 * time.Millisecond
+)
+
+// Sampler samples the resource consumption of the workspace.
+type Sampler struct {
+	// CgroupBasePath is the cgroup filesystem of the workspace container. Both cgroup v1 and v2 are supported.
+	CgroupBasePath string
+	// DiskPath is a path on the workspace volume
+	DiskPath string
+}
+
+// NewSampler creates a new sampler for the workspace volume containing diskPath.
+func NewSampler(diskPath string) *Sampler {
+	return &Sampler{
+		CgroupBasePath: DefaultCgroupBasePath,
+		DiskPath:       diskPath,
+	}
+}
+
+// Get samples the current resource consumption.
+func (s *Sampler) Get(ctx context.Context) (*api.ResourcesStatusResponse, error) {
+	prev := s.sample()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(cpuSampleWindow):
+	}
+	return s.sample().usage(prev), nil
+}
+
+// Observe samples the resource consumption every interval and calls onSample with the result
+// until the context is canceled or onSample returns an error.
+func (s *Sampler) Observe(ctx context.Context, interval time.Duration, onSample func(*api.ResourcesStatusResponse) error) error {
+	prev := s.sample()
+
+	// the first sample is produced right away, so that clients don't have to wait a whole interval
+	timer := time.NewTimer(cpuSampleWindow)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		cur := s.sample()
+		err := onSample(cur.usage(prev))
+		if err != nil {
+			return err
+		}
+		prev = cur
+		timer.Reset(interval)
+	}
+}
+
+// sample is a snapshot of the resource consumption. CPU usage is cumulative and
+// only becomes meaningful as the difference of two samples.
+type sample struct {
+	Time     time.Time
+	CPUUsage time.Duration
+	CPULimit int64
+	HasCPU   bool
+	Memory   *api.ResourceStatus
+	Disk     *api.ResourceStatus
+	Inodes   *api.ResourceStatus
+}
+
+func (s *Sampler) sample() *sample {
+	res := &sample{Time: time.Now()}
+
+	cpu, memory := cgroupControllers(s.CgroupBasePath)
+
+	usage, err := cpu.CPUUsage()
+	if err != nil {
+		log.WithError(err).Debug("cannot read CPU usage")
+	} else {
+		res.CPUUsage = usage
+		res.HasCPU = true
+		res.CPULimit, err = cpu.CPULimit()
+		if err != nil {
+			log.WithError(err).Debug("cannot read CPU limit")
+		}
+	}
+
+	res.Memory, err = memory.Memory()
+	if err != nil {
+		log.WithError(err).Debug("cannot read memory usage")
+	}
+
+	var stat syscall.Statfs_t
+	err = syscall.Statfs(s.DiskPath, &stat)
+	if err != nil {
+		log.WithError(err).Debug("cannot read disk usage")
+	} else {
+		res.Disk = &api.ResourceStatus{
+			Used:  int64(stat.Blocks-stat.Bfree) * stat.Bsize,
+			Limit: int64(stat.Blocks) * stat.Bsize,
+		}
+		res.Inodes = &api.ResourceStatus{
+			Used:  int64(stat.Files - stat.Ffree),
+			Limit: int64(stat.Files),
+		}
+	}
+	return res
+}
+
+// usage computes the resource consumption in the time since prev.
+func (s *sample) usage(prev *sample) *api.ResourcesStatusResponse {
+	res := &api.ResourcesStatusResponse{
+		Memory: s.Memory,
+		Disk:   s.Disk,
+		Inodes: s.Inodes,
+	}
+	elapsed := s.Time.Sub(prev.Time)
+	if s.HasCPU && prev.HasCPU && elapsed > 0 {
+		res.Cpu = &api.ResourceStatus{
+			Used:  int64(float64(s.CPUUsage-prev.CPUUsage) / float64(elapsed) * 1000),
+			Limit: s.CPULimit,
+		}
+	}
+	return res
+}
+
+// cgroupControllers detects the cgroup version mounted at basePath and returns its controllers.
+func cgroupControllers(basePath string) (cgroupCPU, cgroupMemory) {
+	if _, err := os.Stat(filepath.Join(basePath, "cgroup.controllers")); err == nil {
+		return cgroupV2{basePath}, cgroupV2{basePath}
+	}
+	return cgroupV1CPU{filepath.Join(basePath, "cpu")}, cgroupV1Memory{filepath.Join(basePath, "memory")}
+}
+
+type cgroupCPU interface {
+	// CPUUsage returns the cumulative CPU time
+	CPUUsage() (time.Duration, error)
+	// CPULimit returns the CPU limit in millicores, or 0 if there's no limit
+	CPULimit() (int64, error)
+}
+
+type cgroupMemory interface {
+	Memory() (*api.ResourceStatus, error)
+	// OOMKills returns the number of processes killed by the OOM killer
+	OOMKills() (uint64, error)
+	// FullPressure returns the share of time in the last 10s all processes were stalled on memory, in percent
+	FullPressure() (float64, error)
+}
+
+type cgroupV2 struct {
+	basePath string
+}
+
+func (c cgroupV2) CPUUsage() (time.Duration, error) {
+	usec, err := readStat(filepath.Join(c.basePath, "cpu.stat"), "usage_usec")
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+func (c cgroupV2) CPULimit() (int64, error) {
+	fc, err := os.ReadFile(filepath.Join(c.basePath, "cpu.max"))
+	if err != nil {
+		return 0, err
+	}
+	segs := strings.Fields(string(fc))
+	if len(segs) != 2 {
+		return 0, xerrors.Errorf("cannot parse cpu.max: %s", string(fc))
+	}
+	if segs[0] == "max" {
+		return 0, nil
+	}
+	quota, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot parse cpu.max: %w", err)
+	}
+	period, err := strconv.ParseInt(segs[1], 10, 64)
+	if err != nil || period == 0 {
+		return 0, xerrors.Errorf("cannot parse cpu.max: %s", string(fc))
+	}
+	return quota * 1000 / period, nil
+}
+
+func (c cgroupV2) Memory() (*api.ResourceStatus, error) {
+	current, err := readUint64(filepath.Join(c.basePath, "memory.current"))
+	if err != nil {
+		return nil, err
+	}
+	limit, err := readUint64(filepath.Join(c.basePath, "memory.max"))
+	if err != nil {
+		return nil, err
+	}
+	inactive, err := readStat(filepath.Join(c.basePath, "memory.stat"), "inactive_file")
+	if err != nil {
+		return nil, err
+	}
+	return memoryStatus(current, limit, inactive), nil
+}
+
+func (c cgroupV2) OOMKills() (uint64, error) {
+	return readStat(filepath.Join(c.basePath, "memory.events"), "oom_kill")
+}
+
+func (c cgroupV2) FullPressure() (float64, error) {
+	f, err := os.Open(filepath.Join(c.basePath, "memory.pressure"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		segs := strings.Fields(scanner.Text())
+		if len(segs) < 2 || segs[0] != "full" || !strings.HasPrefix(segs[1], "avg10=") {
+			continue
+		}
+		res, err := strconv.ParseFloat(strings.TrimPrefix(segs[1], "avg10="), 64)
+		if err != nil {
+			return 0, xerrors.Errorf("cannot parse memory.pressure: %w", err)
+		}
+		return res, nil
+	}
+	return 0, xerrors.Errorf("memory.pressure did not contain full avg10")
+}
+
+type cgroupV1CPU struct {
+	basePath string
+}
+
+func (c cgroupV1CPU) CPUUsage() (time.Duration, error) {
+	nsec, err := readUint64(filepath.Join(c.basePath, "cpuacct.usage"))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(nsec), nil
+}
+
+func (c cgroupV1CPU) CPULimit() (int64, error) {
+	fc, err := os.ReadFile(filepath.Join(c.basePath, "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, err
+	}
+	quota, err := strconv.ParseInt(strings.TrimSpace(string(fc)), 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot parse cpu.cfs_quota_us: %w", err)
+	}
+	if quota < 0 {
+		return 0, nil
+	}
+	period, err := readUint64(filepath.Join(c.basePath, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, err
+	}
+	if period == 0 {
+		return 0, xerrors.Errorf("cpu.cfs_period_us is zero")
+	}
+	return quota * 1000 / int64(period), nil
+}
+
+type cgroupV1Memory struct {
+	basePath string
+}
+
+// cgroupV1Unlimited is the smallest memory limit cgroup v1 reports for unlimited cgroups.
+// The actual value depends on the page size.
+const cgroupV1Unlimited = math.MaxInt64 &^ (1<<16 - 1)
+
+func (c cgroupV1Memory) Memory() (*api.ResourceStatus, error) {
+	usage, err := readUint64(filepath.Join(c.basePath, "memory.usage_in_bytes"))
+	if err != nil {
+		return nil, err
+	}
+	limit, err := readUint64(filepath.Join(c.basePath, "memory.limit_in_bytes"))
+	if err != nil {
+		return nil, err
+	}
+	if limit >= cgroupV1Unlimited {
+		limit = math.MaxUint64
+	}
+	inactive, err := readStat(filepath.Join(c.basePath, "memory.stat"), "total_inactive_file")
+	if err != nil {
+		return nil, err
+	}
+	return memoryStatus(usage, limit, inactive), nil
+}
+
+func (c cgroupV1Memory) OOMKills() (uint64, error) {
+	return readStat(filepath.Join(c.basePath, "memory.oom_control"), "oom_kill")
+}
+
+func (c cgroupV1Memory) FullPressure() (float64, error) {
+	return 0, xerrors.Errorf("memory pressure is not available with cgroup v1")
+}
+
+// memoryStatus computes the working set like the kubelet does, i.e. excluding the inactive page cache
+func memoryStatus(usage, limit, inactive uint64) *api.ResourceStatus {
+	var workingSet uint64
+	if usage > inactive {
+		workingSet = usage - inactive
+	}
+	res := &api.ResourceStatus{Used: int64(workingSet)}
+	if limit != math.MaxUint64 {
+		res.Limit = int64(limit)
+	}
+	return res
+}
+
+// readUint64 reads a single value cgroup file. "max" is read as math.MaxUint64.
+func readUint64(fn string) (uint64, error) {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(fc))
+	if s == "max" {
+		return math.MaxUint64, nil
+	}
+	res, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot parse %s: %w", fn, err)
+	}
+	return res, nil
+}
+
+// readStat reads a value from a flat keyed cgroup file, e.g. cpu.stat.
+func readStat(fn, key string) (uint64, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	prefix := key + " "
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		l := scanner.Text()
+		if !strings.HasPrefix(l, prefix) {
+			continue
+		}
+		res, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(l, prefix)), 10, 64)
+		if err != nil {
+			return 0, xerrors.Errorf("cannot parse %s: %s: %w", fn, l, err)
+		}
+		return res, nil
+	}
+	return 0, xerrors.Errorf("%s did not contain %s", fn, key)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSample(t *testing.T) {
+	type memory struct {
+		Used  int64
+		Limit int64
+	}
+	type Expectation struct {
+		CPUUsage time.Duration
+		CPULimit int64
+		Memory   memory
+	}
+	tests := []struct {
+		Desc        string
+		Files       map[string]string
+		Expectation Expectation
+	}{
+		{
+			Desc: "cgroup v2",
+			Files: map[string]string{
+				"cgroup.controllers": "cpu io memory pids",
+				"cpu.stat":           "usage_usec 1500000\nuser_usec 1000000\nsystem_usec 500000\n",
+				"cpu.max":            "400000 100000\n",
+				"memory.current":     "3000\n",
+				"memory.max":         "8000\n",
+				"memory.stat":        "anon 1000\nfile 2000\ninactive_file 1000\n",
+			},
+			Expectation: Expectation{
+				CPUUsage: 1500 * time.Millisecond,
+				CPULimit: 4000,
+				Memory:   memory{Used: 2000, Limit: 8000},
+			},
+		},
+		{
+			Desc: "cgroup v2 without limits",
+			Files: map[string]string{
+				"cgroup.controllers": "cpu io memory pids",
+				"cpu.stat":           "usage_usec 1500000\n",
+				"cpu.max":            "max 100000\n",
+				"memory.current":     "3000\n",
+				"memory.max":         "max\n",
+				"memory.stat":        "inactive_file 1000\n",
+			},
+			Expectation: Expectation{
+				CPUUsage: 1500 * time.Millisecond,
+				Memory:   memory{Used: 2000},
+			},
+		},
+		{
+			Desc: "cgroup v1",
+			Files: map[string]string{
+				"cpu/cpuacct.usage":            "2000000000\n",
+				"cpu/cpu.cfs_quota_us":         "150000\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+				"memory/memory.usage_in_bytes": "3000\n",
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+				"memory/memory.stat":           "cache 2000\ntotal_inactive_file 500\n",
+			},
+			Expectation: Expectation{
+				CPUUsage: 2 * time.Second,
+				CPULimit: 1500,
+				Memory:   memory{Used: 2500},
+			},
+		},
+		{
+			Desc:        "no cgroups",
+			Expectation: Expectation{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			base := t.TempDir()
+			for fn, content := range test.Files {
+				fn = filepath.Join(base, fn)
+				err := os.MkdirAll(filepath.Dir(fn), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(fn, []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sampler := &Sampler{CgroupBasePath: base, DiskPath: base}
+			s := sampler.sample()
+			if s.Disk == nil || s.Disk.Limit == 0 || s.Inodes == nil {
+				t.Errorf("expected disk usage, got %v", s.Disk)
+			}
+
+			act := Expectation{
+				CPUUsage: s.CPUUsage,
+				CPULimit: s.CPULimit,
+			}
+			if s.Memory != nil {
+				act.Memory = memory{Used: s.Memory.Used, Limit: s.Memory.Limit}
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSampleUsage(t *testing.T) {
+	now := time.Now()
+	prev := &sample{Time: now, CPUUsage: 1 * time.Second, HasCPU: true}
+	cur := &sample{Time: now.Add(2 * time.Second), CPUUsage: 4 * time.Second, CPULimit: 4000, HasCPU: true}
+
+	act := cur.usage(prev).Cpu
+	if act == nil || act.Used != 1500 || act.Limit != 4000 {
+		t.Errorf("unexpected CPU usage: %v", act)
+	}
+	if cpu := cur.usage(&sample{Time: now}).Cpu; cpu != nil {
+		t.Errorf("expected no CPU usage without a previous CPU reading, got %v", cpu)
+	}
+}
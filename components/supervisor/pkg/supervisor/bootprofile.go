@@ -0,0 +1,229 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+)
+
+// bootProfileLocation is where supervisor writes the boot profile, which "gp boot-profile" renders.
+const bootProfileLocation = "/tmp/gitpod-boot-profile.json"
+
+// Boot phases recorded in the boot profile.
+const (
+	bootPhaseContent            = "content"
+	bootPhaseIDE                = "ide"
+	bootPhaseDesktopIDE         = "desktop-ide"
+	bootPhaseDesktopIDEDownload = "desktop-ide-download"
+	bootPhaseTasks              = "tasks"
+	bootPhasePorts              = "ports"
+)
+
+// bootProfile records how long the phases of the workspace start take. Each phase is
+// traced as a span and the timeline is written as JSON to a file, so that it can be
+// inspected from within the workspace. A nil bootProfile records nothing.
+type bootProfile struct {
+	Started time.Time    `json:"started"`
+	Phases  []*bootPhase `json:"phases"`
+
+	location string
+	span     opentracing.Span
+	mu       sync.Mutex
+}
+
+type bootPhase struct {
+	Name  string     `json:"name"`
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+	Error string     `json:"error,omitempty"`
+
+	profile *bootProfile
+	span    opentracing.Span
+}
+
+func newBootProfile(location string) *bootProfile {
+	return &bootProfile{
+		Started:  time.Now(),
+		location: location,
+		span:     opentracing.StartSpan("supervisor.boot"),
+	}
+}
+
+// StartPhase starts a phase of the workspace start.
+func (p *bootProfile) StartPhase(name string) *bootPhase {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	phase := &bootPhase{
+		Name:    name,
+		Start:   time.Now(),
+		profile: p,
+		span:    opentracing.StartSpan(name, opentracing.ChildOf(p.span.Context())),
+	}
+	p.Phases = append(p.Phases, phase)
+	p.write()
+	return phase
+}
+
+// Finish ends a phase. A non-nil error marks the phase as failed.
+func (phase *bootPhase) Finish(err error) {
+	if phase == nil {
+		return
+	}
+
+	p := phase.profile
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if phase.End != nil {
+		return
+	}
+	end := time.Now()
+	phase.End = &end
+	if err != nil {
+		phase.Error = err.Error()
+	}
+	tracing.FinishSpan(phase.span, &err)
+	p.write()
+
+	for _, ph := range p.Phases {
+		if ph.End == nil {
+			return
+		}
+	}
+	p.span.Finish()
+}
+
+// write writes the profile to its location. Callers are expected to hold mu.
+func (p *bootProfile) write() {
+	if p.location == "" {
+		return
+	}
+	fc, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		log.WithError(err).Warn("cannot marshal boot profile")
+		return
+	}
+	// we write to a temporary file first so that readers never see a partial profile
+	tmp := filepath.Join(filepath.Dir(p.location), "."+filepath.Base(p.location)+".tmp")
+	err = os.WriteFile(tmp, fc, 0644)
+	if err == nil {
+		err = os.Rename(tmp, p.location)
+	}
+	if err != nil {
+		log.WithError(err).WithField("location", p.location).Warn("cannot write boot profile")
+	}
+}
+
+// ObserveContent finishes the content phase once the workspace content is ready.
+func (p *bootProfile) ObserveContent(ctx context.Context, cstate ContentState) {
+	phase := p.StartPhase(bootPhaseContent)
+	select {
+	case <-cstate.ContentReady():
+		phase.Finish(nil)
+	case <-ctx.Done():
+	}
+}
+
+// ObserveIDE finishes an IDE phase once the IDE became ready for the first time.
+func (p *bootProfile) ObserveIDE(ctx context.Context, name string, ideReady *ideReadyState) {
+	phase := p.StartPhase(name)
+	select {
+	case <-ideReady.Wait():
+		phase.Finish(nil)
+	case <-ctx.Done():
+	}
+}
+
+// ObserveTasks finishes the tasks phase once all tasks were started.
+func (p *bootProfile) ObserveTasks(ctx context.Context, tm *tasksManager) {
+	phase := p.StartPhase(bootPhaseTasks)
+	select {
+	case <-tm.ready:
+	case <-ctx.Done():
+		return
+	}
+
+	sub := tm.Subscribe()
+	if sub == nil {
+		phase.Finish(xerrors.Errorf("too many task subscriptions"))
+		return
+	}
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case status, ok := <-sub.Updates():
+			if !ok {
+				return
+			}
+			started := true
+			for _, t := range status {
+				if t.State == api.TaskState_opening {
+					started = false
+				}
+			}
+			if started {
+				phase.Finish(nil)
+				return
+			}
+		}
+	}
+}
+
+// ObservePorts finishes the ports phase once all known ports are served.
+// Ports are known if they are configured or served.
+func (p *bootProfile) ObservePorts(ctx context.Context, cstate ContentState, portMgmt *ports.Manager) {
+	phase := p.StartPhase(bootPhasePorts)
+	// the ports configuration is part of the workspace content
+	select {
+	case <-cstate.ContentReady():
+	case <-ctx.Done():
+		return
+	}
+
+	sub, err := portMgmt.Subscribe()
+	if err != nil {
+		phase.Finish(err)
+		return
+	}
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case status, ok := <-sub.Updates():
+			if !ok {
+				return
+			}
+			served := true
+			for _, port := range status {
+				if !port.Served {
+					served = false
+				}
+			}
+			if served {
+				phase.Finish(nil)
+				return
+			}
+		}
+	}
+}
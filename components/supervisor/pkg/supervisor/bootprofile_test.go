@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+)
+
+func TestBootProfile(t *testing.T) {
+	type phase struct {
+		Name     string
+		Finished bool
+		Error    string
+	}
+	readProfile := func(fn string) []phase {
+		fc, err := os.ReadFile(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var profile bootProfile
+		err = json.Unmarshal(fc, &profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res []phase
+		for _, p := range profile.Phases {
+			if p.End != nil && p.End.Before(p.Start) {
+				t.Errorf("phase %s ended before it started", p.Name)
+			}
+			res = append(res, phase{Name: p.Name, Finished: p.End != nil, Error: p.Error})
+		}
+		return res
+	}
+
+	fn := filepath.Join(t.TempDir(), "boot-profile.json")
+	profile := newBootProfile(fn)
+	cstate := NewInMemoryContentState("")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	contentObserved := make(chan struct{})
+	go func() {
+		defer close(contentObserved)
+		profile.ObserveContent(ctx, cstate)
+	}()
+	download := profile.StartPhase(bootPhaseDesktopIDEDownload)
+	download.Finish(xerrors.Errorf("checksum mismatch"))
+	download.Finish(nil)
+
+	// the content phase is started asynchronously
+	for len(readProfile(fn)) < 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cstate.MarkContentReady(csapi.WorkspaceInitFromOther)
+	<-contentObserved
+
+	act := readProfile(fn)
+	// the order of the phases depends on when the goroutine started
+	if act[0].Name != bootPhaseContent {
+		act[0], act[1] = act[1], act[0]
+	}
+	expectation := []phase{
+		{Name: bootPhaseContent, Finished: true},
+		{Name: bootPhaseDesktopIDEDownload, Finished: true, Error: "checksum mismatch"},
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Errorf("unexpected boot profile (-want +got):\n%s", diff)
+	}
+
+	// a nil profile records nothing
+	var nilProfile *bootProfile
+	nilProfile.StartPhase(bootPhaseTasks).Finish(nil)
+}
@@ -5,6 +5,8 @@
 package supervisor
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,13 +15,16 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	env "github.com/Netflix/go-env"
 	"golang.org/x/xerrors"
 
+	"github.com/gitpod-io/gitpod/common-go/util"
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
 )
 
 const supervisorConfigFile = "supervisor-config.json"
@@ -85,6 +90,26 @@ type StaticConfig struct {
 
 	// SSHPort is the port we run the SSH server on
 	SSHPort int `json:"sshPort"`
+
+	// ServedPortsRefreshInterval is the interval at which served ports are refreshed once they have been
+	// stable for a while. While ports are changing they are polled faster. Defaults to 5s.
+	ServedPortsRefreshInterval util.Duration `json:"servedPortsRefreshInterval,omitempty"`
+
+	// DotfilesInstallScripts are the scripts in a dotfiles repository which may be run to install the dotfiles,
+	// relative to the root of the repository. The first executable script is run. Defaults to the usual candidates,
+	// e.g. install.sh or script/bootstrap.
+	DotfilesInstallScripts []string `json:"dotfilesInstallScripts,omitempty"`
+
+	// DotfilesTimeout is the time cloning and installing the dotfiles may take each. Defaults to two minutes.
+	DotfilesTimeout util.Duration `json:"dotfilesTimeout,omitempty"`
+
+	// SessionRecordingMaxFileSize is the size in bytes after which a session recording is continued in a new file.
+	// Defaults to 10MiB.
+	SessionRecordingMaxFileSize int64 `json:"sessionRecordingMaxFileSize,omitempty"`
+
+	// SessionRecordingMaxTotalSize is the size in bytes of all session recordings after which the oldest ones
+	// are removed. Defaults to 500MiB.
+	SessionRecordingMaxTotalSize int64 `json:"sessionRecordingMaxTotalSize,omitempty"`
 }
 
 // Validate validates this configuration.
@@ -101,10 +126,69 @@ func (c StaticConfig) Validate() error {
 	if !(0 < c.SSHPort && c.SSHPort <= math.MaxUint16) {
 		return xerrors.Errorf("sshPort must be between 0 and %d", math.MaxUint16)
 	}
+	if c.ServedPortsRefreshInterval < 0 {
+		return xerrors.Errorf("servedPortsRefreshInterval must be >= 0")
+	}
+	for _, script := range c.DotfilesInstallScripts {
+		if script == "" || filepath.IsAbs(script) || filepath.Clean(script) != script || strings.HasPrefix(script, "..") {
+			return xerrors.Errorf("dotfilesInstallScripts must contain clean paths relative to the dotfiles repository, not %q", script)
+		}
+	}
+	if c.DotfilesTimeout < 0 {
+		return xerrors.Errorf("dotfilesTimeout must be >= 0")
+	}
+	if c.SessionRecordingMaxFileSize < 0 {
+		return xerrors.Errorf("sessionRecordingMaxFileSize must be >= 0")
+	}
+	if c.SessionRecordingMaxTotalSize < 0 {
+		return xerrors.Errorf("sessionRecordingMaxTotalSize must be >= 0")
+	}
 
 	return nil
 }
 
+// defaultServedPortsRefreshInterval is the refresh interval of served ports if none is configured.
+const defaultServedPortsRefreshInterval = 5 * time.Second
+
+// GetServedPortsRefreshInterval returns the configured refresh interval of served ports or the default.
+func (c StaticConfig) GetServedPortsRefreshInterval() time.Duration {
+	if c.ServedPortsRefreshInterval == 0 {
+		return defaultServedPortsRefreshInterval
+	}
+	return time.Duration(c.ServedPortsRefreshInterval)
+}
+
+// defaultDotfilesInstallScripts are the scripts we look for in dotfiles repositories if none are configured.
+var defaultDotfilesInstallScripts = []string{
+	"install.sh",
+	"install",
+	"bootstrap.sh",
+	"bootstrap",
+	"script/bootstrap",
+	"setup.sh",
+	"setup",
+	"script/setup",
+}
+
+// GetDotfilesInstallScripts returns the allowed dotfiles install scripts.
+func (c StaticConfig) GetDotfilesInstallScripts() []string {
+	if len(c.DotfilesInstallScripts) == 0 {
+		return defaultDotfilesInstallScripts
+	}
+	return c.DotfilesInstallScripts
+}
+
+// defaultDotfilesTimeout is the time cloning and installing the dotfiles may take if not configured otherwise.
+const defaultDotfilesTimeout = 120 * time.Second
+
+// GetDotfilesTimeout returns the configured dotfiles timeout or the default.
+func (c StaticConfig) GetDotfilesTimeout() time.Duration {
+	if c.DotfilesTimeout == 0 {
+		return defaultDotfilesTimeout
+	}
+	return time.Duration(c.DotfilesTimeout)
+}
+
 // ReadinessProbeType determines the IDE readiness probe type.
 type ReadinessProbeType string
 
@@ -153,6 +237,42 @@ type IDEConfig struct {
 			Path string `json:"path"`
 		} `json:"http"`
 	} `json:"readinessProbe"`
+
+	// LivenessProbe configures supervisor to restart the IDE if it stops responding to the
+	// HTTP readiness probe once it was ready. Only supported for desktop IDEs.
+	LivenessProbe *IDELivenessProbe `json:"livenessProbe,omitempty"`
+
+	// Download configures supervisor to download the IDE before starting it, instead of
+	// shipping it with the IDE image. Only supported for desktop IDEs.
+	Download *IDEDownload `json:"download,omitempty"`
+
+	// Env are additional environment variables of the IDE process.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// IDELivenessProbe configures how the health of a running IDE is checked.
+type IDELivenessProbe struct {
+	// PeriodSeconds is the time between two probes. Defaults to 10.
+	PeriodSeconds int `json:"periodSeconds"`
+
+	// FailureThreshold is the number of consecutive failed probes after which the IDE is restarted. Defaults to 3.
+	FailureThreshold int `json:"failureThreshold"`
+}
+
+// IDEDownload describes where to download an IDE from.
+type IDEDownload struct {
+	// URL points to a tar.gz archive of the IDE.
+	URL string `json:"url"`
+
+	// SHA256 is the hex encoded SHA-256 checksum of the archive.
+	SHA256 string `json:"sha256"`
+
+	// Location is the directory the archive is extracted to. If it contains the IDE
+	// with the same checksum already, e.g. after a workspace restart, the download is skipped.
+	Location string `json:"location"`
+
+	// StripComponents removes that many leading path elements from the archive entries, like tar --strip-components.
+	StripComponents int `json:"stripComponents"`
 }
 
 // Validate validates this configuration.
@@ -160,15 +280,31 @@ func (c IDEConfig) Validate() error {
 	if c.Entrypoint == "" {
 		return xerrors.Errorf("entrypoint is required")
 	}
-	if stat, err := os.Stat(c.Entrypoint); err != nil {
-		return xerrors.Errorf("invalid entrypoint: %w", err)
-	} else if stat.IsDir() {
-		return xerrors.Errorf("entrypoint is a directory, but should be a file")
+	if c.Download == nil {
+		// downloaded IDEs don't exist yet
+		if stat, err := os.Stat(c.Entrypoint); err != nil {
+			return xerrors.Errorf("invalid entrypoint: %w", err)
+		} else if stat.IsDir() {
+			return xerrors.Errorf("entrypoint is a directory, but should be a file")
+		}
+	} else {
+		if c.Download.URL == "" {
+			return xerrors.Errorf("download.url is required")
+		}
+		if sum, err := hex.DecodeString(c.Download.SHA256); err != nil || len(sum) != sha256.Size {
+			return xerrors.Errorf("download.sha256 must be a hex encoded SHA-256 checksum")
+		}
+		if !filepath.IsAbs(c.Download.Location) {
+			return xerrors.Errorf("download.location must be an absolute path")
+		}
 	}
 
 	if c.LogRateLimit < 0 {
 		return xerrors.Errorf("logRateLimit must be >= 0")
 	}
+	if c.LivenessProbe != nil && c.ReadinessProbe.Type != ReadinessHTTPProbe {
+		return xerrors.Errorf("livenessProbe requires an HTTP readinessProbe")
+	}
 
 	return nil
 }
@@ -213,6 +349,10 @@ type WorkspaceConfig struct {
 	// Tokens is a JSON encoded list of WorkspaceGitpodToken
 	Tokens string `env:"THEIA_SUPERVISOR_TOKENS"`
 
+	// AccessLogToken is the token ws-proxy authenticates with when it records access log entries.
+	// ws-manager derives it from the owner token, which the workspace doesn't know.
+	AccessLogToken string `env:"THEIA_SUPERVISOR_ACCESS_LOG_TOKEN"`
+
 	// WorkspaceID is the ID of the workspace
 	WorkspaceID string `env:"GITPOD_WORKSPACE_ID"`
 
@@ -231,6 +371,9 @@ type WorkspaceConfig struct {
 	// DebugEnabled controls whether the supervisor debugging facilities (pprof, grpc tracing) should be enabled
 	DebugEnable bool `env:"SUPERVISOR_DEBUG_ENABLE"`
 
+	// MetricsEnable controls whether supervisor serves Prometheus metrics on /metrics of the API endpoint
+	MetricsEnable bool `env:"SUPERVISOR_METRICS_ENABLE"`
+
 	// WorkspaceContext is a context for this workspace
 	WorkspaceContext string `env:"GITPOD_WORKSPACE_CONTEXT"`
 
@@ -241,11 +384,22 @@ type WorkspaceConfig struct {
 	// the in-workspace epxerience.
 	DotfileRepo string `env:"SUPERVISOR_DOTFILE_REPO"`
 
+	// SSHCAPublicKey is the public key of the installation's SSH certificate authority. The SSH server accepts
+	// user certificates signed by this CA if they name the workspace ID as principal.
+	SSHCAPublicKey string `env:"SUPERVISOR_SSH_CA_PUBLIC_KEY"`
+
 	// EnvvarOTS points to a URL from which environment variables for child processes can be downloaded from.
 	// This provides a safer means to transport environment variables compared to shipping them on the Kubernetes pod.
 	//
 	// The format of the content downloaded from this URL is expected to be JSON in the form of [{"name":"name", "value":"value"}]
 	EnvvarOTS string `env:"SUPERVISOR_ENVVAR_OTS"`
+
+	// PortsWebhookURL is a local URL to which supervisor posts port lifecycle events (served, exposed, closed).
+	PortsWebhookURL string `env:"SUPERVISOR_PORTS_WEBHOOK_URL"`
+
+	// SessionRecording makes supervisor record all terminal and SSH sessions in the asciicast format
+	// to /workspace/.gitpod/recordings, e.g. for pair-debugging or compliance.
+	SessionRecording bool `env:"SUPERVISOR_SESSION_RECORDING"`
 }
 
 // WorkspaceGitpodToken is a list of tokens that should be added to supervisor's token service.
@@ -264,6 +418,8 @@ type TaskConfig struct {
 	Env      *map[string]interface{} `json:"env,omitempty"`
 	OpenIn   *string                 `json:"openIn,omitempty"`
 	OpenMode *string                 `json:"openMode,omitempty"`
+	Needs    *[]string               `json:"needs,omitempty"`
+	Ready    *string                 `json:"ready,omitempty"`
 }
 
 // Validate validates this configuration.
@@ -288,6 +444,12 @@ func (c WorkspaceConfig) Validate() error {
 		return err
 	}
 
+	if c.PortsWebhookURL != "" {
+		if err := ports.ValidateWebhookURL(c.PortsWebhookURL); err != nil {
+			return xerrors.Errorf("SUPERVISOR_PORTS_WEBHOOK_URL is invalid: %w", err)
+		}
+	}
+
 	return nil
 }
 
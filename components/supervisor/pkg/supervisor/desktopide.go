@@ -0,0 +1,470 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	defaultLivenessPeriod           = 10 * time.Second
+	defaultLivenessFailureThreshold = 3
+
+	// maxDesktopIDEStartFailures is the number of consecutive starts which didn't make the IDE ready,
+	// after which we give up on the desktop IDE
+	maxDesktopIDEStartFailures = 3
+
+	// desktopIDEDownloadMarker is the file in the download location which contains the checksum of the extracted archive
+	desktopIDEDownloadMarker = ".gitpod-download-sha256"
+)
+
+// desktopIDEManager downloads, starts, health-checks and restarts a desktop IDE.
+// Unlike the web IDE, a failing desktop IDE does not fail the workspace.
+type desktopIDEManager struct {
+	cfg       *Config
+	ideConfig *IDEConfig
+	env       func() []string
+	content   ContentState
+	ready     *ideReadyState
+	profile   *bootProfile
+
+	mu       sync.RWMutex
+	phase    api.IDEStatusResponse_DesktopStatus_Phase
+	restarts uint32
+	message  string
+}
+
+func newDesktopIDEManager(cfg *Config, ideConfig *IDEConfig, env func() []string, content ContentState, ready *ideReadyState) *desktopIDEManager {
+	return &desktopIDEManager{
+		cfg:       cfg,
+		ideConfig: ideConfig,
+		env:       env,
+		content:   content,
+		ready:     ready,
+	}
+}
+
+// Status returns the current phase of the IDE, how often it was restarted and the last error.
+func (m *desktopIDEManager) Status() (phase api.IDEStatusResponse_DesktopStatus_Phase, restarts uint32, message string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.phase, m.restarts, m.message
+}
+
+func (m *desktopIDEManager) setPhase(phase api.IDEStatusResponse_DesktopStatus_Phase, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.phase = phase
+	m.message = message
+	log.WithField("phase", phase.String()).WithField("message", message).Info("desktop IDE phase changed")
+}
+
+// Run downloads the IDE if necessary and keeps it running until the context is canceled.
+func (m *desktopIDEManager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer log.WithField("ide", DesktopIDE.String()).Debug("desktop IDE manager shutdown")
+
+	if m.cfg.isHeadless() {
+		m.ready.Set(true, nil)
+		return
+	}
+
+	if m.ideConfig.Download != nil {
+		m.setPhase(api.IDEStatusResponse_DesktopStatus_downloading, "")
+		phase := m.profile.StartPhase(bootPhaseDesktopIDEDownload)
+		err := downloadIDE(ctx, m.ideConfig.Download)
+		phase.Finish(err)
+		if err != nil {
+			log.WithError(err).WithField("url", m.ideConfig.Download.URL).Error("cannot download desktop IDE")
+			m.setPhase(api.IDEStatusResponse_DesktopStatus_failed, fmt.Sprintf("cannot download the IDE: %v", err))
+			return
+		}
+	}
+
+	// the desktop IDE opens the repository right away
+	select {
+	case <-m.content.ContentReady():
+	case <-ctx.Done():
+		return
+	}
+
+	var failures int
+	for {
+		m.mu.Lock()
+		m.phase = api.IDEStatusResponse_DesktopStatus_starting
+		m.mu.Unlock()
+
+		wasReady, err := m.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if wasReady {
+			failures = 0
+		} else {
+			failures++
+		}
+		log.WithError(err).WithField("failures", failures).Warn("desktop IDE stopped")
+		if failures >= maxDesktopIDEStartFailures {
+			m.setPhase(api.IDEStatusResponse_DesktopStatus_failed, fmt.Sprintf("the IDE failed to start %d times: %v", failures, err))
+			return
+		}
+
+		m.mu.Lock()
+		m.restarts++
+		m.message = err.Error()
+		m.mu.Unlock()
+
+		// in case the IDE doesn't start at all we don't want to restart it in a tight loop
+		select {
+		case <-time.After(time.Duration(failures+1) * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runOnce starts the IDE and returns once it stopped, or was stopped because it became unhealthy.
+func (m *desktopIDEManager) runOnce(ctx context.Context) (wasReady bool, err error) {
+	cmd := prepareIDELaunch(m.cfg, m.ideConfig, m.env())
+
+	var (
+		started = make(chan error, 1)
+		exited  = make(chan error, 1)
+	)
+	go func() {
+		// prepareIDELaunch sets Pdeathsig - see launchIDE for why we lock the OS thread.
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		err := cmd.Start()
+		started <- err
+		if err != nil {
+			return
+		}
+		exited <- cmd.Wait()
+	}()
+	err = <-started
+	if err != nil {
+		return false, xerrors.Errorf("cannot start IDE: %w", err)
+	}
+	defer m.ready.Set(false, nil)
+
+	probeCtx, cancelProbe := context.WithCancel(ctx)
+	defer cancelProbe()
+	var (
+		readyInfo = make(chan *DesktopIDEStatus, 1)
+		unhealthy = make(chan error, 1)
+	)
+	go func() {
+		info, err := m.waitUntilReady(probeCtx)
+		if err != nil {
+			return
+		}
+		readyInfo <- info
+
+		err = m.checkLiveness(probeCtx)
+		if err != nil {
+			unhealthy <- err
+		}
+	}()
+
+	for {
+		select {
+		case info := <-readyInfo:
+			wasReady = true
+			m.ready.Set(true, info)
+			m.setPhase(api.IDEStatusResponse_DesktopStatus_ready, "")
+		case err := <-unhealthy:
+			m.setPhase(api.IDEStatusResponse_DesktopStatus_unhealthy, err.Error())
+			stopIDEProcess(cmd, syscall.SIGTERM, exited)
+			return wasReady, err
+		case err := <-exited:
+			// kill all processes in same pgid
+			_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGKILL)
+			if err == nil {
+				return wasReady, xerrors.Errorf("IDE exited")
+			}
+			return wasReady, xerrors.Errorf("IDE exited: %w", err)
+		case <-ctx.Done():
+			stopIDEProcess(cmd, os.Interrupt, exited)
+			return wasReady, ctx.Err()
+		}
+	}
+}
+
+// stopIDEProcess signals the IDE to stop and kills its process group if it didn't stop in time.
+func stopIDEProcess(cmd *exec.Cmd, sig os.Signal, exited <-chan error) {
+	_ = cmd.Process.Signal(sig)
+	select {
+	case <-exited:
+	case <-time.After(timeBudgetIDEShutdown):
+		log.WithField("ide", DesktopIDE.String()).WithField("timeBudgetIDEShutdown", timeBudgetIDEShutdown.String()).Error("IDE did not stop in time - sending SIGKILL")
+	}
+	_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// waitUntilReady probes the IDE until it's ready or the context is canceled.
+func (m *desktopIDEManager) waitUntilReady(ctx context.Context) (*DesktopIDEStatus, error) {
+	if m.ideConfig.ReadinessProbe.Type != ReadinessHTTPProbe {
+		return nil, nil
+	}
+
+	t0 := time.Now()
+	tick := time.NewTicker(500 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tick.C:
+		}
+
+		info, err := probeIDE(ctx, ideProbeURL(m.cfg, m.ideConfig, DesktopIDE))
+		if err != nil {
+			continue
+		}
+		log.WithField("ide", DesktopIDE.String()).Infof("IDE readiness took %.3f seconds", time.Since(t0).Seconds())
+		return info, nil
+	}
+}
+
+// checkLiveness probes the IDE periodically and returns an error once it failed too many probes in a row.
+// It returns nil when the context is canceled.
+func (m *desktopIDEManager) checkLiveness(ctx context.Context) error {
+	probe := m.ideConfig.LivenessProbe
+	if probe == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	period := defaultLivenessPeriod
+	if probe.PeriodSeconds > 0 {
+		period = time.Duration(probe.PeriodSeconds) * time.Second
+	}
+	threshold := defaultLivenessFailureThreshold
+	if probe.FailureThreshold > 0 {
+		threshold = probe.FailureThreshold
+	}
+
+	tick := time.NewTicker(period)
+	defer tick.Stop()
+	var failures int
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick.C:
+		}
+
+		_, err := probeIDE(ctx, ideProbeURL(m.cfg, m.ideConfig, DesktopIDE))
+		if err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		log.WithError(err).WithField("failures", failures).Debug("desktop IDE liveness probe failed")
+		if failures >= threshold {
+			return xerrors.Errorf("IDE failed %d health checks: %w", failures, err)
+		}
+	}
+}
+
+// probeIDE makes a single request against the IDE and returns the desktop IDE status it responded with, if any.
+func probeIDE(ctx context.Context, url string) (*DesktopIDEStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var info DesktopIDEStatus
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	if err != nil {
+		log.WithError(err).Debug("cannot parse desktop IDE status")
+		return nil, nil
+	}
+	return &info, nil
+}
+
+// downloadIDE downloads and extracts the IDE archive unless the download location contains it already.
+func downloadIDE(ctx context.Context, d *IDEDownload) error {
+	expected := strings.ToLower(d.SHA256)
+	marker := filepath.Join(d.Location, desktopIDEDownloadMarker)
+	if existing, err := os.ReadFile(marker); err == nil && strings.TrimSpace(string(existing)) == expected {
+		log.WithField("location", d.Location).Info("desktop IDE was downloaded already")
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(d.Location), 0755)
+	if err != nil {
+		return err
+	}
+	archive, err := os.CreateTemp(filepath.Dir(d.Location), "ide-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(archive, hash), resp.Body)
+	if err != nil {
+		return xerrors.Errorf("cannot download archive: %w", err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return xerrors.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
+	}
+
+	_, err = archive.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(d.Location), "ide-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	err = extractTarGz(archive, tmp, d.StripComponents)
+	if err != nil {
+		return xerrors.Errorf("cannot extract archive: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(tmp, desktopIDEDownloadMarker), []byte(expected), 0644)
+	if err != nil {
+		return err
+	}
+	_ = os.Chmod(tmp, 0755)
+	_ = os.Lchown(tmp, gitpodUID, gitpodGID)
+
+	err = os.RemoveAll(d.Location)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.Location)
+}
+
+// extractTarGz extracts a tar.gz archive to dst. Entries which would end up outside of dst are rejected.
+func extractTarGz(r io.Reader, dst string, stripComponents int) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	dst = filepath.Clean(dst)
+	within := func(p string) bool {
+		return p == dst || strings.HasPrefix(p, dst+string(filepath.Separator))
+	}
+	target := func(name string) (string, bool) {
+		segs := strings.Split(strings.Trim(filepath.ToSlash(filepath.Clean(name)), "/"), "/")
+		if len(segs) <= stripComponents {
+			return "", false
+		}
+		return filepath.Join(dst, filepath.Join(segs[stripComponents:]...)), true
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fn, ok := target(hdr.Name)
+		if !ok {
+			continue
+		}
+		if !within(fn) {
+			return xerrors.Errorf("%s is outside of the destination", hdr.Name)
+		}
+		err = os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(fn, hdr.FileInfo().Mode().Perm())
+		case tar.TypeReg:
+			err = writeFileFrom(fn, tr, hdr.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) || !within(filepath.Join(filepath.Dir(fn), hdr.Linkname)) {
+				return xerrors.Errorf("%s links outside of the destination", hdr.Name)
+			}
+			err = os.Symlink(hdr.Linkname, fn)
+		case tar.TypeLink:
+			link, ok := target(hdr.Linkname)
+			if !ok || !within(link) {
+				return xerrors.Errorf("%s links outside of the destination", hdr.Name)
+			}
+			err = os.Link(link, fn)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_ = os.Lchown(fn, gitpodUID, gitpodGID)
+	}
+}
+
+func writeFileFrom(fn string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDownloadIDE(t *testing.T) {
+	type entry struct {
+		Name     string
+		Content  string
+		Linkname string
+		Type     byte
+	}
+	createArchive := func(entries []entry) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, e := range entries {
+			hdr := &tar.Header{Name: e.Name, Linkname: e.Linkname, Typeflag: e.Type, Mode: 0755, Size: int64(len(e.Content))}
+			if e.Type == 0 {
+				hdr.Typeflag = tar.TypeReg
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte(e.Content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tw.Close()
+		gz.Close()
+		return buf.Bytes()
+	}
+	checksum := func(b []byte) string {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}
+
+	ideArchive := []entry{
+		{Name: "ide-1.0/", Type: tar.TypeDir},
+		{Name: "ide-1.0/bin/", Type: tar.TypeDir},
+		{Name: "ide-1.0/bin/start.sh", Content: "#!/bin/sh"},
+		{Name: "ide-1.0/start.sh", Linkname: "bin/start.sh", Type: tar.TypeSymlink},
+	}
+
+	type Expectation struct {
+		Error    string
+		Files    []string
+		Requests int
+	}
+	tests := []struct {
+		Desc            string
+		Archive         []entry
+		Checksum        string
+		StripComponents int
+		Existing        string
+		Expectation     Expectation
+	}{
+		{
+			Desc:            "download",
+			Archive:         ideArchive,
+			StripComponents: 1,
+			Expectation: Expectation{
+				Files:    []string{desktopIDEDownloadMarker, "bin", "bin/start.sh", "start.sh"},
+				Requests: 1,
+			},
+		},
+		{
+			Desc:    "without strip components",
+			Archive: ideArchive,
+			Expectation: Expectation{
+				Files:    []string{desktopIDEDownloadMarker, "ide-1.0", "ide-1.0/bin", "ide-1.0/bin/start.sh", "ide-1.0/start.sh"},
+				Requests: 1,
+			},
+		},
+		{
+			Desc:     "checksum mismatch",
+			Archive:  ideArchive,
+			Checksum: strings.Repeat("0", 64),
+			Expectation: Expectation{
+				Error:    "checksum mismatch",
+				Requests: 1,
+			},
+		},
+		{
+			Desc:     "downloaded already",
+			Archive:  ideArchive,
+			Existing: "already there",
+			Expectation: Expectation{
+				Files: []string{desktopIDEDownloadMarker, "already there"},
+			},
+		},
+		{
+			Desc:    "path traversal",
+			Archive: []entry{{Name: "../evil.sh", Content: "#!/bin/sh"}},
+			Expectation: Expectation{
+				Error:    "outside of the destination",
+				Requests: 1,
+			},
+		},
+		{
+			Desc:    "symlink traversal",
+			Archive: []entry{{Name: "passwd", Linkname: "../../etc/passwd", Type: tar.TypeSymlink}},
+			Expectation: Expectation{
+				Error:    "links outside of the destination",
+				Requests: 1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			archive := createArchive(test.Archive)
+			var act Expectation
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				act.Requests++
+				_, _ = w.Write(archive)
+			}))
+			defer srv.Close()
+
+			download := &IDEDownload{
+				URL:             srv.URL,
+				SHA256:          test.Checksum,
+				Location:        filepath.Join(t.TempDir(), "ide"),
+				StripComponents: test.StripComponents,
+			}
+			if download.SHA256 == "" {
+				download.SHA256 = checksum(archive)
+			}
+			if test.Existing != "" {
+				if err := os.MkdirAll(download.Location, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(download.Location, test.Existing), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(download.Location, desktopIDEDownloadMarker), []byte(download.SHA256), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := downloadIDE(context.Background(), download)
+			if err != nil {
+				act.Error = err.Error()
+				if strings.Contains(act.Error, test.Expectation.Error) {
+					act.Error = test.Expectation.Error
+				}
+			}
+			_ = filepath.Walk(download.Location, func(path string, info os.FileInfo, err error) error {
+				if err != nil || path == download.Location {
+					return nil
+				}
+				rel, _ := filepath.Rel(download.Location, path)
+				act.Files = append(act.Files, rel)
+				return nil
+			})
+			sort.Strings(act.Files)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDesktopIDELiveness(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ideConfig := &IDEConfig{LivenessProbe: &IDELivenessProbe{PeriodSeconds: 1, FailureThreshold: 2}}
+	ideConfig.ReadinessProbe.Type = ReadinessHTTPProbe
+	ideConfig.ReadinessProbe.HTTPProbe.Host = host
+	ideConfig.ReadinessProbe.HTTPProbe.Port, _ = strconv.Atoi(port)
+
+	m := newDesktopIDEManager(&Config{}, ideConfig, nil, nil, nil)
+	err = m.checkLiveness(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed 2 health checks: unexpected status code 503") {
+		t.Errorf("expected the IDE to be unhealthy, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/git"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+var (
+	errNoDotfiles       = xerrors.New("no dotfiles repository configured")
+	errDotfilesDisabled = xerrors.New("dotfiles are disabled for this repository")
+)
+
+// maxDotfilesLog is the maximum number of bytes of the installation log we keep in memory
+const maxDotfilesLog = 64 << 10
+
+// dotfilesInstaller clones a dotfiles repository and installs it, either by running one of the
+// allowed install scripts or by symlinking its content into the home directory.
+type dotfilesInstaller struct {
+	// Repo is the dotfiles repository configured by the user
+	Repo string
+	// InstallScripts are the scripts which may be run, relative to the repository root
+	InstallScripts []string
+	// Timeout limits cloning and installing each
+	Timeout time.Duration
+	// Home is the home directory the dotfiles are installed into
+	Home string
+	// Env is the environment of the install script
+	Env []string
+	// Credential is the user the installation runs as. Nil means the current user.
+	Credential *syscall.Credential
+	// GitpodConfig provides the .gitpod.yml of the repository, which can override the dotfiles settings
+	GitpodConfig func() (*gitpod.GitpodConfig, error)
+	// AuthProvider provides the credentials to clone the dotfiles repository
+	AuthProvider func(ctx context.Context, host string) git.AuthProvider
+
+	mu sync.Mutex
+}
+
+func newDotfilesInstaller(cfg *Config, tokenService *InMemoryTokenService, childProcEnvvars []string, gitpodConfig func() (*gitpod.GitpodConfig, error)) *dotfilesInstaller {
+	return &dotfilesInstaller{
+		Repo:           cfg.DotfileRepo,
+		InstallScripts: cfg.GetDotfilesInstallScripts(),
+		Timeout:        cfg.GetDotfilesTimeout(),
+		Home:           "/home/gitpod",
+		Env:            childProcEnvvars,
+		// All supervisor children run as gitpod user. The environment variables we produce are also
+		// gitpod user specific.
+		Credential: &syscall.Credential{
+			Uid: gitpodUID,
+			Gid: gitpodGID,
+		},
+		GitpodConfig: gitpodConfig,
+		AuthProvider: func(ctx context.Context, host string) git.AuthProvider {
+			return func() (username string, password string, err error) {
+				resp, err := tokenService.GetToken(ctx, &api.GetTokenRequest{
+					Host: host,
+					Kind: KindGit,
+				})
+				if err != nil {
+					return
+				}
+				username = resp.User
+				password = resp.Token
+				return
+			}
+		},
+	}
+}
+
+func (d *dotfilesInstaller) dotfilesPath() string {
+	return filepath.Join(d.Home, ".dotfiles")
+}
+
+// Install clones and installs the dotfiles. If the dotfiles are installed already, they are
+// only installed again if reinstall is true. Install returns the installation log.
+func (d *dotfilesInstaller) Install(ctx context.Context, reinstall bool) (installLog string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	repo, installScripts, err := d.resolve()
+	if err != nil {
+		return "", err
+	}
+
+	dotfilePath := d.dotfilesPath()
+	if _, err := os.Stat(dotfilePath); err == nil {
+		if !reinstall {
+			// dotfile path exists already - nothing to do here
+			return "", nil
+		}
+		err = os.RemoveAll(dotfilePath)
+		if err != nil {
+			return "", xerrors.Errorf("cannot remove previously installed dotfiles: %w", err)
+		}
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(d.Home, ".dotfiles.log"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer logFile.Close()
+	var captured bytes.Buffer
+	out := io.MultiWriter(logFile, &limitedWriter{W: &captured, N: maxDotfilesLog})
+
+	defer func() {
+		if err != nil {
+			_, _ = out.Write([]byte(fmt.Sprintf("# dotfile init failed: %s\n", err.Error())))
+		}
+		installLog = captured.String()
+	}()
+
+	err = d.clone(ctx, repo, out)
+	if err != nil {
+		return
+	}
+
+	installed, err := d.runInstallScript(ctx, installScripts, out)
+	if err != nil || installed {
+		return
+	}
+
+	// no installation script candidate was found, let's try and symlink this stuff
+	err = d.symlink(out)
+	return
+}
+
+// resolve applies the overrides of the repository's .gitpod.yml to the user's dotfiles settings.
+func (d *dotfilesInstaller) resolve() (repo string, installScripts []string, err error) {
+	repo, installScripts = d.Repo, d.InstallScripts
+
+	var cfg *gitpod.GitpodConfig
+	if d.GitpodConfig != nil {
+		cfg, err = d.GitpodConfig()
+		if err != nil && !os.IsNotExist(err) {
+			log.WithError(err).Warn("cannot read .gitpod.yml, ignoring its dotfiles configuration")
+		}
+		err = nil
+	}
+	if cfg != nil && cfg.Dotfiles != nil {
+		if cfg.Dotfiles.Disabled {
+			return "", nil, errDotfilesDisabled
+		}
+		if cfg.Dotfiles.Repository != "" {
+			repo = cfg.Dotfiles.Repository
+		}
+		if script := cfg.Dotfiles.InstallScript; script != "" {
+			var allowed bool
+			for _, s := range d.InstallScripts {
+				if s == script {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return "", nil, xerrors.Errorf("dotfiles install script %s is not allowed, use one of %s", script, strings.Join(d.InstallScripts, ", "))
+			}
+			installScripts = []string{script}
+		}
+	}
+
+	if repo == "" {
+		return "", nil, errNoDotfiles
+	}
+	return repo, installScripts, nil
+}
+
+func (d *dotfilesInstaller) clone(ctx context.Context, repo string, out io.Writer) error {
+	repoURL, err := url.Parse(repo)
+	if err != nil {
+		return err
+	}
+
+	_, _ = out.Write([]byte(fmt.Sprintf("# cloning dotfiles repository %s\n", repo)))
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+	client := &git.Client{
+		AuthMethod: git.BasicAuth,
+		Location:   d.dotfilesPath(),
+		RemoteURI:  repo,
+	}
+	if d.AuthProvider != nil {
+		client.AuthProvider = d.AuthProvider(ctx, repoURL.Host)
+	}
+	err = client.Clone(ctx)
+	if ctx.Err() == context.DeadlineExceeded {
+		return xerrors.Errorf("dotfiles repo clone did not finish within %s", d.Timeout)
+	}
+	if err != nil {
+		return err
+	}
+
+	if d.Credential != nil {
+		_ = filepath.Walk(d.dotfilesPath(), func(name string, info os.FileInfo, err error) error {
+			if err == nil {
+				err = os.Lchown(name, int(d.Credential.Uid), int(d.Credential.Gid))
+			}
+			return err
+		})
+	}
+	return nil
+}
+
+// runInstallScript runs the first executable install script. It returns false if there is none.
+func (d *dotfilesInstaller) runInstallScript(ctx context.Context, installScripts []string, out io.Writer) (installed bool, err error) {
+	dotfilePath := d.dotfilesPath()
+	for _, c := range installScripts {
+		fn := filepath.Join(dotfilePath, c)
+		stat, err := os.Stat(fn)
+		if err != nil {
+			_, _ = out.Write([]byte(fmt.Sprintf("# installation script candidate %s is not available\n", fn)))
+			continue
+		}
+		if stat.IsDir() {
+			_, _ = out.Write([]byte(fmt.Sprintf("# installation script candidate %s is a directory\n", fn)))
+			continue
+		}
+		if stat.Mode()&0111 == 0 {
+			_, _ = out.Write([]byte(fmt.Sprintf("# installation script candidate %s is not executable\n", fn)))
+			continue
+		}
+
+		_, _ = out.Write([]byte(fmt.Sprintf("# executing installation script candidate %s\n", fn)))
+
+		// looks like we've found a candidate, let's run it
+		ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", "exec "+fn)
+		cmd.Dir = d.Home
+		cmd.Env = d.Env
+		if d.Credential != nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{Credential: d.Credential}
+		}
+		cmd.Stdout = out
+		cmd.Stderr = out
+		err = cmd.Run()
+		if ctx.Err() == context.DeadlineExceeded {
+			return true, xerrors.Errorf("installation process %s took longer than %s", fn, d.Timeout)
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// symlink links the content of the dotfiles repository into the home directory.
+func (d *dotfilesInstaller) symlink(out io.Writer) error {
+	dotfilePath := d.dotfilesPath()
+	return filepath.Walk(dotfilePath, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.Contains(path, "/.git") {
+			// don't symlink the .git directory or any of its content
+			return nil
+		}
+
+		homeFN := filepath.Join(d.Home, strings.TrimPrefix(path, dotfilePath))
+		if _, err := os.Lstat(homeFN); err == nil {
+			// homeFN exists already - do nothing
+			return nil
+		}
+
+		if info.IsDir() {
+			return os.MkdirAll(homeFN, info.Mode().Perm())
+		}
+
+		// write some feedback to the terminal
+		_, _ = out.Write([]byte(fmt.Sprintf("# echo linking %s -> %s\n", path, homeFN)))
+
+		return os.Symlink(path, homeFN)
+	})
+}
+
+// limitedWriter writes up to N bytes to W and silently drops the rest.
+type limitedWriter struct {
+	W io.Writer
+	N int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > l.N {
+		p = p[:l.N]
+	}
+	if len(p) > 0 {
+		_, err := l.W.Write(p)
+		if err != nil {
+			return 0, err
+		}
+		l.N -= len(p)
+	}
+	return n, nil
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+)
+
+func TestDotfilesResolve(t *testing.T) {
+	type Expectation struct {
+		Repo           string
+		InstallScripts []string
+		Error          string
+	}
+	tests := []struct {
+		Desc        string
+		Repo        string
+		Config      *gitpod.GitpodConfig
+		Expectation Expectation
+	}{
+		{
+			Desc:        "no dotfiles",
+			Expectation: Expectation{Error: errNoDotfiles.Error()},
+		},
+		{
+			Desc: "user dotfiles",
+			Repo: "https://github.com/foo/dotfiles",
+			Expectation: Expectation{
+				Repo:           "https://github.com/foo/dotfiles",
+				InstallScripts: []string{"install.sh", "setup"},
+			},
+		},
+		{
+			Desc:        "disabled in .gitpod.yml",
+			Repo:        "https://github.com/foo/dotfiles",
+			Config:      &gitpod.GitpodConfig{Dotfiles: &gitpod.Dotfiles{Disabled: true}},
+			Expectation: Expectation{Error: errDotfilesDisabled.Error()},
+		},
+		{
+			Desc:   "repository and install script overridden",
+			Repo:   "https://github.com/foo/dotfiles",
+			Config: &gitpod.GitpodConfig{Dotfiles: &gitpod.Dotfiles{Repository: "https://github.com/team/dotfiles", InstallScript: "setup"}},
+			Expectation: Expectation{
+				Repo:           "https://github.com/team/dotfiles",
+				InstallScripts: []string{"setup"},
+			},
+		},
+		{
+			Desc:        "install script not allowed",
+			Repo:        "https://github.com/foo/dotfiles",
+			Config:      &gitpod.GitpodConfig{Dotfiles: &gitpod.Dotfiles{InstallScript: "evil.sh"}},
+			Expectation: Expectation{Error: "dotfiles install script evil.sh is not allowed, use one of install.sh, setup"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			d := &dotfilesInstaller{
+				Repo:           test.Repo,
+				InstallScripts: []string{"install.sh", "setup"},
+				GitpodConfig: func() (*gitpod.GitpodConfig, error) {
+					if test.Config == nil {
+						return nil, os.ErrNotExist
+					}
+					return test.Config, nil
+				},
+			}
+
+			var act Expectation
+			var err error
+			act.Repo, act.InstallScripts, err = d.resolve()
+			if err != nil {
+				act.Error = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDotfilesInstallScript(t *testing.T) {
+	home := t.TempDir()
+	d := &dotfilesInstaller{
+		InstallScripts: []string{"install.sh", "setup"},
+		Timeout:        5 * time.Second,
+		Home:           home,
+	}
+	err := os.MkdirAll(d.dotfilesPath(), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(d.dotfilesPath(), "install.sh"), []byte("not executable"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(d.dotfilesPath(), "setup"), []byte("#!/bin/sh\ntouch installed\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := d.runInstallScript(context.Background(), d.InstallScripts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !installed {
+		t.Error("expected the setup script to be run")
+	}
+	if _, err := os.Stat(filepath.Join(home, "installed")); err != nil {
+		t.Errorf("setup script did not run in the home directory: %v", err)
+	}
+}
+
+func TestDotfilesSymlink(t *testing.T) {
+	home := t.TempDir()
+	d := &dotfilesInstaller{Home: home}
+	for _, fn := range []string{".bashrc", ".config/foo.conf", ".git/config"} {
+		fn = filepath.Join(d.dotfilesPath(), fn)
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// dangling symlinks of a previous installation must not break the installation
+	err := os.Symlink(filepath.Join(home, "does-not-exist"), filepath.Join(home, ".bashrc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.symlink(io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target, err := os.Readlink(filepath.Join(home, ".config/foo.conf")); err != nil || target != filepath.Join(d.dotfilesPath(), ".config/foo.conf") {
+		t.Errorf("unexpected symlink target %s: %v", target, err)
+	}
+	if _, err := os.Lstat(filepath.Join(home, ".git")); err == nil {
+		t.Error("the .git directory must not be linked")
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/logs"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// workspaceEnvVarsFile contains workspace scoped environment variables in the format of the env var OTS,
+// i.e. [{"name":"name", "value":"value"}]. Changes to this file are passed to all terminals opened afterwards.
+var workspaceEnvVarsFile = filepath.Join(logs.TerminalStoreLocation, "env.json")
+
+// scopedEnvVar is an environment variable as shipped in the env var OTS.
+type scopedEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Scope is one of user, project or workspace. Servers which don't know about scopes leave it empty.
+	Scope string `json:"scope,omitempty"`
+}
+
+func (v scopedEnvVar) scope() api.EnvironmentVariableScope {
+	return api.EnvironmentVariableScope(api.EnvironmentVariableScope_value[v.Scope])
+}
+
+// envVarStore maintains the environment of supervisor's child processes. The environment consists of
+// the supervisor environment and the scoped variables, which can change while the workspace is running.
+type envVarStore struct {
+	cfg *Config
+	// base is the environment of supervisor without any of the scoped variables
+	base []string
+
+	mu sync.RWMutex
+	// vars are the scoped variables of the env var OTS, with the user scoped ones being replaced on reload
+	vars []scopedEnvVar
+	// fileVars are the variables of the workspaceEnvVarsFile
+	fileVars []scopedEnvVar
+}
+
+// newEnvVarStore creates a new env var store based on envvars and the variables of the env var OTS.
+// If envvars is nil, os.Environ() is used.
+func newEnvVarStore(cfg *Config, envvars []string) *envVarStore {
+	if envvars == nil {
+		envvars = os.Environ()
+	}
+
+	var vars []scopedEnvVar
+	if cfg.EnvvarOTS != "" {
+		var err error
+		vars, err = downloadEnvvarOTS(cfg.EnvvarOTS)
+		if err != nil {
+			log.WithError(err).Warn("unable to download environment variables from OTS")
+		}
+	}
+
+	// For the time being the scoped variables are also part of the pod's environment.
+	// We drop them from the base, so that removing them on reload actually removes them.
+	scoped := make(map[string]struct{}, len(vars))
+	for _, v := range vars {
+		scoped[v.Name] = struct{}{}
+	}
+	base := make([]string, 0, len(envvars))
+	for _, e := range envvars {
+		if _, ok := scoped[strings.SplitN(e, "=", 2)[0]]; ok {
+			continue
+		}
+		base = append(base, e)
+	}
+
+	return &envVarStore{
+		cfg:  cfg,
+		base: base,
+		vars: vars,
+	}
+}
+
+// ChildProcEnv computes the current environment of supervisor's child processes.
+func (s *envVarStore) ChildProcEnv() []string {
+	envs := make(map[string]string)
+	for _, v := range s.Variables() {
+		envs[v.Name] = v.Value
+	}
+	return composeChildProcEnv(s.cfg, s.base, envs)
+}
+
+// Variables lists the effective scoped environment variables. Workspace scoped variables take precedence
+// over project scoped ones, which take precedence over user scoped ones.
+func (s *envVarStore) Variables() []*api.EnvironmentVariable {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		res   []*api.EnvironmentVariable
+		index = make(map[string]int)
+	)
+	add := func(v scopedEnvVar) {
+		if isBlacklistedEnvvar(v.Name) {
+			return
+		}
+		ev := &api.EnvironmentVariable{Name: v.Name, Value: v.Value, Scope: v.scope()}
+		if i, exists := index[v.Name]; exists {
+			res[i] = ev
+			return
+		}
+		index[v.Name] = len(res)
+		res = append(res, ev)
+	}
+	for _, scope := range []api.EnvironmentVariableScope{
+		api.EnvironmentVariableScope_system,
+		api.EnvironmentVariableScope_user,
+		api.EnvironmentVariableScope_project,
+		api.EnvironmentVariableScope_workspace,
+	} {
+		for _, v := range s.vars {
+			if v.scope() == scope {
+				add(v)
+			}
+		}
+	}
+	for _, v := range s.fileVars {
+		add(v)
+	}
+	return res
+}
+
+// SetUserVariables replaces all user scoped variables.
+func (s *envVarStore) SetUserVariables(vars []*gitpod.UserEnvVarValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]scopedEnvVar, 0, len(s.vars))
+	for _, v := range s.vars {
+		if v.scope() != api.EnvironmentVariableScope_user {
+			res = append(res, v)
+		}
+	}
+	for _, v := range vars {
+		res = append(res, scopedEnvVar{Name: v.Name, Value: v.Value, Scope: api.EnvironmentVariableScope_user.String()})
+	}
+	s.vars = res
+}
+
+// Watch reads the workspaceEnvVarsFile whenever it changes until the context is canceled.
+func (s *envVarStore) Watch(ctx context.Context, fn string) {
+	err := os.MkdirAll(filepath.Dir(fn), 0755)
+	if err != nil {
+		log.WithError(err).Error("env var watcher: cannot create the directory of the workspace env vars")
+		return
+	}
+	_ = os.Chown(filepath.Dir(fn), gitpodUID, gitpodGID)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.WithError(err).Error("env var watcher: failed to start")
+		return
+	}
+	defer watcher.Close()
+
+	// we watch the directory because the file might not exist yet, or be replaced by editors
+	err = watcher.Add(filepath.Dir(fn))
+	if err != nil {
+		log.WithError(err).Error("env var watcher: failed to start")
+		return
+	}
+	s.readFile(fn)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-watcher.Errors:
+			log.WithError(err).Error("env var watcher: failed to watch")
+		case evt := <-watcher.Events:
+			if evt.Name != fn {
+				continue
+			}
+			s.readFile(fn)
+		}
+	}
+}
+
+func (s *envVarStore) readFile(fn string) {
+	var vars []scopedEnvVar
+	content, err := os.ReadFile(fn)
+	if err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("fn", fn).Warn("cannot read workspace env vars")
+		return
+	}
+	if len(content) > 0 {
+		err = json.Unmarshal(content, &vars)
+		if err != nil {
+			// the file might be written right now - we'll read it again on the next change
+			log.WithError(err).WithField("fn", fn).Debug("cannot parse workspace env vars")
+			return
+		}
+	}
+	for i := range vars {
+		vars[i].Scope = api.EnvironmentVariableScope_workspace.String()
+	}
+
+	s.mu.Lock()
+	s.fileVars = vars
+	s.mu.Unlock()
+}
+
+// filterUserEnvVars selects the user's environment variables which apply to the repository owner/repo,
+// preferring the most specific repository pattern. It mirrors UserEnvVar.filter of the Gitpod protocol.
+func filterUserEnvVars(vars []*gitpod.UserEnvVarValue, owner, repo string) []*gitpod.UserEnvVarValue {
+	var (
+		res   []*gitpod.UserEnvVarValue
+		index = make(map[string]int)
+	)
+	for _, v := range vars {
+		ownerPattern, repoPattern := splitRepositoryPattern(v.RepositoryPattern)
+		if ownerPattern != "*" && ownerPattern != "#" && owner != "" && ownerPattern != strings.ToLower(owner) {
+			continue
+		}
+		if repoPattern != "*" && repoPattern != "#" && repo != "" && repoPattern != strings.ToLower(repo) {
+			continue
+		}
+
+		i, exists := index[v.Name]
+		if !exists {
+			index[v.Name] = len(res)
+			res = append(res, v)
+			continue
+		}
+		if envVarScore(v) < envVarScore(res[i]) {
+			res[i] = v
+		}
+	}
+	return res
+}
+
+// envVarScore determines the precedence of a user env var - the lower the score, the higher the precedence:
+//
+//	value/value = 0
+//	value/*     = 1
+//	*/value     = 2
+//	*/*         = 3
+//	#/#         = 4 (used for env vars passed through the URL)
+func envVarScore(v *gitpod.UserEnvVarValue) int {
+	ownerPattern, repoPattern := splitRepositoryPattern(v.RepositoryPattern)
+	if ownerPattern == "#" || repoPattern == "#" {
+		return 4
+	}
+	var score int
+	if repoPattern == "*" {
+		score += 1
+	}
+	if ownerPattern == "*" {
+		score += 2
+	}
+	return score
+}
+
+func splitRepositoryPattern(pattern string) (ownerPattern, repoPattern string) {
+	segs := strings.SplitN(pattern, "/", 2)
+	ownerPattern = segs[0]
+	if len(segs) > 1 {
+		repoPattern = segs[1]
+	}
+	return
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+)
+
+func TestFilterUserEnvVars(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Owner       string
+		Repo        string
+		Vars        []*gitpod.UserEnvVarValue
+		Expectation []gitpod.UserEnvVarValue
+	}{
+		{
+			Desc:  "filters other repositories",
+			Owner: "gitpod-io",
+			Repo:  "gitpod",
+			Vars: []*gitpod.UserEnvVarValue{
+				{Name: "FOO", Value: "1", RepositoryPattern: "gitpod-io/gitpod"},
+				{Name: "BAR", Value: "2", RepositoryPattern: "gitpod-io/website"},
+				{Name: "BAZ", Value: "3", RepositoryPattern: "*/*"},
+			},
+			Expectation: []gitpod.UserEnvVarValue{
+				{Name: "FOO", Value: "1", RepositoryPattern: "gitpod-io/gitpod"},
+				{Name: "BAZ", Value: "3", RepositoryPattern: "*/*"},
+			},
+		},
+		{
+			Desc:  "most specific pattern wins",
+			Owner: "Gitpod-IO",
+			Repo:  "gitpod",
+			Vars: []*gitpod.UserEnvVarValue{
+				{Name: "FOO", Value: "any", RepositoryPattern: "*/*"},
+				{Name: "FOO", Value: "owner", RepositoryPattern: "gitpod-io/*"},
+				{Name: "FOO", Value: "repo", RepositoryPattern: "*/gitpod"},
+				{Name: "FOO", Value: "other", RepositoryPattern: "gitpod-io/website"},
+			},
+			Expectation: []gitpod.UserEnvVarValue{
+				{Name: "FOO", Value: "owner", RepositoryPattern: "gitpod-io/*"},
+			},
+		},
+		{
+			Desc: "no repository",
+			Vars: []*gitpod.UserEnvVarValue{
+				{Name: "FOO", Value: "1", RepositoryPattern: "gitpod-io/gitpod"},
+			},
+			Expectation: []gitpod.UserEnvVarValue{
+				{Name: "FOO", Value: "1", RepositoryPattern: "gitpod-io/gitpod"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var act []gitpod.UserEnvVarValue
+			for _, v := range filterUserEnvVars(test.Vars, test.Owner, test.Repo) {
+				act = append(act, *v)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEnvVarStore(t *testing.T) {
+	type Variable struct {
+		Name  string
+		Value string
+		Scope string
+	}
+	type Expectation struct {
+		Variables []Variable
+		Env       []string
+	}
+	tests := []struct {
+		Desc        string
+		Env         []string
+		OTS         string
+		UserVars    []*gitpod.UserEnvVarValue
+		File        string
+		Expectation Expectation
+	}{
+		{
+			Desc: "scopes",
+			Env:  []string{"PATH=/bin"},
+			OTS:  `[{"name":"FOO","value":"user","scope":"user"},{"name":"FOO","value":"workspace","scope":"workspace"},{"name":"BAR","value":"project","scope":"project"},{"name":"BAZ","value":"legacy"}]`,
+			Expectation: Expectation{
+				Variables: []Variable{
+					{Name: "BAZ", Value: "legacy", Scope: "system"},
+					{Name: "FOO", Value: "workspace", Scope: "workspace"},
+					{Name: "BAR", Value: "project", Scope: "project"},
+				},
+				Env: []string{"BAR=project", "BAZ=legacy", "FOO=workspace", "HOME=/home/gitpod", "PATH=/bin", "SUPERVISOR_ADDR=localhost:8080", "USER=gitpod"},
+			},
+		},
+		{
+			Desc:     "reload removes user variables",
+			Env:      []string{"FOO=user", "BAR=project"},
+			OTS:      `[{"name":"FOO","value":"user","scope":"user"},{"name":"BAR","value":"project","scope":"project"}]`,
+			UserVars: []*gitpod.UserEnvVarValue{{Name: "NEW", Value: "user"}},
+			Expectation: Expectation{
+				Variables: []Variable{
+					{Name: "NEW", Value: "user", Scope: "user"},
+					{Name: "BAR", Value: "project", Scope: "project"},
+				},
+				Env: []string{"BAR=project", "HOME=/home/gitpod", "NEW=user", "SUPERVISOR_ADDR=localhost:8080", "USER=gitpod"},
+			},
+		},
+		{
+			Desc: "workspace file",
+			OTS:  `[{"name":"FOO","value":"user","scope":"user"}]`,
+			File: `[{"name":"FOO","value":"file"},{"name":"GITPOD_TOKENS","value":"secret"}]`,
+			Expectation: Expectation{
+				Variables: []Variable{
+					{Name: "FOO", Value: "file", Scope: "workspace"},
+				},
+				Env: []string{"FOO=file", "HOME=/home/gitpod", "SUPERVISOR_ADDR=localhost:8080", "USER=gitpod"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			cfg := &Config{StaticConfig: StaticConfig{APIEndpointPort: 8080}}
+			if test.OTS != "" {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(test.OTS))
+				}))
+				defer srv.Close()
+				cfg.EnvvarOTS = srv.URL
+			}
+			env := test.Env
+			if env == nil {
+				env = []string{}
+			}
+
+			store := newEnvVarStore(cfg, env)
+			if test.UserVars != nil {
+				store.SetUserVariables(test.UserVars)
+			}
+			if test.File != "" {
+				fn := filepath.Join(t.TempDir(), "env.json")
+				err := os.WriteFile(fn, []byte(test.File), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				store.readFile(fn)
+			}
+
+			var act Expectation
+			for _, v := range store.Variables() {
+				act.Variables = append(act.Variables, Variable{Name: v.Name, Value: v.Value, Scope: v.Scope.String()})
+			}
+			act.Env = store.ChildProcEnv()
+			sort.Strings(act.Env)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// fileEventsBatchInterval is the time file events are collected before they're sent
+	fileEventsBatchInterval = 100 * time.Millisecond
+	// maxPendingFileEvents is the number of unsent events after which we replace them with an overflow event
+	maxPendingFileEvents = 1000
+)
+
+// fileWatcher watches a file or a directory tree for changes.
+type fileWatcher struct {
+	root      string
+	recursive bool
+	excludes  []string
+
+	watcher *fsnotify.Watcher
+	// dirs are the watched directories
+	dirs map[string]struct{}
+
+	pending    []*api.FileEvent
+	seen       map[fileEventKey]struct{}
+	overflowed bool
+}
+
+type fileEventKey struct {
+	Type api.FileEventType
+	Path string
+}
+
+func newFileWatcher(root string, recursive bool, excludes []string) (*fileWatcher, error) {
+	for _, p := range excludes {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, xerrors.Errorf("invalid exclude pattern %s: %w", p, err)
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &fileWatcher{
+		root:      filepath.Clean(root),
+		recursive: recursive,
+		excludes:  excludes,
+		watcher:   watcher,
+		dirs:      make(map[string]struct{}),
+		seen:      make(map[fileEventKey]struct{}),
+	}
+
+	stat, err := os.Stat(w.root)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	if stat.IsDir() && recursive {
+		err = w.addDir(w.root, false)
+	} else {
+		err = watcher.Add(w.root)
+	}
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Run sends batches of file events until the context is canceled or send fails.
+func (w *fileWatcher) Run(ctx context.Context, send func(events []*api.FileEvent) error) error {
+	defer w.watcher.Close()
+
+	ticker := time.NewTicker(fileEventsBatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-w.watcher.Events:
+			w.handleEvent(evt)
+		case err := <-w.watcher.Errors:
+			if err == fsnotify.ErrEventOverflow {
+				w.overflow()
+				continue
+			}
+			log.WithError(err).WithField("root", w.root).Warn("file watcher error")
+		case <-ticker.C:
+			if len(w.pending) == 0 {
+				continue
+			}
+			err := send(w.pending)
+			if err != nil {
+				return err
+			}
+			w.pending = nil
+			w.seen = make(map[fileEventKey]struct{})
+			w.overflowed = false
+		}
+	}
+}
+
+func (w *fileWatcher) handleEvent(evt fsnotify.Event) {
+	if w.isExcluded(evt.Name) {
+		return
+	}
+	switch {
+	case evt.Op&fsnotify.Create == fsnotify.Create:
+		w.emit(api.FileEventType_created, evt.Name)
+		if !w.recursive {
+			return
+		}
+		if stat, err := os.Lstat(evt.Name); err == nil && stat.IsDir() {
+			// files might have been created in the new directory before we started to watch it
+			err = w.addDir(evt.Name, true)
+			if err != nil {
+				log.WithError(err).WithField("path", evt.Name).Warn("cannot watch directory")
+			}
+		}
+	case evt.Op&fsnotify.Write == fsnotify.Write:
+		w.emit(api.FileEventType_modified, evt.Name)
+	case evt.Op&fsnotify.Remove == fsnotify.Remove, evt.Op&fsnotify.Rename == fsnotify.Rename:
+		w.emit(api.FileEventType_deleted, evt.Name)
+		w.removeDir(evt.Name)
+	}
+}
+
+// addDir watches dir and all its subdirectories. If emit is true, created events are sent for their contents.
+func (w *fileWatcher) addDir(dir string, emit bool) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			// the file might be gone already or we lack permissions - either way there's nothing to watch
+			return nil
+		}
+		if path != dir && w.isExcluded(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if emit && path != dir {
+			w.emit(api.FileEventType_created, path)
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		err = w.watcher.Add(path)
+		if err != nil {
+			return xerrors.Errorf("cannot watch %s: %w", path, err)
+		}
+		w.dirs[path] = struct{}{}
+		return nil
+	})
+}
+
+// removeDir stops watching dir and its subdirectories, e.g. because they were moved away.
+func (w *fileWatcher) removeDir(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for d := range w.dirs {
+		if d != dir && !strings.HasPrefix(d, prefix) {
+			continue
+		}
+		// removed directories are unwatched by the kernel already
+		_ = w.watcher.Remove(d)
+		delete(w.dirs, d)
+	}
+}
+
+func (w *fileWatcher) isExcluded(path string) bool {
+	name := filepath.Base(path)
+	for _, p := range w.excludes {
+		if match, _ := filepath.Match(p, name); match {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *fileWatcher) emit(tpe api.FileEventType, path string) {
+	if w.overflowed {
+		return
+	}
+	key := fileEventKey{Type: tpe, Path: path}
+	if _, exists := w.seen[key]; exists {
+		return
+	}
+	if len(w.pending) >= maxPendingFileEvents {
+		w.overflow()
+		return
+	}
+	w.seen[key] = struct{}{}
+	w.pending = append(w.pending, &api.FileEvent{Type: tpe, Path: path})
+}
+
+// overflow replaces all pending events with an overflow event, because clients have to rescan anyway.
+func (w *fileWatcher) overflow() {
+	w.pending = []*api.FileEvent{{Type: api.FileEventType_overflow}}
+	w.overflowed = true
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestFileWatcher(t *testing.T) {
+	type step struct {
+		Op func(root string) error
+		// Expectation lists the events in the form "type path", with paths relative to the root
+		Expectation []string
+	}
+	createFile := func(name string) func(root string) error {
+		return func(root string) error {
+			f, err := os.Create(filepath.Join(root, name))
+			if err != nil {
+				return err
+			}
+			return f.Close()
+		}
+	}
+	writeFile := func(name string) func(root string) error {
+		return func(root string) error {
+			return os.WriteFile(filepath.Join(root, name), []byte("content"), 0644)
+		}
+	}
+	mkdir := func(name string) func(root string) error {
+		return func(root string) error {
+			return os.MkdirAll(filepath.Join(root, name), 0755)
+		}
+	}
+	remove := func(name string) func(root string) error {
+		return func(root string) error {
+			return os.RemoveAll(filepath.Join(root, name))
+		}
+	}
+
+	tests := []struct {
+		Desc      string
+		Recursive bool
+		Excludes  []string
+		Setup     []string
+		Steps     []step
+	}{
+		{
+			Desc: "files",
+			Steps: []step{
+				{Op: createFile("foo"), Expectation: []string{"created foo"}},
+				{Op: writeFile("foo"), Expectation: []string{"modified foo"}},
+				{Op: remove("foo"), Expectation: []string{"deleted foo"}},
+			},
+		},
+		{
+			Desc:  "not recursive",
+			Setup: []string{"dir"},
+			Steps: []step{
+				{Op: createFile("dir/foo")},
+				{Op: createFile("bar"), Expectation: []string{"created bar"}},
+			},
+		},
+		{
+			Desc:      "recursive",
+			Recursive: true,
+			Setup:     []string{"dir"},
+			Steps: []step{
+				{Op: createFile("dir/foo"), Expectation: []string{"created dir/foo"}},
+				{Op: mkdir("new/sub"), Expectation: []string{"created new", "created new/sub"}},
+				{Op: createFile("new/sub/bar"), Expectation: []string{"created new/sub/bar"}},
+				{Op: remove("new"), Expectation: []string{"deleted new", "deleted new/sub", "deleted new/sub/bar"}},
+			},
+		},
+		{
+			Desc:      "excludes",
+			Recursive: true,
+			Excludes:  []string{"node_modules", "*.log"},
+			Setup:     []string{"node_modules"},
+			Steps: []step{
+				{Op: createFile("node_modules/foo")},
+				{Op: createFile("debug.log")},
+				{Op: createFile("foo"), Expectation: []string{"created foo"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range test.Setup {
+				err := os.MkdirAll(filepath.Join(root, dir), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			watcher, err := newFileWatcher(root, test.Recursive, test.Excludes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events := make(chan []*api.FileEvent)
+			go func() {
+				_ = watcher.Run(ctx, func(evts []*api.FileEvent) error {
+					select {
+					case events <- evts:
+						return nil
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				})
+			}()
+
+			for i, s := range test.Steps {
+				err := s.Op(root)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// we wait for a few batches because events of one operation might end up in different batches
+				var act []string
+				timeout := time.After(5 * fileEventsBatchInterval)
+			collect:
+				for {
+					select {
+					case evts := <-events:
+						for _, evt := range evts {
+							rel, _ := filepath.Rel(root, evt.Path)
+							act = append(act, evt.Type.String()+" "+rel)
+						}
+					case <-timeout:
+						break collect
+					}
+				}
+				act = uniqueSorted(act)
+
+				if diff := cmp.Diff(test.Steps[i].Expectation, act); diff != "" {
+					t.Errorf("unexpected result in step %d (-want +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func uniqueSorted(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	sort.Strings(s)
+	res := s[:1]
+	for _, e := range s[1:] {
+		if e != res[len(res)-1] {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func TestFileWatcherOverflow(t *testing.T) {
+	w := &fileWatcher{seen: make(map[fileEventKey]struct{})}
+	for i := 0; i < maxPendingFileEvents+10; i++ {
+		w.emit(api.FileEventType_created, fmt.Sprintf("/workspace/file-%d", i))
+	}
+
+	var act []api.FileEventType
+	for _, evt := range w.pending {
+		act = append(act, evt.Type)
+	}
+	if diff := cmp.Diff([]api.FileEventType{api.FileEventType_overflow}, act); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
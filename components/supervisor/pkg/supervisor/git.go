@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/gitpod-io/gitpod/common-go/log"
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
 )
@@ -85,6 +87,16 @@ func (p *GitTokenProvider) GetToken(ctx context.Context, req *api.GetTokenReques
 		Scope: scopes,
 		Reuse: api.TokenReuse_REUSE_NEVER,
 	}
+	if token.ExpiryDate != "" {
+		expiryDate, err := time.Parse(time.RFC3339, token.ExpiryDate)
+		if err != nil {
+			log.WithError(err).WithField("host", req.Host).Warn("cannot parse expiry date of git token")
+			return tkn, nil
+		}
+		// short-lived tokens are cached, so that the tokenRefresher can renew them before they expire
+		tkn.ExpiryDate = &expiryDate
+		tkn.Reuse = api.TokenReuse_REUSE_WHEN_POSSIBLE
+	}
 	return tkn, nil
 }
 
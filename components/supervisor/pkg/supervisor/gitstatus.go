@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// maxGitStatusFiles is the number of files beyond which we no longer report changed files.
+const maxGitStatusFiles = 1000
+
+var gitChanges = map[byte]api.GitFileStatus_Change{
+	'.': api.GitFileStatus_unmodified,
+	'M': api.GitFileStatus_modified,
+	'T': api.GitFileStatus_type_changed,
+	'A': api.GitFileStatus_added,
+	'D': api.GitFileStatus_deleted,
+	'R': api.GitFileStatus_renamed,
+	'C': api.GitFileStatus_copied,
+	'U': api.GitFileStatus_unmerged,
+}
+
+// parseGitStatus parses the output of "git status --porcelain=v2 --branch -z",
+// see https://git-scm.com/docs/git-status#_porcelain_format_version_2
+func parseGitStatus(out []byte) (*api.GitStatusResponse, error) {
+	var (
+		res     api.GitStatusResponse
+		entries = strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+		addFile = func(f *api.GitFileStatus) {
+			res.TotalFiles++
+			if len(res.Files) < maxGitStatusFiles {
+				res.Files = append(res.Files, f)
+			}
+		}
+	)
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
+			continue
+		}
+		switch entry[0] {
+		case '#':
+			header := strings.SplitN(entry, " ", 3)
+			if len(header) < 3 {
+				continue
+			}
+			switch header[1] {
+			case "branch.oid":
+				res.Commit = header[2]
+			case "branch.head":
+				res.Branch = header[2]
+			case "branch.upstream":
+				res.Upstream = header[2]
+			case "branch.ab":
+				_, err := fmt.Sscanf(header[2], "+%d -%d", &res.Ahead, &res.Behind)
+				if err != nil {
+					return nil, xerrors.Errorf("invalid branch.ab header %q: %w", entry, err)
+				}
+			}
+		case '1', '2', 'u':
+			// fields before the path: 8 for ordinary, 9 for renamed or copied and 10 for unmerged entries
+			n := 8
+			if entry[0] == '2' {
+				n = 9
+			} else if entry[0] == 'u' {
+				n = 10
+			}
+			fields := strings.SplitN(entry, " ", n+1)
+			if len(fields) != n+1 || len(fields[1]) != 2 {
+				return nil, xerrors.Errorf("invalid status entry %q", entry)
+			}
+			f := &api.GitFileStatus{
+				Path:     fields[n],
+				Staged:   gitChanges[fields[1][0]],
+				Unstaged: gitChanges[fields[1][1]],
+			}
+			if entry[0] == 'u' {
+				f.Staged, f.Unstaged = api.GitFileStatus_unmerged, api.GitFileStatus_unmerged
+			}
+			if entry[0] == '2' {
+				// the original path is the next NUL-separated entry
+				i++
+				if i >= len(entries) {
+					return nil, xerrors.Errorf("missing original path of %q", entry)
+				}
+				f.OriginalPath = entries[i]
+			}
+			addFile(f)
+		case '?':
+			addFile(&api.GitFileStatus{
+				Path:     strings.TrimPrefix(entry, "? "),
+				Unstaged: api.GitFileStatus_untracked,
+			})
+		}
+	}
+	return &res, nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+var ignoreGitUnexported = cmpopts.IgnoreUnexported(api.GitStatusResponse{}, api.GitFileStatus{}, api.RefreshGitCredentialsResponse{})
+
+func TestParseGitStatus(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Input       string
+		Expectation *api.GitStatusResponse
+		Error       string
+	}{
+		{
+			Desc: "changes",
+			Input: strings.Join([]string{
+				"# branch.oid 68265e04346e5955f738765ef65fe51ae3602d9c",
+				"# branch.head main",
+				"# branch.upstream origin/main",
+				"# branch.ab +1 -2",
+				"1 .M N... 100644 100644 100644 9f8efaf877a34290b1ec672ea76ed23ae1f6fc0e 9f8efaf877a34290b1ec672ea76ed23ae1f6fc0e file with spaces.go",
+				"1 A. N... 000000 100644 100644 0000000000000000000000000000000000000000 fecd2cb9388debfa8b55eff29042940c6b48e34f added.go",
+				"2 R. N... 100644 100644 100644 21d9ddf581bb8c76d4c57e751d6ca70f20d6d988 21d9ddf581bb8c76d4c57e751d6ca70f20d6d988 R100 new.go",
+				"old.go",
+				"u UU N... 100644 100644 100644 100644 0fdd088bb302577f8d9f7b1e24bf1982f1e0b339 5aebcbc6f8e2c400440b08e1ef9a8cf207121ba0 afb7787839a21a1c6ec96b11ac771966e6e59e27 conflict.go",
+				"? untracked.go",
+				"",
+			}, "\x00"),
+			Expectation: &api.GitStatusResponse{
+				Branch:   "main",
+				Commit:   "68265e04346e5955f738765ef65fe51ae3602d9c",
+				Upstream: "origin/main",
+				Ahead:    1,
+				Behind:   2,
+				Files: []*api.GitFileStatus{
+					{Path: "file with spaces.go", Unstaged: api.GitFileStatus_modified},
+					{Path: "added.go", Staged: api.GitFileStatus_added},
+					{Path: "new.go", OriginalPath: "old.go", Staged: api.GitFileStatus_renamed},
+					{Path: "conflict.go", Staged: api.GitFileStatus_unmerged, Unstaged: api.GitFileStatus_unmerged},
+					{Path: "untracked.go", Unstaged: api.GitFileStatus_untracked},
+				},
+				TotalFiles: 5,
+			},
+		},
+		{
+			Desc:  "no commits yet",
+			Input: "# branch.oid (initial)\x00# branch.head main\x00",
+			Expectation: &api.GitStatusResponse{
+				Branch: "main",
+				Commit: "(initial)",
+			},
+		},
+		{
+			Desc:  "truncated",
+			Input: strings.Repeat("? f\x00", maxGitStatusFiles+1),
+			Expectation: func() *api.GitStatusResponse {
+				res := &api.GitStatusResponse{TotalFiles: maxGitStatusFiles + 1}
+				for i := 0; i < maxGitStatusFiles; i++ {
+					res.Files = append(res.Files, &api.GitFileStatus{Path: "f", Unstaged: api.GitFileStatus_untracked})
+				}
+				return res
+			}(),
+		},
+		{
+			Desc:  "missing original path",
+			Input: "2 R. N... 100644 100644 100644 21d9ddf581bb8c76d4c57e751d6ca70f20d6d988 21d9ddf581bb8c76d4c57e751d6ca70f20d6d988 R100 new.go\x00",
+			Error: "missing original path",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act, err := parseGitStatus([]byte(test.Input))
+			if test.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Error) {
+					t.Errorf("expected error containing %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, act, ignoreGitUnexported); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGitService(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	var (
+		ctx    = context.Background()
+		tmpdir = t.TempDir()
+		env    = append(os.Environ(),
+			"HOME="+tmpdir,
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@gitpod.io",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@gitpod.io",
+		)
+		run = func(dir string, args ...string) {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			cmd.Env = env
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+			}
+		}
+		origin = filepath.Join(tmpdir, "origin")
+		other  = filepath.Join(tmpdir, "other")
+	)
+	run(tmpdir, "init", "-q", "--bare", "-b", "main", origin)
+	run(tmpdir, "clone", "-q", origin, other)
+	run(other, "commit", "-q", "--allow-empty", "-m", "initial")
+	run(other, "push", "-q", "origin", "HEAD:main")
+	run(tmpdir, "clone", "-q", "-b", "main", origin, filepath.Join(tmpdir, "repo"))
+	run(other, "commit", "-q", "--allow-empty", "-m", "second")
+	run(other, "push", "-q", "origin", "HEAD:main")
+	err := os.WriteFile(filepath.Join(tmpdir, "repo", "untracked.txt"), nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contentState := NewInMemoryContentState(tmpdir)
+	srv := &gitService{RepoRoot: tmpdir, ContentState: contentState, Env: env}
+	_, err = srv.Status(ctx, &api.GitStatusRequest{Location: "repo"})
+	if err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Fatalf("expected an error before the content is ready, got %v", err)
+	}
+	contentState.MarkContentReady(csapi.WorkspaceInitFromOther)
+
+	status := func() *api.GitStatusResponse {
+		res, err := srv.Status(ctx, &api.GitStatusRequest{Location: "repo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Commit = ""
+		return res
+	}
+	expectation := &api.GitStatusResponse{
+		Branch:     "main",
+		Upstream:   "origin/main",
+		Files:      []*api.GitFileStatus{{Path: "untracked.txt", Unstaged: api.GitFileStatus_untracked}},
+		TotalFiles: 1,
+	}
+	if diff := cmp.Diff(expectation, status(), ignoreGitUnexported); diff != "" {
+		t.Errorf("unexpected status before fetch (-want +got):\n%s", diff)
+	}
+
+	_, err = srv.Fetch(ctx, &api.GitFetchRequest{Location: "repo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation.Behind = 1
+	if diff := cmp.Diff(expectation, status(), ignoreGitUnexported); diff != "" {
+		t.Errorf("unexpected status after fetch (-want +got):\n%s", diff)
+	}
+
+	_, err = srv.Pull(ctx, &api.GitPullRequest{Location: "repo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation.Behind = 0
+	if diff := cmp.Diff(expectation, status(), ignoreGitUnexported); diff != "" {
+		t.Errorf("unexpected status after pull (-want +got):\n%s", diff)
+	}
+
+	_, err = srv.Status(ctx, &api.GitStatusRequest{})
+	if err == nil || !strings.Contains(err.Error(), "not a git repository") {
+		t.Errorf("expected an error outside of a working copy, got %v", err)
+	}
+}
+
+func TestGitServiceLocationOutsideRepoRoot(t *testing.T) {
+	contentState := NewInMemoryContentState("")
+	contentState.MarkContentReady(csapi.WorkspaceInitFromOther)
+	srv := &gitService{RepoRoot: "/workspace", ContentState: contentState}
+
+	for _, location := range []string{"..", "../etc", "repo/../../etc", "/../etc"} {
+		_, err := srv.Status(context.Background(), &api.GitStatusRequest{Location: location})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for location %q, got %v", location, err)
+		}
+	}
+}
+
+func TestGitServiceRefreshCredentials(t *testing.T) {
+	var calls int
+	tokens := NewInMemoryTokenService()
+	tokens.provider[KindGit] = []tokenProvider{tokenProviderFunc(func(ctx context.Context, req *api.GetTokenRequest) (tkn *Token, err error) {
+		calls++
+		return &Token{Host: req.Host, User: "gitpod", Token: "fresh", Scope: mapScopes([]string{"repo"}), Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE}, nil
+	})}
+	tokens.token[KindGit] = []*Token{
+		{Host: "github.com", User: "gitpod", Token: "stale"},
+		{Host: "gitlab.com", User: "gitpod", Token: "other"},
+	}
+
+	srv := &gitService{Tokens: tokens}
+	act, err := srv.RefreshCredentials(context.Background(), &api.RefreshGitCredentialsRequest{Host: "github.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&api.RefreshGitCredentialsResponse{User: "gitpod", Scope: []string{"repo"}}, act, ignoreGitUnexported); diff != "" {
+		t.Errorf("unexpected response (-want +got):\n%s", diff)
+	}
+	if calls != 1 {
+		t.Errorf("expected the token provider to be asked once, was asked %d times", calls)
+	}
+	if len(tokens.token[KindGit]) != 2 || tokens.token[KindGit][0].Host != "gitlab.com" || tokens.token[KindGit][1].Token != "fresh" {
+		t.Errorf("unexpected cached tokens %v", tokens.token[KindGit])
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// headlessLogPollInterval is the time we wait for new output before looking at a prebuild log again
+const headlessLogPollInterval = 500 * time.Millisecond
+
+// serveHeadlessLog serves the prebuild log of a headless task, i.e. GET <task-id>?offset=<bytes>.
+// The response starts at the given byte offset and follows the log until the task is done. Slow clients
+// slow down the response, as we only read from the log as fast as they consume it.
+func (tm *tasksManager) serveHeadlessLog(w http.ResponseWriter, r *http.Request) {
+	if !tm.config.isHeadless() {
+		http.NotFound(w, r)
+		return
+	}
+	var offset int64
+	if o := r.URL.Query().Get("offset"); o != "" {
+		var err error
+		offset, err = strconv.ParseInt(o, 10, 64)
+		if err != nil || offset < 0 {
+			http.Error(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	select {
+	case <-tm.ready:
+	case <-r.Context().Done():
+		return
+	}
+	taskID := strings.TrimPrefix(r.URL.Path, "/")
+	var t *task
+	tm.mu.RLock()
+	for _, candidate := range tm.tasks {
+		if candidate.Id == taskID {
+			t = candidate
+			break
+		}
+	}
+	tm.mu.RUnlock()
+	if t == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	flusher, _ := w.(http.Flusher)
+
+	var (
+		fn   = prebuildLogFileName(t, tm.storeLocation)
+		file *os.File
+	)
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+	for {
+		// we must check if the task is done before reading, so that we don't miss the last bit of its log
+		var done bool
+		select {
+		case <-t.logDone:
+			done = true
+		default:
+		}
+
+		if file == nil {
+			f, err := os.Open(fn)
+			if err != nil && !os.IsNotExist(err) {
+				log.WithError(err).WithField("task", taskID).Error("cannot open prebuild log")
+				http.Error(w, "cannot open prebuild log", http.StatusInternalServerError)
+				return
+			}
+			if err == nil {
+				file = f
+				_, err = file.Seek(offset, io.SeekStart)
+				if err != nil {
+					http.Error(w, "invalid offset", http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		if file != nil {
+			n, err := io.Copy(w, file)
+			if err != nil {
+				// the client has gone away
+				return
+			}
+			if n > 0 && flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if done {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-t.logDone:
+		case <-time.After(headlessLogPollInterval):
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestServeHeadlessLog(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Headless       bool
+		Path           string
+		Log            string
+		Expectation    string
+		ExpectedStatus int
+	}{
+		{Name: "not headless", Path: "/0", ExpectedStatus: http.StatusNotFound},
+		{Name: "unknown task", Headless: true, Path: "/1", ExpectedStatus: http.StatusNotFound},
+		{Name: "invalid offset", Headless: true, Path: "/0?offset=foo", ExpectedStatus: http.StatusBadRequest},
+		{Name: "no log", Headless: true, Path: "/0", ExpectedStatus: http.StatusOK},
+		{Name: "full log", Headless: true, Path: "/0", Log: "hello world", Expectation: "hello world", ExpectedStatus: http.StatusOK},
+		{Name: "with offset", Headless: true, Path: "/0?offset=6", Log: "hello world", Expectation: "world", ExpectedStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			storeLocation := t.TempDir()
+			tsk := &task{TaskStatus: api.TaskStatus{Id: "0"}, logDone: make(chan struct{})}
+			close(tsk.logDone)
+			if test.Log != "" {
+				err := os.WriteFile(prebuildLogFileName(tsk, storeLocation), []byte(test.Log), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var cfg Config
+			if test.Headless {
+				cfg.WorkspaceConfig.GitpodHeadless = "true"
+			}
+			tm := &tasksManager{
+				config:        &cfg,
+				storeLocation: storeLocation,
+				tasks:         []*task{tsk},
+				ready:         make(chan struct{}),
+			}
+			close(tm.ready)
+
+			rec := httptest.NewRecorder()
+			tm.serveHeadlessLog(rec, httptest.NewRequest(http.MethodGet, test.Path, nil))
+
+			if rec.Code != test.ExpectedStatus {
+				t.Errorf("unexpected status: want %d, got %d", test.ExpectedStatus, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			if diff := cmp.Diff(test.Expectation, rec.Body.String()); diff != "" {
+				t.Errorf("unexpected log (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServeHeadlessLogFollows(t *testing.T) {
+	storeLocation := t.TempDir()
+	tsk := &task{TaskStatus: api.TaskStatus{Id: "0"}, logDone: make(chan struct{})}
+	fn := prebuildLogFileName(tsk, storeLocation)
+	err := os.WriteFile(fn, []byte("hello"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm := &tasksManager{
+		config:        &Config{WorkspaceConfig: WorkspaceConfig{GitpodHeadless: "true"}},
+		storeLocation: storeLocation,
+		tasks:         []*task{tsk},
+		ready:         make(chan struct{}),
+	}
+	close(tm.ready)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		f, err := os.OpenFile(fn, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _ = f.Write([]byte(" world"))
+		f.Close()
+		close(tsk.logDone)
+	}()
+
+	rec := httptest.NewRecorder()
+	tm.serveHeadlessLog(rec, httptest.NewRequest(http.MethodGet, "/0", nil))
+	if diff := cmp.Diff("hello world", rec.Body.String()); diff != "" {
+		t.Errorf("unexpected log (-want +got):\n%s", diff)
+	}
+}
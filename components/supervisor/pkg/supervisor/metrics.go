@@ -0,0 +1,128 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"time"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+)
+
+const metricsNamespace = "gitpod_supervisor"
+
+// metrics are the Prometheus metrics supervisor serves on /metrics if enabled.
+type metrics struct {
+	Registry *prometheus.Registry
+	GRPC     *grpc_prometheus.ServerMetrics
+
+	start               time.Time
+	contentInitDuration *prometheus.GaugeVec
+	ideReadyDuration    *prometheus.GaugeVec
+}
+
+func newMetrics(tasks *tasksManager, ports *ports.Manager) *metrics {
+	reg := prometheus.NewRegistry()
+
+	grpcMetrics := grpc_prometheus.NewServerMetrics()
+	grpcMetrics.EnableHandlingTimeHistogram()
+
+	m := &metrics{
+		Registry: reg,
+		GRPC:     grpcMetrics,
+		start:    time.Now(),
+		contentInitDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "content_init_duration_seconds",
+			Help:      "Time from supervisor start until the workspace content was available",
+		}, []string{"source"}),
+		ideReadyDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "ide_ready_duration_seconds",
+			Help:      "Time from supervisor start until the IDE was ready",
+		}, []string{"kind"}),
+	}
+	reg.MustRegister(
+		grpcMetrics,
+		m.contentInitDuration,
+		m.ideReadyDuration,
+		&tasksCollector{tasks: tasks},
+		&portsCollector{ports: ports},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// ObserveContentInit records the content init duration once the content is ready.
+func (m *metrics) ObserveContentInit(cstate ContentState) {
+	<-cstate.ContentReady()
+	src, _ := cstate.ContentSource()
+	m.contentInitDuration.WithLabelValues(string(src)).Set(time.Since(m.start).Seconds())
+}
+
+// ObserveIDEReady records the time it took the IDE to become ready for the first time.
+func (m *metrics) ObserveIDEReady(kind IDEKind, ideReady *ideReadyState) {
+	<-ideReady.Wait()
+	label := "web"
+	if kind == DesktopIDE {
+		label = "desktop"
+	}
+	m.ideReadyDuration.WithLabelValues(label).Set(time.Since(m.start).Seconds())
+}
+
+// tasksCollector reports the number of tasks per state at scrape time.
+type tasksCollector struct {
+	tasks *tasksManager
+}
+
+var tasksDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "tasks"), "Number of tasks per state", []string{"state"}, nil)
+
+func (c *tasksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tasksDesc
+}
+
+func (c *tasksCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := make(map[api.TaskState]int, len(api.TaskState_name))
+	for _, t := range c.tasks.Status() {
+		counts[t.State]++
+	}
+	for state, name := range api.TaskState_name {
+		ch <- prometheus.MustNewConstMetric(tasksDesc, prometheus.GaugeValue, float64(counts[api.TaskState(state)]), name)
+	}
+}
+
+// portsCollector reports the number of served, exposed and tunneled ports at scrape time.
+type portsCollector struct {
+	ports *ports.Manager
+}
+
+var portsDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "ports"), "Number of ports per state", []string{"state"}, nil)
+
+func (c *portsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- portsDesc
+}
+
+func (c *portsCollector) Collect(ch chan<- prometheus.Metric) {
+	var served, exposed, tunneled int
+	for _, p := range c.ports.Status() {
+		if p.Served {
+			served++
+		}
+		if p.Exposed != nil {
+			exposed++
+		}
+		if p.Tunneled != nil {
+			tunneled++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(portsDesc, prometheus.GaugeValue, float64(served), "served")
+	ch <- prometheus.MustNewConstMetric(portsDesc, prometheus.GaugeValue, float64(exposed), "exposed")
+	ch <- prometheus.MustNewConstMetric(portsDesc, prometheus.GaugeValue, float64(tunneled), "tunneled")
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestTasksCollector(t *testing.T) {
+	tm := &tasksManager{
+		tasks: []*task{
+			{TaskStatus: api.TaskStatus{Id: "0", State: api.TaskState_running}},
+			{TaskStatus: api.TaskStatus{Id: "1", State: api.TaskState_running}},
+			{TaskStatus: api.TaskStatus{Id: "2", State: api.TaskState_closed}},
+		},
+	}
+
+	expectation := `
+# HELP gitpod_supervisor_tasks Number of tasks per state
+# TYPE gitpod_supervisor_tasks gauge
+gitpod_supervisor_tasks{state="closed"} 1
+gitpod_supervisor_tasks{state="opening"} 0
+gitpod_supervisor_tasks{state="running"} 2
+`
+	err := testutil.CollectAndCompare(&tasksCollector{tasks: tm}, strings.NewReader(expectation))
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -6,12 +6,14 @@ package supervisor
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/supervisor/api"
@@ -22,11 +24,15 @@ const (
 	SubscriberMaxPendingNotifications = 100
 )
 
+// NotificationCommandHandler runs a command the user chose in response to a notification.
+type NotificationCommandHandler func(ctx context.Context, args []string) error
+
 // NewNotificationService creates a new notification service.
 func NewNotificationService() *NotificationService {
 	return &NotificationService{
 		subscriptions:        make(map[uint64]*subscription),
 		pendingNotifications: make(map[uint64]*pendingNotification),
+		commands:             make(map[string]NotificationCommandHandler),
 	}
 }
 
@@ -37,6 +43,7 @@ type NotificationService struct {
 	subscriptions        map[uint64]*subscription
 	nextNotificationID   uint64
 	pendingNotifications map[uint64]*pendingNotification
+	commands             map[string]NotificationCommandHandler
 
 	api.UnimplementedNotificationServiceServer
 }
@@ -81,11 +88,34 @@ func (srv *NotificationService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint
 	return api.RegisterNotificationServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
 }
 
+// RegisterCommand registers a command which notifications can offer to the user.
+func (srv *NotificationService) RegisterCommand(name string, handler NotificationCommandHandler) {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	srv.commands[name] = handler
+}
+
 // Notify sends a notification to the user.
 func (srv *NotificationService) Notify(ctx context.Context, req *api.NotifyRequest) (*api.NotifyResponse, error) {
 	if len(srv.pendingNotifications) >= NotifierMaxPendingNotifications {
 		return nil, status.Error(codes.ResourceExhausted, "Max number of pending notifications exceeded")
 	}
+	if len(req.Commands) > 0 {
+		srv.mutex.Lock()
+		for _, cmd := range req.Commands {
+			if _, ok := srv.commands[cmd.Command]; !ok {
+				srv.mutex.Unlock()
+				return nil, status.Errorf(codes.InvalidArgument, "Unknown notification command %q", cmd.Command)
+			}
+		}
+		srv.mutex.Unlock()
+
+		// subscribers only know about actions
+		req = proto.Clone(req).(*api.NotifyRequest)
+		for _, cmd := range req.Commands {
+			req.Actions = append(req.Actions, cmd.Title)
+		}
+	}
 
 	pending := srv.notifySubscribers(req)
 	select {
@@ -233,9 +263,36 @@ func (srv *NotificationService) Respond(ctx context.Context, req *api.RespondReq
 		pending.close()
 	}
 	delete(srv.pendingNotifications, pending.message.RequestId)
+	for _, cmd := range pending.message.Request.Commands {
+		if cmd.Title == req.Response.Action {
+			go srv.runCommand(cmd)
+			break
+		}
+	}
 	return &api.RespondResponse{}, nil
 }
 
+// runCommand runs a command the user chose and notifies the user if it fails.
+func (srv *NotificationService) runCommand(cmd *api.NotificationCommand) {
+	srv.mutex.Lock()
+	handler := srv.commands[cmd.Command]
+	srv.mutex.Unlock()
+
+	log.WithField("command", cmd.Command).WithField("args", cmd.Args).Info("running notification command")
+	err := handler(context.Background(), cmd.Args)
+	if err == nil {
+		return
+	}
+	log.WithError(err).WithField("command", cmd.Command).WithField("args", cmd.Args).Warn("notification command failed")
+	_, err = srv.Notify(context.Background(), &api.NotifyRequest{
+		Level:   api.NotifyRequest_ERROR,
+		Message: fmt.Sprintf("%s failed: %v", cmd.Title, err),
+	})
+	if err != nil {
+		log.WithError(err).Warn("cannot notify about failed notification command")
+	}
+}
+
 func isActionAllowed(action string, req *api.NotifyRequest) bool {
 	if action == "" {
 		// user cancelled, which is always allowed
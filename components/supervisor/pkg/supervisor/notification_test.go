@@ -262,4 +262,60 @@ func Test(t *testing.T) {
 		}
 		wg.Wait()
 	})
+	t.Run("Command is run when chosen", func(t *testing.T) {
+		notificationService := NewNotificationService()
+		ran := make(chan []string, 1)
+		notificationService.RegisterCommand("test-command", func(ctx context.Context, args []string) error {
+			ran <- args
+			return nil
+		})
+
+		subscriber := NewSubscribeServer()
+		defer subscriber.cancel()
+		go func() {
+			notification := <-subscriber.resps
+			if len(notification.Request.Actions) != 2 || notification.Request.Actions[1] != "Run" {
+				t.Errorf("expected the command title to be offered as action, got %v", notification.Request.Actions)
+			}
+			_, _ = notificationService.Respond(subscriber.context, &api.RespondRequest{
+				RequestId: notification.RequestId,
+				Response:  &api.NotifyResponse{Action: "Run"},
+			})
+		}()
+		go func() {
+			_ = notificationService.Subscribe(&api.SubscribeRequest{}, subscriber)
+		}()
+		notifyResponse, err := notificationService.Notify(subscriber.context, &api.NotifyRequest{
+			Level:    api.NotifyRequest_INFO,
+			Message:  "Run the command?",
+			Actions:  []string{"Ignore"},
+			Commands: []*api.NotificationCommand{{Title: "Run", Command: "test-command", Args: []string{"arg"}}},
+		})
+		if err != nil {
+			t.Fatalf("error receiving user action %s", err)
+		}
+		if notifyResponse.Action != "Run" {
+			t.Errorf("expected response 'Run' but was '%s'", notifyResponse.Action)
+		}
+		select {
+		case args := <-ran:
+			if len(args) != 1 || args[0] != "arg" {
+				t.Errorf("unexpected command args %v", args)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("command was not run")
+		}
+	})
+
+	t.Run("Unknown command is rejected", func(t *testing.T) {
+		notificationService := NewNotificationService()
+		_, err := notificationService.Notify(context.Background(), &api.NotifyRequest{
+			Level:    api.NotifyRequest_INFO,
+			Message:  "Run the command?",
+			Commands: []*api.NotificationCommand{{Title: "Run", Command: "unknown"}},
+		})
+		if err == nil {
+			t.Errorf("expected error on unknown command")
+		}
+	})
 }
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+// sessionRecordingsLocation is where terminal and SSH sessions are recorded to if session recording is enabled.
+const sessionRecordingsLocation = "/workspace/.gitpod/recordings"
+
+// newSessionRecordings returns the session recordings configured for this workspace, or nil if
+// sessions aren't recorded.
+func newSessionRecordings(cfg *Config) *terminal.Recordings {
+	if !cfg.SessionRecording {
+		return nil
+	}
+	return &terminal.Recordings{
+		Dir:          sessionRecordingsLocation,
+		MaxFileSize:  cfg.SessionRecordingMaxFileSize,
+		MaxTotalSize: cfg.SessionRecordingMaxTotalSize,
+	}
+}
+
+// prepareSessionRecordings creates the recordings directory once the workspace content is ready.
+// SSH sessions are recorded by the gitpod user, hence the directory belongs to them.
+func prepareSessionRecordings(ctx context.Context, recordings *terminal.Recordings, cstate ContentState) {
+	select {
+	case <-cstate.ContentReady():
+	case <-ctx.Done():
+		return
+	}
+
+	err := os.MkdirAll(recordings.Dir, 0755)
+	if err == nil {
+		err = os.Chown(recordings.Dir, gitpodUID, gitpodGID)
+	}
+	if err != nil {
+		log.WithError(err).WithField("dir", recordings.Dir).Error("cannot prepare session recordings")
+	}
+}
+
+// sshRecordSessionCommand is the command sshd forces for every session so that it gets recorded.
+func sshRecordSessionCommand(bin string, recordings *terminal.Recordings) string {
+	return fmt.Sprintf("%s record-session --dir %s --max-file-size %d --max-total-size %d", bin, recordings.Dir, recordings.MaxFileSize, recordings.MaxTotalSize)
+}
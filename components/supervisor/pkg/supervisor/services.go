@@ -7,24 +7,39 @@ package supervisor
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sourcegraph/jsonrpc2"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/sys/unix"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/activity"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/resources"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
 )
 
 // RegisterableService can register a service.
@@ -93,8 +108,11 @@ type statusService struct {
 	ContentState    ContentState
 	Ports           *ports.Manager
 	Tasks           *tasksManager
+	Resources       *resources.Sampler
+	Terminals       *terminal.MuxTerminalService
 	ideReady        *ideReadyState
 	desktopIdeReady *ideReadyState
+	desktopIDE      *desktopIDEManager
 
 	api.UnimplementedStatusServiceServer
 }
@@ -153,6 +171,9 @@ func (s *statusService) IDEStatus(ctx context.Context, req *api.IDEStatusRequest
 		}
 		ok = ok && okR
 	}
+	if s.desktopIDE != nil {
+		desktopStatus.Phase, desktopStatus.Restarts, desktopStatus.Message = s.desktopIDE.Status()
+	}
 	return &api.IDEStatusResponse{Ok: ok, Desktop: desktopStatus}, nil
 }
 
@@ -195,6 +216,32 @@ func (s *statusService) ContentStatus(ctx context.Context, req *api.ContentStatu
 	}, nil
 }
 
+func (s *statusService) ContentProgress(req *api.ContentProgressRequest, srv api.StatusService_ContentProgressServer) error {
+	for {
+		p, changed := s.ContentState.ContentProgress()
+		_, available := s.ContentState.ContentSource()
+		err := srv.Send(&api.ContentProgressResponse{
+			Phase:      string(p.Phase),
+			Percent:    int32(p.Percent),
+			BytesDone:  p.BytesDone,
+			BytesTotal: p.BytesTotal,
+			Available:  available,
+		})
+		if err != nil {
+			return err
+		}
+		if available {
+			return nil
+		}
+
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
 func (s *statusService) BackupStatus(ctx context.Context, req *api.BackupStatusRequest) (*api.BackupStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
@@ -233,6 +280,138 @@ func (s *statusService) PortsStatus(req *api.PortsStatusRequest, srv api.StatusS
 	}
 }
 
+func (s *statusService) PortsStatusChanges(req *api.PortsStatusChangesRequest, srv api.StatusService_PortsStatusChangesServer) error {
+	sub, err := s.Ports.SubscribeChanges()
+	if err == ports.ErrTooManySubscriptions {
+		return status.Error(codes.ResourceExhausted, "too many subscriptions")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case change := <-sub.Updates():
+			if change == nil {
+				return nil
+			}
+			err := srv.Send(&api.PortsStatusChangesResponse{
+				Added:   change.Added,
+				Changed: change.Changed,
+				Removed: change.Removed,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resourcesSampleInterval is the interval in which resource consumption is reported to observers
+const resourcesSampleInterval = 2 * time.Second
+
+func (s *statusService) ResourcesStatus(req *api.ResourcesStatusRequest, srv api.StatusService_ResourcesStatusServer) error {
+	if !req.Observe {
+		resp, err := s.Resources.Get(srv.Context())
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return srv.Send(resp)
+	}
+
+	return s.Resources.Observe(srv.Context(), resourcesSampleInterval, srv.Send)
+}
+
+func (s *statusService) ListProcesses(ctx context.Context, req *api.ListProcessesRequest) (*api.ListProcessesResponse, error) {
+	procs, err := resources.ListProcesses(ctx, resources.DefaultProcFS)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	terminals := make(map[int64]string)
+	if s.Terminals != nil {
+		list, err := s.Terminals.List(ctx, &api.ListTerminalsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, term := range list.Terminals {
+			terminals[term.Pid] = term.Alias
+		}
+	}
+	tasks := make(map[string]string)
+	if s.Tasks != nil {
+		for _, task := range s.Tasks.Status() {
+			if task.Terminal != "" {
+				tasks[task.Terminal] = task.Id
+			}
+		}
+	}
+	servedPorts := make(map[int64][]uint32)
+	if s.Ports != nil {
+		for _, port := range s.Ports.Status() {
+			if port.Served && port.Process != nil {
+				servedPorts[port.Process.Pid] = append(servedPorts[port.Process.Pid], port.LocalPort)
+			}
+		}
+	}
+
+	return &api.ListProcessesResponse{
+		Processes: processTree(procs, terminals, tasks, servedPorts),
+	}, nil
+}
+
+// processTree arranges processes in a tree ordered by PID. Processes inherit the terminal
+// (alias by PID) of their parent and belong to the task (ID by terminal alias) of their terminal.
+func processTree(procs []*resources.Process, terminals map[int64]string, tasks map[string]string, servedPorts map[int64][]uint32) []*api.ProcessInfo {
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+
+	infos := make(map[int64]*api.ProcessInfo, len(procs))
+	for _, p := range procs {
+		ports := servedPorts[p.PID]
+		sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+		infos[p.PID] = &api.ProcessInfo{
+			Pid:      p.PID,
+			Ppid:     p.PPID,
+			Name:     p.Name,
+			Cmdline:  p.Cmdline,
+			Uid:      p.UID,
+			Cpu:      p.CPU,
+			Memory:   p.Memory,
+			Terminal: terminals[p.PID],
+			Ports:    ports,
+		}
+	}
+
+	var roots []*api.ProcessInfo
+	for _, p := range procs {
+		info := infos[p.PID]
+		parent, ok := infos[p.PPID]
+		if !ok || p.PPID == p.PID {
+			roots = append(roots, info)
+			continue
+		}
+		parent.Children = append(parent.Children, info)
+	}
+
+	var inherit func(info *api.ProcessInfo, terminal string)
+	inherit = func(info *api.ProcessInfo, terminal string) {
+		if info.Terminal == "" {
+			info.Terminal = terminal
+		}
+		info.Task = tasks[info.Terminal]
+		for _, child := range info.Children {
+			inherit(child, info.Terminal)
+		}
+	}
+	for _, root := range roots {
+		inherit(root, "")
+	}
+	return roots
+}
+
 func (s *statusService) TasksStatus(req *api.TasksStatusRequest, srv api.StatusService_TasksStatusServer) error {
 	select {
 	case <-srv.Context().Done():
@@ -371,6 +550,16 @@ func (s *InMemoryTokenService) GetToken(ctx context.Context, req *api.GetTokenRe
 		return asGetTokenResponse(tkn), nil
 	}
 
+	tkn = s.getTokenFromProviders(ctx, req)
+	if tkn == nil {
+		return nil, status.Error(codes.NotFound, "no token available")
+	}
+	s.cacheToken(req.Kind, tkn)
+	return asGetTokenResponse(tkn), nil
+}
+
+// getTokenFromProviders asks the registered providers for a token. It returns nil if none provides one.
+func (s *InMemoryTokenService) getTokenFromProviders(ctx context.Context, req *api.GetTokenRequest) *Token {
 	s.mu.RLock()
 	prov := s.provider[req.Kind]
 	s.mu.RUnlock()
@@ -384,12 +573,9 @@ func (s *InMemoryTokenService) GetToken(ctx context.Context, req *api.GetTokenRe
 			log.WithField("kind", req.Kind).WithField("host", req.Host).Warn("got no token from registered provider")
 			continue
 		}
-
-		s.cacheToken(req.Kind, tkn)
-		return asGetTokenResponse(tkn), nil
+		return tkn
 	}
-
-	return nil, status.Error(codes.NotFound, "no token available")
+	return nil
 }
 
 func asGetTokenResponse(tkn *Token) *api.GetTokenResponse {
@@ -512,6 +698,71 @@ func (s *InMemoryTokenService) ClearToken(ctx context.Context, req *api.ClearTok
 	return nil, status.Error(codes.Unknown, "unknown operation")
 }
 
+// expiringTokens returns the cached tokens of a kind which expire before the given time.
+func (s *InMemoryTokenService) expiringTokens(kind string, before time.Time) []*Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []*Token
+	for _, tkn := range s.token[kind] {
+		if tkn.ExpiryDate != nil && tkn.ExpiryDate.Before(before) {
+			res = append(res, tkn)
+		}
+	}
+	return res
+}
+
+// renewToken replaces a cached token with a new one from the registered providers.
+// The cached token is kept if the providers don't offer a token which expires later.
+func (s *InMemoryTokenService) renewToken(ctx context.Context, kind string, old *Token) (*Token, error) {
+	req := &api.GetTokenRequest{Kind: kind, Host: old.Host}
+	for scope := range old.Scope {
+		req.Scope = append(req.Scope, scope)
+	}
+	tkn := s.getTokenFromProviders(ctx, req)
+	if tkn == nil {
+		return nil, xerrors.Errorf("no token available")
+	}
+	if old.ExpiryDate != nil && tkn.ExpiryDate != nil && !tkn.ExpiryDate.After(*old.ExpiryDate) {
+		return nil, xerrors.Errorf("got no token which expires after %s", old.ExpiryDate.Format(time.RFC3339))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := s.token[kind]
+	for i, t := range token {
+		if t != old {
+			continue
+		}
+		if tkn.Reuse == api.TokenReuse_REUSE_NEVER {
+			token = append(token[:i], token[i+1:]...)
+		} else {
+			token[i] = tkn
+		}
+		break
+	}
+	s.token[kind] = token
+	log.WithField("kind", kind).WithField("host", tkn.Host).WithField("scopes", tkn.Scope).Info("renewed token")
+	return tkn, nil
+}
+
+// clearTokensForHost removes all cached tokens of a kind for a host.
+func (s *InMemoryTokenService) clearTokensForHost(kind string, host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var token []*Token
+	for _, t := range s.token[kind] {
+		if t.Host == host {
+			log.WithField("kind", kind).WithField("host", t.Host).WithField("scopes", t.Scope).Info("cleared token")
+			continue
+		}
+		token = append(token, t)
+	}
+	s.token[kind] = token
+}
+
 // ProvideToken registers a token provider.
 func (s *InMemoryTokenService) ProvideToken(srv api.TokenService_ProvideTokenServer) error {
 	req, err := srv.Recv()
@@ -690,7 +941,10 @@ func (is *InfoService) WorkspaceInfo(context.Context, *api.WorkspaceInfoRequest)
 
 // ControlService implements the supervisor control service.
 type ControlService struct {
-	portsManager *ports.Manager
+	cfg           *Config
+	portsManager  *ports.Manager
+	dotfiles      *dotfilesInstaller
+	gitpodService gitpod.APIInterface
 
 	privateKey string
 	publicKey  string
@@ -709,8 +963,153 @@ func (c *ControlService) ExposePort(ctx context.Context, req *api.ExposePortRequ
 	return &api.ExposePortResponse{}, err
 }
 
+// InstallDotfiles clones and installs the dotfiles again.
+func (c *ControlService) InstallDotfiles(ctx context.Context, req *api.InstallDotfilesRequest) (*api.InstallDotfilesResponse, error) {
+	installLog, err := c.dotfiles.Install(ctx, true)
+	if err == errNoDotfiles || err == errDotfilesDisabled {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot install dotfiles: %v\n%s", err, installLog)
+	}
+	return &api.InstallDotfilesResponse{Log: installLog}, nil
+}
+
+// GetSSHPublicKeys returns the SSH public keys the workspace owner registered with Gitpod.
+// Keys which don't parse are skipped.
+func (c *ControlService) GetSSHPublicKeys(ctx context.Context, req *api.GetSSHPublicKeysRequest) (*api.GetSSHPublicKeysResponse, error) {
+	if c.gitpodService == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not connected to the Gitpod server")
+	}
+	keys, err := c.gitpodService.GetSSHPublicKeys(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot fetch SSH public keys: %v", err)
+	}
+
+	res := &api.GetSSHPublicKeysResponse{}
+	for _, k := range keys {
+		if k == nil {
+			continue
+		}
+		pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k.Key))
+		if err != nil {
+			log.WithError(err).WithField("name", k.Name).Debug("ignoring invalid SSH public key")
+			continue
+		}
+		res.Keys = append(res.Keys, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pk))))
+	}
+	return res, nil
+}
+
+const (
+	// errorCodeSnapshotNotFound is the Gitpod server's error code for unknown snapshots
+	errorCodeSnapshotNotFound = 404
+	// errorCodeSnapshotError is the Gitpod server's error code for failed snapshots
+	errorCodeSnapshotError = 630
+)
+
+var (
+	// snapshotPollInterval is the time between two attempts to wait for a snapshot
+	snapshotPollInterval = 3 * time.Second
+
+	// snapshotFlushTimeout is the longest we wait for Git to release its index lock before taking a snapshot
+	snapshotFlushTimeout = 5 * time.Second
+)
+
+// TakeSnapshot takes a snapshot of the workspace content and streams its progress.
+func (c *ControlService) TakeSnapshot(req *api.TakeSnapshotRequest, srv api.ControlService_TakeSnapshotServer) error {
+	if c.gitpodService == nil {
+		return status.Error(codes.FailedPrecondition, "not connected to the Gitpod server")
+	}
+	ctx := srv.Context()
+
+	// ws-daemon snapshots the workspace content while the workspace keeps running
+	flushWorkspaceContent(ctx, c.cfg.RepoRoot)
+
+	snapshotID, err := c.gitpodService.TakeSnapshot(ctx, &gitpod.TakeSnapshotOptions{
+		WorkspaceID: c.cfg.WorkspaceID,
+		DontWait:    true,
+	})
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "cannot take snapshot: %v", err)
+	}
+	err = srv.Send(&api.TakeSnapshotResponse{
+		Phase:      api.TakeSnapshotResponse_in_progress,
+		SnapshotId: snapshotID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		// the server stops waiting after a while, hence we wait again until the snapshot is available
+		err = c.gitpodService.WaitForSnapshot(ctx, snapshotID)
+		if err == nil {
+			break
+		}
+		var rpcErr *jsonrpc2.Error
+		if errors.As(err, &rpcErr) && (rpcErr.Code == errorCodeSnapshotNotFound || rpcErr.Code == errorCodeSnapshotError) {
+			return status.Errorf(codes.Internal, "snapshot %s failed: %s", snapshotID, rpcErr.Message)
+		}
+		log.WithError(err).WithField("snapshotID", snapshotID).Debug("snapshot is not available yet")
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(snapshotPollInterval):
+		}
+	}
+
+	return srv.Send(&api.TakeSnapshotResponse{
+		Phase:      api.TakeSnapshotResponse_available,
+		SnapshotId: snapshotID,
+		Url:        fmt.Sprintf("%s/#snapshot/%s", c.cfg.GitpodHost, snapshotID),
+	})
+}
+
+// flushWorkspaceContent brings the workspace content into a consistent state for a snapshot. It waits for running
+// Git operations to release the index lock, and writes all pending changes to disk.
+func flushWorkspaceContent(ctx context.Context, repoRoot string) {
+	if repoRoot == "" {
+		return
+	}
+
+	var (
+		lock     = filepath.Join(repoRoot, ".git", "index.lock")
+		deadline = time.Now().Add(snapshotFlushTimeout)
+	)
+	for {
+		if _, err := os.Stat(lock); os.IsNotExist(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			log.WithField("lock", lock).Warn("Git holds on to its index lock - taking the snapshot anyway")
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	f, err := os.Open(repoRoot)
+	if err != nil {
+		log.WithError(err).Warn("cannot flush workspace content")
+		return
+	}
+	defer f.Close()
+	err = unix.Syncfs(int(f.Fd()))
+	if err != nil {
+		log.WithError(err).Warn("cannot flush workspace content")
+	}
+}
+
 // CreateSSHKeyPair create a ssh key pair for the workspace.
 func (ss *ControlService) CreateSSHKeyPair(context.Context, *api.CreateSSHKeyPairRequest) (response *api.CreateSSHKeyPairResponse, err error) {
+	// the SSH key sync replaces authorized_keys, which must not drop the key we append here
+	authorizedKeysMu.Lock()
+	defer authorizedKeysMu.Unlock()
+
 	home, _ := os.UserHomeDir()
 	if ss.privateKey != "" && ss.publicKey != "" {
 		checkKey := func() error {
@@ -747,7 +1146,7 @@ func (ss *ControlService) CreateSSHKeyPair(context.Context, *api.CreateSSHKeyPai
 	if err != nil {
 		return nil, xerrors.Errorf("cannot read privatekey: %w", err)
 	}
-	err = os.MkdirAll(filepath.Join(home, ".ssh"), 0o700)
+	err = ensureSSHDir(filepath.Join(home, ".ssh"))
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create dir ~/.ssh/: %w", err)
 	}
@@ -775,6 +1174,8 @@ type ContentState interface {
 	MarkContentReady(src csapi.WorkspaceInitSource)
 	ContentReady() <-chan struct{}
 	ContentSource() (src csapi.WorkspaceInitSource, ok bool)
+	SetContentProgress(p initializer.Progress)
+	ContentProgress() (p initializer.Progress, changed <-chan struct{})
 }
 
 // NewInMemoryContentState creates a new InMemoryContentState.
@@ -782,6 +1183,7 @@ func NewInMemoryContentState(checkoutLocation string) *InMemoryContentState {
 	return &InMemoryContentState{
 		checkoutLocation: checkoutLocation,
 		contentReadyChan: make(chan struct{}),
+		progressChanged:  make(chan struct{}),
 	}
 }
 
@@ -791,6 +1193,10 @@ type InMemoryContentState struct {
 
 	contentReadyChan chan struct{}
 	contentSource    csapi.WorkspaceInitSource
+
+	progressMu      sync.Mutex
+	progress        initializer.Progress
+	progressChanged chan struct{}
 }
 
 // MarkContentReady marks the workspace content as available.
@@ -798,6 +1204,34 @@ type InMemoryContentState struct {
 func (state *InMemoryContentState) MarkContentReady(src csapi.WorkspaceInitSource) {
 	state.contentSource = src
 	close(state.contentReadyChan)
+
+	state.progressMu.Lock()
+	state.notifyProgress()
+	state.progressMu.Unlock()
+}
+
+// SetContentProgress records the progress of the content initialization.
+func (state *InMemoryContentState) SetContentProgress(p initializer.Progress) {
+	state.progressMu.Lock()
+	defer state.progressMu.Unlock()
+
+	state.progress = p
+	state.notifyProgress()
+}
+
+// ContentProgress returns the progress of the content initialization, and a chan that closes
+// when the progress changes or the content becomes available.
+func (state *InMemoryContentState) ContentProgress() (p initializer.Progress, changed <-chan struct{}) {
+	state.progressMu.Lock()
+	defer state.progressMu.Unlock()
+
+	return state.progress, state.progressChanged
+}
+
+// notifyProgress must be called with progressMu held.
+func (state *InMemoryContentState) notifyProgress() {
+	close(state.progressChanged)
+	state.progressChanged = make(chan struct{})
 }
 
 // ContentReady returns a chan that closes when the content becomes available.
@@ -818,6 +1252,10 @@ func (state *InMemoryContentState) ContentSource() (src csapi.WorkspaceInitSourc
 
 type portService struct {
 	portsManager *ports.Manager
+	accessLog    *ports.AccessLog
+	// accessLogToken authenticates ws-proxy, which derives it from the owner token of the workspace and is the only one
+	// allowed to record access log entries
+	accessLogToken string
 
 	api.UnimplementedPortServiceServer
 }
@@ -830,6 +1268,40 @@ func (s *portService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) e
 	return api.RegisterPortServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
 }
 
+// RecordAccessLog records requests to exposed ports observed by ws-proxy.
+// Clients must authenticate with the access log token of the workspace as bearer token.
+func (s *portService) RecordAccessLog(ctx context.Context, req *api.RecordAccessLogRequest) (*api.RecordAccessLogResponse, error) {
+	if !isBearerTokenAuthorized(ctx, s.accessLogToken) {
+		return nil, status.Error(codes.PermissionDenied, "only ws-proxy can record access logs")
+	}
+	s.accessLog.Add(req.Entries...)
+	return &api.RecordAccessLogResponse{}, nil
+}
+
+// isBearerTokenAuthorized returns true if the request carries the token as bearer token in its authorization header.
+// Requests are never authorized if there is no token.
+func isBearerTokenAuthorized(ctx context.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, auth := range md.Get("authorization") {
+		bearer := strings.TrimPrefix(auth, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ListAccessLog lists the most recent requests to exposed ports.
+func (s *portService) ListAccessLog(ctx context.Context, req *api.ListAccessLogRequest) (*api.ListAccessLogResponse, error) {
+	return &api.ListAccessLogResponse{Entries: s.accessLog.Entries()}, nil
+}
+
 // Tunnel opens a new tunnel.
 func (s *portService) Tunnel(ctx context.Context, req *api.TunnelPortRequest) (*api.TunnelPortResponse, error) {
 	err := s.portsManager.Tunnel(ctx, &ports.PortTunnelDescription{
@@ -927,3 +1399,333 @@ func (s *portService) RetryAutoExpose(ctx context.Context, req *api.RetryAutoExp
 	s.portsManager.RetryAutoExpose(ctx, req.Port)
 	return &api.RetryAutoExposeResponse{}, nil
 }
+
+type tasksService struct {
+	tasks *tasksManager
+
+	api.UnimplementedTasksServiceServer
+}
+
+func (s *tasksService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterTasksServiceServer(srv, s)
+}
+
+func (s *tasksService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) error {
+	return api.RegisterTasksServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
+}
+
+// RestartTask closes the terminal of a task and runs the task again in a new terminal.
+func (s *tasksService) RestartTask(ctx context.Context, req *api.RestartTaskRequest) (*api.RestartTaskResponse, error) {
+	err := s.tasks.RestartTask(ctx, req.Id)
+	if err != nil {
+		return nil, taskErrorToStatus(err)
+	}
+	return &api.RestartTaskResponse{}, nil
+}
+
+// RerunTask runs a task again in its terminal.
+func (s *tasksService) RerunTask(ctx context.Context, req *api.RerunTaskRequest) (*api.RerunTaskResponse, error) {
+	err := s.tasks.RerunTask(ctx, req.Id)
+	if err != nil {
+		return nil, taskErrorToStatus(err)
+	}
+	return &api.RerunTaskResponse{}, nil
+}
+
+func taskErrorToStatus(err error) error {
+	switch err {
+	case errTaskNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case errTaskNotStarted, errTaskRestartInPrebuild:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+type environmentService struct {
+	cfg           *Config
+	env           *envVarStore
+	gitpodService gitpod.APIInterface
+
+	api.UnimplementedEnvironmentServiceServer
+}
+
+func (s *environmentService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterEnvironmentServiceServer(srv, s)
+}
+
+func (s *environmentService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) error {
+	return api.RegisterEnvironmentServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
+}
+
+// ListEnvironmentVariables lists the scoped environment variables of this workspace.
+func (s *environmentService) ListEnvironmentVariables(ctx context.Context, req *api.ListEnvironmentVariablesRequest) (*api.ListEnvironmentVariablesResponse, error) {
+	return &api.ListEnvironmentVariablesResponse{Variables: s.env.Variables()}, nil
+}
+
+// ReloadEnvironmentVariables fetches the user's environment variables and passes them to new terminals.
+func (s *environmentService) ReloadEnvironmentVariables(ctx context.Context, req *api.ReloadEnvironmentVariablesRequest) (*api.ReloadEnvironmentVariablesResponse, error) {
+	vars, err := s.gitpodService.GetAllEnvVars(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot fetch environment variables: %v", err)
+	}
+
+	var owner, repo string
+	commit, err := s.cfg.getCommit()
+	if err != nil {
+		log.WithError(err).Warn("cannot determine the repository, not filtering environment variables")
+	} else if commit != nil && commit.Repository != nil {
+		owner, repo = commit.Repository.Owner, commit.Repository.Name
+	}
+	s.env.SetUserVariables(filterUserEnvVars(vars, owner, repo))
+
+	return &api.ReloadEnvironmentVariablesResponse{Variables: s.env.Variables()}, nil
+}
+
+type activityService struct {
+	// trackers are the activity sources which are reported through the API
+	trackers map[string]*activity.Tracker
+	// sources are all activity sources which keep the workspace from timing out
+	sources map[string]activity.Source
+	// started is the time the workspace started, which counts as activity
+	started time.Time
+
+	cfg           *Config
+	gitpodService gitpod.APIInterface
+
+	api.UnimplementedActivityServiceServer
+}
+
+func (s *activityService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterActivityServiceServer(srv, s)
+}
+
+func (s *activityService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) error {
+	return api.RegisterActivityServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
+}
+
+// MarkActive records user activity observed outside of supervisor.
+func (s *activityService) MarkActive(ctx context.Context, req *api.MarkActiveRequest) (*api.MarkActiveResponse, error) {
+	tracker, ok := s.trackers[req.Source]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown activity source: %s", req.Source)
+	}
+	tracker.Mark()
+	return &api.MarkActiveResponse{}, nil
+}
+
+// timeoutWarningPeriod is the time before the workspace times out from which on the timeout is reported as stop reason
+const timeoutWarningPeriod = 5 * time.Minute
+
+// WorkspaceLifecycle reports when the workspace times out and why it is about to stop.
+func (s *activityService) WorkspaceLifecycle(ctx context.Context, req *api.WorkspaceLifecycleRequest) (*api.WorkspaceLifecycleResponse, error) {
+	if s.gitpodService == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not connected to the Gitpod server")
+	}
+	wsTimeout, err := s.gitpodService.GetWorkspaceTimeout(ctx, s.cfg.WorkspaceID)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot get workspace timeout: %v", err)
+	}
+	timeout, err := time.ParseDuration(wsTimeout.Duration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot parse workspace timeout %q: %v", wsTimeout.Duration, err)
+	}
+
+	lastActivity := activity.LastActivity(s.sources)
+	if s.started.After(lastActivity) {
+		lastActivity = s.started
+	}
+	remaining := timeout - time.Since(lastActivity)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	res := &api.WorkspaceLifecycleResponse{
+		TimeoutSeconds:   uint32(timeout.Seconds()),
+		RemainingSeconds: uint32(remaining.Seconds()),
+		LastActivity:     timestamppb.New(lastActivity),
+		CanExtendTimeout: wsTimeout.CanChange,
+	}
+	if remaining <= timeoutWarningPeriod {
+		res.StopReasons = append(res.StopReasons, api.WorkspaceLifecycleResponse_timeout)
+	}
+	if s.cfg.isHeadless() {
+		res.StopReasons = append(res.StopReasons, api.WorkspaceLifecycleResponse_headless)
+	}
+	return res, nil
+}
+
+// ExtendTimeout asks the Gitpod server for a longer workspace timeout.
+func (s *activityService) ExtendTimeout(ctx context.Context, req *api.ExtendTimeoutRequest) (*api.ExtendTimeoutResponse, error) {
+	if s.gitpodService == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not connected to the Gitpod server")
+	}
+	if _, err := time.ParseDuration(req.Duration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid duration %q", req.Duration)
+	}
+	duration := gitpod.WorkspaceTimeoutDuration(req.Duration)
+	_, err := s.gitpodService.SetWorkspaceTimeout(ctx, s.cfg.WorkspaceID, &duration)
+	var rpcErr *jsonrpc2.Error
+	if errors.As(err, &rpcErr) {
+		// the server refuses timeouts the user's plan doesn't allow
+		return nil, status.Errorf(codes.PermissionDenied, "cannot extend workspace timeout: %s", rpcErr.Message)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot extend workspace timeout: %v", err)
+	}
+	return &api.ExtendTimeoutResponse{}, nil
+}
+
+type fileService struct {
+	api.UnimplementedFileServiceServer
+}
+
+func (s *fileService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterFileServiceServer(srv, s)
+}
+
+func (s *fileService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) error {
+	return api.RegisterFileServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
+}
+
+// WatchFiles streams the changes of a file or directory tree.
+func (s *fileService) WatchFiles(req *api.WatchFilesRequest, srv api.FileService_WatchFilesServer) error {
+	if !filepath.IsAbs(req.Path) {
+		return status.Error(codes.InvalidArgument, "path must be absolute")
+	}
+	watcher, err := newFileWatcher(req.Path, req.Recursive, req.Excludes)
+	if os.IsNotExist(err) {
+		return status.Errorf(codes.NotFound, "%s does not exist", req.Path)
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return watcher.Run(srv.Context(), func(events []*api.FileEvent) error {
+		return srv.Send(&api.WatchFilesResponse{Events: events})
+	})
+}
+
+type gitService struct {
+	// RepoRoot is the location of the repository working copies are relative to
+	RepoRoot string
+	// ContentState signals when the working copies are available
+	ContentState ContentState
+	// Env is the environment git runs in
+	Env []string
+	// Credential is the user git runs as. Nil means the current user.
+	Credential *syscall.Credential
+	// Tokens provides the Git credentials
+	Tokens *InMemoryTokenService
+
+	api.UnimplementedGitServiceServer
+}
+
+func (s *gitService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterGitServiceServer(srv, s)
+}
+
+func (s *gitService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) error {
+	return api.RegisterGitServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithInsecure()})
+}
+
+// Status returns the status of a working copy.
+func (s *gitService) Status(ctx context.Context, req *api.GitStatusRequest) (*api.GitStatusResponse, error) {
+	out, err := s.git(ctx, req.Location, "status", "--porcelain=v2", "--branch", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	res, err := parseGitStatus(out)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return res, nil
+}
+
+// Fetch fetches the remotes of a working copy.
+func (s *gitService) Fetch(ctx context.Context, req *api.GitFetchRequest) (*api.GitFetchResponse, error) {
+	args := []string{"fetch"}
+	if req.Prune {
+		args = append(args, "--prune")
+	}
+	if req.Remote != "" {
+		args = append(args, "--", req.Remote)
+	} else {
+		args = append(args, "--all")
+	}
+	_, err := s.git(ctx, req.Location, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &api.GitFetchResponse{}, nil
+}
+
+// Pull pulls the upstream of the current branch.
+func (s *gitService) Pull(ctx context.Context, req *api.GitPullRequest) (*api.GitPullResponse, error) {
+	args := []string{"pull", "--no-edit"}
+	if req.Rebase {
+		args = append(args, "--rebase")
+	} else {
+		args = append(args, "--no-rebase")
+	}
+	_, err := s.git(ctx, req.Location, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &api.GitPullResponse{}, nil
+}
+
+// RefreshCredentials replaces the cached Git credentials of a host with new ones.
+func (s *gitService) RefreshCredentials(ctx context.Context, req *api.RefreshGitCredentialsRequest) (*api.RefreshGitCredentialsResponse, error) {
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "host is required")
+	}
+	s.Tokens.clearTokensForHost(KindGit, req.Host)
+	tkn, err := s.Tokens.GetToken(ctx, &api.GetTokenRequest{
+		Host: req.Host,
+		Kind: KindGit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &api.RefreshGitCredentialsResponse{
+		User:  tkn.User,
+		Scope: tkn.Scope,
+	}, nil
+}
+
+// git runs git in a working copy and returns its stdout.
+func (s *gitService) git(ctx context.Context, location string, args ...string) ([]byte, error) {
+	root := filepath.Clean(s.RepoRoot)
+	dir := filepath.Join(root, location)
+	if dir != root && !strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		return nil, status.Errorf(codes.InvalidArgument, "location %s is outside of the repository root", location)
+	}
+
+	select {
+	case <-s.ContentState.ContentReady():
+	default:
+		return nil, status.Error(codes.Unavailable, "workspace content is not ready yet")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// git must never wait for input, e.g. for credentials or a merge commit message
+	cmd.Env = append(append([]string(nil), s.Env...), "GIT_TERMINAL_PROMPT=0")
+	if s.Credential != nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: s.Credential}
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if _, ok := err.(*exec.ExitError); ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot run git: %v", err)
+	}
+	return out, nil
+}
@@ -6,16 +6,31 @@ package supervisor
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/sourcegraph/jsonrpc2"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/activity"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/resources"
 )
 
 func TestInMemoryTokenServiceGetToken(t *testing.T) {
@@ -384,3 +399,389 @@ type tokenProviderFunc func(ctx context.Context, req *api.GetTokenRequest) (tkn
 func (f tokenProviderFunc) GetToken(ctx context.Context, req *api.GetTokenRequest) (tkn *Token, err error) {
 	return f(ctx, req)
 }
+
+func TestTakeSnapshot(t *testing.T) {
+	defer func(interval time.Duration) { snapshotPollInterval = interval }(snapshotPollInterval)
+	snapshotPollInterval = 10 * time.Millisecond
+
+	type Expectation struct {
+		Phases []api.TakeSnapshotResponse_Phase
+		URL    string
+		Err    string
+	}
+	tests := []struct {
+		Desc         string
+		Disconnected bool
+		WaitErrors   []error
+		Expectation  Expectation
+	}{
+		{
+			Desc: "snapshot available",
+			Expectation: Expectation{
+				Phases: []api.TakeSnapshotResponse_Phase{api.TakeSnapshotResponse_in_progress, api.TakeSnapshotResponse_available},
+				URL:    "https://gitpod.io/#snapshot/snapshot-id",
+			},
+		},
+		{
+			Desc:       "waiting times out",
+			WaitErrors: []error{errors.New("timeout"), errors.New("timeout")},
+			Expectation: Expectation{
+				Phases: []api.TakeSnapshotResponse_Phase{api.TakeSnapshotResponse_in_progress, api.TakeSnapshotResponse_available},
+				URL:    "https://gitpod.io/#snapshot/snapshot-id",
+			},
+		},
+		{
+			Desc:       "snapshot fails",
+			WaitErrors: []error{&jsonrpc2.Error{Code: errorCodeSnapshotError, Message: "backup failed"}},
+			Expectation: Expectation{
+				Phases: []api.TakeSnapshotResponse_Phase{api.TakeSnapshotResponse_in_progress},
+				Err:    "rpc error: code = Internal desc = snapshot snapshot-id failed: backup failed",
+			},
+		},
+		{
+			Desc:         "not connected",
+			Disconnected: true,
+			Expectation: Expectation{
+				Err: "rpc error: code = FailedPrecondition desc = not connected to the Gitpod server",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := &ControlService{cfg: &Config{WorkspaceConfig: WorkspaceConfig{WorkspaceID: "workspace-id", GitpodHost: "https://gitpod.io"}}}
+			if !test.Disconnected {
+				gitpodAPI := gitpod.NewMockAPIInterface(ctrl)
+				gitpodAPI.EXPECT().TakeSnapshot(gomock.Any(), &gitpod.TakeSnapshotOptions{WorkspaceID: "workspace-id", DontWait: true}).Return("snapshot-id", nil)
+				for _, err := range test.WaitErrors {
+					gitpodAPI.EXPECT().WaitForSnapshot(gomock.Any(), "snapshot-id").Return(err)
+				}
+				if len(test.Expectation.Phases) > 1 {
+					gitpodAPI.EXPECT().WaitForSnapshot(gomock.Any(), "snapshot-id").Return(nil)
+				}
+				srv.gitpodService = gitpodAPI
+			}
+
+			var act Expectation
+			err := srv.TakeSnapshot(&api.TakeSnapshotRequest{}, &testTakeSnapshotServer{onSend: func(resp *api.TakeSnapshotResponse) {
+				act.Phases = append(act.Phases, resp.Phase)
+				act.URL = resp.Url
+			}})
+			if err != nil {
+				act.Err = err.Error()
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetSSHPublicKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pk)))
+
+	type Expectation struct {
+		Keys []string
+		Err  string
+	}
+	tests := []struct {
+		Desc         string
+		Disconnected bool
+		Keys         []*gitpod.UserSSHPublicKey
+		FetchErr     error
+		Expectation  Expectation
+	}{
+		{
+			Desc: "no keys",
+		},
+		{
+			Desc: "skips invalid keys and drops comments",
+			Keys: []*gitpod.UserSSHPublicKey{
+				{Name: "invalid", Key: "ssh-ed25519 not-a-key"},
+				{Name: "laptop", Key: userKey + " user@laptop"},
+			},
+			Expectation: Expectation{Keys: []string{userKey}},
+		},
+		{
+			Desc:     "server unavailable",
+			FetchErr: errors.New("connection lost"),
+			Expectation: Expectation{
+				Err: "rpc error: code = Unavailable desc = cannot fetch SSH public keys: connection lost",
+			},
+		},
+		{
+			Desc:         "not connected",
+			Disconnected: true,
+			Expectation: Expectation{
+				Err: "rpc error: code = FailedPrecondition desc = not connected to the Gitpod server",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := &ControlService{cfg: &Config{}}
+			if !test.Disconnected {
+				gitpodAPI := gitpod.NewMockAPIInterface(ctrl)
+				gitpodAPI.EXPECT().GetSSHPublicKeys(gomock.Any()).Return(test.Keys, test.FetchErr)
+				srv.gitpodService = gitpodAPI
+			}
+
+			var act Expectation
+			resp, err := srv.GetSSHPublicKeys(context.Background(), &api.GetSSHPublicKeysRequest{})
+			if err != nil {
+				act.Err = err.Error()
+			} else {
+				act.Keys = resp.Keys
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordAccessLog(t *testing.T) {
+	tests := []struct {
+		Desc           string
+		AccessLogToken string
+		Authorization  []string
+		Expectation    codes.Code
+	}{
+		{Desc: "access log token", AccessLogToken: "access-log-token", Authorization: []string{"Bearer access-log-token"}, Expectation: codes.OK},
+		{Desc: "wrong token", AccessLogToken: "access-log-token", Authorization: []string{"Bearer foobar"}, Expectation: codes.PermissionDenied},
+		{Desc: "no token", AccessLogToken: "access-log-token", Expectation: codes.PermissionDenied},
+		{Desc: "no access log token", Authorization: []string{"Bearer "}, Expectation: codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{"authorization": test.Authorization})
+
+			srv := &portService{accessLog: ports.NewAccessLog(10), accessLogToken: test.AccessLogToken}
+			_, err := srv.RecordAccessLog(ctx, &api.RecordAccessLogRequest{Entries: []*api.PortAccessLogEntry{{Port: 8080}}})
+			if code := status.Code(err); code != test.Expectation {
+				t.Fatalf("unexpected status: expected %v, got %v", test.Expectation, code)
+			}
+
+			var expectedEntries int
+			if test.Expectation == codes.OK {
+				expectedEntries = 1
+			}
+			if entries := srv.accessLog.Entries(); len(entries) != expectedEntries {
+				t.Errorf("unexpected number of entries: expected %d, got %d", expectedEntries, len(entries))
+			}
+		})
+	}
+}
+
+func TestFlushWorkspaceContent(t *testing.T) {
+	defer func(timeout time.Duration) { snapshotFlushTimeout = timeout }(snapshotFlushTimeout)
+	snapshotFlushTimeout = 500 * time.Millisecond
+
+	tests := []struct {
+		Desc        string
+		LockRelease time.Duration
+		MinDuration time.Duration
+	}{
+		{Desc: "no index lock"},
+		{Desc: "index lock is released", LockRelease: 200 * time.Millisecond, MinDuration: 200 * time.Millisecond},
+		{Desc: "index lock is never released", LockRelease: -1, MinDuration: 500 * time.Millisecond},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			repoRoot := t.TempDir()
+			lock := filepath.Join(repoRoot, ".git", "index.lock")
+			if test.LockRelease != 0 {
+				err := os.MkdirAll(filepath.Dir(lock), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(lock, nil, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.LockRelease > 0 {
+				go func() {
+					time.Sleep(test.LockRelease)
+					os.Remove(lock)
+				}()
+			}
+
+			start := time.Now()
+			flushWorkspaceContent(context.Background(), repoRoot)
+			if dur := time.Since(start); dur < test.MinDuration {
+				t.Errorf("flush returned after %s, expected to wait at least %s", dur, test.MinDuration)
+			}
+		})
+	}
+}
+
+type testTakeSnapshotServer struct {
+	onSend func(*api.TakeSnapshotResponse)
+	grpc.ServerStream
+}
+
+func (s *testTakeSnapshotServer) Send(resp *api.TakeSnapshotResponse) error {
+	s.onSend(resp)
+	return nil
+}
+
+func (s *testTakeSnapshotServer) Context() context.Context {
+	return context.Background()
+}
+
+func TestProcessTree(t *testing.T) {
+	procs := []*resources.Process{
+		{PID: 43, PPID: 42, Name: "node"},
+		{PID: 1, PPID: 0, Name: "supervisor"},
+		{PID: 42, PPID: 1, Name: "bash"},
+		{PID: 50, PPID: 1, Name: "bash"},
+		{PID: 51, PPID: 50, Name: "vim"},
+		{PID: 60, PPID: 7, Name: "orphan"},
+	}
+	terminals := map[int64]string{42: "term-1", 50: "term-2"}
+	tasks := map[string]string{"term-1": "0"}
+	servedPorts := map[int64][]uint32{43: {3001, 3000}}
+
+	act := processTree(procs, terminals, tasks, servedPorts)
+
+	expectation := []*api.ProcessInfo{
+		{Pid: 1, Name: "supervisor", Children: []*api.ProcessInfo{
+			{Pid: 42, Ppid: 1, Name: "bash", Terminal: "term-1", Task: "0", Children: []*api.ProcessInfo{
+				{Pid: 43, Ppid: 42, Name: "node", Terminal: "term-1", Task: "0", Ports: []uint32{3000, 3001}},
+			}},
+			{Pid: 50, Ppid: 1, Name: "bash", Terminal: "term-2", Children: []*api.ProcessInfo{
+				{Pid: 51, Ppid: 50, Name: "vim", Terminal: "term-2"},
+			}},
+		}},
+		{Pid: 60, Ppid: 7, Name: "orphan"},
+	}
+	if diff := cmp.Diff(expectation, act, cmpopts.IgnoreUnexported(api.ProcessInfo{})); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+type fixedActivitySource time.Time
+
+func (s fixedActivitySource) LastActivity() time.Time { return time.Time(s) }
+
+func TestWorkspaceLifecycle(t *testing.T) {
+	type Expectation struct {
+		TimeoutSeconds   uint32
+		RemainingMinutes uint32
+		CanExtendTimeout bool
+		StopReasons      []api.WorkspaceLifecycleResponse_StopReason
+		Err              string
+	}
+	tests := []struct {
+		Desc         string
+		Disconnected bool
+		Headless     bool
+		Duration     string
+		LastActivity time.Duration
+		Expectation  Expectation
+	}{
+		{
+			Desc:         "active workspace",
+			Duration:     "30m",
+			LastActivity: 10 * time.Minute,
+			Expectation:  Expectation{TimeoutSeconds: 1800, RemainingMinutes: 20, CanExtendTimeout: true},
+		},
+		{
+			Desc:         "times out soon",
+			Duration:     "30m",
+			LastActivity: 27 * time.Minute,
+			Expectation: Expectation{
+				TimeoutSeconds:   1800,
+				RemainingMinutes: 3,
+				CanExtendTimeout: true,
+				StopReasons:      []api.WorkspaceLifecycleResponse_StopReason{api.WorkspaceLifecycleResponse_timeout},
+			},
+		},
+		{
+			Desc:         "no activity since start",
+			Duration:     "60m",
+			LastActivity: -1,
+			Expectation:  Expectation{TimeoutSeconds: 3600, RemainingMinutes: 60, CanExtendTimeout: true},
+		},
+		{
+			Desc:         "headless",
+			Headless:     true,
+			Duration:     "60m",
+			LastActivity: 90 * time.Minute,
+			Expectation: Expectation{
+				TimeoutSeconds:   3600,
+				CanExtendTimeout: true,
+				StopReasons:      []api.WorkspaceLifecycleResponse_StopReason{api.WorkspaceLifecycleResponse_timeout, api.WorkspaceLifecycleResponse_headless},
+			},
+		},
+		{
+			Desc:         "invalid timeout",
+			Duration:     "forever",
+			LastActivity: -1,
+			Expectation:  Expectation{Err: "rpc error: code = Internal desc = cannot parse workspace timeout \"forever\": time: invalid duration \"forever\""},
+		},
+		{
+			Desc:         "not connected",
+			Disconnected: true,
+			Expectation:  Expectation{Err: "rpc error: code = FailedPrecondition desc = not connected to the Gitpod server"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := &Config{WorkspaceConfig: WorkspaceConfig{WorkspaceID: "workspace-id"}}
+			if test.Headless {
+				cfg.GitpodHeadless = "true"
+			}
+			srv := &activityService{
+				sources: map[string]activity.Source{},
+				started: time.Now(),
+				cfg:     cfg,
+			}
+			if test.LastActivity >= 0 {
+				srv.started = time.Now().Add(-2 * time.Hour)
+				srv.sources[activity.SourceTerminal] = fixedActivitySource(time.Now().Add(-test.LastActivity))
+			}
+			if !test.Disconnected {
+				gitpodAPI := gitpod.NewMockAPIInterface(ctrl)
+				gitpodAPI.EXPECT().GetWorkspaceTimeout(gomock.Any(), "workspace-id").Return(&gitpod.GetWorkspaceTimeoutResult{Duration: test.Duration, CanChange: true}, nil)
+				srv.gitpodService = gitpodAPI
+			}
+
+			var act Expectation
+			resp, err := srv.WorkspaceLifecycle(context.Background(), &api.WorkspaceLifecycleRequest{})
+			if err != nil {
+				act.Err = err.Error()
+			} else {
+				act = Expectation{
+					TimeoutSeconds:   resp.TimeoutSeconds,
+					RemainingMinutes: (resp.RemainingSeconds + 30) / 60,
+					CanExtendTimeout: resp.CanExtendTimeout,
+					StopReasons:      resp.StopReasons,
+				}
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
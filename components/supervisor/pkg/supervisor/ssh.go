@@ -13,13 +13,32 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/activity"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
 )
 
-func newSSHServer(ctx context.Context, cfg *Config, envvars []string) (*sshServer, error) {
+const (
+	// sshKeysSyncInterval is the time between two syncs of the user's SSH public keys
+	sshKeysSyncInterval = 5 * time.Minute
+
+	// authorizedKeysBegin and authorizedKeysEnd enclose the part of authorized_keys supervisor manages
+	authorizedKeysBegin = "# >>> gitpod user keys (managed by supervisor, do not edit)"
+	authorizedKeysEnd   = "# <<< gitpod user keys"
+)
+
+// authorizedKeysMu serialises all changes to the authorized_keys file, which both the SSH key sync
+// and CreateSSHKeyPair modify
+var authorizedKeysMu sync.Mutex
+
+func newSSHServer(ctx context.Context, cfg *Config, envvars []string, gitpodService gitpod.APIInterface, sshActivity *activity.Tracker) (*sshServer, error) {
 	bin, err := os.Executable()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot find executable path: %w", err)
@@ -37,11 +56,33 @@ func newSSHServer(ctx context.Context, cfg *Config, envvars []string) (*sshServe
 		return nil, xerrors.Errorf("unexpected error creating SSH env: %w", err)
 	}
 
+	var trustedUserCAKeys, authorizedPrincipals string
+	if cfg.SSHCAPublicKey != "" {
+		trustedUserCAKeys = filepath.Join(filepath.Dir(sshkey), "trusted_user_ca_keys")
+		authorizedPrincipals = filepath.Join(filepath.Dir(sshkey), "authorized_principals")
+		err = writeSSHCertificateAuthority(cfg, trustedUserCAKeys, authorizedPrincipals)
+		if err != nil {
+			return nil, xerrors.Errorf("unexpected error configuring SSH certificate authority: %w", err)
+		}
+	}
+
+	if gitpodService != nil {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		go syncSSHPublicKeys(ctx, gitpodService, filepath.Join(home, ".ssh", "authorized_keys"))
+	}
+
 	return &sshServer{
-		ctx:     ctx,
-		cfg:     cfg,
-		sshkey:  sshkey,
-		envvars: envvars,
+		ctx:                  ctx,
+		cfg:                  cfg,
+		sshkey:               sshkey,
+		trustedUserCAKeys:    trustedUserCAKeys,
+		authorizedPrincipals: authorizedPrincipals,
+		envvars:              envvars,
+		activity:             sshActivity,
+		recordings:           newSessionRecordings(cfg),
 	}, nil
 }
 
@@ -51,6 +92,15 @@ type sshServer struct {
 	envvars []string
 
 	sshkey string
+	// trustedUserCAKeys and authorizedPrincipals are empty if there is no SSH certificate authority
+	trustedUserCAKeys    string
+	authorizedPrincipals string
+
+	// activity is marked whenever a client sends data
+	activity *activity.Tracker
+
+	// recordings records all sessions if not nil
+	recordings *terminal.Recordings
 }
 
 // ListenAndServe listens on the TCP network address laddr and then handle packets on incoming connections.
@@ -101,6 +151,17 @@ func (s *sshServer) handleConn(ctx context.Context, conn net.Conn) {
 		"-oStrictModes no", // don't care for home directory and file permissions
 	}
 
+	if s.trustedUserCAKeys != "" {
+		args = append(args,
+			"-oTrustedUserCAKeys "+s.trustedUserCAKeys,
+			"-oAuthorizedPrincipalsFile "+s.authorizedPrincipals,
+		)
+	}
+
+	if s.recordings != nil {
+		args = append(args, "-oForceCommand "+sshRecordSessionCommand(bin, s.recordings))
+	}
+
 	if os.Getenv("SUPERVISOR_DEBUG_ENABLE") != "" {
 		args = append(args, "-oLogLevel DEBUG")
 	}
@@ -118,7 +179,7 @@ func (s *sshServer) handleConn(ctx context.Context, conn net.Conn) {
 	cmd.Env = s.envvars
 	cmd.ExtraFiles = []*os.File{socketFD}
 	cmd.Stderr = os.Stderr
-	cmd.Stdin = bufio.NewReader(socketFD)
+	cmd.Stdin = bufio.NewReader(s.activity.Reader(socketFD))
 	cmd.Stdout = bufio.NewWriter(socketFD)
 
 	err = cmd.Start()
@@ -213,3 +274,136 @@ func writeSSHEnv(cfg *Config, envvars []string) error {
 
 	return nil
 }
+
+// writeSSHCertificateAuthority configures sshd to accept user certificates signed by the installation's CA.
+// Since the CA serves all workspaces of the installation, a certificate is only accepted if it names this
+// workspace as principal.
+func writeSSHCertificateAuthority(cfg *Config, trustedUserCAKeys, authorizedPrincipals string) error {
+	pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.SSHCAPublicKey))
+	if err != nil {
+		return xerrors.Errorf("invalid SSH CA public key: %w", err)
+	}
+	err = os.WriteFile(trustedUserCAKeys, ssh.MarshalAuthorizedKey(pk), 0o644)
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", trustedUserCAKeys, err)
+	}
+	err = os.WriteFile(authorizedPrincipals, []byte(cfg.WorkspaceID+"\n"), 0o644)
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", authorizedPrincipals, err)
+	}
+	return nil
+}
+
+// syncSSHPublicKeys periodically fetches the SSH public keys the user registered with Gitpod
+// and adds them to the authorized_keys file until the context is canceled.
+func syncSSHPublicKeys(ctx context.Context, gitpodService gitpod.APIInterface, authorizedKeys string) {
+	ticker := time.NewTicker(sshKeysSyncInterval)
+	defer ticker.Stop()
+	for {
+		keys, err := gitpodService.GetSSHPublicKeys(ctx)
+		if err != nil {
+			log.WithError(err).Warn("cannot fetch the user's SSH public keys")
+		} else {
+			err = updateAuthorizedKeys(authorizedKeys, keys)
+			if err != nil {
+				log.WithError(err).Warn("cannot update authorized SSH keys")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func updateAuthorizedKeys(fn string, keys []*gitpod.UserSSHPublicKey) error {
+	authorizedKeysMu.Lock()
+	defer authorizedKeysMu.Unlock()
+
+	content, err := os.ReadFile(fn)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updated := mergeAuthorizedKeys(string(content), keys)
+	if updated == string(content) {
+		return nil
+	}
+
+	err = ensureSSHDir(filepath.Dir(fn))
+	if err != nil {
+		return err
+	}
+	// write atomically so that sshd never sees a partial file
+	tmp := fn + ".tmp"
+	err = os.WriteFile(tmp, []byte(updated), 0o600)
+	if err != nil {
+		return err
+	}
+	err = os.Chown(tmp, gitpodUID, gitpodGID)
+	if err != nil {
+		log.WithError(err).WithField("fn", tmp).Debug("cannot chown authorized_keys")
+	}
+	return os.Rename(tmp, fn)
+}
+
+// ensureSSHDir creates the ~/.ssh directory if it does not exist yet and hands it to the gitpod user.
+// sshd refuses authorized_keys files in directories the user does not own.
+func ensureSSHDir(dir string) error {
+	_, err := os.Stat(dir)
+	if err == nil {
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return err
+	}
+	return os.Chown(dir, gitpodUID, gitpodGID)
+}
+
+// mergeAuthorizedKeys replaces the supervisor managed section of an authorized_keys file with keys.
+// All other entries, e.g. those added by the user or by CreateSSHKeyPair, are kept.
+// Keys which don't parse are skipped, and options of the keys are dropped.
+func mergeAuthorizedKeys(content string, keys []*gitpod.UserSSHPublicKey) string {
+	var (
+		res     []string
+		managed bool
+	)
+	for _, l := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		switch {
+		case l == authorizedKeysBegin:
+			managed = true
+		case l == authorizedKeysEnd:
+			managed = false
+		case !managed && l != "":
+			res = append(res, l)
+		}
+	}
+
+	var managedKeys []string
+	for _, k := range keys {
+		if k == nil {
+			continue
+		}
+		pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k.Key))
+		if err != nil {
+			log.WithError(err).WithField("name", k.Name).Warn("ignoring invalid SSH public key")
+			continue
+		}
+		managedKeys = append(managedKeys, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pk))))
+	}
+	if len(managedKeys) > 0 {
+		res = append(res, authorizedKeysBegin)
+		res = append(res, managedKeys...)
+		res = append(res, authorizedKeysEnd)
+	}
+
+	if len(res) == 0 {
+		return ""
+	}
+	return strings.Join(res, "\n") + "\n"
+}
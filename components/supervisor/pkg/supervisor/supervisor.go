@@ -13,11 +13,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"io/ioutil"
 	"net"
 	"net/http"
-	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -33,17 +31,21 @@ import (
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpcruntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/procfs"
 	"github.com/soheilhy/cmux"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/sys/unix"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/gitpod-io/gitpod/common-go/analytics"
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/pprof"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/executor"
 	"github.com/gitpod-io/gitpod/content-service/pkg/git"
@@ -51,10 +53,13 @@ import (
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/activation"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/activity"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/config"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/dropwriter"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/resources"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+	daemonapi "github.com/gitpod-io/gitpod/ws-daemon/api"
 )
 
 const (
@@ -63,6 +68,12 @@ const (
 	gitpodGID       = 33333
 	gitpodGroupName = "gitpod"
 	desktopIDEPort  = 24000
+
+	// heartbeatInterval is the time between two checks for user activity, which matches the heartbeat interval of the IDE
+	heartbeatInterval = 30 * time.Second
+
+	// maxPortAccessLogEntries is the number of requests to exposed ports supervisor keeps for users to inspect
+	maxPortAccessLogEntries = 200
 )
 
 var (
@@ -154,9 +165,10 @@ func Run(options ...RunOption) {
 		return
 	}
 
-	// BEWARE: we can only call buildChildProcEnv once, because it might download env vars from a one-time-secret
+	// BEWARE: we can only create the env var store once, because it might download env vars from a one-time-secret
 	//         URL, which would fail if we tried another time.
-	childProcEnvvars := buildChildProcEnv(cfg, nil)
+	envvars := newEnvVarStore(cfg, nil)
+	childProcEnvvars := envvars.ChildProcEnv()
 
 	err = AddGitpodUserIfNotExists()
 	if err != nil {
@@ -198,6 +210,14 @@ func Run(options ...RunOption) {
 		slirp = ports.Slirp4Netns("/.supervisor/slirp4netns.sock/slirp4netns.sock")
 	}
 
+	// Tracing is opt-in because a workspace usually has no Jaeger agent to report to.
+	if os.Getenv("JAEGER_ENDPOINT") != "" || os.Getenv("JAEGER_AGENT_HOST") != "" {
+		if closer := tracing.Init("supervisor"); closer != nil {
+			defer closer.Close()
+		}
+	}
+	profile := newBootProfile(bootProfileLocation)
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	internalPorts := []uint32{uint32(cfg.IDEPort), uint32(cfg.APIEndpointPort), uint32(cfg.SSHPort)}
@@ -206,17 +226,17 @@ func Run(options ...RunOption) {
 	}
 
 	var (
-		shutdown                           = make(chan ShutdownReason, 1)
-		ideReady                           = &ideReadyState{cond: sync.NewCond(&sync.Mutex{})}
-		desktopIdeReady     *ideReadyState = nil
-		cstate                             = NewInMemoryContentState(cfg.RepoRoot)
-		gitpodService                      = createGitpodService(cfg, tokenService)
-		gitpodConfigService                = config.NewConfigService(cfg.RepoRoot+"/.gitpod.yml", cstate.ContentReady(), log.Log)
-		portMgmt                           = ports.NewManager(
+		shutdown                               = make(chan ShutdownReason, 1)
+		ideReady                               = &ideReadyState{cond: sync.NewCond(&sync.Mutex{})}
+		desktopIdeReady     *ideReadyState     = nil
+		desktopIDE          *desktopIDEManager = nil
+		cstate                                 = NewInMemoryContentState(cfg.RepoRoot)
+		gitpodService                          = createGitpodService(cfg, tokenService)
+		gitpodConfigService                    = config.NewConfigService(cfg.RepoRoot+"/.gitpod.yml", cstate.ContentReady(), log.Log)
+		servedPorts                            = ports.NewServedPortsObserver(cfg.GetServedPortsRefreshInterval())
+		portMgmt                               = ports.NewManager(
 			createExposedPortsImpl(cfg, gitpodService),
-			&ports.PollingServedPortsObserver{
-				RefreshInterval: 2 * time.Second,
-			},
+			servedPorts,
 			ports.NewConfigService(cfg.WorkspaceID, gitpodConfigService, gitpodService),
 			tunneledPortsService,
 			slirp,
@@ -227,11 +247,18 @@ func Run(options ...RunOption) {
 		taskManager         = newTasksManager(cfg, termMuxSrv, cstate, nil)
 		analytics           = analytics.NewFromEnvironment()
 		notificationService = NewNotificationService()
+		terminalActivity    = &activity.Tracker{}
+		apiActivity         = &activity.Tracker{}
+		portActivity        = &activity.Tracker{}
+		sshActivity         = &activity.Tracker{}
 	)
 	if cfg.DesktopIDE != nil {
 		desktopIdeReady = &ideReadyState{cond: sync.NewCond(&sync.Mutex{})}
+		desktopIDE = newDesktopIDEManager(cfg, cfg.DesktopIDE, envvars.ChildProcEnv, cstate, desktopIdeReady)
+		desktopIDE.profile = profile
 	}
 	tokenService.provider[KindGit] = []tokenProvider{NewGitTokenProvider(gitpodService, cfg.WorkspaceConfig, notificationService)}
+	go newGitTokenRefresher(tokenService, notificationService).Run(ctx)
 
 	go gitpodConfigService.Watch(ctx)
 
@@ -252,32 +279,108 @@ func Run(options ...RunOption) {
 		}
 	}
 	termMuxSrv.Env = childProcEnvvars
+	termMuxSrv.EnvProvider = envvars.ChildProcEnv
+	go func() {
+		<-cstate.ContentReady()
+		envvars.Watch(ctx, workspaceEnvVarsFile)
+	}()
 	termMuxSrv.DefaultCreds = &syscall.Credential{
 		Uid: gitpodUID,
 		Gid: gitpodGID,
 	}
+	termMuxSrv.OnInput = terminalActivity.Mark
+	termMuxSrv.CgroupBasePath = resources.DefaultCgroupBasePath
+	if recordings := newSessionRecordings(cfg); recordings != nil {
+		termMux.Recordings = recordings
+		go prepareSessionRecordings(ctx, recordings, cstate)
+	}
+
+	dotfiles := newDotfilesInstaller(cfg, tokenService, childProcEnvvars, gitpodConfigService.Read)
+	registerNotificationCommands(notificationService, taskManager, portMgmt, termMuxSrv)
+	controlService := &ControlService{cfg: cfg, portsManager: portMgmt, dotfiles: dotfiles}
+	if gitpodService != nil {
+		controlService.gitpodService = gitpodService
+	}
+
+	activitySources := map[string]activity.Source{
+		activity.SourceTerminal: terminalActivity,
+		activity.SourceAPI:      apiActivity,
+		activity.SourcePort:     portActivity,
+		activity.SourceSSH:      sshActivity,
+	}
+	var activityGitpodService gitpod.APIInterface
+	if gitpodService != nil {
+		activityGitpodService = gitpodService
+	}
 
 	apiServices := []RegisterableService{
 		&statusService{
 			ContentState:    cstate,
 			Ports:           portMgmt,
 			Tasks:           taskManager,
+			Resources:       resources.NewSampler(cfg.WorkspaceRoot),
+			Terminals:       termMuxSrv,
 			ideReady:        ideReady,
 			desktopIdeReady: desktopIdeReady,
+			desktopIDE:      desktopIDE,
 		},
 		termMuxSrv,
 		RegistrableTokenService{Service: tokenService},
 		notificationService,
 		&InfoService{cfg: cfg, ContentState: cstate},
-		&ControlService{portsManager: portMgmt},
-		&portService{portsManager: portMgmt},
+		controlService,
+		&portService{portsManager: portMgmt, accessLog: ports.NewAccessLog(maxPortAccessLogEntries), accessLogToken: cfg.AccessLogToken},
+		&tasksService{tasks: taskManager},
+		&environmentService{cfg: cfg, env: envvars, gitpodService: gitpodService},
+		&activityService{
+			trackers:      map[string]*activity.Tracker{activity.SourcePort: portActivity},
+			sources:       activitySources,
+			started:       time.Now(),
+			cfg:           cfg,
+			gitpodService: activityGitpodService,
+		},
+		&fileService{},
+		&gitService{
+			RepoRoot:     cfg.RepoRoot,
+			ContentState: cstate,
+			Env:          childProcEnvvars,
+			Credential:   &syscall.Credential{Uid: gitpodUID, Gid: gitpodGID},
+			Tokens:       tokenService,
+		},
 	}
 	apiServices = append(apiServices, additionalServices...)
 
 	if !cfg.isHeadless() {
 		// We need to checkout dotfiles first, because they may be changing the path which affects the IDE.
 		// TODO(cw): provide better feedback if the IDE start fails because of the dotfiles (provide any feedback at all).
-		installDotfiles(ctx, cfg, tokenService, childProcEnvvars)
+		_, err := dotfiles.Install(ctx, false)
+		if err != nil && err != errNoDotfiles && err != errDotfilesDisabled {
+			log.WithError(err).Warn("installing dotfiles failed")
+			_, _ = notificationService.Notify(ctx, &api.NotifyRequest{
+				Level:   api.NotifyRequest_WARNING,
+				Message: fmt.Sprintf("Installing your dotfiles failed: %s. See ~/.dotfiles.log for details.", err),
+			})
+		}
+	}
+
+	var supervisorMetrics *metrics
+	if cfg.MetricsEnable {
+		supervisorMetrics = newMetrics(taskManager, portMgmt)
+		go supervisorMetrics.ObserveContentInit(cstate)
+		go supervisorMetrics.ObserveIDEReady(WebIDE, ideReady)
+		if cfg.DesktopIDE != nil {
+			go supervisorMetrics.ObserveIDEReady(DesktopIDE, desktopIdeReady)
+		}
+	}
+
+	go profile.ObserveContent(ctx, cstate)
+	go profile.ObserveTasks(ctx, taskManager)
+	if !cfg.isHeadless() {
+		go profile.ObserveIDE(ctx, bootPhaseIDE, ideReady)
+		if cfg.DesktopIDE != nil {
+			go profile.ObserveIDE(ctx, bootPhaseDesktopIDE, desktopIdeReady)
+		}
+		go profile.ObservePorts(ctx, cstate, portMgmt)
 	}
 
 	var ideWG sync.WaitGroup
@@ -285,16 +388,19 @@ func Run(options ...RunOption) {
 	go startAndWatchIDE(ctx, cfg, &cfg.IDE, childProcEnvvars, &ideWG, ideReady, WebIDE)
 	if cfg.DesktopIDE != nil {
 		ideWG.Add(1)
-		go startAndWatchIDE(ctx, cfg, cfg.DesktopIDE, childProcEnvvars, &ideWG, desktopIdeReady, DesktopIDE)
+		go desktopIDE.Run(ctx, &ideWG)
 	}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go startContentInit(ctx, cfg, &wg, cstate)
 	wg.Add(1)
-	go startAPIEndpoint(ctx, cfg, &wg, apiServices, tunneledPortsService, apiEndpointOpts...)
+	go startAPIEndpoint(ctx, cfg, &wg, apiServices, tunneledPortsService, taskManager, supervisorMetrics, append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(apiActivityInterceptor(apiActivity)),
+		grpc.ChainStreamInterceptor(apiActivityStreamInterceptor(apiActivity)),
+	}, apiEndpointOpts...)...)
 	wg.Add(1)
-	go startSSHServer(ctx, cfg, &wg, childProcEnvvars)
+	go startSSHServer(ctx, cfg, &wg, childProcEnvvars, gitpodService, sshActivity)
 	wg.Add(1)
 	tasksSuccessChan := make(chan taskSuccess, 1)
 	go taskManager.Run(ctx, &wg, tasksSuccessChan)
@@ -307,6 +413,27 @@ func Run(options ...RunOption) {
 	} else {
 		wg.Add(1)
 		go portMgmt.Run(ctx, &wg)
+		if polling, ok := servedPorts.(*ports.PollingServedPortsObserver); ok {
+			go resetPortsPollingOnTaskStart(ctx, taskManager, polling)
+		}
+		if cfg.PortsWebhookURL != "" {
+			go ports.NewPortsWebhook(cfg.PortsWebhookURL).Run(ctx, portMgmt)
+		}
+		go resources.NewMemoryWatcher().Run(ctx, func(evt resources.MemoryEvent) {
+			notifyMemoryEvent(ctx, notificationService, evt)
+		})
+		go watchDiskQuota(ctx, notificationService)
+		if gitpodService != nil {
+			// The IDE sends its own heartbeats. We send heartbeats for everything else the user does in the workspace,
+			// so that e.g. users working only through SSH don't get timed out.
+			go (&activity.Heartbeat{
+				Sources:  activitySources,
+				Interval: heartbeatInterval,
+				Send: func(ctx context.Context) error {
+					return gitpodService.SendHeartBeat(ctx, &gitpod.SendHeartBeatOptions{InstanceID: cfg.WorkspaceInstanceID})
+				},
+			}).Run(ctx)
+		}
 	}
 
 	if cfg.PreventMetadataAccess {
@@ -324,6 +451,11 @@ func Run(options ...RunOption) {
 		go func() {
 			<-cstate.ContentReady()
 
+			if !shouldUnshallow(cfg.RepoRoot, childProcEnvvars) {
+				log.Debug("not unshallowing local repository")
+				return
+			}
+
 			start := time.Now()
 			defer func() {
 				log.Debugf("unshallow of local repository took %v", time.Since(start))
@@ -339,6 +471,38 @@ func Run(options ...RunOption) {
 				log.WithError(err).Error("git fetch error")
 			}
 		}()
+		go func() {
+			<-cstate.ContentReady()
+
+			if !shouldPullLFS(cfg.RepoRoot, childProcEnvvars) {
+				return
+			}
+
+			start := time.Now()
+			defer func() {
+				log.Debugf("pulling Git LFS objects of local repository took %v", time.Since(start))
+			}()
+
+			cmd := runAsGitpodUser(exec.Command("git", "lfs", "pull"))
+			cmd.Env = childProcEnvvars
+			cmd.Dir = cfg.RepoRoot
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			err := cmd.Run()
+			if err != nil {
+				log.WithError(err).Error("git lfs pull error")
+				return
+			}
+
+			// the LFS objects are in place now, there's no need to pull them again when the workspace restarts
+			cmd = runAsGitpodUser(exec.Command("git", "config", "--unset", git.ConfigLFS))
+			cmd.Env = childProcEnvvars
+			cmd.Dir = cfg.RepoRoot
+			err = cmd.Run()
+			if err != nil {
+				log.WithError(err).Warn("cannot unset " + git.ConfigLFS)
+			}
+		}()
 	}
 
 	sigChan := make(chan os.Signal, 1)
@@ -363,179 +527,6 @@ func Run(options ...RunOption) {
 	wg.Wait()
 }
 
-func installDotfiles(ctx context.Context, cfg *Config, tokenService *InMemoryTokenService, childProcEnvvars []string) {
-	repo := cfg.DotfileRepo
-	if repo == "" {
-		return
-	}
-
-	const dotfilePath = "/home/gitpod/.dotfiles"
-	if _, err := os.Stat(dotfilePath); err == nil {
-		// dotfile path exists already - nothing to do here
-		return
-	}
-
-	prep := func(cfg *Config, out io.Writer, name string, args ...string) *exec.Cmd {
-		cmd := exec.Command(name, args...)
-		cmd.Dir = "/home/gitpod"
-		cmd.Env = childProcEnvvars
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			// All supervisor children run as gitpod user. The environment variables we produce are also
-			// gitpod user specific.
-			Credential: &syscall.Credential{
-				Uid: gitpodUID,
-				Gid: gitpodGID,
-			},
-		}
-		cmd.Stdout = out
-		cmd.Stderr = out
-		return cmd
-	}
-
-	err := func() (err error) {
-		out, err := os.OpenFile("/home/gitpod/.dotfiles.log", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-		if err != nil {
-			return err
-		}
-		defer out.Close()
-
-		defer func() {
-			if err != nil {
-				out.WriteString(fmt.Sprintf("# dotfile init failed: %s\n", err.Error()))
-			}
-		}()
-
-		done := make(chan error, 1)
-		go func() {
-			repoUrl, err := url.Parse(repo)
-			if err != nil {
-				done <- err
-				close(done)
-				return
-			}
-			authProvider := func() (username string, password string, err error) {
-				resp, err := tokenService.GetToken(ctx, &api.GetTokenRequest{
-					Host: repoUrl.Host,
-					Kind: KindGit,
-				})
-				if err != nil {
-					return
-				}
-				username = resp.User
-				password = resp.Token
-				return
-			}
-			client := &git.Client{
-				AuthProvider: authProvider,
-				AuthMethod:   git.BasicAuth,
-				Location:     dotfilePath,
-				RemoteURI:    repo,
-			}
-			done <- client.Clone(ctx)
-			close(done)
-		}()
-		select {
-		case err := <-done:
-			if err != nil {
-				return err
-			}
-		case <-time.After(120 * time.Second):
-			return xerrors.Errorf("dotfiles repo clone did not finish within two minutes")
-		}
-
-		filepath.Walk(dotfilePath, func(name string, info os.FileInfo, err error) error {
-			if err == nil {
-				err = os.Chown(name, gitpodUID, gitpodGID)
-			}
-			return err
-		})
-
-		// at this point we have the dotfile repo cloned, let's try and install it
-		var candidates = []string{
-			"install.sh",
-			"install",
-			"bootstrap.sh",
-			"bootstrap",
-			"script/bootstrap",
-			"setup.sh",
-			"setup",
-			"script/setup",
-		}
-		for _, c := range candidates {
-			fn := filepath.Join(dotfilePath, c)
-			stat, err := os.Stat(fn)
-			if err != nil {
-				_, _ = out.WriteString(fmt.Sprintf("# installation script candidate %s is not available\n", fn))
-				continue
-			}
-			if stat.IsDir() {
-				_, _ = out.WriteString(fmt.Sprintf("# installation script candidate %s is a directory\n", fn))
-				continue
-			}
-			if stat.Mode()&0111 == 0 {
-				_, _ = out.WriteString(fmt.Sprintf("# installation script candidate %s is not executable\n", fn))
-				continue
-			}
-
-			_, _ = out.WriteString(fmt.Sprintf("# executing installation script candidate %s\n", fn))
-
-			// looks like we've found a candidate, let's run it
-			cmd := prep(cfg, out, "/bin/sh", "-c", "exec "+fn)
-			err = cmd.Start()
-			if err != nil {
-				return err
-			}
-			done := make(chan error, 1)
-			go func() {
-				done <- cmd.Wait()
-				close(done)
-			}()
-
-			select {
-			case err = <-done:
-				return err
-			case <-time.After(120 * time.Second):
-				cmd.Process.Kill()
-				return xerrors.Errorf("installation process %s tool longer than 120 seconds", fn)
-			}
-		}
-
-		// no installation script candidate was found, let's try and symlink this stuff
-		err = filepath.Walk(dotfilePath, func(path string, info fs.FileInfo, err error) error {
-			if strings.Contains(path, "/.git") {
-				// don't symlink the .git directory or any of its content
-				return nil
-			}
-
-			homeFN := filepath.Join("/home/gitpod", strings.TrimPrefix(path, dotfilePath))
-			if _, err := os.Stat(homeFN); err == nil {
-				// homeFN exists already - do nothing
-				return nil
-			}
-
-			if info.IsDir() {
-				err = os.MkdirAll(homeFN, info.Mode().Perm())
-				if err != nil {
-					return err
-				}
-				return nil
-			}
-
-			// write some feedback to the terminal
-			out.WriteString(fmt.Sprintf("# echo linking %s -> %s\n", path, homeFN))
-
-			return os.Symlink(path, homeFN)
-		})
-
-		return nil
-	}()
-	if err != nil {
-		// installing the dotfiles failed for some reason - we must tell the user
-		// TODO(cw): tell the user
-		log.WithError(err).Warn("installing dotfiles failed")
-	}
-}
-
 func createGitpodService(cfg *Config, tknsrv api.TokenServiceServer) *gitpod.APIoverJSONRPC {
 	endpoint, host, err := cfg.GitpodAPIEndpoint()
 	if err != nil {
@@ -550,6 +541,13 @@ func createGitpodService(cfg *Config, tknsrv api.TokenServiceServer) *gitpod.API
 			"function:openPort",
 			"function:getOpenPorts",
 			"function:guessGitTokenScopes",
+			"function:getAllEnvVars",
+			"function:sendHeartBeat",
+			"function:takeSnapshot",
+			"function:waitForSnapshot",
+			"function:getWorkspaceTimeout",
+			"function:setWorkspaceTimeout",
+			"resource:workspace::" + cfg.WorkspaceID + "::get/update",
 		},
 	})
 	if err != nil {
@@ -801,6 +799,9 @@ func prepareIDELaunch(cfg *Config, ideConfig *IDEConfig, childProcEnvvars []stri
 		},
 	}
 	cmd.Env = childProcEnvvars
+	for k, v := range ideConfig.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
 
 	// Here we must resist the temptation to "neaten up" the IDE output for headless builds.
 	// This would break the JSON parsing of the headless builds.
@@ -821,10 +822,12 @@ func prepareIDELaunch(cfg *Config, ideConfig *IDEConfig, childProcEnvvars []stri
 //
 // Beware: if config contains an OTS URL the results may differ on subsequent calls.
 func buildChildProcEnv(cfg *Config, envvars []string) []string {
-	if envvars == nil {
-		envvars = os.Environ()
-	}
+	return newEnvVarStore(cfg, envvars).ChildProcEnv()
+}
 
+// composeChildProcEnv computes the environment variables passed to a child process from envvars
+// and the scoped variables, which take precedence.
+func composeChildProcEnv(cfg *Config, envvars []string, scoped map[string]string) []string {
 	envs := make(map[string]string)
 	for _, e := range envvars {
 		segs := strings.SplitN(e, "=", 2)
@@ -842,18 +845,12 @@ func buildChildProcEnv(cfg *Config, envvars []string) []string {
 	}
 	envs["SUPERVISOR_ADDR"] = fmt.Sprintf("localhost:%d", cfg.APIEndpointPort)
 
-	if cfg.EnvvarOTS != "" {
-		es, err := downloadEnvvarOTS(cfg.EnvvarOTS)
-		if err != nil {
-			log.WithError(err).Warn("unable to download environment variables from OTS")
+	for k, v := range scoped {
+		if isBlacklistedEnvvar(k) {
+			continue
 		}
-		for k, v := range es {
-			if isBlacklistedEnvvar(k) {
-				continue
-			}
 
-			envs[k] = v
-		}
+		envs[k] = v
 	}
 
 	// We're forcing basic environment variables here, because supervisor acts like a login process at this point.
@@ -891,7 +888,7 @@ func buildChildProcEnv(cfg *Config, envvars []string) []string {
 	return env
 }
 
-func downloadEnvvarOTS(url string) (res map[string]string, err error) {
+func downloadEnvvarOTS(url string) (res []scopedEnvVar, err error) {
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Get(url)
 	if err != nil {
@@ -900,25 +897,15 @@ func downloadEnvvarOTS(url string) (res map[string]string, err error) {
 
 	defer resp.Body.Close()
 
-	var dl []struct {
-		Name  string `json:"name"`
-		Value string `json:"value"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&dl)
+	err = json.NewDecoder(resp.Body).Decode(&res)
 	if err != nil {
 		return nil, err
 	}
-
-	res = make(map[string]string)
-	for _, e := range dl {
-		res[e.Name] = e.Value
-	}
 	return res, nil
 }
 
-func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (desktopIDEStatus *DesktopIDEStatus) {
-	defer log.WithField("ide", ide.String()).Info("IDE is ready")
-
+// ideProbeURL computes the URL of the HTTP readiness probe of an IDE.
+func ideProbeURL(cfg *Config, ideConfig *IDEConfig, ide IDEKind) string {
 	defaultIfEmpty := func(value, defaultValue string) string {
 		if len(value) == 0 {
 			return defaultValue
@@ -937,16 +924,24 @@ func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (deskt
 	if ide == DesktopIDE {
 		defaultProbePort = desktopIDEPort
 	}
+	var (
+		schema = defaultIfEmpty(ideConfig.ReadinessProbe.HTTPProbe.Schema, "http")
+		host   = defaultIfEmpty(ideConfig.ReadinessProbe.HTTPProbe.Host, "localhost")
+		port   = defaultIfZero(ideConfig.ReadinessProbe.HTTPProbe.Port, defaultProbePort)
+	)
+	return fmt.Sprintf("%s://%s:%d/%s", schema, host, port, strings.TrimPrefix(ideConfig.ReadinessProbe.HTTPProbe.Path, "/"))
+}
+
+func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (desktopIDEStatus *DesktopIDEStatus) {
+	defer log.WithField("ide", ide.String()).Info("IDE is ready")
+
 	switch ideConfig.ReadinessProbe.Type {
 	case ReadinessProcessProbe:
 		return
 
 	case ReadinessHTTPProbe:
 		var (
-			schema = defaultIfEmpty(ideConfig.ReadinessProbe.HTTPProbe.Schema, "http")
-			host   = defaultIfEmpty(ideConfig.ReadinessProbe.HTTPProbe.Host, "localhost")
-			port   = defaultIfZero(ideConfig.ReadinessProbe.HTTPProbe.Port, defaultProbePort)
-			url    = fmt.Sprintf("%s://%s:%d/%s", schema, host, port, strings.TrimPrefix(ideConfig.ReadinessProbe.HTTPProbe.Path, "/"))
+			url    = ideProbeURL(cfg, ideConfig, ide)
 			client = http.Client{Timeout: 1 * time.Second}
 			tick   = time.NewTicker(500 * time.Millisecond)
 		)
@@ -1009,7 +1004,37 @@ func isBlacklistedEnvvar(name string) bool {
 	return false
 }
 
-func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, services []RegisterableService, tunneled *ports.TunneledPortsService, opts ...grpc.ServerOption) {
+// apiActivityMethods are the API calls which indicate that the user is working in the workspace.
+// Calls which clients make on their own, e.g. status polling, must not be listed here.
+var apiActivityMethods = map[string]struct{}{
+	"/supervisor.TerminalService/Open":          {},
+	"/supervisor.TerminalService/Exec":          {},
+	"/supervisor.TasksService/RestartTask":      {},
+	"/supervisor.TasksService/RerunTask":        {},
+	"/supervisor.ActivityService/ExtendTimeout": {},
+}
+
+// apiActivityInterceptor marks the tracker whenever one of the apiActivityMethods is called.
+func apiActivityInterceptor(tracker *activity.Tracker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := apiActivityMethods[info.FullMethod]; ok {
+			tracker.Mark()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// apiActivityStreamInterceptor marks the tracker whenever one of the streaming apiActivityMethods is called.
+func apiActivityStreamInterceptor(tracker *activity.Tracker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := apiActivityMethods[info.FullMethod]; ok {
+			tracker.Mark()
+		}
+		return handler(srv, ss)
+	}
+}
+
+func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, services []RegisterableService, tunneled *ports.TunneledPortsService, tasks *tasksManager, metrics *metrics, opts ...grpc.ServerOption) {
 	defer wg.Done()
 	defer log.Debug("startAPIEndpoint shutdown")
 
@@ -1018,10 +1043,16 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 		log.WithError(err).Fatal("cannot start health endpoint")
 	}
 
+	if metrics != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(metrics.GRPC.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(metrics.GRPC.StreamServerInterceptor()),
+		)
+	}
 	if cfg.DebugEnable {
 		opts = append(opts,
-			grpc.UnaryInterceptor(grpc_logrus.UnaryServerInterceptor(log.Log)),
-			grpc.StreamInterceptor(grpc_logrus.StreamServerInterceptor(log.Log)),
+			grpc.ChainUnaryInterceptor(grpc_logrus.UnaryServerInterceptor(log.Log)),
+			grpc.ChainStreamInterceptor(grpc_logrus.StreamServerInterceptor(log.Log)),
 		)
 	}
 
@@ -1041,6 +1072,9 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 			}
 		}
 	}
+	if metrics != nil {
+		metrics.GRPC.InitializeMetrics(grpcServer)
+	}
 	go grpcServer.Serve(grpcMux)
 
 	httpMux := m.Match(cmux.HTTP1Fast())
@@ -1073,6 +1107,12 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 		tunnelOverWebSocket(tunneled, conn)
 	}))
 	routes.Handle("/_supervisor/frontend", http.FileServer(http.Dir(cfg.FrontendLocation)))
+	if metrics != nil {
+		// not prefixed with /_supervisor, so that it's only reachable from within the cluster
+		routes.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	}
+	// not prefixed with /_supervisor, so that it's only reachable from within the cluster
+	routes.Handle("/headless-log/", http.StripPrefix("/headless-log", http.HandlerFunc(tasks.serveHeadlessLog)))
 	if cfg.DebugEnable {
 		routes.Handle("/_supervisor/debug/tunnels", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 			rw.Header().Set("X-Content-Type-Options", "nosniff")
@@ -1090,6 +1130,15 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 	l.Close()
 }
 
+const (
+	// tunnelChannelType is the type of SSH channels which clients open to establish a port tunnel.
+	// Their extra data is a TunnelPortRequest which references a tunnel installed by the Tunnel RPC.
+	tunnelChannelType = "tunnel"
+	// directTCPIPChannelType is the type of SSH channels which standard SSH clients open to forward
+	// a local port (RFC 4254, section 7.2). We only forward ports which were tunneled with the Tunnel RPC.
+	directTCPIPChannelType = "direct-tcpip"
+)
+
 func tunnelOverWebSocket(tunneled *ports.TunneledPortsService, conn *gitpod.WebsocketConnection) {
 	hostKey, err := generateHostKey()
 	if err != nil {
@@ -1113,6 +1162,11 @@ func tunnelOverWebSocket(tunneled *ports.TunneledPortsService, conn *gitpod.Webs
 	go ssh.DiscardRequests(reqs)
 	go func() {
 		for ch := range chans {
+			if ch.ChannelType() != tunnelChannelType && ch.ChannelType() != directTCPIPChannelType {
+				log.WithField("type", ch.ChannelType()).Warn("tunnel: rejecting unknown ssh channel type")
+				_ = ch.Reject(ssh.UnknownChannelType, "unknown channel type: "+ch.ChannelType())
+				continue
+			}
 			go tunnelOverSSH(conn.Ctx, tunneled, ch)
 		}
 	}()
@@ -1131,15 +1185,15 @@ func generateHostKey() (ssh.Signer, error) {
 }
 
 func tunnelOverSSH(ctx context.Context, tunneled *ports.TunneledPortsService, newCh ssh.NewChannel) {
-	tunnelReq := &api.TunnelPortRequest{}
-	err := proto.Unmarshal(newCh.ExtraData(), tunnelReq)
-	if err != nil {
-		log.WithError(err).Error("tunnel: invalid ssh chan request")
-		_ = newCh.Reject(ssh.Prohibited, err.Error())
-		return
+	var (
+		tunnel net.Conn
+		err    error
+	)
+	if newCh.ChannelType() == directTCPIPChannelType {
+		tunnel, err = dialDirectTCPIP(tunneled, newCh.ExtraData())
+	} else {
+		tunnel, err = establishTunnel(ctx, tunneled, newCh.ExtraData())
 	}
-
-	tunnel, err := tunneled.EstablishTunnel(ctx, tunnelReq.ClientId, tunnelReq.Port, tunnelReq.TargetPort)
 	if err != nil {
 		log.WithError(err).Error("tunnel: failed to establish")
 		_ = newCh.Reject(ssh.Prohibited, err.Error())
@@ -1168,6 +1222,85 @@ func tunnelOverSSH(ctx context.Context, tunneled *ports.TunneledPortsService, ne
 	<-ctx.Done()
 }
 
+// establishTunnel connects a tunnel channel to the tunnel which the Tunnel RPC installed for its client.
+func establishTunnel(ctx context.Context, tunneled *ports.TunneledPortsService, extraData []byte) (net.Conn, error) {
+	var req api.TunnelPortRequest
+	err := proto.Unmarshal(extraData, &req)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid tunnel request: %w", err)
+	}
+	return tunneled.EstablishTunnel(ctx, req.ClientId, req.Port, req.TargetPort)
+}
+
+// directTCPIPRequest is the extra data of a direct-tcpip channel (RFC 4254, section 7.2).
+type directTCPIPRequest struct {
+	Host       string
+	Port       uint32
+	OriginHost string
+	OriginPort uint32
+}
+
+// dialDirectTCPIP connects a direct-tcpip channel to a tunneled port. Standard SSH clients don't tell us
+// on which port they listen, hence they don't become clients of the tunnel and we dial the port directly.
+func dialDirectTCPIP(tunneled *ports.TunneledPortsService, extraData []byte) (net.Conn, error) {
+	port, err := parseDirectTCPIPRequest(tunneled, extraData)
+	if err != nil {
+		return nil, err
+	}
+	return net.Dial("tcp", net.JoinHostPort("localhost", strconv.FormatUint(uint64(port), 10)))
+}
+
+// parseDirectTCPIPRequest returns the port a direct-tcpip channel forwards to. We only forward workspace
+// ports which the Tunnel RPC installed a tunnel for.
+func parseDirectTCPIPRequest(tunneled *ports.TunneledPortsService, extraData []byte) (port uint32, err error) {
+	var req directTCPIPRequest
+	err = ssh.Unmarshal(extraData, &req)
+	if err != nil {
+		return 0, xerrors.Errorf("invalid direct-tcpip request: %w", err)
+	}
+	if req.Host != "localhost" && req.Host != "127.0.0.1" && req.Host != "::1" {
+		return 0, xerrors.Errorf("cannot forward to %s: only workspace ports can be forwarded", req.Host)
+	}
+	if _, exists := tunneled.TunnelDescription(req.Port); !exists {
+		return 0, xerrors.Errorf("cannot forward port %d: the port is not tunneled", req.Port)
+	}
+	return req.Port, nil
+}
+
+// resetPortsPollingOnTaskStart makes the served ports observer poll fast whenever a task starts running,
+// since tasks usually start the processes serving ports.
+func resetPortsPollingOnTaskStart(ctx context.Context, tm *tasksManager, polling *ports.PollingServedPortsObserver) {
+	sub := tm.Subscribe()
+	if sub == nil {
+		log.Warn("cannot subscribe to tasks to adapt served ports polling")
+		return
+	}
+	defer sub.Close()
+
+	running := make(map[string]struct{})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update := <-sub.Updates():
+			if update == nil {
+				return
+			}
+			var started bool
+			for _, t := range update {
+				_, wasRunning := running[t.Id]
+				if t.State == api.TaskState_running && !wasRunning {
+					running[t.Id] = struct{}{}
+					started = true
+				}
+			}
+			if started {
+				polling.Reset()
+			}
+		}
+	}
+}
+
 func stopWhenTasksAreDone(ctx context.Context, wg *sync.WaitGroup, shutdown chan ShutdownReason, successChan <-chan taskSuccess) {
 	defer wg.Done()
 	defer close(shutdown)
@@ -1184,15 +1317,20 @@ func stopWhenTasksAreDone(ctx context.Context, wg *sync.WaitGroup, shutdown chan
 	shutdown <- ShutdownReasonSuccess
 }
 
-func startSSHServer(ctx context.Context, cfg *Config, wg *sync.WaitGroup, childProcEnvvars []string) {
+func startSSHServer(ctx context.Context, cfg *Config, wg *sync.WaitGroup, childProcEnvvars []string, gitpodService *gitpod.APIoverJSONRPC, sshActivity *activity.Tracker) {
 	defer wg.Done()
 
 	if cfg.isHeadless() {
 		return
 	}
 
+	var keySource gitpod.APIInterface
+	if gitpodService != nil {
+		keySource = gitpodService
+	}
+
 	go func() {
-		ssh, err := newSSHServer(ctx, cfg, childProcEnvvars)
+		ssh, err := newSSHServer(ctx, cfg, childProcEnvvars, keySource, sshActivity)
 		if err != nil {
 			log.WithError(err).Error("err creating SSH server")
 			return
@@ -1230,6 +1368,10 @@ func startContentInit(ctx context.Context, cfg *Config, wg *sync.WaitGroup, cst
 
 		// If there is no content descriptor the content must have come from somewhere (i.e. a layer or ws-daemon).
 		// Let's wait for that to happen.
+		progressCtx, stopProgress := context.WithCancel(ctx)
+		defer stopProgress()
+		go watchContentInitProgress(progressCtx, cst)
+
 		// TODO: rewrite using fsnotify
 		t := time.NewTicker(100 * time.Millisecond)
 		for range t.C {
@@ -1262,7 +1404,7 @@ func startContentInit(ctx context.Context, cfg *Config, wg *sync.WaitGroup, cst
 		return
 	}
 
-	src, err := executor.Execute(ctx, "/workspace", f, true)
+	src, err := executor.Execute(initializer.WithProgressReporter(ctx, cst.SetContentProgress), "/workspace", f, true)
 	if err != nil {
 		return
 	}
@@ -1486,6 +1628,32 @@ func analyseConfigChanges(ctx context.Context, wscfg *Config, w analytics.Writer
 	}
 }
 
+// shouldUnshallow returns true if the repository is a shallow clone whose clone strategy did not opt out of
+// fetching the full history in the background.
+func shouldUnshallow(repoRoot string, env []string) bool {
+	run := func(args ...string) string {
+		cmd := runAsGitpodUser(exec.Command("git", args...))
+		cmd.Env = env
+		cmd.Dir = repoRoot
+		out, _ := cmd.Output()
+		return strings.TrimSpace(string(out))
+	}
+
+	if run("rev-parse", "--is-shallow-repository") != "true" {
+		return false
+	}
+	return run("config", "--type=bool", "--get", git.ConfigUnshallow) != "false"
+}
+
+// shouldPullLFS returns true if the content initializer left pulling the Git LFS objects of the repository to us
+func shouldPullLFS(repoRoot string, env []string) bool {
+	cmd := runAsGitpodUser(exec.Command("git", "config", "--get", git.ConfigLFS))
+	cmd.Env = env
+	cmd.Dir = repoRoot
+	out, _ := cmd.Output()
+	return strings.TrimSpace(string(out)) == git.LFSLazy
+}
+
 func runAsGitpodUser(cmd *exec.Cmd) *exec.Cmd {
 	if cmd.SysProcAttr == nil {
 		cmd.SysProcAttr = &syscall.SysProcAttr{}
@@ -1503,3 +1671,175 @@ func handleExit(ec *int) {
 	log.WithField("exitCode", exitCode).Debug("supervisor exit")
 	os.Exit(exitCode)
 }
+
+const (
+	// NotificationCommandRestartTask restarts the task whose ID is the first argument
+	NotificationCommandRestartTask = "restart-task"
+	// NotificationCommandOpenPort exposes the port which is the first argument
+	NotificationCommandOpenPort = "open-port"
+	// NotificationCommandRunCommand runs the command line which is the first argument in a new terminal
+	NotificationCommandRunCommand = "run-command"
+)
+
+// registerNotificationCommands registers the commands notifications can offer to the user.
+func registerNotificationCommands(notifications *NotificationService, tasks *tasksManager, portMgmt *ports.Manager, terminals *terminal.MuxTerminalService) {
+	notifications.RegisterCommand(NotificationCommandRestartTask, func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expected a task ID")
+		}
+		return tasks.RestartTask(ctx, args[0])
+	})
+	notifications.RegisterCommand(NotificationCommandOpenPort, func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expected a port")
+		}
+		port, err := strconv.ParseUint(args[0], 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid port %s: %w", args[0], err)
+		}
+		return portMgmt.Expose(ctx, uint32(port))
+	})
+	notifications.RegisterCommand(NotificationCommandRunCommand, func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expected a command line")
+		}
+		resp, err := terminals.OpenWithOptions(ctx, &api.OpenTerminalRequest{}, terminal.TermOptions{
+			Title: args[0],
+		})
+		if err != nil {
+			return err
+		}
+		term, ok := terminals.Mux.Get(resp.Terminal.Alias)
+		if !ok {
+			return fmt.Errorf("cannot find terminal %s", resp.Terminal.Alias)
+		}
+		// like tasks, the command runs in an interactive shell which stays open once it's done
+		_, err = term.PTY.Write([]byte(args[0] + "\n"))
+		return err
+	})
+}
+
+// notifyMemoryEvent lets the user know why processes get slow or die.
+func notifyMemoryEvent(ctx context.Context, notifications *NotificationService, evt resources.MemoryEvent) {
+	var req *api.NotifyRequest
+	switch evt.Type {
+	case resources.OOMKill:
+		msg := "A process was killed because the workspace ran out of memory"
+		if evt.Command != "" {
+			msg = fmt.Sprintf("%s: %s (PID %d)", msg, evt.Command, evt.PID)
+		}
+		req = &api.NotifyRequest{Level: api.NotifyRequest_ERROR, Message: msg + "."}
+	case resources.MemoryPressure:
+		req = &api.NotifyRequest{
+			Level:   api.NotifyRequest_WARNING,
+			Message: fmt.Sprintf("The workspace is running low on memory (%s of %s used). Processes might be killed if the memory usage grows further.", formatGiB(evt.Used), formatGiB(evt.Limit)),
+		}
+	default:
+		return
+	}
+	log.WithField("event", evt).Warn("workspace memory event")
+
+	_, err := notifications.Notify(ctx, req)
+	if err != nil {
+		log.WithError(err).Warn("cannot notify about memory event")
+	}
+}
+
+// inWorkspaceDaemonSocket is the socket of the in-workspace daemon service
+const inWorkspaceDaemonSocket = "/.workspace/daemon.sock"
+
+// watchContentInitProgress relays the progress of the content initialization ws-daemon runs for us, until it's done.
+func watchContentInitProgress(ctx context.Context, cst ContentState) {
+	if _, err := os.Stat(inWorkspaceDaemonSocket); err != nil {
+		log.WithError(err).Debug("in-workspace daemon service is not available, content init progress is not available")
+		return
+	}
+
+	conn, err := grpc.DialContext(ctx, "unix://"+inWorkspaceDaemonSocket, grpc.WithInsecure())
+	if err != nil {
+		log.WithError(err).Warn("cannot connect to in-workspace daemon service, content init progress is not available")
+		return
+	}
+	defer conn.Close()
+
+	events, err := daemonapi.NewInWorkspaceServiceClient(conn).WatchInitProgress(ctx, &daemonapi.WatchInitProgressRequest{})
+	if err != nil {
+		log.WithError(err).Warn("cannot watch content init progress")
+		return
+	}
+	for {
+		evt, err := events.Recv()
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return
+		}
+		if err != nil {
+			log.WithError(err).Debug("cannot watch content init progress")
+			return
+		}
+		if evt.Done {
+			return
+		}
+
+		cst.SetContentProgress(initializer.Progress{
+			Phase:      initializer.ProgressPhase(evt.Phase),
+			Percent:    int(evt.Percent),
+			BytesDone:  evt.BytesDone,
+			BytesTotal: evt.BytesTotal,
+		})
+	}
+}
+
+// watchDiskQuota lets the user know when the workspace content comes close to its disk quota.
+func watchDiskQuota(ctx context.Context, notifications *NotificationService) {
+	socketFN := inWorkspaceDaemonSocket
+	if _, err := os.Stat(socketFN); err != nil {
+		log.WithError(err).Debug("in-workspace daemon service is not available, disk quota events are not available")
+		return
+	}
+
+	conn, err := grpc.DialContext(ctx, "unix://"+socketFN, grpc.WithInsecure())
+	if err != nil {
+		log.WithError(err).Warn("cannot connect to in-workspace daemon service, disk quota events are not available")
+		return
+	}
+	defer conn.Close()
+
+	events, err := daemonapi.NewInWorkspaceServiceClient(conn).WatchDiskQuota(ctx, &daemonapi.WatchDiskQuotaRequest{})
+	if err != nil {
+		log.WithError(err).Warn("cannot watch disk quota")
+		return
+	}
+	for {
+		evt, err := events.Recv()
+		if status.Code(err) == codes.FailedPrecondition {
+			log.Debug("workspace has no disk quota")
+			return
+		}
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return
+		}
+		if err != nil {
+			log.WithError(err).Warn("cannot watch disk quota")
+			return
+		}
+
+		notifyDiskQuota(ctx, notifications, evt)
+	}
+}
+
+// notifyDiskQuota lets the user know that writes will fail soon.
+func notifyDiskQuota(ctx context.Context, notifications *NotificationService, evt *daemonapi.WatchDiskQuotaResponse) {
+	log.WithField("used", evt.UsedBytes).WithField("quota", evt.QuotaBytes).Warn("workspace is close to its disk quota")
+
+	_, err := notifications.Notify(ctx, &api.NotifyRequest{
+		Level:   api.NotifyRequest_WARNING,
+		Message: fmt.Sprintf("The workspace is running out of disk space (%s of %s used). Writing files will fail once the disk quota is reached.", formatGiB(evt.UsedBytes), formatGiB(evt.QuotaBytes)),
+	})
+	if err != nil {
+		log.WithError(err).Warn("cannot notify about disk quota")
+	}
+}
+
+func formatGiB(bytes int64) string {
+	return fmt.Sprintf("%.1f GiB", float64(bytes)/(1<<30))
+}
@@ -5,6 +5,7 @@
 package supervisor
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
 )
 
 func TestBuildChildProcEnv(t *testing.T) {
@@ -151,3 +156,54 @@ func TestIsBlacklistedEnvvar(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDirectTCPIPRequest(t *testing.T) {
+	tunneled := ports.NewTunneledPortsService(false)
+	_, err := tunneled.Tunnel(context.Background(), &ports.TunnelOptions{}, &ports.PortTunnelDescription{LocalPort: 8080, TargetPort: 3000, Visibility: api.TunnelVisiblity_host})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Expectation struct {
+		Port  uint32
+		Error string
+	}
+	tests := []struct {
+		Desc        string
+		ExtraData   []byte
+		Expectation Expectation
+	}{
+		{
+			Desc:        "tunneled port",
+			ExtraData:   ssh.Marshal(directTCPIPRequest{Host: "localhost", Port: 8080, OriginHost: "127.0.0.1", OriginPort: 54321}),
+			Expectation: Expectation{Port: 8080},
+		},
+		{
+			Desc:        "port which is not tunneled",
+			ExtraData:   ssh.Marshal(directTCPIPRequest{Host: "localhost", Port: 9090}),
+			Expectation: Expectation{Error: "cannot forward port 9090: the port is not tunneled"},
+		},
+		{
+			Desc:        "other host",
+			ExtraData:   ssh.Marshal(directTCPIPRequest{Host: "example.com", Port: 8080}),
+			Expectation: Expectation{Error: "cannot forward to example.com: only workspace ports can be forwarded"},
+		},
+		{
+			Desc:        "invalid extra data",
+			ExtraData:   []byte{0x01},
+			Expectation: Expectation{Error: "invalid direct-tcpip request: ssh: short read"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			port, err := parseDirectTCPIPRequest(tunneled, test.ExtraData)
+			act := Expectation{Port: port}
+			if err != nil {
+				act.Error = err.Error()
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -11,12 +11,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/xerrors"
+
 	"github.com/gitpod-io/gitpod/common-go/log"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/logs"
@@ -69,6 +72,28 @@ type task struct {
 	successChan chan taskSuccess
 	title       string
 	lastOutput  string
+
+	// needs are the tasks which have to be ready before this task is started
+	needs []*task
+	// needed is true if other tasks wait for this task to become ready
+	needed bool
+	// readyMarker is created by the task command once before and init are done.
+	// It's only used if the task is needed and has no ready command.
+	readyMarker string
+	ready       chan struct{}
+	readyOnce   sync.Once
+	readyErr    error
+	// logDone is closed once the prebuild log of a headless task is complete
+	logDone chan struct{}
+}
+
+// markReady unblocks all tasks which need this task. A non-nil err means the task
+// will never become ready.
+func (t *task) markReady(err error) {
+	t.readyOnce.Do(func() {
+		t.readyErr = err
+		close(t.ready)
+	})
 }
 
 type headlessTaskProgressReporter interface {
@@ -217,16 +242,96 @@ func (tm *tasksManager) init(ctx context.Context) {
 			config:      config,
 			successChan: make(chan taskSuccess, 1),
 			title:       title,
+			ready:       make(chan struct{}),
+			logDone:     make(chan struct{}),
+		}
+		tm.tasks = append(tm.tasks, task)
+	}
+
+	err = resolveTaskDependencies(tm.tasks)
+	if err != nil {
+		log.WithError(err).Error("cannot resolve task dependencies, starting all tasks at once")
+	}
+
+	for _, task := range tm.tasks {
+		if task.needed && task.config.Ready == nil {
+			task.readyMarker = tm.storeLocation + "/ready-" + task.Id
+			// the marker of a previous workspace start must not make the task ready
+			_ = os.Remove(task.readyMarker)
 		}
 		task.command = getCommand(task, tm.config.isHeadless(), tm.contentSource, tm.storeLocation)
 		if tm.config.isHeadless() && task.command == "exit" {
 			task.State = api.TaskState_closed
 			task.successChan <- taskSuccessful
+			task.markReady(nil)
+			close(task.logDone)
 		}
-		tm.tasks = append(tm.tasks, task)
 	}
 }
 
+// resolveTaskDependencies links tasks to the tasks they need by name.
+// If the dependencies contain a cycle, all dependencies are dropped and an error is returned.
+func resolveTaskDependencies(tasks []*task) error {
+	byName := make(map[string][]*task)
+	for _, t := range tasks {
+		if t.config.Name != nil {
+			byName[*t.config.Name] = append(byName[*t.config.Name], t)
+		}
+	}
+	for _, t := range tasks {
+		if t.config.Needs == nil {
+			continue
+		}
+		for _, name := range *t.config.Needs {
+			needed, ok := byName[name]
+			if !ok {
+				log.WithField("task", t.Id).WithField("needs", name).Warn("task needs an unknown task, ignoring the dependency")
+				continue
+			}
+			t.needs = append(t.needs, needed...)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*task]int, len(tasks))
+	var visit func(t *task) error
+	visit = func(t *task) error {
+		switch state[t] {
+		case visiting:
+			return xerrors.Errorf("task %s depends on itself", t.Id)
+		case visited:
+			return nil
+		}
+		state[t] = visiting
+		for _, n := range t.needs {
+			if err := visit(n); err != nil {
+				return err
+			}
+		}
+		state[t] = visited
+		return nil
+	}
+	for _, t := range tasks {
+		if err := visit(t); err != nil {
+			for _, t := range tasks {
+				t.needs = nil
+			}
+			return err
+		}
+	}
+
+	for _, t := range tasks {
+		for _, n := range t.needs {
+			n.needed = true
+		}
+	}
+	return nil
+}
+
 func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup, successChan chan taskSuccess) {
 	defer wg.Done()
 	defer log.Debug("tasksManager shutdown")
@@ -237,110 +342,316 @@ func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup, successChan
 		if t.State == api.TaskState_closed {
 			continue
 		}
-		taskLog := log.WithField("command", t.command)
-		taskLog.Info("starting a task terminal...")
-		openRequest := &api.OpenTerminalRequest{}
-		if t.config.Env != nil {
-			openRequest.Env = make(map[string]string, len(*t.config.Env))
-			for key, value := range *t.config.Env {
-				// Required check because a string is considered valid JSON (e.g. "hello")
-				// We don't want to marshall basic strings otherwise we get a double quoted environment variable
-				// See: https://github.com/gitpod-io/gitpod/issues/5887
-				if val, ok := value.(string); ok {
-					openRequest.Env[key] = val
+		if len(t.needs) == 0 {
+			tm.startTask(ctx, t, t.command)
+			continue
+		}
+		go func(t *task) {
+			err := t.waitForDependencies(ctx)
+			if err != nil {
+				log.WithError(err).WithField("task", t.Id).Error("cannot start task")
+				t.successChan <- taskFailed(err.Error())
+				t.markReady(err)
+				tm.setTaskState(t, api.TaskState_closed)
+				return
+			}
+			tm.startTask(ctx, t, t.command)
+		}(t)
+	}
+
+	var success taskSuccess
+	for _, task := range tm.tasks {
+		select {
+		case <-ctx.Done():
+			success = taskFailed(ctx.Err().Error())
+		case taskResult := <-task.successChan:
+			if taskResult.Failed() {
+				success = success.Fail(string(taskResult))
+			}
+		}
+	}
+
+	if tm.config.isHeadless() && tm.reporter != nil {
+		tm.reporter.done(success)
+	}
+	successChan <- success
+}
+
+// startTask opens the terminal of a task and runs command in it.
+func (tm *tasksManager) startTask(ctx context.Context, t *task, command string) {
+	taskLog := log.WithField("command", command)
+	taskLog.Info("starting a task terminal...")
+	openRequest := &api.OpenTerminalRequest{}
+	if t.config.Env != nil {
+		openRequest.Env = make(map[string]string, len(*t.config.Env))
+		for key, value := range *t.config.Env {
+			// Required check because a string is considered valid JSON (e.g. "hello")
+			// We don't want to marshall basic strings otherwise we get a double quoted environment variable
+			// See: https://github.com/gitpod-io/gitpod/issues/5887
+			if val, ok := value.(string); ok {
+				openRequest.Env[key] = val
+			} else {
+				v, err := json.Marshal(value)
+				if err != nil {
+					taskLog.WithError(err).WithField("key", key).Error("cannot marshal env var")
 				} else {
-					v, err := json.Marshal(value)
-					if err != nil {
-						taskLog.WithError(err).WithField("key", key).Error("cannot marshal env var")
-					} else {
-						openRequest.Env[key] = string(v)
-					}
+					openRequest.Env[key] = string(v)
 				}
 			}
 		}
-		var readTimeout time.Duration
-		if !tm.config.isHeadless() {
-			readTimeout = 5 * time.Second
+	}
+	var readTimeout time.Duration
+	if !tm.config.isHeadless() {
+		readTimeout = 5 * time.Second
+	}
+	resp, err := tm.terminalService.OpenWithOptions(ctx, openRequest, terminal.TermOptions{
+		ReadTimeout: readTimeout,
+		Title:       t.title,
+	})
+	if err != nil {
+		taskLog.WithError(err).Error("cannot open new task terminal")
+		t.successChan <- taskFailed("cannot open new task terminal")
+		t.markReady(xerrors.Errorf("task terminal was not started"))
+		tm.setTaskState(t, api.TaskState_closed)
+		return
+	}
+
+	taskLog = taskLog.WithField("terminal", resp.Terminal.Alias)
+	term, ok := tm.terminalService.Mux.Get(resp.Terminal.Alias)
+	if !ok {
+		taskLog.Error("cannot find a task terminal")
+		t.successChan <- taskFailed("cannot find a task terminal")
+		t.markReady(xerrors.Errorf("task terminal was not started"))
+		tm.setTaskState(t, api.TaskState_closed)
+		return
+	}
+
+	taskLog = taskLog.WithField("pid", term.Command.Process.Pid)
+	taskLog.Info("task terminal has been started")
+	tm.updateState(func() bool {
+		t.Terminal = resp.Terminal.Alias
+		t.State = api.TaskState_running
+		return true
+	})
+	if t.needed {
+		go tm.watchReadiness(ctx, t, openRequest.Env)
+	}
+
+	go func(t *task, term *terminal.Term, alias string) {
+		state, err := term.Wait()
+		if state != nil {
+			if state.Success() {
+				t.reportResult(taskSuccessful)
+			} else {
+				t.reportResult(taskFailed(state.String()))
+			}
+		} else if err != nil {
+			t.reportResult(taskSuccessful)
+		} else {
+			msg := "cannot wait for task"
+			if err != nil {
+				msg = err.Error()
+			}
+
+			t.reportResult(taskFailed(fmt.Sprintf("%s: %s", msg, t.lastOutput)))
+		}
+		taskLog.Info("task terminal has been closed")
+		if t.readyMarker != "" {
+			// the task might have become ready right before it terminated
+			if _, err := os.Stat(t.readyMarker); err == nil {
+				t.markReady(nil)
+			}
 		}
-		resp, err := tm.terminalService.OpenWithOptions(ctx, openRequest, terminal.TermOptions{
-			ReadTimeout: readTimeout,
-			Title:       t.title,
+		t.markReady(xerrors.Errorf("task terminal was closed"))
+		tm.updateState(func() bool {
+			if t.Terminal != alias {
+				// the task was restarted in another terminal
+				return false
+			}
+			t.State = api.TaskState_closed
+			return true
 		})
-		if err != nil {
-			taskLog.WithError(err).Error("cannot open new task terminal")
-			t.successChan <- taskFailed("cannot open new task terminal")
-			tm.setTaskState(t, api.TaskState_closed)
+	}(t, term, resp.Terminal.Alias)
+
+	tm.watch(t, term)
+
+	if command != "" {
+		term.PTY.Write([]byte(command + "\n"))
+	}
+}
+
+// reportResult reports the result of a task run. Only the result of the first run is awaited,
+// results of restarted tasks are dropped if nobody reads them.
+func (t *task) reportResult(success taskSuccess) {
+	select {
+	case t.successChan <- success:
+	default:
+	}
+}
+
+var (
+	errTaskNotFound          = xerrors.New("task not found")
+	errTaskNotStarted        = xerrors.New("task has not been started yet")
+	errTaskRestartInPrebuild = xerrors.New("tasks cannot be restarted in prebuilds")
+)
+
+// getRestartableTask returns the task with the given ID and its terminal alias.
+func (tm *tasksManager) getRestartableTask(id string) (t *task, alias string, err error) {
+	if tm.config.isHeadless() {
+		return nil, "", errTaskRestartInPrebuild
+	}
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	for _, t := range tm.tasks {
+		if t.Id != id {
 			continue
 		}
+		if t.State == api.TaskState_opening {
+			return nil, "", errTaskNotStarted
+		}
+		return t, t.Terminal, nil
+	}
+	return nil, "", errTaskNotFound
+}
 
-		taskLog = taskLog.WithField("terminal", resp.Terminal.Alias)
-		term, ok := tm.terminalService.Mux.Get(resp.Terminal.Alias)
-		if !ok {
-			taskLog.Error("cannot find a task terminal")
-			t.successChan <- taskFailed("cannot find a task terminal")
-			tm.setTaskState(t, api.TaskState_closed)
-			continue
+// RestartTask closes the terminal of a task and runs the task again in a new terminal.
+func (tm *tasksManager) RestartTask(ctx context.Context, id string) error {
+	t, alias, err := tm.getRestartableTask(id)
+	if err != nil {
+		return err
+	}
+
+	if alias != "" {
+		err = tm.terminalService.Mux.CloseTerminal(alias, closeTaskTerminalGracePeriod)
+		if err != nil && err != terminal.ErrNotFound {
+			return xerrors.Errorf("cannot close task terminal: %w", err)
 		}
+	}
+	log.WithField("task", id).Info("restarting task")
+	tm.startTask(ctx, t, getRestartCommand(t, tm.contentSource, tm.storeLocation))
+	return nil
+}
 
-		taskLog = taskLog.WithField("pid", term.Command.Process.Pid)
-		taskLog.Info("task terminal has been started")
-		tm.updateState(func() bool {
-			t.Terminal = resp.Terminal.Alias
-			t.State = api.TaskState_running
-			return true
-		})
+// closeTaskTerminalGracePeriod is the time a task terminal has to shut down on restart
+const closeTaskTerminalGracePeriod = 5 * time.Second
 
-		go func(t *task, term *terminal.Term) {
-			state, err := term.Wait()
-			if state != nil {
-				if state.Success() {
-					t.successChan <- taskSuccessful
-				} else {
-					t.successChan <- taskFailed(state.String())
-				}
-			} else if err != nil {
-				t.successChan <- taskSuccessful
-			} else {
-				msg := "cannot wait for task"
-				if err != nil {
-					msg = err.Error()
-				}
+// RerunTask runs the task again in its terminal, so that the terminal's history and environment are preserved.
+func (tm *tasksManager) RerunTask(ctx context.Context, id string) error {
+	t, alias, err := tm.getRestartableTask(id)
+	if err != nil {
+		return err
+	}
+	term, ok := tm.terminalService.Mux.Get(alias)
+	if !ok {
+		return tm.RestartTask(ctx, id)
+	}
 
-				t.successChan <- taskFailed(fmt.Sprintf("%s: %s", msg, t.lastOutput))
-			}
-			taskLog.Info("task terminal has been closed")
-			tm.setTaskState(t, api.TaskState_closed)
-		}(t, term)
+	log.WithField("task", id).WithField("terminal", alias).Info("re-running task")
+	// interrupt whatever currently runs in the foreground
+	_, err = term.PTY.Write([]byte{0x03})
+	if err != nil {
+		return xerrors.Errorf("cannot interrupt task: %w", err)
+	}
+	_, err = term.PTY.Write([]byte(getRerunCommand(t) + "\n"))
+	if err != nil {
+		return xerrors.Errorf("cannot re-run task: %w", err)
+	}
+	return nil
+}
 
-		tm.watch(t, term)
+// getRerunCommands returns the commands of a task as if the workspace was freshly initialized.
+func getRerunCommands(task *task) []*string {
+	return []*string{task.config.Before, task.config.Init, task.config.Command}
+}
 
-		if t.command != "" {
-			term.PTY.Write([]byte(t.command + "\n"))
+func getRerunCommand(task *task) string {
+	return composeCommand(composeCommandOptions{
+		commands: getRerunCommands(task),
+		format:   "{\n%s\n}",
+		sep:      " && ",
+	})
+}
+
+// getRestartCommand returns the command which re-runs a task in a new terminal.
+func getRestartCommand(task *task, contentSource csapi.WorkspaceInitSource, storeLocation string) string {
+	command := getRerunCommand(task)
+	histfileCommand := getHistfileCommand(task, getRerunCommands(task), contentSource, storeLocation)
+	if strings.TrimSpace(command) == "" {
+		return histfileCommand
+	}
+	if histfileCommand == "" {
+		return command
+	}
+	return histfileCommand + "; " + command
+}
+
+// waitForDependencies blocks until all tasks needed by t are ready.
+func (t *task) waitForDependencies(ctx context.Context) error {
+	for _, n := range t.needs {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-n.ready:
+		}
+		if n.readyErr != nil {
+			return xerrors.Errorf("needed task %s did not become ready: %w", n.Id, n.readyErr)
 		}
 	}
+	return nil
+}
 
-	var success taskSuccess
-	for _, task := range tm.tasks {
+// taskReadyCheckInterval is the interval in which we check if a needed task became ready
+const taskReadyCheckInterval = 1 * time.Second
+
+// watchReadiness marks t ready once its ready command succeeds or its ready marker exists.
+func (tm *tasksManager) watchReadiness(ctx context.Context, t *task, env map[string]string) {
+	ticker := time.NewTicker(taskReadyCheckInterval)
+	defer ticker.Stop()
+	for {
+		if t.isReady(ctx, tm.terminalService.DefaultWorkdir, env) {
+			log.WithField("task", t.Id).Info("task is ready")
+			t.markReady(nil)
+			return
+		}
 		select {
 		case <-ctx.Done():
-			success = taskFailed(ctx.Err().Error())
-		case taskResult := <-task.successChan:
-			if taskResult.Failed() {
-				success = success.Fail(string(taskResult))
-			}
+			t.markReady(ctx.Err())
+			return
+		case <-t.ready:
+			return
+		case <-ticker.C:
 		}
 	}
+}
 
-	if tm.config.isHeadless() && tm.reporter != nil {
-		tm.reporter.done(success)
+func (t *task) isReady(ctx context.Context, workdir string, env map[string]string) bool {
+	if t.config.Ready == nil {
+		_, err := os.Stat(t.readyMarker)
+		return err == nil
 	}
-	successChan <- success
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", *t.config.Ready)
+	cmd.Dir = workdir
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	return cmd.Run() == nil
 }
 
 func getCommand(task *task, isHeadless bool, contentSource csapi.WorkspaceInitSource, storeLocation string) string {
 	commands := getCommands(task, isHeadless, contentSource, storeLocation)
+	composed := commands
+	if task.readyMarker != "" {
+		// the task is ready once all commands but the last, i.e. before and init, are done
+		touchMarker := "touch " + task.readyMarker
+		composed = make([]*string, 0, len(commands)+1)
+		composed = append(composed, commands[:len(commands)-1]...)
+		composed = append(composed, &touchMarker, commands[len(commands)-1])
+	}
 	command := composeCommand(composeCommandOptions{
-		commands: commands,
+		commands: composed,
 		format:   "{\n%s\n}",
 		sep:      " && ",
 	})
@@ -354,6 +665,7 @@ func getCommand(task *task, isHeadless bool, contentSource csapi.WorkspaceInitSo
 		return command + "; exit"
 	}
 
+	// the histfile should only contain the commands configured by the user
 	histfileCommand := getHistfileCommand(task, commands, contentSource, storeLocation)
 	if strings.TrimSpace(command) == "" {
 		return histfileCommand
@@ -424,6 +736,7 @@ func (tm *tasksManager) watch(task *task, terminal *terminal.Term) {
 		start       = time.Now()
 	)
 	go func() {
+		defer close(task.logDone)
 		defer stdout.Close()
 
 		var (
@@ -447,15 +760,17 @@ func (tm *tasksManager) watch(task *task, terminal *terminal.Term) {
 			fileWriter = bufio.NewWriter(file)
 			defer fileWriter.Flush()
 		}
+		// the log can be tailed while the task runs, hence we must not hold back its output
+		flushingFileWriter := &flushingWriter{fileWriter}
 		// Import any parent prebuild logs and parse their total duration if available
 		parentElapsed := importParentLogAndGetDuration(oldFileName, fileWriter)
 
 		var writer io.Writer
 
 		if os.Getenv("SUPERVISOR_DEBUG_ENABLE") == "true" {
-			writer = io.MultiWriter(fileWriter, os.Stdout)
+			writer = io.MultiWriter(flushingFileWriter, os.Stdout)
 		} else {
-			writer = fileWriter
+			writer = flushingFileWriter
 		}
 
 		_, err = io.Copy(writer, stdout)
@@ -487,6 +802,19 @@ func (tm *tasksManager) watch(task *task, terminal *terminal.Term) {
 	}()
 }
 
+// flushingWriter flushes a buffered writer after every write
+type flushingWriter struct {
+	w *bufio.Writer
+}
+
+func (f *flushingWriter) Write(p []byte) (n int, err error) {
+	n, err = f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.w.Flush()
+}
+
 func importParentLogAndGetDuration(fn string, out io.Writer) time.Duration {
 	if _, err := os.Stat(fn); err != nil {
 		return 0
@@ -267,6 +267,7 @@ func TestGetTask(t *testing.T) {
 		Task          TaskConfig
 		IsHeadless    bool
 		ContentSource csapi.WorkspaceInitSource
+		ReadyMarker   string
 		Expectation   string
 	}{
 		{
@@ -276,6 +277,14 @@ func TestGetTask(t *testing.T) {
 			ContentSource: csapi.WorkspaceInitFromOther,
 			Expectation:   "{\nbefore\n} && {\ninit\n} && {\nprebuild\n}; exit",
 		},
+		{
+			Name:          "prebuild with ready marker",
+			Task:          allTasks,
+			IsHeadless:    true,
+			ContentSource: csapi.WorkspaceInitFromOther,
+			ReadyMarker:   "/ready-0",
+			Expectation:   "{\nbefore\n} && {\ninit\n} && {\ntouch /ready-0\n} && {\nprebuild\n}; exit",
+		},
 		{
 			Name:          "from prebuild",
 			Task:          allTasks,
@@ -298,7 +307,7 @@ func TestGetTask(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			command := getCommand(&task{config: test.Task, TaskStatus: api.TaskStatus{Id: "0"}}, test.IsHeadless, test.ContentSource, "/")
+			command := getCommand(&task{config: test.Task, TaskStatus: api.TaskStatus{Id: "0"}, readyMarker: test.ReadyMarker}, test.IsHeadless, test.ContentSource, "/")
 			if diff := cmp.Diff(test.Expectation, command); diff != "" {
 				t.Errorf("unexpected getCommand() (-want +got):\n%s", diff)
 			}
@@ -306,6 +315,115 @@ func TestGetTask(t *testing.T) {
 	}
 }
 
+func TestResolveTaskDependencies(t *testing.T) {
+	type Expectation struct {
+		Needs  map[string][]string
+		Needed []string
+		Error  bool
+	}
+	newTasks := func(needs ...[]string) []*task {
+		var res []*task
+		for i, n := range needs {
+			name := "task" + strconv.Itoa(i)
+			n := n
+			res = append(res, &task{TaskStatus: api.TaskStatus{Id: strconv.Itoa(i)}, config: TaskConfig{Name: &name, Needs: &n}})
+		}
+		return res
+	}
+	tests := []struct {
+		Name        string
+		Tasks       []*task
+		Expectation Expectation
+	}{
+		{
+			Name:        "no dependencies",
+			Tasks:       newTasks(nil, nil),
+			Expectation: Expectation{Needs: map[string][]string{}},
+		},
+		{
+			Name:  "chain",
+			Tasks: newTasks(nil, []string{"task0"}, []string{"task1", "task0"}),
+			Expectation: Expectation{
+				Needs:  map[string][]string{"1": {"0"}, "2": {"1", "0"}},
+				Needed: []string{"0", "1"},
+			},
+		},
+		{
+			Name:        "unknown task",
+			Tasks:       newTasks([]string{"foobar"}),
+			Expectation: Expectation{Needs: map[string][]string{}},
+		},
+		{
+			Name:        "cycle",
+			Tasks:       newTasks([]string{"task2"}, []string{"task0"}, []string{"task1"}),
+			Expectation: Expectation{Needs: map[string][]string{}, Error: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := resolveTaskDependencies(test.Tasks)
+
+			act := Expectation{Needs: make(map[string][]string), Error: err != nil}
+			for _, task := range test.Tasks {
+				for _, n := range task.needs {
+					act.Needs[task.Id] = append(act.Needs[task.Id], n.Id)
+				}
+				if task.needed {
+					act.Needed = append(act.Needed, task.Id)
+				}
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetRestartableTask(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Headless    bool
+		ID          string
+		Expectation error
+	}{
+		{Name: "running task", ID: "0"},
+		{Name: "closed task", ID: "1"},
+		{Name: "task not started", ID: "2", Expectation: errTaskNotStarted},
+		{Name: "unknown task", ID: "3", Expectation: errTaskNotFound},
+		{Name: "prebuild", Headless: true, ID: "0", Expectation: errTaskRestartInPrebuild},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tm := newTasksManager(&Config{
+				WorkspaceConfig: WorkspaceConfig{GitpodHeadless: strconv.FormatBool(test.Headless)},
+			}, nil, nil, nil)
+			tm.tasks = []*task{
+				{TaskStatus: api.TaskStatus{Id: "0", State: api.TaskState_running, Terminal: "term-0"}},
+				{TaskStatus: api.TaskStatus{Id: "1", State: api.TaskState_closed}},
+				{TaskStatus: api.TaskStatus{Id: "2", State: api.TaskState_opening}},
+			}
+
+			_, _, err := tm.getRestartableTask(test.ID)
+			if err != test.Expectation {
+				t.Errorf("unexpected error: want %v, got %v", test.Expectation, err)
+			}
+		})
+	}
+}
+
+func TestGetRerunCommand(t *testing.T) {
+	p := func(v string) *string { return &v }
+	act := getRerunCommand(&task{config: TaskConfig{
+		Before:   p("before"),
+		Init:     p("init"),
+		Prebuild: p("prebuild"),
+		Command:  p("command"),
+	}})
+	if diff := cmp.Diff("{\nbefore\n} && {\ninit\n} && {\ncommand\n}", act); diff != "" {
+		t.Errorf("unexpected getRerunCommand() (-want +got):\n%s", diff)
+	}
+}
+
 func TestTaskSuccess(t *testing.T) {
 	type Expectation struct {
 		Failed bool
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// tokenRefreshInterval is the time between two checks for expiring tokens
+	tokenRefreshInterval = 1 * time.Minute
+	// tokenRefreshLead is how long before their expiry tokens are renewed. It leaves room for
+	// long-running operations like a push and a few failed renewal attempts.
+	tokenRefreshLead = 10 * time.Minute
+	// tokenRenewalTimeout limits a single renewal
+	tokenRenewalTimeout = 30 * time.Second
+)
+
+// tokenRefresher renews cached tokens before they expire, so that operations that
+// started with a token don't fail halfway because it expired.
+type tokenRefresher struct {
+	Tokens        *InMemoryTokenService
+	Kind          string
+	Notifications *NotificationService
+	// Interval is the time between two checks for expiring tokens
+	Interval time.Duration
+	// Lead is how long before their expiry tokens are renewed
+	Lead time.Duration
+
+	// failed contains the hosts whose renewal failed and which the user was notified about already
+	failed map[string]struct{}
+}
+
+func newGitTokenRefresher(tokens *InMemoryTokenService, notifications *NotificationService) *tokenRefresher {
+	return &tokenRefresher{
+		Tokens:        tokens,
+		Kind:          KindGit,
+		Notifications: notifications,
+		Interval:      tokenRefreshInterval,
+		Lead:          tokenRefreshLead,
+	}
+}
+
+// Run renews expiring tokens until the context is canceled.
+func (r *tokenRefresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+func (r *tokenRefresher) refresh(ctx context.Context) {
+	if r.failed == nil {
+		r.failed = make(map[string]struct{})
+	}
+	for _, tkn := range r.Tokens.expiringTokens(r.Kind, time.Now().Add(r.Lead)) {
+		renewCtx, cancel := context.WithTimeout(ctx, tokenRenewalTimeout)
+		_, err := r.Tokens.renewToken(renewCtx, r.Kind, tkn)
+		cancel()
+		if err == nil {
+			delete(r.failed, tkn.Host)
+			continue
+		}
+
+		log.WithError(err).WithField("kind", r.Kind).WithField("host", tkn.Host).Warn("cannot renew token")
+		if _, notified := r.failed[tkn.Host]; notified {
+			continue
+		}
+		r.failed[tkn.Host] = struct{}{}
+		_, err = r.Notifications.Notify(ctx, &api.NotifyRequest{
+			Level:   api.NotifyRequest_WARNING,
+			Message: fmt.Sprintf("Renewing your Git credentials for %s failed. Git operations on %s might fail after %s.", tkn.Host, tkn.Host, tkn.ExpiryDate.Format(time.Kitchen)),
+		})
+		if err != nil {
+			log.WithError(err).Warn("cannot notify about failed token renewal")
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestTokenRefresher(t *testing.T) {
+	var (
+		now        = time.Now()
+		expiry     = func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+		soon       = expiry(5 * time.Minute)
+		later      = expiry(time.Hour)
+		freshToken = func(ctx context.Context, req *api.GetTokenRequest) (*Token, error) {
+			return &Token{Host: req.Host, Token: "fresh", ExpiryDate: later, Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE}, nil
+		}
+	)
+
+	type Expectation struct {
+		Tokens        []string
+		Notifications int
+	}
+	tests := []struct {
+		Desc        string
+		Cached      []*Token
+		Provider    tokenProviderFunc
+		Refreshes   int
+		Expectation Expectation
+	}{
+		{
+			Desc:        "renews expiring token",
+			Cached:      []*Token{{Host: "github.com", Token: "stale", ExpiryDate: soon, Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE}},
+			Provider:    freshToken,
+			Refreshes:   1,
+			Expectation: Expectation{Tokens: []string{"fresh"}},
+		},
+		{
+			Desc: "keeps other tokens",
+			Cached: []*Token{
+				{Host: "github.com", Token: "valid", ExpiryDate: later, Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE},
+				{Host: "gitlab.com", Token: "forever", Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE},
+			},
+			Provider:    freshToken,
+			Refreshes:   1,
+			Expectation: Expectation{Tokens: []string{"forever", "valid"}},
+		},
+		{
+			Desc:   "renewal fails",
+			Cached: []*Token{{Host: "github.com", Token: "stale", ExpiryDate: soon, Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE}},
+			Provider: func(ctx context.Context, req *api.GetTokenRequest) (*Token, error) {
+				return nil, nil
+			},
+			Refreshes:   3,
+			Expectation: Expectation{Tokens: []string{"stale"}, Notifications: 1},
+		},
+		{
+			Desc:   "no later expiry",
+			Cached: []*Token{{Host: "github.com", Token: "stale", ExpiryDate: soon, Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE}},
+			Provider: func(ctx context.Context, req *api.GetTokenRequest) (*Token, error) {
+				return &Token{Host: req.Host, Token: "stale", ExpiryDate: soon, Reuse: api.TokenReuse_REUSE_WHEN_POSSIBLE}, nil
+			},
+			Refreshes:   1,
+			Expectation: Expectation{Tokens: []string{"stale"}, Notifications: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			tokens := NewInMemoryTokenService()
+			tokens.token[KindGit] = test.Cached
+			tokens.provider[KindGit] = []tokenProvider{test.Provider}
+			notifications := NewNotificationService()
+
+			refresher := newGitTokenRefresher(tokens, notifications)
+			for i := 0; i < test.Refreshes; i++ {
+				refresher.refresh(context.Background())
+			}
+
+			var act Expectation
+			for _, tkn := range tokens.token[KindGit] {
+				act.Tokens = append(act.Tokens, tkn.Token)
+			}
+			sort.Strings(act.Tokens)
+			act.Notifications = len(notifications.pendingNotifications)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
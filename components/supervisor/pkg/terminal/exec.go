@@ -0,0 +1,293 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// cpuPeriod is the cgroup CPU period in microseconds we use for CPU limits
+const cpuPeriod = 100000
+
+// execWaitDelay is the time we keep forwarding output after an exec'ed command exited
+var execWaitDelay = 5 * time.Second
+
+var execCgroupCounter uint64
+
+// Exec runs a command to completion and streams its output.
+func (srv *MuxTerminalService) Exec(req *api.ExecRequest, resp api.TerminalService_ExecServer) error {
+	if len(req.Command) == 0 {
+		return status.Error(codes.InvalidArgument, "command is required")
+	}
+
+	cmd := exec.Command(req.Command[0], req.Command[1:]...)
+	cmd.Dir = srv.workdir(req.Workdir)
+	cmd.Env = append([]string(nil), srv.env()...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: srv.DefaultCreds,
+		// with a PTY the command runs in its own session, which also gives it its own process group
+		Setpgid: !req.Pty,
+	}
+	if req.User != "" {
+		u, err := user.Lookup(req.User)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "unknown user %s", req.User)
+		}
+		cmd.SysProcAttr.Credential, err = credential(u)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		cmd.Env = append(cmd.Env, "HOME="+u.HomeDir, "USER="+u.Username)
+	}
+	for key, value := range req.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%v=%v", key, value))
+	}
+
+	if req.Limits != nil && (req.Limits.Cpu > 0 || req.Limits.Memory > 0) {
+		cgroup, err := srv.createExecCgroup(req.Limits)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "cannot apply resource limits: %v", err)
+		}
+		defer func() {
+			// the removal fails if processes the command started in the background are still running
+			err := os.Remove(cgroup)
+			if err != nil {
+				log.WithError(err).WithField("cgroup", cgroup).Debug("cannot remove exec cgroup")
+			}
+		}()
+
+		// the command starts right in its cgroup, so that it never runs without limits
+		cgroupDir, err := os.Open(cgroup)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "cannot apply resource limits: %v", err)
+		}
+		defer cgroupDir.Close()
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = int(cgroupDir.Fd())
+	}
+
+	ctx, cancel := resp.Context(), func() {}
+	if req.TimeoutSeconds > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+	}
+	defer cancel()
+
+	var (
+		output = make(chan *api.ExecResponse)
+		stdout = func(b []byte) *api.ExecResponse {
+			return &api.ExecResponse{Output: &api.ExecResponse_Stdout{Stdout: b}}
+		}
+		stderr = func(b []byte) *api.ExecResponse {
+			return &api.ExecResponse{Output: &api.ExecResponse_Stderr{Stderr: b}}
+		}
+		ptmx       *os.File
+		ptmxReader = make(chan struct{})
+	)
+	if req.Pty {
+		var size *pty.Winsize
+		if req.Size != nil {
+			size = &pty.Winsize{
+				Cols: uint16(req.Size.Cols),
+				Rows: uint16(req.Size.Rows),
+				X:    uint16(req.Size.WidthPx),
+				Y:    uint16(req.Size.HeightPx),
+			}
+		}
+		var err error
+		ptmx, err = pty.StartWithSize(cmd, size)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "cannot start command: %v", err)
+		}
+		defer ptmx.Close()
+		if len(req.Stdin) > 0 {
+			_, err = ptmx.Write(req.Stdin)
+			if err != nil {
+				log.WithError(err).Debug("cannot write stdin of exec'ed command")
+			}
+		}
+		go func() {
+			defer close(ptmxReader)
+			_, _ = io.Copy(&execOutputWriter{output: output, wrap: stdout}, ptmx)
+		}()
+	} else {
+		cmd.Stdin = bytes.NewReader(req.Stdin)
+		cmd.Stdout = &execOutputWriter{output: output, wrap: stdout}
+		cmd.Stderr = &execOutputWriter{output: output, wrap: stderr}
+		// processes the command started in the background may keep its output open - we don't wait for them
+		cmd.WaitDelay = execWaitDelay
+		err := cmd.Start()
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "cannot start command: %v", err)
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		exited   bool
+		timedOut bool
+	)
+	kill := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if exited {
+			return
+		}
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				mu.Lock()
+				timedOut = true
+				mu.Unlock()
+			}
+			kill()
+		}
+	}()
+
+	var waitErr error
+	go func() {
+		defer close(output)
+
+		err := cmd.Wait()
+		mu.Lock()
+		exited = true
+		mu.Unlock()
+		if req.Pty {
+			select {
+			case <-ptmxReader:
+			case <-time.After(execWaitDelay):
+				// processes the command started in the background keep the terminal open - we stop reading
+				ptmx.Close()
+				<-ptmxReader
+			}
+		}
+		waitErr = err
+	}()
+	var sendErr error
+	for msg := range output {
+		if sendErr != nil {
+			// the client is gone - we drain the output until the command was killed
+			continue
+		}
+		sendErr = resp.Send(msg)
+		if sendErr != nil {
+			cancel()
+			kill()
+		}
+	}
+
+	mu.Lock()
+	exit := &api.ExecExit{Code: int32(cmd.ProcessState.ExitCode()), TimedOut: timedOut}
+	mu.Unlock()
+	if sendErr != nil {
+		return sendErr
+	}
+	if _, ok := waitErr.(*exec.ExitError); waitErr != nil && !ok && !errors.Is(waitErr, exec.ErrWaitDelay) {
+		return status.Error(codes.Internal, waitErr.Error())
+	}
+	return resp.Send(&api.ExecResponse{Output: &api.ExecResponse_Exit{Exit: exit}})
+}
+
+// execOutputWriter forwards the output of an exec'ed command to the client.
+type execOutputWriter struct {
+	output chan<- *api.ExecResponse
+	wrap   func([]byte) *api.ExecResponse
+}
+
+func (w *execOutputWriter) Write(b []byte) (int, error) {
+	w.output <- w.wrap(append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func credential(u *user.User) (*syscall.Credential, error) {
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid uid of %s: %w", u.Username, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid gid of %s: %w", u.Username, err)
+	}
+	return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+// createExecCgroup creates a cgroup below supervisor's cgroup which enforces the limits.
+func (srv *MuxTerminalService) createExecCgroup(limits *api.ExecResourceLimits) (string, error) {
+	if srv.CgroupBasePath == "" {
+		return "", xerrors.Errorf("resource limits are not supported")
+	}
+	if _, err := os.Stat(filepath.Join(srv.CgroupBasePath, "cgroup.controllers")); err != nil {
+		return "", xerrors.Errorf("resource limits require cgroup v2")
+	}
+	own, err := ownCgroup("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	parent := filepath.Join(srv.CgroupBasePath, own)
+
+	err = os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("+cpu +memory"), 0644)
+	if err != nil {
+		return "", xerrors.Errorf("cannot enable cgroup controllers: %w", err)
+	}
+
+	cgroup := filepath.Join(parent, fmt.Sprintf("supervisor-exec-%d", atomic.AddUint64(&execCgroupCounter, 1)))
+	err = os.Mkdir(cgroup, 0755)
+	if err != nil {
+		return "", err
+	}
+	if limits.Cpu > 0 {
+		err = os.WriteFile(filepath.Join(cgroup, "cpu.max"), []byte(fmt.Sprintf("%d %d", limits.Cpu*cpuPeriod/1000, cpuPeriod)), 0644)
+	}
+	if err == nil && limits.Memory > 0 {
+		err = os.WriteFile(filepath.Join(cgroup, "memory.max"), []byte(strconv.FormatInt(limits.Memory, 10)), 0644)
+	}
+	if err != nil {
+		_ = os.Remove(cgroup)
+		return "", xerrors.Errorf("cannot set limits: %w", err)
+	}
+	return cgroup, nil
+}
+
+// ownCgroup returns the cgroup v2 path of the current process from its /proc/<pid>/cgroup file.
+func ownCgroup(fn string) (string, error) {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(fc), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			return strings.TrimPrefix(line, "0::"), nil
+		}
+	}
+	return "", xerrors.Errorf("no cgroup v2 entry in %s", fn)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestExec(t *testing.T) {
+	defer func(delay time.Duration) { execWaitDelay = delay }(execWaitDelay)
+	execWaitDelay = 100 * time.Millisecond
+
+	type Expectation struct {
+		Stdout   string
+		Stderr   string
+		Code     int32
+		TimedOut bool
+		Error    string
+	}
+	tests := []struct {
+		Desc        string
+		Req         *api.ExecRequest
+		Expectation Expectation
+	}{
+		{
+			Desc:        "stdout, stderr and exit code",
+			Req:         &api.ExecRequest{Command: []string{"sh", "-c", "echo out; echo err >&2; exit 3"}},
+			Expectation: Expectation{Stdout: "out\n", Stderr: "err\n", Code: 3},
+		},
+		{
+			Desc:        "stdin",
+			Req:         &api.ExecRequest{Command: []string{"cat"}, Stdin: []byte("hello")},
+			Expectation: Expectation{Stdout: "hello"},
+		},
+		{
+			Desc:        "env and workdir",
+			Req:         &api.ExecRequest{Command: []string{"sh", "-c", "echo $FOO $PWD"}, Env: map[string]string{"FOO": "bar"}, Workdir: "/"},
+			Expectation: Expectation{Stdout: "bar /\n"},
+		},
+		{
+			Desc:        "pty",
+			Req:         &api.ExecRequest{Command: []string{"sh", "-c", "test -t 1 && echo tty; echo err >&2"}, Pty: true},
+			Expectation: Expectation{Stdout: "tty\r\nerr\r\n"},
+		},
+		{
+			Desc:        "timeout",
+			Req:         &api.ExecRequest{Command: []string{"sh", "-c", "echo started; sleep 10"}, TimeoutSeconds: 1},
+			Expectation: Expectation{Stdout: "started\n", Code: -1, TimedOut: true},
+		},
+		{
+			Desc:        "background process keeps output open",
+			Req:         &api.ExecRequest{Command: []string{"sh", "-c", "echo started; sleep 10 &"}, TimeoutSeconds: 5},
+			Expectation: Expectation{Stdout: "started\n"},
+		},
+		{
+			Desc:        "background process keeps pty open",
+			Req:         &api.ExecRequest{Command: []string{"sh", "-c", "echo started; sleep 10 &"}, Pty: true, TimeoutSeconds: 5},
+			Expectation: Expectation{Stdout: "started\r\n"},
+		},
+		{
+			Desc:        "no command",
+			Req:         &api.ExecRequest{},
+			Expectation: Expectation{Error: "command is required"},
+		},
+		{
+			Desc:        "unknown user",
+			Req:         &api.ExecRequest{Command: []string{"true"}, User: "does-not-exist"},
+			Expectation: Expectation{Error: "unknown user does-not-exist"},
+		},
+		{
+			Desc:        "limits without cgroup",
+			Req:         &api.ExecRequest{Command: []string{"true"}, Limits: &api.ExecResourceLimits{Memory: 1024 * 1024}},
+			Expectation: Expectation{Error: "cannot apply resource limits: resource limits are not supported"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			srv := NewMuxTerminalService(NewMux())
+			srv.DefaultWorkdir = "/"
+			srv.Env = []string{"PATH=" + os.Getenv("PATH")}
+
+			var act Expectation
+			err := srv.Exec(test.Req, &testExecServer{onSend: func(resp *api.ExecResponse) {
+				switch o := resp.Output.(type) {
+				case *api.ExecResponse_Stdout:
+					act.Stdout += string(o.Stdout)
+				case *api.ExecResponse_Stderr:
+					act.Stderr += string(o.Stderr)
+				case *api.ExecResponse_Exit:
+					act.Code, act.TimedOut = o.Exit.Code, o.Exit.TimedOut
+				}
+			}})
+			if err != nil {
+				act.Error = status.Convert(err).Message()
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type testExecServer struct {
+	onSend func(*api.ExecResponse)
+	grpc.ServerStream
+}
+
+func (s *testExecServer) Send(resp *api.ExecResponse) error {
+	s.onSend(resp)
+	return nil
+}
+
+func (s *testExecServer) Context() context.Context {
+	return context.Background()
+}
+
+func TestOwnCgroup(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Content     string
+		Expectation string
+	}{
+		{Desc: "cgroup v2", Content: "0::/workspace\n", Expectation: "/workspace"},
+		{Desc: "hybrid", Content: "12:memory:/workspace\n0::/workspace/unified\n", Expectation: "/workspace/unified"},
+		{Desc: "cgroup v1", Content: "12:memory:/workspace\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			fn := filepath.Join(t.TempDir(), "cgroup")
+			err := os.WriteFile(fn, []byte(test.Content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act, err := ownCgroup(fn)
+			if err != nil && !strings.Contains(err.Error(), "no cgroup v2 entry") {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
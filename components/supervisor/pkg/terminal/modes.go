@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// terminalModes tracks DEC private modes (ESC [ ? Pm h/l) which influence how the terminal output is rendered.
+// Once the backlog no longer contains the sequences which enabled them, e.g. switching to the alternate screen,
+// the modes have to be restored before the backlog is replayed to a new listener.
+type terminalModes struct {
+	enabled map[string]bool
+	// carry holds an incomplete escape sequence at the end of the last write
+	carry []byte
+}
+
+// trackedModes are the modes we restore, in the order we restore them.
+var trackedModes = []struct {
+	Mode    string
+	Default bool
+}{
+	// alternate screen buffer
+	{Mode: "1049"},
+	{Mode: "1047"},
+	{Mode: "47"},
+	// application cursor keys
+	{Mode: "1"},
+	// bracketed paste
+	{Mode: "2004"},
+	// cursor visibility
+	{Mode: "25", Default: true},
+}
+
+var decPrivateModeRegexp = regexp.MustCompile(`\x1b\[\?([0-9;]+)([hl])`)
+
+// maxCarry is the maximum length of an incomplete escape sequence we carry over to the next write
+const maxCarry = 32
+
+func newTerminalModes() *terminalModes {
+	return &terminalModes{enabled: make(map[string]bool)}
+}
+
+// Write tracks the modes set or reset in p.
+func (m *terminalModes) Write(p []byte) {
+	data := p
+	if len(m.carry) > 0 {
+		data = append(m.carry, p...)
+		m.carry = nil
+	}
+
+	for _, match := range decPrivateModeRegexp.FindAllSubmatch(data, -1) {
+		on := string(match[2]) == "h"
+		for _, mode := range strings.Split(string(match[1]), ";") {
+			m.enabled[mode] = on
+		}
+	}
+
+	// an escape sequence might be split across writes
+	if idx := bytes.LastIndexByte(data, 0x1b); idx >= 0 && len(data)-idx < maxCarry {
+		tail := data[idx:]
+		if !decPrivateModeRegexp.Match(tail) && !bytes.ContainsAny(tail[1:], "hl\n") {
+			m.carry = append([]byte(nil), tail...)
+		}
+	}
+}
+
+// Restore produces the escape sequences which restore all modes which differ from their defaults.
+func (m *terminalModes) Restore() []byte {
+	var res []byte
+	for _, t := range trackedModes {
+		on, exists := m.enabled[t.Mode]
+		if !exists || on == t.Default {
+			continue
+		}
+		suffix := "l"
+		if on {
+			suffix = "h"
+		}
+		res = append(res, []byte("\x1b[?"+t.Mode+suffix)...)
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTerminalModes(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Writes      []string
+		Expectation string
+	}{
+		{
+			Desc:   "no modes",
+			Writes: []string{"hello world\r\n"},
+		},
+		{
+			Desc:        "alternate screen",
+			Writes:      []string{"\x1b[?1049h\x1b[H\x1b[2J"},
+			Expectation: "\x1b[?1049h",
+		},
+		{
+			Desc:   "mode reset to default",
+			Writes: []string{"\x1b[?1049h", "vim", "\x1b[?1049l"},
+		},
+		{
+			Desc:        "hidden cursor and multiple modes",
+			Writes:      []string{"\x1b[?25l\x1b[?1;2004h"},
+			Expectation: "\x1b[?1h\x1b[?2004h\x1b[?25l",
+		},
+		{
+			Desc:        "sequence split across writes",
+			Writes:      []string{"foo\x1b[?10", "49h bar"},
+			Expectation: "\x1b[?1049h",
+		},
+		{
+			Desc:   "untracked modes",
+			Writes: []string{"\x1b[?1000h"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			modes := newTerminalModes()
+			for _, w := range test.Writes {
+				modes.Write([]byte(w))
+			}
+			if diff := cmp.Diff(test.Expectation, string(modes.Restore())); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/creack/pty"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// DefaultRecordingMaxFileSize is the size after which a recording is continued in a new file.
+	DefaultRecordingMaxFileSize = 10 << 20
+	// DefaultRecordingMaxTotalSize is the size of all recordings after which the oldest ones are removed.
+	DefaultRecordingMaxTotalSize = 500 << 20
+
+	// recordingExt is the file extension of asciicast recordings
+	recordingExt = ".cast"
+)
+
+// Recordings writes recordings of terminal sessions in the asciicast v2 format
+// (https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md) to a directory.
+type Recordings struct {
+	// Dir is the directory the recordings are written to.
+	Dir string
+	// MaxFileSize is the size after which a recording is continued in a new file. Use 0 for the default.
+	MaxFileSize int64
+	// MaxTotalSize is the size of all recordings in Dir after which the oldest ones are removed. Use 0 for the default.
+	MaxTotalSize int64
+
+	mu sync.Mutex
+}
+
+// Open starts recording a terminal session. The recording is named after the
+// time it started and the given name.
+func (r *Recordings) Open(name string, size *pty.Winsize, title string) (*Recording, error) {
+	err := os.MkdirAll(r.Dir, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create recordings directory: %w", err)
+	}
+
+	start := time.Now()
+	rec := &Recording{
+		recordings: r,
+		name:       start.UTC().Format("20060102T150405") + "-" + name,
+		title:      title,
+		width:      80,
+		height:     24,
+	}
+	if size != nil && size.Cols > 0 && size.Rows > 0 {
+		rec.width, rec.height = size.Cols, size.Rows
+	}
+	err = rec.rotate()
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (r *Recordings) maxFileSize() int64 {
+	if r.MaxFileSize == 0 {
+		return DefaultRecordingMaxFileSize
+	}
+	return r.MaxFileSize
+}
+
+func (r *Recordings) maxTotalSize() int64 {
+	if r.MaxTotalSize == 0 {
+		return DefaultRecordingMaxTotalSize
+	}
+	return r.MaxTotalSize
+}
+
+// prune removes the oldest recordings until all recordings fit into MaxTotalSize.
+// The recording at active is never removed.
+func (r *Recordings) prune(active string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil {
+		log.WithError(err).WithField("dir", r.Dir).Warn("cannot list terminal recordings")
+		return
+	}
+	type recording struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var (
+		recordings []recording
+		total      int64
+	)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), recordingExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		recordings = append(recordings, recording{
+			path:    filepath.Join(r.Dir, e.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+	sort.Slice(recordings, func(i, j int) bool { return recordings[i].modTime.Before(recordings[j].modTime) })
+
+	for _, rec := range recordings {
+		if total <= r.maxTotalSize() {
+			return
+		}
+		if rec.path == active {
+			continue
+		}
+		err := os.Remove(rec.path)
+		if err != nil {
+			log.WithError(err).WithField("path", rec.path).Warn("cannot remove terminal recording")
+			continue
+		}
+		total -= rec.size
+	}
+}
+
+// Recording is a single recorded terminal session. Recordings which grow beyond
+// the maximum file size are continued in a new file.
+type Recording struct {
+	recordings *Recordings
+	name       string
+	title      string
+
+	mu      sync.Mutex
+	width   uint16
+	height  uint16
+	part    int
+	f       *os.File
+	start   time.Time
+	written int64
+	// partial holds the start of a UTF-8 sequence which was cut off by the last write
+	partial []byte
+}
+
+type recordingHeader struct {
+	Version   int    `json:"version"`
+	Width     uint16 `json:"width"`
+	Height    uint16 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// rotate continues the recording in a new file. Callers are expected to hold mu.
+func (rec *Recording) rotate() error {
+	if rec.f != nil {
+		err := rec.f.Close()
+		if err != nil {
+			log.WithError(err).WithField("path", rec.f.Name()).Warn("cannot close terminal recording")
+		}
+		rec.f = nil
+	}
+
+	rec.part++
+	fn := rec.name
+	if rec.part > 1 {
+		fn += fmt.Sprintf(".%d", rec.part)
+	}
+	fn = filepath.Join(rec.recordings.Dir, fn+recordingExt)
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot create terminal recording: %w", err)
+	}
+	rec.f = f
+	rec.start = time.Now()
+	rec.written = 0
+
+	header, err := json.Marshal(recordingHeader{
+		Version:   2,
+		Width:     rec.width,
+		Height:    rec.height,
+		Timestamp: rec.start.Unix(),
+		Title:     rec.title,
+	})
+	if err != nil {
+		return err
+	}
+	err = rec.writeLine(header)
+	if err != nil {
+		return err
+	}
+
+	rec.recordings.prune(fn)
+	return nil
+}
+
+// writeEvent appends an event to the recording. Callers are expected to hold mu.
+func (rec *Recording) writeEvent(code, data string) error {
+	if rec.f == nil {
+		return os.ErrClosed
+	}
+	if rec.written >= rec.recordings.maxFileSize() {
+		err := rec.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	event, err := json.Marshal([]interface{}{time.Since(rec.start).Seconds(), code, data})
+	if err != nil {
+		return err
+	}
+	return rec.writeLine(event)
+}
+
+func (rec *Recording) writeLine(line []byte) error {
+	n, err := rec.f.Write(append(line, '\n'))
+	rec.written += int64(n)
+	return err
+}
+
+// Write records terminal output.
+func (rec *Recording) Write(p []byte) (n int, err error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	data := append(rec.partial, p...)
+	rec.partial = nil
+	// JSON strings must be valid UTF-8, hence we hold back a sequence which is continued by the next write
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				rec.partial = append([]byte(nil), data[i:]...)
+				data = data[:i]
+			}
+			break
+		}
+	}
+	if len(data) == 0 {
+		return len(p), nil
+	}
+
+	err = rec.writeEvent("o", string(data))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size.
+func (rec *Recording) Resize(size *pty.Winsize) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.width, rec.height = size.Cols, size.Rows
+	return rec.writeEvent("r", fmt.Sprintf("%dx%d", size.Cols, size.Rows))
+}
+
+// Close ends the recording.
+func (rec *Recording) Close() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if rec.f == nil {
+		return nil
+	}
+	if len(rec.partial) > 0 {
+		_ = rec.writeEvent("o", string(rec.partial))
+		rec.partial = nil
+	}
+	err := rec.f.Close()
+	rec.f = nil
+	return err
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/creack/pty"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecording(t *testing.T) {
+	type event struct {
+		Code string
+		Data string
+	}
+	type file struct {
+		Header recordingHeader
+		Events []event
+	}
+	readRecordings := func(t *testing.T, dir string) map[string]file {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := make(map[string]file)
+		for _, e := range entries {
+			f, err := os.Open(filepath.Join(dir, e.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			var rec file
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				if rec.Header.Version == 0 {
+					err = json.Unmarshal(scanner.Bytes(), &rec.Header)
+					rec.Header.Timestamp = 0
+				} else {
+					var ev []interface{}
+					err = json.Unmarshal(scanner.Bytes(), &ev)
+					if err == nil {
+						rec.Events = append(rec.Events, event{Code: ev[1].(string), Data: ev[2].(string)})
+					}
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			// strip the start time from the name
+			res[e.Name()[len("20060102T150405-"):]] = rec
+		}
+		return res
+	}
+
+	tests := []struct {
+		Desc         string
+		MaxFileSize  int64
+		MaxTotalSize int64
+		Writes       []string
+		Expectation  map[string]file
+	}{
+		{
+			Desc:   "output and resize",
+			Writes: []string{"hello ", "world\r\n", "resize", "\xe2\x82", "\xac\r\n"},
+			Expectation: map[string]file{
+				"test.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{
+						{Code: "o", Data: "hello "},
+						{Code: "o", Data: "world\r\n"},
+						{Code: "r", Data: "120x40"},
+						{Code: "o", Data: "€\r\n"},
+					},
+				},
+			},
+		},
+		{
+			Desc:        "rotation",
+			MaxFileSize: 90,
+			Writes:      []string{"first", "second", "third"},
+			Expectation: map[string]file{
+				"test.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{{Code: "o", Data: "first"}},
+				},
+				"test.2.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{{Code: "o", Data: "second"}},
+				},
+				"test.3.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{{Code: "o", Data: "third"}},
+				},
+			},
+		},
+		{
+			Desc:         "oldest recordings are removed",
+			MaxFileSize:  90,
+			MaxTotalSize: 340,
+			Writes:       []string{"first", "second", "third", "fourth"},
+			Expectation: map[string]file{
+				"test.2.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{{Code: "o", Data: "second"}},
+				},
+				"test.3.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{{Code: "o", Data: "third"}},
+				},
+				"test.4.cast": {
+					Header: recordingHeader{Version: 2, Width: 80, Height: 24, Title: "title"},
+					Events: []event{{Code: "o", Data: "fourth"}},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "recordings")
+			recordings := &Recordings{Dir: dir, MaxFileSize: test.MaxFileSize, MaxTotalSize: test.MaxTotalSize}
+			rec, err := recordings.Open("test", nil, "title")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range test.Writes {
+				if w == "resize" {
+					err = rec.Resize(&pty.Winsize{Cols: 120, Rows: 40})
+				} else {
+					_, err = rec.Write([]byte(w))
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = rec.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			act := readRecordings(t, dir)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected recordings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -55,7 +55,15 @@ type MuxTerminalService struct {
 
 	DefaultShell string
 	Env          []string
+	// EnvProvider allows to compute the environment of new terminals dynamically.
+	// If nil, Env is used.
+	EnvProvider  func() []string
 	DefaultCreds *syscall.Credential
+	// OnInput is called whenever input was written to a terminal, e.g. to track user activity.
+	OnInput func()
+	// CgroupBasePath is where the cgroup v2 filesystem is mounted. Exec creates cgroups below supervisor's
+	// cgroup to enforce resource limits. If empty, resource limits are not supported.
+	CgroupBasePath string
 
 	api.UnimplementedTerminalServiceServer
 }
@@ -73,8 +81,9 @@ func (srv *MuxTerminalService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint
 // Open opens a new terminal running the shell.
 func (srv *MuxTerminalService) Open(ctx context.Context, req *api.OpenTerminalRequest) (*api.OpenTerminalResponse, error) {
 	return srv.OpenWithOptions(ctx, req, TermOptions{
-		ReadTimeout: 5 * time.Second,
-		Annotations: req.Annotations,
+		ReadTimeout:    5 * time.Second,
+		Annotations:    req.Annotations,
+		ScrollbackSize: req.ScrollbackSize,
 	})
 }
 
@@ -91,15 +100,8 @@ func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.Ope
 			Credential: srv.DefaultCreds,
 		}
 	}
-	if req.Workdir != "" {
-		cmd.Dir = req.Workdir
-	} else if srv.DefaultWorkdirProvider != nil {
-		cmd.Dir = srv.DefaultWorkdirProvider()
-	}
-	if cmd.Dir == "" {
-		cmd.Dir = srv.DefaultWorkdir
-	}
-	cmd.Env = append(srv.Env, "TERM=xterm-color")
+	cmd.Dir = srv.workdir(req.Workdir)
+	cmd.Env = append(srv.env(), "TERM=xterm-color")
 	for key, value := range req.Env {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%v=%v", key, value))
 	}
@@ -136,6 +138,29 @@ func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.Ope
 	}, nil
 }
 
+// workdir returns the working directory of new processes, given the one the client requested.
+func (srv *MuxTerminalService) workdir(workdir string) string {
+	if workdir != "" {
+		return workdir
+	}
+	if srv.DefaultWorkdirProvider != nil {
+		workdir = srv.DefaultWorkdirProvider()
+	}
+	if workdir == "" {
+		workdir = srv.DefaultWorkdir
+	}
+	return workdir
+}
+
+// env returns the environment of new processes.
+func (srv *MuxTerminalService) env() []string {
+	env := srv.Env
+	if srv.EnvProvider != nil {
+		env = srv.EnvProvider()
+	}
+	return env
+}
+
 // Close closes a terminal for the given alias.
 func (srv *MuxTerminalService) Shutdown(ctx context.Context, req *api.ShutdownTerminalRequest) (*api.ShutdownTerminalResponse, error) {
 	err := srv.Mux.CloseTerminal(req.Alias, closeTerminaldefaultGracePeriod)
@@ -307,6 +332,9 @@ func (srv *MuxTerminalService) Write(ctx context.Context, req *api.WriteTerminal
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	if srv.OnInput != nil {
+		srv.OnInput()
+	}
 	return &api.WriteTerminalResponse{BytesWritten: uint32(n)}, nil
 }
 
@@ -325,15 +353,17 @@ func (srv *MuxTerminalService) SetSize(ctx context.Context, req *api.SetTerminal
 		return nil, status.Error(codes.FailedPrecondition, "wrong token or force not set")
 	}
 
-	err := pty.Setsize(term.PTY, &pty.Winsize{
+	size := &pty.Winsize{
 		Cols: uint16(req.Size.Cols),
 		Rows: uint16(req.Size.Rows),
 		X:    uint16(req.Size.WidthPx),
 		Y:    uint16(req.Size.HeightPx),
-	})
+	}
+	err := pty.Setsize(term.PTY, size)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	term.Stdout.resize(size)
 
 	return &api.SetTerminalSizeResponse{}, nil
 }
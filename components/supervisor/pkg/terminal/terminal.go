@@ -37,6 +37,9 @@ type Mux struct {
 	aliases []string
 	terms   map[string]*Term
 	mu      sync.RWMutex
+
+	// Recordings records the sessions of all terminals if not nil.
+	Recordings *Recordings
 }
 
 // Get returns a terminal for the given alias.
@@ -64,9 +67,20 @@ func (m *Mux) Start(cmd *exec.Cmd, options TermOptions) (alias string, err error
 	}
 	alias = uid.String()
 
-	term, err := newTerm(alias, pty, cmd, options)
+	var recording *Recording
+	if m.Recordings != nil {
+		recording, err = m.Recordings.Open(alias, options.Size, options.Title)
+		if err != nil {
+			log.WithError(err).WithField("alias", alias).Warn("cannot record terminal session")
+		}
+	}
+
+	term, err := newTerm(alias, pty, cmd, options, recording)
 	if err != nil {
 		pty.Close()
+		if recording != nil {
+			_ = recording.Close()
+		}
 		return "", err
 	}
 	m.aliases = append(m.aliases, alias)
@@ -191,13 +205,23 @@ func (term *Term) shutdownProcessImmediately() error {
 // For now we assume an average of five terminals per workspace, which makes this consume 1MiB of RAM.
 const terminalBacklogSize = 256 << 10
 
-func newTerm(alias string, pty *os.File, cmd *exec.Cmd, options TermOptions) (*Term, error) {
+// maxTerminalBacklogSize limits the backlog size clients can ask for.
+const maxTerminalBacklogSize = 8 << 20
+
+func newTerm(alias string, pty *os.File, cmd *exec.Cmd, options TermOptions, recording *Recording) (*Term, error) {
 	token, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
 
-	recorder, err := NewRingBuffer(terminalBacklogSize)
+	backlogSize := options.ScrollbackSize
+	if backlogSize == 0 {
+		backlogSize = terminalBacklogSize
+	}
+	if backlogSize > maxTerminalBacklogSize {
+		backlogSize = maxTerminalBacklogSize
+	}
+	recorder, err := NewRingBuffer(backlogSize)
 	if err != nil {
 		return nil, err
 	}
@@ -213,6 +237,8 @@ func newTerm(alias string, pty *os.File, cmd *exec.Cmd, options TermOptions) (*T
 			timeout:   timeout,
 			listener:  make(map[*multiWriterListener]struct{}),
 			recorder:  recorder,
+			recording: recording,
+			modes:     newTerminalModes(),
 			logStdout: options.LogToStdout,
 			logLabel:  alias,
 		},
@@ -260,6 +286,9 @@ type TermOptions struct {
 
 	// LogToStdout forwards the terminal's stdout to supervisor's stdout
 	LogToStdout bool
+
+	// ScrollbackSize is the number of bytes of output replayed to new listeners. Use 0 for the default.
+	ScrollbackSize int64
 }
 
 // Term is a pseudo-terminal.
@@ -361,6 +390,10 @@ type multiWriter struct {
 	// ring buffer to record last 256kb of pty output
 	// new listener is initialized with the latest recodring first
 	recorder *RingBuffer
+	// modes which need to be restored once the recording was truncated
+	modes *terminalModes
+	// recording of the whole session, nil if sessions aren't recorded
+	recording *Recording
 
 	logStdout bool
 	logLabel  string
@@ -431,7 +464,7 @@ func (mw *multiWriter) Listen() io.ReadCloser {
 		closeChan: closeChan,
 	}
 
-	recording := mw.recorder.Bytes()
+	recording := mw.replay()
 	go func() {
 		_, _ = w.Write(recording)
 
@@ -470,11 +503,42 @@ func (mw *multiWriter) Listen() io.ReadCloser {
 	return res
 }
 
+// replay produces the output replayed to new listeners. Callers are expected to hold mu.
+func (mw *multiWriter) replay() []byte {
+	recording := mw.recorder.Bytes()
+	if mw.recorder.TotalWritten() <= mw.recorder.Size() {
+		return recording
+	}
+
+	// The recording starts somewhere in the middle of the output. We skip the first partial line,
+	// so that we don't start in the middle of an escape sequence or character, and restore the modes
+	// which were enabled before the recording starts.
+	if idx := bytes.IndexByte(recording, '\n'); idx >= 0 && idx < maxPartialLine {
+		recording = recording[idx+1:]
+	}
+	restore := mw.modes.Restore()
+	res := make([]byte, 0, len(restore)+len(recording))
+	res = append(res, restore...)
+	return append(res, recording...)
+}
+
+// maxPartialLine is the maximum number of bytes we skip to find the start of the first complete line of a recording
+const maxPartialLine = 4 << 10
+
 func (mw *multiWriter) Write(p []byte) (n int, err error) {
 	mw.mu.Lock()
 	defer mw.mu.Unlock()
 
 	mw.recorder.Write(p)
+	mw.modes.Write(p)
+	if mw.recording != nil {
+		_, err := mw.recording.Write(p)
+		if err != nil {
+			log.WithError(err).WithField("label", mw.logLabel).Warn("cannot record terminal output")
+			_ = mw.recording.Close()
+			mw.recording = nil
+		}
+	}
 	if mw.logStdout {
 		log.WithFields(logrus.Fields{
 			"terminalOutput": true,
@@ -515,9 +579,30 @@ func (mw *multiWriter) Close() error {
 			err = cerr
 		}
 	}
+	if mw.recording != nil {
+		cerr := mw.recording.Close()
+		if cerr != nil {
+			err = cerr
+		}
+		mw.recording = nil
+	}
 	return err
 }
 
+// resize records a change of the terminal size.
+func (mw *multiWriter) resize(size *pty.Winsize) {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	if mw.recording == nil {
+		return
+	}
+	err := mw.recording.Resize(size)
+	if err != nil {
+		log.WithError(err).WithField("label", mw.logLabel).Warn("cannot record terminal resize")
+	}
+}
+
 func (mw *multiWriter) ListenerCount() int {
 	mw.mu.Lock()
 	defer mw.mu.Unlock()
@@ -112,6 +112,7 @@ var ring0Cmd = &cobra.Command{
 		cmd.Env = append(os.Environ(),
 			"WORKSPACEKIT_FSSHIFT="+prep.FsShift.String(),
 			fmt.Sprintf("WORKSPACEKIT_FULL_WORKSPACE_BACKUP=%v", prep.FullWorkspaceBackup),
+			"WORKSPACEKIT_SECCOMP_PROFILE="+prep.SeccompProfile,
 		)
 
 		if err := cmd.Start(); err != nil {
@@ -489,9 +490,10 @@ var ring1Cmd = &cobra.Command{
 
 		log.Info("signaling to child process")
 		_, err = msgutil.MarshalToWriter(ring2Conn, ringSyncMsg{
-			Stage:   1,
-			Rootfs:  ring2Root,
-			FSShift: fsshift,
+			Stage:          1,
+			Rootfs:         ring2Root,
+			FSShift:        fsshift,
+			SeccompProfile: os.Getenv("WORKSPACEKIT_SECCOMP_PROFILE"),
 		})
 		if err != nil {
 			log.WithError(err).Error("cannot send ring sync msg to ring2")
@@ -787,7 +789,7 @@ var ring2Cmd = &cobra.Command{
 
 		// Now that we're in our new root filesystem, including proc and all, we can load
 		// our seccomp filter, and tell our parent about it.
-		scmpFd, err := seccomp.LoadFilter()
+		scmpFd, err := seccomp.LoadFilter(msg.SeccompProfile)
 		if err != nil {
 			log.WithError(err).Error("cannot load seccomp filter - syscall handling would be broken")
 			return
@@ -903,9 +905,10 @@ func sleepForDebugging() {
 }
 
 type ringSyncMsg struct {
-	Stage   int               `json:"stage"`
-	Rootfs  string            `json:"rootfs"`
-	FSShift api.FSShiftMethod `json:"fsshift"`
+	Stage          int               `json:"stage"`
+	Rootfs         string            `json:"rootfs"`
+	FSShift        api.FSShiftMethod `json:"fsshift"`
+	SeccompProfile string            `json:"seccompProfile,omitempty"`
 }
 
 type inWorkspaceServiceClient struct {
@@ -44,11 +44,61 @@ func mapHandler(h SyscallHandler) map[string]syscallHandler {
 	}
 }
 
-// LoadFilter loads the syscall filter required to make the handler work.
+const (
+	// ProfileDefault is the seccomp filter of regular workspaces. It supports nested containers, e.g. Docker.
+	ProfileDefault = "default"
+	// ProfileRestricted prevents workloads from mounting filesystems and entering or creating namespaces,
+	// and from using kernel facilities developers rarely need. Nested containers do not work with it.
+	ProfileRestricted = "restricted"
+)
+
+// profile describes what a seccomp filter does with syscalls. All other syscalls are allowed.
+type profile struct {
+	// Denied syscalls fail with EPERM
+	Denied []string
+	// Handled syscalls are passed on to the SyscallHandler. They must be part of mapHandler.
+	Handled []string
+}
+
+var profiles = map[string]profile{
+	ProfileDefault: {
+		// we explicitly prohibit open_tree/move_mount to prevent container workloads
+		// from moving a proc mask using open_tree(..., CLONE|RECURSIVE).
+		Denied:  []string{"open_tree", "move_mount"},
+		Handled: []string{"mount", "umount", "umount2", "bind", "chown"},
+	},
+	ProfileRestricted: {
+		Denied: []string{
+			"open_tree",
+			"move_mount",
+			"mount",
+			"umount",
+			"umount2",
+			"pivot_root",
+			"unshare",
+			"setns",
+			"bpf",
+			"perf_event_open",
+			"userfaultfd",
+		},
+		Handled: []string{"bind", "chown"},
+	},
+}
+
+// LoadFilter loads the syscall filter of the named profile, which includes what's required to make the handler work.
+// If name is empty, the default profile applies.
 // Calling this function has a range of side-effects:
 //   - we'll lock the caller using `runtime.LockOSThread()`
 //   - we'll set no_new_privs on the process
-func LoadFilter() (libseccomp.ScmpFd, error) {
+func LoadFilter(name string) (libseccomp.ScmpFd, error) {
+	if name == "" {
+		name = ProfileDefault
+	}
+	prof, ok := profiles[name]
+	if !ok {
+		return 0, xerrors.Errorf("unknown seccomp profile %q", name)
+	}
+
 	filter, err := libseccomp.NewFilter(libseccomp.ActAllow)
 	if err != nil {
 		return 0, xerrors.Errorf("cannot create filter: %w", err)
@@ -62,13 +112,7 @@ func LoadFilter() (libseccomp.ScmpFd, error) {
 		return 0, xerrors.Errorf("cannot set no_new_privs: %w", err)
 	}
 
-	// we explicitly prohibit open_tree/move_mount to prevent container workloads
-	// from moving a proc mask using open_tree(..., CLONE|RECURSIVE).
-	deniedSyscalls := []string{
-		"open_tree",
-		"move_mount",
-	}
-	for _, sc := range deniedSyscalls {
+	for _, sc := range prof.Denied {
 		syscallID, err := libseccomp.GetSyscallFromName(sc)
 		if err != nil {
 			return 0, xerrors.Errorf("unknown syscall %s: %w", sc, err)
@@ -79,8 +123,7 @@ func LoadFilter() (libseccomp.ScmpFd, error) {
 		}
 	}
 
-	handledSyscalls := mapHandler(&InWorkspaceHandler{})
-	for sc := range handledSyscalls {
+	for _, sc := range prof.Handled {
 		syscallID, err := libseccomp.GetSyscallFromName(sc)
 		if err != nil {
 			return 0, xerrors.Errorf("unknown syscall %s: %w", sc, err)
@@ -30,10 +30,16 @@ type WorkspaceContentServiceClient interface {
 	WaitForInit(ctx context.Context, in *WaitForInitRequest, opts ...grpc.CallOption) (*WaitForInitResponse, error)
 	// TakeSnapshot creates a backup/snapshot of a workspace
 	TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error)
+	// CreateSnapshot creates a snapshot of a workspace while it keeps running. Other than TakeSnapshot it clones
+	// the workspace content before uploading it, and never marks the workspace content as not ready.
+	CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotResponse, error)
 	// disposeWorkspace cleans up a workspace, possibly after taking a final backup
 	DisposeWorkspace(ctx context.Context, in *DisposeWorkspaceRequest, opts ...grpc.CallOption) (*DisposeWorkspaceResponse, error)
 	// BackupWorkspace creates a backup of a workspace
 	BackupWorkspace(ctx context.Context, in *BackupWorkspaceRequest, opts ...grpc.CallOption) (*BackupWorkspaceResponse, error)
+	// RepairBackup verifies the remote backups of a workspace against the checksums recorded when they were uploaded,
+	// and uploads the workspace content anew if any of them are corrupted
+	RepairBackup(ctx context.Context, in *RepairBackupRequest, opts ...grpc.CallOption) (*RepairBackupResponse, error)
 }
 
 type workspaceContentServiceClient struct {
@@ -71,6 +77,15 @@ func (c *workspaceContentServiceClient) TakeSnapshot(ctx context.Context, in *Ta
 	return out, nil
 }
 
+func (c *workspaceContentServiceClient) CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*CreateSnapshotResponse, error) {
+	out := new(CreateSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/wsdaemon.WorkspaceContentService/CreateSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workspaceContentServiceClient) DisposeWorkspace(ctx context.Context, in *DisposeWorkspaceRequest, opts ...grpc.CallOption) (*DisposeWorkspaceResponse, error) {
 	out := new(DisposeWorkspaceResponse)
 	err := c.cc.Invoke(ctx, "/wsdaemon.WorkspaceContentService/DisposeWorkspace", in, out, opts...)
@@ -89,6 +104,15 @@ func (c *workspaceContentServiceClient) BackupWorkspace(ctx context.Context, in
 	return out, nil
 }
 
+func (c *workspaceContentServiceClient) RepairBackup(ctx context.Context, in *RepairBackupRequest, opts ...grpc.CallOption) (*RepairBackupResponse, error) {
+	out := new(RepairBackupResponse)
+	err := c.cc.Invoke(ctx, "/wsdaemon.WorkspaceContentService/RepairBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkspaceContentServiceServer is the server API for WorkspaceContentService service.
 // All implementations must embed UnimplementedWorkspaceContentServiceServer
 // for forward compatibility
@@ -101,10 +125,16 @@ type WorkspaceContentServiceServer interface {
 	WaitForInit(context.Context, *WaitForInitRequest) (*WaitForInitResponse, error)
 	// TakeSnapshot creates a backup/snapshot of a workspace
 	TakeSnapshot(context.Context, *TakeSnapshotRequest) (*TakeSnapshotResponse, error)
+	// CreateSnapshot creates a snapshot of a workspace while it keeps running. Other than TakeSnapshot it clones
+	// the workspace content before uploading it, and never marks the workspace content as not ready.
+	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*CreateSnapshotResponse, error)
 	// disposeWorkspace cleans up a workspace, possibly after taking a final backup
 	DisposeWorkspace(context.Context, *DisposeWorkspaceRequest) (*DisposeWorkspaceResponse, error)
 	// BackupWorkspace creates a backup of a workspace
 	BackupWorkspace(context.Context, *BackupWorkspaceRequest) (*BackupWorkspaceResponse, error)
+	// RepairBackup verifies the remote backups of a workspace against the checksums recorded when they were uploaded,
+	// and uploads the workspace content anew if any of them are corrupted
+	RepairBackup(context.Context, *RepairBackupRequest) (*RepairBackupResponse, error)
 	mustEmbedUnimplementedWorkspaceContentServiceServer()
 }
 
@@ -121,12 +151,18 @@ func (UnimplementedWorkspaceContentServiceServer) WaitForInit(context.Context, *
 func (UnimplementedWorkspaceContentServiceServer) TakeSnapshot(context.Context, *TakeSnapshotRequest) (*TakeSnapshotResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TakeSnapshot not implemented")
 }
+func (UnimplementedWorkspaceContentServiceServer) CreateSnapshot(context.Context, *CreateSnapshotRequest) (*CreateSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
 func (UnimplementedWorkspaceContentServiceServer) DisposeWorkspace(context.Context, *DisposeWorkspaceRequest) (*DisposeWorkspaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DisposeWorkspace not implemented")
 }
 func (UnimplementedWorkspaceContentServiceServer) BackupWorkspace(context.Context, *BackupWorkspaceRequest) (*BackupWorkspaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BackupWorkspace not implemented")
 }
+func (UnimplementedWorkspaceContentServiceServer) RepairBackup(context.Context, *RepairBackupRequest) (*RepairBackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RepairBackup not implemented")
+}
 func (UnimplementedWorkspaceContentServiceServer) mustEmbedUnimplementedWorkspaceContentServiceServer() {
 }
 
@@ -195,6 +231,24 @@ func _WorkspaceContentService_TakeSnapshot_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkspaceContentService_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceContentServiceServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsdaemon.WorkspaceContentService/CreateSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceContentServiceServer).CreateSnapshot(ctx, req.(*CreateSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkspaceContentService_DisposeWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DisposeWorkspaceRequest)
 	if err := dec(in); err != nil {
@@ -231,6 +285,24 @@ func _WorkspaceContentService_BackupWorkspace_Handler(srv interface{}, ctx conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkspaceContentService_RepairBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepairBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceContentServiceServer).RepairBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsdaemon.WorkspaceContentService/RepairBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceContentServiceServer).RepairBackup(ctx, req.(*RepairBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkspaceContentService_ServiceDesc is the grpc.ServiceDesc for WorkspaceContentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -250,6 +322,10 @@ var WorkspaceContentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TakeSnapshot",
 			Handler:    _WorkspaceContentService_TakeSnapshot_Handler,
 		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _WorkspaceContentService_CreateSnapshot_Handler,
+		},
 		{
 			MethodName: "DisposeWorkspace",
 			Handler:    _WorkspaceContentService_DisposeWorkspace_Handler,
@@ -258,6 +334,10 @@ var WorkspaceContentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BackupWorkspace",
 			Handler:    _WorkspaceContentService_BackupWorkspace_Handler,
 		},
+		{
+			MethodName: "RepairBackup",
+			Handler:    _WorkspaceContentService_RepairBackup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "daemon.proto",
@@ -60,6 +60,26 @@ func (mr *MockWorkspaceContentServiceClientMockRecorder) BackupWorkspace(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupWorkspace", reflect.TypeOf((*MockWorkspaceContentServiceClient)(nil).BackupWorkspace), varargs...)
 }
 
+// CreateSnapshot mocks base method.
+func (m *MockWorkspaceContentServiceClient) CreateSnapshot(arg0 context.Context, arg1 *api.CreateSnapshotRequest, arg2 ...grpc.CallOption) (*api.CreateSnapshotResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateSnapshot", varargs...)
+	ret0, _ := ret[0].(*api.CreateSnapshotResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockWorkspaceContentServiceClientMockRecorder) CreateSnapshot(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockWorkspaceContentServiceClient)(nil).CreateSnapshot), varargs...)
+}
+
 // DisposeWorkspace mocks base method.
 func (m *MockWorkspaceContentServiceClient) DisposeWorkspace(arg0 context.Context, arg1 *api.DisposeWorkspaceRequest, arg2 ...grpc.CallOption) (*api.DisposeWorkspaceResponse, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +120,26 @@ func (mr *MockWorkspaceContentServiceClientMockRecorder) InitWorkspace(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitWorkspace", reflect.TypeOf((*MockWorkspaceContentServiceClient)(nil).InitWorkspace), varargs...)
 }
 
+// RepairBackup mocks base method.
+func (m *MockWorkspaceContentServiceClient) RepairBackup(arg0 context.Context, arg1 *api.RepairBackupRequest, arg2 ...grpc.CallOption) (*api.RepairBackupResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RepairBackup", varargs...)
+	ret0, _ := ret[0].(*api.RepairBackupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairBackup indicates an expected call of RepairBackup.
+func (mr *MockWorkspaceContentServiceClientMockRecorder) RepairBackup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairBackup", reflect.TypeOf((*MockWorkspaceContentServiceClient)(nil).RepairBackup), varargs...)
+}
+
 // TakeSnapshot mocks base method.
 func (m *MockWorkspaceContentServiceClient) TakeSnapshot(arg0 context.Context, arg1 *api.TakeSnapshotRequest, arg2 ...grpc.CallOption) (*api.TakeSnapshotResponse, error) {
 	m.ctrl.T.Helper()
@@ -179,6 +219,21 @@ func (mr *MockWorkspaceContentServiceServerMockRecorder) BackupWorkspace(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupWorkspace", reflect.TypeOf((*MockWorkspaceContentServiceServer)(nil).BackupWorkspace), arg0, arg1)
 }
 
+// CreateSnapshot mocks base method.
+func (m *MockWorkspaceContentServiceServer) CreateSnapshot(arg0 context.Context, arg1 *api.CreateSnapshotRequest) (*api.CreateSnapshotResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(*api.CreateSnapshotResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockWorkspaceContentServiceServerMockRecorder) CreateSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockWorkspaceContentServiceServer)(nil).CreateSnapshot), arg0, arg1)
+}
+
 // DisposeWorkspace mocks base method.
 func (m *MockWorkspaceContentServiceServer) DisposeWorkspace(arg0 context.Context, arg1 *api.DisposeWorkspaceRequest) (*api.DisposeWorkspaceResponse, error) {
 	m.ctrl.T.Helper()
@@ -209,6 +264,21 @@ func (mr *MockWorkspaceContentServiceServerMockRecorder) InitWorkspace(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitWorkspace", reflect.TypeOf((*MockWorkspaceContentServiceServer)(nil).InitWorkspace), arg0, arg1)
 }
 
+// RepairBackup mocks base method.
+func (m *MockWorkspaceContentServiceServer) RepairBackup(arg0 context.Context, arg1 *api.RepairBackupRequest) (*api.RepairBackupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairBackup", arg0, arg1)
+	ret0, _ := ret[0].(*api.RepairBackupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairBackup indicates an expected call of RepairBackup.
+func (mr *MockWorkspaceContentServiceServerMockRecorder) RepairBackup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairBackup", reflect.TypeOf((*MockWorkspaceContentServiceServer)(nil).RepairBackup), arg0, arg1)
+}
+
 // TakeSnapshot mocks base method.
 func (m *MockWorkspaceContentServiceServer) TakeSnapshot(arg0 context.Context, arg1 *api.TakeSnapshotRequest) (*api.TakeSnapshotResponse, error) {
 	m.ctrl.T.Helper()
@@ -394,6 +464,46 @@ func (mr *MockInWorkspaceServiceClientMockRecorder) UmountSysfs(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UmountSysfs", reflect.TypeOf((*MockInWorkspaceServiceClient)(nil).UmountSysfs), varargs...)
 }
 
+// WatchDiskQuota mocks base method.
+func (m *MockInWorkspaceServiceClient) WatchDiskQuota(arg0 context.Context, arg1 *api.WatchDiskQuotaRequest, arg2 ...grpc.CallOption) (api.InWorkspaceService_WatchDiskQuotaClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchDiskQuota", varargs...)
+	ret0, _ := ret[0].(api.InWorkspaceService_WatchDiskQuotaClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchDiskQuota indicates an expected call of WatchDiskQuota.
+func (mr *MockInWorkspaceServiceClientMockRecorder) WatchDiskQuota(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchDiskQuota", reflect.TypeOf((*MockInWorkspaceServiceClient)(nil).WatchDiskQuota), varargs...)
+}
+
+// WatchInitProgress mocks base method.
+func (m *MockInWorkspaceServiceClient) WatchInitProgress(arg0 context.Context, arg1 *api.WatchInitProgressRequest, arg2 ...grpc.CallOption) (api.InWorkspaceService_WatchInitProgressClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchInitProgress", varargs...)
+	ret0, _ := ret[0].(api.InWorkspaceService_WatchInitProgressClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchInitProgress indicates an expected call of WatchInitProgress.
+func (mr *MockInWorkspaceServiceClientMockRecorder) WatchInitProgress(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchInitProgress", reflect.TypeOf((*MockInWorkspaceServiceClient)(nil).WatchInitProgress), varargs...)
+}
+
 // WriteIDMapping mocks base method.
 func (m *MockInWorkspaceServiceClient) WriteIDMapping(arg0 context.Context, arg1 *api.WriteIDMappingRequest, arg2 ...grpc.CallOption) (*api.WriteIDMappingResponse, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +51,12 @@ type InWorkspaceServiceClient interface {
 	// Teardown prepares workspace content backups and unmounts shiftfs mounts. The canary is supposed to be triggered
 	// when the workspace is about to shut down, e.g. using the PreStop hook of a Kubernetes container.
 	Teardown(ctx context.Context, in *TeardownRequest, opts ...grpc.CallOption) (*TeardownResponse, error)
+	// WatchDiskQuota notifies whenever the workspace content comes close to its disk quota, so that the user
+	// can free up space before writes start failing.
+	WatchDiskQuota(ctx context.Context, in *WatchDiskQuotaRequest, opts ...grpc.CallOption) (InWorkspaceService_WatchDiskQuotaClient, error)
+	// WatchInitProgress streams the progress of the workspace content initialization until it's done, so that
+	// the workspace can show how far it has come.
+	WatchInitProgress(ctx context.Context, in *WatchInitProgressRequest, opts ...grpc.CallOption) (InWorkspaceService_WatchInitProgressClient, error)
 }
 
 type inWorkspaceServiceClient struct {
@@ -124,6 +130,70 @@ func (c *inWorkspaceServiceClient) Teardown(ctx context.Context, in *TeardownReq
 	return out, nil
 }
 
+func (c *inWorkspaceServiceClient) WatchDiskQuota(ctx context.Context, in *WatchDiskQuotaRequest, opts ...grpc.CallOption) (InWorkspaceService_WatchDiskQuotaClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InWorkspaceService_ServiceDesc.Streams[0], "/iws.InWorkspaceService/WatchDiskQuota", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inWorkspaceServiceWatchDiskQuotaClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InWorkspaceService_WatchDiskQuotaClient interface {
+	Recv() (*WatchDiskQuotaResponse, error)
+	grpc.ClientStream
+}
+
+type inWorkspaceServiceWatchDiskQuotaClient struct {
+	grpc.ClientStream
+}
+
+func (x *inWorkspaceServiceWatchDiskQuotaClient) Recv() (*WatchDiskQuotaResponse, error) {
+	m := new(WatchDiskQuotaResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *inWorkspaceServiceClient) WatchInitProgress(ctx context.Context, in *WatchInitProgressRequest, opts ...grpc.CallOption) (InWorkspaceService_WatchInitProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InWorkspaceService_ServiceDesc.Streams[1], "/iws.InWorkspaceService/WatchInitProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inWorkspaceServiceWatchInitProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InWorkspaceService_WatchInitProgressClient interface {
+	Recv() (*WatchInitProgressResponse, error)
+	grpc.ClientStream
+}
+
+type inWorkspaceServiceWatchInitProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *inWorkspaceServiceWatchInitProgressClient) Recv() (*WatchInitProgressResponse, error) {
+	m := new(WatchInitProgressResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // InWorkspaceServiceServer is the server API for InWorkspaceService service.
 // All implementations must embed UnimplementedInWorkspaceServiceServer
 // for forward compatibility
@@ -157,6 +227,12 @@ type InWorkspaceServiceServer interface {
 	// Teardown prepares workspace content backups and unmounts shiftfs mounts. The canary is supposed to be triggered
 	// when the workspace is about to shut down, e.g. using the PreStop hook of a Kubernetes container.
 	Teardown(context.Context, *TeardownRequest) (*TeardownResponse, error)
+	// WatchDiskQuota notifies whenever the workspace content comes close to its disk quota, so that the user
+	// can free up space before writes start failing.
+	WatchDiskQuota(*WatchDiskQuotaRequest, InWorkspaceService_WatchDiskQuotaServer) error
+	// WatchInitProgress streams the progress of the workspace content initialization until it's done, so that
+	// the workspace can show how far it has come.
+	WatchInitProgress(*WatchInitProgressRequest, InWorkspaceService_WatchInitProgressServer) error
 	mustEmbedUnimplementedInWorkspaceServiceServer()
 }
 
@@ -185,6 +261,12 @@ func (UnimplementedInWorkspaceServiceServer) UmountSysfs(context.Context, *Umoun
 func (UnimplementedInWorkspaceServiceServer) Teardown(context.Context, *TeardownRequest) (*TeardownResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Teardown not implemented")
 }
+func (UnimplementedInWorkspaceServiceServer) WatchDiskQuota(*WatchDiskQuotaRequest, InWorkspaceService_WatchDiskQuotaServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDiskQuota not implemented")
+}
+func (UnimplementedInWorkspaceServiceServer) WatchInitProgress(*WatchInitProgressRequest, InWorkspaceService_WatchInitProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchInitProgress not implemented")
+}
 func (UnimplementedInWorkspaceServiceServer) mustEmbedUnimplementedInWorkspaceServiceServer() {}
 
 // UnsafeInWorkspaceServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -324,6 +406,48 @@ func _InWorkspaceService_Teardown_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InWorkspaceService_WatchDiskQuota_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDiskQuotaRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InWorkspaceServiceServer).WatchDiskQuota(m, &inWorkspaceServiceWatchDiskQuotaServer{stream})
+}
+
+type InWorkspaceService_WatchDiskQuotaServer interface {
+	Send(*WatchDiskQuotaResponse) error
+	grpc.ServerStream
+}
+
+type inWorkspaceServiceWatchDiskQuotaServer struct {
+	grpc.ServerStream
+}
+
+func (x *inWorkspaceServiceWatchDiskQuotaServer) Send(m *WatchDiskQuotaResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InWorkspaceService_WatchInitProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchInitProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InWorkspaceServiceServer).WatchInitProgress(m, &inWorkspaceServiceWatchInitProgressServer{stream})
+}
+
+type InWorkspaceService_WatchInitProgressServer interface {
+	Send(*WatchInitProgressResponse) error
+	grpc.ServerStream
+}
+
+type inWorkspaceServiceWatchInitProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *inWorkspaceServiceWatchInitProgressServer) Send(m *WatchInitProgressResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // InWorkspaceService_ServiceDesc is the grpc.ServiceDesc for InWorkspaceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -360,6 +484,17 @@ var InWorkspaceService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _InWorkspaceService_Teardown_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDiskQuota",
+			Handler:       _InWorkspaceService_WatchDiskQuota_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchInitProgress",
+			Handler:       _InWorkspaceService_WatchInitProgress_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "workspace_daemon.proto",
 }
@@ -18,8 +18,10 @@ interface IWorkspaceContentServiceService extends grpc.ServiceDefinition<grpc.Un
     initWorkspace: IWorkspaceContentServiceService_IInitWorkspace;
     waitForInit: IWorkspaceContentServiceService_IWaitForInit;
     takeSnapshot: IWorkspaceContentServiceService_ITakeSnapshot;
+    createSnapshot: IWorkspaceContentServiceService_ICreateSnapshot;
     disposeWorkspace: IWorkspaceContentServiceService_IDisposeWorkspace;
     backupWorkspace: IWorkspaceContentServiceService_IBackupWorkspace;
+    repairBackup: IWorkspaceContentServiceService_IRepairBackup;
 }
 
 interface IWorkspaceContentServiceService_IInitWorkspace extends grpc.MethodDefinition<daemon_pb.InitWorkspaceRequest, daemon_pb.InitWorkspaceResponse> {
@@ -49,6 +51,15 @@ interface IWorkspaceContentServiceService_ITakeSnapshot extends grpc.MethodDefin
     responseSerialize: grpc.serialize<daemon_pb.TakeSnapshotResponse>;
     responseDeserialize: grpc.deserialize<daemon_pb.TakeSnapshotResponse>;
 }
+interface IWorkspaceContentServiceService_ICreateSnapshot extends grpc.MethodDefinition<daemon_pb.CreateSnapshotRequest, daemon_pb.CreateSnapshotResponse> {
+    path: "/wsdaemon.WorkspaceContentService/CreateSnapshot";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<daemon_pb.CreateSnapshotRequest>;
+    requestDeserialize: grpc.deserialize<daemon_pb.CreateSnapshotRequest>;
+    responseSerialize: grpc.serialize<daemon_pb.CreateSnapshotResponse>;
+    responseDeserialize: grpc.deserialize<daemon_pb.CreateSnapshotResponse>;
+}
 interface IWorkspaceContentServiceService_IDisposeWorkspace extends grpc.MethodDefinition<daemon_pb.DisposeWorkspaceRequest, daemon_pb.DisposeWorkspaceResponse> {
     path: "/wsdaemon.WorkspaceContentService/DisposeWorkspace";
     requestStream: false;
@@ -67,6 +78,15 @@ interface IWorkspaceContentServiceService_IBackupWorkspace extends grpc.MethodDe
     responseSerialize: grpc.serialize<daemon_pb.BackupWorkspaceResponse>;
     responseDeserialize: grpc.deserialize<daemon_pb.BackupWorkspaceResponse>;
 }
+interface IWorkspaceContentServiceService_IRepairBackup extends grpc.MethodDefinition<daemon_pb.RepairBackupRequest, daemon_pb.RepairBackupResponse> {
+    path: "/wsdaemon.WorkspaceContentService/RepairBackup";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<daemon_pb.RepairBackupRequest>;
+    requestDeserialize: grpc.deserialize<daemon_pb.RepairBackupRequest>;
+    responseSerialize: grpc.serialize<daemon_pb.RepairBackupResponse>;
+    responseDeserialize: grpc.deserialize<daemon_pb.RepairBackupResponse>;
+}
 
 export const WorkspaceContentServiceService: IWorkspaceContentServiceService;
 
@@ -74,8 +94,10 @@ export interface IWorkspaceContentServiceServer extends grpc.UntypedServiceImple
     initWorkspace: grpc.handleUnaryCall<daemon_pb.InitWorkspaceRequest, daemon_pb.InitWorkspaceResponse>;
     waitForInit: grpc.handleUnaryCall<daemon_pb.WaitForInitRequest, daemon_pb.WaitForInitResponse>;
     takeSnapshot: grpc.handleUnaryCall<daemon_pb.TakeSnapshotRequest, daemon_pb.TakeSnapshotResponse>;
+    createSnapshot: grpc.handleUnaryCall<daemon_pb.CreateSnapshotRequest, daemon_pb.CreateSnapshotResponse>;
     disposeWorkspace: grpc.handleUnaryCall<daemon_pb.DisposeWorkspaceRequest, daemon_pb.DisposeWorkspaceResponse>;
     backupWorkspace: grpc.handleUnaryCall<daemon_pb.BackupWorkspaceRequest, daemon_pb.BackupWorkspaceResponse>;
+    repairBackup: grpc.handleUnaryCall<daemon_pb.RepairBackupRequest, daemon_pb.RepairBackupResponse>;
 }
 
 export interface IWorkspaceContentServiceClient {
@@ -88,12 +110,18 @@ export interface IWorkspaceContentServiceClient {
     takeSnapshot(request: daemon_pb.TakeSnapshotRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.TakeSnapshotResponse) => void): grpc.ClientUnaryCall;
     takeSnapshot(request: daemon_pb.TakeSnapshotRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.TakeSnapshotResponse) => void): grpc.ClientUnaryCall;
     takeSnapshot(request: daemon_pb.TakeSnapshotRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.TakeSnapshotResponse) => void): grpc.ClientUnaryCall;
+    createSnapshot(request: daemon_pb.CreateSnapshotRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.CreateSnapshotResponse) => void): grpc.ClientUnaryCall;
+    createSnapshot(request: daemon_pb.CreateSnapshotRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.CreateSnapshotResponse) => void): grpc.ClientUnaryCall;
+    createSnapshot(request: daemon_pb.CreateSnapshotRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.CreateSnapshotResponse) => void): grpc.ClientUnaryCall;
     disposeWorkspace(request: daemon_pb.DisposeWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.DisposeWorkspaceResponse) => void): grpc.ClientUnaryCall;
     disposeWorkspace(request: daemon_pb.DisposeWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.DisposeWorkspaceResponse) => void): grpc.ClientUnaryCall;
     disposeWorkspace(request: daemon_pb.DisposeWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.DisposeWorkspaceResponse) => void): grpc.ClientUnaryCall;
     backupWorkspace(request: daemon_pb.BackupWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.BackupWorkspaceResponse) => void): grpc.ClientUnaryCall;
     backupWorkspace(request: daemon_pb.BackupWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.BackupWorkspaceResponse) => void): grpc.ClientUnaryCall;
     backupWorkspace(request: daemon_pb.BackupWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.BackupWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    repairBackup(request: daemon_pb.RepairBackupRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.RepairBackupResponse) => void): grpc.ClientUnaryCall;
+    repairBackup(request: daemon_pb.RepairBackupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.RepairBackupResponse) => void): grpc.ClientUnaryCall;
+    repairBackup(request: daemon_pb.RepairBackupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.RepairBackupResponse) => void): grpc.ClientUnaryCall;
 }
 
 export class WorkspaceContentServiceClient extends grpc.Client implements IWorkspaceContentServiceClient {
@@ -107,10 +135,16 @@ export class WorkspaceContentServiceClient extends grpc.Client implements IWorks
     public takeSnapshot(request: daemon_pb.TakeSnapshotRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.TakeSnapshotResponse) => void): grpc.ClientUnaryCall;
     public takeSnapshot(request: daemon_pb.TakeSnapshotRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.TakeSnapshotResponse) => void): grpc.ClientUnaryCall;
     public takeSnapshot(request: daemon_pb.TakeSnapshotRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.TakeSnapshotResponse) => void): grpc.ClientUnaryCall;
+    public createSnapshot(request: daemon_pb.CreateSnapshotRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.CreateSnapshotResponse) => void): grpc.ClientUnaryCall;
+    public createSnapshot(request: daemon_pb.CreateSnapshotRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.CreateSnapshotResponse) => void): grpc.ClientUnaryCall;
+    public createSnapshot(request: daemon_pb.CreateSnapshotRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.CreateSnapshotResponse) => void): grpc.ClientUnaryCall;
     public disposeWorkspace(request: daemon_pb.DisposeWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.DisposeWorkspaceResponse) => void): grpc.ClientUnaryCall;
     public disposeWorkspace(request: daemon_pb.DisposeWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.DisposeWorkspaceResponse) => void): grpc.ClientUnaryCall;
     public disposeWorkspace(request: daemon_pb.DisposeWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.DisposeWorkspaceResponse) => void): grpc.ClientUnaryCall;
     public backupWorkspace(request: daemon_pb.BackupWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.BackupWorkspaceResponse) => void): grpc.ClientUnaryCall;
     public backupWorkspace(request: daemon_pb.BackupWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.BackupWorkspaceResponse) => void): grpc.ClientUnaryCall;
     public backupWorkspace(request: daemon_pb.BackupWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.BackupWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public repairBackup(request: daemon_pb.RepairBackupRequest, callback: (error: grpc.ServiceError | null, response: daemon_pb.RepairBackupResponse) => void): grpc.ClientUnaryCall;
+    public repairBackup(request: daemon_pb.RepairBackupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: daemon_pb.RepairBackupResponse) => void): grpc.ClientUnaryCall;
+    public repairBackup(request: daemon_pb.RepairBackupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: daemon_pb.RepairBackupResponse) => void): grpc.ClientUnaryCall;
 }
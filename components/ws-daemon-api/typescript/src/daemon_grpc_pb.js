@@ -33,6 +33,28 @@ function deserialize_wsdaemon_BackupWorkspaceResponse(buffer_arg) {
   return daemon_pb.BackupWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsdaemon_CreateSnapshotRequest(arg) {
+  if (!(arg instanceof daemon_pb.CreateSnapshotRequest)) {
+    throw new Error('Expected argument of type wsdaemon.CreateSnapshotRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsdaemon_CreateSnapshotRequest(buffer_arg) {
+  return daemon_pb.CreateSnapshotRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsdaemon_CreateSnapshotResponse(arg) {
+  if (!(arg instanceof daemon_pb.CreateSnapshotResponse)) {
+    throw new Error('Expected argument of type wsdaemon.CreateSnapshotResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsdaemon_CreateSnapshotResponse(buffer_arg) {
+  return daemon_pb.CreateSnapshotResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsdaemon_DisposeWorkspaceRequest(arg) {
   if (!(arg instanceof daemon_pb.DisposeWorkspaceRequest)) {
     throw new Error('Expected argument of type wsdaemon.DisposeWorkspaceRequest');
@@ -77,6 +99,28 @@ function deserialize_wsdaemon_InitWorkspaceResponse(buffer_arg) {
   return daemon_pb.InitWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsdaemon_RepairBackupRequest(arg) {
+  if (!(arg instanceof daemon_pb.RepairBackupRequest)) {
+    throw new Error('Expected argument of type wsdaemon.RepairBackupRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsdaemon_RepairBackupRequest(buffer_arg) {
+  return daemon_pb.RepairBackupRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsdaemon_RepairBackupResponse(arg) {
+  if (!(arg instanceof daemon_pb.RepairBackupResponse)) {
+    throw new Error('Expected argument of type wsdaemon.RepairBackupResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsdaemon_RepairBackupResponse(buffer_arg) {
+  return daemon_pb.RepairBackupResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsdaemon_TakeSnapshotRequest(arg) {
   if (!(arg instanceof daemon_pb.TakeSnapshotRequest)) {
     throw new Error('Expected argument of type wsdaemon.TakeSnapshotRequest');
@@ -161,6 +205,19 @@ takeSnapshot: {
     responseSerialize: serialize_wsdaemon_TakeSnapshotResponse,
     responseDeserialize: deserialize_wsdaemon_TakeSnapshotResponse,
   },
+  // CreateSnapshot creates a snapshot of a workspace while it keeps running. Other than TakeSnapshot it clones
+// the workspace content before uploading it, and never marks the workspace content as not ready.
+createSnapshot: {
+    path: '/wsdaemon.WorkspaceContentService/CreateSnapshot',
+    requestStream: false,
+    responseStream: false,
+    requestType: daemon_pb.CreateSnapshotRequest,
+    responseType: daemon_pb.CreateSnapshotResponse,
+    requestSerialize: serialize_wsdaemon_CreateSnapshotRequest,
+    requestDeserialize: deserialize_wsdaemon_CreateSnapshotRequest,
+    responseSerialize: serialize_wsdaemon_CreateSnapshotResponse,
+    responseDeserialize: deserialize_wsdaemon_CreateSnapshotResponse,
+  },
   // disposeWorkspace cleans up a workspace, possibly after taking a final backup
 disposeWorkspace: {
     path: '/wsdaemon.WorkspaceContentService/DisposeWorkspace',
@@ -185,6 +242,19 @@ backupWorkspace: {
     responseSerialize: serialize_wsdaemon_BackupWorkspaceResponse,
     responseDeserialize: deserialize_wsdaemon_BackupWorkspaceResponse,
   },
+  // RepairBackup verifies the remote backups of a workspace against the checksums recorded when they were uploaded,
+// and uploads the workspace content anew if any of them are corrupted
+repairBackup: {
+    path: '/wsdaemon.WorkspaceContentService/RepairBackup',
+    requestStream: false,
+    responseStream: false,
+    requestType: daemon_pb.RepairBackupRequest,
+    responseType: daemon_pb.RepairBackupResponse,
+    requestSerialize: serialize_wsdaemon_RepairBackupRequest,
+    requestDeserialize: deserialize_wsdaemon_RepairBackupRequest,
+    responseSerialize: serialize_wsdaemon_RepairBackupResponse,
+    responseDeserialize: deserialize_wsdaemon_RepairBackupResponse,
+  },
 };
 
 exports.WorkspaceContentServiceClient = grpc.makeGenericClientConstructor(WorkspaceContentServiceService);
@@ -34,6 +34,12 @@ export class InitWorkspaceRequest extends jspb.Message {
     setContentManifest(value: Uint8Array | string): InitWorkspaceRequest;
     getRemoteStorageDisabled(): boolean;
     setRemoteStorageDisabled(value: boolean): InitWorkspaceRequest;
+    getPersistentVolumeClaim(): boolean;
+    setPersistentVolumeClaim(value: boolean): InitWorkspaceRequest;
+    getStorageQuota(): number;
+    setStorageQuota(value: number): InitWorkspaceRequest;
+    getSeccompProfile(): string;
+    setSeccompProfile(value: string): InitWorkspaceRequest;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): InitWorkspaceRequest.AsObject;
@@ -53,6 +59,9 @@ export namespace InitWorkspaceRequest {
         fullWorkspaceBackup: boolean,
         contentManifest: Uint8Array | string,
         remoteStorageDisabled: boolean,
+        persistentVolumeClaim: boolean,
+        storageQuota: number,
+        seccompProfile: string,
     }
 }
 
@@ -176,6 +185,49 @@ export namespace TakeSnapshotResponse {
     }
 }
 
+export class CreateSnapshotRequest extends jspb.Message {
+    getId(): string;
+    setId(value: string): CreateSnapshotRequest;
+    getReturnImmediately(): boolean;
+    setReturnImmediately(value: boolean): CreateSnapshotRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CreateSnapshotRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: CreateSnapshotRequest): CreateSnapshotRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CreateSnapshotRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CreateSnapshotRequest;
+    static deserializeBinaryFromReader(message: CreateSnapshotRequest, reader: jspb.BinaryReader): CreateSnapshotRequest;
+}
+
+export namespace CreateSnapshotRequest {
+    export type AsObject = {
+        id: string,
+        returnImmediately: boolean,
+    }
+}
+
+export class CreateSnapshotResponse extends jspb.Message {
+    getUrl(): string;
+    setUrl(value: string): CreateSnapshotResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CreateSnapshotResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: CreateSnapshotResponse): CreateSnapshotResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CreateSnapshotResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CreateSnapshotResponse;
+    static deserializeBinaryFromReader(message: CreateSnapshotResponse, reader: jspb.BinaryReader): CreateSnapshotResponse;
+}
+
+export namespace CreateSnapshotResponse {
+    export type AsObject = {
+        url: string,
+    }
+}
+
 export class DisposeWorkspaceRequest extends jspb.Message {
     getId(): string;
     setId(value: string): DisposeWorkspaceRequest;
@@ -265,6 +317,54 @@ export namespace BackupWorkspaceResponse {
     }
 }
 
+export class RepairBackupRequest extends jspb.Message {
+    getId(): string;
+    setId(value: string): RepairBackupRequest;
+    getDryRun(): boolean;
+    setDryRun(value: boolean): RepairBackupRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): RepairBackupRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: RepairBackupRequest): RepairBackupRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: RepairBackupRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): RepairBackupRequest;
+    static deserializeBinaryFromReader(message: RepairBackupRequest, reader: jspb.BinaryReader): RepairBackupRequest;
+}
+
+export namespace RepairBackupRequest {
+    export type AsObject = {
+        id: string,
+        dryRun: boolean,
+    }
+}
+
+export class RepairBackupResponse extends jspb.Message {
+    clearCorruptedBackupsList(): void;
+    getCorruptedBackupsList(): Array<string>;
+    setCorruptedBackupsList(value: Array<string>): RepairBackupResponse;
+    addCorruptedBackups(value: string, index?: number): string;
+    getRepaired(): boolean;
+    setRepaired(value: boolean): RepairBackupResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): RepairBackupResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: RepairBackupResponse): RepairBackupResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: RepairBackupResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): RepairBackupResponse;
+    static deserializeBinaryFromReader(message: RepairBackupResponse, reader: jspb.BinaryReader): RepairBackupResponse;
+}
+
+export namespace RepairBackupResponse {
+    export type AsObject = {
+        corruptedBackupsList: Array<string>,
+        repaired: boolean,
+    }
+}
+
 export enum WorkspaceContentState {
     NONE = 0,
     SETTING_UP = 1,
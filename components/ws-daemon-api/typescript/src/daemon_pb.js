@@ -31,10 +31,14 @@ var content$service$api_initializer_pb = require('@gitpod/content-service/lib');
 goog.object.extend(proto, content$service$api_initializer_pb);
 goog.exportSymbol('proto.wsdaemon.BackupWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsdaemon.BackupWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsdaemon.CreateSnapshotRequest', null, global);
+goog.exportSymbol('proto.wsdaemon.CreateSnapshotResponse', null, global);
 goog.exportSymbol('proto.wsdaemon.DisposeWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsdaemon.DisposeWorkspaceResponse', null, global);
 goog.exportSymbol('proto.wsdaemon.InitWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsdaemon.InitWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsdaemon.RepairBackupRequest', null, global);
+goog.exportSymbol('proto.wsdaemon.RepairBackupResponse', null, global);
 goog.exportSymbol('proto.wsdaemon.TakeSnapshotRequest', null, global);
 goog.exportSymbol('proto.wsdaemon.TakeSnapshotResponse', null, global);
 goog.exportSymbol('proto.wsdaemon.WaitForInitRequest', null, global);
@@ -188,6 +192,48 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.wsdaemon.TakeSnapshotResponse.displayName = 'proto.wsdaemon.TakeSnapshotResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsdaemon.CreateSnapshotRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsdaemon.CreateSnapshotRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsdaemon.CreateSnapshotRequest.displayName = 'proto.wsdaemon.CreateSnapshotRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsdaemon.CreateSnapshotResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsdaemon.CreateSnapshotResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsdaemon.CreateSnapshotResponse.displayName = 'proto.wsdaemon.CreateSnapshotResponse';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -272,6 +318,48 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.wsdaemon.BackupWorkspaceResponse.displayName = 'proto.wsdaemon.BackupWorkspaceResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsdaemon.RepairBackupRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsdaemon.RepairBackupRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsdaemon.RepairBackupRequest.displayName = 'proto.wsdaemon.RepairBackupRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsdaemon.RepairBackupResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsdaemon.RepairBackupResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsdaemon.RepairBackupResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsdaemon.RepairBackupResponse.displayName = 'proto.wsdaemon.RepairBackupResponse';
+}
 
 
 
@@ -309,7 +397,10 @@ proto.wsdaemon.InitWorkspaceRequest.toObject = function(includeInstance, msg) {
     initializer: (f = msg.getInitializer()) && content$service$api_initializer_pb.WorkspaceInitializer.toObject(includeInstance, f),
     fullWorkspaceBackup: jspb.Message.getBooleanFieldWithDefault(msg, 4, false),
     contentManifest: msg.getContentManifest_asB64(),
-    remoteStorageDisabled: jspb.Message.getBooleanFieldWithDefault(msg, 7, false)
+    remoteStorageDisabled: jspb.Message.getBooleanFieldWithDefault(msg, 7, false),
+    persistentVolumeClaim: jspb.Message.getBooleanFieldWithDefault(msg, 8, false),
+    storageQuota: jspb.Message.getFieldWithDefault(msg, 9, 0),
+    seccompProfile: jspb.Message.getFieldWithDefault(msg, 10, "")
   };
 
   if (includeInstance) {
@@ -372,6 +463,18 @@ proto.wsdaemon.InitWorkspaceRequest.deserializeBinaryFromReader = function(msg,
       var value = /** @type {boolean} */ (reader.readBool());
       msg.setRemoteStorageDisabled(value);
       break;
+    case 8:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setPersistentVolumeClaim(value);
+      break;
+    case 9:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setStorageQuota(value);
+      break;
+    case 10:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSeccompProfile(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -445,6 +548,27 @@ proto.wsdaemon.InitWorkspaceRequest.serializeBinaryToWriter = function(message,
       f
     );
   }
+  f = message.getPersistentVolumeClaim();
+  if (f) {
+    writer.writeBool(
+      8,
+      f
+    );
+  }
+  f = message.getStorageQuota();
+  if (f !== 0) {
+    writer.writeInt64(
+      9,
+      f
+    );
+  }
+  f = message.getSeccompProfile();
+  if (f.length > 0) {
+    writer.writeString(
+      10,
+      f
+    );
+  }
 };
 
 
@@ -618,6 +742,60 @@ proto.wsdaemon.InitWorkspaceRequest.prototype.setRemoteStorageDisabled = functio
 };
 
 
+/**
+ * optional bool persistent_volume_claim = 8;
+ * @return {boolean}
+ */
+proto.wsdaemon.InitWorkspaceRequest.prototype.getPersistentVolumeClaim = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 8, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsdaemon.InitWorkspaceRequest} returns this
+ */
+proto.wsdaemon.InitWorkspaceRequest.prototype.setPersistentVolumeClaim = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 8, value);
+};
+
+
+/**
+ * optional int64 storage_quota = 9;
+ * @return {number}
+ */
+proto.wsdaemon.InitWorkspaceRequest.prototype.getStorageQuota = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 9, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.wsdaemon.InitWorkspaceRequest} returns this
+ */
+proto.wsdaemon.InitWorkspaceRequest.prototype.setStorageQuota = function(value) {
+  return jspb.Message.setProto3IntField(this, 9, value);
+};
+
+
+/**
+ * optional string seccomp_profile = 10;
+ * @return {string}
+ */
+proto.wsdaemon.InitWorkspaceRequest.prototype.getSeccompProfile = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsdaemon.InitWorkspaceRequest} returns this
+ */
+proto.wsdaemon.InitWorkspaceRequest.prototype.setSeccompProfile = function(value) {
+  return jspb.Message.setProto3StringField(this, 10, value);
+};
+
+
 
 
 
@@ -1402,7 +1580,6 @@ proto.wsdaemon.TakeSnapshotResponse.prototype.setUrl = function(value) {
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -1416,8 +1593,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsdaemon.DisposeWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsdaemon.CreateSnapshotRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.CreateSnapshotRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -1426,15 +1603,14 @@ proto.wsdaemon.DisposeWorkspaceRequest.prototype.toObject = function(opt_include
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsdaemon.DisposeWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsdaemon.CreateSnapshotRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.DisposeWorkspaceRequest.toObject = function(includeInstance, msg) {
+proto.wsdaemon.CreateSnapshotRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    backup: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
-    backupLogs: jspb.Message.getBooleanFieldWithDefault(msg, 3, false)
+    returnImmediately: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
   };
 
   if (includeInstance) {
@@ -1448,23 +1624,23 @@ proto.wsdaemon.DisposeWorkspaceRequest.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsdaemon.DisposeWorkspaceRequest}
+ * @return {!proto.wsdaemon.CreateSnapshotRequest}
  */
-proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsdaemon.CreateSnapshotRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsdaemon.DisposeWorkspaceRequest;
-  return proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsdaemon.CreateSnapshotRequest;
+  return proto.wsdaemon.CreateSnapshotRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsdaemon.DisposeWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsdaemon.CreateSnapshotRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsdaemon.DisposeWorkspaceRequest}
+ * @return {!proto.wsdaemon.CreateSnapshotRequest}
  */
-proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsdaemon.CreateSnapshotRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1477,11 +1653,7 @@ proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinaryFromReader = function(ms
       break;
     case 2:
       var value = /** @type {boolean} */ (reader.readBool());
-      msg.setBackup(value);
-      break;
-    case 3:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setBackupLogs(value);
+      msg.setReturnImmediately(value);
       break;
     default:
       reader.skipField();
@@ -1496,9 +1668,9 @@ proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinaryFromReader = function(ms
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsdaemon.CreateSnapshotRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsdaemon.DisposeWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsdaemon.CreateSnapshotRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1506,11 +1678,11 @@ proto.wsdaemon.DisposeWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsdaemon.DisposeWorkspaceRequest} message
+ * @param {!proto.wsdaemon.CreateSnapshotRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.DisposeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsdaemon.CreateSnapshotRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -1519,20 +1691,13 @@ proto.wsdaemon.DisposeWorkspaceRequest.serializeBinaryToWriter = function(messag
       f
     );
   }
-  f = message.getBackup();
+  f = message.getReturnImmediately();
   if (f) {
     writer.writeBool(
       2,
       f
     );
   }
-  f = message.getBackupLogs();
-  if (f) {
-    writer.writeBool(
-      3,
-      f
-    );
-  }
 };
 
 
@@ -1540,57 +1705,38 @@ proto.wsdaemon.DisposeWorkspaceRequest.serializeBinaryToWriter = function(messag
  * optional string id = 1;
  * @return {string}
  */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.getId = function() {
+proto.wsdaemon.CreateSnapshotRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsdaemon.DisposeWorkspaceRequest} returns this
+ * @return {!proto.wsdaemon.CreateSnapshotRequest} returns this
  */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.setId = function(value) {
+proto.wsdaemon.CreateSnapshotRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional bool backup = 2;
+ * optional bool return_immediately = 2;
  * @return {boolean}
  */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.getBackup = function() {
+proto.wsdaemon.CreateSnapshotRequest.prototype.getReturnImmediately = function() {
   return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
 };
 
 
 /**
  * @param {boolean} value
- * @return {!proto.wsdaemon.DisposeWorkspaceRequest} returns this
+ * @return {!proto.wsdaemon.CreateSnapshotRequest} returns this
  */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.setBackup = function(value) {
+proto.wsdaemon.CreateSnapshotRequest.prototype.setReturnImmediately = function(value) {
   return jspb.Message.setProto3BooleanField(this, 2, value);
 };
 
 
-/**
- * optional bool backup_logs = 3;
- * @return {boolean}
- */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.getBackupLogs = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 3, false));
-};
-
-
-/**
- * @param {boolean} value
- * @return {!proto.wsdaemon.DisposeWorkspaceRequest} returns this
- */
-proto.wsdaemon.DisposeWorkspaceRequest.prototype.setBackupLogs = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 3, value);
-};
-
-
-
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -1606,8 +1752,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsdaemon.DisposeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsdaemon.DisposeWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsdaemon.CreateSnapshotResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.CreateSnapshotResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -1616,13 +1762,13 @@ proto.wsdaemon.DisposeWorkspaceResponse.prototype.toObject = function(opt_includ
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsdaemon.DisposeWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsdaemon.CreateSnapshotResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.DisposeWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsdaemon.CreateSnapshotResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    gitStatus: (f = msg.getGitStatus()) && content$service$api_initializer_pb.GitStatus.toObject(includeInstance, f)
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -1636,23 +1782,23 @@ proto.wsdaemon.DisposeWorkspaceResponse.toObject = function(includeInstance, msg
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsdaemon.DisposeWorkspaceResponse}
+ * @return {!proto.wsdaemon.CreateSnapshotResponse}
  */
-proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsdaemon.CreateSnapshotResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsdaemon.DisposeWorkspaceResponse;
-  return proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsdaemon.CreateSnapshotResponse;
+  return proto.wsdaemon.CreateSnapshotResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsdaemon.DisposeWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsdaemon.CreateSnapshotResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsdaemon.DisposeWorkspaceResponse}
+ * @return {!proto.wsdaemon.CreateSnapshotResponse}
  */
-proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsdaemon.CreateSnapshotResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1660,9 +1806,8 @@ proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinaryFromReader = function(m
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new content$service$api_initializer_pb.GitStatus;
-      reader.readMessage(value,content$service$api_initializer_pb.GitStatus.deserializeBinaryFromReader);
-      msg.setGitStatus(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
       break;
     default:
       reader.skipField();
@@ -1677,9 +1822,9 @@ proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinaryFromReader = function(m
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsdaemon.DisposeWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsdaemon.CreateSnapshotResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsdaemon.DisposeWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsdaemon.CreateSnapshotResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1687,57 +1832,37 @@ proto.wsdaemon.DisposeWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsdaemon.DisposeWorkspaceResponse} message
+ * @param {!proto.wsdaemon.CreateSnapshotResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.DisposeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsdaemon.CreateSnapshotResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getGitStatus();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
       1,
-      f,
-      content$service$api_initializer_pb.GitStatus.serializeBinaryToWriter
+      f
     );
   }
 };
 
 
 /**
- * optional contentservice.GitStatus git_status = 1;
- * @return {?proto.contentservice.GitStatus}
- */
-proto.wsdaemon.DisposeWorkspaceResponse.prototype.getGitStatus = function() {
-  return /** @type{?proto.contentservice.GitStatus} */ (
-    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.GitStatus, 1));
-};
-
-
-/**
- * @param {?proto.contentservice.GitStatus|undefined} value
- * @return {!proto.wsdaemon.DisposeWorkspaceResponse} returns this
-*/
-proto.wsdaemon.DisposeWorkspaceResponse.prototype.setGitStatus = function(value) {
-  return jspb.Message.setWrapperField(this, 1, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.wsdaemon.DisposeWorkspaceResponse} returns this
+ * optional string url = 1;
+ * @return {string}
  */
-proto.wsdaemon.DisposeWorkspaceResponse.prototype.clearGitStatus = function() {
-  return this.setGitStatus(undefined);
+proto.wsdaemon.CreateSnapshotResponse.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.wsdaemon.CreateSnapshotResponse} returns this
  */
-proto.wsdaemon.DisposeWorkspaceResponse.prototype.hasGitStatus = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.wsdaemon.CreateSnapshotResponse.prototype.setUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
@@ -1757,8 +1882,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsdaemon.BackupWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsdaemon.BackupWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.DisposeWorkspaceRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -1767,13 +1892,484 @@ proto.wsdaemon.BackupWorkspaceRequest.prototype.toObject = function(opt_includeI
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsdaemon.BackupWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsdaemon.DisposeWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    backup: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
+    backupLogs: jspb.Message.getBooleanFieldWithDefault(msg, 3, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsdaemon.DisposeWorkspaceRequest}
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsdaemon.DisposeWorkspaceRequest;
+  return proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsdaemon.DisposeWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsdaemon.DisposeWorkspaceRequest}
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setBackup(value);
+      break;
+    case 3:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setBackupLogs(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsdaemon.DisposeWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsdaemon.DisposeWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getBackup();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getBackupLogs();
+  if (f) {
+    writer.writeBool(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsdaemon.DisposeWorkspaceRequest} returns this
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional bool backup = 2;
+ * @return {boolean}
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.getBackup = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsdaemon.DisposeWorkspaceRequest} returns this
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.setBackup = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+/**
+ * optional bool backup_logs = 3;
+ * @return {boolean}
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.getBackupLogs = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 3, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsdaemon.DisposeWorkspaceRequest} returns this
+ */
+proto.wsdaemon.DisposeWorkspaceRequest.prototype.setBackupLogs = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.DisposeWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsdaemon.DisposeWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    gitStatus: (f = msg.getGitStatus()) && content$service$api_initializer_pb.GitStatus.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsdaemon.DisposeWorkspaceResponse}
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsdaemon.DisposeWorkspaceResponse;
+  return proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsdaemon.DisposeWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsdaemon.DisposeWorkspaceResponse}
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new content$service$api_initializer_pb.GitStatus;
+      reader.readMessage(value,content$service$api_initializer_pb.GitStatus.deserializeBinaryFromReader);
+      msg.setGitStatus(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsdaemon.DisposeWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsdaemon.DisposeWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getGitStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      content$service$api_initializer_pb.GitStatus.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional contentservice.GitStatus git_status = 1;
+ * @return {?proto.contentservice.GitStatus}
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.prototype.getGitStatus = function() {
+  return /** @type{?proto.contentservice.GitStatus} */ (
+    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.GitStatus, 1));
+};
+
+
+/**
+ * @param {?proto.contentservice.GitStatus|undefined} value
+ * @return {!proto.wsdaemon.DisposeWorkspaceResponse} returns this
+*/
+proto.wsdaemon.DisposeWorkspaceResponse.prototype.setGitStatus = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsdaemon.DisposeWorkspaceResponse} returns this
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.prototype.clearGitStatus = function() {
+  return this.setGitStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsdaemon.DisposeWorkspaceResponse.prototype.hasGitStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsdaemon.BackupWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.BackupWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsdaemon.BackupWorkspaceRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
 proto.wsdaemon.BackupWorkspaceRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsdaemon.BackupWorkspaceRequest}
+ */
+proto.wsdaemon.BackupWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsdaemon.BackupWorkspaceRequest;
+  return proto.wsdaemon.BackupWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsdaemon.BackupWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsdaemon.BackupWorkspaceRequest}
+ */
+proto.wsdaemon.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsdaemon.BackupWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsdaemon.BackupWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsdaemon.BackupWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.BackupWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsdaemon.BackupWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsdaemon.BackupWorkspaceRequest} returns this
+ */
+proto.wsdaemon.BackupWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsdaemon.BackupWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.BackupWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsdaemon.BackupWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.BackupWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -1787,23 +2383,23 @@ proto.wsdaemon.BackupWorkspaceRequest.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsdaemon.BackupWorkspaceRequest}
+ * @return {!proto.wsdaemon.BackupWorkspaceResponse}
  */
-proto.wsdaemon.BackupWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsdaemon.BackupWorkspaceResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsdaemon.BackupWorkspaceRequest;
-  return proto.wsdaemon.BackupWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsdaemon.BackupWorkspaceResponse;
+  return proto.wsdaemon.BackupWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsdaemon.BackupWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsdaemon.BackupWorkspaceResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsdaemon.BackupWorkspaceRequest}
+ * @return {!proto.wsdaemon.BackupWorkspaceResponse}
  */
-proto.wsdaemon.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsdaemon.BackupWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1812,7 +2408,7 @@ proto.wsdaemon.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
+      msg.setUrl(value);
       break;
     default:
       reader.skipField();
@@ -1827,9 +2423,9 @@ proto.wsdaemon.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsdaemon.BackupWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsdaemon.BackupWorkspaceResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsdaemon.BackupWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsdaemon.BackupWorkspaceResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1837,13 +2433,13 @@ proto.wsdaemon.BackupWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsdaemon.BackupWorkspaceRequest} message
+ * @param {!proto.wsdaemon.BackupWorkspaceResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.BackupWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsdaemon.BackupWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
+  f = message.getUrl();
   if (f.length > 0) {
     writer.writeString(
       1,
@@ -1854,26 +2450,25 @@ proto.wsdaemon.BackupWorkspaceRequest.serializeBinaryToWriter = function(message
 
 
 /**
- * optional string id = 1;
+ * optional string url = 1;
  * @return {string}
  */
-proto.wsdaemon.BackupWorkspaceRequest.prototype.getId = function() {
+proto.wsdaemon.BackupWorkspaceResponse.prototype.getUrl = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsdaemon.BackupWorkspaceRequest} returns this
+ * @return {!proto.wsdaemon.BackupWorkspaceResponse} returns this
  */
-proto.wsdaemon.BackupWorkspaceRequest.prototype.setId = function(value) {
+proto.wsdaemon.BackupWorkspaceResponse.prototype.setUrl = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -1887,8 +2482,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsdaemon.BackupWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsdaemon.BackupWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsdaemon.RepairBackupRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.RepairBackupRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -1897,13 +2492,14 @@ proto.wsdaemon.BackupWorkspaceResponse.prototype.toObject = function(opt_include
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsdaemon.BackupWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsdaemon.RepairBackupRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.BackupWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsdaemon.RepairBackupRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    dryRun: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
   };
 
   if (includeInstance) {
@@ -1917,23 +2513,23 @@ proto.wsdaemon.BackupWorkspaceResponse.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsdaemon.BackupWorkspaceResponse}
+ * @return {!proto.wsdaemon.RepairBackupRequest}
  */
-proto.wsdaemon.BackupWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsdaemon.RepairBackupRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsdaemon.BackupWorkspaceResponse;
-  return proto.wsdaemon.BackupWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsdaemon.RepairBackupRequest;
+  return proto.wsdaemon.RepairBackupRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsdaemon.BackupWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsdaemon.RepairBackupRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsdaemon.BackupWorkspaceResponse}
+ * @return {!proto.wsdaemon.RepairBackupRequest}
  */
-proto.wsdaemon.BackupWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsdaemon.RepairBackupRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1942,7 +2538,11 @@ proto.wsdaemon.BackupWorkspaceResponse.deserializeBinaryFromReader = function(ms
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDryRun(value);
       break;
     default:
       reader.skipField();
@@ -1957,9 +2557,9 @@ proto.wsdaemon.BackupWorkspaceResponse.deserializeBinaryFromReader = function(ms
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsdaemon.BackupWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsdaemon.RepairBackupRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsdaemon.BackupWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsdaemon.RepairBackupRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1967,40 +2567,252 @@ proto.wsdaemon.BackupWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsdaemon.BackupWorkspaceResponse} message
+ * @param {!proto.wsdaemon.RepairBackupRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsdaemon.BackupWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsdaemon.RepairBackupRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getUrl();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
+  f = message.getDryRun();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string url = 1;
+ * optional string id = 1;
  * @return {string}
  */
-proto.wsdaemon.BackupWorkspaceResponse.prototype.getUrl = function() {
+proto.wsdaemon.RepairBackupRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsdaemon.BackupWorkspaceResponse} returns this
+ * @return {!proto.wsdaemon.RepairBackupRequest} returns this
  */
-proto.wsdaemon.BackupWorkspaceResponse.prototype.setUrl = function(value) {
+proto.wsdaemon.RepairBackupRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
+/**
+ * optional bool dry_run = 2;
+ * @return {boolean}
+ */
+proto.wsdaemon.RepairBackupRequest.prototype.getDryRun = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsdaemon.RepairBackupRequest} returns this
+ */
+proto.wsdaemon.RepairBackupRequest.prototype.setDryRun = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsdaemon.RepairBackupResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsdaemon.RepairBackupResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsdaemon.RepairBackupResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.RepairBackupResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    corruptedBackupsList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f,
+    repaired: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsdaemon.RepairBackupResponse}
+ */
+proto.wsdaemon.RepairBackupResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsdaemon.RepairBackupResponse;
+  return proto.wsdaemon.RepairBackupResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsdaemon.RepairBackupResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsdaemon.RepairBackupResponse}
+ */
+proto.wsdaemon.RepairBackupResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addCorruptedBackups(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setRepaired(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsdaemon.RepairBackupResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsdaemon.RepairBackupResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsdaemon.RepairBackupResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCorruptedBackupsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
+  f = message.getRepaired();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * repeated string corrupted_backups = 1;
+ * @return {!Array<string>}
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.getCorruptedBackupsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.wsdaemon.RepairBackupResponse} returns this
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.setCorruptedBackupsList = function(value) {
+  return jspb.Message.setField(this, 1, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.wsdaemon.RepairBackupResponse} returns this
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.addCorruptedBackups = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsdaemon.RepairBackupResponse} returns this
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.clearCorruptedBackupsList = function() {
+  return this.setCorruptedBackupsList([]);
+};
+
+
+/**
+ * optional bool repaired = 2;
+ * @return {boolean}
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.getRepaired = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsdaemon.RepairBackupResponse} returns this
+ */
+proto.wsdaemon.RepairBackupResponse.prototype.setRepaired = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
 /**
  * @enum {number}
  */
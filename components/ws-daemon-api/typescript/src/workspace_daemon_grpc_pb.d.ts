@@ -21,6 +21,8 @@ interface IInWorkspaceServiceService extends grpc.ServiceDefinition<grpc.Untyped
     mountSysfs: IInWorkspaceServiceService_IMountSysfs;
     umountSysfs: IInWorkspaceServiceService_IUmountSysfs;
     teardown: IInWorkspaceServiceService_ITeardown;
+    watchDiskQuota: IInWorkspaceServiceService_IWatchDiskQuota;
+    watchInitProgress: IInWorkspaceServiceService_IWatchInitProgress;
 }
 
 interface IInWorkspaceServiceService_IPrepareForUserNS extends grpc.MethodDefinition<workspace_daemon_pb.PrepareForUserNSRequest, workspace_daemon_pb.PrepareForUserNSResponse> {
@@ -86,6 +88,24 @@ interface IInWorkspaceServiceService_ITeardown extends grpc.MethodDefinition<wor
     responseSerialize: grpc.serialize<workspace_daemon_pb.TeardownResponse>;
     responseDeserialize: grpc.deserialize<workspace_daemon_pb.TeardownResponse>;
 }
+interface IInWorkspaceServiceService_IWatchDiskQuota extends grpc.MethodDefinition<workspace_daemon_pb.WatchDiskQuotaRequest, workspace_daemon_pb.WatchDiskQuotaResponse> {
+    path: "/iws.InWorkspaceService/WatchDiskQuota";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<workspace_daemon_pb.WatchDiskQuotaRequest>;
+    requestDeserialize: grpc.deserialize<workspace_daemon_pb.WatchDiskQuotaRequest>;
+    responseSerialize: grpc.serialize<workspace_daemon_pb.WatchDiskQuotaResponse>;
+    responseDeserialize: grpc.deserialize<workspace_daemon_pb.WatchDiskQuotaResponse>;
+}
+interface IInWorkspaceServiceService_IWatchInitProgress extends grpc.MethodDefinition<workspace_daemon_pb.WatchInitProgressRequest, workspace_daemon_pb.WatchInitProgressResponse> {
+    path: "/iws.InWorkspaceService/WatchInitProgress";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<workspace_daemon_pb.WatchInitProgressRequest>;
+    requestDeserialize: grpc.deserialize<workspace_daemon_pb.WatchInitProgressRequest>;
+    responseSerialize: grpc.serialize<workspace_daemon_pb.WatchInitProgressResponse>;
+    responseDeserialize: grpc.deserialize<workspace_daemon_pb.WatchInitProgressResponse>;
+}
 
 export const InWorkspaceServiceService: IInWorkspaceServiceService;
 
@@ -97,6 +117,8 @@ export interface IInWorkspaceServiceServer extends grpc.UntypedServiceImplementa
     mountSysfs: grpc.handleUnaryCall<workspace_daemon_pb.MountProcRequest, workspace_daemon_pb.MountProcResponse>;
     umountSysfs: grpc.handleUnaryCall<workspace_daemon_pb.UmountProcRequest, workspace_daemon_pb.UmountProcResponse>;
     teardown: grpc.handleUnaryCall<workspace_daemon_pb.TeardownRequest, workspace_daemon_pb.TeardownResponse>;
+    watchDiskQuota: grpc.handleServerStreamingCall<workspace_daemon_pb.WatchDiskQuotaRequest, workspace_daemon_pb.WatchDiskQuotaResponse>;
+    watchInitProgress: grpc.handleServerStreamingCall<workspace_daemon_pb.WatchInitProgressRequest, workspace_daemon_pb.WatchInitProgressResponse>;
 }
 
 export interface IInWorkspaceServiceClient {
@@ -121,6 +143,10 @@ export interface IInWorkspaceServiceClient {
     teardown(request: workspace_daemon_pb.TeardownRequest, callback: (error: grpc.ServiceError | null, response: workspace_daemon_pb.TeardownResponse) => void): grpc.ClientUnaryCall;
     teardown(request: workspace_daemon_pb.TeardownRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: workspace_daemon_pb.TeardownResponse) => void): grpc.ClientUnaryCall;
     teardown(request: workspace_daemon_pb.TeardownRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: workspace_daemon_pb.TeardownResponse) => void): grpc.ClientUnaryCall;
+    watchDiskQuota(request: workspace_daemon_pb.WatchDiskQuotaRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchDiskQuotaResponse>;
+    watchDiskQuota(request: workspace_daemon_pb.WatchDiskQuotaRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchDiskQuotaResponse>;
+    watchInitProgress(request: workspace_daemon_pb.WatchInitProgressRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchInitProgressResponse>;
+    watchInitProgress(request: workspace_daemon_pb.WatchInitProgressRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchInitProgressResponse>;
 }
 
 export class InWorkspaceServiceClient extends grpc.Client implements IInWorkspaceServiceClient {
@@ -146,4 +172,8 @@ export class InWorkspaceServiceClient extends grpc.Client implements IInWorkspac
     public teardown(request: workspace_daemon_pb.TeardownRequest, callback: (error: grpc.ServiceError | null, response: workspace_daemon_pb.TeardownResponse) => void): grpc.ClientUnaryCall;
     public teardown(request: workspace_daemon_pb.TeardownRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: workspace_daemon_pb.TeardownResponse) => void): grpc.ClientUnaryCall;
     public teardown(request: workspace_daemon_pb.TeardownRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: workspace_daemon_pb.TeardownResponse) => void): grpc.ClientUnaryCall;
+    public watchDiskQuota(request: workspace_daemon_pb.WatchDiskQuotaRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchDiskQuotaResponse>;
+    public watchDiskQuota(request: workspace_daemon_pb.WatchDiskQuotaRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchDiskQuotaResponse>;
+    public watchInitProgress(request: workspace_daemon_pb.WatchInitProgressRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchInitProgressResponse>;
+    public watchInitProgress(request: workspace_daemon_pb.WatchInitProgressRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<workspace_daemon_pb.WatchInitProgressResponse>;
 }
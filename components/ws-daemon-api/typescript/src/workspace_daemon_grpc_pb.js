@@ -98,6 +98,50 @@ function deserialize_iws_UmountProcResponse(buffer_arg) {
   return workspace_daemon_pb.UmountProcResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_iws_WatchDiskQuotaRequest(arg) {
+  if (!(arg instanceof workspace_daemon_pb.WatchDiskQuotaRequest)) {
+    throw new Error('Expected argument of type iws.WatchDiskQuotaRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_iws_WatchDiskQuotaRequest(buffer_arg) {
+  return workspace_daemon_pb.WatchDiskQuotaRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_iws_WatchDiskQuotaResponse(arg) {
+  if (!(arg instanceof workspace_daemon_pb.WatchDiskQuotaResponse)) {
+    throw new Error('Expected argument of type iws.WatchDiskQuotaResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_iws_WatchDiskQuotaResponse(buffer_arg) {
+  return workspace_daemon_pb.WatchDiskQuotaResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_iws_WatchInitProgressRequest(arg) {
+  if (!(arg instanceof workspace_daemon_pb.WatchInitProgressRequest)) {
+    throw new Error('Expected argument of type iws.WatchInitProgressRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_iws_WatchInitProgressRequest(buffer_arg) {
+  return workspace_daemon_pb.WatchInitProgressRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_iws_WatchInitProgressResponse(arg) {
+  if (!(arg instanceof workspace_daemon_pb.WatchInitProgressResponse)) {
+    throw new Error('Expected argument of type iws.WatchInitProgressResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_iws_WatchInitProgressResponse(buffer_arg) {
+  return workspace_daemon_pb.WatchInitProgressResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_iws_WriteIDMappingRequest(arg) {
   if (!(arg instanceof workspace_daemon_pb.WriteIDMappingRequest)) {
     throw new Error('Expected argument of type iws.WriteIDMappingRequest');
@@ -221,6 +265,32 @@ teardown: {
     responseSerialize: serialize_iws_TeardownResponse,
     responseDeserialize: deserialize_iws_TeardownResponse,
   },
+  // WatchDiskQuota notifies whenever the workspace content comes close to its disk quota, so that the user
+// can free up space before writes start failing.
+watchDiskQuota: {
+    path: '/iws.InWorkspaceService/WatchDiskQuota',
+    requestStream: false,
+    responseStream: true,
+    requestType: workspace_daemon_pb.WatchDiskQuotaRequest,
+    responseType: workspace_daemon_pb.WatchDiskQuotaResponse,
+    requestSerialize: serialize_iws_WatchDiskQuotaRequest,
+    requestDeserialize: deserialize_iws_WatchDiskQuotaRequest,
+    responseSerialize: serialize_iws_WatchDiskQuotaResponse,
+    responseDeserialize: deserialize_iws_WatchDiskQuotaResponse,
+  },
+  // WatchInitProgress streams the progress of the workspace content initialization until it's done, so that
+// the workspace can show how far it has come.
+watchInitProgress: {
+    path: '/iws.InWorkspaceService/WatchInitProgress',
+    requestStream: false,
+    responseStream: true,
+    requestType: workspace_daemon_pb.WatchInitProgressRequest,
+    responseType: workspace_daemon_pb.WatchInitProgressResponse,
+    requestSerialize: serialize_iws_WatchInitProgressRequest,
+    requestDeserialize: deserialize_iws_WatchInitProgressRequest,
+    responseSerialize: serialize_iws_WatchInitProgressResponse,
+    responseDeserialize: deserialize_iws_WatchInitProgressResponse,
+  },
 };
 
 exports.InWorkspaceServiceClient = grpc.makeGenericClientConstructor(InWorkspaceServiceService);
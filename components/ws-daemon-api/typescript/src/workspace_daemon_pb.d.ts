@@ -34,6 +34,8 @@ export class PrepareForUserNSResponse extends jspb.Message {
     setFsShift(value: FSShiftMethod): PrepareForUserNSResponse;
     getFullWorkspaceBackup(): boolean;
     setFullWorkspaceBackup(value: boolean): PrepareForUserNSResponse;
+    getSeccompProfile(): string;
+    setSeccompProfile(value: string): PrepareForUserNSResponse;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): PrepareForUserNSResponse.AsObject;
@@ -49,6 +51,7 @@ export namespace PrepareForUserNSResponse {
     export type AsObject = {
         fsShift: FSShiftMethod,
         fullWorkspaceBackup: boolean,
+        seccompProfile: string,
     }
 }
 
@@ -248,6 +251,95 @@ export namespace TeardownResponse {
     }
 }
 
+export class WatchDiskQuotaRequest extends jspb.Message {
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WatchDiskQuotaRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: WatchDiskQuotaRequest): WatchDiskQuotaRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WatchDiskQuotaRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WatchDiskQuotaRequest;
+    static deserializeBinaryFromReader(message: WatchDiskQuotaRequest, reader: jspb.BinaryReader): WatchDiskQuotaRequest;
+}
+
+export namespace WatchDiskQuotaRequest {
+    export type AsObject = {
+    }
+}
+
+export class WatchDiskQuotaResponse extends jspb.Message {
+    getUsedBytes(): number;
+    setUsedBytes(value: number): WatchDiskQuotaResponse;
+    getQuotaBytes(): number;
+    setQuotaBytes(value: number): WatchDiskQuotaResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WatchDiskQuotaResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: WatchDiskQuotaResponse): WatchDiskQuotaResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WatchDiskQuotaResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WatchDiskQuotaResponse;
+    static deserializeBinaryFromReader(message: WatchDiskQuotaResponse, reader: jspb.BinaryReader): WatchDiskQuotaResponse;
+}
+
+export namespace WatchDiskQuotaResponse {
+    export type AsObject = {
+        usedBytes: number,
+        quotaBytes: number,
+    }
+}
+
+export class WatchInitProgressRequest extends jspb.Message {
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WatchInitProgressRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: WatchInitProgressRequest): WatchInitProgressRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WatchInitProgressRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WatchInitProgressRequest;
+    static deserializeBinaryFromReader(message: WatchInitProgressRequest, reader: jspb.BinaryReader): WatchInitProgressRequest;
+}
+
+export namespace WatchInitProgressRequest {
+    export type AsObject = {
+    }
+}
+
+export class WatchInitProgressResponse extends jspb.Message {
+    getPhase(): string;
+    setPhase(value: string): WatchInitProgressResponse;
+    getPercent(): number;
+    setPercent(value: number): WatchInitProgressResponse;
+    getBytesDone(): number;
+    setBytesDone(value: number): WatchInitProgressResponse;
+    getBytesTotal(): number;
+    setBytesTotal(value: number): WatchInitProgressResponse;
+    getDone(): boolean;
+    setDone(value: boolean): WatchInitProgressResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WatchInitProgressResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: WatchInitProgressResponse): WatchInitProgressResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WatchInitProgressResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WatchInitProgressResponse;
+    static deserializeBinaryFromReader(message: WatchInitProgressResponse, reader: jspb.BinaryReader): WatchInitProgressResponse;
+}
+
+export namespace WatchInitProgressResponse {
+    export type AsObject = {
+        phase: string,
+        percent: number,
+        bytesDone: number,
+        bytesTotal: number,
+        done: boolean,
+    }
+}
+
 export enum FSShiftMethod {
     SHIFTFS = 0,
     FUSE = 1,
@@ -0,0 +1,24 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cgroup
+
+import (
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+)
+
+// IsUnifiedCgroupSetup returns true if the cgroup hierarchy mounted at basePath is the unified cgroup v2 hierarchy.
+// Hybrid setups mount their controllers as cgroup v1 and are not considered unified.
+//
+// We look at the base path rather than /sys/fs/cgroup because ws-daemon sees the node's cgroups through a mount
+// which need not match the hierarchy of its own container.
+func IsUnifiedCgroupSetup(basePath string) (bool, error) {
+	var st unix.Statfs_t
+	err := unix.Statfs(basePath, &st)
+	if err != nil {
+		return false, xerrors.Errorf("cannot statfs cgroup base path %s: %w", basePath, err)
+	}
+	return st.Type == unix.CGROUP2_SUPER_MAGIC, nil
+}
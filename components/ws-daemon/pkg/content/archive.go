@@ -24,6 +24,15 @@ type ConvertWhiteout func(*tar.Header, string) (bool, error)
 
 // BuildTarbal creates an OCI compatible tar file dst from the folder src, expecting the overlay whiteout format
 func BuildTarbal(ctx context.Context, src string, dst string, fullWorkspaceBackup bool, opts ...carchive.TarOption) (err error) {
+	return buildTarbal(ctx, src, dst, fullWorkspaceBackup, nil, opts...)
+}
+
+// BuildIncrementalTarbal creates a tar file dst which contains the changes of the folder src since a previous backup
+func BuildIncrementalTarbal(ctx context.Context, src string, dst string, changes *BackupChanges, opts ...carchive.TarOption) (err error) {
+	return buildTarbal(ctx, src, dst, false, changes, opts...)
+}
+
+func buildTarbal(ctx context.Context, src string, dst string, fullWorkspaceBackup bool, changes *BackupChanges, opts ...carchive.TarOption) (err error) {
 	var cfg carchive.TarConfig
 	for _, opt := range opts {
 		opt(&cfg)
@@ -68,6 +77,7 @@ func BuildTarbal(ctx context.Context, src string, dst string, fullWorkspaceBacku
 		tarout, err = TarWithOptions(src, &TarOptions{
 			UIDMaps: uidMaps,
 			GIDMaps: gidMaps,
+			Changes: changes,
 		})
 	}
 
@@ -56,6 +56,24 @@ type BackupConfig struct {
 
 	// Period is the time between regular workspace backups
 	Period util.Duration `json:"period"`
+
+	// Incremental configures incremental backups of regular workspaces
+	Incremental IncrementalBackupConfig `json:"incremental,omitempty"`
+
+	// Verify makes ws-daemon download every backup after its upload and check it against the checksums
+	// of the local archive. Corrupted uploads count as failed attempts.
+	Verify bool `json:"verify,omitempty"`
+}
+
+// IncrementalBackupConfig configures incremental backups. Once a regular workspace has a backup, further backups
+// upload only the files which changed since. If a backup period is configured, ws-daemon also takes live backups
+// of running workspaces, so that the final backup has little left to upload.
+type IncrementalBackupConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// MaxLength is the number of incremental backups after which we take a full backup again. Defaults to 10.
+	// Live backups stop once a workspace reaches this length, leaving the full backup to the final one.
+	MaxLength int `json:"maxLength,omitempty"`
 }
 
 type UserNamespacesConfig struct {
@@ -22,7 +22,7 @@ import (
 func workspaceLifecycleHooks(cfg Config, kubernetesNamespace string, workspaceExistenceCheck WorkspaceExistenceCheck, uidmapper *iws.Uidmapper, xfs *quota.XFS) map[session.WorkspaceState][]session.WorkspaceLivecycleHook {
 	// startIWS starts the in-workspace service for a workspace. This lifecycle hook is idempotent, hence can - and must -
 	// be called on initialization and ready. The on-ready hook exists only to support ws-daemon restarts.
-	startIWS := iws.ServeWorkspace(uidmapper, api.FSShiftMethod(cfg.UserNamespaces.FSShift))
+	startIWS := iws.ServeWorkspace(uidmapper, api.FSShiftMethod(cfg.UserNamespaces.FSShift), xfs)
 
 	return map[session.WorkspaceState][]session.WorkspaceLivecycleHook{
 		session.WorkspaceInitializing: {
@@ -71,6 +71,10 @@ func hookSetupRemoteStorage(cfg Config) session.WorkspaceLivecycleHook {
 
 // hookSetupWorkspaceLocation recreates the workspace location
 func hookSetupWorkspaceLocation(ctx context.Context, ws *session.Workspace) error {
+	if ws.PersistentVolumeClaim {
+		// the workspace content lives on a persistent volume claim which Kubernetes sets up for us
+		return nil
+	}
 	location := ws.Location
 
 	// 1. Clean out the workspace directory
@@ -92,15 +96,24 @@ func hookSetupWorkspaceLocation(ctx context.Context, ws *session.Workspace) erro
 	return nil
 }
 
-// hookInstallQuota enforces filesystem quota on the workspace location (if the filesystem supports it)
-func hookInstallQuota(xfs *quota.XFS, size quota.Size) session.WorkspaceLivecycleHook {
+// hookInstallQuota enforces filesystem quota on the workspace location (if the filesystem supports it).
+// The workspace's own storage quota takes precedence over the default size.
+func hookInstallQuota(xfs *quota.XFS, defaultSize quota.Size) session.WorkspaceLivecycleHook {
 	return func(ctx context.Context, ws *session.Workspace) error {
 		if xfs == nil {
 			return nil
 		}
+		size := defaultSize
+		if ws.StorageQuota > 0 {
+			size = quota.Size(ws.StorageQuota)
+		}
 		if size == 0 {
 			return nil
 		}
+		if ws.PersistentVolumeClaim {
+			// the size of a persistent volume claim is limited by the claim itself
+			return nil
+		}
 
 		if ws.XFSProjectID != 0 {
 			xfs.RegisterProject(ws.XFSProjectID)
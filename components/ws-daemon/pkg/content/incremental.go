@@ -0,0 +1,325 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+// backupIndexFile is the file in the workspace's daemon service location which stores its backup index
+const backupIndexFile = "backup-index.json"
+
+// defaultMaxIncrementalBackups is the number of incremental backups after which we take a full backup again
+const defaultMaxIncrementalBackups = 10
+
+// BackupChanges are the changes of a workspace since its previous backup. Paths are relative to the workspace location.
+type BackupChanges struct {
+	Changed []string
+	Deleted []string
+}
+
+// backupIndex records the state of the workspace content at the time of its last backup, so that the next
+// backup needs to upload only what changed since.
+type backupIndex struct {
+	// IncrementalBackups is the number of incremental backups on top of the default backup
+	IncrementalBackups int `json:"incrementalBackups"`
+	// Files maps the paths relative to the workspace location to their state
+	Files map[string]fileState `json:"files"`
+}
+
+type fileState struct {
+	Mode  os.FileMode `json:"m"`
+	Size  int64       `json:"s"`
+	MTime int64       `json:"mt"`
+	CTime int64       `json:"ct"`
+}
+
+// indexFiles records the state of all files in the workspace location
+func indexFiles(location string) (map[string]fileState, error) {
+	res := make(map[string]fileState)
+	err := filepath.Walk(location, func(path string, fi os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			// the file was deleted while we were walking
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(location, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." || rel == wsinit.WorkspaceReadyFile {
+			return nil
+		}
+
+		state := fileState{
+			Mode:  fi.Mode(),
+			Size:  fi.Size(),
+			MTime: fi.ModTime().UnixNano(),
+		}
+		if stat, ok := fi.Sys().(*syscall.Stat_t); ok {
+			// the change time catches metadata changes and content changes which preserve the modification time
+			state.CTime = stat.Ctim.Nano()
+		}
+		res[rel] = state
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot index workspace files: %w", err)
+	}
+	return res, nil
+}
+
+// changesSince computes the changes between the indexed files and the files at present
+func (idx *backupIndex) changesSince(files map[string]fileState) BackupChanges {
+	var res BackupChanges
+	for p, state := range files {
+		if prev, ok := idx.Files[p]; !ok || prev != state {
+			res.Changed = append(res.Changed, p)
+		}
+	}
+	for p := range idx.Files {
+		if _, ok := files[p]; !ok {
+			res.Deleted = append(res.Deleted, p)
+		}
+	}
+
+	// Parent directories must precede their content in the archive
+	sort.Strings(res.Changed)
+
+	// Deleting a directory deletes its content, hence we don't list the content separately
+	sort.Strings(res.Deleted)
+	deleted := res.Deleted[:0]
+	for _, p := range res.Deleted {
+		if len(deleted) > 0 && strings.HasPrefix(p, deleted[len(deleted)-1]+"/") {
+			continue
+		}
+		deleted = append(deleted, p)
+	}
+	res.Deleted = deleted
+
+	return res
+}
+
+// loadBackupIndex loads the backup index from the service location. Returns nil if there is none.
+func loadBackupIndex(serviceLoc string) (*backupIndex, error) {
+	fc, err := os.ReadFile(filepath.Join(serviceLoc, backupIndexFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read backup index: %w", err)
+	}
+
+	var res backupIndex
+	err = json.Unmarshal(fc, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal backup index: %w", err)
+	}
+	return &res, nil
+}
+
+// save atomically stores the backup index in the service location
+func (idx *backupIndex) save(serviceLoc string) error {
+	fc, err := json.Marshal(idx)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal backup index: %w", err)
+	}
+
+	err = os.MkdirAll(serviceLoc, 0755)
+	if err != nil {
+		return xerrors.Errorf("cannot write backup index: %w", err)
+	}
+	tmpf := filepath.Join(serviceLoc, backupIndexFile+".tmp")
+	err = os.WriteFile(tmpf, fc, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write backup index: %w", err)
+	}
+	err = os.Rename(tmpf, filepath.Join(serviceLoc, backupIndexFile))
+	if err != nil {
+		return xerrors.Errorf("cannot write backup index: %w", err)
+	}
+	return nil
+}
+
+// indexBackup records the workspace content as it is in a backup with n incremental backups on top
+func indexBackup(sess *session.Workspace, n int) error {
+	files, err := indexFiles(sess.Location)
+	if err != nil {
+		return err
+	}
+	return (&backupIndex{IncrementalBackups: n, Files: files}).save(sess.ServiceLocDaemon)
+}
+
+func (s *WorkspaceService) maxIncrementalBackups() int {
+	if s.config.Backup.Incremental.MaxLength > 0 {
+		return s.config.Backup.Incremental.MaxLength
+	}
+	return defaultMaxIncrementalBackups
+}
+
+// lockBackup makes sure we take only one backup of a workspace at a time. Call the returned function to unlock.
+func (s *WorkspaceService) lockBackup(sess *session.Workspace) (unlock func()) {
+	mu, _ := s.backupLocks.LoadOrStore(sess.InstanceID, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// uploadIncrementalBackup uploads the changes of a workspace since its last backup
+func (s *WorkspaceService) uploadIncrementalBackup(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess, idx *backupIndex, files map[string]fileState) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "uploadIncrementalBackup")
+	span.SetTag("workspace", sess.WorkspaceID)
+	span.SetTag("instance", sess.InstanceID)
+	defer tracing.FinishSpan(span, &err)
+
+	changes := idx.changesSince(files)
+	span.SetTag("changed", len(changes.Changed))
+	span.SetTag("deleted", len(changes.Deleted))
+	if len(changes.Changed) == 0 && len(changes.Deleted) == 0 {
+		log.WithFields(sess.OWI()).Debug("workspace content did not change since the last backup")
+		return nil
+	}
+
+	name := fmt.Sprintf(storage.FmtIncrementalBackup, idx.IncrementalBackups+1)
+	span.SetTag("backup", name)
+
+	var tmpf *os.File
+	err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "create incremental archive"), func(ctx context.Context) (err error) {
+		tmpf, err = os.CreateTemp(s.config.TmpDir, fmt.Sprintf("wsbkp-%s-*.tar", sess.InstanceID))
+		if err != nil {
+			return
+		}
+		defer tmpf.Close()
+
+		opts := append([]archive.TarOption{archive.TarbalMaxSize(int64(s.config.WorkspaceSizeLimit))}, regularBackupMappings()...)
+		return BuildIncrementalTarbal(ctx, sess.Location, tmpf.Name(), &changes, opts...)
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot create incremental archive: %w", err)
+	}
+	defer os.Remove(tmpf.Name())
+
+	integrity, err := archiveIntegrity(tmpf.Name())
+	if err != nil {
+		log.WithError(err).WithFields(sess.OWI()).Warn("cannot compute backup integrity")
+	}
+
+	err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "upload incremental backup"), func(ctx context.Context) (err error) {
+		_, _, err = rs.Upload(ctx, tmpf.Name(), name)
+		if err != nil {
+			return
+		}
+
+		if s.config.Backup.Verify && integrity != nil {
+			err = verifyUpload(ctx, rs, name, integrity)
+		}
+		return
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot upload incremental backup: %w", err)
+	}
+	log.WithFields(sess.OWI()).WithField("backup", name).WithField("changed", len(changes.Changed)).WithField("deleted", len(changes.Deleted)).Debug("uploaded incremental backup")
+
+	if integrity != nil {
+		err = recordBackupIntegrity(sess, name, integrity, false)
+		if err != nil {
+			log.WithError(err).WithFields(sess.OWI()).Warn("cannot record backup integrity")
+		}
+	}
+
+	err = (&backupIndex{IncrementalBackups: idx.IncrementalBackups + 1, Files: files}).save(sess.ServiceLocDaemon)
+	if err != nil {
+		// Without the index we'd upload the next incremental backup under the same name - better delete this one
+		// and start over with a full backup.
+		os.Remove(filepath.Join(sess.ServiceLocDaemon, backupIndexFile))
+		return err
+	}
+	return nil
+}
+
+// deleteIncrementalBackups deletes all incremental backups of a workspace
+func (s *WorkspaceService) deleteIncrementalBackups(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess) error {
+	ps, err := storage.NewPresignedAccess(&s.config.Storage)
+	if err != nil {
+		return xerrors.Errorf("cannot delete incremental backups: %w", err)
+	}
+	err = ps.DeleteObject(ctx, rs.Bucket(sess.Owner), &storage.DeleteObjectQuery{Prefix: rs.BackupObject(storage.IncrementalBackupPrefix)})
+	if err != nil && err != storage.ErrNotFound {
+		return xerrors.Errorf("cannot delete incremental backups: %w", err)
+	}
+	return nil
+}
+
+// runLiveBackups takes an incremental backup of all running regular workspaces every period, so that
+// their final backup has little left to upload.
+func (s *WorkspaceService) runLiveBackups(ctx context.Context, period time.Duration) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		for _, sess := range s.store.List() {
+			if !sess.IsReady() || sess.IsDisposing() || sess.FullWorkspaceBackup || sess.PersistentVolumeClaim || sess.RemoteStorageDisabled {
+				continue
+			}
+
+			err := s.takeLiveBackup(ctx, sess)
+			if err != nil {
+				log.WithError(err).WithFields(sess.OWI()).Warn("live backup failed")
+			}
+		}
+	}
+}
+
+// takeLiveBackup uploads the changes of a running workspace since its last backup. Live backups are incremental only:
+// a full backup would have to remove the workspace ready file, and hence is left to the final backup.
+func (s *WorkspaceService) takeLiveBackup(ctx context.Context, sess *session.Workspace) error {
+	unlock := s.lockBackup(sess)
+	defer unlock()
+
+	rs, ok := sess.NonPersistentAttrs[session.AttrRemoteStorage].(storage.DirectAccess)
+	if rs == nil || !ok {
+		return xerrors.Errorf("no remote storage configured")
+	}
+	idx, err := loadBackupIndex(sess.ServiceLocDaemon)
+	if err != nil {
+		return err
+	}
+	if idx == nil || idx.IncrementalBackups >= s.maxIncrementalBackups() {
+		return nil
+	}
+
+	files, err := indexFiles(sess.Location)
+	if err != nil {
+		return err
+	}
+	return s.uploadIncrementalBackup(ctx, sess, rs, idx, files)
+}
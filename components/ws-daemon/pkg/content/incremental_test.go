@@ -0,0 +1,175 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+func TestBackupIndexChangesSince(t *testing.T) {
+	var (
+		file    = fileState{Mode: 0644, Size: 10, MTime: 100, CTime: 100}
+		changed = fileState{Mode: 0644, Size: 10, MTime: 100, CTime: 200}
+		dir     = fileState{Mode: os.ModeDir | 0755, MTime: 100, CTime: 100}
+	)
+	tests := []struct {
+		Name        string
+		Index       map[string]fileState
+		Files       map[string]fileState
+		Expectation BackupChanges
+	}{
+		{
+			Name:  "unchanged",
+			Index: map[string]fileState{"a.txt": file},
+			Files: map[string]fileState{"a.txt": file},
+		},
+		{
+			Name:        "new files",
+			Index:       map[string]fileState{"a.txt": file},
+			Files:       map[string]fileState{"a.txt": file, "dir": dir, "dir/b.txt": file},
+			Expectation: BackupChanges{Changed: []string{"dir", "dir/b.txt"}},
+		},
+		{
+			Name:        "changed file",
+			Index:       map[string]fileState{"a.txt": file, "b.txt": file},
+			Files:       map[string]fileState{"a.txt": changed, "b.txt": file},
+			Expectation: BackupChanges{Changed: []string{"a.txt"}},
+		},
+		{
+			Name:        "deleted directory",
+			Index:       map[string]fileState{"a.txt": file, "dir": dir, "dir/b.txt": file, "dir/sub": dir, "dir/sub/c.txt": file, "dir2": dir},
+			Files:       map[string]fileState{"a.txt": file, "dir2": dir},
+			Expectation: BackupChanges{Deleted: []string{"dir"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			idx := &backupIndex{Files: test.Index}
+			act := idx.changesSince(test.Files)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected changesSince (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBackupIndexRoundTrip(t *testing.T) {
+	loc := t.TempDir()
+	err := os.MkdirAll(filepath.Join(loc, ".gitpod"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"a.txt", ".gitpod/ready"} {
+		err = os.WriteFile(filepath.Join(loc, fn), []byte("hello"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := indexFiles(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	if diff := cmp.Diff([]string{".gitpod", "a.txt"}, paths); diff != "" {
+		t.Errorf("unexpected indexed files (-want +got):\n%s", diff)
+	}
+
+	serviceLoc := t.TempDir()
+	idx, err := loadBackupIndex(serviceLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != nil {
+		t.Fatalf("expected no backup index, got %v", idx)
+	}
+
+	err = (&backupIndex{IncrementalBackups: 2, Files: files}).save(serviceLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err = loadBackupIndex(serviceLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&backupIndex{IncrementalBackups: 2, Files: files}, idx); diff != "" {
+		t.Errorf("unexpected backup index (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildIncrementalTarbal(t *testing.T) {
+	src := t.TempDir()
+	err := os.MkdirAll(filepath.Join(src, "dir"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"a.txt", "dir/b.txt", "unchanged.txt"} {
+		err = os.WriteFile(filepath.Join(src, fn), []byte("hello"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := filepath.Join(t.TempDir(), "incremental.tar")
+	err = BuildIncrementalTarbal(context.Background(), src, dst, &BackupChanges{
+		Changed: []string{"a.txt", "dir", "dir/b.txt", "gone.txt"},
+		Deleted: []string{"old", "older.txt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var (
+		names     []string
+		deletions string
+	)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		if hdr.Name == "./"+storage.IncrementalBackupDeletions {
+			fc, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deletions = string(fc)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"./a.txt", "./dir/", "./dir/b.txt", "./" + storage.IncrementalBackupDeletions}, names); diff != "" {
+		t.Errorf("unexpected archive content (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("old\nolder.txt\n", deletions); diff != "" {
+		t.Errorf("unexpected deletions (-want +got):\n%s", diff)
+	}
+}
@@ -5,10 +5,13 @@
 package content
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -47,6 +50,9 @@ type RunInitializerOpts struct {
 	GID uint32
 
 	OWI OWI
+
+	// Progress is called whenever the content initialization makes progress
+	Progress func(wsinit.Progress)
 }
 
 type OWI struct {
@@ -75,6 +81,19 @@ func collectRemoteContent(ctx context.Context, rs storage.DirectAccess, ps stora
 		return nil, err
 	} else {
 		rc[storage.DefaultBackup] = *backup
+
+		// incremental backups apply on top of the default backup in order, hence we stop at the first gap
+		for i := 1; ; i++ {
+			name := fmt.Sprintf(storage.FmtIncrementalBackup, i)
+			info, err := ps.SignDownload(ctx, rs.Bucket(workspaceOwner), rs.BackupObject(name), &storage.SignedURLOptions{})
+			if err == storage.ErrNotFound {
+				break
+			}
+			if err != nil {
+				return nil, xerrors.Errorf("cannot find incremental backup %s: %w", name, err)
+			}
+			rc[name] = *info
+		}
 	}
 
 	if si := initializer.GetSnapshot(); si != nil {
@@ -165,6 +184,19 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 		return err
 	}
 
+	// The decryption keys of the remote content must not touch the disk, hence they are not part of content.json.
+	// The initializer reads them from its stdin instead.
+	keys := make(map[string][]byte)
+	for name, info := range remoteContent {
+		if info.DecryptionKey != nil {
+			keys[name] = info.DecryptionKey
+		}
+	}
+	keysMsg, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
 	spec := specconv.Example()
 
 	// we assemble the root filesystem from the ws-daemon container
@@ -240,7 +272,7 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 	}
 
 	args = append(args, "--log-format", "json", "run")
-	args = append(args, "--preserve-fds", "1")
+	args = append(args, "--preserve-fds", "2")
 	args = append(args, name)
 
 	errIn, errOut, err := os.Pipe()
@@ -253,16 +285,28 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 		errch <- errmsg
 	}()
 
+	progressIn, progressOut, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		defer progressIn.Close()
+		readInitializerProgress(progressIn, opts.Progress)
+	}()
+
 	var cmdOut bytes.Buffer
 	cmd := exec.Command("runc", args...)
 	cmd.Dir = tmpdir
 	cmd.Stdout = &cmdOut
 	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	cmd.ExtraFiles = []*os.File{errOut}
+	cmd.Stdin = bytes.NewReader(keysMsg)
+	cmd.ExtraFiles = []*os.File{errOut, progressOut}
 	err = cmd.Run()
 	log.FromBuffer(&cmdOut, log.WithFields(opts.OWI.Fields()))
 	errOut.Close()
+	progressOut.Close()
 
 	var errmsg []byte
 	select {
@@ -270,6 +314,11 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 	case <-time.After(1 * time.Second):
 		errmsg = []byte("failed to read content initializer response")
 	}
+	select {
+	case <-progressDone:
+	case <-time.After(1 * time.Second):
+		log.WithFields(opts.OWI.Fields()).Debug("failed to read content initializer progress")
+	}
 	if err != nil {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			// The program has exited with an exit code != 0. If it's FAIL_CONTENT_INITIALIZER_EXIT_CODE, it was deliberate.
@@ -285,6 +334,24 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 	return nil
 }
 
+// readInitializerProgress reads the progress the content initializer writes as JSON lines, until the initializer exits
+func readInitializerProgress(r io.Reader, report func(wsinit.Progress)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if report == nil {
+			continue
+		}
+
+		var p wsinit.Progress
+		err := json.Unmarshal(scanner.Bytes(), &p)
+		if err != nil {
+			log.WithError(err).Debug("cannot unmarshal content initializer progress")
+			continue
+		}
+		report(p)
+	}
+}
+
 // RunInitializerChild is the function that's expected to run when we call `/proc/self/exe content-initializer`
 func RunInitializerChild() (err error) {
 	fc, err := os.ReadFile("/content.json")
@@ -297,6 +364,20 @@ func RunInitializerChild() (err error) {
 	if err != nil {
 		return err
 	}
+
+	var keys map[string][]byte
+	err = json.NewDecoder(os.Stdin).Decode(&keys)
+	if err != nil {
+		return xerrors.Errorf("cannot read decryption keys: %w", err)
+	}
+	for name, key := range keys {
+		info, ok := initmsg.RemoteContent[name]
+		if !ok {
+			continue
+		}
+		info.DecryptionKey = key
+		initmsg.RemoteContent[name] = info
+	}
 	log.Log = logrus.WithFields(initmsg.OWI)
 
 	defer func() {
@@ -308,6 +389,7 @@ func RunInitializerChild() (err error) {
 	span := opentracing.StartSpan("RunInitializerChild", opentracing.ChildOf(tracing.FromTraceID(initmsg.TraceInfo)))
 	defer tracing.FinishSpan(span, &err)
 	ctx := opentracing.ContextWithSpan(context.Background(), span)
+	ctx = wsinit.WithProgressReporter(ctx, writeInitializerProgress(os.NewFile(uintptr(4), "progress")))
 
 	var req csapi.WorkspaceInitializer
 	err = proto.Unmarshal(initmsg.Initializer, &req)
@@ -348,6 +430,17 @@ func RunInitializerChild() (err error) {
 	return nil
 }
 
+// writeInitializerProgress reports the progress of the content initialization to the parent process
+func writeInitializerProgress(w io.Writer) wsinit.ProgressReporter {
+	enc := json.NewEncoder(w)
+	return func(p wsinit.Progress) {
+		err := enc.Encode(p)
+		if err != nil {
+			log.WithError(err).Debug("cannot report content initializer progress")
+		}
+	}
+}
+
 var _ storage.DirectAccess = &remoteContentStorage{}
 
 type remoteContentStorage struct {
@@ -377,7 +470,11 @@ func (rs *remoteContentStorage) Download(ctx context.Context, destination string
 	}
 	defer resp.Body.Close()
 
-	err = archive.ExtractTarbal(ctx, resp.Body, destination, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
+	body, err := storage.DecryptingReader(storage.DownloadProgressReader(ctx, resp.Body, info.Size), info.DecryptionKey)
+	if err != nil {
+		return true, err
+	}
+	err = archive.ExtractTarbal(ctx, body, destination, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
 	if err != nil {
 		return true, xerrors.Errorf("tar %s: %s", destination, err.Error())
 	}
@@ -410,6 +507,11 @@ func (rs *remoteContentStorage) UploadInstance(ctx context.Context, source strin
 	return "", "", xerrors.Errorf("not implemented")
 }
 
+// VerifyObject does nothing
+func (rs *remoteContentStorage) VerifyObject(ctx context.Context, name string, mf *storage.IntegrityManifest) ([]int, error) {
+	return nil, xerrors.Errorf("not implemented")
+}
+
 // Bucket returns an empty string
 func (rs *remoteContentStorage) Bucket(string) string {
 	return ""
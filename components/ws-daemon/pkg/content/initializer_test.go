@@ -0,0 +1,38 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+)
+
+func TestInitializerProgressRoundTrip(t *testing.T) {
+	progress := []wsinit.Progress{
+		{Phase: wsinit.ProgressBackup},
+		{Phase: wsinit.ProgressBackup, Percent: 50, BytesDone: 512, BytesTotal: 1024},
+		{Phase: wsinit.ProgressClone},
+		{Phase: wsinit.ProgressClone, Percent: 70},
+	}
+
+	var buf bytes.Buffer
+	report := writeInitializerProgress(&buf)
+	for _, p := range progress {
+		report(p)
+	}
+	// the parent must skip whatever it cannot understand
+	buf.WriteString("not json\n")
+
+	var act []wsinit.Progress
+	readInitializerProgress(&buf, func(p wsinit.Progress) { act = append(act, p) })
+
+	if diff := cmp.Diff(progress, act); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+// backupIntegrityFile is the file in the workspace's daemon service location which stores the integrity manifests of its backup
+const backupIntegrityFile = "backup-integrity.json"
+
+// backupIntegrity maps the names of the objects which make up the current backup of a workspace, i.e. a complete
+// backup and possibly incremental backups on top, to the integrity manifests of the archives we uploaded.
+type backupIntegrity map[string]*storage.IntegrityManifest
+
+// archiveIntegrity computes the integrity manifest of an archive
+func archiveIntegrity(fn string) (*storage.IntegrityManifest, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot compute archive integrity: %w", err)
+	}
+	defer f.Close()
+
+	return storage.NewIntegrityManifest(f, storage.DefaultIntegrityChunkSize)
+}
+
+// verifyUpload downloads an uploaded archive and fails if it does not match its integrity manifest
+func verifyUpload(ctx context.Context, rs storage.DirectAccess, name string, mf *storage.IntegrityManifest) error {
+	corrupted, err := rs.VerifyObject(ctx, name, mf)
+	if err != nil {
+		return xerrors.Errorf("cannot verify upload of %s: %w", name, err)
+	}
+	if len(corrupted) > 0 {
+		return xerrors.Errorf("upload of %s is corrupted in chunks %v", name, corrupted)
+	}
+	return nil
+}
+
+// loadBackupIntegrity loads the backup integrity from the service location. Returns an empty backup integrity if there is none.
+func loadBackupIntegrity(serviceLoc string) (backupIntegrity, error) {
+	fc, err := os.ReadFile(filepath.Join(serviceLoc, backupIntegrityFile))
+	if os.IsNotExist(err) {
+		return make(backupIntegrity), nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read backup integrity: %w", err)
+	}
+
+	res := make(backupIntegrity)
+	err = json.Unmarshal(fc, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal backup integrity: %w", err)
+	}
+	return res, nil
+}
+
+// save atomically stores the backup integrity in the service location
+func (bi backupIntegrity) save(serviceLoc string) error {
+	fc, err := json.Marshal(bi)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal backup integrity: %w", err)
+	}
+
+	tmpf := filepath.Join(serviceLoc, backupIntegrityFile+".tmp")
+	err = os.WriteFile(tmpf, fc, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write backup integrity: %w", err)
+	}
+	err = os.Rename(tmpf, filepath.Join(serviceLoc, backupIntegrityFile))
+	if err != nil {
+		return xerrors.Errorf("cannot write backup integrity: %w", err)
+	}
+	return nil
+}
+
+// recordBackupIntegrity records the integrity manifest of an uploaded backup archive. A complete backup
+// supersedes everything recorded before, an incremental one adds to it.
+func recordBackupIntegrity(sess *session.Workspace, name string, mf *storage.IntegrityManifest, complete bool) error {
+	bi := make(backupIntegrity)
+	if !complete {
+		var err error
+		bi, err = loadBackupIntegrity(sess.ServiceLocDaemon)
+		if err != nil {
+			return err
+		}
+	}
+	bi[name] = mf
+	return bi.save(sess.ServiceLocDaemon)
+}
+
+// verifyBackup checks all objects which make up the current backup of a workspace against their integrity manifests,
+// and returns the names of those which are corrupted.
+func (s *WorkspaceService) verifyBackup(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess) (corrupted []string, err error) {
+	unlock := s.lockBackup(sess)
+	defer unlock()
+
+	bi, err := loadBackupIntegrity(sess.ServiceLocDaemon)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, mf := range bi {
+		chunks, err := rs.VerifyObject(ctx, name, mf)
+		if err == storage.ErrNotFound {
+			log.WithFields(sess.OWI()).WithField("backup", name).Warn("backup is missing")
+			corrupted = append(corrupted, name)
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot verify %s: %w", name, err)
+		}
+		if len(chunks) > 0 {
+			log.WithFields(sess.OWI()).WithField("backup", name).WithField("chunks", chunks).Warn("backup is corrupted")
+			corrupted = append(corrupted, name)
+		}
+	}
+	sort.Strings(corrupted)
+
+	return corrupted, nil
+}
+
+// RepairBackup verifies the current backup of a workspace against the integrity manifests recorded when it was uploaded.
+// Object storage cannot rewrite parts of an object, and incremental backups cannot be rebuilt once the workspace content
+// moved on. Hence, we repair a corrupted backup by uploading a complete backup of the workspace content which supersedes it.
+func (s *WorkspaceService) RepairBackup(ctx context.Context, req *api.RepairBackupRequest) (res *api.RepairBackupResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RepairBackup")
+	span.SetTag("workspace", req.Id)
+	span.SetTag("dryRun", req.DryRun)
+	defer tracing.FinishSpan(span, &err)
+
+	sess := s.store.Get(req.Id)
+	if sess == nil {
+		return nil, status.Error(codes.NotFound, "workspace does not exist")
+	}
+	if sess.RemoteStorageDisabled {
+		return nil, status.Error(codes.FailedPrecondition, "workspace has no remote storage")
+	}
+	if sess.PersistentVolumeClaim {
+		return nil, status.Error(codes.FailedPrecondition, "workspace content lives on a persistent volume claim and is snapshotted by ws-manager")
+	}
+	rs, ok := sess.NonPersistentAttrs[session.AttrRemoteStorage].(storage.DirectAccess)
+	if rs == nil || !ok {
+		log.WithFields(sess.OWI()).Error("cannot repair backup: no remote storage configured")
+		return nil, status.Error(codes.Internal, "workspace has no remote storage")
+	}
+
+	corrupted, err := s.verifyBackup(ctx, sess, rs)
+	if err != nil {
+		log.WithError(err).WithFields(sess.OWI()).Error("cannot verify backup")
+		return nil, status.Error(codes.Internal, "cannot verify backup")
+	}
+	res = &api.RepairBackupResponse{CorruptedBackups: corrupted}
+	if len(corrupted) == 0 || req.DryRun {
+		return res, nil
+	}
+	if !sess.IsReady() {
+		return nil, status.Error(codes.FailedPrecondition, "workspace content is not ready")
+	}
+
+	// without a backup index the next backup is a complete one
+	err = os.Remove(filepath.Join(sess.ServiceLocDaemon, backupIndexFile))
+	if err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithFields(sess.OWI()).Error("cannot remove backup index")
+		return nil, status.Error(codes.Internal, "cannot repair backup")
+	}
+
+	backupName := storage.DefaultBackup
+	if sess.FullWorkspaceBackup {
+		backupName = fmt.Sprintf(storage.FmtFullWorkspaceBackup, time.Now().UnixNano())
+	}
+	err = s.uploadWorkspaceContent(ctx, sess, backupName, storage.DefaultBackupManifest)
+	if err != nil {
+		log.WithError(err).WithFields(sess.OWI()).Error("backup repair failed")
+		return nil, status.Error(codes.DataLoss, "backup repair failed")
+	}
+	log.WithFields(sess.OWI()).WithField("corrupted", corrupted).Info("repaired backup")
+
+	res.Repaired = true
+	return res, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+func TestRecordBackupIntegrity(t *testing.T) {
+	type Record struct {
+		Name     string
+		Complete bool
+	}
+	tests := []struct {
+		Name        string
+		Records     []Record
+		Expectation []string
+	}{
+		{
+			Name:        "complete backup",
+			Records:     []Record{{Name: storage.DefaultBackup, Complete: true}},
+			Expectation: []string{storage.DefaultBackup},
+		},
+		{
+			Name: "incremental backups",
+			Records: []Record{
+				{Name: storage.DefaultBackup, Complete: true},
+				{Name: "incremental-001.tar"},
+				{Name: "incremental-002.tar"},
+			},
+			Expectation: []string{storage.DefaultBackup, "incremental-001.tar", "incremental-002.tar"},
+		},
+		{
+			Name: "complete backup supersedes incremental ones",
+			Records: []Record{
+				{Name: storage.DefaultBackup, Complete: true},
+				{Name: "incremental-001.tar"},
+				{Name: storage.DefaultBackup, Complete: true},
+			},
+			Expectation: []string{storage.DefaultBackup},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sess := &session.Workspace{ServiceLocDaemon: t.TempDir()}
+			for i, r := range test.Records {
+				err := recordBackupIntegrity(sess, r.Name, &storage.IntegrityManifest{Size: int64(i)}, r.Complete)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			bi, err := loadBackupIntegrity(sess.ServiceLocDaemon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act := make([]string, 0, len(bi))
+			for name := range bi {
+				act = append(act, name)
+			}
+			sort.Strings(act)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected backup integrity (-want +got):\n%s", diff)
+			}
+			if last := test.Records[len(test.Records)-1]; bi[last.Name].Size != int64(len(test.Records)-1) {
+				t.Errorf("the latest record of %s did not replace earlier ones", last.Name)
+			}
+		})
+	}
+}
@@ -12,6 +12,9 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -47,6 +50,9 @@ type WorkspaceService struct {
 	stopService context.CancelFunc
 	runtime     container.Runtime
 
+	// backupLocks serializes the backups of a workspace, keyed by instance ID
+	backupLocks sync.Map
+
 	api.UnimplementedInWorkspaceServiceServer
 	api.UnimplementedWorkspaceContentServiceServer
 }
@@ -115,6 +121,9 @@ func registerWorkingAreaDiskspaceGauge(workingArea string, reg prometheus.Regist
 // Start starts this workspace service and returns when the service gets stopped.
 // This function is intended to run as Go routine.
 func (s *WorkspaceService) Start() {
+	if s.config.Backup.Incremental.Enabled && s.config.Backup.Period > 0 {
+		go s.runLiveBackups(s.ctx, time.Duration(s.config.Backup.Period))
+	}
 	s.store.StartHousekeeping(s.ctx, 5*time.Minute)
 }
 
@@ -151,12 +160,17 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 	log.Debug("InitWorkspace called")
 
 	var (
-		wsloc string
+		wsloc   string
+		initloc string
 	)
 	if req.FullWorkspaceBackup {
 		if s.runtime == nil {
 			return nil, status.Errorf(codes.FailedPrecondition, "full workspace backup is not available - not connected to container runtime")
 		}
+		if s.config.Storage.Encryption != nil {
+			// registry-facade serves the layers of full workspace backups as they are, hence they cannot be encrypted
+			return nil, status.Errorf(codes.FailedPrecondition, "full workspace backup is not available with encrypted storage")
+		}
 		var mf csapi.WorkspaceContentManifest
 		if len(req.ContentManifest) == 0 {
 			return nil, status.Errorf(codes.InvalidArgument, "content manifest is required")
@@ -165,8 +179,20 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid content manifest: %s", err.Error())
 		}
+	} else if req.PersistentVolumeClaim {
+		if s.runtime == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "persistent volume claims are not available - not connected to container runtime")
+		}
+		// The workspace content lives on the persistent volume claim, not on the node. Hence the session has no location
+		// and we initialize the content through the workspace container's mount namespace.
+		initloc, err = s.persistentVolumeClaimLocation(ctx, req.Id)
+		if err != nil {
+			log.WithError(err).Error("cannot find persistent volume claim of workspace")
+			return nil, status.Error(codes.FailedPrecondition, "cannot find persistent volume claim of workspace")
+		}
 	} else {
 		wsloc = filepath.Join(s.store.Location, req.Id)
+		initloc = wsloc
 	}
 
 	workspace, err := s.store.NewWorkspace(ctx, req.Id, wsloc, s.creator(req))
@@ -203,6 +229,18 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 				log.WithError(err).Error("cannot collect remote content")
 				return nil, status.Error(codes.Internal, "remote content error")
 			}
+
+			// the initializer downloads the remote content without access to the KMS, hence we decrypt the keys of encrypted objects for it
+			enc, err := storage.NewEnvelopeEncryption(s.config.Storage.Encryption)
+			if err != nil {
+				log.WithError(err).Error("cannot configure encryption")
+				return nil, status.Error(codes.Internal, "remote content error")
+			}
+			err = enc.DecryptKeys(ctx, remoteContent)
+			if err != nil {
+				log.WithError(err).Error("cannot decrypt keys of remote content")
+				return nil, status.Error(codes.Internal, "remote content error")
+			}
 		}
 
 		// This task/call cannot be canceled. Once it's started it's brought to a conclusion, independent of the caller disconnecting
@@ -230,13 +268,28 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 				WorkspaceID: req.Metadata.MetaId,
 				InstanceID:  req.Id,
 			},
+			Progress: workspace.SetInitProgress,
 		}
 
-		err = RunInitializer(ctx, workspace.Location, req.Initializer, remoteContent, opts)
+		err = RunInitializer(ctx, initloc, req.Initializer, remoteContent, opts)
 		if err != nil {
 			log.WithError(err).WithField("workspaceId", req.Id).Error("cannot initialize workspace")
 			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
+
+		if _, restored := remoteContent[storage.DefaultBackup]; restored && s.config.Backup.Incremental.Enabled && !req.PersistentVolumeClaim {
+			// The next backup can be incremental to the one we've just restored
+			var n int
+			for name := range remoteContent {
+				if strings.HasPrefix(name, storage.IncrementalBackupPrefix) {
+					n++
+				}
+			}
+			err = indexBackup(workspace, n)
+			if err != nil {
+				log.WithError(err).Warn("cannot index restored backup - next backup will be a full one")
+			}
+		}
 	}
 
 	// Tell the world we're done
@@ -261,6 +314,9 @@ func (s *WorkspaceService) creator(req *api.InitWorkspaceRequest) session.Worksp
 			FullWorkspaceBackup:   req.FullWorkspaceBackup,
 			ContentManifest:       req.ContentManifest,
 			RemoteStorageDisabled: req.RemoteStorageDisabled,
+			PersistentVolumeClaim: req.PersistentVolumeClaim,
+			StorageQuota:          req.StorageQuota,
+			SeccompProfile:        req.SeccompProfile,
 
 			ServiceLocDaemon: filepath.Join(s.config.WorkingArea, ServiceDirName(req.Id)),
 			ServiceLocNode:   filepath.Join(s.config.WorkingAreaNode, ServiceDirName(req.Id)),
@@ -268,6 +324,20 @@ func (s *WorkspaceService) creator(req *api.InitWorkspaceRequest) session.Worksp
 	}
 }
 
+// persistentVolumeClaimLocation finds the /workspace mount of a workspace container, which is where
+// its persistent volume claim is mounted. The location is only valid for as long as the container runs.
+func (s *WorkspaceService) persistentVolumeClaimLocation(ctx context.Context, instanceID string) (string, error) {
+	id, err := s.runtime.WaitForContainer(ctx, instanceID)
+	if err != nil {
+		return "", xerrors.Errorf("cannot find workspace container: %w", err)
+	}
+	pid, err := s.runtime.ContainerPID(ctx, id)
+	if err != nil {
+		return "", xerrors.Errorf("cannot find workspace container PID: %w", err)
+	}
+	return filepath.Join("/proc", strconv.FormatUint(pid, 10), "root", "workspace"), nil
+}
+
 // ServiceDirName produces the directory name for a workspace
 func ServiceDirName(instanceID string) string {
 	return instanceID + "-daemon"
@@ -344,6 +414,9 @@ func (s *WorkspaceService) DisposeWorkspace(ctx context.Context, req *api.Dispos
 		if sess.RemoteStorageDisabled {
 			return nil, status.Errorf(codes.FailedPrecondition, "workspace has no remote storage")
 		}
+		if sess.PersistentVolumeClaim {
+			return nil, status.Errorf(codes.FailedPrecondition, "workspace content lives on a persistent volume claim and is snapshotted by ws-manager")
+		}
 
 		var (
 			backupName = storage.DefaultBackup
@@ -377,6 +450,7 @@ func (s *WorkspaceService) DisposeWorkspace(ctx context.Context, req *api.Dispos
 		span.LogKV("error", err.Error())
 		return nil, status.Error(codes.Internal, "cannot delete workspace from store")
 	}
+	s.backupLocks.Delete(req.Id)
 
 	// remove workspace daemon directory in the node
 	if err := os.RemoveAll(sess.ServiceLocDaemon); err != nil {
@@ -396,29 +470,42 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 	span.SetTag("full", sess.FullWorkspaceBackup)
 	defer tracing.FinishSpan(span, &err)
 
+	unlock := s.lockBackup(sess)
+	defer unlock()
+
+	err = os.Remove(filepath.Join(sess.Location, wsinit.WorkspaceReadyFile))
+	if err != nil && !os.IsNotExist(err) {
+		// We'll still upload the backup, well aware that the UX during restart will be broken.
+		// But it's better to have a backup with all files (albeit one too many), than having no backup at all.
+		log.WithError(err).WithFields(sess.OWI()).Warn("cannot remove workspace ready file")
+	}
+
+	return s.uploadContent(ctx, sess, contentLocation(sess), backupName, mfName)
+}
+
+// contentLocation returns the location of the workspace content we back up
+func contentLocation(sess *session.Workspace) string {
+	if sess.FullWorkspaceBackup {
+		// Backup any change located in the upper overlay directory of the workspace in the node
+		return filepath.Join(sess.ServiceLocDaemon, "upper")
+	}
+	return sess.Location
+}
+
+// uploadContent archives the workspace content found at loc and uploads it. Callers must hold the backup lock of the workspace.
+func (s *WorkspaceService) uploadContent(ctx context.Context, sess *session.Workspace, loc, backupName, mfName string) (err error) {
 	var (
-		loc  = sess.Location
 		opts []storage.UploadOption
 		mf   csapi.WorkspaceContentManifest
 	)
 
 	if sess.FullWorkspaceBackup {
-		// Backup any change located in the upper overlay directory of the workspace in the node
-		loc = filepath.Join(sess.ServiceLocDaemon, "upper")
-
 		err = json.Unmarshal(sess.ContentManifest, &mf)
 		if err != nil {
 			return xerrors.Errorf("cannot unmarshal original content manifest: %w", err)
 		}
 	}
 
-	err = os.Remove(filepath.Join(sess.Location, wsinit.WorkspaceReadyFile))
-	if err != nil && !os.IsNotExist(err) {
-		// We'll still upload the backup, well aware that the UX during restart will be broken.
-		// But it's better to have a backup with all files (albeit one too many), than having no backup at all.
-		log.WithError(err).WithFields(sess.OWI()).Warn("cannot remove workspace ready file")
-	}
-
 	if s.config.Storage.BackupTrail.Enabled && !sess.FullWorkspaceBackup {
 		opts = append(opts, storage.WithBackupTrail("trail", s.config.Storage.BackupTrail.MaxLength))
 	}
@@ -428,6 +515,24 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		return xerrors.Errorf("no remote storage configured")
 	}
 
+	// Incremental backups apply to the default backup of regular workspaces only. Snapshots are always complete.
+	var files map[string]fileState
+	if s.config.Backup.Incremental.Enabled && !sess.FullWorkspaceBackup && backupName == storage.DefaultBackup {
+		// We index the files before archiving them. Changes which happen in between end up in both, this and the next backup.
+		files, err = indexFiles(loc)
+		if err != nil {
+			return err
+		}
+
+		idx, err := loadBackupIndex(sess.ServiceLocDaemon)
+		if err != nil {
+			log.WithError(err).WithFields(sess.OWI()).Warn("cannot load backup index - taking a full backup")
+		}
+		if idx != nil && idx.IncrementalBackups < s.maxIncrementalBackups() {
+			return s.uploadIncrementalBackup(ctx, sess, rs, idx, files)
+		}
+	}
+
 	var (
 		tmpf       *os.File
 		tmpfSize   int64
@@ -440,17 +545,9 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		}
 		defer tmpf.Close()
 
-		var opts []archive.TarOption
-		opts = append(opts, archive.TarbalMaxSize(int64(s.config.WorkspaceSizeLimit)))
+		opts := []archive.TarOption{archive.TarbalMaxSize(int64(s.config.WorkspaceSizeLimit))}
 		if !sess.FullWorkspaceBackup {
-			mappings := []archive.IDMapping{
-				{ContainerID: 0, HostID: wsinit.GitpodUID, Size: 1},
-				{ContainerID: 1, HostID: 100000, Size: 65534},
-			}
-			opts = append(opts,
-				archive.WithUIDMapping(mappings),
-				archive.WithGIDMapping(mappings),
-			)
+			opts = append(opts, regularBackupMappings()...)
 		}
 
 		err = BuildTarbal(ctx, loc, tmpf.Name(), sess.FullWorkspaceBackup, opts...)
@@ -489,6 +586,12 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		}
 	}()
 
+	integrity, err := archiveIntegrity(tmpf.Name())
+	if err != nil {
+		// us being unable to checksum the archive is not enough of a reason to fail the backup altogether
+		log.WithError(err).WithFields(sess.OWI()).Warn("cannot compute backup integrity")
+	}
+
 	var (
 		layerBucket string
 		layerObject string
@@ -511,12 +614,42 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 			return
 		}
 
+		if s.config.Backup.Verify && integrity != nil {
+			err = verifyUpload(ctx, rs, backupName, integrity)
+		}
 		return
 	})
 	if err != nil {
 		return xerrors.Errorf("cannot upload workspace content: %w", err)
 	}
 
+	// Snapshots are not part of the workspace's backup, hence we don't repair them
+	if integrity != nil && mfName == storage.DefaultBackupManifest {
+		err = recordBackupIntegrity(sess, backupName, integrity, true)
+		if err != nil {
+			log.WithError(err).WithFields(sess.OWI()).Warn("cannot record backup integrity")
+		}
+	}
+
+	if files != nil {
+		// A full backup supersedes all incremental backups. We delete them only once the full backup made it,
+		// lest a failed upload leaves us with neither. Until they're gone they'd be restored on top of the new
+		// backup, hence we must not report success before.
+		err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "delete incremental backups"), func(ctx context.Context) error {
+			return s.deleteIncrementalBackups(ctx, sess, rs)
+		})
+		if err != nil {
+			// the next backup must be a full one again, which retries the deletion
+			os.Remove(filepath.Join(sess.ServiceLocDaemon, backupIndexFile))
+			return err
+		}
+
+		err = (&backupIndex{Files: files}).save(sess.ServiceLocDaemon)
+		if err != nil {
+			log.WithError(err).WithFields(sess.OWI()).Warn("cannot save backup index - next backup will be a full one")
+		}
+	}
+
 	err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "upload manifest"), func(ctx context.Context) (err error) {
 		if !sess.FullWorkspaceBackup {
 			return
@@ -572,6 +705,18 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 	return nil
 }
 
+// regularBackupMappings maps the IDs of the workspace content on the node to the IDs in the workspace container
+func regularBackupMappings() []archive.TarOption {
+	mappings := []archive.IDMapping{
+		{ContainerID: 0, HostID: wsinit.GitpodUID, Size: 1},
+		{ContainerID: 1, HostID: 100000, Size: 65534},
+	}
+	return []archive.TarOption{
+		archive.WithUIDMapping(mappings),
+		archive.WithGIDMapping(mappings),
+	}
+}
+
 func (s *WorkspaceService) uploadWorkspaceLogs(ctx context.Context, sess *session.Workspace) (err error) {
 	rs, ok := sess.NonPersistentAttrs[session.AttrRemoteStorage].(storage.DirectAccess)
 	if rs == nil || !ok {
@@ -688,6 +833,9 @@ func (s *WorkspaceService) TakeSnapshot(ctx context.Context, req *api.TakeSnapsh
 	if sess.RemoteStorageDisabled {
 		return nil, status.Error(codes.FailedPrecondition, "workspace has no remote storage")
 	}
+	if sess.PersistentVolumeClaim {
+		return nil, status.Error(codes.FailedPrecondition, "workspace content lives on a persistent volume claim and is snapshotted by ws-manager")
+	}
 	rs, ok := sess.NonPersistentAttrs[session.AttrRemoteStorage].(storage.DirectAccess)
 	if rs == nil || !ok {
 		log.WithFields(sess.OWI()).WithError(err).Error("cannot upload snapshot: no remote storage configured")
@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+// CreateSnapshot creates a snapshot of a workspace while it keeps running. We clone the workspace content first and
+// upload the clone, so that neither the user nor later backups have to wait for the upload.
+func (s *WorkspaceService) CreateSnapshot(ctx context.Context, req *api.CreateSnapshotRequest) (res *api.CreateSnapshotResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "CreateSnapshot")
+	span.SetTag("workspace", req.Id)
+	defer tracing.FinishSpan(span, &err)
+
+	sess := s.store.Get(req.Id)
+	if sess == nil {
+		return nil, status.Error(codes.NotFound, "workspace does not exist")
+	}
+	if !sess.IsReady() {
+		return nil, status.Error(codes.FailedPrecondition, "workspace is not ready")
+	}
+	if sess.RemoteStorageDisabled {
+		return nil, status.Error(codes.FailedPrecondition, "workspace has no remote storage")
+	}
+	if sess.PersistentVolumeClaim {
+		return nil, status.Error(codes.FailedPrecondition, "workspace content lives on a persistent volume claim and is snapshotted by ws-manager")
+	}
+	rs, ok := sess.NonPersistentAttrs[session.AttrRemoteStorage].(storage.DirectAccess)
+	if rs == nil || !ok {
+		log.WithFields(sess.OWI()).Error("cannot upload snapshot: no remote storage configured")
+		return nil, status.Error(codes.Internal, "workspace has no remote storage")
+	}
+
+	var (
+		baseName     = fmt.Sprintf("snapshot-%d", time.Now().UnixNano())
+		backupName   = baseName + ".tar"
+		mfName       = baseName + ".mf.json"
+		snapshotName string
+	)
+	if sess.FullWorkspaceBackup {
+		snapshotName = rs.Qualify(mfName)
+	} else {
+		snapshotName = rs.Qualify(backupName)
+	}
+
+	clone, err := cloneWorkspaceContent(ctx, contentLocation(sess), filepath.Join(s.config.WorkingArea, sess.InstanceID+"-snapshot-*"))
+	if err != nil {
+		log.WithError(err).WithFields(sess.OWI()).Error("cannot clone workspace content")
+		return nil, status.Error(codes.Internal, "cannot clone workspace content")
+	}
+
+	upload := func(ctx context.Context) error {
+		defer os.RemoveAll(clone)
+
+		unlock := s.lockBackup(sess)
+		defer unlock()
+
+		return s.uploadContent(ctx, sess, clone, backupName, mfName)
+	}
+	if req.ReturnImmediately {
+		go func() {
+			err := upload(context.Background())
+			if err != nil {
+				log.WithError(err).WithField("workspaceId", req.Id).Error("snapshot upload failed")
+			}
+		}()
+	} else {
+		err = upload(ctx)
+		if err != nil {
+			log.WithError(err).WithField("workspaceId", req.Id).Error("snapshot upload failed")
+			return nil, status.Error(codes.Internal, "cannot upload snapshot")
+		}
+	}
+
+	return &api.CreateSnapshotResponse{
+		Url: snapshotName,
+	}, nil
+}
+
+// cloneWorkspaceContent copies the workspace content at src into a new directory matching the pattern. Where the filesystem
+// supports it, the copy shares its data blocks with the workspace content and takes hardly any time or space.
+// The clone never contains the workspace ready file, just like the archives of uploadWorkspaceContent.
+func cloneWorkspaceContent(ctx context.Context, src, pattern string) (clone string, err error) {
+	clone, err = os.MkdirTemp(filepath.Dir(pattern), filepath.Base(pattern))
+	if err != nil {
+		return "", xerrors.Errorf("cannot create clone directory: %w", err)
+	}
+
+	out, err := exec.CommandContext(ctx, "cp", "--archive", "--reflink=auto", src+"/.", clone).CombinedOutput()
+	if err != nil {
+		os.RemoveAll(clone)
+		return "", xerrors.Errorf("cannot clone %s: %w: %s", src, err, string(out))
+	}
+
+	err = os.Remove(filepath.Join(clone, wsinit.WorkspaceReadyFile))
+	if err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(clone)
+		return "", xerrors.Errorf("cannot remove workspace ready file from clone: %w", err)
+	}
+
+	return clone, nil
+}
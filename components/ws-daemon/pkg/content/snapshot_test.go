@@ -0,0 +1,75 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+)
+
+func TestCloneWorkspaceContent(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"README.md":               "hello world",
+		"src/main.go":             "package main",
+		wsinit.WorkspaceReadyFile: "{}",
+	}
+	for name, content := range files {
+		fn := filepath.Join(src, name)
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clone, err := cloneWorkspaceContent(context.Background(), src, filepath.Join(t.TempDir(), "ws-snapshot-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// changes to the workspace content after cloning must not show up in the clone
+	err = os.WriteFile(filepath.Join(src, "README.md"), []byte("changed"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act := make(map[string]string)
+	err = filepath.Walk(clone, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		fc, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(clone, path)
+		act[rel] = string(fc)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]string{
+		"README.md":   "hello world",
+		"src/main.go": "package main",
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected clone (-want +got):\n%s", diff)
+	}
+
+	if _, err := os.Stat(filepath.Join(src, wsinit.WorkspaceReadyFile)); err != nil {
+		t.Errorf("cloning removed the ready file of the workspace content: %v", err)
+	}
+}
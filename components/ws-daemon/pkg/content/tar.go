@@ -27,18 +27,23 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/pools"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 )
 
 // TarOptions wraps the tar options.
 type TarOptions struct {
 	UIDMaps []idtools.IDMap
 	GIDMaps []idtools.IDMap
+
+	// Changes restricts the archive to the changes since a previous backup. If nil, the archive contains the whole source.
+	Changes *BackupChanges
 }
 
 // tarWithOptions creates an archive from the directory at `path`
@@ -71,6 +76,11 @@ func TarWithOptions(srcPath string, options *TarOptions) (io.ReadCloser, error)
 		// this buffer is needed for the duration of this piped stream
 		defer pools.BufioWriter32KPool.Put(ta.Buffer)
 
+		if options.Changes != nil {
+			ta.addChanges(srcPath, options.Changes)
+			return
+		}
+
 		seen := make(map[string]bool)
 
 		_ = filepath.Walk(srcPath, func(filePath string, f os.FileInfo, err error) error {
@@ -225,6 +235,42 @@ func (ta *tarAppender) addTarFile(path, name string) error {
 	return nil
 }
 
+// addChanges adds the changed files and the list of deleted files to the tar archive
+func (ta *tarAppender) addChanges(srcPath string, changes *BackupChanges) {
+	for _, p := range changes.Changed {
+		err := ta.addTarFile(filepath.Join(srcPath, p), "./"+p)
+		if os.IsNotExist(err) {
+			// the file was deleted after we found it changed - the next backup will list it as deleted
+			continue
+		}
+		if err != nil {
+			log.Errorf("Can't add file %s to tar: %s", p, err)
+			// if pipe is broken, stop writing tar stream to it
+			if err == io.ErrClosedPipe {
+				return
+			}
+		}
+	}
+
+	if len(changes.Deleted) == 0 {
+		return
+	}
+	deletions := []byte(strings.Join(changes.Deleted, "\n") + "\n")
+	err := ta.TarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     "./" + storage.IncrementalBackupDeletions,
+		Mode:     0644,
+		Size:     int64(len(deletions)),
+		ModTime:  time.Now(),
+	})
+	if err == nil {
+		_, err = ta.TarWriter.Write(deletions)
+	}
+	if err != nil {
+		log.Errorf("Can't add deletions to tar: %s", err)
+	}
+}
+
 func hasHardlinks(fi os.FileInfo) bool {
 	return fi.Sys().(*syscall.Stat_t).Nlink > 1
 }
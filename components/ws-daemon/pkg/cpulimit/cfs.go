@@ -16,7 +16,25 @@ import (
 	"golang.org/x/xerrors"
 )
 
-// CgroupCFSController controls a cgroup's CFS settings
+// CFSController controls the CPU bandwidth of a cgroup
+type CFSController interface {
+	// Usage returns the total CPU time the cgroup has consumed
+	Usage() (usage CPUTime, err error)
+	// SetLimit sets a new CPU bandwidth limit on the cgroup. Returns true if the limit changed.
+	SetLimit(limit Bandwidth) (changed bool, err error)
+	// NrThrottled returns the number of CFS periods the cgroup was throttled in
+	NrThrottled() (uint64, error)
+}
+
+// NewCFSController produces the CFS controller of a container's cgroup, depending on the cgroup hierarchy of the node
+func NewCFSController(basePath, cgroupPath string, unified bool) CFSController {
+	if unified {
+		return CgroupV2CFSController(filepath.Join(basePath, cgroupPath))
+	}
+	return CgroupCFSController(filepath.Join(basePath, "cpu", cgroupPath))
+}
+
+// CgroupCFSController controls a cgroup's CFS settings on a cgroup v1 hierarchy
 type CgroupCFSController string
 
 // Usage returns the cpuacct.usage value of the cgroup
@@ -78,26 +96,31 @@ func (basePath CgroupCFSController) readUint64(path string) (uint64, error) {
 
 // NrThrottled returns the number of CFS periods the cgroup was throttled in
 func (basePath CgroupCFSController) NrThrottled() (uint64, error) {
-	f, err := os.Open(filepath.Join(string(basePath), "cpu.stat"))
+	return readCPUStat(string(basePath), "nr_throttled")
+}
+
+// readCPUStat reads a single value from the cpu.stat file of a cgroup
+func readCPUStat(basePath, key string) (uint64, error) {
+	f, err := os.Open(filepath.Join(basePath, "cpu.stat"))
 	if err != nil {
 		return 0, xerrors.Errorf("cannot read cpu.stat: %w", err)
 	}
 	defer f.Close()
 
-	const prefixNrThrottled = "nr_throttled "
+	prefix := key + " "
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		l := scanner.Text()
-		if !strings.HasPrefix(l, prefixNrThrottled) {
+		if !strings.HasPrefix(l, prefix) {
 			continue
 		}
 
-		r, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(l, prefixNrThrottled)), 10, 64)
+		r, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(l, prefix)), 10, 64)
 		if err != nil {
 			return 0, xerrors.Errorf("cannot parse cpu.stat: %s: %w", l, err)
 		}
 		return uint64(r), nil
 	}
-	return 0, xerrors.Errorf("cpu.stat did not contain nr_throttled")
+	return 0, xerrors.Errorf("cpu.stat did not contain %s", key)
 }
@@ -0,0 +1,84 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cpulimit
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CgroupV2CFSController controls a cgroup's CFS settings on the unified cgroup v2 hierarchy
+type CgroupV2CFSController string
+
+// Usage returns the usage_usec value of the cgroup's cpu.stat
+func (basePath CgroupV2CFSController) Usage() (usage CPUTime, err error) {
+	cpuTimeInUS, err := readCPUStat(string(basePath), "usage_usec")
+	if err != nil {
+		return 0, xerrors.Errorf("cannot read usage_usec: %w", err)
+	}
+
+	return CPUTime(time.Duration(cpuTimeInUS) * time.Microsecond), nil
+}
+
+// SetLimit sets a new CFS quota on the cgroup
+func (basePath CgroupV2CFSController) SetLimit(limit Bandwidth) (changed bool, err error) {
+	quota, period, err := basePath.readCPUMax()
+	if err != nil {
+		return false, xerrors.Errorf("cannot parse cpu.max: %w", err)
+	}
+
+	target := limit.Quota(period)
+	if quota == target {
+		return false, nil
+	}
+
+	err = os.WriteFile(filepath.Join(string(basePath), "cpu.max"), []byte(fmt.Sprintf("%d %d", target.Microseconds(), period.Microseconds())), 0644)
+	if err != nil {
+		return false, xerrors.Errorf("cannot set CFS quota of %d (period is %d): %w", target.Microseconds(), period.Microseconds(), err)
+	}
+	return true, nil
+}
+
+// NrThrottled returns the number of CFS periods the cgroup was throttled in
+func (basePath CgroupV2CFSController) NrThrottled() (uint64, error) {
+	return readCPUStat(string(basePath), "nr_throttled")
+}
+
+// readCPUMax reads quota and period from cpu.max, which looks like "$MAX $PERIOD" where $MAX may be "max"
+func (basePath CgroupV2CFSController) readCPUMax() (quota, period time.Duration, err error) {
+	fc, err := os.ReadFile(filepath.Join(string(basePath), "cpu.max"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	segs := strings.Fields(string(fc))
+	if len(segs) != 2 {
+		return 0, 0, xerrors.Errorf("unexpected content: %s", string(fc))
+	}
+
+	p, err := strconv.ParseInt(segs[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	period = time.Duration(p) * time.Microsecond
+
+	if segs[0] == "max" {
+		return time.Duration(math.MaxInt64), period, nil
+	}
+	q, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	quota = time.Duration(q) * time.Microsecond
+
+	return quota, period, nil
+}
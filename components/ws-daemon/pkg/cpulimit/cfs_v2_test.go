@@ -0,0 +1,70 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cpulimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCgroupV2CFSController(t *testing.T) {
+	tests := []struct {
+		Name            string
+		CPUMax          string
+		Limit           Bandwidth
+		ExpectedChanged bool
+		ExpectedCPUMax  string
+	}{
+		{Name: "unlimited", CPUMax: "max 100000", Limit: 2000, ExpectedChanged: true, ExpectedCPUMax: "200000 100000"},
+		{Name: "change", CPUMax: "200000 100000", Limit: 4000, ExpectedChanged: true, ExpectedCPUMax: "400000 100000"},
+		{Name: "unchanged", CPUMax: "200000 100000", Limit: 2000, ExpectedChanged: false, ExpectedCPUMax: "200000 100000"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "cpu.max"), []byte(test.CPUMax), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = os.WriteFile(filepath.Join(dir, "cpu.stat"), []byte("usage_usec 1500000\nuser_usec 1000000\nsystem_usec 500000\nnr_periods 10\nnr_throttled 3\nthrottled_usec 2000\n"), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfs := CgroupV2CFSController(dir)
+			changed, err := cfs.SetLimit(test.Limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed != test.ExpectedChanged {
+				t.Errorf("unexpected changed: expected %v, got %v", test.ExpectedChanged, changed)
+			}
+			cpuMax, err := os.ReadFile(filepath.Join(dir, "cpu.max"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(cpuMax) != test.ExpectedCPUMax {
+				t.Errorf("unexpected cpu.max: expected %q, got %q", test.ExpectedCPUMax, string(cpuMax))
+			}
+
+			usage, err := cfs.Usage()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if usage != CPUTime(1500*time.Millisecond) {
+				t.Errorf("unexpected usage: %v", time.Duration(usage))
+			}
+			throttled, err := cfs.NrThrottled()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if throttled != 3 {
+				t.Errorf("unexpected nr_throttled: %d", throttled)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ type Workspace struct {
 	NrThrottled uint64
 	Usage       CPUTime
 	QoS         int
+	Headless    bool
 }
 
 type WorkspaceHistory struct {
@@ -25,6 +26,7 @@ type WorkspaceHistory struct {
 
 	LastUpdate  *Workspace
 	UsageT0     CPUTime
+	UsageLag    CPUTime
 	ThrottleLag uint64
 	Limit       Bandwidth
 }
@@ -36,10 +38,19 @@ func (h *WorkspaceHistory) Usage() CPUTime {
 	return h.LastUpdate.Usage - h.UsageT0
 }
 
+// TickUsage returns the CPU time the workspace used between the last two updates
+func (h *WorkspaceHistory) TickUsage() CPUTime {
+	if h == nil || h.LastUpdate == nil || h.UsageLag == 0 {
+		return 0
+	}
+	return h.LastUpdate.Usage - h.UsageLag
+}
+
 func (h *WorkspaceHistory) Update(w Workspace) {
 	if h.LastUpdate == nil {
 		h.UsageT0 = w.Usage
 	} else {
+		h.UsageLag = h.LastUpdate.Usage
 		h.ThrottleLag = h.LastUpdate.NrThrottled
 	}
 	h.LastUpdate = &w
@@ -56,12 +67,11 @@ func (h *WorkspaceHistory) Throttled() bool {
 type DistributorSource func(context.Context) ([]Workspace, error)
 type DistributorSink func(id string, limit Bandwidth, burst bool)
 
-func NewDistributor(source DistributorSource, sink DistributorSink, limiter ResourceLimiter, burstLimiter ResourceLimiter, totalBandwidth Bandwidth) *Distributor {
+func NewDistributor(source DistributorSource, sink DistributorSink, policy Policy, totalBandwidth Bandwidth) *Distributor {
 	return &Distributor{
 		Source:         source,
 		Sink:           sink,
-		Limiter:        limiter,
-		BurstLimiter:   burstLimiter,
+		Policy:         policy,
 		TotalBandwidth: totalBandwidth,
 		History:        make(map[string]*WorkspaceHistory),
 	}
@@ -71,9 +81,8 @@ type Distributor struct {
 	Source DistributorSource
 	Sink   DistributorSink
 
-	History      map[string]*WorkspaceHistory
-	Limiter      ResourceLimiter
-	BurstLimiter ResourceLimiter
+	History map[string]*WorkspaceHistory
+	Policy  Policy
 
 	// TotalBandwidth is the total CPU time available in nanoseconds per second
 	TotalBandwidth Bandwidth
@@ -169,33 +178,21 @@ func (d *Distributor) Tick(dt time.Duration) (DistributorDebug, error) {
 	d.LastTickUsage = totalUsage
 
 	// enforce limits
-	var burstBandwidth Bandwidth
+	ordered := make([]*WorkspaceHistory, 0, len(wsOrder))
 	for _, id := range wsOrder {
-		ws := d.History[id]
-		limit := d.Limiter.Limit(ws.Usage())
-
-		// if we didn't get the max bandwidth, but were throttled last time
-		// and there's still some bandwidth left to give, let's act as if had
-		// never spent any CPU time and assume the workspace will spend their
-		// entire bandwidth at once.
-		var burst bool
-		if totalBandwidth < d.TotalBandwidth && ws.Throttled() {
-			limit = d.BurstLimiter.Limit(ws.Usage())
-
-			// We assume the workspace is going to use as much as their limit allows.
-			// This might not be true, because their process which consumed so much CPU
-			// may have ended by now.
-			totalBandwidth += limit
-
-			burstBandwidth += limit
+		ordered = append(ordered, d.History[id])
+	}
+	var burstBandwidth Bandwidth
+	for _, l := range d.Policy.Distribute(ordered, d.TotalBandwidth, totalBandwidth, dt) {
+		if l.Burst {
+			burstBandwidth += l.Limit
 		}
-
-		d.Sink(id, limit, burst)
+		d.Sink(l.ID, l.Limit, l.Burst)
 	}
 
 	return DistributorDebug{
 		BandwidthAvail: d.TotalBandwidth,
-		BandwidthUsed:  totalBandwidth,
+		BandwidthUsed:  totalBandwidth + burstBandwidth,
 		BandwidthBurst: burstBandwidth,
 	}, nil
 }
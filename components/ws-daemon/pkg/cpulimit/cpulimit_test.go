@@ -29,6 +29,7 @@ const (
 var (
 	defaultLimit         = cpulimit.FixedLimiter(2000)
 	defaultBreakoutLimit = cpulimit.FixedLimiter(6000)
+	defaultPolicy        = cpulimit.BucketPolicy{Limiter: defaultLimit, BurstLimiter: defaultBreakoutLimit}
 )
 
 // Consumer consumes CPU time
@@ -251,7 +252,7 @@ func TestBucketLimitsEatAll(t *testing.T) {
 		SteadyConsumer{id: "a3", rate: 2000},
 		SteadyConsumer{id: "a4", rate: 1000},
 	)
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultLimit, defaultBreakoutLimit, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultPolicy, totalCapacity)
 	runSimulation(t, node, dist)
 }
 
@@ -268,7 +269,7 @@ func TestBucketLimitsSine(t *testing.T) {
 			ampl:   5000,
 		},
 	)
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultLimit, defaultBreakoutLimit, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultPolicy, totalCapacity)
 	runSimulation(t, node, dist)
 }
 
@@ -277,7 +278,7 @@ func TestBucketLimitsMiner(t *testing.T) {
 	cs = append(cs, SteadyConsumer{id: "miner01", rate: 10000})
 	node := NewNode(cs...)
 
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultLimit, defaultBreakoutLimit, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultPolicy, totalCapacity)
 
 	runSimulation(t, node, dist)
 }
@@ -288,7 +289,7 @@ func TestBucketLimitsMixedQoS(t *testing.T) {
 	cs = append(cs, defaultQoSConsumerSet(t)...)
 	node := NewNode(cs...)
 
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultLimit, defaultBreakoutLimit, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultPolicy, totalCapacity)
 
 	runSimulation(t, node, dist)
 }
@@ -301,7 +302,7 @@ func TestBucketLimitsMaxConsumer(t *testing.T) {
 		)
 	}
 	node := NewNode(cs...)
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultLimit, defaultBreakoutLimit, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultPolicy, totalCapacity)
 
 	runSimulation(t, node, dist)
 }
@@ -310,7 +311,7 @@ func TestBucketLimitsNewProdBehaviour(t *testing.T) {
 	cs := defaultConsumerSet(t)
 	node := NewNode(cs...)
 
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultLimit, defaultBreakoutLimit, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, defaultPolicy, totalCapacity)
 
 	runSimulation(t, node, dist)
 }
@@ -323,7 +324,7 @@ func TestProdBehaviour(t *testing.T) {
 		cpulimit.Bucket{Budget: 5 * 60 * 2000, Limit: 2000},
 	}
 	breakoutLimiter := limiter
-	dist := cpulimit.NewDistributor(node.Source, node.Sink, limiter, breakoutLimiter, totalCapacity)
+	dist := cpulimit.NewDistributor(node.Source, node.Sink, cpulimit.BucketPolicy{Limiter: limiter, BurstLimiter: breakoutLimiter}, totalCapacity)
 
 	runSimulation(t, node, dist)
 }
@@ -6,7 +6,6 @@ package cpulimit
 
 import (
 	"context"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -27,16 +26,19 @@ type Config struct {
 	TotalBandwidth resource.Quantity `json:"totalBandwidth"`
 	Limit          resource.Quantity `json:"limit"`
 	BurstLimit     resource.Quantity `json:"burstLimit"`
+	Policy         PolicyConfig      `json:"policy"`
 
 	ControlPeriod  util.Duration `json:"controlPeriod"`
 	CGroupBasePath string        `json:"cgroupBasePath"`
 }
 
-// NewDispatchListener creates a new resource governer dispatch listener
-func NewDispatchListener(cfg *Config, prom prometheus.Registerer) *DispatchListener {
+// NewDispatchListener creates a new resource governer dispatch listener. Unified determines if the node
+// uses the cgroup v2 hierarchy.
+func NewDispatchListener(cfg *Config, unified bool, prom prometheus.Registerer) (*DispatchListener, error) {
 	d := &DispatchListener{
 		Prometheus: prom,
 		Config:     cfg,
+		Unified:    unified,
 		workspaces: make(map[string]*workspace),
 
 		workspacesAddedCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -62,11 +64,11 @@ func NewDispatchListener(cfg *Config, prom prometheus.Registerer) *DispatchListe
 	}
 
 	if cfg.Enabled {
-		dist := NewDistributor(d.source, d.sink,
-			FixedLimiter(BandwidthFromQuantity(d.Config.Limit)),
-			FixedLimiter(BandwidthFromQuantity(d.Config.BurstLimit)),
-			BandwidthFromQuantity(d.Config.TotalBandwidth),
-		)
+		policy, err := NewPolicy(cfg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create CPU limiting policy: %w", err)
+		}
+		dist := NewDistributor(d.source, d.sink, policy, BandwidthFromQuantity(d.Config.TotalBandwidth))
 		go dist.Run(context.Background(), time.Duration(d.Config.ControlPeriod))
 	}
 
@@ -78,13 +80,14 @@ func NewDispatchListener(cfg *Config, prom prometheus.Registerer) *DispatchListe
 		d.workspacesCPUTimeVec,
 	)
 
-	return d
+	return d, nil
 }
 
 // DispatchListener starts new resource governer using the workspace dispatch
 type DispatchListener struct {
 	Prometheus prometheus.Registerer
 	Config     *Config
+	Unified    bool
 
 	workspaces map[string]*workspace
 	mu         sync.RWMutex
@@ -97,9 +100,10 @@ type DispatchListener struct {
 }
 
 type workspace struct {
-	CFS       CgroupCFSController
+	CFS       CFSController
 	OWI       logrus.Fields
 	HardLimit ResourceLimiter
+	Headless  bool
 
 	lastThrottled uint64
 }
@@ -134,6 +138,7 @@ func (d *DispatchListener) source(context.Context) ([]Workspace, error) {
 			ID:          id,
 			NrThrottled: throttled,
 			Usage:       usage,
+			Headless:    w.Headless,
 		})
 	}
 	return res, nil
@@ -149,7 +154,9 @@ func (d *DispatchListener) sink(id string, limit Bandwidth, burst bool) {
 		return
 	}
 
-	d.workspacesBurstCounterVec.WithLabelValues("none").Inc()
+	if burst {
+		d.workspacesBurstCounterVec.WithLabelValues("none").Inc()
+	}
 
 	changed, err := ws.CFS.SetLimit(limit)
 	if err != nil {
@@ -176,8 +183,9 @@ func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Work
 	}
 
 	d.workspaces[ws.InstanceID] = &workspace{
-		CFS: CgroupCFSController(filepath.Join(d.Config.CGroupBasePath, "cpu", cgroupPath)),
-		OWI: ws.OWI(),
+		CFS:      NewCFSController(d.Config.CGroupBasePath, cgroupPath, d.Unified),
+		OWI:      ws.OWI(),
+		Headless: !wsk8s.IsRegularWorkspace(ws.Pod),
 	}
 	go func() {
 		<-ctx.Done()
@@ -0,0 +1,260 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cpulimit
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// Policy decides how the CPU bandwidth of a node is distributed among its workspaces
+type Policy interface {
+	// Distribute decides on the limit of each workspace. The workspaces are ordered by priority, highest first.
+	// totalBandwidth is the bandwidth available on the node, usedBandwidth the bandwidth all workspaces used
+	// since the last tick, and dt the time since the last tick.
+	Distribute(ws []*WorkspaceHistory, totalBandwidth, usedBandwidth Bandwidth, dt time.Duration) []WorkspaceLimit
+}
+
+// WorkspaceLimit is the limit a policy decided on for a workspace
+type WorkspaceLimit struct {
+	ID    string
+	Limit Bandwidth
+	// Burst is true if the workspace gets more than it would usually get
+	Burst bool
+}
+
+// PolicyName names a CPU limiting policy
+type PolicyName string
+
+const (
+	// PolicyBucket limits workspaces using the limit and burst limit of the config
+	PolicyBucket PolicyName = "bucket"
+	// PolicyFairShare splits the bandwidth of a node evenly among its workspaces
+	PolicyFairShare PolicyName = "fairShare"
+	// PolicyBoost acts like PolicyBucket, but boosts regular workspaces which turn busy after being idle
+	PolicyBoost PolicyName = "boost"
+	// PolicyHeadlessCap acts like PolicyBucket, but caps headless workspaces at a hard limit
+	PolicyHeadlessCap PolicyName = "headlessCap"
+)
+
+// PolicyConfig selects and configures the policy which distributes the CPU bandwidth of a node
+type PolicyConfig struct {
+	// Name is the policy to use. Defaults to PolicyBucket.
+	Name PolicyName `json:"name,omitempty"`
+
+	// MinLimit is the least bandwidth the fairShare policy grants a workspace, even if the node is overbooked
+	MinLimit resource.Quantity `json:"minLimit,omitempty"`
+
+	// BoostLimit is the limit of boosted workspaces
+	BoostLimit resource.Quantity `json:"boostLimit,omitempty"`
+	// BoostDuration is how long a boost lasts
+	BoostDuration util.Duration `json:"boostDuration,omitempty"`
+	// IdleBandwidth is the bandwidth below which a workspace counts as idle and can be boosted once it turns busy
+	IdleBandwidth resource.Quantity `json:"idleBandwidth,omitempty"`
+
+	// HeadlessLimit is the hard limit of headless workspaces
+	HeadlessLimit resource.Quantity `json:"headlessLimit,omitempty"`
+}
+
+// NewPolicy produces the policy the config selects
+func NewPolicy(cfg *Config) (Policy, error) {
+	bucket := BucketPolicy{
+		Limiter:      FixedLimiter(BandwidthFromQuantity(cfg.Limit)),
+		BurstLimiter: FixedLimiter(BandwidthFromQuantity(cfg.BurstLimit)),
+	}
+
+	pcfg := cfg.Policy
+	switch pcfg.Name {
+	case "", PolicyBucket:
+		return bucket, nil
+	case PolicyFairShare:
+		return FairSharePolicy{MinLimit: BandwidthFromQuantity(pcfg.MinLimit)}, nil
+	case PolicyBoost:
+		if pcfg.BoostLimit.IsZero() || pcfg.BoostDuration == 0 {
+			return nil, xerrors.Errorf("boost policy needs a boostLimit and boostDuration")
+		}
+		return NewBoostPolicy(bucket, BandwidthFromQuantity(pcfg.BoostLimit), time.Duration(pcfg.BoostDuration), BandwidthFromQuantity(pcfg.IdleBandwidth)), nil
+	case PolicyHeadlessCap:
+		if pcfg.HeadlessLimit.IsZero() {
+			return nil, xerrors.Errorf("headlessCap policy needs a headlessLimit")
+		}
+		return HeadlessCapPolicy{Policy: bucket, Limit: BandwidthFromQuantity(pcfg.HeadlessLimit)}, nil
+	default:
+		return nil, xerrors.Errorf("unknown CPU limiting policy: %s", pcfg.Name)
+	}
+}
+
+// BucketPolicy limits workspaces using a limiter. Workspaces which were throttled get their
+// limit from the burst limiter instead, as long as the node has bandwidth left to give.
+type BucketPolicy struct {
+	Limiter      ResourceLimiter
+	BurstLimiter ResourceLimiter
+}
+
+// Distribute decides on the limit of each workspace
+func (p BucketPolicy) Distribute(ws []*WorkspaceHistory, totalBandwidth, usedBandwidth Bandwidth, dt time.Duration) []WorkspaceLimit {
+	res := make([]WorkspaceLimit, 0, len(ws))
+	for _, w := range ws {
+		limit := p.Limiter.Limit(w.Usage())
+
+		// if we didn't get the max bandwidth, but were throttled last time
+		// and there's still some bandwidth left to give, let's act as if had
+		// never spent any CPU time and assume the workspace will spend their
+		// entire bandwidth at once.
+		var burst bool
+		if usedBandwidth < totalBandwidth && w.Throttled() {
+			limit = p.BurstLimiter.Limit(w.Usage())
+			burst = true
+
+			// We assume the workspace is going to use as much as their limit allows.
+			// This might not be true, because their process which consumed so much CPU
+			// may have ended by now.
+			usedBandwidth += limit
+		}
+
+		res = append(res, WorkspaceLimit{ID: w.ID, Limit: limit, Burst: burst})
+	}
+	return res
+}
+
+// FairSharePolicy splits the bandwidth of a node evenly among its workspaces. Whatever share a workspace
+// did not use during the last tick goes to the workspaces which were throttled.
+type FairSharePolicy struct {
+	// MinLimit is the least bandwidth a workspace gets, even if the node is overbooked
+	MinLimit Bandwidth
+}
+
+// Distribute decides on the limit of each workspace
+func (p FairSharePolicy) Distribute(ws []*WorkspaceHistory, totalBandwidth, usedBandwidth Bandwidth, dt time.Duration) []WorkspaceLimit {
+	if len(ws) == 0 {
+		return nil
+	}
+
+	share := totalBandwidth / Bandwidth(len(ws))
+	if share < p.MinLimit {
+		share = p.MinLimit
+	}
+
+	var (
+		spare     Bandwidth
+		throttled int
+	)
+	for _, w := range ws {
+		if w.Throttled() {
+			throttled++
+			continue
+		}
+
+		rate, err := BandwithFromUsage(0, w.TickUsage(), dt)
+		if err == nil && rate < share {
+			spare += share - rate
+		}
+	}
+
+	res := make([]WorkspaceLimit, 0, len(ws))
+	for _, w := range ws {
+		if !w.Throttled() || spare == 0 {
+			res = append(res, WorkspaceLimit{ID: w.ID, Limit: share})
+			continue
+		}
+		res = append(res, WorkspaceLimit{ID: w.ID, Limit: share + spare/Bandwidth(throttled), Burst: true})
+	}
+	return res
+}
+
+// NewBoostPolicy produces a new boost policy on top of another policy
+func NewBoostPolicy(policy Policy, limit Bandwidth, duration time.Duration, idleBandwidth Bandwidth) *BoostPolicy {
+	return &BoostPolicy{
+		Policy:        policy,
+		Limit:         limit,
+		Duration:      duration,
+		IdleBandwidth: idleBandwidth,
+		boostedUntil:  make(map[string]time.Duration),
+	}
+}
+
+// BoostPolicy boosts regular workspaces which get throttled right after they were idle, e.g. because
+// someone started a command in a terminal. Such workspaces get the boost limit for the boost duration,
+// all other workspaces are limited by the underlying policy. Workspaces which stay busy, e.g. because
+// they're running a build, don't get boosted again until they were idle.
+type BoostPolicy struct {
+	Policy        Policy
+	Limit         Bandwidth
+	Duration      time.Duration
+	IdleBandwidth Bandwidth
+
+	// now is the sum of all ticks so far. We count time in ticks rather than using the clock,
+	// so that the policy behaves the same in simulations.
+	now          time.Duration
+	boostedUntil map[string]time.Duration
+	wasIdle      map[string]bool
+}
+
+// Distribute decides on the limit of each workspace
+func (p *BoostPolicy) Distribute(ws []*WorkspaceHistory, totalBandwidth, usedBandwidth Bandwidth, dt time.Duration) []WorkspaceLimit {
+	p.now += dt
+
+	idle := make(map[string]bool, len(ws))
+	for _, w := range ws {
+		rate, err := BandwithFromUsage(0, w.TickUsage(), dt)
+		idle[w.ID] = err == nil && rate <= p.IdleBandwidth && !w.Throttled()
+
+		if w.LastUpdate.Headless {
+			continue
+		}
+		if p.wasIdle[w.ID] && w.Throttled() {
+			p.boostedUntil[w.ID] = p.now + p.Duration
+		}
+	}
+	p.wasIdle = idle
+
+	res := p.Policy.Distribute(ws, totalBandwidth, usedBandwidth, dt)
+	for i, l := range res {
+		until, ok := p.boostedUntil[l.ID]
+		if !ok {
+			continue
+		}
+		if until < p.now {
+			delete(p.boostedUntil, l.ID)
+			continue
+		}
+		if l.Limit < p.Limit {
+			res[i] = WorkspaceLimit{ID: l.ID, Limit: p.Limit, Burst: true}
+		}
+	}
+	for id := range p.boostedUntil {
+		if _, ok := idle[id]; !ok {
+			delete(p.boostedUntil, id)
+		}
+	}
+	return res
+}
+
+// HeadlessCapPolicy caps the limit of headless workspaces, e.g. prebuilds, at a hard limit.
+// All workspaces are limited by the underlying policy first.
+type HeadlessCapPolicy struct {
+	Policy Policy
+	Limit  Bandwidth
+}
+
+// Distribute decides on the limit of each workspace
+func (p HeadlessCapPolicy) Distribute(ws []*WorkspaceHistory, totalBandwidth, usedBandwidth Bandwidth, dt time.Duration) []WorkspaceLimit {
+	headless := make(map[string]bool, len(ws))
+	for _, w := range ws {
+		headless[w.ID] = w.LastUpdate.Headless
+	}
+
+	res := p.Policy.Distribute(ws, totalBandwidth, usedBandwidth, dt)
+	for i, l := range res {
+		if headless[l.ID] && l.Limit > p.Limit {
+			res[i] = WorkspaceLimit{ID: l.ID, Limit: p.Limit}
+		}
+	}
+	return res
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cpulimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cpulimit"
+)
+
+// history produces the history of a workspace which used tickUsage during the last tick
+func history(id string, tickUsage time.Duration, throttled, headless bool) *cpulimit.WorkspaceHistory {
+	h := &cpulimit.WorkspaceHistory{ID: id}
+	h.Update(cpulimit.Workspace{ID: id, Usage: cpulimit.CPUTime(1 * time.Second), NrThrottled: 1, Headless: headless})
+
+	var nrThrottled uint64 = 1
+	if throttled {
+		nrThrottled++
+	}
+	h.Update(cpulimit.Workspace{ID: id, Usage: cpulimit.CPUTime(1*time.Second + tickUsage), NrThrottled: nrThrottled, Headless: headless})
+	return h
+}
+
+func TestFairSharePolicyDistribute(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Policy      cpulimit.FairSharePolicy
+		Workspaces  []*cpulimit.WorkspaceHistory
+		Expectation []cpulimit.WorkspaceLimit
+	}{
+		{
+			Name:   "no workspaces",
+			Policy: cpulimit.FairSharePolicy{},
+		},
+		{
+			Name:   "spare share goes to throttled workspaces",
+			Policy: cpulimit.FairSharePolicy{},
+			Workspaces: []*cpulimit.WorkspaceHistory{
+				history("idle", 500*time.Millisecond, false, false),
+				history("throttled", 2*time.Second, true, false),
+				history("busy", 2*time.Second, false, false),
+			},
+			Expectation: []cpulimit.WorkspaceLimit{
+				{ID: "idle", Limit: 2000},
+				{ID: "throttled", Limit: 3500, Burst: true},
+				{ID: "busy", Limit: 2000},
+			},
+		},
+		{
+			Name:   "overbooked node",
+			Policy: cpulimit.FairSharePolicy{MinLimit: 4000},
+			Workspaces: []*cpulimit.WorkspaceHistory{
+				history("a", 4*time.Second, true, false),
+				history("b", 4*time.Second, true, false),
+				history("c", 4*time.Second, true, false),
+			},
+			Expectation: []cpulimit.WorkspaceLimit{
+				{ID: "a", Limit: 4000},
+				{ID: "b", Limit: 4000},
+				{ID: "c", Limit: 4000},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := test.Policy.Distribute(test.Workspaces, 6000, 6000, 1*time.Second)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected limits (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeadlessCapPolicyDistribute(t *testing.T) {
+	policy := cpulimit.HeadlessCapPolicy{
+		Policy: cpulimit.BucketPolicy{Limiter: cpulimit.FixedLimiter(4000), BurstLimiter: cpulimit.FixedLimiter(6000)},
+		Limit:  1000,
+	}
+	ws := []*cpulimit.WorkspaceHistory{
+		history("regular", 4*time.Second, true, false),
+		history("headless", 4*time.Second, true, true),
+	}
+
+	act := policy.Distribute(ws, 12000, 2000, 1*time.Second)
+	if diff := cmp.Diff([]cpulimit.WorkspaceLimit{
+		{ID: "regular", Limit: 6000, Burst: true},
+		{ID: "headless", Limit: 1000},
+	}, act); diff != "" {
+		t.Errorf("unexpected limits (-want +got):\n%s", diff)
+	}
+}
+
+func TestBoostPolicyDistribute(t *testing.T) {
+	const dt = 10 * time.Second
+	base := cpulimit.BucketPolicy{Limiter: cpulimit.FixedLimiter(2000), BurstLimiter: cpulimit.FixedLimiter(2000)}
+	policy := cpulimit.NewBoostPolicy(base, 6000, 15*time.Second, 500)
+
+	var (
+		interactive = &cpulimit.WorkspaceHistory{ID: "interactive"}
+		headless    = &cpulimit.WorkspaceHistory{ID: "headless"}
+		usage       = cpulimit.CPUTime(1 * time.Second)
+		nrThrottled = uint64(1)
+	)
+	tick := func(used time.Duration, throttled bool) []cpulimit.WorkspaceLimit {
+		usage += cpulimit.CPUTime(used)
+		if throttled {
+			nrThrottled++
+		}
+		interactive.Update(cpulimit.Workspace{ID: interactive.ID, Usage: usage, NrThrottled: nrThrottled})
+		headless.Update(cpulimit.Workspace{ID: headless.ID, Usage: usage, NrThrottled: nrThrottled, Headless: true})
+		return policy.Distribute([]*cpulimit.WorkspaceHistory{interactive, headless}, 12000, 12000, dt)
+	}
+	limits := func(interactive cpulimit.Bandwidth, burst bool) []cpulimit.WorkspaceLimit {
+		return []cpulimit.WorkspaceLimit{
+			{ID: "interactive", Limit: interactive, Burst: burst},
+			{ID: "headless", Limit: 2000},
+		}
+	}
+
+	steps := []struct {
+		Name        string
+		Used        time.Duration
+		Throttled   bool
+		Expectation []cpulimit.WorkspaceLimit
+	}{
+		{Name: "first update", Used: 0, Expectation: limits(2000, false)},
+		{Name: "idle", Used: 100 * time.Millisecond, Expectation: limits(2000, false)},
+		{Name: "turns busy", Used: 20 * time.Second, Throttled: true, Expectation: limits(6000, true)},
+		{Name: "stays busy", Used: 20 * time.Second, Throttled: true, Expectation: limits(6000, true)},
+		{Name: "boost expired", Used: 20 * time.Second, Throttled: true, Expectation: limits(2000, false)},
+	}
+	for _, step := range steps {
+		act := tick(step.Used, step.Throttled)
+		if diff := cmp.Diff(step.Expectation, act); diff != "" {
+			t.Errorf("%s: unexpected limits (-want +got):\n%s", step.Name, diff)
+		}
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Policy cpulimit.PolicyConfig
+		Error  bool
+	}{
+		{Name: "default"},
+		{Name: "bucket", Policy: cpulimit.PolicyConfig{Name: cpulimit.PolicyBucket}},
+		{Name: "fair share", Policy: cpulimit.PolicyConfig{Name: cpulimit.PolicyFairShare}},
+		{Name: "boost", Policy: cpulimit.PolicyConfig{Name: cpulimit.PolicyBoost, BoostLimit: resource.MustParse("6"), BoostDuration: util.Duration(30 * time.Second)}},
+		{Name: "boost without limit", Policy: cpulimit.PolicyConfig{Name: cpulimit.PolicyBoost, BoostDuration: util.Duration(30 * time.Second)}, Error: true},
+		{Name: "headless cap", Policy: cpulimit.PolicyConfig{Name: cpulimit.PolicyHeadlessCap, HeadlessLimit: resource.MustParse("2")}},
+		{Name: "headless cap without limit", Policy: cpulimit.PolicyConfig{Name: cpulimit.PolicyHeadlessCap}, Error: true},
+		{Name: "unknown", Policy: cpulimit.PolicyConfig{Name: "foobar"}, Error: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := cpulimit.NewPolicy(&cpulimit.Config{Policy: test.Policy})
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -20,10 +20,15 @@ import (
 	"golang.org/x/xerrors"
 )
 
-type CacheReclaim string
+// CacheReclaim periodically reclaims the page cache of workspaces which use more than 15% of their memory limit as cache
+type CacheReclaim struct {
+	CGroupBasePath string
+	// Unified is true if the node uses the cgroup v2 hierarchy
+	Unified bool
+}
 
 // WorkspaceAdded will customize the cgroups for every workspace that is started
-func (c CacheReclaim) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+func (c *CacheReclaim) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
 	disp := dispatch.GetFromContext(ctx)
 	if disp == nil {
 		return xerrors.Errorf("no dispatch available")
@@ -34,7 +39,10 @@ func (c CacheReclaim) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace
 		return xerrors.Errorf("cannot start governer: %w", err)
 	}
 
-	memPath := filepath.Join(string(c), "memory", cgroupPath)
+	var mem memoryController = memoryControllerV1(filepath.Join(c.CGroupBasePath, "memory", cgroupPath))
+	if c.Unified {
+		mem = memoryControllerV2(filepath.Join(c.CGroupBasePath, cgroupPath))
+	}
 
 	go func() {
 		owi := ws.OWI()
@@ -56,7 +64,7 @@ func (c CacheReclaim) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace
 				continue
 			}
 
-			stats, err := reclaimPageCache(memPath)
+			stats, err := reclaimPageCache(mem)
 			if err != nil {
 				log.WithFields(owi).WithError(err).Warn("cannot reclaim page cache")
 				continue
@@ -84,26 +92,76 @@ func (r *reclaimStats) Reclaimed() int64 {
 	return int64(r.CacheBefore) - int64(r.CacheAfter)
 }
 
-func reclaimPageCache(memCgroupPath string) (stats *reclaimStats, err error) {
-	cache, err := readCache(memCgroupPath)
+// memoryController gives access to the page cache of a cgroup
+type memoryController interface {
+	// Cache returns the amount of page cache used by the cgroup
+	Cache() (uint64, error)
+	// Limit returns the memory limit of the cgroup
+	Limit() (uint64, error)
+	// Reclaim drops as much of the cgroup's page cache as possible
+	Reclaim(cache uint64) error
+}
+
+// memoryControllerV1 is the memory controller of a cgroup on a cgroup v1 hierarchy
+type memoryControllerV1 string
+
+func (c memoryControllerV1) Cache() (uint64, error) {
+	return readCache(string(c))
+}
+
+func (c memoryControllerV1) Limit() (uint64, error) {
+	return readLimit(string(c))
+}
+
+func (c memoryControllerV1) Reclaim(cache uint64) error {
+	err := ioutil.WriteFile(filepath.Join(string(c), "memory.force_empty"), []byte("1"), 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write memory.force_empty: %v", err)
+	}
+	return nil
+}
+
+// memoryControllerV2 is the memory controller of a cgroup on the unified cgroup v2 hierarchy
+type memoryControllerV2 string
+
+func (c memoryControllerV2) Cache() (uint64, error) {
+	return readMemoryStat(string(c), "file")
+}
+
+func (c memoryControllerV2) Limit() (uint64, error) {
+	return readMemoryLimit(string(c), "memory.max")
+}
+
+// Reclaim asks the kernel to reclaim the page cache. cgroup v2 has no equivalent of memory.force_empty,
+// and memory.reclaim requires Linux 5.19 or newer.
+func (c memoryControllerV2) Reclaim(cache uint64) error {
+	err := ioutil.WriteFile(filepath.Join(string(c), "memory.reclaim"), []byte(strconv.FormatUint(cache, 10)), 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write memory.reclaim: %v", err)
+	}
+	return nil
+}
+
+func reclaimPageCache(mem memoryController) (stats *reclaimStats, err error) {
+	cache, err := mem.Cache()
 	if err != nil {
 		return nil, err
 	}
-	limit, err := readLimit(memCgroupPath)
+	limit, err := mem.Limit()
 	if err != nil {
 		return nil, err
 	}
 
 	var didReclaim bool
 	if cache > uint64(float64(limit)*0.15) {
-		err := ioutil.WriteFile(filepath.Join(memCgroupPath, "memory.force_empty"), []byte("1"), 0644)
+		err := mem.Reclaim(cache)
 		if err != nil {
-			return nil, xerrors.Errorf("cannot write memory.force_empty: %v", err)
+			return nil, err
 		}
 		didReclaim = true
 	}
 
-	nowCache, _ := readCache(memCgroupPath)
+	nowCache, _ := mem.Cache()
 	return &reclaimStats{
 		CacheBefore: cache,
 		CacheAfter:  nowCache,
@@ -113,10 +171,14 @@ func reclaimPageCache(memCgroupPath string) (stats *reclaimStats, err error) {
 }
 
 func readLimit(memCgroupPath string) (uint64, error) {
-	fn := filepath.Join(string(memCgroupPath), "memory.limit_in_bytes")
+	return readMemoryLimit(memCgroupPath, "memory.limit_in_bytes")
+}
+
+func readMemoryLimit(memCgroupPath, name string) (uint64, error) {
+	fn := filepath.Join(string(memCgroupPath), name)
 	fc, err := os.ReadFile(fn)
 	if err != nil {
-		return 0, xerrors.Errorf("cannot read memory.limit_in_bytes: %v", err)
+		return 0, xerrors.Errorf("cannot read %s: %v", name, err)
 	}
 
 	s := strings.TrimSpace(string(fc))
@@ -126,32 +188,36 @@ func readLimit(memCgroupPath string) (uint64, error) {
 
 	p, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
-		return 0, xerrors.Errorf("cannot parse memory.limit_in_bytes (%s): %v", s, err)
+		return 0, xerrors.Errorf("cannot parse %s (%s): %v", name, s, err)
 	}
 	return p, nil
 }
 
 func readCache(memCgroupPath string) (uint64, error) {
+	return readMemoryStat(memCgroupPath, "cache")
+}
+
+func readMemoryStat(memCgroupPath, key string) (uint64, error) {
 	f, err := os.Open(filepath.Join(string(memCgroupPath), "memory.stat"))
 	if err != nil {
 		return 0, xerrors.Errorf("cannot read memory.stat: %w", err)
 	}
 	defer f.Close()
 
-	const prefixCache = "cache "
+	prefix := key + " "
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		l := scanner.Text()
-		if !strings.HasPrefix(l, prefixCache) {
+		if !strings.HasPrefix(l, prefix) {
 			continue
 		}
 
-		r, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(l, prefixCache)), 10, 64)
+		r, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(l, prefix)), 10, 64)
 		if err != nil {
 			return 0, xerrors.Errorf("cannot parse memory.stat: %s: %w", l, err)
 		}
 		return r, nil
 	}
-	return 0, xerrors.Errorf("memory.stat did not contain cache")
+	return 0, xerrors.Errorf("memory.stat did not contain %s", key)
 }
@@ -6,6 +6,7 @@ package daemon
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -93,3 +94,31 @@ func TestReadCacheBadValue(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryControllerV2(t *testing.T) {
+	tempdir := createTempDir(t, "workspace")
+	err := cgroups.WriteFile(tempdir, "memory.max", "max")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cgroups.WriteFile(tempdir, "memory.stat", "anon 1024\nfile 512\nkernel_stack 64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mem := memoryControllerV2(tempdir)
+	limit, err := mem.Limit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit != math.MaxUint64 {
+		t.Fatalf("unexpected limit: is '%v' but expected '%v'", limit, uint64(math.MaxUint64))
+	}
+	cache, err := mem.Cache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache != 512 {
+		t.Fatalf("unexpected cache: is '%v' but expected '%v'", cache, 512)
+	}
+}
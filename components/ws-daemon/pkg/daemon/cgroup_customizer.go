@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/containerd/cgroups"
+	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/xerrors"
@@ -20,14 +21,27 @@ var (
 
 type CgroupCustomizer struct {
 	cgroupBasePath string
+	unified        bool
 }
 
 func (c *CgroupCustomizer) WithCgroupBasePath(basePath string) {
 	c.cgroupBasePath = basePath
 }
 
+// WithUnifiedCgroupHierarchy tells the customizer that the node uses the cgroup v2 hierarchy
+func (c *CgroupCustomizer) WithUnifiedCgroupHierarchy(unified bool) {
+	c.unified = unified
+}
+
 // WorkspaceAdded will customize the cgroups for every workspace that is started
 func (c *CgroupCustomizer) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	if c.unified {
+		// cgroup v2 has no devices controller. Device access is governed by an eBPF program which the
+		// container runtime attaches when it creates the container.
+		log.WithFields(ws.OWI()).Debug("not customizing device access on the unified cgroup hierarchy")
+		return nil
+	}
+
 	disp := dispatch.GetFromContext(ctx)
 	if disp == nil {
 		return xerrors.Errorf("no dispatch available")
@@ -9,8 +9,12 @@ import (
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/content"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cpulimit"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/diskguard"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/fileaudit"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/hosts"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iolimit"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iws"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/memreclaim"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/netlimit"
 )
 
 // Config configures the workspace node daemon
@@ -21,8 +25,12 @@ type Config struct {
 	Content        content.Config      `json:"content"`
 	Uidmapper      iws.UidmapperConfig `json:"uidmapper"`
 	Resources      cpulimit.Config     `json:"cpulimit"`
+	IOLimit        iolimit.Config      `json:"iolimit"`
+	NetLimit       netlimit.Config     `json:"netlimit"`
+	MemoryReclaim  memreclaim.Config   `json:"memoryReclaim"`
 	Hosts          hosts.Config        `json:"hosts"`
 	DiskSpaceGuard diskguard.Config    `json:"disk"`
+	FileAudit      fileaudit.Config    `json:"fileAudit"`
 }
 
 type RuntimeConfig struct {
@@ -19,13 +19,18 @@ import (
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cgroup"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/content"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cpulimit"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/diskguard"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/fileaudit"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/hosts"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iolimit"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iws"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/memreclaim"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/netlimit"
 )
 
 // NewDaemon produces a new daemon
@@ -46,15 +51,30 @@ func NewDaemon(config Config, reg prometheus.Registerer) (*Daemon, error) {
 	if nodename == "" {
 		return nil, xerrors.Errorf("NODENAME env var isn't set")
 	}
+	unified, err := cgroup.IsUnifiedCgroupSetup(config.Resources.CGroupBasePath)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot determine cgroup setup: %w", err)
+	}
+	log.WithField("unified", unified).Info("detected cgroup hierarchy")
+
 	cgCustomizer := &CgroupCustomizer{}
 	cgCustomizer.WithCgroupBasePath(config.Resources.CGroupBasePath)
+	cgCustomizer.WithUnifiedCgroupHierarchy(unified)
 	markUnmountFallback, err := NewMarkUnmountFallback(reg)
 	if err != nil {
 		return nil, err
 	}
+	cpuLimit, err := cpulimit.NewDispatchListener(&config.Resources, unified, reg)
+	if err != nil {
+		return nil, err
+	}
 	dsptch, err := dispatch.NewDispatch(containerRuntime, clientset, config.Runtime.KubernetesNamespace, nodename,
-		cpulimit.NewDispatchListener(&config.Resources, reg),
-		CacheReclaim(config.Resources.CGroupBasePath),
+		cpuLimit,
+		iolimit.NewDispatchListener(&config.IOLimit, config.Resources.CGroupBasePath, unified, reg),
+		netlimit.NewDispatchListener(&config.NetLimit, reg),
+		memreclaim.NewDispatchListener(&config.MemoryReclaim, config.Resources.CGroupBasePath, unified, reg),
+		fileaudit.NewDispatchListener(&config.FileAudit, reg),
+		&CacheReclaim{CGroupBasePath: config.Resources.CGroupBasePath, Unified: unified},
 		cgCustomizer,
 		markUnmountFallback,
 	)
@@ -0,0 +1,117 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package fileaudit
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+)
+
+// auditMask are the fanotify events we record. We only listen for files which were closed after being written to,
+// rather than every write, so that a single modification produces a single event.
+const auditMask = unix.FAN_CLOSE_WRITE | unix.FAN_EVENT_ON_CHILD
+
+// fanotifyGroup is a fanotify notification group which watches the sensitive paths of a single workspace
+type fanotifyGroup struct {
+	f *os.File
+}
+
+// newFanotifyGroup creates a new notification group. The group's file descriptor is non-blocking,
+// so that closing the group ends a pending read.
+func newFanotifyGroup() (*fanotifyGroup, error) {
+	fd, err := unix.FanotifyInit(unix.FAN_CLASS_NOTIF|unix.FAN_CLOEXEC|unix.FAN_NONBLOCK, unix.O_RDONLY|unix.O_LARGEFILE|unix.O_CLOEXEC)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create fanotify group: %w", err)
+	}
+	return &fanotifyGroup{f: os.NewFile(uintptr(fd), "fanotify")}, nil
+}
+
+// Mark adds a file or directory to the group. For directories we watch the files in the directory,
+// but not its subdirectories. Marking a path twice is fine.
+func (g *fanotifyGroup) Mark(path string) error {
+	// We don't use g.f.Fd() because that makes the file descriptor blocking. Control also guarantees
+	// that we don't mark using a file descriptor which was closed and reused in the meantime.
+	rc, err := g.f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var merr error
+	err = rc.Control(func(fd uintptr) {
+		merr = unix.FanotifyMark(int(fd), unix.FAN_MARK_ADD, auditMask, unix.AT_FDCWD, path)
+	})
+	if err == nil {
+		err = merr
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot mark %s: %w", path, err)
+	}
+	return nil
+}
+
+// fanotifyEvent is a file modification fanotify reported
+type fanotifyEvent struct {
+	Path string
+	PID  int
+}
+
+// Read blocks until fanotify reports file modifications, and returns them. overflow is true if the kernel
+// dropped events because we didn't read them fast enough.
+func (g *fanotifyGroup) Read(buf []byte) (evts []fanotifyEvent, overflow bool, err error) {
+	n, err := g.f.Read(buf)
+	if err != nil {
+		return nil, false, err
+	}
+
+	metas, err := parseEventMetadata(buf[:n])
+	if err != nil {
+		return nil, false, err
+	}
+	for _, meta := range metas {
+		if meta.Mask&unix.FAN_Q_OVERFLOW != 0 {
+			overflow = true
+		}
+		if meta.Fd < 0 {
+			continue
+		}
+
+		path, err := os.Readlink(fmt.Sprintf("/proc/self/fd/%d", meta.Fd))
+		unix.Close(int(meta.Fd))
+		if err != nil {
+			continue
+		}
+		evts = append(evts, fanotifyEvent{Path: path, PID: int(meta.Pid)})
+	}
+	return evts, overflow, nil
+}
+
+// Close closes the group, which removes all its marks
+func (g *fanotifyGroup) Close() error {
+	return g.f.Close()
+}
+
+// parseEventMetadata parses the event metadata the kernel writes to a fanotify group's file descriptor
+func parseEventMetadata(buf []byte) ([]unix.FanotifyEventMetadata, error) {
+	var (
+		res  []unix.FanotifyEventMetadata
+		size = int(unsafe.Sizeof(unix.FanotifyEventMetadata{}))
+	)
+	for len(buf) >= size {
+		meta := *(*unix.FanotifyEventMetadata)(unsafe.Pointer(&buf[0]))
+		if meta.Vers != unix.FANOTIFY_METADATA_VERSION {
+			return nil, xerrors.Errorf("unsupported fanotify metadata version %d", meta.Vers)
+		}
+		if int(meta.Event_len) < size || int(meta.Event_len) > len(buf) {
+			return nil, xerrors.Errorf("invalid fanotify event length %d", meta.Event_len)
+		}
+
+		res = append(res, meta)
+		buf = buf[meta.Event_len:]
+	}
+	return res, nil
+}
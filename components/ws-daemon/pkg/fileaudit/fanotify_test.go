@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package fileaudit
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/sys/unix"
+)
+
+func TestParseEventMetadata(t *testing.T) {
+	size := uint32(unsafe.Sizeof(unix.FanotifyEventMetadata{}))
+	event := func(mask uint64, fd, pid int32) unix.FanotifyEventMetadata {
+		return unix.FanotifyEventMetadata{
+			Event_len:    size,
+			Vers:         unix.FANOTIFY_METADATA_VERSION,
+			Metadata_len: uint16(size),
+			Mask:         mask,
+			Fd:           fd,
+			Pid:          pid,
+		}
+	}
+	serialize := func(metas ...unix.FanotifyEventMetadata) []byte {
+		var res []byte
+		for i := range metas {
+			res = append(res, (*[unsafe.Sizeof(unix.FanotifyEventMetadata{})]byte)(unsafe.Pointer(&metas[i]))[:]...)
+		}
+		return res
+	}
+
+	tests := []struct {
+		Name        string
+		Input       []byte
+		Expectation []unix.FanotifyEventMetadata
+		Error       bool
+	}{
+		{Name: "empty"},
+		{
+			Name:  "events",
+			Input: serialize(event(unix.FAN_CLOSE_WRITE, 5, 42), event(unix.FAN_Q_OVERFLOW, -1, 0)),
+			Expectation: []unix.FanotifyEventMetadata{
+				event(unix.FAN_CLOSE_WRITE, 5, 42),
+				event(unix.FAN_Q_OVERFLOW, -1, 0),
+			},
+		},
+		{
+			Name: "unsupported version",
+			Input: serialize(unix.FanotifyEventMetadata{
+				Event_len: size,
+				Vers:      unix.FANOTIFY_METADATA_VERSION + 1,
+			}),
+			Error: true,
+		},
+		{
+			Name: "truncated event",
+			Input: serialize(unix.FanotifyEventMetadata{
+				Event_len: 2 * size,
+				Vers:      unix.FANOTIFY_METADATA_VERSION,
+			}),
+			Error: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := parseEventMetadata(test.Input)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
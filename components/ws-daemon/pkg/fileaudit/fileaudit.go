@@ -0,0 +1,257 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package fileaudit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/fileaudit"
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// Config configures the auditing of file modifications in sensitive paths of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Paths are the sensitive paths within the workspace container. Paths may contain globs, e.g. /workspace/*/.git/hooks.
+	// We audit the files in directories, but not their subdirectories. Defaults to defaultPaths.
+	Paths []string `json:"paths,omitempty"`
+	// RescanPeriod is the time between two scans for sensitive paths, so that we audit paths which
+	// did not exist when the workspace started, too.
+	RescanPeriod util.Duration `json:"rescanPeriod"`
+	// AgentSmithSocket is the unix socket agent-smith receives the events on. If empty, events are only logged.
+	AgentSmithSocket string `json:"agentSmithSocket,omitempty"`
+}
+
+var defaultPaths = []string{
+	"/home/gitpod/.ssh",
+	"/home/gitpod/.bashrc",
+	"/home/gitpod/.profile",
+	"/home/gitpod/.gitconfig",
+	"/workspace/*/.git/hooks",
+}
+
+// eventQueueSize is the number of events we hold on to while agent-smith is slow or unavailable
+const eventQueueSize = 500
+
+// NewDispatchListener creates a new file auditing dispatch listener
+func NewDispatchListener(cfg *Config, prom prometheus.Registerer) *DispatchListener {
+	d := &DispatchListener{
+		Config: cfg,
+		events: make(chan fileaudit.Event, eventQueueSize),
+
+		eventsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fileaudit_events_total",
+			Help: "Number of file modifications recorded in sensitive paths of workspaces",
+		}),
+		eventsDroppedCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fileaudit_events_dropped_total",
+			Help: "Number of file modifications which were not shipped to agent-smith",
+		}, []string{"reason"}),
+	}
+
+	if cfg.Enabled && cfg.AgentSmithSocket != "" {
+		go d.ship(context.Background())
+	}
+
+	prom.MustRegister(d.eventsCounter, d.eventsDroppedCounterVec)
+
+	return d
+}
+
+// DispatchListener audits file modifications in sensitive paths of workspaces using the workspace dispatch
+type DispatchListener struct {
+	Config *Config
+
+	events chan fileaudit.Event
+
+	eventsCounter           prometheus.Counter
+	eventsDroppedCounterVec *prometheus.CounterVec
+}
+
+// WorkspaceAdded starts auditing the sensitive paths of a workspace
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	if !d.Config.Enabled {
+		return nil
+	}
+
+	disp := dispatch.GetFromContext(ctx)
+	if disp == nil {
+		return xerrors.Errorf("no dispatch available")
+	}
+
+	pid, err := disp.Runtime.ContainerPID(context.Background(), ws.ContainerID)
+	if err != nil {
+		return xerrors.Errorf("cannot find workspace container PID: %w", err)
+	}
+
+	grp, err := newFanotifyGroup()
+	if err != nil {
+		return xerrors.Errorf("cannot audit workspace: %w", err)
+	}
+
+	aws := &workspace{
+		Rootfs:  fmt.Sprintf("/proc/%d/root", pid),
+		OwnerID: ws.Pod.Labels[wsk8s.OwnerLabel],
+		WS:      ws,
+		OWI:     ws.OWI(),
+		group:   grp,
+	}
+	aws.markPaths(d.paths())
+
+	go func() {
+		<-ctx.Done()
+		grp.Close()
+	}()
+	go d.rescan(ctx, aws)
+	go d.record(aws)
+
+	return nil
+}
+
+func (d *DispatchListener) paths() []string {
+	if len(d.Config.Paths) == 0 {
+		return defaultPaths
+	}
+	return d.Config.Paths
+}
+
+type workspace struct {
+	Rootfs  string
+	OwnerID string
+	WS      *dispatch.Workspace
+	OWI     logrus.Fields
+
+	group *fanotifyGroup
+}
+
+// markPaths adds all sensitive paths which exist in the workspace to its fanotify group
+func (ws *workspace) markPaths(patterns []string) {
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(ws.Rootfs, pattern))
+		if err != nil {
+			log.WithFields(ws.OWI).WithError(err).WithField("pattern", pattern).Warn("invalid sensitive path")
+			continue
+		}
+		for _, m := range matches {
+			err = ws.group.Mark(m)
+			if err != nil {
+				log.WithFields(ws.OWI).WithError(err).Debug("cannot audit sensitive path")
+			}
+		}
+	}
+}
+
+// rescan marks sensitive paths which were created after the workspace started, until the workspace is gone
+func (d *DispatchListener) rescan(ctx context.Context, ws *workspace) {
+	if d.Config.RescanPeriod == 0 {
+		return
+	}
+
+	t := time.NewTicker(time.Duration(d.Config.RescanPeriod))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		ws.markPaths(d.paths())
+	}
+}
+
+// record reads the file modifications fanotify reports until the workspace's fanotify group is closed
+func (d *DispatchListener) record(ws *workspace) {
+	buf := make([]byte, 4096)
+	for {
+		evts, overflow, err := ws.group.Read(buf)
+		if errors.Is(err, os.ErrClosed) {
+			return
+		}
+		if err != nil {
+			log.WithFields(ws.OWI).WithError(err).Warn("cannot read file modifications, stopped auditing workspace")
+			return
+		}
+		if overflow {
+			d.eventsDroppedCounterVec.WithLabelValues("overflow").Inc()
+		}
+
+		for _, evt := range evts {
+			path := strings.TrimPrefix(evt.Path, ws.Rootfs)
+
+			d.eventsCounter.Inc()
+			log.WithFields(ws.OWI).WithField("path", path).WithField("pid", evt.PID).Info("file in sensitive path was modified")
+			if d.Config.AgentSmithSocket == "" {
+				continue
+			}
+
+			select {
+			case d.events <- fileaudit.Event{
+				Time:        time.Now(),
+				OwnerID:     ws.OwnerID,
+				WorkspaceID: ws.WS.WorkspaceID,
+				InstanceID:  ws.WS.InstanceID,
+				Path:        path,
+				PID:         evt.PID,
+			}:
+			default:
+				d.eventsDroppedCounterVec.WithLabelValues("queue_full").Inc()
+			}
+		}
+	}
+}
+
+// ship sends the recorded events to agent-smith, one JSON object per line. If agent-smith is unavailable,
+// we drop the events rather than holding on to them.
+func (d *DispatchListener) ship(ctx context.Context) {
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	for {
+		var evt fileaudit.Event
+		select {
+		case <-ctx.Done():
+			return
+		case evt = <-d.events:
+		}
+
+		if conn == nil {
+			var err error
+			conn, err = net.Dial("unix", d.Config.AgentSmithSocket)
+			if err != nil {
+				log.WithError(err).Debug("cannot connect to agent-smith")
+				d.eventsDroppedCounterVec.WithLabelValues("unavailable").Inc()
+				continue
+			}
+		}
+
+		err := json.NewEncoder(conn).Encode(evt)
+		if err != nil {
+			log.WithError(err).Debug("cannot ship file audit event to agent-smith")
+			d.eventsDroppedCounterVec.WithLabelValues("unavailable").Inc()
+			conn.Close()
+			conn = nil
+		}
+	}
+}
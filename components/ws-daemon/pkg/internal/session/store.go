@@ -155,6 +155,18 @@ func (s *Store) Get(instanceID string) *Workspace {
 	return s.workspaces[instanceID]
 }
 
+// List returns all workspaces of the store
+func (s *Store) List() []*Workspace {
+	s.workspacesLock.Lock()
+	defer s.workspacesLock.Unlock()
+
+	res := make([]*Workspace, 0, len(s.workspaces))
+	for _, ws := range s.workspaces {
+		res = append(res, ws)
+	}
+	return res
+}
+
 // StartHousekeeping starts garbage collection and regular cleanup.
 // This function returns when the context is canceled.
 func (s *Store) StartHousekeeping(ctx context.Context, interval time.Duration) {
@@ -21,6 +21,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/git"
+	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 )
 
 const (
@@ -68,8 +69,15 @@ type Workspace struct {
 	ServiceLocDaemon string `json:"serviceLocDaemon"`
 
 	RemoteStorageDisabled bool `json:"remoteStorageDisabled,omitempty"`
+	// PersistentVolumeClaim is true if the workspace content lives on a persistent volume claim rather than on the node.
+	// Such workspaces have no location.
+	PersistentVolumeClaim bool `json:"persistentVolumeClaim,omitempty"`
 
 	XFSProjectID int `json:"xfsProjectID"`
+	// StorageQuota is the disk quota of the workspace content in bytes. Zero means the daemon default applies.
+	StorageQuota int64 `json:"storageQuota,omitempty"`
+	// SeccompProfile names the seccomp filter workspacekit installs in the workspace. Empty means the default filter.
+	SeccompProfile string `json:"seccompProfile,omitempty"`
 
 	NonPersistentAttrs map[string]interface{} `json:"-"`
 
@@ -77,6 +85,9 @@ type Workspace struct {
 	state              WorkspaceState
 	stateLock          sync.RWMutex
 	operatingCondition *sync.Cond
+
+	initProgress        wsinit.Progress
+	initProgressChanged chan struct{}
 }
 
 // OWI produces the owner, workspace, instance log metadata from the information
@@ -140,6 +151,7 @@ func (s *Workspace) MarkInitDone(ctx context.Context) (err error) {
 	s.stateLock.Lock()
 	s.state = WorkspaceReady
 	s.operatingCondition.Broadcast()
+	s.notifyInitProgress()
 	s.stateLock.Unlock()
 
 	err = s.store.runLifecycleHooks(ctx, s)
@@ -168,6 +180,7 @@ func (s *Workspace) WaitOrMarkForDisposal(ctx context.Context) (done bool, repo
 		return true, nil, nil
 	} else if s.state != WorkspaceDisposing {
 		s.state = WorkspaceDisposing
+		s.notifyInitProgress()
 		s.stateLock.Unlock()
 
 		err = s.persist()
@@ -208,6 +221,7 @@ func (s *Workspace) Dispose(ctx context.Context) (err error) {
 	s.stateLock.Lock()
 	s.state = WorkspaceDisposed
 	s.operatingCondition.Broadcast()
+	s.notifyInitProgress()
 	s.stateLock.Unlock()
 
 	err = s.store.runLifecycleHooks(ctx, s)
@@ -215,7 +229,7 @@ func (s *Workspace) Dispose(ctx context.Context) (err error) {
 		return err
 	}
 
-	if !s.FullWorkspaceBackup {
+	if !s.FullWorkspaceBackup && !s.PersistentVolumeClaim {
 		err = os.RemoveAll(s.Location)
 	}
 	if err != nil {
@@ -241,6 +255,35 @@ func (s *Workspace) IsDisposing() bool {
 	return r
 }
 
+// SetInitProgress records the progress of the content initialization and notifies everyone watching it
+func (s *Workspace) SetInitProgress(p wsinit.Progress) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	s.initProgress = p
+	s.notifyInitProgress()
+}
+
+// InitProgress returns the progress of the content initialization, and a channel which is closed once that
+// progress changes. done is true once the workspace is no longer initializing.
+func (s *Workspace) InitProgress() (p wsinit.Progress, changed <-chan struct{}, done bool) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if s.initProgressChanged == nil {
+		s.initProgressChanged = make(chan struct{})
+	}
+	return s.initProgress, s.initProgressChanged, s.state != WorkspaceInitializing
+}
+
+// notifyInitProgress wakes everyone watching the content initialization progress. Callers must hold the state lock.
+func (s *Workspace) notifyInitProgress() {
+	if s.initProgressChanged != nil {
+		close(s.initProgressChanged)
+		s.initProgressChanged = nil
+	}
+}
+
 // SetGitStatus sets the last git status field and persists the change
 func (s *Workspace) SetGitStatus(status *csapi.GitStatus) error {
 	s.stateLock.Lock()
@@ -262,7 +305,9 @@ func (s *Workspace) UpdateGitStatus(ctx context.Context) (res *csapi.GitStatus,
 		// does not work.
 		//
 		// TODO(cw): figure out a way to get ahold of the Git status.
-		log.WithField("loc", loc).WithFields(s.OWI()).Debug("not updating Git status of FWB workspace")
+		//
+		// The same holds for workspaces whose content lives on a persistent volume claim.
+		log.WithField("loc", loc).WithFields(s.OWI()).Debug("not updating Git status of FWB or PVC workspace")
 		return
 	}
 
@@ -18,6 +18,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/sirupsen/logrus"
+
+	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 )
 
 func init() {
@@ -188,6 +190,53 @@ func TestWaitForInit(t *testing.T) {
 	}
 }
 
+func TestInitProgress(t *testing.T) {
+	store, err := getTestStore()
+	if err != nil {
+		t.Fatalf("cannot create test store: %v", err)
+	}
+	ws, err := addRandomWorkspace(store)
+	if err != nil {
+		t.Fatalf("cannot create test workspace: %v", err)
+	}
+
+	p, changed, done := ws.InitProgress()
+	if done {
+		t.Fatal("initializing workspace reports its content initialization as done")
+	}
+	if diff := cmp.Diff(wsinit.Progress{}, p); diff != "" {
+		t.Errorf("unexpected initial progress (-want +got):\n%s", diff)
+	}
+
+	expectChange := func(changed <-chan struct{}) {
+		select {
+		case <-changed:
+		case <-time.After(1 * time.Second):
+			t.Fatal("progress change was not notified")
+		}
+	}
+
+	ws.SetInitProgress(wsinit.Progress{Phase: wsinit.ProgressClone, Percent: 42})
+	expectChange(changed)
+	p, changed, done = ws.InitProgress()
+	if done {
+		t.Fatal("initializing workspace reports its content initialization as done")
+	}
+	if diff := cmp.Diff(wsinit.Progress{Phase: wsinit.ProgressClone, Percent: 42}, p); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+
+	err = ws.MarkInitDone(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectChange(changed)
+	_, _, done = ws.InitProgress()
+	if !done {
+		t.Error("ready workspace does not report its content initialization as done")
+	}
+}
+
 func TestWaitOrMarkForDisposalRace(t *testing.T) {
 	store, err := getTestStore()
 	if err != nil {
@@ -0,0 +1,119 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iolimit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// IOController controls the IO bandwidth of a cgroup
+type IOController interface {
+	// Usage returns the total number of bytes the cgroup has read from and written to a device
+	Usage(device string) (bytes uint64, err error)
+	// SetLimit sets a new read and write bandwidth limit in bytes per second on a device. A limit of zero removes the limit.
+	SetLimit(device string, limit uint64) error
+}
+
+// NewIOController produces the IO controller of a container's cgroup, depending on the cgroup hierarchy of the node
+func NewIOController(basePath, cgroupPath string, unified bool) IOController {
+	if unified {
+		return CgroupV2IOController(filepath.Join(basePath, cgroupPath))
+	}
+	return CgroupBlkioController(filepath.Join(basePath, "blkio", cgroupPath))
+}
+
+// CgroupBlkioController controls a cgroup's blkio throttling on a cgroup v1 hierarchy
+type CgroupBlkioController string
+
+// Usage sums up the bytes read and written on the device from blkio.throttle.io_service_bytes
+func (basePath CgroupBlkioController) Usage(device string) (bytes uint64, err error) {
+	err = scanLines(filepath.Join(string(basePath), "blkio.throttle.io_service_bytes"), func(fields []string) error {
+		// lines read "<major>:<minor> <operation> <bytes>"
+		if len(fields) != 3 || fields[0] != device || (fields[1] != "Read" && fields[1] != "Write") {
+			return nil
+		}
+		v, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return err
+		}
+		bytes += v
+		return nil
+	})
+	return
+}
+
+// SetLimit sets the read and write throttling of the device
+func (basePath CgroupBlkioController) SetLimit(device string, limit uint64) error {
+	for _, fn := range []string{"blkio.throttle.read_bps_device", "blkio.throttle.write_bps_device"} {
+		err := os.WriteFile(filepath.Join(string(basePath), fn), []byte(fmt.Sprintf("%s %d", device, limit)), 0644)
+		if err != nil {
+			return xerrors.Errorf("cannot write %s: %w", fn, err)
+		}
+	}
+	return nil
+}
+
+// CgroupV2IOController controls a cgroup's IO settings on the unified cgroup v2 hierarchy
+type CgroupV2IOController string
+
+// Usage sums up the rbytes and wbytes of the device from io.stat
+func (basePath CgroupV2IOController) Usage(device string) (bytes uint64, err error) {
+	err = scanLines(filepath.Join(string(basePath), "io.stat"), func(fields []string) error {
+		// lines read "<major>:<minor> rbytes=<n> wbytes=<n> rios=<n> wios=<n> dbytes=<n> dios=<n>"
+		if len(fields) == 0 || fields[0] != device {
+			return nil
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 || (kv[0] != "rbytes" && kv[0] != "wbytes") {
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			bytes += v
+		}
+		return nil
+	})
+	return
+}
+
+// SetLimit sets the rbps and wbps of the device in io.max
+func (basePath CgroupV2IOController) SetLimit(device string, limit uint64) error {
+	bps := "max"
+	if limit > 0 {
+		bps = strconv.FormatUint(limit, 10)
+	}
+	err := os.WriteFile(filepath.Join(string(basePath), "io.max"), []byte(fmt.Sprintf("%s rbps=%s wbps=%s", device, bps, bps)), 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write io.max: %w", err)
+	}
+	return nil
+}
+
+func scanLines(fn string, f func(fields []string) error) error {
+	fd, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		err = f(strings.Fields(scanner.Text()))
+		if err != nil {
+			return xerrors.Errorf("cannot parse %s: %w", fn, err)
+		}
+	}
+	return scanner.Err()
+}
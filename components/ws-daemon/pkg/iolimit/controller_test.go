@@ -0,0 +1,87 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iolimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIOController(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Controller    func(dir string) IOController
+		StatFile      string
+		Stat          string
+		Limit         uint64
+		ExpectedUsage uint64
+		ExpectedFiles map[string]string
+	}{
+		{
+			Name:          "cgroup v1",
+			Controller:    func(dir string) IOController { return CgroupBlkioController(dir) },
+			StatFile:      "blkio.throttle.io_service_bytes",
+			Stat:          "8:0 Read 1000\n8:0 Write 2000\n8:0 Sync 3000\n8:0 Total 3000\n8:16 Read 500\nTotal 3500\n",
+			Limit:         1024,
+			ExpectedUsage: 3000,
+			ExpectedFiles: map[string]string{
+				"blkio.throttle.read_bps_device":  "8:0 1024",
+				"blkio.throttle.write_bps_device": "8:0 1024",
+			},
+		},
+		{
+			Name:          "cgroup v2",
+			Controller:    func(dir string) IOController { return CgroupV2IOController(dir) },
+			StatFile:      "io.stat",
+			Stat:          "8:16 rbytes=500 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n8:0 rbytes=1000 wbytes=2000 rios=10 wios=20 dbytes=0 dios=0\n",
+			Limit:         1024,
+			ExpectedUsage: 3000,
+			ExpectedFiles: map[string]string{
+				"io.max": "8:0 rbps=1024 wbps=1024",
+			},
+		},
+		{
+			Name:       "cgroup v2 unlimited",
+			Controller: func(dir string) IOController { return CgroupV2IOController(dir) },
+			StatFile:   "io.stat",
+			ExpectedFiles: map[string]string{
+				"io.max": "8:0 rbps=max wbps=max",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, test.StatFile), []byte(test.Stat), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := test.Controller(dir)
+			usage, err := ctrl.Usage("8:0")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if usage != test.ExpectedUsage {
+				t.Errorf("unexpected usage: expected %d, got %d", test.ExpectedUsage, usage)
+			}
+
+			err = ctrl.SetLimit("8:0", test.Limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for fn, expected := range test.ExpectedFiles {
+				fc, err := os.ReadFile(filepath.Join(dir, fn))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(fc) != expected {
+					t.Errorf("unexpected %s: expected %q, got %q", fn, expected, string(fc))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iolimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// Config configures the IO bandwidth limiting of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Devices are the block devices, given as "major:minor", which workspace content lives on
+	Devices []string `json:"devices"`
+	// ControlPeriod is the time between two adjustments of the IO limits
+	ControlPeriod util.Duration `json:"controlPeriod"`
+}
+
+// Limits are the IO limits of a workspace. All bandwidths are in bytes per second and apply to reads and writes alike.
+type Limits struct {
+	// Bandwidth is the bandwidth a workspace is limited to once it has used up its burst allowance
+	Bandwidth uint64
+	// BurstBandwidth is the bandwidth a workspace may use as long as it has burst allowance left
+	BurstBandwidth uint64
+	// BurstAllowance is the number of bytes a workspace may transfer at burst bandwidth. It replenishes at Bandwidth.
+	BurstAllowance uint64
+}
+
+// limitsFromAnnotations reads the IO limits ws-manager put on a workspace pod. Returns nil if the workspace has none.
+func limitsFromAnnotations(annotations map[string]string) (*Limits, error) {
+	parse := func(key string) (uint64, error) {
+		v, ok := annotations[key]
+		if !ok {
+			return 0, nil
+		}
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return 0, xerrors.Errorf("cannot parse %s: %w", key, err)
+		}
+		if q.Sign() < 0 {
+			return 0, xerrors.Errorf("%s must not be negative", key)
+		}
+		return uint64(q.Value()), nil
+	}
+
+	var (
+		res Limits
+		err error
+	)
+	res.Bandwidth, err = parse(wsk8s.IOBandwidthAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if res.Bandwidth == 0 {
+		return nil, nil
+	}
+	res.BurstBandwidth, err = parse(wsk8s.IOBurstBandwidthAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	res.BurstAllowance, err = parse(wsk8s.IOBurstAllowanceAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// nextLimit implements a token bucket: a workspace earns allowance at its base bandwidth and spends it on IO.
+// While it has allowance left it may use the burst bandwidth, otherwise it's limited to the base bandwidth.
+func nextLimit(l Limits, allowance uint64, consumed uint64, period time.Duration) (newAllowance uint64, limit uint64) {
+	if l.BurstBandwidth <= l.Bandwidth || l.BurstAllowance == 0 {
+		return 0, l.Bandwidth
+	}
+
+	earned := uint64(float64(l.Bandwidth) * period.Seconds())
+	newAllowance = allowance + earned
+	if consumed >= newAllowance {
+		return 0, l.Bandwidth
+	}
+	newAllowance -= consumed
+	if newAllowance > l.BurstAllowance {
+		newAllowance = l.BurstAllowance
+	}
+	return newAllowance, l.BurstBandwidth
+}
+
+// NewDispatchListener creates a new IO limiting dispatch listener. Unified determines if the node
+// uses the cgroup v2 hierarchy.
+func NewDispatchListener(cfg *Config, cgroupBasePath string, unified bool, prom prometheus.Registerer) *DispatchListener {
+	d := &DispatchListener{
+		Config:         cfg,
+		CGroupBasePath: cgroupBasePath,
+		Unified:        unified,
+		workspaces:     make(map[string]*workspace),
+
+		workspacesBurstingGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "iolimit_workspaces_bursting",
+			Help: "Number of workspaces which currently run with burst IO limits",
+		}),
+	}
+
+	if cfg.Enabled {
+		if len(cfg.Devices) == 0 {
+			log.Warn("IO limiting is enabled but no devices are configured")
+		}
+		go d.run(context.Background(), time.Duration(cfg.ControlPeriod))
+	}
+
+	prom.MustRegister(d.workspacesBurstingGauge)
+
+	return d
+}
+
+// DispatchListener limits the IO bandwidth of workspaces using the workspace dispatch
+type DispatchListener struct {
+	Config         *Config
+	CGroupBasePath string
+	Unified        bool
+
+	workspaces map[string]*workspace
+	mu         sync.Mutex
+
+	workspacesBurstingGauge prometheus.Gauge
+}
+
+type workspace struct {
+	IO     IOController
+	OWI    logrus.Fields
+	Limits Limits
+
+	allowance uint64
+	lastUsage uint64
+	limit     uint64
+}
+
+func (d *DispatchListener) run(ctx context.Context, period time.Duration) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		d.control(period)
+	}
+}
+
+func (d *DispatchListener) control(period time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var bursting int
+	for _, ws := range d.workspaces {
+		var usage uint64
+		for _, dev := range d.Config.Devices {
+			u, err := ws.IO.Usage(dev)
+			if err != nil {
+				log.WithFields(ws.OWI).WithError(err).WithField("device", dev).Warn("cannot read IO usage")
+				continue
+			}
+			usage += u
+		}
+
+		var consumed uint64
+		if usage > ws.lastUsage {
+			consumed = usage - ws.lastUsage
+		}
+		ws.lastUsage = usage
+
+		var limit uint64
+		ws.allowance, limit = nextLimit(ws.Limits, ws.allowance, consumed, period)
+		if limit > ws.Limits.Bandwidth {
+			bursting++
+		}
+		if limit == ws.limit {
+			continue
+		}
+
+		ws.limit = limit
+		for _, dev := range d.Config.Devices {
+			err := ws.IO.SetLimit(dev, limit)
+			if err != nil {
+				log.WithFields(ws.OWI).WithError(err).WithField("device", dev).Warn("cannot set IO limit")
+			}
+		}
+		log.WithFields(ws.OWI).WithField("limit", limit).Debug("applied new IO limit")
+	}
+	d.workspacesBurstingGauge.Set(float64(bursting))
+}
+
+// WorkspaceAdded puts a workspace under IO control if it has IO limits
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	if !d.Config.Enabled {
+		return nil
+	}
+
+	limits, err := limitsFromAnnotations(ws.Pod.Annotations)
+	if err != nil {
+		return xerrors.Errorf("cannot enforce IO limits: %w", err)
+	}
+	if limits == nil {
+		return nil
+	}
+
+	disp := dispatch.GetFromContext(ctx)
+	if disp == nil {
+		return xerrors.Errorf("no dispatch available")
+	}
+
+	cgroupPath, err := disp.Runtime.ContainerCGroupPath(context.Background(), ws.ContainerID)
+	if err != nil {
+		return xerrors.Errorf("cannot enforce IO limits: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.workspaces[ws.InstanceID] = &workspace{
+		IO:        NewIOController(d.CGroupBasePath, cgroupPath, d.Unified),
+		OWI:       ws.OWI(),
+		Limits:    *limits,
+		allowance: limits.BurstAllowance,
+	}
+	go func() {
+		<-ctx.Done()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.workspaces, ws.InstanceID)
+	}()
+
+	return nil
+}
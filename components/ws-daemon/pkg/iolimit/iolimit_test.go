@@ -0,0 +1,100 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iolimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+)
+
+func TestNextLimit(t *testing.T) {
+	const mib = 1024 * 1024
+	limits := Limits{Bandwidth: 10 * mib, BurstBandwidth: 100 * mib, BurstAllowance: 1024 * mib}
+
+	type Expectation struct {
+		Allowance uint64
+		Limit     uint64
+	}
+	tests := []struct {
+		Name        string
+		Limits      Limits
+		Allowance   uint64
+		Consumed    uint64
+		Expectation Expectation
+	}{
+		{Name: "no burst", Limits: Limits{Bandwidth: 10 * mib}, Consumed: 100 * mib, Expectation: Expectation{Limit: 10 * mib}},
+		{Name: "idle", Limits: limits, Allowance: 1024 * mib, Expectation: Expectation{Allowance: 1024 * mib, Limit: 100 * mib}},
+		{Name: "spending", Limits: limits, Allowance: 1024 * mib, Consumed: 600 * mib, Expectation: Expectation{Allowance: 574 * mib, Limit: 100 * mib}},
+		{Name: "used up", Limits: limits, Allowance: 500 * mib, Consumed: 700 * mib, Expectation: Expectation{Limit: 10 * mib}},
+		{Name: "replenishing", Limits: limits, Consumed: 50 * mib, Expectation: Expectation{Allowance: 100 * mib, Limit: 100 * mib}},
+		{Name: "consuming at base bandwidth", Limits: limits, Consumed: 150 * mib, Expectation: Expectation{Limit: 10 * mib}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			act.Allowance, act.Limit = nextLimit(test.Limits, test.Allowance, test.Consumed, 15*time.Second)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected nextLimit (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLimitsFromAnnotations(t *testing.T) {
+	type Expectation struct {
+		Limits *Limits
+		Error  string
+	}
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		Expectation Expectation
+	}{
+		{Name: "no limits"},
+		{
+			Name: "burst without bandwidth",
+			Annotations: map[string]string{
+				wsk8s.IOBurstBandwidthAnnotation: "100Mi",
+			},
+		},
+		{
+			Name: "all limits",
+			Annotations: map[string]string{
+				wsk8s.IOBandwidthAnnotation:      "10Mi",
+				wsk8s.IOBurstBandwidthAnnotation: "100Mi",
+				wsk8s.IOBurstAllowanceAnnotation: "1Gi",
+			},
+			Expectation: Expectation{Limits: &Limits{Bandwidth: 10 << 20, BurstBandwidth: 100 << 20, BurstAllowance: 1 << 30}},
+		},
+		{
+			Name: "negative bandwidth",
+			Annotations: map[string]string{
+				wsk8s.IOBandwidthAnnotation: "-10Mi",
+			},
+			Expectation: Expectation{Error: wsk8s.IOBandwidthAnnotation + " must not be negative"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			limits, err := limitsFromAnnotations(test.Annotations)
+			act.Limits = limits
+			if err != nil {
+				act.Error = err.Error()
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected limitsFromAnnotations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
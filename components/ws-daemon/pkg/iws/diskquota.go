@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iws
+
+import (
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+const (
+	// diskQuotaThreshold is the share of the disk quota at which we notify the workspace
+	diskQuotaThreshold = 0.9
+	// diskQuotaRecovered is the share of the disk quota below which we notify the workspace again
+	diskQuotaRecovered = 0.8
+	// diskQuotaInterval is the time between two disk usage checks
+	diskQuotaInterval = 30 * time.Second
+)
+
+// WatchDiskQuota notifies the workspace whenever its content comes close to the disk quota
+func (wbs *InWorkspaceServiceServer) WatchDiskQuota(req *api.WatchDiskQuotaRequest, srv api.InWorkspaceService_WatchDiskQuotaServer) error {
+	if wbs.XFS == nil || wbs.Session.XFSProjectID == 0 {
+		return status.Error(codes.FailedPrecondition, "workspace has no disk quota")
+	}
+	if !atomic.CompareAndSwapInt32(&wbs.diskQuotaWatched, 0, 1) {
+		return status.Error(codes.ResourceExhausted, "disk quota is already watched")
+	}
+	defer atomic.StoreInt32(&wbs.diskQuotaWatched, 0)
+
+	var (
+		ctx       = srv.Context()
+		nearQuota bool
+		t         = time.NewTicker(diskQuotaInterval)
+	)
+	defer t.Stop()
+	for {
+		used, limit, err := wbs.XFS.GetUsage(wbs.Session.XFSProjectID)
+		if err != nil {
+			log.WithError(err).WithFields(wbs.Session.OWI()).Warn("cannot read disk usage")
+		} else {
+			var notify bool
+			nearQuota, notify = checkDiskQuota(used, limit, nearQuota)
+			if notify {
+				err = srv.Send(&api.WatchDiskQuotaResponse{
+					UsedBytes:  int64(used),
+					QuotaBytes: int64(limit),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// checkDiskQuota determines whether the disk usage is close to the quota, and whether that's news worth notifying.
+// Once notified, usage has to drop below diskQuotaRecovered before we notify again.
+func checkDiskQuota(used, limit quota.Size, wasNearQuota bool) (nearQuota, notify bool) {
+	if limit == 0 {
+		return false, false
+	}
+
+	share := float64(used) / float64(limit)
+	switch {
+	case share >= diskQuotaThreshold:
+		return true, !wasNearQuota
+	case share < diskQuotaRecovered:
+		return false, false
+	default:
+		return wasNearQuota, false
+	}
+}
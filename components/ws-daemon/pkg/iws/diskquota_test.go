@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iws
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+func TestCheckDiskQuota(t *testing.T) {
+	type Expectation struct {
+		NearQuota bool
+		Notify    bool
+	}
+	tests := []struct {
+		Name         string
+		Used         quota.Size
+		Limit        quota.Size
+		WasNearQuota bool
+		Expectation  Expectation
+	}{
+		{Name: "no limit", Used: 10 * quota.Gigabyte},
+		{Name: "plenty of space", Used: 1 * quota.Gigabyte, Limit: 10 * quota.Gigabyte},
+		{Name: "near quota", Used: 9 * quota.Gigabyte, Limit: 10 * quota.Gigabyte, Expectation: Expectation{NearQuota: true, Notify: true}},
+		{Name: "still near quota", Used: 9 * quota.Gigabyte, Limit: 10 * quota.Gigabyte, WasNearQuota: true, Expectation: Expectation{NearQuota: true}},
+		{Name: "between thresholds", Used: 8500 * quota.Megabyte, Limit: 10000 * quota.Megabyte, WasNearQuota: true, Expectation: Expectation{NearQuota: true}},
+		{Name: "between thresholds without prior notification", Used: 8500 * quota.Megabyte, Limit: 10000 * quota.Megabyte},
+		{Name: "recovered", Used: 7 * quota.Gigabyte, Limit: 10 * quota.Gigabyte, WasNearQuota: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			act.NearQuota, act.Notify = checkDiskQuota(test.Used, test.Limit, test.WasNearQuota)
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected checkDiskQuota (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package iws
+
+import (
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+// WatchInitProgress streams the progress of the workspace content initialization until it's done
+func (wbs *InWorkspaceServiceServer) WatchInitProgress(req *api.WatchInitProgressRequest, srv api.InWorkspaceService_WatchInitProgressServer) error {
+	ctx := srv.Context()
+	for {
+		p, changed, done := wbs.Session.InitProgress()
+		err := srv.Send(&api.WatchInitProgressResponse{
+			Phase:      string(p.Phase),
+			Percent:    int32(p.Percent),
+			BytesDone:  p.BytesDone,
+			BytesTotal: p.BytesTotal,
+			Done:       done,
+		})
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/gitpod-io/gitpod/ws-daemon/api"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
 )
 
 //
@@ -77,7 +78,7 @@ var (
 )
 
 // ServeWorkspace establishes the IWS server for a workspace
-func ServeWorkspace(uidmapper *Uidmapper, fsshift api.FSShiftMethod) func(ctx context.Context, ws *session.Workspace) error {
+func ServeWorkspace(uidmapper *Uidmapper, fsshift api.FSShiftMethod, xfs *quota.XFS) func(ctx context.Context, ws *session.Workspace) error {
 	return func(ctx context.Context, ws *session.Workspace) (err error) {
 		if _, running := ws.NonPersistentAttrs[session.AttrWorkspaceServer]; running {
 			return nil
@@ -91,6 +92,7 @@ func ServeWorkspace(uidmapper *Uidmapper, fsshift api.FSShiftMethod) func(ctx co
 			Uidmapper: uidmapper,
 			Session:   ws,
 			FSShift:   fsshift,
+			XFS:       xfs,
 		}
 		err = helper.Start()
 		if err != nil {
@@ -130,6 +132,10 @@ type InWorkspaceServiceServer struct {
 	Uidmapper *Uidmapper
 	Session   *session.Workspace
 	FSShift   api.FSShiftMethod
+	// XFS enforces the workspace disk quota. It is nil if the filesystem does not support quotas.
+	XFS *quota.XFS
+
+	diskQuotaWatched int32
 
 	srv  *grpc.Server
 	sckt io.Closer
@@ -266,6 +272,7 @@ func (wbs *InWorkspaceServiceServer) PrepareForUserNS(ctx context.Context, req *
 		return &api.PrepareForUserNSResponse{
 			FsShift:             api.FSShiftMethod_FUSE,
 			FullWorkspaceBackup: wbs.Session.FullWorkspaceBackup,
+			SeccompProfile:      wbs.Session.SeccompProfile,
 		}, nil
 	}
 
@@ -290,6 +297,7 @@ func (wbs *InWorkspaceServiceServer) PrepareForUserNS(ctx context.Context, req *
 	return &api.PrepareForUserNSResponse{
 		FsShift:             api.FSShiftMethod_SHIFTFS,
 		FullWorkspaceBackup: wbs.Session.FullWorkspaceBackup,
+		SeccompProfile:      wbs.Session.SeccompProfile,
 	}, nil
 }
 
@@ -0,0 +1,257 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package memreclaim
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cpulimit"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// Config configures the proactive memory reclaim of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// PressureThreshold is the share of time in percent, averaged over ten seconds, in which some tasks on the node
+	// stalled on memory. Above it we reclaim memory from workspaces.
+	PressureThreshold float64 `json:"pressureThreshold"`
+	// ReclaimStep is the amount of memory, e.g. 256Mi, we ask the kernel to reclaim from a single workspace at a time
+	ReclaimStep resource.Quantity `json:"reclaimStep"`
+	// WorkspacesPerPeriod is the number of workspaces we reclaim memory from per control period. Defaults to 3.
+	WorkspacesPerPeriod int `json:"workspacesPerPeriod,omitempty"`
+	// ControlPeriod is the time between two checks of the node's memory pressure
+	ControlPeriod util.Duration `json:"controlPeriod"`
+}
+
+const (
+	defaultWorkspacesPerPeriod = 3
+
+	// nodePressureFile reports the pressure stall information of the whole node
+	nodePressureFile = "/proc/pressure/memory"
+)
+
+// NewDispatchListener creates a new memory reclaim dispatch listener. Reclaim requires
+// the unified cgroup hierarchy and Linux 5.19 or newer.
+func NewDispatchListener(cfg *Config, cgroupBasePath string, unified bool, prom prometheus.Registerer) *DispatchListener {
+	d := &DispatchListener{
+		Config:         cfg,
+		CGroupBasePath: cgroupBasePath,
+		workspaces:     make(map[string]*workspace),
+
+		reclaimedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "memreclaim_reclaimed_bytes_total",
+			Help: "Number of bytes reclaimed from workspaces due to node memory pressure",
+		}),
+		nodePressureGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "memreclaim_node_pressure_percent",
+			Help: "Share of time in which some tasks on the node stalled on memory, averaged over ten seconds",
+		}),
+	}
+
+	if cfg.Enabled && !unified {
+		log.Warn("memory reclaim requires the unified cgroup hierarchy - disabling it")
+		d.disabled = true
+	}
+	if cfg.Enabled && !d.disabled {
+		go d.run(context.Background(), time.Duration(cfg.ControlPeriod))
+	}
+
+	prom.MustRegister(d.reclaimedBytes, d.nodePressureGauge)
+
+	return d
+}
+
+// DispatchListener reclaims memory from workspaces when the node comes under memory pressure, idle workspaces first
+type DispatchListener struct {
+	Config         *Config
+	CGroupBasePath string
+
+	disabled   bool
+	workspaces map[string]*workspace
+	mu         sync.Mutex
+
+	reclaimedBytes    prometheus.Counter
+	nodePressureGauge prometheus.Gauge
+}
+
+type workspace struct {
+	CGroupPath string
+	OWI        logrus.Fields
+
+	lastCPUUsage cpulimit.CPUTime
+	// activity is the CPU time the workspace used during the last control period
+	activity cpulimit.CPUTime
+}
+
+func (d *DispatchListener) run(ctx context.Context, period time.Duration) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		d.control()
+	}
+}
+
+func (d *DispatchListener) control() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, ws := range d.workspaces {
+		usage, err := cpulimit.CgroupV2CFSController(ws.CGroupPath).Usage()
+		if err != nil {
+			log.WithFields(ws.OWI).WithError(err).Debug("cannot read CPU usage")
+			continue
+		}
+		if ws.lastCPUUsage > 0 {
+			ws.activity = usage - ws.lastCPUUsage
+		}
+		ws.lastCPUUsage = usage
+	}
+
+	pressure, err := readNodePressure(nodePressureFile)
+	if err != nil {
+		log.WithError(err).Warn("cannot read node memory pressure")
+		return
+	}
+	d.nodePressureGauge.Set(pressure)
+	if pressure < d.Config.PressureThreshold {
+		return
+	}
+
+	n := d.Config.WorkspacesPerPeriod
+	if n <= 0 {
+		n = defaultWorkspacesPerPeriod
+	}
+	step := uint64(d.Config.ReclaimStep.Value())
+	for _, ws := range mostIdle(d.workspaces, n) {
+		before, err := readUint64(ws.CGroupPath, "memory.current")
+		if err != nil {
+			log.WithFields(ws.OWI).WithError(err).Warn("cannot read memory usage")
+			continue
+		}
+		if before < step {
+			continue
+		}
+
+		// The kernel fails with EAGAIN if it could not reclaim the full amount, which is fine with us.
+		_ = os.WriteFile(filepath.Join(ws.CGroupPath, "memory.reclaim"), []byte(strconv.FormatUint(step, 10)), 0644)
+
+		after, err := readUint64(ws.CGroupPath, "memory.current")
+		if err != nil || after >= before {
+			continue
+		}
+		d.reclaimedBytes.Add(float64(before - after))
+		log.WithFields(ws.OWI).WithField("reclaimed", before-after).WithField("pressure", pressure).Debug("reclaimed workspace memory")
+	}
+}
+
+// mostIdle returns up to n workspaces which used the least CPU time during the last control period
+func mostIdle(workspaces map[string]*workspace, n int) []*workspace {
+	res := make([]*workspace, 0, len(workspaces))
+	for _, ws := range workspaces {
+		res = append(res, ws)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].activity < res[j].activity })
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// WorkspaceAdded makes the workspace eligible for memory reclaim
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	if !d.Config.Enabled || d.disabled {
+		return nil
+	}
+
+	disp := dispatch.GetFromContext(ctx)
+	if disp == nil {
+		return xerrors.Errorf("no dispatch available")
+	}
+
+	cgroupPath, err := disp.Runtime.ContainerCGroupPath(context.Background(), ws.ContainerID)
+	if err != nil {
+		return xerrors.Errorf("cannot start memory reclaim: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.workspaces[ws.InstanceID] = &workspace{
+		CGroupPath: filepath.Join(d.CGroupBasePath, cgroupPath),
+		OWI:        ws.OWI(),
+		// until we've observed them for a full control period, new workspaces count as busy
+		activity: cpulimit.CPUTime(math.MaxInt64),
+	}
+	go func() {
+		<-ctx.Done()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.workspaces, ws.InstanceID)
+	}()
+
+	return nil
+}
+
+func readNodePressure(fn string) (float64, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return parsePressure(f)
+}
+
+// parsePressure returns the "some avg10" value of pressure stall information
+func parsePressure(r io.Reader) (float64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "avg10=") {
+				continue
+			}
+			return strconv.ParseFloat(strings.TrimPrefix(f, "avg10="), 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, xerrors.Errorf("no \"some avg10\" value found")
+}
+
+func readUint64(cgroupPath, name string) (uint64, error) {
+	fc, err := os.ReadFile(filepath.Join(cgroupPath, name))
+	if err != nil {
+		return 0, xerrors.Errorf("cannot read %s: %w", name, err)
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(fc)), 10, 64)
+}
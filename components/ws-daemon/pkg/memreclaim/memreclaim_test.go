@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package memreclaim
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cpulimit"
+)
+
+func TestParsePressure(t *testing.T) {
+	type Expectation struct {
+		Pressure float64
+		Error    string
+	}
+	tests := []struct {
+		Name        string
+		Input       string
+		Expectation Expectation
+	}{
+		{
+			Name:        "some and full",
+			Input:       "some avg10=12.50 avg60=3.10 avg300=0.82 total=1234567\nfull avg10=4.00 avg60=1.00 avg300=0.20 total=234567\n",
+			Expectation: Expectation{Pressure: 12.5},
+		},
+		{
+			Name:        "no pressure",
+			Input:       "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			Expectation: Expectation{Pressure: 0},
+		},
+		{
+			Name:        "missing some",
+			Input:       "full avg10=4.00 avg60=1.00 avg300=0.20 total=234567\n",
+			Expectation: Expectation{Error: "no \"some avg10\" value found"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			p, err := parsePressure(strings.NewReader(test.Input))
+			if err != nil {
+				act.Error = err.Error()
+			} else {
+				act.Pressure = p
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected parsePressure (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMostIdle(t *testing.T) {
+	ws := func(path string, activity time.Duration) *workspace {
+		return &workspace{CGroupPath: path, activity: cpulimit.CPUTime(activity)}
+	}
+	workspaces := map[string]*workspace{
+		"busy":   ws("busy", 10*time.Second),
+		"idle":   ws("idle", 0),
+		"quiet":  ws("quiet", 100*time.Millisecond),
+		"active": ws("active", 2*time.Second),
+	}
+
+	tests := []struct {
+		Name        string
+		N           int
+		Expectation []string
+	}{
+		{Name: "fewer than available", N: 2, Expectation: []string{"idle", "quiet"}},
+		{Name: "all", N: 10, Expectation: []string{"idle", "quiet", "active", "busy"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act []string
+			for _, w := range mostIdle(workspaces, test.N) {
+				act = append(act, w.CGroupPath)
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected mostIdle (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package netlimit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// Config configures the egress shaping and metering of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Interface is the network interface within the workspace's network namespace which egress traffic leaves through.
+	// Defaults to eth0.
+	Interface string `json:"interface,omitempty"`
+	// DefaultEgressBandwidth is the egress bandwidth in bytes per second, e.g. 10Mi, of workspaces whose class
+	// does not specify one. If empty, such workspaces are metered but not shaped.
+	DefaultEgressBandwidth string `json:"defaultEgressBandwidth,omitempty"`
+	// MeteringPeriod is the time between two reads of the egress byte counters
+	MeteringPeriod util.Duration `json:"meteringPeriod"`
+}
+
+const defaultInterface = "eth0"
+
+// NewDispatchListener creates a new egress shaping and metering dispatch listener
+func NewDispatchListener(cfg *Config, prom prometheus.Registerer) *DispatchListener {
+	d := &DispatchListener{
+		Config:     cfg,
+		workspaces: make(map[string]*workspace),
+
+		egressBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "netlimit_egress_bytes_total",
+			Help: "Number of bytes workspaces sent over the network, by workspace owner",
+		}, []string{"owner"}),
+		workspacesShapedGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "netlimit_workspaces_shaped",
+			Help: "Number of workspaces whose egress bandwidth is shaped",
+		}),
+	}
+
+	if cfg.Enabled {
+		go d.run(context.Background(), time.Duration(cfg.MeteringPeriod))
+	}
+
+	prom.MustRegister(d.egressBytes, d.workspacesShapedGauge)
+
+	return d
+}
+
+// DispatchListener shapes and meters the egress traffic of workspaces using the workspace dispatch
+type DispatchListener struct {
+	Config *Config
+
+	workspaces map[string]*workspace
+	mu         sync.Mutex
+
+	egressBytes           *prometheus.CounterVec
+	workspacesShapedGauge prometheus.Gauge
+}
+
+type workspace struct {
+	PID    uint64
+	Owner  string
+	OWI    logrus.Fields
+	Shaped bool
+
+	lastTxBytes uint64
+}
+
+func (d *DispatchListener) run(ctx context.Context, period time.Duration) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		d.meter()
+	}
+}
+
+func (d *DispatchListener) meter() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var shaped int
+	for _, ws := range d.workspaces {
+		if ws.Shaped {
+			shaped++
+		}
+
+		tx, err := readTxBytes(ws.PID, d.iface())
+		if err != nil {
+			log.WithFields(ws.OWI).WithError(err).Warn("cannot read egress byte counter")
+			continue
+		}
+		if tx > ws.lastTxBytes {
+			d.egressBytes.WithLabelValues(ws.Owner).Add(float64(tx - ws.lastTxBytes))
+		}
+		ws.lastTxBytes = tx
+	}
+	d.workspacesShapedGauge.Set(float64(shaped))
+}
+
+func (d *DispatchListener) iface() string {
+	if d.Config.Interface == "" {
+		return defaultInterface
+	}
+	return d.Config.Interface
+}
+
+// WorkspaceAdded shapes the egress bandwidth of a workspace and starts metering its egress traffic
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	if !d.Config.Enabled {
+		return nil
+	}
+
+	bandwidth, err := egressBandwidth(ws.Pod.Annotations, d.Config.DefaultEgressBandwidth)
+	if err != nil {
+		return xerrors.Errorf("cannot shape egress traffic: %w", err)
+	}
+
+	disp := dispatch.GetFromContext(ctx)
+	if disp == nil {
+		return xerrors.Errorf("no dispatch available")
+	}
+
+	pid, err := disp.Runtime.ContainerPID(context.Background(), ws.ContainerID)
+	if err != nil {
+		return xerrors.Errorf("cannot find workspace container PID: %w", err)
+	}
+
+	if bandwidth > 0 {
+		cmd := exec.Command("nsenter", append([]string{"--target", strconv.FormatUint(pid, 10), "--net", "tc"}, tbfArgs(d.iface(), bandwidth)...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return xerrors.Errorf("cannot shape egress traffic: %w: %s", err, string(out))
+		}
+	}
+
+	// lastTxBytes starts at zero so that traffic sent before we got here, e.g. during content initialization, is metered too
+	ows := &workspace{
+		PID:    pid,
+		Owner:  ws.Pod.Labels[wsk8s.OwnerLabel],
+		OWI:    ws.OWI(),
+		Shaped: bandwidth > 0,
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.workspaces[ws.InstanceID] = ows
+	go func() {
+		<-ctx.Done()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.workspaces, ws.InstanceID)
+		for _, w := range d.workspaces {
+			if w.Owner == ows.Owner {
+				return
+			}
+		}
+		d.egressBytes.DeleteLabelValues(ows.Owner)
+	}()
+
+	return nil
+}
+
+// egressBandwidth returns the egress bandwidth in bytes per second a workspace is limited to, or zero if
+// the workspace's egress traffic is not to be shaped.
+func egressBandwidth(annotations map[string]string, defaultBandwidth string) (uint64, error) {
+	v, ok := annotations[wsk8s.EgressBandwidthAnnotation]
+	if !ok {
+		v = defaultBandwidth
+	}
+	if v == "" {
+		return 0, nil
+	}
+
+	q, err := resource.ParseQuantity(v)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot parse egress bandwidth: %w", err)
+	}
+	if q.Sign() < 0 {
+		return 0, xerrors.Errorf("egress bandwidth must not be negative")
+	}
+	return uint64(q.Value()), nil
+}
+
+// tbfArgs produces the tc arguments which install a token bucket filter limiting the egress of iface to bandwidth bytes per second
+func tbfArgs(iface string, bandwidth uint64) []string {
+	// tbf needs a bucket of at least rate/HZ to reach the configured rate. A tenth of a second's worth of traffic
+	// is comfortably above that for all common kernel configurations.
+	burst := bandwidth / 10
+	if burst < 32*1024 {
+		burst = 32 * 1024
+	}
+	return []string{
+		"qdisc", "replace", "dev", iface, "root", "tbf",
+		// tc understands "bps" as bytes per second
+		"rate", fmt.Sprintf("%dbps", bandwidth),
+		"burst", strconv.FormatUint(burst, 10),
+		"latency", "50ms",
+	}
+}
+
+func readTxBytes(pid uint64, iface string) (uint64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/net/dev", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return parseTxBytes(f, iface)
+}
+
+// parseTxBytes reads the number of transmitted bytes of iface from the content of /proc/net/dev
+func parseTxBytes(r io.Reader, iface string) (uint64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		segs := strings.SplitN(scanner.Text(), ":", 2)
+		if len(segs) != 2 || strings.TrimSpace(segs[0]) != iface {
+			continue
+		}
+
+		// the receive section has eight fields, transmitted bytes is the first field of the transmit section
+		fields := strings.Fields(segs[1])
+		if len(fields) < 9 {
+			return 0, xerrors.Errorf("unexpected number of fields for %s: %d", iface, len(fields))
+		}
+		return strconv.ParseUint(fields[8], 10, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, xerrors.Errorf("interface %s not found", iface)
+}
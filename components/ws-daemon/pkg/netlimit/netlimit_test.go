@@ -0,0 +1,108 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package netlimit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+)
+
+const procNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    2776      32    0    0    0     0          0         0     2776      32    0    0    0     0       0          0
+  eth0: 12904871    9811    0    0    0     0          0         0  1387419    7245    0    0    0     0       0          0
+`
+
+func TestParseTxBytes(t *testing.T) {
+	type Expectation struct {
+		Bytes uint64
+		Error string
+	}
+	tests := []struct {
+		Name        string
+		Input       string
+		Interface   string
+		Expectation Expectation
+	}{
+		{Name: "eth0", Input: procNetDev, Interface: "eth0", Expectation: Expectation{Bytes: 1387419}},
+		{Name: "lo", Input: procNetDev, Interface: "lo", Expectation: Expectation{Bytes: 2776}},
+		{Name: "unknown interface", Input: procNetDev, Interface: "eth1", Expectation: Expectation{Error: "interface eth1 not found"}},
+		{Name: "truncated", Input: "eth0: 1 2 3\n", Interface: "eth0", Expectation: Expectation{Error: "unexpected number of fields for eth0: 3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			b, err := parseTxBytes(strings.NewReader(test.Input), test.Interface)
+			if err != nil {
+				act.Error = err.Error()
+			} else {
+				act.Bytes = b
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected parseTxBytes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEgressBandwidth(t *testing.T) {
+	type Expectation struct {
+		Bandwidth uint64
+		Error     string
+	}
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		Default     string
+		Expectation Expectation
+	}{
+		{Name: "none"},
+		{Name: "default", Default: "10Mi", Expectation: Expectation{Bandwidth: 10 * 1024 * 1024}},
+		{Name: "annotation", Annotations: map[string]string{wsk8s.EgressBandwidthAnnotation: "1Mi"}, Default: "10Mi", Expectation: Expectation{Bandwidth: 1024 * 1024}},
+		{Name: "negative", Annotations: map[string]string{wsk8s.EgressBandwidthAnnotation: "-1Mi"}, Expectation: Expectation{Error: "egress bandwidth must not be negative"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act Expectation
+			b, err := egressBandwidth(test.Annotations, test.Default)
+			if err != nil {
+				act.Error = err.Error()
+			} else {
+				act.Bandwidth = b
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected egressBandwidth (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTbfArgs(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Bandwidth   uint64
+		Expectation []string
+	}{
+		{Name: "small bandwidth", Bandwidth: 1000, Expectation: []string{"qdisc", "replace", "dev", "eth0", "root", "tbf", "rate", "1000bps", "burst", "32768", "latency", "50ms"}},
+		{Name: "large bandwidth", Bandwidth: 10000000, Expectation: []string{"qdisc", "replace", "dev", "eth0", "root", "tbf", "rate", "10000000bps", "burst", "1000000", "latency", "50ms"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := tbfArgs("eth0", test.Bandwidth)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected tbfArgs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
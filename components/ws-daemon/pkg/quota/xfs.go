@@ -144,6 +144,29 @@ func (xfs *XFS) RemoveQuota(projectID int) error {
 	return nil
 }
 
+// GetUsage returns the disk space used by a project and its hard limit. The limit is zero if the project has none.
+func (xfs *XFS) GetUsage(projectID int) (used, limit Size, err error) {
+	out, err := xfs.exec(xfs.Dir, fmt.Sprintf("quota -p -N -b %d", projectID))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// the output reads "<device> <used> <soft> <hard> <warn> <grace>" with all sizes in kilobytes
+	fields := strings.Fields(out)
+	if len(fields) < 4 {
+		return 0, 0, fmt.Errorf("cannot parse xfs_quota output: %s", out)
+	}
+	u, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot parse used blocks: %w", err)
+	}
+	l, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot parse block hard limit: %w", err)
+	}
+	return Size(u) * Kilobyte, Size(l) * Kilobyte, nil
+}
+
 // GetProjectUseCount returns the number of projectIDs in use
 func (xfs *XFS) GetProjectUseCount() int {
 	xfs.mu.Lock()
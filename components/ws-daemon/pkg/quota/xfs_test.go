@@ -174,3 +174,76 @@ func TestSetQuota(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUsage(t *testing.T) {
+	type Expectation struct {
+		Used    Size
+		Limit   Size
+		Command string
+		Error   string
+	}
+	tests := []struct {
+		Name        string
+		Input       string
+		InputErr    error
+		Expectation Expectation
+	}{
+		{
+			Name:  "used project",
+			Input: "/dev/sdb        9437184   10485760   10485760   00 [--------]\n",
+			Expectation: Expectation{
+				Used:  9 * Gigabyte,
+				Limit: 10 * Gigabyte,
+			},
+		},
+		{
+			Name:  "no limit",
+			Input: "/dev/sdb              4          0          0   00 [--------]\n",
+			Expectation: Expectation{
+				Used: 4 * Kilobyte,
+			},
+		},
+		{
+			Name:  "unexpected output",
+			Input: "foobar",
+			Expectation: Expectation{
+				Error: "cannot parse xfs_quota output: foobar",
+			},
+		},
+		{
+			Name:     "exec failure",
+			InputErr: fmt.Errorf("exec failed"),
+			Expectation: Expectation{
+				Error: "exec failed",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var cmd string
+			xfs := &XFS{
+				exec: func(dir, command string) (output string, err error) {
+					cmd = command
+					return test.Input, test.InputErr
+				},
+			}
+
+			var (
+				act Expectation
+				err error
+			)
+			act.Used, act.Limit, err = xfs.GetUsage(1000)
+			if err != nil {
+				act.Error = err.Error()
+			}
+			if cmd != "quota -p -N -b 1000" {
+				t.Errorf("unexpected command: %s", cmd)
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected GetUsage (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
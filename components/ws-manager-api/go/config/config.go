@@ -8,20 +8,25 @@ import (
 	"bytes"
 	"html/template"
 	iofs "io/fs"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/gitpod-io/gitpod/common-go/grpc"
 	"github.com/gitpod-io/gitpod/common-go/util"
 	cntntcfg "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
 )
 
 type osFS struct{}
@@ -107,6 +112,324 @@ type Configuration struct {
 	RegistryFacadeHost string `json:"registryFacadeHost"`
 	// Cluster host under which workspaces are served, e.g. ws-eu11.gitpod.io
 	WorkspaceClusterHost string `json:"workspaceClusterHost"`
+	// Region is the region the cluster runs in, e.g. europe-west1. DescribeCluster reports it so that clients
+	// can start workspaces close to their users.
+	Region string `json:"region,omitempty"`
+	// WorkspaceClasses are named classes of workspaces with their own resources, timeout and node selector.
+	// StartWorkspace requests select a class by name. Workspaces which don't name a class use the DefaultWorkspaceClass,
+	// or the container and timeouts configuration if there is no default class.
+	WorkspaceClasses map[string]*WorkspaceClass `json:"workspaceClasses,omitempty"`
+	// DefaultWorkspaceClass is the class of workspaces whose StartWorkspace request doesn't name one
+	DefaultWorkspaceClass string `json:"defaultWorkspaceClass,omitempty"`
+	// SchedulingHints are additional affinity rules, tolerations and topology spread constraints of workspace pods
+	// by workspace type (regular, prebuild, probe, ghost or imagebuild), e.g. to pin prebuilds to spot nodes.
+	SchedulingHints map[string]*SchedulingHints `json:"schedulingHints,omitempty"`
+	// GPU configures the GPUs workspaces can request. If nil, workspaces cannot request GPUs.
+	GPU *GPUConfiguration `json:"gpu,omitempty"`
+	// StartAdmission configures an external policy endpoint which admits workspace starts. If nil, all valid
+	// start requests are admitted.
+	StartAdmission *StartAdmissionConfiguration `json:"startAdmission,omitempty"`
+	// NetworkPolicy is the template of the network policy ws-manager creates for each workspace. If nil, workspaces
+	// are only subject to the network policies of the installation.
+	NetworkPolicy *NetworkPolicyTemplate `json:"networkPolicy,omitempty"`
+	// InjectedContainers are init containers and sidecars which are added to workspace pods, e.g. to install
+	// a corporate CA certificate or to run a license scanner.
+	InjectedContainers []InjectedContainer `json:"injectedContainers,omitempty"`
+}
+
+// InjectedContainer is a container which is added to workspace pods
+type InjectedContainer struct {
+	// Container is the container to add. Its resources are set from requests and limits.
+	Container corev1.Container `json:"container"`
+	// Init makes the container an init container which runs to completion before the workspace starts.
+	// Otherwise the container runs as a sidecar next to the workspace.
+	Init bool `json:"init,omitempty"`
+	// Order is the position of the container among the injected init containers or sidecars. Lower orders
+	// come first, containers of the same order keep the order they're configured in.
+	Order int `json:"order,omitempty"`
+	// Requests are the resource requests of the container
+	Requests ResourceConfiguration `json:"requests"`
+	// Limits are the resource limits of the container. They're mandatory so that the container cannot eat into
+	// the resources of the workspace.
+	Limits ResourceConfiguration `json:"limits"`
+	// WorkspaceTypes are the types of workspaces (regular, prebuild, probe, ghost or imagebuild) the container is
+	// added to. If empty, the container is added to all workspaces.
+	WorkspaceTypes []string `json:"workspaceTypes,omitempty"`
+}
+
+// Validate validates an injected container
+func (c *InjectedContainer) Validate() error {
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Requests, validResourceConfig),
+		validation.Field(&c.Limits, validResourceConfig),
+	)
+	if err != nil {
+		return err
+	}
+	if c.Container.Name == "" || c.Container.Image == "" {
+		return xerrors.Errorf("container must have a name and an image")
+	}
+	if c.Limits.CPU == "" || c.Limits.Memory == "" {
+		return xerrors.Errorf("limits must include cpu and memory")
+	}
+	requests, err := c.Requests.ResourceList()
+	if err != nil {
+		return xerrors.Errorf("requests: %w", err)
+	}
+	limits, err := c.Limits.ResourceList()
+	if err != nil {
+		return xerrors.Errorf("limits: %w", err)
+	}
+	for name, q := range requests {
+		if l, ok := limits[name]; ok && q.Cmp(l) > 0 {
+			return xerrors.Errorf("%s request must not exceed its limit", name)
+		}
+	}
+	for _, tpe := range c.WorkspaceTypes {
+		if _, ok := api.WorkspaceType_value[strings.ToUpper(tpe)]; !ok {
+			return xerrors.Errorf("unknown workspace type %s", tpe)
+		}
+	}
+	return nil
+}
+
+// NetworkPolicyTemplate configures the network policy of each workspace. Workspaces which get their own policy aren't
+// selected by the default workspace network policy, hence all traffic which isn't allowed here is denied - including
+// traffic between workspaces.
+type NetworkPolicyTemplate struct {
+	// Ingress are the rules for traffic into the workspace, e.g. from proxy and ws-daemon
+	Ingress []networkingv1.NetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress are the rules for traffic leaving the workspace
+	Egress []networkingv1.NetworkPolicyEgressRule `json:"egress,omitempty"`
+	// EgressCIDRs are the IP ranges workspaces can connect to in addition to what the egress rules allow
+	EgressCIDRs []string `json:"egressCIDRs,omitempty"`
+}
+
+// Validate validates a network policy template
+func (t *NetworkPolicyTemplate) Validate() error {
+	for _, cidr := range t.EgressCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return xerrors.Errorf("egressCIDRs: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartAdmissionConfiguration configures the external policy endpoint workspace starts are admitted by
+type StartAdmissionConfiguration struct {
+	// URL is the HTTP endpoint we POST start requests to
+	URL string `json:"url"`
+	// Timeout is the time the endpoint has to make its decision. Defaults to 5 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// FailOpen admits workspace starts if the endpoint cannot be reached or fails. By default such starts are rejected.
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// GPUConfiguration configures the GPUs workspaces can request
+type GPUConfiguration struct {
+	// ResourceName is the extended resource name the GPU device plugin advertises. Defaults to nvidia.com/gpu.
+	ResourceName string `json:"resourceName,omitempty"`
+	// MaxCount is the maximum number of GPUs a single workspace can request
+	MaxCount uint32 `json:"maxCount"`
+	// Types maps the GPU types workspaces can request to the node selector of the nodes which have them,
+	// e.g. {"t4": {"cloud.google.com/gke-accelerator": "nvidia-tesla-t4"}}. Workspaces which don't name a
+	// type can run on any node with GPUs.
+	Types map[string]map[string]string `json:"types,omitempty"`
+}
+
+// DefaultGPUResourceName is the resource name of GPUs if the configuration does not name one
+const DefaultGPUResourceName = "nvidia.com/gpu"
+
+// GetResourceName returns the extended resource name of GPUs
+func (c *GPUConfiguration) GetResourceName() string {
+	if c.ResourceName == "" {
+		return DefaultGPUResourceName
+	}
+	return c.ResourceName
+}
+
+// WorkspaceClass configures the resources and scheduling of a class of workspaces
+type WorkspaceClass struct {
+	// Requests are the resource requests of the workspace container. They replace container.workspace.requests.
+	Requests ResourceConfiguration `json:"requests"`
+	// Limits are the resource limits of the workspace container. They replace container.workspace.limits.
+	Limits ResourceConfiguration `json:"limits"`
+	// Timeout is the time a regular workspace of this class can be without activity before it's shut down.
+	// Defaults to timeouts.regularWorkspace.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// NodeSelector restricts the nodes workspaces of this class are scheduled on
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// PVC stores /workspace of workspaces of this class on a persistent volume claim, and backs it up
+	// using volume snapshots instead of uploading it to remote storage. If nil, /workspace lives on the node.
+	PVC *PVCConfiguration `json:"pvc,omitempty"`
+	// SchedulingHints are additional scheduling constraints of workspaces of this class. They add to the
+	// scheduling hints of the workspace type.
+	SchedulingHints *SchedulingHints `json:"schedulingHints,omitempty"`
+	// SnapshotPolicy makes ws-manager take periodic snapshots of running regular workspaces of this class.
+	// Start requests can override this policy.
+	SnapshotPolicy *SnapshotPolicy `json:"snapshotPolicy,omitempty"`
+	// StorageQuota is the disk quota of the workspace content of this class, e.g. 30Gi. ws-daemon enforces it and
+	// warns users who come close to it. If empty, ws-daemon applies its own default.
+	StorageQuota string `json:"storageQuota,omitempty"`
+	// IOLimits limit the disk bandwidth of workspaces of this class. ws-daemon enforces them if IO limiting is enabled.
+	IOLimits *IOLimitConfiguration `json:"ioLimits,omitempty"`
+	// EgressBandwidth limits the network egress of workspaces of this class in bytes per second, e.g. 10Mi.
+	// ws-daemon enforces it if egress shaping is enabled. If empty, ws-daemon applies its own default.
+	EgressBandwidth string `json:"egressBandwidth,omitempty"`
+	// SeccompProfile names the seccomp filter workspacekit installs in workspaces of this class, "default" or "restricted".
+	// It applies in addition to the seccomp profile of the workspace pod. If empty, the default filter applies.
+	SeccompProfile string `json:"seccompProfile,omitempty"`
+}
+
+// IOLimitConfiguration configures the disk bandwidth of workspaces. Bandwidths are quantities of bytes per second,
+// e.g. 50Mi, and apply to reads and writes alike.
+type IOLimitConfiguration struct {
+	// Bandwidth is the bandwidth a workspace is limited to once it has used up its burst allowance
+	Bandwidth string `json:"bandwidth"`
+	// BurstBandwidth is the bandwidth a workspace may use while it has burst allowance left
+	BurstBandwidth string `json:"burstBandwidth,omitempty"`
+	// BurstAllowance is the amount of data, e.g. 5Gi, a workspace may transfer at burst bandwidth.
+	// It replenishes at the regular bandwidth.
+	BurstAllowance string `json:"burstAllowance,omitempty"`
+}
+
+// Validate validates an IO limit configuration
+func (c *IOLimitConfiguration) Validate() error {
+	bandwidth, err := resource.ParseQuantity(c.Bandwidth)
+	if err != nil {
+		return xerrors.Errorf("bandwidth: %w", err)
+	}
+	if bandwidth.Sign() <= 0 {
+		return xerrors.Errorf("bandwidth must be positive")
+	}
+	if c.BurstBandwidth == "" && c.BurstAllowance == "" {
+		return nil
+	}
+
+	burst, err := resource.ParseQuantity(c.BurstBandwidth)
+	if err != nil {
+		return xerrors.Errorf("burstBandwidth: %w", err)
+	}
+	if burst.Cmp(bandwidth) <= 0 {
+		return xerrors.Errorf("burstBandwidth must be greater than bandwidth")
+	}
+	allowance, err := resource.ParseQuantity(c.BurstAllowance)
+	if err != nil {
+		return xerrors.Errorf("burstAllowance: %w", err)
+	}
+	if allowance.Sign() <= 0 {
+		return xerrors.Errorf("burstAllowance must be positive")
+	}
+	return nil
+}
+
+// minSnapshotInterval is the shortest interval at which we take periodic snapshots of a workspace
+const minSnapshotInterval = time.Minute
+
+// SnapshotPolicy configures periodic snapshots of running workspaces
+type SnapshotPolicy struct {
+	// Interval is the time between two snapshots of a running workspace
+	Interval util.Duration `json:"interval"`
+	// Keep is the number of periodic snapshots we keep per workspace. Older ones are deleted.
+	Keep int `json:"keep"`
+}
+
+// Validate validates a snapshot policy
+func (p *SnapshotPolicy) Validate() error {
+	if time.Duration(p.Interval) < minSnapshotInterval {
+		return xerrors.Errorf("interval must be at least %s", minSnapshotInterval)
+	}
+	if p.Keep <= 0 {
+		return xerrors.Errorf("keep must be greater than zero")
+	}
+	return nil
+}
+
+// SchedulingHints are scheduling constraints which are added to those ws-manager sets on workspace pods
+type SchedulingHints struct {
+	// Affinity is combined with the workspace pod's affinity. Required node affinity terms must be met
+	// in addition to the existing ones.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations are added to the tolerations of the workspace pod
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// TopologySpreadConstraints are added to the topology spread constraints of the workspace pod
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// PVCConfiguration configures the persistent volume claims workspaces store their content on
+type PVCConfiguration struct {
+	// Size is the size of the persistent volume claim, e.g. 30Gi
+	Size string `json:"size"`
+	// StorageClass is the storage class of the persistent volume claim. If empty, the cluster's default storage class is used.
+	StorageClass string `json:"storageClass,omitempty"`
+	// SnapshotClass is the volume snapshot class of the snapshots taken when workspaces stop.
+	// If empty, the cluster's default volume snapshot class is used.
+	SnapshotClass string `json:"snapshotClass,omitempty"`
+}
+
+// Validate validates a workspace class
+func (c *WorkspaceClass) Validate() error {
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Requests, validResourceConfig),
+		validation.Field(&c.Limits, validResourceConfig),
+		validation.Field(&c.SeccompProfile, validation.In("default", "restricted")),
+	)
+	if err != nil {
+		return err
+	}
+	if c.Timeout < 0 {
+		return xerrors.Errorf("timeout must be >= 0")
+	}
+	if c.PVC != nil {
+		if _, err := resource.ParseQuantity(c.PVC.Size); err != nil {
+			return xerrors.Errorf("pvc.size: %w", err)
+		}
+	}
+	if c.SnapshotPolicy != nil {
+		if err := c.SnapshotPolicy.Validate(); err != nil {
+			return xerrors.Errorf("snapshotPolicy: %w", err)
+		}
+	}
+	if c.StorageQuota != "" {
+		q, err := resource.ParseQuantity(c.StorageQuota)
+		if err != nil {
+			return xerrors.Errorf("storageQuota: %w", err)
+		}
+		if q.Sign() <= 0 {
+			return xerrors.Errorf("storageQuota must be positive")
+		}
+	}
+	if c.IOLimits != nil {
+		if err := c.IOLimits.Validate(); err != nil {
+			return xerrors.Errorf("ioLimits: %w", err)
+		}
+	}
+	if c.EgressBandwidth != "" {
+		q, err := resource.ParseQuantity(c.EgressBandwidth)
+		if err != nil {
+			return xerrors.Errorf("egressBandwidth: %w", err)
+		}
+		if q.Sign() <= 0 {
+			return xerrors.Errorf("egressBandwidth must be positive")
+		}
+	}
+	return nil
+}
+
+// GetWorkspaceClass returns the workspace class of the given name, or the default class if name is empty.
+// Returns nil if name is empty and there is no default class.
+func (c *Configuration) GetWorkspaceClass(name string) (*WorkspaceClass, error) {
+	if name == "" {
+		name = c.DefaultWorkspaceClass
+	}
+	if name == "" {
+		return nil, nil
+	}
+	class, ok := c.WorkspaceClasses[name]
+	if !ok {
+		return nil, xerrors.Errorf("unknown workspace class: %s", name)
+	}
+	return class, nil
 }
 
 // AllContainerConfiguration contains the configuration for all container in a workspace pod
@@ -133,6 +456,35 @@ type WorkspaceTimeoutConfiguration struct {
 	Stopping util.Duration `json:"stopping"`
 	// Interrupted is the time a workspace may be interrupted (since it last saw activity or since it was created if it never saw any)
 	Interrupted util.Duration `json:"interrupted"`
+	// MinCustom is the shortest custom timeout a workspace can be started with or set to. Zero means there's no lower bound.
+	MinCustom util.Duration `json:"minCustom,omitempty"`
+	// MaxCustom is the longest custom timeout a workspace can be started with or set to. Zero means there's no upper bound.
+	MaxCustom util.Duration `json:"maxCustom,omitempty"`
+}
+
+// CheckCustom returns an error if a custom timeout lies outside the configured bounds
+func (c *WorkspaceTimeoutConfiguration) CheckCustom(timeout time.Duration) error {
+	if timeout <= 0 {
+		return xerrors.Errorf("timeout must be greater than zero")
+	}
+	if c.MinCustom > 0 && timeout < time.Duration(c.MinCustom) {
+		return xerrors.Errorf("timeout must be at least %s", time.Duration(c.MinCustom))
+	}
+	if c.MaxCustom > 0 && timeout > time.Duration(c.MaxCustom) {
+		return xerrors.Errorf("timeout must be at most %s", time.Duration(c.MaxCustom))
+	}
+	return nil
+}
+
+// ClampCustom limits a custom timeout to the configured bounds
+func (c *WorkspaceTimeoutConfiguration) ClampCustom(timeout time.Duration) time.Duration {
+	if c.MinCustom > 0 && timeout < time.Duration(c.MinCustom) {
+		return time.Duration(c.MinCustom)
+	}
+	if c.MaxCustom > 0 && timeout > time.Duration(c.MaxCustom) {
+		return time.Duration(c.MaxCustom)
+	}
+	return timeout
 }
 
 // InitProbeConfiguration configures the behaviour of the workspace ready probe
@@ -160,6 +512,8 @@ type WorkspacePodTemplateConfiguration struct {
 	GhostPath string `json:"ghostPath,omitempty"`
 	// ImagebuildPath is a path to an additional workspace pod template YAML file for imagebuild workspaces
 	ImagebuildPath string `json:"imagebuildPath,omitempty"`
+	// GPUPath is a path to an additional workspace pod template YAML file for workspaces which request GPUs
+	GPUPath string `json:"gpuPath,omitempty"`
 }
 
 // WorkspaceDaemonConfiguration configures our connection to the workspace sync daemons runnin on the nodes
@@ -198,6 +552,12 @@ func (c *Configuration) Validate() error {
 	if c.Timeouts.Stopping < c.Timeouts.ContentFinalization {
 		return xerrors.Errorf("stopping timeout must be greater than content finalization timeout")
 	}
+	if c.Timeouts.MinCustom < 0 || c.Timeouts.MaxCustom < 0 {
+		return xerrors.Errorf("custom timeout bounds must be >= 0")
+	}
+	if c.Timeouts.MaxCustom > 0 && c.Timeouts.MaxCustom < c.Timeouts.MinCustom {
+		return xerrors.Errorf("maxCustom timeout must be greater than minCustom timeout")
+	}
 
 	err = validation.ValidateStruct(&c.WorkspacePodTemplate,
 		validation.Field(&c.WorkspacePodTemplate.DefaultPath, validPodTemplate),
@@ -205,11 +565,56 @@ func (c *Configuration) Validate() error {
 		validation.Field(&c.WorkspacePodTemplate.ProbePath, validPodTemplate),
 		validation.Field(&c.WorkspacePodTemplate.GhostPath, validPodTemplate),
 		validation.Field(&c.WorkspacePodTemplate.RegularPath, validPodTemplate),
+		validation.Field(&c.WorkspacePodTemplate.GPUPath, validPodTemplate),
 	)
 	if err != nil {
 		return xerrors.Errorf("workspacePodTemplate: %w", err)
 	}
 
+	for name, class := range c.WorkspaceClasses {
+		if class == nil {
+			return xerrors.Errorf("workspaceClasses.%s: must not be empty", name)
+		}
+		if err := class.Validate(); err != nil {
+			return xerrors.Errorf("workspaceClasses.%s: %w", name, err)
+		}
+	}
+	if _, err := c.GetWorkspaceClass(""); err != nil {
+		return xerrors.Errorf("defaultWorkspaceClass: %w", err)
+	}
+	for tpe := range c.SchedulingHints {
+		if _, ok := api.WorkspaceType_value[strings.ToUpper(tpe)]; !ok {
+			return xerrors.Errorf("schedulingHints: unknown workspace type %s", tpe)
+		}
+	}
+	if c.GPU != nil && c.GPU.MaxCount == 0 {
+		return xerrors.Errorf("gpu.maxCount: must be > 0")
+	}
+	if c.StartAdmission != nil {
+		err = validation.ValidateStruct(c.StartAdmission,
+			validation.Field(&c.StartAdmission.URL, validation.Required, is.URL),
+		)
+		if err != nil {
+			return xerrors.Errorf("startAdmission: %w", err)
+		}
+	}
+	if c.NetworkPolicy != nil {
+		if err := c.NetworkPolicy.Validate(); err != nil {
+			return xerrors.Errorf("networkPolicy: %w", err)
+		}
+	}
+	containerNames := map[string]struct{}{"workspace": {}}
+	for i := range c.InjectedContainers {
+		ic := &c.InjectedContainers[i]
+		if err := ic.Validate(); err != nil {
+			return xerrors.Errorf("injectedContainers[%d]: %w", i, err)
+		}
+		if _, exists := containerNames[ic.Container.Name]; exists {
+			return xerrors.Errorf("injectedContainers[%d]: container name %s is already taken", i, ic.Container.Name)
+		}
+		containerNames[ic.Container.Name] = struct{}{}
+	}
+
 	err = validation.ValidateStruct(c,
 		validation.Field(&c.WorkspaceURLTemplate, validation.Required, validWorkspaceURLTemplate),
 		validation.Field(&c.WorkspaceHostPath, validation.Required),
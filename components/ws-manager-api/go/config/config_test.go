@@ -6,6 +6,9 @@ package config
 
 import (
 	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
 )
 
 func BenchmarkRenderWorkspacePortURL(b *testing.B) {
@@ -15,3 +18,39 @@ func BenchmarkRenderWorkspacePortURL(b *testing.B) {
 		RenderWorkspaceURL("{{.Port}}-{{.Prefix}}.{{.Host}}", "foo", "bar", "gitpod.io")
 	}
 }
+
+func TestCustomTimeoutBounds(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Timeout     time.Duration
+		Min         time.Duration
+		Max         time.Duration
+		ExpectError bool
+		Clamped     time.Duration
+	}{
+		{Name: "no bounds", Timeout: 10 * time.Hour, Clamped: 10 * time.Hour},
+		{Name: "zero timeout", Timeout: 0, ExpectError: true},
+		{Name: "negative timeout", Timeout: -time.Minute, ExpectError: true, Clamped: -time.Minute},
+		{Name: "within bounds", Timeout: time.Hour, Min: 30 * time.Minute, Max: 2 * time.Hour, Clamped: time.Hour},
+		{Name: "below min", Timeout: 10 * time.Minute, Min: 30 * time.Minute, Max: 2 * time.Hour, ExpectError: true, Clamped: 30 * time.Minute},
+		{Name: "above max", Timeout: 3 * time.Hour, Min: 30 * time.Minute, Max: 2 * time.Hour, ExpectError: true, Clamped: 2 * time.Hour},
+		{Name: "min only", Timeout: 3 * time.Hour, Min: 30 * time.Minute, Clamped: 3 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := WorkspaceTimeoutConfiguration{
+				MinCustom: util.Duration(test.Min),
+				MaxCustom: util.Duration(test.Max),
+			}
+
+			err := cfg.CheckCustom(test.Timeout)
+			if (err != nil) != test.ExpectError {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if act := cfg.ClampCustom(test.Timeout); act != test.Clamped {
+				t.Errorf("unexpected clamped timeout: want %s, got %s", test.Clamped, act)
+			}
+		})
+	}
+}
@@ -44,6 +44,20 @@ type WorkspaceManagerClient interface {
 	TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error)
 	// controlAdmission makes a workspace accessible for everyone or for the owner only
 	ControlAdmission(ctx context.Context, in *ControlAdmissionRequest, opts ...grpc.CallOption) (*ControlAdmissionResponse, error)
+	// drainNode stops new workspaces from being scheduled onto a node and backs up the workspaces running on it,
+	// e.g. ahead of a cluster upgrade. It streams the progress of the drain.
+	DrainNode(ctx context.Context, in *DrainNodeRequest, opts ...grpc.CallOption) (WorkspaceManager_DrainNodeClient, error)
+	// getHeadlessLog streams the log of a headless workspace's task, e.g. of a prebuild, while the task runs.
+	// The stream ends once the task is done.
+	GetHeadlessLog(ctx context.Context, in *GetHeadlessLogRequest, opts ...grpc.CallOption) (WorkspaceManager_GetHeadlessLogClient, error)
+	// describeCluster describes the cluster this workspace manager operates in, e.g. its region, capacity and
+	// the features it supports, so that clients can choose the cluster best suited to start a workspace in.
+	DescribeCluster(ctx context.Context, in *DescribeClusterRequest, opts ...grpc.CallOption) (*DescribeClusterResponse, error)
+	// pauseWorkspace backs up a running workspace and removes its pod, but remembers the pod so that the workspace
+	// can be resumed with the same configuration, e.g. its exposed ports and environment.
+	PauseWorkspace(ctx context.Context, in *PauseWorkspaceRequest, opts ...grpc.CallOption) (*PauseWorkspaceResponse, error)
+	// resumeWorkspace restores a paused workspace, possibly on a different node.
+	ResumeWorkspace(ctx context.Context, in *ResumeWorkspaceRequest, opts ...grpc.CallOption) (*ResumeWorkspaceResponse, error)
 }
 
 type workspaceManagerClient struct {
@@ -176,6 +190,97 @@ func (c *workspaceManagerClient) ControlAdmission(ctx context.Context, in *Contr
 	return out, nil
 }
 
+func (c *workspaceManagerClient) DrainNode(ctx context.Context, in *DrainNodeRequest, opts ...grpc.CallOption) (WorkspaceManager_DrainNodeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WorkspaceManager_ServiceDesc.Streams[1], "/wsman.WorkspaceManager/DrainNode", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workspaceManagerDrainNodeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkspaceManager_DrainNodeClient interface {
+	Recv() (*DrainNodeResponse, error)
+	grpc.ClientStream
+}
+
+type workspaceManagerDrainNodeClient struct {
+	grpc.ClientStream
+}
+
+func (x *workspaceManagerDrainNodeClient) Recv() (*DrainNodeResponse, error) {
+	m := new(DrainNodeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workspaceManagerClient) GetHeadlessLog(ctx context.Context, in *GetHeadlessLogRequest, opts ...grpc.CallOption) (WorkspaceManager_GetHeadlessLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WorkspaceManager_ServiceDesc.Streams[2], "/wsman.WorkspaceManager/GetHeadlessLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workspaceManagerGetHeadlessLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkspaceManager_GetHeadlessLogClient interface {
+	Recv() (*GetHeadlessLogResponse, error)
+	grpc.ClientStream
+}
+
+type workspaceManagerGetHeadlessLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *workspaceManagerGetHeadlessLogClient) Recv() (*GetHeadlessLogResponse, error) {
+	m := new(GetHeadlessLogResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workspaceManagerClient) DescribeCluster(ctx context.Context, in *DescribeClusterRequest, opts ...grpc.CallOption) (*DescribeClusterResponse, error) {
+	out := new(DescribeClusterResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) PauseWorkspace(ctx context.Context, in *PauseWorkspaceRequest, opts ...grpc.CallOption) (*PauseWorkspaceResponse, error) {
+	out := new(PauseWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/PauseWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ResumeWorkspace(ctx context.Context, in *ResumeWorkspaceRequest, opts ...grpc.CallOption) (*ResumeWorkspaceResponse, error) {
+	out := new(ResumeWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ResumeWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkspaceManagerServer is the server API for WorkspaceManager service.
 // All implementations must embed UnimplementedWorkspaceManagerServer
 // for forward compatibility
@@ -202,6 +307,20 @@ type WorkspaceManagerServer interface {
 	TakeSnapshot(context.Context, *TakeSnapshotRequest) (*TakeSnapshotResponse, error)
 	// controlAdmission makes a workspace accessible for everyone or for the owner only
 	ControlAdmission(context.Context, *ControlAdmissionRequest) (*ControlAdmissionResponse, error)
+	// drainNode stops new workspaces from being scheduled onto a node and backs up the workspaces running on it,
+	// e.g. ahead of a cluster upgrade. It streams the progress of the drain.
+	DrainNode(*DrainNodeRequest, WorkspaceManager_DrainNodeServer) error
+	// getHeadlessLog streams the log of a headless workspace's task, e.g. of a prebuild, while the task runs.
+	// The stream ends once the task is done.
+	GetHeadlessLog(*GetHeadlessLogRequest, WorkspaceManager_GetHeadlessLogServer) error
+	// describeCluster describes the cluster this workspace manager operates in, e.g. its region, capacity and
+	// the features it supports, so that clients can choose the cluster best suited to start a workspace in.
+	DescribeCluster(context.Context, *DescribeClusterRequest) (*DescribeClusterResponse, error)
+	// pauseWorkspace backs up a running workspace and removes its pod, but remembers the pod so that the workspace
+	// can be resumed with the same configuration, e.g. its exposed ports and environment.
+	PauseWorkspace(context.Context, *PauseWorkspaceRequest) (*PauseWorkspaceResponse, error)
+	// resumeWorkspace restores a paused workspace, possibly on a different node.
+	ResumeWorkspace(context.Context, *ResumeWorkspaceRequest) (*ResumeWorkspaceResponse, error)
 	mustEmbedUnimplementedWorkspaceManagerServer()
 }
 
@@ -242,6 +361,21 @@ func (UnimplementedWorkspaceManagerServer) TakeSnapshot(context.Context, *TakeSn
 func (UnimplementedWorkspaceManagerServer) ControlAdmission(context.Context, *ControlAdmissionRequest) (*ControlAdmissionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ControlAdmission not implemented")
 }
+func (UnimplementedWorkspaceManagerServer) DrainNode(*DrainNodeRequest, WorkspaceManager_DrainNodeServer) error {
+	return status.Errorf(codes.Unimplemented, "method DrainNode not implemented")
+}
+func (UnimplementedWorkspaceManagerServer) GetHeadlessLog(*GetHeadlessLogRequest, WorkspaceManager_GetHeadlessLogServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetHeadlessLog not implemented")
+}
+func (UnimplementedWorkspaceManagerServer) DescribeCluster(context.Context, *DescribeClusterRequest) (*DescribeClusterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeCluster not implemented")
+}
+func (UnimplementedWorkspaceManagerServer) PauseWorkspace(context.Context, *PauseWorkspaceRequest) (*PauseWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseWorkspace not implemented")
+}
+func (UnimplementedWorkspaceManagerServer) ResumeWorkspace(context.Context, *ResumeWorkspaceRequest) (*ResumeWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeWorkspace not implemented")
+}
 func (UnimplementedWorkspaceManagerServer) mustEmbedUnimplementedWorkspaceManagerServer() {}
 
 // UnsafeWorkspaceManagerServer may be embedded to opt out of forward compatibility for this service.
@@ -456,6 +590,102 @@ func _WorkspaceManager_ControlAdmission_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkspaceManager_DrainNode_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DrainNodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkspaceManagerServer).DrainNode(m, &workspaceManagerDrainNodeServer{stream})
+}
+
+type WorkspaceManager_DrainNodeServer interface {
+	Send(*DrainNodeResponse) error
+	grpc.ServerStream
+}
+
+type workspaceManagerDrainNodeServer struct {
+	grpc.ServerStream
+}
+
+func (x *workspaceManagerDrainNodeServer) Send(m *DrainNodeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkspaceManager_GetHeadlessLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetHeadlessLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkspaceManagerServer).GetHeadlessLog(m, &workspaceManagerGetHeadlessLogServer{stream})
+}
+
+type WorkspaceManager_GetHeadlessLogServer interface {
+	Send(*GetHeadlessLogResponse) error
+	grpc.ServerStream
+}
+
+type workspaceManagerGetHeadlessLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *workspaceManagerGetHeadlessLogServer) Send(m *GetHeadlessLogResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkspaceManager_DescribeCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).DescribeCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/DescribeCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).DescribeCluster(ctx, req.(*DescribeClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_PauseWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).PauseWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/PauseWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).PauseWorkspace(ctx, req.(*PauseWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_ResumeWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ResumeWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ResumeWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ResumeWorkspace(ctx, req.(*ResumeWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkspaceManager_ServiceDesc is the grpc.ServiceDesc for WorkspaceManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -503,6 +733,18 @@ var WorkspaceManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ControlAdmission",
 			Handler:    _WorkspaceManager_ControlAdmission_Handler,
 		},
+		{
+			MethodName: "DescribeCluster",
+			Handler:    _WorkspaceManager_DescribeCluster_Handler,
+		},
+		{
+			MethodName: "PauseWorkspace",
+			Handler:    _WorkspaceManager_PauseWorkspace_Handler,
+		},
+		{
+			MethodName: "ResumeWorkspace",
+			Handler:    _WorkspaceManager_ResumeWorkspace_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -510,6 +752,16 @@ var WorkspaceManager_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _WorkspaceManager_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DrainNode",
+			Handler:       _WorkspaceManager_DrainNode_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetHeadlessLog",
+			Handler:       _WorkspaceManager_GetHeadlessLog_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "core.proto",
 }
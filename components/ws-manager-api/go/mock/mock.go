@@ -205,6 +205,21 @@ func (mr *MockWorkspaceManagerServerMockRecorder) ControlPort(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPort", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).ControlPort), arg0, arg1)
 }
 
+// DescribeCluster mocks base method.
+func (m *MockWorkspaceManagerServer) DescribeCluster(arg0 context.Context, arg1 *api.DescribeClusterRequest) (*api.DescribeClusterResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeCluster", arg0, arg1)
+	ret0, _ := ret[0].(*api.DescribeClusterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCluster indicates an expected call of DescribeCluster.
+func (mr *MockWorkspaceManagerServerMockRecorder) DescribeCluster(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).DescribeCluster), arg0, arg1)
+}
+
 // DescribeWorkspace mocks base method.
 func (m *MockWorkspaceManagerServer) DescribeWorkspace(arg0 context.Context, arg1 *api.DescribeWorkspaceRequest) (*api.DescribeWorkspaceResponse, error) {
 	m.ctrl.T.Helper()
@@ -220,6 +235,34 @@ func (mr *MockWorkspaceManagerServerMockRecorder) DescribeWorkspace(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkspace", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).DescribeWorkspace), arg0, arg1)
 }
 
+// DrainNode mocks base method.
+func (m *MockWorkspaceManagerServer) DrainNode(arg0 *api.DrainNodeRequest, arg1 api.WorkspaceManager_DrainNodeServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainNode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DrainNode indicates an expected call of DrainNode.
+func (mr *MockWorkspaceManagerServerMockRecorder) DrainNode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainNode", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).DrainNode), arg0, arg1)
+}
+
+// GetHeadlessLog mocks base method.
+func (m *MockWorkspaceManagerServer) GetHeadlessLog(arg0 *api.GetHeadlessLogRequest, arg1 api.WorkspaceManager_GetHeadlessLogServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeadlessLog", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetHeadlessLog indicates an expected call of GetHeadlessLog.
+func (mr *MockWorkspaceManagerServerMockRecorder) GetHeadlessLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeadlessLog", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).GetHeadlessLog), arg0, arg1)
+}
+
 // GetWorkspaces mocks base method.
 func (m *MockWorkspaceManagerServer) GetWorkspaces(arg0 context.Context, arg1 *api.GetWorkspacesRequest) (*api.GetWorkspacesResponse, error) {
 	m.ctrl.T.Helper()
@@ -250,6 +293,36 @@ func (mr *MockWorkspaceManagerServerMockRecorder) MarkActive(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkActive", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).MarkActive), arg0, arg1)
 }
 
+// PauseWorkspace mocks base method.
+func (m *MockWorkspaceManagerServer) PauseWorkspace(arg0 context.Context, arg1 *api.PauseWorkspaceRequest) (*api.PauseWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(*api.PauseWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseWorkspace indicates an expected call of PauseWorkspace.
+func (mr *MockWorkspaceManagerServerMockRecorder) PauseWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseWorkspace", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).PauseWorkspace), arg0, arg1)
+}
+
+// ResumeWorkspace mocks base method.
+func (m *MockWorkspaceManagerServer) ResumeWorkspace(arg0 context.Context, arg1 *api.ResumeWorkspaceRequest) (*api.ResumeWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(*api.ResumeWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeWorkspace indicates an expected call of ResumeWorkspace.
+func (mr *MockWorkspaceManagerServerMockRecorder) ResumeWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeWorkspace", reflect.TypeOf((*MockWorkspaceManagerServer)(nil).ResumeWorkspace), arg0, arg1)
+}
+
 // SetTimeout mocks base method.
 func (m *MockWorkspaceManagerServer) SetTimeout(arg0 context.Context, arg1 *api.SetTimeoutRequest) (*api.SetTimeoutResponse, error) {
 	m.ctrl.T.Helper()
@@ -542,6 +615,26 @@ func (mr *MockWorkspaceManagerClientMockRecorder) ControlPort(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPort", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ControlPort), varargs...)
 }
 
+// DescribeCluster mocks base method.
+func (m *MockWorkspaceManagerClient) DescribeCluster(arg0 context.Context, arg1 *api.DescribeClusterRequest, arg2 ...grpc.CallOption) (*api.DescribeClusterResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCluster", varargs...)
+	ret0, _ := ret[0].(*api.DescribeClusterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCluster indicates an expected call of DescribeCluster.
+func (mr *MockWorkspaceManagerClientMockRecorder) DescribeCluster(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeCluster), varargs...)
+}
+
 // DescribeWorkspace mocks base method.
 func (m *MockWorkspaceManagerClient) DescribeWorkspace(arg0 context.Context, arg1 *api.DescribeWorkspaceRequest, arg2 ...grpc.CallOption) (*api.DescribeWorkspaceResponse, error) {
 	m.ctrl.T.Helper()
@@ -562,6 +655,46 @@ func (mr *MockWorkspaceManagerClientMockRecorder) DescribeWorkspace(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeWorkspace), varargs...)
 }
 
+// DrainNode mocks base method.
+func (m *MockWorkspaceManagerClient) DrainNode(arg0 context.Context, arg1 *api.DrainNodeRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_DrainNodeClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DrainNode", varargs...)
+	ret0, _ := ret[0].(api.WorkspaceManager_DrainNodeClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainNode indicates an expected call of DrainNode.
+func (mr *MockWorkspaceManagerClientMockRecorder) DrainNode(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainNode", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DrainNode), varargs...)
+}
+
+// GetHeadlessLog mocks base method.
+func (m *MockWorkspaceManagerClient) GetHeadlessLog(arg0 context.Context, arg1 *api.GetHeadlessLogRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_GetHeadlessLogClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetHeadlessLog", varargs...)
+	ret0, _ := ret[0].(api.WorkspaceManager_GetHeadlessLogClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeadlessLog indicates an expected call of GetHeadlessLog.
+func (mr *MockWorkspaceManagerClientMockRecorder) GetHeadlessLog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeadlessLog", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).GetHeadlessLog), varargs...)
+}
+
 // GetWorkspaces mocks base method.
 func (m *MockWorkspaceManagerClient) GetWorkspaces(arg0 context.Context, arg1 *api.GetWorkspacesRequest, arg2 ...grpc.CallOption) (*api.GetWorkspacesResponse, error) {
 	m.ctrl.T.Helper()
@@ -602,6 +735,46 @@ func (mr *MockWorkspaceManagerClientMockRecorder) MarkActive(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkActive", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).MarkActive), varargs...)
 }
 
+// PauseWorkspace mocks base method.
+func (m *MockWorkspaceManagerClient) PauseWorkspace(arg0 context.Context, arg1 *api.PauseWorkspaceRequest, arg2 ...grpc.CallOption) (*api.PauseWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PauseWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.PauseWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseWorkspace indicates an expected call of PauseWorkspace.
+func (mr *MockWorkspaceManagerClientMockRecorder) PauseWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).PauseWorkspace), varargs...)
+}
+
+// ResumeWorkspace mocks base method.
+func (m *MockWorkspaceManagerClient) ResumeWorkspace(arg0 context.Context, arg1 *api.ResumeWorkspaceRequest, arg2 ...grpc.CallOption) (*api.ResumeWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.ResumeWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeWorkspace indicates an expected call of ResumeWorkspace.
+func (mr *MockWorkspaceManagerClientMockRecorder) ResumeWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ResumeWorkspace), varargs...)
+}
+
 // SetTimeout mocks base method.
 func (m *MockWorkspaceManagerClient) SetTimeout(arg0 context.Context, arg1 *api.SetTimeoutRequest, arg2 ...grpc.CallOption) (*api.SetTimeoutResponse, error) {
 	m.ctrl.T.Helper()
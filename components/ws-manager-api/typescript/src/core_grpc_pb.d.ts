@@ -27,6 +27,11 @@ interface IWorkspaceManagerService extends grpc.ServiceDefinition<grpc.UntypedSe
     controlPort: IWorkspaceManagerService_IControlPort;
     takeSnapshot: IWorkspaceManagerService_ITakeSnapshot;
     controlAdmission: IWorkspaceManagerService_IControlAdmission;
+    drainNode: IWorkspaceManagerService_IDrainNode;
+    getHeadlessLog: IWorkspaceManagerService_IGetHeadlessLog;
+    describeCluster: IWorkspaceManagerService_IDescribeCluster;
+    pauseWorkspace: IWorkspaceManagerService_IPauseWorkspace;
+    resumeWorkspace: IWorkspaceManagerService_IResumeWorkspace;
 }
 
 interface IWorkspaceManagerService_IGetWorkspaces extends grpc.MethodDefinition<core_pb.GetWorkspacesRequest, core_pb.GetWorkspacesResponse> {
@@ -128,6 +133,51 @@ interface IWorkspaceManagerService_IControlAdmission extends grpc.MethodDefiniti
     responseSerialize: grpc.serialize<core_pb.ControlAdmissionResponse>;
     responseDeserialize: grpc.deserialize<core_pb.ControlAdmissionResponse>;
 }
+interface IWorkspaceManagerService_IDrainNode extends grpc.MethodDefinition<core_pb.DrainNodeRequest, core_pb.DrainNodeResponse> {
+    path: "/wsman.WorkspaceManager/DrainNode";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<core_pb.DrainNodeRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DrainNodeRequest>;
+    responseSerialize: grpc.serialize<core_pb.DrainNodeResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DrainNodeResponse>;
+}
+interface IWorkspaceManagerService_IGetHeadlessLog extends grpc.MethodDefinition<core_pb.GetHeadlessLogRequest, core_pb.GetHeadlessLogResponse> {
+    path: "/wsman.WorkspaceManager/GetHeadlessLog";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<core_pb.GetHeadlessLogRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.GetHeadlessLogRequest>;
+    responseSerialize: grpc.serialize<core_pb.GetHeadlessLogResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.GetHeadlessLogResponse>;
+}
+interface IWorkspaceManagerService_IDescribeCluster extends grpc.MethodDefinition<core_pb.DescribeClusterRequest, core_pb.DescribeClusterResponse> {
+    path: "/wsman.WorkspaceManager/DescribeCluster";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DescribeClusterRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DescribeClusterRequest>;
+    responseSerialize: grpc.serialize<core_pb.DescribeClusterResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DescribeClusterResponse>;
+}
+interface IWorkspaceManagerService_IPauseWorkspace extends grpc.MethodDefinition<core_pb.PauseWorkspaceRequest, core_pb.PauseWorkspaceResponse> {
+    path: "/wsman.WorkspaceManager/PauseWorkspace";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.PauseWorkspaceRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.PauseWorkspaceRequest>;
+    responseSerialize: grpc.serialize<core_pb.PauseWorkspaceResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.PauseWorkspaceResponse>;
+}
+interface IWorkspaceManagerService_IResumeWorkspace extends grpc.MethodDefinition<core_pb.ResumeWorkspaceRequest, core_pb.ResumeWorkspaceResponse> {
+    path: "/wsman.WorkspaceManager/ResumeWorkspace";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ResumeWorkspaceRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ResumeWorkspaceRequest>;
+    responseSerialize: grpc.serialize<core_pb.ResumeWorkspaceResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ResumeWorkspaceResponse>;
+}
 
 export const WorkspaceManagerService: IWorkspaceManagerService;
 
@@ -143,6 +193,11 @@ export interface IWorkspaceManagerServer extends grpc.UntypedServiceImplementati
     controlPort: grpc.handleUnaryCall<core_pb.ControlPortRequest, core_pb.ControlPortResponse>;
     takeSnapshot: grpc.handleUnaryCall<core_pb.TakeSnapshotRequest, core_pb.TakeSnapshotResponse>;
     controlAdmission: grpc.handleUnaryCall<core_pb.ControlAdmissionRequest, core_pb.ControlAdmissionResponse>;
+    drainNode: grpc.handleServerStreamingCall<core_pb.DrainNodeRequest, core_pb.DrainNodeResponse>;
+    getHeadlessLog: grpc.handleServerStreamingCall<core_pb.GetHeadlessLogRequest, core_pb.GetHeadlessLogResponse>;
+    describeCluster: grpc.handleUnaryCall<core_pb.DescribeClusterRequest, core_pb.DescribeClusterResponse>;
+    pauseWorkspace: grpc.handleUnaryCall<core_pb.PauseWorkspaceRequest, core_pb.PauseWorkspaceResponse>;
+    resumeWorkspace: grpc.handleUnaryCall<core_pb.ResumeWorkspaceRequest, core_pb.ResumeWorkspaceResponse>;
 }
 
 export interface IWorkspaceManagerClient {
@@ -178,6 +233,19 @@ export interface IWorkspaceManagerClient {
     controlAdmission(request: core_pb.ControlAdmissionRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
+    drainNode(request: core_pb.DrainNodeRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.DrainNodeResponse>;
+    drainNode(request: core_pb.DrainNodeRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.DrainNodeResponse>;
+    getHeadlessLog(request: core_pb.GetHeadlessLogRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.GetHeadlessLogResponse>;
+    getHeadlessLog(request: core_pb.GetHeadlessLogRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.GetHeadlessLogResponse>;
+    describeCluster(request: core_pb.DescribeClusterRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeClusterResponse) => void): grpc.ClientUnaryCall;
+    describeCluster(request: core_pb.DescribeClusterRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeClusterResponse) => void): grpc.ClientUnaryCall;
+    describeCluster(request: core_pb.DescribeClusterRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeClusterResponse) => void): grpc.ClientUnaryCall;
+    pauseWorkspace(request: core_pb.PauseWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.PauseWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    pauseWorkspace(request: core_pb.PauseWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.PauseWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    pauseWorkspace(request: core_pb.PauseWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.PauseWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    resumeWorkspace(request: core_pb.ResumeWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ResumeWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    resumeWorkspace(request: core_pb.ResumeWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ResumeWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    resumeWorkspace(request: core_pb.ResumeWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ResumeWorkspaceResponse) => void): grpc.ClientUnaryCall;
 }
 
 export class WorkspaceManagerClient extends grpc.Client implements IWorkspaceManagerClient {
@@ -214,4 +282,17 @@ export class WorkspaceManagerClient extends grpc.Client implements IWorkspaceMan
     public controlAdmission(request: core_pb.ControlAdmissionRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     public controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     public controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
+    public drainNode(request: core_pb.DrainNodeRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.DrainNodeResponse>;
+    public drainNode(request: core_pb.DrainNodeRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.DrainNodeResponse>;
+    public getHeadlessLog(request: core_pb.GetHeadlessLogRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.GetHeadlessLogResponse>;
+    public getHeadlessLog(request: core_pb.GetHeadlessLogRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.GetHeadlessLogResponse>;
+    public describeCluster(request: core_pb.DescribeClusterRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeClusterResponse) => void): grpc.ClientUnaryCall;
+    public describeCluster(request: core_pb.DescribeClusterRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeClusterResponse) => void): grpc.ClientUnaryCall;
+    public describeCluster(request: core_pb.DescribeClusterRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeClusterResponse) => void): grpc.ClientUnaryCall;
+    public pauseWorkspace(request: core_pb.PauseWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.PauseWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public pauseWorkspace(request: core_pb.PauseWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.PauseWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public pauseWorkspace(request: core_pb.PauseWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.PauseWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public resumeWorkspace(request: core_pb.ResumeWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ResumeWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public resumeWorkspace(request: core_pb.ResumeWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ResumeWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public resumeWorkspace(request: core_pb.ResumeWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ResumeWorkspaceResponse) => void): grpc.ClientUnaryCall;
 }
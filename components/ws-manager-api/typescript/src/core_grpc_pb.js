@@ -78,6 +78,28 @@ function deserialize_wsman_ControlPortResponse(buffer_arg) {
   return core_pb.ControlPortResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_DescribeClusterRequest(arg) {
+  if (!(arg instanceof core_pb.DescribeClusterRequest)) {
+    throw new Error('Expected argument of type wsman.DescribeClusterRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeClusterRequest(buffer_arg) {
+  return core_pb.DescribeClusterRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeClusterResponse(arg) {
+  if (!(arg instanceof core_pb.DescribeClusterResponse)) {
+    throw new Error('Expected argument of type wsman.DescribeClusterResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeClusterResponse(buffer_arg) {
+  return core_pb.DescribeClusterResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_DescribeWorkspaceRequest(arg) {
   if (!(arg instanceof core_pb.DescribeWorkspaceRequest)) {
     throw new Error('Expected argument of type wsman.DescribeWorkspaceRequest');
@@ -100,6 +122,50 @@ function deserialize_wsman_DescribeWorkspaceResponse(buffer_arg) {
   return core_pb.DescribeWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_DrainNodeRequest(arg) {
+  if (!(arg instanceof core_pb.DrainNodeRequest)) {
+    throw new Error('Expected argument of type wsman.DrainNodeRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DrainNodeRequest(buffer_arg) {
+  return core_pb.DrainNodeRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DrainNodeResponse(arg) {
+  if (!(arg instanceof core_pb.DrainNodeResponse)) {
+    throw new Error('Expected argument of type wsman.DrainNodeResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DrainNodeResponse(buffer_arg) {
+  return core_pb.DrainNodeResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_GetHeadlessLogRequest(arg) {
+  if (!(arg instanceof core_pb.GetHeadlessLogRequest)) {
+    throw new Error('Expected argument of type wsman.GetHeadlessLogRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_GetHeadlessLogRequest(buffer_arg) {
+  return core_pb.GetHeadlessLogRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_GetHeadlessLogResponse(arg) {
+  if (!(arg instanceof core_pb.GetHeadlessLogResponse)) {
+    throw new Error('Expected argument of type wsman.GetHeadlessLogResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_GetHeadlessLogResponse(buffer_arg) {
+  return core_pb.GetHeadlessLogResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_GetWorkspacesRequest(arg) {
   if (!(arg instanceof core_pb.GetWorkspacesRequest)) {
     throw new Error('Expected argument of type wsman.GetWorkspacesRequest');
@@ -144,6 +210,50 @@ function deserialize_wsman_MarkActiveResponse(buffer_arg) {
   return core_pb.MarkActiveResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_PauseWorkspaceRequest(arg) {
+  if (!(arg instanceof core_pb.PauseWorkspaceRequest)) {
+    throw new Error('Expected argument of type wsman.PauseWorkspaceRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_PauseWorkspaceRequest(buffer_arg) {
+  return core_pb.PauseWorkspaceRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_PauseWorkspaceResponse(arg) {
+  if (!(arg instanceof core_pb.PauseWorkspaceResponse)) {
+    throw new Error('Expected argument of type wsman.PauseWorkspaceResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_PauseWorkspaceResponse(buffer_arg) {
+  return core_pb.PauseWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ResumeWorkspaceRequest(arg) {
+  if (!(arg instanceof core_pb.ResumeWorkspaceRequest)) {
+    throw new Error('Expected argument of type wsman.ResumeWorkspaceRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ResumeWorkspaceRequest(buffer_arg) {
+  return core_pb.ResumeWorkspaceRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ResumeWorkspaceResponse(arg) {
+  if (!(arg instanceof core_pb.ResumeWorkspaceResponse)) {
+    throw new Error('Expected argument of type wsman.ResumeWorkspaceResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ResumeWorkspaceResponse(buffer_arg) {
+  return core_pb.ResumeWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_SetTimeoutRequest(arg) {
   if (!(arg instanceof core_pb.SetTimeoutRequest)) {
     throw new Error('Expected argument of type wsman.SetTimeoutRequest');
@@ -388,6 +498,70 @@ controlAdmission: {
     responseSerialize: serialize_wsman_ControlAdmissionResponse,
     responseDeserialize: deserialize_wsman_ControlAdmissionResponse,
   },
+  // drainNode stops new workspaces from being scheduled onto a node and backs up the workspaces running on it,
+// e.g. ahead of a cluster upgrade. It streams the progress of the drain.
+drainNode: {
+    path: '/wsman.WorkspaceManager/DrainNode',
+    requestStream: false,
+    responseStream: true,
+    requestType: core_pb.DrainNodeRequest,
+    responseType: core_pb.DrainNodeResponse,
+    requestSerialize: serialize_wsman_DrainNodeRequest,
+    requestDeserialize: deserialize_wsman_DrainNodeRequest,
+    responseSerialize: serialize_wsman_DrainNodeResponse,
+    responseDeserialize: deserialize_wsman_DrainNodeResponse,
+  },
+  // getHeadlessLog streams the log of a headless workspace's task, e.g. of a prebuild, while the task runs.
+// The stream ends once the task is done.
+getHeadlessLog: {
+    path: '/wsman.WorkspaceManager/GetHeadlessLog',
+    requestStream: false,
+    responseStream: true,
+    requestType: core_pb.GetHeadlessLogRequest,
+    responseType: core_pb.GetHeadlessLogResponse,
+    requestSerialize: serialize_wsman_GetHeadlessLogRequest,
+    requestDeserialize: deserialize_wsman_GetHeadlessLogRequest,
+    responseSerialize: serialize_wsman_GetHeadlessLogResponse,
+    responseDeserialize: deserialize_wsman_GetHeadlessLogResponse,
+  },
+  // describeCluster describes the cluster this workspace manager operates in, e.g. its region, capacity and
+// the features it supports, so that clients can choose the cluster best suited to start a workspace in.
+describeCluster: {
+    path: '/wsman.WorkspaceManager/DescribeCluster',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DescribeClusterRequest,
+    responseType: core_pb.DescribeClusterResponse,
+    requestSerialize: serialize_wsman_DescribeClusterRequest,
+    requestDeserialize: deserialize_wsman_DescribeClusterRequest,
+    responseSerialize: serialize_wsman_DescribeClusterResponse,
+    responseDeserialize: deserialize_wsman_DescribeClusterResponse,
+  },
+  // pauseWorkspace backs up a running workspace and removes its pod, but remembers the pod so that the workspace
+// can be resumed with the same configuration, e.g. its exposed ports and environment.
+pauseWorkspace: {
+    path: '/wsman.WorkspaceManager/PauseWorkspace',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.PauseWorkspaceRequest,
+    responseType: core_pb.PauseWorkspaceResponse,
+    requestSerialize: serialize_wsman_PauseWorkspaceRequest,
+    requestDeserialize: deserialize_wsman_PauseWorkspaceRequest,
+    responseSerialize: serialize_wsman_PauseWorkspaceResponse,
+    responseDeserialize: deserialize_wsman_PauseWorkspaceResponse,
+  },
+  // resumeWorkspace restores a paused workspace, possibly on a different node.
+resumeWorkspace: {
+    path: '/wsman.WorkspaceManager/ResumeWorkspace',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ResumeWorkspaceRequest,
+    responseType: core_pb.ResumeWorkspaceResponse,
+    requestSerialize: serialize_wsman_ResumeWorkspaceRequest,
+    requestDeserialize: deserialize_wsman_ResumeWorkspaceRequest,
+    responseSerialize: serialize_wsman_ResumeWorkspaceResponse,
+    responseDeserialize: deserialize_wsman_ResumeWorkspaceResponse,
+  },
 };
 
 exports.WorkspaceManagerClient = grpc.makeGenericClientConstructor(WorkspaceManagerService);
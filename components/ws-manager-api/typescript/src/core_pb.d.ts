@@ -12,6 +12,7 @@
 
 import * as jspb from "google-protobuf";
 import * as content_service_api_initializer_pb from "@gitpod/content-service/lib";
+import * as google_protobuf_field_mask_pb from "google-protobuf/google/protobuf/field_mask_pb";
 import * as google_protobuf_timestamp_pb from "google-protobuf/google/protobuf/timestamp_pb";
 
 export class MetadataFilter extends jspb.Message {
@@ -104,6 +105,8 @@ export class StartWorkspaceRequest extends jspb.Message {
     setSpec(value?: StartWorkspaceSpec): StartWorkspaceRequest;
     getType(): WorkspaceType;
     setType(value: WorkspaceType): StartWorkspaceRequest;
+    getDryRun(): boolean;
+    setDryRun(value: boolean): StartWorkspaceRequest;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StartWorkspaceRequest.AsObject;
@@ -122,6 +125,7 @@ export namespace StartWorkspaceRequest {
         metadata?: WorkspaceMetadata.AsObject,
         spec?: StartWorkspaceSpec.AsObject,
         type: WorkspaceType,
+        dryRun: boolean,
     }
 }
 
@@ -131,6 +135,11 @@ export class StartWorkspaceResponse extends jspb.Message {
     getOwnerToken(): string;
     setOwnerToken(value: string): StartWorkspaceResponse;
 
+    hasDryRun(): boolean;
+    clearDryRun(): void;
+    getDryRun(): StartWorkspaceDryRunResult | undefined;
+    setDryRun(value?: StartWorkspaceDryRunResult): StartWorkspaceResponse;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StartWorkspaceResponse.AsObject;
     static toObject(includeInstance: boolean, msg: StartWorkspaceResponse): StartWorkspaceResponse.AsObject;
@@ -145,6 +154,36 @@ export namespace StartWorkspaceResponse {
     export type AsObject = {
         url: string,
         ownerToken: string,
+        dryRun?: StartWorkspaceDryRunResult.AsObject,
+    }
+}
+
+export class StartWorkspaceDryRunResult extends jspb.Message {
+    getFits(): boolean;
+    setFits(value: boolean): StartWorkspaceDryRunResult;
+    getNodeName(): string;
+    setNodeName(value: string): StartWorkspaceDryRunResult;
+    getReason(): string;
+    setReason(value: string): StartWorkspaceDryRunResult;
+    getPod(): string;
+    setPod(value: string): StartWorkspaceDryRunResult;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): StartWorkspaceDryRunResult.AsObject;
+    static toObject(includeInstance: boolean, msg: StartWorkspaceDryRunResult): StartWorkspaceDryRunResult.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: StartWorkspaceDryRunResult, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StartWorkspaceDryRunResult;
+    static deserializeBinaryFromReader(message: StartWorkspaceDryRunResult, reader: jspb.BinaryReader): StartWorkspaceDryRunResult;
+}
+
+export namespace StartWorkspaceDryRunResult {
+    export type AsObject = {
+        fits: boolean,
+        nodeName: string,
+        reason: string,
+        pod: string,
     }
 }
 
@@ -153,6 +192,8 @@ export class StopWorkspaceRequest extends jspb.Message {
     setId(value: string): StopWorkspaceRequest;
     getPolicy(): StopWorkspacePolicy;
     setPolicy(value: StopWorkspacePolicy): StopWorkspaceRequest;
+    getReason(): StopReason;
+    setReason(value: StopReason): StopWorkspaceRequest;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StopWorkspaceRequest.AsObject;
@@ -168,6 +209,7 @@ export namespace StopWorkspaceRequest {
     export type AsObject = {
         id: string,
         policy: StopWorkspacePolicy,
+        reason: StopReason,
     }
 }
 
@@ -188,6 +230,83 @@ export namespace StopWorkspaceResponse {
     }
 }
 
+export class PauseWorkspaceRequest extends jspb.Message {
+    getId(): string;
+    setId(value: string): PauseWorkspaceRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PauseWorkspaceRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: PauseWorkspaceRequest): PauseWorkspaceRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: PauseWorkspaceRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PauseWorkspaceRequest;
+    static deserializeBinaryFromReader(message: PauseWorkspaceRequest, reader: jspb.BinaryReader): PauseWorkspaceRequest;
+}
+
+export namespace PauseWorkspaceRequest {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class PauseWorkspaceResponse extends jspb.Message {
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PauseWorkspaceResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: PauseWorkspaceResponse): PauseWorkspaceResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: PauseWorkspaceResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PauseWorkspaceResponse;
+    static deserializeBinaryFromReader(message: PauseWorkspaceResponse, reader: jspb.BinaryReader): PauseWorkspaceResponse;
+}
+
+export namespace PauseWorkspaceResponse {
+    export type AsObject = {
+    }
+}
+
+export class ResumeWorkspaceRequest extends jspb.Message {
+    getId(): string;
+    setId(value: string): ResumeWorkspaceRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ResumeWorkspaceRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ResumeWorkspaceRequest): ResumeWorkspaceRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ResumeWorkspaceRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ResumeWorkspaceRequest;
+    static deserializeBinaryFromReader(message: ResumeWorkspaceRequest, reader: jspb.BinaryReader): ResumeWorkspaceRequest;
+}
+
+export namespace ResumeWorkspaceRequest {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class ResumeWorkspaceResponse extends jspb.Message {
+    getUrl(): string;
+    setUrl(value: string): ResumeWorkspaceResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ResumeWorkspaceResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ResumeWorkspaceResponse): ResumeWorkspaceResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ResumeWorkspaceResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ResumeWorkspaceResponse;
+    static deserializeBinaryFromReader(message: ResumeWorkspaceResponse, reader: jspb.BinaryReader): ResumeWorkspaceResponse;
+}
+
+export namespace ResumeWorkspaceResponse {
+    export type AsObject = {
+        url: string,
+    }
+}
+
 export class DescribeWorkspaceRequest extends jspb.Message {
     getId(): string;
     setId(value: string): DescribeWorkspaceRequest;
@@ -240,6 +359,19 @@ export class SubscribeRequest extends jspb.Message {
     clearMustMatch(): void;
     getMustMatch(): MetadataFilter | undefined;
     setMustMatch(value?: MetadataFilter): SubscribeRequest;
+    clearIdsList(): void;
+    getIdsList(): Array<string>;
+    setIdsList(value: Array<string>): SubscribeRequest;
+    addIds(value: string, index?: number): string;
+    clearPhasesList(): void;
+    getPhasesList(): Array<WorkspacePhase>;
+    setPhasesList(value: Array<WorkspacePhase>): SubscribeRequest;
+    addPhases(value: WorkspacePhase, index?: number): WorkspacePhase;
+
+    hasFieldMask(): boolean;
+    clearFieldMask(): void;
+    getFieldMask(): google_protobuf_field_mask_pb.FieldMask | undefined;
+    setFieldMask(value?: google_protobuf_field_mask_pb.FieldMask): SubscribeRequest;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): SubscribeRequest.AsObject;
@@ -254,6 +386,9 @@ export class SubscribeRequest extends jspb.Message {
 export namespace SubscribeRequest {
     export type AsObject = {
         mustMatch?: MetadataFilter.AsObject,
+        idsList: Array<string>,
+        phasesList: Array<WorkspacePhase>,
+        fieldMask?: google_protobuf_field_mask_pb.FieldMask.AsObject,
     }
 }
 
@@ -494,6 +629,232 @@ export namespace ControlAdmissionResponse {
     }
 }
 
+export class DrainNodeRequest extends jspb.Message {
+    getNode(): string;
+    setNode(value: string): DrainNodeRequest;
+    getStopHeadless(): boolean;
+    setStopHeadless(value: boolean): DrainNodeRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DrainNodeRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DrainNodeRequest): DrainNodeRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DrainNodeRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DrainNodeRequest;
+    static deserializeBinaryFromReader(message: DrainNodeRequest, reader: jspb.BinaryReader): DrainNodeRequest;
+}
+
+export namespace DrainNodeRequest {
+    export type AsObject = {
+        node: string,
+        stopHeadless: boolean,
+    }
+}
+
+export class DrainNodeResponse extends jspb.Message {
+    getStep(): DrainNodeStep;
+    setStep(value: DrainNodeStep): DrainNodeResponse;
+    getId(): string;
+    setId(value: string): DrainNodeResponse;
+    getMessage(): string;
+    setMessage(value: string): DrainNodeResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DrainNodeResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DrainNodeResponse): DrainNodeResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DrainNodeResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DrainNodeResponse;
+    static deserializeBinaryFromReader(message: DrainNodeResponse, reader: jspb.BinaryReader): DrainNodeResponse;
+}
+
+export namespace DrainNodeResponse {
+    export type AsObject = {
+        step: DrainNodeStep,
+        id: string,
+        message: string,
+    }
+}
+
+export class GetHeadlessLogRequest extends jspb.Message {
+    getId(): string;
+    setId(value: string): GetHeadlessLogRequest;
+    getTaskId(): string;
+    setTaskId(value: string): GetHeadlessLogRequest;
+    getResumeToken(): string;
+    setResumeToken(value: string): GetHeadlessLogRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GetHeadlessLogRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: GetHeadlessLogRequest): GetHeadlessLogRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GetHeadlessLogRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GetHeadlessLogRequest;
+    static deserializeBinaryFromReader(message: GetHeadlessLogRequest, reader: jspb.BinaryReader): GetHeadlessLogRequest;
+}
+
+export namespace GetHeadlessLogRequest {
+    export type AsObject = {
+        id: string,
+        taskId: string,
+        resumeToken: string,
+    }
+}
+
+export class GetHeadlessLogResponse extends jspb.Message {
+    getData(): Uint8Array | string;
+    getData_asU8(): Uint8Array;
+    getData_asB64(): string;
+    setData(value: Uint8Array | string): GetHeadlessLogResponse;
+    getResumeToken(): string;
+    setResumeToken(value: string): GetHeadlessLogResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GetHeadlessLogResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: GetHeadlessLogResponse): GetHeadlessLogResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GetHeadlessLogResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GetHeadlessLogResponse;
+    static deserializeBinaryFromReader(message: GetHeadlessLogResponse, reader: jspb.BinaryReader): GetHeadlessLogResponse;
+}
+
+export namespace GetHeadlessLogResponse {
+    export type AsObject = {
+        data: Uint8Array | string,
+        resumeToken: string,
+    }
+}
+
+export class DescribeClusterRequest extends jspb.Message {
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeClusterRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeClusterRequest): DescribeClusterRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeClusterRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeClusterRequest;
+    static deserializeBinaryFromReader(message: DescribeClusterRequest, reader: jspb.BinaryReader): DescribeClusterRequest;
+}
+
+export namespace DescribeClusterRequest {
+    export type AsObject = {
+    }
+}
+
+export class DescribeClusterResponse extends jspb.Message {
+    getRegion(): string;
+    setRegion(value: string): DescribeClusterResponse;
+    getWorkspaceClusterHost(): string;
+    setWorkspaceClusterHost(value: string): DescribeClusterResponse;
+    getDefaultWorkspaceClass(): string;
+    setDefaultWorkspaceClass(value: string): DescribeClusterResponse;
+
+    hasCapacity(): boolean;
+    clearCapacity(): void;
+    getCapacity(): ClusterCapacity | undefined;
+    setCapacity(value?: ClusterCapacity): DescribeClusterResponse;
+    clearWorkspaceClassesList(): void;
+    getWorkspaceClassesList(): Array<WorkspaceClassDescription>;
+    setWorkspaceClassesList(value: Array<WorkspaceClassDescription>): DescribeClusterResponse;
+    addWorkspaceClasses(value?: WorkspaceClassDescription, index?: number): WorkspaceClassDescription;
+    clearFeaturesList(): void;
+    getFeaturesList(): Array<ClusterFeature>;
+    setFeaturesList(value: Array<ClusterFeature>): DescribeClusterResponse;
+    addFeatures(value: ClusterFeature, index?: number): ClusterFeature;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeClusterResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeClusterResponse): DescribeClusterResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeClusterResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeClusterResponse;
+    static deserializeBinaryFromReader(message: DescribeClusterResponse, reader: jspb.BinaryReader): DescribeClusterResponse;
+}
+
+export namespace DescribeClusterResponse {
+    export type AsObject = {
+        region: string,
+        workspaceClusterHost: string,
+        defaultWorkspaceClass: string,
+        capacity?: ClusterCapacity.AsObject,
+        workspaceClassesList: Array<WorkspaceClassDescription.AsObject>,
+        featuresList: Array<ClusterFeature>,
+    }
+}
+
+export class ClusterCapacity extends jspb.Message {
+    getNodes(): number;
+    setNodes(value: number): ClusterCapacity;
+    getAllocatableCpu(): string;
+    setAllocatableCpu(value: string): ClusterCapacity;
+    getAllocatableMemory(): string;
+    setAllocatableMemory(value: string): ClusterCapacity;
+    getRequestedCpu(): string;
+    setRequestedCpu(value: string): ClusterCapacity;
+    getRequestedMemory(): string;
+    setRequestedMemory(value: string): ClusterCapacity;
+    getAllocatableGpus(): number;
+    setAllocatableGpus(value: number): ClusterCapacity;
+    getWorkspaces(): number;
+    setWorkspaces(value: number): ClusterCapacity;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ClusterCapacity.AsObject;
+    static toObject(includeInstance: boolean, msg: ClusterCapacity): ClusterCapacity.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ClusterCapacity, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ClusterCapacity;
+    static deserializeBinaryFromReader(message: ClusterCapacity, reader: jspb.BinaryReader): ClusterCapacity;
+}
+
+export namespace ClusterCapacity {
+    export type AsObject = {
+        nodes: number,
+        allocatableCpu: string,
+        allocatableMemory: string,
+        requestedCpu: string,
+        requestedMemory: string,
+        allocatableGpus: number,
+        workspaces: number,
+    }
+}
+
+export class WorkspaceClassDescription extends jspb.Message {
+    getName(): string;
+    setName(value: string): WorkspaceClassDescription;
+    getCpu(): string;
+    setCpu(value: string): WorkspaceClassDescription;
+    getMemory(): string;
+    setMemory(value: string): WorkspaceClassDescription;
+    getPvc(): boolean;
+    setPvc(value: boolean): WorkspaceClassDescription;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceClassDescription.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceClassDescription): WorkspaceClassDescription.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceClassDescription, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceClassDescription;
+    static deserializeBinaryFromReader(message: WorkspaceClassDescription, reader: jspb.BinaryReader): WorkspaceClassDescription;
+}
+
+export namespace WorkspaceClassDescription {
+    export type AsObject = {
+        name: string,
+        cpu: string,
+        memory: string,
+        pvc: boolean,
+    }
+}
+
 export class BackupWorkspaceRequest extends jspb.Message {
     getId(): string;
     setId(value: string): BackupWorkspaceRequest;
@@ -573,6 +934,8 @@ export class WorkspaceStatus extends jspb.Message {
     clearAuth(): void;
     getAuth(): WorkspaceAuthentication | undefined;
     setAuth(value?: WorkspaceAuthentication): WorkspaceStatus;
+    getStopReason(): StopReason;
+    setStopReason(value: StopReason): WorkspaceStatus;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): WorkspaceStatus.AsObject;
@@ -596,6 +959,7 @@ export namespace WorkspaceStatus {
         repo?: content_service_api_initializer_pb.GitStatus.AsObject,
         runtime?: WorkspaceRuntimeInfo.AsObject,
         auth?: WorkspaceAuthentication.AsObject,
+        stopReason: StopReason,
     }
 }
 
@@ -647,6 +1011,8 @@ export class WorkspaceSpec extends jspb.Message {
     clearIdeImage(): void;
     getIdeImage(): IDEImage | undefined;
     setIdeImage(value?: IDEImage): WorkspaceSpec;
+    getWorkspaceClass(): string;
+    setWorkspaceClass(value: string): WorkspaceSpec;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): WorkspaceSpec.AsObject;
@@ -668,6 +1034,7 @@ export namespace WorkspaceSpec {
         type: WorkspaceType,
         timeout: string,
         ideImage?: IDEImage.AsObject,
+        workspaceClass: string,
     }
 }
 
@@ -679,6 +1046,11 @@ export class PortSpec extends jspb.Message {
     getUrl(): string;
     setUrl(value: string): PortSpec;
 
+    hasHeaders(): boolean;
+    clearHeaders(): void;
+    getHeaders(): PortHeaders | undefined;
+    setHeaders(value?: PortHeaders): PortSpec;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): PortSpec.AsObject;
     static toObject(includeInstance: boolean, msg: PortSpec): PortSpec.AsObject;
@@ -694,6 +1066,7 @@ export namespace PortSpec {
         port: number,
         visibility: PortVisibility,
         url: string,
+        headers?: PortHeaders.AsObject,
     }
 }
 
@@ -721,6 +1094,16 @@ export class WorkspaceConditions extends jspb.Message {
     setHeadlessTaskFailed(value: string): WorkspaceConditions;
     getStoppedByRequest(): WorkspaceConditionBool;
     setStoppedByRequest(value: WorkspaceConditionBool): WorkspaceConditions;
+    getVolumeSnapshot(): string;
+    setVolumeSnapshot(value: string): WorkspaceConditions;
+    getEvicted(): string;
+    setEvicted(value: string): WorkspaceConditions;
+    clearScheduledSnapshotsList(): void;
+    getScheduledSnapshotsList(): Array<string>;
+    setScheduledSnapshotsList(value: Array<string>): WorkspaceConditions;
+    addScheduledSnapshots(value: string, index?: number): string;
+    getPaused(): WorkspaceConditionBool;
+    setPaused(value: WorkspaceConditionBool): WorkspaceConditions;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): WorkspaceConditions.AsObject;
@@ -744,6 +1127,10 @@ export namespace WorkspaceConditions {
         firstUserActivity?: google_protobuf_timestamp_pb.Timestamp.AsObject,
         headlessTaskFailed: string,
         stoppedByRequest: WorkspaceConditionBool,
+        volumeSnapshot: string,
+        evicted: string,
+        scheduledSnapshotsList: Array<string>,
+        paused: WorkspaceConditionBool,
     }
 }
 
@@ -870,6 +1257,20 @@ export class StartWorkspaceSpec extends jspb.Message {
     clearIdeImage(): void;
     getIdeImage(): IDEImage | undefined;
     setIdeImage(value?: IDEImage): StartWorkspaceSpec;
+    getWorkspaceClass(): string;
+    setWorkspaceClass(value: string): StartWorkspaceSpec;
+    getVolumeSnapshot(): string;
+    setVolumeSnapshot(value: string): StartWorkspaceSpec;
+
+    hasGpu(): boolean;
+    clearGpu(): void;
+    getGpu(): GPURequest | undefined;
+    setGpu(value?: GPURequest): StartWorkspaceSpec;
+
+    hasSnapshotPolicy(): boolean;
+    clearSnapshotPolicy(): void;
+    getSnapshotPolicy(): SnapshotPolicy | undefined;
+    setSnapshotPolicy(value?: SnapshotPolicy): StartWorkspaceSpec;
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StartWorkspaceSpec.AsObject;
@@ -895,6 +1296,56 @@ export namespace StartWorkspaceSpec {
         timeout: string,
         admission: AdmissionLevel,
         ideImage?: IDEImage.AsObject,
+        workspaceClass: string,
+        volumeSnapshot: string,
+        gpu?: GPURequest.AsObject,
+        snapshotPolicy?: SnapshotPolicy.AsObject,
+    }
+}
+
+export class SnapshotPolicy extends jspb.Message {
+    getInterval(): string;
+    setInterval(value: string): SnapshotPolicy;
+    getKeep(): number;
+    setKeep(value: number): SnapshotPolicy;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): SnapshotPolicy.AsObject;
+    static toObject(includeInstance: boolean, msg: SnapshotPolicy): SnapshotPolicy.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: SnapshotPolicy, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): SnapshotPolicy;
+    static deserializeBinaryFromReader(message: SnapshotPolicy, reader: jspb.BinaryReader): SnapshotPolicy;
+}
+
+export namespace SnapshotPolicy {
+    export type AsObject = {
+        interval: string,
+        keep: number,
+    }
+}
+
+export class GPURequest extends jspb.Message {
+    getCount(): number;
+    setCount(value: number): GPURequest;
+    getType(): string;
+    setType(value: string): GPURequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GPURequest.AsObject;
+    static toObject(includeInstance: boolean, msg: GPURequest): GPURequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GPURequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GPURequest;
+    static deserializeBinaryFromReader(message: GPURequest, reader: jspb.BinaryReader): GPURequest;
+}
+
+export namespace GPURequest {
+    export type AsObject = {
+        count: number,
+        type: string,
     }
 }
 
@@ -997,6 +1448,36 @@ export namespace ExposedPorts {
     }
 }
 
+export class PortHeaders extends jspb.Message {
+    clearCorsAllowedOriginsList(): void;
+    getCorsAllowedOriginsList(): Array<string>;
+    setCorsAllowedOriginsList(value: Array<string>): PortHeaders;
+    addCorsAllowedOrigins(value: string, index?: number): string;
+    getXFrameOptions(): string;
+    setXFrameOptions(value: string): PortHeaders;
+
+    getCustomMap(): jspb.Map<string, string>;
+    clearCustomMap(): void;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PortHeaders.AsObject;
+    static toObject(includeInstance: boolean, msg: PortHeaders): PortHeaders.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: PortHeaders, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PortHeaders;
+    static deserializeBinaryFromReader(message: PortHeaders, reader: jspb.BinaryReader): PortHeaders;
+}
+
+export namespace PortHeaders {
+    export type AsObject = {
+        corsAllowedOriginsList: Array<string>,
+        xFrameOptions: string,
+
+        customMap: Array<[string, string]>,
+    }
+}
+
 export enum StopWorkspacePolicy {
     NORMALLY = 0,
     IMMEDIATELY = 1,
@@ -1007,9 +1488,39 @@ export enum AdmissionLevel {
     ADMIT_EVERYONE = 1,
 }
 
+export enum DrainNodeStep {
+    NODE_CORDONED = 0,
+    WORKSPACE_BACKED_UP = 1,
+    WORKSPACE_BACKUP_FAILED = 2,
+    WORKSPACE_STOPPED = 3,
+    WORKSPACE_STOP_FAILED = 4,
+    NODE_DRAINED = 5,
+}
+
+export enum ClusterFeature {
+    NO_CLUSTER_FEATURE = 0,
+    PVC_BACKUPS = 1,
+    GPUS = 2,
+    DRY_RUN_STARTS = 3,
+}
+
 export enum PortVisibility {
     PORT_VISIBILITY_PRIVATE = 0,
     PORT_VISIBILITY_PUBLIC = 1,
+    PORT_VISIBILITY_AUTHENTICATED = 2,
+}
+
+export enum StopReason {
+    STOP_REASON_NONE = 0,
+    STOP_REASON_USER_REQUEST = 1,
+    STOP_REASON_ADMIN_REQUEST = 2,
+    STOP_REASON_TIMEOUT = 3,
+    STOP_REASON_EVICTED = 4,
+    STOP_REASON_PENALTY = 5,
+    STOP_REASON_BACKUP_FAILED = 6,
+    STOP_REASON_FAILED = 7,
+    STOP_REASON_PAUSED = 8,
+    STOP_REASON_COMPLETED = 9,
 }
 
 export enum WorkspaceConditionBool {
@@ -29,20 +29,32 @@ var global = (function() {
 
 var content$service$api_initializer_pb = require('@gitpod/content-service/lib');
 goog.object.extend(proto, content$service$api_initializer_pb);
+var google_protobuf_field_mask_pb = require('google-protobuf/google/protobuf/field_mask_pb.js');
+goog.object.extend(proto, google_protobuf_field_mask_pb);
 var google_protobuf_timestamp_pb = require('google-protobuf/google/protobuf/timestamp_pb.js');
 goog.object.extend(proto, google_protobuf_timestamp_pb);
 goog.exportSymbol('proto.wsman.AdmissionLevel', null, global);
 goog.exportSymbol('proto.wsman.BackupWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.BackupWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.ClusterCapacity', null, global);
+goog.exportSymbol('proto.wsman.ClusterFeature', null, global);
 goog.exportSymbol('proto.wsman.ControlAdmissionRequest', null, global);
 goog.exportSymbol('proto.wsman.ControlAdmissionResponse', null, global);
 goog.exportSymbol('proto.wsman.ControlPortRequest', null, global);
 goog.exportSymbol('proto.wsman.ControlPortResponse', null, global);
+goog.exportSymbol('proto.wsman.DescribeClusterRequest', null, global);
+goog.exportSymbol('proto.wsman.DescribeClusterResponse', null, global);
 goog.exportSymbol('proto.wsman.DescribeWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.DescribeWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.DrainNodeRequest', null, global);
+goog.exportSymbol('proto.wsman.DrainNodeResponse', null, global);
+goog.exportSymbol('proto.wsman.DrainNodeStep', null, global);
 goog.exportSymbol('proto.wsman.EnvironmentVariable', null, global);
 goog.exportSymbol('proto.wsman.EnvironmentVariable.SecretKeyRef', null, global);
 goog.exportSymbol('proto.wsman.ExposedPorts', null, global);
+goog.exportSymbol('proto.wsman.GPURequest', null, global);
+goog.exportSymbol('proto.wsman.GetHeadlessLogRequest', null, global);
+goog.exportSymbol('proto.wsman.GetHeadlessLogResponse', null, global);
 goog.exportSymbol('proto.wsman.GetWorkspacesRequest', null, global);
 goog.exportSymbol('proto.wsman.GetWorkspacesResponse', null, global);
 goog.exportSymbol('proto.wsman.GitSpec', null, global);
@@ -50,13 +62,21 @@ goog.exportSymbol('proto.wsman.IDEImage', null, global);
 goog.exportSymbol('proto.wsman.MarkActiveRequest', null, global);
 goog.exportSymbol('proto.wsman.MarkActiveResponse', null, global);
 goog.exportSymbol('proto.wsman.MetadataFilter', null, global);
+goog.exportSymbol('proto.wsman.PauseWorkspaceRequest', null, global);
+goog.exportSymbol('proto.wsman.PauseWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.PortHeaders', null, global);
 goog.exportSymbol('proto.wsman.PortSpec', null, global);
 goog.exportSymbol('proto.wsman.PortVisibility', null, global);
+goog.exportSymbol('proto.wsman.ResumeWorkspaceRequest', null, global);
+goog.exportSymbol('proto.wsman.ResumeWorkspaceResponse', null, global);
 goog.exportSymbol('proto.wsman.SetTimeoutRequest', null, global);
 goog.exportSymbol('proto.wsman.SetTimeoutResponse', null, global);
+goog.exportSymbol('proto.wsman.SnapshotPolicy', null, global);
+goog.exportSymbol('proto.wsman.StartWorkspaceDryRunResult', null, global);
 goog.exportSymbol('proto.wsman.StartWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.StartWorkspaceResponse', null, global);
 goog.exportSymbol('proto.wsman.StartWorkspaceSpec', null, global);
+goog.exportSymbol('proto.wsman.StopReason', null, global);
 goog.exportSymbol('proto.wsman.StopWorkspacePolicy', null, global);
 goog.exportSymbol('proto.wsman.StopWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.StopWorkspaceResponse', null, global);
@@ -65,6 +85,7 @@ goog.exportSymbol('proto.wsman.SubscribeResponse', null, global);
 goog.exportSymbol('proto.wsman.TakeSnapshotRequest', null, global);
 goog.exportSymbol('proto.wsman.TakeSnapshotResponse', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceAuthentication', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceClassDescription', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceConditionBool', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceConditions', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceFeatureFlag', null, global);
@@ -179,6 +200,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.wsman.StartWorkspaceResponse.displayName = 'proto.wsman.StartWorkspaceResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.StartWorkspaceDryRunResult = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.StartWorkspaceDryRunResult, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.StartWorkspaceDryRunResult.displayName = 'proto.wsman.StartWorkspaceDryRunResult';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -221,6 +263,90 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.wsman.StopWorkspaceResponse.displayName = 'proto.wsman.StopWorkspaceResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.PauseWorkspaceRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.PauseWorkspaceRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.PauseWorkspaceRequest.displayName = 'proto.wsman.PauseWorkspaceRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.PauseWorkspaceResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.PauseWorkspaceResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.PauseWorkspaceResponse.displayName = 'proto.wsman.PauseWorkspaceResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ResumeWorkspaceRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ResumeWorkspaceRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ResumeWorkspaceRequest.displayName = 'proto.wsman.ResumeWorkspaceRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ResumeWorkspaceResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ResumeWorkspaceResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ResumeWorkspaceResponse.displayName = 'proto.wsman.ResumeWorkspaceResponse';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -274,7 +400,7 @@ if (goog.DEBUG && !COMPILED) {
  * @constructor
  */
 proto.wsman.SubscribeRequest = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.SubscribeRequest.repeatedFields_, null);
 };
 goog.inherits(proto.wsman.SubscribeRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
@@ -515,6 +641,174 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.wsman.ControlAdmissionResponse.displayName = 'proto.wsman.ControlAdmissionResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DrainNodeRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DrainNodeRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DrainNodeRequest.displayName = 'proto.wsman.DrainNodeRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DrainNodeResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DrainNodeResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DrainNodeResponse.displayName = 'proto.wsman.DrainNodeResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GetHeadlessLogRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GetHeadlessLogRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GetHeadlessLogRequest.displayName = 'proto.wsman.GetHeadlessLogRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GetHeadlessLogResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GetHeadlessLogResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GetHeadlessLogResponse.displayName = 'proto.wsman.GetHeadlessLogResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeClusterRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DescribeClusterRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeClusterRequest.displayName = 'proto.wsman.DescribeClusterRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeClusterResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.DescribeClusterResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.DescribeClusterResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeClusterResponse.displayName = 'proto.wsman.DescribeClusterResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ClusterCapacity = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ClusterCapacity, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ClusterCapacity.displayName = 'proto.wsman.ClusterCapacity';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceClassDescription = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceClassDescription, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceClassDescription.displayName = 'proto.wsman.WorkspaceClassDescription';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -652,7 +946,7 @@ if (goog.DEBUG && !COMPILED) {
  * @constructor
  */
 proto.wsman.WorkspaceConditions = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.WorkspaceConditions.repeatedFields_, null);
 };
 goog.inherits(proto.wsman.WorkspaceConditions, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
@@ -756,16 +1050,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.GitSpec = function(opt_data) {
+proto.wsman.SnapshotPolicy = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.GitSpec, jspb.Message);
+goog.inherits(proto.wsman.SnapshotPolicy, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.GitSpec.displayName = 'proto.wsman.GitSpec';
+  proto.wsman.SnapshotPolicy.displayName = 'proto.wsman.SnapshotPolicy';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -777,16 +1071,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.EnvironmentVariable = function(opt_data) {
+proto.wsman.GPURequest = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.EnvironmentVariable, jspb.Message);
+goog.inherits(proto.wsman.GPURequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.EnvironmentVariable.displayName = 'proto.wsman.EnvironmentVariable';
+  proto.wsman.GPURequest.displayName = 'proto.wsman.GPURequest';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -798,16 +1092,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.EnvironmentVariable.SecretKeyRef = function(opt_data) {
+proto.wsman.GitSpec = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.EnvironmentVariable.SecretKeyRef, jspb.Message);
+goog.inherits(proto.wsman.GitSpec, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.EnvironmentVariable.SecretKeyRef.displayName = 'proto.wsman.EnvironmentVariable.SecretKeyRef';
+  proto.wsman.GitSpec.displayName = 'proto.wsman.GitSpec';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -819,18 +1113,81 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.ExposedPorts = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.ExposedPorts.repeatedFields_, null);
+proto.wsman.EnvironmentVariable = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.ExposedPorts, jspb.Message);
+goog.inherits(proto.wsman.EnvironmentVariable, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.ExposedPorts.displayName = 'proto.wsman.ExposedPorts';
+  proto.wsman.EnvironmentVariable.displayName = 'proto.wsman.EnvironmentVariable';
 }
-
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.EnvironmentVariable.SecretKeyRef = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.EnvironmentVariable.SecretKeyRef, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.EnvironmentVariable.SecretKeyRef.displayName = 'proto.wsman.EnvironmentVariable.SecretKeyRef';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ExposedPorts = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.ExposedPorts.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.ExposedPorts, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ExposedPorts.displayName = 'proto.wsman.ExposedPorts';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.PortHeaders = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.PortHeaders.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.PortHeaders, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.PortHeaders.displayName = 'proto.wsman.PortHeaders';
+}
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -1370,7 +1727,8 @@ proto.wsman.StartWorkspaceRequest.toObject = function(includeInstance, msg) {
     servicePrefix: jspb.Message.getFieldWithDefault(msg, 2, ""),
     metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
     spec: (f = msg.getSpec()) && proto.wsman.StartWorkspaceSpec.toObject(includeInstance, f),
-    type: jspb.Message.getFieldWithDefault(msg, 6, 0)
+    type: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    dryRun: jspb.Message.getBooleanFieldWithDefault(msg, 7, false)
   };
 
   if (includeInstance) {
@@ -1429,6 +1787,10 @@ proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader = function(msg, re
       var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
       msg.setType(value);
       break;
+    case 7:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDryRun(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1495,6 +1857,13 @@ proto.wsman.StartWorkspaceRequest.serializeBinaryToWriter = function(message, wr
       f
     );
   }
+  f = message.getDryRun();
+  if (f) {
+    writer.writeBool(
+      7,
+      f
+    );
+  }
 };
 
 
@@ -1626,6 +1995,24 @@ proto.wsman.StartWorkspaceRequest.prototype.setType = function(value) {
 };
 
 
+/**
+ * optional bool dry_run = 7;
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getDryRun = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 7, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsman.StartWorkspaceRequest} returns this
+ */
+proto.wsman.StartWorkspaceRequest.prototype.setDryRun = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 7, value);
+};
+
+
 
 
 
@@ -1659,7 +2046,8 @@ proto.wsman.StartWorkspaceResponse.prototype.toObject = function(opt_includeInst
 proto.wsman.StartWorkspaceResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
     url: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    ownerToken: jspb.Message.getFieldWithDefault(msg, 2, "")
+    ownerToken: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    dryRun: (f = msg.getDryRun()) && proto.wsman.StartWorkspaceDryRunResult.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -1704,6 +2092,11 @@ proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader = function(msg, r
       var value = /** @type {string} */ (reader.readString());
       msg.setOwnerToken(value);
       break;
+    case 3:
+      var value = new proto.wsman.StartWorkspaceDryRunResult;
+      reader.readMessage(value,proto.wsman.StartWorkspaceDryRunResult.deserializeBinaryFromReader);
+      msg.setDryRun(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1747,6 +2140,14 @@ proto.wsman.StartWorkspaceResponse.serializeBinaryToWriter = function(message, w
       f
     );
   }
+  f = message.getDryRun();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.StartWorkspaceDryRunResult.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -1786,6 +2187,42 @@ proto.wsman.StartWorkspaceResponse.prototype.setOwnerToken = function(value) {
 };
 
 
+/**
+ * optional StartWorkspaceDryRunResult dry_run = 3;
+ * @return {?proto.wsman.StartWorkspaceDryRunResult}
+ */
+proto.wsman.StartWorkspaceResponse.prototype.getDryRun = function() {
+  return /** @type{?proto.wsman.StartWorkspaceDryRunResult} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.StartWorkspaceDryRunResult, 3));
+};
+
+
+/**
+ * @param {?proto.wsman.StartWorkspaceDryRunResult|undefined} value
+ * @return {!proto.wsman.StartWorkspaceResponse} returns this
+*/
+proto.wsman.StartWorkspaceResponse.prototype.setDryRun = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.StartWorkspaceResponse} returns this
+ */
+proto.wsman.StartWorkspaceResponse.prototype.clearDryRun = function() {
+  return this.setDryRun(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceResponse.prototype.hasDryRun = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
 
 
 
@@ -1802,8 +2239,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.StopWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StopWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsman.StartWorkspaceDryRunResult.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceDryRunResult.toObject(opt_includeInstance, this);
 };
 
 
@@ -1812,14 +2249,16 @@ proto.wsman.StopWorkspaceRequest.prototype.toObject = function(opt_includeInstan
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.StopWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.StartWorkspaceDryRunResult} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StopWorkspaceRequest.toObject = function(includeInstance, msg) {
+proto.wsman.StartWorkspaceDryRunResult.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    policy: jspb.Message.getFieldWithDefault(msg, 2, 0)
+    fits: jspb.Message.getBooleanFieldWithDefault(msg, 1, false),
+    nodeName: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    reason: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    pod: jspb.Message.getFieldWithDefault(msg, 4, "")
   };
 
   if (includeInstance) {
@@ -1833,23 +2272,23 @@ proto.wsman.StopWorkspaceRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StopWorkspaceRequest}
+ * @return {!proto.wsman.StartWorkspaceDryRunResult}
  */
-proto.wsman.StopWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsman.StartWorkspaceDryRunResult.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StopWorkspaceRequest;
-  return proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.StartWorkspaceDryRunResult;
+  return proto.wsman.StartWorkspaceDryRunResult.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.StopWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.StartWorkspaceDryRunResult} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StopWorkspaceRequest}
+ * @return {!proto.wsman.StartWorkspaceDryRunResult}
  */
-proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.StartWorkspaceDryRunResult.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1857,12 +2296,20 @@ proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader = function(msg, rea
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setFits(value);
       break;
     case 2:
-      var value = /** @type {!proto.wsman.StopWorkspacePolicy} */ (reader.readEnum());
-      msg.setPolicy(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setNodeName(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setReason(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPod(value);
       break;
     default:
       reader.skipField();
@@ -1877,9 +2324,9 @@ proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader = function(msg, rea
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.StopWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsman.StartWorkspaceDryRunResult.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.StartWorkspaceDryRunResult.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1887,62 +2334,112 @@ proto.wsman.StopWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StopWorkspaceRequest} message
+ * @param {!proto.wsman.StartWorkspaceDryRunResult} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.StartWorkspaceDryRunResult.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getFits();
+  if (f) {
+    writer.writeBool(
       1,
       f
     );
   }
-  f = message.getPolicy();
-  if (f !== 0.0) {
-    writer.writeEnum(
+  f = message.getNodeName();
+  if (f.length > 0) {
+    writer.writeString(
       2,
       f
     );
   }
+  f = message.getReason();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getPod();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string id = 1;
+ * optional bool fits = 1;
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceDryRunResult.prototype.getFits = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 1, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsman.StartWorkspaceDryRunResult} returns this
+ */
+proto.wsman.StartWorkspaceDryRunResult.prototype.setFits = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 1, value);
+};
+
+
+/**
+ * optional string node_name = 2;
  * @return {string}
  */
-proto.wsman.StopWorkspaceRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.StartWorkspaceDryRunResult.prototype.getNodeName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.StopWorkspaceRequest} returns this
+ * @return {!proto.wsman.StartWorkspaceDryRunResult} returns this
  */
-proto.wsman.StopWorkspaceRequest.prototype.setId = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.wsman.StartWorkspaceDryRunResult.prototype.setNodeName = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional StopWorkspacePolicy policy = 2;
- * @return {!proto.wsman.StopWorkspacePolicy}
+ * optional string reason = 3;
+ * @return {string}
  */
-proto.wsman.StopWorkspaceRequest.prototype.getPolicy = function() {
-  return /** @type {!proto.wsman.StopWorkspacePolicy} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+proto.wsman.StartWorkspaceDryRunResult.prototype.getReason = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * @param {!proto.wsman.StopWorkspacePolicy} value
- * @return {!proto.wsman.StopWorkspaceRequest} returns this
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceDryRunResult} returns this
  */
-proto.wsman.StopWorkspaceRequest.prototype.setPolicy = function(value) {
-  return jspb.Message.setProto3EnumField(this, 2, value);
+proto.wsman.StartWorkspaceDryRunResult.prototype.setReason = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string pod = 4;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceDryRunResult.prototype.getPod = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceDryRunResult} returns this
+ */
+proto.wsman.StartWorkspaceDryRunResult.prototype.setPod = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
 };
 
 
@@ -1962,8 +2459,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.StopWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StopWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsman.StopWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StopWorkspaceRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -1972,13 +2469,15 @@ proto.wsman.StopWorkspaceResponse.prototype.toObject = function(opt_includeInsta
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.StopWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.StopWorkspaceRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StopWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsman.StopWorkspaceRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    policy: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    reason: jspb.Message.getFieldWithDefault(msg, 3, 0)
   };
 
   if (includeInstance) {
@@ -1992,29 +2491,41 @@ proto.wsman.StopWorkspaceResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StopWorkspaceResponse}
+ * @return {!proto.wsman.StopWorkspaceRequest}
  */
-proto.wsman.StopWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsman.StopWorkspaceRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StopWorkspaceResponse;
-  return proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.StopWorkspaceRequest;
+  return proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.StopWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.StopWorkspaceRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StopWorkspaceResponse}
+ * @return {!proto.wsman.StopWorkspaceRequest}
  */
-proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.StopWorkspacePolicy} */ (reader.readEnum());
+      msg.setPolicy(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.wsman.StopReason} */ (reader.readEnum());
+      msg.setReason(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -2028,9 +2539,9 @@ proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader = function(msg, re
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.StopWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsman.StopWorkspaceRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2038,12 +2549,87 @@ proto.wsman.StopWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StopWorkspaceResponse} message
+ * @param {!proto.wsman.StopWorkspaceRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getPolicy();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getReason();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StopWorkspaceRequest} returns this
+ */
+proto.wsman.StopWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional StopWorkspacePolicy policy = 2;
+ * @return {!proto.wsman.StopWorkspacePolicy}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.getPolicy = function() {
+  return /** @type {!proto.wsman.StopWorkspacePolicy} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.StopWorkspacePolicy} value
+ * @return {!proto.wsman.StopWorkspaceRequest} returns this
+ */
+proto.wsman.StopWorkspaceRequest.prototype.setPolicy = function(value) {
+  return jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * optional StopReason reason = 3;
+ * @return {!proto.wsman.StopReason}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.getReason = function() {
+  return /** @type {!proto.wsman.StopReason} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.StopReason} value
+ * @return {!proto.wsman.StopWorkspaceRequest} returns this
+ */
+proto.wsman.StopWorkspaceRequest.prototype.setReason = function(value) {
+  return jspb.Message.setProto3EnumField(this, 3, value);
 };
 
 
@@ -2063,8 +2649,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.DescribeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.DescribeWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsman.StopWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StopWorkspaceResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -2073,13 +2659,13 @@ proto.wsman.DescribeWorkspaceRequest.prototype.toObject = function(opt_includeIn
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.DescribeWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.StopWorkspaceResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceRequest.toObject = function(includeInstance, msg) {
+proto.wsman.StopWorkspaceResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+
   };
 
   if (includeInstance) {
@@ -2093,33 +2679,29 @@ proto.wsman.DescribeWorkspaceRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.DescribeWorkspaceRequest}
+ * @return {!proto.wsman.StopWorkspaceResponse}
  */
-proto.wsman.DescribeWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsman.StopWorkspaceResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.DescribeWorkspaceRequest;
-  return proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.StopWorkspaceResponse;
+  return proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.DescribeWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.StopWorkspaceResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.DescribeWorkspaceRequest}
+ * @return {!proto.wsman.StopWorkspaceResponse}
  */
-proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -2133,9 +2715,9 @@ proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg,
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.DescribeWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsman.StopWorkspaceResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2143,43 +2725,17 @@ proto.wsman.DescribeWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.DescribeWorkspaceRequest} message
+ * @param {!proto.wsman.StopWorkspaceResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-};
-
-
-/**
- * optional string id = 1;
- * @return {string}
- */
-proto.wsman.DescribeWorkspaceRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.wsman.DescribeWorkspaceRequest} returns this
- */
-proto.wsman.DescribeWorkspaceRequest.prototype.setId = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -2193,8 +2749,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.DescribeWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsman.PauseWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.PauseWorkspaceRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -2203,14 +2759,13 @@ proto.wsman.DescribeWorkspaceResponse.prototype.toObject = function(opt_includeI
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.DescribeWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.PauseWorkspaceRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsman.PauseWorkspaceRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
-    lastactivity: jspb.Message.getFieldWithDefault(msg, 2, "")
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -2224,23 +2779,23 @@ proto.wsman.DescribeWorkspaceResponse.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.DescribeWorkspaceResponse}
+ * @return {!proto.wsman.PauseWorkspaceRequest}
  */
-proto.wsman.DescribeWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsman.PauseWorkspaceRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.DescribeWorkspaceResponse;
-  return proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.PauseWorkspaceRequest;
+  return proto.wsman.PauseWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.DescribeWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.PauseWorkspaceRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.DescribeWorkspaceResponse}
+ * @return {!proto.wsman.PauseWorkspaceRequest}
  */
-proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.PauseWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2248,13 +2803,8 @@ proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new proto.wsman.WorkspaceStatus;
-      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
-      msg.setStatus(value);
-      break;
-    case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setLastactivity(value);
+      msg.setId(value);
       break;
     default:
       reader.skipField();
@@ -2269,9 +2819,9 @@ proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsman.PauseWorkspaceRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.PauseWorkspaceRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2279,24 +2829,16 @@ proto.wsman.DescribeWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.DescribeWorkspaceResponse} message
+ * @param {!proto.wsman.PauseWorkspaceRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.PauseWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getStatus();
-  if (f != null) {
-    writer.writeMessage(
-      1,
-      f,
-      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
-    );
-  }
-  f = message.getLastactivity();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
-      2,
+      1,
       f
     );
   }
@@ -2304,63 +2846,25 @@ proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter = function(message
 
 
 /**
- * optional WorkspaceStatus status = 1;
- * @return {?proto.wsman.WorkspaceStatus}
- */
-proto.wsman.DescribeWorkspaceResponse.prototype.getStatus = function() {
-  return /** @type{?proto.wsman.WorkspaceStatus} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
-};
-
-
-/**
- * @param {?proto.wsman.WorkspaceStatus|undefined} value
- * @return {!proto.wsman.DescribeWorkspaceResponse} returns this
-*/
-proto.wsman.DescribeWorkspaceResponse.prototype.setStatus = function(value) {
-  return jspb.Message.setWrapperField(this, 1, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.DescribeWorkspaceResponse} returns this
- */
-proto.wsman.DescribeWorkspaceResponse.prototype.clearStatus = function() {
-  return this.setStatus(undefined);
-};
-
-
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.wsman.DescribeWorkspaceResponse.prototype.hasStatus = function() {
-  return jspb.Message.getField(this, 1) != null;
-};
-
-
-/**
- * optional string lastActivity = 2;
+ * optional string id = 1;
  * @return {string}
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.getLastactivity = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.PauseWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.DescribeWorkspaceResponse} returns this
+ * @return {!proto.wsman.PauseWorkspaceRequest} returns this
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.setLastactivity = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.wsman.PauseWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -2374,8 +2878,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SubscribeRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SubscribeRequest.toObject(opt_includeInstance, this);
+proto.wsman.PauseWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.PauseWorkspaceResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -2384,13 +2888,13 @@ proto.wsman.SubscribeRequest.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SubscribeRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.PauseWorkspaceResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeRequest.toObject = function(includeInstance, msg) {
+proto.wsman.PauseWorkspaceResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    mustMatch: (f = msg.getMustMatch()) && proto.wsman.MetadataFilter.toObject(includeInstance, f)
+
   };
 
   if (includeInstance) {
@@ -2404,34 +2908,29 @@ proto.wsman.SubscribeRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SubscribeRequest}
+ * @return {!proto.wsman.PauseWorkspaceResponse}
  */
-proto.wsman.SubscribeRequest.deserializeBinary = function(bytes) {
+proto.wsman.PauseWorkspaceResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SubscribeRequest;
-  return proto.wsman.SubscribeRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.PauseWorkspaceResponse;
+  return proto.wsman.PauseWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SubscribeRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.PauseWorkspaceResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SubscribeRequest}
+ * @return {!proto.wsman.PauseWorkspaceResponse}
  */
-proto.wsman.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.PauseWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
-    case 1:
-      var value = new proto.wsman.MetadataFilter;
-      reader.readMessage(value,proto.wsman.MetadataFilter.deserializeBinaryFromReader);
-      msg.setMustMatch(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -2445,9 +2944,9 @@ proto.wsman.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader)
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SubscribeRequest.prototype.serializeBinary = function() {
+proto.wsman.PauseWorkspaceResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SubscribeRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.PauseWorkspaceResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2455,64 +2954,18 @@ proto.wsman.SubscribeRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SubscribeRequest} message
+ * @param {!proto.wsman.PauseWorkspaceResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.PauseWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getMustMatch();
-  if (f != null) {
-    writer.writeMessage(
-      1,
-      f,
-      proto.wsman.MetadataFilter.serializeBinaryToWriter
-    );
-  }
 };
 
 
-/**
- * optional MetadataFilter must_match = 1;
- * @return {?proto.wsman.MetadataFilter}
- */
-proto.wsman.SubscribeRequest.prototype.getMustMatch = function() {
-  return /** @type{?proto.wsman.MetadataFilter} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.MetadataFilter, 1));
-};
 
 
-/**
- * @param {?proto.wsman.MetadataFilter|undefined} value
- * @return {!proto.wsman.SubscribeRequest} returns this
-*/
-proto.wsman.SubscribeRequest.prototype.setMustMatch = function(value) {
-  return jspb.Message.setWrapperField(this, 1, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.SubscribeRequest} returns this
- */
-proto.wsman.SubscribeRequest.prototype.clearMustMatch = function() {
-  return this.setMustMatch(undefined);
-};
-
-
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.wsman.SubscribeRequest.prototype.hasMustMatch = function() {
-  return jspb.Message.getField(this, 1) != null;
-};
-
-
-
-
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
  * Field names that are reserved in JavaScript and will be renamed to pb_name.
@@ -2525,8 +2978,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SubscribeResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SubscribeResponse.toObject(opt_includeInstance, this);
+proto.wsman.ResumeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ResumeWorkspaceRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -2535,14 +2988,13 @@ proto.wsman.SubscribeResponse.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SubscribeResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.ResumeWorkspaceRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeResponse.toObject = function(includeInstance, msg) {
+proto.wsman.ResumeWorkspaceRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
-    headerMap: (f = msg.getHeaderMap()) ? f.toObject(includeInstance, undefined) : []
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -2556,23 +3008,23 @@ proto.wsman.SubscribeResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SubscribeResponse}
+ * @return {!proto.wsman.ResumeWorkspaceRequest}
  */
-proto.wsman.SubscribeResponse.deserializeBinary = function(bytes) {
+proto.wsman.ResumeWorkspaceRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SubscribeResponse;
-  return proto.wsman.SubscribeResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.ResumeWorkspaceRequest;
+  return proto.wsman.ResumeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SubscribeResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.ResumeWorkspaceRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SubscribeResponse}
+ * @return {!proto.wsman.ResumeWorkspaceRequest}
  */
-proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.ResumeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2580,15 +3032,8 @@ proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new proto.wsman.WorkspaceStatus;
-      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
-      msg.setStatus(value);
-      break;
-    case 3:
-      var value = msg.getHeaderMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
-         });
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
       break;
     default:
       reader.skipField();
@@ -2603,9 +3048,9 @@ proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SubscribeResponse.prototype.serializeBinary = function() {
+proto.wsman.ResumeWorkspaceRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SubscribeResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.ResumeWorkspaceRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2613,87 +3058,40 @@ proto.wsman.SubscribeResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SubscribeResponse} message
+ * @param {!proto.wsman.ResumeWorkspaceRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.ResumeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getStatus();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
       1,
-      f,
-      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getHeaderMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
-  }
-};
-
-
-/**
- * optional WorkspaceStatus status = 1;
- * @return {?proto.wsman.WorkspaceStatus}
- */
-proto.wsman.SubscribeResponse.prototype.getStatus = function() {
-  return /** @type{?proto.wsman.WorkspaceStatus} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
-};
-
-
-/**
- * @param {?proto.wsman.WorkspaceStatus|undefined} value
- * @return {!proto.wsman.SubscribeResponse} returns this
-*/
-proto.wsman.SubscribeResponse.prototype.setStatus = function(value) {
-  return jspb.Message.setWrapperField(this, 1, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.SubscribeResponse} returns this
- */
-proto.wsman.SubscribeResponse.prototype.clearStatus = function() {
-  return this.setStatus(undefined);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * optional string id = 1;
+ * @return {string}
  */
-proto.wsman.SubscribeResponse.prototype.hasStatus = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.wsman.ResumeWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * map<string, string> header = 3;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
+ * @param {string} value
+ * @return {!proto.wsman.ResumeWorkspaceRequest} returns this
  */
-proto.wsman.SubscribeResponse.prototype.getHeaderMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
-      null));
+proto.wsman.ResumeWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
-/**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.wsman.SubscribeResponse} returns this
- */
-proto.wsman.SubscribeResponse.prototype.clearHeaderMap = function() {
-  this.getHeaderMap().clear();
-  return this;};
-
-
-
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -2709,8 +3107,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.MarkActiveRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.MarkActiveRequest.toObject(opt_includeInstance, this);
+proto.wsman.ResumeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ResumeWorkspaceResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -2719,14 +3117,13 @@ proto.wsman.MarkActiveRequest.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.MarkActiveRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.ResumeWorkspaceResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.MarkActiveRequest.toObject = function(includeInstance, msg) {
+proto.wsman.ResumeWorkspaceResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    closed: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -2740,23 +3137,23 @@ proto.wsman.MarkActiveRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.MarkActiveRequest}
+ * @return {!proto.wsman.ResumeWorkspaceResponse}
  */
-proto.wsman.MarkActiveRequest.deserializeBinary = function(bytes) {
+proto.wsman.ResumeWorkspaceResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.MarkActiveRequest;
-  return proto.wsman.MarkActiveRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.ResumeWorkspaceResponse;
+  return proto.wsman.ResumeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.MarkActiveRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.ResumeWorkspaceResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.MarkActiveRequest}
+ * @return {!proto.wsman.ResumeWorkspaceResponse}
  */
-proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.ResumeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2765,11 +3162,7 @@ proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
-      break;
-    case 2:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setClosed(value);
+      msg.setUrl(value);
       break;
     default:
       reader.skipField();
@@ -2784,9 +3177,9 @@ proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.MarkActiveRequest.prototype.serializeBinary = function() {
+proto.wsman.ResumeWorkspaceResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.MarkActiveRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.ResumeWorkspaceResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2794,65 +3187,40 @@ proto.wsman.MarkActiveRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.MarkActiveRequest} message
+ * @param {!proto.wsman.ResumeWorkspaceResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.MarkActiveRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.ResumeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
+  f = message.getUrl();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getClosed();
-  if (f) {
-    writer.writeBool(
-      2,
-      f
-    );
-  }
 };
 
 
 /**
- * optional string id = 1;
+ * optional string url = 1;
  * @return {string}
  */
-proto.wsman.MarkActiveRequest.prototype.getId = function() {
+proto.wsman.ResumeWorkspaceResponse.prototype.getUrl = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.MarkActiveRequest} returns this
+ * @return {!proto.wsman.ResumeWorkspaceResponse} returns this
  */
-proto.wsman.MarkActiveRequest.prototype.setId = function(value) {
+proto.wsman.ResumeWorkspaceResponse.prototype.setUrl = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
-/**
- * optional bool closed = 2;
- * @return {boolean}
- */
-proto.wsman.MarkActiveRequest.prototype.getClosed = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
-};
-
-
-/**
- * @param {boolean} value
- * @return {!proto.wsman.MarkActiveRequest} returns this
- */
-proto.wsman.MarkActiveRequest.prototype.setClosed = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 2, value);
-};
-
-
 
 
 
@@ -2869,8 +3237,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.MarkActiveResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.MarkActiveResponse.toObject(opt_includeInstance, this);
+proto.wsman.DescribeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeWorkspaceRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -2879,13 +3247,13 @@ proto.wsman.MarkActiveResponse.prototype.toObject = function(opt_includeInstance
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.MarkActiveResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.DescribeWorkspaceRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.MarkActiveResponse.toObject = function(includeInstance, msg) {
+proto.wsman.DescribeWorkspaceRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -2899,29 +3267,33 @@ proto.wsman.MarkActiveResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.MarkActiveResponse}
+ * @return {!proto.wsman.DescribeWorkspaceRequest}
  */
-proto.wsman.MarkActiveResponse.deserializeBinary = function(bytes) {
+proto.wsman.DescribeWorkspaceRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.MarkActiveResponse;
-  return proto.wsman.MarkActiveResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DescribeWorkspaceRequest;
+  return proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.MarkActiveResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.DescribeWorkspaceRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.MarkActiveResponse}
+ * @return {!proto.wsman.DescribeWorkspaceRequest}
  */
-proto.wsman.MarkActiveResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -2935,9 +3307,9 @@ proto.wsman.MarkActiveResponse.deserializeBinaryFromReader = function(msg, reade
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.MarkActiveResponse.prototype.serializeBinary = function() {
+proto.wsman.DescribeWorkspaceRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.MarkActiveResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2945,12 +3317,37 @@ proto.wsman.MarkActiveResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.MarkActiveResponse} message
+ * @param {!proto.wsman.DescribeWorkspaceRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.MarkActiveResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.DescribeWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.DescribeWorkspaceRequest} returns this
+ */
+proto.wsman.DescribeWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
@@ -2970,8 +3367,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SetTimeoutRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SetTimeoutRequest.toObject(opt_includeInstance, this);
+proto.wsman.DescribeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeWorkspaceResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -2980,14 +3377,14 @@ proto.wsman.SetTimeoutRequest.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SetTimeoutRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.DescribeWorkspaceResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SetTimeoutRequest.toObject = function(includeInstance, msg) {
+proto.wsman.DescribeWorkspaceResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    duration: jspb.Message.getFieldWithDefault(msg, 2, "")
+    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
+    lastactivity: jspb.Message.getFieldWithDefault(msg, 2, "")
   };
 
   if (includeInstance) {
@@ -3001,23 +3398,23 @@ proto.wsman.SetTimeoutRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SetTimeoutRequest}
+ * @return {!proto.wsman.DescribeWorkspaceResponse}
  */
-proto.wsman.SetTimeoutRequest.deserializeBinary = function(bytes) {
+proto.wsman.DescribeWorkspaceResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SetTimeoutRequest;
-  return proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DescribeWorkspaceResponse;
+  return proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SetTimeoutRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.DescribeWorkspaceResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SetTimeoutRequest}
+ * @return {!proto.wsman.DescribeWorkspaceResponse}
  */
-proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3025,12 +3422,13 @@ proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
+      var value = new proto.wsman.WorkspaceStatus;
+      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
       break;
     case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setDuration(value);
+      msg.setLastactivity(value);
       break;
     default:
       reader.skipField();
@@ -3045,9 +3443,9 @@ proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SetTimeoutRequest.prototype.serializeBinary = function() {
+proto.wsman.DescribeWorkspaceResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SetTimeoutRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3055,20 +3453,21 @@ proto.wsman.SetTimeoutRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SetTimeoutRequest} message
+ * @param {!proto.wsman.DescribeWorkspaceResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
       1,
-      f
+      f,
+      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
     );
   }
-  f = message.getDuration();
+  f = message.getLastactivity();
   if (f.length > 0) {
     writer.writeString(
       2,
@@ -3079,142 +3478,67 @@ proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer
 
 
 /**
- * optional string id = 1;
- * @return {string}
- */
-proto.wsman.SetTimeoutRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.wsman.SetTimeoutRequest} returns this
- */
-proto.wsman.SetTimeoutRequest.prototype.setId = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
-};
-
-
-/**
- * optional string duration = 2;
- * @return {string}
+ * optional WorkspaceStatus status = 1;
+ * @return {?proto.wsman.WorkspaceStatus}
  */
-proto.wsman.SetTimeoutRequest.prototype.getDuration = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.DescribeWorkspaceResponse.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.WorkspaceStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.SetTimeoutRequest} returns this
- */
-proto.wsman.SetTimeoutRequest.prototype.setDuration = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+ * @param {?proto.wsman.WorkspaceStatus|undefined} value
+ * @return {!proto.wsman.DescribeWorkspaceResponse} returns this
+*/
+proto.wsman.DescribeWorkspaceResponse.prototype.setStatus = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
 };
 
 
-
-
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.DescribeWorkspaceResponse} returns this
  */
-proto.wsman.SetTimeoutResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SetTimeoutResponse.toObject(opt_includeInstance, this);
+proto.wsman.DescribeWorkspaceResponse.prototype.clearStatus = function() {
+  return this.setStatus(undefined);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.wsman.SetTimeoutResponse} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.SetTimeoutResponse.toObject = function(includeInstance, msg) {
-  var f, obj = {
-
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.wsman.DescribeWorkspaceResponse.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SetTimeoutResponse}
+ * optional string lastActivity = 2;
+ * @return {string}
  */
-proto.wsman.SetTimeoutResponse.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SetTimeoutResponse;
-  return proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader(msg, reader);
+proto.wsman.DescribeWorkspaceResponse.prototype.getLastactivity = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.wsman.SetTimeoutResponse} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SetTimeoutResponse}
+ * @param {string} value
+ * @return {!proto.wsman.DescribeWorkspaceResponse} returns this
  */
-proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.wsman.DescribeWorkspaceResponse.prototype.setLastactivity = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
-/**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
- */
-proto.wsman.SetTimeoutResponse.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.SetTimeoutResponse.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
-};
-
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SetTimeoutResponse} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
  */
-proto.wsman.SetTimeoutResponse.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-};
-
-
+proto.wsman.SubscribeRequest.repeatedFields_ = [2,3];
 
 
 
@@ -3231,8 +3555,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlPortRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlPortRequest.toObject(opt_includeInstance, this);
+proto.wsman.SubscribeRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SubscribeRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -3241,15 +3565,16 @@ proto.wsman.ControlPortRequest.prototype.toObject = function(opt_includeInstance
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlPortRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.SubscribeRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortRequest.toObject = function(includeInstance, msg) {
+proto.wsman.SubscribeRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    expose: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
-    spec: (f = msg.getSpec()) && proto.wsman.PortSpec.toObject(includeInstance, f)
+    mustMatch: (f = msg.getMustMatch()) && proto.wsman.MetadataFilter.toObject(includeInstance, f),
+    idsList: (f = jspb.Message.getRepeatedField(msg, 2)) == null ? undefined : f,
+    phasesList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
+    fieldMask: (f = msg.getFieldMask()) && google_protobuf_field_mask_pb.FieldMask.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -3263,23 +3588,23 @@ proto.wsman.ControlPortRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlPortRequest}
+ * @return {!proto.wsman.SubscribeRequest}
  */
-proto.wsman.ControlPortRequest.deserializeBinary = function(bytes) {
+proto.wsman.SubscribeRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlPortRequest;
-  return proto.wsman.ControlPortRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.SubscribeRequest;
+  return proto.wsman.SubscribeRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlPortRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.SubscribeRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlPortRequest}
+ * @return {!proto.wsman.SubscribeRequest}
  */
-proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3287,17 +3612,24 @@ proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reade
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
+      var value = new proto.wsman.MetadataFilter;
+      reader.readMessage(value,proto.wsman.MetadataFilter.deserializeBinaryFromReader);
+      msg.setMustMatch(value);
       break;
     case 2:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setExpose(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.addIds(value);
       break;
     case 3:
-      var value = new proto.wsman.PortSpec;
-      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
-      msg.setSpec(value);
+      var values = /** @type {!Array<!proto.wsman.WorkspacePhase>} */ (reader.isDelimited() ? reader.readPackedEnum() : [reader.readEnum()]);
+      for (var i = 0; i < values.length; i++) {
+        msg.addPhases(values[i]);
+      }
+      break;
+    case 4:
+      var value = new google_protobuf_field_mask_pb.FieldMask;
+      reader.readMessage(value,google_protobuf_field_mask_pb.FieldMask.deserializeBinaryFromReader);
+      msg.setFieldMask(value);
       break;
     default:
       reader.skipField();
@@ -3312,9 +3644,9 @@ proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reade
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.ControlPortRequest.prototype.serializeBinary = function() {
+proto.wsman.SubscribeRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlPortRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.SubscribeRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3322,98 +3654,181 @@ proto.wsman.ControlPortRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlPortRequest} message
+ * @param {!proto.wsman.SubscribeRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.SubscribeRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getMustMatch();
+  if (f != null) {
+    writer.writeMessage(
       1,
-      f
+      f,
+      proto.wsman.MetadataFilter.serializeBinaryToWriter
     );
   }
-  f = message.getExpose();
-  if (f) {
-    writer.writeBool(
+  f = message.getIdsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
       2,
       f
     );
   }
-  f = message.getSpec();
+  f = message.getPhasesList();
+  if (f.length > 0) {
+    writer.writePackedEnum(
+      3,
+      f
+    );
+  }
+  f = message.getFieldMask();
   if (f != null) {
     writer.writeMessage(
-      3,
+      4,
       f,
-      proto.wsman.PortSpec.serializeBinaryToWriter
+      google_protobuf_field_mask_pb.FieldMask.serializeBinaryToWriter
     );
   }
 };
 
 
 /**
- * optional string id = 1;
- * @return {string}
+ * optional MetadataFilter must_match = 1;
+ * @return {?proto.wsman.MetadataFilter}
  */
-proto.wsman.ControlPortRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.SubscribeRequest.prototype.getMustMatch = function() {
+  return /** @type{?proto.wsman.MetadataFilter} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.MetadataFilter, 1));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.ControlPortRequest} returns this
+ * @param {?proto.wsman.MetadataFilter|undefined} value
+ * @return {!proto.wsman.SubscribeRequest} returns this
+*/
+proto.wsman.SubscribeRequest.prototype.setMustMatch = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.SubscribeRequest} returns this
  */
-proto.wsman.ControlPortRequest.prototype.setId = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.wsman.SubscribeRequest.prototype.clearMustMatch = function() {
+  return this.setMustMatch(undefined);
 };
 
 
 /**
- * optional bool expose = 2;
+ * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.ControlPortRequest.prototype.getExpose = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+proto.wsman.SubscribeRequest.prototype.hasMustMatch = function() {
+  return jspb.Message.getField(this, 1) != null;
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.wsman.ControlPortRequest} returns this
+ * repeated string ids = 2;
+ * @return {!Array<string>}
  */
-proto.wsman.ControlPortRequest.prototype.setExpose = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 2, value);
+proto.wsman.SubscribeRequest.prototype.getIdsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 2));
 };
 
 
 /**
- * optional PortSpec spec = 3;
- * @return {?proto.wsman.PortSpec}
+ * @param {!Array<string>} value
+ * @return {!proto.wsman.SubscribeRequest} returns this
  */
-proto.wsman.ControlPortRequest.prototype.getSpec = function() {
-  return /** @type{?proto.wsman.PortSpec} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.PortSpec, 3));
+proto.wsman.SubscribeRequest.prototype.setIdsList = function(value) {
+  return jspb.Message.setField(this, 2, value || []);
 };
 
 
 /**
- * @param {?proto.wsman.PortSpec|undefined} value
- * @return {!proto.wsman.ControlPortRequest} returns this
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.SubscribeRequest} returns this
+ */
+proto.wsman.SubscribeRequest.prototype.addIds = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 2, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.SubscribeRequest} returns this
+ */
+proto.wsman.SubscribeRequest.prototype.clearIdsList = function() {
+  return this.setIdsList([]);
+};
+
+
+/**
+ * repeated WorkspacePhase phases = 3;
+ * @return {!Array<!proto.wsman.WorkspacePhase>}
+ */
+proto.wsman.SubscribeRequest.prototype.getPhasesList = function() {
+  return /** @type {!Array<!proto.wsman.WorkspacePhase>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/**
+ * @param {!Array<!proto.wsman.WorkspacePhase>} value
+ * @return {!proto.wsman.SubscribeRequest} returns this
+ */
+proto.wsman.SubscribeRequest.prototype.setPhasesList = function(value) {
+  return jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspacePhase} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.SubscribeRequest} returns this
+ */
+proto.wsman.SubscribeRequest.prototype.addPhases = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.SubscribeRequest} returns this
+ */
+proto.wsman.SubscribeRequest.prototype.clearPhasesList = function() {
+  return this.setPhasesList([]);
+};
+
+
+/**
+ * optional google.protobuf.FieldMask field_mask = 4;
+ * @return {?proto.google.protobuf.FieldMask}
+ */
+proto.wsman.SubscribeRequest.prototype.getFieldMask = function() {
+  return /** @type{?proto.google.protobuf.FieldMask} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_field_mask_pb.FieldMask, 4));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.FieldMask|undefined} value
+ * @return {!proto.wsman.SubscribeRequest} returns this
 */
-proto.wsman.ControlPortRequest.prototype.setSpec = function(value) {
-  return jspb.Message.setWrapperField(this, 3, value);
+proto.wsman.SubscribeRequest.prototype.setFieldMask = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.wsman.ControlPortRequest} returns this
+ * @return {!proto.wsman.SubscribeRequest} returns this
  */
-proto.wsman.ControlPortRequest.prototype.clearSpec = function() {
-  return this.setSpec(undefined);
+proto.wsman.SubscribeRequest.prototype.clearFieldMask = function() {
+  return this.setFieldMask(undefined);
 };
 
 
@@ -3421,8 +3836,8 @@ proto.wsman.ControlPortRequest.prototype.clearSpec = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.ControlPortRequest.prototype.hasSpec = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.wsman.SubscribeRequest.prototype.hasFieldMask = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
@@ -3442,8 +3857,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlPortResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlPortResponse.toObject(opt_includeInstance, this);
+proto.wsman.SubscribeResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SubscribeResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -3452,13 +3867,14 @@ proto.wsman.ControlPortResponse.prototype.toObject = function(opt_includeInstanc
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlPortResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.SubscribeResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortResponse.toObject = function(includeInstance, msg) {
+proto.wsman.SubscribeResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
+    headerMap: (f = msg.getHeaderMap()) ? f.toObject(includeInstance, undefined) : []
   };
 
   if (includeInstance) {
@@ -3472,29 +3888,40 @@ proto.wsman.ControlPortResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlPortResponse}
+ * @return {!proto.wsman.SubscribeResponse}
  */
-proto.wsman.ControlPortResponse.deserializeBinary = function(bytes) {
+proto.wsman.SubscribeResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlPortResponse;
-  return proto.wsman.ControlPortResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.SubscribeResponse;
+  return proto.wsman.SubscribeResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlPortResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.SubscribeResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlPortResponse}
+ * @return {!proto.wsman.SubscribeResponse}
  */
-proto.wsman.ControlPortResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceStatus;
+      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
+      break;
+    case 3:
+      var value = msg.getHeaderMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
     default:
       reader.skipField();
       break;
@@ -3508,9 +3935,9 @@ proto.wsman.ControlPortResponse.deserializeBinaryFromReader = function(msg, read
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.ControlPortResponse.prototype.serializeBinary = function() {
+proto.wsman.SubscribeResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlPortResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.SubscribeResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3518,15 +3945,86 @@ proto.wsman.ControlPortResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlPortResponse} message
+ * @param {!proto.wsman.SubscribeResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.SubscribeResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getHeaderMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * optional WorkspaceStatus status = 1;
+ * @return {?proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.SubscribeResponse.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.WorkspaceStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+};
+
+
+/**
+ * @param {?proto.wsman.WorkspaceStatus|undefined} value
+ * @return {!proto.wsman.SubscribeResponse} returns this
+*/
+proto.wsman.SubscribeResponse.prototype.setStatus = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.SubscribeResponse} returns this
+ */
+proto.wsman.SubscribeResponse.prototype.clearStatus = function() {
+  return this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.SubscribeResponse.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * map<string, string> header = 3;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.SubscribeResponse.prototype.getHeaderMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
+      null));
 };
 
 
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.wsman.SubscribeResponse} returns this
+ */
+proto.wsman.SubscribeResponse.prototype.clearHeaderMap = function() {
+  this.getHeaderMap().clear();
+  return this;};
+
+
 
 
 
@@ -3543,8 +4041,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.TakeSnapshotRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.TakeSnapshotRequest.toObject(opt_includeInstance, this);
+proto.wsman.MarkActiveRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.MarkActiveRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -3553,14 +4051,14 @@ proto.wsman.TakeSnapshotRequest.prototype.toObject = function(opt_includeInstanc
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.TakeSnapshotRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.MarkActiveRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotRequest.toObject = function(includeInstance, msg) {
+proto.wsman.MarkActiveRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    returnImmediately: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
+    closed: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
   };
 
   if (includeInstance) {
@@ -3574,23 +4072,23 @@ proto.wsman.TakeSnapshotRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.TakeSnapshotRequest}
+ * @return {!proto.wsman.MarkActiveRequest}
  */
-proto.wsman.TakeSnapshotRequest.deserializeBinary = function(bytes) {
+proto.wsman.MarkActiveRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.TakeSnapshotRequest;
-  return proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.MarkActiveRequest;
+  return proto.wsman.MarkActiveRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.TakeSnapshotRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.MarkActiveRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.TakeSnapshotRequest}
+ * @return {!proto.wsman.MarkActiveRequest}
  */
-proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3603,7 +4101,7 @@ proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, read
       break;
     case 2:
       var value = /** @type {boolean} */ (reader.readBool());
-      msg.setReturnImmediately(value);
+      msg.setClosed(value);
       break;
     default:
       reader.skipField();
@@ -3618,9 +4116,9 @@ proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, read
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.TakeSnapshotRequest.prototype.serializeBinary = function() {
+proto.wsman.MarkActiveRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.MarkActiveRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3628,11 +4126,11 @@ proto.wsman.TakeSnapshotRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.TakeSnapshotRequest} message
+ * @param {!proto.wsman.MarkActiveRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.MarkActiveRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -3641,7 +4139,7 @@ proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writ
       f
     );
   }
-  f = message.getReturnImmediately();
+  f = message.getClosed();
   if (f) {
     writer.writeBool(
       2,
@@ -3655,34 +4153,34 @@ proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writ
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.TakeSnapshotRequest.prototype.getId = function() {
+proto.wsman.MarkActiveRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.TakeSnapshotRequest} returns this
+ * @return {!proto.wsman.MarkActiveRequest} returns this
  */
-proto.wsman.TakeSnapshotRequest.prototype.setId = function(value) {
+proto.wsman.MarkActiveRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional bool return_immediately = 2;
+ * optional bool closed = 2;
  * @return {boolean}
  */
-proto.wsman.TakeSnapshotRequest.prototype.getReturnImmediately = function() {
+proto.wsman.MarkActiveRequest.prototype.getClosed = function() {
   return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
 };
 
 
 /**
  * @param {boolean} value
- * @return {!proto.wsman.TakeSnapshotRequest} returns this
+ * @return {!proto.wsman.MarkActiveRequest} returns this
  */
-proto.wsman.TakeSnapshotRequest.prototype.setReturnImmediately = function(value) {
+proto.wsman.MarkActiveRequest.prototype.setClosed = function(value) {
   return jspb.Message.setProto3BooleanField(this, 2, value);
 };
 
@@ -3703,8 +4201,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.TakeSnapshotResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.TakeSnapshotResponse.toObject(opt_includeInstance, this);
+proto.wsman.MarkActiveResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.MarkActiveResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -3713,13 +4211,13 @@ proto.wsman.TakeSnapshotResponse.prototype.toObject = function(opt_includeInstan
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.TakeSnapshotResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.MarkActiveResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotResponse.toObject = function(includeInstance, msg) {
+proto.wsman.MarkActiveResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+
   };
 
   if (includeInstance) {
@@ -3733,33 +4231,29 @@ proto.wsman.TakeSnapshotResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.TakeSnapshotResponse}
+ * @return {!proto.wsman.MarkActiveResponse}
  */
-proto.wsman.TakeSnapshotResponse.deserializeBinary = function(bytes) {
+proto.wsman.MarkActiveResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.TakeSnapshotResponse;
-  return proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.MarkActiveResponse;
+  return proto.wsman.MarkActiveResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.TakeSnapshotResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.MarkActiveResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.TakeSnapshotResponse}
+ * @return {!proto.wsman.MarkActiveResponse}
  */
-proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.MarkActiveResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -3773,9 +4267,9 @@ proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, rea
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.TakeSnapshotResponse.prototype.serializeBinary = function() {
+proto.wsman.MarkActiveResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.MarkActiveResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3783,37 +4277,12 @@ proto.wsman.TakeSnapshotResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.TakeSnapshotResponse} message
+ * @param {!proto.wsman.MarkActiveResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.MarkActiveResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getUrl();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-};
-
-
-/**
- * optional string url = 1;
- * @return {string}
- */
-proto.wsman.TakeSnapshotResponse.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.wsman.TakeSnapshotResponse} returns this
- */
-proto.wsman.TakeSnapshotResponse.prototype.setUrl = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
@@ -3833,8 +4302,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlAdmissionRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlAdmissionRequest.toObject(opt_includeInstance, this);
+proto.wsman.SetTimeoutRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SetTimeoutRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -3843,14 +4312,14 @@ proto.wsman.ControlAdmissionRequest.prototype.toObject = function(opt_includeIns
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlAdmissionRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.SetTimeoutRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlAdmissionRequest.toObject = function(includeInstance, msg) {
+proto.wsman.SetTimeoutRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    level: jspb.Message.getFieldWithDefault(msg, 2, 0)
+    duration: jspb.Message.getFieldWithDefault(msg, 2, "")
   };
 
   if (includeInstance) {
@@ -3864,23 +4333,23 @@ proto.wsman.ControlAdmissionRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlAdmissionRequest}
+ * @return {!proto.wsman.SetTimeoutRequest}
  */
-proto.wsman.ControlAdmissionRequest.deserializeBinary = function(bytes) {
+proto.wsman.SetTimeoutRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlAdmissionRequest;
-  return proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.SetTimeoutRequest;
+  return proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlAdmissionRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.SetTimeoutRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlAdmissionRequest}
+ * @return {!proto.wsman.SetTimeoutRequest}
  */
-proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3892,8 +4361,8 @@ proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg,
       msg.setId(value);
       break;
     case 2:
-      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
-      msg.setLevel(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDuration(value);
       break;
     default:
       reader.skipField();
@@ -3908,9 +4377,9 @@ proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg,
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.ControlAdmissionRequest.prototype.serializeBinary = function() {
+proto.wsman.SetTimeoutRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.SetTimeoutRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3918,11 +4387,11 @@ proto.wsman.ControlAdmissionRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlAdmissionRequest} message
+ * @param {!proto.wsman.SetTimeoutRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -3931,9 +4400,9 @@ proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter = function(message,
       f
     );
   }
-  f = message.getLevel();
-  if (f !== 0.0) {
-    writer.writeEnum(
+  f = message.getDuration();
+  if (f.length > 0) {
+    writer.writeString(
       2,
       f
     );
@@ -3945,35 +4414,35 @@ proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter = function(message,
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.ControlAdmissionRequest.prototype.getId = function() {
+proto.wsman.SetTimeoutRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.ControlAdmissionRequest} returns this
+ * @return {!proto.wsman.SetTimeoutRequest} returns this
  */
-proto.wsman.ControlAdmissionRequest.prototype.setId = function(value) {
+proto.wsman.SetTimeoutRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional AdmissionLevel level = 2;
- * @return {!proto.wsman.AdmissionLevel}
+ * optional string duration = 2;
+ * @return {string}
  */
-proto.wsman.ControlAdmissionRequest.prototype.getLevel = function() {
-  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+proto.wsman.SetTimeoutRequest.prototype.getDuration = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * @param {!proto.wsman.AdmissionLevel} value
- * @return {!proto.wsman.ControlAdmissionRequest} returns this
+ * @param {string} value
+ * @return {!proto.wsman.SetTimeoutRequest} returns this
  */
-proto.wsman.ControlAdmissionRequest.prototype.setLevel = function(value) {
-  return jspb.Message.setProto3EnumField(this, 2, value);
+proto.wsman.SetTimeoutRequest.prototype.setDuration = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
@@ -3993,8 +4462,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlAdmissionResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlAdmissionResponse.toObject(opt_includeInstance, this);
+proto.wsman.SetTimeoutResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SetTimeoutResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -4003,11 +4472,11 @@ proto.wsman.ControlAdmissionResponse.prototype.toObject = function(opt_includeIn
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlAdmissionResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.SetTimeoutResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlAdmissionResponse.toObject = function(includeInstance, msg) {
+proto.wsman.SetTimeoutResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
 
   };
@@ -4023,23 +4492,23 @@ proto.wsman.ControlAdmissionResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlAdmissionResponse}
+ * @return {!proto.wsman.SetTimeoutResponse}
  */
-proto.wsman.ControlAdmissionResponse.deserializeBinary = function(bytes) {
+proto.wsman.SetTimeoutResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlAdmissionResponse;
-  return proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.SetTimeoutResponse;
+  return proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlAdmissionResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.SetTimeoutResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlAdmissionResponse}
+ * @return {!proto.wsman.SetTimeoutResponse}
  */
-proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4059,9 +4528,9 @@ proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader = function(msg,
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.ControlAdmissionResponse.prototype.serializeBinary = function() {
+proto.wsman.SetTimeoutResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.SetTimeoutResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4069,11 +4538,11 @@ proto.wsman.ControlAdmissionResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlAdmissionResponse} message
+ * @param {!proto.wsman.SetTimeoutResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.SetTimeoutResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
 };
 
@@ -4094,8 +4563,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.BackupWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.BackupWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsman.ControlPortRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlPortRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -4104,13 +4573,15 @@ proto.wsman.BackupWorkspaceRequest.prototype.toObject = function(opt_includeInst
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.BackupWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.ControlPortRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.BackupWorkspaceRequest.toObject = function(includeInstance, msg) {
+proto.wsman.ControlPortRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    expose: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
+    spec: (f = msg.getSpec()) && proto.wsman.PortSpec.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -4124,23 +4595,23 @@ proto.wsman.BackupWorkspaceRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.BackupWorkspaceRequest}
+ * @return {!proto.wsman.ControlPortRequest}
  */
-proto.wsman.BackupWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsman.ControlPortRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.BackupWorkspaceRequest;
-  return proto.wsman.BackupWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.ControlPortRequest;
+  return proto.wsman.ControlPortRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.BackupWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.ControlPortRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.BackupWorkspaceRequest}
+ * @return {!proto.wsman.ControlPortRequest}
  */
-proto.wsman.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4151,6 +4622,15 @@ proto.wsman.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg, r
       var value = /** @type {string} */ (reader.readString());
       msg.setId(value);
       break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setExpose(value);
+      break;
+    case 3:
+      var value = new proto.wsman.PortSpec;
+      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -4164,9 +4644,9 @@ proto.wsman.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg, r
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.BackupWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsman.ControlPortRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.BackupWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.ControlPortRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4174,11 +4654,11 @@ proto.wsman.BackupWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.BackupWorkspaceRequest} message
+ * @param {!proto.wsman.ControlPortRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.BackupWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.ControlPortRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -4187,6 +4667,21 @@ proto.wsman.BackupWorkspaceRequest.serializeBinaryToWriter = function(message, w
       f
     );
   }
+  f = message.getExpose();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.PortSpec.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -4194,53 +4689,108 @@ proto.wsman.BackupWorkspaceRequest.serializeBinaryToWriter = function(message, w
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.BackupWorkspaceRequest.prototype.getId = function() {
+proto.wsman.ControlPortRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.BackupWorkspaceRequest} returns this
+ * @return {!proto.wsman.ControlPortRequest} returns this
  */
-proto.wsman.BackupWorkspaceRequest.prototype.setId = function(value) {
+proto.wsman.ControlPortRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
+/**
+ * optional bool expose = 2;
+ * @return {boolean}
+ */
+proto.wsman.ControlPortRequest.prototype.getExpose = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+};
 
 
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * @param {boolean} value
+ * @return {!proto.wsman.ControlPortRequest} returns this
  */
-proto.wsman.BackupWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.BackupWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsman.ControlPortRequest.prototype.setExpose = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.wsman.BackupWorkspaceResponse} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * optional PortSpec spec = 3;
+ * @return {?proto.wsman.PortSpec}
  */
-proto.wsman.BackupWorkspaceResponse.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+proto.wsman.ControlPortRequest.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.PortSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.PortSpec, 3));
+};
+
+
+/**
+ * @param {?proto.wsman.PortSpec|undefined} value
+ * @return {!proto.wsman.ControlPortRequest} returns this
+*/
+proto.wsman.ControlPortRequest.prototype.setSpec = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.ControlPortRequest} returns this
+ */
+proto.wsman.ControlPortRequest.prototype.clearSpec = function() {
+  return this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ControlPortRequest.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ControlPortResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlPortResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlPortResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlPortResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
   };
 
   if (includeInstance) {
@@ -4254,33 +4804,29 @@ proto.wsman.BackupWorkspaceResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.BackupWorkspaceResponse}
+ * @return {!proto.wsman.ControlPortResponse}
  */
-proto.wsman.BackupWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsman.ControlPortResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.BackupWorkspaceResponse;
-  return proto.wsman.BackupWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.ControlPortResponse;
+  return proto.wsman.ControlPortResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.BackupWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.ControlPortResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.BackupWorkspaceResponse}
+ * @return {!proto.wsman.ControlPortResponse}
  */
-proto.wsman.BackupWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.ControlPortResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -4294,9 +4840,9 @@ proto.wsman.BackupWorkspaceResponse.deserializeBinaryFromReader = function(msg,
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.BackupWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsman.ControlPortResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.BackupWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.ControlPortResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4304,37 +4850,12 @@ proto.wsman.BackupWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.BackupWorkspaceResponse} message
+ * @param {!proto.wsman.ControlPortResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.BackupWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.ControlPortResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getUrl();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-};
-
-
-/**
- * optional string url = 1;
- * @return {string}
- */
-proto.wsman.BackupWorkspaceResponse.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.wsman.BackupWorkspaceResponse} returns this
- */
-proto.wsman.BackupWorkspaceResponse.prototype.setUrl = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
@@ -4354,8 +4875,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceStatus.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceStatus.toObject(opt_includeInstance, this);
+proto.wsman.TakeSnapshotRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.TakeSnapshotRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -4364,22 +4885,14 @@ proto.wsman.WorkspaceStatus.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceStatus} msg The msg instance to transform.
+ * @param {!proto.wsman.TakeSnapshotRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.toObject = function(includeInstance, msg) {
+proto.wsman.TakeSnapshotRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    statusVersion: jspb.Message.getFieldWithDefault(msg, 10, 0),
-    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
-    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceSpec.toObject(includeInstance, f),
-    phase: jspb.Message.getFieldWithDefault(msg, 4, 0),
-    conditions: (f = msg.getConditions()) && proto.wsman.WorkspaceConditions.toObject(includeInstance, f),
-    message: jspb.Message.getFieldWithDefault(msg, 6, ""),
-    repo: (f = msg.getRepo()) && content$service$api_initializer_pb.GitStatus.toObject(includeInstance, f),
-    runtime: (f = msg.getRuntime()) && proto.wsman.WorkspaceRuntimeInfo.toObject(includeInstance, f),
-    auth: (f = msg.getAuth()) && proto.wsman.WorkspaceAuthentication.toObject(includeInstance, f)
+    returnImmediately: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
   };
 
   if (includeInstance) {
@@ -4393,23 +4906,23 @@ proto.wsman.WorkspaceStatus.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceStatus}
+ * @return {!proto.wsman.TakeSnapshotRequest}
  */
-proto.wsman.WorkspaceStatus.deserializeBinary = function(bytes) {
+proto.wsman.TakeSnapshotRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceStatus;
-  return proto.wsman.WorkspaceStatus.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.TakeSnapshotRequest;
+  return proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceStatus} msg The message object to deserialize into.
+ * @param {!proto.wsman.TakeSnapshotRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceStatus}
+ * @return {!proto.wsman.TakeSnapshotRequest}
  */
-proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4420,47 +4933,9 @@ proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader)
       var value = /** @type {string} */ (reader.readString());
       msg.setId(value);
       break;
-    case 10:
-      var value = /** @type {number} */ (reader.readUint64());
-      msg.setStatusVersion(value);
-      break;
     case 2:
-      var value = new proto.wsman.WorkspaceMetadata;
-      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
-      msg.setMetadata(value);
-      break;
-    case 3:
-      var value = new proto.wsman.WorkspaceSpec;
-      reader.readMessage(value,proto.wsman.WorkspaceSpec.deserializeBinaryFromReader);
-      msg.setSpec(value);
-      break;
-    case 4:
-      var value = /** @type {!proto.wsman.WorkspacePhase} */ (reader.readEnum());
-      msg.setPhase(value);
-      break;
-    case 5:
-      var value = new proto.wsman.WorkspaceConditions;
-      reader.readMessage(value,proto.wsman.WorkspaceConditions.deserializeBinaryFromReader);
-      msg.setConditions(value);
-      break;
-    case 6:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setMessage(value);
-      break;
-    case 7:
-      var value = new content$service$api_initializer_pb.GitStatus;
-      reader.readMessage(value,content$service$api_initializer_pb.GitStatus.deserializeBinaryFromReader);
-      msg.setRepo(value);
-      break;
-    case 8:
-      var value = new proto.wsman.WorkspaceRuntimeInfo;
-      reader.readMessage(value,proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader);
-      msg.setRuntime(value);
-      break;
-    case 9:
-      var value = new proto.wsman.WorkspaceAuthentication;
-      reader.readMessage(value,proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader);
-      msg.setAuth(value);
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setReturnImmediately(value);
       break;
     default:
       reader.skipField();
@@ -4475,9 +4950,9 @@ proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader)
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceStatus.prototype.serializeBinary = function() {
+proto.wsman.TakeSnapshotRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceStatus.serializeBinaryToWriter(this, writer);
+  proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4485,11 +4960,11 @@ proto.wsman.WorkspaceStatus.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceStatus} message
+ * @param {!proto.wsman.TakeSnapshotRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -4498,75 +4973,13 @@ proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer)
       f
     );
   }
-  f = message.getStatusVersion();
-  if (f !== 0) {
-    writer.writeUint64(
-      10,
-      f
-    );
-  }
-  f = message.getMetadata();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getReturnImmediately();
+  if (f) {
+    writer.writeBool(
       2,
-      f,
-      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
-    );
-  }
-  f = message.getSpec();
-  if (f != null) {
-    writer.writeMessage(
-      3,
-      f,
-      proto.wsman.WorkspaceSpec.serializeBinaryToWriter
-    );
-  }
-  f = message.getPhase();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      4,
-      f
-    );
-  }
-  f = message.getConditions();
-  if (f != null) {
-    writer.writeMessage(
-      5,
-      f,
-      proto.wsman.WorkspaceConditions.serializeBinaryToWriter
-    );
-  }
-  f = message.getMessage();
-  if (f.length > 0) {
-    writer.writeString(
-      6,
       f
     );
   }
-  f = message.getRepo();
-  if (f != null) {
-    writer.writeMessage(
-      7,
-      f,
-      content$service$api_initializer_pb.GitStatus.serializeBinaryToWriter
-    );
-  }
-  f = message.getRuntime();
-  if (f != null) {
-    writer.writeMessage(
-      8,
-      f,
-      proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter
-    );
-  }
-  f = message.getAuth();
-  if (f != null) {
-    writer.writeMessage(
-      9,
-      f,
-      proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter
-    );
-  }
 };
 
 
@@ -4574,296 +4987,428 @@ proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer)
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.WorkspaceStatus.prototype.getId = function() {
+proto.wsman.TakeSnapshotRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * @return {!proto.wsman.TakeSnapshotRequest} returns this
  */
-proto.wsman.WorkspaceStatus.prototype.setId = function(value) {
+proto.wsman.TakeSnapshotRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional uint64 status_version = 10;
- * @return {number}
+ * optional bool return_immediately = 2;
+ * @return {boolean}
  */
-proto.wsman.WorkspaceStatus.prototype.getStatusVersion = function() {
-  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 10, 0));
+proto.wsman.TakeSnapshotRequest.prototype.getReturnImmediately = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
 };
 
 
 /**
- * @param {number} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * @param {boolean} value
+ * @return {!proto.wsman.TakeSnapshotRequest} returns this
  */
-proto.wsman.WorkspaceStatus.prototype.setStatusVersion = function(value) {
-  return jspb.Message.setProto3IntField(this, 10, value);
+proto.wsman.TakeSnapshotRequest.prototype.setReturnImmediately = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
 };
 
 
-/**
- * optional WorkspaceMetadata metadata = 2;
- * @return {?proto.wsman.WorkspaceMetadata}
- */
-proto.wsman.WorkspaceStatus.prototype.getMetadata = function() {
-  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 2));
-};
-
 
-/**
- * @param {?proto.wsman.WorkspaceMetadata|undefined} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
-*/
-proto.wsman.WorkspaceStatus.prototype.setMetadata = function(value) {
-  return jspb.Message.setWrapperField(this, 2, value);
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.WorkspaceStatus.prototype.clearMetadata = function() {
-  return this.setMetadata(undefined);
+proto.wsman.TakeSnapshotResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.TakeSnapshotResponse.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.TakeSnapshotResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.prototype.hasMetadata = function() {
-  return jspb.Message.getField(this, 2) != null;
-};
-
+proto.wsman.TakeSnapshotResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
 
-/**
- * optional WorkspaceSpec spec = 3;
- * @return {?proto.wsman.WorkspaceSpec}
- */
-proto.wsman.WorkspaceStatus.prototype.getSpec = function() {
-  return /** @type{?proto.wsman.WorkspaceSpec} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceSpec, 3));
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * @param {?proto.wsman.WorkspaceSpec|undefined} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
-*/
-proto.wsman.WorkspaceStatus.prototype.setSpec = function(value) {
-  return jspb.Message.setWrapperField(this, 3, value);
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.TakeSnapshotResponse}
+ */
+proto.wsman.TakeSnapshotResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.TakeSnapshotResponse;
+  return proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.TakeSnapshotResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.TakeSnapshotResponse}
  */
-proto.wsman.WorkspaceStatus.prototype.clearSpec = function() {
-  return this.setSpec(undefined);
+proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceStatus.prototype.hasSpec = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.wsman.TakeSnapshotResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * optional WorkspacePhase phase = 4;
- * @return {!proto.wsman.WorkspacePhase}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.TakeSnapshotResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.prototype.getPhase = function() {
-  return /** @type {!proto.wsman.WorkspacePhase} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
 };
 
 
 /**
- * @param {!proto.wsman.WorkspacePhase} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * optional string url = 1;
+ * @return {string}
  */
-proto.wsman.WorkspaceStatus.prototype.setPhase = function(value) {
-  return jspb.Message.setProto3EnumField(this, 4, value);
+proto.wsman.TakeSnapshotResponse.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * optional WorkspaceConditions conditions = 5;
- * @return {?proto.wsman.WorkspaceConditions}
+ * @param {string} value
+ * @return {!proto.wsman.TakeSnapshotResponse} returns this
  */
-proto.wsman.WorkspaceStatus.prototype.getConditions = function() {
-  return /** @type{?proto.wsman.WorkspaceConditions} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceConditions, 5));
+proto.wsman.TakeSnapshotResponse.prototype.setUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
-/**
- * @param {?proto.wsman.WorkspaceConditions|undefined} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
-*/
-proto.wsman.WorkspaceStatus.prototype.setConditions = function(value) {
-  return jspb.Message.setWrapperField(this, 5, value);
-};
-
 
-/**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceStatus} returns this
- */
-proto.wsman.WorkspaceStatus.prototype.clearConditions = function() {
-  return this.setConditions(undefined);
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.WorkspaceStatus.prototype.hasConditions = function() {
-  return jspb.Message.getField(this, 5) != null;
+proto.wsman.ControlAdmissionRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlAdmissionRequest.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * optional string message = 6;
- * @return {string}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlAdmissionRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.prototype.getMessage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
-};
-
+proto.wsman.ControlAdmissionRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    level: jspb.Message.getFieldWithDefault(msg, 2, 0)
+  };
 
-/**
- * @param {string} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
- */
-proto.wsman.WorkspaceStatus.prototype.setMessage = function(value) {
-  return jspb.Message.setProto3StringField(this, 6, value);
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * optional contentservice.GitStatus repo = 7;
- * @return {?proto.contentservice.GitStatus}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ControlAdmissionRequest}
  */
-proto.wsman.WorkspaceStatus.prototype.getRepo = function() {
-  return /** @type{?proto.contentservice.GitStatus} */ (
-    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.GitStatus, 7));
+proto.wsman.ControlAdmissionRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ControlAdmissionRequest;
+  return proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * @param {?proto.contentservice.GitStatus|undefined} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
-*/
-proto.wsman.WorkspaceStatus.prototype.setRepo = function(value) {
-  return jspb.Message.setWrapperField(this, 7, value);
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ControlAdmissionRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ControlAdmissionRequest}
+ */
+proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
+      msg.setLevel(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceStatus.prototype.clearRepo = function() {
-  return this.setRepo(undefined);
+proto.wsman.ControlAdmissionRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ControlAdmissionRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.prototype.hasRepo = function() {
-  return jspb.Message.getField(this, 7) != null;
+proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getLevel();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
 };
 
 
 /**
- * optional WorkspaceRuntimeInfo runtime = 8;
- * @return {?proto.wsman.WorkspaceRuntimeInfo}
+ * optional string id = 1;
+ * @return {string}
  */
-proto.wsman.WorkspaceStatus.prototype.getRuntime = function() {
-  return /** @type{?proto.wsman.WorkspaceRuntimeInfo} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceRuntimeInfo, 8));
+proto.wsman.ControlAdmissionRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * @param {?proto.wsman.WorkspaceRuntimeInfo|undefined} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
-*/
-proto.wsman.WorkspaceStatus.prototype.setRuntime = function(value) {
-  return jspb.Message.setWrapperField(this, 8, value);
+ * @param {string} value
+ * @return {!proto.wsman.ControlAdmissionRequest} returns this
+ */
+proto.wsman.ControlAdmissionRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * optional AdmissionLevel level = 2;
+ * @return {!proto.wsman.AdmissionLevel}
  */
-proto.wsman.WorkspaceStatus.prototype.clearRuntime = function() {
-  return this.setRuntime(undefined);
+proto.wsman.ControlAdmissionRequest.prototype.getLevel = function() {
+  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {!proto.wsman.AdmissionLevel} value
+ * @return {!proto.wsman.ControlAdmissionRequest} returns this
  */
-proto.wsman.WorkspaceStatus.prototype.hasRuntime = function() {
-  return jspb.Message.getField(this, 8) != null;
+proto.wsman.ControlAdmissionRequest.prototype.setLevel = function(value) {
+  return jspb.Message.setProto3EnumField(this, 2, value);
 };
 
 
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * optional WorkspaceAuthentication auth = 9;
- * @return {?proto.wsman.WorkspaceAuthentication}
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.WorkspaceStatus.prototype.getAuth = function() {
-  return /** @type{?proto.wsman.WorkspaceAuthentication} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceAuthentication, 9));
+proto.wsman.ControlAdmissionResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlAdmissionResponse.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * @param {?proto.wsman.WorkspaceAuthentication|undefined} value
- * @return {!proto.wsman.WorkspaceStatus} returns this
-*/
-proto.wsman.WorkspaceStatus.prototype.setAuth = function(value) {
-  return jspb.Message.setWrapperField(this, 9, value);
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlAdmissionResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlAdmissionResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceStatus} returns this
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ControlAdmissionResponse}
  */
-proto.wsman.WorkspaceStatus.prototype.clearAuth = function() {
-  return this.setAuth(undefined);
+proto.wsman.ControlAdmissionResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ControlAdmissionResponse;
+  return proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ControlAdmissionResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ControlAdmissionResponse}
  */
-proto.wsman.WorkspaceStatus.prototype.hasAuth = function() {
-  return jspb.Message.getField(this, 9) != null;
+proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ControlAdmissionResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ControlAdmissionResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
 
 
 
@@ -4880,8 +5425,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.IDEImage.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.IDEImage.toObject(opt_includeInstance, this);
+proto.wsman.DrainNodeRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DrainNodeRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -4890,15 +5435,14 @@ proto.wsman.IDEImage.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.IDEImage} msg The msg instance to transform.
+ * @param {!proto.wsman.DrainNodeRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.IDEImage.toObject = function(includeInstance, msg) {
+proto.wsman.DrainNodeRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    webRef: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    desktopRef: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    supervisorRef: jspb.Message.getFieldWithDefault(msg, 3, "")
+    node: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    stopHeadless: jspb.Message.getBooleanFieldWithDefault(msg, 2, false)
   };
 
   if (includeInstance) {
@@ -4912,23 +5456,23 @@ proto.wsman.IDEImage.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.IDEImage}
+ * @return {!proto.wsman.DrainNodeRequest}
  */
-proto.wsman.IDEImage.deserializeBinary = function(bytes) {
+proto.wsman.DrainNodeRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.IDEImage;
-  return proto.wsman.IDEImage.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DrainNodeRequest;
+  return proto.wsman.DrainNodeRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.IDEImage} msg The message object to deserialize into.
+ * @param {!proto.wsman.DrainNodeRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.IDEImage}
+ * @return {!proto.wsman.DrainNodeRequest}
  */
-proto.wsman.IDEImage.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DrainNodeRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4937,15 +5481,11 @@ proto.wsman.IDEImage.deserializeBinaryFromReader = function(msg, reader) {
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setWebRef(value);
+      msg.setNode(value);
       break;
     case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setDesktopRef(value);
-      break;
-    case 3:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setSupervisorRef(value);
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setStopHeadless(value);
       break;
     default:
       reader.skipField();
@@ -4960,9 +5500,9 @@ proto.wsman.IDEImage.deserializeBinaryFromReader = function(msg, reader) {
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.IDEImage.prototype.serializeBinary = function() {
+proto.wsman.DrainNodeRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.IDEImage.serializeBinaryToWriter(this, writer);
+  proto.wsman.DrainNodeRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4970,101 +5510,68 @@ proto.wsman.IDEImage.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.IDEImage} message
+ * @param {!proto.wsman.DrainNodeRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.IDEImage.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DrainNodeRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getWebRef();
+  f = message.getNode();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getDesktopRef();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getStopHeadless();
+  if (f) {
+    writer.writeBool(
       2,
       f
     );
   }
-  f = message.getSupervisorRef();
-  if (f.length > 0) {
-    writer.writeString(
-      3,
-      f
-    );
-  }
 };
 
 
 /**
- * optional string web_ref = 1;
+ * optional string node = 1;
  * @return {string}
  */
-proto.wsman.IDEImage.prototype.getWebRef = function() {
+proto.wsman.DrainNodeRequest.prototype.getNode = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.IDEImage} returns this
+ * @return {!proto.wsman.DrainNodeRequest} returns this
  */
-proto.wsman.IDEImage.prototype.setWebRef = function(value) {
+proto.wsman.DrainNodeRequest.prototype.setNode = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string desktop_ref = 2;
- * @return {string}
+ * optional bool stop_headless = 2;
+ * @return {boolean}
  */
-proto.wsman.IDEImage.prototype.getDesktopRef = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.DrainNodeRequest.prototype.getStopHeadless = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.IDEImage} returns this
+ * @param {boolean} value
+ * @return {!proto.wsman.DrainNodeRequest} returns this
  */
-proto.wsman.IDEImage.prototype.setDesktopRef = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.wsman.DrainNodeRequest.prototype.setStopHeadless = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
 };
 
 
-/**
- * optional string supervisor_ref = 3;
- * @return {string}
- */
-proto.wsman.IDEImage.prototype.getSupervisorRef = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.wsman.IDEImage} returns this
- */
-proto.wsman.IDEImage.prototype.setSupervisorRef = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
-};
-
-
-
-/**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
- */
-proto.wsman.WorkspaceSpec.repeatedFields_ = [5];
-
-
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
  * Field names that are reserved in JavaScript and will be renamed to pb_name.
@@ -5077,8 +5584,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceSpec.toObject(opt_includeInstance, this);
+proto.wsman.DrainNodeResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DrainNodeResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -5087,21 +5594,15 @@ proto.wsman.WorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceSpec} msg The msg instance to transform.
+ * @param {!proto.wsman.DrainNodeResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceSpec.toObject = function(includeInstance, msg) {
+proto.wsman.DrainNodeResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    deprecatedIdeImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    headless: jspb.Message.getBooleanFieldWithDefault(msg, 3, false),
-    url: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    exposedPortsList: jspb.Message.toObjectList(msg.getExposedPortsList(),
-    proto.wsman.PortSpec.toObject, includeInstance),
-    type: jspb.Message.getFieldWithDefault(msg, 6, 0),
-    timeout: jspb.Message.getFieldWithDefault(msg, 7, ""),
-    ideImage: (f = msg.getIdeImage()) && proto.wsman.IDEImage.toObject(includeInstance, f)
+    step: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    id: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    message: jspb.Message.getFieldWithDefault(msg, 3, "")
   };
 
   if (includeInstance) {
@@ -5115,23 +5616,23 @@ proto.wsman.WorkspaceSpec.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceSpec}
+ * @return {!proto.wsman.DrainNodeResponse}
  */
-proto.wsman.WorkspaceSpec.deserializeBinary = function(bytes) {
+proto.wsman.DrainNodeResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceSpec;
-  return proto.wsman.WorkspaceSpec.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DrainNodeResponse;
+  return proto.wsman.DrainNodeResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceSpec} msg The message object to deserialize into.
+ * @param {!proto.wsman.DrainNodeResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceSpec}
+ * @return {!proto.wsman.DrainNodeResponse}
  */
-proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DrainNodeResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -5139,38 +5640,16 @@ proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setWorkspaceImage(value);
+      var value = /** @type {!proto.wsman.DrainNodeStep} */ (reader.readEnum());
+      msg.setStep(value);
       break;
     case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setDeprecatedIdeImage(value);
+      msg.setId(value);
       break;
     case 3:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setHeadless(value);
-      break;
-    case 4:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
-      break;
-    case 5:
-      var value = new proto.wsman.PortSpec;
-      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
-      msg.addExposedPorts(value);
-      break;
-    case 6:
-      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
-      msg.setType(value);
-      break;
-    case 7:
       var value = /** @type {string} */ (reader.readString());
-      msg.setTimeout(value);
-      break;
-    case 8:
-      var value = new proto.wsman.IDEImage;
-      reader.readMessage(value,proto.wsman.IDEImage.deserializeBinaryFromReader);
-      msg.setIdeImage(value);
+      msg.setMessage(value);
       break;
     default:
       reader.skipField();
@@ -5185,9 +5664,9 @@ proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceSpec.prototype.serializeBinary = function() {
+proto.wsman.DrainNodeResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceSpec.serializeBinaryToWriter(this, writer);
+  proto.wsman.DrainNodeResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -5195,259 +5674,281 @@ proto.wsman.WorkspaceSpec.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceSpec} message
+ * @param {!proto.wsman.DrainNodeResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DrainNodeResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getWorkspaceImage();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getStep();
+  if (f !== 0.0) {
+    writer.writeEnum(
       1,
       f
     );
   }
-  f = message.getDeprecatedIdeImage();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
       2,
       f
     );
   }
-  f = message.getHeadless();
-  if (f) {
-    writer.writeBool(
-      3,
-      f
-    );
-  }
-  f = message.getUrl();
-  if (f.length > 0) {
-    writer.writeString(
-      4,
-      f
-    );
-  }
-  f = message.getExposedPortsList();
-  if (f.length > 0) {
-    writer.writeRepeatedMessage(
-      5,
-      f,
-      proto.wsman.PortSpec.serializeBinaryToWriter
-    );
-  }
-  f = message.getType();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      6,
-      f
-    );
-  }
-  f = message.getTimeout();
+  f = message.getMessage();
   if (f.length > 0) {
     writer.writeString(
-      7,
+      3,
       f
     );
   }
-  f = message.getIdeImage();
-  if (f != null) {
-    writer.writeMessage(
-      8,
-      f,
-      proto.wsman.IDEImage.serializeBinaryToWriter
-    );
-  }
 };
 
 
 /**
- * optional string workspace_image = 1;
- * @return {string}
+ * optional DrainNodeStep step = 1;
+ * @return {!proto.wsman.DrainNodeStep}
  */
-proto.wsman.WorkspaceSpec.prototype.getWorkspaceImage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.DrainNodeResponse.prototype.getStep = function() {
+  return /** @type {!proto.wsman.DrainNodeStep} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * @param {!proto.wsman.DrainNodeStep} value
+ * @return {!proto.wsman.DrainNodeResponse} returns this
  */
-proto.wsman.WorkspaceSpec.prototype.setWorkspaceImage = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.wsman.DrainNodeResponse.prototype.setStep = function(value) {
+  return jspb.Message.setProto3EnumField(this, 1, value);
 };
 
 
 /**
- * optional string deprecated_ide_image = 2;
+ * optional string id = 2;
  * @return {string}
  */
-proto.wsman.WorkspaceSpec.prototype.getDeprecatedIdeImage = function() {
+proto.wsman.DrainNodeResponse.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * @return {!proto.wsman.DrainNodeResponse} returns this
  */
-proto.wsman.WorkspaceSpec.prototype.setDeprecatedIdeImage = function(value) {
+proto.wsman.DrainNodeResponse.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional bool headless = 3;
- * @return {boolean}
+ * optional string message = 3;
+ * @return {string}
  */
-proto.wsman.WorkspaceSpec.prototype.getHeadless = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 3, false));
+proto.wsman.DrainNodeResponse.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * @param {string} value
+ * @return {!proto.wsman.DrainNodeResponse} returns this
  */
-proto.wsman.WorkspaceSpec.prototype.setHeadless = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 3, value);
+proto.wsman.DrainNodeResponse.prototype.setMessage = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
-/**
- * optional string url = 4;
- * @return {string}
- */
-proto.wsman.WorkspaceSpec.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {string} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.WorkspaceSpec.prototype.setUrl = function(value) {
-  return jspb.Message.setProto3StringField(this, 4, value);
+proto.wsman.GetHeadlessLogRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GetHeadlessLogRequest.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * repeated PortSpec exposed_ports = 5;
- * @return {!Array<!proto.wsman.PortSpec>}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GetHeadlessLogRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceSpec.prototype.getExposedPortsList = function() {
-  return /** @type{!Array<!proto.wsman.PortSpec>} */ (
-    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PortSpec, 5));
-};
-
+proto.wsman.GetHeadlessLogRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    taskId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    resumeToken: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
 
-/**
- * @param {!Array<!proto.wsman.PortSpec>} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
-*/
-proto.wsman.WorkspaceSpec.prototype.setExposedPortsList = function(value) {
-  return jspb.Message.setRepeatedWrapperField(this, 5, value);
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * @param {!proto.wsman.PortSpec=} opt_value
- * @param {number=} opt_index
- * @return {!proto.wsman.PortSpec}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GetHeadlessLogRequest}
  */
-proto.wsman.WorkspaceSpec.prototype.addExposedPorts = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.PortSpec, opt_index);
+proto.wsman.GetHeadlessLogRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GetHeadlessLogRequest;
+  return proto.wsman.GetHeadlessLogRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GetHeadlessLogRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GetHeadlessLogRequest}
  */
-proto.wsman.WorkspaceSpec.prototype.clearExposedPortsList = function() {
-  return this.setExposedPortsList([]);
+proto.wsman.GetHeadlessLogRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTaskId(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setResumeToken(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * optional WorkspaceType type = 6;
- * @return {!proto.wsman.WorkspaceType}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceSpec.prototype.getType = function() {
-  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+proto.wsman.GetHeadlessLogRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GetHeadlessLogRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * @param {!proto.wsman.WorkspaceType} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GetHeadlessLogRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceSpec.prototype.setType = function(value) {
-  return jspb.Message.setProto3EnumField(this, 6, value);
-};
-
+proto.wsman.GetHeadlessLogRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getTaskId();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getResumeToken();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
 
 /**
- * optional string timeout = 7;
+ * optional string id = 1;
  * @return {string}
  */
-proto.wsman.WorkspaceSpec.prototype.getTimeout = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+proto.wsman.GetHeadlessLogRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * @return {!proto.wsman.GetHeadlessLogRequest} returns this
  */
-proto.wsman.WorkspaceSpec.prototype.setTimeout = function(value) {
-  return jspb.Message.setProto3StringField(this, 7, value);
+proto.wsman.GetHeadlessLogRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional IDEImage ide_image = 8;
- * @return {?proto.wsman.IDEImage}
+ * optional string task_id = 2;
+ * @return {string}
  */
-proto.wsman.WorkspaceSpec.prototype.getIdeImage = function() {
-  return /** @type{?proto.wsman.IDEImage} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.IDEImage, 8));
+proto.wsman.GetHeadlessLogRequest.prototype.getTaskId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * @param {?proto.wsman.IDEImage|undefined} value
- * @return {!proto.wsman.WorkspaceSpec} returns this
-*/
-proto.wsman.WorkspaceSpec.prototype.setIdeImage = function(value) {
-  return jspb.Message.setWrapperField(this, 8, value);
+ * @param {string} value
+ * @return {!proto.wsman.GetHeadlessLogRequest} returns this
+ */
+proto.wsman.GetHeadlessLogRequest.prototype.setTaskId = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceSpec} returns this
+ * optional string resume_token = 3;
+ * @return {string}
  */
-proto.wsman.WorkspaceSpec.prototype.clearIdeImage = function() {
-  return this.setIdeImage(undefined);
+proto.wsman.GetHeadlessLogRequest.prototype.getResumeToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.wsman.GetHeadlessLogRequest} returns this
  */
-proto.wsman.WorkspaceSpec.prototype.hasIdeImage = function() {
-  return jspb.Message.getField(this, 8) != null;
+proto.wsman.GetHeadlessLogRequest.prototype.setResumeToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -5461,8 +5962,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.PortSpec.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.PortSpec.toObject(opt_includeInstance, this);
+proto.wsman.GetHeadlessLogResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GetHeadlessLogResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -5471,15 +5972,14 @@ proto.wsman.PortSpec.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.PortSpec} msg The msg instance to transform.
+ * @param {!proto.wsman.GetHeadlessLogResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.PortSpec.toObject = function(includeInstance, msg) {
+proto.wsman.GetHeadlessLogResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    port: jspb.Message.getFieldWithDefault(msg, 1, 0),
-    visibility: jspb.Message.getFieldWithDefault(msg, 3, 0),
-    url: jspb.Message.getFieldWithDefault(msg, 4, "")
+    data: msg.getData_asB64(),
+    resumeToken: jspb.Message.getFieldWithDefault(msg, 2, "")
   };
 
   if (includeInstance) {
@@ -5493,23 +5993,23 @@ proto.wsman.PortSpec.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.PortSpec}
+ * @return {!proto.wsman.GetHeadlessLogResponse}
  */
-proto.wsman.PortSpec.deserializeBinary = function(bytes) {
+proto.wsman.GetHeadlessLogResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.PortSpec;
-  return proto.wsman.PortSpec.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.GetHeadlessLogResponse;
+  return proto.wsman.GetHeadlessLogResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.PortSpec} msg The message object to deserialize into.
+ * @param {!proto.wsman.GetHeadlessLogResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.PortSpec}
+ * @return {!proto.wsman.GetHeadlessLogResponse}
  */
-proto.wsman.PortSpec.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.GetHeadlessLogResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -5517,16 +6017,12 @@ proto.wsman.PortSpec.deserializeBinaryFromReader = function(msg, reader) {
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {number} */ (reader.readUint32());
-      msg.setPort(value);
-      break;
-    case 3:
-      var value = /** @type {!proto.wsman.PortVisibility} */ (reader.readEnum());
-      msg.setVisibility(value);
+      var value = /** @type {!Uint8Array} */ (reader.readBytes());
+      msg.setData(value);
       break;
-    case 4:
+    case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
+      msg.setResumeToken(value);
       break;
     default:
       reader.skipField();
@@ -5541,9 +6037,9 @@ proto.wsman.PortSpec.deserializeBinaryFromReader = function(msg, reader) {
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.PortSpec.prototype.serializeBinary = function() {
+proto.wsman.GetHeadlessLogResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.PortSpec.serializeBinaryToWriter(this, writer);
+  proto.wsman.GetHeadlessLogResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -5551,30 +6047,23 @@ proto.wsman.PortSpec.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.PortSpec} message
+ * @param {!proto.wsman.GetHeadlessLogResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.PortSpec.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.GetHeadlessLogResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getPort();
-  if (f !== 0) {
-    writer.writeUint32(
+  f = message.getData_asU8();
+  if (f.length > 0) {
+    writer.writeBytes(
       1,
       f
     );
   }
-  f = message.getVisibility();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      3,
-      f
-    );
-  }
-  f = message.getUrl();
+  f = message.getResumeToken();
   if (f.length > 0) {
     writer.writeString(
-      4,
+      2,
       f
     );
   }
@@ -5582,62 +6071,67 @@ proto.wsman.PortSpec.serializeBinaryToWriter = function(message, writer) {
 
 
 /**
- * optional uint32 port = 1;
- * @return {number}
+ * optional bytes data = 1;
+ * @return {!(string|Uint8Array)}
  */
-proto.wsman.PortSpec.prototype.getPort = function() {
-  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+proto.wsman.GetHeadlessLogResponse.prototype.getData = function() {
+  return /** @type {!(string|Uint8Array)} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * @param {number} value
- * @return {!proto.wsman.PortSpec} returns this
+ * optional bytes data = 1;
+ * This is a type-conversion wrapper around `getData()`
+ * @return {string}
  */
-proto.wsman.PortSpec.prototype.setPort = function(value) {
-  return jspb.Message.setProto3IntField(this, 1, value);
+proto.wsman.GetHeadlessLogResponse.prototype.getData_asB64 = function() {
+  return /** @type {string} */ (jspb.Message.bytesAsB64(
+      this.getData()));
 };
 
 
 /**
- * optional PortVisibility visibility = 3;
- * @return {!proto.wsman.PortVisibility}
+ * optional bytes data = 1;
+ * Note that Uint8Array is not supported on all browsers.
+ * @see http://caniuse.com/Uint8Array
+ * This is a type-conversion wrapper around `getData()`
+ * @return {!Uint8Array}
  */
-proto.wsman.PortSpec.prototype.getVisibility = function() {
-  return /** @type {!proto.wsman.PortVisibility} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+proto.wsman.GetHeadlessLogResponse.prototype.getData_asU8 = function() {
+  return /** @type {!Uint8Array} */ (jspb.Message.bytesAsU8(
+      this.getData()));
 };
 
 
 /**
- * @param {!proto.wsman.PortVisibility} value
- * @return {!proto.wsman.PortSpec} returns this
+ * @param {!(string|Uint8Array)} value
+ * @return {!proto.wsman.GetHeadlessLogResponse} returns this
  */
-proto.wsman.PortSpec.prototype.setVisibility = function(value) {
-  return jspb.Message.setProto3EnumField(this, 3, value);
+proto.wsman.GetHeadlessLogResponse.prototype.setData = function(value) {
+  return jspb.Message.setProto3BytesField(this, 1, value);
 };
 
 
 /**
- * optional string url = 4;
+ * optional string resume_token = 2;
  * @return {string}
  */
-proto.wsman.PortSpec.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+proto.wsman.GetHeadlessLogResponse.prototype.getResumeToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.PortSpec} returns this
+ * @return {!proto.wsman.GetHeadlessLogResponse} returns this
  */
-proto.wsman.PortSpec.prototype.setUrl = function(value) {
-  return jspb.Message.setProto3StringField(this, 4, value);
+proto.wsman.GetHeadlessLogResponse.prototype.setResumeToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -5651,8 +6145,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceConditions.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceConditions.toObject(opt_includeInstance, this);
+proto.wsman.DescribeClusterRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeClusterRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -5661,22 +6155,13 @@ proto.wsman.WorkspaceConditions.prototype.toObject = function(opt_includeInstanc
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceConditions} msg The msg instance to transform.
+ * @param {!proto.wsman.DescribeClusterRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceConditions.toObject = function(includeInstance, msg) {
+proto.wsman.DescribeClusterRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    failed: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    timeout: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    pullingImages: jspb.Message.getFieldWithDefault(msg, 3, 0),
-    snapshot: jspb.Message.getFieldWithDefault(msg, 5, ""),
-    finalBackupComplete: jspb.Message.getFieldWithDefault(msg, 6, 0),
-    deployed: jspb.Message.getFieldWithDefault(msg, 7, 0),
-    networkNotReady: jspb.Message.getFieldWithDefault(msg, 8, 0),
-    firstUserActivity: (f = msg.getFirstUserActivity()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
-    headlessTaskFailed: jspb.Message.getFieldWithDefault(msg, 10, ""),
-    stoppedByRequest: jspb.Message.getFieldWithDefault(msg, 11, 0)
+
   };
 
   if (includeInstance) {
@@ -5690,70 +6175,29 @@ proto.wsman.WorkspaceConditions.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceConditions}
+ * @return {!proto.wsman.DescribeClusterRequest}
  */
-proto.wsman.WorkspaceConditions.deserializeBinary = function(bytes) {
+proto.wsman.DescribeClusterRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceConditions;
-  return proto.wsman.WorkspaceConditions.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DescribeClusterRequest;
+  return proto.wsman.DescribeClusterRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceConditions} msg The message object to deserialize into.
+ * @param {!proto.wsman.DescribeClusterRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceConditions}
+ * @return {!proto.wsman.DescribeClusterRequest}
  */
-proto.wsman.WorkspaceConditions.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DescribeClusterRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setFailed(value);
-      break;
-    case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setTimeout(value);
-      break;
-    case 3:
-      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
-      msg.setPullingImages(value);
-      break;
-    case 5:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setSnapshot(value);
-      break;
-    case 6:
-      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
-      msg.setFinalBackupComplete(value);
-      break;
-    case 7:
-      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
-      msg.setDeployed(value);
-      break;
-    case 8:
-      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
-      msg.setNetworkNotReady(value);
-      break;
-    case 9:
-      var value = new google_protobuf_timestamp_pb.Timestamp;
-      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
-      msg.setFirstUserActivity(value);
-      break;
-    case 10:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setHeadlessTaskFailed(value);
-      break;
-    case 11:
-      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
-      msg.setStoppedByRequest(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -5767,9 +6211,9 @@ proto.wsman.WorkspaceConditions.deserializeBinaryFromReader = function(msg, read
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceConditions.prototype.serializeBinary = function() {
+proto.wsman.DescribeClusterRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceConditions.serializeBinaryToWriter(this, writer);
+  proto.wsman.DescribeClusterRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -5777,80 +6221,196 @@ proto.wsman.WorkspaceConditions.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceConditions} message
+ * @param {!proto.wsman.DescribeClusterRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceConditions.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DescribeClusterRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getFailed();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getTimeout();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
-    );
-  }
-  f = message.getPullingImages();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      3,
-      f
-    );
+};
+
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.DescribeClusterResponse.repeatedFields_ = [5,6];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeClusterResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeClusterResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeClusterResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeClusterResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    region: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    workspaceClusterHost: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    defaultWorkspaceClass: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    capacity: (f = msg.getCapacity()) && proto.wsman.ClusterCapacity.toObject(includeInstance, f),
+    workspaceClassesList: jspb.Message.toObjectList(msg.getWorkspaceClassesList(),
+    proto.wsman.WorkspaceClassDescription.toObject, includeInstance),
+    featuresList: (f = jspb.Message.getRepeatedField(msg, 6)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
   }
-  f = message.getSnapshot();
-  if (f.length > 0) {
-    writer.writeString(
-      5,
-      f
-    );
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeClusterResponse}
+ */
+proto.wsman.DescribeClusterResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeClusterResponse;
+  return proto.wsman.DescribeClusterResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeClusterResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeClusterResponse}
+ */
+proto.wsman.DescribeClusterResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRegion(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceClusterHost(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDefaultWorkspaceClass(value);
+      break;
+    case 4:
+      var value = new proto.wsman.ClusterCapacity;
+      reader.readMessage(value,proto.wsman.ClusterCapacity.deserializeBinaryFromReader);
+      msg.setCapacity(value);
+      break;
+    case 5:
+      var value = new proto.wsman.WorkspaceClassDescription;
+      reader.readMessage(value,proto.wsman.WorkspaceClassDescription.deserializeBinaryFromReader);
+      msg.addWorkspaceClasses(value);
+      break;
+    case 6:
+      var values = /** @type {!Array<!proto.wsman.ClusterFeature>} */ (reader.isDelimited() ? reader.readPackedEnum() : [reader.readEnum()]);
+      for (var i = 0; i < values.length; i++) {
+        msg.addFeatures(values[i]);
+      }
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
   }
-  f = message.getFinalBackupComplete();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      6,
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeClusterResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeClusterResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeClusterResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeClusterResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getRegion();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
       f
     );
   }
-  f = message.getDeployed();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      7,
+  f = message.getWorkspaceClusterHost();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
       f
     );
   }
-  f = message.getNetworkNotReady();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      8,
+  f = message.getDefaultWorkspaceClass();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
       f
     );
   }
-  f = message.getFirstUserActivity();
+  f = message.getCapacity();
   if (f != null) {
     writer.writeMessage(
-      9,
+      4,
       f,
-      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+      proto.wsman.ClusterCapacity.serializeBinaryToWriter
     );
   }
-  f = message.getHeadlessTaskFailed();
+  f = message.getWorkspaceClassesList();
   if (f.length > 0) {
-    writer.writeString(
-      10,
-      f
+    writer.writeRepeatedMessage(
+      5,
+      f,
+      proto.wsman.WorkspaceClassDescription.serializeBinaryToWriter
     );
   }
-  f = message.getStoppedByRequest();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      11,
+  f = message.getFeaturesList();
+  if (f.length > 0) {
+    writer.writePackedEnum(
+      6,
       f
     );
   }
@@ -5858,207 +6418,173 @@ proto.wsman.WorkspaceConditions.serializeBinaryToWriter = function(message, writ
 
 
 /**
- * optional string failed = 1;
+ * optional string region = 1;
  * @return {string}
  */
-proto.wsman.WorkspaceConditions.prototype.getFailed = function() {
+proto.wsman.DescribeClusterResponse.prototype.getRegion = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.setFailed = function(value) {
+proto.wsman.DescribeClusterResponse.prototype.setRegion = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string timeout = 2;
+ * optional string workspace_cluster_host = 2;
  * @return {string}
  */
-proto.wsman.WorkspaceConditions.prototype.getTimeout = function() {
+proto.wsman.DescribeClusterResponse.prototype.getWorkspaceClusterHost = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.setTimeout = function(value) {
+proto.wsman.DescribeClusterResponse.prototype.setWorkspaceClusterHost = function(value) {
   return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional WorkspaceConditionBool pulling_images = 3;
- * @return {!proto.wsman.WorkspaceConditionBool}
- */
-proto.wsman.WorkspaceConditions.prototype.getPullingImages = function() {
-  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
-};
-
-
-/**
- * @param {!proto.wsman.WorkspaceConditionBool} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
- */
-proto.wsman.WorkspaceConditions.prototype.setPullingImages = function(value) {
-  return jspb.Message.setProto3EnumField(this, 3, value);
-};
-
-
-/**
- * optional string snapshot = 5;
+ * optional string default_workspace_class = 3;
  * @return {string}
  */
-proto.wsman.WorkspaceConditions.prototype.getSnapshot = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+proto.wsman.DescribeClusterResponse.prototype.getDefaultWorkspaceClass = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
- */
-proto.wsman.WorkspaceConditions.prototype.setSnapshot = function(value) {
-  return jspb.Message.setProto3StringField(this, 5, value);
-};
-
-
-/**
- * optional WorkspaceConditionBool final_backup_complete = 6;
- * @return {!proto.wsman.WorkspaceConditionBool}
- */
-proto.wsman.WorkspaceConditions.prototype.getFinalBackupComplete = function() {
-  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
-};
-
-
-/**
- * @param {!proto.wsman.WorkspaceConditionBool} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.setFinalBackupComplete = function(value) {
-  return jspb.Message.setProto3EnumField(this, 6, value);
+proto.wsman.DescribeClusterResponse.prototype.setDefaultWorkspaceClass = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * optional WorkspaceConditionBool deployed = 7;
- * @return {!proto.wsman.WorkspaceConditionBool}
+ * optional ClusterCapacity capacity = 4;
+ * @return {?proto.wsman.ClusterCapacity}
  */
-proto.wsman.WorkspaceConditions.prototype.getDeployed = function() {
-  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+proto.wsman.DescribeClusterResponse.prototype.getCapacity = function() {
+  return /** @type{?proto.wsman.ClusterCapacity} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.ClusterCapacity, 4));
 };
 
 
 /**
- * @param {!proto.wsman.WorkspaceConditionBool} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
- */
-proto.wsman.WorkspaceConditions.prototype.setDeployed = function(value) {
-  return jspb.Message.setProto3EnumField(this, 7, value);
+ * @param {?proto.wsman.ClusterCapacity|undefined} value
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
+*/
+proto.wsman.DescribeClusterResponse.prototype.setCapacity = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
 };
 
 
 /**
- * optional WorkspaceConditionBool network_not_ready = 8;
- * @return {!proto.wsman.WorkspaceConditionBool}
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.getNetworkNotReady = function() {
-  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
+proto.wsman.DescribeClusterResponse.prototype.clearCapacity = function() {
+  return this.setCapacity(undefined);
 };
 
 
 /**
- * @param {!proto.wsman.WorkspaceConditionBool} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.WorkspaceConditions.prototype.setNetworkNotReady = function(value) {
-  return jspb.Message.setProto3EnumField(this, 8, value);
+proto.wsman.DescribeClusterResponse.prototype.hasCapacity = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
 /**
- * optional google.protobuf.Timestamp first_user_activity = 9;
- * @return {?proto.google.protobuf.Timestamp}
+ * repeated WorkspaceClassDescription workspace_classes = 5;
+ * @return {!Array<!proto.wsman.WorkspaceClassDescription>}
  */
-proto.wsman.WorkspaceConditions.prototype.getFirstUserActivity = function() {
-  return /** @type{?proto.google.protobuf.Timestamp} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 9));
+proto.wsman.DescribeClusterResponse.prototype.getWorkspaceClassesList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceClassDescription>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceClassDescription, 5));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Timestamp|undefined} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * @param {!Array<!proto.wsman.WorkspaceClassDescription>} value
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
 */
-proto.wsman.WorkspaceConditions.prototype.setFirstUserActivity = function(value) {
-  return jspb.Message.setWrapperField(this, 9, value);
+proto.wsman.DescribeClusterResponse.prototype.setWorkspaceClassesList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 5, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * @param {!proto.wsman.WorkspaceClassDescription=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceClassDescription}
  */
-proto.wsman.WorkspaceConditions.prototype.clearFirstUserActivity = function() {
-  return this.setFirstUserActivity(undefined);
+proto.wsman.DescribeClusterResponse.prototype.addWorkspaceClasses = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.WorkspaceClassDescription, opt_index);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.hasFirstUserActivity = function() {
-  return jspb.Message.getField(this, 9) != null;
+proto.wsman.DescribeClusterResponse.prototype.clearWorkspaceClassesList = function() {
+  return this.setWorkspaceClassesList([]);
 };
 
 
 /**
- * optional string headless_task_failed = 10;
- * @return {string}
+ * repeated ClusterFeature features = 6;
+ * @return {!Array<!proto.wsman.ClusterFeature>}
  */
-proto.wsman.WorkspaceConditions.prototype.getHeadlessTaskFailed = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+proto.wsman.DescribeClusterResponse.prototype.getFeaturesList = function() {
+  return /** @type {!Array<!proto.wsman.ClusterFeature>} */ (jspb.Message.getRepeatedField(this, 6));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * @param {!Array<!proto.wsman.ClusterFeature>} value
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.setHeadlessTaskFailed = function(value) {
-  return jspb.Message.setProto3StringField(this, 10, value);
+proto.wsman.DescribeClusterResponse.prototype.setFeaturesList = function(value) {
+  return jspb.Message.setField(this, 6, value || []);
 };
 
 
 /**
- * optional WorkspaceConditionBool stopped_by_request = 11;
- * @return {!proto.wsman.WorkspaceConditionBool}
+ * @param {!proto.wsman.ClusterFeature} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.getStoppedByRequest = function() {
-  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 11, 0));
+proto.wsman.DescribeClusterResponse.prototype.addFeatures = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 6, value, opt_index);
 };
 
 
 /**
- * @param {!proto.wsman.WorkspaceConditionBool} value
- * @return {!proto.wsman.WorkspaceConditions} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.DescribeClusterResponse} returns this
  */
-proto.wsman.WorkspaceConditions.prototype.setStoppedByRequest = function(value) {
-  return jspb.Message.setProto3EnumField(this, 11, value);
+proto.wsman.DescribeClusterResponse.prototype.clearFeaturesList = function() {
+  return this.setFeaturesList([]);
 };
 
 
 
 
-
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto.
@@ -6072,8 +6598,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceMetadata.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceMetadata.toObject(opt_includeInstance, this);
+proto.wsman.ClusterCapacity.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ClusterCapacity.toObject(opt_includeInstance, this);
 };
 
 
@@ -6082,16 +6608,19 @@ proto.wsman.WorkspaceMetadata.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceMetadata} msg The msg instance to transform.
+ * @param {!proto.wsman.ClusterCapacity} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceMetadata.toObject = function(includeInstance, msg) {
+proto.wsman.ClusterCapacity.toObject = function(includeInstance, msg) {
   var f, obj = {
-    owner: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    metaId: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    startedAt: (f = msg.getStartedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
-    annotationsMap: (f = msg.getAnnotationsMap()) ? f.toObject(includeInstance, undefined) : []
+    nodes: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    allocatableCpu: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    allocatableMemory: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    requestedCpu: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    requestedMemory: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    allocatableGpus: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    workspaces: jspb.Message.getFieldWithDefault(msg, 7, 0)
   };
 
   if (includeInstance) {
@@ -6105,23 +6634,23 @@ proto.wsman.WorkspaceMetadata.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceMetadata}
+ * @return {!proto.wsman.ClusterCapacity}
  */
-proto.wsman.WorkspaceMetadata.deserializeBinary = function(bytes) {
+proto.wsman.ClusterCapacity.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceMetadata;
-  return proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.ClusterCapacity;
+  return proto.wsman.ClusterCapacity.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceMetadata} msg The message object to deserialize into.
+ * @param {!proto.wsman.ClusterCapacity} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceMetadata}
+ * @return {!proto.wsman.ClusterCapacity}
  */
-proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.ClusterCapacity.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -6129,29 +6658,38 @@ proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader = function(msg, reader
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setOwner(value);
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setNodes(value);
       break;
     case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setMetaId(value);
+      msg.setAllocatableCpu(value);
       break;
     case 3:
-      var value = new google_protobuf_timestamp_pb.Timestamp;
-      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
-      msg.setStartedAt(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAllocatableMemory(value);
       break;
     case 4:
-      var value = msg.getAnnotationsMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
-         });
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRequestedCpu(value);
       break;
-    default:
-      reader.skipField();
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRequestedMemory(value);
       break;
-    }
-  }
+    case 6:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setAllocatableGpus(value);
+      break;
+    case 7:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setWorkspaces(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
   return msg;
 };
 
@@ -6160,9 +6698,9 @@ proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceMetadata.prototype.serializeBinary = function() {
+proto.wsman.ClusterCapacity.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceMetadata.serializeBinaryToWriter(this, writer);
+  proto.wsman.ClusterCapacity.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -6170,136 +6708,189 @@ proto.wsman.WorkspaceMetadata.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceMetadata} message
+ * @param {!proto.wsman.ClusterCapacity} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceMetadata.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.ClusterCapacity.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getOwner();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getNodes();
+  if (f !== 0) {
+    writer.writeUint32(
       1,
       f
     );
   }
-  f = message.getMetaId();
+  f = message.getAllocatableCpu();
   if (f.length > 0) {
     writer.writeString(
       2,
       f
     );
   }
-  f = message.getStartedAt();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getAllocatableMemory();
+  if (f.length > 0) {
+    writer.writeString(
       3,
-      f,
-      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getAnnotationsMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(4, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  f = message.getRequestedCpu();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getRequestedMemory();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getAllocatableGpus();
+  if (f !== 0) {
+    writer.writeUint32(
+      6,
+      f
+    );
+  }
+  f = message.getWorkspaces();
+  if (f !== 0) {
+    writer.writeUint32(
+      7,
+      f
+    );
   }
 };
 
 
 /**
- * optional string owner = 1;
- * @return {string}
+ * optional uint32 nodes = 1;
+ * @return {number}
  */
-proto.wsman.WorkspaceMetadata.prototype.getOwner = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.ClusterCapacity.prototype.getNodes = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.WorkspaceMetadata} returns this
+ * @param {number} value
+ * @return {!proto.wsman.ClusterCapacity} returns this
  */
-proto.wsman.WorkspaceMetadata.prototype.setOwner = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.wsman.ClusterCapacity.prototype.setNodes = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
 /**
- * optional string meta_id = 2;
+ * optional string allocatable_cpu = 2;
  * @return {string}
  */
-proto.wsman.WorkspaceMetadata.prototype.getMetaId = function() {
+proto.wsman.ClusterCapacity.prototype.getAllocatableCpu = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceMetadata} returns this
+ * @return {!proto.wsman.ClusterCapacity} returns this
  */
-proto.wsman.WorkspaceMetadata.prototype.setMetaId = function(value) {
+proto.wsman.ClusterCapacity.prototype.setAllocatableCpu = function(value) {
   return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional google.protobuf.Timestamp started_at = 3;
- * @return {?proto.google.protobuf.Timestamp}
+ * optional string allocatable_memory = 3;
+ * @return {string}
  */
-proto.wsman.WorkspaceMetadata.prototype.getStartedAt = function() {
-  return /** @type{?proto.google.protobuf.Timestamp} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+proto.wsman.ClusterCapacity.prototype.getAllocatableMemory = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Timestamp|undefined} value
- * @return {!proto.wsman.WorkspaceMetadata} returns this
-*/
-proto.wsman.WorkspaceMetadata.prototype.setStartedAt = function(value) {
-  return jspb.Message.setWrapperField(this, 3, value);
+ * @param {string} value
+ * @return {!proto.wsman.ClusterCapacity} returns this
+ */
+proto.wsman.ClusterCapacity.prototype.setAllocatableMemory = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.WorkspaceMetadata} returns this
+ * optional string requested_cpu = 4;
+ * @return {string}
  */
-proto.wsman.WorkspaceMetadata.prototype.clearStartedAt = function() {
-  return this.setStartedAt(undefined);
+proto.wsman.ClusterCapacity.prototype.getRequestedCpu = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.wsman.ClusterCapacity} returns this
  */
-proto.wsman.WorkspaceMetadata.prototype.hasStartedAt = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.wsman.ClusterCapacity.prototype.setRequestedCpu = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
 };
 
 
 /**
- * map<string, string> annotations = 4;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
+ * optional string requested_memory = 5;
+ * @return {string}
  */
-proto.wsman.WorkspaceMetadata.prototype.getAnnotationsMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 4, opt_noLazyCreate,
-      null));
+proto.wsman.ClusterCapacity.prototype.getRequestedMemory = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.wsman.WorkspaceMetadata} returns this
+ * @param {string} value
+ * @return {!proto.wsman.ClusterCapacity} returns this
  */
-proto.wsman.WorkspaceMetadata.prototype.clearAnnotationsMap = function() {
-  this.getAnnotationsMap().clear();
-  return this;};
+proto.wsman.ClusterCapacity.prototype.setRequestedMemory = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional uint32 allocatable_gpus = 6;
+ * @return {number}
+ */
+proto.wsman.ClusterCapacity.prototype.getAllocatableGpus = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.wsman.ClusterCapacity} returns this
+ */
+proto.wsman.ClusterCapacity.prototype.setAllocatableGpus = function(value) {
+  return jspb.Message.setProto3IntField(this, 6, value);
+};
 
 
+/**
+ * optional uint32 workspaces = 7;
+ * @return {number}
+ */
+proto.wsman.ClusterCapacity.prototype.getWorkspaces = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.wsman.ClusterCapacity} returns this
+ */
+proto.wsman.ClusterCapacity.prototype.setWorkspaces = function(value) {
+  return jspb.Message.setProto3IntField(this, 7, value);
+};
+
 
 
 
@@ -6316,8 +6907,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceRuntimeInfo.toObject(opt_includeInstance, this);
+proto.wsman.WorkspaceClassDescription.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceClassDescription.toObject(opt_includeInstance, this);
 };
 
 
@@ -6326,15 +6917,16 @@ proto.wsman.WorkspaceRuntimeInfo.prototype.toObject = function(opt_includeInstan
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceRuntimeInfo} msg The msg instance to transform.
+ * @param {!proto.wsman.WorkspaceClassDescription} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceRuntimeInfo.toObject = function(includeInstance, msg) {
+proto.wsman.WorkspaceClassDescription.toObject = function(includeInstance, msg) {
   var f, obj = {
-    nodeName: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    podName: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    nodeIp: jspb.Message.getFieldWithDefault(msg, 3, "")
+    name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    cpu: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    memory: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    pvc: jspb.Message.getBooleanFieldWithDefault(msg, 4, false)
   };
 
   if (includeInstance) {
@@ -6348,23 +6940,23 @@ proto.wsman.WorkspaceRuntimeInfo.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceRuntimeInfo}
+ * @return {!proto.wsman.WorkspaceClassDescription}
  */
-proto.wsman.WorkspaceRuntimeInfo.deserializeBinary = function(bytes) {
+proto.wsman.WorkspaceClassDescription.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceRuntimeInfo;
-  return proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.WorkspaceClassDescription;
+  return proto.wsman.WorkspaceClassDescription.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceRuntimeInfo} msg The message object to deserialize into.
+ * @param {!proto.wsman.WorkspaceClassDescription} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceRuntimeInfo}
+ * @return {!proto.wsman.WorkspaceClassDescription}
  */
-proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.WorkspaceClassDescription.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -6373,15 +6965,19 @@ proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader = function(msg, rea
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setNodeName(value);
+      msg.setName(value);
       break;
     case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setPodName(value);
+      msg.setCpu(value);
       break;
     case 3:
       var value = /** @type {string} */ (reader.readString());
-      msg.setNodeIp(value);
+      msg.setMemory(value);
+      break;
+    case 4:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setPvc(value);
       break;
     default:
       reader.skipField();
@@ -6396,9 +6992,9 @@ proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader = function(msg, rea
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.serializeBinary = function() {
+proto.wsman.WorkspaceClassDescription.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter(this, writer);
+  proto.wsman.WorkspaceClassDescription.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -6406,90 +7002,115 @@ proto.wsman.WorkspaceRuntimeInfo.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceRuntimeInfo} message
+ * @param {!proto.wsman.WorkspaceClassDescription} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.WorkspaceClassDescription.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getNodeName();
+  f = message.getName();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getPodName();
+  f = message.getCpu();
   if (f.length > 0) {
     writer.writeString(
       2,
       f
     );
   }
-  f = message.getNodeIp();
+  f = message.getMemory();
   if (f.length > 0) {
     writer.writeString(
       3,
       f
     );
   }
+  f = message.getPvc();
+  if (f) {
+    writer.writeBool(
+      4,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string node_name = 1;
+ * optional string name = 1;
  * @return {string}
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.getNodeName = function() {
+proto.wsman.WorkspaceClassDescription.prototype.getName = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceRuntimeInfo} returns this
+ * @return {!proto.wsman.WorkspaceClassDescription} returns this
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.setNodeName = function(value) {
+proto.wsman.WorkspaceClassDescription.prototype.setName = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string pod_name = 2;
+ * optional string cpu = 2;
  * @return {string}
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.getPodName = function() {
+proto.wsman.WorkspaceClassDescription.prototype.getCpu = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceRuntimeInfo} returns this
+ * @return {!proto.wsman.WorkspaceClassDescription} returns this
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.setPodName = function(value) {
+proto.wsman.WorkspaceClassDescription.prototype.setCpu = function(value) {
   return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional string node_ip = 3;
+ * optional string memory = 3;
  * @return {string}
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.getNodeIp = function() {
+proto.wsman.WorkspaceClassDescription.prototype.getMemory = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.WorkspaceRuntimeInfo} returns this
+ * @return {!proto.wsman.WorkspaceClassDescription} returns this
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.setNodeIp = function(value) {
+proto.wsman.WorkspaceClassDescription.prototype.setMemory = function(value) {
   return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
+/**
+ * optional bool pvc = 4;
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceClassDescription.prototype.getPvc = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsman.WorkspaceClassDescription} returns this
+ */
+proto.wsman.WorkspaceClassDescription.prototype.setPvc = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
 
 
 
@@ -6506,8 +7127,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceAuthentication.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceAuthentication.toObject(opt_includeInstance, this);
+proto.wsman.BackupWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BackupWorkspaceRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -6516,14 +7137,13 @@ proto.wsman.WorkspaceAuthentication.prototype.toObject = function(opt_includeIns
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceAuthentication} msg The msg instance to transform.
+ * @param {!proto.wsman.BackupWorkspaceRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceAuthentication.toObject = function(includeInstance, msg) {
+proto.wsman.BackupWorkspaceRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    admission: jspb.Message.getFieldWithDefault(msg, 1, 0),
-    ownerToken: jspb.Message.getFieldWithDefault(msg, 2, "")
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -6537,23 +7157,23 @@ proto.wsman.WorkspaceAuthentication.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceAuthentication}
+ * @return {!proto.wsman.BackupWorkspaceRequest}
  */
-proto.wsman.WorkspaceAuthentication.deserializeBinary = function(bytes) {
+proto.wsman.BackupWorkspaceRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceAuthentication;
-  return proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BackupWorkspaceRequest;
+  return proto.wsman.BackupWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceAuthentication} msg The message object to deserialize into.
+ * @param {!proto.wsman.BackupWorkspaceRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceAuthentication}
+ * @return {!proto.wsman.BackupWorkspaceRequest}
  */
-proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BackupWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -6561,12 +7181,8 @@ proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader = function(msg,
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
-      msg.setAdmission(value);
-      break;
-    case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setOwnerToken(value);
+      msg.setId(value);
       break;
     default:
       reader.skipField();
@@ -6581,9 +7197,9 @@ proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader = function(msg,
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceAuthentication.prototype.serializeBinary = function() {
+proto.wsman.BackupWorkspaceRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter(this, writer);
+  proto.wsman.BackupWorkspaceRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -6591,23 +7207,16 @@ proto.wsman.WorkspaceAuthentication.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceAuthentication} message
+ * @param {!proto.wsman.BackupWorkspaceRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BackupWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getAdmission();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      1,
-      f
-    );
-  }
-  f = message.getOwnerToken();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
-      2,
+      1,
       f
     );
   }
@@ -6615,48 +7224,153 @@ proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter = function(message,
 
 
 /**
- * optional AdmissionLevel admission = 1;
- * @return {!proto.wsman.AdmissionLevel}
+ * optional string id = 1;
+ * @return {string}
  */
-proto.wsman.WorkspaceAuthentication.prototype.getAdmission = function() {
-  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+proto.wsman.BackupWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * @param {!proto.wsman.AdmissionLevel} value
- * @return {!proto.wsman.WorkspaceAuthentication} returns this
+ * @param {string} value
+ * @return {!proto.wsman.BackupWorkspaceRequest} returns this
  */
-proto.wsman.WorkspaceAuthentication.prototype.setAdmission = function(value) {
-  return jspb.Message.setProto3EnumField(this, 1, value);
+proto.wsman.BackupWorkspaceRequest.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * optional string owner_token = 2;
- * @return {string}
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.WorkspaceAuthentication.prototype.getOwnerToken = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.BackupWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BackupWorkspaceResponse.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.WorkspaceAuthentication} returns this
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.BackupWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceAuthentication.prototype.setOwnerToken = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.wsman.BackupWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
+
 
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.BackupWorkspaceResponse}
+ */
+proto.wsman.BackupWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.BackupWorkspaceResponse;
+  return proto.wsman.BackupWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
 
 
 /**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.BackupWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.BackupWorkspaceResponse}
  */
-proto.wsman.StartWorkspaceSpec.repeatedFields_ = [3,5,6];
+proto.wsman.BackupWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.BackupWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.BackupWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.BackupWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.BackupWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string url = 1;
+ * @return {string}
+ */
+proto.wsman.BackupWorkspaceResponse.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.BackupWorkspaceResponse} returns this
+ */
+proto.wsman.BackupWorkspaceResponse.prototype.setUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
 
 
 
@@ -6673,8 +7387,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.StartWorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StartWorkspaceSpec.toObject(opt_includeInstance, this);
+proto.wsman.WorkspaceStatus.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceStatus.toObject(opt_includeInstance, this);
 };
 
 
@@ -6683,26 +7397,23 @@ proto.wsman.StartWorkspaceSpec.prototype.toObject = function(opt_includeInstance
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.StartWorkspaceSpec} msg The msg instance to transform.
+ * @param {!proto.wsman.WorkspaceStatus} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceSpec.toObject = function(includeInstance, msg) {
+proto.wsman.WorkspaceStatus.toObject = function(includeInstance, msg) {
   var f, obj = {
-    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    deprecatedIdeImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    featureFlagsList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
-    initializer: (f = msg.getInitializer()) && content$service$api_initializer_pb.WorkspaceInitializer.toObject(includeInstance, f),
-    portsList: jspb.Message.toObjectList(msg.getPortsList(),
-    proto.wsman.PortSpec.toObject, includeInstance),
-    envvarsList: jspb.Message.toObjectList(msg.getEnvvarsList(),
-    proto.wsman.EnvironmentVariable.toObject, includeInstance),
-    checkoutLocation: jspb.Message.getFieldWithDefault(msg, 7, ""),
-    workspaceLocation: jspb.Message.getFieldWithDefault(msg, 8, ""),
-    git: (f = msg.getGit()) && proto.wsman.GitSpec.toObject(includeInstance, f),
-    timeout: jspb.Message.getFieldWithDefault(msg, 10, ""),
-    admission: jspb.Message.getFieldWithDefault(msg, 11, 0),
-    ideImage: (f = msg.getIdeImage()) && proto.wsman.IDEImage.toObject(includeInstance, f)
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    statusVersion: jspb.Message.getFieldWithDefault(msg, 10, 0),
+    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
+    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceSpec.toObject(includeInstance, f),
+    phase: jspb.Message.getFieldWithDefault(msg, 4, 0),
+    conditions: (f = msg.getConditions()) && proto.wsman.WorkspaceConditions.toObject(includeInstance, f),
+    message: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    repo: (f = msg.getRepo()) && content$service$api_initializer_pb.GitStatus.toObject(includeInstance, f),
+    runtime: (f = msg.getRuntime()) && proto.wsman.WorkspaceRuntimeInfo.toObject(includeInstance, f),
+    auth: (f = msg.getAuth()) && proto.wsman.WorkspaceAuthentication.toObject(includeInstance, f),
+    stopReason: jspb.Message.getFieldWithDefault(msg, 11, 0)
   };
 
   if (includeInstance) {
@@ -6716,23 +7427,23 @@ proto.wsman.StartWorkspaceSpec.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StartWorkspaceSpec}
+ * @return {!proto.wsman.WorkspaceStatus}
  */
-proto.wsman.StartWorkspaceSpec.deserializeBinary = function(bytes) {
+proto.wsman.WorkspaceStatus.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StartWorkspaceSpec;
-  return proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.WorkspaceStatus;
+  return proto.wsman.WorkspaceStatus.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.StartWorkspaceSpec} msg The message object to deserialize into.
+ * @param {!proto.wsman.WorkspaceStatus} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StartWorkspaceSpec}
+ * @return {!proto.wsman.WorkspaceStatus}
  */
-proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -6741,58 +7452,53 @@ proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader = function(msg, reade
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setWorkspaceImage(value);
+      msg.setId(value);
+      break;
+    case 10:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setStatusVersion(value);
       break;
     case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setDeprecatedIdeImage(value);
+      var value = new proto.wsman.WorkspaceMetadata;
+      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
+      msg.setMetadata(value);
       break;
     case 3:
-      var values = /** @type {!Array<!proto.wsman.WorkspaceFeatureFlag>} */ (reader.isDelimited() ? reader.readPackedEnum() : [reader.readEnum()]);
-      for (var i = 0; i < values.length; i++) {
-        msg.addFeatureFlags(values[i]);
-      }
+      var value = new proto.wsman.WorkspaceSpec;
+      reader.readMessage(value,proto.wsman.WorkspaceSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
       break;
     case 4:
-      var value = new content$service$api_initializer_pb.WorkspaceInitializer;
-      reader.readMessage(value,content$service$api_initializer_pb.WorkspaceInitializer.deserializeBinaryFromReader);
-      msg.setInitializer(value);
+      var value = /** @type {!proto.wsman.WorkspacePhase} */ (reader.readEnum());
+      msg.setPhase(value);
       break;
     case 5:
-      var value = new proto.wsman.PortSpec;
-      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
-      msg.addPorts(value);
+      var value = new proto.wsman.WorkspaceConditions;
+      reader.readMessage(value,proto.wsman.WorkspaceConditions.deserializeBinaryFromReader);
+      msg.setConditions(value);
       break;
     case 6:
-      var value = new proto.wsman.EnvironmentVariable;
-      reader.readMessage(value,proto.wsman.EnvironmentVariable.deserializeBinaryFromReader);
-      msg.addEnvvars(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMessage(value);
       break;
     case 7:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setCheckoutLocation(value);
+      var value = new content$service$api_initializer_pb.GitStatus;
+      reader.readMessage(value,content$service$api_initializer_pb.GitStatus.deserializeBinaryFromReader);
+      msg.setRepo(value);
       break;
     case 8:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setWorkspaceLocation(value);
+      var value = new proto.wsman.WorkspaceRuntimeInfo;
+      reader.readMessage(value,proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader);
+      msg.setRuntime(value);
       break;
     case 9:
-      var value = new proto.wsman.GitSpec;
-      reader.readMessage(value,proto.wsman.GitSpec.deserializeBinaryFromReader);
-      msg.setGit(value);
-      break;
-    case 10:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setTimeout(value);
+      var value = new proto.wsman.WorkspaceAuthentication;
+      reader.readMessage(value,proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader);
+      msg.setAuth(value);
       break;
     case 11:
-      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
-      msg.setAdmission(value);
-      break;
-    case 12:
-      var value = new proto.wsman.IDEImage;
-      reader.readMessage(value,proto.wsman.IDEImage.deserializeBinaryFromReader);
-      msg.setIdeImage(value);
+      var value = /** @type {!proto.wsman.StopReason} */ (reader.readEnum());
+      msg.setStopReason(value);
       break;
     default:
       reader.skipField();
@@ -6807,9 +7513,9 @@ proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader = function(msg, reade
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.StartWorkspaceSpec.prototype.serializeBinary = function() {
+proto.wsman.WorkspaceStatus.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter(this, writer);
+  proto.wsman.WorkspaceStatus.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -6817,110 +7523,3080 @@ proto.wsman.StartWorkspaceSpec.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StartWorkspaceSpec} message
+ * @param {!proto.wsman.WorkspaceStatus} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getWorkspaceImage();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getDeprecatedIdeImage();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
+  f = message.getStatusVersion();
+  if (f !== 0) {
+    writer.writeUint64(
+      10,
       f
     );
   }
-  f = message.getFeatureFlagsList();
-  if (f.length > 0) {
-    writer.writePackedEnum(
-      3,
-      f
+  f = message.getMetadata();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
     );
   }
-  f = message.getInitializer();
+  f = message.getSpec();
   if (f != null) {
     writer.writeMessage(
-      4,
+      3,
       f,
-      content$service$api_initializer_pb.WorkspaceInitializer.serializeBinaryToWriter
+      proto.wsman.WorkspaceSpec.serializeBinaryToWriter
     );
   }
-  f = message.getPortsList();
-  if (f.length > 0) {
-    writer.writeRepeatedMessage(
+  f = message.getPhase();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      4,
+      f
+    );
+  }
+  f = message.getConditions();
+  if (f != null) {
+    writer.writeMessage(
       5,
       f,
-      proto.wsman.PortSpec.serializeBinaryToWriter
+      proto.wsman.WorkspaceConditions.serializeBinaryToWriter
     );
   }
-  f = message.getEnvvarsList();
+  f = message.getMessage();
   if (f.length > 0) {
-    writer.writeRepeatedMessage(
+    writer.writeString(
       6,
-      f,
-      proto.wsman.EnvironmentVariable.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getCheckoutLocation();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getRepo();
+  if (f != null) {
+    writer.writeMessage(
       7,
-      f
+      f,
+      content$service$api_initializer_pb.GitStatus.serializeBinaryToWriter
     );
   }
-  f = message.getWorkspaceLocation();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getRuntime();
+  if (f != null) {
+    writer.writeMessage(
       8,
-      f
+      f,
+      proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter
     );
   }
-  f = message.getGit();
+  f = message.getAuth();
   if (f != null) {
     writer.writeMessage(
       9,
       f,
-      proto.wsman.GitSpec.serializeBinaryToWriter
-    );
-  }
-  f = message.getTimeout();
-  if (f.length > 0) {
-    writer.writeString(
-      10,
-      f
+      proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter
     );
   }
-  f = message.getAdmission();
+  f = message.getStopReason();
   if (f !== 0.0) {
     writer.writeEnum(
       11,
       f
     );
   }
-  f = message.getIdeImage();
-  if (f != null) {
-    writer.writeMessage(
-      12,
-      f,
-      proto.wsman.IDEImage.serializeBinaryToWriter
-    );
-  }
 };
 
 
 /**
- * optional string workspace_image = 1;
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceStatus.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional uint64 status_version = 10;
+ * @return {number}
+ */
+proto.wsman.WorkspaceStatus.prototype.getStatusVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 10, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.setStatusVersion = function(value) {
+  return jspb.Message.setProto3IntField(this, 10, value);
+};
+
+
+/**
+ * optional WorkspaceMetadata metadata = 2;
+ * @return {?proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.WorkspaceStatus.prototype.getMetadata = function() {
+  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 2));
+};
+
+
+/**
+ * @param {?proto.wsman.WorkspaceMetadata|undefined} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+*/
+proto.wsman.WorkspaceStatus.prototype.setMetadata = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.clearMetadata = function() {
+  return this.setMetadata(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasMetadata = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional WorkspaceSpec spec = 3;
+ * @return {?proto.wsman.WorkspaceSpec}
+ */
+proto.wsman.WorkspaceStatus.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.WorkspaceSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceSpec, 3));
+};
+
+
+/**
+ * @param {?proto.wsman.WorkspaceSpec|undefined} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+*/
+proto.wsman.WorkspaceStatus.prototype.setSpec = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.clearSpec = function() {
+  return this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional WorkspacePhase phase = 4;
+ * @return {!proto.wsman.WorkspacePhase}
+ */
+proto.wsman.WorkspaceStatus.prototype.getPhase = function() {
+  return /** @type {!proto.wsman.WorkspacePhase} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspacePhase} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.setPhase = function(value) {
+  return jspb.Message.setProto3EnumField(this, 4, value);
+};
+
+
+/**
+ * optional WorkspaceConditions conditions = 5;
+ * @return {?proto.wsman.WorkspaceConditions}
+ */
+proto.wsman.WorkspaceStatus.prototype.getConditions = function() {
+  return /** @type{?proto.wsman.WorkspaceConditions} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceConditions, 5));
+};
+
+
+/**
+ * @param {?proto.wsman.WorkspaceConditions|undefined} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+*/
+proto.wsman.WorkspaceStatus.prototype.setConditions = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.clearConditions = function() {
+  return this.setConditions(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasConditions = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional string message = 6;
+ * @return {string}
+ */
+proto.wsman.WorkspaceStatus.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.setMessage = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * optional contentservice.GitStatus repo = 7;
+ * @return {?proto.contentservice.GitStatus}
+ */
+proto.wsman.WorkspaceStatus.prototype.getRepo = function() {
+  return /** @type{?proto.contentservice.GitStatus} */ (
+    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.GitStatus, 7));
+};
+
+
+/**
+ * @param {?proto.contentservice.GitStatus|undefined} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+*/
+proto.wsman.WorkspaceStatus.prototype.setRepo = function(value) {
+  return jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.clearRepo = function() {
+  return this.setRepo(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasRepo = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional WorkspaceRuntimeInfo runtime = 8;
+ * @return {?proto.wsman.WorkspaceRuntimeInfo}
+ */
+proto.wsman.WorkspaceStatus.prototype.getRuntime = function() {
+  return /** @type{?proto.wsman.WorkspaceRuntimeInfo} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceRuntimeInfo, 8));
+};
+
+
+/**
+ * @param {?proto.wsman.WorkspaceRuntimeInfo|undefined} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+*/
+proto.wsman.WorkspaceStatus.prototype.setRuntime = function(value) {
+  return jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.clearRuntime = function() {
+  return this.setRuntime(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasRuntime = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional WorkspaceAuthentication auth = 9;
+ * @return {?proto.wsman.WorkspaceAuthentication}
+ */
+proto.wsman.WorkspaceStatus.prototype.getAuth = function() {
+  return /** @type{?proto.wsman.WorkspaceAuthentication} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceAuthentication, 9));
+};
+
+
+/**
+ * @param {?proto.wsman.WorkspaceAuthentication|undefined} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+*/
+proto.wsman.WorkspaceStatus.prototype.setAuth = function(value) {
+  return jspb.Message.setWrapperField(this, 9, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.clearAuth = function() {
+  return this.setAuth(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasAuth = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional StopReason stop_reason = 11;
+ * @return {!proto.wsman.StopReason}
+ */
+proto.wsman.WorkspaceStatus.prototype.getStopReason = function() {
+  return /** @type {!proto.wsman.StopReason} */ (jspb.Message.getFieldWithDefault(this, 11, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.StopReason} value
+ * @return {!proto.wsman.WorkspaceStatus} returns this
+ */
+proto.wsman.WorkspaceStatus.prototype.setStopReason = function(value) {
+  return jspb.Message.setProto3EnumField(this, 11, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.IDEImage.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.IDEImage.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.IDEImage} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.IDEImage.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    webRef: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    desktopRef: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    supervisorRef: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.IDEImage}
+ */
+proto.wsman.IDEImage.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.IDEImage;
+  return proto.wsman.IDEImage.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.IDEImage} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.IDEImage}
+ */
+proto.wsman.IDEImage.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWebRef(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDesktopRef(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSupervisorRef(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.IDEImage.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.IDEImage.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.IDEImage} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.IDEImage.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWebRef();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getDesktopRef();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getSupervisorRef();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string web_ref = 1;
+ * @return {string}
+ */
+proto.wsman.IDEImage.prototype.getWebRef = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.IDEImage} returns this
+ */
+proto.wsman.IDEImage.prototype.setWebRef = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string desktop_ref = 2;
+ * @return {string}
+ */
+proto.wsman.IDEImage.prototype.getDesktopRef = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.IDEImage} returns this
+ */
+proto.wsman.IDEImage.prototype.setDesktopRef = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string supervisor_ref = 3;
+ * @return {string}
+ */
+proto.wsman.IDEImage.prototype.getSupervisorRef = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.IDEImage} returns this
+ */
+proto.wsman.IDEImage.prototype.setSupervisorRef = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.WorkspaceSpec.repeatedFields_ = [5];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceSpec.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceSpec} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceSpec.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    deprecatedIdeImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    headless: jspb.Message.getBooleanFieldWithDefault(msg, 3, false),
+    url: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    exposedPortsList: jspb.Message.toObjectList(msg.getExposedPortsList(),
+    proto.wsman.PortSpec.toObject, includeInstance),
+    type: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    timeout: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    ideImage: (f = msg.getIdeImage()) && proto.wsman.IDEImage.toObject(includeInstance, f),
+    workspaceClass: jspb.Message.getFieldWithDefault(msg, 9, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceSpec}
+ */
+proto.wsman.WorkspaceSpec.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceSpec;
+  return proto.wsman.WorkspaceSpec.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceSpec} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceSpec}
+ */
+proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceImage(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDeprecatedIdeImage(value);
+      break;
+    case 3:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setHeadless(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    case 5:
+      var value = new proto.wsman.PortSpec;
+      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
+      msg.addExposedPorts(value);
+      break;
+    case 6:
+      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
+      msg.setType(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    case 8:
+      var value = new proto.wsman.IDEImage;
+      reader.readMessage(value,proto.wsman.IDEImage.deserializeBinaryFromReader);
+      msg.setIdeImage(value);
+      break;
+    case 9:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceClass(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceSpec.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceSpec.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceSpec} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceImage();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getDeprecatedIdeImage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getHeadless();
+  if (f) {
+    writer.writeBool(
+      3,
+      f
+    );
+  }
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getExposedPortsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      5,
+      f,
+      proto.wsman.PortSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getType();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      6,
+      f
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getIdeImage();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.wsman.IDEImage.serializeBinaryToWriter
+    );
+  }
+  f = message.getWorkspaceClass();
+  if (f.length > 0) {
+    writer.writeString(
+      9,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_image = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getWorkspaceImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setWorkspaceImage = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string deprecated_ide_image = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getDeprecatedIdeImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setDeprecatedIdeImage = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional bool headless = 3;
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceSpec.prototype.getHeadless = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 3, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setHeadless = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 3, value);
+};
+
+
+/**
+ * optional string url = 4;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * repeated PortSpec exposed_ports = 5;
+ * @return {!Array<!proto.wsman.PortSpec>}
+ */
+proto.wsman.WorkspaceSpec.prototype.getExposedPortsList = function() {
+  return /** @type{!Array<!proto.wsman.PortSpec>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PortSpec, 5));
+};
+
+
+/**
+ * @param {!Array<!proto.wsman.PortSpec>} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+*/
+proto.wsman.WorkspaceSpec.prototype.setExposedPortsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 5, value);
+};
+
+
+/**
+ * @param {!proto.wsman.PortSpec=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PortSpec}
+ */
+proto.wsman.WorkspaceSpec.prototype.addExposedPorts = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.PortSpec, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.clearExposedPortsList = function() {
+  return this.setExposedPortsList([]);
+};
+
+
+/**
+ * optional WorkspaceType type = 6;
+ * @return {!proto.wsman.WorkspaceType}
+ */
+proto.wsman.WorkspaceSpec.prototype.getType = function() {
+  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceType} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setType = function(value) {
+  return jspb.Message.setProto3EnumField(this, 6, value);
+};
+
+
+/**
+ * optional string timeout = 7;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setTimeout = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * optional IDEImage ide_image = 8;
+ * @return {?proto.wsman.IDEImage}
+ */
+proto.wsman.WorkspaceSpec.prototype.getIdeImage = function() {
+  return /** @type{?proto.wsman.IDEImage} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.IDEImage, 8));
+};
+
+
+/**
+ * @param {?proto.wsman.IDEImage|undefined} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+*/
+proto.wsman.WorkspaceSpec.prototype.setIdeImage = function(value) {
+  return jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.clearIdeImage = function() {
+  return this.setIdeImage(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceSpec.prototype.hasIdeImage = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional string workspace_class = 9;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getWorkspaceClass = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceSpec} returns this
+ */
+proto.wsman.WorkspaceSpec.prototype.setWorkspaceClass = function(value) {
+  return jspb.Message.setProto3StringField(this, 9, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.PortSpec.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.PortSpec.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.PortSpec} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PortSpec.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    port: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    visibility: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    url: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    headers: (f = msg.getHeaders()) && proto.wsman.PortHeaders.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.PortSpec}
+ */
+proto.wsman.PortSpec.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.PortSpec;
+  return proto.wsman.PortSpec.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.PortSpec} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.PortSpec}
+ */
+proto.wsman.PortSpec.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setPort(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.wsman.PortVisibility} */ (reader.readEnum());
+      msg.setVisibility(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    case 5:
+      var value = new proto.wsman.PortHeaders;
+      reader.readMessage(value,proto.wsman.PortHeaders.deserializeBinaryFromReader);
+      msg.setHeaders(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.PortSpec.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.PortSpec.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.PortSpec} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PortSpec.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getPort();
+  if (f !== 0) {
+    writer.writeUint32(
+      1,
+      f
+    );
+  }
+  f = message.getVisibility();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getHeaders();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.wsman.PortHeaders.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional uint32 port = 1;
+ * @return {number}
+ */
+proto.wsman.PortSpec.prototype.getPort = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.wsman.PortSpec} returns this
+ */
+proto.wsman.PortSpec.prototype.setPort = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+/**
+ * optional PortVisibility visibility = 3;
+ * @return {!proto.wsman.PortVisibility}
+ */
+proto.wsman.PortSpec.prototype.getVisibility = function() {
+  return /** @type {!proto.wsman.PortVisibility} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.PortVisibility} value
+ * @return {!proto.wsman.PortSpec} returns this
+ */
+proto.wsman.PortSpec.prototype.setVisibility = function(value) {
+  return jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
+/**
+ * optional string url = 4;
+ * @return {string}
+ */
+proto.wsman.PortSpec.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.PortSpec} returns this
+ */
+proto.wsman.PortSpec.prototype.setUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional PortHeaders headers = 5;
+ * @return {?proto.wsman.PortHeaders}
+ */
+proto.wsman.PortSpec.prototype.getHeaders = function() {
+  return /** @type{?proto.wsman.PortHeaders} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.PortHeaders, 5));
+};
+
+
+/**
+ * @param {?proto.wsman.PortHeaders|undefined} value
+ * @return {!proto.wsman.PortSpec} returns this
+*/
+proto.wsman.PortSpec.prototype.setHeaders = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.PortSpec} returns this
+ */
+proto.wsman.PortSpec.prototype.clearHeaders = function() {
+  return this.setHeaders(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.PortSpec.prototype.hasHeaders = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.WorkspaceConditions.repeatedFields_ = [14];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceConditions.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceConditions.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceConditions} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceConditions.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    failed: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    timeout: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    pullingImages: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    snapshot: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    finalBackupComplete: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    deployed: jspb.Message.getFieldWithDefault(msg, 7, 0),
+    networkNotReady: jspb.Message.getFieldWithDefault(msg, 8, 0),
+    firstUserActivity: (f = msg.getFirstUserActivity()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    headlessTaskFailed: jspb.Message.getFieldWithDefault(msg, 10, ""),
+    stoppedByRequest: jspb.Message.getFieldWithDefault(msg, 11, 0),
+    volumeSnapshot: jspb.Message.getFieldWithDefault(msg, 12, ""),
+    evicted: jspb.Message.getFieldWithDefault(msg, 13, ""),
+    scheduledSnapshotsList: (f = jspb.Message.getRepeatedField(msg, 14)) == null ? undefined : f,
+    paused: jspb.Message.getFieldWithDefault(msg, 15, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceConditions}
+ */
+proto.wsman.WorkspaceConditions.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceConditions;
+  return proto.wsman.WorkspaceConditions.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceConditions} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceConditions}
+ */
+proto.wsman.WorkspaceConditions.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setFailed(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setPullingImages(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSnapshot(value);
+      break;
+    case 6:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setFinalBackupComplete(value);
+      break;
+    case 7:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setDeployed(value);
+      break;
+    case 8:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setNetworkNotReady(value);
+      break;
+    case 9:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setFirstUserActivity(value);
+      break;
+    case 10:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setHeadlessTaskFailed(value);
+      break;
+    case 11:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setStoppedByRequest(value);
+      break;
+    case 12:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setVolumeSnapshot(value);
+      break;
+    case 13:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setEvicted(value);
+      break;
+    case 14:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addScheduledSnapshots(value);
+      break;
+    case 15:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setPaused(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceConditions.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceConditions.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceConditions} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceConditions.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getFailed();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getPullingImages();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+  f = message.getSnapshot();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getFinalBackupComplete();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      6,
+      f
+    );
+  }
+  f = message.getDeployed();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      7,
+      f
+    );
+  }
+  f = message.getNetworkNotReady();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      8,
+      f
+    );
+  }
+  f = message.getFirstUserActivity();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getHeadlessTaskFailed();
+  if (f.length > 0) {
+    writer.writeString(
+      10,
+      f
+    );
+  }
+  f = message.getStoppedByRequest();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      11,
+      f
+    );
+  }
+  f = message.getVolumeSnapshot();
+  if (f.length > 0) {
+    writer.writeString(
+      12,
+      f
+    );
+  }
+  f = message.getEvicted();
+  if (f.length > 0) {
+    writer.writeString(
+      13,
+      f
+    );
+  }
+  f = message.getScheduledSnapshotsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      14,
+      f
+    );
+  }
+  f = message.getPaused();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      15,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string failed = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getFailed = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setFailed = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string timeout = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setTimeout = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional WorkspaceConditionBool pulling_images = 3;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getPullingImages = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceConditionBool} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setPullingImages = function(value) {
+  return jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
+/**
+ * optional string snapshot = 5;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getSnapshot = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setSnapshot = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional WorkspaceConditionBool final_backup_complete = 6;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getFinalBackupComplete = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceConditionBool} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setFinalBackupComplete = function(value) {
+  return jspb.Message.setProto3EnumField(this, 6, value);
+};
+
+
+/**
+ * optional WorkspaceConditionBool deployed = 7;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getDeployed = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceConditionBool} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setDeployed = function(value) {
+  return jspb.Message.setProto3EnumField(this, 7, value);
+};
+
+
+/**
+ * optional WorkspaceConditionBool network_not_ready = 8;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getNetworkNotReady = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceConditionBool} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setNetworkNotReady = function(value) {
+  return jspb.Message.setProto3EnumField(this, 8, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp first_user_activity = 9;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.WorkspaceConditions.prototype.getFirstUserActivity = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 9));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+*/
+proto.wsman.WorkspaceConditions.prototype.setFirstUserActivity = function(value) {
+  return jspb.Message.setWrapperField(this, 9, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.clearFirstUserActivity = function() {
+  return this.setFirstUserActivity(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceConditions.prototype.hasFirstUserActivity = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional string headless_task_failed = 10;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getHeadlessTaskFailed = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setHeadlessTaskFailed = function(value) {
+  return jspb.Message.setProto3StringField(this, 10, value);
+};
+
+
+/**
+ * optional WorkspaceConditionBool stopped_by_request = 11;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getStoppedByRequest = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 11, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceConditionBool} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setStoppedByRequest = function(value) {
+  return jspb.Message.setProto3EnumField(this, 11, value);
+};
+
+
+/**
+ * optional string volume_snapshot = 12;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getVolumeSnapshot = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 12, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setVolumeSnapshot = function(value) {
+  return jspb.Message.setProto3StringField(this, 12, value);
+};
+
+
+/**
+ * optional string evicted = 13;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getEvicted = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 13, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setEvicted = function(value) {
+  return jspb.Message.setProto3StringField(this, 13, value);
+};
+
+
+/**
+ * repeated string scheduled_snapshots = 14;
+ * @return {!Array<string>}
+ */
+proto.wsman.WorkspaceConditions.prototype.getScheduledSnapshotsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 14));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setScheduledSnapshotsList = function(value) {
+  return jspb.Message.setField(this, 14, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.addScheduledSnapshots = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 14, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.clearScheduledSnapshotsList = function() {
+  return this.setScheduledSnapshotsList([]);
+};
+
+
+/**
+ * optional WorkspaceConditionBool paused = 15;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getPaused = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 15, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceConditionBool} value
+ * @return {!proto.wsman.WorkspaceConditions} returns this
+ */
+proto.wsman.WorkspaceConditions.prototype.setPaused = function(value) {
+  return jspb.Message.setProto3EnumField(this, 15, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceMetadata.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceMetadata.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceMetadata} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceMetadata.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    owner: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    metaId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    startedAt: (f = msg.getStartedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    annotationsMap: (f = msg.getAnnotationsMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.WorkspaceMetadata.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceMetadata;
+  return proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceMetadata} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMetaId(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setStartedAt(value);
+      break;
+    case 4:
+      var value = msg.getAnnotationsMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceMetadata.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceMetadata.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceMetadata} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceMetadata.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getMetaId();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getStartedAt();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getAnnotationsMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(4, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * optional string owner = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceMetadata.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceMetadata} returns this
+ */
+proto.wsman.WorkspaceMetadata.prototype.setOwner = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string meta_id = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceMetadata.prototype.getMetaId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceMetadata} returns this
+ */
+proto.wsman.WorkspaceMetadata.prototype.setMetaId = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp started_at = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.WorkspaceMetadata.prototype.getStartedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.wsman.WorkspaceMetadata} returns this
+*/
+proto.wsman.WorkspaceMetadata.prototype.setStartedAt = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.WorkspaceMetadata} returns this
+ */
+proto.wsman.WorkspaceMetadata.prototype.clearStartedAt = function() {
+  return this.setStartedAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceMetadata.prototype.hasStartedAt = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * map<string, string> annotations = 4;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.WorkspaceMetadata.prototype.getAnnotationsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 4, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.wsman.WorkspaceMetadata} returns this
+ */
+proto.wsman.WorkspaceMetadata.prototype.clearAnnotationsMap = function() {
+  this.getAnnotationsMap().clear();
+  return this;};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceRuntimeInfo.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceRuntimeInfo} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceRuntimeInfo.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    nodeName: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    podName: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    nodeIp: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceRuntimeInfo}
+ */
+proto.wsman.WorkspaceRuntimeInfo.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceRuntimeInfo;
+  return proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceRuntimeInfo} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceRuntimeInfo}
+ */
+proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setNodeName(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPodName(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setNodeIp(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceRuntimeInfo} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getNodeName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getPodName();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getNodeIp();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string node_name = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.getNodeName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceRuntimeInfo} returns this
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.setNodeName = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string pod_name = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.getPodName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceRuntimeInfo} returns this
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.setPodName = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string node_ip = 3;
+ * @return {string}
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.getNodeIp = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceRuntimeInfo} returns this
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.setNodeIp = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceAuthentication.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceAuthentication.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceAuthentication} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceAuthentication.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    admission: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    ownerToken: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceAuthentication}
+ */
+proto.wsman.WorkspaceAuthentication.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceAuthentication;
+  return proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceAuthentication} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceAuthentication}
+ */
+proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
+      msg.setAdmission(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwnerToken(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceAuthentication.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceAuthentication} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getAdmission();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      1,
+      f
+    );
+  }
+  f = message.getOwnerToken();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional AdmissionLevel admission = 1;
+ * @return {!proto.wsman.AdmissionLevel}
+ */
+proto.wsman.WorkspaceAuthentication.prototype.getAdmission = function() {
+  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.AdmissionLevel} value
+ * @return {!proto.wsman.WorkspaceAuthentication} returns this
+ */
+proto.wsman.WorkspaceAuthentication.prototype.setAdmission = function(value) {
+  return jspb.Message.setProto3EnumField(this, 1, value);
+};
+
+
+/**
+ * optional string owner_token = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceAuthentication.prototype.getOwnerToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.WorkspaceAuthentication} returns this
+ */
+proto.wsman.WorkspaceAuthentication.prototype.setOwnerToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.StartWorkspaceSpec.repeatedFields_ = [3,5,6];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceSpec.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StartWorkspaceSpec} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceSpec.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    deprecatedIdeImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    featureFlagsList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
+    initializer: (f = msg.getInitializer()) && content$service$api_initializer_pb.WorkspaceInitializer.toObject(includeInstance, f),
+    portsList: jspb.Message.toObjectList(msg.getPortsList(),
+    proto.wsman.PortSpec.toObject, includeInstance),
+    envvarsList: jspb.Message.toObjectList(msg.getEnvvarsList(),
+    proto.wsman.EnvironmentVariable.toObject, includeInstance),
+    checkoutLocation: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    workspaceLocation: jspb.Message.getFieldWithDefault(msg, 8, ""),
+    git: (f = msg.getGit()) && proto.wsman.GitSpec.toObject(includeInstance, f),
+    timeout: jspb.Message.getFieldWithDefault(msg, 10, ""),
+    admission: jspb.Message.getFieldWithDefault(msg, 11, 0),
+    ideImage: (f = msg.getIdeImage()) && proto.wsman.IDEImage.toObject(includeInstance, f),
+    workspaceClass: jspb.Message.getFieldWithDefault(msg, 13, ""),
+    volumeSnapshot: jspb.Message.getFieldWithDefault(msg, 14, ""),
+    gpu: (f = msg.getGpu()) && proto.wsman.GPURequest.toObject(includeInstance, f),
+    snapshotPolicy: (f = msg.getSnapshotPolicy()) && proto.wsman.SnapshotPolicy.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StartWorkspaceSpec}
+ */
+proto.wsman.StartWorkspaceSpec.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StartWorkspaceSpec;
+  return proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StartWorkspaceSpec} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StartWorkspaceSpec}
+ */
+proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceImage(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDeprecatedIdeImage(value);
+      break;
+    case 3:
+      var values = /** @type {!Array<!proto.wsman.WorkspaceFeatureFlag>} */ (reader.isDelimited() ? reader.readPackedEnum() : [reader.readEnum()]);
+      for (var i = 0; i < values.length; i++) {
+        msg.addFeatureFlags(values[i]);
+      }
+      break;
+    case 4:
+      var value = new content$service$api_initializer_pb.WorkspaceInitializer;
+      reader.readMessage(value,content$service$api_initializer_pb.WorkspaceInitializer.deserializeBinaryFromReader);
+      msg.setInitializer(value);
+      break;
+    case 5:
+      var value = new proto.wsman.PortSpec;
+      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
+      msg.addPorts(value);
+      break;
+    case 6:
+      var value = new proto.wsman.EnvironmentVariable;
+      reader.readMessage(value,proto.wsman.EnvironmentVariable.deserializeBinaryFromReader);
+      msg.addEnvvars(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setCheckoutLocation(value);
+      break;
+    case 8:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceLocation(value);
+      break;
+    case 9:
+      var value = new proto.wsman.GitSpec;
+      reader.readMessage(value,proto.wsman.GitSpec.deserializeBinaryFromReader);
+      msg.setGit(value);
+      break;
+    case 10:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    case 11:
+      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
+      msg.setAdmission(value);
+      break;
+    case 12:
+      var value = new proto.wsman.IDEImage;
+      reader.readMessage(value,proto.wsman.IDEImage.deserializeBinaryFromReader);
+      msg.setIdeImage(value);
+      break;
+    case 13:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceClass(value);
+      break;
+    case 14:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setVolumeSnapshot(value);
+      break;
+    case 15:
+      var value = new proto.wsman.GPURequest;
+      reader.readMessage(value,proto.wsman.GPURequest.deserializeBinaryFromReader);
+      msg.setGpu(value);
+      break;
+    case 16:
+      var value = new proto.wsman.SnapshotPolicy;
+      reader.readMessage(value,proto.wsman.SnapshotPolicy.deserializeBinaryFromReader);
+      msg.setSnapshotPolicy(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StartWorkspaceSpec} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceImage();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getDeprecatedIdeImage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getFeatureFlagsList();
+  if (f.length > 0) {
+    writer.writePackedEnum(
+      3,
+      f
+    );
+  }
+  f = message.getInitializer();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      content$service$api_initializer_pb.WorkspaceInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getPortsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      5,
+      f,
+      proto.wsman.PortSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getEnvvarsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      6,
+      f,
+      proto.wsman.EnvironmentVariable.serializeBinaryToWriter
+    );
+  }
+  f = message.getCheckoutLocation();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getWorkspaceLocation();
+  if (f.length > 0) {
+    writer.writeString(
+      8,
+      f
+    );
+  }
+  f = message.getGit();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      proto.wsman.GitSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      10,
+      f
+    );
+  }
+  f = message.getAdmission();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      11,
+      f
+    );
+  }
+  f = message.getIdeImage();
+  if (f != null) {
+    writer.writeMessage(
+      12,
+      f,
+      proto.wsman.IDEImage.serializeBinaryToWriter
+    );
+  }
+  f = message.getWorkspaceClass();
+  if (f.length > 0) {
+    writer.writeString(
+      13,
+      f
+    );
+  }
+  f = message.getVolumeSnapshot();
+  if (f.length > 0) {
+    writer.writeString(
+      14,
+      f
+    );
+  }
+  f = message.getGpu();
+  if (f != null) {
+    writer.writeMessage(
+      15,
+      f,
+      proto.wsman.GPURequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getSnapshotPolicy();
+  if (f != null) {
+    writer.writeMessage(
+      16,
+      f,
+      proto.wsman.SnapshotPolicy.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_image = 1;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getWorkspaceImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setWorkspaceImage = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string deprecated_ide_image = 2;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getDeprecatedIdeImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setDeprecatedIdeImage = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * repeated WorkspaceFeatureFlag feature_flags = 3;
+ * @return {!Array<!proto.wsman.WorkspaceFeatureFlag>}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getFeatureFlagsList = function() {
+  return /** @type {!Array<!proto.wsman.WorkspaceFeatureFlag>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/**
+ * @param {!Array<!proto.wsman.WorkspaceFeatureFlag>} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setFeatureFlagsList = function(value) {
+  return jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceFeatureFlag} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.addFeatureFlags = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearFeatureFlagsList = function() {
+  return this.setFeatureFlagsList([]);
+};
+
+
+/**
+ * optional contentservice.WorkspaceInitializer initializer = 4;
+ * @return {?proto.contentservice.WorkspaceInitializer}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getInitializer = function() {
+  return /** @type{?proto.contentservice.WorkspaceInitializer} */ (
+    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.WorkspaceInitializer, 4));
+};
+
+
+/**
+ * @param {?proto.contentservice.WorkspaceInitializer|undefined} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+*/
+proto.wsman.StartWorkspaceSpec.prototype.setInitializer = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearInitializer = function() {
+  return this.setInitializer(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.hasInitializer = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * repeated PortSpec ports = 5;
+ * @return {!Array<!proto.wsman.PortSpec>}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getPortsList = function() {
+  return /** @type{!Array<!proto.wsman.PortSpec>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PortSpec, 5));
+};
+
+
+/**
+ * @param {!Array<!proto.wsman.PortSpec>} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+*/
+proto.wsman.StartWorkspaceSpec.prototype.setPortsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 5, value);
+};
+
+
+/**
+ * @param {!proto.wsman.PortSpec=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PortSpec}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.addPorts = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.PortSpec, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearPortsList = function() {
+  return this.setPortsList([]);
+};
+
+
+/**
+ * repeated EnvironmentVariable envvars = 6;
+ * @return {!Array<!proto.wsman.EnvironmentVariable>}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getEnvvarsList = function() {
+  return /** @type{!Array<!proto.wsman.EnvironmentVariable>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.EnvironmentVariable, 6));
+};
+
+
+/**
+ * @param {!Array<!proto.wsman.EnvironmentVariable>} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+*/
+proto.wsman.StartWorkspaceSpec.prototype.setEnvvarsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 6, value);
+};
+
+
+/**
+ * @param {!proto.wsman.EnvironmentVariable=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.EnvironmentVariable}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.addEnvvars = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 6, opt_value, proto.wsman.EnvironmentVariable, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearEnvvarsList = function() {
+  return this.setEnvvarsList([]);
+};
+
+
+/**
+ * optional string checkout_location = 7;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getCheckoutLocation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setCheckoutLocation = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * optional string workspace_location = 8;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getWorkspaceLocation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setWorkspaceLocation = function(value) {
+  return jspb.Message.setProto3StringField(this, 8, value);
+};
+
+
+/**
+ * optional GitSpec git = 9;
+ * @return {?proto.wsman.GitSpec}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getGit = function() {
+  return /** @type{?proto.wsman.GitSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.GitSpec, 9));
+};
+
+
+/**
+ * @param {?proto.wsman.GitSpec|undefined} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+*/
+proto.wsman.StartWorkspaceSpec.prototype.setGit = function(value) {
+  return jspb.Message.setWrapperField(this, 9, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearGit = function() {
+  return this.setGit(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.hasGit = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional string timeout = 10;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setTimeout = function(value) {
+  return jspb.Message.setProto3StringField(this, 10, value);
+};
+
+
+/**
+ * optional AdmissionLevel admission = 11;
+ * @return {!proto.wsman.AdmissionLevel}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getAdmission = function() {
+  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 11, 0));
+};
+
+
+/**
+ * @param {!proto.wsman.AdmissionLevel} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.setAdmission = function(value) {
+  return jspb.Message.setProto3EnumField(this, 11, value);
+};
+
+
+/**
+ * optional IDEImage ide_image = 12;
+ * @return {?proto.wsman.IDEImage}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getIdeImage = function() {
+  return /** @type{?proto.wsman.IDEImage} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.IDEImage, 12));
+};
+
+
+/**
+ * @param {?proto.wsman.IDEImage|undefined} value
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+*/
+proto.wsman.StartWorkspaceSpec.prototype.setIdeImage = function(value) {
+  return jspb.Message.setWrapperField(this, 12, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearIdeImage = function() {
+  return this.setIdeImage(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.hasIdeImage = function() {
+  return jspb.Message.getField(this, 12) != null;
+};
+
+
+/**
+ * optional string workspace_class = 13;
  * @return {string}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getWorkspaceImage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.StartWorkspaceSpec.prototype.getWorkspaceClass = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 13, ""));
 };
 
 
@@ -6928,17 +10604,17 @@ proto.wsman.StartWorkspaceSpec.prototype.getWorkspaceImage = function() {
  * @param {string} value
  * @return {!proto.wsman.StartWorkspaceSpec} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.setWorkspaceImage = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.wsman.StartWorkspaceSpec.prototype.setWorkspaceClass = function(value) {
+  return jspb.Message.setProto3StringField(this, 13, value);
 };
 
 
 /**
- * optional string deprecated_ide_image = 2;
+ * optional string volume_snapshot = 14;
  * @return {string}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getDeprecatedIdeImage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.StartWorkspaceSpec.prototype.getVolumeSnapshot = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 14, ""));
 };
 
 
@@ -6946,64 +10622,64 @@ proto.wsman.StartWorkspaceSpec.prototype.getDeprecatedIdeImage = function() {
  * @param {string} value
  * @return {!proto.wsman.StartWorkspaceSpec} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.setDeprecatedIdeImage = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.wsman.StartWorkspaceSpec.prototype.setVolumeSnapshot = function(value) {
+  return jspb.Message.setProto3StringField(this, 14, value);
 };
 
 
 /**
- * repeated WorkspaceFeatureFlag feature_flags = 3;
- * @return {!Array<!proto.wsman.WorkspaceFeatureFlag>}
+ * optional GPURequest gpu = 15;
+ * @return {?proto.wsman.GPURequest}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getFeatureFlagsList = function() {
-  return /** @type {!Array<!proto.wsman.WorkspaceFeatureFlag>} */ (jspb.Message.getRepeatedField(this, 3));
+proto.wsman.StartWorkspaceSpec.prototype.getGpu = function() {
+  return /** @type{?proto.wsman.GPURequest} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.GPURequest, 15));
 };
 
 
 /**
- * @param {!Array<!proto.wsman.WorkspaceFeatureFlag>} value
+ * @param {?proto.wsman.GPURequest|undefined} value
  * @return {!proto.wsman.StartWorkspaceSpec} returns this
- */
-proto.wsman.StartWorkspaceSpec.prototype.setFeatureFlagsList = function(value) {
-  return jspb.Message.setField(this, 3, value || []);
+*/
+proto.wsman.StartWorkspaceSpec.prototype.setGpu = function(value) {
+  return jspb.Message.setWrapperField(this, 15, value);
 };
 
 
 /**
- * @param {!proto.wsman.WorkspaceFeatureFlag} value
- * @param {number=} opt_index
+ * Clears the message field making it undefined.
  * @return {!proto.wsman.StartWorkspaceSpec} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.addFeatureFlags = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+proto.wsman.StartWorkspaceSpec.prototype.clearGpu = function() {
+  return this.setGpu(undefined);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.StartWorkspaceSpec.prototype.clearFeatureFlagsList = function() {
-  return this.setFeatureFlagsList([]);
+proto.wsman.StartWorkspaceSpec.prototype.hasGpu = function() {
+  return jspb.Message.getField(this, 15) != null;
 };
 
 
 /**
- * optional contentservice.WorkspaceInitializer initializer = 4;
- * @return {?proto.contentservice.WorkspaceInitializer}
+ * optional SnapshotPolicy snapshot_policy = 16;
+ * @return {?proto.wsman.SnapshotPolicy}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getInitializer = function() {
-  return /** @type{?proto.contentservice.WorkspaceInitializer} */ (
-    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.WorkspaceInitializer, 4));
+proto.wsman.StartWorkspaceSpec.prototype.getSnapshotPolicy = function() {
+  return /** @type{?proto.wsman.SnapshotPolicy} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.SnapshotPolicy, 16));
 };
 
 
 /**
- * @param {?proto.contentservice.WorkspaceInitializer|undefined} value
+ * @param {?proto.wsman.SnapshotPolicy|undefined} value
  * @return {!proto.wsman.StartWorkspaceSpec} returns this
 */
-proto.wsman.StartWorkspaceSpec.prototype.setInitializer = function(value) {
-  return jspb.Message.setWrapperField(this, 4, value);
+proto.wsman.StartWorkspaceSpec.prototype.setSnapshotPolicy = function(value) {
+  return jspb.Message.setWrapperField(this, 16, value);
 };
 
 
@@ -7011,8 +10687,8 @@ proto.wsman.StartWorkspaceSpec.prototype.setInitializer = function(value) {
  * Clears the message field making it undefined.
  * @return {!proto.wsman.StartWorkspaceSpec} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.clearInitializer = function() {
-  return this.setInitializer(undefined);
+proto.wsman.StartWorkspaceSpec.prototype.clearSnapshotPolicy = function() {
+  return this.setSnapshotPolicy(undefined);
 };
 
 
@@ -7020,230 +10696,326 @@ proto.wsman.StartWorkspaceSpec.prototype.clearInitializer = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.StartWorkspaceSpec.prototype.hasInitializer = function() {
-  return jspb.Message.getField(this, 4) != null;
-};
-
-
-/**
- * repeated PortSpec ports = 5;
- * @return {!Array<!proto.wsman.PortSpec>}
- */
-proto.wsman.StartWorkspaceSpec.prototype.getPortsList = function() {
-  return /** @type{!Array<!proto.wsman.PortSpec>} */ (
-    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PortSpec, 5));
+proto.wsman.StartWorkspaceSpec.prototype.hasSnapshotPolicy = function() {
+  return jspb.Message.getField(this, 16) != null;
 };
 
 
-/**
- * @param {!Array<!proto.wsman.PortSpec>} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
-*/
-proto.wsman.StartWorkspaceSpec.prototype.setPortsList = function(value) {
-  return jspb.Message.setRepeatedWrapperField(this, 5, value);
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {!proto.wsman.PortSpec=} opt_value
- * @param {number=} opt_index
- * @return {!proto.wsman.PortSpec}
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.StartWorkspaceSpec.prototype.addPorts = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.PortSpec, opt_index);
+proto.wsman.SnapshotPolicy.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SnapshotPolicy.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SnapshotPolicy} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceSpec.prototype.clearPortsList = function() {
-  return this.setPortsList([]);
+proto.wsman.SnapshotPolicy.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    interval: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    keep: jspb.Message.getFieldWithDefault(msg, 2, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * repeated EnvironmentVariable envvars = 6;
- * @return {!Array<!proto.wsman.EnvironmentVariable>}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SnapshotPolicy}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getEnvvarsList = function() {
-  return /** @type{!Array<!proto.wsman.EnvironmentVariable>} */ (
-    jspb.Message.getRepeatedWrapperField(this, proto.wsman.EnvironmentVariable, 6));
+proto.wsman.SnapshotPolicy.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SnapshotPolicy;
+  return proto.wsman.SnapshotPolicy.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * @param {!Array<!proto.wsman.EnvironmentVariable>} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
-*/
-proto.wsman.StartWorkspaceSpec.prototype.setEnvvarsList = function(value) {
-  return jspb.Message.setRepeatedWrapperField(this, 6, value);
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SnapshotPolicy} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SnapshotPolicy}
+ */
+proto.wsman.SnapshotPolicy.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setInterval(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setKeep(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * @param {!proto.wsman.EnvironmentVariable=} opt_value
- * @param {number=} opt_index
- * @return {!proto.wsman.EnvironmentVariable}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.StartWorkspaceSpec.prototype.addEnvvars = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 6, opt_value, proto.wsman.EnvironmentVariable, opt_index);
+proto.wsman.SnapshotPolicy.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SnapshotPolicy.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SnapshotPolicy} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceSpec.prototype.clearEnvvarsList = function() {
-  return this.setEnvvarsList([]);
+proto.wsman.SnapshotPolicy.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getInterval();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getKeep();
+  if (f !== 0) {
+    writer.writeUint32(
+      2,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string checkout_location = 7;
+ * optional string interval = 1;
  * @return {string}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getCheckoutLocation = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+proto.wsman.SnapshotPolicy.prototype.getInterval = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * @return {!proto.wsman.SnapshotPolicy} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.setCheckoutLocation = function(value) {
-  return jspb.Message.setProto3StringField(this, 7, value);
+proto.wsman.SnapshotPolicy.prototype.setInterval = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string workspace_location = 8;
- * @return {string}
+ * optional uint32 keep = 2;
+ * @return {number}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getWorkspaceLocation = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+proto.wsman.SnapshotPolicy.prototype.getKeep = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * @param {number} value
+ * @return {!proto.wsman.SnapshotPolicy} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.setWorkspaceLocation = function(value) {
-  return jspb.Message.setProto3StringField(this, 8, value);
+proto.wsman.SnapshotPolicy.prototype.setKeep = function(value) {
+  return jspb.Message.setProto3IntField(this, 2, value);
 };
 
 
-/**
- * optional GitSpec git = 9;
- * @return {?proto.wsman.GitSpec}
- */
-proto.wsman.StartWorkspaceSpec.prototype.getGit = function() {
-  return /** @type{?proto.wsman.GitSpec} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.GitSpec, 9));
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {?proto.wsman.GitSpec|undefined} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
-*/
-proto.wsman.StartWorkspaceSpec.prototype.setGit = function(value) {
-  return jspb.Message.setWrapperField(this, 9, value);
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GPURequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GPURequest.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GPURequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceSpec.prototype.clearGit = function() {
-  return this.setGit(undefined);
+proto.wsman.GPURequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    count: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    type: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GPURequest}
  */
-proto.wsman.StartWorkspaceSpec.prototype.hasGit = function() {
-  return jspb.Message.getField(this, 9) != null;
+proto.wsman.GPURequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GPURequest;
+  return proto.wsman.GPURequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * optional string timeout = 10;
- * @return {string}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GPURequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GPURequest}
  */
-proto.wsman.StartWorkspaceSpec.prototype.getTimeout = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+proto.wsman.GPURequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setCount(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setType(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.StartWorkspaceSpec.prototype.setTimeout = function(value) {
-  return jspb.Message.setProto3StringField(this, 10, value);
+proto.wsman.GPURequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GPURequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * optional AdmissionLevel admission = 11;
- * @return {!proto.wsman.AdmissionLevel}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GPURequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceSpec.prototype.getAdmission = function() {
-  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 11, 0));
+proto.wsman.GPURequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCount();
+  if (f !== 0) {
+    writer.writeUint32(
+      1,
+      f
+    );
+  }
+  f = message.getType();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
 };
 
 
 /**
- * @param {!proto.wsman.AdmissionLevel} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * optional uint32 count = 1;
+ * @return {number}
  */
-proto.wsman.StartWorkspaceSpec.prototype.setAdmission = function(value) {
-  return jspb.Message.setProto3EnumField(this, 11, value);
+proto.wsman.GPURequest.prototype.getCount = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * optional IDEImage ide_image = 12;
- * @return {?proto.wsman.IDEImage}
+ * @param {number} value
+ * @return {!proto.wsman.GPURequest} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.getIdeImage = function() {
-  return /** @type{?proto.wsman.IDEImage} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.IDEImage, 12));
-};
-
-
-/**
- * @param {?proto.wsman.IDEImage|undefined} value
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
-*/
-proto.wsman.StartWorkspaceSpec.prototype.setIdeImage = function(value) {
-  return jspb.Message.setWrapperField(this, 12, value);
+proto.wsman.GPURequest.prototype.setCount = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.wsman.StartWorkspaceSpec} returns this
+ * optional string type = 2;
+ * @return {string}
  */
-proto.wsman.StartWorkspaceSpec.prototype.clearIdeImage = function() {
-  return this.setIdeImage(undefined);
+proto.wsman.GPURequest.prototype.getType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.wsman.GPURequest} returns this
  */
-proto.wsman.StartWorkspaceSpec.prototype.hasIdeImage = function() {
-  return jspb.Message.getField(this, 12) != null;
+proto.wsman.GPURequest.prototype.setType = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
@@ -7938,6 +11710,224 @@ proto.wsman.ExposedPorts.prototype.clearPortsList = function() {
 };
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.PortHeaders.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.PortHeaders.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.PortHeaders.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.PortHeaders} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PortHeaders.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    corsAllowedOriginsList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f,
+    xFrameOptions: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    customMap: (f = msg.getCustomMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.PortHeaders}
+ */
+proto.wsman.PortHeaders.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.PortHeaders;
+  return proto.wsman.PortHeaders.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.PortHeaders} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.PortHeaders}
+ */
+proto.wsman.PortHeaders.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addCorsAllowedOrigins(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setXFrameOptions(value);
+      break;
+    case 3:
+      var value = msg.getCustomMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.PortHeaders.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.PortHeaders.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.PortHeaders} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PortHeaders.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getCorsAllowedOriginsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
+  f = message.getXFrameOptions();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getCustomMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * repeated string cors_allowed_origins = 1;
+ * @return {!Array<string>}
+ */
+proto.wsman.PortHeaders.prototype.getCorsAllowedOriginsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.wsman.PortHeaders} returns this
+ */
+proto.wsman.PortHeaders.prototype.setCorsAllowedOriginsList = function(value) {
+  return jspb.Message.setField(this, 1, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PortHeaders} returns this
+ */
+proto.wsman.PortHeaders.prototype.addCorsAllowedOrigins = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.PortHeaders} returns this
+ */
+proto.wsman.PortHeaders.prototype.clearCorsAllowedOriginsList = function() {
+  return this.setCorsAllowedOriginsList([]);
+};
+
+
+/**
+ * optional string x_frame_options = 2;
+ * @return {string}
+ */
+proto.wsman.PortHeaders.prototype.getXFrameOptions = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.wsman.PortHeaders} returns this
+ */
+proto.wsman.PortHeaders.prototype.setXFrameOptions = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * map<string, string> custom = 3;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.PortHeaders.prototype.getCustomMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.wsman.PortHeaders} returns this
+ */
+proto.wsman.PortHeaders.prototype.clearCustomMap = function() {
+  this.getCustomMap().clear();
+  return this;};
+
+
 /**
  * @enum {number}
  */
@@ -7954,12 +11944,51 @@ proto.wsman.AdmissionLevel = {
   ADMIT_EVERYONE: 1
 };
 
+/**
+ * @enum {number}
+ */
+proto.wsman.DrainNodeStep = {
+  NODE_CORDONED: 0,
+  WORKSPACE_BACKED_UP: 1,
+  WORKSPACE_BACKUP_FAILED: 2,
+  WORKSPACE_STOPPED: 3,
+  WORKSPACE_STOP_FAILED: 4,
+  NODE_DRAINED: 5
+};
+
+/**
+ * @enum {number}
+ */
+proto.wsman.ClusterFeature = {
+  NO_CLUSTER_FEATURE: 0,
+  PVC_BACKUPS: 1,
+  GPUS: 2,
+  DRY_RUN_STARTS: 3
+};
+
 /**
  * @enum {number}
  */
 proto.wsman.PortVisibility = {
   PORT_VISIBILITY_PRIVATE: 0,
-  PORT_VISIBILITY_PUBLIC: 1
+  PORT_VISIBILITY_PUBLIC: 1,
+  PORT_VISIBILITY_AUTHENTICATED: 2
+};
+
+/**
+ * @enum {number}
+ */
+proto.wsman.StopReason = {
+  STOP_REASON_NONE: 0,
+  STOP_REASON_USER_REQUEST: 1,
+  STOP_REASON_ADMIN_REQUEST: 2,
+  STOP_REASON_TIMEOUT: 3,
+  STOP_REASON_EVICTED: 4,
+  STOP_REASON_PENALTY: 5,
+  STOP_REASON_BACKUP_FAILED: 6,
+  STOP_REASON_FAILED: 7,
+  STOP_REASON_PAUSED: 8,
+  STOP_REASON_COMPLETED: 9
 };
 
 /**
@@ -6,7 +6,7 @@
 
 
 import { WorkspaceManagerClient } from "./core_grpc_pb";
-import { ControlPortRequest, ControlPortResponse, DescribeWorkspaceRequest, DescribeWorkspaceResponse, MarkActiveRequest, MarkActiveResponse, StartWorkspaceRequest, StartWorkspaceResponse, StopWorkspaceRequest, StopWorkspaceResponse, GetWorkspacesRequest, GetWorkspacesResponse, TakeSnapshotRequest, SetTimeoutRequest, SetTimeoutResponse, SubscribeRequest, SubscribeResponse, ControlAdmissionRequest, ControlAdmissionResponse, TakeSnapshotResponse } from "./core_pb";
+import { ControlPortRequest, ControlPortResponse, DescribeWorkspaceRequest, DescribeWorkspaceResponse, MarkActiveRequest, MarkActiveResponse, StartWorkspaceRequest, StartWorkspaceResponse, StopWorkspaceRequest, StopWorkspaceResponse, GetWorkspacesRequest, GetWorkspacesResponse, TakeSnapshotRequest, SetTimeoutRequest, SetTimeoutResponse, SubscribeRequest, SubscribeResponse, ControlAdmissionRequest, ControlAdmissionResponse, TakeSnapshotResponse, DescribeClusterRequest, DescribeClusterResponse, PauseWorkspaceRequest, PauseWorkspaceResponse, ResumeWorkspaceRequest, ResumeWorkspaceResponse } from "./core_pb";
 import { TraceContext } from '@gitpod/gitpod-protocol/lib/util/tracing';
 import * as opentracing from 'opentracing';
 import * as grpc from "@grpc/grpc-js";
@@ -209,6 +209,51 @@ export class PromisifiedWorkspaceManagerClient implements Disposable {
         }));
     }
 
+    public describeCluster(ctx: TraceContext, request: DescribeClusterRequest): Promise<DescribeClusterResponse> {
+        return this.retryIfUnavailable((attempt: number) => new Promise<DescribeClusterResponse>((resolve, reject) => {
+            const span = TraceContext.startSpan(`/ws-manager/describeCluster`, ctx);
+            span.log({attempt});
+            this.client.describeCluster(request, withTracing({span}), this.getDefaultUnaryOptions(), (err, resp) => {
+                span.finish();
+                if (err) {
+                    reject(err);
+                } else {
+                    resolve(resp);
+                }
+            });
+        }));
+    }
+
+    public pauseWorkspace(ctx: TraceContext, request: PauseWorkspaceRequest): Promise<PauseWorkspaceResponse> {
+        return this.retryIfUnavailable((attempt: number) => new Promise<PauseWorkspaceResponse>((resolve, reject) => {
+            const span = TraceContext.startSpan(`/ws-manager/pauseWorkspace`, ctx);
+            span.log({attempt});
+            this.client.pauseWorkspace(request, withTracing({span}), this.getDefaultUnaryOptions(), (err, resp) => {
+                span.finish();
+                if (err) {
+                    reject(err);
+                } else {
+                    resolve(resp);
+                }
+            });
+        }));
+    }
+
+    public resumeWorkspace(ctx: TraceContext, request: ResumeWorkspaceRequest): Promise<ResumeWorkspaceResponse> {
+        return this.retryIfUnavailable((attempt: number) => new Promise<ResumeWorkspaceResponse>((resolve, reject) => {
+            const span = TraceContext.startSpan(`/ws-manager/resumeWorkspace`, ctx);
+            span.log({attempt});
+            this.client.resumeWorkspace(request, withTracing({span}), this.getDefaultUnaryOptions(), (err, resp) => {
+                span.finish();
+                if (err) {
+                    reject(err);
+                } else {
+                    resolve(resp);
+                }
+            });
+        }));
+    }
+
     public subscribe(ctx: TraceContext, request: SubscribeRequest): Promise<grpc.ClientReadableStream<SubscribeResponse>> {
         return new Promise<grpc.ClientReadableStream<SubscribeResponse>>((resolve, reject) => {
             const span = TraceContext.startSpan(`/ws-manager/subscribe`, ctx);
@@ -186,6 +186,7 @@ export class WorkspaceManagerBridge implements Disposable {
             instance.status.conditions.firstUserActivity = mapFirstUserActivity(rawStatus.getConditions()!.getFirstUserActivity());
             instance.status.conditions.headlessTaskFailed = status.conditions.headlessTaskFailed;
             instance.status.conditions.stoppedByRequest = toBool(status.conditions.stoppedByRequest);
+            instance.status.conditions.evicted = status.conditions.evicted || undefined;
             instance.status.message = status.message;
             instance.status.nodeName = instance.status.nodeName || status.runtime?.nodeName;
             instance.status.podName = instance.status.podName || status.runtime?.podName;
@@ -396,6 +397,8 @@ const mapPortVisibility = (visibility: WsManPortVisibility | undefined): PortVis
             return "private";
         case WsManPortVisibility.PORT_VISIBILITY_PUBLIC:
             return "public";
+        case WsManPortVisibility.PORT_VISIBILITY_AUTHENTICATED:
+            return "authenticated";
     }
 };
 
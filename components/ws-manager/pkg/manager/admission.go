@@ -0,0 +1,134 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+// defaultStartAdmissionTimeout is the time a start admission endpoint has if the configuration does not say otherwise
+const defaultStartAdmissionTimeout = 5 * time.Second
+
+// StartAdmitter decides whether a workspace may start. It can also modify the start request,
+// e.g. to remove environment variables some users must not set.
+type StartAdmitter interface {
+	// Admit returns the request the workspace is to be started with, or an AdmissionRejectedError
+	// if the workspace must not start.
+	Admit(ctx context.Context, req *api.StartWorkspaceRequest) (*api.StartWorkspaceRequest, error)
+}
+
+// AdmissionRejectedError is returned by a StartAdmitter which rejects a workspace start
+type AdmissionRejectedError struct {
+	Reason string
+}
+
+func (e *AdmissionRejectedError) Error() string {
+	if e.Reason == "" {
+		return "workspace start was rejected"
+	}
+	return fmt.Sprintf("workspace start was rejected: %s", e.Reason)
+}
+
+// startAdmissionRequest is what we send to a start admission endpoint
+type startAdmissionRequest struct {
+	Request json.RawMessage `json:"request"`
+}
+
+// startAdmissionResponse is what a start admission endpoint answers with. If Request is set,
+// the workspace is started with that request instead of the original one.
+type startAdmissionResponse struct {
+	Allowed bool            `json:"allowed"`
+	Reason  string          `json:"reason,omitempty"`
+	Request json.RawMessage `json:"request,omitempty"`
+}
+
+// httpStartAdmitter admits workspace starts by asking an external HTTP policy endpoint
+type httpStartAdmitter struct {
+	Config config.StartAdmissionConfiguration
+	Client *http.Client
+}
+
+func newHTTPStartAdmitter(cfg config.StartAdmissionConfiguration) *httpStartAdmitter {
+	timeout := time.Duration(cfg.Timeout)
+	if timeout == 0 {
+		timeout = defaultStartAdmissionTimeout
+	}
+	return &httpStartAdmitter{
+		Config: cfg,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Admit asks the policy endpoint whether the workspace may start
+func (a *httpStartAdmitter) Admit(ctx context.Context, req *api.StartWorkspaceRequest) (*api.StartWorkspaceRequest, error) {
+	resp, err := a.ask(ctx, req)
+	if err != nil {
+		if a.Config.FailOpen {
+			log.WithError(err).WithFields(log.OWI(req.Metadata.GetOwner(), req.Metadata.GetMetaId(), req.Id)).Warn("start admission endpoint failed - admitting workspace anyways")
+			return req, nil
+		}
+		return nil, xerrors.Errorf("cannot admit workspace start: %w", err)
+	}
+	if !resp.Allowed {
+		return nil, &AdmissionRejectedError{Reason: resp.Reason}
+	}
+	if len(resp.Request) == 0 {
+		return req, nil
+	}
+
+	var mutated api.StartWorkspaceRequest
+	err = protojson.Unmarshal(resp.Request, &mutated)
+	if err != nil {
+		return nil, xerrors.Errorf("start admission endpoint returned an invalid request: %w", err)
+	}
+	return &mutated, nil
+}
+
+func (a *httpStartAdmitter) ask(ctx context.Context, req *api.StartWorkspaceRequest) (*startAdmissionResponse, error) {
+	sreq, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(startAdmissionRequest{Request: sreq})
+	if err != nil {
+		return nil, err
+	}
+
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	hresp, err := a.Client.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer hresp.Body.Close()
+
+	if hresp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(hresp.Body, 1024))
+		return nil, xerrors.Errorf("endpoint responded with %d: %s", hresp.StatusCode, string(msg))
+	}
+
+	var resp startAdmissionResponse
+	err = json.NewDecoder(hresp.Body).Decode(&resp)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode response: %w", err)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+func TestHTTPStartAdmitter(t *testing.T) {
+	req := &api.StartWorkspaceRequest{
+		Id:       "foobar",
+		Metadata: &api.WorkspaceMetadata{Owner: "owner"},
+		Spec: &api.StartWorkspaceSpec{
+			Envvars: []*api.EnvironmentVariable{{Name: "FOO", Value: "bar"}},
+		},
+	}
+	mutated := &api.StartWorkspaceRequest{
+		Id:       "foobar",
+		Metadata: &api.WorkspaceMetadata{Owner: "owner"},
+		Spec:     &api.StartWorkspaceSpec{},
+	}
+
+	tests := []struct {
+		Name         string
+		Status       int
+		Response     string
+		FailOpen     bool
+		Expectation  *api.StartWorkspaceRequest
+		ExpectReject bool
+		ExpectError  bool
+	}{
+		{Name: "allowed", Status: http.StatusOK, Response: `{"allowed":true}`, Expectation: req},
+		{Name: "rejected", Status: http.StatusOK, Response: `{"allowed":false,"reason":"no"}`, ExpectReject: true},
+		{Name: "mutated", Status: http.StatusOK, Response: `{"allowed":true,"request":{"id":"foobar","metadata":{"owner":"owner"},"spec":{}}}`, Expectation: mutated},
+		{Name: "invalid request", Status: http.StatusOK, Response: `{"allowed":true,"request":{"id":42}}`, ExpectError: true},
+		{Name: "failure", Status: http.StatusInternalServerError, Response: "oops", ExpectError: true},
+		{Name: "failure fail open", Status: http.StatusInternalServerError, Response: "oops", FailOpen: true, Expectation: req},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body startAdmissionRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Request) == 0 {
+					t.Errorf("admission endpoint received invalid body: %v", err)
+				}
+				w.WriteHeader(test.Status)
+				_, _ = w.Write([]byte(test.Response))
+			}))
+			defer srv.Close()
+
+			admitter := newHTTPStartAdmitter(config.StartAdmissionConfiguration{URL: srv.URL, FailOpen: test.FailOpen})
+			act, err := admitter.Admit(context.Background(), req)
+
+			var rejected *AdmissionRejectedError
+			if isRejected := xerrors.As(err, &rejected); isRejected != test.ExpectReject {
+				t.Errorf("unexpected rejection: %v", err)
+			}
+			if (err != nil && !test.ExpectReject) != test.ExpectError {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expectation, act, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected admitted request (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
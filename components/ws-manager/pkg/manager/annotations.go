@@ -55,12 +55,30 @@ const (
 	// This is handy if you want to prevent a workspace from timing out during lunch break.
 	customTimeoutAnnotation = "gitpod/customTimeout"
 
+	// workspaceClassAnnotation contains the name of the workspace class a workspace was started with
+	workspaceClassAnnotation = "gitpod/workspaceClass"
+
+	// gpuCountAnnotation contains the number of GPUs a workspace requested
+	gpuCountAnnotation = "gitpod/gpuCount"
+
+	// gpuTypeAnnotation contains the type of GPUs a workspace requested
+	gpuTypeAnnotation = "gitpod/gpuType"
+
 	// firstUserActivityAnnotation marks a workspace woth the timestamp of first user activity in it
 	firstUserActivityAnnotation = "gitpod/firstUserActivity"
 
 	// fullWorkspaceBackupAnnotation is set on workspaces which operate using a full workspace backup
 	fullWorkspaceBackupAnnotation = "gitpod/fullWorkspaceBackup"
 
+	// pvcWorkspaceFeatureAnnotation is set on workspaces which store their content on a persistent volume claim
+	pvcWorkspaceFeatureAnnotation = "gitpod/pvcFeature"
+
+	// volumeSnapshotRestoreAnnotation contains the name of the volume snapshot a workspace's persistent volume claim was restored from
+	volumeSnapshotRestoreAnnotation = "gitpod/restoredVolumeSnapshot"
+
+	// volumeSnapshotAnnotation contains the name of the volume snapshot taken of a workspace's persistent volume claim when it stopped
+	volumeSnapshotAnnotation = "gitpod/volumeSnapshot"
+
 	// gitpodFinalizerName is the name of the Gitpod finalizer we use to clean up a workspace
 	gitpodFinalizerName = "gitpod.io/finalizer"
 
@@ -75,6 +93,18 @@ const (
 
 	// stoppedByRequestAnnotation is set on a pod when it was requested to stop using a StopWorkspace call
 	stoppedByRequestAnnotation = "gitpod.io/stoppedByRequest"
+
+	// workspaceEvictedAnnotation is set on a pod when it was evicted or preempted from its node and contains the reason why
+	workspaceEvictedAnnotation = "gitpod.io/evicted"
+
+	// snapshotPolicyAnnotation contains the JSON serialized policy by which we take periodic snapshots of a running workspace
+	snapshotPolicyAnnotation = "gitpod/snapshotPolicy"
+
+	// scheduledSnapshotsAnnotation contains a JSON list of the periodic snapshots taken of a workspace, oldest first
+	scheduledSnapshotsAnnotation = "gitpod/scheduledSnapshots"
+
+	// workspacePausedAnnotation is set on a pod when it was requested to stop using a PauseWorkspace call
+	workspacePausedAnnotation = "gitpod.io/paused"
 )
 
 // markWorkspaceAsReady adds annotations to a workspace pod
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+// DescribeCluster describes the cluster this workspace manager operates in
+func (m *Manager) DescribeCluster(ctx context.Context, req *api.DescribeClusterRequest) (res *api.DescribeClusterResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "DescribeCluster")
+	defer tracing.FinishSpan(span, &err)
+
+	tpl, err := config.GetWorkspacePodTemplate(m.Config.WorkspacePodTemplate.DefaultPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot read pod template - this is a configuration problem: %q", err)
+	}
+	nodes, err := m.RawClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot list nodes: %q", err)
+	}
+	pods, err := m.RawClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot list pods: %q", err)
+	}
+	var workspaces corev1.PodList
+	err = m.Clientset.List(ctx, &workspaces, workspaceObjectListOptions(m.Config.Namespace))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot list workspaces: %q", err)
+	}
+
+	capacity := describeClusterCapacity(tpl, m.Config.GPU, nodes.Items, pods.Items)
+	capacity.Workspaces = uint32(len(workspaces.Items))
+
+	return &api.DescribeClusterResponse{
+		Region:                m.Config.Region,
+		WorkspaceClusterHost:  m.Config.WorkspaceClusterHost,
+		DefaultWorkspaceClass: m.Config.DefaultWorkspaceClass,
+		Capacity:              capacity,
+		WorkspaceClasses:      describeWorkspaceClasses(m.Config.WorkspaceClasses),
+		Features:              describeClusterFeatures(&m.Config),
+	}, nil
+}
+
+// describeClusterCapacity sums up the resources of the nodes workspaces can run on, i.e. ready and schedulable nodes
+// which match the node selector and required node affinity of the workspace pod template.
+func describeClusterCapacity(tpl *corev1.Pod, gpu *config.GPUConfiguration, nodes []corev1.Node, pods []corev1.Pod) *api.ClusterCapacity {
+	var (
+		res         api.ClusterCapacity
+		allocatable = make(corev1.ResourceList)
+		requested   = make(corev1.ResourceList)
+		gpus        resource.Quantity
+		idx         = make(map[string]struct{})
+	)
+	for i := range nodes {
+		node := &nodes[i]
+		if !isWorkspaceNode(tpl, node) {
+			continue
+		}
+		idx[node.Name] = struct{}{}
+		res.Nodes++
+		addResources(allocatable, node.Status.Allocatable)
+		if gpu != nil {
+			gpus.Add(node.Status.Allocatable[corev1.ResourceName(gpu.GetResourceName())])
+		}
+	}
+	for i := range pods {
+		p := &pods[i]
+		if _, ok := idx[p.Spec.NodeName]; !ok {
+			continue
+		}
+		addResources(requested, podRequests(p))
+	}
+
+	res.AllocatableCpu = allocatable.Cpu().String()
+	res.AllocatableMemory = allocatable.Memory().String()
+	res.RequestedCpu = requested.Cpu().String()
+	res.RequestedMemory = requested.Memory().String()
+	res.AllocatableGpus = uint32(gpus.Value())
+	return &res
+}
+
+// isWorkspaceNode returns true if workspaces can be scheduled on the node
+func isWorkspaceNode(tpl *corev1.Pod, node *corev1.Node) bool {
+	if node.Spec.Unschedulable || !isNodeReady(node) {
+		return false
+	}
+	if tpl == nil {
+		return true
+	}
+	for k, v := range tpl.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	return matchesRequiredNodeAffinity(tpl, node)
+}
+
+// describeWorkspaceClasses lists the workspace classes ordered by name
+func describeWorkspaceClasses(classes map[string]*config.WorkspaceClass) []*api.WorkspaceClassDescription {
+	res := make([]*api.WorkspaceClassDescription, 0, len(classes))
+	for name, class := range classes {
+		res = append(res, &api.WorkspaceClassDescription{
+			Name:   name,
+			Cpu:    class.Requests.CPU,
+			Memory: class.Requests.Memory,
+			Pvc:    class.PVC != nil,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// describeClusterFeatures lists the optional features the configuration enables
+func describeClusterFeatures(cfg *config.Configuration) []api.ClusterFeature {
+	res := []api.ClusterFeature{api.ClusterFeature_DRY_RUN_STARTS}
+	for _, class := range cfg.WorkspaceClasses {
+		if class.PVC != nil {
+			res = append(res, api.ClusterFeature_PVC_BACKUPS)
+			break
+		}
+	}
+	if cfg.GPU != nil {
+		res = append(res, api.ClusterFeature_GPUS)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+func TestDescribeClusterCapacity(t *testing.T) {
+	node := func(name string, ready bool, labels map[string]string) corev1.Node {
+		status := corev1.ConditionTrue
+		if !ready {
+			status = corev1.ConditionFalse
+		}
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:                    resource.MustParse("4"),
+					corev1.ResourceMemory:                 resource.MustParse("16Gi"),
+					corev1.ResourceName("nvidia.com/gpu"): resource.MustParse("1"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			},
+		}
+	}
+	pod := func(nodeName, cpu string) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}}},
+		}}
+	}
+	workspaceNode := map[string]string{"gitpod.io/workload_workspace": "true"}
+	tpl := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: workspaceNode}}
+
+	tests := []struct {
+		Name        string
+		Template    *corev1.Pod
+		GPU         *config.GPUConfiguration
+		Nodes       []corev1.Node
+		Pods        []corev1.Pod
+		Expectation *api.ClusterCapacity
+	}{
+		{
+			Name:        "no nodes",
+			Expectation: &api.ClusterCapacity{AllocatableCpu: "0", AllocatableMemory: "0", RequestedCpu: "0", RequestedMemory: "0"},
+		},
+		{
+			Name:     "workspace nodes only",
+			Template: tpl,
+			GPU:      &config.GPUConfiguration{MaxCount: 1},
+			Nodes: []corev1.Node{
+				node("ws-a", true, workspaceNode),
+				node("ws-b", true, workspaceNode),
+				node("ws-c", false, workspaceNode),
+				node("services", true, nil),
+			},
+			Pods: []corev1.Pod{pod("ws-a", "500m"), pod("ws-b", "1"), pod("services", "2")},
+			Expectation: &api.ClusterCapacity{
+				Nodes:             2,
+				AllocatableCpu:    "8",
+				AllocatableMemory: "32Gi",
+				RequestedCpu:      "1500m",
+				RequestedMemory:   "0",
+				AllocatableGpus:   2,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := describeClusterCapacity(test.Template, test.GPU, test.Nodes, test.Pods)
+			if diff := cmp.Diff(test.Expectation, act, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected capacity (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDescribeClusterFeatures(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      config.Configuration
+		Expectation []api.ClusterFeature
+	}{
+		{Name: "defaults", Expectation: []api.ClusterFeature{api.ClusterFeature_DRY_RUN_STARTS}},
+		{
+			Name: "pvc and gpus",
+			Config: config.Configuration{
+				WorkspaceClasses: map[string]*config.WorkspaceClass{
+					"default": {},
+					"pvc":     {PVC: &config.PVCConfiguration{}},
+				},
+				GPU: &config.GPUConfiguration{MaxCount: 1},
+			},
+			Expectation: []api.ClusterFeature{api.ClusterFeature_PVC_BACKUPS, api.ClusterFeature_GPUS, api.ClusterFeature_DRY_RUN_STARTS},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := describeClusterFeatures(&test.Config)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected features (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +23,7 @@ import (
 	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -67,6 +70,19 @@ func (m *Manager) createWorkspacePod(startContext *startWorkspaceContext) (*core
 			return nil, xerrors.Errorf("cannot apply type-specific pod template: %w", err)
 		}
 	}
+	if gpuCount(startContext.Request) > 0 {
+		// the GPU template is where clusters mount the driver libraries and devices into the workspace
+		gpuTpl, err := config.GetWorkspacePodTemplate(m.Config.WorkspacePodTemplate.GPUPath)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read GPU pod template - this is a configuration problem: %w", err)
+		}
+		if gpuTpl != nil {
+			err = combineDefiniteWorkspacePodWithTemplate(podTemplate, gpuTpl)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot apply GPU pod template: %w", err)
+			}
+		}
+	}
 
 	pod, err := m.createDefiniteWorkspacePod(startContext)
 	if err != nil {
@@ -76,6 +92,10 @@ func (m *Manager) createWorkspacePod(startContext *startWorkspaceContext) (*core
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create workspace pod: %w", err)
 	}
+	err = injectContainers(pod, startContext.Request.Type, m.Config.InjectedContainers)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot inject containers: %w", err)
+	}
 	return pod, nil
 }
 
@@ -233,6 +253,10 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 
 	labels := make(map[string]string)
 	labels["gitpod.io/networkpolicy"] = "default"
+	if m.Config.NetworkPolicy != nil {
+		// the workspace gets its own network policy instead, see newWorkspaceNetworkPolicy
+		labels["gitpod.io/networkpolicy"] = "workspace"
+	}
 	for k, v := range startContext.Labels {
 		labels[k] = v
 	}
@@ -325,6 +349,31 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 			return nil, xerrors.Errorf("invalid workspace timeout \"%s\": %w", req.Spec.Timeout, err)
 		}
 		annotations[customTimeoutAnnotation] = req.Spec.Timeout
+	} else if startContext.Class != nil && startContext.Class.Timeout > 0 {
+		annotations[customTimeoutAnnotation] = time.Duration(startContext.Class.Timeout).String()
+	}
+	if req.Spec.WorkspaceClass != "" {
+		annotations[workspaceClassAnnotation] = req.Spec.WorkspaceClass
+	} else if m.Config.DefaultWorkspaceClass != "" {
+		annotations[workspaceClassAnnotation] = m.Config.DefaultWorkspaceClass
+	}
+	if startContext.Class != nil && startContext.Class.IOLimits != nil {
+		limits := startContext.Class.IOLimits
+		annotations[wsk8s.IOBandwidthAnnotation] = limits.Bandwidth
+		if limits.BurstBandwidth != "" {
+			annotations[wsk8s.IOBurstBandwidthAnnotation] = limits.BurstBandwidth
+			annotations[wsk8s.IOBurstAllowanceAnnotation] = limits.BurstAllowance
+		}
+	}
+	if startContext.Class != nil && startContext.Class.EgressBandwidth != "" {
+		annotations[wsk8s.EgressBandwidthAnnotation] = startContext.Class.EgressBandwidth
+	}
+	if startContext.SnapshotPolicy != nil {
+		policy, err := json.Marshal(startContext.SnapshotPolicy)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot serialize snapshot policy: %w", err)
+		}
+		annotations[snapshotPolicyAnnotation] = string(policy)
 	}
 	for k, v := range req.Metadata.Annotations {
 		annotations[workspaceAnnotationPrefix+k] = v
@@ -390,6 +439,7 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 			SchedulerName:                m.Config.SchedulerName,
 			EnableServiceLinks:           &boolFalse,
 			Affinity:                     affinity,
+			NodeSelector:                 workspaceClassNodeSelector(startContext.Class),
 			Containers: []corev1.Container{
 				*workspaceContainer,
 			},
@@ -459,6 +509,53 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 		}
 	}
 
+	if startContext.Class != nil && startContext.Class.PVC != nil {
+		if _, fwb := pod.Labels[fullWorkspaceBackupAnnotation]; fwb {
+			return nil, xerrors.Errorf("full workspace backup is not supported for workspaces with a persistent volume claim")
+		}
+
+		// the workspace content lives on a persistent volume claim named after the pod, see createWorkspacePVC
+		removeVolume(&pod, workspaceVolumeName)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: workspaceVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pod.Name,
+				},
+			},
+		})
+		pod.Labels[pvcWorkspaceFeatureAnnotation] = "true"
+		pod.Annotations[pvcWorkspaceFeatureAnnotation] = "true"
+		if req.Spec.VolumeSnapshot != "" {
+			pod.Annotations[volumeSnapshotRestoreAnnotation] = req.Spec.VolumeSnapshot
+		}
+	}
+
+	applySchedulingHints(&pod, m.Config.SchedulingHints[workspaceTypeName(req.Type)])
+	if startContext.Class != nil {
+		applySchedulingHints(&pod, startContext.Class.SchedulingHints)
+	}
+
+	if count := gpuCount(req); count > 0 {
+		resourceName := m.Config.GPU.GetResourceName()
+		if pod.Spec.NodeSelector == nil {
+			pod.Spec.NodeSelector = make(map[string]string)
+		}
+		for k, v := range m.Config.GPU.Types[req.Spec.Gpu.Type] {
+			pod.Spec.NodeSelector[k] = v
+		}
+		// GPU nodes are usually tainted so that regular workspaces don't occupy them
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+			Key:      resourceName,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+		pod.Annotations[gpuCountAnnotation] = fmt.Sprint(count)
+		if req.Spec.Gpu.Type != "" {
+			pod.Annotations[gpuTypeAnnotation] = req.Spec.Gpu.Type
+		}
+	}
+
 	if req.Type == api.WorkspaceType_IMAGEBUILD {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
 			Name: "gitpod-ca-certificate",
@@ -476,6 +573,53 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 	return &pod, nil
 }
 
+// createWorkspacePVC produces the persistent volume claim the content of a workspace lives on.
+// Returns nil if the workspace's class doesn't use persistent volume claims.
+func (m *Manager) createWorkspacePVC(startContext *startWorkspaceContext, pod *corev1.Pod) (*corev1.PersistentVolumeClaim, error) {
+	if startContext.Class == nil || startContext.Class.PVC == nil {
+		return nil, nil
+	}
+	cfg := startContext.Class.PVC
+
+	size, err := resource.ParseQuantity(cfg.Size)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse persistent volume claim size: %w", err)
+	}
+
+	labels := make(map[string]string, len(pod.Labels))
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if cfg.StorageClass != "" {
+		storageClass := cfg.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	if snapshot := startContext.Request.Spec.VolumeSnapshot; snapshot != "" {
+		apiGroup := volumeSnapshotGroup
+		pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     volumeSnapshotKind,
+			Name:     snapshot,
+		}
+	}
+	return pvc, nil
+}
+
 func removeVolume(pod *corev1.Pod, name string) {
 	var vols []corev1.Volume
 	for _, v := range pod.Spec.Volumes {
@@ -498,15 +642,188 @@ func removeVolume(pod *corev1.Pod, name string) {
 	}
 }
 
+// workspaceClassNodeSelector returns the node selector of a workspace class, or nil if there is none
+func workspaceClassNodeSelector(class *config.WorkspaceClass) map[string]string {
+	if class == nil || len(class.NodeSelector) == 0 {
+		return nil
+	}
+	res := make(map[string]string, len(class.NodeSelector))
+	for k, v := range class.NodeSelector {
+		res[k] = v
+	}
+	return res
+}
+
+// workspaceTypeName returns the name of a workspace type as used in labels and configuration
+func workspaceTypeName(tpe api.WorkspaceType) string {
+	return strings.ToLower(api.WorkspaceType_name[int32(tpe)])
+}
+
+// applySchedulingHints adds configured scheduling hints to the affinity, tolerations and topology spread constraints of a pod
+func applySchedulingHints(pod *corev1.Pod, hints *config.SchedulingHints) {
+	if hints == nil {
+		return
+	}
+
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, hints.Tolerations...)
+	pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, hints.TopologySpreadConstraints...)
+
+	if hints.Affinity == nil {
+		return
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	affinity := pod.Spec.Affinity
+	if na := hints.Affinity.NodeAffinity; na != nil {
+		if affinity.NodeAffinity == nil {
+			affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = andNodeSelectors(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, na.RequiredDuringSchedulingIgnoredDuringExecution)
+		affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, na.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+	if pa := hints.Affinity.PodAffinity; pa != nil {
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, pa.RequiredDuringSchedulingIgnoredDuringExecution...)
+		affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+	if paa := hints.Affinity.PodAntiAffinity; paa != nil {
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, paa.RequiredDuringSchedulingIgnoredDuringExecution...)
+		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+}
+
+// injectContainers adds the configured init containers and sidecars to a workspace pod. Init containers run after
+// those of the pod template, sidecars run next to the workspace container.
+func injectContainers(pod *corev1.Pod, tpe api.WorkspaceType, containers []config.InjectedContainer) error {
+	var injected []config.InjectedContainer
+	for _, c := range containers {
+		if len(c.WorkspaceTypes) == 0 {
+			injected = append(injected, c)
+			continue
+		}
+		for _, t := range c.WorkspaceTypes {
+			if strings.EqualFold(t, workspaceTypeName(tpe)) {
+				injected = append(injected, c)
+				break
+			}
+		}
+	}
+	sort.SliceStable(injected, func(i, j int) bool { return injected[i].Order < injected[j].Order })
+
+	for _, c := range injected {
+		container := *c.Container.DeepCopy()
+		requests, err := c.Requests.ResourceList()
+		if err != nil {
+			return xerrors.Errorf("%s: cannot parse requests: %w", container.Name, err)
+		}
+		limits, err := c.Limits.ResourceList()
+		if err != nil {
+			return xerrors.Errorf("%s: cannot parse limits: %w", container.Name, err)
+		}
+		container.Resources = corev1.ResourceRequirements{Requests: requests, Limits: limits}
+
+		if c.Init {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, container)
+		} else {
+			pod.Spec.Containers = append(pod.Spec.Containers, container)
+		}
+	}
+	return nil
+}
+
+// andNodeSelectors produces a node selector which matches nodes that match both a and b.
+// The terms of a node selector are ORed, hence we combine every term of a with every term of b.
+func andNodeSelectors(a, b *corev1.NodeSelector) *corev1.NodeSelector {
+	if a == nil || len(a.NodeSelectorTerms) == 0 {
+		return b
+	}
+	if b == nil || len(b.NodeSelectorTerms) == 0 {
+		return a
+	}
+
+	res := &corev1.NodeSelector{}
+	for _, ta := range a.NodeSelectorTerms {
+		for _, tb := range b.NodeSelectorTerms {
+			var term corev1.NodeSelectorTerm
+			term.MatchExpressions = append(term.MatchExpressions, ta.MatchExpressions...)
+			term.MatchExpressions = append(term.MatchExpressions, tb.MatchExpressions...)
+			term.MatchFields = append(term.MatchFields, ta.MatchFields...)
+			term.MatchFields = append(term.MatchFields, tb.MatchFields...)
+			res.NodeSelectorTerms = append(res.NodeSelectorTerms, term)
+		}
+	}
+	return res
+}
+
+// gpuCount returns the number of GPUs a workspace requests
+func gpuCount(req *api.StartWorkspaceRequest) uint32 {
+	if req.Spec == nil || req.Spec.Gpu == nil {
+		return 0
+	}
+	return req.Spec.Gpu.Count
+}
+
+// validateGPURequest checks a workspace's GPU request against what the cluster offers
+func (m *Manager) validateGPURequest(req *api.StartWorkspaceRequest) error {
+	gpu := req.Spec.Gpu
+	if gpu == nil {
+		return nil
+	}
+	if gpu.Count == 0 {
+		if gpu.Type != "" {
+			return xerrors.Errorf("GPU type %s requested without GPUs", gpu.Type)
+		}
+		return nil
+	}
+	if m.Config.GPU == nil {
+		return xerrors.Errorf("this cluster does not offer GPUs")
+	}
+	if req.Type != api.WorkspaceType_REGULAR && req.Type != api.WorkspaceType_PREBUILD {
+		return xerrors.Errorf("%s workspaces cannot request GPUs", workspaceTypeName(req.Type))
+	}
+	if gpu.Count > m.Config.GPU.MaxCount {
+		return xerrors.Errorf("cannot request more than %d GPUs", m.Config.GPU.MaxCount)
+	}
+	if gpu.Type != "" {
+		if _, ok := m.Config.GPU.Types[gpu.Type]; !ok {
+			return xerrors.Errorf("unknown GPU type: %s", gpu.Type)
+		}
+	}
+	return nil
+}
+
+// workspaceContainerResources returns the resource requests and limits of the workspace container,
+// which are determined by the workspace class if there is one.
+func (m *Manager) workspaceContainerResources(startContext *startWorkspaceContext) (requests, limits config.ResourceConfiguration) {
+	if startContext.Class != nil {
+		return startContext.Class.Requests, startContext.Class.Limits
+	}
+	return m.Config.Container.Workspace.Requests, m.Config.Container.Workspace.Limits
+}
+
 func (m *Manager) createWorkspaceContainer(startContext *startWorkspaceContext) (*corev1.Container, error) {
-	limits, err := m.Config.Container.Workspace.Limits.ResourceList()
+	requestsConfig, limitsConfig := m.workspaceContainerResources(startContext)
+	limits, err := limitsConfig.ResourceList()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot parse workspace container limits: %w", err)
 	}
-	requests, err := m.Config.Container.Workspace.Requests.ResourceList()
+	requests, err := requestsConfig.ResourceList()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot parse workspace container requests: %w", err)
 	}
+	if count := gpuCount(startContext.Request); count > 0 {
+		// extended resources must not be overcommitted, hence their requests must equal their limits
+		gpus := resource.NewQuantity(int64(count), resource.DecimalSI)
+		resourceName := corev1.ResourceName(m.Config.GPU.GetResourceName())
+		limits[resourceName] = *gpus
+		requests[resourceName] = *gpus
+	}
 	env, err := m.createWorkspaceEnvironment(startContext)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create workspace env: %w", err)
@@ -596,6 +913,7 @@ func (m *Manager) createWorkspaceEnvironment(startContext *startWorkspaceContext
 	result = append(result, corev1.EnvVar{Name: "GITPOD_WORKSPACE_URL", Value: startContext.WorkspaceURL})
 	result = append(result, corev1.EnvVar{Name: "GITPOD_WORKSPACE_CLUSTER_HOST", Value: m.Config.WorkspaceClusterHost})
 	result = append(result, corev1.EnvVar{Name: "THEIA_SUPERVISOR_ENDPOINT", Value: fmt.Sprintf(":%d", startContext.SupervisorPort)})
+	result = append(result, corev1.EnvVar{Name: "THEIA_SUPERVISOR_ACCESS_LOG_TOKEN", Value: kubernetes.AccessLogToken(startContext.OwnerToken)})
 	// TODO(ak) remove THEIA_WEBVIEW_EXTERNAL_ENDPOINT and THEIA_MINI_BROWSER_HOST_PATTERN when Theia is removed
 	result = append(result, corev1.EnvVar{Name: "THEIA_WEBVIEW_EXTERNAL_ENDPOINT", Value: "webview-{{hostname}}"})
 	result = append(result, corev1.EnvVar{Name: "THEIA_MINI_BROWSER_HOST_PATTERN", Value: "browser-{{hostname}}"})
@@ -641,7 +959,8 @@ func (m *Manager) createWorkspaceEnvironment(startContext *startWorkspaceContext
 	heartbeatInterval := time.Duration(m.Config.HeartbeatInterval)
 	result = append(result, corev1.EnvVar{Name: "GITPOD_INTERVAL", Value: fmt.Sprintf("%d", int64(heartbeatInterval/time.Millisecond))})
 
-	res, err := m.Config.Container.Workspace.Requests.ResourceList()
+	requests, _ := m.workspaceContainerResources(startContext)
+	res, err := requests.ResourceList()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create environment: %w", err)
 	}
@@ -745,6 +1064,30 @@ func (m *Manager) newStartWorkspaceContext(ctx context.Context, req *api.StartWo
 		return nil, xerrors.Errorf("cannot create owner token: %w", err)
 	}
 
+	class, err := m.Config.GetWorkspaceClass(req.Spec.WorkspaceClass)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot start workspace: %w", err)
+	}
+	if req.Spec.VolumeSnapshot != "" && (class == nil || class.PVC == nil) {
+		return nil, xerrors.Errorf("cannot start workspace: restoring a volume snapshot requires a workspace class with a persistent volume claim")
+	}
+	if err := m.validateGPURequest(req); err != nil {
+		return nil, xerrors.Errorf("cannot start workspace: %w", err)
+	}
+	if req.Spec.Timeout != "" {
+		timeout, err := time.ParseDuration(req.Spec.Timeout)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid workspace timeout \"%s\": %w", req.Spec.Timeout, err)
+		}
+		if err := m.Config.Timeouts.CheckCustom(timeout); err != nil {
+			return nil, xerrors.Errorf("invalid workspace timeout \"%s\": %w", req.Spec.Timeout, err)
+		}
+	}
+	snapshotPolicy, err := getSnapshotPolicy(req, class)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot start workspace: %w", err)
+	}
+
 	workspaceSpan := opentracing.StartSpan("workspace", opentracing.FollowsFrom(opentracing.SpanFromContext(ctx).Context()))
 	traceID := tracing.GetTraceID(workspaceSpan)
 
@@ -767,6 +1110,8 @@ func (m *Manager) newStartWorkspaceContext(ctx context.Context, req *api.StartWo
 		WorkspaceURL:   workspaceURL,
 		TraceID:        traceID,
 		Headless:       headless,
+		Class:          class,
+		SnapshotPolicy: snapshotPolicy,
 	}, nil
 }
 
@@ -10,8 +10,10 @@ import (
 	"testing"
 	"testing/fstest"
 
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/encoding/protojson"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 
 	ctesting "github.com/gitpod-io/gitpod/common-go/testing"
@@ -161,3 +163,171 @@ func TestCreateDefiniteWorkspacePod(t *testing.T) {
 	}
 	test.Run()
 }
+
+func TestValidateGPURequest(t *testing.T) {
+	gpuConfig := &config.GPUConfiguration{
+		MaxCount: 2,
+		Types: map[string]map[string]string{
+			"t4": {"cloud.google.com/gke-accelerator": "nvidia-tesla-t4"},
+		},
+	}
+	tests := []struct {
+		Name        string
+		Config      *config.GPUConfiguration
+		Type        api.WorkspaceType
+		GPU         *api.GPURequest
+		ExpectError bool
+	}{
+		{Name: "no request", Config: nil, Type: api.WorkspaceType_REGULAR},
+		{Name: "zero GPUs", Config: nil, Type: api.WorkspaceType_REGULAR, GPU: &api.GPURequest{}},
+		{Name: "type without GPUs", Config: gpuConfig, Type: api.WorkspaceType_REGULAR, GPU: &api.GPURequest{Type: "t4"}, ExpectError: true},
+		{Name: "no GPU config", Config: nil, Type: api.WorkspaceType_REGULAR, GPU: &api.GPURequest{Count: 1}, ExpectError: true},
+		{Name: "regular", Config: gpuConfig, Type: api.WorkspaceType_REGULAR, GPU: &api.GPURequest{Count: 2}},
+		{Name: "prebuild with type", Config: gpuConfig, Type: api.WorkspaceType_PREBUILD, GPU: &api.GPURequest{Count: 1, Type: "t4"}},
+		{Name: "too many", Config: gpuConfig, Type: api.WorkspaceType_REGULAR, GPU: &api.GPURequest{Count: 3}, ExpectError: true},
+		{Name: "unknown type", Config: gpuConfig, Type: api.WorkspaceType_REGULAR, GPU: &api.GPURequest{Count: 1, Type: "a100"}, ExpectError: true},
+		{Name: "imagebuild", Config: gpuConfig, Type: api.WorkspaceType_IMAGEBUILD, GPU: &api.GPURequest{Count: 1}, ExpectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := forTestingOnlyManagerConfig()
+			cfg.GPU = test.Config
+			manager := &Manager{Config: cfg}
+
+			err := manager.validateGPURequest(&api.StartWorkspaceRequest{
+				Type: test.Type,
+				Spec: &api.StartWorkspaceSpec{Gpu: test.GPU},
+			})
+			if (err != nil) != test.ExpectError {
+				t.Errorf("unexpected error: %v, expected error: %v", err, test.ExpectError)
+			}
+		})
+	}
+}
+
+func TestApplySchedulingHints(t *testing.T) {
+	term := func(keys ...string) corev1.NodeSelectorTerm {
+		var res corev1.NodeSelectorTerm
+		for _, k := range keys {
+			res.MatchExpressions = append(res.MatchExpressions, corev1.NodeSelectorRequirement{Key: k, Operator: corev1.NodeSelectorOpExists})
+		}
+		return res
+	}
+	required := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+			},
+		}
+	}
+	spot := corev1.Toleration{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	spread := corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway}
+
+	tests := []struct {
+		Name        string
+		Pod         corev1.PodSpec
+		Hints       *config.SchedulingHints
+		Expectation corev1.PodSpec
+	}{
+		{
+			Name:        "no hints",
+			Pod:         corev1.PodSpec{Affinity: required(term("a"))},
+			Expectation: corev1.PodSpec{Affinity: required(term("a"))},
+		},
+		{
+			Name:        "tolerations and topology spread constraints",
+			Pod:         corev1.PodSpec{},
+			Hints:       &config.SchedulingHints{Tolerations: []corev1.Toleration{spot}, TopologySpreadConstraints: []corev1.TopologySpreadConstraint{spread}},
+			Expectation: corev1.PodSpec{Tolerations: []corev1.Toleration{spot}, TopologySpreadConstraints: []corev1.TopologySpreadConstraint{spread}},
+		},
+		{
+			Name:        "affinity without pod affinity",
+			Pod:         corev1.PodSpec{},
+			Hints:       &config.SchedulingHints{Affinity: required(term("b"))},
+			Expectation: corev1.PodSpec{Affinity: required(term("b"))},
+		},
+		{
+			Name:        "required node affinity is combined",
+			Pod:         corev1.PodSpec{Affinity: required(term("a"))},
+			Hints:       &config.SchedulingHints{Affinity: required(term("b"), term("c"))},
+			Expectation: corev1.PodSpec{Affinity: required(term("a", "b"), term("a", "c"))},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: test.Pod}
+			applySchedulingHints(pod, test.Hints)
+
+			if diff := cmp.Diff(test.Expectation, pod.Spec); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInjectContainers(t *testing.T) {
+	injected := func(name string, init bool, order int, types ...string) config.InjectedContainer {
+		return config.InjectedContainer{
+			Container:      corev1.Container{Name: name, Image: name + ":latest"},
+			Init:           init,
+			Order:          order,
+			Requests:       config.ResourceConfiguration{CPU: "100m"},
+			Limits:         config.ResourceConfiguration{CPU: "200m", Memory: "64Mi"},
+			WorkspaceTypes: types,
+		}
+	}
+	container := func(name string) corev1.Container {
+		return corev1.Container{
+			Name:  name,
+			Image: name + ":latest",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+			},
+		}
+	}
+	workspace := corev1.Container{Name: "workspace"}
+
+	tests := []struct {
+		Name        string
+		Type        api.WorkspaceType
+		Containers  []config.InjectedContainer
+		Expectation corev1.PodSpec
+	}{
+		{
+			Name:        "nothing to inject",
+			Expectation: corev1.PodSpec{Containers: []corev1.Container{workspace}},
+		},
+		{
+			Name: "init containers and sidecars",
+			Containers: []config.InjectedContainer{
+				injected("scanner", false, 0),
+				injected("ca", true, 2),
+				injected("proxy-config", true, 1),
+			},
+			Expectation: corev1.PodSpec{
+				InitContainers: []corev1.Container{container("proxy-config"), container("ca")},
+				Containers:     []corev1.Container{workspace, container("scanner")},
+			},
+		},
+		{
+			Name:        "workspace types",
+			Type:        api.WorkspaceType_PREBUILD,
+			Containers:  []config.InjectedContainer{injected("scanner", false, 0, "regular"), injected("ca", true, 0, "prebuild")},
+			Expectation: corev1.PodSpec{InitContainers: []corev1.Container{container("ca")}, Containers: []corev1.Container{workspace}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{workspace}}}
+			err := injectContainers(pod, test.Type, test.Containers)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.Expectation, pod.Spec); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	wsdaemon "github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// DrainNode cordons a node and backs up the workspaces running on it. Regular workspaces keep running until
+// the node actually goes away, the backup just limits what their users can lose. Headless workspaces are stopped
+// if the request asks for it, so that their owner can start them again on another node.
+func (m *Manager) DrainNode(req *api.DrainNodeRequest, srv api.WorkspaceManager_DrainNodeServer) (err error) {
+	span, ctx := tracing.FromContext(srv.Context(), "DrainNode")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+
+	if req.Node == "" {
+		return status.Error(codes.InvalidArgument, "node is required")
+	}
+
+	var node corev1.Node
+	err = m.Clientset.Get(ctx, types.NamespacedName{Name: req.Node}, &node)
+	if isKubernetesObjNotFoundError(err) {
+		return status.Errorf(codes.NotFound, "node %s does not exist", req.Node)
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot get node: %q", err)
+	}
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		err = m.Clientset.Update(ctx, &node)
+		if err != nil {
+			return status.Errorf(codes.Internal, "cannot cordon node: %q", err)
+		}
+	}
+	log.WithField("node", req.Node).Info("cordoned node for draining")
+	err = srv.Send(&api.DrainNodeResponse{Step: api.DrainNodeStep_NODE_CORDONED})
+	if err != nil {
+		return err
+	}
+
+	wsos, err := m.getAllWorkspaceObjects(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot list workspaces: %q", err)
+	}
+	for _, wso := range wsos {
+		if wso.NodeName() != req.Node {
+			continue
+		}
+		id, ok := wso.WorkspaceID()
+		if !ok {
+			continue
+		}
+
+		var resp *api.DrainNodeResponse
+		if wso.IsWorkspaceHeadless() {
+			if !req.StopHeadless {
+				continue
+			}
+			resp = m.drainStopWorkspace(ctx, id)
+		} else {
+			resp = m.drainBackupWorkspace(ctx, wso)
+			if resp == nil {
+				continue
+			}
+		}
+		resp.Id = id
+		err = srv.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return srv.Send(&api.DrainNodeResponse{Step: api.DrainNodeStep_NODE_DRAINED})
+}
+
+// drainStopWorkspace stops a headless workspace of a node that's being drained
+func (m *Manager) drainStopWorkspace(ctx context.Context, id string) *api.DrainNodeResponse {
+	err := m.markWorkspace(ctx, id,
+		addMark(stoppedByRequestAnnotation, stopWorkspaceNormallyGracePeriod.String()),
+		addMark(wsk8s.StopReasonAnnotation, api.StopReason_STOP_REASON_ADMIN_REQUEST.String()),
+	)
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", "", id)).Warn("cannot stop workspace while draining its node")
+		return &api.DrainNodeResponse{Step: api.DrainNodeStep_WORKSPACE_STOP_FAILED, Message: err.Error()}
+	}
+	return &api.DrainNodeResponse{Step: api.DrainNodeStep_WORKSPACE_STOPPED}
+}
+
+// drainBackupWorkspace backs up a regular workspace of a node that's being drained.
+// Returns nil if the workspace does not need a backup.
+func (m *Manager) drainBackupWorkspace(ctx context.Context, wso workspaceObjects) *api.DrainNodeResponse {
+	if _, pvc := wso.Pod.Labels[pvcWorkspaceFeatureAnnotation]; pvc {
+		// the content of this workspace does not live on the node
+		return nil
+	}
+	sts, err := m.getWorkspaceStatus(wso)
+	if err != nil || sts.Phase != api.WorkspacePhase_RUNNING {
+		// workspaces which aren't running have either no content yet, or are already being backed up
+		return nil
+	}
+
+	failed := func(err error) *api.DrainNodeResponse {
+		log.WithError(err).WithFields(wso.GetOWI()).Warn("cannot back up workspace while draining its node")
+		return &api.DrainNodeResponse{Step: api.DrainNodeStep_WORKSPACE_BACKUP_FAILED, Message: err.Error()}
+	}
+	sync, err := m.connectToWorkspaceDaemon(ctx, wso)
+	if err != nil {
+		return failed(err)
+	}
+	_, err = sync.BackupWorkspace(ctx, &wsdaemon.BackupWorkspaceRequest{Id: sts.Id})
+	if err != nil {
+		return failed(err)
+	}
+	return &api.DrainNodeResponse{Step: api.DrainNodeStep_WORKSPACE_BACKED_UP}
+}
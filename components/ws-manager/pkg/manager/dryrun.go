@@ -0,0 +1,287 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// dryRunStartWorkspace renders the workspace pod of a start request and checks if it would fit on a node
+// of the cluster right now. It does not create any resources.
+func (m *Manager) dryRunStartWorkspace(ctx context.Context, req *api.StartWorkspaceRequest) (res *api.StartWorkspaceResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "dryRunStartWorkspace")
+	defer tracing.FinishSpan(span, &err)
+
+	err = validateStartWorkspaceRequest(req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot start workspace: %w", err)
+	}
+	if m.Admission != nil {
+		req, err = m.admitStartWorkspace(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	startContext, err := m.newStartWorkspaceContext(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create context: %w", err)
+	}
+	pod, err := m.createWorkspacePod(startContext)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create workspace pod: %w", err)
+	}
+
+	nodes, err := m.RawClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list nodes: %w", err)
+	}
+	pods, err := m.RawClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list pods: %w", err)
+	}
+	nodeName, reason := simulateScheduling(pod, nodes.Items, pods.Items)
+
+	jsonPod, err := json.Marshal(pod)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot serialize workspace pod: %w", err)
+	}
+	safePod, err := log.RedactJSON(jsonPod)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot redact workspace pod: %w", err)
+	}
+
+	return &api.StartWorkspaceResponse{
+		Url: startContext.WorkspaceURL,
+		DryRun: &api.StartWorkspaceDryRunResult{
+			Fits:     nodeName != "",
+			NodeName: nodeName,
+			Reason:   reason,
+			Pod:      string(safePod),
+		},
+	}, nil
+}
+
+// simulateScheduling returns the node a pod would be scheduled on, or the reason why it fits on no node.
+// We only consider what matters for workspace pods: node readiness, cordoning, node selector, required node
+// affinity, taints and resource requests. Like the workspace scheduler we pack nodes as densely as possible,
+// i.e. we prefer the node with the least free memory the pod still fits on.
+func simulateScheduling(pod *corev1.Pod, nodes []corev1.Node, pods []corev1.Pod) (nodeName string, reason string) {
+	requested := make(map[string]corev1.ResourceList)
+	podCount := make(map[string]int64)
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, ok := requested[p.Spec.NodeName]; !ok {
+			requested[p.Spec.NodeName] = make(corev1.ResourceList)
+		}
+		addResources(requested[p.Spec.NodeName], podRequests(&p))
+		podCount[p.Spec.NodeName]++
+	}
+
+	var (
+		req      = podRequests(pod)
+		bestFree *resource.Quantity
+		failures = make(map[string]int)
+	)
+	for _, node := range nodes {
+		failure := nodeFits(pod, req, &node, requested[node.Name], podCount[node.Name])
+		if failure != "" {
+			failures[failure]++
+			continue
+		}
+
+		free := node.Status.Allocatable.Memory().DeepCopy()
+		used := requested[node.Name][corev1.ResourceMemory]
+		free.Sub(used)
+		if bestFree == nil || free.Cmp(*bestFree) < 0 {
+			bestFree = &free
+			nodeName = node.Name
+		}
+	}
+	if nodeName != "" {
+		return nodeName, ""
+	}
+	if len(nodes) == 0 {
+		return "", "there are no nodes in the cluster"
+	}
+
+	// mimic the Kubernetes scheduler's FailedScheduling message, e.g. "0/3 nodes are available: 1 node(s) were unschedulable, 2 Insufficient memory."
+	reasons := make([]string, 0, len(failures))
+	for r, n := range failures {
+		reasons = append(reasons, fmt.Sprintf("%d %s", n, r))
+	}
+	sort.Strings(reasons)
+	return "", fmt.Sprintf("0/%d nodes are available: %s.", len(nodes), strings.Join(reasons, ", "))
+}
+
+// nodeFits returns an empty string if the pod fits on the node, or the reason why it doesn't
+func nodeFits(pod *corev1.Pod, req corev1.ResourceList, node *corev1.Node, requested corev1.ResourceList, podCount int64) string {
+	if node.Spec.Unschedulable {
+		return "node(s) were unschedulable"
+	}
+	if !isNodeReady(node) {
+		return "node(s) were not ready"
+	}
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return "node(s) didn't match Pod's node affinity/selector"
+		}
+	}
+	if !matchesRequiredNodeAffinity(pod, node) {
+		return "node(s) didn't match Pod's node affinity/selector"
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		var tolerated bool
+		for _, t := range pod.Spec.Tolerations {
+			if t.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return "node(s) had taint that the pod didn't tolerate"
+		}
+	}
+
+	if maxPods, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && podCount+1 > maxPods.Value() {
+		return "Too many pods"
+	}
+	for name, q := range req {
+		free := node.Status.Allocatable[name].DeepCopy()
+		free.Sub(requested[name])
+		if free.Cmp(q) < 0 {
+			return fmt.Sprintf("Insufficient %s", name)
+		}
+	}
+	return ""
+}
+
+// matchesRequiredNodeAffinity returns true if the node matches at least one of the pod's required node selector terms
+func matchesRequiredNodeAffinity(pod *corev1.Pod, node *corev1.Node) bool {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return true
+	}
+
+	fields := map[string]string{"metadata.name": node.Name}
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			// empty terms match no objects
+			continue
+		}
+		matches := true
+		for _, r := range term.MatchExpressions {
+			matches = matches && matchesNodeSelectorRequirement(r, node.Labels)
+		}
+		for _, r := range term.MatchFields {
+			matches = matches && matchesNodeSelectorRequirement(r, fields)
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNodeSelectorRequirement(r corev1.NodeSelectorRequirement, values map[string]string) bool {
+	v, exists := values[r.Key]
+	switch r.Operator {
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+		var in bool
+		for _, candidate := range r.Values {
+			if exists && candidate == v {
+				in = true
+				break
+			}
+		}
+		return in == (r.Operator == corev1.NodeSelectorOpIn)
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !exists || len(r.Values) != 1 {
+			return false
+		}
+		actual, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return false
+		}
+		bound, err := strconv.ParseInt(r.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if r.Operator == corev1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	default:
+		return false
+	}
+}
+
+// podRequests returns the resources the scheduler reserves for a pod, i.e. the sum of its containers' requests
+// or the largest request of its init containers, whichever is greater.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	res := make(corev1.ResourceList)
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Requests {
+			sum := res[name]
+			sum.Add(q)
+			res[name] = sum
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range c.Resources.Requests {
+			if cur, ok := res[name]; !ok || q.Cmp(cur) > 0 {
+				res[name] = q.DeepCopy()
+			}
+		}
+	}
+	return res
+}
+
+// addResources adds the quantities of src to dst
+func addResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		sum := dst[name]
+		sum.Add(q)
+		dst[name] = sum
+	}
+}
+
+// isNodeReady returns true if the node's ready condition is true
+func isNodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSimulateScheduling(t *testing.T) {
+	node := func(name, memory string, mod ...func(*corev1.Node)) corev1.Node {
+		n := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"gitpod.io/workload_workspace": "true"}},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse(memory),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		for _, m := range mod {
+			m(&n)
+		}
+		return n
+	}
+	pod := func(nodeName, memory string) corev1.Pod {
+		return corev1.Pod{
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	workspace := pod("", "4Gi")
+	workspace.Spec.NodeSelector = map[string]string{"gitpod.io/workload_workspace": "true"}
+
+	tests := []struct {
+		Name           string
+		Nodes          []corev1.Node
+		Pods           []corev1.Pod
+		ExpectedNode   string
+		ExpectedReason string
+	}{
+		{Name: "no nodes", ExpectedReason: "there are no nodes in the cluster"},
+		{Name: "fits", Nodes: []corev1.Node{node("a", "8Gi")}, ExpectedNode: "a"},
+		{
+			Name:         "packs nodes",
+			Nodes:        []corev1.Node{node("a", "8Gi"), node("b", "8Gi")},
+			Pods:         []corev1.Pod{pod("b", "2Gi")},
+			ExpectedNode: "b",
+		},
+		{
+			Name:           "insufficient memory",
+			Nodes:          []corev1.Node{node("a", "8Gi")},
+			Pods:           []corev1.Pod{pod("a", "6Gi")},
+			ExpectedReason: "0/1 nodes are available: 1 Insufficient memory.",
+		},
+		{
+			Name:         "ignores finished pods",
+			Nodes:        []corev1.Node{node("a", "8Gi")},
+			Pods:         []corev1.Pod{func() corev1.Pod { p := pod("a", "6Gi"); p.Status.Phase = corev1.PodSucceeded; return p }()},
+			ExpectedNode: "a",
+		},
+		{
+			Name: "unschedulable and not ready",
+			Nodes: []corev1.Node{
+				node("a", "8Gi", func(n *corev1.Node) { n.Spec.Unschedulable = true }),
+				node("b", "8Gi", func(n *corev1.Node) { n.Status.Conditions[0].Status = corev1.ConditionFalse }),
+			},
+			ExpectedReason: "0/2 nodes are available: 1 node(s) were not ready, 1 node(s) were unschedulable.",
+		},
+		{
+			Name:           "node selector",
+			Nodes:          []corev1.Node{node("a", "8Gi", func(n *corev1.Node) { n.Labels = nil })},
+			ExpectedReason: "0/1 nodes are available: 1 node(s) didn't match Pod's node affinity/selector.",
+		},
+		{
+			Name: "taint",
+			Nodes: []corev1.Node{node("a", "8Gi", func(n *corev1.Node) {
+				n.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "builds", Effect: corev1.TaintEffectNoSchedule}}
+			})},
+			ExpectedReason: "0/1 nodes are available: 1 node(s) had taint that the pod didn't tolerate.",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			nodeName, reason := simulateScheduling(&workspace, test.Nodes, test.Pods)
+			if nodeName != test.ExpectedNode {
+				t.Errorf("unexpected node: want %q, got %q", test.ExpectedNode, nodeName)
+			}
+			if reason != test.ExpectedReason {
+				t.Errorf("unexpected reason: want %q, got %q", test.ExpectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestMatchesRequiredNodeAffinity(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"zone": "eu", "cores": "8"}}}
+
+	tests := []struct {
+		Name        string
+		Terms       []corev1.NodeSelectorTerm
+		Expectation bool
+	}{
+		{Name: "no terms", Expectation: true},
+		{Name: "in", Terms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu", "us"}}}}}, Expectation: true},
+		{Name: "not in", Terms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"eu"}}}}}},
+		{Name: "does not exist", Terms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu", Operator: corev1.NodeSelectorOpDoesNotExist}}}}, Expectation: true},
+		{Name: "gt", Terms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "cores", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}}}}}, Expectation: true},
+		{Name: "match fields", Terms: []corev1.NodeSelectorTerm{{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}}}},
+		{
+			Name: "any term",
+			Terms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us"}}}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpExists}}},
+			},
+			Expectation: true,
+		},
+		{Name: "empty term", Terms: []corev1.NodeSelectorTerm{{}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &corev1.Pod{}
+			if test.Terms != nil {
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: test.Terms},
+				}}
+			}
+			if act := matchesRequiredNodeAffinity(pod, node); act != test.Expectation {
+				t.Errorf("unexpected match: want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
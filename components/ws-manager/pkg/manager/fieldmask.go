@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldMaskTree is the tree of field names selected by the paths of a field mask.
+// A nil subtree selects the field including all its sub-fields.
+type fieldMaskTree map[string]fieldMaskTree
+
+func newFieldMaskTree(paths []string) fieldMaskTree {
+	res := make(fieldMaskTree)
+	for _, path := range paths {
+		node := res
+		segments := strings.Split(path, ".")
+		for i, segment := range segments {
+			child, exists := node[segment]
+			if exists && child == nil {
+				// the field is selected as a whole already
+				break
+			}
+			if i == len(segments)-1 {
+				node[segment] = nil
+				break
+			}
+			if !exists {
+				child = make(fieldMaskTree)
+				node[segment] = child
+			}
+			node = child
+		}
+	}
+	return res
+}
+
+// Apply clears all fields of msg which are not selected by the field mask
+func (t fieldMaskTree) Apply(msg protoreflect.Message) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, selected := t[string(fd.Name())]
+		switch {
+		case !selected:
+			msg.Clear(fd)
+		case sub != nil && fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			sub.Apply(v.Message())
+		}
+		return true
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// headlessLogChunkSize is the maximum amount of log we send in a single response
+	headlessLogChunkSize = 4096
+
+	// defaultSupervisorPort is the port supervisor listens on if we cannot find it on the pod
+	defaultSupervisorPort = 22999
+)
+
+// GetHeadlessLog streams the log of a headless workspace's task from supervisor. We only read from supervisor
+// as fast as the client consumes the stream. The resume token is the log offset the stream has reached.
+func (m *Manager) GetHeadlessLog(req *api.GetHeadlessLogRequest, srv api.WorkspaceManager_GetHeadlessLogServer) (err error) {
+	span, ctx := tracing.FromContext(srv.Context(), "GetHeadlessLog")
+	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
+	defer tracing.FinishSpan(span, &err)
+
+	if req.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task ID is required")
+	}
+	var offset int64
+	if req.ResumeToken != "" {
+		offset, err = strconv.ParseInt(req.ResumeToken, 10, 64)
+		if err != nil || offset < 0 {
+			return status.Error(codes.InvalidArgument, "invalid resume token")
+		}
+	}
+
+	pod, err := m.findWorkspacePod(ctx, req.Id)
+	if isKubernetesObjNotFoundError(err) {
+		return status.Errorf(codes.NotFound, "workspace %s does not exist", req.Id)
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot find workspace: %q", err)
+	}
+	if pod.Labels[headlessLabel] != "true" {
+		return status.Error(codes.FailedPrecondition, "workspace is not headless")
+	}
+	if pod.Status.PodIP == "" {
+		return status.Error(codes.Unavailable, "workspace is not running")
+	}
+
+	logURL := fmt.Sprintf("http://%s/headless-log/%s?offset=%d", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(getSupervisorPort(pod))), url.PathEscape(req.TaskId), offset)
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL, nil)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot create request: %q", err)
+	}
+	// we don't set a timeout as the log is streamed for as long as the task runs
+	resp, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "cannot connect to workspace: %q", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return status.Errorf(codes.NotFound, "task %s does not exist", req.TaskId)
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, "invalid resume token")
+	default:
+		return status.Errorf(codes.Unavailable, "workspace responded with %d", resp.StatusCode)
+	}
+
+	buf := make([]byte, headlessLogChunkSize)
+	for {
+		n, rerr := resp.Body.Read(buf)
+		if n > 0 {
+			offset += int64(n)
+			err = srv.Send(&api.GetHeadlessLogResponse{
+				Data:        buf[:n],
+				ResumeToken: strconv.FormatInt(offset, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if rerr == io.EOF {
+			return nil
+		}
+		if rerr != nil {
+			return status.Errorf(codes.Unavailable, "cannot read log: %q", rerr)
+		}
+	}
+}
+
+// getSupervisorPort returns the port supervisor listens on in a workspace pod
+func getSupervisorPort(pod *corev1.Pod) int {
+	for _, c := range pod.Spec.Containers {
+		if c.Name != "workspace" || c.ReadinessProbe == nil || c.ReadinessProbe.HTTPGet == nil {
+			continue
+		}
+		if port := c.ReadinessProbe.HTTPGet.Port.IntValue(); port > 0 {
+			return port
+		}
+	}
+	return defaultSupervisorPort
+}
@@ -22,6 +22,7 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,6 +53,8 @@ type Manager struct {
 	RawClient kubernetes.Interface
 	Content   *layer.Provider
 	OnChange  func(context.Context, *api.WorkspaceStatus)
+	// Admission admits workspace starts in addition to the validation ws-manager does itself. Can be nil.
+	Admission StartAdmitter
 
 	activity sync.Map
 	clock    *clock.HLC
@@ -77,6 +80,10 @@ type startWorkspaceContext struct {
 	WorkspaceURL   string                     `json:"workspaceURL"`
 	TraceID        string                     `json:"traceID"`
 	Headless       bool                       `json:"headless"`
+	// Class is the workspace class of the workspace, or nil if it uses the default resources and timeout
+	Class *config.WorkspaceClass `json:"class,omitempty"`
+	// SnapshotPolicy configures periodic snapshots of the workspace, or is nil if we don't take any
+	SnapshotPolicy *config.SnapshotPolicy `json:"snapshotPolicy,omitempty"`
 }
 
 const (
@@ -121,6 +128,9 @@ func New(config config.Configuration, client client.Client, rawClient kubernetes
 	}
 	m.metrics = newMetrics(m)
 	m.OnChange = m.onChange
+	if config.StartAdmission != nil {
+		m.Admission = newHTTPStartAdmitter(*config.StartAdmission)
+	}
 	return m, nil
 }
 
@@ -149,6 +159,10 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	safeReqs, _ := log.RedactJSON(reqs)
 	log.WithField("req", string(safeReqs)).Debug("StartWorkspace request received")
 
+	if req.DryRun {
+		return m.dryRunStartWorkspace(ctx, req)
+	}
+
 	// Make sure the objects we're about to create do not exist already
 	switch req.Type {
 	case api.WorkspaceType_IMAGEBUILD:
@@ -183,6 +197,13 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	if err != nil {
 		return nil, xerrors.Errorf("cannot start workspace: %w", err)
 	}
+	if m.Admission != nil {
+		req, err = m.admitStartWorkspace(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		span.LogKV("event", "admitted workspace start request")
+	}
 	span.LogKV("event", "validated workspace start request")
 	// create the objects required to start the workspace pod/service
 	startContext, err := m.newStartWorkspaceContext(ctx, req)
@@ -199,6 +220,56 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	}
 	span.LogKV("event", "pod description created")
 
+	// workspaces whose class stores their content on a persistent volume claim need that claim before the pod can be scheduled
+	pvc, err := m.createWorkspacePVC(startContext, pod)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create workspace persistent volume claim: %w", err)
+	}
+	if pvc != nil {
+		err = m.Clientset.Create(ctx, pvc)
+		if k8serr.IsAlreadyExists(err) {
+			return nil, status.Error(codes.AlreadyExists, "workspace instance already exists")
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create workspace persistent volume claim: %w", err)
+		}
+		span.LogKV("event", "pvc created")
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			// the workspace didn't start - don't leave its claim behind
+			derr := m.Clientset.Delete(context.Background(), pvc)
+			if derr != nil && !isKubernetesObjNotFoundError(derr) {
+				clog.WithError(derr).Warn("cannot delete persistent volume claim of workspace which failed to start")
+			}
+		}()
+	}
+
+	// the workspace's network policy must exist before its pod, so that the pod is never reachable without it
+	policy := newWorkspaceNetworkPolicy(m.Config.NetworkPolicy, pod)
+	if policy != nil {
+		err = m.Clientset.Create(ctx, policy)
+		if k8serr.IsAlreadyExists(err) {
+			return nil, status.Error(codes.AlreadyExists, "workspace instance already exists")
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create workspace network policy: %w", err)
+		}
+		span.LogKV("event", "network policy created")
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			derr := m.Clientset.Delete(context.Background(), policy)
+			if derr != nil && !isKubernetesObjNotFoundError(derr) {
+				clog.WithError(derr).Warn("cannot delete network policy of workspace which failed to start")
+			}
+		}()
+	}
+
 	// create the Pod in the cluster and wait until is scheduled
 	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.22.md#workloads-that-saturate-nodes-with-pods-may-see-pods-that-fail-due-to-node-admission
 	backoff := wait.Backoff{
@@ -272,6 +343,14 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 
 	span.LogKV("event", "pod started successfully")
 
+	if policy != nil {
+		// failing the start now would leave the pod without a network policy
+		oerr := m.ownWorkspaceNetworkPolicy(ctx, policy, pod)
+		if oerr != nil {
+			clog.WithError(oerr).Warn("cannot delete network policy together with workspace pod")
+		}
+	}
+
 	// all workspaces get a service now
 	okResponse := &api.StartWorkspaceResponse{
 		Url:        startContext.WorkspaceURL,
@@ -346,6 +425,28 @@ func validateStartWorkspaceRequest(req *api.StartWorkspaceRequest) error {
 	return nil
 }
 
+// admitStartWorkspace asks the start admitter whether the workspace may start and validates the request it admitted
+func (m *Manager) admitStartWorkspace(ctx context.Context, req *api.StartWorkspaceRequest) (*api.StartWorkspaceRequest, error) {
+	admitted, err := m.Admission.Admit(ctx, req)
+	var rejected *AdmissionRejectedError
+	if xerrors.As(err, &rejected) {
+		return nil, status.Error(codes.PermissionDenied, rejected.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+
+	// the admitter may have changed the request, but it must not turn it into another workspace
+	if admitted.Id != req.Id || admitted.Type != req.Type {
+		return nil, status.Error(codes.Internal, "start admission must not change the workspace ID or type")
+	}
+	err = validateStartWorkspaceRequest(admitted)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "start admission produced an invalid request: %v", err)
+	}
+	return admitted, nil
+}
+
 func isValidWorkspaceType(value interface{}) error {
 	s, ok := value.(api.WorkspaceType)
 	if !ok {
@@ -401,12 +502,34 @@ func (m *Manager) StopWorkspace(ctx context.Context, req *api.StopWorkspaceReque
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	defer tracing.FinishSpan(span, &err)
 
+	discarded, err := m.discardPausedWorkspace(ctx, req.Id)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot discard paused workspace: %w", err)
+	}
+	if discarded {
+		if exists, _ := m.workspaceExists(ctx, req.Id); !exists {
+			return &api.StopWorkspaceResponse{}, nil
+		}
+	}
+
 	gracePeriod := stopWorkspaceNormallyGracePeriod
 	if req.Policy == api.StopWorkspacePolicy_IMMEDIATELY {
 		gracePeriod = stopWorkspaceImmediatelyGracePeriod
 	}
 
-	err = m.markWorkspace(ctx, req.Id, addMark(stoppedByRequestAnnotation, gracePeriod.String()))
+	reason := req.Reason
+	switch reason {
+	case api.StopReason_STOP_REASON_NONE:
+		reason = api.StopReason_STOP_REASON_USER_REQUEST
+	case api.StopReason_STOP_REASON_USER_REQUEST, api.StopReason_STOP_REASON_ADMIN_REQUEST, api.StopReason_STOP_REASON_PENALTY:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "cannot stop a workspace for reason %s", reason.String())
+	}
+
+	err = m.markWorkspace(ctx, req.Id,
+		addMark(stoppedByRequestAnnotation, gracePeriod.String()),
+		addMark(wsk8s.StopReasonAnnotation, reason.String()),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -632,11 +755,13 @@ func (m *Manager) ControlPort(ctx context.Context, req *api.ControlPortRequest)
 				Port:       uint32(port),
 				Visibility: req.Spec.Visibility,
 				Url:        url,
+				Headers:    req.Spec.Headers,
 			}
 
 			exposedPorts.Ports = append(exposedPorts.Ports, portSpec)
 		} else if req.Expose && existingPortSpecIdx >= 0 {
 			exposedPorts.Ports[existingPortSpecIdx].Visibility = req.Spec.Visibility
+			exposedPorts.Ports[existingPortSpecIdx].Headers = req.Spec.Headers
 		} else if !req.Expose && existingPortSpecIdx < 0 {
 			// port isn't exposed already - we're done here
 			return nil
@@ -729,16 +854,47 @@ func (m *Manager) DescribeWorkspace(ctx context.Context, req *api.DescribeWorksp
 // Subscribe streams all status updates to a client
 func (m *Manager) Subscribe(req *api.SubscribeRequest, srv api.WorkspaceManager_SubscribeServer) (err error) {
 	var sub subscriber = srv
-	if req.MustMatch != nil {
-		sub = &filteringSubscriber{srv, req.MustMatch}
+	if req.MustMatch != nil || len(req.Ids) > 0 || len(req.Phases) > 0 || req.FieldMask != nil {
+		if req.FieldMask != nil && !req.FieldMask.IsValid(&api.WorkspaceStatus{}) {
+			return status.Error(codes.InvalidArgument, "invalid field mask")
+		}
+		sub = newFilteringSubscriber(srv, req)
 	}
 
 	return m.subscribe(srv.Context(), sub)
 }
 
+// filteringSubscriber forwards only those status updates which match the subscription,
+// reduced to the fields selected by its field mask.
 type filteringSubscriber struct {
 	Sub    subscriber
 	Filter *api.MetadataFilter
+	IDs    map[string]struct{}
+	Phases map[api.WorkspacePhase]struct{}
+	Mask   fieldMaskTree
+}
+
+func newFilteringSubscriber(sub subscriber, req *api.SubscribeRequest) *filteringSubscriber {
+	res := &filteringSubscriber{
+		Sub:    sub,
+		Filter: req.MustMatch,
+	}
+	if len(req.Ids) > 0 {
+		res.IDs = make(map[string]struct{}, len(req.Ids))
+		for _, id := range req.Ids {
+			res.IDs[id] = struct{}{}
+		}
+	}
+	if len(req.Phases) > 0 {
+		res.Phases = make(map[api.WorkspacePhase]struct{}, len(req.Phases))
+		for _, phase := range req.Phases {
+			res.Phases[phase] = struct{}{}
+		}
+	}
+	if req.FieldMask != nil {
+		res.Mask = newFieldMaskTree(req.FieldMask.Paths)
+	}
+	return res
 }
 
 func matchesMetadataFilter(filter *api.MetadataFilter, md *api.WorkspaceMetadata) bool {
@@ -762,18 +918,31 @@ func matchesMetadataFilter(filter *api.MetadataFilter, md *api.WorkspaceMetadata
 }
 
 func (f *filteringSubscriber) Send(resp *api.SubscribeResponse) error {
-	var md *api.WorkspaceMetadata
-	if sts := resp.GetStatus(); sts != nil {
-		md = sts.Metadata
-	}
-	if md == nil {
+	sts := resp.GetStatus()
+	if sts == nil || sts.Metadata == nil {
 		// no metadata, no forwarding
 		return nil
 	}
-	if !matchesMetadataFilter(f.Filter, md) {
+	if !matchesMetadataFilter(f.Filter, sts.Metadata) {
+		return nil
+	}
+	if _, ok := f.IDs[sts.Id]; f.IDs != nil && !ok {
+		return nil
+	}
+	if _, ok := f.Phases[sts.Phase]; f.Phases != nil && !ok {
 		return nil
 	}
 
+	if f.Mask != nil {
+		// the response is shared between all subscribers, hence we must not modify it
+		masked := proto.Clone(sts).(*api.WorkspaceStatus)
+		f.Mask.Apply(masked.ProtoReflect())
+		resp = &api.SubscribeResponse{
+			Status: masked,
+			Header: resp.Header,
+		}
+	}
+
 	return f.Sub.Send(resp)
 }
 
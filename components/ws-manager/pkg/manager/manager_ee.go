@@ -59,7 +59,8 @@ func (m *Manager) TakeSnapshot(ctx context.Context, req *api.TakeSnapshotRequest
 		return nil, status.Errorf(codes.Unavailable, "cannot connect to workspace daemon: %q", err)
 	}
 
-	r, err := sync.TakeSnapshot(ctx, &wsdaemon.TakeSnapshotRequest{
+	// the workspace keeps running while ws-daemon snapshots a clone of its content
+	r, err := sync.CreateSnapshot(ctx, &wsdaemon.CreateSnapshotRequest{
 		Id:                req.Id,
 		ReturnImmediately: req.ReturnImmediately,
 	})
@@ -125,9 +126,13 @@ func (m *Manager) SetTimeout(ctx context.Context, req *api.SetTimeoutRequest) (r
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	defer tracing.FinishSpan(span, &err)
 
-	_, err = time.ParseDuration(req.Duration)
+	timeout, err := time.ParseDuration(req.Duration)
 	if err != nil {
-		return nil, xerrors.Errorf("invalid duration \"%s\": %w", req.Duration, err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid duration \"%s\": %v", req.Duration, err)
+	}
+	err = m.Config.Timeouts.CheckCustom(timeout)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid duration \"%s\": %v", req.Duration, err)
 	}
 
 	err = m.markWorkspace(ctx, req.Id, addMark(customTimeoutAnnotation, req.Duration))
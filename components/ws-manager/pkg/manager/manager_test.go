@@ -13,7 +13,10 @@ import (
 	ctesting "github.com/gitpod-io/gitpod/common-go/testing"
 	"github.com/gitpod-io/gitpod/ws-manager/api"
 	"github.com/gitpod-io/gitpod/ws-manager/pkg/manager/internal/grpcpool"
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -179,6 +182,87 @@ func TestGetWorkspaces(t *testing.T) {
 	test.Run()
 }
 
+type recordingSubscriber struct {
+	Responses []*api.SubscribeResponse
+}
+
+func (r *recordingSubscriber) Send(resp *api.SubscribeResponse) error {
+	r.Responses = append(r.Responses, resp)
+	return nil
+}
+
+func TestFilteringSubscriber(t *testing.T) {
+	updates := []*api.WorkspaceStatus{
+		{
+			Id:         "a",
+			Metadata:   &api.WorkspaceMetadata{Owner: "foo", MetaId: "ws-a"},
+			Phase:      api.WorkspacePhase_RUNNING,
+			Conditions: &api.WorkspaceConditions{Failed: "nope", Timeout: "later"},
+		},
+		{
+			Id:       "b",
+			Metadata: &api.WorkspaceMetadata{Owner: "bar", MetaId: "ws-b"},
+			Phase:    api.WorkspacePhase_STOPPING,
+		},
+		{
+			Id:    "c",
+			Phase: api.WorkspacePhase_RUNNING,
+		},
+	}
+
+	tests := []struct {
+		Desc        string
+		Req         *api.SubscribeRequest
+		Expectation []*api.WorkspaceStatus
+	}{
+		{
+			Desc:        "metadata filter",
+			Req:         &api.SubscribeRequest{MustMatch: &api.MetadataFilter{Owner: "bar"}},
+			Expectation: []*api.WorkspaceStatus{updates[1]},
+		},
+		{
+			Desc:        "ids",
+			Req:         &api.SubscribeRequest{Ids: []string{"a", "b"}},
+			Expectation: []*api.WorkspaceStatus{updates[0], updates[1]},
+		},
+		{
+			Desc:        "phases",
+			Req:         &api.SubscribeRequest{Phases: []api.WorkspacePhase{api.WorkspacePhase_RUNNING}},
+			Expectation: []*api.WorkspaceStatus{updates[0]},
+		},
+		{
+			Desc: "field mask",
+			Req:  &api.SubscribeRequest{Ids: []string{"a"}, FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "conditions.failed"}}},
+			Expectation: []*api.WorkspaceStatus{
+				{Id: "a", Conditions: &api.WorkspaceConditions{Failed: "nope"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var rec recordingSubscriber
+			sub := newFilteringSubscriber(&rec, test.Req)
+			for _, u := range updates {
+				err := sub.Send(&api.SubscribeResponse{Status: u})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var act []*api.WorkspaceStatus
+			for _, r := range rec.Responses {
+				act = append(act, r.Status)
+			}
+			if diff := cmp.Diff(test.Expectation, act, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+			if updates[0].Conditions.Timeout == "" {
+				t.Errorf("field mask modified the original status")
+			}
+		})
+	}
+}
+
 func TestFindWorkspacePod(t *testing.T) {
 	type tpd struct {
 		WorkspaceID string
@@ -37,19 +37,34 @@ const (
 type metrics struct {
 	manager *Manager
 
-	startupTimeHistVec    *prometheus.HistogramVec
-	totalStartsCounterVec *prometheus.CounterVec
-	totalStopsCounterVec  *prometheus.CounterVec
-	totalOpenPortGauge    prometheus.GaugeFunc
+	startupTimeHistVec      *prometheus.HistogramVec
+	startupPhaseTimeHistVec *prometheus.HistogramVec
+	totalStartsCounterVec   *prometheus.CounterVec
+	totalStopsCounterVec    *prometheus.CounterVec
+	totalOpenPortGauge      prometheus.GaugeFunc
 
 	mu         sync.Mutex
-	phaseState map[string]api.WorkspacePhase
+	phaseState map[string]workspacePhaseState
+}
+
+// workspacePhaseState is the phase a workspace was last seen in, and since when it is in that phase
+type workspacePhaseState struct {
+	Phase api.WorkspacePhase
+	// Since is the time the workspace entered the phase, or the zero time if we don't know
+	Since time.Time
+}
+
+// startupPhaseSteps names the step of the startup pipeline that's complete once a workspace leaves the phase
+var startupPhaseSteps = map[api.WorkspacePhase]string{
+	api.WorkspacePhase_PENDING:      "pod_scheduled",
+	api.WorkspacePhase_CREATING:     "image_pulled",
+	api.WorkspacePhase_INITIALIZING: "content_ready",
 }
 
 func newMetrics(m *Manager) *metrics {
 	return &metrics{
 		manager:    m,
-		phaseState: make(map[string]api.WorkspacePhase),
+		phaseState: make(map[string]workspacePhaseState),
 		startupTimeHistVec: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: metricsNamespace,
 			Subsystem: metricsWorkspaceSubsystem,
@@ -58,6 +73,14 @@ func newMetrics(m *Manager) *metrics {
 			// same as components/ws-manager-bridge/src/prometheus-metrics-exporter.ts#L15
 			Buckets: prometheus.ExponentialBuckets(2, 2, 10),
 		}, []string{"type"}),
+		startupPhaseTimeHistVec: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsWorkspaceSubsystem,
+			Name:        "workspace_startup_phase_seconds",
+			Help:        "time it took workspaces to complete a step of the startup, i.e. pod_scheduled, image_pulled or content_ready",
+			ConstLabels: prometheus.Labels{"cluster": m.Config.WorkspaceClusterHost},
+			Buckets:     prometheus.ExponentialBuckets(0.5, 2, 11),
+		}, []string{"step", "type", "class"}),
 		totalStartsCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: metricsNamespace,
 			Subsystem: metricsWorkspaceSubsystem,
@@ -118,6 +141,7 @@ func newTotalOpenPortGaugeHandler(m *Manager) func() float64 {
 func (m *metrics) Register(reg prometheus.Registerer) error {
 	collectors := []prometheus.Collector{
 		m.startupTimeHistVec,
+		m.startupPhaseTimeHistVec,
 		newPhaseTotalVec(m.manager),
 		newWorkspaceActivityVec(m.manager),
 		newTimeoutSettingsVec(m.manager),
@@ -151,21 +175,26 @@ func (m *metrics) OnChange(status *api.WorkspaceStatus) {
 	var removeFromState bool
 	tpe := api.WorkspaceType_name[int32(status.Spec.Type)]
 	m.mu.Lock()
+	prev, known := m.phaseState[status.Id]
 	defer func() {
 		if removeFromState {
 			delete(m.phaseState, status.Id)
-		} else {
-			m.phaseState[status.Id] = status.Phase
+		} else if prev.Phase != status.Phase || !known {
+			m.phaseState[status.Id] = m.enterPhase(status, known)
 		}
 		m.mu.Unlock()
 	}()
 
+	if known && prev.Phase != status.Phase {
+		m.observeStartupPhase(status, prev)
+	}
+
 	switch status.Phase {
 	case api.WorkspacePhase_RUNNING:
 		if status.Metadata.StartedAt == nil {
 			return
 		}
-		countedAlready := m.phaseState[status.Id] == api.WorkspacePhase_RUNNING
+		countedAlready := prev.Phase == api.WorkspacePhase_RUNNING
 		if countedAlready {
 			return
 		}
@@ -200,6 +229,38 @@ func (m *metrics) OnChange(status *api.WorkspaceStatus) {
 	}
 }
 
+// enterPhase produces the phase state of a workspace which just entered its current phase
+func (m *metrics) enterPhase(status *api.WorkspaceStatus, known bool) workspacePhaseState {
+	res := workspacePhaseState{Phase: status.Phase}
+	if known {
+		res.Since = time.Now()
+	} else if status.Phase == api.WorkspacePhase_PENDING && status.Metadata.StartedAt != nil {
+		// workspaces start in the pending phase
+		res.Since = status.Metadata.StartedAt.AsTime()
+	}
+	return res
+}
+
+// observeStartupPhase records how long it took a workspace to complete a step of its startup
+func (m *metrics) observeStartupPhase(status *api.WorkspaceStatus, prev workspacePhaseState) {
+	step, ok := startupPhaseSteps[prev.Phase]
+	if !ok || prev.Since.IsZero() {
+		return
+	}
+	if status.Phase < prev.Phase || status.Phase > api.WorkspacePhase_RUNNING || status.Conditions.Failed != "" {
+		// the workspace did not complete the step but failed or stopped
+		return
+	}
+
+	tpe := api.WorkspaceType_name[int32(status.Spec.Type)]
+	hist, err := m.startupPhaseTimeHistVec.GetMetricWithLabelValues(step, tpe, status.Spec.WorkspaceClass)
+	if err != nil {
+		log.WithError(err).WithField("step", step).Warn("cannot get startup phase time histogram metric")
+		return
+	}
+	hist.Observe(time.Since(prev.Since).Seconds())
+}
+
 // phaseTotalVec returns a gauge vector counting the workspaces per phase
 type phaseTotalVec struct {
 	name    string
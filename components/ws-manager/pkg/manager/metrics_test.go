@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestStartupPhaseMetrics(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Phases      []api.WorkspacePhase
+		Failed      string
+		Expectation int
+	}{
+		{
+			Name:        "regular startup",
+			Phases:      []api.WorkspacePhase{api.WorkspacePhase_PENDING, api.WorkspacePhase_CREATING, api.WorkspacePhase_INITIALIZING, api.WorkspacePhase_RUNNING},
+			Expectation: 3,
+		},
+		{
+			Name:        "repeated updates",
+			Phases:      []api.WorkspacePhase{api.WorkspacePhase_PENDING, api.WorkspacePhase_PENDING, api.WorkspacePhase_CREATING, api.WorkspacePhase_CREATING},
+			Expectation: 1,
+		},
+		{
+			Name:        "first seen during startup",
+			Phases:      []api.WorkspacePhase{api.WorkspacePhase_CREATING, api.WorkspacePhase_INITIALIZING, api.WorkspacePhase_RUNNING},
+			Expectation: 1,
+		},
+		{
+			Name:        "stopped during startup",
+			Phases:      []api.WorkspacePhase{api.WorkspacePhase_PENDING, api.WorkspacePhase_CREATING, api.WorkspacePhase_STOPPING},
+			Expectation: 1,
+		},
+		{
+			Name:        "failed",
+			Phases:      []api.WorkspacePhase{api.WorkspacePhase_PENDING, api.WorkspacePhase_CREATING},
+			Failed:      "cannot pull image",
+			Expectation: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			m := newMetrics(&Manager{Config: forTestingOnlyManagerConfig()})
+			for _, phase := range test.Phases {
+				m.OnChange(&api.WorkspaceStatus{
+					Id:         "foobar",
+					Phase:      phase,
+					Metadata:   &api.WorkspaceMetadata{StartedAt: timestamppb.New(time.Now().Add(-10 * time.Second))},
+					Spec:       &api.WorkspaceSpec{Type: api.WorkspaceType_REGULAR, WorkspaceClass: "default"},
+					Conditions: &api.WorkspaceConditions{Failed: test.Failed},
+				})
+			}
+
+			if act := testutil.CollectAndCount(m.startupPhaseTimeHistVec); act != test.Expectation {
+				t.Errorf("unexpected number of observed steps: %d, expected %d", act, test.Expectation)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	grpc_status "google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/watch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -73,6 +74,9 @@ type Monitor struct {
 	finalizerMap     map[string]context.CancelFunc
 	finalizerMapLock sync.Mutex
 
+	snapshotMap     map[string]struct{}
+	snapshotMapLock sync.Mutex
+
 	act actingManager
 
 	OnError func(error)
@@ -92,6 +96,7 @@ func (m *Manager) CreateMonitor() (*Monitor, error) {
 		probeMap:       make(map[string]context.CancelFunc),
 		initializerMap: make(map[string]struct{}),
 		finalizerMap:   make(map[string]context.CancelFunc),
+		snapshotMap:    make(map[string]struct{}),
 
 		OnError: func(err error) {
 			log.WithError(err).Error("workspace monitor error")
@@ -259,6 +264,17 @@ func actOnPodEvent(ctx context.Context, m actingManager, status *api.WorkspaceSt
 				log.WithError(err).Error("was unable to update pod's disposal state - this will break someone's experience")
 			}
 		}
+
+		// Special case: evicted or preempted workspaces. We remember the eviction so that it's still visible once the
+		//               events that told us about it are gone. Pods evicted by the kubelet are not deleted, hence
+		//               we stop them below like any other stopping pod. Our finalizer then keeps the pod around until
+		//               its content is backed up.
+		if _, marked := pod.Annotations[workspaceEvictedAnnotation]; status.Conditions.Evicted != "" && !marked {
+			err := m.markWorkspace(ctx, workspaceID, addMark(workspaceEvictedAnnotation, status.Conditions.Evicted))
+			if err != nil {
+				log.WithError(err).Warn("cannot mark workspace as evicted")
+			}
+		}
 	} else if status.Conditions.Failed != "" || status.Conditions.Timeout != "" {
 		// the workspace has failed to run/start - shut it down
 		// we should mark the workspace as failedBeforeStopping - this way the failure status will persist
@@ -419,6 +435,11 @@ func (m *Monitor) doHousekeeping(ctx context.Context) {
 	if err != nil {
 		m.OnError(err)
 	}
+
+	err = m.takeScheduledSnapshots(ctx)
+	if err != nil {
+		m.OnError(err)
+	}
 }
 
 // writeEventTraceLog writes an event trace log if one is configured. This function is written in
@@ -659,6 +680,7 @@ func (m *Monitor) probeWorkspaceReady(ctx context.Context, pod *corev1.Pod) (res
 // prior to this call this function returns once initialization is complete.
 func (m *Monitor) initializeWorkspaceContent(ctx context.Context, pod *corev1.Pod) (err error) {
 	_, fullWorkspaceBackup := pod.Labels[fullWorkspaceBackupAnnotation]
+	_, pvc := pod.Labels[pvcWorkspaceFeatureAnnotation]
 
 	workspaceID, ok := pod.Annotations[workspaceIDAnnotation]
 	if !ok {
@@ -702,6 +724,12 @@ func (m *Monitor) initializeWorkspaceContent(ctx context.Context, pod *corev1.Po
 		if err != nil {
 			return xerrors.Errorf("cannot unmarshal init config: %w", err)
 		}
+		if _, restored := pod.Annotations[volumeSnapshotRestoreAnnotation]; pvc && restored {
+			// the persistent volume claim was restored from a volume snapshot - there's no content left to initialize
+			initializer = csapi.WorkspaceInitializer{
+				Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}},
+			}
+		}
 
 		if fullWorkspaceBackup {
 			_, mf, err := m.manager.Content.GetContentLayer(ctx, workspaceMeta.Owner, workspaceMeta.MetaId, &initializer)
@@ -746,6 +774,9 @@ func (m *Monitor) initializeWorkspaceContent(ctx context.Context, pod *corev1.Po
 			FullWorkspaceBackup:   fullWorkspaceBackup,
 			ContentManifest:       contentManifest,
 			RemoteStorageDisabled: shouldDisableRemoteStorage(pod),
+			PersistentVolumeClaim: pvc,
+			StorageQuota:          m.manager.getStorageQuota(pod),
+			SeccompProfile:        m.manager.getSeccompProfile(pod),
 		})
 		return err
 	})
@@ -839,6 +870,11 @@ func (m *Monitor) finalizeWorkspaceContent(ctx context.Context, wso *workspaceOb
 		tpe = api.WorkspaceType_REGULAR
 	}
 
+	// workspaces which store their content on a persistent volume claim are backed up using a volume snapshot
+	_, pvc := wso.Pod.Labels[pvcWorkspaceFeatureAnnotation]
+	// paused workspaces keep their persistent volume claim so that they can be resumed right away
+	_, paused := wso.Pod.Annotations[workspacePausedAnnotation]
+
 	doBackup := wso.WasEverReady() && !wso.IsWorkspaceHeadless()
 	doBackupLogs := tpe == api.WorkspaceType_PREBUILD
 	doSnapshot := tpe == api.WorkspaceType_PREBUILD
@@ -878,7 +914,7 @@ func (m *Monitor) finalizeWorkspaceContent(ctx context.Context, wso *workspaceOb
 			m.finalizerMapLock.Unlock()
 		}()
 
-		if doSnapshot {
+		if doSnapshot && !pvc {
 			// if this is a prebuild take a snapshot and mark the workspace
 			var res *wsdaemon.TakeSnapshotResponse
 			res, err = snc.TakeSnapshot(ctx, &wsdaemon.TakeSnapshotRequest{Id: workspaceID})
@@ -902,13 +938,26 @@ func (m *Monitor) finalizeWorkspaceContent(ctx context.Context, wso *workspaceOb
 		// This is unlike the initialization process where we wait for things to finish in a later phase.
 		resp, err := snc.DisposeWorkspace(ctx, &wsdaemon.DisposeWorkspaceRequest{
 			Id:         workspaceID,
-			Backup:     doBackup,
+			Backup:     doBackup && !pvc,
 			BackupLogs: doBackupLogs,
 		})
 		if resp != nil {
 			gitStatus = resp.GitStatus
 		}
-		return true, gitStatus, err
+		if err != nil || !pvc || paused || (!doBackup && !doSnapshot) {
+			return true, gitStatus, err
+		}
+
+		// the workspace content has been disposed of on the node, but still lives on the persistent volume claim
+		snapshot, err := m.manager.takeVolumeSnapshot(ctx, wso)
+		if err != nil {
+			return true, gitStatus, err
+		}
+		err = m.manager.markWorkspace(ctx, workspaceID, addMark(volumeSnapshotAnnotation, snapshot))
+		if err != nil {
+			return true, gitStatus, xerrors.Errorf("cannot remember volume snapshot: %w", err)
+		}
+		return true, gitStatus, nil
 	}
 
 	var (
@@ -974,6 +1023,15 @@ func (m *Monitor) finalizeWorkspaceContent(ctx context.Context, wso *workspaceOb
 			tracing.LogError(span, backupError)
 		}
 	}
+
+	// We keep the persistent volume claim if we failed to snapshot it, so that its content isn't lost for good
+	if pvc && !paused && disposalStatus.BackupFailure == "" {
+		err = m.manager.deleteWorkspacePVC(ctx, wso)
+		if err != nil {
+			tracing.LogError(span, err)
+			log.WithError(err).Warn("cannot delete persistent volume claim of workspace")
+		}
+	}
 }
 
 // markTimedoutWorkspaces finds workspaces which haven't been active recently and marks them as timed out
@@ -1060,3 +1118,29 @@ func handleGRPCError(ctx context.Context, err error) error {
 
 	return xerrors.Errorf(grpcErr.Message())
 }
+
+// getStorageQuota returns the disk quota in bytes of the workspace content as configured by the workspace class,
+// or zero if the class doesn't configure one.
+func (m *Manager) getStorageQuota(pod *corev1.Pod) int64 {
+	class, ok := m.Config.WorkspaceClasses[pod.Annotations[workspaceClassAnnotation]]
+	if !ok || class.StorageQuota == "" {
+		return 0
+	}
+	// the quota was validated when we loaded the configuration
+	q, err := resource.ParseQuantity(class.StorageQuota)
+	if err != nil {
+		log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).Warn("invalid storage quota of workspace class")
+		return 0
+	}
+	return q.Value()
+}
+
+// getSeccompProfile returns the name of the seccomp filter workspacekit installs in the workspace as configured by
+// the workspace class, or an empty string if the class doesn't configure one.
+func (m *Manager) getSeccompProfile(pod *corev1.Pod) string {
+	class, ok := m.Config.WorkspaceClasses[pod.Annotations[workspaceClassAnnotation]]
+	if !ok {
+		return ""
+	}
+	return class.SeccompProfile
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+// newWorkspaceNetworkPolicy produces the network policy of a workspace pod from the configured template.
+// Returns nil if there is no template.
+func newWorkspaceNetworkPolicy(tpl *config.NetworkPolicyTemplate, pod *corev1.Pod) *networkingv1.NetworkPolicy {
+	if tpl == nil {
+		return nil
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Labels: map[string]string{
+				"app":                  "gitpod",
+				"component":            "workspace",
+				wsk8s.WorkspaceIDLabel: pod.Labels[wsk8s.WorkspaceIDLabel],
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{
+				wsk8s.WorkspaceIDLabel: pod.Labels[wsk8s.WorkspaceIDLabel],
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+	for _, r := range tpl.Ingress {
+		policy.Spec.Ingress = append(policy.Spec.Ingress, *r.DeepCopy())
+	}
+	for _, r := range tpl.Egress {
+		policy.Spec.Egress = append(policy.Spec.Egress, *r.DeepCopy())
+	}
+	if len(tpl.EgressCIDRs) > 0 {
+		rule := networkingv1.NetworkPolicyEgressRule{}
+		for _, cidr := range tpl.EgressCIDRs {
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+		policy.Spec.Egress = append(policy.Spec.Egress, rule)
+	}
+	return policy
+}
+
+// ownWorkspaceNetworkPolicy makes the workspace pod the owner of its network policy, so that Kubernetes deletes
+// the policy together with the pod. We create the policy before the pod so that the pod is never without one,
+// hence can only add the owner reference once the pod exists.
+func (m *Manager) ownWorkspaceNetworkPolicy(ctx context.Context, policy *networkingv1.NetworkPolicy, pod *corev1.Pod) error {
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var current networkingv1.NetworkPolicy
+		err := m.Clientset.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}, &current)
+		if err != nil {
+			return err
+		}
+		current.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			UID:        pod.UID,
+		}}
+		return m.Clientset.Update(ctx, &current)
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot set owner of network policy: %w", err)
+	}
+	return nil
+}
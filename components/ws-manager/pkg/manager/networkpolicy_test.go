@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+func TestNewWorkspaceNetworkPolicy(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "ws-foo",
+		Namespace: "default",
+		Labels:    map[string]string{"workspaceID": "foo", "owner": "bar"},
+	}}
+	fromProxy := networkingv1.NetworkPolicyIngressRule{From: []networkingv1.NetworkPolicyPeer{{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"component": "proxy"}},
+	}}}
+	toDNS := networkingv1.NetworkPolicyEgressRule{To: []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+	}}}
+	meta := metav1.ObjectMeta{
+		Name:      "ws-foo",
+		Namespace: "default",
+		Labels:    map[string]string{"app": "gitpod", "component": "workspace", "workspaceID": "foo"},
+	}
+	spec := func(ingress []networkingv1.NetworkPolicyIngressRule, egress []networkingv1.NetworkPolicyEgressRule) networkingv1.NetworkPolicySpec {
+		return networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"workspaceID": "foo"}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress:     ingress,
+			Egress:      egress,
+		}
+	}
+
+	tests := []struct {
+		Name        string
+		Template    *config.NetworkPolicyTemplate
+		Expectation *networkingv1.NetworkPolicy
+	}{
+		{Name: "no template"},
+		{
+			Name:        "deny all",
+			Template:    &config.NetworkPolicyTemplate{},
+			Expectation: &networkingv1.NetworkPolicy{ObjectMeta: meta, Spec: spec(nil, nil)},
+		},
+		{
+			Name: "rules and cidrs",
+			Template: &config.NetworkPolicyTemplate{
+				Ingress:     []networkingv1.NetworkPolicyIngressRule{fromProxy},
+				Egress:      []networkingv1.NetworkPolicyEgressRule{toDNS},
+				EgressCIDRs: []string{"10.0.0.0/8", "192.168.1.0/24"},
+			},
+			Expectation: &networkingv1.NetworkPolicy{ObjectMeta: meta, Spec: spec(
+				[]networkingv1.NetworkPolicyIngressRule{fromProxy},
+				[]networkingv1.NetworkPolicyEgressRule{
+					toDNS,
+					{To: []networkingv1.NetworkPolicyPeer{
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.1.0/24"}},
+					}},
+				},
+			)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := newWorkspaceNetworkPolicy(test.Template, pod)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected network policy (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
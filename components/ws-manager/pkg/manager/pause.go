@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// pausedWorkspacePodKey is the key of the paused workspace secret which holds the pod we recreate on resume
+	pausedWorkspacePodKey = "pod"
+)
+
+// transientAnnotations describe the lifecycle of a particular workspace pod and must not carry over to the pod of a resumed workspace
+var transientAnnotations = []string{
+	workspaceTimedOutAnnotation,
+	workspaceClosedAnnotation,
+	workspaceExplicitFailAnnotation,
+	workspaceFailedBeforeStoppingAnnotation,
+	workspaceSnapshotAnnotation,
+	disposalStatusAnnotation,
+	nodeNameAnnotation,
+	stoppedByRequestAnnotation,
+	workspaceEvictedAnnotation,
+	workspacePausedAnnotation,
+	volumeSnapshotAnnotation,
+	wsk8s.ContainerIsGoneAnnotation,
+}
+
+// PauseWorkspace backs up a running workspace and removes its pod. The pod is kept in a secret so that ResumeWorkspace
+// can recreate it with the same configuration, e.g. its exposed ports and environment.
+func (m *Manager) PauseWorkspace(ctx context.Context, req *api.PauseWorkspaceRequest) (res *api.PauseWorkspaceResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "PauseWorkspace")
+	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
+	defer tracing.FinishSpan(span, &err)
+
+	pod, err := m.findWorkspacePod(ctx, req.Id)
+	if isKubernetesObjNotFoundError(err) {
+		return nil, status.Errorf(codes.NotFound, "workspace %s does not exist", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot find workspace: %q", err)
+	}
+	wso := workspaceObjects{Pod: pod}
+	if wso.IsWorkspaceHeadless() {
+		return nil, status.Error(codes.FailedPrecondition, "headless workspaces cannot be paused")
+	}
+	sts, err := m.getWorkspaceStatus(wso)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
+	}
+	if sts.Phase != api.WorkspacePhase_RUNNING {
+		return nil, status.Errorf(codes.FailedPrecondition, "can only pause running workspaces, workspace is %s", sts.Phase.String())
+	}
+
+	resumePod, err := newResumePod(pod)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot produce resume pod: %q", err)
+	}
+	rawPod, err := json.Marshal(resumePod)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot serialize resume pod: %q", err)
+	}
+	err = m.Clientset.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pausedWorkspaceSecretName(req.Id),
+			Namespace: m.Config.Namespace,
+			Labels:    pod.Labels,
+		},
+		Data: map[string][]byte{pausedWorkspacePodKey: rawPod},
+	})
+	if k8serr.IsAlreadyExists(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "workspace %s is already paused", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot remember paused workspace: %q", err)
+	}
+
+	// the workspace stops like any other, except that we keep its persistent volume claim around
+	err = m.markWorkspace(ctx, req.Id,
+		addMark(workspacePausedAnnotation, "true"),
+		addMark(stoppedByRequestAnnotation, stopWorkspaceImmediatelyGracePeriod.String()),
+	)
+	if err != nil {
+		derr := m.Clientset.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: pausedWorkspaceSecretName(req.Id), Namespace: m.Config.Namespace}})
+		if derr != nil {
+			log.WithError(derr).WithFields(wso.GetOWI()).Warn("cannot delete paused workspace secret of workspace which failed to pause")
+		}
+		return nil, err
+	}
+
+	return &api.PauseWorkspaceResponse{}, nil
+}
+
+// ResumeWorkspace recreates the pod of a paused workspace. The workspace may start on a different node than it was paused on.
+func (m *Manager) ResumeWorkspace(ctx context.Context, req *api.ResumeWorkspaceRequest) (res *api.ResumeWorkspaceResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "ResumeWorkspace")
+	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
+	defer tracing.FinishSpan(span, &err)
+
+	pod, err := m.getPausedWorkspacePod(ctx, req.Id)
+	if k8serr.IsNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "workspace %s is not paused", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get paused workspace: %q", err)
+	}
+
+	exists, err := m.workspaceExists(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot resume workspace: %q", err)
+	}
+	if exists {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s is still pausing", req.Id)
+	}
+
+	// the network policy of the paused workspace was deleted together with its pod
+	policy := newWorkspaceNetworkPolicy(m.Config.NetworkPolicy, pod)
+	if policy != nil {
+		err = m.Clientset.Create(ctx, policy)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			return nil, status.Errorf(codes.Internal, "cannot create workspace network policy: %q", err)
+		}
+	}
+
+	err = m.Clientset.Create(ctx, pod)
+	if k8serr.IsAlreadyExists(err) {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s is still pausing", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create workspace pod: %q", err)
+	}
+	if policy != nil {
+		err = m.ownWorkspaceNetworkPolicy(ctx, policy, pod)
+		if err != nil {
+			log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).Warn("cannot delete network policy together with workspace pod")
+		}
+	}
+
+	err = m.Clientset.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: pausedWorkspaceSecretName(req.Id), Namespace: m.Config.Namespace}})
+	if err != nil && !k8serr.IsNotFound(err) {
+		log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).Warn("cannot delete paused workspace secret")
+	}
+
+	return &api.ResumeWorkspaceResponse{
+		Url: pod.Annotations[wsk8s.WorkspaceURLAnnotation],
+	}, nil
+}
+
+// discardPausedWorkspace removes what's left of a paused workspace, i.e. its resume pod and its persistent volume claim.
+// Returns false if the workspace isn't paused.
+func (m *Manager) discardPausedWorkspace(ctx context.Context, workspaceID string) (discarded bool, err error) {
+	pod, err := m.getPausedWorkspacePod(ctx, workspaceID)
+	if k8serr.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, pvc := pod.Labels[pvcWorkspaceFeatureAnnotation]; pvc {
+		err = m.deleteWorkspacePVC(ctx, &workspaceObjects{Pod: pod})
+		if err != nil && !k8serr.IsNotFound(err) {
+			return false, xerrors.Errorf("cannot delete persistent volume claim: %w", err)
+		}
+	}
+	err = m.Clientset.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: pausedWorkspaceSecretName(workspaceID), Namespace: m.Config.Namespace}})
+	if err != nil && !k8serr.IsNotFound(err) {
+		return false, xerrors.Errorf("cannot delete paused workspace secret: %w", err)
+	}
+	return true, nil
+}
+
+// getPausedWorkspacePod returns the pod we recreate when resuming a paused workspace
+func (m *Manager) getPausedWorkspacePod(ctx context.Context, workspaceID string) (*corev1.Pod, error) {
+	var secret corev1.Secret
+	err := m.Clientset.Get(ctx, types.NamespacedName{Namespace: m.Config.Namespace, Name: pausedWorkspaceSecretName(workspaceID)}, &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	var pod corev1.Pod
+	err = json.Unmarshal(secret.Data[pausedWorkspacePodKey], &pod)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal resume pod: %w", err)
+	}
+	return &pod, nil
+}
+
+// newResumePod produces the pod of a paused workspace once it's resumed. The pod isn't bound to a node and restores the
+// workspace content from the backup taken while pausing - or from the persistent volume claim we kept around.
+func newResumePod(pod *corev1.Pod) (*corev1.Pod, error) {
+	res := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Labels:      make(map[string]string, len(pod.Labels)),
+			Annotations: make(map[string]string, len(pod.Annotations)),
+			Finalizers:  []string{gitpodFinalizerName},
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	for k, v := range pod.Labels {
+		res.Labels[k] = v
+	}
+	for k, v := range pod.Annotations {
+		res.Annotations[k] = v
+	}
+	for _, a := range transientAnnotations {
+		delete(res.Annotations, a)
+	}
+	res.Annotations[workspaceNeverReadyAnnotation] = "true"
+	res.Spec.NodeName = ""
+
+	initializer := &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Backup{Backup: &csapi.FromBackupInitializer{}}}
+	if _, pvc := pod.Labels[pvcWorkspaceFeatureAnnotation]; pvc {
+		// the content is still on the persistent volume claim
+		initializer = &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}}}
+	}
+	initCfg, err := proto.Marshal(initializer)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot marshal initializer: %w", err)
+	}
+	res.Annotations[workspaceInitializerAnnotation] = base64.StdEncoding.EncodeToString(initCfg)
+
+	return res, nil
+}
+
+func pausedWorkspaceSecretName(workspaceID string) string {
+	return fmt.Sprintf("paused-%s", workspaceID)
+}
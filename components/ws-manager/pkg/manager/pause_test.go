@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+)
+
+func TestNewResumePod(t *testing.T) {
+	pod := func(labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "ws-foo",
+				Namespace:       "default",
+				UID:             "1234",
+				ResourceVersion: "42",
+				Labels:          labels,
+				Annotations: map[string]string{
+					workspaceIDAnnotation:          "foo",
+					workspaceInitializerAnnotation: "Cg==",
+					stoppedByRequestAnnotation:     "0s",
+					workspacePausedAnnotation:      "true",
+					nodeNameAnnotation:             "node-a",
+					customTimeoutAnnotation:        "2h",
+				},
+				Finalizers: []string{gitpodFinalizerName, "foreground"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node-a",
+				Containers: []corev1.Container{{
+					Name: "workspace",
+					Env:  []corev1.EnvVar{{Name: "GITPOD_WORKSPACE_ID", Value: "foo"}},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	tests := []struct {
+		Name                string
+		Pod                 *corev1.Pod
+		ExpectedInitializer *csapi.WorkspaceInitializer
+	}{
+		{
+			Name:                "regular workspace",
+			Pod:                 pod(map[string]string{"workspaceID": "foo"}),
+			ExpectedInitializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Backup{Backup: &csapi.FromBackupInitializer{}}},
+		},
+		{
+			Name:                "pvc workspace",
+			Pod:                 pod(map[string]string{"workspaceID": "foo", pvcWorkspaceFeatureAnnotation: "true"}),
+			ExpectedInitializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := newResumePod(test.Pod)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			initCfg, err := base64.StdEncoding.DecodeString(act.Annotations[workspaceInitializerAnnotation])
+			if err != nil {
+				t.Fatalf("cannot decode initializer: %v", err)
+			}
+			var initializer csapi.WorkspaceInitializer
+			err = proto.Unmarshal(initCfg, &initializer)
+			if err != nil {
+				t.Fatalf("cannot unmarshal initializer: %v", err)
+			}
+			if diff := cmp.Diff(test.ExpectedInitializer, &initializer, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected initializer (-want +got):\n%s", diff)
+			}
+
+			delete(act.Annotations, workspaceInitializerAnnotation)
+			expectation := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ws-foo",
+					Namespace: "default",
+					Labels:    test.Pod.Labels,
+					Annotations: map[string]string{
+						workspaceIDAnnotation:         "foo",
+						workspaceNeverReadyAnnotation: "true",
+						customTimeoutAnnotation:       "2h",
+					},
+					Finalizers: []string{gitpodFinalizerName},
+				},
+				Spec: corev1.PodSpec{
+					Containers: test.Pod.Spec.Containers,
+				},
+			}
+			if diff := cmp.Diff(expectation, act); diff != "" {
+				t.Errorf("unexpected resume pod (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
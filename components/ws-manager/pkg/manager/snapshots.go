@@ -0,0 +1,226 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+// scheduledSnapshot is a snapshot we took of a workspace because its snapshot policy said so
+type scheduledSnapshot struct {
+	Name    string    `json:"name"`
+	TakenAt time.Time `json:"takenAt"`
+}
+
+// getSnapshotPolicy returns the policy by which we take periodic snapshots of a workspace, or nil if we don't take any.
+// A policy in the start request takes precedence over that of the workspace class.
+func getSnapshotPolicy(req *api.StartWorkspaceRequest, class *config.WorkspaceClass) (*config.SnapshotPolicy, error) {
+	if p := req.Spec.SnapshotPolicy; p != nil {
+		if req.Type != api.WorkspaceType_REGULAR {
+			return nil, xerrors.Errorf("snapshot policies are only supported for regular workspaces")
+		}
+		interval, err := time.ParseDuration(p.Interval)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid snapshot interval \"%s\": %w", p.Interval, err)
+		}
+		policy := &config.SnapshotPolicy{
+			Interval: util.Duration(interval),
+			Keep:     int(p.Keep),
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, xerrors.Errorf("invalid snapshot policy: %w", err)
+		}
+		return policy, nil
+	}
+
+	// class policies apply to regular workspaces only - prebuilds and image builds don't need them
+	if class == nil || class.SnapshotPolicy == nil || req.Type != api.WorkspaceType_REGULAR {
+		return nil, nil
+	}
+	return class.SnapshotPolicy, nil
+}
+
+// getScheduledSnapshots returns the snapshot policy and the periodic snapshots taken so far of a workspace pod.
+// The policy is nil if the workspace has none.
+func getScheduledSnapshots(pod *corev1.Pod) (policy *config.SnapshotPolicy, snapshots []scheduledSnapshot, err error) {
+	p, ok := pod.Annotations[snapshotPolicyAnnotation]
+	if !ok {
+		return nil, nil, nil
+	}
+	err = json.Unmarshal([]byte(p), &policy)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("invalid %s annotation: %w", snapshotPolicyAnnotation, err)
+	}
+
+	if s, ok := pod.Annotations[scheduledSnapshotsAnnotation]; ok {
+		err = json.Unmarshal([]byte(s), &snapshots)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("invalid %s annotation: %w", scheduledSnapshotsAnnotation, err)
+		}
+	}
+	return policy, snapshots, nil
+}
+
+// isSnapshotDue returns true if a workspace which started at started needs a new snapshot
+func isSnapshotDue(policy *config.SnapshotPolicy, snapshots []scheduledSnapshot, started, now time.Time) bool {
+	last := started
+	if len(snapshots) > 0 {
+		last = snapshots[len(snapshots)-1].TakenAt
+	}
+	return now.Sub(last) >= time.Duration(policy.Interval)
+}
+
+// pruneScheduledSnapshots splits snapshots into those we keep and those we delete according to the policy
+func pruneScheduledSnapshots(policy *config.SnapshotPolicy, snapshots []scheduledSnapshot) (keep, prune []scheduledSnapshot) {
+	if len(snapshots) <= policy.Keep {
+		return snapshots, nil
+	}
+	n := len(snapshots) - policy.Keep
+	return snapshots[n:], snapshots[:n]
+}
+
+// takeScheduledSnapshots takes a snapshot of all running workspaces whose snapshot policy says one is due
+func (m *Monitor) takeScheduledSnapshots(ctx context.Context) (err error) {
+	span, ctx := tracing.FromContext(ctx, "takeScheduledSnapshots")
+	defer tracing.FinishSpan(span, &err)
+
+	var pods corev1.PodList
+	err = m.manager.Clientset.List(ctx, &pods, workspaceObjectListOptions(m.manager.Config.Namespace))
+	if err != nil {
+		return xerrors.Errorf("takeScheduledSnapshots: %w", err)
+	}
+
+	errs := make([]string, 0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		workspaceID, ok := pod.Annotations[workspaceIDAnnotation]
+		if !ok {
+			continue
+		}
+
+		policy, snapshots, err := getScheduledSnapshots(pod)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", workspaceID, err))
+			continue
+		}
+		if policy == nil || !isSnapshotDue(policy, snapshots, pod.CreationTimestamp.Time, time.Now()) {
+			continue
+		}
+
+		sts, err := m.manager.getWorkspaceStatus(workspaceObjects{Pod: pod})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", workspaceID, err))
+			continue
+		}
+		if sts.Phase != api.WorkspacePhase_RUNNING {
+			continue
+		}
+
+		// taking a snapshot can take longer than the housekeeping interval
+		m.snapshotMapLock.Lock()
+		_, inProgress := m.snapshotMap[workspaceID]
+		if !inProgress {
+			m.snapshotMap[workspaceID] = struct{}{}
+		}
+		m.snapshotMapLock.Unlock()
+		if inProgress {
+			continue
+		}
+
+		go func(pod *corev1.Pod, workspaceID string, policy *config.SnapshotPolicy) {
+			defer func() {
+				m.snapshotMapLock.Lock()
+				delete(m.snapshotMap, workspaceID)
+				m.snapshotMapLock.Unlock()
+			}()
+
+			// a snapshot must not take longer than the time until the next one is due
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(policy.Interval))
+			defer cancel()
+			err := m.takeScheduledSnapshot(ctx, pod, workspaceID, policy)
+			if err != nil {
+				m.OnError(xerrors.Errorf("workspaceId=%s: cannot take scheduled snapshot: %w", workspaceID, err))
+			}
+		}(pod, workspaceID, policy)
+	}
+
+	if len(errs) > 0 {
+		return xerrors.Errorf("error during periodic run:\n%s", strings.Join(errs, "\n\t"))
+	}
+
+	return nil
+}
+
+// takeScheduledSnapshot takes a snapshot of a workspace, records it on the workspace pod and deletes
+// the snapshots the policy says we must not keep anymore.
+func (m *Monitor) takeScheduledSnapshot(ctx context.Context, pod *corev1.Pod, workspaceID string, policy *config.SnapshotPolicy) (err error) {
+	span, ctx := tracing.FromContext(ctx, "takeScheduledSnapshot")
+	tracing.ApplyOWI(span, wsk8s.GetOWIFromObject(&pod.ObjectMeta))
+	defer tracing.FinishSpan(span, &err)
+
+	resp, err := m.manager.TakeSnapshot(ctx, &api.TakeSnapshotRequest{Id: workspaceID})
+	if err != nil {
+		return err
+	}
+	takenAt := time.Now()
+
+	// the pod might have changed while we took the snapshot
+	pod, err = m.manager.findWorkspacePod(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("cannot find workspace: %w", err)
+	}
+	_, snapshots, err := getScheduledSnapshots(pod)
+	if err != nil {
+		return err
+	}
+	keep, prune := pruneScheduledSnapshots(policy, append(snapshots, scheduledSnapshot{Name: resp.Url, TakenAt: takenAt}))
+	s, err := json.Marshal(keep)
+	if err != nil {
+		return xerrors.Errorf("cannot serialize scheduled snapshots: %w", err)
+	}
+	err = m.manager.markWorkspace(ctx, workspaceID, addMark(scheduledSnapshotsAnnotation, string(s)))
+	if err != nil {
+		return xerrors.Errorf("cannot record scheduled snapshot %s: %w", resp.Url, err)
+	}
+	log.WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).WithField("snapshot", resp.Url).Info("took scheduled snapshot")
+
+	if len(prune) == 0 {
+		return nil
+	}
+	if m.manager.Content == nil {
+		log.WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).Warn("cannot delete old scheduled snapshots: no content storage configured")
+		return nil
+	}
+	errs := make([]string, 0)
+	for _, snapshot := range prune {
+		bkt, obj, err := storage.ParseSnapshotName(snapshot.Name)
+		if err == nil {
+			err = m.manager.Content.Storage.DeleteObject(ctx, bkt, &storage.DeleteObjectQuery{Name: obj})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("snapshot=%s: %q", snapshot.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return xerrors.Errorf("cannot delete old scheduled snapshots:\n%s", strings.Join(errs, "\n\t"))
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	config "github.com/gitpod-io/gitpod/ws-manager/api/config"
+)
+
+func TestGetSnapshotPolicy(t *testing.T) {
+	classPolicy := &config.SnapshotPolicy{Interval: util.Duration(time.Hour), Keep: 3}
+
+	tests := []struct {
+		Name        string
+		Type        api.WorkspaceType
+		Policy      *api.SnapshotPolicy
+		Class       *config.WorkspaceClass
+		Expectation *config.SnapshotPolicy
+		ExpectError bool
+	}{
+		{Name: "no policy"},
+		{Name: "class policy", Class: &config.WorkspaceClass{SnapshotPolicy: classPolicy}, Expectation: classPolicy},
+		{Name: "class policy prebuild", Type: api.WorkspaceType_PREBUILD, Class: &config.WorkspaceClass{SnapshotPolicy: classPolicy}},
+		{
+			Name:        "request policy",
+			Policy:      &api.SnapshotPolicy{Interval: "30m", Keep: 5},
+			Class:       &config.WorkspaceClass{SnapshotPolicy: classPolicy},
+			Expectation: &config.SnapshotPolicy{Interval: util.Duration(30 * time.Minute), Keep: 5},
+		},
+		{Name: "request policy prebuild", Type: api.WorkspaceType_PREBUILD, Policy: &api.SnapshotPolicy{Interval: "30m", Keep: 5}, ExpectError: true},
+		{Name: "invalid interval", Policy: &api.SnapshotPolicy{Interval: "often", Keep: 5}, ExpectError: true},
+		{Name: "short interval", Policy: &api.SnapshotPolicy{Interval: "10s", Keep: 5}, ExpectError: true},
+		{Name: "keep nothing", Policy: &api.SnapshotPolicy{Interval: "30m"}, ExpectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := &api.StartWorkspaceRequest{
+				Type: test.Type,
+				Spec: &api.StartWorkspaceSpec{SnapshotPolicy: test.Policy},
+			}
+			act, err := getSnapshotPolicy(req, test.Class)
+			if (err != nil) != test.ExpectError {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected policy (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScheduledSnapshots(t *testing.T) {
+	var (
+		policy  = &config.SnapshotPolicy{Interval: util.Duration(30 * time.Minute), Keep: 2}
+		started = time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+		at      = func(d time.Duration) scheduledSnapshot {
+			return scheduledSnapshot{Name: d.String(), TakenAt: started.Add(d)}
+		}
+	)
+
+	tests := []struct {
+		Name      string
+		Snapshots []scheduledSnapshot
+		Now       time.Time
+		Due       bool
+		Keep      []scheduledSnapshot
+		Prune     []scheduledSnapshot
+	}{
+		{Name: "just started", Now: started.Add(time.Minute)},
+		{Name: "first snapshot", Now: started.Add(30 * time.Minute), Due: true},
+		{Name: "recent snapshot", Snapshots: []scheduledSnapshot{at(30 * time.Minute)}, Now: started.Add(40 * time.Minute), Keep: []scheduledSnapshot{at(30 * time.Minute)}},
+		{
+			Name:      "too many snapshots",
+			Snapshots: []scheduledSnapshot{at(30 * time.Minute), at(60 * time.Minute), at(90 * time.Minute)},
+			Now:       started.Add(2 * time.Hour),
+			Due:       true,
+			Keep:      []scheduledSnapshot{at(60 * time.Minute), at(90 * time.Minute)},
+			Prune:     []scheduledSnapshot{at(30 * time.Minute)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if due := isSnapshotDue(policy, test.Snapshots, started, test.Now); due != test.Due {
+				t.Errorf("unexpected due: want %v, got %v", test.Due, due)
+			}
+
+			keep, prune := pruneScheduledSnapshots(policy, test.Snapshots)
+			if diff := cmp.Diff(test.Keep, keep); diff != "" {
+				t.Errorf("unexpected kept snapshots (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.Prune, prune); diff != "" {
+				t.Errorf("unexpected pruned snapshots (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -36,6 +36,12 @@ const (
 	// containerUnknownExitCode is the exit code containerd uses if it cannot determine the cause/exit status of
 	// a stopped container.
 	containerUnknownExitCode = 255
+
+	// podEvictedReason is the reason the kubelet and the eviction API report for pods they evicted from a node
+	podEvictedReason = "Evicted"
+
+	// podPreemptedReason is the reason the scheduler reports for pods it preempted to make room for higher priority pods
+	podPreemptedReason = "Preempted"
 )
 
 // Scheme is the default instance of runtime.Scheme to which types in the Kubernetes API are already registered.
@@ -202,6 +208,12 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 	}
 	if v, ok := wso.Pod.Annotations[customTimeoutAnnotation]; ok {
 		timeout = v
+		if ct, err := time.ParseDuration(v); err == nil {
+			// the bounds may have changed since the timeout was set
+			if bounded := m.Config.Timeouts.ClampCustom(ct); bounded != ct {
+				timeout = bounded.String()
+			}
+		}
 	}
 
 	var (
@@ -243,12 +255,14 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 				DesktopRef:    desktopIdeImage,
 				SupervisorRef: supervisorImage,
 			},
-			Url:     wsurl,
-			Type:    tpe,
-			Timeout: timeout,
+			Url:            wsurl,
+			Type:           tpe,
+			Timeout:        timeout,
+			WorkspaceClass: wso.Pod.Annotations[workspaceClassAnnotation],
 		},
 		Conditions: &api.WorkspaceConditions{
-			Snapshot: wso.Pod.Annotations[workspaceSnapshotAnnotation],
+			Snapshot:       wso.Pod.Annotations[workspaceSnapshotAnnotation],
+			VolumeSnapshot: wso.Pod.Annotations[volumeSnapshotAnnotation],
 		},
 		Runtime: &api.WorkspaceRuntimeInfo{
 			NodeName: wso.Pod.Spec.NodeName,
@@ -261,10 +275,19 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 		},
 	}
 
+	if _, snapshots, err := getScheduledSnapshots(wso.Pod); err == nil {
+		for _, s := range snapshots {
+			status.Conditions.ScheduledSnapshots = append(status.Conditions.ScheduledSnapshots, s.Name)
+		}
+	} else {
+		log.WithError(err).WithFields(wso.GetOWI()).Warn("cannot get scheduled snapshots")
+	}
+
 	err = m.extractStatusFromPod(status, wso)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot get workspace status: %w", err)
 	}
+	status.StopReason = extractStopReason(wso.Pod, status)
 
 	return status, nil
 }
@@ -301,6 +324,11 @@ func (m *Manager) extractStatusFromPod(result *api.WorkspaceStatus, wso workspac
 
 	result.Spec.ExposedPorts = extractExposedPorts(pod).Ports
 
+	// eviction is not a failure of the workspace itself, but we want to tell users what happened to their workspace
+	if reason, evicted := extractEviction(wso); evicted {
+		result.Conditions.Evicted = reason
+	}
+
 	// check failure states, i.e. determine value of result.Failed
 	failure, phase := extractFailure(wso)
 	result.Conditions.Failed = failure
@@ -317,6 +345,9 @@ func (m *Manager) extractStatusFromPod(result *api.WorkspaceStatus, wso workspac
 	if _, sbr := pod.Annotations[stoppedByRequestAnnotation]; sbr {
 		result.Conditions.StoppedByRequest = api.WorkspaceConditionBool_TRUE
 	}
+	if _, paused := pod.Annotations[workspacePausedAnnotation]; paused {
+		result.Conditions.Paused = api.WorkspaceConditionBool_TRUE
+	}
 	if wso.IsWorkspaceHeadless() {
 		for _, cs := range pod.Status.ContainerStatuses {
 			if cs.State.Terminated != nil && cs.State.Terminated.Message != "" {
@@ -382,6 +413,14 @@ func (m *Manager) extractStatusFromPod(result *api.WorkspaceStatus, wso workspac
 		return nil
 	}
 
+	if result.Conditions.Evicted != "" {
+		// The pod was evicted, but has not been deleted yet. Its containers are gone, so all that's left to do
+		// is to back up the workspace content and stop it.
+		result.Phase = api.WorkspacePhase_STOPPING
+		result.Message = "workspace was evicted from its node and is stopping"
+		return nil
+	}
+
 	status := pod.Status
 	if status.Phase == corev1.PodPending {
 		// check if any container is still pulling images
@@ -514,7 +553,7 @@ func extractFailure(wso workspaceObjects) (string, *api.WorkspacePhase) {
 	}
 
 	status := pod.Status
-	if status.Phase == corev1.PodFailed && (status.Reason != "" || status.Message != "") {
+	if status.Phase == corev1.PodFailed && status.Reason != podEvictedReason && (status.Reason != "" || status.Message != "") {
 		// Don't force the phase to UNKNONWN here to leave a chance that we may detect the actual phase of
 		// the workspace, e.g. stopping.
 		return fmt.Sprintf("%s: %s", status.Reason, status.Message), nil
@@ -721,7 +760,7 @@ func (m *Manager) isWorkspaceTimedOut(wso workspaceObjects) (reason string, err
 		}
 		if ctv, ok := wso.Pod.Annotations[customTimeoutAnnotation]; ok {
 			if ct, err := time.ParseDuration(ctv); err == nil {
-				timeout = util.Duration(ct)
+				timeout = util.Duration(m.Config.Timeouts.ClampCustom(ct))
 			} else {
 				log.WithError(err).WithField("customTimeout", ctv).WithFields(wsk8s.GetOWIFromObject(&wso.Pod.ObjectMeta)).Warn("pod had custom timeout annotation set, but could not parse its value. Defaulting to ws-manager config.")
 			}
@@ -753,6 +792,67 @@ func (m *Manager) isWorkspaceTimedOut(wso workspaceObjects) (reason string, err
 	}
 }
 
+// extractEviction determines if a workspace pod was evicted or preempted from its node, and if so, why.
+func extractEviction(wso workspaceObjects) (reason string, evicted bool) {
+	pod := wso.Pod
+	if reason, ok := pod.Annotations[workspaceEvictedAnnotation]; ok {
+		return reason, true
+	}
+
+	if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == podEvictedReason {
+		reason = pod.Status.Message
+		evicted = true
+	}
+	for _, evt := range wso.Events {
+		if evt.Reason == podEvictedReason || evt.Reason == podPreemptedReason {
+			reason = evt.Message
+			evicted = true
+			break
+		}
+	}
+	if evicted && reason == "" {
+		reason = "workspace was evicted from its node"
+	}
+	return reason, evicted
+}
+
+// extractStopReason explains why a workspace is stopping or has stopped. The pod of a workspace can carry several reasons,
+// e.g. a workspace that timed out can also fail its final backup, in which case the most specific one wins.
+func extractStopReason(pod *corev1.Pod, status *api.WorkspaceStatus) api.StopReason {
+	if status.Phase != api.WorkspacePhase_STOPPING && status.Phase != api.WorkspacePhase_STOPPED {
+		return api.StopReason_STOP_REASON_NONE
+	}
+
+	if _, paused := pod.Annotations[workspacePausedAnnotation]; paused {
+		return api.StopReason_STOP_REASON_PAUSED
+	}
+	if rawDisposalStatus, ok := pod.Annotations[disposalStatusAnnotation]; ok {
+		var ds workspaceDisposalStatus
+		if err := json.Unmarshal([]byte(rawDisposalStatus), &ds); err == nil && ds.BackupFailure != "" {
+			return api.StopReason_STOP_REASON_BACKUP_FAILED
+		}
+	}
+	if r, ok := api.StopReason_value[pod.Annotations[wsk8s.StopReasonAnnotation]]; ok && r != int32(api.StopReason_STOP_REASON_NONE) {
+		return api.StopReason(r)
+	}
+
+	conditions := status.Conditions
+	switch {
+	case conditions.Evicted != "":
+		return api.StopReason_STOP_REASON_EVICTED
+	case conditions.Timeout != "":
+		return api.StopReason_STOP_REASON_TIMEOUT
+	case conditions.StoppedByRequest == api.WorkspaceConditionBool_TRUE:
+		// pods stopped before we recorded the reason
+		return api.StopReason_STOP_REASON_USER_REQUEST
+	case conditions.Failed != "" || conditions.HeadlessTaskFailed != "":
+		return api.StopReason_STOP_REASON_FAILED
+	case status.Spec.Headless:
+		return api.StopReason_STOP_REASON_COMPLETED
+	}
+	return api.StopReason_STOP_REASON_NONE
+}
+
 // hasNetworkNotReadyEvent determines if a workspace experienced a network outage - now, or any time in the past - based on
 // its kubernetes events
 func hasNetworkNotReadyEvent(wso workspaceObjects) bool {
@@ -15,6 +15,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
 	ctesting "github.com/gitpod-io/gitpod/common-go/testing"
 	"github.com/gitpod-io/gitpod/common-go/util"
 	"github.com/gitpod-io/gitpod/ws-manager/api"
@@ -219,3 +220,70 @@ func TestGetNodeName(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractStopReason(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		Status      *api.WorkspaceStatus
+		Expectation api.StopReason
+	}{
+		{
+			Name:        "running",
+			Annotations: map[string]string{wsk8s.StopReasonAnnotation: "STOP_REASON_PENALTY"},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_RUNNING, Conditions: &api.WorkspaceConditions{}},
+			Expectation: api.StopReason_STOP_REASON_NONE,
+		},
+		{
+			Name:        "penalty",
+			Annotations: map[string]string{wsk8s.StopReasonAnnotation: "STOP_REASON_PENALTY"},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPING, Conditions: &api.WorkspaceConditions{Failed: "container workspace completed; exit code 137"}},
+			Expectation: api.StopReason_STOP_REASON_PENALTY,
+		},
+		{
+			Name:        "admin request",
+			Annotations: map[string]string{stoppedByRequestAnnotation: "30s", wsk8s.StopReasonAnnotation: "STOP_REASON_ADMIN_REQUEST"},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPING, Conditions: &api.WorkspaceConditions{StoppedByRequest: api.WorkspaceConditionBool_TRUE}},
+			Expectation: api.StopReason_STOP_REASON_ADMIN_REQUEST,
+		},
+		{
+			Name:        "stopped by request without reason",
+			Annotations: map[string]string{stoppedByRequestAnnotation: "30s"},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPED, Conditions: &api.WorkspaceConditions{StoppedByRequest: api.WorkspaceConditionBool_TRUE}},
+			Expectation: api.StopReason_STOP_REASON_USER_REQUEST,
+		},
+		{
+			Name:        "invalid reason",
+			Annotations: map[string]string{wsk8s.StopReasonAnnotation: "foobar"},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPING, Conditions: &api.WorkspaceConditions{Timeout: "workspace timed out"}},
+			Expectation: api.StopReason_STOP_REASON_TIMEOUT,
+		},
+		{
+			Name:        "paused",
+			Annotations: map[string]string{workspacePausedAnnotation: "true", stoppedByRequestAnnotation: "0s"},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPING, Conditions: &api.WorkspaceConditions{StoppedByRequest: api.WorkspaceConditionBool_TRUE}},
+			Expectation: api.StopReason_STOP_REASON_PAUSED,
+		},
+		{
+			Name:        "backup failure trumps timeout",
+			Annotations: map[string]string{disposalStatusAnnotation: `{"backupFailure":"no space left on device"}`},
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPED, Conditions: &api.WorkspaceConditions{Timeout: "workspace timed out"}},
+			Expectation: api.StopReason_STOP_REASON_BACKUP_FAILED,
+		},
+		{
+			Name:        "evicted",
+			Status:      &api.WorkspaceStatus{Phase: api.WorkspacePhase_STOPPING, Conditions: &api.WorkspaceConditions{Evicted: "node is shutting down", Failed: "pod failed"}},
+			Expectation: api.StopReason_STOP_REASON_EVICTED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: test.Annotations}}
+			act := extractStopReason(pod, test.Status)
+			if act != test.Expectation {
+				t.Errorf("unexpected stop reason: expected %s, got %s", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+)
+
+const (
+	// volumeSnapshotGroup is the API group of CSI volume snapshots
+	volumeSnapshotGroup = "snapshot.storage.k8s.io"
+	// volumeSnapshotKind is the kind of CSI volume snapshots
+	volumeSnapshotKind = "VolumeSnapshot"
+
+	// volumeSnapshotPollInterval is the interval in which we check if a volume snapshot is ready to use
+	volumeSnapshotPollInterval = 1 * time.Second
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: volumeSnapshotGroup, Version: "v1", Kind: volumeSnapshotKind}
+
+// takeVolumeSnapshot snapshots the persistent volume claim of a workspace and waits until the snapshot is ready to use.
+// The snapshot is named after the workspace instance, which makes this function idempotent.
+func (m *Manager) takeVolumeSnapshot(ctx context.Context, wso *workspaceObjects) (name string, err error) {
+	span, ctx := tracing.FromContext(ctx, "takeVolumeSnapshot")
+	defer tracing.FinishSpan(span, &err)
+
+	workspaceID, ok := wso.WorkspaceID()
+	if !ok {
+		return "", xerrors.Errorf("cannot find %s annotation", workspaceIDAnnotation)
+	}
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(workspaceID)
+	snapshot.SetNamespace(m.Config.Namespace)
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": wso.Pod.Name,
+		},
+	}
+	if class, ok := m.Config.WorkspaceClasses[wso.Pod.Annotations[workspaceClassAnnotation]]; ok && class.PVC != nil && class.PVC.SnapshotClass != "" {
+		spec["volumeSnapshotClassName"] = class.PVC.SnapshotClass
+	}
+	err = unstructured.SetNestedMap(snapshot.Object, spec, "spec")
+	if err != nil {
+		return "", xerrors.Errorf("cannot create volume snapshot: %w", err)
+	}
+
+	err = m.Clientset.Create(ctx, snapshot)
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		return "", xerrors.Errorf("cannot create volume snapshot: %w", err)
+	}
+
+	err = wait.PollImmediateUntil(volumeSnapshotPollInterval, func() (bool, error) {
+		var current unstructured.Unstructured
+		current.SetGroupVersionKind(volumeSnapshotGVK)
+		err := m.Clientset.Get(ctx, types.NamespacedName{Namespace: m.Config.Namespace, Name: workspaceID}, &current)
+		if err != nil {
+			return false, nil
+		}
+		if msg, found, _ := unstructured.NestedString(current.Object, "status", "error", "message"); found {
+			return false, xerrors.Errorf("volume snapshot failed: %s", msg)
+		}
+		ready, _, _ := unstructured.NestedBool(current.Object, "status", "readyToUse")
+		return ready, nil
+	}, ctx.Done())
+	if err != nil {
+		return "", xerrors.Errorf("cannot wait for volume snapshot: %w", err)
+	}
+
+	return workspaceID, nil
+}
+
+// deleteWorkspacePVC deletes the persistent volume claim of a workspace. Kubernetes keeps the claim
+// around until the workspace pod is gone.
+func (m *Manager) deleteWorkspacePVC(ctx context.Context, wso *workspaceObjects) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Name = wso.Pod.Name
+	pvc.Namespace = wso.Pod.Namespace
+	err := m.Clientset.Delete(ctx, pvc)
+	if err != nil && !isKubernetesObjNotFoundError(err) {
+		return xerrors.Errorf("cannot delete persistent volume claim: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"os"
 	"path/filepath"
@@ -19,6 +20,7 @@ import (
 	"github.com/gitpod-io/gitpod/ws-proxy/pkg/config"
 	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
 	"github.com/gitpod-io/gitpod/ws-proxy/pkg/sshproxy"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/tcpproxy"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc"
@@ -28,6 +30,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
@@ -35,6 +38,9 @@ var (
 	verbose bool
 )
 
+// routingTableFallbackCacheSize is the number of workspaces the routing table caches fallback lookups for
+const routingTableFallbackCacheSize = 1000
+
 // runCmd represents the run command.
 var runCmd = &cobra.Command{
 	Use:   "run <config.json>",
@@ -83,6 +89,8 @@ var runCmd = &cobra.Command{
 
 		log.Infof("workspace info provider started")
 
+		var infoProvider proxy.WorkspaceInfoProvider = workspaceInfoProvider
+
 		var heartbeat sshproxy.Heartbeat
 		if wsm := cfg.WorkspaceManager; wsm != nil {
 			var dialOption grpc.DialOption = grpc.WithInsecure()
@@ -107,9 +115,17 @@ var runCmd = &cobra.Command{
 				log.WithError(err).Fatal("cannot connect to ws-manager")
 			}
 
+			wsmClient := wsmanapi.NewWorkspaceManagerClient(conn)
 			heartbeat = &sshproxy.WorkspaceManagerHeartbeat{
-				Client: wsmanapi.NewWorkspaceManagerClient(conn),
+				Client: wsmClient,
 			}
+
+			routingTable, err := proxy.NewRoutingTable(wsmClient, workspaceInfoProvider, proxy.NewPodIPResolver(mgr.GetAPIReader(), cfg.Namespace), routingTableFallbackCacheSize)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create routing table")
+			}
+			go routingTable.Run(context.Background())
+			infoProvider = routingTable
 		}
 
 		// SSH Gateway
@@ -131,7 +147,7 @@ var runCmd = &cobra.Command{
 				signers = append(signers, hostSigner)
 			}
 			if len(signers) > 0 {
-				server := sshproxy.New(signers, workspaceInfoProvider, heartbeat)
+				server := sshproxy.New(signers, infoProvider, heartbeat)
 				l, err := net.Listen("tcp", ":2200")
 				if err != nil {
 					panic(err)
@@ -141,7 +157,30 @@ var runCmd = &cobra.Command{
 			}
 		}
 
-		go proxy.NewWorkspaceProxy(cfg.Ingress, cfg.Proxy, proxy.HostBasedRouter(cfg.Ingress.Header, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffixRegex), workspaceInfoProvider, signers).MustServe()
+		// TCP port exposure
+		if cfg.TCPProxy != nil {
+			crt, err := tls.LoadX509KeyPair(cfg.Proxy.HTTPS.Certificate, cfg.Proxy.HTTPS.Key)
+			if err != nil {
+				log.WithError(err).Fatal("cannot load TCP proxy certificate")
+			}
+			server := tcpproxy.New(&tls.Config{
+				Certificates: []tls.Certificate{crt},
+				MinVersion:   tls.VersionTLS12,
+			}, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix, infoProvider)
+			l, err := net.Listen("tcp", cfg.TCPProxy.Address)
+			if err != nil {
+				log.WithError(err).Fatal("cannot start TCP proxy")
+			}
+			go server.Serve(l)
+			log.WithField("address", cfg.TCPProxy.Address).Info("TCP proxy is up and running")
+		}
+
+		wsproxy := proxy.NewWorkspaceProxy(cfg.Ingress, cfg.Proxy, proxy.HostBasedRouter(cfg.Ingress.Header, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffixRegex), infoProvider, signers)
+		err = wsproxy.RegisterMetrics(metrics.Registry)
+		if err != nil {
+			log.WithError(err).Fatal("cannot register proxy metrics")
+		}
+		go wsproxy.MustServe()
 		log.Infof("started proxying on %s", cfg.Ingress.HTTPAddress)
 
 		log.Info("🚪 ws-proxy is up and running")
@@ -22,6 +22,13 @@ type Config struct {
 	ReadinessProbeAddr string                       `json:"readinessProbeAddr"`
 	Namespace          string                       `json:"namespace"`
 	WorkspaceManager   *WorkspaceManagerConn        `json:"wsManager"`
+	TCPProxy           *TCPProxyConfig              `json:"tcpProxy,omitempty"`
+}
+
+// TCPProxyConfig configures the exposure of workspace ports over raw TCP.
+type TCPProxyConfig struct {
+	// Address is the address the TCP proxy listens on, e.g. :4433
+	Address string `json:"address"`
 }
 
 type WorkspaceManagerConn struct {
@@ -43,6 +50,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if c.TCPProxy != nil && c.TCPProxy.Address == "" {
+		return xerrors.Errorf("tcpProxy.address is mandatory")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,243 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// accessLogForwardInterval is the time between two batches of access log entries sent to a workspace
+	accessLogForwardInterval = 5 * time.Second
+	// accessLogQueueSize is the number of entries which can be pending before new entries are dropped
+	accessLogQueueSize = 1000
+)
+
+// accessLogEntry describes a request to a workspace port. It does not contain the request path
+// which might carry secrets, e.g. webhook tokens, but only its hash.
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	Port      uint32    `json:"port"`
+	Method    string    `json:"method"`
+	PathHash  string    `json:"pathHash"`
+	Status    uint32    `json:"status"`
+	Bytes     uint64    `json:"bytes"`
+	LatencyMs uint32    `json:"latencyMs"`
+}
+
+// accessLogHandler logs a sample of the requests to workspace ports. If configured, the logged requests
+// are forwarded to supervisor so that workspace owners can inspect them.
+func accessLogHandler(config *Config, infoProvider WorkspaceInfoProvider) mux.MiddlewareFunc {
+	cfg := config.AccessLog
+	if cfg.SampleRate <= 0 {
+		return func(h http.Handler) http.Handler { return h }
+	}
+
+	var forwarder *accessLogForwarder
+	if cfg.ForwardToWorkspace {
+		forwarder = newAccessLogForwarder(config, infoProvider)
+		go forwarder.Run()
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if rand.Float64() >= cfg.SampleRate {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			start := time.Now()
+			rec := &accessLogResponseWriter{ResponseWriter: resp}
+			h.ServeHTTP(rec, req)
+
+			coords := getWorkspaceCoords(req)
+			port, _ := strconv.ParseUint(coords.Port, 10, 16)
+			entry := &accessLogEntry{
+				Time:      start.UTC(),
+				Port:      uint32(port),
+				Method:    req.Method,
+				PathHash:  hashPath(req.URL.Path),
+				Status:    uint32(rec.Status()),
+				Bytes:     rec.bytes,
+				LatencyMs: uint32(time.Since(start).Milliseconds()),
+			}
+
+			var instanceID string
+			if info := infoProvider.WorkspaceInfo(coords.ID); info != nil {
+				instanceID = info.InstanceID
+			}
+			log.WithFields(log.OWI("", coords.ID, instanceID)).WithFields(logrus.Fields{
+				"port":      entry.Port,
+				"method":    entry.Method,
+				"pathHash":  entry.PathHash,
+				"status":    entry.Status,
+				"bytes":     entry.Bytes,
+				"latencyMs": entry.LatencyMs,
+			}).Info("workspace port access")
+
+			if forwarder != nil {
+				forwarder.Forward(coords.ID, entry)
+			}
+		})
+	}
+}
+
+// hashPath returns a short hash of the request path, which identifies requests to the same path without revealing it.
+func hashPath(path string) string {
+	h := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(h[:8])
+}
+
+// accessLogResponseWriter records the status and size of a response.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+
+	status int
+	bytes  uint64
+}
+
+// Status returns the status code of the response.
+func (w *accessLogResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *accessLogResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += uint64(n)
+	return n, err
+}
+
+func (w *accessLogResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *accessLogResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("response writer does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return hj.Hijack()
+}
+
+type forwardedAccessLogEntry struct {
+	WorkspaceID string
+	Entry       *accessLogEntry
+}
+
+// accessLogForwarder sends access log entries to the supervisor of their workspace in batches.
+type accessLogForwarder struct {
+	Config       *Config
+	InfoProvider WorkspaceInfoProvider
+	Client       *http.Client
+
+	queue chan forwardedAccessLogEntry
+}
+
+func newAccessLogForwarder(config *Config, infoProvider WorkspaceInfoProvider) *accessLogForwarder {
+	return &accessLogForwarder{
+		Config:       config,
+		InfoProvider: infoProvider,
+		Client:       &http.Client{Timeout: 5 * time.Second},
+		queue:        make(chan forwardedAccessLogEntry, accessLogQueueSize),
+	}
+}
+
+// Forward queues an entry for the workspace. Entries are dropped if the queue is full.
+func (f *accessLogForwarder) Forward(workspaceID string, entry *accessLogEntry) {
+	select {
+	case f.queue <- forwardedAccessLogEntry{WorkspaceID: workspaceID, Entry: entry}:
+	default:
+		log.WithFields(log.OWI("", workspaceID, "")).Debug("access log queue is full - dropping entry")
+	}
+}
+
+// Run sends the queued entries periodically. It never returns.
+func (f *accessLogForwarder) Run() {
+	ticker := time.NewTicker(accessLogForwardInterval)
+	defer ticker.Stop()
+
+	batches := make(map[string][]*accessLogEntry)
+	for {
+		select {
+		case e := <-f.queue:
+			batches[e.WorkspaceID] = append(batches[e.WorkspaceID], e.Entry)
+		case <-ticker.C:
+			for workspaceID, entries := range batches {
+				f.send(workspaceID, entries)
+			}
+			batches = make(map[string][]*accessLogEntry)
+		}
+	}
+}
+
+func (f *accessLogForwarder) send(workspaceID string, entries []*accessLogEntry) {
+	workspaceInfo := f.InfoProvider.WorkspaceInfo(workspaceID)
+	if workspaceInfo == nil {
+		return
+	}
+	supervisor, err := buildWorkspacePodURL(workspaceInfo.IPAddress, fmt.Sprint(f.Config.WorkspacePodConfig.SupervisorPort))
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Debug("cannot forward access log")
+		return
+	}
+	body, err := json.Marshal(map[string]interface{}{"entries": entries})
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Debug("cannot forward access log")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, supervisor.String()+"/_supervisor/v1/port/access_log", bytes.NewReader(body))
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Debug("cannot forward access log")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// supervisor only accepts access log entries from those who can derive the access log token from the owner token
+	if workspaceInfo.Auth != nil {
+		req.Header.Set("Authorization", "Bearer "+kubernetes.AccessLogToken(workspaceInfo.Auth.OwnerToken))
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Debug("cannot forward access log")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.WithField("status", resp.StatusCode).WithFields(log.OWI("", workspaceID, "")).Debug("cannot forward access log")
+	}
+}
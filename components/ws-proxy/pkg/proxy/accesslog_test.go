@@ -0,0 +1,66 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLogResponseWriter(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Handler        http.HandlerFunc
+		ExpectedStatus int
+		ExpectedBytes  uint64
+	}{
+		{
+			Name:           "implicit status",
+			Handler:        func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("hello")) },
+			ExpectedStatus: http.StatusOK,
+			ExpectedBytes:  5,
+		},
+		{
+			Name: "explicit status",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("queued"))
+			},
+			ExpectedStatus: http.StatusAccepted,
+			ExpectedBytes:  6,
+		},
+		{
+			Name:           "no content",
+			Handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			ExpectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rec := &accessLogResponseWriter{ResponseWriter: httptest.NewRecorder()}
+			test.Handler(rec, httptest.NewRequest("GET", "http://example.com", nil))
+
+			if rec.Status() != test.ExpectedStatus {
+				t.Errorf("unexpected status: expected %d, got %d", test.ExpectedStatus, rec.Status())
+			}
+			if rec.bytes != test.ExpectedBytes {
+				t.Errorf("unexpected bytes: expected %d, got %d", test.ExpectedBytes, rec.bytes)
+			}
+		})
+	}
+}
+
+func TestHashPath(t *testing.T) {
+	if hashPath("/webhook/secret-token") == hashPath("/webhook/other-token") {
+		t.Error("different paths must have different hashes")
+	}
+	if hashPath("/webhook") != hashPath("/webhook") {
+		t.Error("the same path must have the same hash")
+	}
+	if len(hashPath("/webhook")) != 16 {
+		t.Errorf("unexpected hash length: %d", len(hashPath("/webhook")))
+	}
+}
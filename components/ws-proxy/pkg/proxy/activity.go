@@ -0,0 +1,183 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// portActivityInterval is the minimum time between two activity signals for the same workspace
+const portActivityInterval = 30 * time.Second
+
+// portActivityHandler signals traffic on workspace ports to supervisor, so that using an application
+// which runs in the workspace keeps the workspace from timing out. WebSocket and Server-Sent-Event
+// connections keep signalling for as long as data flows through them.
+func portActivityHandler(config *Config, infoProvider WorkspaceInfoProvider) func(h http.Handler) http.Handler {
+	var (
+		mu         sync.Mutex
+		lastSignal = make(map[string]time.Time)
+		client     = &http.Client{Timeout: 5 * time.Second}
+	)
+	shouldSignal := func(workspaceID string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		if now.Sub(lastSignal[workspaceID]) < portActivityInterval {
+			return false
+		}
+		lastSignal[workspaceID] = now
+		for id, t := range lastSignal {
+			if now.Sub(t) >= portActivityInterval {
+				delete(lastSignal, id)
+			}
+		}
+		return true
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			coords := getWorkspaceCoords(req)
+			if coords.ID == "" {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			signal := func() {
+				if shouldSignal(coords.ID) {
+					go signalPortActivity(client, config, infoProvider, coords.ID)
+				}
+			}
+			signal()
+			if !isStreamingRequest(req) {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			stream := &activityResponseWriter{ResponseWriter: resp}
+			ctx, cancel := context.WithCancel(req.Context())
+			defer cancel()
+			go func() {
+				ticker := time.NewTicker(portActivityInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if stream.sawActivity() {
+							signal()
+						}
+					}
+				}
+			}()
+			h.ServeHTTP(stream, req)
+		})
+	}
+}
+
+func signalPortActivity(client *http.Client, config *Config, infoProvider WorkspaceInfoProvider, workspaceID string) {
+	workspaceInfo := infoProvider.WorkspaceInfo(workspaceID)
+	if workspaceInfo == nil {
+		return
+	}
+	supervisor, err := buildWorkspacePodURL(workspaceInfo.IPAddress, fmt.Sprint(config.WorkspacePodConfig.SupervisorPort))
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Debug("cannot signal port activity")
+		return
+	}
+
+	resp, err := client.Post(supervisor.String()+"/_supervisor/v1/activity/mark_active", "application/json", strings.NewReader(`{"source":"port"}`))
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Debug("cannot signal port activity")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.WithField("status", resp.StatusCode).WithFields(log.OWI("", workspaceID, "")).Debug("cannot signal port activity")
+	}
+}
+
+// isStreamingRequest returns true if the request opens a long-lived WebSocket or Server-Sent-Event connection.
+func isStreamingRequest(req *http.Request) bool {
+	if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+		return true
+	}
+	return strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+}
+
+// activityResponseWriter records whether data flows through a streaming connection.
+// It supports flushing for Server-Sent-Events and hijacking for WebSocket upgrades.
+type activityResponseWriter struct {
+	http.ResponseWriter
+
+	active int32
+}
+
+func (w *activityResponseWriter) markActive() {
+	atomic.StoreInt32(&w.active, 1)
+}
+
+// sawActivity returns true if data was transferred since the last call.
+func (w *activityResponseWriter) sawActivity() bool {
+	return atomic.SwapInt32(&w.active, 0) == 1
+}
+
+func (w *activityResponseWriter) Write(b []byte) (int, error) {
+	w.markActive()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *activityResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *activityResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("response writer does not support hijacking")
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &activityConn{Conn: conn, w: w}, brw, nil
+}
+
+type activityConn struct {
+	net.Conn
+
+	w *activityResponseWriter
+}
+
+func (c *activityConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.w.markActive()
+	}
+	return n, err
+}
+
+func (c *activityConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.w.markActive()
+	}
+	return n, err
+}
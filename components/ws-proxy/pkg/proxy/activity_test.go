@@ -0,0 +1,70 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsStreamingRequest(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Header      http.Header
+		Expectation bool
+	}{
+		{
+			Name:        "plain request",
+			Header:      http.Header{"Accept": []string{"text/html"}},
+			Expectation: false,
+		},
+		{
+			Name:        "websocket upgrade",
+			Header:      http.Header{"Connection": []string{"Upgrade"}, "Upgrade": []string{"websocket"}},
+			Expectation: true,
+		},
+		{
+			Name:        "websocket upgrade mixed case",
+			Header:      http.Header{"Connection": []string{"Upgrade"}, "Upgrade": []string{"WebSocket"}},
+			Expectation: true,
+		},
+		{
+			Name:        "server-sent events",
+			Header:      http.Header{"Accept": []string{"text/event-stream"}},
+			Expectation: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com", nil)
+			req.Header = test.Header
+
+			act := isStreamingRequest(req)
+			if act != test.Expectation {
+				t.Errorf("unexpected result: expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestActivityResponseWriter(t *testing.T) {
+	w := &activityResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	if w.sawActivity() {
+		t.Fatal("unexpected activity before any write")
+	}
+
+	_, err := w.Write([]byte("data: hello\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	if !w.sawActivity() {
+		t.Error("expected activity after write")
+	}
+	if w.sawActivity() {
+		t.Error("unexpected activity: sawActivity should reset")
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru/simplelru"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	cacheResultHit    = "hit"
+	cacheResultMiss   = "miss"
+	cacheResultBypass = "bypass"
+)
+
+// hashedAssetPattern matches file names which contain a content hash, e.g. main.3f4a9c2d.js or index-B3kd92x1.css,
+// as emitted by common web bundlers. Such files never change, a new build gets a new name.
+var hashedAssetPattern = regexp.MustCompile(`[.-]([0-9A-Za-z_]{8,64})\.(js|mjs|css|map|wasm|woff2?|ttf|otf|eot|png|jpe?g|gif|svg|webp|avif|ico)$`)
+
+// staticAssetCache caches immutable static assets served from workspace ports, so that web previews of large
+// single page applications load quickly over high-latency links. Entries are bound to the workspace instance
+// which served them, i.e. they are invalidated when the workspace restarts.
+type staticAssetCache struct {
+	Config StaticAssetCacheConfig
+
+	mu      sync.Mutex
+	entries *lru.LRU
+	size    int64
+
+	requests *prometheus.CounterVec
+	bytes    prometheus.Gauge
+}
+
+type cachedAsset struct {
+	InstanceID string
+	Status     int
+	Header     http.Header
+	Body       []byte
+}
+
+func newStaticAssetCache(cfg StaticAssetCacheConfig) *staticAssetCache {
+	c := &staticAssetCache{
+		Config: cfg,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ws_proxy_static_asset_cache_requests_total",
+			Help: "Number of requests for static assets of workspace ports, by cache result",
+		}, []string{"result"}),
+		bytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ws_proxy_static_asset_cache_bytes",
+			Help: "Size of the static assets in the cache",
+		}),
+	}
+	// the LRU is bounded by size in bytes rather than by number of entries, see add
+	c.entries, _ = lru.NewLRU(int(^uint(0)>>1), func(key, value interface{}) {
+		c.size -= int64(len(value.(*cachedAsset).Body))
+	})
+	return c
+}
+
+// RegisterMetrics registers the cache metrics.
+func (c *staticAssetCache) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, m := range []prometheus.Collector{c.requests, c.bytes} {
+		err := reg.Register(m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get returns the cached asset if it was served by the given workspace instance. Assets of previous instances are removed.
+func (c *staticAssetCache) get(key, instanceID string) *cachedAsset {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.entries.Get(key)
+	if !ok {
+		return nil
+	}
+	asset := v.(*cachedAsset)
+	if asset.InstanceID != instanceID {
+		c.entries.Remove(key)
+		c.bytes.Set(float64(c.size))
+		return nil
+	}
+	return asset
+}
+
+func (c *staticAssetCache) add(key string, asset *cachedAsset) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries.Remove(key)
+	c.entries.Add(key, asset)
+	c.size += int64(len(asset.Body))
+	for c.size > c.Config.MaxSizeBytes && c.entries.Len() > 0 {
+		c.entries.RemoveOldest()
+	}
+	c.bytes.Set(float64(c.size))
+}
+
+// Handler serves immutable static assets of workspace ports from the cache and caches them on their first request.
+// It must be installed after the workspace authentication handler.
+func (c *staticAssetCache) Handler(infoProvider WorkspaceInfoProvider) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if !c.Config.Enabled || !isCacheableAssetRequest(req) {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			coords := getWorkspaceCoords(req)
+			info := infoProvider.WorkspaceInfo(coords.ID)
+			if info == nil {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			key := strings.Join([]string{coords.ID, coords.Port, req.URL.RequestURI(), req.Header.Get("Accept-Encoding")}, "\x00")
+			if asset := c.get(key, info.InstanceID); asset != nil {
+				c.requests.WithLabelValues(cacheResultHit).Inc()
+				for name, values := range asset.Header {
+					resp.Header()[name] = values
+				}
+				resp.Header().Set("X-Gitpod-Cache", "HIT")
+				resp.WriteHeader(asset.Status)
+				_, _ = resp.Write(asset.Body)
+				return
+			}
+
+			rec := &assetRecorder{ResponseWriter: resp, maxSize: c.Config.MaxEntrySizeBytes}
+			h.ServeHTTP(rec, req)
+			if !rec.cacheable() {
+				c.requests.WithLabelValues(cacheResultBypass).Inc()
+				return
+			}
+			c.requests.WithLabelValues(cacheResultMiss).Inc()
+			c.add(key, &cachedAsset{
+				InstanceID: info.InstanceID,
+				Status:     rec.status,
+				Header:     rec.header,
+				Body:       rec.body.Bytes(),
+			})
+		})
+	}
+}
+
+func isCacheableAssetRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return false
+	}
+	m := hashedAssetPattern.FindStringSubmatch(req.URL.Path)
+	// plain words like "bootstrap" are no content hashes
+	return len(m) > 1 && strings.ContainsAny(m[1], "0123456789")
+}
+
+// isCacheableAssetResponse returns true if the response may be shared between all clients of a workspace port.
+func isCacheableAssetResponse(status int, header http.Header) bool {
+	if status != http.StatusOK {
+		return false
+	}
+	if header.Get("Set-Cookie") != "" || header.Get("Access-Control-Allow-Origin") != "" {
+		return false
+	}
+	cc := strings.ToLower(header.Get("Cache-Control"))
+	if strings.Contains(cc, "no-store") || strings.Contains(cc, "private") || strings.Contains(cc, "no-cache") {
+		return false
+	}
+	for _, v := range header.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f != "" && !strings.EqualFold(f, "Accept-Encoding") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// assetRecorder passes a response through while keeping a copy of it for the cache.
+type assetRecorder struct {
+	http.ResponseWriter
+	maxSize int64
+
+	status   int
+	header   http.Header
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *assetRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+		r.header = r.ResponseWriter.Header().Clone()
+		if cl, err := strconv.ParseInt(r.header.Get("Content-Length"), 10, 64); err == nil && cl > r.maxSize {
+			r.overflow = true
+		}
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *assetRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.overflow {
+		if int64(r.body.Len()+len(b)) > r.maxSize {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *assetRecorder) cacheable() bool {
+	return !r.overflow && isCacheableAssetResponse(r.status, r.header)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+)
+
+func TestIsCacheableAssetRequest(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Method      string
+		Path        string
+		Range       string
+		Expectation bool
+	}{
+		{Desc: "webpack hash", Method: http.MethodGet, Path: "/static/js/main.3f4a9c2d.js", Expectation: true},
+		{Desc: "vite hash", Method: http.MethodGet, Path: "/assets/index-B3kd92x1.css", Expectation: true},
+		{Desc: "font", Method: http.MethodGet, Path: "/fonts/inter.5e2a1b7c.woff2", Expectation: true},
+		{Desc: "no hash", Method: http.MethodGet, Path: "/main.js"},
+		{Desc: "word instead of hash", Method: http.MethodGet, Path: "/vendor/bootstrap-components.js"},
+		{Desc: "html", Method: http.MethodGet, Path: "/index.3f4a9c2d.html"},
+		{Desc: "post", Method: http.MethodPost, Path: "/static/js/main.3f4a9c2d.js"},
+		{Desc: "range request", Method: http.MethodGet, Path: "/static/js/main.3f4a9c2d.js", Range: "bytes=0-100"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			req := httptest.NewRequest(test.Method, "https://3000-amaranth-smelt-9ba20cc1.ws.gitpod.io"+test.Path, nil)
+			if test.Range != "" {
+				req.Header.Set("Range", test.Range)
+			}
+			act := isCacheableAssetRequest(req)
+			if act != test.Expectation {
+				t.Errorf("unexpected result: expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestStaticAssetCacheHandler(t *testing.T) {
+	const workspaceID = "amaranth-smelt-9ba20cc1"
+	type request struct {
+		Path       string
+		InstanceID string
+	}
+	type result struct {
+		Body  string
+		Cache string
+	}
+	tests := []struct {
+		Desc        string
+		Config      StaticAssetCacheConfig
+		Header      http.Header
+		Requests    []request
+		Expectation []result
+	}{
+		{
+			Desc:   "disabled",
+			Config: StaticAssetCacheConfig{MaxSizeBytes: 1024, MaxEntrySizeBytes: 1024},
+			Requests: []request{
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+			},
+			Expectation: []result{{Body: "1"}, {Body: "2"}},
+		},
+		{
+			Desc:   "cached",
+			Config: StaticAssetCacheConfig{Enabled: true, MaxSizeBytes: 1024, MaxEntrySizeBytes: 1024},
+			Requests: []request{
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+			},
+			Expectation: []result{{Body: "1"}, {Body: "1", Cache: "HIT"}},
+		},
+		{
+			Desc:   "invalidated on restart",
+			Config: StaticAssetCacheConfig{Enabled: true, MaxSizeBytes: 1024, MaxEntrySizeBytes: 1024},
+			Requests: []request{
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i2"},
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i2"},
+			},
+			Expectation: []result{{Body: "1"}, {Body: "2"}, {Body: "2", Cache: "HIT"}},
+		},
+		{
+			Desc:   "evicts least recently used",
+			Config: StaticAssetCacheConfig{Enabled: true, MaxSizeBytes: 1, MaxEntrySizeBytes: 1},
+			Requests: []request{
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+				{Path: "/vendor.8b1e0f3a.js", InstanceID: "i1"},
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+			},
+			Expectation: []result{{Body: "1"}, {Body: "2"}, {Body: "3"}},
+		},
+		{
+			Desc:   "no-store",
+			Config: StaticAssetCacheConfig{Enabled: true, MaxSizeBytes: 1024, MaxEntrySizeBytes: 1024},
+			Header: http.Header{"Cache-Control": {"no-store"}},
+			Requests: []request{
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+				{Path: "/main.3f4a9c2d.js", InstanceID: "i1"},
+			},
+			Expectation: []result{{Body: "1"}, {Body: "2"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var (
+				served int
+				cache  = newStaticAssetCache(test.Config)
+				info   = &fakeWsInfoProvider{}
+			)
+			upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served++
+				for name, values := range test.Header {
+					w.Header()[name] = values
+				}
+				_, _ = io.WriteString(w, string(rune('0'+served)))
+			})
+			handler := cache.Handler(info)(upstream)
+
+			var act []result
+			for _, r := range test.Requests {
+				info.infos = []WorkspaceInfo{{WorkspaceID: workspaceID, InstanceID: r.InstanceID}}
+				req := httptest.NewRequest(http.MethodGet, "https://3000-"+workspaceID+".ws.gitpod.io"+r.Path, nil)
+				req = mux.SetURLVars(req, map[string]string{
+					workspaceIDIdentifier:   workspaceID,
+					workspacePortIdentifier: "3000",
+				})
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				act = append(act, result{Body: rec.Body.String(), Cache: rec.Header().Get("X-Gitpod-Cache")})
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected responses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@
 package proxy
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -55,10 +58,11 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 			}
 
 			if port != "" {
-				// this is a workspace port request and ports can be public or private.
-				// For public ports no tokens or cookies matter, private ports are subject
+				// this is a workspace port request and ports can be public, authenticated or private.
+				// For public ports no tokens or cookies matter, authenticated ports are accessible with
+				// the port auth cookie Gitpod hands out to every logged-in user. Private ports are subject
 				// to the same access policies as the workspace itself is.
-				var isPublic bool
+				visibility := api.PortVisibility_PORT_VISIBILITY_PRIVATE
 
 				prt, err := strconv.ParseUint(port, 10, 16)
 				if err != nil {
@@ -66,18 +70,46 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 				} else {
 					for _, p := range ws.Ports {
 						if p.Port == uint32(prt) {
-							isPublic = p.Visibility == api.PortVisibility_PORT_VISIBILITY_PUBLIC
+							visibility = p.Visibility
 
 							break
 						}
 					}
 				}
 
-				if isPublic {
+				switch visibility {
+				case api.PortVisibility_PORT_VISIBILITY_PUBLIC:
 					// workspace port is free for all - no tokens or cookies matter
 					h.ServeHTTP(resp, req)
 
 					return
+				case api.PortVisibility_PORT_VISIBILITY_AUTHENTICATED:
+					cn := fmt.Sprintf("%s%s_port_auth_", cookiePrefix, ws.InstanceID)
+					if c, err := req.Cookie(cn); err == nil && ws.Auth != nil && validPortAuthToken(c.Value, ws.Auth.OwnerToken, ws.InstanceID) {
+						h.ServeHTTP(resp, req)
+
+						return
+					}
+					if !hasOwnerToken(req, cookiePrefix, ws.InstanceID) {
+						if isBrowserNavigation(req) {
+							// Gitpod logs the user in if need be, sets the port auth cookie and sends them back here
+							returnTo := url.URL{Scheme: "https", Host: req.Host, Path: req.URL.Path, RawQuery: req.URL.RawQuery}
+							redirect := url.URL{
+								Scheme:   "https",
+								Host:     domain,
+								Path:     fmt.Sprintf("/api/auth/workspace-port/%s", ws.InstanceID),
+								RawQuery: url.Values{"returnTo": []string{returnTo.String()}}.Encode(),
+							}
+							http.Redirect(resp, req, redirect.String(), http.StatusFound)
+
+							return
+						}
+						log.WithField("cookieName", cn).Debug("no port auth cookie present")
+						resp.WriteHeader(http.StatusUnauthorized)
+
+						return
+					}
+					// the workspace owner may access authenticated ports, too
 				}
 
 				// port seems to be private - subject it to the same access policy as the workspace itself
@@ -115,3 +147,33 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 		})
 	}
 }
+
+// hasOwnerToken returns true if the request carries an owner token, valid or not
+func hasOwnerToken(req *http.Request, cookiePrefix, instanceID string) bool {
+	if req.Header.Get("x-gitpod-owner-token") != "" {
+		return true
+	}
+	_, err := req.Cookie(fmt.Sprintf("%s%s_owner_", cookiePrefix, instanceID))
+	return err == nil
+}
+
+// portAuthToken is the value of the port auth cookie for a workspace instance. Gitpod derives the same value
+// when it hands out the cookie, so that we don't need to reveal the owner token to other users.
+func portAuthToken(ownerToken, instanceID string) string {
+	mac := hmac.New(sha256.New, []byte(ownerToken))
+	_, _ = mac.Write([]byte(instanceID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validPortAuthToken(tkn, ownerToken, instanceID string) bool {
+	if ownerToken == "" {
+		return false
+	}
+	return hmac.Equal([]byte(tkn), []byte(portAuthToken(ownerToken, instanceID)))
+}
+
+// isBrowserNavigation returns true if the request is likely a user navigating to a page in their browser,
+// i.e. we can redirect them to log in.
+func isBrowserNavigation(req *http.Request) bool {
+	return req.Method == http.MethodGet && strings.Contains(req.Header.Get("Accept"), "text/html")
+}
@@ -56,6 +56,17 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 				Ports: []*api.PortSpec{{Port: testPort, Visibility: api.PortVisibility_PORT_VISIBILITY_PUBLIC}},
 			},
 		}
+		authenticatedPortInfos = map[string]*WorkspaceInfo{
+			workspaceID: {
+				WorkspaceID: workspaceID,
+				InstanceID:  instanceID,
+				Auth: &api.WorkspaceAuthentication{
+					Admission:  api.AdmissionLevel_ADMIT_OWNER_ONLY,
+					OwnerToken: ownerToken,
+				},
+				Ports: []*api.PortSpec{{Port: testPort, Visibility: api.PortVisibility_PORT_VISIBILITY_AUTHENTICATED}},
+			},
+		}
 		admitEveryoneInfos = map[string]*WorkspaceInfo{
 			workspaceID: {
 				WorkspaceID: workspaceID,
@@ -65,12 +76,14 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 		}
 	)
 	tests := []struct {
-		Name        string
-		Infos       map[string]*WorkspaceInfo
-		OwnerCookie string
-		WorkspaceID string
-		Port        string
-		Expected    testResult
+		Name           string
+		Infos          map[string]*WorkspaceInfo
+		OwnerCookie    string
+		PortAuthCookie string
+		Accept         string
+		WorkspaceID    string
+		Port           string
+		Expected       testResult
 	}{
 		{
 			Name:        "workspace not found",
@@ -197,6 +210,71 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 				StatusCode:    http.StatusOK,
 			},
 		},
+		{
+			Name:           "authenticated port",
+			Infos:          authenticatedPortInfos,
+			WorkspaceID:    workspaceID,
+			PortAuthCookie: portAuthToken(ownerToken, instanceID),
+			Port:           strconv.Itoa(testPort),
+			Expected: testResult{
+				HandlerCalled: true,
+				StatusCode:    http.StatusOK,
+			},
+		},
+		{
+			Name:        "authenticated port with owner cookie",
+			Infos:       authenticatedPortInfos,
+			WorkspaceID: workspaceID,
+			OwnerCookie: ownerToken,
+			Port:        strconv.Itoa(testPort),
+			Expected: testResult{
+				HandlerCalled: true,
+				StatusCode:    http.StatusOK,
+			},
+		},
+		{
+			Name:           "authenticated port with wrong cookie",
+			Infos:          authenticatedPortInfos,
+			WorkspaceID:    workspaceID,
+			PortAuthCookie: portAuthToken("another-owner-token", instanceID),
+			Port:           strconv.Itoa(testPort),
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusUnauthorized,
+			},
+		},
+		{
+			Name:        "authenticated port without cookie",
+			Infos:       authenticatedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusUnauthorized,
+			},
+		},
+		{
+			Name:        "authenticated port without cookie in browser",
+			Infos:       authenticatedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			Accept:      "text/html,application/xhtml+xml",
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusFound,
+			},
+		},
+		{
+			Name:           "private port with port auth cookie",
+			Infos:          ownerOnlyInfos,
+			WorkspaceID:    workspaceID,
+			PortAuthCookie: portAuthToken(ownerToken, instanceID),
+			Port:           strconv.Itoa(testPort),
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusUnauthorized,
+			},
+		},
 		{
 			Name:        "broken port",
 			Infos:       publicPortInfos,
@@ -233,6 +311,12 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 			if test.OwnerCookie != "" {
 				setOwnerTokenCookie(req, instanceID, test.OwnerCookie)
 			}
+			if test.PortAuthCookie != "" {
+				req.AddCookie(&http.Cookie{Name: "_test_domain_com_ws_" + instanceID + "_port_auth_", Value: test.PortAuthCookie})
+			}
+			if test.Accept != "" {
+				req.Header.Set("Accept", test.Accept)
+			}
 			vars := map[string]string{
 				workspaceIDIdentifier: test.WorkspaceID,
 			}
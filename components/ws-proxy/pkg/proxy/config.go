@@ -27,7 +27,11 @@ type Config struct {
 	GitpodInstallation *GitpodInstallation `json:"gitpodInstallation"`
 	WorkspacePodConfig *WorkspacePodConfig `json:"workspacePodConfig"`
 
-	BuiltinPages BuiltinPagesConfig `json:"builtinPages"`
+	BuiltinPages     BuiltinPagesConfig     `json:"builtinPages"`
+	AccessLog        AccessLogConfig        `json:"accessLog"`
+	RateLimit        RateLimitConfig        `json:"rateLimit"`
+	PortHeaders      PortHeadersConfig      `json:"portHeaders"`
+	StaticAssetCache StaticAssetCacheConfig `json:"staticAssetCache"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime.
@@ -40,6 +44,10 @@ func (c *Config) Validate() error {
 		c.BlobServer,
 		c.GitpodInstallation,
 		c.WorkspacePodConfig,
+		&c.AccessLog,
+		&c.RateLimit,
+		&c.PortHeaders,
+		&c.StaticAssetCache,
 	} {
 		err := v.Validate()
 		if err != nil {
@@ -162,9 +170,112 @@ func (c *TransportConfig) Validate() error {
 	)
 }
 
+// AccessLogConfig configures access logging of workspace port traffic.
+type AccessLogConfig struct {
+	// SampleRate is the fraction of requests which are logged, between 0 (none) and 1 (all)
+	SampleRate float64 `json:"sampleRate"`
+	// ForwardToWorkspace makes ws-proxy forward logged requests to supervisor, so that workspace owners can inspect them
+	ForwardToWorkspace bool `json:"forwardToWorkspace"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime.
+func (c *AccessLogConfig) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.SampleRate, validation.Min(0.0), validation.Max(1.0)),
+	)
+}
+
+// RateLimitConfig configures the rate limiting of requests to publicly shared workspace ports.
+// Limits which are zero are not enforced.
+type RateLimitConfig struct {
+	Enabled bool `json:"enabled"`
+	// PerIP is the number of requests per second a single client IP may send to public ports
+	PerIP      float64 `json:"perIP"`
+	PerIPBurst int     `json:"perIPBurst"`
+	// PerWorkspace is the number of requests per second all public ports of a workspace may receive
+	PerWorkspace      float64 `json:"perWorkspace"`
+	PerWorkspaceBurst int     `json:"perWorkspaceBurst"`
+	// MaxConnectionsPerWorkspace is the number of requests and WebSocket connections which may be in flight for the public ports of a workspace
+	MaxConnectionsPerWorkspace int `json:"maxConnectionsPerWorkspace"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime.
+func (c *RateLimitConfig) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.PerIP, validation.Min(0.0)),
+		validation.Field(&c.PerIPBurst, validation.Min(0)),
+		validation.Field(&c.PerWorkspace, validation.Min(0.0)),
+		validation.Field(&c.PerWorkspaceBurst, validation.Min(0)),
+		validation.Field(&c.MaxConnectionsPerWorkspace, validation.Min(0)),
+	)
+}
+
+// StaticAssetCacheConfig configures the caching of immutable static assets served from workspace ports.
+type StaticAssetCacheConfig struct {
+	Enabled bool `json:"enabled"`
+	// MaxSizeBytes is the total size of all cached assets
+	MaxSizeBytes int64 `json:"maxSizeBytes"`
+	// MaxEntrySizeBytes is the size of the largest asset we cache
+	MaxEntrySizeBytes int64 `json:"maxEntrySizeBytes"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime.
+func (c *StaticAssetCacheConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.MaxSizeBytes, validation.Required, validation.Min(int64(1))),
+		validation.Field(&c.MaxEntrySizeBytes, validation.Required, validation.Min(int64(1)), validation.Max(c.MaxSizeBytes)),
+	)
+}
+
+// PortHeadersConfig configures the headers ws-proxy adds to the responses of workspace ports.
+// Headers which workspace owners configure for a port in their .gitpod.yml take precedence.
+type PortHeadersConfig struct {
+	// Default applies to all ports which have no configuration of their own
+	Default PortHeaders `json:"default"`
+	// Ports configures the headers of individual ports by port number
+	Ports map[string]PortHeaders `json:"ports,omitempty"`
+}
+
+// PortHeaders are the headers added to the responses of a workspace port.
+type PortHeaders struct {
+	// CORSAllowedOrigins are the origins which may access the port from a browser, "*" allows all origins, but without credentials
+	CORSAllowedOrigins []string `json:"corsAllowedOrigins,omitempty"`
+	// XFrameOptions is sent as X-Frame-Options header. If empty, the header is removed so that the port can be embedded.
+	XFrameOptions string `json:"xFrameOptions,omitempty"`
+	// Custom headers are added to every response of the port
+	Custom map[string]string `json:"custom,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime.
+func (c *PortHeadersConfig) Validate() error {
+	err := c.Default.Validate()
+	if err != nil {
+		return xerrors.Errorf("default: %w", err)
+	}
+	for port, headers := range c.Ports {
+		err := headers.Validate()
+		if err != nil {
+			return xerrors.Errorf("port %s: %w", port, err)
+		}
+	}
+	return nil
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime.
+func (c *PortHeaders) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.XFrameOptions, validation.In("DENY", "SAMEORIGIN")),
+	)
+}
+
 // BuiltinPagesConfig configures pages served directly by ws-proxy.
 type BuiltinPagesConfig struct {
 	Location string `json:"location"`
+	// AutoStartWorkspace makes the workspace-not-running page start the workspace right away
+	AutoStartWorkspace bool `json:"autoStartWorkspace,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime.
@@ -178,6 +289,7 @@ func (c *BuiltinPagesConfig) Validate() error {
 			validation.Required,
 			validation.By(validateFileExists("")),
 			validation.By(validateFileExists(builtinPagePortNotFound)),
+			validation.By(validateFileExists(builtinPageWorkspaceNotRunning)),
 		),
 	)
 }
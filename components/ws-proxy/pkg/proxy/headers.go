@@ -0,0 +1,173 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// corsPreflightMaxAge is the number of seconds browsers may cache the answer to a CORS preflight request
+const corsPreflightMaxAge = "600"
+
+// reservedPortHeaders cannot be set by workspace owners, because ws-proxy or the HTTP protocol manage them
+var reservedPortHeaders = map[string]struct{}{
+	"Access-Control-Allow-Credentials": {},
+	"Access-Control-Allow-Headers":     {},
+	"Access-Control-Allow-Methods":     {},
+	"Access-Control-Allow-Origin":      {},
+	"Access-Control-Max-Age":           {},
+	"Connection":                       {},
+	"Content-Length":                   {},
+	"Keep-Alive":                       {},
+	"Set-Cookie":                       {},
+	"Strict-Transport-Security":        {},
+	"Transfer-Encoding":                {},
+	"Upgrade":                          {},
+	"X-Frame-Options":                  {},
+}
+
+// effectivePortHeaders returns the headers to add to the responses of a workspace port. Headers which the workspace
+// owner configured for the port take precedence over the ones configured by the operator.
+func effectivePortHeaders(config PortHeadersConfig, info *WorkspaceInfo, port string) PortHeaders {
+	res := config.Default
+	if p, ok := config.Ports[port]; ok {
+		res = p
+	}
+
+	spec := findPortSpec(info, port)
+	if spec == nil || spec.Headers == nil {
+		return res
+	}
+	owner := spec.Headers
+	if len(owner.CorsAllowedOrigins) > 0 {
+		res.CORSAllowedOrigins = owner.CorsAllowedOrigins
+	}
+	if owner.XFrameOptions != "" {
+		res.XFrameOptions = owner.XFrameOptions
+	}
+	if len(owner.Custom) > 0 {
+		custom := make(map[string]string, len(res.Custom)+len(owner.Custom))
+		for name, value := range res.Custom {
+			custom[name] = value
+		}
+		for name, value := range owner.Custom {
+			name = http.CanonicalHeaderKey(name)
+			if _, reserved := reservedPortHeaders[name]; reserved {
+				continue
+			}
+			custom[name] = value
+		}
+		res.Custom = custom
+	}
+	return res
+}
+
+// allowedOrigin returns the value of the Access-Control-Allow-Origin header for a request from the given origin,
+// whether the origin may send credentials, and false if the origin may not access the port at all.
+// Only explicitly listed origins may send credentials. Otherwise "*" would let any site read the responses
+// of private ports using the owner's cookies.
+func (h PortHeaders) allowedOrigin(origin string) (allowOrigin string, credentials bool, ok bool) {
+	if origin == "" {
+		return "", false, false
+	}
+	var wildcard bool
+	for _, o := range h.CORSAllowedOrigins {
+		if o == origin {
+			return origin, true, true
+		}
+		wildcard = wildcard || o == "*"
+	}
+	if wildcard {
+		return "*", false, true
+	}
+	return "", false, false
+}
+
+func (h PortHeaders) apply(header http.Header, origin string) {
+	if h.XFrameOptions == "" {
+		// workspace ports are embedded in the IDE's preview by default
+		header.Del("X-Frame-Options")
+	} else {
+		header.Set("X-Frame-Options", h.XFrameOptions)
+	}
+	for name, value := range h.Custom {
+		header.Set(name, value)
+	}
+	if allowOrigin, credentials, ok := h.allowedOrigin(origin); ok {
+		header.Set("Access-Control-Allow-Origin", allowOrigin)
+		if credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+		header.Add("Vary", "Origin")
+	}
+}
+
+// withPortHeaders adds the configured headers to the responses of workspace ports.
+func withPortHeaders(config *Config, infoProvider WorkspaceInfoProvider) proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
+			coords := getWorkspaceCoords(req)
+			headers := effectivePortHeaders(config.PortHeaders, infoProvider.WorkspaceInfo(coords.ID), coords.Port)
+			headers.apply(resp.Header, req.Header.Get("Origin"))
+			return nil
+		})
+	}
+}
+
+// portCORSPreflightHandler answers CORS preflight requests from allowed origins. Browsers send preflight requests
+// without credentials, hence they would not pass the workspace authentication of private ports.
+func portCORSPreflightHandler(config *Config, infoProvider WorkspaceInfoProvider) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodOptions || req.Header.Get("Access-Control-Request-Method") == "" {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			coords := getWorkspaceCoords(req)
+			headers := effectivePortHeaders(config.PortHeaders, infoProvider.WorkspaceInfo(coords.ID), coords.Port)
+			allowOrigin, credentials, ok := headers.allowedOrigin(req.Header.Get("Origin"))
+			if !ok {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			header := resp.Header()
+			header.Set("Access-Control-Allow-Origin", allowOrigin)
+			if credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			header.Set("Access-Control-Allow-Methods", req.Header.Get("Access-Control-Request-Method"))
+			if reqHeaders := req.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", reqHeaders)
+			}
+			header.Set("Access-Control-Max-Age", corsPreflightMaxAge)
+			header.Add("Vary", "Origin")
+			resp.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// findPortSpec returns the spec of an exposed workspace port, or nil if the port is not exposed.
+func findPortSpec(info *WorkspaceInfo, port string) *api.PortSpec {
+	if info == nil || port == "" {
+		return nil
+	}
+	prt, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil
+	}
+	for _, p := range info.Ports {
+		if p.Port == uint32(prt) {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestEffectivePortHeaders(t *testing.T) {
+	config := PortHeadersConfig{
+		Default: PortHeaders{
+			Custom: map[string]string{"X-Operator": "default"},
+		},
+		Ports: map[string]PortHeaders{
+			"8080": {
+				CORSAllowedOrigins: []string{"https://example.com"},
+				XFrameOptions:      "DENY",
+			},
+		},
+	}
+	workspace := func(headers *api.PortHeaders) *WorkspaceInfo {
+		return &WorkspaceInfo{Ports: []*api.PortSpec{{Port: 3000, Headers: headers}}}
+	}
+
+	tests := []struct {
+		Desc        string
+		Info        *WorkspaceInfo
+		Port        string
+		Expectation PortHeaders
+	}{
+		{
+			Desc:        "unknown workspace",
+			Port:        "3000",
+			Expectation: config.Default,
+		},
+		{
+			Desc:        "operator port config",
+			Info:        workspace(nil),
+			Port:        "8080",
+			Expectation: config.Ports["8080"],
+		},
+		{
+			Desc: "owner config overrides operator config",
+			Info: workspace(&api.PortHeaders{
+				CorsAllowedOrigins: []string{"*"},
+				XFrameOptions:      "SAMEORIGIN",
+				Custom:             map[string]string{"cache-control": "no-store"},
+			}),
+			Port: "3000",
+			Expectation: PortHeaders{
+				CORSAllowedOrigins: []string{"*"},
+				XFrameOptions:      "SAMEORIGIN",
+				Custom:             map[string]string{"X-Operator": "default", "Cache-Control": "no-store"},
+			},
+		},
+		{
+			Desc: "owner cannot set reserved headers",
+			Info: workspace(&api.PortHeaders{
+				Custom: map[string]string{"set-cookie": "foo=bar", "Access-Control-Allow-Origin": "*", "X-Owner": "yes"},
+			}),
+			Port: "3000",
+			Expectation: PortHeaders{
+				Custom: map[string]string{"X-Operator": "default", "X-Owner": "yes"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act := effectivePortHeaders(config, test.Info, test.Port)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPortHeadersApply(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Headers     PortHeaders
+		Response    http.Header
+		Origin      string
+		Expectation http.Header
+	}{
+		{
+			Desc:        "removes X-Frame-Options by default",
+			Response:    http.Header{"X-Frame-Options": {"DENY"}},
+			Expectation: http.Header{},
+		},
+		{
+			Desc:        "sets X-Frame-Options",
+			Headers:     PortHeaders{XFrameOptions: "SAMEORIGIN"},
+			Response:    http.Header{},
+			Expectation: http.Header{"X-Frame-Options": {"SAMEORIGIN"}},
+		},
+		{
+			Desc:        "custom headers",
+			Headers:     PortHeaders{Custom: map[string]string{"Cache-Control": "no-store"}},
+			Response:    http.Header{"Cache-Control": {"max-age=60"}},
+			Expectation: http.Header{"Cache-Control": {"no-store"}},
+		},
+		{
+			Desc:     "allowed origin",
+			Headers:  PortHeaders{CORSAllowedOrigins: []string{"https://example.com"}},
+			Response: http.Header{},
+			Origin:   "https://example.com",
+			Expectation: http.Header{
+				"Access-Control-Allow-Origin":      {"https://example.com"},
+				"Access-Control-Allow-Credentials": {"true"},
+				"Vary":                             {"Origin"},
+			},
+		},
+		{
+			Desc:     "any origin",
+			Headers:  PortHeaders{CORSAllowedOrigins: []string{"*"}},
+			Response: http.Header{},
+			Origin:   "https://example.com",
+			Expectation: http.Header{
+				"Access-Control-Allow-Origin": {"*"},
+				"Vary":                        {"Origin"},
+			},
+		},
+		{
+			Desc:     "listed origin with any origin",
+			Headers:  PortHeaders{CORSAllowedOrigins: []string{"*", "https://example.com"}},
+			Response: http.Header{},
+			Origin:   "https://example.com",
+			Expectation: http.Header{
+				"Access-Control-Allow-Origin":      {"https://example.com"},
+				"Access-Control-Allow-Credentials": {"true"},
+				"Vary":                             {"Origin"},
+			},
+		},
+		{
+			Desc:        "disallowed origin",
+			Headers:     PortHeaders{CORSAllowedOrigins: []string{"https://example.com"}},
+			Response:    http.Header{},
+			Origin:      "https://evil.com",
+			Expectation: http.Header{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			test.Headers.apply(test.Response, test.Origin)
+			if diff := cmp.Diff(test.Expectation, test.Response); diff != "" {
+				t.Errorf("unexpected response headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
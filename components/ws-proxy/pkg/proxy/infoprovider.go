@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -162,6 +163,19 @@ func mapPodToWorkspaceInfo(pod *corev1.Pod) *WorkspaceInfo {
 	}
 }
 
+// NewPodIPResolver creates a PodIPResolver which reads workspace pods from the given namespace.
+// Use a reader which is not backed by the informer cache to avoid its lag.
+func NewPodIPResolver(reader client.Reader, namespace string) PodIPResolver {
+	return func(ctx context.Context, podName string) (string, error) {
+		var pod corev1.Pod
+		err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: podName}, &pod)
+		if err != nil {
+			return "", err
+		}
+		return pod.Status.PodIP, nil
+	}
+}
+
 // WorkspaceInfo return the WorkspaceInfo available for the given workspaceID.
 func (r *RemoteWorkspaceInfoProvider) WorkspaceInfo(workspaceID string) *WorkspaceInfo {
 	workspaces, err := r.store.ByIndex(workspaceIndex, workspaceID)
@@ -5,6 +5,7 @@
 package proxy
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
@@ -78,6 +80,10 @@ func proxyPass(config *RouteHandlerConfig, infoProvider WorkspaceInfoProvider, r
 		// TODO(cw): we should cache the proxy for some time for each target URL
 		proxy := httputil.NewSingleHostReverseProxy(targetURL)
 		proxy.Transport = h.Transport
+		if isGRPCRequest(req) {
+			// gRPC streams messages in both directions, hence responses must not be buffered
+			proxy.FlushInterval = -1
+		}
 		proxy.ModifyResponse = func(resp *http.Response) error {
 			url := resp.Request.URL
 			if url == nil {
@@ -172,6 +178,20 @@ func createDefaultTransport(config *TransportConfig) *http.Transport {
 	}
 }
 
+// createH2CTransport creates a transport which speaks HTTP/2 over cleartext (h2c) with prior knowledge.
+func createH2CTransport(config *TransportConfig) *http2.Transport {
+	dialer := &net.Dialer{
+		Timeout:   time.Duration(config.ConnectTimeout), // default: 30s
+		KeepAlive: 30 * time.Second,
+	}
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		},
+	}
+}
+
 // tell the browser to cache for 1 year and don't ask the server during this period.
 func withLongTermCaching() proxyPassOpt {
 	return func(cfg *proxyPassConfig) {
@@ -185,15 +205,6 @@ func withLongTermCaching() proxyPassOpt {
 	}
 }
 
-func withXFrameOptionsFilter() proxyPassOpt {
-	return func(cfg *proxyPassConfig) {
-		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
-			resp.Header.Del("X-Frame-Options")
-			return nil
-		})
-	}
-}
-
 type workspaceTransport struct {
 	transport http.RoundTripper
 }
@@ -212,3 +223,31 @@ func withWorkspaceTransport() proxyPassOpt {
 		h.Transport = &workspaceTransport{h.Transport}
 	}
 }
+
+// isGRPCRequest returns true if the request is a gRPC call, which requires HTTP/2 end-to-end.
+func isGRPCRequest(req *http.Request) bool {
+	return req.ProtoMajor == 2 && strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
+
+// grpcTransport forwards gRPC calls using h2c and all other requests using the regular transport.
+// Workspace ports serve plain HTTP, so without h2c HTTP/2 would be downgraded to HTTP/1.1 which breaks gRPC clients.
+type grpcTransport struct {
+	transport    http.RoundTripper
+	h2cTransport http.RoundTripper
+}
+
+func (t *grpcTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	if isGRPCRequest(req) {
+		return t.h2cTransport.RoundTrip(req)
+	}
+	return t.transport.RoundTrip(req)
+}
+
+func withGRPCTransport(h2cTransport http.RoundTripper) proxyPassOpt {
+	return func(h *proxyPassConfig) {
+		h.Transport = &grpcTransport{
+			transport:    h.Transport,
+			h2cTransport: h2cTransport,
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/klauspost/cpuid/v2"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/crypto/ssh"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
@@ -24,6 +25,9 @@ type WorkspaceProxy struct {
 	WorkspaceRouter       WorkspaceRouter
 	WorkspaceInfoProvider WorkspaceInfoProvider
 	SSHHostSigners        []ssh.Signer
+
+	rateLimiter *portRateLimiter
+	assetCache  *staticAssetCache
 }
 
 // NewWorkspaceProxy creates a new workspace proxy.
@@ -34,9 +38,28 @@ func NewWorkspaceProxy(ingress HostBasedIngressConfig, config Config, workspaceR
 		WorkspaceRouter:       workspaceRouter,
 		WorkspaceInfoProvider: workspaceInfoProvider,
 		SSHHostSigners:        signers,
+		rateLimiter:           newPortRateLimiter(config.RateLimit),
+		assetCache:            newStaticAssetCache(config.StaticAssetCache),
 	}
 }
 
+// RegisterMetrics registers the metrics of the proxy.
+func (p *WorkspaceProxy) RegisterMetrics(reg prometheus.Registerer) error {
+	if p.rateLimiter != nil {
+		err := p.rateLimiter.RegisterMetrics(reg)
+		if err != nil {
+			return err
+		}
+	}
+	if p.assetCache != nil {
+		err := p.assetCache.RegisterMetrics(reg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
 	target := "https://" + r.Host + r.URL.Path
 	if len(r.URL.RawQuery) > 0 {
@@ -93,7 +116,14 @@ func (p *WorkspaceProxy) Handler() (http.Handler, error) {
 	r := mux.NewRouter()
 
 	// install routes
-	handlerConfig, err := NewRouteHandlerConfig(&p.Config, WithDefaultAuth(p.WorkspaceInfoProvider))
+	opts := []RouteHandlerConfigOpt{WithDefaultAuth(p.WorkspaceInfoProvider)}
+	if p.rateLimiter != nil {
+		opts = append(opts, withPortRateLimit(p.rateLimiter, p.WorkspaceInfoProvider))
+	}
+	if p.assetCache != nil {
+		opts = append(opts, withStaticAssetCache(p.assetCache, p.WorkspaceInfoProvider))
+	}
+	handlerConfig, err := NewRouteHandlerConfig(&p.Config, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,186 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// rateLimiterIdleTimeout is the time after which the limiter of an inactive client IP or workspace is dropped
+const rateLimiterIdleTimeout = 10 * time.Minute
+
+const (
+	limitReasonIP          = "ip"
+	limitReasonWorkspace   = "workspace"
+	limitReasonConnections = "connections"
+)
+
+// portRateLimiter limits the requests to publicly shared workspace ports, so that they cannot be abused
+// e.g. for traffic reflection. Private and authenticated ports are not limited.
+type portRateLimiter struct {
+	Config RateLimitConfig
+
+	mu          sync.Mutex
+	ips         map[string]*idleLimiter
+	workspaces  map[string]*idleLimiter
+	connections map[string]int
+	lastGC      time.Time
+
+	requestsLimited   *prometheus.CounterVec
+	connectionsActive prometheus.Gauge
+}
+
+type idleLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+func newPortRateLimiter(cfg RateLimitConfig) *portRateLimiter {
+	return &portRateLimiter{
+		Config:      cfg,
+		ips:         make(map[string]*idleLimiter),
+		workspaces:  make(map[string]*idleLimiter),
+		connections: make(map[string]int),
+
+		requestsLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ws_proxy_public_port_requests_limited_total",
+			Help: "Number of requests to public workspace ports rejected by the rate limiter, by reason",
+		}, []string{"reason"}),
+		connectionsActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ws_proxy_public_port_connections",
+			Help: "Number of requests to public workspace ports in flight, including WebSocket connections",
+		}),
+	}
+}
+
+// RegisterMetrics registers the rate limiter metrics.
+func (l *portRateLimiter) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{l.requestsLimited, l.connectionsActive} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler rejects requests to public ports which exceed the configured limits with 429 Too Many Requests.
+func (l *portRateLimiter) Handler(infoProvider WorkspaceInfoProvider) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if !l.Config.Enabled {
+			return h
+		}
+
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			coords := getWorkspaceCoords(req)
+			if !isPublicPort(infoProvider.WorkspaceInfo(coords.ID), coords.Port) {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			reason, ok := l.acquire(clientIP(req), coords.ID, time.Now())
+			if !ok {
+				l.requestsLimited.WithLabelValues(reason).Inc()
+				log.WithFields(log.OWI("", coords.ID, "")).WithField("reason", reason).Debug("rate limited request to public port")
+				resp.Header().Set("Retry-After", "1")
+				http.Error(resp, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			l.connectionsActive.Inc()
+			defer func() {
+				l.release(coords.ID)
+				l.connectionsActive.Dec()
+			}()
+
+			h.ServeHTTP(resp, req)
+		})
+	}
+}
+
+// acquire checks the limits for a request and counts it as connection of the workspace.
+// If a limit is exceeded, acquire returns the reason and false.
+func (l *portRateLimiter) acquire(ip, workspaceID string, now time.Time) (reason string, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastGC) > rateLimiterIdleTimeout {
+		gcLimiters(l.ips, now)
+		gcLimiters(l.workspaces, now)
+		l.lastGC = now
+	}
+
+	if max := l.Config.MaxConnectionsPerWorkspace; max > 0 && l.connections[workspaceID] >= max {
+		return limitReasonConnections, false
+	}
+	if l.Config.PerIP > 0 && !getLimiter(l.ips, ip, l.Config.PerIP, l.Config.PerIPBurst, now).AllowN(now, 1) {
+		return limitReasonIP, false
+	}
+	if l.Config.PerWorkspace > 0 && !getLimiter(l.workspaces, workspaceID, l.Config.PerWorkspace, l.Config.PerWorkspaceBurst, now).AllowN(now, 1) {
+		return limitReasonWorkspace, false
+	}
+
+	l.connections[workspaceID]++
+	return "", true
+}
+
+func (l *portRateLimiter) release(workspaceID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.connections[workspaceID]--
+	if l.connections[workspaceID] <= 0 {
+		delete(l.connections, workspaceID)
+	}
+}
+
+func getLimiter(limiters map[string]*idleLimiter, key string, limit float64, burst int, now time.Time) *idleLimiter {
+	lim, ok := limiters[key]
+	if !ok {
+		if burst < 1 {
+			burst = 1
+		}
+		lim = &idleLimiter{Limiter: rate.NewLimiter(rate.Limit(limit), burst)}
+		limiters[key] = lim
+	}
+	lim.lastSeen = now
+	return lim
+}
+
+func gcLimiters(limiters map[string]*idleLimiter, now time.Time) {
+	for key, lim := range limiters {
+		if now.Sub(lim.lastSeen) > rateLimiterIdleTimeout {
+			delete(limiters, key)
+		}
+	}
+}
+
+// isPublicPort returns true if the port of the workspace is shared publicly.
+func isPublicPort(info *WorkspaceInfo, port string) bool {
+	spec := findPortSpec(info, port)
+	return spec != nil && spec.Visibility == api.PortVisibility_PORT_VISIBILITY_PUBLIC
+}
+
+// clientIP returns the IP of the client which sent the request. The proxy in front of ws-proxy
+// passes it in the X-Real-IP header.
+func clientIP(req *http.Request) string {
+	if ip := req.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
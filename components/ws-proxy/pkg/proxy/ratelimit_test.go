@@ -0,0 +1,161 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestPortRateLimiterAcquire(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type request struct {
+		IP          string
+		WorkspaceID string
+		Time        time.Time
+		Release     bool
+	}
+	type result struct {
+		Reason string
+		OK     bool
+	}
+	tests := []struct {
+		Desc        string
+		Config      RateLimitConfig
+		Requests    []request
+		Expectation []result
+	}{
+		{
+			Desc:   "no limits",
+			Config: RateLimitConfig{Enabled: true},
+			Requests: []request{
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0},
+			},
+			Expectation: []result{{OK: true}, {OK: true}},
+		},
+		{
+			Desc:   "per IP limit",
+			Config: RateLimitConfig{Enabled: true, PerIP: 1, PerIPBurst: 2},
+			Requests: []request{
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.1", WorkspaceID: "bar", Time: t0},
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.2", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0.Add(1 * time.Second)},
+			},
+			Expectation: []result{{OK: true}, {OK: true}, {Reason: limitReasonIP}, {OK: true}, {OK: true}},
+		},
+		{
+			Desc:   "per workspace limit",
+			Config: RateLimitConfig{Enabled: true, PerWorkspace: 1, PerWorkspaceBurst: 1},
+			Requests: []request{
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.2", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.2", WorkspaceID: "bar", Time: t0},
+			},
+			Expectation: []result{{OK: true}, {Reason: limitReasonWorkspace}, {OK: true}},
+		},
+		{
+			Desc:   "connection cap",
+			Config: RateLimitConfig{Enabled: true, MaxConnectionsPerWorkspace: 1},
+			Requests: []request{
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.2", WorkspaceID: "foo", Time: t0},
+				{IP: "10.0.0.2", WorkspaceID: "bar", Time: t0},
+			},
+			Expectation: []result{{OK: true}, {Reason: limitReasonConnections}, {OK: true}},
+		},
+		{
+			Desc:   "released connections",
+			Config: RateLimitConfig{Enabled: true, MaxConnectionsPerWorkspace: 1},
+			Requests: []request{
+				{IP: "10.0.0.1", WorkspaceID: "foo", Time: t0, Release: true},
+				{IP: "10.0.0.2", WorkspaceID: "foo", Time: t0},
+			},
+			Expectation: []result{{OK: true}, {OK: true}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			limiter := newPortRateLimiter(test.Config)
+
+			var act []result
+			for _, req := range test.Requests {
+				reason, ok := limiter.acquire(req.IP, req.WorkspaceID, req.Time)
+				act = append(act, result{Reason: reason, OK: ok})
+				if ok && req.Release {
+					limiter.release(req.WorkspaceID)
+				}
+			}
+
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsPublicPort(t *testing.T) {
+	info := &WorkspaceInfo{
+		Ports: []*api.PortSpec{
+			{Port: 3000, Visibility: api.PortVisibility_PORT_VISIBILITY_PUBLIC},
+			{Port: 8080, Visibility: api.PortVisibility_PORT_VISIBILITY_PRIVATE},
+		},
+	}
+
+	tests := []struct {
+		Desc        string
+		Info        *WorkspaceInfo
+		Port        string
+		Expectation bool
+	}{
+		{Desc: "public port", Info: info, Port: "3000", Expectation: true},
+		{Desc: "private port", Info: info, Port: "8080", Expectation: false},
+		{Desc: "unknown port", Info: info, Port: "9000", Expectation: false},
+		{Desc: "invalid port", Info: info, Port: "foo", Expectation: false},
+		{Desc: "unknown workspace", Port: "3000", Expectation: false},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act := isPublicPort(test.Info, test.Port)
+			if act != test.Expectation {
+				t.Errorf("unexpected result: expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		RealIP      string
+		RemoteAddr  string
+		Expectation string
+	}{
+		{Desc: "real IP header", RealIP: "10.0.0.1", RemoteAddr: "192.168.0.1:1234", Expectation: "10.0.0.1"},
+		{Desc: "remote address", RemoteAddr: "192.168.0.1:1234", Expectation: "192.168.0.1"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://localhost/", nil)
+			req.RemoteAddr = test.RemoteAddr
+			if test.RealIP != "" {
+				req.Header.Set("X-Real-IP", test.RealIP)
+			}
+
+			act := clientIP(req)
+			if act != test.Expectation {
+				t.Errorf("unexpected client IP: expected %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
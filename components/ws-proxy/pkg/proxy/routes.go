@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -31,10 +32,13 @@ import (
 
 // RouteHandlerConfig configures a RouteHandler.
 type RouteHandlerConfig struct {
-	Config               *Config
-	DefaultTransport     http.RoundTripper
-	CorsHandler          mux.MiddlewareFunc
-	WorkspaceAuthHandler mux.MiddlewareFunc
+	Config                  *Config
+	DefaultTransport        http.RoundTripper
+	H2CTransport            http.RoundTripper
+	CorsHandler             mux.MiddlewareFunc
+	WorkspaceAuthHandler    mux.MiddlewareFunc
+	PortRateLimitHandler    mux.MiddlewareFunc
+	StaticAssetCacheHandler mux.MiddlewareFunc
 }
 
 // RouteHandlerConfigOpt modifies the router handler config.
@@ -47,6 +51,20 @@ func WithDefaultAuth(infoprov WorkspaceInfoProvider) RouteHandlerConfigOpt {
 	}
 }
 
+// withPortRateLimit enables rate limiting of requests to public workspace ports.
+func withPortRateLimit(limiter *portRateLimiter, infoprov WorkspaceInfoProvider) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.PortRateLimitHandler = limiter.Handler(infoprov)
+	}
+}
+
+// withStaticAssetCache enables caching of immutable static assets served from workspace ports.
+func withStaticAssetCache(cache *staticAssetCache, infoprov WorkspaceInfoProvider) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.StaticAssetCacheHandler = cache.Handler(infoprov)
+	}
+}
+
 // NewRouteHandlerConfig creates a new instance.
 func NewRouteHandlerConfig(config *Config, opts ...RouteHandlerConfigOpt) (*RouteHandlerConfig, error) {
 	corsHandler, err := corsHandler(config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName)
@@ -55,10 +73,13 @@ func NewRouteHandlerConfig(config *Config, opts ...RouteHandlerConfigOpt) (*Rout
 	}
 
 	cfg := &RouteHandlerConfig{
-		Config:               config,
-		DefaultTransport:     createDefaultTransport(config.TransportConfig),
-		CorsHandler:          corsHandler,
-		WorkspaceAuthHandler: func(h http.Handler) http.Handler { return h },
+		Config:                  config,
+		DefaultTransport:        createDefaultTransport(config.TransportConfig),
+		H2CTransport:            createH2CTransport(config.TransportConfig),
+		CorsHandler:             corsHandler,
+		WorkspaceAuthHandler:    func(h http.Handler) http.Handler { return h },
+		PortRateLimitHandler:    func(h http.Handler) http.Handler { return h },
+		StaticAssetCacheHandler: func(h http.Handler) http.Handler { return h },
 	}
 	for _, o := range opts {
 		o(config, cfg)
@@ -370,10 +391,21 @@ func installWorkspacePortRoutes(r *mux.Router, config *RouteHandlerConfig, infoP
 		return err
 	}
 
+	showWorkspaceNotRunningPage, err := serveWorkspaceNotRunningPage(config.Config)
+	if err != nil {
+		return err
+	}
+
 	r.Use(logHandler)
+	r.Use(accessLogHandler(config.Config, infoProvider))
+	r.Use(workspaceMustBeRunningHandler(infoProvider, showWorkspaceNotRunningPage))
+	r.Use(config.PortRateLimitHandler)
+	r.Use(portCORSPreflightHandler(config.Config, infoProvider))
 	r.Use(config.WorkspaceAuthHandler)
 	// filter all session cookies
 	r.Use(sensitiveCookieHandler(config.Config.GitpodInstallation.HostName))
+	r.Use(portActivityHandler(config.Config, infoProvider))
+	r.Use(config.StaticAssetCacheHandler)
 
 	// forward request to workspace port
 	r.NewRoute().HandlerFunc(
@@ -393,7 +425,8 @@ func installWorkspacePortRoutes(r *mux.Router, config *RouteHandlerConfig, infoP
 				infoProvider,
 				workspacePodPortResolver,
 				withHTTPErrorHandler(showPortNotFoundPage),
-				withXFrameOptionsFilter(),
+				withPortHeaders(config.Config, infoProvider),
+				withGRPCTransport(config.H2CTransport),
 				withWorkspaceTransport(),
 			)(rw, r)
 		},
@@ -439,7 +472,8 @@ func dynamicIDEResolver(config *Config, infoProvider WorkspaceInfoProvider, req
 }
 
 func buildWorkspacePodURL(ipAddress string, port string) (*url.URL, error) {
-	return url.Parse(fmt.Sprintf("http://%v:%v", ipAddress, port))
+	// JoinHostPort brackets IPv6 addresses
+	return url.Parse("http://" + net.JoinHostPort(ipAddress, port))
 }
 
 // corsHandler produces the CORS handler for workspaces.
@@ -578,6 +612,11 @@ func workspaceMustExistHandler(config *Config, infoProvider WorkspaceInfoProvide
 		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 			coords := getWorkspaceCoords(req)
 			info := infoProvider.WorkspaceInfo(coords.ID)
+			if info == nil && acceptsJSON(req) {
+				log.WithFields(log.OWI("", coords.ID, "")).Info("no workspace info found - workspace is not running")
+				writeWorkspaceNotRunningJSON(resp, config, coords.ID)
+				return
+			}
 			if info == nil {
 				log.WithFields(log.OWI("", coords.ID, "")).Info("no workspace info found - redirecting to start")
 				redirectURL := fmt.Sprintf("%s://%s/start/?not_found=true#%s", config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName, coords.ID)
@@ -590,6 +629,22 @@ func workspaceMustExistHandler(config *Config, infoProvider WorkspaceInfoProvide
 	}
 }
 
+// workspaceMustBeRunningHandler serves the workspace-not-running response if we don't know about a workspace.
+func workspaceMustBeRunningHandler(infoProvider WorkspaceInfoProvider, notRunning http.Handler) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			coords := getWorkspaceCoords(req)
+			if coords.ID != "" && infoProvider.WorkspaceInfo(coords.ID) == nil {
+				log.WithFields(log.OWI("", coords.ID, "")).Debug("no workspace info found - workspace is not running")
+				notRunning.ServeHTTP(resp, req)
+				return
+			}
+
+			h.ServeHTTP(resp, req)
+		})
+	}
+}
+
 // getWorkspaceInfoFromContext retrieves workspace information put there by the workspaceMustExistHandler.
 func getWorkspaceInfoFromContext(ctx context.Context) *WorkspaceInfo {
 	r := ctx.Value(infoContextValueKey)
@@ -748,7 +803,14 @@ func (t *blobserveTransport) asBlobserveURL(image string, path string) string {
 // endregion
 
 const (
-	builtinPagePortNotFound = "port-not-found.html"
+	builtinPagePortNotFound        = "port-not-found.html"
+	builtinPageWorkspaceNotRunning = "workspace-not-running.html"
+
+	// workspaceStatusHeader marks responses which ws-proxy serves on behalf of a workspace that is not running,
+	// so that the workspace-not-running page can tell them apart from responses of the workspace itself.
+	workspaceStatusHeader = "X-Gitpod-Workspace-Status"
+	// workspaceNotRunningRetryAfter is the number of seconds clients should wait before retrying a workspace that is not running
+	workspaceNotRunningRetryAfter = "5"
 )
 
 func servePortNotFoundPage(config *Config) (http.Handler, error) {
@@ -767,3 +829,56 @@ func servePortNotFoundPage(config *Config) (http.Handler, error) {
 		_, _ = w.Write(page)
 	}), nil
 }
+
+func serveWorkspaceNotRunningPage(config *Config) (http.Handler, error) {
+	fn := filepath.Join(config.BuiltinPages.Location, builtinPageWorkspaceNotRunning)
+	if tp := os.Getenv("TELEPRESENCE_ROOT"); tp != "" {
+		fn = filepath.Join(tp, fn)
+	}
+	page, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	page = bytes.ReplaceAll(page, []byte("https://gitpod.io"), []byte(fmt.Sprintf("%s://%s", config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName)))
+	page = bytes.ReplaceAll(page, []byte(`data-autostart="false"`), []byte(fmt.Sprintf(`data-autostart="%t"`, config.BuiltinPages.AutoStartWorkspace)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if acceptsJSON(r) {
+			writeWorkspaceNotRunningJSON(w, config, getWorkspaceCoords(r).ID)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Retry-After", workspaceNotRunningRetryAfter)
+		w.Header().Set(workspaceStatusHeader, "not-running")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write(page)
+	}), nil
+}
+
+// workspaceNotRunningResponse is served to API clients which hit a workspace that is not running.
+type workspaceNotRunningResponse struct {
+	WorkspaceID string `json:"workspaceId"`
+	Status      string `json:"status"`
+	StartURL    string `json:"startUrl"`
+}
+
+func writeWorkspaceNotRunningJSON(w http.ResponseWriter, config *Config, workspaceID string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", workspaceNotRunningRetryAfter)
+	w.Header().Set(workspaceStatusHeader, "not-running")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(workspaceNotRunningResponse{
+		WorkspaceID: workspaceID,
+		Status:      "not-running",
+		StartURL:    fmt.Sprintf("%s://%s/start/#%s", config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName, workspaceID),
+	})
+}
+
+// acceptsJSON returns true if the request comes from an API client rather than a browser.
+func acceptsJSON(req *http.Request) bool {
+	accept := req.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/util"
@@ -441,6 +443,24 @@ func TestRoutes(t *testing.T) {
 				Body: ("<a href=\"https://test-domain.com/start/?not_found=true#blabla-smelt-9ba20cc1\">Found</a>.\n\n"),
 			},
 		},
+		{
+			Desc: "non-existent GET / from API client",
+			Request: modifyRequest(httptest.NewRequest("GET", strings.ReplaceAll(workspaces[0].URL, "amaranth", "blabla"), nil),
+				addHostHeader,
+				addHeader("Accept", "application/json"),
+			),
+			Expectation: Expectation{
+				Status: http.StatusServiceUnavailable,
+				Header: http.Header{
+					"Cache-Control":             {"no-store"},
+					"Content-Type":              {"application/json"},
+					"Retry-After":               {"5"},
+					"Vary":                      {"Accept-Encoding"},
+					"X-Gitpod-Workspace-Status": {"not-running"},
+				},
+				Body: "{\"workspaceId\":\"blabla-smelt-9ba20cc1\",\"status\":\"not-running\",\"startUrl\":\"https://test-domain.com/start/#blabla-smelt-9ba20cc1\"}\n",
+			},
+		},
 		{
 			Desc:   "blobserve supervisor frontend /worker-proxy.js",
 			Config: &config,
@@ -524,6 +544,40 @@ func TestRoutes(t *testing.T) {
 				Body:   "",
 			},
 		},
+		{
+			Desc: "port GET of non-existent workspace",
+			Request: modifyRequest(httptest.NewRequest("GET", strings.ReplaceAll(workspaces[0].Ports[0].Url, "amaranth", "blabla"), nil),
+				addHostHeader,
+				addHeader("Accept", "text/html"),
+			),
+			IgnoreBody: true,
+			Expectation: Expectation{
+				Status: http.StatusServiceUnavailable,
+				Header: http.Header{
+					"Cache-Control":             {"no-store"},
+					"Content-Type":              {"text/html; charset=utf-8"},
+					"Retry-After":               {"5"},
+					"X-Gitpod-Workspace-Status": {"not-running"},
+				},
+			},
+		},
+		{
+			Desc: "port GET of non-existent workspace from API client",
+			Request: modifyRequest(httptest.NewRequest("GET", strings.ReplaceAll(workspaces[0].Ports[0].Url, "amaranth", "blabla"), nil),
+				addHostHeader,
+				addHeader("Accept", "application/json"),
+			),
+			Expectation: Expectation{
+				Status: http.StatusServiceUnavailable,
+				Header: http.Header{
+					"Cache-Control":             {"no-store"},
+					"Content-Type":              {"application/json"},
+					"Retry-After":               {"5"},
+					"X-Gitpod-Workspace-Status": {"not-running"},
+				},
+				Body: "{\"workspaceId\":\"blabla-smelt-9ba20cc1\",\"status\":\"not-running\",\"startUrl\":\"https://test-domain.com/start/#blabla-smelt-9ba20cc1\"}\n",
+			},
+		},
 		{
 			Desc: "port cookies",
 			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].Ports[0].Url+"this-does-not-exist", nil),
@@ -701,6 +755,76 @@ func TestRoutes(t *testing.T) {
 	}
 }
 
+func TestGRPCPassthrough(t *testing.T) {
+	l, err := net.Listen("tcp", portServeHost)
+	if err != nil {
+		t.Fatalf("cannot start fake port host: %q", err)
+	}
+	srv := &http.Server{Handler: h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "%s %s", r.Proto, r.URL.Path)
+		w.Header().Set("Grpc-Status", "0")
+	}), &http2.Server{})}
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	tests := []struct {
+		Desc        string
+		ContentType string
+		Expectation string
+	}{
+		{
+			Desc:        "gRPC call",
+			ContentType: "application/grpc",
+			Expectation: "HTTP/2.0 /foo.Service/Bar",
+		},
+		{
+			Desc:        "gRPC call with sub-type",
+			ContentType: "application/grpc+proto",
+			Expectation: "HTTP/2.0 /foo.Service/Bar",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			router := HostBasedRouter(hostBasedHeader, wsHostSuffix, wsHostNameRegex)
+			ingress := HostBasedIngressConfig{
+				HTTPAddress:  "8080",
+				HTTPSAddress: "9090",
+				Header:       "",
+			}
+			proxy := NewWorkspaceProxy(ingress, config, router, &fakeWsInfoProvider{infos: workspaces}, nil)
+			handler, err := proxy.Handler()
+			if err != nil {
+				t.Fatalf("cannot create proxy handler: %q", err)
+			}
+
+			req := modifyRequest(httptest.NewRequest("POST", workspaces[0].Ports[0].Url+"foo.Service/Bar", strings.NewReader("")),
+				addHostHeader,
+				addHeader("Content-Type", test.ContentType),
+			)
+			req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: expected %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+			if diff := cmp.Diff(test.Expectation, string(body)); diff != "" {
+				t.Errorf("unexpected body (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff("0", resp.Trailer.Get("Grpc-Status")); diff != "" {
+				t.Errorf("unexpected grpc-status trailer (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type fakeWsInfoProvider struct {
 	infos []WorkspaceInfo
 }
@@ -897,3 +1021,26 @@ func TestSensitiveCookieHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildWorkspacePodURL(t *testing.T) {
+	tests := []struct {
+		Name      string
+		IPAddress string
+		Port      string
+		Expected  string
+	}{
+		{"IPv4", "10.0.0.1", "3000", "http://10.0.0.1:3000"},
+		{"IPv6", "fd00::1", "3000", "http://[fd00::1]:3000"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, err := buildWorkspacePodURL(test.IPAddress, test.Port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, res.String()); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
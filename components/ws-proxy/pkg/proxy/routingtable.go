@@ -0,0 +1,249 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// fallbackCacheTTL limits how long a fallback lookup is cached, in case we never see a status update which invalidates it
+const fallbackCacheTTL = 30 * time.Second
+
+// PodIPResolver returns the IP address of a workspace pod, or an empty string if the pod has none yet.
+type PodIPResolver func(ctx context.Context, podName string) (string, error)
+
+// RoutingTable provides infos about workspaces from the status updates ws-manager pushes to ws-proxy.
+// Unlike the pod-based RemoteWorkspaceInfoProvider it knows about a workspace as soon as ws-manager does,
+// which avoids failing requests right after a workspace started.
+//
+// Workspaces the routing table has no complete info about are looked up using the fallback provider.
+// Those lookups are kept in an LRU cache until the next status update of the workspace invalidates them.
+type RoutingTable struct {
+	Client       wsapi.WorkspaceManagerClient
+	Fallback     WorkspaceInfoProvider
+	ResolvePodIP PodIPResolver
+
+	mu      sync.RWMutex
+	entries map[string]*WorkspaceInfo
+	// resolving contains the instances whose pod IP we're resolving
+	resolving map[string]struct{}
+	resolvers sync.WaitGroup
+
+	fallbackCache *lru.Cache
+}
+
+// NewRoutingTable creates a new routing table which caches up to fallbackCacheSize fallback lookups.
+func NewRoutingTable(client wsapi.WorkspaceManagerClient, fallback WorkspaceInfoProvider, resolvePodIP PodIPResolver, fallbackCacheSize int) (*RoutingTable, error) {
+	fallbackCache, err := lru.New(fallbackCacheSize)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create fallback cache: %w", err)
+	}
+
+	return &RoutingTable{
+		Client:        client,
+		Fallback:      fallback,
+		ResolvePodIP:  resolvePodIP,
+		entries:       make(map[string]*WorkspaceInfo),
+		resolving:     make(map[string]struct{}),
+		fallbackCache: fallbackCache,
+	}, nil
+}
+
+// Run subscribes to ws-manager and keeps the routing table up to date until the context is canceled.
+func (r *RoutingTable) Run(ctx context.Context) {
+	for {
+		err := r.sync(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.WithError(err).Info("connection to ws-manager lost - retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (r *RoutingTable) sync(ctx context.Context) error {
+	wss, err := r.Client.GetWorkspaces(ctx, &wsapi.GetWorkspacesRequest{})
+	if err != nil {
+		return xerrors.Errorf("cannot get workspaces: %w", err)
+	}
+
+	entries := make(map[string]*WorkspaceInfo, len(wss.Status))
+	for _, status := range wss.Status {
+		if status.Phase == wsapi.WorkspacePhase_STOPPED || status.Metadata == nil {
+			continue
+		}
+		info := mapStatusToWorkspaceInfo(status)
+		if old := r.lookup(info.WorkspaceID); old != nil && old.InstanceID == info.InstanceID {
+			info.IPAddress = old.IPAddress
+		}
+		entries[info.WorkspaceID] = info
+	}
+	r.mu.Lock()
+	r.entries = entries
+	r.mu.Unlock()
+	// while we were disconnected we might have missed updates which would have invalidated cached lookups
+	r.fallbackCache.Purge()
+
+	sub, err := r.Client.Subscribe(ctx, &wsapi.SubscribeRequest{})
+	if err != nil {
+		return xerrors.Errorf("cannot subscribe: %w", err)
+	}
+	for {
+		msg, err := sub.Recv()
+		if err != nil {
+			return err
+		}
+
+		status := msg.GetStatus()
+		if status == nil || status.Metadata == nil {
+			continue
+		}
+		r.update(ctx, status)
+	}
+}
+
+// update applies a status update of a workspace instance to the routing table.
+func (r *RoutingTable) update(ctx context.Context, status *wsapi.WorkspaceStatus) {
+	info := mapStatusToWorkspaceInfo(status)
+	defer r.fallbackCache.Remove(info.WorkspaceID)
+
+	if status.Phase == wsapi.WorkspacePhase_STOPPED {
+		r.mu.Lock()
+		if old, ok := r.entries[info.WorkspaceID]; ok && old.InstanceID == info.InstanceID {
+			delete(r.entries, info.WorkspaceID)
+		}
+		r.mu.Unlock()
+		return
+	}
+
+	if old := r.lookup(info.WorkspaceID); old != nil && old.InstanceID == info.InstanceID {
+		info.IPAddress = old.IPAddress
+	}
+
+	// entries are handed out to callers of WorkspaceInfo, hence we replace instead of modifying them
+	r.mu.Lock()
+	r.entries[info.WorkspaceID] = info
+	r.mu.Unlock()
+
+	if info.IPAddress == "" && status.Runtime != nil && status.Runtime.PodName != "" && r.ResolvePodIP != nil {
+		r.resolvePodIP(ctx, info.WorkspaceID, info.InstanceID, status.Runtime.PodName)
+	}
+}
+
+// resolvePodIP resolves the pod IP of a workspace instance in the background, so that a slow Kubernetes API
+// doesn't hold up the status updates of all other workspaces. There is at most one resolution per instance at a time.
+func (r *RoutingTable) resolvePodIP(ctx context.Context, workspaceID, instanceID, podName string) {
+	r.mu.Lock()
+	_, resolving := r.resolving[instanceID]
+	if !resolving {
+		r.resolving[instanceID] = struct{}{}
+	}
+	r.mu.Unlock()
+	if resolving {
+		return
+	}
+
+	r.resolvers.Add(1)
+	go func() {
+		defer r.resolvers.Done()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		ip, err := r.ResolvePodIP(ctx, podName)
+		if err != nil {
+			log.WithError(err).WithFields(log.OWI("", workspaceID, instanceID)).Debug("cannot resolve workspace pod IP")
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.resolving, instanceID)
+		old, ok := r.entries[workspaceID]
+		if ip == "" || !ok || old.InstanceID != instanceID || old.IPAddress != "" {
+			return
+		}
+		info := *old
+		info.IPAddress = ip
+		r.entries[workspaceID] = &info
+	}()
+}
+
+func (r *RoutingTable) lookup(workspaceID string) *WorkspaceInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.entries[workspaceID]
+}
+
+// WorkspaceInfo returns the workspace information of a workspace using its workspace ID.
+func (r *RoutingTable) WorkspaceInfo(workspaceID string) *WorkspaceInfo {
+	info := r.lookup(workspaceID)
+	if info != nil && info.IPAddress != "" {
+		return info
+	}
+
+	if cached, ok := r.fallbackCache.Get(workspaceID); ok {
+		entry := cached.(*fallbackCacheEntry)
+		if time.Since(entry.Created) < fallbackCacheTTL {
+			return entry.Info
+		}
+		r.fallbackCache.Remove(workspaceID)
+	}
+	if r.Fallback == nil {
+		return info
+	}
+	fallback := r.Fallback.WorkspaceInfo(workspaceID)
+	if fallback == nil {
+		return info
+	}
+	if info != nil {
+		if fallback.InstanceID != info.InstanceID || fallback.IPAddress == "" {
+			return info
+		}
+		// the pod has an IP already, but we have not seen a status update since
+		merged := *info
+		merged.IPAddress = fallback.IPAddress
+		fallback = &merged
+	}
+	r.fallbackCache.Add(workspaceID, &fallbackCacheEntry{Info: fallback, Created: time.Now()})
+	return fallback
+}
+
+type fallbackCacheEntry struct {
+	Info    *WorkspaceInfo
+	Created time.Time
+}
+
+func mapStatusToWorkspaceInfo(status *wsapi.WorkspaceStatus) *WorkspaceInfo {
+	info := &WorkspaceInfo{
+		WorkspaceID: status.Metadata.MetaId,
+		InstanceID:  status.Id,
+		Auth:        status.Auth,
+	}
+	if status.Metadata.StartedAt != nil {
+		info.StartedAt = status.Metadata.StartedAt.AsTime()
+	}
+	if spec := status.Spec; spec != nil {
+		info.URL = spec.Url
+		info.IDEPublicPort = getPortStr(spec.Url)
+		info.Ports = spec.ExposedPorts
+		if spec.IdeImage != nil {
+			info.IDEImage = spec.IdeImage.WebRef
+			info.SupervisorImage = spec.IdeImage.SupervisorRef
+		}
+	}
+	return info
+}
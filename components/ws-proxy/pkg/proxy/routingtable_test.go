@@ -0,0 +1,173 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestRoutingTable(t *testing.T) {
+	startedAt := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	status := func(instanceID string, phase wsapi.WorkspacePhase) *wsapi.WorkspaceStatus {
+		return &wsapi.WorkspaceStatus{
+			Id: instanceID,
+			Metadata: &wsapi.WorkspaceMetadata{
+				MetaId:    "amaranth-smelt-9ba20cc1",
+				StartedAt: timestamppb.New(startedAt),
+			},
+			Spec: &wsapi.WorkspaceSpec{
+				Url: "https://amaranth-smelt-9ba20cc1.ws.gitpod.io",
+				IdeImage: &wsapi.IDEImage{
+					WebRef:        "gitpod-io/ide:latest",
+					SupervisorRef: "gitpod-io/supervisor:latest",
+				},
+				ExposedPorts: []*wsapi.PortSpec{{Port: 3000, Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC}},
+			},
+			Phase:   phase,
+			Runtime: &wsapi.WorkspaceRuntimeInfo{PodName: "ws-" + instanceID},
+			Auth:    &wsapi.WorkspaceAuthentication{Admission: wsapi.AdmissionLevel_ADMIT_OWNER_ONLY, OwnerToken: "owner-token"},
+		}
+	}
+	info := func(instanceID, ip string) *WorkspaceInfo {
+		return &WorkspaceInfo{
+			WorkspaceID:     "amaranth-smelt-9ba20cc1",
+			InstanceID:      instanceID,
+			URL:             "https://amaranth-smelt-9ba20cc1.ws.gitpod.io",
+			IDEImage:        "gitpod-io/ide:latest",
+			SupervisorImage: "gitpod-io/supervisor:latest",
+			IDEPublicPort:   "443",
+			IPAddress:       ip,
+			Ports:           []*wsapi.PortSpec{{Port: 3000, Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC}},
+			Auth:            &wsapi.WorkspaceAuthentication{Admission: wsapi.AdmissionLevel_ADMIT_OWNER_ONLY, OwnerToken: "owner-token"},
+			StartedAt:       startedAt,
+		}
+	}
+
+	tests := []struct {
+		Desc        string
+		PodIPs      map[string]string
+		Fallback    map[string]*WorkspaceInfo
+		Updates     []*wsapi.WorkspaceStatus
+		Expectation *WorkspaceInfo
+	}{
+		{
+			Desc: "unknown workspace",
+		},
+		{
+			Desc:        "pushed workspace",
+			PodIPs:      map[string]string{"ws-foo": "10.0.0.1"},
+			Updates:     []*wsapi.WorkspaceStatus{status("foo", wsapi.WorkspacePhase_RUNNING)},
+			Expectation: info("foo", "10.0.0.1"),
+		},
+		{
+			Desc:   "pod IP of earlier update",
+			PodIPs: map[string]string{"ws-foo": "10.0.0.1"},
+			Updates: []*wsapi.WorkspaceStatus{
+				status("foo", wsapi.WorkspacePhase_CREATING),
+				status("foo", wsapi.WorkspacePhase_RUNNING),
+			},
+			Expectation: info("foo", "10.0.0.1"),
+		},
+		{
+			Desc:        "pod without IP",
+			Updates:     []*wsapi.WorkspaceStatus{status("foo", wsapi.WorkspacePhase_CREATING)},
+			Expectation: info("foo", ""),
+		},
+		{
+			Desc:        "pod IP from fallback",
+			Fallback:    map[string]*WorkspaceInfo{"amaranth-smelt-9ba20cc1": {InstanceID: "foo", IPAddress: "10.0.0.2"}},
+			Updates:     []*wsapi.WorkspaceStatus{status("foo", wsapi.WorkspacePhase_CREATING)},
+			Expectation: info("foo", "10.0.0.2"),
+		},
+		{
+			Desc:        "fallback of other instance",
+			Fallback:    map[string]*WorkspaceInfo{"amaranth-smelt-9ba20cc1": {InstanceID: "bar", IPAddress: "10.0.0.2"}},
+			Updates:     []*wsapi.WorkspaceStatus{status("foo", wsapi.WorkspacePhase_CREATING)},
+			Expectation: info("foo", ""),
+		},
+		{
+			Desc:        "unpushed workspace",
+			Fallback:    map[string]*WorkspaceInfo{"amaranth-smelt-9ba20cc1": info("foo", "10.0.0.2")},
+			Expectation: info("foo", "10.0.0.2"),
+		},
+		{
+			Desc:   "stopped workspace",
+			PodIPs: map[string]string{"ws-foo": "10.0.0.1"},
+			Updates: []*wsapi.WorkspaceStatus{
+				status("foo", wsapi.WorkspacePhase_RUNNING),
+				status("foo", wsapi.WorkspacePhase_STOPPED),
+			},
+		},
+		{
+			Desc:   "stopped earlier instance",
+			PodIPs: map[string]string{"ws-foo": "10.0.0.1", "ws-bar": "10.0.0.2"},
+			Updates: []*wsapi.WorkspaceStatus{
+				status("foo", wsapi.WorkspacePhase_STOPPING),
+				status("bar", wsapi.WorkspacePhase_RUNNING),
+				status("foo", wsapi.WorkspacePhase_STOPPED),
+			},
+			Expectation: info("bar", "10.0.0.2"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			resolvePodIP := func(ctx context.Context, podName string) (string, error) {
+				return test.PodIPs[podName], nil
+			}
+			table, err := NewRoutingTable(nil, &fixedInfoProvider{Infos: test.Fallback}, resolvePodIP, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, update := range test.Updates {
+				table.update(context.Background(), update)
+				table.resolvers.Wait()
+			}
+
+			act := table.WorkspaceInfo("amaranth-smelt-9ba20cc1")
+			if diff := cmp.Diff(test.Expectation, act, cmpopts.IgnoreUnexported(wsapi.PortSpec{}, wsapi.WorkspaceAuthentication{})); diff != "" {
+				t.Errorf("unexpected workspace info (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRoutingTableResolvesPodIPInBackground(t *testing.T) {
+	release := make(chan struct{})
+	resolvePodIP := func(ctx context.Context, podName string) (string, error) {
+		<-release
+		return "10.0.0.1", nil
+	}
+	table, err := NewRoutingTable(nil, nil, resolvePodIP, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := &wsapi.WorkspaceStatus{
+		Id:       "foo",
+		Metadata: &wsapi.WorkspaceMetadata{MetaId: "amaranth-smelt-9ba20cc1"},
+		Phase:    wsapi.WorkspacePhase_CREATING,
+		Runtime:  &wsapi.WorkspaceRuntimeInfo{PodName: "ws-foo"},
+	}
+	// neither update must wait for the pod IP
+	table.update(context.Background(), status)
+	table.update(context.Background(), status)
+	if info := table.WorkspaceInfo("amaranth-smelt-9ba20cc1"); info == nil || info.IPAddress != "" {
+		t.Fatalf("unexpected workspace info before the pod IP was resolved: %+v", info)
+	}
+
+	close(release)
+	table.resolvers.Wait()
+	if info := table.WorkspaceInfo("amaranth-smelt-9ba20cc1"); info == nil || info.IPAddress != "10.0.0.1" {
+		t.Errorf("unexpected workspace info after the pod IP was resolved: %+v", info)
+	}
+}
@@ -207,3 +207,19 @@ func getWorkspaceCoords(req *http.Request) WorkspaceCoords {
 		Port: vars[workspacePortIdentifier],
 	}
 }
+
+// WorkspacePortHostMatcher returns a function which extracts the workspace coordinates from the host name of a
+// workspace port, e.g. 3000-coral-dragon-ilr0r6eq.ws-eu10.gitpod.io.
+func WorkspacePortHostMatcher(wsHostSuffix string) func(hostname string) (coords WorkspaceCoords, ok bool) {
+	r := regexp.MustCompile("^" + workspacePortRegex + workspaceIDRegex + wsHostSuffix + "$")
+	return func(hostname string) (coords WorkspaceCoords, ok bool) {
+		matches := r.FindStringSubmatch(hostname)
+		if len(matches) < 3 {
+			return WorkspaceCoords{}, false
+		}
+		return WorkspaceCoords{
+			ID:   matches[r.SubexpIndex(workspaceIDIdentifier)],
+			Port: matches[r.SubexpIndex(workspacePortIdentifier)],
+		}, true
+	}
+}
@@ -89,6 +89,50 @@ func (s *Server) ChannelForward(ctx context.Context, session *Session, client *s
 	log.WithFields(log.OWI("", session.WorkspaceID, session.InstanceID)).Debug("session forward stop")
 }
 
+// agentChannelType is the type of the channels sshd opens to reach the user's SSH agent,
+// once the user requested agent forwarding on a session using an auth-agent-req@openssh.com request
+const agentChannelType = "auth-agent@openssh.com"
+
+// AgentForward forwards the agent channels the workspace opens to the user's connection, until the workspace connection closes
+func (s *Server) AgentForward(session *Session, chans <-chan ssh.NewChannel) {
+	for newChannel := range chans {
+		go s.agentChannelForward(session, newChannel)
+	}
+}
+
+func (s *Server) agentChannelForward(session *Session, newChannel ssh.NewChannel) {
+	userChan, userReqs, err := session.Conn.OpenChannel(newChannel.ChannelType(), newChannel.ExtraData())
+	if err != nil {
+		log.WithFields(log.OWI("", session.WorkspaceID, session.InstanceID)).WithError(err).Debug("open agent channel error")
+		newChannel.Reject(ssh.ConnectionFailed, "open agent channel error")
+		return
+	}
+	defer userChan.Close()
+	go ssh.DiscardRequests(userReqs)
+
+	workspaceChan, workspaceReqs, err := newChannel.Accept()
+	if err != nil {
+		log.WithFields(log.OWI("", session.WorkspaceID, session.InstanceID)).Error("accept agent channel failed")
+		return
+	}
+	defer workspaceChan.Close()
+	go ssh.DiscardRequests(workspaceReqs)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(userChan, workspaceChan)
+		userChan.CloseWrite()
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(workspaceChan, userChan)
+		workspaceChan.CloseWrite()
+	}()
+	wg.Wait()
+}
+
 func startHeartbeatingChannel(c ssh.Channel, heartbeat Heartbeat, instanceID string) ssh.Channel {
 	ctx, cancel := context.WithCancel(context.Background())
 	res := &heartbeatingChannel{
@@ -5,10 +5,12 @@
 package sshproxy
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
@@ -19,7 +21,15 @@ import (
 	"google.golang.org/grpc"
 )
 
-const GitpodUsername = "gitpod"
+const (
+	GitpodUsername = "gitpod"
+
+	// maxAuthTries is the number of authentication attempts per connection. Each public key a client offers
+	// counts as an attempt.
+	maxAuthTries = 6
+	// ownerSSHPublicKeysTTL is the time we keep the SSH public keys of a workspace owner
+	ownerSSHPublicKeysTTL = 30 * time.Second
+)
 
 type Session struct {
 	Conn *ssh.ServerConn
@@ -36,6 +46,7 @@ type Server struct {
 
 	sshConfig             *ssh.ServerConfig
 	workspaceInfoProvider p.WorkspaceInfoProvider
+	ownerSSHPublicKeys    *sshPublicKeysCache
 }
 
 // New creates a new SSH proxy server
@@ -44,6 +55,7 @@ func New(signers []ssh.Signer, workspaceInfoProvider p.WorkspaceInfoProvider, he
 	server := &Server{
 		workspaceInfoProvider: workspaceInfoProvider,
 		Heartbeater:           &noHeartbeat{},
+		ownerSSHPublicKeys:    newSSHPublicKeysCache(ownerSSHPublicKeysTTL),
 	}
 	if heartbeat != nil {
 		server.Heartbeater = heartbeat
@@ -51,6 +63,7 @@ func New(signers []ssh.Signer, workspaceInfoProvider p.WorkspaceInfoProvider, he
 
 	server.sshConfig = &ssh.ServerConfig{
 		ServerVersion: "SSH-2.0-GITPOD-GATEWAY",
+		MaxAuthTries:  maxAuthTries,
 		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
 			workspaceId, ownerToken := conn.User(), string(password)
 			err := server.Authenticator(workspaceId, ownerToken)
@@ -65,20 +78,34 @@ func New(signers []ssh.Signer, workspaceInfoProvider p.WorkspaceInfoProvider, he
 		},
 		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
 			args := strings.Split(conn.User(), "#")
-			// workspaceId#ownerToken
-			if len(args) != 2 {
+			switch len(args) {
+			case 1:
+				// workspaceId, e.g. from a standard ssh config: we authenticate the owner using the public keys they registered with Gitpod
+				workspaceId := args[0]
+				err := server.PublicKeyAuthenticator(workspaceId, key)
+				if err != nil {
+					return nil, err
+				}
+				return &ssh.Permissions{
+					Extensions: map[string]string{
+						"workspaceId": workspaceId,
+					},
+				}, nil
+			case 2:
+				// workspaceId#ownerToken
+				workspaceId, ownerToken := args[0], args[1]
+				err := server.Authenticator(workspaceId, ownerToken)
+				if err != nil {
+					return nil, err
+				}
+				return &ssh.Permissions{
+					Extensions: map[string]string{
+						"workspaceId": workspaceId,
+					},
+				}, nil
+			default:
 				return nil, fmt.Errorf("username error")
 			}
-			workspaceId, ownerToken := args[0], args[1]
-			err := server.Authenticator(workspaceId, ownerToken)
-			if err != nil {
-				return nil, err
-			}
-			return &ssh.Permissions{
-				Extensions: map[string]string{
-					"workspaceId": workspaceId,
-				},
-			}, nil
 		},
 	}
 	for _, s := range signers {
@@ -118,7 +145,7 @@ func (s *Server) HandleConn(c net.Conn) {
 		InstanceID:          wsInfo.InstanceID,
 		WorkspacePrivateKey: key,
 	}
-	remoteAddr := wsInfo.IPAddress + ":23001"
+	remoteAddr := net.JoinHostPort(wsInfo.IPAddress, "23001")
 	conn, err := net.Dial("tcp", remoteAddr)
 	if err != nil {
 		log.WithField("instanceId", wsInfo.InstanceID).WithField("workspaceIP", wsInfo.IPAddress).WithError(err).Error("dail failed")
@@ -142,6 +169,8 @@ func (s *Server) HandleConn(c net.Conn) {
 	}
 	s.Heartbeater.SendHeartbeat(wsInfo.InstanceID, false)
 	client := ssh.NewClient(clientConn, clientChans, clientReqs)
+	// sshd in the workspace opens agent channels if the user forwards their SSH agent
+	go s.AgentForward(session, client.HandleChannelOpen(agentChannelType))
 	ctx, cancel = context.WithCancel(context.Background())
 
 	go func() {
@@ -172,8 +201,112 @@ func (s *Server) Authenticator(workspaceId, ownerToken string) (err error) {
 	return nil
 }
 
+// PublicKeyAuthenticator authenticates the owner of a workspace using the SSH public keys they registered with Gitpod
+func (s *Server) PublicKeyAuthenticator(workspaceId string, key ssh.PublicKey) (err error) {
+	wsInfo := s.workspaceInfoProvider.WorkspaceInfo(workspaceId)
+	if wsInfo == nil {
+		return fmt.Errorf("not found workspace")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	// clients try one key after the other, hence we ask supervisor only once per instance for a while
+	authorizedKeys, err := s.ownerSSHPublicKeys.Get(wsInfo.InstanceID, func() ([]string, error) {
+		return s.GetOwnerSSHPublicKeys(ctx, wsInfo.IPAddress)
+	})
+	if err != nil {
+		log.WithField("instanceId", wsInfo.InstanceID).WithError(err).Warn("cannot get the SSH public keys of the workspace owner")
+		return fmt.Errorf("auth failed")
+	}
+	if !isAuthorizedKey(authorizedKeys, key) {
+		return fmt.Errorf("auth failed")
+	}
+	return nil
+}
+
+// isAuthorizedKey returns true if key is one of the authorized keys. Authorized keys which don't parse are ignored.
+func isAuthorizedKey(authorizedKeys []string, key ssh.PublicKey) bool {
+	marshalled := key.Marshal()
+	for _, k := range authorizedKeys {
+		pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k))
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(pk.Marshal(), marshalled) {
+			return true
+		}
+	}
+	return false
+}
+
+// sshPublicKeysCache keeps the SSH public keys of workspace owners per workspace instance for a limited time.
+type sshPublicKeysCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]sshPublicKeysCacheEntry
+}
+
+type sshPublicKeysCacheEntry struct {
+	keys    []string
+	expires time.Time
+}
+
+func newSSHPublicKeysCache(ttl time.Duration) *sshPublicKeysCache {
+	return &sshPublicKeysCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]sshPublicKeysCacheEntry),
+	}
+}
+
+// Get returns the cached keys of an instance, or fetches them if they aren't cached or expired.
+// Errors are not cached.
+func (c *sshPublicKeysCache) Get(instanceID string, fetch func() ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	now := c.now()
+	entry, exists := c.entries[instanceID]
+	if exists && now.Before(entry.expires) {
+		c.mu.Unlock()
+		return entry.keys, nil
+	}
+	for id, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, id)
+		}
+	}
+	c.mu.Unlock()
+
+	// we don't hold the lock while fetching, so that a slow workspace doesn't block the authentication of others
+	keys, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[instanceID] = sshPublicKeysCacheEntry{keys: keys, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return keys, nil
+}
+
+// GetOwnerSSHPublicKeys returns the SSH public keys the workspace owner registered with Gitpod.
+// Supervisor fetches them on our behalf, because only the workspace has a token for the Gitpod API.
+func (s *Server) GetOwnerSSHPublicKeys(ctx context.Context, workspaceIP string) ([]string, error) {
+	supervisorConn, err := grpc.Dial(net.JoinHostPort(workspaceIP, "22999"), grpc.WithInsecure())
+	if err != nil {
+		return nil, xerrors.Errorf("failed connecting to supervisor: %w", err)
+	}
+	defer supervisorConn.Close()
+	resp, err := supervisor.NewControlServiceClient(supervisorConn).GetSSHPublicKeys(ctx, &supervisor.GetSSHPublicKeysRequest{})
+	if err != nil {
+		return nil, xerrors.Errorf("failed getting ssh public keys from supervisor: %w", err)
+	}
+	return resp.Keys, nil
+}
+
 func (s *Server) GetWorkspaceSSHKey(ctx context.Context, workspaceIP string) (ssh.Signer, error) {
-	supervisorConn, err := grpc.Dial(workspaceIP+":22999", grpc.WithInsecure())
+	supervisorConn, err := grpc.Dial(net.JoinHostPort(workspaceIP, "22999"), grpc.WithInsecure())
 	if err != nil {
 		return nil, xerrors.Errorf("failed connecting to supervisor: %w", err)
 	}
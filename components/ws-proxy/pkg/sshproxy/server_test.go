@@ -0,0 +1,132 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package sshproxy
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestIsAuthorizedKey(t *testing.T) {
+	genKey := func() ssh.PublicKey {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := ssh.NewPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pk
+	}
+	authorizedKey := func(pk ssh.PublicKey) string {
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pk)))
+	}
+	var (
+		userKey  = genKey()
+		otherKey = genKey()
+	)
+
+	tests := []struct {
+		Desc           string
+		AuthorizedKeys []string
+		Key            ssh.PublicKey
+		Expectation    bool
+	}{
+		{
+			Desc:        "no authorized keys",
+			Key:         userKey,
+			Expectation: false,
+		},
+		{
+			Desc:           "authorized key",
+			AuthorizedKeys: []string{authorizedKey(otherKey), authorizedKey(userKey)},
+			Key:            userKey,
+			Expectation:    true,
+		},
+		{
+			Desc:           "authorized key with comment",
+			AuthorizedKeys: []string{authorizedKey(userKey) + " user@laptop"},
+			Key:            userKey,
+			Expectation:    true,
+		},
+		{
+			Desc:           "unknown key",
+			AuthorizedKeys: []string{authorizedKey(otherKey)},
+			Key:            userKey,
+			Expectation:    false,
+		},
+		{
+			Desc:           "invalid authorized keys",
+			AuthorizedKeys: []string{"ssh-ed25519 not-a-key", authorizedKey(userKey)},
+			Key:            userKey,
+			Expectation:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act := isAuthorizedKey(test.AuthorizedKeys, test.Key)
+			if act != test.Expectation {
+				t.Errorf("unexpected result: expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestSSHPublicKeysCache(t *testing.T) {
+	var (
+		now     = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		fetches int
+		fetch   = func(keys ...string) func() ([]string, error) {
+			return func() ([]string, error) {
+				fetches++
+				return keys, nil
+			}
+		}
+		failingFetch = func() ([]string, error) {
+			fetches++
+			return nil, errors.New("supervisor is not available")
+		}
+	)
+	cache := newSSHPublicKeysCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	type Step struct {
+		Desc        string
+		Advance     time.Duration
+		InstanceID  string
+		Fetch       func() ([]string, error)
+		Expectation []string
+		Error       bool
+		Fetches     int
+	}
+	steps := []Step{
+		{Desc: "fetch error", InstanceID: "a", Fetch: failingFetch, Error: true, Fetches: 1},
+		{Desc: "errors are not cached", InstanceID: "a", Fetch: fetch("key-1"), Expectation: []string{"key-1"}, Fetches: 2},
+		{Desc: "cached", Advance: 30 * time.Second, InstanceID: "a", Fetch: fetch("key-2"), Expectation: []string{"key-1"}, Fetches: 2},
+		{Desc: "other instance", InstanceID: "b", Fetch: fetch("key-3"), Expectation: []string{"key-3"}, Fetches: 3},
+		{Desc: "expired", Advance: 30 * time.Second, InstanceID: "a", Fetch: fetch("key-2"), Expectation: []string{"key-2"}, Fetches: 4},
+	}
+	for _, step := range steps {
+		now = now.Add(step.Advance)
+		act, err := cache.Get(step.InstanceID, step.Fetch)
+		if (err != nil) != step.Error {
+			t.Errorf("%s: unexpected error: %v", step.Desc, err)
+		}
+		if diff := cmp.Diff(step.Expectation, act); diff != "" {
+			t.Errorf("%s: unexpected keys (-want +got):\n%s", step.Desc, diff)
+		}
+		if fetches != step.Fetches {
+			t.Errorf("%s: unexpected number of fetches: expected %d, got %d", step.Desc, step.Fetches, fetches)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package tcpproxy
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	p "github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+const (
+	// handshakeTimeout is the time clients have to complete the TLS handshake and send their access token
+	handshakeTimeout = 10 * time.Second
+	// dialTimeout is the time we wait for the workspace port to accept a connection
+	dialTimeout = 5 * time.Second
+	// maxAccessTokenLength limits how much we read before the client is authenticated
+	maxAccessTokenLength = 256
+)
+
+// Server exposes workspace ports over raw TCP. Clients connect using TLS with the host name of the workspace port
+// as server name (SNI), e.g. 5432-coral-dragon-ilr0r6eq.ws-eu10.gitpod.io, and send an access token obtained
+// from the Gitpod ports API followed by a newline. Everything after that is forwarded to the workspace port as is.
+type Server struct {
+	tlsConfig             *tls.Config
+	workspaceInfoProvider p.WorkspaceInfoProvider
+	matchHost             func(hostname string) (p.WorkspaceCoords, bool)
+}
+
+// New creates a new TCP proxy server
+func New(tlsConfig *tls.Config, wsHostSuffix string, workspaceInfoProvider p.WorkspaceInfoProvider) *Server {
+	return &Server{
+		tlsConfig:             tlsConfig,
+		workspaceInfoProvider: workspaceInfoProvider,
+		matchHost:             p.WorkspacePortHostMatcher(wsHostSuffix),
+	}
+}
+
+// Serve accepts connections until the listener fails.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go s.HandleConn(conn)
+	}
+}
+
+// HandleConn authenticates a client connection and forwards it to the workspace port.
+func (s *Server) HandleConn(c net.Conn) {
+	conn := tls.Server(c, s.tlsConfig)
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	err := conn.Handshake()
+	if err != nil {
+		log.WithError(err).WithField("remoteAddr", c.RemoteAddr().String()).Debug("TLS handshake failed")
+		return
+	}
+	serverName := conn.ConnectionState().ServerName
+	coords, ok := s.matchHost(serverName)
+	if !ok {
+		log.WithField("serverName", serverName).Debug("server name is not a workspace port")
+		return
+	}
+	log := log.WithField("workspaceId", coords.ID).WithField("port", coords.Port)
+
+	client := bufio.NewReaderSize(conn, maxAccessTokenLength)
+	tkn, err := readAccessToken(client)
+	if err != nil {
+		log.WithError(err).Debug("cannot read access token")
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	wsInfo, port, err := s.authorize(coords, tkn, time.Now())
+	if err != nil {
+		log.WithError(err).Debug("TCP port access denied")
+		return
+	}
+	log = log.WithField("instanceId", wsInfo.InstanceID)
+
+	upstream, err := net.DialTimeout("tcp", net.JoinHostPort(wsInfo.IPAddress, strconv.FormatUint(uint64(port), 10)), dialTimeout)
+	if err != nil {
+		log.WithField("workspaceIP", wsInfo.IPAddress).WithError(err).Warn("cannot connect to workspace port")
+		return
+	}
+	defer upstream.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, client)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	// when either side closes the connection we close both
+	<-done
+}
+
+// authorize checks that the workspace port is exposed and that the access token is valid for it.
+func (s *Server) authorize(coords p.WorkspaceCoords, tkn string, now time.Time) (*p.WorkspaceInfo, uint32, error) {
+	prt, err := strconv.ParseUint(coords.Port, 10, 16)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("invalid port %s: %w", coords.Port, err)
+	}
+	port := uint32(prt)
+
+	wsInfo := s.workspaceInfoProvider.WorkspaceInfo(coords.ID)
+	if wsInfo == nil {
+		return nil, 0, xerrors.Errorf("workspace not found")
+	}
+	var exposed bool
+	for _, ps := range wsInfo.Ports {
+		if ps.Port == port {
+			exposed = true
+			break
+		}
+	}
+	if !exposed {
+		return nil, 0, xerrors.Errorf("port is not exposed")
+	}
+	if wsInfo.Auth == nil || !validAccessToken(tkn, wsInfo.Auth.OwnerToken, wsInfo.InstanceID, port, now) {
+		return nil, 0, xerrors.Errorf("invalid access token")
+	}
+	return wsInfo, port, nil
+}
+
+// readAccessToken reads the newline terminated access token clients send before any payload.
+func readAccessToken(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package tcpproxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	p "github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+type fakeInfoProvider map[string]*p.WorkspaceInfo
+
+func (f fakeInfoProvider) WorkspaceInfo(workspaceID string) *p.WorkspaceInfo {
+	return f[workspaceID]
+}
+
+func TestAuthorize(t *testing.T) {
+	const (
+		workspaceID = "amaranth-smelt-9ba20cc1"
+		instanceID  = "1943c611-a014-4f4d-bf5d-14ccf0123c60"
+		ownerToken  = "owner-token"
+	)
+	var (
+		now       = time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+		expiry    = now.Add(5 * time.Minute)
+		workspace = &p.WorkspaceInfo{
+			WorkspaceID: workspaceID,
+			InstanceID:  instanceID,
+			IPAddress:   "10.0.0.1",
+			Auth:        &api.WorkspaceAuthentication{OwnerToken: ownerToken},
+			Ports:       []*api.PortSpec{{Port: 5432}},
+		}
+		server = &Server{
+			workspaceInfoProvider: fakeInfoProvider{workspaceID: workspace},
+			matchHost:             p.WorkspacePortHostMatcher(".ws.gitpod.io"),
+		}
+	)
+
+	tests := []struct {
+		Desc        string
+		ServerName  string
+		Token       string
+		Expectation bool
+	}{
+		{
+			Desc:        "valid token",
+			ServerName:  "5432-" + workspaceID + ".ws.gitpod.io",
+			Token:       AccessToken(ownerToken, instanceID, 5432, expiry),
+			Expectation: true,
+		},
+		{
+			Desc:       "expired token",
+			ServerName: "5432-" + workspaceID + ".ws.gitpod.io",
+			Token:      AccessToken(ownerToken, instanceID, 5432, now.Add(-1*time.Second)),
+		},
+		{
+			Desc:       "tampered expiry",
+			ServerName: "5432-" + workspaceID + ".ws.gitpod.io",
+			Token:      "1893456000." + AccessToken(ownerToken, instanceID, 5432, expiry)[11:],
+		},
+		{
+			Desc:       "token for another port",
+			ServerName: "5432-" + workspaceID + ".ws.gitpod.io",
+			Token:      AccessToken(ownerToken, instanceID, 3000, expiry),
+		},
+		{
+			Desc:       "token of another instance",
+			ServerName: "5432-" + workspaceID + ".ws.gitpod.io",
+			Token:      AccessToken(ownerToken, "other-instance", 5432, expiry),
+		},
+		{
+			Desc:       "port not exposed",
+			ServerName: "3000-" + workspaceID + ".ws.gitpod.io",
+			Token:      AccessToken(ownerToken, instanceID, 3000, expiry),
+		},
+		{
+			Desc:       "unknown workspace",
+			ServerName: "5432-pink-panda-ns35kd21.ws.gitpod.io",
+			Token:      AccessToken(ownerToken, instanceID, 5432, expiry),
+		},
+		{
+			Desc:       "malformed token",
+			ServerName: "5432-" + workspaceID + ".ws.gitpod.io",
+			Token:      ownerToken,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			coords, ok := server.matchHost(test.ServerName)
+			if !ok {
+				t.Fatalf("server name %s does not match", test.ServerName)
+			}
+			_, _, err := server.authorize(coords, test.Token, now)
+			if act := err == nil; act != test.Expectation {
+				t.Errorf("unexpected result: expected %v, got %v (%v)", test.Expectation, act, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package tcpproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessToken returns the token which grants access to a workspace port over TCP until it expires. Gitpod derives
+// the same value when it hands out TCP access through the ports API, so that the owner token is never revealed.
+// The token has the form <expiry in unix seconds>.<hex encoded HMAC>.
+func AccessToken(ownerToken, instanceID string, port uint32, expiry time.Time) string {
+	exp := strconv.FormatInt(expiry.Unix(), 10)
+	return exp + "." + accessTokenMAC(ownerToken, instanceID, port, exp)
+}
+
+func accessTokenMAC(ownerToken, instanceID string, port uint32, expiry string) string {
+	mac := hmac.New(sha256.New, []byte(ownerToken))
+	_, _ = mac.Write([]byte(fmt.Sprintf("%s:%d:%s", instanceID, port, expiry)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validAccessToken(tkn, ownerToken, instanceID string, port uint32, now time.Time) bool {
+	if ownerToken == "" {
+		return false
+	}
+	segs := strings.SplitN(tkn, ".", 2)
+	if len(segs) != 2 {
+		return false
+	}
+	exp, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil || now.After(time.Unix(exp, 0)) {
+		return false
+	}
+	return hmac.Equal([]byte(segs[1]), []byte(accessTokenMAC(ownerToken, instanceID, port, segs[0])))
+}